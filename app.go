@@ -15,6 +15,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,6 +31,9 @@ import (
 	billing_domain "vault-app/internal/billing/domain"
 	billing_ui "vault-app/internal/billing/ui"
 	"vault-app/internal/blockchain"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+	browser_extension_persistence "vault-app/internal/browser_extension/infrastructure/persistence"
+	browser_extension_ui "vault-app/internal/browser_extension/ui"
 	app_config "vault-app/internal/config"
 	app_config_dto "vault-app/internal/config/application/dto"
 	app_config_worker "vault-app/internal/config/application/worker"
@@ -37,14 +41,18 @@ import (
 	app_config_ui "vault-app/internal/config/ui"
 	share_domain "vault-app/internal/domain/shared"
 	"vault-app/internal/driver"
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_ui "vault-app/internal/git_credential/ui"
 	"vault-app/internal/handlers"
 	identity_commands "vault-app/internal/identity/application/commands"
 	identity_dtos "vault-app/internal/identity/application/dtos"
+	identity_usecase "vault-app/internal/identity/application/usecase"
 	identity_domain "vault-app/internal/identity/domain"
 	identity_ui "vault-app/internal/identity/ui"
 	"vault-app/internal/logger/logger"
 	notification_center_usecases "vault-app/internal/notification_center/application/use_cases"
 	notification_center_domain "vault-app/internal/notification_center/domain"
+	notification_center_services "vault-app/internal/notification_center/infrastructure/services"
 	notification_center_ui "vault-app/internal/notification_center/ui"
 	onboarding_usecase "vault-app/internal/onboarding/application/usecase"
 	onboarding_domain "vault-app/internal/onboarding/domain"
@@ -58,8 +66,12 @@ import (
 	share_entry_domain "vault-app/internal/share_entry/domain"
 	share_entry_infrastructure "vault-app/internal/share_entry/infrastructure"
 	sahre_entry_ui_wails "vault-app/internal/share_entry/ui/wails"
+	shared_offline "vault-app/internal/shared/offline"
 	shared_realtime "vault-app/internal/shared/realtime"
 	shared "vault-app/internal/shared/stellar"
+	ssh_agent_usecases "vault-app/internal/ssh_agent/application/usecases"
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	ssh_agent_ui "vault-app/internal/ssh_agent/ui"
 	stellar_recovery_domain "vault-app/internal/stellar_recovery/domain"
 	"vault-app/internal/stellar_recovery/infrastructure/events"
 	"vault-app/internal/stellar_recovery/infrastructure/token"
@@ -69,6 +81,9 @@ import (
 	subscription_persistence "vault-app/internal/subscription/infrastructure/persistence"
 	subscription_ui_wails "vault-app/internal/subscription/ui/wails"
 	"vault-app/internal/tracecore"
+	tracecore_models "vault-app/internal/tracecore/models"
+	tracecore_rules "vault-app/internal/tracecore/rules"
+	tracecore_signing "vault-app/internal/tracecore/signing"
 	tracecore_types "vault-app/internal/tracecore/types"
 	utils "vault-app/internal/utils"
 	vault_commands "vault-app/internal/vault/application/commands"
@@ -80,21 +95,51 @@ import (
 	vault_infrastructure_crypto "vault-app/internal/vault/infrastructure/crypto"
 	vaults_persistence "vault-app/internal/vault/infrastructure/persistence"
 	vault_ui "vault-app/internal/vault/ui"
+	vault_export_usecases "vault-app/internal/vault_export/application/usecases"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+	vault_export_formats "vault-app/internal/vault_export/infrastructure/formats"
+	vault_export_persistence "vault-app/internal/vault_export/infrastructure/persistence"
+	vault_export_security "vault-app/internal/vault_export/infrastructure/security"
+	vault_export_ui "vault-app/internal/vault_export/ui"
+	vault_health_domain "vault-app/internal/vault_health/domain"
+	vault_health_ui "vault-app/internal/vault_health/ui"
+	vault_import_usecases "vault-app/internal/vault_import/application/usecases"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+	vault_import_formats "vault-app/internal/vault_import/infrastructure/formats"
+	vault_import_persistence "vault-app/internal/vault_import/infrastructure/persistence"
+	vault_import_ui "vault-app/internal/vault_import/ui"
 	// "vault-app/internal/logger/logger"
+	channel_application "vault-app/internal/channel/application"
+	channelconfigusecases "vault-app/internal/channel/application/channel_config-usecases"
 	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_occupancy_usecases "vault-app/internal/channel/application/channel_occupancy_usecases"
+	channel_template_usecases "vault-app/internal/channel/application/channel_template_usecases"
+	channel_federation "vault-app/internal/channel/application/federation"
 	channel_domain "vault-app/internal/channel/domain"
 	channel_eventbus "vault-app/internal/channel/infrastructure/eventbus"
+	channel_persistence "vault-app/internal/channel/infrastructure/persistence"
+	channel_templates "vault-app/internal/channel/infrastructure/templates"
+	channel_transport "vault-app/internal/channel/infrastructure/transport"
 	channel_ui "vault-app/internal/channel/ui"
 	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
-	collaboration_ui "vault-app/internal/collaboration/ui"
 	collaboration_usecases "vault-app/internal/collaboration/application/usecases"
+	collaboration_identity "vault-app/internal/collaboration/infrastructure/identity"
+	collaboration_ui "vault-app/internal/collaboration/ui"
 	"vault-app/internal/models"
 	thread_usecase "vault-app/internal/thread/application/usecases"
 	thread_domain "vault-app/internal/thread/domain"
+	thread_devicekeys "vault-app/internal/thread/infrastructure/devicekeys"
 	thread_infrastructure_eventbus "vault-app/internal/thread/infrastructure/eventbus"
+	thread_persistence "vault-app/internal/thread/infrastructure/persistence"
 	thread_ui "vault-app/internal/thread/ui"
+	trustgroup_orchestrator "vault-app/internal/trust_group/application/orchestrator"
+	trustgroup_adapters "vault-app/internal/trust_group/infrastructure/adapters"
+	trustgroup_infrastructure_eventbus "vault-app/internal/trust_group/infrastructure/eventbus"
+	workspace_application "vault-app/internal/workspace/application"
 	workspace_usecase "vault-app/internal/workspace/application/usecases"
+	workspace_domain "vault-app/internal/workspace/domain"
 	workspace_infrastructure_eventbus "vault-app/internal/workspace/infrastructure/eventbus"
+	workspace_persistence "vault-app/internal/workspace/infrastructure/persistence"
 	workspace_ui "vault-app/internal/workspace/ui"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -155,6 +200,15 @@ type config struct {
 	ANKHORA_WEBSOCKET_GATEWAY string
 
 	KEYRING_PATH string
+
+	// Channels
+	ChannelInvitationTTL time.Duration
+	// InvitationSweepInterval is how often the invitations a signed-in user
+	// sent are checked for expiry.
+	InvitationSweepInterval time.Duration
+	FederationSyncInterval  time.Duration
+	// FederationEndpoint is the URL remote vaults reach this vault at.
+	FederationEndpoint string
 }
 
 type App struct {
@@ -176,6 +230,7 @@ type App struct {
 	CryptographicShareHandler *sahre_entry_ui_wails.CryptographicShareHandler
 	LinkShareHandler          *sahre_entry_ui_wails.LinkShareHandler
 	Identity                  *identity_ui.IdentityHandler
+	DeviceHandler             *identity_ui.DeviceHandler
 	OnBoardingHandler         *onboarding_ui_wails.OnBoardingHandler
 	NotificationCenterHandler *notification_center_ui.NotificationHandler
 	ShareEntryHandler         *sahre_entry_ui_wails.ShareEntryHandler
@@ -183,17 +238,30 @@ type App struct {
 	StellarRecoveryHandler    *stellar_recovery_ui_api.StellarRecoveryHandler
 	SubscriptionHandler       *subscription_ui_wails.SubscriptionHandler
 	Vault                     *vault_ui.VaultHandler
+	ImportHandler             *vault_import_ui.ImportHandler
+	ExportHandler             *vault_export_ui.ExportHandler
+	SSHAgentHandler           *ssh_agent_ui.SSHAgentHandler
+	GitCredentialHandler      *git_credential_ui.GitCredentialHandler
+	BrowserExtensionHandler   *browser_extension_ui.BrowserExtensionHandler
+	VaultHealthHandler        *vault_health_ui.VaultHealthHandler
 	// Vaults                    *handlers.VaultHandler
 
 	// C3 Handlers
 	WorkspaceHandler     *workspace_ui.WorkspaceHandler
 	ChannelHandler       *channel_ui.ChannelHandler
+	FederationHandler    *channel_ui.FederationHandler
 	ThreadHandler        *thread_ui.ThreadHandler
 	CollaborationHandler *collaboration_ui.CollaborationHandler
+	C3Cache              *shared_offline.Cache
 
 	// New: Global state
 	RuntimeContext *vault_session.RuntimeContext
 	cancel         context.CancelFunc
+
+	// invitationSweeps stops the invitation expiry sweep of each signed-in
+	// user.
+	invitationSweepsMu sync.Mutex
+	invitationSweeps   map[string]context.CancelFunc
 }
 
 // NewApp creates a new App instance (required by Wails)
@@ -368,6 +436,11 @@ func NewApp() *App {
 			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.SSHKeyEntry{} },
 			Handler: vault_ui.NewSSHKeyHandler(*db, appLogger),
 		},
+		{
+			Type:    "otp",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.OTPEntry{} },
+			Handler: vault_ui.NewOTPHandler(*db, appLogger),
+		},
 	})
 	appLogger.Info("✅ Registry initialized")
 
@@ -407,6 +480,13 @@ func NewApp() *App {
 
 	appConfigHandler.SetVaultHandler(*vaultHandler)
 
+	// Imports: parsed batches stay in memory until committed or expired.
+	importPreviewStore := vault_import_persistence.NewMemoryPreviewStore()
+	importHandler := vault_import_ui.NewImportHandler(
+		vault_import_usecases.NewPreviewImportUsecase(vault_import_formats.Parsers(), vaultHandler, importPreviewStore),
+		vault_import_usecases.NewCommitImportUsecase(vaultHandler, importPreviewStore),
+	)
+
 	// -------------------------------------------------------------------------------------------------
 	// Subscription
 	// -------------------------------------------------------------------------------------------------
@@ -428,6 +508,37 @@ func NewApp() *App {
 
 	appConfigHandler.SetOnboardingHandler(*onBoardingHandler)
 
+	// Exports: plaintext formats re-check the onboarding password.
+	exportAuditRepo := vault_export_persistence.NewGormAuditRepository(db.DB)
+	exportHandler := vault_export_ui.NewExportHandler(
+		vault_export_usecases.NewExportVaultUsecase(
+			vaultHandler,
+			vaultHandler,
+			vault_export_formats.Writers(),
+			vault_export_security.NewPasswordReauthenticator(onBoardingHandler.UserRepo),
+			exportAuditRepo,
+		),
+		vault_export_usecases.NewListExportsUsecase(exportAuditRepo),
+	)
+
+	// SSH agent: serves the vault's SSH keys; confirmations are wired to the UI below.
+	sshAgentConfirmer := ssh_agent_ui.NewUIConfirmer(nil)
+	sshAgentHandler := ssh_agent_ui.NewSSHAgentHandler(
+		vaultHandler,
+		ssh_agent_usecases.NewGenerateKeyUsecase(vaultHandler),
+		ssh_agent_usecases.NewSetKeyPolicyUsecase(vaultHandler),
+		sshAgentConfirmer,
+	)
+
+	// Git credential helper: answers git-credential-dvault from the vault's logins.
+	gitCredentialHandler := git_credential_ui.NewGitCredentialHandler(vaultHandler)
+
+	// Browser extension: loopback API for autofill, paired clients are kept in the database.
+	browserExtensionHandler := browser_extension_ui.NewBrowserExtensionHandler(
+		vaultHandler,
+		browser_extension_persistence.NewGormClientRepository(db.DB),
+	)
+
 	// -------------------------------------------------------------------------------------------------
 	// Auth Infrastructure
 	// -------------------------------------------------------------------------------------------------
@@ -438,6 +549,15 @@ func NewApp() *App {
 	// Identity
 	// -------------------------------------------------------------------------------------------------
 	identityHandler := identity_ui.NewIdentityHandler(db.DB, authTokenService, onBoardingHandler.UserRepo)
+	// Devices and their enrollments live in the Cloud identity store so every
+	// installation of a vault sees the same devices.
+	deviceRepo := tracecore.NewCloudDeviceRepository(tracecoreClient)
+	deviceHandler := identity_ui.NewDeviceHandler(
+		deviceRepo,
+		tracecore.NewCloudDeviceEnrollmentRepository(tracecoreClient),
+		identity_usecase.StaticDevicePolicy{MaxDevices: 10, RequireDeviceApproval: true},
+		identityHandler.Bus,
+	)
 
 	// -------------------------------------------------------------------------------------------------
 	// Auth
@@ -496,10 +616,17 @@ func NewApp() *App {
 	// -------------------------------------------------------------------------------------------------
 	// Notification Center
 	// // -------------------------------------------------------------------------------------------------
-	notificationUsecase := notification_center_usecases.NewNotificationUseCase(tracecoreClient)
+	notificationUsecase := notification_center_usecases.NewNotificationUseCase(tracecoreClient).
+		WithLocalStore(notification_center_services.NewMemoryLocalStore())
 	notificationHandler := notification_center_ui.NewNotificationHandler(*notificationUsecase)
 	appLogger.Info("notificationHandler", notificationHandler)
 
+	// Vault health: threat detection over the unlocked vault, summaries go to the notification center.
+	vaultHealthHandler := vault_health_ui.NewVaultHealthHandler(vaultHandler, notificationHandler)
+	if status, err := vaultHealthHandler.LoadBreachCorpus(""); err == nil {
+		appLogger.Info("🛡️ Breach corpus loaded from %s", status.Path)
+	}
+
 	// -------------------------------------------------------------------------------------------------
 	// Stellar Recovery
 	// -------------------------------------------------------------------------------------------------
@@ -592,40 +719,148 @@ func NewApp() *App {
 	// -------------------------------------------------------------------------------------------------
 	// C3 Handlers Initialization
 	// -------------------------------------------------------------------------------------------------
+	// Offline-first C3 reads/writes: Cloud stays authoritative, the local
+	// SQLite read model serves reads and queues writes while offline.
+	c3Cache, err := shared_offline.NewCache(db.DB)
+	if err != nil {
+		appLogger.Error("❌ Failed to initialize C3 offline cache: %v", err)
+		os.Exit(1)
+	}
+	workspaceRepo := workspace_persistence.NewOfflineRepository(tracecoreClient, c3Cache)
+	channelRepo := channel_persistence.NewOfflineRepository(tracecoreClient, c3Cache)
+	threadRepo := thread_persistence.NewOfflineRepository(tracecoreClient, c3Cache)
+
 	workspaceBus := workspace_infrastructure_eventbus.NewMemoryBus()
-	createWorkspaceUC := workspace_usecase.NewCreateWorkspaceUsecase(tracecoreClient, workspaceBus)
-	listWorkspaceUC := workspace_usecase.NewListWorkspaceUsecase(tracecoreClient, workspaceBus)
+	createWorkspaceUC := workspace_usecase.NewCreateWorkspaceUsecase(workspaceRepo, workspaceBus)
+	listWorkspaceUC := workspace_usecase.NewListWorkspaceUsecase(workspaceRepo, workspaceBus)
 	workspaceHandler := workspace_ui.NewWorkspaceHandler(createWorkspaceUC, listWorkspaceUC)
+	workspaceHandler.SetLifecycleUseCases(
+		workspace_usecase.NewArchiveWorkspaceUsecase(workspaceRepo, workspaceBus, channelRepo),
+		workspace_usecase.NewRestoreWorkspaceUsecase(workspaceRepo, workspaceBus, channelRepo),
+		workspace_usecase.NewMoveChannelUsecase(workspaceRepo, workspaceBus, channelRepo, threadRepo),
+	)
+	workspaceHandler.SetMembershipUseCase(workspace_usecase.NewWorkspaceMembershipUsecase(workspaceRepo, workspaceBus))
+	workspaceHandler.SetSearchUseCase(workspace_usecase.NewSearchWorkspacesUsecase(workspaceRepo, channelRepo, threadRepo))
 
 	channelBus := channel_eventbus.NewMemoryEventBus()
-	createChannelUC := channel_usecase.NewCreateChannelUsecase(tracecoreClient, channelBus)
-	listChannelUC := channel_usecase.NewListChannelUsecase(tracecoreClient)
-	getChannelUC := channel_usecase.NewGetChannelUsecase(tracecoreClient)
-	updateChannelUC := channel_usecase.NewUpdateChannelUsecase(tracecoreClient)
-	deleteChannelUC := channel_usecase.NewDeleteChannelUsecase(tracecoreClient)
-	activateChannelUC := channel_usecase.NewActivateChannelUsecase(tracecoreClient)
-	revokeChannelUC := channel_usecase.NewRevokeChannelUsecase(tracecoreClient)
-	addParticipantUC := channel_usecase.NewAddParticipantUsecase(tracecoreClient)
-	listParticipantsUC := channel_usecase.NewListParticipantsUsecase(tracecoreClient)
-	inviteToChannelUC := channel_usecase.NewInviteToChannelUsecase(tracecoreClient)
-	acceptInvitationUC := channel_usecase.NewAcceptChannelInvitationUsecase(tracecoreClient)
+	createChannelUC := channel_usecase.NewCreateChannelUsecase(channelRepo, channelBus)
+	listChannelUC := channel_usecase.NewListChannelUsecase(channelRepo)
+	channelPolicy := channel_domain.NewPolicyEvaluator()
+	getChannelUC := channel_usecase.NewGetChannelUsecase(channelRepo).WithPolicy(channelPolicy)
+	updateChannelUC := channel_usecase.NewUpdateChannelUsecase(channelRepo).WithPolicy(channelPolicy)
+	deleteChannelUC := channel_usecase.NewDeleteChannelUsecase(channelRepo).WithPolicy(channelPolicy)
+	activateChannelUC := channel_usecase.NewActivateChannelUsecase(channelRepo).WithPolicy(channelPolicy)
+	revokeChannelUC := channel_usecase.NewRevokeChannelUsecase(channelRepo).WithPolicy(channelPolicy)
+	addParticipantUC := channel_usecase.NewAddParticipantUsecase(channelRepo).WithPolicy(channelPolicy)
+	listParticipantsUC := channel_usecase.NewListParticipantsUsecase(channelRepo).WithPolicy(channelPolicy)
+	inviteToChannelUC := channel_usecase.NewInviteToChannelUsecase(channelRepo).WithPolicy(channelPolicy).WithTTL(cfg.ChannelInvitationTTL)
+	acceptInvitationUC := channel_usecase.NewAcceptChannelInvitationUsecase(channelRepo).WithPolicy(channelPolicy).WithTTL(cfg.ChannelInvitationTTL)
 	channelHandler := channel_ui.NewChannelHandler(createChannelUC, listChannelUC, getChannelUC, updateChannelUC, deleteChannelUC, activateChannelUC, revokeChannelUC, addParticipantUC, listParticipantsUC, inviteToChannelUC, acceptInvitationUC)
+	channelHandler.SetPolicyUseCases(
+		channelconfigusecases.NewGetChannelPolicyUsecase(channelRepo),
+		channelconfigusecases.NewUpdateChannelPolicyUsecase(channelRepo).WithPolicy(channelPolicy),
+	)
+	channelHandler.SetPermissionUseCases(
+		channelconfigusecases.NewGrantChannelPermissionUsecase(channelRepo, channelBus).WithEventLog(tracecoreClient),
+		channelconfigusecases.NewRevokeChannelPermissionUsecase(channelRepo, channelBus).WithEventLog(tracecoreClient),
+		channelconfigusecases.NewGetParticipantPermissionsUsecase(channelRepo),
+	)
+	// Channel templates: Cloud registry first, built-in blueprints offline.
+	channelTemplates := channel_templates.NewRegistry(tracecoreClient, channel_templates.BuiltinTemplates()...)
+	channelHandler.SetTemplateUseCases(
+		channel_template_usecases.NewListChannelTemplatesUsecase(channelTemplates),
+		channel_template_usecases.NewGetChannelTemplateUsecase(channelTemplates),
+		channel_template_usecases.NewInstantiateChannelTemplateUsecase(channelTemplates, createChannelUC),
+		channel_template_usecases.NewMigrateChannelTemplateUsecase(channelRepo, channelTemplates).WithPolicy(channelPolicy),
+	)
+	// Permission changes are shared through the channel event log; the bus
+	// mirrors them into the local audit log.
+	channelBus.SubscribeToChannelPermissionGranted(func(ctx context.Context, e channel_domain.ChannelPermissionGranted) {
+		appLogger.Info("🔐 Channel %s: %s granted %s to %s (policy rev %d)", e.ChannelID, e.ActorVaultID, e.Permission, e.VaultID, e.PolicyRevision)
+	})
+	channelBus.SubscribeToChannelPermissionRevoked(func(ctx context.Context, e channel_domain.ChannelPermissionRevoked) {
+		appLogger.Info("🔐 Channel %s: %s revoked %s from %s (policy rev %d)", e.ChannelID, e.ActorVaultID, e.Permission, e.VaultID, e.PolicyRevision)
+	})
+	channelHandler.SetInvitationLifecycleUseCases(
+		channel_usecase.NewRejectChannelInvitationUsecase(channelRepo),
+		channel_usecase.NewRevokeChannelInvitationUsecase(channelRepo),
+		channel_usecase.NewListChannelInvitationsUsecase(channelRepo).WithTTL(cfg.ChannelInvitationTTL),
+		channel_usecase.NewExpireChannelInvitationsUsecase(channelRepo, cfg.ChannelInvitationTTL),
+	)
+	// Gated slots are filled once the policy's N-of-M approvers signed off.
+	// The occupancy log verifies the votes it replays with the service's
+	// own checks.
+	occupancyLog := channel_persistence.NewOccupancyEventLog(tracecoreClient, channel_persistence.NewOccupancyStore(c3Cache.Store))
+	slotOccupancy := channel_occupancy_usecases.NewSlotOccupancyService(
+		channelRepo,
+		occupancyLog,
+		channelBus,
+		blockchain.StellarKeyVerifier{},
+	).WithPolicy(channelPolicy)
+	occupancyLog.WithVerification(channelRepo, slotOccupancy)
+	channelHandler.SetOccupancyService(slotOccupancy)
+
+	// Federation: push pending sync items to trusted remote vaults.
+	federationTransport := channel_transport.NewHTTPFederationTransport(nil)
+	federationEngine := channel_federation.NewEngine(
+		channel_persistence.NewFederationStore(c3Cache.Store).WithVaultSessions(vaultHandler.SessionManager),
+		federationTransport,
+	).WithExchanges(channel_persistence.NewThreadEventExchanges(c3Cache.Store))
+	federationEngine.OnPass = func(report *channel_federation.SyncReport, err error) {
+		if err != nil {
+			appLogger.Error("❌ Federation sync pass failed: %v", err)
+			return
+		}
+		if report != nil && report.DeadLettered > 0 {
+			appLogger.Warn("⚠️ Federation sync dead-lettered %d item(s)", report.DeadLettered)
+		}
+	}
+	federationHandler := channel_ui.NewFederationHandler(federationEngine)
+	federationHandler.SetTrustService(
+		channel_federation.NewTrustService(federationEngine, federationTransport, blockchain.StellarKeyVerifier{}),
+	)
 
 	threadBus := thread_infrastructure_eventbus.NewMemoryBus()
-	createThreadUC := thread_usecase.NewCreateThreadUsecase(tracecoreClient, threadBus, tracecoreClient)
-	listThreadsUC := thread_usecase.NewListThreadsUsecase(tracecoreClient)
-	listThreadEventsUC := thread_usecase.NewListThreadEventsUsecase(tracecoreClient)
-	appendThreadEventUC := thread_usecase.NewAppendThreadEventUsecase(tracecoreClient)
+	createThreadUC := thread_usecase.NewCreateThreadUsecase(threadRepo, threadBus, channelRepo).WithPolicy(channelPolicy)
+	listThreadsUC := thread_usecase.NewListThreadsUsecase(threadRepo).WithPolicy(channelRepo, channelPolicy)
+	listThreadEventsUC := thread_usecase.NewListThreadEventsUsecase(threadRepo).WithPolicy(channelRepo, channelPolicy)
+	appendThreadEventUC := thread_usecase.NewAppendThreadEventUsecase(threadRepo).WithPolicy(channelRepo, channelPolicy).WithExchanges(channel_federation.NewExchangeFeeder(federationEngine, channelRepo))
 	threadHandler := thread_ui.NewThreadHandler(createThreadUC, listThreadsUC, listThreadEventsUC, appendThreadEventUC)
+	deviceKeys := thread_devicekeys.NewIdentityResolver(deviceRepo)
+	threadHandler.SetVerifyEventsUseCase(thread_usecase.NewVerifyThreadEventsUsecase(threadRepo, deviceKeys))
+	threadHandler.SetLifecycleUseCases(
+		thread_usecase.NewCloseThreadUsecase(threadRepo, threadBus).WithPolicy(channelRepo, channelPolicy).WithEvents(appendThreadEventUC),
+		thread_usecase.NewReopenThreadUsecase(threadRepo, threadBus, channelRepo).WithPolicy(channelRepo, channelPolicy).WithEvents(appendThreadEventUC),
+		thread_usecase.NewInitiateThreadTransferUsecase(threadRepo, threadBus).WithPolicy(channelRepo, channelPolicy).WithEvents(appendThreadEventUC),
+		thread_usecase.NewCompleteThreadTransferUsecase(threadRepo, threadBus).WithPolicy(channelRepo, channelPolicy).WithEvents(appendThreadEventUC),
+	)
 
 	// C3 collaboration: real Cloud-backed repositories (TracecoreClient
 	// implements both trustgroup_domain.TrustGroupRepository and
 	// c3_asset_domain.ShareEntryRepository against /api/trustgroups and
 	// /api/c3/share-entries).
-	shareAssetWithTrustGroupUC := collaboration_usecases.NewShareAssetWithTrustGroupUsecase(tracecoreClient, tracecore.NewCloudShareEntryRepository(tracecoreClient))
-	createCollabShareUC := collaboration_usecases.NewCreateCollaborativeShareUseCase(shareAssetWithTrustGroupUC, nil)
+	cloudShareEntryRepo := tracecore.NewCloudShareEntryRepository(tracecoreClient)
+	shareAssetWithTrustGroupUC := collaboration_usecases.NewShareAssetWithTrustGroupUsecase(tracecoreClient, cloudShareEntryRepo)
+	createCollabShareUC := collaboration_usecases.NewCreateCollaborativeShareUseCase(shareAssetWithTrustGroupUC, nil).WithThreadGate(appendThreadEventUC)
 	collaborationHandler := collaboration_ui.NewCollaborationHandler(createCollabShareUC, nil, appendThreadEventUC)
 
+	// KEK rotation: the new KEK is sealed for every remaining active device
+	// and the caller's keyring is unlocked with their Stellar secret.
+	rotateKEKUC := collaboration_usecases.NewRotateTrustGroupKEKUseCase(tracecoreClient, cloudShareEntryRepo)
+	trustGroupDevices := trustgroup_adapters.NewIdentityDeviceAdapter(deviceRepo)
+	collabIdentity := collaboration_identity.NewSessionIdentityResolver(appConfigHandler, onBoardingHandler.UserRepo, vaultHandler.KeyringService)
+	trustGroupCrypto := trustgroup_orchestrator.NewTrustGroupCryptoOrchestrator(vaultHandler.KeyringService, nil, nil)
+	trustGroupBus := trustgroup_infrastructure_eventbus.NewMemoryBus()
+	collaborationHandler.SetRemoveMemberUseCase(collaboration_usecases.NewRemoveTrustGroupMemberUseCase(
+		rotateKEKUC, tracecoreClient, cloudShareEntryRepo, trustGroupDevices, collabIdentity, trustGroupCrypto, trustGroupBus,
+	))
+	collaborationHandler.SetDeviceTrustUseCases(
+		collaboration_usecases.NewProvisionDeviceEnvelopesUseCase(tracecoreClient, tracecoreClient, collabIdentity, trustGroupCrypto),
+		collaboration_usecases.NewRotateForRevokedDeviceUseCase(
+			rotateKEKUC, tracecoreClient, cloudShareEntryRepo, trustGroupDevices, collabIdentity, trustGroupCrypto, trustGroupBus,
+		),
+	)
+
 	application := &App{
 		AppConfigHandler: appConfigHandler,
 		// Auth:                      nil, // auth,
@@ -641,6 +876,7 @@ func NewApp() *App {
 		NotificationCenterHandler: notificationHandler,
 		NowUTC:                    func() string { return time.Now().Format(time.RFC3339) },
 		Identity:                  identityHandler,
+		DeviceHandler:             deviceHandler,
 		Logger:                    *appLogger,
 		OnBoardingHandler:         onBoardingHandler,
 		sessions:                  sessions, // TODO: remove legacy sessions
@@ -650,10 +886,18 @@ func NewApp() *App {
 		SubscriptionHandler:       subscriptionHandler,
 		RuntimeContext:            runtimeCtxLegacy,
 		Vault:                     vaultHandler, // internal/vault/ui/vault_handler.go
+		ImportHandler:             importHandler,
+		ExportHandler:             exportHandler,
+		SSHAgentHandler:           sshAgentHandler,
+		GitCredentialHandler:      gitCredentialHandler,
+		BrowserExtensionHandler:   browserExtensionHandler,
+		VaultHealthHandler:        vaultHealthHandler,
 		WorkspaceHandler:          workspaceHandler,
 		ChannelHandler:            channelHandler,
+		FederationHandler:         federationHandler,
 		ThreadHandler:             threadHandler,
 		CollaborationHandler:      collaborationHandler,
+		C3Cache:                   c3Cache,
 		// Vaults:                    nil,          // vaults, // internal/handlers/vault_handler.go legacy
 		version: version,
 	}
@@ -669,6 +913,34 @@ func NewApp() *App {
 	go vaultListener.Listen(ctx)
 	appLogger.Info("✅ Vault share created listener started")
 
+	// Pending approvers and filled slots surface in the UI as Wails events.
+	slotOccupancy.WithNotifier(channel_occupancy_usecases.ApproverNotifierFunc(
+		func(ctx context.Context, r channel_domain.OccupancyRequest, approvers []string) error {
+			if application.ctx == nil {
+				return nil
+			}
+			runtime.EventsEmit(application.ctx, "channel:slot-approval-requested", map[string]any{
+				"request":   r,
+				"approvers": approvers,
+			})
+			return nil
+		},
+	))
+	sshAgentConfirmer.Emit = func(event string, payload any) {
+		if application.ctx != nil {
+			runtime.EventsEmit(application.ctx, event, payload)
+		}
+	}
+	channelBus.SubscribeToSlotFilled(func(ctx context.Context, e channel_domain.SlotFilled) {
+		appLogger.Info("🧩 Channel %s: slot %s filled by %s (request %s)", e.ChannelID, e.Assignment.SlotID, e.Assignment.OwnerID, e.RequestID)
+		if application.ctx != nil {
+			runtime.EventsEmit(application.ctx, "channel:slot-filled", e)
+		}
+	})
+
+	go federationEngine.Run(ctx, cfg.FederationSyncInterval)
+	appLogger.Info("✅ Federation sync worker started")
+
 	return application
 }
 
@@ -916,6 +1188,13 @@ func (a *App) SignOut(userID string) error {
 		a.Logger.Error("❌ SignOut failed for user %s: %v", userID, err)
 		return err
 	}
+	// The vault is locked: the SSH agent and the browser extension API must stop serving it.
+	a.SSHAgentHandler.OnVaultLocked(userID)
+	a.BrowserExtensionHandler.OnVaultLocked(userID)
+	if a.FederationHandler != nil {
+		a.FederationHandler.OnVaultLocked(userID)
+	}
+	a.stopInvitationExpiry(userID)
 	a.Logger.Info("✅ User %s signed out", userID)
 
 	return nil
@@ -1115,11 +1394,22 @@ func (a *App) SignIn(req handlers.LoginRequest) (*vault_dto.LoginResponse, error
 				result.User.ID, vaultRes.RuntimeContext.VaultID, err)
 			return nil, fmt.Errorf("vault cloud connection failed: %w", err)
 		}
+		// Federation batches go out under the vault that is now open.
+		if a.FederationHandler != nil {
+			a.FederationHandler.SetLocalVault(result.User.ID, vaultRes.RuntimeContext.VaultID)
+		}
+		a.setThreadEventSigner(result.User.ID, vaultRes.RuntimeContext.VaultID)
 	}
 
 	// ---------- Connect to real-time --------- //
 	a.ConnectToRealtime(*result.User)
 
+	// ---------- Channel invitations: expire the ones past their TTL --------- //
+	a.startInvitationExpiry(result.User.ID)
+
+	// ---------- Vault health: refresh the notification center summary --------- //
+	go a.refreshVaultHealth(result.User.ID, result.User.Email)
+
 	loginRes := &vault_dto.LoginResponse{
 		User:                *result.User,
 		Tokens:              result.Tokens,
@@ -1150,6 +1440,10 @@ func (a *App) RestoreCloudTokenForUser(userID string) error {
 	if userSession != nil && userSession.Runtime != nil && userSession.Runtime.SessionSecrets != nil {
 		if cloudJWT, ok := userSession.Runtime.SessionSecrets["cloud_jwt"]; ok && cloudJWT != "" {
 			a.Vault.TracecoreClient.SetToken(cloudJWT)
+			if a.C3Cache != nil {
+				// Offline writes are queued for, and replayed with, this token.
+				a.C3Cache.Queue.SetUser(userID)
+			}
 			a.Logger.Info("☁️ [CLOUD-AUTH] Restored Cloud token for user=%s", userID)
 		} else {
 			a.Logger.Info("☁️ [CLOUD-AUTH] Cloud token absent in session for user=%s", userID)
@@ -1258,6 +1552,110 @@ func (a *App) EditConfig(vaultName string, s *app_config_dto.Settings, jwtToken
 	return a.AppConfigHandler.EditSettings(claims.UserID, vaultName, s)
 }
 
+// DryRunCommitRules evaluates the configured commit rules against historical
+// commits without submitting anything. Admin only.
+func (a *App) DryRunCommitRules(commits []tracecore_models.CommitEnvelope, jwtToken string) (*tracecore_rules.DryRunSummary, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	if a.AppConfigHandler == nil {
+		return nil, fmt.Errorf("app config handler not initialized")
+	}
+
+	userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if userCfg.Role != "admin" {
+		return nil, fmt.Errorf("only admins can dry-run commit rules")
+	}
+
+	// same source as PrepareTracecoreEnvelope, which enforces these rules
+	appCfg, err := a.DB.GetAppConfigByUserID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := tracecore_rules.NewEngine(tracecore_rules.FromCommitRules(appCfg.CommitRules), appCfg.Actors)
+	if a.Vault != nil && a.Vault.TracecoreClient != nil {
+		actors, err := a.Vault.TracecoreClient.ListRepoActors(a.ctx, appCfg.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		engine.WithRepoActors(actors)
+	}
+
+	summary := engine.DryRun(commits)
+	return &summary, nil
+}
+
+// GetCommitHistory reads the Tracecore commit history of the user's repo and
+// verifies every envelope against the repo's registered actors and
+// delegation grants. Commits whose actor or app signature does not verify,
+// or whose delegation chain is broken, are refused and reported in
+// History.Rejected; legacy-format commits are listed in History.Legacy.
+func (a *App) GetCommitHistory(jwtToken string, branch string) (*tracecore_signing.History, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.AppConfigHandler == nil {
+		return nil, fmt.Errorf("app config handler not initialized")
+	}
+
+	appCfg, err := a.AppConfigHandler.GetAppConfigByUserID(a.ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		branch = appCfg.Branch
+	}
+
+	appKey, err := blockchain.DvaultPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("app public key unavailable: %w", err)
+	}
+
+	actors, err := a.Vault.TracecoreClient.ListRepoActors(a.ctx, appCfg.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := a.Vault.TracecoreClient.ListDelegationGrants(a.ctx, appCfg.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	directory := tracecore_signing.NewRepoDirectory(actors, grants)
+	// the local key of the signed-in user is trusted over the repo copy
+	directory.AddActor(userCfg.ID, userCfg.StellarAccount.PublicKey)
+
+	commits, err := a.Vault.TracecoreClient.ListCommits(a.ctx, appCfg.RepoID, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := tracecore_signing.NewVerifier(blockchain.StellarKeyVerifier{}, directory, appKey).WithLegacySignatures()
+	history, err := verifier.ReconstructHistory(commits)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Rejected) > 0 {
+		a.Logger.Warn("⚠️ Refused %d unverifiable commit(s) for user=%s", len(history.Rejected), claims.UserID)
+	}
+	if len(history.Legacy) > 0 {
+		a.Logger.Info("Accepted %d legacy-signed commit(s) for user=%s", len(history.Legacy), claims.UserID)
+	}
+
+	return history, nil
+}
+
 // -----------------------------
 // JWT Token
 // -----------------------------
@@ -1368,505 +1766,593 @@ func (a *App) DeleteEntry(entryType string, raw json.RawMessage, jwtToken string
 	utils.LogPretty("App - DeleteEntry - res", res)
 	return res, nil
 }
-func (a *App) CreateFolder(name string, jwtToken string) (*vaults_domain.VaultPayload, error) {
+
+// ImportOTPAuthURI accepts an otpauth:// URI or a Google Authenticator
+// otpauth-migration:// export.
+func (a *App) ImportOTPAuthURI(uri string, folderID string, linkedLoginID string, jwtToken string) ([]*vaults_domain.OTPEntry, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - CreateFolder - error: %v", err)
+		a.Logger.Error("App - ImportOTPAuthURI - error: %v", err)
 		return nil, err
 	}
-	return a.Vault.CreateFolder(claims.UserID, name)
-}
-func (a *App) GetFoldersByVault(vaultCID string, jwtToken string) ([]vaults_domain.Folder, error) {
-	_, err := a.RequireAuth(jwtToken)
+	res, err := a.Vault.ImportOTPAuthURI(claims.UserID, uri, folderID, linkedLoginID)
 	if err != nil {
-		a.Logger.Error("App - GetFoldersByVault - error: %v", err)
+		a.Logger.Error("App - ImportOTPAuthURI - error: %v", err)
 		return nil, err
 	}
-	return a.Vault.GetFoldersByVault(vaultCID)
+	return res, nil
 }
-func (a *App) UpdateFolder(id string, newName string, isDraft bool, jwtToken string) (*vaults_domain.Folder, error) {
+func (a *App) GenerateOTPCode(entryID string, jwtToken string) (*vaults_domain.OTPCode, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UpdateFolder - error: %v", err)
+		a.Logger.Error("App - GenerateOTPCode - error: %v", err)
 		return nil, err
 	}
-	return a.Vault.UpdateFolder(claims.UserID, newName, isDraft)
+	res, err := a.Vault.GenerateOTPCode(claims.UserID, entryID)
+	if err != nil {
+		a.Logger.Error("App - GenerateOTPCode - error: %v", err)
+		return nil, err
+	}
+	return res, nil
 }
-func (a *App) DeleteFolder(id string, jwtToken string) (string, error) {
+
+// SearchEntries queries the encrypted search index. Clauses are ANDed and
+// support prefix (git*), fuzzy (gihtub~) and field-scoped (url:github) forms.
+func (a *App) SearchEntries(query string, opts vaults_domain.SearchOptions, jwtToken string) ([]vaults_domain.SearchHit, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - DeleteFolder - error: %v", err)
-		return "", err
+		a.Logger.Error("App - SearchEntries - error: %v", err)
+		return nil, err
 	}
-	a.Vault.DeleteFolder(claims.UserID, id)
-	return fmt.Sprintf("Folder deleted %s successfuly", id), nil
+	res, err := a.Vault.SearchEntries(claims.UserID, query, opts)
+	if err != nil {
+		a.Logger.Error("App - SearchEntries - error: %v", err)
+		return nil, err
+	}
+	return res, nil
 }
 
-// -----------------------------
-// Cloud Services
-// -----------------------------
-func (a *App) SynchronizeVault(jwtToken string, password string) (string, error) {
-	utils.LogPretty("App - SynchronizeVault - jwtToken", jwtToken) // ✅ log
+// RotateSearchIndexKey synchronizes the vault with its search index sealed
+// under a new index key. Older keys stay in the keyring for past versions.
+func (a *App) RotateSearchIndexKey(jwtToken string, password string) (string, error) {
+	return a.synchronizeVault(jwtToken, password, true)
+}
+
+// PreviewVaultImport parses a Bitwarden, 1Password, KeePass or LastPass
+// export and returns a dry-run report; nothing is written to the vault.
+func (a *App) PreviewVaultImport(format string, data []byte, password string, jwtToken string) (*vault_import_domain.Preview, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - SynchronizeVault - error: %v", err)
-		return "", err
+		a.Logger.Error("App - PreviewVaultImport - error: %v", err)
+		return nil, err
 	}
-
-	// Get Vault ==============================
-	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	res, err := a.ImportHandler.PreviewImport(context.Background(), claims.UserID, format, data, password)
 	if err != nil {
-		a.Logger.Error("App - SynchronizeVault - error: %v", err)
-		return "", err
+		a.Logger.Error("App - PreviewVaultImport - error: %v", err)
+		return nil, err
 	}
-	a.Logger.LogPretty("App - SynchronizeVault - vault", vault)
+	return res, nil
+}
 
-	// Get configs ==============================
-	cfgs, err := a.GetConfig(vault.Name, jwtToken)
+type CommitVaultImportRequest struct {
+	PreviewID      string `json:"preview_id"`
+	SkipDuplicates bool   `json:"skip_duplicates"`
+	// VaultName and Password are only used to upload attachments carried by
+	// the export.
+	VaultName string `json:"vault_name"`
+	Password  string `json:"password"`
+}
+
+// CommitVaultImport adds a previewed import to the vault session and uploads
+// its attachments. Entries are persisted by the next SyncVault.
+func (a *App) CommitVaultImport(req CommitVaultImportRequest, jwtToken string) (*vault_import_usecases.CommitImportResult, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - SynchronizeVault - error: %v", err)
-		return "", err
+		a.Logger.Error("App - CommitVaultImport - error: %v", err)
+		return nil, err
 	}
-	a.Logger.LogPretty("App - SynchronizeVault - cfgs", cfgs)
-
-	// Get User onboarding ==============================
-	userOnboarding, err := a.OnBoardingHandler.UserRepo.FindByEmail(claims.Email)
+	res, err := a.ImportHandler.CommitImport(context.Background(), claims.UserID, req.PreviewID, req.SkipDuplicates)
 	if err != nil {
-		a.Logger.Error("App - GetConfigByUserID - error: %v", err)
-		return "", err
+		a.Logger.Error("App - CommitVaultImport - error: %v", err)
+		return res, err
 	}
 
-	// Sync Vault ==============================
-	input := vault_dto.SynchronizeVaultRequest{
-		UserID:         claims.UserID,
-		Password:       password,
-		Vault:          *vault,
-		UserOnboarding: userOnboarding.ID,
-		Configs:        *cfgs,
+	hasAttachments := false
+	for _, imported := range res.Imported {
+		hasAttachments = hasAttachments || len(imported.Attachments) > 0
+	}
+	if !hasAttachments {
+		return res, nil
 	}
-	a.Vault.Ctx = a.ctx
 
-	res, err := a.Vault.SyncVault(a.ctx, input, *&a.Vault.TracecoreClient)
+	configs, err := a.GetConfig(req.VaultName, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - SynchronizeVault - error: %v", err)
-		return "", err
+		a.Logger.Error("App - CommitVaultImport - error: %v", err)
+		return res, err
 	}
-	utils.LogPretty("App - SynchronizeVault - res", res)
-	return res, err
+	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	if err != nil {
+		a.Logger.Error("App - CommitVaultImport - error: %v", err)
+		return res, err
+	}
+	for _, imported := range res.Imported {
+		for _, att := range imported.Attachments {
+			_, err := a.Vault.AddAttachement(context.Background(), vault_dto.AddAttachementRequest{
+				UserID:           claims.UserID,
+				Data:             att.Data,
+				Password:         req.Password,
+				EntryID:          imported.EntryID,
+				VaultName:        req.VaultName,
+				UserOnboardingID: userOnboarding.ID,
+				Configs:          *configs,
+				Name:             att.Name,
+				Size:             int64(len(att.Data)),
+				Ext:              att.Ext(),
+			})
+			if err != nil {
+				a.Logger.Error("App - CommitVaultImport - attachment %s: %v", att.Name, err)
+				return res, err
+			}
+			if err := a.ImportHandler.RecordImportedAttachment(context.Background(), claims.UserID, req.PreviewID, imported.SourceID); err != nil {
+				a.Logger.Error("App - CommitVaultImport - error: %v", err)
+				return res, err
+			}
+		}
+	}
+	if err := a.ImportHandler.FinishImport(context.Background(), claims.UserID, req.PreviewID); err != nil {
+		a.Logger.Error("App - CommitVaultImport - error: %v", err)
+		return res, err
+	}
+	return res, nil
 }
 
-// func (a *App) EncryptFile(jwtToken string, fileData string, password string) (string, error) {
-// 	claims, err := a.RequireAuth(jwtToken)
-// 	if err != nil {
-// 		a.Logger.Error("App - EncryptFile - error: %v", err)
-// 		return "", err
-// 	}
-
-// 	// Emit start progress
-// 	runtime.EventsEmit(a.ctx, "progress-update", map[string]interface{}{
-// 		"percent": 0,
-// 		"stage":   "encrypting",
-// 	})
-// 	a.Logger.LogPretty("App - EncryptFile - fileData", fileData)
-
-// 	// Real AES-256-GCM encryption with progress
-// 	encryptedPath, err := a.Vault.EncryptFile(claims.UserID, []byte(fileData), password)
-// 	if err != nil {
-// 		a.Logger.Error("App - EncryptFile - error: %v", err)
-// 		return "", err
-// 	}
-
-// 	runtime.EventsEmit(a.ctx, "progress-update", map[string]interface{}{
-// 		"percent": 70,
-// 		"stage":   "encrypted",
-// 	})
+type ExportVaultRequest struct {
+	Format string `json:"format"`
+	// Password protects the archive and KeePass formats.
+	Password string `json:"password"`
+	// AccountPassword re-authenticates the user for plaintext formats.
+	AccountPassword string `json:"account_password"`
+}
 
-//		return encryptedPath, nil
-//	}
-func (a *App) EncryptAttachment(jwtToken string, data []byte, password string) ([]byte, error) {
-	_, err := a.RequireAuth(jwtToken)
+// ExportVault writes the open vault to a portable file (native encrypted
+// archive, Bitwarden JSON or KeePass KDBX). Every export is audited.
+func (a *App) ExportVault(req ExportVaultRequest, jwtToken string) (*vault_export_domain.Result, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - ExportVault - error: %v", err)
 		return nil, err
 	}
-
-	return a.Vault.EncryptAttachment(data, password)
-}
-func (a *App) DecryptAttachment(jwtToken string, data []byte, password string) ([]byte, error) {
-	_, err := a.RequireAuth(jwtToken)
+	res, err := a.ExportHandler.ExportVault(context.Background(), vault_export_usecases.ExportVaultRequest{
+		UserID:          claims.UserID,
+		Email:           claims.Email,
+		Format:          vault_export_domain.Format(req.Format),
+		Password:        req.Password,
+		AccountPassword: req.AccountPassword,
+	})
 	if err != nil {
+		a.Logger.Error("App - ExportVault - error: %v", err)
 		return nil, err
 	}
-	a.Logger.Info("DecryptAttachment processing....")
+	return res, nil
+}
 
-	return a.Vault.DecryptAttachment(data, password)
+// ListVaultExports returns the export audit trail, newest first.
+func (a *App) ListVaultExports(jwtToken string) ([]vault_export_domain.ExportAudit, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - ListVaultExports - error: %v", err)
+		return nil, err
+	}
+	res, err := a.ExportHandler.ListExports(context.Background(), claims.UserID)
+	if err != nil {
+		a.Logger.Error("App - ListVaultExports - error: %v", err)
+		return nil, err
+	}
+	return res, nil
 }
 
-func (a *App) GetIPFSFile(jwtToken string, cid string, password string, vaultName string) (string, error) {
+// -----------------------------
+// SSH Agent
+// -----------------------------
+// StartSSHAgent serves the user's vault SSH keys on a Unix socket and returns
+// the path to use as SSH_AUTH_SOCK. An empty socketPath uses the default.
+func (a *App) StartSSHAgent(socketPath string, jwtToken string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - StartSSHAgent - error: %v", err)
 		return "", err
 	}
-
-	// Get Configs ==============================
-	config, err := a.GetConfig(vaultName, jwtToken)
+	path, err := a.SSHAgentHandler.Start(claims.UserID, socketPath)
 	if err != nil {
+		a.Logger.Error("App - StartSSHAgent - error: %v", err)
 		return "", err
 	}
-
-	// Get user onboarding ==============================
-	userOnboarding, err := a.OnBoardingHandler.UserRepo.FindByEmail(claims.Email)
-	if err != nil {
-		a.Logger.Error("App - GetIPFSFile - error: %v", err)
-		return "", err
-	}
-
-	ipfsQuery, err := a.Vault.GetIPFSFile(vault_queries.GetIPFSDataQuerry{
-		UserID:           claims.UserID,
-		CID:              cid,
-		Password:         password,
-		Configs:          *config,
-		VaultName:        config.Vaults.VaultName,
-		UserOnboardingID: userOnboarding.ID,
-	})
-	if err != nil {
-		a.Logger.Error("App - GetIPFSFile - error: %v", err)
-		return "", err
-	}
-
-	return base64.StdEncoding.EncodeToString(ipfsQuery), nil
+	a.Logger.Info("🔑 SSH agent listening on %s", path)
+	return path, nil
 }
-func (a *App) UploadToIPFS(jwtToken string, filePath string) (string, error) {
+func (a *App) StopSSHAgent(jwtToken string) error {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - StopSSHAgent - error: %v", err)
+		return err
 	}
-
-	// Simulate upload progress (integrate with your IPFS client for real progress)
-	current := 70
-	for i := 1; i <= 20; i++ {
-		current += 1
-		runtime.EventsEmit(a.ctx, "progress-update", current)
-		time.Sleep(50 * time.Millisecond) // Simulate; use actual IPFS progress
+	if err := a.SSHAgentHandler.Stop(claims.UserID); err != nil {
+		a.Logger.Error("App - StopSSHAgent - error: %v", err)
+		return err
 	}
-
-	cid, err := a.Vault.UploadToIPFS(claims.UserID, filePath)
-	runtime.EventsEmit(a.ctx, "progress-update", 95) // Near complete
-	if err != nil {
-		a.Logger.Error("App - UploadToIPFS - error: %v", err)
-		return "", err
+	return nil
+}
+func (a *App) GetSSHAgentStatus(jwtToken string) (ssh_agent_ui.AgentStatus, error) {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - GetSSHAgentStatus - error: %v", err)
+		return ssh_agent_ui.AgentStatus{}, err
 	}
-	return cid, nil
+	return a.SSHAgentHandler.Status(), nil
 }
-
-// From IPFS
-func (a *App) DownloadAttachment(jwtToken string, password string, cid string, ext string) (string, error) {
+func (a *App) ListSSHAgentKeys(jwtToken string) ([]ssh_agent_domain.KeyInfo, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - ListSSHAgentKeys - error: %v", err)
+		return nil, err
 	}
-
-	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	res, err := a.SSHAgentHandler.ListKeys(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - ListSSHAgentKeys - error: %v", err)
+		return nil, err
 	}
-
-	return a.Vault.DownloadAttachment(context.Background(), vault_ui.DownloadAttachmentRequest{
-		UserID:   claims.UserID,
-		Vault:    *vault,
-		CID:      cid,
-		Password: password,
-		Ext:      ext,
-	})
+	return res, nil
 }
 
-type DecryptCryptoShareResponse struct {
-	Payload      string            `json:"payload"`
-	ExpiresIn    int64             `json:"expires_in,omitempty"`
-	Attachments  map[string]string `json:"attachments"`
-	EncryptedKey string            `json:"encrypted_key"`
+type GenerateSSHKeyRequest struct {
+	Type     ssh_agent_domain.KeyType   `json:"type"`
+	Name     string                     `json:"name"`
+	Comment  string                     `json:"comment"`
+	FolderID string                     `json:"folder_id"`
+	Policy   ssh_agent_domain.KeyPolicy `json:"policy"`
 }
 
-func (a *App) AccessDecryptVaultEntry(jwtToken string, entry tracecore_types.AccessCryptoShareRequest) (*tracecore_types.CloudResponse[tracecore_types.DecryptCryptoShareResponse], error) {
+// GenerateSSHKey creates an ed25519 or ECDSA key pair directly in the vault.
+func (a *App) GenerateSSHKey(req GenerateSSHKeyRequest, jwtToken string) (*vaults_domain.SSHKeyEntry, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - GenerateSSHKey - error: %v", err)
 		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-
-	// 1. Access encrypted entry ==============================
-	entry.IPAddress = GetLocalIP()
-	res, err := a.Vault.AccessEncryptedEntry(a.ctx, claims.UserID, entry, a.Vault.TracecoreClient)
+	res, err := a.SSHAgentHandler.GenerateKey(context.Background(), ssh_agent_usecases.GenerateKeyRequest{
+		UserID:   claims.UserID,
+		Type:     req.Type,
+		Name:     req.Name,
+		Comment:  req.Comment,
+		FolderID: req.FolderID,
+		Policy:   req.Policy,
+	})
 	if err != nil {
+		a.Logger.Error("App - GenerateSSHKey - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("App - AccessEncryptedEntry - res", res)
-
-	// UserConfig - Get stellar private key from user config
-	userConfig, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	return res, nil
+}
+func (a *App) SetSSHKeyPolicy(entryID string, policy ssh_agent_domain.KeyPolicy, jwtToken string) (*vaults_domain.SSHKeyEntry, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - SetSSHKeyPolicy - error: %v", err)
 		return nil, err
 	}
-	// 2. Decrypt ==============================
-	stellarAccount := userConfig.StellarAccount
-	req := tracecore_types.DecryptCryptoShareRequest{
-		EncryptedKey:        res.Data.EncryptedKey,
-		EncryptedPayload:    res.Data.EncryptedPayload,
-		RecipientPrivateKey: stellarAccount.PrivateKey,
-	}
-	a.Logger.LogPretty("App - DecryptVaultEntry - stellarAccount", stellarAccount)
-
-	response, err := a.Vault.DecryptVaultEntry(context.Background(), req, a.Vault.TracecoreClient)
+	res, err := a.SSHAgentHandler.SetKeyPolicy(context.Background(), claims.UserID, entryID, policy)
 	if err != nil {
+		a.Logger.Error("App - SetSSHKeyPolicy - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("App - DecryptVaultEntry - Entry Snapshot decrypted response", response)
-	// TODO decrypt attachement from the response
-	a.Logger.Info("payload hex (first 32):", hex.EncodeToString([]byte(response.Data.Payload)[:32]))
-
-	utils.LogPretty("AccessDecryptVaultEntry - BEFORE DownloadAttachment loop", "start")
-	time.Sleep(100 * time.Millisecond) // give goroutines a chance to settle
-	utils.LogPretty("AccessDecryptVaultEntry - BEGINNING DownloadAttachment loop", "entered")
-
-	// 2. Parse JSON payload into VaultNode / EntrySnapshot
-	var snapshot share_domain.EntrySnapshot
-	if err := json.Unmarshal([]byte(response.Data.Payload), &snapshot); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal Payload: %w", err)
-	}
+	return res, nil
+}
 
-	// 👇 add this
-	appConfig, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
+// RespondSSHAgentConfirmation answers an "ssh-agent:confirm" event.
+func (a *App) RespondSSHAgentConfirmation(id string, approved bool, jwtToken string) error {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return nil, err
+		a.Logger.Error("App - RespondSSHAgentConfirmation - error: %v", err)
+		return err
 	}
-	response.Data.ExpiresIn = appConfig.AccessPolicyDuration
-	utils.LogPretty("App - DecryptVaultEntry - Config", appConfig)
-	// utils.LogPretty("App - DecryptVaultEntry - Final response", response.Data)
-
-	var finalResponse tracecore_types.DecryptCryptoShareResponse
-
-	finalResponse.Payload = response.Data.Payload
-	finalResponse.ExpiresIn = response.Data.ExpiresIn + 300 // 5 minutes
-	finalResponse.EncryptedKey = res.Data.EncryptedKey
-
-	return &tracecore_types.CloudResponse[tracecore_types.DecryptCryptoShareResponse]{
-		Data: finalResponse,
-	}, nil
+	if err := a.SSHAgentHandler.RespondConfirmation(claims.UserID, id, approved); err != nil {
+		a.Logger.Error("App - RespondSSHAgentConfirmation - error: %v", err)
+		return err
+	}
+	return nil
 }
 
-func (a *App) DownloadShareAttachement(req vault_dto.DownloadShareAttachmentRequest, jwtToken string) (string, error) {
+// -----------------------------
+// Git credential helper
+// -----------------------------
+// StartGitCredentialHelper answers git-credential-dvault with the user's
+// vault logins over a Unix socket and returns its path. An empty socketPath
+// uses the default. Requires the Git CLI feature of the subscription.
+func (a *App) StartGitCredentialHelper(socketPath string, jwtToken string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - DownloadShareAttachement - error: %v", err)
+		a.Logger.Error("App - StartGitCredentialHelper - error: %v", err)
 		return "", err
 	}
-	// 0. Get user config - Get stellar private key from user config ==============================
-	userConfig, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	gate := git_credential_domain.FeatureGateFunc(a.featureGate(claims.Email, func(f app_config_domain.FeatureFlags) bool {
+		return f.GitCLIEnabled
+	}))
+	path, err := a.GitCredentialHandler.Start(claims.UserID, gate, socketPath)
 	if err != nil {
+		a.Logger.Error("App - StartGitCredentialHelper - error: %v", err)
 		return "", err
 	}
-
-	// 1. Get sstellar masterkey ==============================
-	stellarAccount := userConfig.StellarAccount
-
-	// 2. Get user vault ==============================
-	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
-	if err != nil {
-		utils.LogPretty("App - DownloadShareAttachement - GetVaultByName failed", err)
-		return "", err
+	a.Logger.Info("🔑 Git credential helper listening on %s", path)
+	return path, nil
+}
+func (a *App) StopGitCredentialHelper(jwtToken string) error {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - StopGitCredentialHelper - error: %v", err)
+		return err
 	}
-
-	// 3. Get app config ==============================
-	cfgs, err := a.GetConfig(vault.Name, jwtToken)
-	if err != nil {
-		return "", fmt.Errorf("❌ DownloadShareAttachement: failed to get app config %w", err)
+	if err := a.GitCredentialHandler.Stop(); err != nil {
+		a.Logger.Error("App - StopGitCredentialHelper - error: %v", err)
+		return err
 	}
-
-	// 4. Download attachment ==============================
-	res, err := a.Vault.DownloadAttachment(context.Background(), vault_ui.DownloadAttachmentRequest{
-		UserID:       claims.UserID,
-		Vault:        *vault,
-		CID:          req.AttachmentCID,
-		Ext:          req.FileExtension,
-		Password:     "password",
-		PrivateKey:   stellarAccount.PrivateKey,
-		EncryptedKey: req.EncryptedKey,
-		Configs:      cfgs,
-		IsShared:     true,
-	})
-	if err != nil {
-		a.Logger.Error("App - DownloadShareAttachement - error: %v", err)
-		return "", err
+	return nil
+}
+func (a *App) GetGitCredentialHelperStatus(jwtToken string) (git_credential_ui.HelperStatus, error) {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - GetGitCredentialHelperStatus - error: %v", err)
+		return git_credential_ui.HelperStatus{}, err
 	}
-	utils.LogPretty("App - DownloadShareAttachement - res", res)
-	return res, nil
+	return a.GitCredentialHandler.Status(), nil
 }
 
-func (a *App) UploadAttachmentToIPFS(jwtToken string, data []uint8, password string) (string, error) {
+// -----------------------------
+// Browser extension API
+// -----------------------------
+// StartBrowserExtensionAPI serves the loopback API the browser extension
+// talks to and returns its address. An empty address uses the default.
+// Requires the browser extension feature of the subscription.
+func (a *App) StartBrowserExtensionAPI(address string, jwtToken string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		a.Logger.Error("App - StartBrowserExtensionAPI - error: %v", err)
 		return "", err
 	}
-	// Get Vault ==============================
-	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	gate := browser_extension_domain.FeatureGateFunc(a.featureGate(claims.Email, func(f app_config_domain.FeatureFlags) bool {
+		return f.BrowserExtensionEnabled
+	}))
+	addr, err := a.BrowserExtensionHandler.Start(claims.UserID, gate, address)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		a.Logger.Error("App - StartBrowserExtensionAPI - error: %v", err)
 		return "", err
 	}
-	a.Logger.LogPretty("App - UploadAttachmentToIPFS - vault", vault)
+	a.Logger.Info("🧩 Browser extension API listening on %s", addr)
+	return addr, nil
+}
+func (a *App) StopBrowserExtensionAPI(jwtToken string) error {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - StopBrowserExtensionAPI - error: %v", err)
+		return err
+	}
+	if err := a.BrowserExtensionHandler.Stop(); err != nil {
+		a.Logger.Error("App - StopBrowserExtensionAPI - error: %v", err)
+		return err
+	}
+	return nil
+}
+func (a *App) GetBrowserExtensionAPIStatus(jwtToken string) (browser_extension_ui.APIStatus, error) {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - GetBrowserExtensionAPIStatus - error: %v", err)
+		return browser_extension_ui.APIStatus{}, err
+	}
+	return a.BrowserExtensionHandler.Status(), nil
+}
 
-	// Get Configs ==============================
-	configs, err := a.GetConfig(vault.Name, jwtToken)
+// StartBrowserExtensionPairing returns the code to type into the extension.
+func (a *App) StartBrowserExtensionPairing(scopes []browser_extension_domain.Scope, jwtToken string) (*browser_extension_domain.PairingCode, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return "", err
+		a.Logger.Error("App - StartBrowserExtensionPairing - error: %v", err)
+		return nil, err
 	}
-
-	filePath, err := a.Vault.UploadAttachementToIPFS(claims.UserID, vault_ui.UploadAttachRequest{
-		Configs:            *configs,
-		Data:               data,
-		VaultName:          vault.Name,
-		UserSubscriptionID: vault.UserSubscriptionID,
-		Password:           password,
-	})
+	res, err := a.BrowserExtensionHandler.StartPairing(claims.UserID, scopes)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - StartBrowserExtensionPairing - error: %v", err)
+		return nil, err
 	}
-	return filePath, nil
+	return res, nil
 }
-func (a *App) UploadAttachmentToIPFSWithEncryption(jwtToken string, data []uint8, password string) (string, error) {
+func (a *App) ListBrowserExtensionClients(jwtToken string) ([]browser_extension_domain.PairedClient, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - ListBrowserExtensionClients - error: %v", err)
+		return nil, err
 	}
-
-	// Get Vault ==============================
-	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	res, err := a.BrowserExtensionHandler.ListClients(context.Background(), claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - ListBrowserExtensionClients - error: %v", err)
+		return nil, err
 	}
-
-	// Get Subscription ==============================
-	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	return res, nil
+}
+func (a *App) RevokeBrowserExtensionClient(clientID string, jwtToken string) error {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - RevokeBrowserExtensionClient - error: %v", err)
+		return err
+	}
+	if err := a.BrowserExtensionHandler.RevokeClient(context.Background(), claims.UserID, clientID); err != nil {
+		a.Logger.Error("App - RevokeBrowserExtensionClient - error: %v", err)
+		return err
 	}
+	return nil
+}
 
-	// Get user onboarding ==============================
-	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+// GetVaultHealthReport checks the unlocked vault for weak, reused, old and
+// breached passwords and other risks. The summary also lands in the
+// notification center.
+func (a *App) GetVaultHealthReport(jwtToken string) (*vault_health_domain.Report, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+		a.Logger.Error("App - GetVaultHealthReport - error: %v", err)
+		return nil, err
+	}
+	report, err := a.VaultHealthHandler.Report(context.Background(), claims.UserID, a.threatDetectionGate(claims.Email))
+	if err != nil && report == nil {
+		a.Logger.Error("App - GetVaultHealthReport - error: %v", err)
+		return nil, err
 	}
-	utils.LogPretty("App - UploadAttachmentToIPFS - userOnboarding", userOnboarding)
-
-	// Get Configs ==============================
-	configs, err := a.GetConfig(vault.Name, jwtToken)
 	if err != nil {
-		return "", err
+		// The report is complete; only the notification failed.
+		a.Logger.Warn("App - GetVaultHealthReport - %v", err)
 	}
+	return report, nil
+}
 
-	filePath, err := a.Vault.UploadAttachementToIPFSWithEncryption(claims.UserID, vault_ui.UploadAttachRequest{
-		Configs:            *configs,
-		Data:               data,
-		VaultName:          vault.Name,
-		UserSubscriptionID: sub.UserID, // TODO: replace with configs.Subscription.UserID
-		Password:           password,
-		UserOnboarding:     userOnboarding.ID,
-	})
-	if err != nil {
-		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
-		return "", err
+// refreshVaultHealth runs the health report after the vault is unlocked so
+// its summary is in the notification center without the user asking.
+func (a *App) refreshVaultHealth(userID string, email string) {
+	_, err := a.VaultHealthHandler.Report(context.Background(), userID, a.threatDetectionGate(email))
+	switch {
+	case errors.Is(err, vault_health_domain.ErrFeatureDisabled):
+	case err != nil:
+		a.Logger.Warn("App - refreshVaultHealth - %v", err)
 	}
+}
 
-	return filePath, nil
+func (a *App) threatDetectionGate(email string) vault_health_domain.FeatureGate {
+	return vault_health_domain.FeatureGateFunc(a.featureGate(email, func(f app_config_domain.FeatureFlags) bool {
+		return f.ThreatDetectionEnabled
+	}))
 }
 
-func (a *App) AddAttachements(jwtToken string, req vault_dto.AddAttachementsRequest) ([]*vaults_domain.Attachment, error) {
+// featureGate reports whether flag is set in the subscription of the user.
+// The gates run on the helper servers' goroutines, so unlike GetAllConfigs
+// it only reads the stored subscription config.
+func (a *App) featureGate(email string, flag func(app_config_domain.FeatureFlags) bool) func(userID string) (bool, error) {
+	return func(userID string) (bool, error) {
+		vault, err := a.Vault.GetLatestByUserID(userID)
+		if err != nil {
+			return false, err
+		}
+		subscription, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), email)
+		if err != nil {
+			return false, err
+		}
+		cfg, err := a.AppConfigHandler.GetSubscriptionConfigByUserID(subscription.UserID, vault.Name)
+		if err != nil {
+			return false, err
+		}
+		return flag(cfg.Features), nil
+	}
+}
+
+// LoadBreachCorpus loads the offline breach corpus at path, the default
+// location when empty.
+func (a *App) LoadBreachCorpus(path string, jwtToken string) (vault_health_ui.CorpusStatus, error) {
+	if _, err := a.RequireAuth(jwtToken); err != nil {
+		a.Logger.Error("App - LoadBreachCorpus - error: %v", err)
+		return vault_health_ui.CorpusStatus{}, err
+	}
+	status, err := a.VaultHealthHandler.LoadBreachCorpus(path)
+	if err != nil {
+		a.Logger.Error("App - LoadBreachCorpus - error: %v", err)
+		return vault_health_ui.CorpusStatus{}, err
+	}
+	return status, nil
+}
+func (a *App) CreateFolder(name string, jwtToken string) (*vaults_domain.VaultPayload, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - AddAttachement - error: %v", err)
+		a.Logger.Error("App - CreateFolder - error: %v", err)
 		return nil, err
 	}
-
-	configs, err := a.GetConfig(req.VaultName, jwtToken)
+	return a.Vault.CreateFolder(claims.UserID, name)
+}
+func (a *App) GetFoldersByVault(vaultCID string, jwtToken string) ([]vaults_domain.Folder, error) {
+	_, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - AddAttachement - error: %v", err)
+		a.Logger.Error("App - GetFoldersByVault - error: %v", err)
 		return nil, err
 	}
-
-	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	return a.Vault.GetFoldersByVault(vaultCID)
+}
+func (a *App) UpdateFolder(id string, newName string, isDraft bool, jwtToken string) (*vaults_domain.Folder, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - AddAttachement - error: %v", err)
+		a.Logger.Error("App - UpdateFolder - error: %v", err)
 		return nil, err
 	}
+	return a.Vault.UpdateFolder(claims.UserID, newName, isDraft)
+}
+func (a *App) DeleteFolder(id string, jwtToken string) (string, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - DeleteFolder - error: %v", err)
+		return "", err
+	}
+	a.Vault.DeleteFolder(claims.UserID, id)
+	return fmt.Sprintf("Folder deleted %s successfuly", id), nil
+}
 
-	var AddAttachementsResponse []*vaults_domain.Attachment
-
-	for _, att := range req.Attachments {
-		input := vault_dto.AddAttachementRequest{
-			UserID:           claims.UserID,
-			Data:             att.Data,
-			Password:         req.Password,
-			EntryID:          req.EntryID,
-			VaultName:        req.VaultName,
-			UserOnboardingID: userOnboarding.ID,
-			Configs:          *configs,
-			Name:             att.Name,
-			Size:             att.Size,
-			Ext:              att.Ext,
-		}
-
-		attachment, err := a.Vault.AddAttachement(context.Background(), input)
-		if err != nil {
-			a.Logger.Error("App - AddAttachements - error: %v", err)
-			return nil, err
-		}
+// -----------------------------
+// Cloud Services
+// -----------------------------
+func (a *App) SynchronizeVault(jwtToken string, password string) (string, error) {
+	return a.synchronizeVault(jwtToken, password, false)
+}
+func (a *App) synchronizeVault(jwtToken string, password string, rotateSearchIndexKey bool) (string, error) {
+	utils.LogPretty("App - SynchronizeVault - jwtToken", jwtToken) // ✅ log
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - SynchronizeVault - error: %v", err)
+		return "", err
+	}
 
-		AddAttachementsResponse = append(AddAttachementsResponse, attachment)
+	// Get Vault ==============================
+	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	if err != nil {
+		a.Logger.Error("App - SynchronizeVault - error: %v", err)
+		return "", err
+	}
+	a.Logger.LogPretty("App - SynchronizeVault - vault", vault)
 
+	// Get configs ==============================
+	cfgs, err := a.GetConfig(vault.Name, jwtToken)
+	if err != nil {
+		a.Logger.Error("App - SynchronizeVault - error: %v", err)
+		return "", err
 	}
+	a.Logger.LogPretty("App - SynchronizeVault - cfgs", cfgs)
 
-	return AddAttachementsResponse, nil
-}
-func (a *App) UpdateAttachment(jwtToken string, attachment vaults_domain.Attachment) (*vaults_domain.Attachment, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// Get User onboarding ==============================
+	userOnboarding, err := a.OnBoardingHandler.UserRepo.FindByEmail(claims.Email)
 	if err != nil {
-		a.Logger.Error("App - UpdateAttachment - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - GetConfigByUserID - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - UpdateAttachment - attachment", attachment)
 
-	res, err := a.Vault.UpdateAttachment(context.Background(), claims.UserID, attachment)
+	// Sync Vault ==============================
+	input := vault_dto.SynchronizeVaultRequest{
+		UserID:         claims.UserID,
+		Password:       password,
+		Vault:          *vault,
+		UserOnboarding: userOnboarding.ID,
+		Configs:        *cfgs,
+
+		RotateSearchIndexKey: rotateSearchIndexKey,
+	}
+	a.Vault.Ctx = a.ctx
+
+	res, err := a.Vault.SyncVault(a.ctx, input, *&a.Vault.TracecoreClient)
 	if err != nil {
-		a.Logger.Error("App - UpdateAttachment - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - SynchronizeVault - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - UpdateAttachment - res", res)
-	return res, nil
+	utils.LogPretty("App - SynchronizeVault - res", res)
+	return res, err
 }
 
-// func (a *App) CreateStellarCommit(jwtToken string, cid string) (string, error) {
-// 	claims, err := a.RequireAuth(jwtToken)
-// 	if err != nil {
-// 		a.Logger.Error("App - CreateStellarCommit - error: %v", err)
-// 		return "", err
-// 	}
-
-// 	// Quick commit with final progress
-// 	runtime.EventsEmit(a.ctx, "progress-update", 100)
-// 	return a.Vaults.CreateStellarCommit(claims.UserID, cid)
-// }
-// func (a *App) EncryptVault(jwtToken string, password string) (string, error) {
+// func (a *App) EncryptFile(jwtToken string, fileData string, password string) (string, error) {
 // 	claims, err := a.RequireAuth(jwtToken)
 // 	if err != nil {
-// 		a.Logger.Error("App - EncryptVault - error: %v", err)
+// 		a.Logger.Error("App - EncryptFile - error: %v", err)
 // 		return "", err
 // 	}
 
@@ -1875,11 +2361,12 @@ func (a *App) UpdateAttachment(jwtToken string, attachment vaults_domain.Attachm
 // 		"percent": 0,
 // 		"stage":   "encrypting",
 // 	})
+// 	a.Logger.LogPretty("App - EncryptFile - fileData", fileData)
 
 // 	// Real AES-256-GCM encryption with progress
-// 	encryptedPath, err := a.Vaults.EncryptVault(claims.UserID, password)
+// 	encryptedPath, err := a.Vault.EncryptFile(claims.UserID, []byte(fileData), password)
 // 	if err != nil {
-// 		a.Logger.Error("App - EncryptVault - error: %v", err)
+// 		a.Logger.Error("App - EncryptFile - error: %v", err)
 // 		return "", err
 // 	}
 
@@ -1890,708 +2377,574 @@ func (a *App) UpdateAttachment(jwtToken string, attachment vaults_domain.Attachm
 
 //		return encryptedPath, nil
 //	}
-func (a *App) UploadAvatar(jwtToken string, vaultName string, avatar []byte) (string, error) {
-	claims, err := a.RequireAuth(jwtToken)
-	if err != nil {
-		a.Logger.Error("App - UploadAvatar - error: %v", err)
-		return "", err
-	}
-	a.Logger.Info("App - UploadAvatar - vaultName", vaultName)
-	return a.Vault.UploadAvatar(claims.UserID, vaultName, avatar)
-}
-
-func (a *App) GetPacks(packID string) (*app_config_worker.PackDTO, error) {
-	res, err := a.Vault.TracecoreClient.GetPack(context.Background(), packID)
+func (a *App) EncryptAttachment(jwtToken string, data []byte, password string) ([]byte, error) {
+	_, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.LogPretty("GetPacks - res", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("GetPacks - res", res)
-	return res, nil
+
+	return a.Vault.EncryptAttachment(data, password)
 }
-func (a *App) GetTemplate(templateID string) (*app_config_worker.TemplateDTO, error) {
-	res, err := a.Vault.TracecoreClient.GetTemplate(context.Background(), templateID)
+func (a *App) DecryptAttachment(jwtToken string, data []byte, password string) ([]byte, error) {
+	_, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.LogPretty("GetGetTemplatePacks - res", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("GetTemplate - res", res)
-	return res, nil
+	a.Logger.Info("DecryptAttachment processing....")
+
+	return a.Vault.DecryptAttachment(data, password)
 }
-func (a *App) SimulatePackWorker(jwtToken string, vaultName string) error {
+
+func (a *App) GetIPFSFile(jwtToken string, cid string, password string, vaultName string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - SimulatePackWorker - Auth error: %v", err)
-		return err
+		return "", err
 	}
 
-	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	// Get Configs ==============================
+	config, err := a.GetConfig(vaultName, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - SimulatePackWorker - userOnboarding error: %v", err)
-		return err
+		return "", err
 	}
-	a.Logger.Info("App - SimulatePackWorker - userOnboarding: ", userOnboarding)
 
-	a.AppConfigHandler.OnApplyOnboardingPacks(claims.UserID, vaultName, userOnboarding.ID)
-	return nil
-}
-
-// -----------------------------
-// Vault Avatar - Vault Attachments
-// -----------------------------
-func (a *App) GetVaultAvatar(jwtToken string, vaultName string) (string, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// Get user onboarding ==============================
+	userOnboarding, err := a.OnBoardingHandler.UserRepo.FindByEmail(claims.Email)
 	if err != nil {
-		a.Logger.Error("App - GetVaultAvatar - error: %v", err)
+		a.Logger.Error("App - GetIPFSFile - error: %v", err)
 		return "", err
 	}
-	vault, err := a.Vault.GetVault(claims.UserID, vaultName)
+
+	ipfsQuery, err := a.Vault.GetIPFSFile(vault_queries.GetIPFSDataQuerry{
+		UserID:           claims.UserID,
+		CID:              cid,
+		Password:         password,
+		Configs:          *config,
+		VaultName:        config.Vaults.VaultName,
+		UserOnboardingID: userOnboarding.ID,
+	})
 	if err != nil {
-		a.Logger.Error("App - GetVaultAvatar - error: %v", err)
+		a.Logger.Error("App - GetIPFSFile - error: %v", err)
 		return "", err
 	}
-	a.Logger.LogPretty("App - GetVaultAvatar - vault", vault)
-	return vault.Avatar, nil
+
+	return base64.StdEncoding.EncodeToString(ipfsQuery), nil
 }
-func (a *App) LoadAvatar(jwtToken string, vaultName string) (string, error) {
+func (a *App) UploadToIPFS(jwtToken string, filePath string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - LoadAvatar - error: %v", err)
+		a.Logger.Error("App - UploadToIPFS - error: %v", err)
 		return "", err
 	}
 
-	avatar, err := a.Vault.LoadAvatar(claims.UserID, vaultName)
+	// Simulate upload progress (integrate with your IPFS client for real progress)
+	current := 70
+	for i := 1; i <= 20; i++ {
+		current += 1
+		runtime.EventsEmit(a.ctx, "progress-update", current)
+		time.Sleep(50 * time.Millisecond) // Simulate; use actual IPFS progress
+	}
+
+	cid, err := a.Vault.UploadToIPFS(claims.UserID, filePath)
+	runtime.EventsEmit(a.ctx, "progress-update", 95) // Near complete
 	if err != nil {
-		a.Logger.Error("App - LoadAvatar - error: %v", err)
+		a.Logger.Error("App - UploadToIPFS - error: %v", err)
 		return "", err
 	}
-	return avatar, nil
+	return cid, nil
 }
 
-// upload First local (then ipfs)
-func (a *App) UploadAttachments(jwtToken string, vaultName string, entryType string, raw json.RawMessage, attachments vault_dto.SelectedAttachments) (*vaults_domain.VaultEntry, error) {
+// From IPFS
+func (a *App) DownloadAttachment(jwtToken string, password string, cid string, ext string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UploadAttachment - error: %v", err)
-		return nil, err
-	}
-	a.Logger.Info("App - UploadAttachment - vaultName", vaultName)
-	ve, err := a.Vault.UpdateEntryWithAttachments(claims.UserID, entryType, raw, vaultName, attachments)
-	if err != nil {
-		a.Logger.Error("App - UploadAttachment - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
 
-	return ve, nil
-}
-func (a *App) LoadAttachment(jwtToken string, vaultName string, hash string) (string, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - LoadAttachment - error: %v", err)
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
 		return "", err
 	}
-	a.Logger.Info("App - LoadAttachment - vaultName", vaultName)
-	res, err := a.Vault.LoadAttachment(claims.UserID, vaultName, hash, "string")
-	if err != nil {
-		a.Logger.Error("App - LoadAttachment - error: %v", err)
-		return "", err
-	}
-	return res.Hash, nil
-}
-
-func (a *App) GetVault(userID string) (map[string]interface{}, error) {
-	user, err := a.Identity.FindUserById(a.ctx, userID)
-	if err != nil {
-		a.Logger.Error("App - GetVault - error: %v", err)
-		return nil, err
-	}
-	session, err := a.Vault.GetSession(userID)
-	if err != nil {
-		a.Logger.Error("App - GetVault - error: %v", err)
-		return nil, err
-	}
 
-	response := map[string]interface{}{
-		"User":                user,
-		"role":                "user",
-		"Vault":               session.Vault,
-		"SharedEntries":       []vaults_domain.VaultEntry{},
-		"VaultRuntimeContext": *session.Runtime,
-		"LastCID":             session.LastCID,
-		"Dirty":               session.Dirty,
-	}
-	return response, nil
+	return a.Vault.DownloadAttachment(context.Background(), vault_ui.DownloadAttachmentRequest{
+		UserID:   claims.UserID,
+		Vault:    *vault,
+		CID:      cid,
+		Password: password,
+		Ext:      ext,
+	})
 }
-func (a *App) GetVaultFromCloud(jwtToken string, vaultName string) (*tracecore_types.Vault, error) {
-	claims, err := a.RequireAuth(jwtToken)
-	if err != nil {
-		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
-		return nil, err
-	}
-	// FETCH SUBSCRIPTION
-	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
-	if err != nil {
-		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
-		return nil, err
-	}
-	a.Logger.LogPretty("App - GetVaultFromCloud - sub", sub)
 
-	response, err := a.Vault.GetVaultFromCloud(sub.ID)
-	if err != nil {
-		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
-		return nil, err
-	}
-	utils.LogPretty("App - GetVaultFromCloud - response", response.Data)
-	return &response.Data, nil
+type DecryptCryptoShareResponse struct {
+	Payload      string            `json:"payload"`
+	ExpiresIn    int64             `json:"expires_in,omitempty"`
+	Attachments  map[string]string `json:"attachments"`
+	EncryptedKey string            `json:"encrypted_key"`
 }
-func (a *App) GetSubscriptionFromCloud(jwtToken string, vaultName string) (*subscription_domain.Subscription, error) {
+
+func (a *App) AccessDecryptVaultEntry(jwtToken string, entry tracecore_types.AccessCryptoShareRequest) (*tracecore_types.CloudResponse[tracecore_types.DecryptCryptoShareResponse], error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
+	fmt.Println("userID", claims.UserID)
 
-	// FETCH SUBSCRIPTION
-	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	// 1. Access encrypted entry ==============================
+	entry.IPAddress = GetLocalIP()
+	res, err := a.Vault.AccessEncryptedEntry(a.ctx, claims.UserID, entry, a.Vault.TracecoreClient)
 	if err != nil {
-		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
 		return nil, err
 	}
+	a.Logger.LogPretty("App - AccessEncryptedEntry - res", res)
 
-	res, err := a.Vault.TracecoreClient.GetSubscriptionByUserID(context.Background(), sub.UserID)
+	// UserConfig - Get stellar private key from user config
+	userConfig, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
+	// 2. Decrypt ==============================
+	stellarAccount := userConfig.StellarAccount
+	req := tracecore_types.DecryptCryptoShareRequest{
+		EncryptedKey:        res.Data.EncryptedKey,
+		EncryptedPayload:    res.Data.EncryptedPayload,
+		RecipientPrivateKey: stellarAccount.PrivateKey,
+	}
+	a.Logger.LogPretty("App - DecryptVaultEntry - stellarAccount", stellarAccount)
 
-	// Get configs ==============================
-	cfgs, err := a.GetConfig(vaultName, jwtToken)
+	response, err := a.Vault.DecryptVaultEntry(context.Background(), req, a.Vault.TracecoreClient)
 	if err != nil {
-		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
+	a.Logger.LogPretty("App - DecryptVaultEntry - Entry Snapshot decrypted response", response)
+	// TODO decrypt attachement from the response
+	a.Logger.Info("payload hex (first 32):", hex.EncodeToString([]byte(response.Data.Payload)[:32]))
 
-	cfgs.Subscription = &app_config_domain.SubscriptionConfig{
-		BaseVaultConfig: app_config_domain.BaseVaultConfig{
-			ID:        res.Data.ID,
-			UserID:    res.Data.UserID,
-			VaultName: vaultName,
-		},
-		Plan: res.Data.Tier,
-		Features: app_config_domain.FeatureFlags{
-			TracecoreEnabled:        res.Data.Features.Tracecore,
-			CloudBackupEnabled:      res.Data.Features.CloudBackup,
-			ThreatDetectionEnabled:  res.Data.Features.ThreatDetection,
-			BrowserExtensionEnabled: res.Data.Features.BrowserExtension,
-			GitCLIEnabled:           res.Data.Features.GitCLI,
-		},
-		Limits: app_config_domain.SubscriptionLimits{
-			MaxVaults:  3,
-			MaxUsers:   5,
-			MaxDevices: 10,
-			MaxShares:  200,
-		},
-	}
+	utils.LogPretty("AccessDecryptVaultEntry - BEFORE DownloadAttachment loop", "start")
+	time.Sleep(100 * time.Millisecond) // give goroutines a chance to settle
+	utils.LogPretty("AccessDecryptVaultEntry - BEGINNING DownloadAttachment loop", "entered")
 
-	utils.LogPretty("App - GetSubscriptionFromCloud - cfgs", &cfgs)
+	// 2. Parse JSON payload into VaultNode / EntrySnapshot
+	var snapshot share_domain.EntrySnapshot
+	if err := json.Unmarshal([]byte(response.Data.Payload), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Payload: %w", err)
+	}
 
-	err = a.AppConfigHandler.SubscriptionConfigRepository.Update(cfgs.Subscription.ID, cfgs.Subscription)
+	// 👇 add this
+	appConfig, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
+	response.Data.ExpiresIn = appConfig.AccessPolicyDuration
+	utils.LogPretty("App - DecryptVaultEntry - Config", appConfig)
+	// utils.LogPretty("App - DecryptVaultEntry - Final response", response.Data)
 
-	utils.LogPretty("App - GetSubscriptionFromCloud - response", res.Data)
-	return &res.Data, nil
-}
+	var finalResponse tracecore_types.DecryptCryptoShareResponse
 
-// -----------------------------
-// Link shares
-// -----------------------------
-type CreateLinkShareOutput struct {
-	Data   *share_entry_domain.LinkShare `json:"data"`
-	Status string                        `json:"status"`
-	Error  string                        `json:"error"`
-	Code   string                        `json:"code"`
+	finalResponse.Payload = response.Data.Payload
+	finalResponse.ExpiresIn = response.Data.ExpiresIn + 300 // 5 minutes
+	finalResponse.EncryptedKey = res.Data.EncryptedKey
+
+	return &tracecore_types.CloudResponse[tracecore_types.DecryptCryptoShareResponse]{
+		Data: finalResponse,
+	}, nil
 }
 
-func (a *App) CreateLinkShare(payload share_entry_application_dto.LinkShareCreateRequest, jwtToken string) (*CreateLinkShareOutput, error) {
+func (a *App) DownloadShareAttachement(req vault_dto.DownloadShareAttachmentRequest, jwtToken string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - CreateLinkShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - DownloadShareAttachement - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - CreateLinkShare - payload", payload)
-	output := CreateLinkShareOutput{}
-	output.Data, err = a.LinkShareHandler.CreateLinkShare(context.Background(), claims.Email, payload)
+	// 0. Get user config - Get stellar private key from user config ==============================
+	userConfig, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - CreateLinkShare - error: %v", err)
-		output.Error = err.Error()
-		output.Code = "500"
-		return nil, err
+		return "", err
 	}
-	output.Code = "200"
-	output.Status = "success"
-	return &output, nil
-}
 
-type ListLinkSharesByMeResponse struct {
-	Data       *[]tracecore.WailsLinkShare `json:"data"`
-	Status     string                      `json:"status"`
-	Error      string                      `json:"error"`
-	StatusCode string                      `json:"status_code"`
-}
+	// 1. Get sstellar masterkey ==============================
+	stellarAccount := userConfig.StellarAccount
 
-func (a *App) ListLinkSharesByMe(jwtToken string) (*ListLinkSharesByMeResponse, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// 2. Get user vault ==============================
+	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - ListLinkSharesByMe - error: %v", err)
-		return nil, err
+		utils.LogPretty("App - DownloadShareAttachement - GetVaultByName failed", err)
+		return "", err
 	}
-	res := ListLinkSharesByMeResponse{}
-	res.Data, err = a.LinkShareHandler.ListLinkSharesByMe(context.Background(), claims.Email)
+
+	// 3. Get app config ==============================
+	cfgs, err := a.GetConfig(vault.Name, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - ListLinkSharesByMe - error: %v", err)
-		res.Error = err.Error()
-		res.StatusCode = "500"
-		return nil, err
+		return "", fmt.Errorf("❌ DownloadShareAttachement: failed to get app config %w", err)
 	}
-	res.StatusCode = "200"
-	res.Status = "success"
 
-	a.Logger.LogPretty("App - ListLinkSharesByMe - res", res)
-	return &res, nil
-}
-func (a *App) ListLinkSharesWithMe(jwtToken string) (*[]tracecore.WailsLinkShare, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// 4. Download attachment ==============================
+	res, err := a.Vault.DownloadAttachment(context.Background(), vault_ui.DownloadAttachmentRequest{
+		UserID:       claims.UserID,
+		Vault:        *vault,
+		CID:          req.AttachmentCID,
+		Ext:          req.FileExtension,
+		Password:     "password",
+		PrivateKey:   stellarAccount.PrivateKey,
+		EncryptedKey: req.EncryptedKey,
+		Configs:      cfgs,
+		IsShared:     true,
+	})
 	if err != nil {
-		a.Logger.Error("App - ListLinkSharesWithMe - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - DownloadShareAttachement - error: %v", err)
+		return "", err
 	}
-	return a.LinkShareHandler.ListLinkSharesWithMe(context.Background(), claims.Email)
-}
-
-// func (a *App) DeleteLinkShare(jwtToken string, shareID string) (string, error) {
-// 	claims, err := a.RequireAuth(jwtToken)
-// 	if err != nil {
-// 		return "", err
-// 	}
-// 	return a.Vaults.DeleteLinkShare(claims.UserID, shareID)
-// }
-
-// -----------------------------
-// Cryptographic shares
-// -----------------------------
-type CreateShareInput struct {
-	Payload  share_entry_application_dto.CreateShareEntryPayload `json:"payload"`
-	JwtToken string                                              `json:"jwtToken"`
+	utils.LogPretty("App - DownloadShareAttachement - res", res)
+	return res, nil
 }
 
-func (a *App) CreateShare(input CreateShareInput) (*share_entry_domain.ShareEntry, error) {
-	claims, err := a.RequireAuth(input.JwtToken)
+func (a *App) UploadAttachmentToIPFS(jwtToken string, data []uint8, password string) (string, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - CreateShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
-
-	a.Logger.LogPretty("App - CreateShare - AttachmentCIDs", input.Payload.AttachmentCIDs)
-
-	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	// Get Vault ==============================
+	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
 	if err != nil {
-		a.Logger.Error("App - CreateShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
+	a.Logger.LogPretty("App - UploadAttachmentToIPFS - vault", vault)
 
-	_, _, configs, err := a.GetAllConfigs(claims.UserID, claims.Email)
+	// Get Configs ==============================
+	configs, err := a.GetConfig(vault.Name, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - CreateShare - error: %v", err)
-		return nil, err
+		return "", err
 	}
 
-	subscription, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	filePath, err := a.Vault.UploadAttachementToIPFS(claims.UserID, vault_ui.UploadAttachRequest{
+		Configs:            *configs,
+		Data:               data,
+		VaultName:          vault.Name,
+		UserSubscriptionID: vault.UserSubscriptionID,
+		Password:           password,
+	})
 	if err != nil {
-		a.Logger.Error("App - CreateShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
-
-	return a.CryptographicShareHandler.CreateShareEntry(
-		context.Background(),
-		input.Payload,
-		claims.UserID,
-		claims.Email,
-		a.AppConfigHandler,
-		a.config.ANCHORA_SECRET,
-		a.Vault,
-		a.Vault.TracecoreClient,
-		userOnboarding.ID,
-		*configs,
-		subscription.UserID,
-	)
+	return filePath, nil
 }
-
-// Cryptographic share by me
-func (a *App) ListSharedEntries(jwtToken string) (*[]share_entry_domain.ShareEntry, error) {
+func (a *App) UploadAttachmentToIPFSWithEncryption(jwtToken string, data []uint8, password string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - ListSharedEntries - error: %v", err)
-		return nil, fmt.Errorf("ListSharedEntries - auth failed: %w", err)
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
 
-	if a.ShareEntryHandler == nil {
-		a.Logger.LogPretty("App - ListSharedEntries - ShareEntryHandler is nil", nil)
-		return nil, errors.New("ShareEntryHandler is nil")
+	// Get Vault ==============================
+	vault, err := a.Vault.VaultRepository.GetLatestByUserID(claims.UserID)
+	if err != nil {
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - ListSharedEntries - claims", claims.Email)
 
-	entries, err := a.CryptographicShareHandler.ListSharedEntries(context.Background(), claims.Email)
+	// Get Subscription ==============================
+	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
 	if err != nil {
-		a.Logger.Error("App - ListSharedEntries - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
 
-	return &entries, nil
-}
-
-// Cryptographic share with by me
-func (a *App) ListReceivedShares(jwtToken string) (*[]share_entry_domain.ShareEntry, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// Get user onboarding ==============================
+	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
 	if err != nil {
-		a.Logger.Error("App - ListReceivedShares - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
+	utils.LogPretty("App - UploadAttachmentToIPFS - userOnboarding", userOnboarding)
 
-	entries, err := a.CryptographicShareHandler.ListReceivedShares(context.Background(), claims.Email)
+	// Get Configs ==============================
+	configs, err := a.GetConfig(vault.Name, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - ListReceivedShares - error: %v", err)
-		return nil, err
+		return "", err
 	}
 
-	return &entries, nil // Wails wants pointer
-}
-func (a *App) GetShareForAccept(jwt, shareID string) (*share_entry_domain.ShareAcceptData, error) {
-	claims, err := a.RequireAuth(jwt)
+	filePath, err := a.Vault.UploadAttachementToIPFSWithEncryption(claims.UserID, vault_ui.UploadAttachRequest{
+		Configs:            *configs,
+		Data:               data,
+		VaultName:          vault.Name,
+		UserSubscriptionID: sub.UserID, // TODO: replace with configs.Subscription.UserID
+		Password:           password,
+		UserOnboarding:     userOnboarding.ID,
+	})
 	if err != nil {
-		a.Logger.Error("App - GetShareForAccept - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAttachmentToIPFS - error: %v", err)
+		return "", err
 	}
 
-	return a.CryptographicShareHandler.CryptographicShareUseCase.GetShareForAccept(
-		context.Background(),
-		claims.UserID,
-		shareID,
-	)
+	return filePath, nil
 }
-func (a *App) AddReceiver(jwtToken string, inputReceiver share_entry_use_cases.AddReceiverInput) (*share_entry_use_cases.AddReceiverResult, error) {
+
+func (a *App) AddAttachements(jwtToken string, req vault_dto.AddAttachementsRequest) ([]*vaults_domain.Attachment, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - AddReceiver - error: %v", err)
+		a.Logger.Error("App - AddAttachement - error: %v", err)
 		return nil, err
 	}
 
-	return a.CryptographicShareHandler.CryptographicShareUseCase.AddReceiver(context.Background(), claims.UserID, inputReceiver)
-}
-
-func (a *App) AddRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
-	claims, err := a.RequireAuth(jwtToken)
+	configs, err := a.GetConfig(req.VaultName, jwtToken)
 	if err != nil {
-		a.Logger.Error("App - AddRecipient - error: %v", err)
+		a.Logger.Error("App - AddAttachement - error: %v", err)
 		return nil, err
 	}
 
-	var addRecipRequest share_entry_application_dto.AddRecipientRequest
-	if err := json.Unmarshal(raw, &addRecipRequest); err != nil {
-		a.Logger.Error("App - AddRecipient - error: %v", err)
+	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	if err != nil {
+		a.Logger.Error("App - AddAttachement - error: %v", err)
 		return nil, err
 	}
-	return a.CryptographicShareHandler.CryptographicShareUseCase.AddRecipient(
-		context.Background(),
-		claims.UserID,
-		addRecipRequest,
-		a.AppConfigHandler,
-		a.config.ANCHORA_SECRET,
-	)
-}
 
-func (a *App) UpdateRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+	var AddAttachementsResponse []*vaults_domain.Attachment
+
+	for _, att := range req.Attachments {
+		input := vault_dto.AddAttachementRequest{
+			UserID:           claims.UserID,
+			Data:             att.Data,
+			Password:         req.Password,
+			EntryID:          req.EntryID,
+			VaultName:        req.VaultName,
+			UserOnboardingID: userOnboarding.ID,
+			Configs:          *configs,
+			Name:             att.Name,
+			Size:             att.Size,
+			Ext:              att.Ext,
+		}
+
+		attachment, err := a.Vault.AddAttachement(context.Background(), input)
+		if err != nil {
+			a.Logger.Error("App - AddAttachements - error: %v", err)
+			return nil, err
+		}
+
+		AddAttachementsResponse = append(AddAttachementsResponse, attachment)
+
+	}
+
+	return AddAttachementsResponse, nil
+}
+func (a *App) UpdateAttachment(jwtToken string, attachment vaults_domain.Attachment) (*vaults_domain.Attachment, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - UpdateRecipient - error: %v", err)
+		a.Logger.Error("App - UpdateAttachment - error: %v", err)
 		return nil, err
 	}
+	a.Logger.LogPretty("App - UpdateAttachment - attachment", attachment)
 
-	var updateRecipRequest share_entry_application_dto.UpdateRecipientRequest
-	if err := json.Unmarshal(raw, &updateRecipRequest); err != nil {
-		a.Logger.Error("App - UpdateRecipient - error: %v", err)
+	res, err := a.Vault.UpdateAttachment(context.Background(), claims.UserID, attachment)
+	if err != nil {
+		a.Logger.Error("App - UpdateAttachment - error: %v", err)
 		return nil, err
 	}
-	return a.CryptographicShareHandler.UpdateRecipient(context.Background(), claims.UserID, updateRecipRequest)
+	a.Logger.LogPretty("App - UpdateAttachment - res", res)
+	return res, nil
 }
 
-func (a *App) RevokeRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+// func (a *App) CreateStellarCommit(jwtToken string, cid string) (string, error) {
+// 	claims, err := a.RequireAuth(jwtToken)
+// 	if err != nil {
+// 		a.Logger.Error("App - CreateStellarCommit - error: %v", err)
+// 		return "", err
+// 	}
+
+// 	// Quick commit with final progress
+// 	runtime.EventsEmit(a.ctx, "progress-update", 100)
+// 	return a.Vaults.CreateStellarCommit(claims.UserID, cid)
+// }
+// func (a *App) EncryptVault(jwtToken string, password string) (string, error) {
+// 	claims, err := a.RequireAuth(jwtToken)
+// 	if err != nil {
+// 		a.Logger.Error("App - EncryptVault - error: %v", err)
+// 		return "", err
+// 	}
+
+// 	// Emit start progress
+// 	runtime.EventsEmit(a.ctx, "progress-update", map[string]interface{}{
+// 		"percent": 0,
+// 		"stage":   "encrypting",
+// 	})
+
+// 	// Real AES-256-GCM encryption with progress
+// 	encryptedPath, err := a.Vaults.EncryptVault(claims.UserID, password)
+// 	if err != nil {
+// 		a.Logger.Error("App - EncryptVault - error: %v", err)
+// 		return "", err
+// 	}
+
+// 	runtime.EventsEmit(a.ctx, "progress-update", map[string]interface{}{
+// 		"percent": 70,
+// 		"stage":   "encrypted",
+// 	})
+
+//		return encryptedPath, nil
+//	}
+func (a *App) UploadAvatar(jwtToken string, vaultName string, avatar []byte) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - RevokeRecipient - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - UploadAvatar - error: %v", err)
+		return "", err
 	}
+	a.Logger.Info("App - UploadAvatar - vaultName", vaultName)
+	return a.Vault.UploadAvatar(claims.UserID, vaultName, avatar)
+}
 
-	var revokeRecipRequest share_entry_application_dto.UpdateRecipientRequest
-	if err := json.Unmarshal(raw, &revokeRecipRequest); err != nil {
-		a.Logger.Error("App - RevokeRecipient - error: %v", err)
+func (a *App) GetPacks(packID string) (*app_config_worker.PackDTO, error) {
+	res, err := a.Vault.TracecoreClient.GetPack(context.Background(), packID)
+	if err != nil {
+		a.Logger.LogPretty("GetPacks - res", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("App - RevokeRecipient - request: %v", revokeRecipRequest)
-	return a.CryptographicShareHandler.RevokeRecipient(context.Background(), claims.UserID, revokeRecipRequest)
+	a.Logger.LogPretty("GetPacks - res", res)
+	return res, nil
 }
-func (a *App) AcceptShare(jwtToken string, shareID string, intentID string) (*tracecore_types.CloudResponse[tracecore_types.PendingShareIntent], error) {
-	claims, err := a.RequireAuth(jwtToken)
+func (a *App) GetTemplate(templateID string) (*app_config_worker.TemplateDTO, error) {
+	res, err := a.Vault.TracecoreClient.GetTemplate(context.Background(), templateID)
 	if err != nil {
-		a.Logger.Error("App - AcceptShare - error: %v", err)
+		a.Logger.LogPretty("GetGetTemplatePacks - res", err)
 		return nil, err
 	}
-	return a.CryptographicShareHandler.AcceptShare(context.Background(), shareID, intentID, claims.Email)
+	a.Logger.LogPretty("GetTemplate - res", res)
+	return res, nil
 }
-func (a *App) RejectShare(jwtToken string, shareID string, intentID string) (*tracecore_types.CloudResponse[tracecore_types.PendingShareIntent], error) {
+func (a *App) SimulatePackWorker(jwtToken string, vaultName string) error {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - RejectShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - SimulatePackWorker - Auth error: %v", err)
+		return err
 	}
 
-	return a.CryptographicShareHandler.RejectShare(context.Background(), shareID, intentID, claims.Email)
-}
-func (a *App) RevokeShare(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
-	claims, err := a.RequireAuth(jwtToken)
+	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
 	if err != nil {
-		a.Logger.Error("App - RevokeShare - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - SimulatePackWorker - userOnboarding error: %v", err)
+		return err
 	}
+	a.Logger.Info("App - SimulatePackWorker - userOnboarding: ", userOnboarding)
 
-	var revokeShareRequest share_entry_application_dto.UpdateRecipientRequest
-	if err := json.Unmarshal(raw, &revokeShareRequest); err != nil {
-		a.Logger.Error("App - RevokeShare - error: %v", err)
-		return nil, err
-	}
-	a.Logger.LogPretty("App - RevokeShare - request: %v", revokeShareRequest)
-	return a.CryptographicShareHandler.RevokeShare(context.Background(), claims.UserID, revokeShareRequest, a.AppConfigHandler)
+	a.AppConfigHandler.OnApplyOnboardingPacks(claims.UserID, vaultName, userOnboarding.ID)
+	return nil
 }
 
-func (a *App) ListPendingIntentSharesByMe(jwtToken string) (*tracecore_types.CloudResponse[[]tracecore_types.PendingShareIntent], error) {
+// -----------------------------
+// Vault Avatar - Vault Attachments
+// -----------------------------
+func (a *App) GetVaultAvatar(jwtToken string, vaultName string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - ListPendingIntentSharesByMe - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - GetVaultAvatar - error: %v", err)
+		return "", err
 	}
-	res, err := a.CryptographicShareHandler.ListPendingIntentSharesByMe(context.Background(), claims.Email)
+	vault, err := a.Vault.GetVault(claims.UserID, vaultName)
 	if err != nil {
-		a.Logger.Error("App - ListPendingIntentSharesByMe - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - GetVaultAvatar - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - ListPendingIntentSharesByMe - res", res)
-	return res, nil
+	a.Logger.LogPretty("App - GetVaultAvatar - vault", vault)
+	return vault.Avatar, nil
 }
-func (a *App) ListPendingIntentSharesWithMe(jwtToken string) (*tracecore_types.CloudResponse[[]tracecore_types.PendingShareIntent], error) {
+func (a *App) LoadAvatar(jwtToken string, vaultName string) (string, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("App - ListPendingIntentSharesWithMe - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - LoadAvatar - error: %v", err)
+		return "", err
 	}
-	res, err := a.CryptographicShareHandler.ListPendingIntentSharesWithMe(context.Background(), claims.Email)
+
+	avatar, err := a.Vault.LoadAvatar(claims.UserID, vaultName)
 	if err != nil {
-		a.Logger.Error("App - ListPendingIntentSharesWithMe - error: %v", err)
-		return nil, err
+		a.Logger.Error("App - LoadAvatar - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("App - ListPendingIntentSharesWithMe - res", res)
-	return res, nil
-}
-
-// -----------------------------
-// Vault Config
-// -----------------------------
-type GenerateApiKeyInput struct {
-	Password string `json:"password"`
-	JwtToken string `json:"jwtToken"`
-}
-type GenerateApiKeyOutput struct {
-	PublicKey  string `json:"public_key"`
-	PrivateKey string `json:"private_key"`
+	return avatar, nil
 }
 
-func (a *App) GenerateApiKey(input GenerateApiKeyInput) (*GenerateApiKeyOutput, error) {
-	claims, err := a.RequireAuth(input.JwtToken)
+// upload First local (then ipfs)
+func (a *App) UploadAttachments(jwtToken string, vaultName string, entryType string, raw json.RawMessage, attachments vault_dto.SelectedAttachments) (*vaults_domain.VaultEntry, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+		a.Logger.Error("App - UploadAttachment - error: %v", err)
 		return nil, err
 	}
-	userID := claims.UserID
-	a.Logger.Info("GenerateApiKey - user id %s:", userID)
-
-	// -------------------------------------------------------------------------------------------------
-	// Stellar - Create Stellar account keypair with no friendbot funding
-	// -------------------------------------------------------------------------------------------------
-	// account, err := a.StellarService.OnGenerateApiKey(input.Password)
-	keyEnc := vault_infrastructure_crypto.NewKeyService()
-	account, err := keyEnc.CreateAccount("password")
+	a.Logger.Info("App - UploadAttachment - vaultName", vaultName)
+	ve, err := a.Vault.UpdateEntryWithAttachments(claims.UserID, entryType, raw, vaultName, attachments)
 	if err != nil {
-		a.Logger.Error("❌ GenerateApiKey - Stellar account creation failed: %v", err)
+		a.Logger.Error("App - UploadAttachment - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("✅ GenerateApiKey - Stellar account Keypair created & funded: %s", account)
 
-	// -------------------------------------------------------------------------------------------------
-	// Identity - save stellar public key to user identity
-	// -------------------------------------------------------------------------------------------------
-	identityUser, err := a.Identity.OnGenerateApiKey(a.ctx, userID, account.PublicKey)
+	return ve, nil
+}
+func (a *App) LoadAttachment(jwtToken string, vaultName string, hash string) (string, error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		a.Logger.Error("❌ App - GenerateApiKey - failed to find user %s: %v", userID, err)
-		return nil, err
+		a.Logger.Error("App - LoadAttachment - error: %v", err)
+		return "", err
 	}
-	a.Logger.LogPretty("✅ App - GenerateApiKey - identity user updated: %s", identityUser)
-
-	// -------------------------------------------------------------------------------------------------
-	// UserConfig - Save stellarAccount in user config
-	// -------------------------------------------------------------------------------------------------
-	stellarAccount := app_config_domain.NewStellarAccountConfigOnGeneratedApiKey(account)
-	updatedUserCfg, err := a.AppConfigHandler.OnGenerateApiKey(userID, stellarAccount)
+	a.Logger.Info("App - LoadAttachment - vaultName", vaultName)
+	res, err := a.Vault.LoadAttachment(claims.UserID, vaultName, hash, "string")
 	if err != nil {
-		a.Logger.Error("❌ GenerateApiKey - Failed to update user config: %v", err)
-		return nil, err
+		a.Logger.Error("App - LoadAttachment - error: %v", err)
+		return "", err
 	}
-	a.Logger.Debug("✅ GenerateApiKey - User config updated: %s", updatedUserCfg)
+	return res.Hash, nil
+}
 
-	// -------------------------------------------------------------------------------------------------
-	// Vault - save UserConfig to user vault
-	// -------------------------------------------------------------------------------------------------
-	if err := a.Vault.OnGenerateApiKey(context.Background(), vault_ui.OnGenerateApiKeyParams{
-		UserID:     userID,
-		UserConfig: *updatedUserCfg,
-	}); err != nil {
-		a.Logger.Error("❌ App - GenerateApiKey - failed to find user %s: %v", userID, err)
+func (a *App) GetVault(userID string) (map[string]interface{}, error) {
+	user, err := a.Identity.FindUserById(a.ctx, userID)
+	if err != nil {
+		a.Logger.Error("App - GetVault - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("✅ App - GenerateApiKey - user session updated: %s", updatedUserCfg)
-
-	// -------------------------------------------------------------------------------------------------
-	// Ankhora cloud - add public key to customer
-	// -------------------------------------------------------------------------------------------------
-	response, err := a.Vault.TracecoreClient.AddPublicKeyToCustomer(context.Background(), tracecore_types.AddPublicKeyToCustomerRequest{
-		PublicKey: account.PublicKey,
-		Email:     identityUser.Email,
-	})
+	session, err := a.Vault.GetSession(userID)
 	if err != nil {
-		a.Logger.Error("❌ App - GenerateApiKey - failed to add public key to customer: %v", err)
+		a.Logger.Error("App - GetVault - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("✅ App - GenerateApiKey - public key added to customer: %s", response)
-
-	return &GenerateApiKeyOutput{
-		PublicKey:  account.PublicKey,
-		PrivateKey: account.PrivateKey,
-	}, nil
-}
 
-func (a *App) IsVaultDirty(jwtToken string) (bool, error) {
-	claims, err := a.RequireAuth(jwtToken)
-	if err != nil {
-		return false, err
+	response := map[string]interface{}{
+		"User":                user,
+		"role":                "user",
+		"Vault":               session.Vault,
+		"SharedEntries":       []vaults_domain.VaultEntry{},
+		"VaultRuntimeContext": *session.Runtime,
+		"LastCID":             session.LastCID,
+		"Dirty":               session.Dirty,
 	}
-	return a.Vault.IsMarkedDirty(claims.UserID), nil
+	return response, nil
 }
-
-// -----------------------------
-// Billing - Subscription
-// -----------------------------
-// GetPendingPaymentRequests returns all pending payment requests for current user
-func (a *App) GetPendingPaymentRequests(jwtToken string) ([]*billing_domain.PaymentRequest, error) {
+func (a *App) GetVaultFromCloud(jwtToken string, vaultName string) (*tracecore_types.Vault, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
 		return nil, err
 	}
-	return a.BillingHandler.GetPendingPaymentRequestsByUserID(a.ctx, claims.UserID)
-}
-
-type ClientPaymentRequest struct {
-	PaymentRequestID      string `json:"payment_request_id"`
-	StripePaymentMethodID string `json:"stripe_payment_method_id"`
-}
-
-// ProcessEncryptedPayment processes payment using decrypted card data
-func (a *App) ProcessEncryptedPayment(req *ClientPaymentRequest) error {
-	// return a.billingService.HandleClientInitiatedPayment(a.ctx, req)
-	fmt.Println("✅ ProcessEncryptedPayment")
-	return nil
-}
-
-type Subscription struct {
-	ID          string `json:"id"`
-	Amount      int    `json:"amount"`
-	Currency    string `json:"currency"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	UserID      string `json:"user_id"`
-}
-
-// GetSubscriptionDetails returns current subscription details
-func (a *App) GetSubscriptionDetails(jwtToken string) (*Subscription, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// FETCH SUBSCRIPTION
+	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
 	if err != nil {
+		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
 		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-	// return a.subscriptionService.GetActiveSubscription(a.ctx, claims.UserID)
-	return &Subscription{
-		ID:          "1",
-		Amount:      10,
-		Currency:    "USD",
-		Description: "Monthly subscription",
-		Status:      "active",
-		UserID:      claims.UserID,
-	}, nil
-}
-
-// CancelSubscription cancels current subscription
-func (a *App) CancelSubscription(jwtToken string, reason string) error {
-	claims, err := a.RequireAuth(jwtToken)
-	if err != nil {
-		return err
-	}
-	fmt.Println("userID", claims.UserID)
-	// return a.subscriptionService.CancelSubscription(a.ctx, claims.UserID, reason)
-	return nil
-}
-
-type UpdatePaymentMethodRequest struct {
-	UserID        string `json:"user_id"`
-	PaymentMethod string `json:"payment_method"`
-}
+	a.Logger.LogPretty("App - GetVaultFromCloud - sub", sub)
 
-// UpdatePaymentMethod updates payment method for subscription
-func (a *App) UpdatePaymentMethod(jwtToken string, req *UpdatePaymentMethodRequest) error {
-	claims, err := a.RequireAuth(jwtToken)
+	response, err := a.Vault.GetVaultFromCloud(sub.ID)
 	if err != nil {
-		return err
+		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
+		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-	req.UserID = claims.UserID
-	// return a.subscriptionService.UpdatePaymentMethod(a.ctx, req)
-	return nil
-}
-
-type PaymentHistory struct {
-	ID          string `json:"id"`
-	Amount      int    `json:"amount"`
-	Currency    string `json:"currency"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	UserID      string `json:"user_id"`
+	utils.LogPretty("App - GetVaultFromCloud - response", response.Data)
+	return &response.Data, nil
 }
-
-// GetBillingHistory returns payment history
-func (a *App) GetBillingHistory(jwtToken string, limit int) (*tracecore_types.CloudResponse[[]tracecore_types.PaymentHistory], error) {
+func (a *App) GetSubscriptionFromCloud(jwtToken string, vaultName string) (*subscription_domain.Subscription, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
+
 	// FETCH SUBSCRIPTION
 	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
 	if err != nil {
@@ -2599,444 +2952,1691 @@ func (a *App) GetBillingHistory(jwtToken string, limit int) (*tracecore_types.Cl
 		return nil, err
 	}
 
-	response, err := a.BillingHandler.GetBillingHistoryByUserID(a.ctx, sub.UserID, limit)
+	res, err := a.Vault.TracecoreClient.GetSubscriptionByUserID(context.Background(), sub.UserID)
 	if err != nil {
+		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
-	a.Logger.LogPretty("✅ App - GetBillingHistory - payment history fetched: %v", response)
-	return response, nil
-}
-
-type Receipt struct {
-	ID          string `json:"id"`
-	Amount      int    `json:"amount"`
-	Currency    string `json:"currency"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	UserID      string `json:"user_id"`
-}
 
-// DownloadReceipt downloads blockchain-verified receipt
-func (a *App) DownloadReceipt(jwtToken string, paymentID string) (*Receipt, error) {
-	claims, err := a.RequireAuth(jwtToken)
+	// Get configs ==============================
+	cfgs, err := a.GetConfig(vaultName, jwtToken)
 	if err != nil {
+		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
 		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-	// return a.billingService.GenerateReceipt(a.ctx, claims.UserID, paymentID)
-	return nil, nil
+
+	cfgs.Subscription = &app_config_domain.SubscriptionConfig{
+		BaseVaultConfig: app_config_domain.BaseVaultConfig{
+			ID:        res.Data.ID,
+			UserID:    res.Data.UserID,
+			VaultName: vaultName,
+		},
+		Plan: res.Data.Tier,
+		Features: app_config_domain.FeatureFlags{
+			TracecoreEnabled:        res.Data.Features.Tracecore,
+			CloudBackupEnabled:      res.Data.Features.CloudBackup,
+			ThreatDetectionEnabled:  res.Data.Features.ThreatDetection,
+			BrowserExtensionEnabled: res.Data.Features.BrowserExtension,
+			GitCLIEnabled:           res.Data.Features.GitCLI,
+		},
+		Limits: app_config_domain.SubscriptionLimits{
+			MaxVaults:  3,
+			MaxUsers:   5,
+			MaxDevices: 10,
+			MaxShares:  200,
+		},
+	}
+
+	utils.LogPretty("App - GetSubscriptionFromCloud - cfgs", &cfgs)
+
+	err = a.AppConfigHandler.SubscriptionConfigRepository.Update(cfgs.Subscription.ID, cfgs.Subscription)
+	if err != nil {
+		a.Logger.Error("App - GetSubscriptionFromCloud - error: %v", err)
+		return nil, err
+	}
+
+	utils.LogPretty("App - GetSubscriptionFromCloud - response", res.Data)
+	return &res.Data, nil
 }
 
-type StorageUsage struct {
-	Used   int    `json:"used"`
-	Quota  int    `json:"quota"`
-	UserID string `json:"user_id"`
+// -----------------------------
+// Link shares
+// -----------------------------
+type CreateLinkShareOutput struct {
+	Data   *share_entry_domain.LinkShare `json:"data"`
+	Status string                        `json:"status"`
+	Error  string                        `json:"error"`
+	Code   string                        `json:"code"`
 }
 
-// GetStorageUsage returns current storage usage vs quota
-func (a *App) GetStorageUsage(jwtToken string, tier subscription_domain.SubscriptionTier) (*tracecore_types.CloudResponse[tracecore_types.StorageUsageResponse], error) {
+func (a *App) CreateLinkShare(payload share_entry_application_dto.LinkShareCreateRequest, jwtToken string) (*CreateLinkShareOutput, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - CreateLinkShare - error: %v", err)
 		return nil, err
 	}
-
-	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	a.Logger.LogPretty("App - CreateLinkShare - payload", payload)
+	output := CreateLinkShareOutput{}
+	output.Data, err = a.LinkShareHandler.CreateLinkShare(context.Background(), claims.Email, payload)
 	if err != nil {
-		a.Logger.Error("App - GetStorageUsage - error: %v", err)
+		a.Logger.Error("App - CreateLinkShare - error: %v", err)
+		output.Error = err.Error()
+		output.Code = "500"
 		return nil, err
 	}
-
-	return a.SubscriptionHandler.GetStorageUsage(
-		a.ctx,
-		sub.UserID,
-		tier,
-	)
+	output.Code = "200"
+	output.Status = "success"
+	return &output, nil
 }
 
-type UpgradeRequest struct {
-	UserID        string `json:"user_id"`
-	NewTier       string `json:"new_tier"`
-	PaymentMethod string `json:"payment_method"`
+type ListLinkSharesByMeResponse struct {
+	Data       *[]tracecore.WailsLinkShare `json:"data"`
+	Status     string                      `json:"status"`
+	Error      string                      `json:"error"`
+	StatusCode string                      `json:"status_code"`
 }
 
-// UpgradeSubscription upgrades to a higher tier
-func (a *App) UpgradeSubscription(jwtToken string, req *UpgradeRequest) error {
+func (a *App) ListLinkSharesByMe(jwtToken string) (*ListLinkSharesByMeResponse, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return err
+		a.Logger.Error("App - ListLinkSharesByMe - error: %v", err)
+		return nil, err
 	}
-	req.UserID = claims.UserID
-	return a.SubscriptionHandler.HandleUpgrade(a.ctx, req.UserID, req.NewTier, req.PaymentMethod)
-}
-
-// ReactivateSubscription reactivates a cancelled subscription
-func (a *App) ReactivateSubscription(jwtToken string, tier string, paymentMethod string) error {
-	claims, err := a.RequireAuth(jwtToken)
+	res := ListLinkSharesByMeResponse{}
+	res.Data, err = a.LinkShareHandler.ListLinkSharesByMe(context.Background(), claims.Email)
 	if err != nil {
-		return err
+		a.Logger.Error("App - ListLinkSharesByMe - error: %v", err)
+		res.Error = err.Error()
+		res.StatusCode = "500"
+		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-	// return a.subscriptionService.ReactivateSubscription(a.ctx, claims.UserID, tier, paymentMethod)
-	return nil
-}
+	res.StatusCode = "200"
+	res.Status = "success"
 
-func (a *App) getCurrentUserID() string {
-	// Get user ID from session/context
-	// This would be set during authentication
-	return a.ctx.Value("user_id").(string)
+	a.Logger.LogPretty("App - ListLinkSharesByMe - res", res)
+	return &res, nil
 }
-func (a *App) GetUserVaultKey(jwtToken string) (string, error) {
+func (a *App) ListLinkSharesWithMe(jwtToken string) (*[]tracecore.WailsLinkShare, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return "", err
+		a.Logger.Error("App - ListLinkSharesWithMe - error: %v", err)
+		return nil, err
 	}
-	fmt.Println("userID", claims.UserID)
-	// return a.subscriptionService.GetUserVaultKey(a.ctx, claims.UserID)
-	return "", nil
+	return a.LinkShareHandler.ListLinkSharesWithMe(context.Background(), claims.Email)
 }
 
+// func (a *App) DeleteLinkShare(jwtToken string, shareID string) (string, error) {
+// 	claims, err := a.RequireAuth(jwtToken)
+// 	if err != nil {
+// 		return "", err
+// 	}
+// 	return a.Vaults.DeleteLinkShare(claims.UserID, shareID)
+// }
+
 // -----------------------------
-// User
+// Cryptographic shares
 // -----------------------------
-func (a *App) EditUserInfos(jwtToken string, req *identity_dtos.EditUserInfosRequest) error {
-	claims, err := a.RequireAuth(jwtToken)
-	if err != nil {
-		return err
-	}
-	fmt.Println("userID", claims.UserID)
-	// return a.Identity.EditUserInfos(a.ctx, claims.UserID, req)
-	return nil
+type CreateShareInput struct {
+	Payload  share_entry_application_dto.CreateShareEntryPayload `json:"payload"`
+	JwtToken string                                              `json:"jwtToken"`
 }
 
-func (a *App) FetchUsers() ([]models.UserDTO, error) {
-	users, err := a.OnBoardingHandler.FetchUsers()
+func (a *App) CreateShare(input CreateShareInput) (*share_entry_domain.ShareEntry, error) {
+	claims, err := a.RequireAuth(input.JwtToken)
 	if err != nil {
-		a.Logger.Error("APP - FetchUsers -failed to load all vault users", err)
+		a.Logger.Error("App - CreateShare - error: %v", err)
 		return nil, err
 	}
-	var userDTOs []models.UserDTO
-	for _, user := range users {
-		userDTOs = append(userDTOs, models.UserDTO{
-			ID:              user.ID,
-			Email:           user.Email,
-			Role:            "user",
-			LastConnectedAt: time.Now().Format("2006-01-02 15:04:05"), // Should be from the db not hardcoded
-		})
-	}
-
-	return userDTOs, nil
-}
 
-// -----------------------------
-// Websocket integration
-// -----------------------------
-func (a *App) ConnectToRealtime(user identity_domain.User) error {
-
-	a.Logger.Info("ConnectToRealtime called")
-	a.Logger.Info("ConnectToRealtime - user id: %s", user.ID)
-	a.ctx = context.WithValue(a.ctx, "user", user)
-
-	ws := realtime_client_infrastructure_websocket.NewClient(a.config.ANKHORA_WEBSOCKET_GATEWAY + "/ws/" + user.ID)
+	a.Logger.LogPretty("App - CreateShare - AttachmentCIDs", input.Payload.AttachmentCIDs)
 
-	handlers := realtime_client_handlers.RegisterHandlers(a.ctx)
+	userOnboarding, err := a.OnBoardingHandler.FindUsersUseCase.FindByEmail(claims.Email)
+	if err != nil {
+		a.Logger.Error("App - CreateShare - error: %v", err)
+		return nil, err
+	}
 
-	client := realtime_client_application_services.NewClient(handlers)
+	_, _, configs, err := a.GetAllConfigs(claims.UserID, claims.Email)
+	if err != nil {
+		a.Logger.Error("App - CreateShare - error: %v", err)
+		return nil, err
+	}
 
-	go func() {
-		err := ws.Run(a.ctx, func(msg shared_realtime.Message) error {
-			return client.Handle(a.ctx, msg)
-		})
-		if err != nil {
-			utils.LogPretty("APP - ConnectToRealtime - error: %v", err)
-		}
-	}()
+	subscription, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - CreateShare - error: %v", err)
+		return nil, err
+	}
 
-	return nil
+	return a.CryptographicShareHandler.CreateShareEntry(
+		context.Background(),
+		input.Payload,
+		claims.UserID,
+		claims.Email,
+		a.AppConfigHandler,
+		a.config.ANCHORA_SECRET,
+		a.Vault,
+		a.Vault.TracecoreClient,
+		userOnboarding.ID,
+		*configs,
+		subscription.UserID,
+	)
 }
 
-// -----------------------------
-// Notifications Center
-// -----------------------------
-func (a *App) ListByUser(jwtToken string, limit int, offset int) ([]notification_center_domain.Notification, error) {
+// Cryptographic share by me
+func (a *App) ListSharedEntries(jwtToken string) (*[]share_entry_domain.ShareEntry, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
+		a.Logger.Error("App - ListSharedEntries - error: %v", err)
+		return nil, fmt.Errorf("ListSharedEntries - auth failed: %w", err)
+	}
+
+	if a.ShareEntryHandler == nil {
+		a.Logger.LogPretty("App - ListSharedEntries - ShareEntryHandler is nil", nil)
+		return nil, errors.New("ShareEntryHandler is nil")
+	}
+	a.Logger.LogPretty("App - ListSharedEntries - claims", claims.Email)
+
+	entries, err := a.CryptographicShareHandler.ListSharedEntries(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - ListSharedEntries - error: %v", err)
 		return nil, err
 	}
 
-	return a.NotificationCenterHandler.ListByUser(context.Background(), claims.UserID, limit, offset)
+	return &entries, nil
 }
-func (a *App) CountUnread(jwtToken string) (int64, error) {
+
+// Cryptographic share with by me
+func (a *App) ListReceivedShares(jwtToken string) (*[]share_entry_domain.ShareEntry, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return 0, err
+		a.Logger.Error("App - ListReceivedShares - error: %v", err)
+		return nil, err
 	}
 
-	return a.NotificationCenterHandler.CountUnread(context.Background(), claims.UserID)
-}
-func (a *App) MarkRead(jwtToken string, id string) error {
-	_, err := a.RequireAuth(jwtToken)
+	entries, err := a.CryptographicShareHandler.ListReceivedShares(context.Background(), claims.Email)
 	if err != nil {
-		return err
+		a.Logger.Error("App - ListReceivedShares - error: %v", err)
+		return nil, err
 	}
 
-	return a.NotificationCenterHandler.MarkRead(context.Background(), id)
+	return &entries, nil // Wails wants pointer
 }
-func (a *App) Archive(jwtToken string, id string) error {
-	_, err := a.RequireAuth(jwtToken)
+func (a *App) GetShareForAccept(jwt, shareID string) (*share_entry_domain.ShareAcceptData, error) {
+	claims, err := a.RequireAuth(jwt)
 	if err != nil {
-		return err
+		a.Logger.Error("App - GetShareForAccept - error: %v", err)
+		return nil, err
 	}
-	return a.NotificationCenterHandler.Archive(context.Background(), id)
-}
-func (a *App) MarkAllRead(jwtToken string) error {
+
+	return a.CryptographicShareHandler.CryptographicShareUseCase.GetShareForAccept(
+		context.Background(),
+		claims.UserID,
+		shareID,
+	)
+}
+func (a *App) AddReceiver(jwtToken string, inputReceiver share_entry_use_cases.AddReceiverInput) (*share_entry_use_cases.AddReceiverResult, error) {
 	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		return err
+		a.Logger.Error("App - AddReceiver - error: %v", err)
+		return nil, err
 	}
-	return a.NotificationCenterHandler.MarkAllRead(context.Background(), claims.UserID)
-}
 
-// -----------------------------
-// Helpers
-// -----------------------------
+	return a.CryptographicShareHandler.CryptographicShareUseCase.AddReceiver(context.Background(), claims.UserID, inputReceiver)
+}
 
-// FlushAllSessions persists and clears all active sessions.
-func (a *App) FlushAllSessions() {
-	a.Vault.SessionsMu.Lock()
-	defer a.Vault.SessionsMu.Unlock()
-	// -----------------------------
-	// 0. ENFORCE INVARIANTS (NON-NEGOTIABLE)
-	// -----------------------------
-	if !a.Vault.HasSession() {
-		a.Logger.Info("No sessions to flush")
-		return
+func (a *App) AddRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - AddRecipient - error: %v", err)
+		return nil, err
 	}
 
-	a.Logger.Info("💾 Flushing %d active sessions...", len(a.Vault.GetAllSessions()))
-	// -----------------------------
-	// 1. FLUSH ALL SESSIONS
-	// -----------------------------
-	for userID := range a.Vault.GetAllSessions() {
-		a.Vault.SessionManager.EndSession(userID)
+	var addRecipRequest share_entry_application_dto.AddRecipientRequest
+	if err := json.Unmarshal(raw, &addRecipRequest); err != nil {
+		a.Logger.Error("App - AddRecipient - error: %v", err)
+		return nil, err
 	}
-	a.Logger.Info("✨ All sessions flushed and cleared")
+	return a.CryptographicShareHandler.CryptographicShareUseCase.AddRecipient(
+		context.Background(),
+		claims.UserID,
+		addRecipRequest,
+		a.AppConfigHandler,
+		a.config.ANCHORA_SECRET,
+	)
 }
 
-// Wails needs this to generate Entries struct in TypeScript
-func (a *App) DummyExposeEntries(e models.Entries) models.Entries {
-	return e
-}
-func loadConfig() config {
-	return config{
-		db: struct{ dsn string }{
-			dsn: os.Getenv("DB_DSN"), // or default
-		},
-		stripe: struct {
-			secret string
-			key    string
-		}{
-			secret: os.Getenv("STRIPE_SECRET"),
-			key:    os.Getenv("STRIPE_SECRET"),
-		},
-		StellarNetwork:            os.Getenv("STELLAR_NETWORK"),
-		StellarHorizonURL:         os.Getenv("STELLAR_HORIZON_URL"),
-		StellarAssetCode:          os.Getenv("STELLAR_ASSET_CODE"),
-		StellarAssetIssuer:        os.Getenv("STELLAR_ASSET_ISSUER"),
-		IPFSClient:                os.Getenv("IPFS_CLIENT"),
-		IPFSGateway:               os.Getenv("IPFS_GATEWAY"),
-		IPFSNetwork:               os.Getenv("IPFS_NETWORK"),
-		Branch:                    os.Getenv("BRANCH"),
-		EncryptionPolicy:          os.Getenv("ENCRYPTION_POLICY"),
-		TracecoreURL:              os.Getenv("TRACECORE_URL"),
-		TracecoreToken:            os.Getenv("TRACECORE_TOKEN"),
-		CloudBackURL:              os.Getenv("CLOUD_BACK_URL"),
-		CloudFrontURL:             os.Getenv("CLOUD_FRONT_URL"),
-		ANCHORA_SECRET:            os.Getenv("ANCHORA_SECRET"),
-		KEYRING_PATH:              os.Getenv("KEYRING_PATH"),
-		ANKHORA_WEBSOCKET_GATEWAY: os.Getenv("ANKHORA_WEBSOCKET_GATEWAY"),
+func (a *App) UpdateRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - UpdateRecipient - error: %v", err)
+		return nil, err
 	}
+
+	var updateRecipRequest share_entry_application_dto.UpdateRecipientRequest
+	if err := json.Unmarshal(raw, &updateRecipRequest); err != nil {
+		a.Logger.Error("App - UpdateRecipient - error: %v", err)
+		return nil, err
+	}
+	return a.CryptographicShareHandler.UpdateRecipient(context.Background(), claims.UserID, updateRecipRequest)
 }
 
-func (a *App) CheckPaymentOnResume() {
-	// status, err := a.SubscriptionRepo.GetStatusForUser()
-	// if err != nil {
-	// 	return
-	// }
+func (a *App) RevokeRecipient(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - RevokeRecipient - error: %v", err)
+		return nil, err
+	}
 
-	// if status == "active" {
-	// 	runtime.EventsEmit(a.ctx, "payment:success")
-	// }
+	var revokeRecipRequest share_entry_application_dto.UpdateRecipientRequest
+	if err := json.Unmarshal(raw, &revokeRecipRequest); err != nil {
+		a.Logger.Error("App - RevokeRecipient - error: %v", err)
+		return nil, err
+	}
+	a.Logger.LogPretty("App - RevokeRecipient - request: %v", revokeRecipRequest)
+	return a.CryptographicShareHandler.RevokeRecipient(context.Background(), claims.UserID, revokeRecipRequest)
 }
-
-func (a *App) NotifyPaymentSuccess(subID string) {
-	a.Logger.Info("✅ Subscription created successfully: %v", subID)
-	runtime.EventsEmit(a.ctx, "payment:success", subID)
+func (a *App) AcceptShare(jwtToken string, shareID string, intentID string) (*tracecore_types.CloudResponse[tracecore_types.PendingShareIntent], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - AcceptShare - error: %v", err)
+		return nil, err
+	}
+	return a.CryptographicShareHandler.AcceptShare(context.Background(), shareID, intentID, claims.Email)
 }
+func (a *App) RejectShare(jwtToken string, shareID string, intentID string) (*tracecore_types.CloudResponse[tracecore_types.PendingShareIntent], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - RejectShare - error: %v", err)
+		return nil, err
+	}
 
-func (a *App) startup(ctx context.Context) {
-	fmt.Println("App has started.")
-	a.ctx = ctx
-	// Fired every time the app regains focus
-	runtime.EventsOn(ctx, "wails:window:focus", func(_ ...interface{}) {
-		go a.CheckPaymentOnResume()
-	})
+	return a.CryptographicShareHandler.RejectShare(context.Background(), shareID, intentID, claims.Email)
 }
-
-// Simple method to open a URL in the system browser
-func (a *App) OpenGoogle() {
-	if a.ctx == nil {
-		log.Println("❌ Context not set!")
-		return
+func (a *App) RevokeShare(jwtToken string, raw json.RawMessage) (*tracecore_types.CloudResponse[tracecore.CloudCryptographicShare], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - RevokeShare - error: %v", err)
+		return nil, err
 	}
 
-	// Opens default browser to Google
-	runtime.BrowserOpenURL(a.ctx, "http://164.90.213.173:4002/checkout")
+	var revokeShareRequest share_entry_application_dto.UpdateRecipientRequest
+	if err := json.Unmarshal(raw, &revokeShareRequest); err != nil {
+		a.Logger.Error("App - RevokeShare - error: %v", err)
+		return nil, err
+	}
+	a.Logger.LogPretty("App - RevokeShare - request: %v", revokeShareRequest)
+	return a.CryptographicShareHandler.RevokeShare(context.Background(), claims.UserID, revokeShareRequest, a.AppConfigHandler)
 }
 
-func GetLocalIP() string {
-	addrs, err := net.InterfaceAddrs()
+func (a *App) ListPendingIntentSharesByMe(jwtToken string) (*tracecore_types.CloudResponse[[]tracecore_types.PendingShareIntent], error) {
+	claims, err := a.RequireAuth(jwtToken)
 	if err != nil {
-		fmt.Println("Error getting local IP:", err)
-		return "unknown"
+		a.Logger.Error("App - ListPendingIntentSharesByMe - error: %v", err)
+		return nil, err
 	}
-
-	for _, addr := range addrs {
-		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
-			if ipnet.IP.To4() != nil {
-				fmt.Println("Local IP:", ipnet.IP.String())
-				return ipnet.IP.String()
-			}
-		}
+	res, err := a.CryptographicShareHandler.ListPendingIntentSharesByMe(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - ListPendingIntentSharesByMe - error: %v", err)
+		return nil, err
 	}
-
-	return "unknown"
+	a.Logger.LogPretty("App - ListPendingIntentSharesByMe - res", res)
+	return res, nil
+}
+func (a *App) ListPendingIntentSharesWithMe(jwtToken string) (*tracecore_types.CloudResponse[[]tracecore_types.PendingShareIntent], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		a.Logger.Error("App - ListPendingIntentSharesWithMe - error: %v", err)
+		return nil, err
+	}
+	res, err := a.CryptographicShareHandler.ListPendingIntentSharesWithMe(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - ListPendingIntentSharesWithMe - error: %v", err)
+		return nil, err
+	}
+	a.Logger.LogPretty("App - ListPendingIntentSharesWithMe - res", res)
+	return res, nil
 }
 
-// main.go - FORCE clean schema
-func ResetAndMigrate(db *gorm.DB) error {
-	// Drop problematic tables
-	// db.Migrator().DropTable(&persistence.UserConfigMapper{})
-	// db.Migrator().DropTable(&app_config_domain.SharingRule{})
-	db.Migrator().DropTable(&onboarding_domain.AppState{})
-
-	// Recreate with correct types
-	return db.AutoMigrate(
-		// &persistence.UserConfigMapper{},
-		// &app_config_domain.SharingRule{},
-		// &app_config_domain.StellarAccountConfig{},
-		&onboarding_domain.AppState{},
-	)
+// -----------------------------
+// Vault Config
+// -----------------------------
+type GenerateApiKeyInput struct {
+	Password string `json:"password"`
+	JwtToken string `json:"jwtToken"`
 }
-func (a *App) SetStorageMode(JwtToken string, mode string) {
-	claims, err := a.RequireAuth(JwtToken)
+type GenerateApiKeyOutput struct {
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key"`
+}
+
+func (a *App) GenerateApiKey(input GenerateApiKeyInput) (*GenerateApiKeyOutput, error) {
+	claims, err := a.RequireAuth(input.JwtToken)
 	if err != nil {
 		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+		return nil, err
 	}
-	appCfg, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
+	userID := claims.UserID
+	a.Logger.Info("GenerateApiKey - user id %s:", userID)
+
+	// -------------------------------------------------------------------------------------------------
+	// Stellar - Create Stellar account keypair with no friendbot funding
+	// -------------------------------------------------------------------------------------------------
+	// account, err := a.StellarService.OnGenerateApiKey(input.Password)
+	keyEnc := vault_infrastructure_crypto.NewKeyService()
+	account, err := keyEnc.CreateAccount("password")
 	if err != nil {
-		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+		a.Logger.Error("❌ GenerateApiKey - Stellar account creation failed: %v", err)
+		return nil, err
 	}
-	appCfg.Storage.Mode = app_config.StorageMode(mode)
-
-	a.AppConfigHandler.UpdateAppConfig(appCfg)
-	a.Vault.UpdateAppConfig(claims.UserID, *appCfg)
+	a.Logger.LogPretty("✅ GenerateApiKey - Stellar account Keypair created & funded: %s", account)
 
-	appCfgUpdated, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
+	// -------------------------------------------------------------------------------------------------
+	// Identity - save stellar public key to user identity
+	// -------------------------------------------------------------------------------------------------
+	identityUser, err := a.Identity.OnGenerateApiKey(a.ctx, userID, account.PublicKey)
 	if err != nil {
-		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+		a.Logger.Error("❌ App - GenerateApiKey - failed to find user %s: %v", userID, err)
+		return nil, err
 	}
-	utils.LogPretty("appCfgUpdated", appCfgUpdated)
+	a.Logger.LogPretty("✅ App - GenerateApiKey - identity user updated: %s", identityUser)
+
+	// -------------------------------------------------------------------------------------------------
+	// UserConfig - Save stellarAccount in user config
+	// -------------------------------------------------------------------------------------------------
+	stellarAccount := app_config_domain.NewStellarAccountConfigOnGeneratedApiKey(account)
+	updatedUserCfg, err := a.AppConfigHandler.OnGenerateApiKey(userID, stellarAccount)
+	if err != nil {
+		a.Logger.Error("❌ GenerateApiKey - Failed to update user config: %v", err)
+		return nil, err
+	}
+	a.Logger.Debug("✅ GenerateApiKey - User config updated: %s", updatedUserCfg)
+
+	// -------------------------------------------------------------------------------------------------
+	// Vault - save UserConfig to user vault
+	// -------------------------------------------------------------------------------------------------
+	if err := a.Vault.OnGenerateApiKey(context.Background(), vault_ui.OnGenerateApiKeyParams{
+		UserID:     userID,
+		UserConfig: *updatedUserCfg,
+	}); err != nil {
+		a.Logger.Error("❌ App - GenerateApiKey - failed to find user %s: %v", userID, err)
+		return nil, err
+	}
+	a.Logger.LogPretty("✅ App - GenerateApiKey - user session updated: %s", updatedUserCfg)
+
+	// -------------------------------------------------------------------------------------------------
+	// Ankhora cloud - add public key to customer
+	// -------------------------------------------------------------------------------------------------
+	response, err := a.Vault.TracecoreClient.AddPublicKeyToCustomer(context.Background(), tracecore_types.AddPublicKeyToCustomerRequest{
+		PublicKey: account.PublicKey,
+		Email:     identityUser.Email,
+	})
+	if err != nil {
+		a.Logger.Error("❌ App - GenerateApiKey - failed to add public key to customer: %v", err)
+		return nil, err
+	}
+	a.Logger.LogPretty("✅ App - GenerateApiKey - public key added to customer: %s", response)
+
+	return &GenerateApiKeyOutput{
+		PublicKey:  account.PublicKey,
+		PrivateKey: account.PrivateKey,
+	}, nil
+}
+
+func (a *App) IsVaultDirty(jwtToken string) (bool, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return false, err
+	}
+	return a.Vault.IsMarkedDirty(claims.UserID), nil
+}
+
+// -----------------------------
+// Billing - Subscription
+// -----------------------------
+// GetPendingPaymentRequests returns all pending payment requests for current user
+func (a *App) GetPendingPaymentRequests(jwtToken string) ([]*billing_domain.PaymentRequest, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	return a.BillingHandler.GetPendingPaymentRequestsByUserID(a.ctx, claims.UserID)
+}
+
+type ClientPaymentRequest struct {
+	PaymentRequestID      string `json:"payment_request_id"`
+	StripePaymentMethodID string `json:"stripe_payment_method_id"`
+}
+
+// ProcessEncryptedPayment processes payment using decrypted card data
+func (a *App) ProcessEncryptedPayment(req *ClientPaymentRequest) error {
+	// return a.billingService.HandleClientInitiatedPayment(a.ctx, req)
+	fmt.Println("✅ ProcessEncryptedPayment")
+	return nil
+}
+
+type Subscription struct {
+	ID          string `json:"id"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	UserID      string `json:"user_id"`
+}
+
+// GetSubscriptionDetails returns current subscription details
+func (a *App) GetSubscriptionDetails(jwtToken string) (*Subscription, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.subscriptionService.GetActiveSubscription(a.ctx, claims.UserID)
+	return &Subscription{
+		ID:          "1",
+		Amount:      10,
+		Currency:    "USD",
+		Description: "Monthly subscription",
+		Status:      "active",
+		UserID:      claims.UserID,
+	}, nil
+}
+
+// CancelSubscription cancels current subscription
+func (a *App) CancelSubscription(jwtToken string, reason string) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.subscriptionService.CancelSubscription(a.ctx, claims.UserID, reason)
+	return nil
+}
+
+type UpdatePaymentMethodRequest struct {
+	UserID        string `json:"user_id"`
+	PaymentMethod string `json:"payment_method"`
+}
+
+// UpdatePaymentMethod updates payment method for subscription
+func (a *App) UpdatePaymentMethod(jwtToken string, req *UpdatePaymentMethodRequest) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	fmt.Println("userID", claims.UserID)
+	req.UserID = claims.UserID
+	// return a.subscriptionService.UpdatePaymentMethod(a.ctx, req)
+	return nil
+}
+
+type PaymentHistory struct {
+	ID          string `json:"id"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	UserID      string `json:"user_id"`
+}
+
+// GetBillingHistory returns payment history
+func (a *App) GetBillingHistory(jwtToken string, limit int) (*tracecore_types.CloudResponse[[]tracecore_types.PaymentHistory], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("userID", claims.UserID)
+	// FETCH SUBSCRIPTION
+	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - GetVaultFromCloud - error: %v", err)
+		return nil, err
+	}
+
+	response, err := a.BillingHandler.GetBillingHistoryByUserID(a.ctx, sub.UserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	a.Logger.LogPretty("✅ App - GetBillingHistory - payment history fetched: %v", response)
+	return response, nil
+}
+
+type Receipt struct {
+	ID          string `json:"id"`
+	Amount      int    `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	UserID      string `json:"user_id"`
+}
+
+// DownloadReceipt downloads blockchain-verified receipt
+func (a *App) DownloadReceipt(jwtToken string, paymentID string) (*Receipt, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.billingService.GenerateReceipt(a.ctx, claims.UserID, paymentID)
+	return nil, nil
+}
+
+type StorageUsage struct {
+	Used   int    `json:"used"`
+	Quota  int    `json:"quota"`
+	UserID string `json:"user_id"`
+}
+
+// GetStorageUsage returns current storage usage vs quota
+func (a *App) GetStorageUsage(jwtToken string, tier subscription_domain.SubscriptionTier) (*tracecore_types.CloudResponse[tracecore_types.StorageUsageResponse], error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := a.SubscriptionHandler.GetUserSubscriptionByEmail(context.Background(), claims.Email)
+	if err != nil {
+		a.Logger.Error("App - GetStorageUsage - error: %v", err)
+		return nil, err
+	}
+
+	return a.SubscriptionHandler.GetStorageUsage(
+		a.ctx,
+		sub.UserID,
+		tier,
+	)
+}
+
+type UpgradeRequest struct {
+	UserID        string `json:"user_id"`
+	NewTier       string `json:"new_tier"`
+	PaymentMethod string `json:"payment_method"`
+}
+
+// UpgradeSubscription upgrades to a higher tier
+func (a *App) UpgradeSubscription(jwtToken string, req *UpgradeRequest) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	req.UserID = claims.UserID
+	return a.SubscriptionHandler.HandleUpgrade(a.ctx, req.UserID, req.NewTier, req.PaymentMethod)
+}
+
+// ReactivateSubscription reactivates a cancelled subscription
+func (a *App) ReactivateSubscription(jwtToken string, tier string, paymentMethod string) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.subscriptionService.ReactivateSubscription(a.ctx, claims.UserID, tier, paymentMethod)
+	return nil
+}
+
+func (a *App) getCurrentUserID() string {
+	// Get user ID from session/context
+	// This would be set during authentication
+	return a.ctx.Value("user_id").(string)
+}
+func (a *App) GetUserVaultKey(jwtToken string) (string, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return "", err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.subscriptionService.GetUserVaultKey(a.ctx, claims.UserID)
+	return "", nil
+}
+
+// -----------------------------
+// User
+// -----------------------------
+func (a *App) EditUserInfos(jwtToken string, req *identity_dtos.EditUserInfosRequest) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	fmt.Println("userID", claims.UserID)
+	// return a.Identity.EditUserInfos(a.ctx, claims.UserID, req)
+	return nil
+}
+
+func (a *App) FetchUsers() ([]models.UserDTO, error) {
+	users, err := a.OnBoardingHandler.FetchUsers()
+	if err != nil {
+		a.Logger.Error("APP - FetchUsers -failed to load all vault users", err)
+		return nil, err
+	}
+	var userDTOs []models.UserDTO
+	for _, user := range users {
+		userDTOs = append(userDTOs, models.UserDTO{
+			ID:              user.ID,
+			Email:           user.Email,
+			Role:            "user",
+			LastConnectedAt: time.Now().Format("2006-01-02 15:04:05"), // Should be from the db not hardcoded
+		})
+	}
+
+	return userDTOs, nil
+}
+
+// -----------------------------
+// Websocket integration
+// -----------------------------
+func (a *App) ConnectToRealtime(user identity_domain.User) error {
+
+	a.Logger.Info("ConnectToRealtime called")
+	a.Logger.Info("ConnectToRealtime - user id: %s", user.ID)
+	a.ctx = context.WithValue(a.ctx, "user", user)
+
+	ws := realtime_client_infrastructure_websocket.NewClient(a.config.ANKHORA_WEBSOCKET_GATEWAY + "/ws/" + user.ID)
+
+	handlers := realtime_client_handlers.RegisterHandlers(a.ctx)
+	c3Invalidation := realtime_client_handlers.NewC3CacheInvalidationHandler(a.ctx, a.C3Cache)
+	handlers[shared_realtime.C3WorkspaceChanged] = c3Invalidation
+	handlers[shared_realtime.C3ChannelChanged] = c3Invalidation
+	handlers[shared_realtime.C3ThreadChanged] = c3Invalidation
+	handlers[shared_realtime.C3ThreadEventAppended] = c3Invalidation
+	channelInvitations := realtime_client_handlers.NewChannelInvitationHandler(a.ctx, a.C3Cache)
+	handlers[shared_realtime.ChannelInvitationReceived] = channelInvitations
+	handlers[shared_realtime.ChannelInvitationAccepted] = channelInvitations
+	handlers[shared_realtime.ChannelInvitationRejected] = channelInvitations
+	handlers[shared_realtime.ChannelInvitationRevoked] = channelInvitations
+	handlers[shared_realtime.ChannelInvitationExpired] = channelInvitations
+
+	client := realtime_client_application_services.NewClient(handlers)
+
+	go func() {
+		err := ws.Run(a.ctx, func(msg shared_realtime.Message) error {
+			return client.Handle(a.ctx, msg)
+		})
+		if err != nil {
+			utils.LogPretty("APP - ConnectToRealtime - error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// -----------------------------
+// Notifications Center
+// -----------------------------
+func (a *App) ListByUser(jwtToken string, limit int, offset int) ([]notification_center_domain.Notification, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.NotificationCenterHandler.ListByUser(context.Background(), claims.UserID, limit, offset)
+}
+func (a *App) CountUnread(jwtToken string) (int64, error) {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return 0, err
+	}
+
+	return a.NotificationCenterHandler.CountUnread(context.Background(), claims.UserID)
+}
+func (a *App) MarkRead(jwtToken string, id string) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+
+	return a.NotificationCenterHandler.MarkRead(context.Background(), claims.UserID, id)
+}
+func (a *App) Archive(jwtToken string, id string) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	return a.NotificationCenterHandler.Archive(context.Background(), claims.UserID, id)
+}
+func (a *App) MarkAllRead(jwtToken string) error {
+	claims, err := a.RequireAuth(jwtToken)
+	if err != nil {
+		return err
+	}
+	return a.NotificationCenterHandler.MarkAllRead(context.Background(), claims.UserID)
+}
+
+// -----------------------------
+// Helpers
+// -----------------------------
+
+// FlushAllSessions persists and clears all active sessions.
+func (a *App) FlushAllSessions() {
+	a.Vault.SessionsMu.Lock()
+	defer a.Vault.SessionsMu.Unlock()
+	// -----------------------------
+	// 0. ENFORCE INVARIANTS (NON-NEGOTIABLE)
+	// -----------------------------
+	if !a.Vault.HasSession() {
+		a.Logger.Info("No sessions to flush")
+		return
+	}
+
+	a.Logger.Info("💾 Flushing %d active sessions...", len(a.Vault.GetAllSessions()))
+	// -----------------------------
+	// 1. FLUSH ALL SESSIONS
+	// -----------------------------
+	for userID := range a.Vault.GetAllSessions() {
+		a.Vault.SessionManager.EndSession(userID)
+	}
+	a.Logger.Info("✨ All sessions flushed and cleared")
+}
+
+// Wails needs this to generate Entries struct in TypeScript
+func (a *App) DummyExposeEntries(e models.Entries) models.Entries {
+	return e
+}
+func loadConfig() config {
+	return config{
+		db: struct{ dsn string }{
+			dsn: os.Getenv("DB_DSN"), // or default
+		},
+		stripe: struct {
+			secret string
+			key    string
+		}{
+			secret: os.Getenv("STRIPE_SECRET"),
+			key:    os.Getenv("STRIPE_SECRET"),
+		},
+		StellarNetwork:            os.Getenv("STELLAR_NETWORK"),
+		StellarHorizonURL:         os.Getenv("STELLAR_HORIZON_URL"),
+		StellarAssetCode:          os.Getenv("STELLAR_ASSET_CODE"),
+		StellarAssetIssuer:        os.Getenv("STELLAR_ASSET_ISSUER"),
+		IPFSClient:                os.Getenv("IPFS_CLIENT"),
+		IPFSGateway:               os.Getenv("IPFS_GATEWAY"),
+		IPFSNetwork:               os.Getenv("IPFS_NETWORK"),
+		Branch:                    os.Getenv("BRANCH"),
+		EncryptionPolicy:          os.Getenv("ENCRYPTION_POLICY"),
+		TracecoreURL:              os.Getenv("TRACECORE_URL"),
+		TracecoreToken:            os.Getenv("TRACECORE_TOKEN"),
+		CloudBackURL:              os.Getenv("CLOUD_BACK_URL"),
+		CloudFrontURL:             os.Getenv("CLOUD_FRONT_URL"),
+		ANCHORA_SECRET:            os.Getenv("ANCHORA_SECRET"),
+		KEYRING_PATH:              os.Getenv("KEYRING_PATH"),
+		ANKHORA_WEBSOCKET_GATEWAY: os.Getenv("ANKHORA_WEBSOCKET_GATEWAY"),
+		ChannelInvitationTTL:      channelInvitationTTL(os.Getenv("CHANNEL_INVITATION_TTL")),
+		InvitationSweepInterval:   channelInvitationSweepInterval(os.Getenv("CHANNEL_INVITATION_SWEEP_INTERVAL")),
+		FederationSyncInterval:    federationSyncInterval(os.Getenv("FEDERATION_SYNC_INTERVAL")),
+		FederationEndpoint:        os.Getenv("FEDERATION_ENDPOINT"),
+	}
+}
+
+// channelInvitationTTL parses CHANNEL_INVITATION_TTL (a Go duration such as
+// "72h") and falls back to the domain default when unset or invalid.
+func channelInvitationTTL(value string) time.Duration {
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return channel_domain.DefaultInvitationTTL
+	}
+	return ttl
+}
+
+// channelInvitationSweepInterval parses CHANNEL_INVITATION_SWEEP_INTERVAL (a
+// Go duration) and defaults to 15m.
+func channelInvitationSweepInterval(value string) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 15 * time.Minute
+	}
+	return interval
+}
+
+// federationSyncInterval parses FEDERATION_SYNC_INTERVAL (a Go duration) and
+// defaults to 30s.
+func federationSyncInterval(value string) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 30 * time.Second
+	}
+	return interval
+}
+
+func (a *App) CheckPaymentOnResume() {
+	// status, err := a.SubscriptionRepo.GetStatusForUser()
+	// if err != nil {
+	// 	return
+	// }
+
+	// if status == "active" {
+	// 	runtime.EventsEmit(a.ctx, "payment:success")
+	// }
+}
+
+func (a *App) NotifyPaymentSuccess(subID string) {
+	a.Logger.Info("✅ Subscription created successfully: %v", subID)
+	runtime.EventsEmit(a.ctx, "payment:success", subID)
+}
+
+func (a *App) startup(ctx context.Context) {
+	fmt.Println("App has started.")
+	a.ctx = ctx
+	// Fired every time the app regains focus
+	runtime.EventsOn(ctx, "wails:window:focus", func(_ ...interface{}) {
+		go a.CheckPaymentOnResume()
+	})
+	// Staleness indicator: tell the UI when C3 data starts/stops coming from the offline cache
+	if a.C3Cache != nil {
+		a.C3Cache.Connectivity.OnChange(func(online bool) {
+			runtime.EventsEmit(ctx, "c3:connectivity", online)
+		})
+	}
+}
+
+// Simple method to open a URL in the system browser
+func (a *App) OpenGoogle() {
+	if a.ctx == nil {
+		log.Println("❌ Context not set!")
+		return
+	}
+
+	// Opens default browser to Google
+	runtime.BrowserOpenURL(a.ctx, "http://164.90.213.173:4002/checkout")
+}
+
+func GetLocalIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		fmt.Println("Error getting local IP:", err)
+		return "unknown"
+	}
+
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+			if ipnet.IP.To4() != nil {
+				fmt.Println("Local IP:", ipnet.IP.String())
+				return ipnet.IP.String()
+			}
+		}
+	}
+
+	return "unknown"
+}
+
+// main.go - FORCE clean schema
+func ResetAndMigrate(db *gorm.DB) error {
+	// Drop problematic tables
+	// db.Migrator().DropTable(&persistence.UserConfigMapper{})
+	// db.Migrator().DropTable(&app_config_domain.SharingRule{})
+	db.Migrator().DropTable(&onboarding_domain.AppState{})
+
+	// Recreate with correct types
+	return db.AutoMigrate(
+		// &persistence.UserConfigMapper{},
+		// &app_config_domain.SharingRule{},
+		// &app_config_domain.StellarAccountConfig{},
+		&onboarding_domain.AppState{},
+	)
+}
+func (a *App) SetStorageMode(JwtToken string, mode string) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+	}
+	appCfg, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
+	if err != nil {
+		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+	}
+	appCfg.Storage.Mode = app_config.StorageMode(mode)
+
+	a.AppConfigHandler.UpdateAppConfig(appCfg)
+	a.Vault.UpdateAppConfig(claims.UserID, *appCfg)
+
+	appCfgUpdated, err := a.AppConfigHandler.GetAppConfigByUserID(context.Background(), claims.UserID)
+	if err != nil {
+		a.Logger.Error("❌ GenerateApiKey - Failed to authenticate user: %v", err)
+	}
+	utils.LogPretty("appCfgUpdated", appCfgUpdated)
+}
+
+// C3 Wails App Methods
+
+func (a *App) CreateWorkspace(JwtToken string, vaultId string, name string, description string) (*tracecore_types.Workspace, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.CreateWorkspace(a.ctx, claims.UserID, vaultId, name, description)
+}
+
+func (a *App) ListWorkspaces(JwtToken string, vaultId string) ([]tracecore_types.Workspace, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	a.Logger.Info("Listing workspaces for user: %v, vaultId: %v", claims.UserID, vaultId)
+
+	// Restore cloud token before making the request
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		a.Logger.Error("Failed to restore cloud token: %v", err)
+	}
+
+	a.Logger.Info("☁️ [CLOUD-WORKSPACE] Listing workspaces for vault=%s", vaultId)
+
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+
+	res, err := a.WorkspaceHandler.ListWorkspaces(a.ctx, vaultId)
+	if err != nil {
+		a.Logger.Error("App.ListWorkspaces error: %v", err)
+		return nil, err
+	}
+	utils.LogPretty("[Workspace] App.ListWorkspaces final result", res)
+	return res, nil
+}
+
+func (a *App) ArchiveWorkspace(JwtToken string, workspaceID string) (*tracecore_types.Workspace, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.ArchiveWorkspace(a.ctx, a.sessionVaultID(claims.UserID), workspaceID)
+}
+
+func (a *App) RestoreWorkspace(JwtToken string, workspaceID string) (*tracecore_types.Workspace, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.RestoreWorkspace(a.ctx, a.sessionVaultID(claims.UserID), workspaceID)
+}
+
+func (a *App) AddWorkspaceMember(JwtToken string, workspaceID string, vaultID string, role string) ([]workspace_domain.WorkspaceMember, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.AddWorkspaceMember(a.ctx, a.sessionVaultID(claims.UserID), workspaceID, vaultID, role)
+}
+
+func (a *App) UpdateWorkspaceMemberRole(JwtToken string, workspaceID string, vaultID string, role string) ([]workspace_domain.WorkspaceMember, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.UpdateWorkspaceMemberRole(a.ctx, a.sessionVaultID(claims.UserID), workspaceID, vaultID, role)
+}
+
+func (a *App) RemoveWorkspaceMember(JwtToken string, workspaceID string, vaultID string) ([]workspace_domain.WorkspaceMember, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.RemoveWorkspaceMember(a.ctx, a.sessionVaultID(claims.UserID), workspaceID, vaultID)
+}
+
+func (a *App) MoveChannelToWorkspace(JwtToken string, channelID string, fromWorkspaceID string, toWorkspaceID string) (*channel_domain.Channel, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.MoveChannel(a.ctx, a.sessionVaultID(claims.UserID), channelID, fromWorkspaceID, toWorkspaceID)
+}
+
+func (a *App) SearchWorkspaces(JwtToken string, query string, includeArchived bool, limit int) ([]workspace_application.SearchHit, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.WorkspaceHandler == nil {
+		return nil, fmt.Errorf("workspace handler is not initialized")
+	}
+	return a.WorkspaceHandler.SearchWorkspaces(a.ctx, a.sessionVaultID(claims.UserID), query, includeArchived, limit)
+}
+
+func (a *App) CreateChannel(JwtToken string, workspaceID string, title string, templateID string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy map[string]interface{}, federation string) (*tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.CreateChannel(a.ctx, claims.UserID, workspaceID, title, templateID, slots, assignments, properties, policy, federation)
+}
+
+// ListChannelTemplates returns the channel template catalog: the Cloud
+// registry merged with the built-in blueprints.
+func (a *App) ListChannelTemplates(JwtToken string) ([]channel_domain.ChannelTemplate, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ListChannelTemplates(a.ctx, claims.UserID)
+}
+
+// GetChannelTemplate returns the latest version of a channel template.
+func (a *App) GetChannelTemplate(JwtToken string, templateID string) (*channel_domain.ChannelTemplate, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.GetChannelTemplate(a.ctx, claims.UserID, templateID)
+}
+
+// CreateChannelFromTemplate creates a channel with the slots, roles and
+// default policy of a template. Assignments must target template slots and
+// every property the template requires must be supplied.
+func (a *App) CreateChannelFromTemplate(JwtToken string, workspaceID string, templateID string, title string, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, federation string) (*tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.CreateChannelFromTemplate(a.ctx, claims.UserID, workspaceID, templateID, title, assignments, properties, federation)
+}
+
+// MigrateChannelTemplate moves a channel to the latest version of its
+// template. properties supplies values for newly required properties.
+func (a *App) MigrateChannelTemplate(JwtToken string, channelID string, properties []channel_domain.ChannelProperty) (*channel_domain.TemplateMigration, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.MigrateChannelTemplate(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID, properties)
+}
+
+// RequestSlotOccupancy asks for a vault to fill a gated slot; the channel's
+// approvers are notified. An empty candidateVaultID requests the slot for the
+// caller's vault, held with the caller's Stellar key.
+func (a *App) RequestSlotOccupancy(JwtToken string, channelID string, slotID string, candidateVaultID string, candidatePublicKey string) (*channel_domain.OccupancyRequest, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+
+	actorVaultID := a.sessionVaultID(claims.UserID)
+	if candidateVaultID == "" {
+		candidateVaultID = actorVaultID
+		if candidatePublicKey == "" {
+			if userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID); err == nil {
+				candidatePublicKey = userCfg.StellarAccount.PublicKey
+			}
+		}
+	}
+
+	return a.ChannelHandler.RequestSlotOccupancy(a.ctx, claims.UserID, actorVaultID, channelID, slotID, channel_domain.Assignment{
+		OwnerID:   candidateVaultID,
+		PublicKey: candidatePublicKey,
+	})
+}
+
+// VoteSlotOccupancy approves or rejects an occupancy request. The vote is
+// signed with the caller's Stellar key; decision is "approve" or "reject".
+func (a *App) VoteSlotOccupancy(JwtToken string, requestID string, decision string, reason string) (*channel_domain.OccupancyRequest, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+
+	userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("slot occupancy vote failed retrieving user config: %w", err)
+	}
+	if userCfg.StellarAccount.PrivateKey == "" {
+		return nil, fmt.Errorf("slot occupancy vote failed: stellar signing key not found in user config")
+	}
+	signer, err := blockchain.NewStellarIdentitySigner(userCfg.StellarAccount.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("slot occupancy vote failed: %w", err)
+	}
+
+	return a.ChannelHandler.VoteSlotOccupancy(a.ctx, claims.UserID, signer, a.sessionVaultID(claims.UserID), requestID, decision, reason)
+}
+
+// WithdrawSlotOccupancy cancels a pending occupancy request made by or for
+// the caller.
+func (a *App) WithdrawSlotOccupancy(JwtToken string, requestID string) (*channel_domain.OccupancyRequest, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.WithdrawSlotOccupancy(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), requestID)
+}
+
+func (a *App) ListSlotOccupancyRequests(JwtToken string, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ListSlotOccupancyRequests(a.ctx, claims.UserID, channelID)
+}
+
+// GetChannelBlockers explains why threads cannot be created in the channel
+// yet: its status, its expiry and each unfilled gated slot with the
+// approvers still to decide.
+func (a *App) GetChannelBlockers(JwtToken string, channelID string) ([]channel_domain.ChannelBlocker, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.GetChannelBlockers(a.ctx, claims.UserID, channelID)
+}
+
+func (a *App) ListChannels(JwtToken string, workspaceID string) ([]tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ListChannels(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), workspaceID)
+}
+
+// GetChannel fetches a single Channel from the authoritative Cloud backend
+// (GET /channels/{id}). The returned Channel is the Cloud-persisted aggregate;
+// no local channel is ever fabricated.
+func (a *App) GetChannel(JwtToken string, channelID string) (*tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.GetChannel(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
+}
+
+// UpdateChannel updates an existing Channel through the authoritative Cloud
+// backend (PUT /channels/{id}). The Cloud-persisted aggregate is returned; no
+// local mutation is performed.
+func (a *App) UpdateChannel(JwtToken string, channelID string, title string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy map[string]interface{}) (*tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.UpdateChannel(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID, title, slots, assignments, properties, policy)
+}
+
+// GetChannelPolicy returns the typed, versioned policy of a channel.
+func (a *App) GetChannelPolicy(JwtToken string, channelID string) (*channel_domain.PolicyDocument, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.GetChannelPolicy(a.ctx, claims.UserID, channelID)
+}
+
+// UpdateChannelPolicy validates the policy against the channel slots and
+// stores it. expectedRevision is the revision the caller last read.
+func (a *App) UpdateChannelPolicy(JwtToken string, channelID string, policy channel_domain.PolicyDocument, expectedRevision int) (*channel_domain.PolicyDocument, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.UpdateChannelPolicy(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID, policy, expectedRevision)
+}
+
+// GrantChannelPermission gives vaultID a channel permission. The caller's
+// vault must hold channel.admin; the grant is recorded in the channel policy.
+func (a *App) GrantChannelPermission(JwtToken string, channelID string, vaultID string, permission string, reason string) (*channel_domain.PolicyDocument, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.GrantChannelPermission(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID, vaultID, permission, reason)
+}
+
+// RevokeChannelPermission takes a channel permission from vaultID, even one
+// its role grants.
+func (a *App) RevokeChannelPermission(JwtToken string, channelID string, vaultID string, permission string, reason string) (*channel_domain.PolicyDocument, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.RevokeChannelPermission(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID, vaultID, permission, reason)
+}
+
+// GetChannelPermissions returns the effective permissions of vaultID in a
+// channel; an empty vaultID means the caller's vault.
+func (a *App) GetChannelPermissions(JwtToken string, channelID string, vaultID string) (*channel_application.ParticipantPermissions, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	actorVaultID := a.sessionVaultID(claims.UserID)
+	if vaultID == "" {
+		vaultID = actorVaultID
+	}
+	return a.ChannelHandler.GetChannelPermissions(a.ctx, claims.UserID, actorVaultID, channelID, vaultID)
+}
+
+// DeleteChannel deletes a Channel through the authoritative Cloud backend
+// (DELETE /channels/{id}). Cloud is the single source of truth for channel
+// existence; a 2xx response is success and HTTP >=400 is surfaced verbatim.
+func (a *App) DeleteChannel(JwtToken string, channelID string) error {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.DeleteChannel(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
+}
+
+func (a *App) ActivateChannel(JwtToken string, channelID string) (*tracecore_types.ChannelDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ActivateChannel(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
+}
+
+// RevokeChannel revokes an active Channel through the authoritative Cloud
+// backend. The Cloud response carries no Channel data; the frontend refreshes
+// the workspace channel list to observe the revoked status.
+func (a *App) RevokeChannel(JwtToken string, channelID string) error {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.RevokeChannel(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
+}
+
+// AddParticipant joins an external vault to a Channel through the authoritative
+// Cloud backend (POST /channels/{id}/participants). slotID and role are
+// optional; when supplied they are forwarded verbatim to the Cloud contract.
+func (a *App) AddParticipant(JwtToken string, channelID string, vaultID string, publicKey string, direction string, slotID string, role string) (*tracecore_types.ChannelParticipantDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.AddParticipant(a.ctx, claims.UserID, channelID, vaultID, publicKey, direction, slotID, role)
+}
+
+// ListParticipants returns the vaults Cloud has persisted as participants for
+// the Channel (GET /channels/{id}/participants). An empty result is valid.
+func (a *App) ListParticipants(JwtToken string, channelID string) ([]tracecore_types.ChannelParticipantDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ListParticipants(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
+}
+
+// InviteToChannel creates a channel invitation through the authoritative Cloud
+// backend (POST /channels/{id}/invitations). The invitation carries no slot or
+// role information; role semantics are a channel participant concern.
+func (a *App) InviteToChannel(JwtToken string, channelID string, inviteeVaultID string) (*tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.InviteToChannel(a.ctx, claims.UserID, channelID, a.sessionVaultID(claims.UserID), inviteeVaultID)
+}
+
+// AcceptChannelInvitation accepts a pending channel invitation through the
+// authoritative Cloud backend (POST /channels/invitations/{id}/accept). Cloud
+// validates the acceptance and persists the resulting participant; the accept
+// response carries the accepted Invitation, not the participant. Cloud is
+// idempotent: accepting an already-accepted invitation returns the accepted
+// invitation without a duplicate participant. A pending invitation past its
+// expiry is refused before it reaches the Cloud.
+func (a *App) AcceptChannelInvitation(JwtToken string, invitationID string, inviteePublicKey string) (*tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.AcceptChannelInvitation(a.ctx, claims.UserID, invitationID, a.sessionVaultID(claims.UserID), inviteePublicKey)
+}
+
+// RejectChannelInvitation declines a pending invitation on behalf of its
+// invitee. The Cloud checks the invitee and notifies the inviter.
+func (a *App) RejectChannelInvitation(JwtToken string, invitationID string) (*tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.RejectChannelInvitation(a.ctx, claims.UserID, invitationID, a.sessionVaultID(claims.UserID))
+}
+
+// RevokeChannelInvitation withdraws a pending invitation on behalf of its
+// inviter. The Cloud checks the inviter and notifies the invitee.
+func (a *App) RevokeChannelInvitation(JwtToken string, invitationID string, reason string) (*tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.RevokeChannelInvitation(a.ctx, claims.UserID, invitationID, a.sessionVaultID(claims.UserID), reason)
+}
+
+// ListChannelInvitations lists the invitations the user's vault received or sent
+// (direction "received" or "sent"). pendingOnly drops answered and expired
+// invitations.
+func (a *App) ListChannelInvitations(JwtToken string, direction string, channelID string, pendingOnly bool) ([]tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ListChannelInvitations(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), direction, channelID, pendingOnly)
+}
+
+// ExpireChannelInvitations marks expired the pending invitations the user's
+// vault sent that ran past the configured CHANNEL_INVITATION_TTL. The sweep
+// also runs at sign-in and every CHANNEL_INVITATION_SWEEP_INTERVAL.
+func (a *App) ExpireChannelInvitations(JwtToken string) ([]tracecore_types.ChannelInvitationDTO, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	if a.ChannelHandler == nil {
+		return nil, fmt.Errorf("channel handler is not initialized")
+	}
+	return a.ChannelHandler.ExpireChannelInvitations(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID))
+}
+
+// RunFederationSync pushes every due federation sync item now instead of
+// waiting for the worker.
+func (a *App) RunFederationSync(JwtToken string) (*channel_federation.SyncReport, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
+	}
+	return a.FederationHandler.RunSync(a.ctx, claims.UserID)
+}
+
+// ListFederationDeadLetters lists the sync items that exhausted their
+// retries, for one remote vault or all of them when vaultID is empty.
+func (a *App) ListFederationDeadLetters(JwtToken string, vaultID string) ([]channel_federation.DeadLetter, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
+	}
+	return a.FederationHandler.ListDeadLetters(a.ctx, claims.UserID, vaultID)
 }
 
-// C3 Wails App Methods
-
-func (a *App) CreateWorkspace(JwtToken string, vaultId string, name string, description string) (*tracecore_types.Workspace, error) {
+// RequeueFederationDeadLetters requeues one dead letter, or all of them for
+// the vault when exchangeID is empty.
+func (a *App) RequeueFederationDeadLetters(JwtToken string, vaultID string, exchangeID string) (int, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized: %w", err)
+		return 0, fmt.Errorf("unauthorized: %w", err)
 	}
-	if a.WorkspaceHandler == nil {
-		return nil, fmt.Errorf("workspace handler is not initialized")
+	if a.FederationHandler == nil {
+		return 0, fmt.Errorf("federation handler is not initialized")
 	}
-	return a.WorkspaceHandler.CreateWorkspace(a.ctx, claims.UserID, vaultId, name, description)
+	return a.FederationHandler.RequeueDeadLetters(a.ctx, claims.UserID, vaultID, exchangeID)
 }
 
-func (a *App) ListWorkspaces(JwtToken string, vaultId string) ([]tracecore_types.Workspace, error) {
+// HandshakeRemoteVault exchanges signed identity documents with the vault at
+// remoteEndpoint. The remote stays pending until ApproveRemoteVault.
+func (a *App) HandshakeRemoteVault(JwtToken string, localVaultID string, remoteEndpoint string) (*channel_domain.RemoteVault, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	a.Logger.Info("Listing workspaces for user: %v, vaultId: %v", claims.UserID, vaultId)
-
-	// Restore cloud token before making the request
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		a.Logger.Error("Failed to restore cloud token: %v", err)
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
 	}
 
-	a.Logger.Info("☁️ [CLOUD-WORKSPACE] Listing workspaces for vault=%s", vaultId)
-
-	if a.WorkspaceHandler == nil {
-		return nil, fmt.Errorf("workspace handler is not initialized")
+	userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("federation handshake failed retrieving user config: %w", err)
 	}
-
-	res, err := a.WorkspaceHandler.ListWorkspaces(a.ctx, vaultId)
+	if userCfg.StellarAccount.PrivateKey == "" {
+		return nil, fmt.Errorf("federation handshake failed: stellar signing key not found in user config")
+	}
+	signer, err := blockchain.NewStellarIdentitySigner(userCfg.StellarAccount.PrivateKey)
 	if err != nil {
-		a.Logger.Error("App.ListWorkspaces error: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("federation handshake failed: %w", err)
 	}
-	utils.LogPretty("[Workspace] App.ListWorkspaces final result", res)
-	return res, nil
+
+	return a.FederationHandler.Handshake(a.ctx, claims.UserID, signer, localVaultID, a.config.FederationEndpoint, remoteEndpoint)
 }
 
-func (a *App) CreateChannel(JwtToken string, workspaceID string, title string, templateID string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy map[string]interface{}, federation string) (*tracecore_types.ChannelDTO, error) {
+// ListRemoteVaults lists the known remote vaults with their trust state and
+// pinned keys.
+func (a *App) ListRemoteVaults(JwtToken string) ([]channel_domain.RemoteVault, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
-	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
 	}
-	return a.ChannelHandler.CreateChannel(a.ctx, claims.UserID, workspaceID, title, templateID, slots, assignments, properties, policy, federation)
+	return a.FederationHandler.ListRemoteVaults(a.ctx, claims.UserID)
 }
 
-func (a *App) ListChannels(JwtToken string, workspaceID string) ([]tracecore_types.ChannelDTO, error) {
+// ApproveRemoteVault pins publicKey for the remote vault and trusts it. The
+// key must match the one the remote presented, including after a key change.
+func (a *App) ApproveRemoteVault(JwtToken string, vaultID string, publicKey string) (*channel_domain.RemoteVault, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
-	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
 	}
-	return a.ChannelHandler.ListChannels(a.ctx, claims.UserID, workspaceID)
+	return a.FederationHandler.ApproveRemoteVault(a.ctx, claims.UserID, vaultID, publicKey)
 }
 
-// GetChannel fetches a single Channel from the authoritative Cloud backend
-// (GET /channels/{id}). The returned Channel is the Cloud-persisted aggregate;
-// no local channel is ever fabricated.
-func (a *App) GetChannel(JwtToken string, channelID string) (*tracecore_types.ChannelDTO, error) {
+func (a *App) SuspendRemoteVault(JwtToken string, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	return a.FederationHandler.SuspendRemoteVault(a.ctx, claims.UserID, vaultID, reason)
+}
+
+func (a *App) RevokeRemoteVault(JwtToken string, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.FederationHandler == nil {
+		return nil, fmt.Errorf("federation handler is not initialized")
 	}
-	return a.ChannelHandler.GetChannel(a.ctx, claims.UserID, channelID)
+	return a.FederationHandler.RevokeRemoteVault(a.ctx, claims.UserID, vaultID, reason)
 }
 
-// UpdateChannel updates an existing Channel through the authoritative Cloud
-// backend (PUT /channels/{id}). The Cloud-persisted aggregate is returned; no
-// local mutation is performed.
-func (a *App) UpdateChannel(JwtToken string, channelID string, title string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy map[string]interface{}) (*tracecore_types.ChannelDTO, error) {
+func (a *App) CreateThread(JwtToken string, channelID string, title string, subtitle string, assetType string) (*tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3044,64 +4644,147 @@ func (a *App) UpdateChannel(JwtToken string, channelID string, title string, slo
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
+	}
+	return a.ThreadHandler.CreateThread(a.ctx, a.sessionVaultID(claims.UserID), channelID, title, subtitle, assetType)
+}
+
+// sessionVaultID is the vault the user acts as in channels and threads: the
+// vault of their open session, or the user ID when no vault is open.
+// startInvitationExpiry marks expired the invitations the user's vault sent
+// that ran past their TTL, now and then every sweep interval until the user
+// signs out. The Cloud tells both sides of each invitation it expired.
+func (a *App) startInvitationExpiry(userID string) {
 	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a.invitationSweepsMu.Lock()
+	if stop, ok := a.invitationSweeps[userID]; ok {
+		stop()
+	}
+	if a.invitationSweeps == nil {
+		a.invitationSweeps = make(map[string]context.CancelFunc)
+	}
+	a.invitationSweeps[userID] = cancel
+	a.invitationSweepsMu.Unlock()
+
+	interval := a.config.InvitationSweepInterval
+	if interval <= 0 {
+		interval = channelInvitationSweepInterval("")
 	}
-	return a.ChannelHandler.UpdateChannel(a.ctx, claims.UserID, channelID, title, slots, assignments, properties, policy)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			a.sweepChannelInvitations(ctx, userID)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
 }
 
-// DeleteChannel deletes a Channel through the authoritative Cloud backend
-// (DELETE /channels/{id}). Cloud is the single source of truth for channel
-// existence; a 2xx response is success and HTTP >=400 is surfaced verbatim.
-func (a *App) DeleteChannel(JwtToken string, channelID string) error {
-	claims, err := a.RequireAuth(JwtToken)
+func (a *App) stopInvitationExpiry(userID string) {
+	a.invitationSweepsMu.Lock()
+	defer a.invitationSweepsMu.Unlock()
+
+	if stop, ok := a.invitationSweeps[userID]; ok {
+		stop()
+		delete(a.invitationSweeps, userID)
+	}
+}
+
+func (a *App) sweepChannelInvitations(ctx context.Context, userID string) {
+	if err := a.RestoreCloudTokenForUser(userID); err != nil {
+		a.Logger.Warn("App - sweepChannelInvitations - restore Cloud token for user %s: %v", userID, err)
+		return
+	}
+	expired, err := a.ChannelHandler.ExpireChannelInvitations(ctx, userID, a.sessionVaultID(userID))
 	if err != nil {
-		return fmt.Errorf("unauthorized: %w", err)
+		if ctx.Err() == nil {
+			a.Logger.Warn("App - sweepChannelInvitations - user %s: %v", userID, err)
+		}
+		return
 	}
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return fmt.Errorf("failed to restore Cloud token: %w", err)
+	if len(expired) > 0 {
+		a.Logger.Info("⏳ Expired %d channel invitation(s) sent by user %s", len(expired), userID)
 	}
-	if a.ChannelHandler == nil {
-		return fmt.Errorf("channel handler is not initialized")
+}
+
+func (a *App) sessionVaultID(userID string) string {
+	if a.Vault != nil && a.Vault.SessionManager != nil {
+		if session, err := a.Vault.GetSession(userID); err == nil && session != nil && session.Runtime != nil && session.Runtime.VaultID != "" {
+			return session.Runtime.VaultID
+		}
 	}
-	return a.ChannelHandler.DeleteChannel(a.ctx, claims.UserID, channelID)
+	return userID
 }
 
-func (a *App) ActivateChannel(JwtToken string, channelID string) (*tracecore_types.ChannelDTO, error) {
-	claims, err := a.RequireAuth(JwtToken)
+// setThreadEventSigner makes thread appends sign with the vault's device
+// registered under the user's Stellar key. Without such a device, events go
+// out unsigned and VerifyThreadEvents reports them as such.
+func (a *App) setThreadEventSigner(userID string, vaultID string) {
+	if a.ThreadHandler == nil {
+		return
+	}
+	a.ThreadHandler.SetEventSigner(nil)
+	signer, err := a.sessionDeviceSigner(userID, vaultID)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized: %w", err)
+		a.Logger.Warn("App - setThreadEventSigner - %v", err)
+		return
 	}
-	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	a.ThreadHandler.SetEventSigner(signer)
+}
+
+// sessionDeviceSigner returns a signer for the vault's active device whose
+// key is the user's Stellar key.
+func (a *App) sessionDeviceSigner(userID string, vaultID string) (*thread_devicekeys.DeviceSigner, error) {
+	if a.AppConfigHandler == nil || a.DeviceHandler == nil {
+		return nil, fmt.Errorf("device signing is not initialized")
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	userCfg, err := a.AppConfigHandler.GetUserConfigByUserID(userID)
+	if err != nil || userCfg == nil || userCfg.StellarAccount.PrivateKey == "" {
+		return nil, fmt.Errorf("no stellar signing key for user %s: %v", userID, err)
+	}
+	key, err := blockchain.StellarDeviceKey(userCfg.StellarAccount.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := a.DeviceHandler.ListDevices(a.ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices of vault %s: %w", vaultID, err)
 	}
-	return a.ChannelHandler.ActivateChannel(a.ctx, claims.UserID, channelID)
+	signer, err := thread_devicekeys.NewDeviceSigner(devices, key)
+	if err != nil {
+		return nil, fmt.Errorf("vault %s: %w", vaultID, err)
+	}
+	return signer, nil
 }
 
-// RevokeChannel revokes an active Channel through the authoritative Cloud
-// backend. The Cloud response carries no Channel data; the frontend refreshes
-// the workspace channel list to observe the revoked status.
-func (a *App) RevokeChannel(JwtToken string, channelID string) error {
+func (a *App) ListThreads(JwtToken string, channelID string) ([]tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
-		return fmt.Errorf("unauthorized: %w", err)
+		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
+	log.Printf("[THREAD LIST APP] JwtToken present=%v userID=%s channelID=%s", JwtToken != "", claims.UserID, channelID)
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
-		return fmt.Errorf("failed to restore Cloud token: %w", err)
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	if a.ChannelHandler == nil {
-		return fmt.Errorf("channel handler is not initialized")
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ChannelHandler.RevokeChannel(a.ctx, claims.UserID, channelID)
+	return a.ThreadHandler.ListThreads(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), channelID)
 }
 
-// AddParticipant joins an external vault to a Channel through the authoritative
-// Cloud backend (POST /channels/{id}/participants). slotID and role are
-// optional; when supplied they are forwarded verbatim to the Cloud contract.
-func (a *App) AddParticipant(JwtToken string, channelID string, vaultID string, publicKey string, direction string, slotID string, role string) (*tracecore_types.ChannelParticipantDTO, error) {
+// CloseThread closes an open thread; its history stays readable.
+func (a *App) CloseThread(JwtToken string, threadID string, reason string) (*tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3109,15 +4792,14 @@ func (a *App) AddParticipant(JwtToken string, channelID string, vaultID string,
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ChannelHandler.AddParticipant(a.ctx, claims.UserID, channelID, vaultID, publicKey, direction, slotID, role)
+	return a.ThreadHandler.CloseThread(a.ctx, a.sessionVaultID(claims.UserID), threadID, reason)
 }
 
-// ListParticipants returns the vaults Cloud has persisted as participants for
-// the Channel (GET /channels/{id}/participants). An empty result is valid.
-func (a *App) ListParticipants(JwtToken string, channelID string) ([]tracecore_types.ChannelParticipantDTO, error) {
+// ReopenThread reopens a closed thread when the channel policy allows it.
+func (a *App) ReopenThread(JwtToken string, threadID string, reason string) (*tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3125,16 +4807,15 @@ func (a *App) ListParticipants(JwtToken string, channelID string) ([]tracecore_t
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ChannelHandler.ListParticipants(a.ctx, claims.UserID, channelID)
+	return a.ThreadHandler.ReopenThread(a.ctx, a.sessionVaultID(claims.UserID), threadID, reason)
 }
 
-// InviteToChannel creates a channel invitation through the authoritative Cloud
-// backend (POST /channels/{id}/invitations). The invitation carries no slot or
-// role information; role semantics are a channel participant concern.
-func (a *App) InviteToChannel(JwtToken string, channelID string, inviterVaultID string, inviteeVaultID string) (*tracecore_types.ChannelInvitationDTO, error) {
+// InitiateThreadTransfer hands a thread over to another vault. Appends are
+// blocked until the transfer completes.
+func (a *App) InitiateThreadTransfer(JwtToken string, threadID string, toVaultID string) (*tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3142,30 +4823,28 @@ func (a *App) InviteToChannel(JwtToken string, channelID string, inviterVaultID
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ChannelHandler.InviteToChannel(a.ctx, claims.UserID, channelID, inviterVaultID, inviteeVaultID)
+	return a.ThreadHandler.InitiateThreadTransfer(a.ctx, a.sessionVaultID(claims.UserID), threadID, toVaultID)
 }
 
-// AcceptChannelInvitation accepts a pending channel invitation through the
-// authoritative Cloud backend (POST /channels/invitations/{id}/accept). Cloud
-// validates the acceptance and persists the resulting participant; the accept
-// response carries the accepted Invitation, not the participant. Cloud is
-// idempotent: accepting an already-accepted invitation returns the accepted
-// invitation without a duplicate participant.
-func (a *App) AcceptChannelInvitation(JwtToken string, invitationID string, inviteeVaultID string, inviteePublicKey string) (*tracecore_types.ChannelInvitationDTO, error) {
+// CompleteThreadTransfer completes a pending transfer and reopens the thread.
+func (a *App) CompleteThreadTransfer(JwtToken string, threadID string) (*tracecore_types.ThreadDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	if a.ChannelHandler == nil {
-		return nil, fmt.Errorf("channel handler is not initialized")
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	return a.ChannelHandler.AcceptChannelInvitation(a.ctx, claims.UserID, invitationID, inviteeVaultID, inviteePublicKey)
+	if a.ThreadHandler == nil {
+		return nil, fmt.Errorf("thread handler is not initialized")
+	}
+	return a.ThreadHandler.CompleteThreadTransfer(a.ctx, a.sessionVaultID(claims.UserID), threadID)
 }
 
-func (a *App) CreateThread(JwtToken string, channelID string, title string, subtitle string, assetType string) (*tracecore_types.ThreadDTO, error) {
+func (a *App) ListThreadEvents(JwtToken string, threadID string) ([]tracecore_types.ThreadEventDTO, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3176,35 +4855,40 @@ func (a *App) CreateThread(JwtToken string, channelID string, title string, subt
 	if a.ThreadHandler == nil {
 		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-
-	var vaultID string
-	if a.Vault != nil && a.Vault.SessionManager != nil {
-		if session, err := a.Vault.GetSession(claims.UserID); err == nil && session != nil && session.Runtime != nil {
-			vaultID = session.Runtime.VaultID
-		}
-	}
-	if vaultID == "" {
-		vaultID = claims.UserID
-	}
-	return a.ThreadHandler.CreateThread(a.ctx, vaultID, channelID, title, subtitle, assetType)
+	return a.ThreadHandler.ListThreadEvents(a.ctx, claims.UserID, threadID)
 }
 
-func (a *App) ListThreads(JwtToken string, channelID string) ([]tracecore_types.ThreadDTO, error) {
+// VerifyThreadEvents checks the thread log's hash chain and author
+// signatures locally and reports gaps, forks and reordering.
+func (a *App) VerifyThreadEvents(JwtToken string, threadID string) (*thread_domain.ChainReport, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
 	}
-	log.Printf("[THREAD LIST APP] JwtToken present=%v userID=%s channelID=%s", JwtToken != "", claims.UserID, channelID)
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
 	if a.ThreadHandler == nil {
 		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ThreadHandler.ListThreads(a.ctx, claims.UserID, channelID)
+	return a.ThreadHandler.VerifyThreadEvents(a.ctx, claims.UserID, threadID)
 }
 
-func (a *App) ListThreadEvents(JwtToken string, threadID string) ([]tracecore_types.ThreadEventDTO, error) {
+// GetC3CacheStatus reports connectivity, queued offline mutations and the
+// staleness of every cached workspace/channel/thread listing.
+func (a *App) GetC3CacheStatus(JwtToken string) (*shared_offline.Status, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.C3Cache == nil {
+		return nil, fmt.Errorf("c3 cache is not initialized")
+	}
+	return a.C3Cache.Status(a.ctx, claims.UserID)
+}
+
+// ReplayC3Mutations replays mutations queued while offline.
+func (a *App) ReplayC3Mutations(JwtToken string) (*shared_offline.ReplayResult, error) {
 	claims, err := a.RequireAuth(JwtToken)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized: %w", err)
@@ -3212,10 +4896,10 @@ func (a *App) ListThreadEvents(JwtToken string, threadID string) ([]tracecore_ty
 	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
 		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
 	}
-	if a.ThreadHandler == nil {
-		return nil, fmt.Errorf("thread handler is not initialized")
+	if a.C3Cache == nil {
+		return nil, fmt.Errorf("c3 cache is not initialized")
 	}
-	return a.ThreadHandler.ListThreadEvents(a.ctx, claims.UserID, threadID)
+	return a.C3Cache.Queue.Replay(a.ctx, claims.UserID)
 }
 
 func (a *App) AppendThreadEvent(JwtToken string, threadID string, eventType string, payloadJson string) (*tracecore_types.ThreadEventDTO, error) {
@@ -3235,7 +4919,7 @@ func (a *App) AppendThreadEvent(JwtToken string, threadID string, eventType stri
 	if a.ThreadHandler == nil {
 		return nil, fmt.Errorf("thread handler is not initialized")
 	}
-	return a.ThreadHandler.AppendThreadEvent(a.ctx, claims.UserID, threadID, eventType, ref)
+	return a.ThreadHandler.AppendThreadEvent(a.ctx, a.sessionVaultID(claims.UserID), threadID, eventType, ref)
 }
 
 func (a *App) CreateCollaborativeShare(JwtToken string, threadID string, trustGroupID string, assetCID string, targetVaultID string, notes string, wrappedDEK string, kekVersion uint64) (*tracecore_types.ShareEntryRefDTO, error) {
@@ -3246,7 +4930,7 @@ func (a *App) CreateCollaborativeShare(JwtToken string, threadID string, trustGr
 	if a.CollaborationHandler == nil {
 		return nil, fmt.Errorf("collaboration handler is not initialized")
 	}
-	return a.CollaborationHandler.CreateCollaborativeShare(a.ctx, claims.UserID, threadID, trustGroupID, assetCID, targetVaultID, notes, wrappedDEK, kekVersion)
+	return a.CollaborationHandler.CreateCollaborativeShare(a.ctx, claims.UserID, a.sessionVaultID(claims.UserID), threadID, trustGroupID, assetCID, targetVaultID, notes, wrappedDEK, kekVersion)
 }
 
 func (a *App) ResolveCollaborativeShare(JwtToken string, shareEntryID string, deviceID string) (*collaboration_dtos.ResolveCollaborativeShareResponse, error) {
@@ -3260,6 +4944,117 @@ func (a *App) ResolveCollaborativeShare(JwtToken string, shareEntryID string, de
 	return a.CollaborationHandler.ResolveCollaborativeShare(a.ctx, claims.UserID, shareEntryID, deviceID)
 }
 
+func (a *App) RemoveTrustGroupMember(JwtToken string, trustGroupID string, memberID string, reason string) (*collaboration_dtos.RemoveTrustGroupMemberResponse, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.CollaborationHandler == nil {
+		return nil, fmt.Errorf("collaboration handler is not initialized")
+	}
+	return a.CollaborationHandler.RemoveTrustGroupMember(a.ctx, claims.UserID, trustGroupID, memberID, reason)
+}
+
+// EnrollDevice registers a new device key for the caller's vault. Unless the
+// device is trusted straight away, the response carries the QR payload and
+// short code an already-trusted device must confirm.
+func (a *App) EnrollDevice(JwtToken string, publicKey string, keyType string, name string) (*identity_usecase.EnrollDeviceResponse, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.DeviceHandler == nil {
+		return nil, fmt.Errorf("device handler is not initialized")
+	}
+	return a.DeviceHandler.EnrollDevice(a.ctx, a.sessionVaultID(claims.UserID), publicKey, keyType, name)
+}
+
+// ApproveDeviceEnrollment confirms a pending device from this one and seals
+// the caller's trust group KEKs for it. The approval is signed here with the
+// session's device key, i.e. the registered device behind the user's Stellar
+// key. The device stays approved when envelope provisioning fails; the error
+// says so.
+func (a *App) ApproveDeviceEnrollment(JwtToken string, enrollmentID string, code string) (*identity_domain.Device, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.DeviceHandler == nil {
+		return nil, fmt.Errorf("device handler is not initialized")
+	}
+	if a.CollaborationHandler == nil || !a.CollaborationHandler.HasDeviceTrust() {
+		return nil, fmt.Errorf("trust group device provisioning is not initialized")
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	vaultID := a.sessionVaultID(claims.UserID)
+	signer, err := a.sessionDeviceSigner(claims.UserID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	dev, err := a.DeviceHandler.ApproveDeviceEnrollmentWithSigner(a.ctx, vaultID, enrollmentID, code, signer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.CollaborationHandler.ProvisionDeviceEnvelopes(a.ctx, claims.UserID, vaultID, signer.DeviceID(), dev.ID, dev.PublicKey); err != nil {
+		return dev, fmt.Errorf("device approved but trust group envelopes were not provisioned: %w", err)
+	}
+	return dev, nil
+}
+
+// ListDevices returns the caller's devices, most recently seen first.
+func (a *App) ListDevices(JwtToken string) ([]*identity_domain.Device, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.DeviceHandler == nil {
+		return nil, fmt.Errorf("device handler is not initialized")
+	}
+	return a.DeviceHandler.ListDevices(a.ctx, a.sessionVaultID(claims.UserID))
+}
+
+// TouchDevice marks one of the caller's devices as seen now.
+func (a *App) TouchDevice(JwtToken string, deviceID string) error {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.DeviceHandler == nil {
+		return fmt.Errorf("device handler is not initialized")
+	}
+	return a.DeviceHandler.TouchDevice(a.ctx, a.sessionVaultID(claims.UserID), deviceID)
+}
+
+// RevokeDevice revokes one of the caller's devices and rotates the KEK of
+// every trust group it could still open. Nothing is revoked when rotation
+// is not available.
+func (a *App) RevokeDevice(JwtToken string, deviceID string) (*identity_domain.Device, error) {
+	claims, err := a.RequireAuth(JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized: %w", err)
+	}
+	if a.DeviceHandler == nil {
+		return nil, fmt.Errorf("device handler is not initialized")
+	}
+	if a.CollaborationHandler == nil || !a.CollaborationHandler.HasDeviceTrust() {
+		return nil, fmt.Errorf("trust group key rotation is not initialized")
+	}
+	if err := a.RestoreCloudTokenForUser(claims.UserID); err != nil {
+		return nil, fmt.Errorf("failed to restore Cloud token: %w", err)
+	}
+	vaultID := a.sessionVaultID(claims.UserID)
+	dev, err := a.DeviceHandler.RevokeDevice(a.ctx, vaultID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.CollaborationHandler.RotateForRevokedDevice(a.ctx, claims.UserID, vaultID, deviceID); err != nil {
+		return dev, fmt.Errorf("device revoked but trust group keys were not rotated: %w", err)
+	}
+	return dev, nil
+}
+
 // ConnectVault explicitly connects the local vault to Ankhora Cloud.
 // This is a first-class vault lifecycle operation, NOT a hidden side effect
 // of ListWorkspaces.
@@ -43,6 +43,7 @@ type threadEventStub struct {
 	mu           sync.Mutex
 	trustGroups  map[string]map[string]interface{}
 	shareEntries map[string]c3_asset_domain.ShareEntry
+	threads      []map[string]interface{}
 	events       map[string][]map[string]interface{} // threadID -> persisted events
 	appendBodies []string                            // raw captured POST bodies
 	token        string
@@ -83,6 +84,19 @@ func (s *threadEventStub) seedTrustGroup(id string, kekVersion uint64, memberCID
 	}
 }
 
+func (s *threadEventStub) seedThread(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.threads = append(s.threads, map[string]interface{}{
+		"id":         id,
+		"channel_id": "",
+		"asset_type": "entry",
+		"title":      "Vertical thread",
+		"status":     "open",
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 func (s *threadEventStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Authorization") != "Bearer "+s.token {
 		http.Error(w, "unauthorized", http.StatusUnauthorized)
@@ -183,6 +197,10 @@ func (s *threadEventStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.events[storedThreadID] = append(s.events[storedThreadID], event)
 		writeEnvelope(w, http.StatusCreated, event)
 
+	// The append use case resolves the thread to check its state.
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/threads/by-channel/"):
+		writeEnvelope(w, http.StatusOK, s.threads)
+
 	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/events"):
 		parts := strings.Split(r.URL.Path, "/")
 		threadID := parts[len(parts)-2]
@@ -275,6 +293,7 @@ func TestAppendThreadEvent_ReferencesPersistedShareEntry(t *testing.T) {
 	require.NoError(t, err)
 
 	const threadID = "thread_vertical_events_1"
+	stub.seedThread(threadID)
 
 	shareRef, err := app.CreateCollaborativeShare(
 		pairs.Token, threadID, tgID, assetCID, "vault_partner_02",
@@ -0,0 +1,45 @@
+// Command dvault is the headless vault client: it unlocks the vault with the
+// same application services as the desktop app and works on it from the
+// shell, scripts and CI.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	cli_backend "vault-app/internal/cli/infrastructure/backend"
+	cli_ui "vault-app/internal/cli/ui"
+	"vault-app/internal/logger/logger"
+)
+
+func main() {
+	// The services log to stdout; keep it for command output only.
+	stdout := os.Stdout
+	chatter, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil || os.Getenv("DVAULT_DEBUG") != "" {
+		chatter = os.Stderr
+	}
+	os.Stdout = chatter
+	log.SetOutput(chatter)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	backend, err := cli_backend.NewServiceBackendFromEnv(ctx, logger.NewFromEnv())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dvault: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := cli_ui.Run(ctx, os.Args[1:], cli_ui.IO{
+		Stdin:  os.Stdin,
+		Stdout: stdout,
+		Stderr: os.Stderr,
+		Env:    os.Environ(),
+	}, backend)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,63 @@
+// Command git-credential-dvault is a git credential helper answered by the
+// running desktop app or a "dvault git-credential serve" session:
+//
+//	git config --global credential.helper dvault
+//
+// Set DVAULT_GIT_CREDENTIAL_SOCKET when the helper listens on a
+// non-default socket.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_socket "vault-app/internal/git_credential/infrastructure/socket"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: git-credential-dvault get|store|erase")
+		os.Exit(2)
+	}
+	// Git may grow new operations; helpers must ignore those they do not know.
+	op := git_credential_domain.Operation(os.Args[1])
+	if !op.Valid() {
+		return
+	}
+
+	cred, err := git_credential_domain.ReadCredential(os.Stdin)
+	if err != nil {
+		fail(err)
+	}
+	socketPath := os.Getenv("DVAULT_GIT_CREDENTIAL_SOCKET")
+	if socketPath == "" {
+		if socketPath, err = git_credential_socket.DefaultSocketPath(); err != nil {
+			fail(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	res, err := git_credential_socket.Call(ctx, socketPath, op, cred)
+	// Plain http remotes are left to other helpers or the prompt.
+	if errors.Is(err, git_credential_domain.ErrUnsupportedProtocol) {
+		return
+	}
+	if err != nil {
+		fail(err)
+	}
+	if op == git_credential_domain.OpGet && res != nil {
+		if err := git_credential_domain.WriteCredential(os.Stdout, *res); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "git-credential-dvault: %v\n", err)
+	os.Exit(1)
+}
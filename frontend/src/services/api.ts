@@ -1736,7 +1736,7 @@ export interface InviteToChannelPayload {
 	invitee_vault_id: string;
 }
 
-// The inviter is the Desktop user's own vault (the current runtime vault).
+// The Desktop user's own vault (the current runtime vault).
 function currentVaultIdentity(): { vaultId: string; publicKey: string } | null {
 	const vault = useVaultStore.getState().vault;
 	const vaultId = vault?.vault_runtime_context?.VaultID as string | undefined;
@@ -1748,9 +1748,9 @@ function currentVaultIdentity(): { vaultId: string; publicKey: string } | null {
 }
 
 // InviteToChannel creates a channel invitation through the authoritative Cloud
-// backend (POST /channels/{id}/invitations). The inviter is the Desktop user's
-// own vault. Cloud persists the pending invitation and dedupes pending
-// invitations for the same channel + invitee.
+// backend (POST /channels/{id}/invitations). The inviter is the signed-in
+// user's session vault. Cloud persists the pending invitation and dedupes
+// pending invitations for the same channel + invitee.
 export async function inviteToChannel(
 	channelId: string,
 	payload: InviteToChannelPayload,
@@ -1767,15 +1767,9 @@ export async function inviteToChannel(
 		throw new Error('Authentication required');
 	}
 
-	const identity = currentVaultIdentity();
-	if (!identity) {
-		throw new Error('Vault identity is required to send an invitation');
-	}
-
 	const result = await AppAPI.InviteToChannel(
 		jwtToken,
 		channelId,
-		identity.vaultId,
 		payload.invitee_vault_id,
 	);
 	return result as ChannelInvitationResponse;
@@ -1806,7 +1800,6 @@ export async function acceptChannelInvitation(invitationId: string): Promise<Cha
 	const result = await AppAPI.AcceptChannelInvitation(
 		jwtToken,
 		invitationId,
-		identity.vaultId,
 		identity.publicKey,
 	);
 	return result as ChannelInvitationResponse;
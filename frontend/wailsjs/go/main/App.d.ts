@@ -27,7 +27,7 @@ import {notification_center_domain} from '../models';
 import {collaboration_dtos} from '../models';
 import {tracecore} from '../models';
 
-export function AcceptChannelInvitation(arg1:string,arg2:string,arg3:string,arg4:string):Promise<tracecore_types.ChannelInvitationDTO>;
+export function AcceptChannelInvitation(arg1:string,arg2:string,arg3:string):Promise<tracecore_types.ChannelInvitationDTO>;
 
 export function AcceptShare(arg1:string,arg2:string,arg3:string):Promise<tracecore_types.CloudResponse_vault_app_internal_tracecore_types_PendingShareIntent_>;
 
@@ -163,7 +163,7 @@ export function GetVaultFromCloud(arg1:string,arg2:string):Promise<tracecore_typ
 
 export function ImportVaultWithKey(arg1:string):Promise<stellar_recovery_domain.ImportedKey>;
 
-export function InviteToChannel(arg1:string,arg2:string,arg3:string,arg4:string):Promise<tracecore_types.ChannelInvitationDTO>;
+export function InviteToChannel(arg1:string,arg2:string,arg3:string):Promise<tracecore_types.ChannelInvitationDTO>;
 
 export function IsVaultDirty(arg1:string):Promise<boolean>;
 
@@ -2,8 +2,8 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
-export function AcceptChannelInvitation(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['AcceptChannelInvitation'](arg1, arg2, arg3, arg4);
+export function AcceptChannelInvitation(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AcceptChannelInvitation'](arg1, arg2, arg3);
 }
 
 export function AcceptShare(arg1, arg2, arg3) {
@@ -274,8 +274,8 @@ export function ImportVaultWithKey(arg1) {
   return window['go']['main']['App']['ImportVaultWithKey'](arg1);
 }
 
-export function InviteToChannel(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['InviteToChannel'](arg1, arg2, arg3, arg4);
+export function InviteToChannel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['InviteToChannel'](arg1, arg2, arg3);
 }
 
 export function IsVaultDirty(arg1) {
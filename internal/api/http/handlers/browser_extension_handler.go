@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"vault-app/internal/api/http/middleware"
+	browser_extension_usecases "vault-app/internal/browser_extension/application/usecases"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// maxBodyBytes bounds request bodies; the largest is a captured login.
+const maxBodyBytes = 64 << 10
+
+// BrowserExtensionAPI is the loopback API the browser extension talks to.
+// It serves one user's vault and answers 423 while locked.
+//
+//	GET  /v1/status                 running and locked state, no token
+//	POST /v1/pair                   trade a pairing code for a client token
+//	GET  /v1/logins?origin=...      logins to fill on a page (autofill)
+//	POST /v1/logins                 save a captured login (save_login)
+//	POST /v1/passwords/generate     suggest a password (generate_password)
+type BrowserExtensionAPI struct {
+	UserID   string
+	Pairing  *browser_extension_usecases.PairingUsecase
+	Clients  *browser_extension_usecases.ClientsUsecase
+	Lookup   *browser_extension_usecases.LookupLoginsUsecase
+	Save     *browser_extension_usecases.SaveLoginUsecase
+	Generate *browser_extension_usecases.GeneratePasswordUsecase
+
+	mu      sync.Mutex
+	locked  bool
+	handler http.Handler
+}
+
+func NewBrowserExtensionAPI(
+	userID string,
+	pairing *browser_extension_usecases.PairingUsecase,
+	clients *browser_extension_usecases.ClientsUsecase,
+	lookup *browser_extension_usecases.LookupLoginsUsecase,
+	save *browser_extension_usecases.SaveLoginUsecase,
+	generate *browser_extension_usecases.GeneratePasswordUsecase,
+) *BrowserExtensionAPI {
+	api := &BrowserExtensionAPI{
+		UserID:   userID,
+		Pairing:  pairing,
+		Clients:  clients,
+		Lookup:   lookup,
+		Save:     save,
+		Generate: generate,
+	}
+	auth := func(ctx context.Context, token string) (*browser_extension_domain.PairedClient, error) {
+		return api.Clients.Authenticate(ctx, api.UserID, token)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", api.status)
+	mux.Handle("POST /v1/pair", api.unlocked(http.HandlerFunc(api.pair)))
+	mux.Handle("GET /v1/logins", api.unlocked(middleware.RequireScope(auth, browser_extension_domain.ScopeAutofill, http.HandlerFunc(api.lookupLogins))))
+	mux.Handle("POST /v1/logins", api.unlocked(middleware.RequireScope(auth, browser_extension_domain.ScopeSaveLogin, http.HandlerFunc(api.saveLogin))))
+	mux.Handle("POST /v1/passwords/generate", api.unlocked(middleware.RequireScope(auth, browser_extension_domain.ScopeGeneratePassword, http.HandlerFunc(api.generatePassword))))
+	api.handler = middleware.LoopbackOnly(middleware.ExtensionOrigin(mux))
+	return api
+}
+
+func (api *BrowserExtensionAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.handler.ServeHTTP(w, r)
+}
+
+// Lock makes every vault route answer 423 until Unlock.
+func (api *BrowserExtensionAPI) Lock() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.locked = true
+}
+
+func (api *BrowserExtensionAPI) Unlock() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.locked = false
+}
+
+func (api *BrowserExtensionAPI) Locked() bool {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.locked
+}
+
+func (api *BrowserExtensionAPI) unlocked(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if api.Locked() {
+			writeError(w, browser_extension_domain.ErrVaultLocked)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type StatusResponse struct {
+	Locked bool `json:"locked"`
+}
+
+func (api *BrowserExtensionAPI) status(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, StatusResponse{Locked: api.Locked()})
+}
+
+type PairRequest struct {
+	Code       string                           `json:"code"`
+	ClientName string                           `json:"client_name"`
+	Scopes     []browser_extension_domain.Scope `json:"scopes"`
+}
+
+func (api *BrowserExtensionAPI) pair(w http.ResponseWriter, r *http.Request) {
+	var req PairRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	res, err := api.Pairing.Complete(r.Context(), api.UserID, req.Code, req.ClientName, req.Scopes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+type LoginsResponse struct {
+	Logins []browser_extension_domain.LoginMatch `json:"logins"`
+}
+
+func (api *BrowserExtensionAPI) lookupLogins(w http.ResponseWriter, r *http.Request) {
+	logins, err := api.Lookup.Execute(r.Context(), api.UserID, r.URL.Query().Get("origin"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, LoginsResponse{Logins: logins})
+}
+
+type SavedLoginResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Website string `json:"website"`
+}
+
+func (api *BrowserExtensionAPI) saveLogin(w http.ResponseWriter, r *http.Request) {
+	var req browser_extension_usecases.SaveLoginRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	entry, err := api.Save.Execute(r.Context(), api.UserID, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, SavedLoginResponse{ID: entry.ID, Name: entry.EntryName, Website: entry.Website})
+}
+
+type GeneratedPasswordResponse struct {
+	Password string `json:"password"`
+}
+
+func (api *BrowserExtensionAPI) generatePassword(w http.ResponseWriter, r *http.Request) {
+	// The body is optional; without one every character class is used.
+	var opts browser_extension_domain.PasswordOptions
+	if !decodeJSON(w, r, &opts, true) {
+		return
+	}
+	password, err := api.Generate.Execute(r.Context(), api.UserID, opts)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, GeneratedPasswordResponse{Password: password})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	return decodeJSON(w, r, v, false)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any, optional bool) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if optional && errors.Is(err, io.EOF) {
+		return true
+	}
+	if err != nil {
+		middleware.WriteError(w, http.StatusBadRequest, "bad_request", err)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// errorStatus maps domain errors to the status and code the extension
+// branches on.
+var errorStatus = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{browser_extension_domain.ErrVaultLocked, http.StatusLocked, "vault_locked"},
+	{browser_extension_domain.ErrFeatureDisabled, http.StatusForbidden, "feature_disabled"},
+	{browser_extension_domain.ErrScopeDenied, http.StatusForbidden, "scope_denied"},
+	{browser_extension_domain.ErrInvalidToken, http.StatusUnauthorized, "unauthorized"},
+	{browser_extension_domain.ErrNoPairing, http.StatusUnauthorized, "invalid_pairing_code"},
+	{browser_extension_domain.ErrInvalidPairingCode, http.StatusUnauthorized, "invalid_pairing_code"},
+	{browser_extension_domain.ErrPairingExpired, http.StatusUnauthorized, "pairing_expired"},
+	{browser_extension_domain.ErrTooManyAttempts, http.StatusTooManyRequests, "too_many_attempts"},
+	{browser_extension_domain.ErrLoginExists, http.StatusConflict, "login_exists"},
+	{browser_extension_domain.ErrInvalidOrigin, http.StatusBadRequest, "invalid_origin"},
+	{browser_extension_domain.ErrInvalidLogin, http.StatusBadRequest, "invalid_login"},
+	{browser_extension_domain.ErrInvalidPasswordSpec, http.StatusBadRequest, "invalid_password_options"},
+	{browser_extension_domain.ErrUnknownScope, http.StatusBadRequest, "unknown_scope"},
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	for _, e := range errorStatus {
+		if errors.Is(err, e.err) {
+			middleware.WriteError(w, e.status, e.code, err)
+			return
+		}
+	}
+	middleware.WriteError(w, http.StatusInternalServerError, "internal", err)
+}
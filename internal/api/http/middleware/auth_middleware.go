@@ -1,2 +1,61 @@
 package middleware
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// TokenAuthenticator resolves a bearer token to the paired client it was
+// issued to.
+type TokenAuthenticator func(ctx context.Context, token string) (*browser_extension_domain.PairedClient, error)
+
+type clientKey struct{}
+
+// ClientFromContext returns the client RequireScope authenticated.
+func ClientFromContext(ctx context.Context) *browser_extension_domain.PairedClient {
+	c, _ := ctx.Value(clientKey{}).(*browser_extension_domain.PairedClient)
+	return c
+}
+
+// RequireScope lets a request through when its bearer token belongs to a
+// paired client holding scope.
+func RequireScope(auth TokenAuthenticator, scope browser_extension_domain.Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			WriteError(w, http.StatusUnauthorized, "unauthorized", browser_extension_domain.ErrInvalidToken)
+			return
+		}
+		client, err := auth(r.Context(), strings.TrimSpace(token))
+		if err != nil {
+			if errors.Is(err, browser_extension_domain.ErrInvalidToken) {
+				WriteError(w, http.StatusUnauthorized, "unauthorized", err)
+			} else {
+				WriteError(w, http.StatusInternalServerError, "internal", err)
+			}
+			return
+		}
+		if !client.Allows(scope) {
+			WriteError(w, http.StatusForbidden, "scope_denied", browser_extension_domain.ErrScopeDenied)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientKey{}, client)))
+	})
+}
+
+// ErrorBody is the JSON body of every error response.
+type ErrorBody struct {
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+func WriteError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorBody{Code: code, Error: err.Error()})
+}
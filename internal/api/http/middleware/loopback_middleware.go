@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// extensionSchemes are the origins browsers give extension pages.
+var extensionSchemes = []string{"chrome-extension", "moz-extension", "safari-web-extension", "extension"}
+
+// LoopbackOnly refuses requests that do not come from this machine or that
+// name another host, which is what a DNS rebinding page would send.
+func LoopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil || !isLoopback(host) {
+			WriteError(w, http.StatusForbidden, "forbidden", errors.New("only local clients are served"))
+			return
+		}
+		name := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			name = h
+		}
+		if !isLoopback(name) {
+			WriteError(w, http.StatusForbidden, "forbidden", errors.New("unexpected host "+r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ExtensionOrigin keeps web pages out: a request carrying an Origin must
+// come from a browser extension. Extension origins get CORS headers and
+// their preflights are answered here.
+func ExtensionOrigin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !isExtensionOrigin(origin) {
+			WriteError(w, http.StatusForbidden, "forbidden", errors.New("origin "+origin+" is not a browser extension"))
+			return
+		}
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		h.Add("Vary", "Origin")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func isExtensionOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, s := range extensionSchemes {
+		if strings.EqualFold(u.Scheme, s) {
+			return true
+		}
+	}
+	return false
+}
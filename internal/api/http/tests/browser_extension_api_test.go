@@ -0,0 +1,414 @@
+package api_http_tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vault-app/internal/api/http/handlers"
+	"vault-app/internal/api/http/middleware"
+	browser_extension_usecases "vault-app/internal/browser_extension/application/usecases"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+	browser_extension_persistence "vault-app/internal/browser_extension/infrastructure/persistence"
+	browser_extension_ui "vault-app/internal/browser_extension/ui"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+const (
+	userID          = "user-1"
+	extensionOrigin = "chrome-extension://abcdefghijklmnop"
+)
+
+type vaultMock struct {
+	mu     sync.Mutex
+	vp     vaults_domain.VaultPayload
+	locked bool
+	dirty  int
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	vp := m.vp
+	return &vp, nil
+}
+
+func (m *vaultMock) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := entry.(*vaults_domain.LoginEntry)
+	e.ID = "saved-1"
+	m.vp.Entries.Login = append(m.vp.Entries.Login, *e)
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) MarkDirty(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dirty++
+}
+
+type gate struct {
+	mu      sync.Mutex
+	enabled bool
+}
+
+func (g *gate) BrowserExtensionEnabled(string) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.enabled, nil
+}
+
+func (g *gate) set(enabled bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.enabled = enabled
+}
+
+type fixture struct {
+	t      *testing.T
+	vault  *vaultMock
+	gate   *gate
+	api    *handlers.BrowserExtensionAPI
+	server *httptest.Server
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	vault := &vaultMock{}
+	github := vaults_domain.LoginEntry{UserName: "alice", Password: "gh-pw", Website: "https://github.com"}
+	github.ID, github.EntryName = "gh", "GitHub"
+	google := vaults_domain.LoginEntry{UserName: "alice@gmail.com", Password: "g-pw", Website: "google.com"}
+	google.ID, google.EntryName = "google", "Google"
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{github, google}
+
+	g := &gate{enabled: true}
+	repo := browser_extension_persistence.NewMemoryClientRepository()
+	api := handlers.NewBrowserExtensionAPI(
+		userID,
+		browser_extension_usecases.NewPairingUsecase(repo, g),
+		browser_extension_usecases.NewClientsUsecase(repo),
+		browser_extension_usecases.NewLookupLoginsUsecase(vault, g),
+		browser_extension_usecases.NewSaveLoginUsecase(vault, g),
+		browser_extension_usecases.NewGeneratePasswordUsecase(g),
+	)
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return &fixture{t: t, vault: vault, gate: g, api: api, server: server}
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (f *fixture) do(method, path, token string, body any) response {
+	f.t.Helper()
+	var r io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		require.NoError(f.t, err)
+		r = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, f.server.URL+path, r)
+	require.NoError(f.t, err)
+	req.Header.Set("Origin", extensionOrigin)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := f.server.Client().Do(req)
+	require.NoError(f.t, err)
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(f.t, err)
+	return response{status: resp.StatusCode, header: resp.Header, body: raw}
+}
+
+func decode[T any](t *testing.T, res response) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.Unmarshal(res.body, &v), string(res.body))
+	return v
+}
+
+func errorCode(t *testing.T, res response) string {
+	t.Helper()
+	return decode[middleware.ErrorBody](t, res).Code
+}
+
+// pair runs the whole pairing: the app shows a code, the extension trades it.
+func (f *fixture) pair(scopes ...browser_extension_domain.Scope) string {
+	f.t.Helper()
+	code, err := f.api.Pairing.Begin(userID, scopes)
+	require.NoError(f.t, err)
+	res := f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: code.Code, ClientName: "Chrome"})
+	require.Equal(f.t, http.StatusCreated, res.status, string(res.body))
+	return decode[browser_extension_domain.PairingResult](f.t, res).Token
+}
+
+func lookupPath(origin string) string {
+	return "/v1/logins?origin=" + url.QueryEscape(origin)
+}
+
+func TestPairAndAutofill(t *testing.T) {
+	f := newFixture(t)
+
+	res := f.do(http.MethodGet, "/v1/status", "", nil)
+	require.Equal(t, http.StatusOK, res.status)
+	require.False(t, decode[handlers.StatusResponse](t, res).Locked)
+
+	token := f.pair()
+
+	res = f.do(http.MethodGet, lookupPath("https://github.com/login"), token, nil)
+	require.Equal(t, http.StatusOK, res.status, string(res.body))
+	require.Equal(t, "no-store", res.header.Get("Cache-Control"))
+	logins := decode[handlers.LoginsResponse](t, res).Logins
+	require.Len(t, logins, 1)
+	require.Equal(t, "gh-pw", logins[0].Password)
+	require.True(t, logins[0].Exact)
+
+	res = f.do(http.MethodGet, lookupPath("https://accounts.google.com"), token, nil)
+	require.Equal(t, http.StatusOK, res.status)
+	logins = decode[handlers.LoginsResponse](t, res).Logins
+	require.Len(t, logins, 1)
+	require.False(t, logins[0].Exact)
+
+	res = f.do(http.MethodGet, lookupPath("http://github.com"), token, nil)
+	require.Equal(t, http.StatusOK, res.status)
+	require.Empty(t, decode[handlers.LoginsResponse](t, res).Logins)
+
+	res = f.do(http.MethodGet, lookupPath("about:blank"), token, nil)
+	require.Equal(t, http.StatusBadRequest, res.status)
+	require.Equal(t, "invalid_origin", errorCode(t, res))
+}
+
+func TestSaveLoginAndGeneratePassword(t *testing.T) {
+	f := newFixture(t)
+	token := f.pair()
+
+	res := f.do(http.MethodPost, "/v1/passwords/generate", token, nil)
+	require.Equal(t, http.StatusOK, res.status, string(res.body))
+	generated := decode[handlers.GeneratedPasswordResponse](t, res).Password
+	require.Len(t, generated, browser_extension_domain.DefaultPasswordLength)
+
+	res = f.do(http.MethodPost, "/v1/passwords/generate", token, browser_extension_domain.PasswordOptions{Length: 32, Digits: true})
+	require.Equal(t, http.StatusOK, res.status)
+	require.Len(t, decode[handlers.GeneratedPasswordResponse](t, res).Password, 32)
+
+	res = f.do(http.MethodPost, "/v1/passwords/generate", token, browser_extension_domain.PasswordOptions{Length: 3})
+	require.Equal(t, http.StatusBadRequest, res.status)
+
+	save := browser_extension_usecases.SaveLoginRequest{Origin: "https://shop.example.com/signup", Username: "alice", Password: generated}
+	res = f.do(http.MethodPost, "/v1/logins", token, save)
+	require.Equal(t, http.StatusCreated, res.status, string(res.body))
+	saved := decode[handlers.SavedLoginResponse](t, res)
+	require.Equal(t, "https://shop.example.com", saved.Website)
+	require.Equal(t, 1, f.vault.dirty)
+
+	res = f.do(http.MethodGet, lookupPath("https://shop.example.com"), token, nil)
+	require.Equal(t, generated, decode[handlers.LoginsResponse](t, res).Logins[0].Password)
+
+	res = f.do(http.MethodPost, "/v1/logins", token, save)
+	require.Equal(t, http.StatusConflict, res.status)
+	require.Equal(t, "login_exists", errorCode(t, res))
+
+	res = f.do(http.MethodPost, "/v1/logins", token, map[string]string{"origin": "https://a.test", "admin": "1"})
+	require.Equal(t, http.StatusBadRequest, res.status)
+}
+
+func TestTokensAreScoped(t *testing.T) {
+	f := newFixture(t)
+	token := f.pair(browser_extension_domain.ScopeAutofill)
+
+	res := f.do(http.MethodGet, lookupPath("https://github.com"), "", nil)
+	require.Equal(t, http.StatusUnauthorized, res.status)
+	res = f.do(http.MethodGet, lookupPath("https://github.com"), token+"x", nil)
+	require.Equal(t, http.StatusUnauthorized, res.status)
+
+	res = f.do(http.MethodGet, lookupPath("https://github.com"), token, nil)
+	require.Equal(t, http.StatusOK, res.status)
+	res = f.do(http.MethodPost, "/v1/logins", token, browser_extension_usecases.SaveLoginRequest{Origin: "https://a.test", Password: "x"})
+	require.Equal(t, http.StatusForbidden, res.status)
+	require.Equal(t, "scope_denied", errorCode(t, res))
+	res = f.do(http.MethodPost, "/v1/passwords/generate", token, nil)
+	require.Equal(t, http.StatusForbidden, res.status)
+	require.Empty(t, f.vault.vp.Entries.Login[2:])
+
+	clientID, _, _ := strings.Cut(token, ".")
+	require.NoError(t, f.api.Clients.Revoke(context.Background(), userID, clientID))
+	res = f.do(http.MethodGet, lookupPath("https://github.com"), token, nil)
+	require.Equal(t, http.StatusUnauthorized, res.status)
+}
+
+func TestPairingCodeErrors(t *testing.T) {
+	f := newFixture(t)
+
+	res := f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: "1234-5678"})
+	require.Equal(t, http.StatusUnauthorized, res.status)
+	require.Equal(t, "invalid_pairing_code", errorCode(t, res))
+
+	_, err := f.api.Pairing.Begin(userID, nil)
+	require.NoError(t, err)
+	for i := 1; i < browser_extension_usecases.MaxPairingAttempts; i++ {
+		res = f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: "wrong"})
+		require.Equal(t, http.StatusUnauthorized, res.status)
+	}
+	res = f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: "wrong"})
+	require.Equal(t, http.StatusTooManyRequests, res.status)
+
+	res = f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: "x", Scopes: []browser_extension_domain.Scope{"root"}})
+	require.Equal(t, http.StatusBadRequest, res.status)
+}
+
+func TestLockedVault(t *testing.T) {
+	f := newFixture(t)
+	token := f.pair()
+
+	f.api.Lock()
+	res := f.do(http.MethodGet, "/v1/status", "", nil)
+	require.True(t, decode[handlers.StatusResponse](t, res).Locked)
+	for _, r := range []struct{ method, path string }{
+		{http.MethodGet, lookupPath("https://github.com")},
+		{http.MethodPost, "/v1/logins"},
+		{http.MethodPost, "/v1/passwords/generate"},
+		{http.MethodPost, "/v1/pair"},
+	} {
+		res = f.do(r.method, r.path, token, nil)
+		require.Equal(t, http.StatusLocked, res.status, r.path)
+		require.Equal(t, "vault_locked", errorCode(t, res))
+	}
+
+	f.api.Unlock()
+	res = f.do(http.MethodGet, lookupPath("https://github.com"), token, nil)
+	require.Equal(t, http.StatusOK, res.status)
+
+	// A session that went away underneath the API is reported the same way.
+	f.vault.locked = true
+	res = f.do(http.MethodGet, lookupPath("https://github.com"), token, nil)
+	require.Equal(t, http.StatusLocked, res.status)
+}
+
+func TestFeatureGate(t *testing.T) {
+	f := newFixture(t)
+	token := f.pair()
+
+	f.gate.set(false)
+	res := f.do(http.MethodGet, lookupPath("https://github.com"), token, nil)
+	require.Equal(t, http.StatusForbidden, res.status)
+	require.Equal(t, "feature_disabled", errorCode(t, res))
+	res = f.do(http.MethodPost, "/v1/pair", "", handlers.PairRequest{Code: "1234-5678"})
+	require.Equal(t, http.StatusForbidden, res.status)
+}
+
+func TestOnlyLocalExtensionsAreServed(t *testing.T) {
+	f := newFixture(t)
+	token := f.pair()
+
+	// Web pages may not call the API, even with a stolen token.
+	req, err := http.NewRequest(http.MethodGet, f.server.URL+lookupPath("https://github.com"), nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://evil.example")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := f.server.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Extension preflights get CORS headers.
+	req, err = http.NewRequest(http.MethodOptions, f.server.URL+"/v1/logins", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", extensionOrigin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	resp, err = f.server.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Equal(t, extensionOrigin, resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Authorization")
+
+	// A DNS-rebound name pointing at loopback is refused.
+	req, err = http.NewRequest(http.MethodGet, f.server.URL+"/v1/status", nil)
+	require.NoError(t, err)
+	req.Host = "attacker.example:80"
+	resp, err = f.server.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// So are remote peers.
+	rec := httptest.NewRecorder()
+	remote := httptest.NewRequest(http.MethodGet, "http://127.0.0.1/v1/status", nil)
+	remote.RemoteAddr = "192.0.2.10:51000"
+	f.api.ServeHTTP(rec, remote)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestHandlerLocksWithVaultSession(t *testing.T) {
+	vault := &vaultMock{}
+	handler := browser_extension_ui.NewBrowserExtensionHandler(vault, browser_extension_persistence.NewMemoryClientRepository())
+	g := &gate{enabled: true}
+
+	_, err := handler.Start(userID, g, "0.0.0.0:0")
+	require.ErrorIs(t, err, browser_extension_domain.ErrNotLoopback)
+	_, err = handler.StartPairing(userID, nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrAPINotRunning)
+
+	addr, err := handler.Start(userID, g, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer handler.Stop()
+	_, err = handler.Start("user-2", g, "127.0.0.1:0")
+	require.ErrorIs(t, err, browser_extension_domain.ErrAPIRunning)
+
+	code, err := handler.StartPairing(userID, nil)
+	require.NoError(t, err)
+	// No keep-alives, so Stop does not wait on a connection the transport
+	// dialed ahead and never used.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	status := func() int {
+		resp, err := client.Post("http://"+addr+"/v1/pair", "application/json",
+			bytes.NewReader([]byte(`{"code":"`+code.Code+`","client_name":"Firefox"}`)))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	handler.OnVaultLocked(userID)
+	require.True(t, handler.Status().Locked)
+	require.Equal(t, http.StatusLocked, status())
+	_, err = handler.StartPairing(userID, nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrVaultLocked)
+
+	// Signing in again restarts the API in place; the old code was dropped.
+	again, err := handler.Start(userID, g, "")
+	require.NoError(t, err)
+	require.Equal(t, addr, again)
+	require.False(t, handler.Status().Locked)
+	require.Equal(t, http.StatusUnauthorized, status())
+
+	code, err = handler.StartPairing(userID, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, status())
+	clients, err := handler.ListClients(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+	require.Equal(t, "Firefox", clients[0].Name)
+
+	require.NoError(t, handler.Stop())
+	require.False(t, handler.Status().Running)
+	require.ErrorIs(t, handler.Stop(), browser_extension_domain.ErrAPINotRunning)
+}
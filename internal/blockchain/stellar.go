@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -12,6 +13,7 @@ import (
 	"time"
 	"vault-app/internal/logger/logger"
 	tracecore_models "vault-app/internal/tracecore/models"
+	tracecore_signing "vault-app/internal/tracecore/signing"
 	utils "vault-app/internal/utils"
 
 	// "time"
@@ -21,6 +23,7 @@ import (
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/txnbuild"
 )
 
@@ -346,10 +349,28 @@ func SignActorWithStellarPrivateKey(privateKey string, message string) (string,
 	return base64.StdEncoding.EncodeToString(sig), nil
 }
 
+// SignCommitAsActor signs the canonical actor bytes of the payload with the
+// actor's Stellar private key.
+func SignCommitAsActor(privateKey string, cp tracecore_models.CommitPayload) (string, error) {
+	data, err := tracecore_signing.ActorSigningBytes(cp)
+	if err != nil {
+		return "", err
+	}
+	kp, err := keypair.ParseFull(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("❌ failed to retrieve keypair from private key: %w", err)
+	}
+	sig, err := kp.Sign(data)
+	if err != nil {
+		return "", fmt.Errorf("❌ failed to sign the commit: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
 func SignWithDvaultPrivateKey(cp tracecore_models.CommitPayload) (string, error) {
-	data, err := json.Marshal(cp)
+	data, err := tracecore_signing.CanonicalPayload(cp)
 	if err != nil {
-		return "", fmt.Errorf("marshal commit payload failed: %w", err)
+		return "", err
 	}
 
 	kp, err := keypair.ParseFull(os.Getenv("TRACECORE_SECRETKEY"))
@@ -362,3 +383,61 @@ func SignWithDvaultPrivateKey(cp tracecore_models.CommitPayload) (string, error)
 	}
 	return base64.StdEncoding.EncodeToString(sig), nil
 }
+
+// DvaultPublicKey returns the app public key commit envelopes are signed
+// with: TRACECORE_PUBLICKEY when set, otherwise derived from
+// TRACECORE_SECRETKEY.
+func DvaultPublicKey() (string, error) {
+	if pub := os.Getenv("TRACECORE_PUBLICKEY"); pub != "" {
+		return pub, nil
+	}
+	kp, err := keypair.ParseFull(os.Getenv("TRACECORE_SECRETKEY"))
+	if err != nil {
+		return "", fmt.Errorf("parse key failed: %w", err)
+	}
+	return kp.Address(), nil
+}
+
+// StellarKeyVerifier verifies raw ed25519 signatures against Stellar
+// addresses. It implements tracecore_signing.KeyVerifier.
+type StellarKeyVerifier struct{}
+
+func (StellarKeyVerifier) Verify(publicKey string, message, signature []byte) error {
+	kp, err := keypair.ParseAddress(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return kp.Verify(message, signature)
+}
+
+// StellarIdentitySigner signs with a Stellar secret seed. It implements
+// channel_federation.IdentitySigner.
+type StellarIdentitySigner struct {
+	kp *keypair.Full
+}
+
+func NewStellarIdentitySigner(secretKey string) (*StellarIdentitySigner, error) {
+	kp, err := keypair.ParseFull(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key failed: %w", err)
+	}
+	return &StellarIdentitySigner{kp: kp}, nil
+}
+
+func (s *StellarIdentitySigner) PublicKey() string {
+	return s.kp.Address()
+}
+
+func (s *StellarIdentitySigner) Sign(message []byte) ([]byte, error) {
+	return s.kp.Sign(message)
+}
+
+// StellarDeviceKey returns the raw ed25519 key behind a Stellar secret seed,
+// for signers that work with registered device keys rather than addresses.
+func StellarDeviceKey(secretKey string) (ed25519.PrivateKey, error) {
+	seed, err := strkey.Decode(strkey.VersionByteSeed, secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key failed: %w", err)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
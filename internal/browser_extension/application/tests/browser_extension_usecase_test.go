@@ -0,0 +1,302 @@
+package browser_extension_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	browser_extension_usecases "vault-app/internal/browser_extension/application/usecases"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+	browser_extension_persistence "vault-app/internal/browser_extension/infrastructure/persistence"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type vaultMock struct {
+	vp     vaults_domain.VaultPayload
+	locked bool
+	dirty  int
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	return &m.vp, nil
+}
+
+func (m *vaultMock) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	e := entry.(*vaults_domain.LoginEntry)
+	e.ID = "saved-1"
+	m.vp.Entries.Login = append(m.vp.Entries.Login, *e)
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) MarkDirty(string) { m.dirty++ }
+
+func login(id, name, website, user string) vaults_domain.LoginEntry {
+	e := vaults_domain.LoginEntry{UserName: user, Password: id + "-pw", Website: website}
+	e.ID, e.EntryName = id, name
+	return e
+}
+
+var enabled = browser_extension_domain.FeatureGateFunc(func(string) (bool, error) { return true, nil })
+
+func TestMatchOrigin(t *testing.T) {
+	page, err := browser_extension_domain.ParseOrigin("https://Login.Example.com/signin?next=/")
+	require.NoError(t, err)
+	require.Equal(t, "https://login.example.com", page.String())
+
+	for _, tc := range []struct {
+		website        string
+		matched, exact bool
+	}{
+		{"https://login.example.com/account", true, true},
+		{"login.example.com", true, true},
+		{"https://login.example.com:443", true, true},
+		{"https://example.com", true, false},
+		{"example.com", true, false},
+		{"http://login.example.com", false, false},
+		{"https://login.example.com:8443", false, false},
+		{"https://notexample.com", false, false},
+		{"https://evil-example.com", false, false},
+		{"https://other.login.example.com", false, false},
+		{"https://com", false, false},
+		{"", false, false},
+		{"ftp://login.example.com", false, false},
+	} {
+		matched, exact := browser_extension_domain.MatchOrigin(tc.website, page)
+		require.Equal(t, tc.matched, matched, tc.website)
+		require.Equal(t, tc.exact, exact, tc.website)
+	}
+
+	local, err := browser_extension_domain.ParseOrigin("http://127.0.0.1:8080")
+	require.NoError(t, err)
+	matched, _ := browser_extension_domain.MatchOrigin("http://127.0.0.1:8080/admin", local)
+	require.True(t, matched)
+	matched, _ = browser_extension_domain.MatchOrigin("http://0.1:8080", local)
+	require.False(t, matched)
+
+	for _, bad := range []string{"", "chrome-extension://abc", "file:///etc/passwd", "not a url"} {
+		_, err := browser_extension_domain.ParseOrigin(bad)
+		require.ErrorIs(t, err, browser_extension_domain.ErrInvalidOrigin, bad)
+	}
+}
+
+func TestLookupLogins_OrdersExactHostFirst(t *testing.T) {
+	vault := &vaultMock{}
+	trashed := login("trashed", "Trashed", "https://login.example.com", "t")
+	trashed.Trashed = true
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{
+		login("parent", "Example", "example.com", "alice"),
+		login("exact-b", "b login", "https://login.example.com", "bob"),
+		login("exact-a", "A login", "https://login.example.com/path", "ann"),
+		login("other", "Other", "https://other.com", "x"),
+		trashed,
+	}
+	uc := browser_extension_usecases.NewLookupLoginsUsecase(vault, enabled)
+
+	res, err := uc.Execute(context.Background(), "user-1", "https://login.example.com/signin")
+	require.NoError(t, err)
+	var ids []string
+	for _, m := range res {
+		ids = append(ids, m.ID)
+	}
+	require.Equal(t, []string{"exact-a", "exact-b", "parent"}, ids)
+	require.Equal(t, "exact-a-pw", res[0].Password)
+	require.False(t, res[2].Exact)
+
+	res, err = uc.Execute(context.Background(), "user-1", "https://nothing.test")
+	require.NoError(t, err)
+	require.Empty(t, res)
+
+	_, err = uc.Execute(context.Background(), "user-1", "javascript:alert(1)")
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidOrigin)
+}
+
+func TestVaultUsecases_RefuseWithoutFeatureOrSession(t *testing.T) {
+	ctx := context.Background()
+	disabled := browser_extension_domain.FeatureGateFunc(func(string) (bool, error) { return false, nil })
+
+	_, err := browser_extension_usecases.NewLookupLoginsUsecase(&vaultMock{}, disabled).Execute(ctx, "user-1", "https://a.test")
+	require.ErrorIs(t, err, browser_extension_domain.ErrFeatureDisabled)
+	_, err = browser_extension_usecases.NewGeneratePasswordUsecase(disabled).Execute(ctx, "user-1", browser_extension_domain.PasswordOptions{})
+	require.ErrorIs(t, err, browser_extension_domain.ErrFeatureDisabled)
+	_, err = browser_extension_usecases.NewPairingUsecase(browser_extension_persistence.NewMemoryClientRepository(), disabled).Begin("user-1", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrFeatureDisabled)
+
+	_, err = browser_extension_usecases.NewLookupLoginsUsecase(&vaultMock{locked: true}, enabled).Execute(ctx, "user-1", "https://a.test")
+	require.ErrorIs(t, err, browser_extension_domain.ErrVaultLocked)
+	_, err = browser_extension_usecases.NewSaveLoginUsecase(&vaultMock{locked: true}, enabled).Execute(ctx, "user-1", browser_extension_usecases.SaveLoginRequest{})
+	require.ErrorIs(t, err, browser_extension_domain.ErrVaultLocked)
+}
+
+func TestSaveLogin_CreatesOnceThenReportsExisting(t *testing.T) {
+	vault := &vaultMock{}
+	uc := browser_extension_usecases.NewSaveLoginUsecase(vault, enabled)
+	ctx := context.Background()
+
+	req := browser_extension_usecases.SaveLoginRequest{
+		Origin:   "https://Shop.example.com/checkout",
+		Username: "alice",
+		Password: "hunter2",
+	}
+	entry, err := uc.Execute(ctx, "user-1", req)
+	require.NoError(t, err)
+	require.Equal(t, "https://shop.example.com", entry.Website)
+	require.Equal(t, "shop.example.com", entry.EntryName)
+	require.Equal(t, vaults_domain.EntryLogin, entry.Type)
+	require.Equal(t, 1, vault.dirty)
+
+	_, err = uc.Execute(ctx, "user-1", req)
+	require.ErrorIs(t, err, browser_extension_domain.ErrLoginExists)
+
+	req.Username, req.Name = "bob", "Shop (Bob)"
+	entry, err = uc.Execute(ctx, "user-1", req)
+	require.NoError(t, err)
+	require.Equal(t, "Shop (Bob)", entry.EntryName)
+	require.Len(t, vault.vp.Entries.Login, 2)
+
+	req.Password = ""
+	_, err = uc.Execute(ctx, "user-1", req)
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidLogin)
+}
+
+func TestGeneratePassword(t *testing.T) {
+	uc := browser_extension_usecases.NewGeneratePasswordUsecase(enabled)
+	ctx := context.Background()
+
+	pw, err := uc.Execute(ctx, "user-1", browser_extension_domain.PasswordOptions{})
+	require.NoError(t, err)
+	require.Len(t, pw, browser_extension_domain.DefaultPasswordLength)
+	for _, class := range []string{"abcdefghijkmnopqrstuvwxyz", "ABCDEFGHJKLMNPQRSTUVWXYZ", "23456789", "!#$%&*+-=?@^_~"} {
+		require.True(t, strings.ContainsAny(pw, class), "%q lacks one of %q", pw, class)
+	}
+
+	pw, err = uc.Execute(ctx, "user-1", browser_extension_domain.PasswordOptions{Length: 12, Digits: true})
+	require.NoError(t, err)
+	require.Len(t, pw, 12)
+	require.Empty(t, strings.Trim(pw, "23456789"))
+
+	_, err = uc.Execute(ctx, "user-1", browser_extension_domain.PasswordOptions{Length: 4})
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidPasswordSpec)
+	_, err = uc.Execute(ctx, "user-1", browser_extension_domain.PasswordOptions{Length: 500, Symbols: true})
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidPasswordSpec)
+}
+
+func TestPairing_IssuesScopedTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := browser_extension_persistence.NewMemoryClientRepository()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	pairing := browser_extension_usecases.NewPairingUsecase(repo, enabled)
+	pairing.Now = func() time.Time { return now }
+	clients := browser_extension_usecases.NewClientsUsecase(repo)
+
+	code, err := pairing.Begin("user-1", []browser_extension_domain.Scope{browser_extension_domain.ScopeAutofill, browser_extension_domain.ScopeGeneratePassword})
+	require.NoError(t, err)
+	require.Regexp(t, `^\d{4}-\d{4}$`, code.Code)
+
+	// Another user's API cannot redeem it.
+	_, err = pairing.Complete(ctx, "user-2", code.Code, "Firefox", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrNoPairing)
+
+	// Asking only for scopes the pairing does not grant fails without consuming it.
+	_, err = pairing.Complete(ctx, "user-1", code.Code, "Firefox", []browser_extension_domain.Scope{browser_extension_domain.ScopeSaveLogin})
+	require.ErrorIs(t, err, browser_extension_domain.ErrScopeDenied)
+	code, err = pairing.Begin("user-1", []browser_extension_domain.Scope{browser_extension_domain.ScopeAutofill, browser_extension_domain.ScopeGeneratePassword})
+	require.NoError(t, err)
+
+	res, err := pairing.Complete(ctx, "user-1", strings.ReplaceAll(code.Code, "-", " "), "Firefox",
+		[]browser_extension_domain.Scope{browser_extension_domain.ScopeAutofill, browser_extension_domain.ScopeSaveLogin})
+	require.NoError(t, err)
+	require.Equal(t, []browser_extension_domain.Scope{browser_extension_domain.ScopeAutofill}, res.Scopes)
+
+	// Codes are single use.
+	_, err = pairing.Complete(ctx, "user-1", code.Code, "Firefox", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrNoPairing)
+
+	client, err := clients.Authenticate(ctx, "user-1", res.Token)
+	require.NoError(t, err)
+	require.Equal(t, "Firefox", client.Name)
+	require.True(t, client.Allows(browser_extension_domain.ScopeAutofill))
+	require.False(t, client.Allows(browser_extension_domain.ScopeSaveLogin))
+	require.NotNil(t, client.LastUsedAt)
+
+	_, err = clients.Authenticate(ctx, "user-2", res.Token)
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidToken)
+	_, err = clients.Authenticate(ctx, "user-1", res.ClientID+".forged")
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidToken)
+	_, err = clients.Authenticate(ctx, "user-1", "garbage")
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidToken)
+
+	require.ErrorIs(t, clients.Revoke(ctx, "user-2", res.ClientID), browser_extension_domain.ErrClientNotFound)
+	require.NoError(t, clients.Revoke(ctx, "user-1", res.ClientID))
+	_, err = clients.Authenticate(ctx, "user-1", res.Token)
+	require.ErrorIs(t, err, browser_extension_domain.ErrInvalidToken)
+}
+
+func TestPairing_ExpiresAndLimitsAttempts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	pairing := browser_extension_usecases.NewPairingUsecase(browser_extension_persistence.NewMemoryClientRepository(), enabled)
+	pairing.Now = func() time.Time { return now }
+
+	code, err := pairing.Begin("user-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, browser_extension_domain.AllScopes, code.Scopes)
+	now = now.Add(browser_extension_usecases.DefaultPairingTTL + time.Second)
+	_, err = pairing.Complete(ctx, "user-1", code.Code, "", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrPairingExpired)
+
+	code, err = pairing.Begin("user-1", nil)
+	require.NoError(t, err)
+	for i := 1; i < browser_extension_usecases.MaxPairingAttempts; i++ {
+		_, err = pairing.Complete(ctx, "user-1", "0000-000x", "", nil)
+		require.ErrorIs(t, err, browser_extension_domain.ErrInvalidPairingCode)
+	}
+	_, err = pairing.Complete(ctx, "user-1", "0000-000x", "", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrTooManyAttempts)
+	_, err = pairing.Complete(ctx, "user-1", code.Code, "", nil)
+	require.ErrorIs(t, err, browser_extension_domain.ErrNoPairing)
+
+	_, err = pairing.Begin("user-1", []browser_extension_domain.Scope{"admin"})
+	require.ErrorIs(t, err, browser_extension_domain.ErrUnknownScope)
+}
+
+func TestGormClientRepository(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&browser_extension_domain.PairedClient{}))
+	repo := browser_extension_persistence.NewGormClientRepository(db)
+	ctx := context.Background()
+
+	client := &browser_extension_domain.PairedClient{
+		ID: "c1", UserID: "user-1", Name: "Chrome", TokenHash: "h",
+		Scopes:    []browser_extension_domain.Scope{browser_extension_domain.ScopeAutofill},
+		CreatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, repo.Save(ctx, client))
+	used := client.CreatedAt.Add(time.Hour)
+	client.LastUsedAt = &used
+	require.NoError(t, repo.Save(ctx, client))
+
+	got, err := repo.FindByID(ctx, "c1")
+	require.NoError(t, err)
+	require.Equal(t, client.Scopes, got.Scopes)
+	require.True(t, used.Equal(*got.LastUsedAt))
+
+	list, err := repo.ListByUser(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, repo.Delete(ctx, "c1"))
+	_, err = repo.FindByID(ctx, "c1")
+	require.ErrorIs(t, err, browser_extension_domain.ErrClientNotFound)
+}
@@ -0,0 +1,72 @@
+package browser_extension_usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// ClientsUsecase authenticates paired clients and lets the user review and
+// revoke them.
+type ClientsUsecase struct {
+	Clients browser_extension_domain.ClientRepository
+	Now     func() time.Time
+}
+
+func NewClientsUsecase(clients browser_extension_domain.ClientRepository) *ClientsUsecase {
+	return &ClientsUsecase{Clients: clients, Now: time.Now}
+}
+
+// Authenticate resolves a "<client id>.<secret>" token to its client,
+// which must belong to userID, and records the use.
+func (uc *ClientsUsecase) Authenticate(ctx context.Context, userID string, token string) (*browser_extension_domain.PairedClient, error) {
+	if uc.Clients == nil {
+		return nil, fmt.Errorf("browser extension client repository is not initialized")
+	}
+	id, secret, ok := strings.Cut(token, ".")
+	if !ok || id == "" || secret == "" {
+		return nil, browser_extension_domain.ErrInvalidToken
+	}
+	client, err := uc.Clients.FindByID(ctx, id)
+	if err != nil || client == nil {
+		return nil, browser_extension_domain.ErrInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(client.TokenHash)) != 1 || client.UserID != userID {
+		return nil, browser_extension_domain.ErrInvalidToken
+	}
+	now := time.Now()
+	if uc.Now != nil {
+		now = uc.Now()
+	}
+	client.LastUsedAt = &now
+	if err := uc.Clients.Save(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (uc *ClientsUsecase) List(ctx context.Context, userID string) ([]browser_extension_domain.PairedClient, error) {
+	if uc.Clients == nil {
+		return nil, fmt.Errorf("browser extension client repository is not initialized")
+	}
+	if userID == "" {
+		return nil, browser_extension_domain.ErrUserIDRequired
+	}
+	return uc.Clients.ListByUser(ctx, userID)
+}
+
+// Revoke deletes a client; its token stops working immediately.
+func (uc *ClientsUsecase) Revoke(ctx context.Context, userID string, clientID string) error {
+	if uc.Clients == nil {
+		return fmt.Errorf("browser extension client repository is not initialized")
+	}
+	client, err := uc.Clients.FindByID(ctx, clientID)
+	if err != nil || client == nil || client.UserID != userID {
+		return browser_extension_domain.ErrClientNotFound
+	}
+	return uc.Clients.Delete(ctx, clientID)
+}
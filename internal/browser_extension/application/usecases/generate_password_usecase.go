@@ -0,0 +1,34 @@
+package browser_extension_usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// GeneratePasswordUsecase suggests a password for a sign-up form.
+type GeneratePasswordUsecase struct {
+	Gate browser_extension_domain.FeatureGate
+	Rand io.Reader
+}
+
+func NewGeneratePasswordUsecase(gate browser_extension_domain.FeatureGate) *GeneratePasswordUsecase {
+	return &GeneratePasswordUsecase{Gate: gate, Rand: rand.Reader}
+}
+
+// Execute uses every character class when opts is left empty.
+func (uc *GeneratePasswordUsecase) Execute(ctx context.Context, userID string, opts browser_extension_domain.PasswordOptions) (string, error) {
+	if err := checkFeature(uc.Gate, userID); err != nil {
+		return "", err
+	}
+	if !opts.Lowercase && !opts.Uppercase && !opts.Digits && !opts.Symbols {
+		length := opts.Length
+		opts = browser_extension_domain.DefaultPasswordOptions
+		if length != 0 {
+			opts.Length = length
+		}
+	}
+	return browser_extension_domain.GeneratePassword(uc.Rand, opts)
+}
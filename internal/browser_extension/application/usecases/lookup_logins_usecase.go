@@ -0,0 +1,56 @@
+package browser_extension_usecases
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// LookupLoginsUsecase finds the logins to offer on a page.
+type LookupLoginsUsecase struct {
+	Vault VaultEntries
+	Gate  browser_extension_domain.FeatureGate
+}
+
+func NewLookupLoginsUsecase(vault VaultEntries, gate browser_extension_domain.FeatureGate) *LookupLoginsUsecase {
+	return &LookupLoginsUsecase{Vault: vault, Gate: gate}
+}
+
+// Execute returns the logins matching origin, exact-host ones first.
+func (uc *LookupLoginsUsecase) Execute(ctx context.Context, userID string, origin string) ([]browser_extension_domain.LoginMatch, error) {
+	vp, err := openVault(uc.Gate, uc.Vault, userID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := browser_extension_domain.ParseOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+	out := []browser_extension_domain.LoginMatch{}
+	for _, e := range vp.Entries.Login {
+		if e.Trashed {
+			continue
+		}
+		matched, exact := browser_extension_domain.MatchOrigin(e.Website, page)
+		if !matched {
+			continue
+		}
+		out = append(out, browser_extension_domain.LoginMatch{
+			ID:       e.ID,
+			Name:     e.EntryName,
+			Username: e.UserName,
+			Password: e.Password,
+			Website:  e.Website,
+			Exact:    exact,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Exact != out[j].Exact {
+			return out[i].Exact
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out, nil
+}
@@ -0,0 +1,208 @@
+package browser_extension_usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+const (
+	DefaultPairingTTL  = 5 * time.Minute
+	MaxPairingAttempts = 5
+	pairingCodeDigits  = 8
+)
+
+type pendingPairing struct {
+	userID    string
+	code      string
+	scopes    []browser_extension_domain.Scope
+	expiresAt time.Time
+	attempts  int
+}
+
+// PairingUsecase registers extension installs. The app starts a pairing
+// and shows its code; the extension completes it with the code and gets a
+// token limited to the pairing's scopes. Only one pairing is pending at a
+// time, and a few wrong codes cancel it.
+type PairingUsecase struct {
+	Clients browser_extension_domain.ClientRepository
+	Gate    browser_extension_domain.FeatureGate
+	Rand    io.Reader
+	Now     func() time.Time
+	TTL     time.Duration
+
+	mu      sync.Mutex
+	pending *pendingPairing
+}
+
+func NewPairingUsecase(clients browser_extension_domain.ClientRepository, gate browser_extension_domain.FeatureGate) *PairingUsecase {
+	return &PairingUsecase{Clients: clients, Gate: gate, Rand: rand.Reader, Now: time.Now, TTL: DefaultPairingTTL}
+}
+
+// Begin replaces any pending pairing with a new code. No scopes means all.
+func (uc *PairingUsecase) Begin(userID string, scopes []browser_extension_domain.Scope) (*browser_extension_domain.PairingCode, error) {
+	if err := checkFeature(uc.Gate, userID); err != nil {
+		return nil, err
+	}
+	scopes, err := browser_extension_domain.ValidateScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		scopes = browser_extension_domain.AllScopes
+	}
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(pairingCodeDigits), nil)
+	n, err := rand.Int(uc.random(), limit)
+	if err != nil {
+		return nil, err
+	}
+	code := fmt.Sprintf("%0*d", pairingCodeDigits, n)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.pending = &pendingPairing{
+		userID:    userID,
+		code:      code,
+		scopes:    scopes,
+		expiresAt: uc.now().Add(uc.ttl()),
+	}
+	return &browser_extension_domain.PairingCode{
+		Code:      code[:4] + "-" + code[4:],
+		Scopes:    scopes,
+		ExpiresAt: uc.pending.expiresAt,
+	}, nil
+}
+
+// Cancel drops the pending pairing of userID, if any.
+func (uc *PairingUsecase) Cancel(userID string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.pending != nil && uc.pending.userID == userID {
+		uc.pending = nil
+	}
+}
+
+// Complete trades the code for a client token. The extension may ask for
+// fewer scopes than the pairing allows, never more.
+func (uc *PairingUsecase) Complete(ctx context.Context, userID string, code string, name string, requested []browser_extension_domain.Scope) (*browser_extension_domain.PairingResult, error) {
+	if uc.Clients == nil {
+		return nil, fmt.Errorf("browser extension client repository is not initialized")
+	}
+	if err := checkFeature(uc.Gate, userID); err != nil {
+		return nil, err
+	}
+	requested, err := browser_extension_domain.ValidateScopes(requested)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := uc.take(userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := pending.scopes
+	if len(requested) > 0 {
+		scopes = nil
+		for _, s := range requested {
+			for _, allowed := range pending.scopes {
+				if s == allowed {
+					scopes = append(scopes, s)
+				}
+			}
+		}
+		if len(scopes) == 0 {
+			return nil, browser_extension_domain.ErrScopeDenied
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(uc.random(), secret); err != nil {
+		return nil, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Browser extension"
+	}
+	client := &browser_extension_domain.PairedClient{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		TokenHash: hashSecret(encoded),
+		CreatedAt: uc.now(),
+	}
+	if err := uc.Clients.Save(ctx, client); err != nil {
+		return nil, err
+	}
+	return &browser_extension_domain.PairingResult{
+		ClientID: client.ID,
+		Token:    client.ID + "." + encoded,
+		Scopes:   scopes,
+	}, nil
+}
+
+// take consumes the pending pairing when code matches it.
+func (uc *PairingUsecase) take(userID string, code string) (*pendingPairing, error) {
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	p := uc.pending
+	if p == nil || p.userID != userID {
+		return nil, browser_extension_domain.ErrNoPairing
+	}
+	if uc.now().After(p.expiresAt) {
+		uc.pending = nil
+		return nil, browser_extension_domain.ErrPairingExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(code), []byte(p.code)) != 1 {
+		p.attempts++
+		if p.attempts >= MaxPairingAttempts {
+			uc.pending = nil
+			return nil, browser_extension_domain.ErrTooManyAttempts
+		}
+		return nil, browser_extension_domain.ErrInvalidPairingCode
+	}
+	uc.pending = nil
+	return p, nil
+}
+
+func (uc *PairingUsecase) random() io.Reader {
+	if uc.Rand == nil {
+		return rand.Reader
+	}
+	return uc.Rand
+}
+
+func (uc *PairingUsecase) now() time.Time {
+	if uc.Now == nil {
+		return time.Now()
+	}
+	return uc.Now()
+}
+
+func (uc *PairingUsecase) ttl() time.Duration {
+	if uc.TTL <= 0 {
+		return DefaultPairingTTL
+	}
+	return uc.TTL
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
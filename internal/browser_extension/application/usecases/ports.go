@@ -0,0 +1,49 @@
+package browser_extension_usecases
+
+import (
+	"fmt"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultEntries is the part of the vault handler the API reads logins from
+// and saves captured ones through, so they follow the normal commit path.
+type VaultEntries interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+	AddEntryFor(userID string, entry any) (*vaults_domain.VaultEntry, error)
+	MarkDirty(userID string)
+}
+
+// checkFeature refuses users whose subscription lacks the extension.
+func checkFeature(gate browser_extension_domain.FeatureGate, userID string) error {
+	if gate == nil {
+		return fmt.Errorf("browser extension feature gate is not initialized")
+	}
+	if userID == "" {
+		return browser_extension_domain.ErrUserIDRequired
+	}
+	enabled, err := gate.BrowserExtensionEnabled(userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return browser_extension_domain.ErrFeatureDisabled
+	}
+	return nil
+}
+
+// openVault checks the feature and returns the unlocked vault of userID.
+func openVault(gate browser_extension_domain.FeatureGate, vault VaultEntries, userID string) (*vaults_domain.VaultPayload, error) {
+	if vault == nil {
+		return nil, fmt.Errorf("browser extension vault session is not initialized")
+	}
+	if err := checkFeature(gate, userID); err != nil {
+		return nil, err
+	}
+	vp, err := vault.GetVaultSession(userID)
+	if err != nil || vp == nil {
+		return nil, fmt.Errorf("%w: %v", browser_extension_domain.ErrVaultLocked, err)
+	}
+	return vp, nil
+}
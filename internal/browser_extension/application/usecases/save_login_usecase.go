@@ -0,0 +1,69 @@
+package browser_extension_usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type SaveLoginRequest struct {
+	Origin   string `json:"origin"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	FolderID string `json:"folder_id"`
+}
+
+// SaveLoginUsecase stores a login captured on a page. It never overwrites:
+// a login already saved for the same origin and username is reported so
+// the user can edit it in the app.
+type SaveLoginUsecase struct {
+	Vault VaultEntries
+	Gate  browser_extension_domain.FeatureGate
+}
+
+func NewSaveLoginUsecase(vault VaultEntries, gate browser_extension_domain.FeatureGate) *SaveLoginUsecase {
+	return &SaveLoginUsecase{Vault: vault, Gate: gate}
+}
+
+func (uc *SaveLoginUsecase) Execute(ctx context.Context, userID string, req SaveLoginRequest) (*vaults_domain.LoginEntry, error) {
+	vp, err := openVault(uc.Gate, uc.Vault, userID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := browser_extension_domain.ParseOrigin(req.Origin)
+	if err != nil {
+		return nil, err
+	}
+	if req.Password == "" {
+		return nil, fmt.Errorf("%w: password is required", browser_extension_domain.ErrInvalidLogin)
+	}
+	for _, e := range vp.Entries.Login {
+		if e.Trashed || e.UserName != req.Username {
+			continue
+		}
+		if _, exact := browser_extension_domain.MatchOrigin(e.Website, page); exact {
+			return nil, browser_extension_domain.ErrLoginExists
+		}
+	}
+
+	entry := &vaults_domain.LoginEntry{
+		UserName: req.Username,
+		Password: req.Password,
+		Website:  page.String(),
+	}
+	entry.EntryName = strings.TrimSpace(req.Name)
+	if entry.EntryName == "" {
+		entry.EntryName = page.Host
+	}
+	entry.FolderID = req.FolderID
+	entry.Type = vaults_domain.EntryLogin
+	if _, err := uc.Vault.AddEntryFor(userID, entry); err != nil {
+		return nil, err
+	}
+	uc.Vault.MarkDirty(userID)
+	return entry, nil
+}
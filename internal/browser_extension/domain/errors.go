@@ -0,0 +1,24 @@
+package browser_extension_domain
+
+import "errors"
+
+var (
+	ErrFeatureDisabled     = errors.New("browser extension is not included in your subscription")
+	ErrVaultLocked         = errors.New("vault is locked")
+	ErrUserIDRequired      = errors.New("user id is required")
+	ErrNoPairing           = errors.New("no pairing in progress")
+	ErrInvalidPairingCode  = errors.New("invalid pairing code")
+	ErrPairingExpired      = errors.New("pairing code expired")
+	ErrTooManyAttempts     = errors.New("too many pairing attempts")
+	ErrInvalidToken        = errors.New("invalid or revoked client token")
+	ErrScopeDenied         = errors.New("client is not allowed this operation")
+	ErrUnknownScope        = errors.New("unknown scope")
+	ErrClientNotFound      = errors.New("browser extension client not found")
+	ErrInvalidOrigin       = errors.New("origin must be an http or https origin")
+	ErrInvalidLogin        = errors.New("invalid login")
+	ErrLoginExists         = errors.New("a login for this site and username already exists")
+	ErrInvalidPasswordSpec = errors.New("invalid password options")
+	ErrNotLoopback         = errors.New("browser extension API only listens on loopback addresses")
+	ErrAPIRunning          = errors.New("browser extension API is already running")
+	ErrAPINotRunning       = errors.New("browser extension API is not running")
+)
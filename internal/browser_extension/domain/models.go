@@ -0,0 +1,114 @@
+package browser_extension_domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Scope is one thing a paired client may do.
+type Scope string
+
+const (
+	// ScopeAutofill reads the logins matching the page origin.
+	ScopeAutofill Scope = "autofill"
+	// ScopeSaveLogin saves a login captured from a page.
+	ScopeSaveLogin Scope = "save_login"
+	// ScopeGeneratePassword asks for a new random password.
+	ScopeGeneratePassword Scope = "generate_password"
+)
+
+// AllScopes is granted when a pairing does not restrict them.
+var AllScopes = []Scope{ScopeAutofill, ScopeSaveLogin, ScopeGeneratePassword}
+
+func (s Scope) Valid() bool {
+	for _, known := range AllScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateScopes rejects unknown scopes and drops duplicates.
+func ValidateScopes(scopes []Scope) ([]Scope, error) {
+	out := make([]Scope, 0, len(scopes))
+	seen := map[Scope]bool{}
+	for _, s := range scopes {
+		if !s.Valid() {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownScope, s)
+		}
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// PairedClient is a browser extension install allowed to call the API. Only
+// a digest of its token is kept.
+type PairedClient struct {
+	ID         string     `json:"id" gorm:"primaryKey;size:64"`
+	UserID     string     `json:"user_id" gorm:"index;size:64"`
+	Name       string     `json:"name"`
+	Scopes     []Scope    `json:"scopes" gorm:"serializer:json"`
+	TokenHash  string     `json:"-" gorm:"size:64"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (PairedClient) TableName() string { return "browser_extension_clients" }
+
+func (c *PairedClient) Allows(scope Scope) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type ClientRepository interface {
+	Save(ctx context.Context, c *PairedClient) error
+	FindByID(ctx context.Context, id string) (*PairedClient, error)
+	ListByUser(ctx context.Context, userID string) ([]PairedClient, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PairingCode is shown in the app and typed into the extension.
+type PairingCode struct {
+	Code      string    `json:"code"`
+	Scopes    []Scope   `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PairingResult is returned once to the extension; the token is not
+// recoverable afterwards.
+type PairingResult struct {
+	ClientID string  `json:"client_id"`
+	Token    string  `json:"token"`
+	Scopes   []Scope `json:"scopes"`
+}
+
+// LoginMatch is a login offered for autofill.
+type LoginMatch struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Website  string `json:"website"`
+	// Exact is false when the login was saved for a parent domain.
+	Exact bool `json:"exact"`
+}
+
+// FeatureGate tells whether the user's subscription includes the browser
+// extension (FeatureFlags.BrowserExtensionEnabled).
+type FeatureGate interface {
+	BrowserExtensionEnabled(userID string) (bool, error)
+}
+
+// FeatureGateFunc adapts a function to FeatureGate.
+type FeatureGateFunc func(userID string) (bool, error)
+
+func (f FeatureGateFunc) BrowserExtensionEnabled(userID string) (bool, error) { return f(userID) }
@@ -0,0 +1,84 @@
+package browser_extension_domain
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Origin is a normalized web origin: lowercase scheme and host, and the
+// port spelled out.
+type Origin struct {
+	Scheme string
+	Host   string
+	Port   string
+}
+
+func (o Origin) String() string {
+	if o.Port == defaultPort(o.Scheme) {
+		return o.Scheme + "://" + o.Host
+	}
+	return o.Scheme + "://" + net.JoinHostPort(o.Host, o.Port)
+}
+
+// ParseOrigin accepts an origin or a full page URL, as the extension may
+// send either, and keeps only scheme, host and port.
+func ParseOrigin(s string) (Origin, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Hostname() == "" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	return originOf(u)
+}
+
+// EntryOrigin reads the origin of a login's website; a website without a
+// scheme is taken as https.
+func EntryOrigin(website string) (Origin, error) {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	if !strings.Contains(website, "://") {
+		website = "https://" + website
+	}
+	return ParseOrigin(website)
+}
+
+func originOf(u *url.URL) (Origin, error) {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "https" && scheme != "http" {
+		return Origin{}, ErrInvalidOrigin
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort(scheme)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	return Origin{Scheme: scheme, Host: host, Port: port}, nil
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "http" {
+		return "80"
+	}
+	return "443"
+}
+
+// MatchOrigin tells whether a login saved for website may be filled on
+// page, and whether it was saved for that exact host. Scheme and port must
+// be equal, so an https login is never offered to an http page. A login
+// saved for example.com also fills its subdomains; IP addresses only match
+// themselves.
+func MatchOrigin(website string, page Origin) (matched bool, exact bool) {
+	entry, err := EntryOrigin(website)
+	if err != nil || entry.Scheme != page.Scheme || entry.Port != page.Port {
+		return false, false
+	}
+	if entry.Host == page.Host {
+		return true, true
+	}
+	if net.ParseIP(entry.Host) != nil || net.ParseIP(page.Host) != nil || !strings.Contains(entry.Host, ".") {
+		return false, false
+	}
+	return strings.HasSuffix(page.Host, "."+entry.Host), false
+}
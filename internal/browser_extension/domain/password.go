@@ -0,0 +1,99 @@
+package browser_extension_domain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+const (
+	lowercaseChars = "abcdefghijkmnopqrstuvwxyz"
+	uppercaseChars = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digitChars     = "23456789"
+	symbolChars    = "!#$%&*+-=?@^_~"
+
+	MinPasswordLength     = 8
+	MaxPasswordLength     = 128
+	DefaultPasswordLength = 20
+)
+
+// PasswordOptions picks the character classes of a generated password.
+// Look-alike characters (l, I, O, 0, 1) are left out.
+type PasswordOptions struct {
+	Length    int  `json:"length"`
+	Lowercase bool `json:"lowercase"`
+	Uppercase bool `json:"uppercase"`
+	Digits    bool `json:"digits"`
+	Symbols   bool `json:"symbols"`
+}
+
+// DefaultPasswordOptions uses every class.
+var DefaultPasswordOptions = PasswordOptions{
+	Length: DefaultPasswordLength, Lowercase: true, Uppercase: true, Digits: true, Symbols: true,
+}
+
+// GeneratePassword draws a password from random with at least one
+// character of each selected class.
+func GeneratePassword(random io.Reader, opts PasswordOptions) (string, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	if opts.Length == 0 {
+		opts.Length = DefaultPasswordLength
+	}
+	if opts.Length < MinPasswordLength || opts.Length > MaxPasswordLength {
+		return "", fmt.Errorf("%w: length must be between %d and %d", ErrInvalidPasswordSpec, MinPasswordLength, MaxPasswordLength)
+	}
+	var classes []string
+	for _, c := range []struct {
+		on    bool
+		chars string
+	}{
+		{opts.Lowercase, lowercaseChars},
+		{opts.Uppercase, uppercaseChars},
+		{opts.Digits, digitChars},
+		{opts.Symbols, symbolChars},
+	} {
+		if c.on {
+			classes = append(classes, c.chars)
+		}
+	}
+	if len(classes) == 0 {
+		return "", fmt.Errorf("%w: no character class selected", ErrInvalidPasswordSpec)
+	}
+	all := ""
+	for _, c := range classes {
+		all += c
+	}
+
+	out := make([]byte, opts.Length)
+	for i := range out {
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		ch, err := pick(random, set)
+		if err != nil {
+			return "", err
+		}
+		out[i] = ch
+	}
+	// Move the guaranteed characters away from the front.
+	for i := len(out) - 1; i > 0; i-- {
+		j, err := rand.Int(random, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		out[i], out[j.Int64()] = out[j.Int64()], out[i]
+	}
+	return string(out), nil
+}
+
+func pick(random io.Reader, set string) (byte, error) {
+	n, err := rand.Int(random, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+	return set[n.Int64()], nil
+}
@@ -0,0 +1,48 @@
+package browser_extension_persistence
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+type GormClientRepository struct {
+	db *gorm.DB
+}
+
+func NewGormClientRepository(db *gorm.DB) *GormClientRepository {
+	return &GormClientRepository{db: db}
+}
+
+func (r *GormClientRepository) Save(ctx context.Context, c *browser_extension_domain.PairedClient) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+func (r *GormClientRepository) FindByID(ctx context.Context, id string) (*browser_extension_domain.PairedClient, error) {
+	var c browser_extension_domain.PairedClient
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, browser_extension_domain.ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *GormClientRepository) ListByUser(ctx context.Context, userID string) ([]browser_extension_domain.PairedClient, error) {
+	var out []browser_extension_domain.PairedClient
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *GormClientRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&browser_extension_domain.PairedClient{}).Error
+}
+
+var _ browser_extension_domain.ClientRepository = (*GormClientRepository)(nil)
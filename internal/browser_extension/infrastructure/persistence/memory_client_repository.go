@@ -0,0 +1,57 @@
+package browser_extension_persistence
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+type MemoryClientRepository struct {
+	mu      sync.Mutex
+	clients map[string]browser_extension_domain.PairedClient
+}
+
+func NewMemoryClientRepository() *MemoryClientRepository {
+	return &MemoryClientRepository{clients: map[string]browser_extension_domain.PairedClient{}}
+}
+
+func (r *MemoryClientRepository) Save(_ context.Context, c *browser_extension_domain.PairedClient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[c.ID] = *c
+	return nil
+}
+
+func (r *MemoryClientRepository) FindByID(_ context.Context, id string) (*browser_extension_domain.PairedClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.clients[id]
+	if !ok {
+		return nil, browser_extension_domain.ErrClientNotFound
+	}
+	return &c, nil
+}
+
+func (r *MemoryClientRepository) ListByUser(_ context.Context, userID string) ([]browser_extension_domain.PairedClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []browser_extension_domain.PairedClient{}
+	for _, c := range r.clients {
+		if c.UserID == userID {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *MemoryClientRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, id)
+	return nil
+}
+
+var _ browser_extension_domain.ClientRepository = (*MemoryClientRepository)(nil)
@@ -0,0 +1,162 @@
+package browser_extension_ui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"vault-app/internal/api/http/handlers"
+	browser_extension_usecases "vault-app/internal/browser_extension/application/usecases"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
+)
+
+// DefaultAddress is where the extension looks for the app.
+const DefaultAddress = "127.0.0.1:47318"
+
+// APIStatus is what the settings screen shows about the API.
+type APIStatus struct {
+	Running bool   `json:"running"`
+	Locked  bool   `json:"locked"`
+	Address string `json:"address,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+}
+
+// BrowserExtensionHandler runs the loopback API for the user who started
+// it and manages the extension installs paired with it.
+type BrowserExtensionHandler struct {
+	vault   browser_extension_usecases.VaultEntries
+	clients *browser_extension_usecases.ClientsUsecase
+
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener
+	api      *handlers.BrowserExtensionAPI
+}
+
+func NewBrowserExtensionHandler(vault browser_extension_usecases.VaultEntries, clients browser_extension_domain.ClientRepository) *BrowserExtensionHandler {
+	return &BrowserExtensionHandler{
+		vault:   vault,
+		clients: browser_extension_usecases.NewClientsUsecase(clients),
+	}
+}
+
+// Start serves userID's vault on addr, DefaultAddress when empty, and
+// returns the address listened on. Starting again for the same user after
+// the vault was locked unlocks the running API.
+func (h *BrowserExtensionHandler) Start(userID string, gate browser_extension_domain.FeatureGate, addr string) (string, error) {
+	if h.vault == nil {
+		return "", fmt.Errorf("browser extension vault session is not initialized")
+	}
+	if userID == "" {
+		return "", browser_extension_domain.ErrUserIDRequired
+	}
+	enabled, err := gate.BrowserExtensionEnabled(userID)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "", browser_extension_domain.ErrFeatureDisabled
+	}
+	if addr == "" {
+		addr = DefaultAddress
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return "", browser_extension_domain.ErrNotLoopback
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server != nil {
+		if h.api.UserID == userID && h.api.Locked() {
+			h.api.Unlock()
+			return h.listener.Addr().String(), nil
+		}
+		return "", browser_extension_domain.ErrAPIRunning
+	}
+
+	api := handlers.NewBrowserExtensionAPI(
+		userID,
+		browser_extension_usecases.NewPairingUsecase(h.clients.Clients, gate),
+		h.clients,
+		browser_extension_usecases.NewLookupLoginsUsecase(h.vault, gate),
+		browser_extension_usecases.NewSaveLoginUsecase(h.vault, gate),
+		browser_extension_usecases.NewGeneratePasswordUsecase(gate),
+	)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	server := &http.Server{Handler: api, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = server.Serve(l) }()
+	h.server, h.listener, h.api = server, l, api
+	return l.Addr().String(), nil
+}
+
+func (h *BrowserExtensionHandler) Stop() error {
+	h.mu.Lock()
+	server := h.server
+	h.server, h.listener, h.api = nil, nil, nil
+	h.mu.Unlock()
+	if server == nil {
+		return browser_extension_domain.ErrAPINotRunning
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+func (h *BrowserExtensionHandler) Status() APIStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server == nil {
+		return APIStatus{}
+	}
+	return APIStatus{
+		Running: true,
+		Locked:  h.api.Locked(),
+		Address: h.listener.Addr().String(),
+		UserID:  h.api.UserID,
+	}
+}
+
+// OnVaultLocked locks the API serving userID and drops its pending
+// pairing. The API keeps listening and answers 423 until started again.
+func (h *BrowserExtensionHandler) OnVaultLocked(userID string) {
+	h.mu.Lock()
+	api := h.api
+	h.mu.Unlock()
+	if api != nil && api.UserID == userID {
+		api.Lock()
+		api.Pairing.Cancel(userID)
+	}
+}
+
+// StartPairing returns a code to type into the extension. No scopes means
+// all of them.
+func (h *BrowserExtensionHandler) StartPairing(userID string, scopes []browser_extension_domain.Scope) (*browser_extension_domain.PairingCode, error) {
+	h.mu.Lock()
+	api := h.api
+	h.mu.Unlock()
+	if api == nil || api.UserID != userID {
+		return nil, browser_extension_domain.ErrAPINotRunning
+	}
+	if api.Locked() {
+		return nil, browser_extension_domain.ErrVaultLocked
+	}
+	return api.Pairing.Begin(userID, scopes)
+}
+
+func (h *BrowserExtensionHandler) ListClients(ctx context.Context, userID string) ([]browser_extension_domain.PairedClient, error) {
+	return h.clients.List(ctx, userID)
+}
+
+func (h *BrowserExtensionHandler) RevokeClient(ctx context.Context, userID string, clientID string) error {
+	return h.clients.Revoke(ctx, userID, clientID)
+}
@@ -120,6 +120,30 @@ func (s *stubThreadEventBus) PublishThreadUpdated(_ context.Context, _ thread_do
 func (s *stubThreadEventBus) SubscribeToThreadUpdated(_ func(ctx context.Context, event thread_domain.ThreadUpdated)) error {
 	return nil
 }
+func (s *stubThreadEventBus) PublishThreadClosed(_ context.Context, _ thread_domain.ThreadClosedEvent) error {
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadClosed(_ func(ctx context.Context, event thread_domain.ThreadClosedEvent)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadReopened(_ context.Context, _ thread_domain.ThreadReopened) error {
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadReopened(_ func(ctx context.Context, event thread_domain.ThreadReopened)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadTransferInitiated(_ context.Context, _ thread_domain.ThreadTransferInitiated) error {
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadTransferInitiated(_ func(ctx context.Context, event thread_domain.ThreadTransferInitiated)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadTransferCompleted(_ context.Context, _ thread_domain.ThreadTransferCompleted) error {
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadTransferCompleted(_ func(ctx context.Context, event thread_domain.ThreadTransferCompleted)) error {
+	return nil
+}
 
 // ---------------------------------------------------------------------------
 // Tests
@@ -25,6 +25,10 @@ func (u *ChannelAssignmentUsecase) AddAssignment(ctx context.Context, req channe
 		return nil, channel_domain.ErrChannelNotFound
 	}
 
+	if err := u.authorize(ctx, &channel.Data, req.ActorVaultID); err != nil {
+		return nil, err
+	}
+
 	// if err := channel.Data.AddAssignment(req.Assignment); err != nil {
 	// 	return nil, err
 	// }
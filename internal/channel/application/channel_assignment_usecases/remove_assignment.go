@@ -26,6 +26,10 @@ func (u *ChannelAssignmentUsecase) RemoveAssignment(ctx context.Context, req cha
 		return nil, channel_domain.ErrChannelNotFound
 	}
 
+	if err := u.authorize(ctx, &channel.Data, req.ActorVaultID); err != nil {
+		return nil, err
+	}
+
 	// if err := channel.Data.RemoveAssignmentBySlotID(req.AssignmentID); err != nil {
 	// 	return nil, err
 	// }
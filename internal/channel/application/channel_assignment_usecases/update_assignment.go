@@ -25,6 +25,10 @@ func (u *ChannelAssignmentUsecase) UpdateAssignment(ctx context.Context, req cha
 		return nil, channel_domain.ErrChannelNotFound
 	}
 
+	if err := u.authorize(ctx, &channel.Data, req.ActorVaultID); err != nil {
+		return nil, err
+	}
+
 	if ok := channel.Data.UpdateAssignment(req.Assignment); !ok {
 		return nil, channel_domain.ErrChannelNotModifiable
 	}
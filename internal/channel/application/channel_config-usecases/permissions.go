@@ -0,0 +1,309 @@
+package channelconfigusecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_events "vault-app/internal/channel/application/events"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// GrantChannelPermissionUsecase records a permission grant in the channel
+// policy and publishes ChannelPermissionGranted as its audit record. The
+// actor must hold channel.admin.
+type GrantChannelPermissionUsecase struct {
+	Repo      channel_domain.ChannelRepository
+	DomainBus channel_events.ChannelEventBus
+	Policy    *channel_domain.PolicyEvaluator
+	// Log, when set, receives the grant so every participant's vault sees
+	// it in the channel event log.
+	Log channel_domain.ChannelEventLog
+}
+
+// WithEventLog records grants in the channel event log.
+func (c *GrantChannelPermissionUsecase) WithEventLog(log channel_domain.ChannelEventLog) *GrantChannelPermissionUsecase {
+	c.Log = log
+	return c
+}
+
+func NewGrantChannelPermissionUsecase(repo channel_domain.ChannelRepository, channelBus channel_events.ChannelEventBus) *GrantChannelPermissionUsecase {
+	return &GrantChannelPermissionUsecase{
+		Repo:      repo,
+		DomainBus: channelBus,
+		Policy:    channel_domain.NewPolicyEvaluator(),
+	}
+}
+
+func (c *GrantChannelPermissionUsecase) Execute(ctx context.Context, req *channel_application.GrantChannelPermissionRequest) (*channel_domain.PolicyDocument, error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+
+	doc, changed, err := changePermission(ctx, c.Repo, c.DomainBus, c.Policy, req.ChannelID, req.ActorVaultID, req.VaultID, req.Permission,
+		func(p *channel_domain.PermissionPolicy) bool { return p.Grant(req.VaultID, req.Permission) })
+	if err != nil {
+		return nil, err
+	}
+
+	event := channel_domain.ChannelPermissionGranted{
+		EventID:        uuid.NewString(),
+		EventTimestamp: time.Now(),
+		ChannelID:      req.ChannelID,
+		ActorVaultID:   req.ActorVaultID,
+		VaultID:        req.VaultID,
+		Permission:     req.Permission,
+		Reason:         req.Reason,
+		PolicyRevision: doc.Revision,
+	}
+	if err := logPermissionChange(ctx, c.Log, channel_domain.ChannelEventPermissionGranted, req.ChannelID, req.ActorVaultID, req.VaultID, req.Permission, doc.Revision, event); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return doc, nil
+	}
+	if err := c.DomainBus.PublishChannelPermissionGranted(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// RevokeChannelPermissionUsecase records a revocation in the channel policy,
+// which also overrides role templates, and publishes
+// ChannelPermissionRevoked. Revoking the last channel.admin is refused.
+type RevokeChannelPermissionUsecase struct {
+	Repo      channel_domain.ChannelRepository
+	DomainBus channel_events.ChannelEventBus
+	Policy    *channel_domain.PolicyEvaluator
+	// Log, when set, receives the revocation (see
+	// GrantChannelPermissionUsecase.Log).
+	Log channel_domain.ChannelEventLog
+}
+
+// WithEventLog records revocations in the channel event log.
+func (c *RevokeChannelPermissionUsecase) WithEventLog(log channel_domain.ChannelEventLog) *RevokeChannelPermissionUsecase {
+	c.Log = log
+	return c
+}
+
+func NewRevokeChannelPermissionUsecase(repo channel_domain.ChannelRepository, channelBus channel_events.ChannelEventBus) *RevokeChannelPermissionUsecase {
+	return &RevokeChannelPermissionUsecase{
+		Repo:      repo,
+		DomainBus: channelBus,
+		Policy:    channel_domain.NewPolicyEvaluator(),
+	}
+}
+
+func (c *RevokeChannelPermissionUsecase) Execute(ctx context.Context, req *channel_application.RevokeChannelPermissionRequest) (*channel_domain.PolicyDocument, error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+
+	doc, changed, err := changePermission(ctx, c.Repo, c.DomainBus, c.Policy, req.ChannelID, req.ActorVaultID, req.VaultID, req.Permission,
+		func(p *channel_domain.PermissionPolicy) bool { return p.Revoke(req.VaultID, req.Permission) })
+	if err != nil {
+		return nil, err
+	}
+
+	event := channel_domain.ChannelPermissionRevoked{
+		EventID:        uuid.NewString(),
+		EventTimestamp: time.Now(),
+		ChannelID:      req.ChannelID,
+		ActorVaultID:   req.ActorVaultID,
+		VaultID:        req.VaultID,
+		Permission:     req.Permission,
+		Reason:         req.Reason,
+		PolicyRevision: doc.Revision,
+	}
+	if err := logPermissionChange(ctx, c.Log, channel_domain.ChannelEventPermissionRevoked, req.ChannelID, req.ActorVaultID, req.VaultID, req.Permission, doc.Revision, event); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return doc, nil
+	}
+	if err := c.DomainBus.PublishChannelPermissionRevoked(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// logPermissionChange appends a grant or revocation to the channel event
+// log. The change and the policy revision holding it key the append, so a
+// request that finds the policy already changed logs again under the same
+// key: a retry after a failed append completes the audit record, and one
+// after a successful append is deduped by the Cloud.
+func logPermissionChange(
+	ctx context.Context,
+	log channel_domain.ChannelEventLog,
+	eventType string,
+	channelID string,
+	actorVaultID string,
+	vaultID string,
+	perm string,
+	revision int,
+	event any,
+) error {
+	if log == nil {
+		return nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := log.AppendChannelEvent(ctx, &channel_domain.AppendChannelEventRequest{
+		ChannelID:      channelID,
+		Type:           eventType,
+		ActorVaultID:   actorVaultID,
+		Payload:        payload,
+		IdempotencyKey: fmt.Sprintf("permission:%s:rev%d:%s:%s:%s", channelID, revision, eventType, vaultID, perm),
+	}); err != nil {
+		return fmt.Errorf("policy revision %d saved but not logged: %w", revision, err)
+	}
+	return nil
+}
+
+// changePermission applies a grant or revocation to the stored policy. It
+// reports whether the policy changed; an unchanged policy is not written and
+// keeps its revision.
+func changePermission(
+	ctx context.Context,
+	repo channel_domain.ChannelRepository,
+	bus channel_events.ChannelEventBus,
+	policy *channel_domain.PolicyEvaluator,
+	channelID string,
+	actorVaultID string,
+	vaultID string,
+	perm string,
+	apply func(p *channel_domain.PermissionPolicy) bool,
+) (*channel_domain.PolicyDocument, bool, error) {
+	if repo == nil {
+		return nil, false, channel_domain.ErrRepositoryNil
+	}
+	if bus == nil {
+		return nil, false, channel_domain.ErrChannelBusRequired
+	}
+	if channelID == "" {
+		return nil, false, channel_domain.ErrChannelIDRequired
+	}
+	// Both vaults are named in the audit event.
+	if vaultID == "" || actorVaultID == "" {
+		return nil, false, channel_domain.ErrVaultIDRequired
+	}
+	if err := channel_domain.ValidatePermission(perm); err != nil {
+		return nil, false, err
+	}
+	if policy == nil {
+		policy = channel_domain.NewPolicyEvaluator()
+	}
+
+	resp, err := repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, false, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, false, channel_domain.ErrChannelNotFound
+	}
+	channel := resp.Data
+
+	if err := policy.RequirePermission(&channel, actorVaultID, channel_domain.PermChannelAdmin, policy.GoverningParticipants(ctx, repo, &channel)...); err != nil {
+		return nil, false, err
+	}
+
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	if err != nil {
+		return nil, false, err
+	}
+	if !apply(&doc.Permissions) {
+		return &doc, false, nil
+	}
+	if err := doc.Validate(&channel); err != nil {
+		return nil, false, err
+	}
+	doc.Revision++
+
+	stored, err := doc.ToPolicy()
+	if err != nil {
+		return nil, false, err
+	}
+	channel.SetPolicy(stored)
+
+	if _, err := repo.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel}); err != nil {
+		return nil, false, err
+	}
+
+	return &doc, true, nil
+}
+
+// GetParticipantPermissionsUsecase resolves what a vault may do in a channel.
+// Only the channel's participants may ask.
+type GetParticipantPermissionsUsecase struct {
+	Repo   channel_domain.ChannelRepository
+	Policy *channel_domain.PolicyEvaluator
+}
+
+func NewGetParticipantPermissionsUsecase(repo channel_domain.ChannelRepository) *GetParticipantPermissionsUsecase {
+	return &GetParticipantPermissionsUsecase{
+		Repo:   repo,
+		Policy: channel_domain.NewPolicyEvaluator(),
+	}
+}
+
+func (c *GetParticipantPermissionsUsecase) Execute(ctx context.Context, req *channel_application.GetParticipantPermissionsRequest) (*channel_application.ParticipantPermissions, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+	if req.VaultID == "" || req.ActorVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	resp, err := c.Repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+	channel := resp.Data
+
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := channel_domain.ChannelParticipants(ctx, c.Repo, &channel)
+	policy := c.Policy
+	if policy == nil {
+		policy = channel_domain.NewPolicyEvaluator()
+	}
+	if err := policy.RequireParticipant(&channel, req.ActorVaultID, participants...); err != nil {
+		return nil, err
+	}
+
+	roles := channel.RolesOf(req.VaultID)
+	for _, p := range participants {
+		if p.VaultID == req.VaultID && p.Role != "" && !slices.Contains(roles, p.Role) {
+			roles = append(roles, p.Role)
+		}
+	}
+
+	return &channel_application.ParticipantPermissions{
+		ChannelID:   channel.ID,
+		VaultID:     req.VaultID,
+		Roles:       roles,
+		Permissions: channel.EffectivePermissions(doc.Permissions, req.VaultID, participants...),
+		Enforced:    doc.Permissions.Enforced(),
+	}, nil
+}
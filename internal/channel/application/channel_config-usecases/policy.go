@@ -1 +1,132 @@
-package channelconfigusecases
\ No newline at end of file
+package channelconfigusecases
+
+import (
+	"context"
+	"maps"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// GetChannelPolicyUsecase returns the typed policy of a channel. Policies
+// stored before the schema existed are returned migrated.
+type GetChannelPolicyUsecase struct {
+	Repo channel_domain.ChannelRepository
+}
+
+func NewGetChannelPolicyUsecase(repo channel_domain.ChannelRepository) *GetChannelPolicyUsecase {
+	return &GetChannelPolicyUsecase{
+		Repo: repo,
+	}
+}
+
+func (c *GetChannelPolicyUsecase) Execute(ctx context.Context, req *channel_application.GetChannelPolicyRequest) (*channel_domain.PolicyDocument, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+
+	resp, err := c.Repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	doc, err := channel_domain.ParsePolicy(resp.Data.Policy)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// UpdateChannelPolicyUsecase validates a policy document against the channel
+// slots and stores it with optimistic concurrency on the revision. Extension
+// keys of the stored policy are kept unless the update sets them.
+type UpdateChannelPolicyUsecase struct {
+	Repo channel_domain.ChannelRepository
+	// Policy, when set, requires the actor to hold channel.admin under the
+	// stored policy.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *UpdateChannelPolicyUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *UpdateChannelPolicyUsecase {
+	c.Policy = policy
+	return c
+}
+
+func NewUpdateChannelPolicyUsecase(repo channel_domain.ChannelRepository) *UpdateChannelPolicyUsecase {
+	return &UpdateChannelPolicyUsecase{
+		Repo: repo,
+	}
+}
+
+func (c *UpdateChannelPolicyUsecase) Execute(ctx context.Context, req *channel_application.UpdateChannelPolicyRequest) (*channel_domain.PolicyDocument, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+
+	resp, err := c.Repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+	channel := resp.Data
+
+	current, err := channel_domain.ParsePolicy(channel.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if current.Revision != req.ExpectedRevision {
+		return nil, channel_domain.ErrPolicyRevisionConflict
+	}
+	if c.Policy != nil {
+		if err := c.Policy.RequirePermission(&channel, req.ActorVaultID, channel_domain.PermChannelAdmin, c.Policy.GoverningParticipants(ctx, c.Repo, &channel)...); err != nil {
+			return nil, err
+		}
+	}
+
+	doc := req.Policy
+	if doc.Schema == "" {
+		doc.Schema = channel_domain.PolicySchema
+	}
+	if doc.Version == 0 {
+		doc.Version = channel_domain.PolicySchemaVersion
+	}
+	if err := doc.Validate(&channel); err != nil {
+		return nil, err
+	}
+	doc.Revision = current.Revision + 1
+	if len(current.Extensions) > 0 {
+		extensions := maps.Clone(current.Extensions)
+		maps.Copy(extensions, doc.Extensions)
+		doc.Extensions = extensions
+	}
+
+	policy, err := doc.ToPolicy()
+	if err != nil {
+		return nil, err
+	}
+	channel.SetPolicy(policy)
+
+	if _, err := c.Repo.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel}); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
@@ -9,6 +9,14 @@ import (
 
 type ActivateChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *ActivateChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *ActivateChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewActivateChannelUsecase(repo channel_domain.ChannelRepository) *ActivateChannelUsecase {
@@ -29,6 +37,10 @@ func (c *ActivateChannelUsecase) Execute(ctx context.Context, req *channel_appli
 		return nil, err
 	}
 
+	if err := requirePermission(ctx, c.Repo, c.Policy, req.ChannelID, req.ActorVaultID, channel_domain.PermChannelAdmin); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.Repo.ActivateChannel(ctx, &channel_domain.ActivateChannelRequest{
 		ChannelID: req.ChannelID,
 	})
@@ -14,6 +14,14 @@ import (
 type ArchiveChannelUsecase struct {
 	Repo      channel_domain.ChannelRepository
 	DomainBus channel_events.ChannelEventBus
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *ArchiveChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *ArchiveChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewArchiveChannelUsecase(repo channel_domain.ChannelRepository, channelBus channel_events.ChannelEventBus) *ArchiveChannelUsecase {
@@ -45,6 +53,11 @@ func (c *ArchiveChannelUsecase) Execute(ctx context.Context, req *channel_applic
 
 	// 2. Apply domain behavior — aggregate enforces invariants
 	channel := getResp.Data
+	if c.Policy != nil {
+		if err := c.Policy.RequirePermission(&channel, req.ActorVaultID, channel_domain.PermChannelAdmin, c.Policy.GoverningParticipants(ctx, c.Repo, &channel)...); err != nil {
+			return err
+		}
+	}
 	if err := channel.Archive(); err != nil {
 		return err
 	}
@@ -104,5 +104,10 @@ func (c *CreateChannelUsecase) ValidateRequest(req *channel_application.CreateCh
 	if req.WorkspaceID == "" {
 		return channel_domain.ErrChannelNameRequired
 	}
+
+	if err := validatePolicy(req.Policy, req.Slots); err != nil {
+		return err
+	}
+
 	return nil
 }
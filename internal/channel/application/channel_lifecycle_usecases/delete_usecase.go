@@ -9,6 +9,14 @@ import (
 
 type DeleteChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *DeleteChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *DeleteChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewDeleteChannelUsecase(repo channel_domain.ChannelRepository) *DeleteChannelUsecase {
@@ -26,6 +34,10 @@ func (c *DeleteChannelUsecase) Execute(ctx context.Context, req *channel_applica
 		return err
 	}
 
+	if err := requirePermission(ctx, c.Repo, c.Policy, req.ChannelID, req.ActorVaultID, channel_domain.PermChannelAdmin); err != nil {
+		return err
+	}
+
 	err := c.Repo.DeleteChannel(ctx, &channel_domain.DeleteChannelRequest{
 		ChannelID: req.ChannelID,
 	})
@@ -9,6 +9,15 @@ import (
 
 type GetChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, shows channels that enforce permissions to their
+	// participants only.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the participant check.
+func (c *GetChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *GetChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewGetChannelUsecase(repo channel_domain.ChannelRepository) *GetChannelUsecase {
@@ -32,6 +41,16 @@ func (c *GetChannelUsecase) Execute(ctx context.Context, req *channel_applicatio
 	if err != nil {
 		return nil, err
 	}
+	if channel == nil || channel.Data.ID == "" {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+
+	if c.Policy != nil {
+		participants := c.Policy.GoverningParticipants(ctx, c.Repo, &channel.Data)
+		if err := c.Policy.RequireParticipant(&channel.Data, req.ActorVaultID, participants...); err != nil {
+			return nil, err
+		}
+	}
 
 	return &channel.Data, nil
 }
@@ -0,0 +1,225 @@
+package channel_usecase
+
+import (
+	"context"
+	"time"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func invitationClock(now func() time.Time) time.Time {
+	if now != nil {
+		return now()
+	}
+	return time.Now().UTC()
+}
+
+// RejectChannelInvitationUsecase declines a pending invitation through the
+// authoritative Cloud backend. Cloud checks that the rejecting vault is the
+// invitee and that the invitation is still pending.
+type RejectChannelInvitationUsecase struct {
+	Repo channel_domain.ChannelRepository
+}
+
+func NewRejectChannelInvitationUsecase(repo channel_domain.ChannelRepository) *RejectChannelInvitationUsecase {
+	return &RejectChannelInvitationUsecase{
+		Repo: repo,
+	}
+}
+
+func (c *RejectChannelInvitationUsecase) Execute(ctx context.Context, req *channel_application.RejectChannelInvitationRequest) (*channel_domain.Invitation, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InvitationID == "" {
+		return nil, channel_domain.ErrInvitationIDRequired
+	}
+	if req.InviteeVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	resp, err := c.Repo.RejectChannelInvitation(ctx, &channel_domain.RejectInvitationRequest{
+		InvitationID:   req.InvitationID,
+		InviteeVaultID: req.InviteeVaultID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	return &resp.Data, nil
+}
+
+// RevokeChannelInvitationUsecase withdraws a pending invitation through the
+// authoritative Cloud backend. Cloud checks that the revoking vault is the
+// inviter and that the invitation is still pending.
+type RevokeChannelInvitationUsecase struct {
+	Repo channel_domain.ChannelRepository
+}
+
+func NewRevokeChannelInvitationUsecase(repo channel_domain.ChannelRepository) *RevokeChannelInvitationUsecase {
+	return &RevokeChannelInvitationUsecase{
+		Repo: repo,
+	}
+}
+
+func (c *RevokeChannelInvitationUsecase) Execute(ctx context.Context, req *channel_application.RevokeChannelInvitationRequest) (*channel_domain.Invitation, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InvitationID == "" {
+		return nil, channel_domain.ErrInvitationIDRequired
+	}
+	if req.InviterVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	resp, err := c.Repo.RevokeChannelInvitation(ctx, &channel_domain.RevokeInvitationRequest{
+		InvitationID:   req.InvitationID,
+		InviterVaultID: req.InviterVaultID,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	return &resp.Data, nil
+}
+
+// ListChannelInvitationsUsecase lists the invitations a vault received or
+// sent. Pending invitations past their expiry are reported as expired, so the
+// UI never offers to accept one the Cloud would refuse.
+type ListChannelInvitationsUsecase struct {
+	Repo channel_domain.ChannelRepository
+	// TTL applies to invitations the Cloud recorded without an expiry.
+	TTL time.Duration
+	Now func() time.Time
+}
+
+func NewListChannelInvitationsUsecase(repo channel_domain.ChannelRepository) *ListChannelInvitationsUsecase {
+	return &ListChannelInvitationsUsecase{
+		Repo: repo,
+	}
+}
+
+// WithTTL sets the expiry applied to invitations without one.
+func (c *ListChannelInvitationsUsecase) WithTTL(ttl time.Duration) *ListChannelInvitationsUsecase {
+	c.TTL = ttl
+	return c
+}
+
+func (c *ListChannelInvitationsUsecase) Execute(ctx context.Context, req *channel_application.ListChannelInvitationsRequest) ([]channel_domain.Invitation, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.VaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+	if req.Direction != channel_domain.InvitationsReceived && req.Direction != channel_domain.InvitationsSent {
+		return nil, channel_domain.ErrInvitationDirection
+	}
+
+	listReq := &channel_domain.ListInvitationsRequest{
+		VaultID:   req.VaultID,
+		Direction: req.Direction,
+		ChannelID: req.ChannelID,
+	}
+	if req.PendingOnly {
+		listReq.Status = channel_domain.InvitationStatusPending
+	}
+
+	resp, err := c.Repo.ListChannelInvitations(ctx, listReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	now := invitationClock(c.Now)
+	invitations := make([]channel_domain.Invitation, 0, len(resp.Data))
+	for _, inv := range resp.Data {
+		inv.Status = inv.EffectiveStatus(now, c.TTL)
+		if req.PendingOnly && inv.Status != channel_domain.InvitationStatusPending {
+			continue
+		}
+		invitations = append(invitations, inv)
+	}
+
+	return invitations, nil
+}
+
+// ExpireChannelInvitationsUsecase records the expiry of the pending
+// invitations a vault sent that are past it, so the Cloud state converges
+// with what the Desktop already shows. It returns the expired invitations.
+type ExpireChannelInvitationsUsecase struct {
+	Repo channel_domain.ChannelRepository
+	TTL  time.Duration
+	Now  func() time.Time
+}
+
+func NewExpireChannelInvitationsUsecase(repo channel_domain.ChannelRepository, ttl time.Duration) *ExpireChannelInvitationsUsecase {
+	return &ExpireChannelInvitationsUsecase{
+		Repo: repo,
+		TTL:  ttl,
+	}
+}
+
+func (c *ExpireChannelInvitationsUsecase) Execute(ctx context.Context, req *channel_application.ExpireChannelInvitationsRequest) ([]channel_domain.Invitation, error) {
+	if c.Repo == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InviterVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	resp, err := c.Repo.ListChannelInvitations(ctx, &channel_domain.ListInvitationsRequest{
+		VaultID:   req.InviterVaultID,
+		Direction: channel_domain.InvitationsSent,
+		Status:    channel_domain.InvitationStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	now := invitationClock(c.Now)
+	expired := make([]channel_domain.Invitation, 0)
+	for _, inv := range resp.Data {
+		if !inv.IsExpired(now, c.TTL) {
+			continue
+		}
+		resp, err := c.Repo.ExpireChannelInvitation(ctx, &channel_domain.ExpireInvitationRequest{
+			InvitationID:   inv.ID,
+			InviterVaultID: req.InviterVaultID,
+		})
+		if err != nil {
+			return expired, err
+		}
+		if resp != nil {
+			expired = append(expired, resp.Data)
+		}
+	}
+
+	return expired, nil
+}
@@ -2,6 +2,7 @@ package channel_usecase
 
 import (
 	"context"
+	"time"
 
 	channel_application "vault-app/internal/channel/application"
 	channel_domain "vault-app/internal/channel/domain"
@@ -13,6 +14,25 @@ import (
 // invitation locally.
 type InviteToChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, checks the inviter's invite rights (invite roles and
+	// participant.invite) before the Cloud is called.
+	Policy *channel_domain.PolicyEvaluator
+	// TTL, when positive, sets the invitation expiry sent to the Cloud.
+	TTL time.Duration
+	// Now defaults to the wall clock.
+	Now func() time.Time
+}
+
+// WithTTL makes new invitations expire after ttl.
+func (c *InviteToChannelUsecase) WithTTL(ttl time.Duration) *InviteToChannelUsecase {
+	c.TTL = ttl
+	return c
+}
+
+// WithPolicy enables the channel policy check.
+func (c *InviteToChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *InviteToChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewInviteToChannelUsecase(repo channel_domain.ChannelRepository) *InviteToChannelUsecase {
@@ -30,10 +50,31 @@ func (c *InviteToChannelUsecase) Execute(ctx context.Context, req *channel_appli
 		return nil, err
 	}
 
+	if c.Policy != nil {
+		channel, err := loadGovernedChannel(ctx, c.Repo, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Policy.CanInvite(channel, req.InviterVaultID); err != nil {
+			return nil, err
+		}
+		participants := c.Policy.GoverningParticipants(ctx, c.Repo, channel)
+		if err := c.Policy.RequirePermission(channel, req.InviterVaultID, channel_domain.PermParticipantInvite, participants...); err != nil {
+			return nil, err
+		}
+	}
+
+	var expiresAt *time.Time
+	if c.TTL > 0 {
+		at := invitationClock(c.Now).Add(c.TTL)
+		expiresAt = &at
+	}
+
 	resp, err := c.Repo.InviteToChannel(ctx, &channel_domain.InviteToChannelRequest{
 		ChannelID:      req.ChannelID,
 		InviterVaultID: req.InviterVaultID,
 		InviteeVaultID: req.InviteeVaultID,
+		ExpiresAt:      expiresAt,
 	})
 	if err != nil {
 		return nil, err
@@ -81,6 +122,25 @@ func (c *InviteToChannelUsecase) ValidateRequest(req *channel_application.Invite
 // returns the accepted invitation without a duplicate participant.
 type AcceptChannelInvitationUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, rejects invitations to expired channels and
+	// invitations whose inviter no longer may invite.
+	Policy *channel_domain.PolicyEvaluator
+	// TTL expires pending invitations the Cloud recorded no expiry for.
+	TTL time.Duration
+	Now func() time.Time
+}
+
+// WithTTL rejects pending invitations older than ttl. Invitations carrying
+// their own expiry are rejected past it once WithTTL or WithPolicy is set.
+func (c *AcceptChannelInvitationUsecase) WithTTL(ttl time.Duration) *AcceptChannelInvitationUsecase {
+	c.TTL = ttl
+	return c
+}
+
+// WithPolicy enables the channel policy check.
+func (c *AcceptChannelInvitationUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *AcceptChannelInvitationUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewAcceptChannelInvitationUsecase(repo channel_domain.ChannelRepository) *AcceptChannelInvitationUsecase {
@@ -98,6 +158,12 @@ func (c *AcceptChannelInvitationUsecase) Execute(ctx context.Context, req *chann
 		return nil, err
 	}
 
+	if c.Policy != nil || c.TTL > 0 {
+		if err := c.checkInvitation(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
 	resp, err := c.Repo.AcceptChannelInvitation(ctx, &channel_domain.AcceptInvitationRequest{
 		InvitationID:     req.InvitationID,
 		InviteeVaultID:   req.InviteeVaultID,
@@ -113,6 +179,57 @@ func (c *AcceptChannelInvitationUsecase) Execute(ctx context.Context, req *chann
 	return &resp.Data, nil
 }
 
+// checkInvitation rejects a pending invitation past its expiry and re-checks
+// it against the channel policy as it stands now: the inviter must still be
+// allowed to invite. Settled invitations are left to the Cloud, which answers
+// a repeated accept with the accepted invitation.
+func (c *AcceptChannelInvitationUsecase) checkInvitation(ctx context.Context, req *channel_application.AcceptChannelInvitationRequest) error {
+	inv, err := findReceivedInvitation(ctx, c.Repo, req.InviteeVaultID, req.InvitationID)
+	if err != nil {
+		return err
+	}
+	if inv.Status != channel_domain.InvitationStatusPending {
+		return nil
+	}
+	if inv.IsExpired(invitationClock(c.Now), c.TTL) {
+		return channel_domain.ErrInvitationExpired
+	}
+	if c.Policy == nil {
+		return nil
+	}
+
+	channel, err := loadGovernedChannel(ctx, c.Repo, inv.ChannelID)
+	if err != nil {
+		return err
+	}
+	if err := c.Policy.CanInvite(channel, inv.InviterVaultID); err != nil {
+		return err
+	}
+	return c.Policy.RequirePermission(channel, inv.InviterVaultID, channel_domain.PermParticipantInvite, c.Policy.GoverningParticipants(ctx, c.Repo, channel)...)
+}
+
+// findReceivedInvitation looks up an invitation among the ones the invitee
+// received.
+func findReceivedInvitation(ctx context.Context, repo channel_domain.ChannelRepository, inviteeVaultID string, invitationID string) (*channel_domain.Invitation, error) {
+	resp, err := repo.ListChannelInvitations(ctx, &channel_domain.ListInvitationsRequest{
+		VaultID:   inviteeVaultID,
+		Direction: channel_domain.InvitationsReceived,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	for i := range resp.Data {
+		if resp.Data[i].ID == invitationID {
+			return &resp.Data[i], nil
+		}
+	}
+	return nil, channel_domain.ErrInvitationNotFound
+}
+
 func (c *AcceptChannelInvitationUsecase) ValidateDependencies() error {
 	if c.Repo == nil {
 		return channel_domain.ErrRepositoryNil
@@ -17,6 +17,7 @@ func NewListChannelUsecase(repo channel_domain.ChannelRepository) *ListChannelUs
 	}
 }
 
+// Execute lists the workspace channels the actor vault takes part in.
 func (c *ListChannelUsecase) Execute(ctx context.Context, req *channel_application.ListChannelsRequest) ([]channel_domain.Channel, error) {
 	if err := c.ValidateDependencies(); err != nil {
 		return nil, err
@@ -33,7 +34,15 @@ func (c *ListChannelUsecase) Execute(ctx context.Context, req *channel_applicati
 		return nil, err
 	}
 
-	return collection.Data, nil
+	channels := make([]channel_domain.Channel, 0, len(collection.Data))
+	for _, ch := range collection.Data {
+		if ch.IsParticipant(req.ActorVaultID) ||
+			ch.IsParticipant(req.ActorVaultID, channel_domain.ChannelParticipants(ctx, c.Repo, &ch)...) {
+			channels = append(channels, ch)
+		}
+	}
+
+	return channels, nil
 }
 
 func (c *ListChannelUsecase) ValidateDependencies() error {
@@ -53,5 +62,9 @@ func (c *ListChannelUsecase) ValidateRequest(req *channel_application.ListChanne
 		return channel_domain.ErrWorkspaceIDRequired
 	}
 
+	if req.ActorVaultID == "" {
+		return channel_domain.ErrVaultIDRequired
+	}
+
 	return nil
 }
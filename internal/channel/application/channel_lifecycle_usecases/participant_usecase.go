@@ -13,6 +13,15 @@ import (
 // locally whether a vault may join.
 type AddParticipantUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, rejects direct joins on channels that require an
+	// invitation and on expired channels.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel policy check.
+func (c *AddParticipantUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *AddParticipantUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewAddParticipantUsecase(repo channel_domain.ChannelRepository) *AddParticipantUsecase {
@@ -30,6 +39,16 @@ func (c *AddParticipantUsecase) Execute(ctx context.Context, req *channel_applic
 		return nil, err
 	}
 
+	if c.Policy != nil {
+		channel, err := loadGovernedChannel(ctx, c.Repo, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Policy.CanJoin(channel); err != nil {
+			return nil, err
+		}
+	}
+
 	resp, err := c.Repo.AddParticipant(ctx, &channel_domain.JoinChannelRequest{
 		ChannelID: req.ChannelID,
 		VaultID:   req.VaultID,
@@ -77,6 +96,15 @@ func (c *AddParticipantUsecase) ValidateRequest(req *channel_application.AddPart
 // returns is surfaced to the caller.
 type ListParticipantsUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, restricts the listing to the channel's participants
+	// on channels that enforce permissions.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the participant check.
+func (c *ListParticipantsUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *ListParticipantsUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewListParticipantsUsecase(repo channel_domain.ChannelRepository) *ListParticipantsUsecase {
@@ -100,6 +128,19 @@ func (c *ListParticipantsUsecase) Execute(ctx context.Context, req *channel_appl
 	if err != nil {
 		return nil, err
 	}
+	if collection == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+
+	if c.Policy != nil {
+		channel, err := loadGovernedChannel(ctx, c.Repo, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Policy.RequireParticipant(channel, req.ActorVaultID, collection.Data...); err != nil {
+			return nil, err
+		}
+	}
 
 	return collection.Data, nil
 }
@@ -0,0 +1,60 @@
+package channel_usecase
+
+import (
+	"context"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// loadGovernedChannel fetches the channel whose policy governs an action.
+func loadGovernedChannel(ctx context.Context, repo channel_domain.ChannelRepository, channelID string) (*channel_domain.Channel, error) {
+	resp, err := repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+	return &resp.Data, nil
+}
+
+// validatePolicy checks a policy map carried by a create or update request.
+// Roles are checked against the request slots when there are any.
+func validatePolicy(policy channel_domain.Policy, slots []channel_domain.Slot) error {
+	if len(policy) == 0 {
+		return nil
+	}
+
+	doc, err := channel_domain.ParsePolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	var channel *channel_domain.Channel
+	if len(slots) > 0 {
+		channel = &channel_domain.Channel{Slots: slots}
+	}
+	return doc.Validate(channel)
+}
+
+// requirePermission checks the actor holds perm on the channel. It passes
+// without an evaluator, so use cases built without WithPolicy keep their
+// behaviour.
+func requirePermission(
+	ctx context.Context,
+	repo channel_domain.ChannelRepository,
+	policy *channel_domain.PolicyEvaluator,
+	channelID string,
+	actorVaultID string,
+	perm string,
+) error {
+	if policy == nil {
+		return nil
+	}
+
+	channel, err := loadGovernedChannel(ctx, repo, channelID)
+	if err != nil {
+		return err
+	}
+	return policy.RequirePermission(channel, actorVaultID, perm, policy.GoverningParticipants(ctx, repo, channel)...)
+}
@@ -9,6 +9,14 @@ import (
 
 type RevokeChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *RevokeChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *RevokeChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewRevokeChannelUsecase(repo channel_domain.ChannelRepository) *RevokeChannelUsecase {
@@ -32,6 +40,10 @@ func (c *RevokeChannelUsecase) Execute(ctx context.Context, req *channel_applica
 		return err
 	}
 
+	if err := requirePermission(ctx, c.Repo, c.Policy, req.ChannelID, req.ActorVaultID, channel_domain.PermChannelAdmin); err != nil {
+		return err
+	}
+
 	return c.Repo.RevokeChannel(ctx, &channel_domain.RevokeChannelRequest{
 		ChannelID: req.ChannelID,
 	})
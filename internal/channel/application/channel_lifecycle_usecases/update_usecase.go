@@ -9,6 +9,14 @@ import (
 
 type UpdateChannelUsecase struct {
 	Repo channel_domain.ChannelRepository
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel permission check.
+func (c *UpdateChannelUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *UpdateChannelUsecase {
+	c.Policy = policy
+	return c
 }
 
 func NewUpdateChannelUsecase(repo channel_domain.ChannelRepository) *UpdateChannelUsecase {
@@ -31,6 +39,10 @@ func (c *UpdateChannelUsecase) Execute(ctx context.Context, req *channel_applica
 		return nil, err
 	}
 
+	if err := requirePermission(ctx, c.Repo, c.Policy, req.ChannelID, req.ActorVaultID, channel_domain.PermChannelAdmin); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.Repo.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{
 		Channel: channel_domain.Channel{
 			ID:          req.ChannelID,
@@ -68,5 +80,9 @@ func (c *UpdateChannelUsecase) ValidateRequest(req *channel_application.UpdateCh
 		return channel_domain.ErrChannelIDRequired
 	}
 
+	if err := validatePolicy(req.Policy, req.Slots); err != nil {
+		return err
+	}
+
 	return nil
 }
\ No newline at end of file
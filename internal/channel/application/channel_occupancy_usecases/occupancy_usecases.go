@@ -0,0 +1,419 @@
+package channel_occupancy_usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_events "vault-app/internal/channel/application/events"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// SlotOccupancyService runs the gated slot workflow: a vault requests a
+// slot, the eligible approvers sign their votes, and the Assignment is only
+// written once the policy's N-of-M approvals are in. Votes are serialised so
+// two approvals arriving together cannot both fill the slot.
+type SlotOccupancyService struct {
+	Repo      channel_domain.ChannelRepository
+	Requests  channel_domain.OccupancyRequestRepository
+	DomainBus channel_events.ChannelEventBus
+	Keys      KeyVerifier
+	Policy    *channel_domain.PolicyEvaluator
+	Notifier  ApproverNotifier
+	Now       func() time.Time
+
+	mu sync.Mutex
+}
+
+func NewSlotOccupancyService(
+	repo channel_domain.ChannelRepository,
+	requests channel_domain.OccupancyRequestRepository,
+	bus channel_events.ChannelEventBus,
+	keys KeyVerifier,
+) *SlotOccupancyService {
+	return &SlotOccupancyService{
+		Repo:      repo,
+		Requests:  requests,
+		DomainBus: bus,
+		Keys:      keys,
+	}
+}
+
+// WithPolicy checks the channel is not expired and that requesting a slot
+// for another vault is allowed (participant.invite).
+func (s *SlotOccupancyService) WithPolicy(policy *channel_domain.PolicyEvaluator) *SlotOccupancyService {
+	s.Policy = policy
+	return s
+}
+
+// WithNotifier sets who tells pending approvers about new requests.
+func (s *SlotOccupancyService) WithNotifier(notifier ApproverNotifier) *SlotOccupancyService {
+	s.Notifier = notifier
+	return s
+}
+
+func (s *SlotOccupancyService) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now().UTC()
+}
+
+func (s *SlotOccupancyService) ready() error {
+	if s.Repo == nil || s.Requests == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+	return nil
+}
+
+func (s *SlotOccupancyService) loadChannel(ctx context.Context, channelID string) (*channel_domain.Channel, error) {
+	if channelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+	resp, err := s.Repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+	channel := resp.Data
+	return &channel, nil
+}
+
+// -------- REQUEST --------
+
+// Request opens an occupancy request for a gated slot and notifies the
+// approvers. A slot has at most one pending request at a time.
+func (s *SlotOccupancyService) Request(ctx context.Context, req *channel_application.RequestSlotOccupancyRequest) (*channel_domain.OccupancyRequest, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+
+	candidate := req.Candidate
+	if candidate.OwnerID == "" {
+		candidate.OwnerID = req.ActorVaultID
+	}
+	candidate.SlotID = req.SlotID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, err := s.loadChannel(ctx, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Status == channel_domain.StatusRevoked || channel.Status == channel_domain.StatusArchived {
+		return nil, channel_domain.ErrChannelNotModifiable
+	}
+	if s.Policy != nil {
+		if err := s.Policy.CheckActive(channel); err != nil {
+			return nil, err
+		}
+		if candidate.OwnerID != req.ActorVaultID {
+			if err := s.Policy.RequirePermission(channel, req.ActorVaultID, channel_domain.PermParticipantInvite); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	existing, err := s.Requests.ListOccupancyRequests(ctx, channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(existing, func(r channel_domain.OccupancyRequest) bool {
+		return r.SlotID == req.SlotID && r.Status == channel_domain.OccupancyPending
+	}) {
+		return nil, channel_domain.ErrOccupancyRequestPending
+	}
+
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	if err != nil {
+		return nil, err
+	}
+	request, err := channel_domain.NewOccupancyRequest(channel, doc, uuid.NewString(), req.SlotID, candidate, req.ActorVaultID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Requests.SaveOccupancyRequest(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to save occupancy request: %w", err)
+	}
+
+	if s.DomainBus != nil {
+		_ = s.DomainBus.PublishSlotOccupancyRequested(ctx, channel_domain.SlotOccupancyRequested{
+			EventID:          uuid.NewString(),
+			EventTimestamp:   request.RequestedAt,
+			ChannelID:        request.ChannelID,
+			RequestID:        request.ID,
+			SlotID:           request.SlotID,
+			CandidateVaultID: request.CandidateVaultID,
+			RequestedBy:      request.RequestedBy,
+			Required:         request.Required,
+			Approvers:        request.Approvers,
+		})
+	}
+	if s.Notifier != nil {
+		_ = s.Notifier.NotifyApprovers(ctx, request, request.PendingApprovers())
+	}
+
+	return &request, nil
+}
+
+// -------- VOTE --------
+
+// SignVote builds the actor's vote on a request and signs it.
+func (s *SlotOccupancyService) SignVote(signer VoteSigner, request channel_domain.OccupancyRequest, req *channel_application.VoteSlotOccupancyRequest) (channel_domain.OccupancyVote, error) {
+	if signer == nil {
+		return channel_domain.OccupancyVote{}, errors.New("vote signer is nil")
+	}
+
+	vote := channel_domain.OccupancyVote{
+		RequestID: request.ID,
+		VaultID:   req.ActorVaultID,
+		Decision:  req.Decision,
+		Reason:    req.Reason,
+		PublicKey: signer.PublicKey(),
+		VotedAt:   s.now(),
+	}
+	message, err := vote.SigningBytes(request)
+	if err != nil {
+		return channel_domain.OccupancyVote{}, err
+	}
+	sig, err := signer.Sign(message)
+	if err != nil {
+		return channel_domain.OccupancyVote{}, fmt.Errorf("sign occupancy vote failed: %w", err)
+	}
+	vote.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	return vote, nil
+}
+
+// Vote signs the actor's decision and submits it.
+func (s *SlotOccupancyService) Vote(ctx context.Context, signer VoteSigner, req *channel_application.VoteSlotOccupancyRequest) (*channel_domain.OccupancyRequest, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+
+	request, err := s.Requests.GetOccupancyRequest(ctx, req.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	vote, err := s.SignVote(signer, *request, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.SubmitVote(ctx, vote)
+}
+
+// SubmitVote verifies a signed vote and records it. The vote must be signed
+// with the key known for the approver (see verify). The vote is logged
+// before anything acts on it; once it reaches the required approvals the
+// Assignment is written. If that write fails, submitting the same vote
+// again logs nothing new and retries the Assignment.
+func (s *SlotOccupancyService) SubmitVote(ctx context.Context, vote channel_domain.OccupancyVote) (*channel_domain.OccupancyRequest, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if s.Keys == nil {
+		return nil, errors.New("occupancy key verifier is nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request, err := s.Requests.GetOccupancyRequest(ctx, vote.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := s.loadChannel(ctx, request.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verify(ctx, channel, *request, vote); err != nil {
+		return nil, err
+	}
+	if a, filled := channel.GetAssignmentBySlotID(request.SlotID); filled && a.OwnerID != request.CandidateVaultID {
+		return nil, channel_domain.ErrSlotAlreadyFilled
+	}
+
+	now := s.now()
+	retry := slices.ContainsFunc(request.Votes, func(v channel_domain.OccupancyVote) bool {
+		return v.VaultID == vote.VaultID && v.Signature == vote.Signature
+	})
+	if !retry {
+		if err := request.Vote(vote, now); err != nil {
+			return nil, err
+		}
+		if err := s.Requests.SaveOccupancyRequest(ctx, *request); err != nil {
+			return nil, fmt.Errorf("failed to save occupancy request: %w", err)
+		}
+		if s.DomainBus != nil {
+			_ = s.DomainBus.PublishSlotOccupancyVoted(ctx, channel_domain.SlotOccupancyVoted{
+				EventID:          uuid.NewString(),
+				EventTimestamp:   now,
+				ChannelID:        request.ChannelID,
+				Vote:             vote,
+				Status:           request.Status,
+				Approvals:        request.Approvals(),
+				Required:         request.Required,
+				PendingApprovers: request.PendingApprovers(),
+			})
+		}
+	}
+
+	if request.Status != channel_domain.OccupancyApproved {
+		return request, nil
+	}
+	if _, assigned := channel.GetAssignmentBySlotID(request.SlotID); assigned {
+		return request, nil
+	}
+	channel.AddAssignment(request.Assignment())
+	if _, err := s.Repo.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: *channel}); err != nil {
+		return nil, fmt.Errorf("vote recorded but the slot was not assigned: %w", err)
+	}
+	if s.DomainBus != nil {
+		_ = s.DomainBus.PublishSlotFilled(ctx, channel_domain.SlotFilled{
+			EventID:        uuid.NewString(),
+			EventTimestamp: now,
+			ChannelID:      request.ChannelID,
+			RequestID:      request.ID,
+			Assignment:     request.Assignment(),
+		})
+	}
+
+	return request, nil
+}
+
+// VerifyOccupancyVote lets the occupancy log check the votes it replays the
+// way SubmitVote checked them.
+func (s *SlotOccupancyService) VerifyOccupancyVote(ctx context.Context, channel *channel_domain.Channel, request channel_domain.OccupancyRequest, vote channel_domain.OccupancyVote) error {
+	if s.Keys == nil {
+		return errors.New("occupancy key verifier is nil")
+	}
+	return s.verify(ctx, channel, request, vote)
+}
+
+// verify checks the vote against the key the Desktop trusts for the voter,
+// never the one the vote declares: the key the voter holds a slot with, or
+// else the key the Cloud recorded for it as a participant. A voter with no
+// known key cannot vote.
+func (s *SlotOccupancyService) verify(ctx context.Context, channel *channel_domain.Channel, request channel_domain.OccupancyRequest, vote channel_domain.OccupancyVote) error {
+	key, err := s.voterKey(ctx, channel, vote.VaultID)
+	if err != nil {
+		return err
+	}
+	if vote.PublicKey != key {
+		return fmt.Errorf("%w: vote is not signed with the key known for vault %s", channel_domain.ErrOccupancySignature, vote.VaultID)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(vote.Signature)
+	if err != nil || len(sig) == 0 {
+		return channel_domain.ErrOccupancySignature
+	}
+	message, err := vote.SigningBytes(request)
+	if err != nil {
+		return err
+	}
+	if err := s.Keys.Verify(key, message, sig); err != nil {
+		return fmt.Errorf("%w: %v", channel_domain.ErrOccupancySignature, err)
+	}
+	return nil
+}
+
+func (s *SlotOccupancyService) voterKey(ctx context.Context, channel *channel_domain.Channel, vaultID string) (string, error) {
+	key := ""
+	for _, a := range channel.GetAssignmentsByOwnerID(vaultID) {
+		if a.PublicKey == "" {
+			continue
+		}
+		if key != "" && key != a.PublicKey {
+			return "", fmt.Errorf("%w: vault %s holds its slots with different keys", channel_domain.ErrOccupancySignature, vaultID)
+		}
+		key = a.PublicKey
+	}
+	if key != "" {
+		return key, nil
+	}
+
+	resp, err := s.Repo.ListParticipants(ctx, &channel_domain.ListParticipantsRequest{ChannelID: channel.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the key of vault %s: %w", vaultID, err)
+	}
+	if resp != nil {
+		for _, p := range resp.Data {
+			if p.VaultID == vaultID && p.PublicKey != "" {
+				return p.PublicKey, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: no key is known for vault %s", channel_domain.ErrOccupancySignature, vaultID)
+}
+
+var _ channel_domain.OccupancyVoteVerifier = (*SlotOccupancyService)(nil)
+
+// -------- WITHDRAW --------
+
+func (s *SlotOccupancyService) Withdraw(ctx context.Context, req *channel_application.WithdrawSlotOccupancyRequest) (*channel_domain.OccupancyRequest, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	request, err := s.Requests.GetOccupancyRequest(ctx, req.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := request.Withdraw(req.ActorVaultID, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.Requests.SaveOccupancyRequest(ctx, *request); err != nil {
+		return nil, fmt.Errorf("failed to save occupancy request: %w", err)
+	}
+	return request, nil
+}
+
+// -------- QUERIES --------
+
+func (s *SlotOccupancyService) List(ctx context.Context, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if channelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+	return s.Requests.ListOccupancyRequests(ctx, channelID)
+}
+
+// Blockers explains what keeps the channel from accepting threads.
+func (s *SlotOccupancyService) Blockers(ctx context.Context, channelID string) ([]channel_domain.ChannelBlocker, error) {
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	channel, err := s.loadChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.Requests.ListOccupancyRequests(ctx, channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	return channel_domain.ChannelBlockers(channel, requests, s.now()), nil
+}
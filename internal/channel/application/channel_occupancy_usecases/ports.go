@@ -0,0 +1,33 @@
+package channel_occupancy_usecases
+
+import (
+	"context"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// VoteSigner signs occupancy votes with the voting vault's Stellar key. The
+// Stellar implementation is blockchain.StellarIdentitySigner.
+type VoteSigner interface {
+	PublicKey() string
+	Sign(message []byte) ([]byte, error)
+}
+
+// KeyVerifier checks a raw signature against a public key. The Stellar
+// implementation is blockchain.StellarKeyVerifier.
+type KeyVerifier interface {
+	Verify(publicKey string, message, signature []byte) error
+}
+
+// ApproverNotifier tells approvers a request awaits their decision. Failing
+// to notify never fails the request.
+type ApproverNotifier interface {
+	NotifyApprovers(ctx context.Context, request channel_domain.OccupancyRequest, approverVaultIDs []string) error
+}
+
+// ApproverNotifierFunc adapts a function to ApproverNotifier.
+type ApproverNotifierFunc func(ctx context.Context, request channel_domain.OccupancyRequest, approverVaultIDs []string) error
+
+func (f ApproverNotifierFunc) NotifyApprovers(ctx context.Context, request channel_domain.OccupancyRequest, approverVaultIDs []string) error {
+	return f(ctx, request, approverVaultIDs)
+}
@@ -1,16 +1,35 @@
 package channel_slot_usecases
 
 import (
+	"context"
+
 	channel_application "vault-app/internal/channel/application"
 	channel_domain "vault-app/internal/channel/domain"
 )
 
 type ChannelSlotUsecase struct {
-	repo channel_domain.ChannelRepository
+	repo   channel_domain.ChannelRepository
+	policy *channel_domain.PolicyEvaluator
 }
 
+// NewChannelSlotUsecase requires the actor to hold channel.admin, counting the
+// roles and permissions Cloud stored on participant records.
 func NewChannelSlotUsecase(repo channel_domain.ChannelRepository) *ChannelSlotUsecase {
-	return &ChannelSlotUsecase{repo: repo}
+	return &ChannelSlotUsecase{repo: repo, policy: channel_domain.NewPolicyEvaluator()}
+}
+
+// WithPolicy replaces the policy evaluator, e.g. to share its clock.
+func (u *ChannelSlotUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *ChannelSlotUsecase {
+	u.policy = policy
+	return u
+}
+
+func (u *ChannelSlotUsecase) authorize(ctx context.Context, channel *channel_domain.Channel, actorVaultID string) error {
+	policy := u.policy
+	if policy == nil {
+		policy = channel_domain.NewPolicyEvaluator()
+	}
+	return policy.RequirePermission(channel, actorVaultID, channel_domain.PermChannelAdmin, policy.GoverningParticipants(ctx, u.repo, channel)...)
 }
 
 func (u *ChannelSlotUsecase) ValidateDependencies() error {
@@ -26,6 +26,10 @@ func (u *ChannelSlotUsecase) RemoveSlot(ctx context.Context, req channel_applica
 		return nil, channel_domain.ErrChannelNotFound
 	}
 
+	if err := u.authorize(ctx, &channel.Data, req.ActorVaultID); err != nil {
+		return nil, err
+	}
+
 	if err := channel.Data.RemoveSlotByID(req.SlotID); err != nil {
 		return nil, err
 	}
@@ -25,6 +25,10 @@ func (u *ChannelSlotUsecase) UpdateSlot(ctx context.Context, req channel_applica
 		return nil, channel_domain.ErrChannelNotFound
 	}
 
+	if err := u.authorize(ctx, &channel.Data, req.ActorVaultID); err != nil {
+		return nil, err
+	}
+
 	if err := channel.Data.UpdateSlot(req.Slot); err != nil {
 		return nil, err
 	}
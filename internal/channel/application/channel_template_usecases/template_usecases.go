@@ -0,0 +1,181 @@
+package channel_template_usecases
+
+import (
+	"context"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// -------- LIST / GET --------
+
+type ListChannelTemplatesUsecase struct {
+	Registry channel_domain.ChannelTemplateRegistry
+}
+
+func NewListChannelTemplatesUsecase(registry channel_domain.ChannelTemplateRegistry) *ListChannelTemplatesUsecase {
+	return &ListChannelTemplatesUsecase{
+		Registry: registry,
+	}
+}
+
+func (u *ListChannelTemplatesUsecase) Execute(ctx context.Context) ([]channel_domain.ChannelTemplate, error) {
+	if u.Registry == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	return u.Registry.ListChannelTemplates(ctx)
+}
+
+type GetChannelTemplateUsecase struct {
+	Registry channel_domain.ChannelTemplateRegistry
+}
+
+func NewGetChannelTemplateUsecase(registry channel_domain.ChannelTemplateRegistry) *GetChannelTemplateUsecase {
+	return &GetChannelTemplateUsecase{
+		Registry: registry,
+	}
+}
+
+func (u *GetChannelTemplateUsecase) Execute(ctx context.Context, templateID string) (*channel_domain.ChannelTemplate, error) {
+	if u.Registry == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if templateID == "" {
+		return nil, channel_domain.ErrTemplateIDRequired
+	}
+	return u.Registry.GetChannelTemplate(ctx, templateID)
+}
+
+// -------- INSTANTIATE --------
+
+// InstantiateChannelTemplateUsecase builds a channel from a template and
+// creates it through CreateChannelUsecase, so it is persisted and announced
+// like any other channel.
+type InstantiateChannelTemplateUsecase struct {
+	Registry channel_domain.ChannelTemplateRegistry
+	Create   *channel_usecase.CreateChannelUsecase
+}
+
+func NewInstantiateChannelTemplateUsecase(
+	registry channel_domain.ChannelTemplateRegistry,
+	create *channel_usecase.CreateChannelUsecase,
+) *InstantiateChannelTemplateUsecase {
+	return &InstantiateChannelTemplateUsecase{
+		Registry: registry,
+		Create:   create,
+	}
+}
+
+func (u *InstantiateChannelTemplateUsecase) Execute(ctx context.Context, req *channel_application.InstantiateChannelTemplateRequest) (*channel_domain.Channel, error) {
+	if u.Registry == nil || u.Create == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.TemplateID == "" {
+		return nil, channel_domain.ErrTemplateIDRequired
+	}
+
+	template, err := u.Registry.GetChannelTemplate(ctx, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	blueprint, err := template.Instantiate(req.Title, req.WorkspaceID, req.Assignments, req.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Create.Execute(ctx, &channel_application.CreateChannelRequest{
+		TemplateID:  blueprint.TemplateID,
+		Title:       blueprint.Title,
+		WorkspaceID: blueprint.WorkspaceID,
+		Slots:       blueprint.Slots,
+		Assignments: blueprint.Assignments,
+		Properties:  blueprint.Properties,
+		Policy:      blueprint.Policy,
+		Federation:  req.Federation,
+	})
+}
+
+// -------- MIGRATE --------
+
+// MigrateChannelTemplateUsecase moves a channel to the latest version of its
+// template. A channel already at that version is returned unchanged.
+type MigrateChannelTemplateUsecase struct {
+	Repo     channel_domain.ChannelRepository
+	Registry channel_domain.ChannelTemplateRegistry
+	// Policy, when set, requires the actor to hold channel.admin.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+func NewMigrateChannelTemplateUsecase(repo channel_domain.ChannelRepository, registry channel_domain.ChannelTemplateRegistry) *MigrateChannelTemplateUsecase {
+	return &MigrateChannelTemplateUsecase{
+		Repo:     repo,
+		Registry: registry,
+	}
+}
+
+// WithPolicy enables the channel.admin permission check.
+func (u *MigrateChannelTemplateUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *MigrateChannelTemplateUsecase {
+	u.Policy = policy
+	return u
+}
+
+func (u *MigrateChannelTemplateUsecase) Execute(ctx context.Context, req *channel_application.MigrateChannelTemplateRequest) (*channel_application.ChannelTemplateMigrationResult, error) {
+	if u.Repo == nil || u.Registry == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+
+	resp, err := u.Repo.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+	channel := resp.Data
+	if channel.TemplateID == "" {
+		return nil, channel_domain.ErrTemplateIDRequired
+	}
+
+	if u.Policy != nil {
+		if err := u.Policy.RequirePermission(&channel, req.ActorVaultID, channel_domain.PermChannelAdmin); err != nil {
+			return nil, err
+		}
+	}
+
+	template, err := u.Registry.GetChannelTemplate(ctx, channel.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := template.MigrateChannel(&channel, req.Properties)
+	if err != nil {
+		return nil, err
+	}
+	if !migration.Changed() {
+		return &channel_application.ChannelTemplateMigrationResult{Channel: &channel, Migration: migration}, nil
+	}
+
+	updated, err := u.Repo.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel})
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, channel_domain.ErrRepositoryResponse
+	}
+	if updated.Data.ID != "" {
+		channel = updated.Data
+	}
+
+	return &channel_application.ChannelTemplateMigrationResult{Channel: &channel, Migration: migration}, nil
+}
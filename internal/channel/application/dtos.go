@@ -20,31 +20,40 @@ type UpdateChannelRequest struct {
 	Assignments []channel_domain.Assignment
 	Properties  []channel_domain.ChannelProperty
 	Policy      channel_domain.Policy
+	// ActorVaultID is the acting vault; use cases built WithPolicy check its
+	// channel permissions.
+	ActorVaultID string
 }
 
 type ListChannelsRequest struct {
-	WorkspaceID string
+	WorkspaceID  string
+	ActorVaultID string
 }
 
 type ArchiveChannelRequest struct {
-	ChannelID   string
-	WorkspaceID string
+	ChannelID    string
+	WorkspaceID  string
+	ActorVaultID string
 }
 
 type GetChannelRequest struct {
-	ChannelID string
+	ChannelID    string
+	ActorVaultID string
 }
 
 type DeleteChannelRequest struct {
-	ChannelID string
+	ChannelID    string
+	ActorVaultID string
 }
 
 type ActivateChannelRequest struct {
-	ChannelID string
+	ChannelID    string
+	ActorVaultID string
 }
 
 type RevokeChannelRequest struct {
-	ChannelID string
+	ChannelID    string
+	ActorVaultID string
 }
 
 type AddParticipantRequest struct {
@@ -56,8 +65,11 @@ type AddParticipantRequest struct {
 	Role      string
 }
 
+// ListParticipantsRequest lists a channel's participants on behalf of
+// ActorVaultID, which must take part in a channel that enforces permissions.
 type ListParticipantsRequest struct {
-	ChannelID string
+	ChannelID    string
+	ActorVaultID string
 }
 
 // InviteToChannelRequest mirrors the Cloud invitation contract
@@ -79,32 +91,166 @@ type AcceptChannelInvitationRequest struct {
 	InviteePublicKey string
 }
 
+// RejectChannelInvitationRequest declines a pending invitation. Only the
+// invitee may reject.
+type RejectChannelInvitationRequest struct {
+	InvitationID   string
+	InviteeVaultID string
+}
+
+// RevokeChannelInvitationRequest withdraws a pending invitation. Only the
+// inviter may revoke.
+type RevokeChannelInvitationRequest struct {
+	InvitationID   string
+	InviterVaultID string
+	Reason         string
+}
+
+// ListChannelInvitationsRequest lists the invitations a vault received or
+// sent. PendingOnly drops answered and expired invitations.
+type ListChannelInvitationsRequest struct {
+	VaultID     string
+	Direction   channel_domain.InvitationDirection
+	ChannelID   string
+	PendingOnly bool
+}
+
+// ExpireChannelInvitationsRequest marks expired the pending invitations a
+// vault sent that ran past their expiry.
+type ExpireChannelInvitationsRequest struct {
+	InviterVaultID string
+}
+
 type AddSlotRequest struct {
-	ChannelID string
-	Slot      channel_domain.Slot
+	ChannelID    string
+	Slot         channel_domain.Slot
+	ActorVaultID string
 }
 
 type UpdateSlotRequest struct {
-	ChannelID string
-	Slot      channel_domain.Slot
+	ChannelID    string
+	Slot         channel_domain.Slot
+	ActorVaultID string
 }
 
 type RemoveSlotRequest struct {
-	ChannelID string
-	SlotID    string
+	ChannelID    string
+	SlotID       string
+	ActorVaultID string
 }
 
 type AddAssignmentRequest struct {
-	ChannelID  string
-	Assignment channel_domain.Assignment
+	ChannelID    string
+	Assignment   channel_domain.Assignment
+	ActorVaultID string
 }
 
 type UpdateAssignmentRequest struct {
-	ChannelID  string
-	Assignment channel_domain.Assignment
+	ChannelID    string
+	Assignment   channel_domain.Assignment
+	ActorVaultID string
 }
 
 type RemoveAssignmentRequest struct {
 	ChannelID    string
 	AssignmentID string
+	ActorVaultID string
+}
+
+type GetChannelPolicyRequest struct {
+	ChannelID string
+}
+
+// UpdateChannelPolicyRequest replaces the channel policy. ExpectedRevision
+// must match the stored document's revision; the update bumps it.
+type UpdateChannelPolicyRequest struct {
+	ChannelID        string
+	Policy           channel_domain.PolicyDocument
+	ExpectedRevision int
+	ActorVaultID     string
+}
+
+// GrantChannelPermissionRequest gives VaultID a permission. The actor needs
+// channel.admin.
+type GrantChannelPermissionRequest struct {
+	ChannelID    string
+	ActorVaultID string
+	VaultID      string
+	Permission   string
+	Reason       string
+}
+
+// RevokeChannelPermissionRequest takes a permission from VaultID, including
+// one its role template gives it.
+type RevokeChannelPermissionRequest struct {
+	ChannelID    string
+	ActorVaultID string
+	VaultID      string
+	Permission   string
+	Reason       string
+}
+
+type GetParticipantPermissionsRequest struct {
+	ChannelID string
+	// ActorVaultID is the caller's vault; it must take part in the channel.
+	ActorVaultID string
+	VaultID      string
+}
+
+// ParticipantPermissions is what a vault may do in a channel. Enforced is
+// false while the channel has no permission templates, in which case every
+// participant may do everything.
+type ParticipantPermissions struct {
+	ChannelID   string   `json:"channel_id"`
+	VaultID     string   `json:"vault_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+	Enforced    bool     `json:"enforced"`
+}
+
+// InstantiateChannelTemplateRequest creates a channel from the latest version
+// of a template. Title defaults to the template title.
+type InstantiateChannelTemplateRequest struct {
+	TemplateID  string
+	Title       string
+	WorkspaceID string
+	Assignments []channel_domain.Assignment
+	Properties  []channel_domain.ChannelProperty
+	Federation  string
+}
+
+// MigrateChannelTemplateRequest moves a channel to the latest version of its
+// template. Properties supplies values the new version requires.
+type MigrateChannelTemplateRequest struct {
+	ChannelID    string
+	ActorVaultID string
+	Properties   []channel_domain.ChannelProperty
+}
+
+type ChannelTemplateMigrationResult struct {
+	Channel   *channel_domain.Channel          `json:"channel"`
+	Migration channel_domain.TemplateMigration `json:"migration"`
+}
+
+// RequestSlotOccupancyRequest asks for Candidate to fill a gated slot.
+// Candidate.OwnerID defaults to the acting vault.
+type RequestSlotOccupancyRequest struct {
+	ChannelID    string
+	SlotID       string
+	ActorVaultID string
+	Candidate    channel_domain.Assignment
+}
+
+// VoteSlotOccupancyRequest approves or rejects an occupancy request on
+// behalf of ActorVaultID.
+type VoteSlotOccupancyRequest struct {
+	RequestID    string
+	ActorVaultID string
+	Decision     string
+	Reason       string
+}
+
+type WithdrawSlotOccupancyRequest struct {
+	RequestID    string
+	ActorVaultID string
 }
@@ -23,4 +23,19 @@ type ChannelEventBus interface {
 
 	PublishChannelArchived(ctx context.Context, event channel_domain.ChannelArchived) error
 	SubscribeToChannelArchived(handler func(ctx context.Context, event channel_domain.ChannelArchived)) error
+
+	PublishChannelPermissionGranted(ctx context.Context, event channel_domain.ChannelPermissionGranted) error
+	SubscribeToChannelPermissionGranted(handler func(ctx context.Context, event channel_domain.ChannelPermissionGranted)) error
+
+	PublishChannelPermissionRevoked(ctx context.Context, event channel_domain.ChannelPermissionRevoked) error
+	SubscribeToChannelPermissionRevoked(handler func(ctx context.Context, event channel_domain.ChannelPermissionRevoked)) error
+
+	PublishSlotOccupancyRequested(ctx context.Context, event channel_domain.SlotOccupancyRequested) error
+	SubscribeToSlotOccupancyRequested(handler func(ctx context.Context, event channel_domain.SlotOccupancyRequested)) error
+
+	PublishSlotOccupancyVoted(ctx context.Context, event channel_domain.SlotOccupancyVoted) error
+	SubscribeToSlotOccupancyVoted(handler func(ctx context.Context, event channel_domain.SlotOccupancyVoted)) error
+
+	PublishSlotFilled(ctx context.Context, event channel_domain.SlotFilled) error
+	SubscribeToSlotFilled(handler func(ctx context.Context, event channel_domain.SlotFilled)) error
 }
@@ -0,0 +1,545 @@
+package channel_federation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+const (
+	ProtocolVersion  = "1.0"
+	DefaultBatchSize = 50
+)
+
+// Engine drives the federation snapshot: it pushes due PendingSyncItem to
+// trusted remote vaults, applies their acks, advances cursors, retries failed
+// items with backoff and dead-letters the ones that exhausted their attempts.
+//
+// Every operation loads the snapshot, mutates it and saves it under one lock,
+// so the worker loop and operator calls never interleave.
+type Engine struct {
+	Store     SnapshotStore
+	Transport Transport
+	Exchanges ExchangeLoader
+	Retry     channel_domain.RetryPolicy
+	BatchSize int
+	// LocalUserID owns the snapshot: the store keeps one per signed-in user.
+	LocalUserID string
+	// LocalVaultID is sent as the origin of every batch.
+	LocalVaultID string
+	Now          func() time.Time
+	// OnPass, when set, observes every worker pass.
+	OnPass func(report *SyncReport, err error)
+
+	mu sync.Mutex
+}
+
+func NewEngine(store SnapshotStore, transport Transport) *Engine {
+	return &Engine{
+		Store:     store,
+		Transport: transport,
+		Retry:     channel_domain.DefaultRetryPolicy(),
+		BatchSize: DefaultBatchSize,
+	}
+}
+
+// ErrNoLocalVault is returned by every snapshot operation until a vault
+// session is open.
+var ErrNoLocalVault = errors.New("no vault session is open for federation")
+
+// SetLocalVault sets the user whose snapshot the engine drives and the vault
+// sent as the origin of every batch. Both are known once a vault session is
+// open; until then passes push nothing.
+func (e *Engine) SetLocalVault(userID string, vaultID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.LocalUserID = userID
+	e.LocalVaultID = vaultID
+}
+
+// ClearLocalVault detaches the engine from userID's vault when it locks.
+func (e *Engine) ClearLocalVault(userID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.LocalUserID == userID {
+		e.LocalUserID = ""
+		e.LocalVaultID = ""
+	}
+}
+
+// owner returns the user whose snapshot is loaded. Callers hold e.mu.
+func (e *Engine) owner() (string, error) {
+	if e.LocalUserID == "" {
+		return "", ErrNoLocalVault
+	}
+	return e.LocalUserID, nil
+}
+
+// WithExchanges sets the loader used to attach payloads to pushed items.
+func (e *Engine) WithExchanges(exchanges ExchangeLoader) *Engine {
+	e.Exchanges = exchanges
+	return e
+}
+
+// SyncReport summarises one worker pass.
+type SyncReport struct {
+	Pushed       int      `json:"pushed"`
+	Acked        int      `json:"acked"`
+	Failed       int      `json:"failed"`
+	DeadLettered int      `json:"dead_lettered"`
+	Untrusted    []string `json:"untrusted,omitempty"`
+}
+
+// DeadLetter is a dead-lettered item and the remote vault it was bound for.
+type DeadLetter struct {
+	VaultID string                         `json:"vault_id"`
+	Item    channel_domain.PendingSyncItem `json:"item"`
+}
+
+func (e *Engine) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+	return time.Now().UTC()
+}
+
+func (e *Engine) validate() error {
+	if e.Store == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+	if e.Transport == nil {
+		return errors.New("federation transport is nil")
+	}
+	return nil
+}
+
+// Run executes a pass every interval until ctx is cancelled.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := e.RunOnce(ctx)
+		if e.OnPass != nil {
+			e.OnPass(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce pushes every due item once.
+func (e *Engine) RunOnce(ctx context.Context) (*SyncReport, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := &SyncReport{}
+	if e.LocalUserID == "" || e.LocalVaultID == "" {
+		return report, nil
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, e.LocalUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for i := range snapshot.RemoteVaults {
+		remote := &snapshot.RemoteVaults[i]
+
+		due := e.dueItems(remote)
+		if len(due) == 0 {
+			continue
+		}
+		if !remote.IsTrusted() {
+			report.Untrusted = append(report.Untrusted, remote.VaultID)
+			continue
+		}
+
+		changed = true
+		for start := 0; start < len(due); start += e.batchSize() {
+			end := start + e.batchSize()
+			if end > len(due) {
+				end = len(due)
+			}
+			e.pushBatch(ctx, remote, due[start:end], report)
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		remote.PruneCompleted()
+	}
+
+	if !changed {
+		return report, nil
+	}
+
+	snapshot.IsDirty = true
+	if err := e.Store.SaveFederation(ctx, e.LocalUserID, snapshot); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (e *Engine) batchSize() int {
+	if e.BatchSize > 0 {
+		return e.BatchSize
+	}
+	return DefaultBatchSize
+}
+
+func (e *Engine) dueItems(remote *channel_domain.RemoteVault) []*channel_domain.PendingSyncItem {
+	now := e.now()
+	due := make([]*channel_domain.PendingSyncItem, 0)
+	for i := range remote.Pending {
+		if remote.Pending[i].Due(now, e.Retry) {
+			due = append(due, &remote.Pending[i])
+		}
+	}
+	return due
+}
+
+func (e *Engine) pushBatch(
+	ctx context.Context,
+	remote *channel_domain.RemoteVault,
+	items []*channel_domain.PendingSyncItem,
+	report *SyncReport,
+) {
+	now := e.now()
+
+	sent := make([]*channel_domain.PendingSyncItem, 0, len(items))
+	batch := SyncBatch{
+		FromVaultID:     e.LocalVaultID,
+		ProtocolVersion: ProtocolVersion,
+		Items:           make([]SyncEnvelope, 0, len(items)),
+	}
+	for _, item := range items {
+		if item.AwaitingAck() {
+			item.MarkAckTimedOut(now, e.Retry)
+			if item.Status == channel_domain.SyncDeadLetter {
+				report.DeadLettered++
+				continue
+			}
+		}
+
+		envelope := SyncEnvelope{ExchangeID: item.ExchangeID, Cursor: item.Cursor}
+		if e.Exchanges != nil {
+			payload, err := e.Exchanges.LoadExchange(ctx, item.ExchangeID)
+			if err != nil {
+				e.fail(item, now, err, report)
+				continue
+			}
+			envelope.Payload = payload
+		}
+
+		item.MarkSending(now)
+		sent = append(sent, item)
+		batch.Items = append(batch.Items, envelope)
+	}
+	if len(sent) == 0 {
+		return
+	}
+
+	var ack *SyncAck
+	err := channel_domain.ErrRemoteEndpointRequired
+	if remote.Endpoint != "" {
+		ack, err = e.Transport.Push(ctx, *remote, batch)
+	}
+	if err != nil {
+		for _, item := range sent {
+			e.fail(item, now, err, report)
+		}
+		return
+	}
+
+	report.Pushed += len(sent)
+	for _, item := range sent {
+		item.MarkWaitingAck(now)
+	}
+	if ack != nil {
+		report.Acked += applyAck(remote, *ack, now)
+	}
+}
+
+func (e *Engine) fail(item *channel_domain.PendingSyncItem, now time.Time, cause error, report *SyncReport) {
+	item.MarkFailed(now, cause, e.Retry)
+	if item.Status == channel_domain.SyncDeadLetter {
+		report.DeadLettered++
+		return
+	}
+	report.Failed++
+}
+
+// applyAck completes the acked items and every in-flight item at or below
+// the acked cursor, then advances the remote cursor. It returns how many
+// items completed.
+func applyAck(remote *channel_domain.RemoteVault, ack SyncAck, now time.Time) int {
+	acked := make(map[string]bool, len(ack.Acked))
+	for _, id := range ack.Acked {
+		acked[id] = true
+	}
+
+	completed := 0
+	for i := range remote.Pending {
+		item := &remote.Pending[i]
+		if item.Status == channel_domain.SyncCompleted || item.Status == channel_domain.SyncDeadLetter {
+			continue
+		}
+
+		inFlight := item.Status == channel_domain.SyncSending || item.Status == channel_domain.SyncWaitingAck
+		if acked[item.ExchangeID] || (inFlight && ack.Cursor > 0 && item.Cursor <= ack.Cursor) {
+			item.MarkCompleted(now)
+			completed++
+		}
+	}
+
+	remote.AdvanceCursor(ack.Cursor, now)
+
+	return completed
+}
+
+// Acknowledge applies an ack that arrived outside a push, e.g. from a remote
+// that acks asynchronously. Acks from untrusted remotes are refused.
+func (e *Engine) Acknowledge(ctx context.Context, ack SyncAck) (int, error) {
+	if e.Store == nil {
+		return 0, channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return 0, err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	remote, found := snapshot.GetRemoteVaultByID(ack.VaultID)
+	if !found {
+		return 0, channel_domain.ErrRemoteVaultNotFound
+	}
+	if !remote.IsTrusted() {
+		return 0, channel_domain.ErrRemoteVaultNotTrusted
+	}
+
+	completed := applyAck(remote, ack, e.now())
+	remote.PruneCompleted()
+	snapshot.IsDirty = true
+
+	return completed, e.Store.SaveFederation(ctx, userID, snapshot)
+}
+
+// Snapshot loads the snapshot, serialised with the worker.
+func (e *Engine) Snapshot(ctx context.Context) (channel_domain.FederationSnapshot, error) {
+	if e.Store == nil {
+		return channel_domain.FederationSnapshot{}, channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return channel_domain.FederationSnapshot{}, err
+	}
+
+	return e.Store.LoadFederation(ctx, userID)
+}
+
+// Update loads the snapshot, applies fn and saves the result, serialised
+// with the worker. Nothing is saved when fn fails.
+func (e *Engine) Update(ctx context.Context, fn func(snapshot *channel_domain.FederationSnapshot) error) error {
+	if e.Store == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := fn(&snapshot); err != nil {
+		return err
+	}
+	snapshot.IsDirty = true
+
+	return e.Store.SaveFederation(ctx, userID, snapshot)
+}
+
+// Enqueue schedules an exchange for a remote vault. Enqueuing an exchange
+// already queued for that vault is a no-op. Items queued for a pending or
+// suspended remote wait until it is trusted; revoked remotes refuse them.
+func (e *Engine) Enqueue(ctx context.Context, vaultID string, exchangeID string, cursor uint64) error {
+	if e.Store == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+	if vaultID == "" {
+		return channel_domain.ErrVaultIDRequired
+	}
+	if exchangeID == "" {
+		return channel_domain.ErrExchangeIDRequired
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	remote, found := snapshot.GetRemoteVaultByID(vaultID)
+	if !found {
+		return channel_domain.ErrRemoteVaultNotFound
+	}
+	if remote.TrustState == channel_domain.TrustState(channel_domain.TrustRevoked) {
+		return channel_domain.ErrRemoteVaultRevoked
+	}
+	if _, exists := remote.GetPendingSyncItemByID(exchangeID); exists {
+		return nil
+	}
+
+	remote.AddPendingSyncItem(channel_domain.PendingSyncItem{
+		ExchangeID: exchangeID,
+		Status:     channel_domain.SyncPending,
+		Cursor:     cursor,
+		UpdatedAt:  e.now(),
+	})
+	snapshot.IsDirty = true
+
+	return e.Store.SaveFederation(ctx, userID, snapshot)
+}
+
+// DeadLetters lists the dead-lettered items, optionally for one vault.
+func (e *Engine) DeadLetters(ctx context.Context, vaultID string) ([]DeadLetter, error) {
+	if e.Store == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0)
+	for _, remote := range snapshot.RemoteVaults {
+		if vaultID != "" && remote.VaultID != vaultID {
+			continue
+		}
+		for _, item := range remote.GetDeadLetterSyncItems() {
+			letters = append(letters, DeadLetter{VaultID: remote.VaultID, Item: item})
+		}
+	}
+
+	return letters, nil
+}
+
+// Requeue returns one dead-lettered item to the queue with a fresh retry
+// budget.
+func (e *Engine) Requeue(ctx context.Context, vaultID string, exchangeID string) error {
+	if e.Store == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	item, found := snapshot.GetPendingSyncItem(vaultID, exchangeID)
+	if !found {
+		return channel_domain.ErrSyncItemNotFound
+	}
+	if err := item.Requeue(e.now()); err != nil {
+		return err
+	}
+	snapshot.IsDirty = true
+
+	return e.Store.SaveFederation(ctx, userID, snapshot)
+}
+
+// RequeueAll requeues every dead-lettered item, optionally for one vault, and
+// returns how many were requeued.
+func (e *Engine) RequeueAll(ctx context.Context, vaultID string) (int, error) {
+	if e.Store == nil {
+		return 0, channel_domain.ErrRepositoryNil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	userID, err := e.owner()
+	if err != nil {
+		return 0, err
+	}
+
+	snapshot, err := e.Store.LoadFederation(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := e.now()
+	requeued := 0
+	for i := range snapshot.RemoteVaults {
+		remote := &snapshot.RemoteVaults[i]
+		if vaultID != "" && remote.VaultID != vaultID {
+			continue
+		}
+		for j := range remote.Pending {
+			if remote.Pending[j].Requeue(now) == nil {
+				requeued++
+			}
+		}
+	}
+	if requeued == 0 {
+		return 0, nil
+	}
+	snapshot.IsDirty = true
+
+	return requeued, e.Store.SaveFederation(ctx, userID, snapshot)
+}
@@ -0,0 +1,63 @@
+package channel_federation
+
+import (
+	"context"
+	"errors"
+
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// ParticipantLister lists the vaults taking part in a channel.
+type ParticipantLister interface {
+	ListParticipants(ctx context.Context, req *channel_domain.ListParticipantsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error)
+}
+
+// ExchangeFeeder queues the exchanges of a channel for the remote vaults
+// among its participants. Participants that are not known remote vaults,
+// the local vault included, are skipped, and so are revoked remotes.
+type ExchangeFeeder struct {
+	Engine       *Engine
+	Participants ParticipantLister
+}
+
+func NewExchangeFeeder(engine *Engine, participants ParticipantLister) *ExchangeFeeder {
+	return &ExchangeFeeder{
+		Engine:       engine,
+		Participants: participants,
+	}
+}
+
+// FeedExchange enqueues exchangeID at cursor for every remote participant
+// of channelID.
+func (f *ExchangeFeeder) FeedExchange(ctx context.Context, channelID string, exchangeID string, cursor uint64) error {
+	if f.Engine == nil || f.Participants == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+	if channelID == "" {
+		return channel_domain.ErrChannelIDRequired
+	}
+
+	resp, err := f.Participants.ListParticipants(ctx, &channel_domain.ListParticipantsRequest{ChannelID: channelID})
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+
+	for _, participant := range resp.Data {
+		err := f.Engine.Enqueue(ctx, participant.VaultID, exchangeID, cursor)
+		switch {
+		case err == nil,
+			errors.Is(err, channel_domain.ErrVaultIDRequired),
+			errors.Is(err, channel_domain.ErrRemoteVaultNotFound),
+			errors.Is(err, channel_domain.ErrRemoteVaultRevoked):
+			continue
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
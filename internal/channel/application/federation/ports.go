@@ -0,0 +1,69 @@
+package channel_federation
+
+import (
+	"context"
+	"encoding/json"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// SnapshotStore loads and saves the federation snapshot the engine drives.
+// Each user has their own snapshot.
+type SnapshotStore interface {
+	LoadFederation(ctx context.Context, userID string) (channel_domain.FederationSnapshot, error)
+	SaveFederation(ctx context.Context, userID string, snapshot channel_domain.FederationSnapshot) error
+}
+
+// ExchangeLoader resolves the payload pushed for an exchange. Without one the
+// engine pushes exchange ids and cursors only, and the remote fetches the
+// content itself.
+type ExchangeLoader interface {
+	LoadExchange(ctx context.Context, exchangeID string) (json.RawMessage, error)
+}
+
+// Transport pushes a batch to a remote vault. The returned ack may cover only
+// part of the batch; unacked items wait for a later ack or time out.
+type Transport interface {
+	Push(ctx context.Context, remote channel_domain.RemoteVault, batch SyncBatch) (*SyncAck, error)
+}
+
+// SyncEnvelope is one exchange as sent on the wire.
+type SyncEnvelope struct {
+	ExchangeID string          `json:"exchange_id"`
+	Cursor     uint64          `json:"cursor"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// SyncBatch is what the engine pushes to one remote vault in one attempt.
+type SyncBatch struct {
+	FromVaultID     string         `json:"from_vault_id"`
+	ProtocolVersion string         `json:"protocol_version"`
+	Items           []SyncEnvelope `json:"items"`
+}
+
+// SyncAck is the remote vault's acknowledgement. Cursor is the highest
+// cursor the remote has durably applied.
+type SyncAck struct {
+	VaultID string   `json:"vault_id"`
+	Acked   []string `json:"acked"`
+	Cursor  uint64   `json:"cursor"`
+}
+
+// KeyVerifier checks a raw signature against a public key. The Stellar
+// implementation is blockchain.StellarKeyVerifier.
+type KeyVerifier interface {
+	Verify(publicKey string, message, signature []byte) error
+}
+
+// IdentitySigner signs the local vault's identity document with its Stellar
+// key.
+type IdentitySigner interface {
+	PublicKey() string
+	Sign(message []byte) ([]byte, error)
+}
+
+// Handshaker sends the local identity document to a remote endpoint and
+// returns the remote's.
+type Handshaker interface {
+	Handshake(ctx context.Context, endpoint string, local channel_domain.IdentityDocument) (*channel_domain.IdentityDocument, error)
+}
@@ -0,0 +1,239 @@
+package channel_federation
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// TrustService establishes and manages trust with remote vaults. Vaults
+// exchange signed identity documents; the presented key is pinned once an
+// operator approves it, and a later key change suspends the remote until it
+// is approved again. All changes go through the engine so they never race a
+// sync pass.
+type TrustService struct {
+	Engine     *Engine
+	Handshaker Handshaker
+	Keys       KeyVerifier
+	Now        func() time.Time
+}
+
+func NewTrustService(engine *Engine, handshaker Handshaker, keys KeyVerifier) *TrustService {
+	return &TrustService{
+		Engine:     engine,
+		Handshaker: handshaker,
+		Keys:       keys,
+	}
+}
+
+func (s *TrustService) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now().UTC()
+}
+
+// SignIdentity builds and signs the local vault's identity document.
+func (s *TrustService) SignIdentity(signer IdentitySigner, vaultID string, endpoint string) (channel_domain.IdentityDocument, error) {
+	if signer == nil {
+		return channel_domain.IdentityDocument{}, errors.New("identity signer is nil")
+	}
+
+	doc := channel_domain.IdentityDocument{
+		VaultID:         vaultID,
+		PublicKey:       signer.PublicKey(),
+		Endpoint:        endpoint,
+		ProtocolVersion: ProtocolVersion,
+		IssuedAt:        s.now(),
+	}
+	if err := doc.Validate(); err != nil {
+		return channel_domain.IdentityDocument{}, err
+	}
+
+	message, err := doc.SigningBytes()
+	if err != nil {
+		return channel_domain.IdentityDocument{}, err
+	}
+	sig, err := signer.Sign(message)
+	if err != nil {
+		return channel_domain.IdentityDocument{}, fmt.Errorf("sign identity document failed: %w", err)
+	}
+	doc.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	return doc, nil
+}
+
+// VerifyIdentity checks that the document is complete, speaks a compatible
+// protocol and is signed by the key it carries.
+func (s *TrustService) VerifyIdentity(doc channel_domain.IdentityDocument) error {
+	if s.Keys == nil {
+		return errors.New("federation key verifier is nil")
+	}
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+	if !compatibleProtocol(doc.ProtocolVersion) {
+		return fmt.Errorf("%w: protocol version %s is not supported", channel_domain.ErrIdentityDocumentInvalid, doc.ProtocolVersion)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(doc.Signature)
+	if err != nil || len(sig) == 0 {
+		return channel_domain.ErrIdentitySignature
+	}
+	message, err := doc.SigningBytes()
+	if err != nil {
+		return err
+	}
+	if err := s.Keys.Verify(doc.PublicKey, message, sig); err != nil {
+		return fmt.Errorf("%w: %v", channel_domain.ErrIdentitySignature, err)
+	}
+
+	return nil
+}
+
+// compatibleProtocol accepts any version with the same major as ours.
+func compatibleProtocol(version string) bool {
+	major := func(v string) string {
+		return strings.SplitN(v, ".", 2)[0]
+	}
+	return major(version) == major(ProtocolVersion)
+}
+
+// Handshake sends the local identity to remoteEndpoint and records the
+// identity it answers with. A new remote is left pending approval.
+func (s *TrustService) Handshake(
+	ctx context.Context,
+	signer IdentitySigner,
+	localVaultID string,
+	localEndpoint string,
+	remoteEndpoint string,
+) (*channel_domain.RemoteVault, error) {
+	if s.Handshaker == nil {
+		return nil, errors.New("federation handshaker is nil")
+	}
+	if remoteEndpoint == "" {
+		return nil, channel_domain.ErrRemoteEndpointRequired
+	}
+
+	local, err := s.SignIdentity(signer, localVaultID, localEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.Handshaker.Handshake(ctx, remoteEndpoint, local)
+	if err != nil {
+		return nil, fmt.Errorf("federation handshake failed: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("%w: remote returned no identity", channel_domain.ErrIdentityDocumentInvalid)
+	}
+	if doc.Endpoint == "" {
+		doc.Endpoint = remoteEndpoint
+	}
+
+	return s.AcceptIdentity(ctx, *doc)
+}
+
+// AcceptIdentity verifies an identity document presented by a remote vault,
+// whether it answered our handshake or initiated its own, and records it.
+func (s *TrustService) AcceptIdentity(ctx context.Context, doc channel_domain.IdentityDocument) (*channel_domain.RemoteVault, error) {
+	if err := s.VerifyIdentity(doc); err != nil {
+		return nil, err
+	}
+
+	var result channel_domain.RemoteVault
+	err := s.update(ctx, func(snapshot *channel_domain.FederationSnapshot) error {
+		remote, found := snapshot.GetRemoteVaultByID(doc.VaultID)
+		if !found {
+			snapshot.AddRemoteVault(channel_domain.RemoteVault{VaultID: doc.VaultID})
+			remote, _ = snapshot.GetRemoteVaultByID(doc.VaultID)
+		}
+
+		if _, err := remote.PresentIdentity(doc, s.now()); err != nil {
+			return err
+		}
+		result = *remote
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Approve pins the key the remote presented and trusts it. expectedKey is
+// the key the operator verified out of band.
+func (s *TrustService) Approve(ctx context.Context, vaultID string, expectedKey string) (*channel_domain.RemoteVault, error) {
+	return s.transition(ctx, vaultID, func(remote *channel_domain.RemoteVault) error {
+		return remote.ApproveTrust(expectedKey, s.now())
+	})
+}
+
+// Suspend stops sync to a trusted remote until it is approved again.
+func (s *TrustService) Suspend(ctx context.Context, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
+	return s.transition(ctx, vaultID, func(remote *channel_domain.RemoteVault) error {
+		return remote.SuspendTrust(reason, s.now())
+	})
+}
+
+// Revoke permanently distrusts a remote and drops its undelivered items.
+func (s *TrustService) Revoke(ctx context.Context, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
+	return s.transition(ctx, vaultID, func(remote *channel_domain.RemoteVault) error {
+		remote.RevokeTrust(reason, s.now())
+		return nil
+	})
+}
+
+// List returns every known remote vault with its trust state.
+func (s *TrustService) List(ctx context.Context) ([]channel_domain.RemoteVault, error) {
+	if s.Engine == nil {
+		return nil, channel_domain.ErrRepositoryNil
+	}
+
+	snapshot, err := s.Engine.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.ListRemoteVaults(), nil
+}
+
+func (s *TrustService) transition(
+	ctx context.Context,
+	vaultID string,
+	apply func(remote *channel_domain.RemoteVault) error,
+) (*channel_domain.RemoteVault, error) {
+	if vaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	var result channel_domain.RemoteVault
+	err := s.update(ctx, func(snapshot *channel_domain.FederationSnapshot) error {
+		remote, found := snapshot.GetRemoteVaultByID(vaultID)
+		if !found {
+			return channel_domain.ErrRemoteVaultNotFound
+		}
+		if err := apply(remote); err != nil {
+			return err
+		}
+		result = *remote
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (s *TrustService) update(ctx context.Context, fn func(snapshot *channel_domain.FederationSnapshot) error) error {
+	if s.Engine == nil {
+		return channel_domain.ErrRepositoryNil
+	}
+	return s.Engine.Update(ctx, fn)
+}
@@ -0,0 +1,302 @@
+package channel_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	channel_federation "vault-app/internal/channel/application/federation"
+	channel_domain "vault-app/internal/channel/domain"
+	channel_persistence "vault-app/internal/channel/infrastructure/persistence"
+	"vault-app/internal/channel/infrastructure/transport"
+	shared_offline "vault-app/internal/shared/offline"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+type federationClock struct {
+	now time.Time
+}
+
+func (c *federationClock) Now() time.Time { return c.now }
+
+func (c *federationClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFederationStore(t *testing.T, remotes ...channel_domain.RemoteVault) *channel_persistence.FederationStore {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	cache, err := shared_offline.NewCache(db)
+	require.NoError(t, err)
+
+	store := channel_persistence.NewFederationStore(cache.Store)
+	require.NoError(t, store.SaveFederation(context.Background(), "user-local", channel_domain.FederationSnapshot{RemoteVaults: remotes}))
+	return store
+}
+
+func trustedRemote(vaultID string) channel_domain.RemoteVault {
+	return channel_domain.RemoteVault{
+		VaultID:    vaultID,
+		Endpoint:   "local://" + vaultID,
+		TrustState: channel_domain.TrustState(channel_domain.Trusted),
+	}
+}
+
+func newTestEngine(store channel_federation.SnapshotStore, remote channel_federation.Transport, clock *federationClock) *channel_federation.Engine {
+	engine := channel_federation.NewEngine(store, remote)
+	engine.SetLocalVault("user-local", "vault-local")
+	engine.Now = clock.Now
+	engine.Retry = channel_domain.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		AckTimeout:  time.Minute,
+	}
+	return engine
+}
+
+func loadRemote(t *testing.T, store *channel_persistence.FederationStore, vaultID string) channel_domain.RemoteVault {
+	snapshot, err := store.LoadFederation(context.Background(), "user-local")
+	require.NoError(t, err)
+	remote, found := snapshot.GetRemoteVaultByID(vaultID)
+	require.True(t, found)
+	return *remote
+}
+
+func TestFederationEngine_PushesAndAdvancesCursor(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	store := newFederationStore(t, trustedRemote("vault-b"))
+	remote := transport.NewLocalRemoteVault("vault-b")
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-2", 2))
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-2", 2))
+
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Pushed)
+	require.Equal(t, 2, report.Acked)
+
+	require.Len(t, remote.Received(), 2)
+	stored := loadRemote(t, store, "vault-b")
+	require.Equal(t, uint64(2), stored.LastCursor)
+	require.Empty(t, stored.Pending)
+}
+
+func TestFederationEngine_SkipsUntrustedRemotes(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	untrusted := trustedRemote("vault-c")
+	untrusted.TrustState = "pending"
+	store := newFederationStore(t, untrusted)
+	remote := transport.NewLocalRemoteVault("vault-c")
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-c", "ex-1", 1))
+
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault-c"}, report.Untrusted)
+	require.Empty(t, remote.Received())
+
+	_, err = engine.Acknowledge(ctx, channel_federation.SyncAck{VaultID: "vault-c", Cursor: 1})
+	require.ErrorIs(t, err, channel_domain.ErrRemoteVaultNotTrusted)
+}
+
+func TestFederationEngine_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	store := newFederationStore(t, trustedRemote("vault-b"))
+	remote := transport.NewLocalRemoteVault("vault-b")
+	remote.SetOffline(true)
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Failed)
+
+	item := loadRemote(t, store, "vault-b").Pending[0]
+	require.Equal(t, channel_domain.SyncFailed, item.Status)
+	require.Equal(t, clock.now.Add(time.Second), item.NextAttemptAt)
+	require.Contains(t, item.LastError, "unavailable")
+
+	// Not due before the backoff elapses.
+	report, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, report.Failed)
+
+	clock.Advance(time.Second)
+	_, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, clock.now.Add(2*time.Second), loadRemote(t, store, "vault-b").Pending[0].NextAttemptAt)
+
+	clock.Advance(2 * time.Second)
+	report, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.DeadLettered)
+
+	letters, err := engine.DeadLetters(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	require.Equal(t, "vault-b", letters[0].VaultID)
+	require.Equal(t, 3, letters[0].Item.RetryCount)
+
+	// Dead letters are not retried until an operator requeues them.
+	clock.Advance(time.Hour)
+	report, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, report.Pushed+report.Failed)
+
+	remote.SetOffline(false)
+	require.ErrorIs(t, engine.Requeue(ctx, "vault-b", "ex-unknown"), channel_domain.ErrSyncItemNotFound)
+	requeued, err := engine.RequeueAll(ctx, "vault-b")
+	require.NoError(t, err)
+	require.Equal(t, 1, requeued)
+
+	report, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Acked)
+	require.Empty(t, loadRemote(t, store, "vault-b").Pending)
+}
+
+func TestFederationEngine_WaitsForAsyncAckOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+
+	remote := transport.NewLocalRemoteVault("vault-b")
+	remote.HoldAcks(true)
+	server := httptest.NewServer(remote)
+	defer server.Close()
+
+	endpoint := trustedRemote("vault-b")
+	endpoint.Endpoint = server.URL
+	store := newFederationStore(t, endpoint)
+	engine := newTestEngine(store, transport.NewHTTPFederationTransport(server.Client()), clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 7))
+
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Pushed)
+	require.Zero(t, report.Acked)
+	require.Equal(t, channel_domain.SyncWaitingAck, loadRemote(t, store, "vault-b").Pending[0].Status)
+
+	completed, err := engine.Acknowledge(ctx, remote.Ack())
+	require.NoError(t, err)
+	require.Equal(t, 1, completed)
+
+	stored := loadRemote(t, store, "vault-b")
+	require.Equal(t, uint64(7), stored.LastCursor)
+	require.Empty(t, stored.Pending)
+}
+
+func TestFederationEngine_AckTimeoutCountsAsFailure(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	store := newFederationStore(t, trustedRemote("vault-b"))
+	remote := transport.NewLocalRemoteVault("vault-b")
+	remote.HoldAcks(true)
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	_, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+
+	// Redelivered once the ack timed out, and the timeout counts as an
+	// attempt.
+	clock.Advance(time.Minute)
+	_, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	item := loadRemote(t, store, "vault-b").Pending[0]
+	require.Equal(t, 1, item.RetryCount)
+	require.Equal(t, channel_domain.ErrSyncAckTimeout.Error(), item.LastError)
+
+	// The remote dedupes the redelivery and now acks.
+	clock.Advance(time.Minute)
+	remote.HoldAcks(false)
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Acked)
+	require.Len(t, remote.Received(), 1)
+}
+
+func TestFederationEngine_AckTimeoutsDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	store := newFederationStore(t, trustedRemote("vault-b"))
+	remote := transport.NewLocalRemoteVault("vault-b")
+	remote.HoldAcks(true)
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	var report *channel_federation.SyncReport
+	for i := 0; i < 4; i++ {
+		var err error
+		report, err = engine.RunOnce(ctx)
+		require.NoError(t, err)
+		clock.Advance(time.Minute)
+	}
+
+	require.Equal(t, 1, report.DeadLettered)
+	require.Equal(t, channel_domain.SyncDeadLetter, loadRemote(t, store, "vault-b").Pending[0].Status)
+}
+
+func TestFederationEngine_PushesNothingWithoutLocalVault(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	store := newFederationStore(t, trustedRemote("vault-b"))
+	remote := transport.NewLocalRemoteVault("vault-b")
+	engine := newTestEngine(store, remote, clock)
+
+	require.NoError(t, engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	engine.ClearLocalVault("user-local")
+
+	report, err := engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Zero(t, report.Pushed)
+	require.Empty(t, remote.Received())
+	require.ErrorIs(t, engine.Enqueue(ctx, "vault-b", "ex-2", 2), channel_federation.ErrNoLocalVault)
+
+	engine.SetLocalVault("user-local", "vault-local")
+	report, err = engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Pushed)
+}
+
+type stubParticipants struct {
+	vaults []string
+}
+
+func (s stubParticipants) ListParticipants(_ context.Context, req *channel_domain.ListParticipantsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+	participants := make([]channel_domain.Participant, 0, len(s.vaults))
+	for _, vaultID := range s.vaults {
+		participants = append(participants, channel_domain.Participant{ChannelID: req.ChannelID, VaultID: vaultID})
+	}
+	return &tracecore_types.CloudResponse[[]channel_domain.Participant]{Status: 200, Data: participants}, nil
+}
+
+func TestExchangeFeeder_QueuesForRemoteParticipants(t *testing.T) {
+	ctx := context.Background()
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	revoked := trustedRemote("vault-c")
+	revoked.TrustState = channel_domain.TrustState(channel_domain.TrustRevoked)
+	store := newFederationStore(t, trustedRemote("vault-b"), revoked)
+	engine := newTestEngine(store, transport.NewLocalRemoteVault("vault-b"), clock)
+
+	feeder := channel_federation.NewExchangeFeeder(engine, stubParticipants{vaults: []string{"vault-local", "vault-b", "vault-c"}})
+	require.NoError(t, feeder.FeedExchange(ctx, "ch-1", "evt-1", 3))
+
+	pending := loadRemote(t, store, "vault-b").Pending
+	require.Len(t, pending, 1)
+	require.Equal(t, "evt-1", pending[0].ExchangeID)
+	require.Equal(t, uint64(3), pending[0].Cursor)
+	require.Empty(t, loadRemote(t, store, "vault-c").Pending)
+}
@@ -0,0 +1,276 @@
+package channel_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	channel_federation "vault-app/internal/channel/application/federation"
+	channel_domain "vault-app/internal/channel/domain"
+	"vault-app/internal/channel/infrastructure/transport"
+)
+
+// ed25519Keys stands in for the Stellar signer and verifier: public keys are
+// base64 raw ed25519 keys instead of strkey addresses.
+type ed25519Keys struct {
+	private ed25519.PrivateKey
+}
+
+func newEd25519Keys(t *testing.T) *ed25519Keys {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return &ed25519Keys{private: private}
+}
+
+func (k *ed25519Keys) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(k.private.Public().(ed25519.PublicKey))
+}
+
+func (k *ed25519Keys) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(k.private, message), nil
+}
+
+type ed25519Verifier struct{}
+
+func (ed25519Verifier) Verify(publicKey string, message, signature []byte) error {
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid public key")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), message, signature) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+type trustFixture struct {
+	clock  *federationClock
+	engine *channel_federation.Engine
+	trust  *channel_federation.TrustService
+	remote *transport.LocalRemoteVault
+	url    string
+}
+
+func newTrustFixture(t *testing.T) *trustFixture {
+	clock := &federationClock{now: time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)}
+	remote := transport.NewLocalRemoteVault("vault-b")
+	server := httptest.NewServer(remote)
+	t.Cleanup(server.Close)
+
+	httpTransport := transport.NewHTTPFederationTransport(server.Client())
+	engine := newTestEngine(newFederationStore(t), httpTransport, clock)
+	trust := channel_federation.NewTrustService(engine, httpTransport, ed25519Verifier{})
+	trust.Now = clock.Now
+
+	return &trustFixture{clock: clock, engine: engine, trust: trust, remote: remote, url: server.URL}
+}
+
+// presentAs makes the stand-in remote answer handshakes signed with keys.
+func (f *trustFixture) presentAs(t *testing.T, keys *ed25519Keys) {
+	doc, err := f.trust.SignIdentity(keys, "vault-b", f.url)
+	require.NoError(t, err)
+	f.remote.SetIdentity(doc)
+}
+
+func (f *trustFixture) handshake(t *testing.T) (*channel_domain.RemoteVault, error) {
+	return f.trust.Handshake(context.Background(), newEd25519Keys(t), "vault-local", "https://local.example", f.url)
+}
+
+func TestTrustService_HandshakeLeavesRemotePendingUntilApproved(t *testing.T) {
+	ctx := context.Background()
+	f := newTrustFixture(t)
+	remoteKeys := newEd25519Keys(t)
+	f.presentAs(t, remoteKeys)
+
+	remote, err := f.handshake(t)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.TrustState(channel_domain.TrustPending), remote.TrustState)
+	require.Equal(t, remoteKeys.PublicKey(), remote.PresentedKey)
+	require.Empty(t, remote.PublicKey)
+	require.Equal(t, f.url, remote.Endpoint)
+
+	peers := f.remote.Peers()
+	require.Len(t, peers, 1)
+	require.Equal(t, "vault-local", peers[0].VaultID)
+	require.NoError(t, f.trust.VerifyIdentity(peers[0]))
+
+	require.NoError(t, f.engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	report, err := f.engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault-b"}, report.Untrusted)
+	require.Empty(t, f.remote.Received())
+
+	_, err = f.trust.Approve(ctx, "vault-b", newEd25519Keys(t).PublicKey())
+	require.ErrorIs(t, err, channel_domain.ErrRemoteKeyMismatch)
+
+	remote, err = f.trust.Approve(ctx, "vault-b", remoteKeys.PublicKey())
+	require.NoError(t, err)
+	require.True(t, remote.IsTrusted())
+	require.Equal(t, remoteKeys.PublicKey(), remote.PublicKey)
+	require.Empty(t, remote.PresentedKey)
+
+	report, err = f.engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Acked)
+	require.Len(t, f.remote.Received(), 1)
+}
+
+func TestTrustService_KeyChangeSuspendsUntilReapproved(t *testing.T) {
+	ctx := context.Background()
+	f := newTrustFixture(t)
+	oldKeys := newEd25519Keys(t)
+	f.presentAs(t, oldKeys)
+
+	_, err := f.handshake(t)
+	require.NoError(t, err)
+	_, err = f.trust.Approve(ctx, "vault-b", oldKeys.PublicKey())
+	require.NoError(t, err)
+
+	// Same key again only refreshes the remote.
+	f.clock.Advance(time.Minute)
+	f.presentAs(t, oldKeys)
+	remote, err := f.handshake(t)
+	require.NoError(t, err)
+	require.True(t, remote.IsTrusted())
+
+	f.clock.Advance(time.Minute)
+	newKeys := newEd25519Keys(t)
+	f.presentAs(t, newKeys)
+	remote, err = f.handshake(t)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.TrustState(channel_domain.TrustSuspended), remote.TrustState)
+	require.Equal(t, channel_domain.TrustReasonKeyChanged, remote.TrustReason)
+	require.True(t, remote.KeyChangePending())
+	require.Equal(t, oldKeys.PublicKey(), remote.PublicKey)
+
+	require.NoError(t, f.engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	report, err := f.engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault-b"}, report.Untrusted)
+
+	_, err = f.trust.Approve(ctx, "vault-b", oldKeys.PublicKey())
+	require.ErrorIs(t, err, channel_domain.ErrRemoteKeyMismatch)
+
+	remote, err = f.trust.Approve(ctx, "vault-b", newKeys.PublicKey())
+	require.NoError(t, err)
+	require.True(t, remote.IsTrusted())
+	require.Equal(t, newKeys.PublicKey(), remote.PublicKey)
+
+	report, err = f.engine.RunOnce(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Acked)
+}
+
+func TestTrustService_RejectsForgedAndIncompatibleIdentities(t *testing.T) {
+	ctx := context.Background()
+	f := newTrustFixture(t)
+	keys := newEd25519Keys(t)
+
+	doc, err := f.trust.SignIdentity(keys, "vault-b", f.url)
+	require.NoError(t, err)
+
+	forged := doc
+	forged.Endpoint = "https://attacker.example"
+	_, err = f.trust.AcceptIdentity(ctx, forged)
+	require.ErrorIs(t, err, channel_domain.ErrIdentitySignature)
+
+	impostor := doc
+	impostor.PublicKey = newEd25519Keys(t).PublicKey()
+	_, err = f.trust.AcceptIdentity(ctx, impostor)
+	require.ErrorIs(t, err, channel_domain.ErrIdentitySignature)
+
+	future := channel_domain.IdentityDocument{VaultID: "vault-b", PublicKey: keys.PublicKey(), ProtocolVersion: "2.0"}
+	message, err := future.SigningBytes()
+	require.NoError(t, err)
+	sig, _ := keys.Sign(message)
+	future.Signature = base64.StdEncoding.EncodeToString(sig)
+	_, err = f.trust.AcceptIdentity(ctx, future)
+	require.ErrorIs(t, err, channel_domain.ErrIdentityDocumentInvalid)
+
+	remotes, err := f.trust.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, remotes)
+}
+
+func TestTrustService_RejectsReplayedIdentities(t *testing.T) {
+	ctx := context.Background()
+	f := newTrustFixture(t)
+	oldKeys := newEd25519Keys(t)
+	f.presentAs(t, oldKeys)
+
+	_, err := f.handshake(t)
+	require.NoError(t, err)
+	stale, err := f.trust.SignIdentity(oldKeys, "vault-b", f.url)
+	require.NoError(t, err)
+	_, err = f.trust.Approve(ctx, "vault-b", oldKeys.PublicKey())
+	require.NoError(t, err)
+
+	// The remote rotates its key and the rotation is approved.
+	f.clock.Advance(time.Hour)
+	newKeys := newEd25519Keys(t)
+	f.presentAs(t, newKeys)
+	_, err = f.handshake(t)
+	require.NoError(t, err)
+	_, err = f.trust.Approve(ctx, "vault-b", newKeys.PublicKey())
+	require.NoError(t, err)
+
+	// Replaying a document signed with the retired key must not suspend it.
+	_, err = f.trust.AcceptIdentity(ctx, stale)
+	require.ErrorIs(t, err, channel_domain.ErrIdentityReplayed)
+
+	remotes, err := f.trust.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, remotes, 1)
+	require.True(t, remotes[0].IsTrusted())
+	require.Equal(t, newKeys.PublicKey(), remotes[0].PublicKey)
+	require.False(t, remotes[0].KeyChangePending())
+}
+
+func TestTrustService_SuspendAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	f := newTrustFixture(t)
+	keys := newEd25519Keys(t)
+	f.presentAs(t, keys)
+
+	_, err := f.handshake(t)
+	require.NoError(t, err)
+
+	_, err = f.trust.Suspend(ctx, "vault-b", "audit")
+	require.ErrorIs(t, err, channel_domain.ErrTrustTransition)
+
+	_, err = f.trust.Approve(ctx, "vault-b", keys.PublicKey())
+	require.NoError(t, err)
+
+	remote, err := f.trust.Suspend(ctx, "vault-b", "audit")
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.TrustState(channel_domain.TrustSuspended), remote.TrustState)
+	require.Equal(t, "audit", remote.TrustReason)
+
+	// A suspended remote keeps its queue and resumes on re-approval.
+	require.NoError(t, f.engine.Enqueue(ctx, "vault-b", "ex-1", 1))
+	remote, err = f.trust.Approve(ctx, "vault-b", keys.PublicKey())
+	require.NoError(t, err)
+	require.True(t, remote.IsTrusted())
+	require.Len(t, remote.Pending, 1)
+
+	remote, err = f.trust.Revoke(ctx, "vault-b", "compromised")
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.TrustState(channel_domain.TrustRevoked), remote.TrustState)
+	require.Empty(t, remote.Pending)
+
+	require.ErrorIs(t, f.engine.Enqueue(ctx, "vault-b", "ex-2", 2), channel_domain.ErrRemoteVaultRevoked)
+	_, err = f.trust.Approve(ctx, "vault-b", keys.PublicKey())
+	require.ErrorIs(t, err, channel_domain.ErrRemoteVaultRevoked)
+	_, err = f.handshake(t)
+	require.ErrorIs(t, err, channel_domain.ErrRemoteVaultRevoked)
+
+	_, err = f.trust.Revoke(ctx, "vault-unknown", "")
+	require.ErrorIs(t, err, channel_domain.ErrRemoteVaultNotFound)
+}
@@ -0,0 +1,202 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+var invitationNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func pendingInvitation(id string, createdAt time.Time) channel_domain.Invitation {
+	return channel_domain.Invitation{
+		ID:             id,
+		ChannelID:      "channel-001",
+		InviterVaultID: "vault_owner",
+		InviteeVaultID: "vault_external",
+		Status:         channel_domain.InvitationStatusPending,
+		CreatedAt:      createdAt,
+	}
+}
+
+func TestInviteToChannelUsecase_SetsExpiryFromTTL(t *testing.T) {
+	repo := &channelRepositoryMock{
+		inviteToChannelFn: func(
+			ctx context.Context,
+			req *channel_domain.InviteToChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			require.NotNil(t, req.ExpiresAt)
+			require.Equal(t, invitationNow.Add(48*time.Hour), *req.ExpiresAt)
+
+			return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+				Data: channel_domain.Invitation{ID: "inv-001", ExpiresAt: req.ExpiresAt},
+			}, nil
+		},
+	}
+
+	uc := channel_usecase.NewInviteToChannelUsecase(repo).WithTTL(48 * time.Hour)
+	uc.Now = func() time.Time { return invitationNow }
+
+	inv, err := uc.Execute(context.Background(), validInviteToChannelRequest())
+
+	require.NoError(t, err)
+	require.Equal(t, invitationNow.Add(48*time.Hour), *inv.ExpiresAt)
+}
+
+func TestRejectChannelInvitationUsecase_Execute(t *testing.T) {
+	repo := &channelRepositoryMock{
+		rejectChannelInvitationFn: func(
+			ctx context.Context,
+			req *channel_domain.RejectInvitationRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			require.Equal(t, "inv-001", req.InvitationID)
+			require.Equal(t, "vault_external", req.InviteeVaultID)
+
+			return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+				Data: channel_domain.Invitation{ID: req.InvitationID, Status: channel_domain.InvitationStatusRejected},
+			}, nil
+		},
+	}
+	uc := channel_usecase.NewRejectChannelInvitationUsecase(repo)
+
+	inv, err := uc.Execute(context.Background(), &channel_application.RejectChannelInvitationRequest{
+		InvitationID:   "inv-001",
+		InviteeVaultID: "vault_external",
+	})
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.InvitationStatusRejected, inv.Status)
+
+	_, err = uc.Execute(context.Background(), &channel_application.RejectChannelInvitationRequest{InviteeVaultID: "vault_external"})
+	require.ErrorIs(t, err, channel_domain.ErrInvitationIDRequired)
+}
+
+func TestRevokeChannelInvitationUsecase_SurfacesCloudError(t *testing.T) {
+	cloudErr := errors.New("Cloud backend returned status 403: invitation not yours")
+	repo := &channelRepositoryMock{
+		revokeChannelInvitationFn: func(
+			ctx context.Context,
+			req *channel_domain.RevokeInvitationRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			require.Equal(t, "changed my mind", req.Reason)
+			return nil, cloudErr
+		},
+	}
+
+	_, err := channel_usecase.NewRevokeChannelInvitationUsecase(repo).Execute(context.Background(), &channel_application.RevokeChannelInvitationRequest{
+		InvitationID:   "inv-001",
+		InviterVaultID: "vault_stranger",
+		Reason:         "changed my mind",
+	})
+
+	require.ErrorIs(t, err, cloudErr)
+}
+
+func TestListChannelInvitationsUsecase_ReportsExpiredInvitations(t *testing.T) {
+	explicit := invitationNow.Add(-time.Minute)
+	expiredByCloud := pendingInvitation("inv-explicit", invitationNow.Add(-time.Hour))
+	expiredByCloud.ExpiresAt = &explicit
+
+	accepted := pendingInvitation("inv-accepted", invitationNow.Add(-30*24*time.Hour))
+	accepted.Status = channel_domain.InvitationStatusAccepted
+
+	repo := &channelRepositoryMock{
+		listChannelInvitationsFn: func(
+			ctx context.Context,
+			req *channel_domain.ListInvitationsRequest,
+		) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+			require.Equal(t, "vault_external", req.VaultID)
+			require.Equal(t, channel_domain.InvitationsReceived, req.Direction)
+
+			return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{
+				Data: []channel_domain.Invitation{
+					pendingInvitation("inv-fresh", invitationNow.Add(-time.Hour)),
+					pendingInvitation("inv-stale", invitationNow.Add(-8*24*time.Hour)),
+					expiredByCloud,
+					accepted,
+				},
+			}, nil
+		},
+	}
+
+	uc := channel_usecase.NewListChannelInvitationsUsecase(repo).WithTTL(channel_domain.DefaultInvitationTTL)
+	uc.Now = func() time.Time { return invitationNow }
+
+	all, err := uc.Execute(context.Background(), &channel_application.ListChannelInvitationsRequest{
+		VaultID:   "vault_external",
+		Direction: channel_domain.InvitationsReceived,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	require.Equal(t, channel_domain.InvitationStatusPending, all[0].Status)
+	require.Equal(t, channel_domain.InvitationStatusExpired, all[1].Status)
+	require.Equal(t, channel_domain.InvitationStatusExpired, all[2].Status)
+	require.Equal(t, channel_domain.InvitationStatusAccepted, all[3].Status)
+
+	pending, err := uc.Execute(context.Background(), &channel_application.ListChannelInvitationsRequest{
+		VaultID:     "vault_external",
+		Direction:   channel_domain.InvitationsReceived,
+		PendingOnly: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "inv-fresh", pending[0].ID)
+
+	_, err = uc.Execute(context.Background(), &channel_application.ListChannelInvitationsRequest{VaultID: "vault_external"})
+	require.ErrorIs(t, err, channel_domain.ErrInvitationDirection)
+}
+
+func TestExpireChannelInvitationsUsecase_ExpiresOnlyExpired(t *testing.T) {
+	marked := []string{}
+	repo := &channelRepositoryMock{
+		listChannelInvitationsFn: func(
+			ctx context.Context,
+			req *channel_domain.ListInvitationsRequest,
+		) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+			require.Equal(t, channel_domain.InvitationsSent, req.Direction)
+			require.Equal(t, channel_domain.InvitationStatusPending, req.Status)
+
+			return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{
+				Data: []channel_domain.Invitation{
+					pendingInvitation("inv-fresh", invitationNow.Add(-time.Hour)),
+					pendingInvitation("inv-stale", invitationNow.Add(-3*time.Hour)),
+				},
+			}, nil
+		},
+		expireChannelInvitationFn: func(
+			ctx context.Context,
+			req *channel_domain.ExpireInvitationRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			require.Equal(t, "vault_owner", req.InviterVaultID)
+			marked = append(marked, req.InvitationID)
+
+			return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+				Data: channel_domain.Invitation{ID: req.InvitationID, Status: channel_domain.InvitationStatusExpired},
+			}, nil
+		},
+		revokeChannelInvitationFn: func(
+			ctx context.Context,
+			req *channel_domain.RevokeInvitationRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			t.Fatalf("expiry must not revoke invitation %s", req.InvitationID)
+			return nil, nil
+		},
+	}
+
+	uc := channel_usecase.NewExpireChannelInvitationsUsecase(repo, 2*time.Hour)
+	uc.Now = func() time.Time { return invitationNow }
+
+	expired, err := uc.Execute(context.Background(), &channel_application.ExpireChannelInvitationsRequest{InviterVaultID: "vault_owner"})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"inv-stale"}, marked)
+	require.Len(t, expired, 1)
+	require.Equal(t, channel_domain.InvitationStatusExpired, expired[0].Status)
+}
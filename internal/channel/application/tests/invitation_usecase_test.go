@@ -250,3 +250,86 @@ func TestAcceptChannelInvitationUsecase_ValidateRequest(t *testing.T) {
 
 	require.NoError(t, uc.ValidateRequest(validAcceptChannelInvitationRequest()))
 }
+
+func TestAcceptChannelInvitationUsecase_WithPolicyChecksInviter(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := permissionedChannelRepo(t)
+	repo.listChannelInvitationsFn = func(
+		ctx context.Context,
+		req *channel_domain.ListInvitationsRequest,
+	) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+		require.Equal(t, "vault_external", req.VaultID)
+		require.Equal(t, channel_domain.InvitationsReceived, req.Direction)
+		return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{Data: []channel_domain.Invitation{
+			{ID: "inv-owner", ChannelID: "channel-001", InviterVaultID: "vault_owner", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusPending},
+			{ID: "inv-supplier", ChannelID: "channel-001", InviterVaultID: "vault_supplier", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusPending},
+		}}, nil
+	}
+	accepted := 0
+	repo.acceptChannelInvitationFn = func(
+		ctx context.Context,
+		req *channel_domain.AcceptInvitationRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+		accepted++
+		return &tracecore_types.CloudResponse[channel_domain.Invitation]{Data: channel_domain.Invitation{ID: req.InvitationID}}, nil
+	}
+
+	uc := channel_usecase.NewAcceptChannelInvitationUsecase(repo).WithPolicy(channel_domain.NewPolicyEvaluator())
+	accept := func(invitationID string) error {
+		_, err := uc.Execute(ctx, &channel_application.AcceptChannelInvitationRequest{
+			InvitationID:     invitationID,
+			InviteeVaultID:   "vault_external",
+			InviteePublicKey: "GEXT...",
+		})
+		return err
+	}
+
+	require.NoError(t, accept("inv-owner"))
+	// The supplier role does not hold participant.invite.
+	require.ErrorIs(t, accept("inv-supplier"), channel_domain.ErrPermissionDenied)
+	require.ErrorIs(t, accept("inv-missing"), channel_domain.ErrInvitationNotFound)
+	require.Equal(t, 1, accepted)
+}
+
+func TestAcceptChannelInvitationUsecase_RejectsExpiredInvitation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	pastExpiry := now.Add(-time.Minute)
+
+	repo := &channelRepositoryMock{
+		listChannelInvitationsFn: func(
+			ctx context.Context,
+			req *channel_domain.ListInvitationsRequest,
+		) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+			return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{Data: []channel_domain.Invitation{
+				{ID: "inv-stale", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusPending, CreatedAt: now.Add(-8 * 24 * time.Hour)},
+				{ID: "inv-dated", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusPending, CreatedAt: now, ExpiresAt: &pastExpiry},
+				{ID: "inv-fresh", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusPending, CreatedAt: now.Add(-time.Hour)},
+				{ID: "inv-accepted", InviteeVaultID: "vault_external", Status: channel_domain.InvitationStatusAccepted, CreatedAt: now.Add(-30 * 24 * time.Hour)},
+			}}, nil
+		},
+		acceptChannelInvitationFn: func(
+			ctx context.Context,
+			req *channel_domain.AcceptInvitationRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+			return &tracecore_types.CloudResponse[channel_domain.Invitation]{Data: channel_domain.Invitation{ID: req.InvitationID}}, nil
+		},
+	}
+
+	uc := channel_usecase.NewAcceptChannelInvitationUsecase(repo).WithTTL(7 * 24 * time.Hour)
+	uc.Now = func() time.Time { return now }
+	accept := func(invitationID string) error {
+		_, err := uc.Execute(ctx, &channel_application.AcceptChannelInvitationRequest{
+			InvitationID:     invitationID,
+			InviteeVaultID:   "vault_external",
+			InviteePublicKey: "GEXT...",
+		})
+		return err
+	}
+
+	require.ErrorIs(t, accept("inv-stale"), channel_domain.ErrInvitationExpired)
+	require.ErrorIs(t, accept("inv-dated"), channel_domain.ErrInvitationExpired)
+	require.NoError(t, accept("inv-fresh"))
+	// A repeated accept is answered by the Cloud.
+	require.NoError(t, accept("inv-accepted"))
+}
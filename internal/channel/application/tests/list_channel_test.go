@@ -70,6 +70,26 @@ type channelRepositoryMock struct {
 		req *channel_domain.InviteToChannelRequest,
 	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error)
 
+	rejectChannelInvitationFn func(
+		ctx context.Context,
+		req *channel_domain.RejectInvitationRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error)
+
+	revokeChannelInvitationFn func(
+		ctx context.Context,
+		req *channel_domain.RevokeInvitationRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error)
+
+	expireChannelInvitationFn func(
+		ctx context.Context,
+		req *channel_domain.ExpireInvitationRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error)
+
+	listChannelInvitationsFn func(
+		ctx context.Context,
+		req *channel_domain.ListInvitationsRequest,
+	) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error)
+
 	acceptChannelInvitationFn func(
 		ctx context.Context,
 		req *channel_domain.AcceptInvitationRequest,
@@ -221,6 +241,67 @@ func (m *channelRepositoryMock) AcceptChannelInvitation(
 	}, nil
 }
 
+func (m *channelRepositoryMock) RejectChannelInvitation(
+	ctx context.Context,
+	req *channel_domain.RejectInvitationRequest,
+) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if m.rejectChannelInvitationFn != nil {
+		return m.rejectChannelInvitationFn(ctx, req)
+	}
+
+	return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+		Data: channel_domain.Invitation{
+			ID:     req.InvitationID,
+			Status: channel_domain.InvitationStatusRejected,
+		},
+	}, nil
+}
+
+func (m *channelRepositoryMock) RevokeChannelInvitation(
+	ctx context.Context,
+	req *channel_domain.RevokeInvitationRequest,
+) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if m.revokeChannelInvitationFn != nil {
+		return m.revokeChannelInvitationFn(ctx, req)
+	}
+
+	return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+		Data: channel_domain.Invitation{
+			ID:     req.InvitationID,
+			Status: channel_domain.InvitationStatusRevoked,
+		},
+	}, nil
+}
+
+func (m *channelRepositoryMock) ExpireChannelInvitation(
+	ctx context.Context,
+	req *channel_domain.ExpireInvitationRequest,
+) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if m.expireChannelInvitationFn != nil {
+		return m.expireChannelInvitationFn(ctx, req)
+	}
+
+	return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+		Data: channel_domain.Invitation{
+			ID:     req.InvitationID,
+			Status: channel_domain.InvitationStatusExpired,
+		},
+	}, nil
+}
+
+func (m *channelRepositoryMock) ListChannelInvitations(
+	ctx context.Context,
+	req *channel_domain.ListInvitationsRequest,
+) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+	if m.listChannelInvitationsFn != nil {
+		return m.listChannelInvitationsFn(ctx, req)
+	}
+
+	return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{
+		Data: []channel_domain.Invitation{},
+	}, nil
+}
+
 type channelEventBusMock struct {
 	publishCreatedFn func(
 		ctx context.Context,
@@ -246,6 +327,13 @@ type channelEventBusMock struct {
 	publishedRevokedEvents  []channel_domain.ChannelRevoked
 	publishedDeletedEvents  []channel_domain.ChannelDeleted
 	publishedArchivedEvents []channel_domain.ChannelArchived
+
+	publishedPermissionGrantedEvents []channel_domain.ChannelPermissionGranted
+	publishedPermissionRevokedEvents []channel_domain.ChannelPermissionRevoked
+
+	publishedSlotOccupancyRequestedEvents []channel_domain.SlotOccupancyRequested
+	publishedSlotOccupancyVotedEvents     []channel_domain.SlotOccupancyVoted
+	publishedSlotFilledEvents             []channel_domain.SlotFilled
 }
 
 func (m *channelEventBusMock) PublishChannelCreated(
@@ -324,6 +412,76 @@ func (m *channelEventBusMock) SubscribeToChannelArchived(
 	return nil
 }
 
+func (m *channelEventBusMock) PublishChannelPermissionGranted(
+	ctx context.Context,
+	event channel_domain.ChannelPermissionGranted,
+) error {
+	m.publishedPermissionGrantedEvents = append(m.publishedPermissionGrantedEvents, event)
+	return nil
+}
+
+func (m *channelEventBusMock) SubscribeToChannelPermissionGranted(
+	handler func(ctx context.Context, event channel_domain.ChannelPermissionGranted),
+) error {
+	return nil
+}
+
+func (m *channelEventBusMock) PublishChannelPermissionRevoked(
+	ctx context.Context,
+	event channel_domain.ChannelPermissionRevoked,
+) error {
+	m.publishedPermissionRevokedEvents = append(m.publishedPermissionRevokedEvents, event)
+	return nil
+}
+
+func (m *channelEventBusMock) SubscribeToChannelPermissionRevoked(
+	handler func(ctx context.Context, event channel_domain.ChannelPermissionRevoked),
+) error {
+	return nil
+}
+
+func (m *channelEventBusMock) PublishSlotOccupancyRequested(
+	ctx context.Context,
+	event channel_domain.SlotOccupancyRequested,
+) error {
+	m.publishedSlotOccupancyRequestedEvents = append(m.publishedSlotOccupancyRequestedEvents, event)
+	return nil
+}
+
+func (m *channelEventBusMock) SubscribeToSlotOccupancyRequested(
+	handler func(ctx context.Context, event channel_domain.SlotOccupancyRequested),
+) error {
+	return nil
+}
+
+func (m *channelEventBusMock) PublishSlotOccupancyVoted(
+	ctx context.Context,
+	event channel_domain.SlotOccupancyVoted,
+) error {
+	m.publishedSlotOccupancyVotedEvents = append(m.publishedSlotOccupancyVotedEvents, event)
+	return nil
+}
+
+func (m *channelEventBusMock) SubscribeToSlotOccupancyVoted(
+	handler func(ctx context.Context, event channel_domain.SlotOccupancyVoted),
+) error {
+	return nil
+}
+
+func (m *channelEventBusMock) PublishSlotFilled(
+	ctx context.Context,
+	event channel_domain.SlotFilled,
+) error {
+	m.publishedSlotFilledEvents = append(m.publishedSlotFilledEvents, event)
+	return nil
+}
+
+func (m *channelEventBusMock) SubscribeToSlotFilled(
+	handler func(ctx context.Context, event channel_domain.SlotFilled),
+) error {
+	return nil
+}
+
 // Compile-time interface checks
 var _ channel_domain.ChannelRepository = (*channelRepositoryMock)(nil)
 var _ channel_events.ChannelEventBus = (*channelEventBusMock)(nil)
@@ -334,7 +492,8 @@ var _ channel_events.ChannelEventBus = (*channelEventBusMock)(nil)
 
 func validListChannelsRequest() *channel_application.ListChannelsRequest {
 	return &channel_application.ListChannelsRequest{
-		WorkspaceID: "workspace-001",
+		WorkspaceID:  "workspace-001",
+		ActorVaultID: "vault-001",
 	}
 }
 
@@ -354,6 +513,7 @@ func TestListChannelUsecase_Execute_Success(t *testing.T) {
 			Title:       "Battery Engineering Review",
 			Status:      channel_domain.StatusActive,
 			WorkspaceID: "workspace-001",
+			Slots:       []channel_domain.Slot{{ID: "slot-1", Role: "owner", VaultID: "vault-001"}},
 			CreatedAt:   now,
 			UpdatedAt:   now,
 			IsDraft:     false,
@@ -365,6 +525,7 @@ func TestListChannelUsecase_Execute_Success(t *testing.T) {
 			Title:       "Flight Control Discussion",
 			Status:      channel_domain.StatusActive,
 			WorkspaceID: "workspace-001",
+			Assignments: []channel_domain.Assignment{{SlotID: "slot-1", OwnerID: "vault-001"}},
 			CreatedAt:   now,
 			UpdatedAt:   now,
 			IsDraft:     true,
@@ -394,6 +555,45 @@ func TestListChannelUsecase_Execute_Success(t *testing.T) {
 	require.Equal(t, expectedChannels, result)
 }
 
+func TestListChannelUsecase_Execute_OnlyChannelsTheActorTakesPartIn(t *testing.T) {
+	ctx := context.Background()
+
+	member := channel_domain.Channel{ID: "channel-member", WorkspaceID: "workspace-001"}
+	stranger := channel_domain.Channel{
+		ID:          "channel-stranger",
+		WorkspaceID: "workspace-001",
+		Slots:       []channel_domain.Slot{{ID: "slot-1", Role: "owner", VaultID: "vault-002"}},
+	}
+
+	repo := &channelRepositoryMock{
+		listFn: func(
+			ctx context.Context,
+			req *channel_domain.ListChannelsRequest,
+		) (*tracecore_types.CloudResponse[[]channel_domain.Channel], error) {
+			return &tracecore_types.CloudResponse[[]channel_domain.Channel]{
+				Data: []channel_domain.Channel{member, stranger},
+			}, nil
+		},
+		listParticipantsFn: func(
+			ctx context.Context,
+			req *channel_domain.ListParticipantsRequest,
+		) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+			participants := []channel_domain.Participant{}
+			if req.ChannelID == "channel-member" {
+				participants = append(participants, channel_domain.Participant{ChannelID: req.ChannelID, VaultID: "vault-001"})
+			}
+			return &tracecore_types.CloudResponse[[]channel_domain.Participant]{Data: participants}, nil
+		},
+	}
+
+	uc := channel_usecase.NewListChannelUsecase(repo)
+
+	result, err := uc.Execute(ctx, validListChannelsRequest())
+
+	require.NoError(t, err)
+	require.Equal(t, []channel_domain.Channel{member}, result)
+}
+
 func TestListChannelUsecase_Execute_EmptyCollection(t *testing.T) {
 	ctx := context.Background()
 
@@ -511,10 +711,15 @@ func TestListChannelUsecase_ValidateRequest(t *testing.T) {
 			expectedError: channel_domain.ErrWorkspaceIDRequired.Error(),
 		},
 		{
-			name: "valid request",
+			name: "missing actor vault id",
 			request: &channel_application.ListChannelsRequest{
 				WorkspaceID: "workspace-001",
 			},
+			expectedError: channel_domain.ErrVaultIDRequired.Error(),
+		},
+		{
+			name:    "valid request",
+			request: validListChannelsRequest(),
 		},
 	}
 
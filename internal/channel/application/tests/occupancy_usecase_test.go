@@ -0,0 +1,281 @@
+package channel_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_occupancy_usecases "vault-app/internal/channel/application/channel_occupancy_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// occupancyRequestsStub keeps occupancy requests in memory.
+type occupancyRequestsStub struct {
+	requests map[string]channel_domain.OccupancyRequest
+	order    []string
+}
+
+func (s *occupancyRequestsStub) SaveOccupancyRequest(ctx context.Context, r channel_domain.OccupancyRequest) error {
+	if s.requests == nil {
+		s.requests = map[string]channel_domain.OccupancyRequest{}
+	}
+	if _, ok := s.requests[r.ID]; !ok {
+		s.order = append(s.order, r.ID)
+	}
+	s.requests[r.ID] = r
+	return nil
+}
+
+func (s *occupancyRequestsStub) GetOccupancyRequest(ctx context.Context, requestID string) (*channel_domain.OccupancyRequest, error) {
+	r, ok := s.requests[requestID]
+	if !ok {
+		return nil, channel_domain.ErrOccupancyRequestNotFound
+	}
+	return &r, nil
+}
+
+func (s *occupancyRequestsStub) ListOccupancyRequests(ctx context.Context, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	requests := []channel_domain.OccupancyRequest{}
+	for _, id := range s.order {
+		if r := s.requests[id]; r.ChannelID == channelID {
+			requests = append(requests, r)
+		}
+	}
+	return requests, nil
+}
+
+type occupancyFixture struct {
+	channel  *channel_domain.Channel
+	repo     *channelRepositoryMock
+	bus      *channelEventBusMock
+	service  *channel_occupancy_usecases.SlotOccupancyService
+	notified [][]string
+	buyer    *ed25519Keys
+	finance  *ed25519Keys
+}
+
+// newOccupancyFixture builds a channel whose gated supplier slot needs the
+// buyer and finance to approve.
+func newOccupancyFixture(t *testing.T) *occupancyFixture {
+	f := &occupancyFixture{
+		bus:     &channelEventBusMock{},
+		buyer:   newEd25519Keys(t),
+		finance: newEd25519Keys(t),
+	}
+
+	doc := channel_domain.DefaultPolicy()
+	doc.GatedSlots = channel_domain.SlotApprovalPolicy{Approvals: 2, ApproverRoles: []string{"buyer", "finance"}}
+	repo, channel := governedChannelRepo(t, doc)
+	channel.Status = channel_domain.StatusActive
+	channel.Slots = []channel_domain.Slot{
+		{ID: "buyer", Role: "buyer", Gated: true},
+		{ID: "supplier", Role: "supplier", Gated: true},
+		{ID: "finance", Role: "finance"},
+	}
+	channel.Assignments = []channel_domain.Assignment{
+		{SlotID: "buyer", OwnerID: "vault_buyer", PublicKey: f.buyer.PublicKey()},
+		{SlotID: "finance", OwnerID: "vault_finance"},
+	}
+	f.channel = channel
+	f.repo = repo
+	// Finance holds its slot without a key; the Cloud knows it as a
+	// participant.
+	repo.listParticipantsFn = func(ctx context.Context, req *channel_domain.ListParticipantsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+		return &tracecore_types.CloudResponse[[]channel_domain.Participant]{Data: []channel_domain.Participant{
+			{ChannelID: req.ChannelID, VaultID: "vault_finance", PublicKey: f.finance.PublicKey()},
+		}}, nil
+	}
+
+	f.service = channel_occupancy_usecases.NewSlotOccupancyService(repo, &occupancyRequestsStub{}, f.bus, ed25519Verifier{}).
+		WithPolicy(channel_domain.NewPolicyEvaluator()).
+		WithNotifier(channel_occupancy_usecases.ApproverNotifierFunc(
+			func(ctx context.Context, r channel_domain.OccupancyRequest, approvers []string) error {
+				f.notified = append(f.notified, approvers)
+				return nil
+			},
+		))
+	return f
+}
+
+func (f *occupancyFixture) request(t *testing.T) *channel_domain.OccupancyRequest {
+	request, err := f.service.Request(context.Background(), &channel_application.RequestSlotOccupancyRequest{
+		ChannelID:    "channel-001",
+		SlotID:       "supplier",
+		ActorVaultID: "vault_supplier",
+		Candidate:    channel_domain.Assignment{PublicKey: "supplier-key"},
+	})
+	require.NoError(t, err)
+	return request
+}
+
+func (f *occupancyFixture) approve(requestID string, vaultID string, keys *ed25519Keys) (*channel_domain.OccupancyRequest, error) {
+	return f.service.Vote(context.Background(), keys, &channel_application.VoteSlotOccupancyRequest{
+		RequestID:    requestID,
+		ActorVaultID: vaultID,
+		Decision:     channel_domain.OccupancyVoteApprove,
+	})
+}
+
+func TestSlotOccupancyService_FillsSlotAfterSignedApprovals(t *testing.T) {
+	f := newOccupancyFixture(t)
+	ctx := context.Background()
+
+	request := f.request(t)
+	require.Equal(t, "vault_supplier", request.CandidateVaultID)
+	require.Equal(t, [][]string{{"vault_buyer", "vault_finance"}}, f.notified)
+	require.Len(t, f.bus.publishedSlotOccupancyRequestedEvents, 1)
+
+	_, err := f.service.Request(ctx, &channel_application.RequestSlotOccupancyRequest{
+		ChannelID: "channel-001", SlotID: "supplier", ActorVaultID: "vault_other",
+	})
+	require.ErrorIs(t, err, channel_domain.ErrOccupancyRequestPending)
+
+	_, err = f.approve(request.ID, "vault_buyer", newEd25519Keys(t))
+	require.ErrorIs(t, err, channel_domain.ErrOccupancySignature, "the buyer must sign with the key it holds its slot with")
+
+	updated, err := f.approve(request.ID, "vault_buyer", f.buyer)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.OccupancyPending, updated.Status)
+	_, filled := f.channel.GetAssignmentBySlotID("supplier")
+	require.False(t, filled)
+
+	blockers, err := f.service.Blockers(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	require.Equal(t, []string{"vault_finance"}, blockers[0].PendingApprovers)
+
+	updated, err = f.approve(request.ID, "vault_finance", f.finance)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.OccupancyApproved, updated.Status)
+
+	assignment, filled := f.channel.GetAssignmentBySlotID("supplier")
+	require.True(t, filled)
+	require.Equal(t, "vault_supplier", assignment.OwnerID)
+	require.Equal(t, "supplier-key", assignment.PublicKey)
+
+	require.Len(t, f.bus.publishedSlotOccupancyVotedEvents, 2)
+	for _, e := range f.bus.publishedSlotOccupancyVotedEvents {
+		require.NotEmpty(t, e.Vote.Signature)
+	}
+	require.Len(t, f.bus.publishedSlotFilledEvents, 1)
+
+	blockers, err = f.service.Blockers(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Empty(t, blockers)
+}
+
+func TestSlotOccupancyService_RejectsForgedVotes(t *testing.T) {
+	f := newOccupancyFixture(t)
+	request := f.request(t)
+
+	vote, err := f.service.SignVote(f.finance, *request, &channel_application.VoteSlotOccupancyRequest{
+		RequestID:    request.ID,
+		ActorVaultID: "vault_finance",
+		Decision:     channel_domain.OccupancyVoteApprove,
+	})
+	require.NoError(t, err)
+
+	forged := vote
+	forged.Decision = channel_domain.OccupancyVoteReject
+	_, err = f.service.SubmitVote(context.Background(), forged)
+	require.ErrorIs(t, err, channel_domain.ErrOccupancySignature)
+
+	forged = vote
+	forged.Signature = base64.StdEncoding.EncodeToString([]byte("nope"))
+	_, err = f.service.SubmitVote(context.Background(), forged)
+	require.ErrorIs(t, err, channel_domain.ErrOccupancySignature)
+
+	updated, err := f.service.SubmitVote(context.Background(), vote)
+	require.NoError(t, err)
+	require.Equal(t, 1, updated.Approvals())
+	require.Empty(t, f.bus.publishedSlotFilledEvents)
+}
+
+func TestSlotOccupancyService_RetryingTheDecidingVoteAssignsTheSlot(t *testing.T) {
+	f := newOccupancyFixture(t)
+	ctx := context.Background()
+	request := f.request(t)
+	_, err := f.approve(request.ID, "vault_buyer", f.buyer)
+	require.NoError(t, err)
+
+	vote, err := f.service.SignVote(f.finance, *request, &channel_application.VoteSlotOccupancyRequest{
+		RequestID:    request.ID,
+		ActorVaultID: "vault_finance",
+		Decision:     channel_domain.OccupancyVoteApprove,
+	})
+	require.NoError(t, err)
+
+	update := f.repo.updateFn
+	f.repo.updateFn = func(ctx context.Context, req *channel_domain.UpdateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+		return nil, errors.New("cloud unavailable")
+	}
+	_, err = f.service.SubmitVote(ctx, vote)
+	require.Error(t, err)
+	_, filled := f.channel.GetAssignmentBySlotID("supplier")
+	require.False(t, filled)
+
+	// The deciding vote was recorded before the failed write.
+	recorded, err := f.service.Requests.GetOccupancyRequest(ctx, request.ID)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.OccupancyApproved, recorded.Status)
+
+	f.repo.updateFn = update
+	updated, err := f.service.SubmitVote(ctx, vote)
+	require.NoError(t, err)
+	require.Equal(t, 2, updated.Approvals())
+	_, filled = f.channel.GetAssignmentBySlotID("supplier")
+	require.True(t, filled)
+	require.Len(t, f.bus.publishedSlotOccupancyVotedEvents, 2)
+	require.Len(t, f.bus.publishedSlotFilledEvents, 1)
+}
+
+func TestSlotOccupancyService_RequestingForAnotherVaultNeedsInvitePermission(t *testing.T) {
+	f := newOccupancyFixture(t)
+	doc := channel_domain.DefaultPolicy()
+	doc.GatedSlots = channel_domain.SlotApprovalPolicy{ApproverRoles: []string{"buyer"}}
+	doc.Permissions.Roles = map[string][]string{"buyer": {channel_domain.PermChannelAdmin}, "finance": {channel_domain.PermThreadCreate}}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	f.channel.SetPolicy(policy)
+
+	_, err = f.service.Request(context.Background(), &channel_application.RequestSlotOccupancyRequest{
+		ChannelID:    "channel-001",
+		SlotID:       "supplier",
+		ActorVaultID: "vault_finance",
+		Candidate:    channel_domain.Assignment{OwnerID: "vault_supplier"},
+	})
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+
+	request, err := f.service.Request(context.Background(), &channel_application.RequestSlotOccupancyRequest{
+		ChannelID:    "channel-001",
+		SlotID:       "supplier",
+		ActorVaultID: "vault_buyer",
+		Candidate:    channel_domain.Assignment{OwnerID: "vault_supplier"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault_buyer"}, request.Approvers)
+	require.Equal(t, 1, request.Required)
+}
+
+func TestSlotOccupancyService_RejectsVotesWithoutAKnownKey(t *testing.T) {
+	f := newOccupancyFixture(t)
+	doc := channel_domain.DefaultPolicy()
+	doc.GatedSlots = channel_domain.SlotApprovalPolicy{Approvals: 1}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	f.channel.SetPolicy(policy)
+	f.channel.Slots = append(f.channel.Slots, channel_domain.Slot{ID: "auditor", Role: "auditor", VaultID: "vault_auditor"})
+	request := f.request(t)
+	require.Contains(t, request.Approvers, "vault_auditor")
+
+	// The auditor signs with a key of its own choosing; nothing vouches for it.
+	_, err = f.approve(request.ID, "vault_auditor", newEd25519Keys(t))
+	require.ErrorIs(t, err, channel_domain.ErrOccupancySignature)
+	_, filled := f.channel.GetAssignmentBySlotID("supplier")
+	require.False(t, filled)
+}
@@ -302,6 +302,30 @@ func TestListParticipantsUsecase_Execute_Success(t *testing.T) {
 	require.Equal(t, expected, result)
 }
 
+func TestListParticipantsUsecase_WithPolicyRequiresParticipant(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := permissionedChannelRepo(t)
+	repo.listParticipantsFn = func(
+		ctx context.Context,
+		req *channel_domain.ListParticipantsRequest,
+	) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+		return &tracecore_types.CloudResponse[[]channel_domain.Participant]{
+			Data: []channel_domain.Participant{{ChannelID: "channel-001", VaultID: "vault_external"}},
+		}, nil
+	}
+
+	uc := channel_usecase.NewListParticipantsUsecase(repo).WithPolicy(channel_domain.NewPolicyEvaluator())
+	list := func(actor string) error {
+		_, err := uc.Execute(ctx, &channel_application.ListParticipantsRequest{ChannelID: "channel-001", ActorVaultID: actor})
+		return err
+	}
+
+	require.NoError(t, list("vault_supplier"))
+	require.NoError(t, list("vault_external"))
+	require.ErrorIs(t, list("vault_stranger"), channel_domain.ErrPermissionDenied)
+	require.ErrorIs(t, list(""), channel_domain.ErrPermissionDenied)
+}
+
 func TestListParticipantsUsecase_Execute_EmptyCollection(t *testing.T) {
 	ctx := context.Background()
 
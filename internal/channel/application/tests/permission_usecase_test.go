@@ -0,0 +1,271 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_application "vault-app/internal/channel/application"
+	channelconfigusecases "vault-app/internal/channel/application/channel_config-usecases"
+	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	"vault-app/internal/channel/application/channel_slot_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// recordingEventLog keeps the channel events appended to it. While failNext
+// is set the next append fails.
+type recordingEventLog struct {
+	appended []channel_domain.AppendChannelEventRequest
+	failNext bool
+}
+
+func (l *recordingEventLog) AppendChannelEvent(_ context.Context, req *channel_domain.AppendChannelEventRequest) (*tracecore_types.CloudResponse[channel_domain.ChannelEvent], error) {
+	if l.failNext {
+		l.failNext = false
+		return nil, errors.New("cloud unavailable")
+	}
+	l.appended = append(l.appended, *req)
+	return &tracecore_types.CloudResponse[channel_domain.ChannelEvent]{Data: channel_domain.ChannelEvent{ChannelID: req.ChannelID, Type: req.Type}}, nil
+}
+
+func (l *recordingEventLog) ListChannelEvents(_ context.Context, _ *channel_domain.ListChannelEventsRequest) (*tracecore_types.CloudResponse[[]channel_domain.ChannelEvent], error) {
+	return &tracecore_types.CloudResponse[[]channel_domain.ChannelEvent]{}, nil
+}
+
+func permissionedChannelRepo(t *testing.T) (*channelRepositoryMock, *channel_domain.Channel) {
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions.Roles = map[string][]string{
+		"buyer":    {channel_domain.PermChannelAdmin},
+		"supplier": {channel_domain.ThreadAppendPermission("invoice.created")},
+	}
+	return governedChannelRepo(t, doc)
+}
+
+func TestGrantChannelPermissionUsecase_RecordsAndAudits(t *testing.T) {
+	repo, channel := permissionedChannelRepo(t)
+	bus := &channelEventBusMock{}
+
+	doc, err := channelconfigusecases.NewGrantChannelPermissionUsecase(repo, bus).Execute(
+		context.Background(),
+		&channel_application.GrantChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_owner",
+			VaultID:      "vault_supplier",
+			Permission:   channel_domain.PermThreadCreate,
+			Reason:       "supplier opens its own threads",
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, doc.Revision)
+	require.Len(t, bus.publishedPermissionGrantedEvents, 1)
+	event := bus.publishedPermissionGrantedEvents[0]
+	require.Equal(t, "vault_owner", event.ActorVaultID)
+	require.Equal(t, "vault_supplier", event.VaultID)
+	require.Equal(t, channel_domain.PermThreadCreate, event.Permission)
+	require.Equal(t, 1, event.PolicyRevision)
+
+	stored, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+	require.Equal(t, []string{channel_domain.PermThreadCreate}, stored.Permissions.Grants["vault_supplier"])
+	require.NoError(t, channel_domain.NewPolicyEvaluator().RequirePermission(channel, "vault_supplier", channel_domain.PermThreadCreate))
+
+	// Granting what is already granted is not written or published again.
+	_, err = channelconfigusecases.NewGrantChannelPermissionUsecase(repo, bus).Execute(
+		context.Background(),
+		&channel_application.GrantChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_owner",
+			VaultID:      "vault_supplier",
+			Permission:   channel_domain.PermThreadCreate,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, bus.publishedPermissionGrantedEvents, 1)
+}
+
+func TestPermissionUsecases_LogChangesToChannelEventLog(t *testing.T) {
+	repo, _ := permissionedChannelRepo(t)
+	bus := &channelEventBusMock{}
+	log := &recordingEventLog{}
+	ctx := context.Background()
+
+	_, err := channelconfigusecases.NewGrantChannelPermissionUsecase(repo, bus).WithEventLog(log).Execute(ctx,
+		&channel_application.GrantChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_owner",
+			VaultID:      "vault_supplier",
+			Permission:   channel_domain.PermThreadCreate,
+		},
+	)
+	require.NoError(t, err)
+	_, err = channelconfigusecases.NewRevokeChannelPermissionUsecase(repo, bus).WithEventLog(log).Execute(ctx,
+		&channel_application.RevokeChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_owner",
+			VaultID:      "vault_supplier",
+			Permission:   channel_domain.PermThreadCreate,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, log.appended, 2)
+	require.Equal(t, channel_domain.ChannelEventPermissionGranted, log.appended[0].Type)
+	require.Equal(t, channel_domain.ChannelEventPermissionRevoked, log.appended[1].Type)
+	require.Equal(t, "vault_owner", log.appended[0].ActorVaultID)
+	require.NotEqual(t, log.appended[0].IdempotencyKey, log.appended[1].IdempotencyKey)
+	require.Contains(t, string(log.appended[0].Payload), "vault_supplier")
+}
+
+func TestGrantChannelPermissionUsecase_RetryLogsAGrantWhoseAppendFailed(t *testing.T) {
+	repo, _ := permissionedChannelRepo(t)
+	bus := &channelEventBusMock{}
+	log := &recordingEventLog{failNext: true}
+	grant := channelconfigusecases.NewGrantChannelPermissionUsecase(repo, bus).WithEventLog(log)
+	req := &channel_application.GrantChannelPermissionRequest{
+		ChannelID:    "channel-001",
+		ActorVaultID: "vault_owner",
+		VaultID:      "vault_supplier",
+		Permission:   channel_domain.PermThreadCreate,
+	}
+
+	_, err := grant.Execute(context.Background(), req)
+	require.Error(t, err)
+	require.Empty(t, log.appended)
+
+	doc, err := grant.Execute(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, 1, doc.Revision, "the policy is not written twice")
+	require.Len(t, log.appended, 1, "the retry completes the audit record")
+
+	_, err = grant.Execute(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, log.appended, 2)
+	require.Equal(t, log.appended[0].IdempotencyKey, log.appended[1].IdempotencyKey, "further retries are deduped by the Cloud")
+}
+
+func TestGrantChannelPermissionUsecase_RequiresAdmin(t *testing.T) {
+	repo, _ := permissionedChannelRepo(t)
+	bus := &channelEventBusMock{}
+
+	_, err := channelconfigusecases.NewGrantChannelPermissionUsecase(repo, bus).Execute(
+		context.Background(),
+		&channel_application.GrantChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_supplier",
+			VaultID:      "vault_supplier",
+			Permission:   channel_domain.PermChannelAdmin,
+		},
+	)
+
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+	require.Empty(t, bus.publishedPermissionGrantedEvents)
+}
+
+func TestRevokeChannelPermissionUsecase_OverridesRoleTemplate(t *testing.T) {
+	repo, channel := permissionedChannelRepo(t)
+	bus := &channelEventBusMock{}
+	revoke := channelconfigusecases.NewRevokeChannelPermissionUsecase(repo, bus)
+
+	_, err := revoke.Execute(context.Background(), &channel_application.RevokeChannelPermissionRequest{
+		ChannelID:    "channel-001",
+		ActorVaultID: "vault_owner",
+		VaultID:      "vault_supplier",
+		Permission:   channel_domain.ThreadAppendPermission("invoice.created"),
+	})
+	require.NoError(t, err)
+	require.Len(t, bus.publishedPermissionRevokedEvents, 1)
+	require.ErrorIs(t,
+		channel_domain.NewPolicyEvaluator().RequirePermission(channel, "vault_supplier", channel_domain.ThreadAppendPermission("invoice.created")),
+		channel_domain.ErrPermissionDenied,
+	)
+
+	getPerms := channelconfigusecases.NewGetParticipantPermissionsUsecase(repo)
+	perms, err := getPerms.Execute(
+		context.Background(),
+		&channel_application.GetParticipantPermissionsRequest{ChannelID: "channel-001", ActorVaultID: "vault_owner", VaultID: "vault_supplier"},
+	)
+	require.NoError(t, err)
+	require.True(t, perms.Enforced)
+	require.Equal(t, []string{"supplier"}, perms.Roles)
+	require.Empty(t, perms.Permissions)
+
+	_, err = getPerms.Execute(
+		context.Background(),
+		&channel_application.GetParticipantPermissionsRequest{ChannelID: "channel-001", ActorVaultID: "vault_stranger", VaultID: "vault_supplier"},
+	)
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied, "outsiders cannot read a channel's permissions")
+}
+
+func TestRevokeChannelPermissionUsecase_RefusesLastAdmin(t *testing.T) {
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions.Roles = map[string][]string{"buyer": {channel_domain.PermThreadCreate}}
+	doc.Permissions.Grants = map[string][]string{"vault_owner": {channel_domain.PermChannelAdmin}}
+	repo, _ := governedChannelRepo(t, doc)
+	bus := &channelEventBusMock{}
+
+	_, err := channelconfigusecases.NewRevokeChannelPermissionUsecase(repo, bus).Execute(
+		context.Background(),
+		&channel_application.RevokeChannelPermissionRequest{
+			ChannelID:    "channel-001",
+			ActorVaultID: "vault_owner",
+			VaultID:      "vault_owner",
+			Permission:   channel_domain.PermChannelAdmin,
+		},
+	)
+
+	require.ErrorIs(t, err, channel_domain.ErrPolicyInvalid)
+	require.Empty(t, bus.publishedPermissionRevokedEvents)
+}
+
+func TestUpdateChannelUsecase_WithPolicyRequiresAdmin(t *testing.T) {
+	repo, _ := permissionedChannelRepo(t)
+	update := channel_usecase.NewUpdateChannelUsecase(repo).WithPolicy(channel_domain.NewPolicyEvaluator())
+
+	_, err := update.Execute(context.Background(), &channel_application.UpdateChannelRequest{
+		ChannelID:    "channel-001",
+		ActorVaultID: "vault_supplier",
+		Title:        "Renamed",
+	})
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+
+	_, err = update.Execute(context.Background(), &channel_application.UpdateChannelRequest{
+		ChannelID:    "channel-001",
+		ActorVaultID: "vault_owner",
+		Title:        "Renamed",
+	})
+	require.NoError(t, err)
+}
+
+func TestChannelSlotUsecase_CountsParticipantRecordPermissions(t *testing.T) {
+	repo, channel := permissionedChannelRepo(t)
+	channel.Status = channel_domain.StatusActive
+	repo.listParticipantsFn = func(ctx context.Context, req *channel_domain.ListParticipantsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+		return &tracecore_types.CloudResponse[[]channel_domain.Participant]{Data: []channel_domain.Participant{
+			{ChannelID: req.ChannelID, VaultID: "vault_delegate", Permissions: []string{channel_domain.PermChannelAdmin}},
+		}}, nil
+	}
+	slots := channel_slot_usecases.NewChannelSlotUsecase(repo)
+	slot := channel_domain.Slot{ID: "slot-003", Role: "auditor"}
+
+	_, err := slots.AddSlot(context.Background(), channel_application.AddSlotRequest{ChannelID: "channel-001", Slot: slot, ActorVaultID: "vault_supplier"})
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied, "the gate is on by default")
+
+	_, err = slots.AddSlot(context.Background(), channel_application.AddSlotRequest{ChannelID: "channel-001", Slot: slot, ActorVaultID: "vault_delegate"})
+	require.NoError(t, err)
+}
+
+func TestGetChannelUsecase_HidesPermissionedChannelsFromOutsiders(t *testing.T) {
+	repo, _ := permissionedChannelRepo(t)
+	get := channel_usecase.NewGetChannelUsecase(repo).WithPolicy(channel_domain.NewPolicyEvaluator())
+
+	channel, err := get.Execute(context.Background(), &channel_application.GetChannelRequest{ChannelID: "channel-001", ActorVaultID: "vault_supplier"})
+	require.NoError(t, err)
+	require.Equal(t, "channel-001", channel.ID)
+
+	_, err = get.Execute(context.Background(), &channel_application.GetChannelRequest{ChannelID: "channel-001", ActorVaultID: "vault_stranger"})
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+}
@@ -0,0 +1,178 @@
+package channel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_application "vault-app/internal/channel/application"
+	channelconfigusecases "vault-app/internal/channel/application/channel_config-usecases"
+	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+func governedChannelRepo(t *testing.T, doc channel_domain.PolicyDocument) (*channelRepositoryMock, *channel_domain.Channel) {
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+
+	channel := &channel_domain.Channel{
+		ID:    "channel-001",
+		Title: "Supplier onboarding",
+		Slots: []channel_domain.Slot{
+			{ID: "slot-001", Role: "buyer", VaultID: "vault_owner"},
+			{ID: "slot-002", Role: "supplier", VaultID: "vault_supplier"},
+		},
+		Policy: policy,
+	}
+
+	repo := &channelRepositoryMock{
+		getFn: func(
+			ctx context.Context,
+			req *channel_domain.GetChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			require.Equal(t, "channel-001", req.ChannelID)
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: *channel}, nil
+		},
+		updateFn: func(
+			ctx context.Context,
+			req *channel_domain.UpdateChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			*channel = req.Channel
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: req.Channel}, nil
+		},
+	}
+
+	return repo, channel
+}
+
+func TestGetChannelPolicyUsecase_MigratesLegacyPolicy(t *testing.T) {
+	repo := &channelRepositoryMock{
+		getFn: func(
+			ctx context.Context,
+			req *channel_domain.GetChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{
+				Data: channel_domain.Channel{
+					ID:     req.ChannelID,
+					Policy: channel_domain.Policy{channel_domain.LegacyPolicyAllowThreadReopen: true},
+				},
+			}, nil
+		},
+	}
+
+	doc, err := channelconfigusecases.NewGetChannelPolicyUsecase(repo).Execute(
+		context.Background(),
+		&channel_application.GetChannelPolicyRequest{ChannelID: "channel-001"},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.PolicySchema, doc.Schema)
+	require.True(t, doc.AllowThreadReopen)
+}
+
+func TestUpdateChannelPolicyUsecase_BumpsRevision(t *testing.T) {
+	repo, channel := governedChannelRepo(t, channel_domain.DefaultPolicy())
+
+	next := channel_domain.PolicyDocument{
+		Invite:       channel_domain.InvitePolicy{Roles: []string{"buyer"}},
+		ThreadEvents: map[string][]string{"supplier": {"invoice.created"}},
+	}
+
+	doc, err := channelconfigusecases.NewUpdateChannelPolicyUsecase(repo).Execute(
+		context.Background(),
+		&channel_application.UpdateChannelPolicyRequest{ChannelID: "channel-001", Policy: next},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, doc.Revision)
+	require.Equal(t, channel_domain.PolicySchemaVersion, doc.Version)
+
+	stored, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+	require.Equal(t, *doc, stored)
+}
+
+func TestUpdateChannelPolicyUsecase_KeepsUnknownKeys(t *testing.T) {
+	repo, channel := governedChannelRepo(t, channel_domain.DefaultPolicy())
+	channel.Policy["ui.color"] = "teal"
+
+	_, err := channelconfigusecases.NewUpdateChannelPolicyUsecase(repo).Execute(
+		context.Background(),
+		&channel_application.UpdateChannelPolicyRequest{
+			ChannelID: "channel-001",
+			Policy:    channel_domain.PolicyDocument{AllowThreadReopen: true},
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, "teal", channel.Policy["ui.color"])
+	require.Equal(t, true, channel.Policy["allow_thread_reopen"])
+}
+
+func TestUpdateChannelPolicyUsecase_RevisionConflict(t *testing.T) {
+	current := channel_domain.DefaultPolicy()
+	current.Revision = 4
+	repo, _ := governedChannelRepo(t, current)
+	repo.updateFn = func(
+		ctx context.Context,
+		req *channel_domain.UpdateChannelRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+		t.Fatal("UpdateChannel must not be called on a stale revision")
+		return nil, nil
+	}
+
+	_, err := channelconfigusecases.NewUpdateChannelPolicyUsecase(repo).Execute(
+		context.Background(),
+		&channel_application.UpdateChannelPolicyRequest{
+			ChannelID:        "channel-001",
+			Policy:           channel_domain.DefaultPolicy(),
+			ExpectedRevision: 3,
+		},
+	)
+
+	require.ErrorIs(t, err, channel_domain.ErrPolicyRevisionConflict)
+}
+
+func TestUpdateChannelPolicyUsecase_RejectsUnknownRole(t *testing.T) {
+	repo, _ := governedChannelRepo(t, channel_domain.DefaultPolicy())
+
+	_, err := channelconfigusecases.NewUpdateChannelPolicyUsecase(repo).Execute(
+		context.Background(),
+		&channel_application.UpdateChannelPolicyRequest{
+			ChannelID: "channel-001",
+			Policy:    channel_domain.PolicyDocument{Invite: channel_domain.InvitePolicy{Roles: []string{"auditor"}}},
+		},
+	)
+
+	require.ErrorIs(t, err, channel_domain.ErrPolicyUnknownRole)
+}
+
+func TestInviteToChannelUsecase_PolicyDeniesInviter(t *testing.T) {
+	doc := channel_domain.DefaultPolicy()
+	doc.Invite.Roles = []string{"buyer"}
+	repo, _ := governedChannelRepo(t, doc)
+	repo.inviteToChannelFn = func(
+		ctx context.Context,
+		req *channel_domain.InviteToChannelRequest,
+	) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+		require.Equal(t, "vault_owner", req.InviterVaultID)
+		return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+			Data: channel_domain.Invitation{ID: "inv-001", ChannelID: req.ChannelID},
+		}, nil
+	}
+
+	uc := channel_usecase.NewInviteToChannelUsecase(repo).WithPolicy(channel_domain.NewPolicyEvaluator())
+
+	_, err := uc.Execute(context.Background(), &channel_application.InviteToChannelRequest{
+		ChannelID:      "channel-001",
+		InviterVaultID: "vault_supplier",
+		InviteeVaultID: "vault_external",
+	})
+	require.ErrorIs(t, err, channel_domain.ErrPolicyDenied)
+
+	invitation, err := uc.Execute(context.Background(), validInviteToChannelRequest())
+	require.NoError(t, err)
+	require.Equal(t, "inv-001", invitation.ID)
+}
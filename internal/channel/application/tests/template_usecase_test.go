@@ -0,0 +1,175 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_application "vault-app/internal/channel/application"
+	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_template_usecases "vault-app/internal/channel/application/channel_template_usecases"
+	channel_domain "vault-app/internal/channel/domain"
+	channel_templates "vault-app/internal/channel/infrastructure/templates"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// cloudTemplatesStub stands in for the Cloud channel template registry.
+type cloudTemplatesStub struct {
+	templates map[string]channel_domain.ChannelTemplate
+	err       error
+}
+
+func (s *cloudTemplatesStub) GetChannelTemplate(ctx context.Context, templateID string) (*channel_domain.ChannelTemplate, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	t, ok := s.templates[templateID]
+	if !ok {
+		return nil, channel_domain.ErrTemplateNotFound
+	}
+	return &t, nil
+}
+
+func (s *cloudTemplatesStub) ListChannelTemplates(ctx context.Context) ([]channel_domain.ChannelTemplate, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	templates := []channel_domain.ChannelTemplate{}
+	for _, t := range s.templates {
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func procurementTemplate(t *testing.T) channel_domain.ChannelTemplate {
+	for _, tpl := range channel_templates.BuiltinTemplates() {
+		if tpl.ID == "procurement" {
+			return tpl
+		}
+	}
+	t.Fatal("procurement template is not built in")
+	return channel_domain.ChannelTemplate{}
+}
+
+func TestBuiltinChannelTemplates_AreValid(t *testing.T) {
+	for _, tpl := range channel_templates.BuiltinTemplates() {
+		require.NoError(t, tpl.Validate(), tpl.ID)
+	}
+}
+
+func TestChannelTemplateRegistry_PrefersNewestAndFallsBackOffline(t *testing.T) {
+	ctx := context.Background()
+	v2 := procurementTemplate(t)
+	v2.Version = 2
+	v2.Title = "Procurement v2"
+	invalid := channel_domain.ChannelTemplate{ID: "broken", Version: 1}
+
+	cloud := &cloudTemplatesStub{templates: map[string]channel_domain.ChannelTemplate{"procurement": v2, "broken": invalid}}
+	registry := channel_templates.NewRegistry(cloud, channel_templates.BuiltinTemplates()...)
+
+	tpl, err := registry.GetChannelTemplate(ctx, "procurement")
+	require.NoError(t, err)
+	require.Equal(t, 2, tpl.Version)
+
+	_, err = registry.GetChannelTemplate(ctx, "broken")
+	require.ErrorIs(t, err, channel_domain.ErrTemplateInvalid)
+
+	list, err := registry.ListChannelTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "invalid Cloud templates are not listed")
+	require.Equal(t, "Procurement v2", list[0].Title)
+
+	cloud.err = errors.New("offline")
+	tpl, err = registry.GetChannelTemplate(ctx, "procurement")
+	require.NoError(t, err)
+	require.Equal(t, 1, tpl.Version, "the built-in blueprint serves offline")
+
+	_, err = registry.GetChannelTemplate(ctx, "unknown")
+	require.ErrorIs(t, err, channel_domain.ErrTemplateNotFound)
+}
+
+func TestInstantiateChannelTemplateUsecase_CreatesChannel(t *testing.T) {
+	var created channel_domain.Channel
+	repo := &channelRepositoryMock{
+		createFn: func(
+			ctx context.Context,
+			req *channel_domain.CreateChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			created = req.Channel
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: req.Channel}, nil
+		},
+	}
+	bus := &channelEventBusMock{}
+	registry := channel_templates.NewRegistry(nil, channel_templates.BuiltinTemplates()...)
+	uc := channel_template_usecases.NewInstantiateChannelTemplateUsecase(registry, channel_usecase.NewCreateChannelUsecase(repo, bus))
+
+	_, err := uc.Execute(context.Background(), &channel_application.InstantiateChannelTemplateRequest{
+		TemplateID:  "procurement",
+		WorkspaceID: "workspace-001",
+		Properties:  []channel_domain.ChannelProperty{{Key: "purchase_order", Value: "PO-7"}},
+	})
+	require.ErrorIs(t, err, channel_domain.ErrTemplatePropertyMissing)
+	require.Empty(t, bus.publishedCreatedEvents)
+
+	channel, err := uc.Execute(context.Background(), &channel_application.InstantiateChannelTemplateRequest{
+		TemplateID:  "procurement",
+		WorkspaceID: "workspace-001",
+		Assignments: []channel_domain.Assignment{{SlotID: "buyer", OwnerID: "vault_owner"}},
+		Properties: []channel_domain.ChannelProperty{
+			{Key: "purchase_order", Value: "PO-7"},
+			{Key: "currency", Value: "EUR"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Procurement", channel.Title)
+	require.Len(t, created.Slots, 4)
+	require.Len(t, created.GetGatedSlots(), 2)
+	require.Equal(t, 1, channel_domain.TemplateVersionOf(&created))
+	require.Len(t, bus.publishedCreatedEvents, 1)
+}
+
+func TestMigrateChannelTemplateUsecase_PersistsMigration(t *testing.T) {
+	v1 := procurementTemplate(t)
+	channel, err := v1.Instantiate("", "workspace-001", nil, []channel_domain.ChannelProperty{
+		{Key: "purchase_order", Value: "PO-7"},
+		{Key: "currency", Value: "EUR"},
+	})
+	require.NoError(t, err)
+
+	updates := 0
+	repo := &channelRepositoryMock{
+		getFn: func(
+			ctx context.Context,
+			req *channel_domain.GetChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: channel}, nil
+		},
+		updateFn: func(
+			ctx context.Context,
+			req *channel_domain.UpdateChannelRequest,
+		) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+			updates++
+			channel = req.Channel
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: req.Channel}, nil
+		},
+	}
+
+	v2 := procurementTemplate(t)
+	v2.Version = 2
+	v2.Slots = append(v2.Slots, channel_domain.Slot{ID: "logistics", Name: "Logistics", Role: "logistics", Order: 5})
+	registry := channel_templates.NewRegistry(&cloudTemplatesStub{templates: map[string]channel_domain.ChannelTemplate{"procurement": v2}})
+	uc := channel_template_usecases.NewMigrateChannelTemplateUsecase(repo, registry).WithPolicy(channel_domain.NewPolicyEvaluator())
+
+	result, err := uc.Execute(context.Background(), &channel_application.MigrateChannelTemplateRequest{ChannelID: channel.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"logistics"}, result.Migration.AddedSlots)
+	require.Equal(t, 2, channel_domain.TemplateVersionOf(result.Channel))
+	require.Equal(t, 1, updates)
+
+	result, err = uc.Execute(context.Background(), &channel_application.MigrateChannelTemplateRequest{ChannelID: channel.ID})
+	require.NoError(t, err)
+	require.False(t, result.Migration.Changed())
+	require.Equal(t, 1, updates, "an up-to-date channel is not written")
+}
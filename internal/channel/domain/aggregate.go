@@ -74,8 +74,13 @@ func NewChannel(tplID string, title string, workspaceID string) Channel {
 
 type TrustState string
 
+// Trust states of a RemoteVault: pending → trusted → suspended → revoked.
+// Only trusted remotes receive sync items.
 var (
-	Trusted = "trusted"
+	Trusted        = "trusted"
+	TrustPending   = "pending"
+	TrustSuspended = "suspended"
+	TrustRevoked   = "revoked"
 )
 
 type FederationSnapshot struct {
@@ -91,6 +96,15 @@ type RemoteVault struct {
 	TrustState      TrustState        `json:"trust_state"`
 	Pending         []PendingSyncItem `json:"pending"`
 	ProtocolVersion string            `json:"protocol_version"`
+	// PublicKey is the pinned Stellar key of the remote; PresentedKey is a
+	// key seen in a handshake and awaiting approval.
+	PublicKey      string    `json:"public_key,omitempty"`
+	PresentedKey   string    `json:"presented_key,omitempty"`
+	TrustChangedAt time.Time `json:"trust_changed_at,omitempty"`
+	TrustReason    string    `json:"trust_reason,omitempty"`
+	// IdentityIssuedAt is the IssuedAt of the newest identity document
+	// recorded; older or equal documents are replays.
+	IdentityIssuedAt time.Time `json:"identity_issued_at,omitempty"`
 }
 
 type SyncStatus string
@@ -105,11 +119,13 @@ const (
 )
 
 type PendingSyncItem struct {
-	ExchangeID string     `json:"exchange_id"`
-	Status     SyncStatus `json:"status"`
-	Cursor     uint64     `json:"cursor"`
-	RetryCount int        `json:"retry_count"`
-	UpdatedAt  time.Time  `json:"updatedAt"`
+	ExchangeID    string     `json:"exchange_id"`
+	Status        SyncStatus `json:"status"`
+	Cursor        uint64     `json:"cursor"`
+	RetryCount    int        `json:"retry_count"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	NextAttemptAt time.Time  `json:"next_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
 }
 
 type Participant struct {
@@ -132,8 +148,13 @@ const (
 	InvitationStatusAccepted InvitationStatus = "accepted"
 	InvitationStatusRejected InvitationStatus = "rejected"
 	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
 )
 
+// DefaultInvitationTTL is how long a pending invitation stays valid when the
+// application does not configure a TTL.
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
 // Invitation mirrors the Cloud Invitation aggregate. The Cloud is the single
 // source of truth: the Desktop never fabricates an invitation and never mutates
 // its lifecycle locally. An invitation carries no slot or role information; role
@@ -146,6 +167,32 @@ type Invitation struct {
 	Status         InvitationStatus `json:"status"`
 	CreatedAt      time.Time        `json:"created_at"`
 	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	ExpiresAt      *time.Time       `json:"expires_at,omitempty"`
+}
+
+// IsExpired reports whether a pending invitation is past its expiry. When the
+// Cloud recorded no expiry, ttl is applied to CreatedAt; a zero ttl never
+// expires such an invitation.
+func (i Invitation) IsExpired(now time.Time, ttl time.Duration) bool {
+	if i.Status != InvitationStatusPending {
+		return false
+	}
+	if i.ExpiresAt != nil {
+		return !now.Before(*i.ExpiresAt)
+	}
+	if ttl <= 0 || i.CreatedAt.IsZero() {
+		return false
+	}
+	return !now.Before(i.CreatedAt.Add(ttl))
+}
+
+// EffectiveStatus is the status the Desktop presents: a pending invitation
+// past its expiry reads as expired until the Cloud records the transition.
+func (i Invitation) EffectiveStatus(now time.Time, ttl time.Duration) InvitationStatus {
+	if i.IsExpired(now, ttl) {
+		return InvitationStatusExpired
+	}
+	return i.Status
 }
 
 // ==============================================================================
@@ -169,6 +216,52 @@ func (c *Channel) Archive() error {
 	return nil
 }
 
+// WorkspaceArchivedProperty marks a channel archived because its workspace
+// was; restoring the workspace only reactivates channels carrying it.
+const WorkspaceArchivedProperty = "workspace.archived"
+
+// ArchiveWithWorkspace archives an active channel as part of archiving its
+// workspace. It reports false for channels that are not active, which keep
+// their status.
+func (c *Channel) ArchiveWithWorkspace() bool {
+	if c.Archive() != nil {
+		return false
+	}
+	c.AddChannelProperty(ChannelProperty{Key: WorkspaceArchivedProperty, Value: c.WorkspaceID})
+	return true
+}
+
+// RestoreWithWorkspace reactivates a channel archived by ArchiveWithWorkspace.
+// Channels archived on their own stay archived.
+func (c *Channel) RestoreWithWorkspace() bool {
+	if c.Status != StatusArchived {
+		return false
+	}
+	if _, cascaded := c.Property(WorkspaceArchivedProperty); !cascaded {
+		return false
+	}
+
+	c.RemoveChannelProperty(WorkspaceArchivedProperty)
+	c.Status = StatusActive
+	c.ArchivedAt = nil
+	c.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// MoveToWorkspace re-homes a modifiable channel.
+func (c *Channel) MoveToWorkspace(workspaceID string) error {
+	if workspaceID == "" {
+		return ErrWorkspaceIDRequired
+	}
+	if !c.canModify() {
+		return ErrChannelNotModifiable
+	}
+
+	c.WorkspaceID = workspaceID
+	c.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
 // ==============================================================================
 // Slot CRUD
 // ==============================================================================
@@ -11,6 +11,7 @@ var (
 	ErrChannelIDRequired         = errors.New("channel id is required")
 	ErrChannelNameRequired       = errors.New("channel name is required")
 	ErrVaultIDRequired           = errors.New("vault id is required")
+	ErrUserIDRequired            = errors.New("user id is required")
 	ErrChannelBusRequired        = errors.New("Event bus is nil")
 	ErrRequestRequired           = errors.New("Request is nil")
 	ErrRepositoryResponse        = errors.New("channel repository returned nil response")
@@ -29,5 +30,56 @@ var (
 	ErrAssignmentRepositoryRequired = errors.New("assignment repository is required")
 
 	ErrInvitationIDRequired     = errors.New("invitation id is required")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationExpired        = errors.New("invitation has expired")
 	ErrInviteePublicKeyRequired = errors.New("invitee public key is required")
+	ErrInvitationDirection      = errors.New("invitation direction must be received or sent")
+
+	ErrPolicyInvalid             = errors.New("channel policy is invalid")
+	ErrPolicyVersionUnsupported  = errors.New("channel policy version is not supported")
+	ErrPolicyUnknownRole         = errors.New("channel policy names a role with no slot")
+	ErrPolicyRevisionConflict    = errors.New("channel policy was updated concurrently")
+	ErrPolicyDenied              = errors.New("channel policy denies the action")
+	ErrPolicyApprovalsMissing    = errors.New("channel policy approvals are missing")
+	ErrPolicyAssetTypeNotAllowed = errors.New("channel policy does not allow the asset type")
+	ErrPolicyChannelExpired      = errors.New("channel has expired")
+	ErrPolicyRetentionElapsed    = errors.New("thread history is past the channel retention window")
+	ErrPermissionDenied          = errors.New("participant lacks the permission")
+	ErrPermissionUnknown         = errors.New("permission is not known")
+
+	ErrRemoteVaultNotFound     = errors.New("remote vault not found")
+	ErrRemoteVaultNotTrusted   = errors.New("remote vault is not trusted")
+	ErrRemoteEndpointRequired  = errors.New("remote vault endpoint is required")
+	ErrSyncItemNotFound        = errors.New("sync item not found")
+	ErrExchangeIDRequired      = errors.New("exchange id is required")
+	ErrSyncItemNotDeadLettered = errors.New("sync item is not dead-lettered")
+	ErrSyncAckTimeout          = errors.New("sync item ack timed out")
+	ErrExchangeNotFound        = errors.New("exchange not found")
+	ErrRemoteVaultRevoked      = errors.New("remote vault trust is revoked")
+	ErrIdentityDocumentInvalid = errors.New("identity document is invalid")
+	ErrIdentitySignature       = errors.New("identity document signature does not verify")
+	ErrIdentityReplayed        = errors.New("identity document is not newer than the one on record")
+	ErrRemoteKeyRequired       = errors.New("remote vault has not presented a key")
+	ErrRemoteKeyMismatch       = errors.New("remote vault key does not match the approved key")
+	ErrTrustTransition         = errors.New("remote vault trust transition is not allowed")
+
+	ErrTemplateIDRequired         = errors.New("channel template id is required")
+	ErrTemplateNotFound           = errors.New("channel template not found")
+	ErrTemplateInvalid            = errors.New("channel template is invalid")
+	ErrTemplateMismatch           = errors.New("channel does not match its template")
+	ErrTemplatePropertyMissing    = errors.New("channel template requires the property")
+	ErrTemplateVersionUnsupported = errors.New("channel template version is not supported")
+	ErrTemplateMigrationConflict  = errors.New("channel template migration conflicts with the channel")
+
+	ErrSlotNotGated               = errors.New("slot is not gated")
+	ErrSlotAlreadyFilled          = errors.New("slot is already filled")
+	ErrOccupancyCandidateRequired = errors.New("occupancy candidate vault is required")
+	ErrOccupancyNoApprovers       = errors.New("no participant can approve the occupancy request")
+	ErrOccupancyRequestNotFound   = errors.New("occupancy request not found")
+	ErrOccupancyRequestPending    = errors.New("slot already has a pending occupancy request")
+	ErrOccupancyNotPending        = errors.New("occupancy request is no longer pending")
+	ErrOccupancyNotApprover       = errors.New("vault may not approve the occupancy request")
+	ErrOccupancyAlreadyVoted      = errors.New("vault already voted on the occupancy request")
+	ErrOccupancyVoteInvalid       = errors.New("occupancy vote is invalid")
+	ErrOccupancySignature         = errors.New("occupancy vote signature does not verify")
 )
@@ -0,0 +1,59 @@
+package channel_domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// ==============================================================================
+// Channel event log
+// ==============================================================================
+//
+// The Cloud keeps an append-only event log per channel for the decisions
+// taken in it, so every participant's vault replays the same history: gated
+// slot occupancy requests and their signed votes, and permission grants and
+// revocations.
+
+// Channel event types.
+const (
+	ChannelEventOccupancyRequested = "slot.occupancy_requested"
+	ChannelEventOccupancyVoted     = "slot.occupancy_voted"
+	ChannelEventOccupancyWithdrawn = "slot.occupancy_withdrawn"
+	ChannelEventPermissionGranted  = "permission.granted"
+	ChannelEventPermissionRevoked  = "permission.revoked"
+)
+
+type ChannelEvent struct {
+	ID             string          `json:"id"`
+	ChannelID      string          `json:"channel_id"`
+	Type           string          `json:"type"`
+	ActorVaultID   string          `json:"actor_vault_id,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	Cursor         uint64          `json:"cursor"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// AppendChannelEventRequest appends one event. The Cloud dedupes appends
+// carrying an IdempotencyKey it has already seen for the channel.
+type AppendChannelEventRequest struct {
+	ChannelID      string
+	Type           string
+	ActorVaultID   string
+	Payload        json.RawMessage
+	IdempotencyKey string
+}
+
+type ListChannelEventsRequest struct {
+	ChannelID string
+}
+
+// ChannelEventLog appends to and lists a channel's event log
+// (POST/GET /channels/{id}/events). Events are listed in cursor order.
+type ChannelEventLog interface {
+	AppendChannelEvent(ctx context.Context, req *AppendChannelEventRequest) (*tracecore_types.CloudResponse[ChannelEvent], error)
+	ListChannelEvents(ctx context.Context, req *ListChannelEventsRequest) (*tracecore_types.CloudResponse[[]ChannelEvent], error)
+}
@@ -9,6 +9,13 @@ const (
 	EventChannelRevoked  = "channel.revoked"
 	EventChannelDeleted  = "channel.deleted"
 	EventChannelArchived = "channel.archived"
+
+	EventChannelPermissionGranted = "channel.permission.granted"
+	EventChannelPermissionRevoked = "channel.permission.revoked"
+
+	EventSlotOccupancyRequested = "channel.slot.occupancy_requested"
+	EventSlotOccupancyVoted     = "channel.slot.occupancy_voted"
+	EventSlotFilled             = "channel.slot.filled"
 )
 
 type ChannelCreated struct {
@@ -61,4 +68,93 @@ type ChannelArchived struct {
 
 func (ChannelArchived) EventType() string {
 	return EventChannelArchived
-}
\ No newline at end of file
+}
+
+// ChannelPermissionGranted records that ActorVaultID gave VaultID a
+// permission. It is the audit trail of permission changes.
+type ChannelPermissionGranted struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID    string
+	ActorVaultID string
+	VaultID      string
+	Permission   string
+	Reason       string
+	// PolicyRevision is the policy revision the grant produced.
+	PolicyRevision int
+}
+
+func (ChannelPermissionGranted) EventType() string {
+	return EventChannelPermissionGranted
+}
+
+// ChannelPermissionRevoked records that ActorVaultID took a permission from
+// VaultID.
+type ChannelPermissionRevoked struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID      string
+	ActorVaultID   string
+	VaultID        string
+	Permission     string
+	Reason         string
+	PolicyRevision int
+}
+
+func (ChannelPermissionRevoked) EventType() string {
+	return EventChannelPermissionRevoked
+}
+
+// SlotOccupancyRequested announces a request to fill a gated slot to the
+// approvers that must decide on it.
+type SlotOccupancyRequested struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID        string
+	RequestID        string
+	SlotID           string
+	CandidateVaultID string
+	RequestedBy      string
+	Required         int
+	Approvers        []string
+}
+
+func (SlotOccupancyRequested) EventType() string {
+	return EventSlotOccupancyRequested
+}
+
+// SlotOccupancyVoted records one signed approval or rejection. Status is the
+// request status the vote produced.
+type SlotOccupancyVoted struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID string
+	Vote      OccupancyVote
+	Status    OccupancyStatus
+	Approvals int
+	Required  int
+	// PendingApprovers still have to vote while Status is pending.
+	PendingApprovers []string
+}
+
+func (SlotOccupancyVoted) EventType() string {
+	return EventSlotOccupancyVoted
+}
+
+// SlotFilled records the assignment an approved occupancy request created.
+type SlotFilled struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID  string
+	RequestID  string
+	Assignment Assignment
+}
+
+func (SlotFilled) EventType() string {
+	return EventSlotFilled
+}
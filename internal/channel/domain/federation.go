@@ -0,0 +1,158 @@
+package channel_domain
+
+import (
+	"time"
+)
+
+// ==============================================================================
+// Federation sync
+// ==============================================================================
+
+// RetryPolicy drives the redelivery of PendingSyncItem. Each failed attempt
+// waits BaseDelay doubled per retry, capped at MaxDelay. An item that failed
+// MaxAttempts times moves to dead-letter.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// AckTimeout is how long an item may wait for an ack before the attempt
+	// counts as failed.
+	AckTimeout time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   5 * time.Second,
+		MaxDelay:    30 * time.Minute,
+		AckTimeout:  2 * time.Minute,
+	}
+}
+
+// Backoff returns the delay before the next attempt after retryCount failures.
+func (p RetryPolicy) Backoff(retryCount int) time.Duration {
+	if retryCount < 1 {
+		retryCount = 1
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retryCount && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+func (r RemoteVault) IsTrusted() bool {
+	return r.TrustState == TrustState(Trusted)
+}
+
+// Due reports whether the item should be sent now: new items, failed items
+// whose backoff elapsed, and in-flight items whose ack timed out.
+func (i PendingSyncItem) Due(now time.Time, policy RetryPolicy) bool {
+	switch i.Status {
+	case SyncPending, "":
+		return true
+	case SyncFailed:
+		return !now.Before(i.NextAttemptAt)
+	case SyncSending, SyncWaitingAck:
+		return policy.AckTimeout > 0 && !now.Before(i.UpdatedAt.Add(policy.AckTimeout))
+	}
+
+	return false
+}
+
+// AwaitingAck reports whether the item was sent and has not been acked yet.
+func (i PendingSyncItem) AwaitingAck() bool {
+	return i.Status == SyncSending || i.Status == SyncWaitingAck
+}
+
+func (i *PendingSyncItem) MarkSending(now time.Time) {
+	i.Status = SyncSending
+	i.UpdatedAt = now
+}
+
+func (i *PendingSyncItem) MarkWaitingAck(now time.Time) {
+	i.Status = SyncWaitingAck
+	i.UpdatedAt = now
+}
+
+func (i *PendingSyncItem) MarkCompleted(now time.Time) {
+	i.Status = SyncCompleted
+	i.LastError = ""
+	i.UpdatedAt = now
+}
+
+// MarkFailed records a failed attempt and schedules the retry, or moves the
+// item to dead-letter once the policy's attempts are exhausted.
+func (i *PendingSyncItem) MarkFailed(now time.Time, cause error, policy RetryPolicy) {
+	i.RetryCount++
+	if cause != nil {
+		i.LastError = cause.Error()
+	}
+	i.UpdatedAt = now
+
+	if policy.MaxAttempts > 0 && i.RetryCount >= policy.MaxAttempts {
+		i.Status = SyncDeadLetter
+		i.NextAttemptAt = time.Time{}
+		return
+	}
+
+	i.Status = SyncFailed
+	i.NextAttemptAt = now.Add(policy.Backoff(i.RetryCount))
+}
+
+// MarkAckTimedOut counts an attempt whose ack never came as failed. The ack
+// timeout already spaced the attempts out, so the item stays due at once
+// unless it exhausted the policy's attempts.
+func (i *PendingSyncItem) MarkAckTimedOut(now time.Time, policy RetryPolicy) {
+	i.MarkFailed(now, ErrSyncAckTimeout, policy)
+	if i.Status == SyncFailed {
+		i.NextAttemptAt = now
+	}
+}
+
+// Requeue returns a dead-lettered item to the queue with a fresh retry budget.
+func (i *PendingSyncItem) Requeue(now time.Time) error {
+	if i.Status != SyncDeadLetter {
+		return ErrSyncItemNotDeadLettered
+	}
+
+	i.Status = SyncPending
+	i.RetryCount = 0
+	i.LastError = ""
+	i.NextAttemptAt = time.Time{}
+	i.UpdatedAt = now
+
+	return nil
+}
+
+// AdvanceCursor moves the acknowledged cursor forward; an older cursor from a
+// late or duplicated ack is ignored.
+func (r *RemoteVault) AdvanceCursor(cursor uint64, now time.Time) bool {
+	r.LastSeen = now
+	if cursor <= r.LastCursor {
+		return false
+	}
+
+	r.LastCursor = cursor
+	return true
+}
+
+// PruneCompleted drops acknowledged items and returns how many were removed.
+func (r *RemoteVault) PruneCompleted() int {
+	kept := r.Pending[:0]
+	for _, item := range r.Pending {
+		if item.Status != SyncCompleted {
+			kept = append(kept, item)
+		}
+	}
+
+	removed := len(r.Pending) - len(kept)
+	r.Pending = kept
+
+	return removed
+}
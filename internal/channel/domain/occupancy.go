@@ -0,0 +1,337 @@
+package channel_domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ==============================================================================
+// Gated slot occupancy
+// ==============================================================================
+//
+// A gated slot must be filled before threads can open in the channel. A vault
+// asks to occupy it, the vaults already in the channel approve it N-of-M as
+// the policy requires, and only then is the Assignment created. Every vote is
+// signed with the voter's Stellar key so the decision can be audited.
+
+type OccupancyStatus string
+
+const (
+	OccupancyPending   OccupancyStatus = "pending"
+	OccupancyApproved  OccupancyStatus = "approved"
+	OccupancyRejected  OccupancyStatus = "rejected"
+	OccupancyWithdrawn OccupancyStatus = "withdrawn"
+)
+
+// Vote decisions.
+const (
+	OccupancyVoteApprove = "approve"
+	OccupancyVoteReject  = "reject"
+)
+
+// Blocker kinds reported by ChannelBlockers.
+const (
+	BlockerChannelInactive   = "channel_inactive"
+	BlockerChannelExpired    = "channel_expired"
+	BlockerSlotUnfilled      = "slot_unfilled"
+	BlockerAwaitingApprovals = "awaiting_approvals"
+)
+
+type OccupancyRequest struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	SlotID    string `json:"slot_id"`
+
+	// The vault asking to occupy the slot, as it will be assigned.
+	CandidateVaultID      string `json:"candidate_vault_id"`
+	CandidatePublicKey    string `json:"candidate_public_key"`
+	CandidateVaultAddress string `json:"candidate_vault_address,omitempty"`
+
+	RequestedBy string          `json:"requested_by"`
+	RequestedAt time.Time       `json:"requested_at"`
+	Status      OccupancyStatus `json:"status"`
+	DecidedAt   *time.Time      `json:"decided_at,omitempty"`
+	// WithdrawnBy is the vault that withdrew the request, if any.
+	WithdrawnBy string `json:"withdrawn_by,omitempty"`
+
+	// Approvers are the M vaults eligible when the request was made and
+	// Required is N.
+	Approvers []string        `json:"approvers"`
+	Required  int             `json:"required"`
+	Votes     []OccupancyVote `json:"votes,omitempty"`
+}
+
+// OccupancyVote is one approver's signed decision.
+type OccupancyVote struct {
+	RequestID string    `json:"request_id"`
+	VaultID   string    `json:"vault_id"`
+	Decision  string    `json:"decision"`
+	Reason    string    `json:"reason,omitempty"`
+	PublicKey string    `json:"public_key"`
+	VotedAt   time.Time `json:"voted_at"`
+	// Signature is the base64 signature over SigningBytes.
+	Signature string `json:"signature,omitempty"`
+}
+
+// SigningBytes returns the bytes the signature covers: the vote with its
+// signature cleared, bound to the slot and candidate it decides on.
+func (v OccupancyVote) SigningBytes(r OccupancyRequest) ([]byte, error) {
+	v.Signature = ""
+	return json.Marshal(struct {
+		Vote             OccupancyVote `json:"vote"`
+		ChannelID        string        `json:"channel_id"`
+		SlotID           string        `json:"slot_id"`
+		CandidateVaultID string        `json:"candidate_vault_id"`
+	}{v, r.ChannelID, r.SlotID, r.CandidateVaultID})
+}
+
+// OccupancyVoteVerifier checks a vote read back from a shared log exactly as
+// it was checked when it was cast: signed with the key known for the voter.
+type OccupancyVoteVerifier interface {
+	VerifyOccupancyVote(ctx context.Context, c *Channel, r OccupancyRequest, v OccupancyVote) error
+}
+
+// OccupancyRequestRepository persists occupancy requests per channel.
+type OccupancyRequestRepository interface {
+	SaveOccupancyRequest(ctx context.Context, r OccupancyRequest) error
+	GetOccupancyRequest(ctx context.Context, requestID string) (*OccupancyRequest, error)
+	ListOccupancyRequests(ctx context.Context, channelID string) ([]OccupancyRequest, error)
+}
+
+// SlotApprovers returns the vaults that may approve filling a gated slot,
+// sorted, without exclude (the candidate never approves itself).
+func (c *Channel) SlotApprovers(p SlotApprovalPolicy, exclude string) []string {
+	vaults := []string{}
+	add := func(vaultID string) {
+		if vaultID == "" || vaultID == exclude || slices.Contains(vaults, vaultID) {
+			return
+		}
+		if len(p.ApproverRoles) > 0 && !anyMatches(c.RolesOf(vaultID), p.ApproverRoles, AnyRole) {
+			return
+		}
+		vaults = append(vaults, vaultID)
+	}
+
+	for _, slot := range c.Slots {
+		add(slot.VaultID)
+	}
+	for _, a := range c.Assignments {
+		add(a.OwnerID)
+	}
+
+	slices.Sort(vaults)
+	return vaults
+}
+
+// NewOccupancyRequest opens a request for candidate to fill a gated slot. It
+// fails when the slot is not gated or already filled, or when nobody in the
+// channel could approve it; such slots are assigned directly.
+func NewOccupancyRequest(c *Channel, policy PolicyDocument, id string, slotID string, candidate Assignment, requestedBy string, now time.Time) (OccupancyRequest, error) {
+	if slotID == "" {
+		return OccupancyRequest{}, ErrSlotIDRequired
+	}
+	if candidate.OwnerID == "" {
+		return OccupancyRequest{}, ErrOccupancyCandidateRequired
+	}
+	slot, ok := c.GetSlotByID(slotID)
+	if !ok {
+		return OccupancyRequest{}, ErrSlotNotFound
+	}
+	if !slot.Gated {
+		return OccupancyRequest{}, ErrSlotNotGated
+	}
+	if _, assigned := c.GetAssignmentBySlotID(slotID); assigned {
+		return OccupancyRequest{}, ErrSlotAlreadyFilled
+	}
+
+	approvers := c.SlotApprovers(policy.GatedSlots, candidate.OwnerID)
+	if len(approvers) == 0 {
+		return OccupancyRequest{}, ErrOccupancyNoApprovers
+	}
+
+	return OccupancyRequest{
+		ID:                    id,
+		ChannelID:             c.ID,
+		SlotID:                slotID,
+		CandidateVaultID:      candidate.OwnerID,
+		CandidatePublicKey:    candidate.PublicKey,
+		CandidateVaultAddress: candidate.VaultAddress,
+		RequestedBy:           requestedBy,
+		RequestedAt:           now,
+		Status:                OccupancyPending,
+		Approvers:             approvers,
+		Required:              policy.GatedSlots.RequiredApprovals(len(approvers)),
+	}, nil
+}
+
+// BoundTo limits a request read back from a shared log to what the
+// channel's policy allows, whatever the log claims: only the logged
+// approvers the policy still makes eligible may vote, and Required is never
+// below what the policy requires of them.
+func (r *OccupancyRequest) BoundTo(c *Channel, policy PolicyDocument) {
+	eligible := c.SlotApprovers(policy.GatedSlots, r.CandidateVaultID)
+	approvers := []string{}
+	for _, vaultID := range r.Approvers {
+		if slices.Contains(eligible, vaultID) && !slices.Contains(approvers, vaultID) {
+			approvers = append(approvers, vaultID)
+		}
+	}
+	slices.Sort(approvers)
+	r.Approvers = approvers
+	r.Required = max(r.Required, policy.GatedSlots.RequiredApprovals(len(approvers)))
+}
+
+// Assignment is what the request creates once approved.
+func (r OccupancyRequest) Assignment() Assignment {
+	return Assignment{
+		SlotID:       r.SlotID,
+		OwnerID:      r.CandidateVaultID,
+		PublicKey:    r.CandidatePublicKey,
+		VaultAddress: r.CandidateVaultAddress,
+	}
+}
+
+func (r OccupancyRequest) count(decision string) int {
+	n := 0
+	for _, v := range r.Votes {
+		if v.Decision == decision {
+			n++
+		}
+	}
+	return n
+}
+
+func (r OccupancyRequest) Approvals() int  { return r.count(OccupancyVoteApprove) }
+func (r OccupancyRequest) Rejections() int { return r.count(OccupancyVoteReject) }
+
+// PendingApprovers are the eligible approvers that have not voted yet.
+func (r OccupancyRequest) PendingApprovers() []string {
+	pending := []string{}
+	for _, vaultID := range r.Approvers {
+		if !slices.ContainsFunc(r.Votes, func(v OccupancyVote) bool { return v.VaultID == vaultID }) {
+			pending = append(pending, vaultID)
+		}
+	}
+	return pending
+}
+
+// Vote records a verified vote. The request is approved once Required
+// approvals are in, and rejected as soon as enough approvers refused that
+// Required can no longer be reached.
+func (r *OccupancyRequest) Vote(v OccupancyVote, now time.Time) error {
+	if r.Status != OccupancyPending {
+		return fmt.Errorf("%w: request is %s", ErrOccupancyNotPending, r.Status)
+	}
+	if v.Decision != OccupancyVoteApprove && v.Decision != OccupancyVoteReject {
+		return fmt.Errorf("%w: unknown decision %q", ErrOccupancyVoteInvalid, v.Decision)
+	}
+	if v.RequestID != r.ID {
+		return fmt.Errorf("%w: vote is for request %q", ErrOccupancyVoteInvalid, v.RequestID)
+	}
+	if !slices.Contains(r.Approvers, v.VaultID) {
+		return ErrOccupancyNotApprover
+	}
+	if slices.ContainsFunc(r.Votes, func(prev OccupancyVote) bool { return prev.VaultID == v.VaultID }) {
+		return ErrOccupancyAlreadyVoted
+	}
+
+	r.Votes = append(r.Votes, v)
+	switch {
+	case r.Approvals() >= r.Required:
+		r.decide(OccupancyApproved, now)
+	case len(r.Approvers)-r.Rejections() < r.Required:
+		r.decide(OccupancyRejected, now)
+	}
+	return nil
+}
+
+// Withdraw cancels a pending request. Only the requester or the candidate
+// may withdraw it.
+func (r *OccupancyRequest) Withdraw(vaultID string, now time.Time) error {
+	if r.Status != OccupancyPending {
+		return fmt.Errorf("%w: request is %s", ErrOccupancyNotPending, r.Status)
+	}
+	if vaultID != r.RequestedBy && vaultID != r.CandidateVaultID {
+		return fmt.Errorf("%w: only the requester or the candidate may withdraw", ErrPermissionDenied)
+	}
+	r.WithdrawnBy = vaultID
+	r.decide(OccupancyWithdrawn, now)
+	return nil
+}
+
+func (r *OccupancyRequest) decide(status OccupancyStatus, now time.Time) {
+	r.Status = status
+	r.DecidedAt = &now
+}
+
+// ChannelBlocker is one reason threads cannot be created in a channel yet.
+type ChannelBlocker struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+
+	SlotID           string   `json:"slot_id,omitempty"`
+	Role             string   `json:"role,omitempty"`
+	RequestID        string   `json:"request_id,omitempty"`
+	Approvals        int      `json:"approvals,omitempty"`
+	Required         int      `json:"required,omitempty"`
+	PendingApprovers []string `json:"pending_approvers,omitempty"`
+}
+
+// ChannelBlockers explains what keeps the channel from accepting threads:
+// its status, its expiry and every unfilled gated slot, with the pending
+// occupancy request and who still has to approve it. An empty result means
+// nothing blocks.
+func ChannelBlockers(c *Channel, requests []OccupancyRequest, now time.Time) []ChannelBlocker {
+	blockers := []ChannelBlocker{}
+
+	if c.Status != StatusActive {
+		blockers = append(blockers, ChannelBlocker{
+			Kind:    BlockerChannelInactive,
+			Message: fmt.Sprintf("channel is %s", c.Status),
+		})
+	}
+	if doc, err := ParsePolicy(c.Policy); err == nil {
+		if exp := doc.Retention.ExpiresAt; exp != nil && !now.Before(*exp) {
+			blockers = append(blockers, ChannelBlocker{
+				Kind:    BlockerChannelExpired,
+				Message: fmt.Sprintf("channel expired at %s", exp.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	for _, slot := range c.GetGatedSlots() {
+		if _, assigned := c.GetAssignmentBySlotID(slot.ID); assigned {
+			continue
+		}
+
+		idx := slices.IndexFunc(requests, func(r OccupancyRequest) bool {
+			return r.SlotID == slot.ID && r.Status == OccupancyPending
+		})
+		if idx < 0 {
+			blockers = append(blockers, ChannelBlocker{
+				Kind:    BlockerSlotUnfilled,
+				Message: fmt.Sprintf("gated slot %q has no occupant and no pending request", slot.Name),
+				SlotID:  slot.ID,
+				Role:    slot.Role,
+			})
+			continue
+		}
+
+		r := requests[idx]
+		blockers = append(blockers, ChannelBlocker{
+			Kind:             BlockerAwaitingApprovals,
+			Message:          fmt.Sprintf("gated slot %q awaits %d of %d approvals for %s", slot.Name, r.Required-r.Approvals(), r.Required, r.CandidateVaultID),
+			SlotID:           slot.ID,
+			Role:             slot.Role,
+			RequestID:        r.ID,
+			Approvals:        r.Approvals(),
+			Required:         r.Required,
+			PendingApprovers: r.PendingApprovers(),
+		})
+	}
+
+	return blockers
+}
@@ -0,0 +1,334 @@
+package channel_domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// ==============================================================================
+// Participant permissions
+// ==============================================================================
+//
+// A vault's permissions in a channel are the templates of the roles it holds
+// (slot roles, assignments and the participant role), plus the permissions
+// Cloud stored on its participant record, plus the grants recorded in the
+// policy, minus the revocations recorded in the policy.
+//
+// Permissions are enforced once PermissionPolicy.Roles names a role; until
+// then every participant may do everything, as before permissions existed.
+
+const (
+	PermThreadCreate      = "thread.create"
+	PermThreadManage      = "thread.manage"
+	PermAssetShare        = "asset.share"
+	PermParticipantInvite = "participant.invite"
+	// PermChannelAdmin implies every other permission.
+	PermChannelAdmin = "channel.admin"
+
+	// PermThreadAppendPrefix prefixes thread.append:<event-type>.
+	PermThreadAppendPrefix = "thread.append:"
+)
+
+// PermThreadAppendAny lets a vault append every event type.
+var PermThreadAppendAny = ThreadAppendPermission(AnyEventType)
+
+// DefaultRolePermissions is the template of a slot role the policy does not
+// list.
+var DefaultRolePermissions = []string{
+	PermThreadCreate,
+	PermThreadAppendAny,
+	PermAssetShare,
+}
+
+// ThreadAppendPermission is the permission to append eventType to a thread.
+func ThreadAppendPermission(eventType string) string {
+	return PermThreadAppendPrefix + eventType
+}
+
+// ValidatePermission checks the name is a known permission.
+func ValidatePermission(perm string) error {
+	switch perm {
+	case PermThreadCreate, PermThreadManage, PermAssetShare, PermParticipantInvite, PermChannelAdmin:
+		return nil
+	}
+	if eventType, ok := strings.CutPrefix(perm, PermThreadAppendPrefix); ok && eventType != "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrPermissionUnknown, perm)
+}
+
+// PermissionPolicy holds the role templates and per-vault adjustments.
+type PermissionPolicy struct {
+	// Roles is the permission template of a slot role. Slot roles it does not
+	// list get DefaultRolePermissions.
+	Roles map[string][]string `json:"roles,omitempty"`
+	// Grants and Revocations adjust the templates for one vault.
+	Grants      map[string][]string `json:"grants,omitempty"`
+	Revocations map[string][]string `json:"revocations,omitempty"`
+}
+
+// Enforced reports whether permissions are checked at all.
+func (p PermissionPolicy) Enforced() bool {
+	return len(p.Roles) > 0
+}
+
+// Grant gives perm to vaultID, lifting a previous revocation. It reports
+// whether anything changed.
+func (p *PermissionPolicy) Grant(vaultID string, perm string) bool {
+	revoked := removeFrom(p.Revocations, vaultID, perm)
+	if slices.Contains(p.Grants[vaultID], perm) {
+		return revoked
+	}
+	if p.Grants == nil {
+		p.Grants = map[string][]string{}
+	}
+	p.Grants[vaultID] = append(p.Grants[vaultID], perm)
+	return true
+}
+
+// Revoke takes perm from vaultID, whether it came from a grant or a role
+// template. It reports whether anything changed.
+func (p *PermissionPolicy) Revoke(vaultID string, perm string) bool {
+	removeFrom(p.Grants, vaultID, perm)
+	if slices.Contains(p.Revocations[vaultID], perm) {
+		return false
+	}
+	if p.Revocations == nil {
+		p.Revocations = map[string][]string{}
+	}
+	p.Revocations[vaultID] = append(p.Revocations[vaultID], perm)
+	return true
+}
+
+func removeFrom(m map[string][]string, vaultID string, perm string) bool {
+	perms, ok := m[vaultID]
+	if !ok || !slices.Contains(perms, perm) {
+		return false
+	}
+	perms = slices.DeleteFunc(slices.Clone(perms), func(p string) bool { return p == perm })
+	if len(perms) == 0 {
+		delete(m, vaultID)
+	} else {
+		m[vaultID] = perms
+	}
+	return true
+}
+
+func (p PermissionPolicy) validate(c *Channel) error {
+	checkPerms := func(owner string, perms []string) error {
+		for _, perm := range perms {
+			if err := ValidatePermission(perm); err != nil {
+				return fmt.Errorf("%w (%s)", err, owner)
+			}
+		}
+		return nil
+	}
+
+	for role, perms := range p.Roles {
+		if role == "" {
+			return fmt.Errorf("%w: empty role", ErrPolicyInvalid)
+		}
+		if c != nil && len(c.GetSlotsByRole(role)) == 0 {
+			return fmt.Errorf("%w: role %q", ErrPolicyUnknownRole, role)
+		}
+		if err := checkPerms("role "+role, perms); err != nil {
+			return err
+		}
+	}
+	for vaultID, perms := range p.Grants {
+		if err := checkPerms("grant to "+vaultID, perms); err != nil {
+			return err
+		}
+	}
+	for vaultID, perms := range p.Revocations {
+		if err := checkPerms("revocation from "+vaultID, perms); err != nil {
+			return err
+		}
+	}
+
+	if p.Enforced() && !p.hasAdmin() {
+		return fmt.Errorf("%w: no role or vault holds %s", ErrPolicyInvalid, PermChannelAdmin)
+	}
+	return nil
+}
+
+// hasAdmin guards against a policy that locks everyone out of administering
+// the channel.
+func (p PermissionPolicy) hasAdmin() bool {
+	for _, perms := range p.Roles {
+		if slices.Contains(perms, PermChannelAdmin) {
+			return true
+		}
+	}
+	for vaultID, perms := range p.Grants {
+		if slices.Contains(perms, PermChannelAdmin) && !slices.Contains(p.Revocations[vaultID], PermChannelAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleTemplates derives the permission template of every slot role of the
+// channel from the policy.
+func (c *Channel) RoleTemplates(p PermissionPolicy) map[string][]string {
+	templates := map[string][]string{}
+	for _, slot := range c.Slots {
+		if slot.Role == "" {
+			continue
+		}
+		if _, done := templates[slot.Role]; done {
+			continue
+		}
+		if perms, ok := p.Roles[slot.Role]; ok {
+			templates[slot.Role] = slices.Clone(perms)
+		} else {
+			templates[slot.Role] = slices.Clone(DefaultRolePermissions)
+		}
+	}
+	return templates
+}
+
+// EffectivePermissions resolves the permissions vaultID holds in the channel.
+// participants are the Cloud participant records, when the caller has them.
+func (c *Channel) EffectivePermissions(p PermissionPolicy, vaultID string, participants ...Participant) []string {
+	if vaultID == "" {
+		return []string{}
+	}
+
+	set := map[string]bool{}
+	roles := c.RolesOf(vaultID)
+	for _, participant := range participants {
+		if participant.VaultID != vaultID {
+			continue
+		}
+		if participant.Role != "" && !slices.Contains(roles, participant.Role) {
+			roles = append(roles, participant.Role)
+		}
+		for _, perm := range participant.Permissions {
+			set[perm] = true
+		}
+	}
+
+	templates := c.RoleTemplates(p)
+	for _, role := range roles {
+		perms, ok := templates[role]
+		if !ok {
+			perms = p.Roles[role]
+		}
+		for _, perm := range perms {
+			set[perm] = true
+		}
+	}
+	for _, perm := range p.Grants[vaultID] {
+		set[perm] = true
+	}
+	for _, perm := range p.Revocations[vaultID] {
+		delete(set, perm)
+	}
+
+	perms := make([]string, 0, len(set))
+	for perm := range set {
+		perms = append(perms, perm)
+	}
+	sort.Strings(perms)
+	return perms
+}
+
+// permits reports whether held covers perm. channel.admin covers everything
+// and thread.append:* covers every thread.append:<type>.
+func permits(held []string, perm string) bool {
+	if slices.Contains(held, PermChannelAdmin) || slices.Contains(held, perm) {
+		return true
+	}
+	return strings.HasPrefix(perm, PermThreadAppendPrefix) && slices.Contains(held, PermThreadAppendAny)
+}
+
+// RequirePermission checks actorVaultID holds perm in the channel. It passes
+// on channels that do not enforce permissions.
+func (e *PolicyEvaluator) RequirePermission(c *Channel, actorVaultID string, perm string, participants ...Participant) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+	if !doc.Permissions.Enforced() {
+		return nil
+	}
+
+	// An explicit revocation wins over a wildcard or channel.admin.
+	revoked := slices.Contains(doc.Permissions.Revocations[actorVaultID], perm)
+	if revoked || !permits(c.EffectivePermissions(doc.Permissions, actorVaultID, participants...), perm) {
+		if actorVaultID == "" {
+			return fmt.Errorf("%w: %s requires an identified vault", ErrPermissionDenied, perm)
+		}
+		return fmt.Errorf("%w: vault %s lacks %s", ErrPermissionDenied, actorVaultID, perm)
+	}
+	return nil
+}
+
+// RequireParticipant checks actorVaultID takes part in the channel, through
+// a slot, a participant record or a granted permission. It passes on channels
+// that do not enforce permissions. Expired channels stay readable.
+func (e *PolicyEvaluator) RequireParticipant(c *Channel, actorVaultID string, participants ...Participant) error {
+	if c == nil {
+		return ErrChannelNotFound
+	}
+	doc, err := ParsePolicy(c.Policy)
+	if err != nil {
+		return err
+	}
+	if !doc.Permissions.Enforced() {
+		return nil
+	}
+
+	if len(c.RolesOf(actorVaultID)) > 0 || len(c.EffectivePermissions(doc.Permissions, actorVaultID, participants...)) > 0 {
+		return nil
+	}
+	for _, p := range participants {
+		if actorVaultID != "" && p.VaultID == actorVaultID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: vault %q is not a participant", ErrPermissionDenied, actorVaultID)
+}
+
+// PermissionsEnforced reports whether the channel policy enforces
+// permissions. An unreadable policy counts as enforced.
+func (e *PolicyEvaluator) PermissionsEnforced(c *Channel) bool {
+	if c == nil {
+		return true
+	}
+	doc, err := ParsePolicy(c.Policy)
+	return err != nil || doc.Permissions.Enforced()
+}
+
+// ParticipantLister lists the participant records Cloud keeps for a channel.
+type ParticipantLister interface {
+	ListParticipants(ctx context.Context, req *ListParticipantsRequest) (*tracecore_types.CloudResponse[[]Participant], error)
+}
+
+// ChannelParticipants returns the Cloud participant records of a channel. A
+// failed lookup yields none; checks then rely on the channel alone.
+func ChannelParticipants(ctx context.Context, lister ParticipantLister, c *Channel) []Participant {
+	if lister == nil || c == nil {
+		return nil
+	}
+	resp, err := lister.ListParticipants(ctx, &ListParticipantsRequest{ChannelID: c.ID})
+	if err != nil || resp == nil {
+		return nil
+	}
+	return resp.Data
+}
+
+// GoverningParticipants returns the participant records that count in the
+// permission checks of a channel: none unless it enforces permissions.
+func (e *PolicyEvaluator) GoverningParticipants(ctx context.Context, lister ParticipantLister, c *Channel) []Participant {
+	if !e.PermissionsEnforced(c) {
+		return nil
+	}
+	return ChannelParticipants(ctx, lister, c)
+}
@@ -0,0 +1,522 @@
+package channel_domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ==============================================================================
+// Typed policy document
+// ==============================================================================
+//
+// Channel.Policy stays a map on the wire so the Cloud contract is unchanged;
+// PolicyDocument is its typed, versioned reading. ParsePolicy upgrades maps
+// written before the schema existed, ToPolicy writes the document back.
+
+const (
+	PolicySchema        = "ankhora.channel.policy"
+	PolicySchemaVersion = 1
+
+	// AnyRole / AnyEventType / AnyAssetType match every value.
+	AnyRole      = "*"
+	AnyEventType = "*"
+	AnyAssetType = "*"
+
+	// LegacyPolicyAllowThreadReopen is the pre-schema key for AllowThreadReopen.
+	LegacyPolicyAllowThreadReopen = "thread.reopen_allowed"
+)
+
+type PolicyDocument struct {
+	Schema   string `json:"schema"`
+	Version  int    `json:"version"`
+	Revision int    `json:"revision"`
+
+	Invite InvitePolicy `json:"invite"`
+	// ThreadEvents maps a role to the thread event types it may append. An
+	// empty map places no restriction.
+	ThreadEvents      map[string][]string   `json:"thread_events,omitempty"`
+	Approvals         []ApprovalRequirement `json:"approvals,omitempty"`
+	AllowedAssetTypes []string              `json:"allowed_asset_types,omitempty"`
+	Retention         RetentionPolicy       `json:"retention"`
+	AllowThreadReopen bool                  `json:"allow_thread_reopen"`
+	// Permissions are the participant role templates and per-vault grants.
+	Permissions PermissionPolicy `json:"permissions"`
+	// GatedSlots decides who must approve a vault before it fills a gated
+	// slot.
+	GatedSlots SlotApprovalPolicy `json:"gated_slots"`
+
+	// Extensions are the top-level keys this schema does not know. They are
+	// written back untouched so other clients' settings survive an update.
+	Extensions map[string]any `json:"-"`
+}
+
+// InvitePolicy decides who may bring new vaults into the channel.
+type InvitePolicy struct {
+	// Roles allowed to invite. Empty places no restriction.
+	Roles []string `json:"roles,omitempty"`
+	// RequireInvitation forbids joining without an accepted invitation.
+	RequireInvitation bool `json:"require_invitation"`
+}
+
+// ApprovalRequirement blocks EventType until Count distinct vaults appended
+// ApprovalEventType since the last EventType.
+type ApprovalRequirement struct {
+	EventType         string `json:"event_type"`
+	ApprovalEventType string `json:"approval_event_type"`
+	Count             int    `json:"count"`
+}
+
+// ThreadHistoryEntry is one event of a thread as the approval check sees it.
+type ThreadHistoryEntry struct {
+	EventType    string
+	ActorVaultID string
+}
+
+// SlotApprovalPolicy is the N-of-M rule for filling gated slots: Approvals
+// of the eligible approvers must sign off an occupancy request.
+type SlotApprovalPolicy struct {
+	// Approvals is N; zero means one.
+	Approvals int `json:"approvals,omitempty"`
+	// ApproverRoles are the roles whose holders may approve. Empty lets
+	// every vault already holding a slot approve.
+	ApproverRoles []string `json:"approver_roles,omitempty"`
+}
+
+// RequiredApprovals is N, never above the number of eligible approvers.
+func (p SlotApprovalPolicy) RequiredApprovals(eligible int) int {
+	n := p.Approvals
+	if n < 1 {
+		n = 1
+	}
+	return min(n, eligible)
+}
+
+type RetentionPolicy struct {
+	// RetainDays is how long thread history is kept after a thread closes;
+	// zero keeps it forever. Past it, the history is withheld and the thread
+	// cannot be reopened.
+	RetainDays int `json:"retain_days,omitempty"`
+	// ExpiresAt ends the channel: no action is allowed past it.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// DefaultPolicy is the permissive document applied to channels without one.
+func DefaultPolicy() PolicyDocument {
+	return PolicyDocument{
+		Schema:  PolicySchema,
+		Version: PolicySchemaVersion,
+	}
+}
+
+// ParsePolicy reads the typed document out of a channel policy map.
+func ParsePolicy(policy Policy) (PolicyDocument, error) {
+	doc := DefaultPolicy()
+	if len(policy) == 0 {
+		return doc, nil
+	}
+
+	if _, typed := policy["schema"]; !typed {
+		return migrateLegacyPolicy(policy), nil
+	}
+
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return doc, fmt.Errorf("%w: %v", ErrPolicyInvalid, err)
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return doc, fmt.Errorf("%w: %v", ErrPolicyInvalid, err)
+	}
+	doc.Extensions = unknownPolicyKeys(policy)
+	if doc.Schema != PolicySchema {
+		return doc, fmt.Errorf("%w: schema %q", ErrPolicyInvalid, doc.Schema)
+	}
+	if doc.Version > PolicySchemaVersion {
+		return doc, fmt.Errorf("%w: version %d", ErrPolicyVersionUnsupported, doc.Version)
+	}
+	doc.Version = PolicySchemaVersion
+
+	return doc, nil
+}
+
+// migrateLegacyPolicy maps the untyped keys used before the schema existed.
+// Keys it does not map are kept as extensions.
+func migrateLegacyPolicy(policy Policy) PolicyDocument {
+	doc := DefaultPolicy()
+	if v, ok := policy[LegacyPolicyAllowThreadReopen].(bool); ok {
+		doc.AllowThreadReopen = v
+	}
+	for key, value := range unknownPolicyKeys(policy) {
+		if key == LegacyPolicyAllowThreadReopen {
+			continue
+		}
+		if doc.Extensions == nil {
+			doc.Extensions = map[string]any{}
+		}
+		doc.Extensions[key] = value
+	}
+	return doc
+}
+
+// policyKeys are the top-level keys of the typed document.
+var policyKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(PolicyDocument{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}()
+
+func unknownPolicyKeys(policy Policy) map[string]any {
+	var unknown map[string]any
+	for key, value := range policy {
+		if policyKeys[key] {
+			continue
+		}
+		if unknown == nil {
+			unknown = map[string]any{}
+		}
+		unknown[key] = value
+	}
+	return unknown
+}
+
+// ToPolicy writes the document back into the wire map, extensions included.
+func (p PolicyDocument) ToPolicy() (Policy, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := Policy{}
+	if err := json.Unmarshal(raw, &policy); err != nil {
+		return nil, err
+	}
+	for key, value := range p.Extensions {
+		if !policyKeys[key] {
+			policy[key] = value
+		}
+	}
+	return policy, nil
+}
+
+// Validate checks the document against the channel it governs: every role it
+// names must be a slot role of the channel.
+func (p PolicyDocument) Validate(c *Channel) error {
+	if p.Schema != PolicySchema {
+		return fmt.Errorf("%w: schema must be %q", ErrPolicyInvalid, PolicySchema)
+	}
+	if p.Version < 1 || p.Version > PolicySchemaVersion {
+		return fmt.Errorf("%w: version %d", ErrPolicyVersionUnsupported, p.Version)
+	}
+
+	checkRole := func(role string) error {
+		if role == "" {
+			return fmt.Errorf("%w: empty role", ErrPolicyInvalid)
+		}
+		if role != AnyRole && c != nil && len(c.GetSlotsByRole(role)) == 0 {
+			return fmt.Errorf("%w: role %q", ErrPolicyUnknownRole, role)
+		}
+		return nil
+	}
+
+	for _, role := range p.Invite.Roles {
+		if err := checkRole(role); err != nil {
+			return err
+		}
+	}
+	for role, types := range p.ThreadEvents {
+		if err := checkRole(role); err != nil {
+			return err
+		}
+		for _, t := range types {
+			if t == "" {
+				return fmt.Errorf("%w: empty event type for role %q", ErrPolicyInvalid, role)
+			}
+		}
+	}
+	for _, a := range p.Approvals {
+		if a.EventType == "" || a.ApprovalEventType == "" {
+			return fmt.Errorf("%w: approval requires event_type and approval_event_type", ErrPolicyInvalid)
+		}
+		if a.Count < 1 {
+			return fmt.Errorf("%w: approval count for %q must be at least 1", ErrPolicyInvalid, a.EventType)
+		}
+		if a.EventType == a.ApprovalEventType {
+			return fmt.Errorf("%w: %q cannot approve itself", ErrPolicyInvalid, a.EventType)
+		}
+	}
+	for _, t := range p.AllowedAssetTypes {
+		if t == "" {
+			return fmt.Errorf("%w: empty asset type", ErrPolicyInvalid)
+		}
+	}
+	if p.Retention.RetainDays < 0 {
+		return fmt.Errorf("%w: retain_days must not be negative", ErrPolicyInvalid)
+	}
+	if p.GatedSlots.Approvals < 0 {
+		return fmt.Errorf("%w: gated slot approvals must not be negative", ErrPolicyInvalid)
+	}
+	for _, role := range p.GatedSlots.ApproverRoles {
+		if err := checkRole(role); err != nil {
+			return err
+		}
+	}
+
+	return p.Permissions.validate(c)
+}
+
+// RetainUntil is the end of the retention window for a thread closed at
+// closedAt, or nil when history is kept forever.
+func (p PolicyDocument) RetainUntil(closedAt time.Time) *time.Time {
+	if p.Retention.RetainDays == 0 {
+		return nil
+	}
+	until := closedAt.AddDate(0, 0, p.Retention.RetainDays)
+	return &until
+}
+
+// RolesOf returns the slot roles a vault holds in the channel, either through
+// an assignment (by owner id or vault address) or a slot bound to its vault.
+func (c *Channel) RolesOf(vaultID string) []string {
+	roles := []string{}
+	if vaultID == "" {
+		return roles
+	}
+
+	add := func(role string) {
+		if role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	for _, slot := range c.Slots {
+		if slot.VaultID == vaultID {
+			add(slot.Role)
+		}
+	}
+	for _, a := range c.Assignments {
+		if a.OwnerID != vaultID && a.VaultAddress != vaultID {
+			continue
+		}
+		if slot, ok := c.GetSlotByID(a.SlotID); ok {
+			add(slot.Role)
+		}
+	}
+
+	return roles
+}
+
+// IsParticipant reports whether a vault takes part in the channel: it is
+// bound to a slot, holds an assignment or has a participant record.
+func (c *Channel) IsParticipant(vaultID string, participants ...Participant) bool {
+	if vaultID == "" {
+		return false
+	}
+	for _, slot := range c.Slots {
+		if slot.VaultID == vaultID {
+			return true
+		}
+	}
+	for _, a := range c.Assignments {
+		if a.OwnerID == vaultID || a.VaultAddress == vaultID {
+			return true
+		}
+	}
+	for _, p := range participants {
+		if p.VaultID == vaultID {
+			return true
+		}
+	}
+	return false
+}
+
+// ==============================================================================
+// Evaluator
+// ==============================================================================
+
+// PolicyEvaluator answers the policy questions use cases ask before acting.
+// Every check fails closed on an unreadable policy.
+type PolicyEvaluator struct {
+	Now func() time.Time
+}
+
+func NewPolicyEvaluator() *PolicyEvaluator {
+	return &PolicyEvaluator{Now: time.Now}
+}
+
+func (e *PolicyEvaluator) policy(c *Channel) (PolicyDocument, error) {
+	if c == nil {
+		return PolicyDocument{}, ErrChannelNotFound
+	}
+	doc, err := ParsePolicy(c.Policy)
+	if err != nil {
+		return doc, err
+	}
+
+	if exp := doc.Retention.ExpiresAt; exp != nil && !e.Now().Before(*exp) {
+		return doc, ErrPolicyChannelExpired
+	}
+	return doc, nil
+}
+
+// CheckActive fails once the channel passed its expiry.
+func (e *PolicyEvaluator) CheckActive(c *Channel) error {
+	_, err := e.policy(c)
+	return err
+}
+
+// CanInvite checks the inviter holds one of the invite roles.
+func (e *PolicyEvaluator) CanInvite(c *Channel, inviterVaultID string) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+
+	if len(doc.Invite.Roles) == 0 {
+		return nil
+	}
+
+	roles := c.RolesOf(inviterVaultID)
+	if !anyMatches(roles, doc.Invite.Roles, AnyRole) {
+		return fmt.Errorf("%w: roles %v may not invite (allowed: %v)", ErrPolicyDenied, roles, doc.Invite.Roles)
+	}
+	return nil
+}
+
+// CanJoin checks a vault may become a participant without an invitation.
+func (e *PolicyEvaluator) CanJoin(c *Channel) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+	if doc.Invite.RequireInvitation {
+		return fmt.Errorf("%w: channel requires an invitation to join", ErrPolicyDenied)
+	}
+	return nil
+}
+
+// CanAppendEvent checks one of the actor's roles may append the event type.
+func (e *PolicyEvaluator) CanAppendEvent(c *Channel, actorVaultID string, eventType string) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+	if len(doc.ThreadEvents) == 0 {
+		return nil
+	}
+
+	roles := append(c.RolesOf(actorVaultID), AnyRole)
+	for _, role := range roles {
+		if slices.Contains(doc.ThreadEvents[role], eventType) || slices.Contains(doc.ThreadEvents[role], AnyEventType) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: roles %v may not append %q", ErrPolicyDenied, roles[:len(roles)-1], eventType)
+}
+
+// CheckApprovals checks the thread history, oldest first, holds the
+// approvals required before eventType. Each vault counts once, and approvals
+// given before the last eventType were spent on it. Events without an actor
+// do not count.
+func (e *PolicyEvaluator) CheckApprovals(c *Channel, eventType string, history []ThreadHistoryEntry) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+
+	for _, req := range doc.Approvals {
+		if req.EventType != eventType {
+			continue
+		}
+		approvers := map[string]bool{}
+		for _, h := range history {
+			switch {
+			case h.EventType == req.EventType:
+				clear(approvers)
+			case h.EventType == req.ApprovalEventType && h.ActorVaultID != "":
+				approvers[h.ActorVaultID] = true
+			}
+		}
+		if len(approvers) < req.Count {
+			return fmt.Errorf("%w: %q needs %q from %d vault(s), thread has %d",
+				ErrPolicyApprovalsMissing, eventType, req.ApprovalEventType, req.Count, len(approvers))
+		}
+	}
+	return nil
+}
+
+// RequiresApprovals reports whether CheckApprovals needs the thread history
+// for eventType, so callers can skip loading it otherwise.
+func (e *PolicyEvaluator) RequiresApprovals(c *Channel, eventType string) bool {
+	doc, err := ParsePolicy(c.Policy)
+	if err != nil {
+		return true
+	}
+	return slices.ContainsFunc(doc.Approvals, func(a ApprovalRequirement) bool { return a.EventType == eventType })
+}
+
+// CanUseAssetType checks the asset type is allowed in the channel.
+func (e *PolicyEvaluator) CanUseAssetType(c *Channel, assetType string) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+	if len(doc.AllowedAssetTypes) == 0 || assetType == "" {
+		return nil
+	}
+	if !slices.Contains(doc.AllowedAssetTypes, assetType) && !slices.Contains(doc.AllowedAssetTypes, AnyAssetType) {
+		return fmt.Errorf("%w: asset type %q (allowed: %v)", ErrPolicyAssetTypeNotAllowed, assetType, doc.AllowedAssetTypes)
+	}
+	return nil
+}
+
+// CanReopenThread checks closed threads of the channel may be reopened.
+func (e *PolicyEvaluator) CanReopenThread(c *Channel) error {
+	doc, err := e.policy(c)
+	if err != nil {
+		return err
+	}
+	if !doc.AllowThreadReopen {
+		return fmt.Errorf("%w: channel policy does not allow reopening threads", ErrPolicyDenied)
+	}
+	return nil
+}
+
+// CheckRetention fails once a thread closed at closedAt has outlived the
+// channel's retention window. Open threads are always retained.
+func (e *PolicyEvaluator) CheckRetention(c *Channel, closedAt *time.Time) error {
+	if c == nil {
+		return ErrChannelNotFound
+	}
+	doc, err := ParsePolicy(c.Policy)
+	if err != nil {
+		return err
+	}
+	if closedAt == nil {
+		return nil
+	}
+	if until := doc.RetainUntil(*closedAt); until != nil && !e.Now().Before(*until) {
+		return fmt.Errorf("%w: retained until %s", ErrPolicyRetentionElapsed, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func anyMatches(have []string, allowed []string, wildcard string) bool {
+	if slices.Contains(allowed, wildcard) {
+		return true
+	}
+	for _, h := range have {
+		if slices.Contains(allowed, h) {
+			return true
+		}
+	}
+	return false
+}
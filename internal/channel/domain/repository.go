@@ -2,6 +2,7 @@ package channel_domain
 
 import (
 	"context"
+	"time"
 
 	tracecore_types "vault-app/internal/tracecore/types"
 )
@@ -51,6 +52,9 @@ type DeleteChannelRequest struct {
 }
 type ListChannelsRequest struct {
 	WorkspaceID string
+	// UpdatedSince, when set, restricts the listing to channels changed
+	// after it.
+	UpdatedSince time.Time
 }
 
 type ActivateChannelRequest struct {
@@ -90,6 +94,8 @@ type InviteToChannelRequest struct {
 	ChannelID      string
 	InviterVaultID string
 	InviteeVaultID string
+	// ExpiresAt is forwarded to the Cloud, which expires the invitation.
+	ExpiresAt *time.Time
 }
 
 type AcceptInvitationRequest struct {
@@ -99,7 +105,36 @@ type AcceptInvitationRequest struct {
 }
 
 type RejectInvitationRequest struct {
-	InvitationID string
+	InvitationID   string
+	InviteeVaultID string
+}
+
+type RevokeInvitationRequest struct {
+	InvitationID   string
+	InviterVaultID string
+	Reason         string
+}
+
+type ExpireInvitationRequest struct {
+	InvitationID   string
+	InviterVaultID string
+}
+
+// InvitationDirection selects invitations addressed to a vault (received) or
+// issued by it (sent).
+type InvitationDirection string
+
+const (
+	InvitationsReceived InvitationDirection = "received"
+	InvitationsSent     InvitationDirection = "sent"
+)
+
+type ListInvitationsRequest struct {
+	VaultID   string
+	Direction InvitationDirection
+	// ChannelID and Status are optional filters.
+	ChannelID string
+	Status    InvitationStatus
 }
 
 // RevokeChannelRequest carries only the channel id. The Cloud backend is
@@ -141,4 +176,17 @@ type ChannelRepository interface {
 	// accepting an already-accepted invitation returns the accepted invitation
 	// without creating a duplicate participant.
 	AcceptChannelInvitation(ctx context.Context, req *AcceptInvitationRequest) (*tracecore_types.CloudResponse[Invitation], error)
+	// RejectChannelInvitation declines a pending invitation on behalf of its
+	// invitee (POST /channels/invitations/{id}/reject).
+	RejectChannelInvitation(ctx context.Context, req *RejectInvitationRequest) (*tracecore_types.CloudResponse[Invitation], error)
+	// RevokeChannelInvitation withdraws a pending invitation on behalf of its
+	// inviter (POST /channels/invitations/{id}/revoke).
+	RevokeChannelInvitation(ctx context.Context, req *RevokeInvitationRequest) (*tracecore_types.CloudResponse[Invitation], error)
+	// ExpireChannelInvitation records that a pending invitation ran past its
+	// expiry (POST /channels/invitations/{id}/expire). Cloud sets the expired
+	// status and tells the invitee the invitation expired.
+	ExpireChannelInvitation(ctx context.Context, req *ExpireInvitationRequest) (*tracecore_types.CloudResponse[Invitation], error)
+	// ListChannelInvitations returns the invitations a vault received or sent
+	// (GET /channels/invitations). An empty result is valid.
+	ListChannelInvitations(ctx context.Context, req *ListInvitationsRequest) (*tracecore_types.CloudResponse[[]Invitation], error)
 }
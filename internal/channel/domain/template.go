@@ -0,0 +1,389 @@
+package channel_domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ==============================================================================
+// Channel templates
+// ==============================================================================
+//
+// A ChannelTemplate is a versioned blueprint: the slots and roles a channel
+// starts with, which of them are gated, the default policy, the properties a
+// channel must carry and the thread asset type it is meant for. Channels
+// remember the template version they follow in a reserved property, so they
+// can be migrated when the template moves on.
+
+// TemplateVersionProperty is the channel property holding the version of the
+// template the channel follows.
+const TemplateVersionProperty = "template.version"
+
+type ChannelTemplate struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Slots are the blueprint slots. VaultID is normally empty: the vault is
+	// chosen when the channel is instantiated.
+	Slots              []Slot         `json:"slots"`
+	DefaultPolicy      PolicyDocument `json:"default_policy"`
+	RequiredProperties []string       `json:"required_properties,omitempty"`
+	// AssetType is the thread asset type the channel is meant for. It becomes
+	// the policy allow-list unless the default policy sets one.
+	AssetType  string                  `json:"asset_type,omitempty"`
+	Migrations []TemplateMigrationStep `json:"migrations,omitempty"`
+}
+
+// TemplateMigrationStep describes what moving to ToVersion removes or
+// renames; slots the new version adds or changes are taken from its
+// blueprint.
+type TemplateMigrationStep struct {
+	ToVersion   int               `json:"to_version"`
+	RemoveSlots []string          `json:"remove_slots,omitempty"`
+	RenameRoles map[string]string `json:"rename_roles,omitempty"`
+}
+
+// TemplateMigration reports what MigrateChannel changed.
+type TemplateMigration struct {
+	TemplateID     string   `json:"template_id"`
+	FromVersion    int      `json:"from_version"`
+	ToVersion      int      `json:"to_version"`
+	AddedSlots     []string `json:"added_slots"`
+	UpdatedSlots   []string `json:"updated_slots"`
+	RemovedSlots   []string `json:"removed_slots"`
+	PolicyReplaced bool     `json:"policy_replaced"`
+}
+
+// Changed reports whether the migration touched the channel.
+func (m TemplateMigration) Changed() bool {
+	return m.FromVersion != m.ToVersion
+}
+
+// ChannelTemplateRegistry resolves channel templates by ID, returning the
+// latest version.
+type ChannelTemplateRegistry interface {
+	GetChannelTemplate(ctx context.Context, templateID string) (*ChannelTemplate, error)
+	ListChannelTemplates(ctx context.Context) ([]ChannelTemplate, error)
+}
+
+// Policy returns the template's default policy document with the asset type
+// applied.
+func (t ChannelTemplate) Policy() PolicyDocument {
+	doc := t.DefaultPolicy
+	if doc.Schema == "" {
+		doc.Schema = PolicySchema
+	}
+	if doc.Version == 0 {
+		doc.Version = PolicySchemaVersion
+	}
+	if t.AssetType != "" && len(doc.AllowedAssetTypes) == 0 {
+		doc.AllowedAssetTypes = []string{t.AssetType}
+	}
+	return doc
+}
+
+// Validate checks the blueprint is complete and its policy fits its slots.
+func (t ChannelTemplate) Validate() error {
+	if t.ID == "" {
+		return ErrTemplateIDRequired
+	}
+	if t.Version < 1 {
+		return fmt.Errorf("%w: %s version must be at least 1", ErrTemplateInvalid, t.ID)
+	}
+	if t.Title == "" {
+		return fmt.Errorf("%w: %s has no title", ErrTemplateInvalid, t.ID)
+	}
+	if len(t.Slots) == 0 {
+		return fmt.Errorf("%w: %s has no slots", ErrTemplateInvalid, t.ID)
+	}
+
+	seen := map[string]bool{}
+	for _, slot := range t.Slots {
+		if slot.ID == "" || slot.Role == "" {
+			return fmt.Errorf("%w: %s slots need an id and a role", ErrTemplateInvalid, t.ID)
+		}
+		if seen[slot.ID] {
+			return fmt.Errorf("%w: %s repeats slot %q", ErrTemplateInvalid, t.ID, slot.ID)
+		}
+		seen[slot.ID] = true
+	}
+	for _, key := range t.RequiredProperties {
+		if key == "" || key == TemplateVersionProperty {
+			return fmt.Errorf("%w: %s requires property %q", ErrTemplateInvalid, t.ID, key)
+		}
+	}
+
+	doc := t.Policy()
+	if t.AssetType != "" && !slices.Contains(doc.AllowedAssetTypes, t.AssetType) && !slices.Contains(doc.AllowedAssetTypes, AnyAssetType) {
+		return fmt.Errorf("%w: %s default policy does not allow its asset type %q", ErrTemplateInvalid, t.ID, t.AssetType)
+	}
+	for _, step := range t.Migrations {
+		if step.ToVersion < 2 || step.ToVersion > t.Version {
+			return fmt.Errorf("%w: %s has a migration to version %d", ErrTemplateInvalid, t.ID, step.ToVersion)
+		}
+	}
+
+	blueprint := Channel{Slots: t.Slots}
+	if err := doc.Validate(&blueprint); err != nil {
+		return fmt.Errorf("%w: %s default policy: %w", ErrTemplateInvalid, t.ID, err)
+	}
+	return nil
+}
+
+// Instantiate builds a channel from the template. Assignments must target
+// template slots and every required property must have a value.
+func (t ChannelTemplate) Instantiate(title string, workspaceID string, assignments []Assignment, properties []ChannelProperty) (Channel, error) {
+	if err := t.Validate(); err != nil {
+		return Channel{}, err
+	}
+	if title == "" {
+		title = t.Title
+	}
+
+	channel := NewChannel(t.ID, title, workspaceID)
+	for _, slot := range t.Slots {
+		if err := channel.AddSlot(slot); err != nil {
+			return Channel{}, err
+		}
+	}
+	for _, assignment := range assignments {
+		if _, ok := channel.GetSlotByID(assignment.SlotID); !ok {
+			return Channel{}, fmt.Errorf("%w: %q is not a slot of template %s", ErrSlotNotFound, assignment.SlotID, t.ID)
+		}
+		channel.AddAssignment(assignment)
+	}
+
+	channel.Properties = withTemplateVersion(properties, t.Version)
+	policy, err := t.Policy().ToPolicy()
+	if err != nil {
+		return Channel{}, err
+	}
+	channel.SetPolicy(policy)
+
+	if err := t.ValidateChannel(&channel); err != nil {
+		return Channel{}, err
+	}
+	return channel, nil
+}
+
+// ValidateChannel checks a channel still has the shape of the template: every
+// blueprint slot with its role, and every required property set.
+func (t ChannelTemplate) ValidateChannel(c *Channel) error {
+	if c.TemplateID != t.ID {
+		return fmt.Errorf("%w: channel follows %q, not %q", ErrTemplateMismatch, c.TemplateID, t.ID)
+	}
+	for _, slot := range t.Slots {
+		existing, ok := c.GetSlotByID(slot.ID)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrSlotNotFound, slot.ID)
+		}
+		if existing.Role != slot.Role {
+			return fmt.Errorf("%w: slot %q has role %q, template expects %q", ErrTemplateMismatch, slot.ID, existing.Role, slot.Role)
+		}
+	}
+	for _, key := range t.RequiredProperties {
+		if value, ok := c.Property(key); !ok || value == "" {
+			return fmt.Errorf("%w: %q", ErrTemplatePropertyMissing, key)
+		}
+	}
+	return nil
+}
+
+// MigrateChannel moves a channel from the template version it follows to t.
+// Blueprint slots are added or updated in place, keeping their vault and
+// assignments; slots the migration steps remove must be unoccupied. A policy
+// nobody has edited since instantiation (revision 0) is replaced by the new
+// default; an edited one only has its roles renamed. properties supplies
+// values for properties the new version requires. The channel is left
+// untouched when the migration fails.
+func (t ChannelTemplate) MigrateChannel(target *Channel, properties []ChannelProperty) (TemplateMigration, error) {
+	report := TemplateMigration{TemplateID: t.ID, AddedSlots: []string{}, UpdatedSlots: []string{}, RemovedSlots: []string{}}
+	if err := t.Validate(); err != nil {
+		return report, err
+	}
+	if target.TemplateID != t.ID {
+		return report, fmt.Errorf("%w: channel follows %q, not %q", ErrTemplateMismatch, target.TemplateID, t.ID)
+	}
+	if !target.canModify() {
+		return report, ErrChannelNotModifiable
+	}
+
+	c := *target
+	c.Slots = slices.Clone(target.Slots)
+	c.Properties = slices.Clone(target.Properties)
+
+	from := TemplateVersionOf(&c)
+	report.FromVersion, report.ToVersion = from, t.Version
+	if from > t.Version {
+		return report, fmt.Errorf("%w: channel is at version %d, template at %d", ErrTemplateVersionUnsupported, from, t.Version)
+	}
+	if from == t.Version {
+		return report, nil
+	}
+
+	doc, err := ParsePolicy(c.Policy)
+	if err != nil {
+		return report, err
+	}
+
+	// Removals and renames first, so the blueprint can reuse slot IDs.
+	renames := map[string]string{}
+	for _, step := range t.Migrations {
+		if step.ToVersion <= from {
+			continue
+		}
+		for _, slotID := range step.RemoveSlots {
+			if _, ok := c.GetSlotByID(slotID); !ok {
+				continue
+			}
+			if _, occupied := c.GetAssignmentBySlotID(slotID); occupied {
+				return report, fmt.Errorf("%w: slot %q is assigned", ErrTemplateMigrationConflict, slotID)
+			}
+			c.Slots = slices.DeleteFunc(c.Slots, func(s Slot) bool { return s.ID == slotID })
+			report.RemovedSlots = append(report.RemovedSlots, slotID)
+		}
+		for oldRole, newRole := range step.RenameRoles {
+			renames[oldRole] = newRole
+		}
+	}
+	for i := range c.Slots {
+		if newRole, ok := renames[c.Slots[i].Role]; ok {
+			c.Slots[i].Role = newRole
+		}
+	}
+
+	for _, blueprint := range t.Slots {
+		existing, ok := c.GetSlotByID(blueprint.ID)
+		if !ok {
+			c.Slots = append(c.Slots, blueprint)
+			report.AddedSlots = append(report.AddedSlots, blueprint.ID)
+			continue
+		}
+		updated := blueprint
+		if existing.VaultID != "" {
+			updated.VaultID = existing.VaultID
+		}
+		if updated != existing {
+			if err := c.UpdateSlot(updated); err != nil {
+				return report, err
+			}
+			report.UpdatedSlots = append(report.UpdatedSlots, blueprint.ID)
+		}
+	}
+
+	if doc.Revision == 0 {
+		doc = t.Policy()
+		report.PolicyReplaced = true
+	} else {
+		doc = doc.renameRoles(renames)
+		doc.Revision++
+	}
+	if err := doc.Validate(&c); err != nil {
+		return report, err
+	}
+	policy, err := doc.ToPolicy()
+	if err != nil {
+		return report, err
+	}
+	c.SetPolicy(policy)
+
+	c.Properties = withTemplateVersion(mergeProperties(c.Properties, properties), t.Version)
+	if err := t.ValidateChannel(&c); err != nil {
+		return report, err
+	}
+
+	*target = c
+	return report, nil
+}
+
+// TemplateVersionOf is the template version a channel follows, or 0 when it
+// does not record one.
+func TemplateVersionOf(c *Channel) int {
+	value, ok := c.Property(TemplateVersionProperty)
+	if !ok {
+		return 0
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// Property returns the value of a channel property.
+func (c *Channel) Property(key string) (string, bool) {
+	for _, p := range c.Properties {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+func withTemplateVersion(properties []ChannelProperty, version int) []ChannelProperty {
+	out := make([]ChannelProperty, 0, len(properties)+1)
+	for _, p := range properties {
+		if p.Key != TemplateVersionProperty {
+			out = append(out, p)
+		}
+	}
+	return append(out, ChannelProperty{Key: TemplateVersionProperty, Value: strconv.Itoa(version)})
+}
+
+// mergeProperties sets the values of updates on properties, adding keys that
+// are missing.
+func mergeProperties(properties []ChannelProperty, updates []ChannelProperty) []ChannelProperty {
+	out := slices.Clone(properties)
+	for _, u := range updates {
+		i := slices.IndexFunc(out, func(p ChannelProperty) bool { return p.Key == u.Key })
+		if i < 0 {
+			out = append(out, u)
+		} else {
+			out[i].Value = u.Value
+		}
+	}
+	return out
+}
+
+// renameRoles rewrites the roles a policy names.
+func (p PolicyDocument) renameRoles(renames map[string]string) PolicyDocument {
+	if len(renames) == 0 {
+		return p
+	}
+	rename := func(role string) string {
+		if newRole, ok := renames[role]; ok {
+			return newRole
+		}
+		return role
+	}
+	renameKeys := func(m map[string][]string) map[string][]string {
+		if m == nil {
+			return nil
+		}
+		out := make(map[string][]string, len(m))
+		for role, values := range m {
+			out[rename(role)] = values
+		}
+		return out
+	}
+
+	renameAll := func(roles []string) []string {
+		if len(roles) == 0 {
+			return roles
+		}
+		out := make([]string, len(roles))
+		for i, role := range roles {
+			out[i] = rename(role)
+		}
+		return out
+	}
+
+	p.Invite.Roles = renameAll(p.Invite.Roles)
+	p.GatedSlots.ApproverRoles = renameAll(p.GatedSlots.ApproverRoles)
+	p.ThreadEvents = renameKeys(p.ThreadEvents)
+	p.Permissions.Roles = renameKeys(p.Permissions.Roles)
+	return p
+}
@@ -0,0 +1,60 @@
+package channel_tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func TestRetryPolicy_BackoffDoublesUpToMax(t *testing.T) {
+	policy := channel_domain.RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	require.Equal(t, time.Second, policy.Backoff(0))
+	require.Equal(t, time.Second, policy.Backoff(1))
+	require.Equal(t, 2*time.Second, policy.Backoff(2))
+	require.Equal(t, 4*time.Second, policy.Backoff(3))
+	require.Equal(t, 5*time.Second, policy.Backoff(4))
+	require.Equal(t, 5*time.Second, policy.Backoff(40))
+}
+
+func TestPendingSyncItem_FailRequeueAndDue(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	policy := channel_domain.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second, MaxDelay: time.Minute, AckTimeout: time.Minute}
+	item := channel_domain.PendingSyncItem{ExchangeID: "ex-1", Status: channel_domain.SyncPending}
+
+	require.True(t, item.Due(now, policy))
+	require.ErrorIs(t, item.Requeue(now), channel_domain.ErrSyncItemNotDeadLettered)
+
+	item.MarkSending(now)
+	require.False(t, item.Due(now.Add(time.Second), policy))
+	require.True(t, item.Due(now.Add(time.Minute), policy))
+
+	item.MarkFailed(now, errors.New("refused"), policy)
+	require.Equal(t, channel_domain.SyncFailed, item.Status)
+	require.False(t, item.Due(now, policy))
+	require.True(t, item.Due(now.Add(time.Second), policy))
+
+	item.MarkFailed(now, errors.New("refused"), policy)
+	require.Equal(t, channel_domain.SyncDeadLetter, item.Status)
+	require.False(t, item.Due(now.Add(time.Hour), policy))
+
+	require.NoError(t, item.Requeue(now))
+	require.Equal(t, channel_domain.SyncPending, item.Status)
+	require.Zero(t, item.RetryCount)
+	require.Empty(t, item.LastError)
+}
+
+func TestRemoteVault_AdvanceCursorIgnoresStaleAcks(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	remote := channel_domain.RemoteVault{VaultID: "vault-b", LastCursor: 5}
+
+	require.False(t, remote.AdvanceCursor(3, now))
+	require.Equal(t, uint64(5), remote.LastCursor)
+	require.True(t, remote.AdvanceCursor(9, now))
+	require.Equal(t, uint64(9), remote.LastCursor)
+	require.Equal(t, now, remote.LastSeen)
+}
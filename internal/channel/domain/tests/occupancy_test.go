@@ -0,0 +1,151 @@
+package channel_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func procurementChannel(t *testing.T, gated channel_domain.SlotApprovalPolicy) (channel_domain.Channel, channel_domain.PolicyDocument) {
+	channel := newTestChannel()
+	channel.Slots = []channel_domain.Slot{
+		{ID: "buyer", Name: "Buyer", Role: "buyer", Gated: true, Order: 1},
+		{ID: "supplier", Name: "Supplier", Role: "supplier", Gated: true, Order: 2},
+		{ID: "finance", Name: "Finance", Role: "finance", Order: 3},
+		{ID: "auditor", Name: "Auditor", Role: "auditor", Order: 4},
+	}
+	channel.Assignments = []channel_domain.Assignment{
+		{SlotID: "buyer", OwnerID: "vault-buyer"},
+		{SlotID: "finance", OwnerID: "vault-finance"},
+		{SlotID: "auditor", OwnerID: "vault-auditor"},
+	}
+
+	doc := channel_domain.DefaultPolicy()
+	doc.GatedSlots = gated
+	require.NoError(t, doc.Validate(&channel))
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channel.SetPolicy(policy)
+
+	return channel, doc
+}
+
+func vote(requestID string, vaultID string, decision string) channel_domain.OccupancyVote {
+	return channel_domain.OccupancyVote{RequestID: requestID, VaultID: vaultID, Decision: decision}
+}
+
+func TestSlotApprovalPolicy_Validate(t *testing.T) {
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{})
+
+	doc.GatedSlots.ApproverRoles = []string{"ghost"}
+	require.ErrorIs(t, doc.Validate(&channel), channel_domain.ErrPolicyUnknownRole)
+
+	doc.GatedSlots = channel_domain.SlotApprovalPolicy{Approvals: -1}
+	require.ErrorIs(t, doc.Validate(&channel), channel_domain.ErrPolicyInvalid)
+
+	require.Equal(t, 1, channel_domain.SlotApprovalPolicy{}.RequiredApprovals(3))
+	require.Equal(t, 2, channel_domain.SlotApprovalPolicy{Approvals: 5}.RequiredApprovals(2))
+}
+
+func TestNewOccupancyRequest_ChecksSlotAndApprovers(t *testing.T) {
+	now := time.Now().UTC()
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{Approvals: 2, ApproverRoles: []string{"buyer", "finance"}})
+	candidate := channel_domain.Assignment{OwnerID: "vault-supplier"}
+
+	_, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "finance", candidate, "vault-supplier", now)
+	require.ErrorIs(t, err, channel_domain.ErrSlotNotGated)
+
+	_, err = channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "buyer", candidate, "vault-supplier", now)
+	require.ErrorIs(t, err, channel_domain.ErrSlotAlreadyFilled)
+
+	request, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "supplier", candidate, "vault-supplier", now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"vault-buyer", "vault-finance"}, request.Approvers)
+	require.Equal(t, 2, request.Required)
+	require.Equal(t, channel_domain.OccupancyPending, request.Status)
+
+	empty := newTestChannel()
+	empty.Slots = []channel_domain.Slot{{ID: "buyer", Role: "buyer", Gated: true}}
+	_, err = channel_domain.NewOccupancyRequest(&empty, channel_domain.DefaultPolicy(), "req-2", "buyer", candidate, "vault-supplier", now)
+	require.ErrorIs(t, err, channel_domain.ErrOccupancyNoApprovers)
+}
+
+func TestOccupancyRequest_ApprovesAtThreshold(t *testing.T) {
+	now := time.Now().UTC()
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{Approvals: 2, ApproverRoles: []string{"buyer", "finance"}})
+	request, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "supplier", channel_domain.Assignment{OwnerID: "vault-supplier"}, "vault-supplier", now)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, request.Vote(vote("req-1", "vault-auditor", channel_domain.OccupancyVoteApprove), now), channel_domain.ErrOccupancyNotApprover)
+	require.ErrorIs(t, request.Vote(vote("req-9", "vault-buyer", channel_domain.OccupancyVoteApprove), now), channel_domain.ErrOccupancyVoteInvalid)
+
+	require.NoError(t, request.Vote(vote("req-1", "vault-buyer", channel_domain.OccupancyVoteApprove), now))
+	require.Equal(t, channel_domain.OccupancyPending, request.Status)
+	require.Equal(t, []string{"vault-finance"}, request.PendingApprovers())
+	require.ErrorIs(t, request.Vote(vote("req-1", "vault-buyer", channel_domain.OccupancyVoteApprove), now), channel_domain.ErrOccupancyAlreadyVoted)
+
+	require.NoError(t, request.Vote(vote("req-1", "vault-finance", channel_domain.OccupancyVoteApprove), now))
+	require.Equal(t, channel_domain.OccupancyApproved, request.Status)
+	require.NotNil(t, request.DecidedAt)
+	require.Equal(t, channel_domain.Assignment{SlotID: "supplier", OwnerID: "vault-supplier"}, request.Assignment())
+
+	require.ErrorIs(t, request.Vote(vote("req-1", "vault-finance", channel_domain.OccupancyVoteReject), now), channel_domain.ErrOccupancyNotPending)
+}
+
+func TestOccupancyRequest_RejectsOnceThresholdIsUnreachable(t *testing.T) {
+	now := time.Now().UTC()
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{Approvals: 2})
+	request, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "supplier", channel_domain.Assignment{OwnerID: "vault-supplier"}, "vault-buyer", now)
+	require.NoError(t, err)
+	require.Len(t, request.Approvers, 3, "without approver roles every slot holder approves")
+
+	require.NoError(t, request.Vote(vote("req-1", "vault-auditor", channel_domain.OccupancyVoteReject), now))
+	require.Equal(t, channel_domain.OccupancyPending, request.Status, "two approvals are still reachable")
+
+	require.NoError(t, request.Vote(vote("req-1", "vault-finance", channel_domain.OccupancyVoteReject), now))
+	require.Equal(t, channel_domain.OccupancyRejected, request.Status)
+
+	require.ErrorIs(t, request.Withdraw("vault-buyer", now), channel_domain.ErrOccupancyNotPending)
+}
+
+func TestOccupancyRequest_Withdraw(t *testing.T) {
+	now := time.Now().UTC()
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{})
+	request, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "supplier", channel_domain.Assignment{OwnerID: "vault-supplier"}, "vault-buyer", now)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, request.Withdraw("vault-auditor", now), channel_domain.ErrPermissionDenied)
+	require.NoError(t, request.Withdraw("vault-supplier", now))
+	require.Equal(t, channel_domain.OccupancyWithdrawn, request.Status)
+}
+
+func TestChannelBlockers(t *testing.T) {
+	now := time.Now().UTC()
+	channel, doc := procurementChannel(t, channel_domain.SlotApprovalPolicy{Approvals: 2, ApproverRoles: []string{"buyer", "finance"}})
+
+	blockers := channel_domain.ChannelBlockers(&channel, nil, now)
+	require.Len(t, blockers, 1)
+	require.Equal(t, channel_domain.BlockerSlotUnfilled, blockers[0].Kind)
+	require.Equal(t, "supplier", blockers[0].SlotID)
+
+	request, err := channel_domain.NewOccupancyRequest(&channel, doc, "req-1", "supplier", channel_domain.Assignment{OwnerID: "vault-supplier"}, "vault-supplier", now)
+	require.NoError(t, err)
+	require.NoError(t, request.Vote(vote("req-1", "vault-finance", channel_domain.OccupancyVoteApprove), now))
+
+	channel.Status = channel_domain.StatusPending
+	blockers = channel_domain.ChannelBlockers(&channel, []channel_domain.OccupancyRequest{request}, now)
+	require.Len(t, blockers, 2)
+	require.Equal(t, channel_domain.BlockerChannelInactive, blockers[0].Kind)
+	require.Equal(t, channel_domain.BlockerAwaitingApprovals, blockers[1].Kind)
+	require.Equal(t, "req-1", blockers[1].RequestID)
+	require.Equal(t, 1, blockers[1].Approvals)
+	require.Equal(t, 2, blockers[1].Required)
+	require.Equal(t, []string{"vault-buyer"}, blockers[1].PendingApprovers)
+
+	channel.Status = channel_domain.StatusActive
+	channel.AddAssignment(request.Assignment())
+	require.Empty(t, channel_domain.ChannelBlockers(&channel, []channel_domain.OccupancyRequest{request}, now))
+}
@@ -0,0 +1,118 @@
+package channel_tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func permissionedChannel(t *testing.T) channel_domain.Channel {
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions = channel_domain.PermissionPolicy{
+		Roles: map[string][]string{
+			"lead-engineer": {channel_domain.PermChannelAdmin},
+			"reviewer":      {channel_domain.ThreadAppendPermission("review.approved")},
+		},
+	}
+	return governedChannel(t, doc)
+}
+
+func TestRequirePermission_UnenforcedAllowsEveryone(t *testing.T) {
+	channel := governedChannel(t, channel_domain.DefaultPolicy())
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-stranger", channel_domain.PermChannelAdmin))
+	require.False(t, evaluator.PermissionsEnforced(&channel))
+}
+
+func TestRequirePermission_RoleTemplates(t *testing.T) {
+	channel := permissionedChannel(t)
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-oem", channel_domain.PermThreadManage))
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-auditor", channel_domain.ThreadAppendPermission("review.approved")))
+
+	err := evaluator.RequirePermission(&channel, "vault-auditor", channel_domain.PermThreadCreate)
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+
+	err = evaluator.RequirePermission(&channel, "", channel_domain.PermThreadCreate)
+	require.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+}
+
+func TestRequirePermission_ParticipantRecords(t *testing.T) {
+	channel := permissionedChannel(t)
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	participants := []channel_domain.Participant{
+		{VaultID: "vault-guest", Role: "reviewer"},
+		{VaultID: "vault-sharer", Permissions: []string{channel_domain.PermAssetShare}},
+	}
+
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-guest", channel_domain.ThreadAppendPermission("review.approved"), participants...))
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-sharer", channel_domain.PermAssetShare, participants...))
+	require.ErrorIs(t,
+		evaluator.RequirePermission(&channel, "vault-sharer", channel_domain.PermThreadCreate, participants...),
+		channel_domain.ErrPermissionDenied,
+	)
+}
+
+func TestPermissionPolicy_GrantAndRevoke(t *testing.T) {
+	var p channel_domain.PermissionPolicy
+
+	require.True(t, p.Grant("vault-a", channel_domain.PermAssetShare))
+	require.False(t, p.Grant("vault-a", channel_domain.PermAssetShare))
+	require.Equal(t, []string{channel_domain.PermAssetShare}, p.Grants["vault-a"])
+
+	require.True(t, p.Revoke("vault-a", channel_domain.PermAssetShare))
+	require.False(t, p.Revoke("vault-a", channel_domain.PermAssetShare))
+	require.NotContains(t, p.Grants, "vault-a")
+	require.Equal(t, []string{channel_domain.PermAssetShare}, p.Revocations["vault-a"])
+
+	// Granting again lifts the revocation.
+	require.True(t, p.Grant("vault-a", channel_domain.PermAssetShare))
+	require.NotContains(t, p.Revocations, "vault-a")
+}
+
+func TestEffectivePermissions_RevocationOverridesTemplate(t *testing.T) {
+	channel := permissionedChannel(t)
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+
+	doc.Permissions.Grant("vault-auditor", channel_domain.PermThreadAppendAny)
+	doc.Permissions.Revoke("vault-auditor", channel_domain.ThreadAppendPermission("payment.released"))
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channel.SetPolicy(policy)
+
+	require.Equal(t,
+		[]string{channel_domain.PermThreadAppendAny, channel_domain.ThreadAppendPermission("review.approved")},
+		channel.EffectivePermissions(doc.Permissions, "vault-auditor"),
+	)
+
+	evaluator := channel_domain.NewPolicyEvaluator()
+	require.NoError(t, evaluator.RequirePermission(&channel, "vault-auditor", channel_domain.ThreadAppendPermission("invoice.created")))
+	require.ErrorIs(t,
+		evaluator.RequirePermission(&channel, "vault-auditor", channel_domain.ThreadAppendPermission("payment.released")),
+		channel_domain.ErrPermissionDenied,
+	)
+}
+
+func TestPermissionPolicy_Validate(t *testing.T) {
+	channel := newTestChannel()
+	require.NoError(t, channel.AddSlot(testSlotOne()))
+
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions.Roles = map[string][]string{"lead-engineer": {channel_domain.PermThreadCreate}}
+	require.ErrorIs(t, doc.Validate(&channel), channel_domain.ErrPolicyInvalid, "no one could administer the channel")
+
+	doc.Permissions.Roles = map[string][]string{"ghost": {channel_domain.PermChannelAdmin}}
+	require.ErrorIs(t, doc.Validate(&channel), channel_domain.ErrPolicyUnknownRole)
+
+	doc.Permissions.Roles = map[string][]string{"lead-engineer": {"thread.delete"}}
+	require.ErrorIs(t, doc.Validate(&channel), channel_domain.ErrPermissionUnknown)
+
+	doc.Permissions.Roles = map[string][]string{"lead-engineer": {channel_domain.PermChannelAdmin}}
+	require.NoError(t, doc.Validate(&channel))
+}
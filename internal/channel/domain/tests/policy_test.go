@@ -0,0 +1,170 @@
+package channel_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func governedChannel(t *testing.T, doc channel_domain.PolicyDocument) channel_domain.Channel {
+	channel := newTestChannel()
+	require.NoError(t, channel.AddSlot(testSlotOne()))
+	require.NoError(t, channel.AddSlot(testSlotTwo()))
+	channel.AddAssignment(channel_domain.Assignment{SlotID: "slot-002", OwnerID: "vault-auditor"})
+
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channel.SetPolicy(policy)
+
+	return channel
+}
+
+func TestParsePolicy_EmptyAndLegacyMaps(t *testing.T) {
+	doc, err := channel_domain.ParsePolicy(nil)
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.DefaultPolicy(), doc)
+
+	doc, err = channel_domain.ParsePolicy(channel_domain.Policy{
+		channel_domain.LegacyPolicyAllowThreadReopen: true,
+		"something_else": "kept",
+	})
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.PolicySchemaVersion, doc.Version)
+	require.True(t, doc.AllowThreadReopen)
+	require.Equal(t, map[string]any{"something_else": "kept"}, doc.Extensions)
+
+	// The migrated document writes the unknown key back; the legacy key is
+	// replaced by its typed field.
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	require.Equal(t, "kept", policy["something_else"])
+	require.NotContains(t, policy, channel_domain.LegacyPolicyAllowThreadReopen)
+	require.Equal(t, true, policy["allow_thread_reopen"])
+}
+
+func TestParsePolicy_RoundTripAndVersionGuard(t *testing.T) {
+	doc := channel_domain.DefaultPolicy()
+	doc.Revision = 3
+	doc.ThreadEvents = map[string][]string{"reviewer": {"finance.approved"}}
+	doc.Approvals = []channel_domain.ApprovalRequirement{{EventType: "payment.released", ApprovalEventType: "finance.approved", Count: 2}}
+
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+
+	parsed, err := channel_domain.ParsePolicy(policy)
+	require.NoError(t, err)
+	require.Equal(t, doc, parsed)
+
+	policy["version"] = channel_domain.PolicySchemaVersion + 1
+	_, err = channel_domain.ParsePolicy(policy)
+	require.ErrorIs(t, err, channel_domain.ErrPolicyVersionUnsupported)
+}
+
+func TestPolicyDocument_Validate(t *testing.T) {
+	channel := newTestChannel()
+	require.NoError(t, channel.AddSlot(testSlotOne()))
+
+	valid := channel_domain.DefaultPolicy()
+	valid.Invite.Roles = []string{"lead-engineer"}
+	require.NoError(t, valid.Validate(&channel))
+
+	unknownRole := channel_domain.DefaultPolicy()
+	unknownRole.ThreadEvents = map[string][]string{"treasurer": {"payment.released"}}
+	require.ErrorIs(t, unknownRole.Validate(&channel), channel_domain.ErrPolicyUnknownRole)
+
+	badApproval := channel_domain.DefaultPolicy()
+	badApproval.Approvals = []channel_domain.ApprovalRequirement{{EventType: "payment.released", ApprovalEventType: "finance.approved"}}
+	require.ErrorIs(t, badApproval.Validate(&channel), channel_domain.ErrPolicyInvalid)
+
+	negativeRetention := channel_domain.DefaultPolicy()
+	negativeRetention.Retention.RetainDays = -1
+	require.ErrorIs(t, negativeRetention.Validate(&channel), channel_domain.ErrPolicyInvalid)
+}
+
+func TestChannel_RolesOf(t *testing.T) {
+	channel := governedChannel(t, channel_domain.DefaultPolicy())
+
+	require.Equal(t, []string{"lead-engineer"}, channel.RolesOf("vault-oem"))
+	require.Equal(t, []string{"reviewer"}, channel.RolesOf("vault-regulator"))
+	require.Equal(t, []string{"reviewer"}, channel.RolesOf("vault-auditor"))
+	require.Empty(t, channel.RolesOf("vault-stranger"))
+}
+
+func TestPolicyEvaluator(t *testing.T) {
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	doc := channel_domain.DefaultPolicy()
+	doc.Invite = channel_domain.InvitePolicy{Roles: []string{"lead-engineer"}, RequireInvitation: true}
+	doc.ThreadEvents = map[string][]string{
+		"lead-engineer": {"invoice.created", "payment.released"},
+		"reviewer":      {"finance.approved"},
+	}
+	doc.Approvals = []channel_domain.ApprovalRequirement{{EventType: "payment.released", ApprovalEventType: "finance.approved", Count: 2}}
+	doc.AllowedAssetTypes = []string{"invoice"}
+	channel := governedChannel(t, doc)
+
+	require.NoError(t, evaluator.CanInvite(&channel, "vault-oem"))
+	require.ErrorIs(t, evaluator.CanInvite(&channel, "vault-regulator"), channel_domain.ErrPolicyDenied)
+	require.ErrorIs(t, evaluator.CanJoin(&channel), channel_domain.ErrPolicyDenied)
+
+	require.NoError(t, evaluator.CanAppendEvent(&channel, "vault-oem", "invoice.created"))
+	require.NoError(t, evaluator.CanAppendEvent(&channel, "vault-auditor", "finance.approved"))
+	require.ErrorIs(t, evaluator.CanAppendEvent(&channel, "vault-regulator", "payment.released"), channel_domain.ErrPolicyDenied)
+	require.ErrorIs(t, evaluator.CanAppendEvent(&channel, "vault-stranger", "invoice.created"), channel_domain.ErrPolicyDenied)
+
+	require.True(t, evaluator.RequiresApprovals(&channel, "payment.released"))
+	require.False(t, evaluator.RequiresApprovals(&channel, "invoice.created"))
+	approval := func(vaultID string) channel_domain.ThreadHistoryEntry {
+		return channel_domain.ThreadHistoryEntry{EventType: "finance.approved", ActorVaultID: vaultID}
+	}
+	released := channel_domain.ThreadHistoryEntry{EventType: "payment.released", ActorVaultID: "vault-oem"}
+	require.ErrorIs(t, evaluator.CheckApprovals(&channel, "payment.released", []channel_domain.ThreadHistoryEntry{{EventType: "invoice.created", ActorVaultID: "vault-oem"}, approval("vault-auditor")}), channel_domain.ErrPolicyApprovalsMissing)
+	require.ErrorIs(t, evaluator.CheckApprovals(&channel, "payment.released", []channel_domain.ThreadHistoryEntry{approval("vault-auditor"), approval("vault-auditor")}), channel_domain.ErrPolicyApprovalsMissing, "one vault approving twice counts once")
+	require.ErrorIs(t, evaluator.CheckApprovals(&channel, "payment.released", []channel_domain.ThreadHistoryEntry{approval("vault-auditor"), approval("")}), channel_domain.ErrPolicyApprovalsMissing, "anonymous approvals do not count")
+	require.NoError(t, evaluator.CheckApprovals(&channel, "payment.released", []channel_domain.ThreadHistoryEntry{approval("vault-auditor"), approval("vault-cfo")}))
+	require.ErrorIs(t, evaluator.CheckApprovals(&channel, "payment.released", []channel_domain.ThreadHistoryEntry{approval("vault-auditor"), approval("vault-cfo"), released, approval("vault-cfo")}), channel_domain.ErrPolicyApprovalsMissing, "approvals are spent by the release they gated")
+
+	require.NoError(t, evaluator.CanUseAssetType(&channel, "invoice"))
+	require.ErrorIs(t, evaluator.CanUseAssetType(&channel, "cad-drawing"), channel_domain.ErrPolicyAssetTypeNotAllowed)
+
+	require.ErrorIs(t, evaluator.CanReopenThread(&channel), channel_domain.ErrPolicyDenied)
+}
+
+func TestPolicyEvaluator_ExpiredChannelDeniesEverything(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := channel_domain.DefaultPolicy()
+	doc.Retention.ExpiresAt = &expiresAt
+	doc.Retention.RetainDays = 30
+	channel := governedChannel(t, doc)
+
+	evaluator := &channel_domain.PolicyEvaluator{Now: func() time.Time { return expiresAt.Add(-time.Minute) }}
+	require.NoError(t, evaluator.CheckActive(&channel))
+
+	evaluator.Now = func() time.Time { return expiresAt }
+	require.ErrorIs(t, evaluator.CheckActive(&channel), channel_domain.ErrPolicyChannelExpired)
+	require.ErrorIs(t, evaluator.CanInvite(&channel, "vault-oem"), channel_domain.ErrPolicyChannelExpired)
+	require.ErrorIs(t, evaluator.CanAppendEvent(&channel, "vault-oem", "invoice.created"), channel_domain.ErrPolicyChannelExpired)
+
+	closedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, closedAt.AddDate(0, 0, 30), *doc.RetainUntil(closedAt))
+}
+
+func TestPolicyEvaluator_CheckRetention(t *testing.T) {
+	doc := channel_domain.DefaultPolicy()
+	doc.Retention.RetainDays = 30
+	channel := governedChannel(t, doc)
+	closedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	evaluator := &channel_domain.PolicyEvaluator{Now: func() time.Time { return closedAt.AddDate(0, 0, 29) }}
+	require.NoError(t, evaluator.CheckRetention(&channel, &closedAt))
+	require.NoError(t, evaluator.CheckRetention(&channel, nil))
+
+	evaluator.Now = func() time.Time { return closedAt.AddDate(0, 0, 30) }
+	require.ErrorIs(t, evaluator.CheckRetention(&channel, &closedAt), channel_domain.ErrPolicyRetentionElapsed)
+
+	forever := governedChannel(t, channel_domain.DefaultPolicy())
+	require.NoError(t, evaluator.CheckRetention(&forever, &closedAt))
+}
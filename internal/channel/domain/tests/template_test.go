@@ -0,0 +1,162 @@
+package channel_tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+func reviewTemplate() channel_domain.ChannelTemplate {
+	return channel_domain.ChannelTemplate{
+		ID:      "design-review",
+		Version: 1,
+		Title:   "Design review",
+		Slots: []channel_domain.Slot{
+			{ID: "slot-001", Name: "Battery Engineering", Role: "lead-engineer", Gated: true, Order: 1},
+			{ID: "slot-002", Name: "Safety Review", Role: "reviewer", Order: 2},
+		},
+		DefaultPolicy: channel_domain.PolicyDocument{
+			ThreadEvents: map[string][]string{"reviewer": {"review.approved"}},
+		},
+		RequiredProperties: []string{"program"},
+		AssetType:          "cad-drawing",
+	}
+}
+
+func TestChannelTemplate_Validate(t *testing.T) {
+	require.NoError(t, reviewTemplate().Validate())
+
+	tpl := reviewTemplate()
+	tpl.Slots = append(tpl.Slots, tpl.Slots[0])
+	require.ErrorIs(t, tpl.Validate(), channel_domain.ErrTemplateInvalid)
+
+	tpl = reviewTemplate()
+	tpl.DefaultPolicy.ThreadEvents = map[string][]string{"ghost": {"x"}}
+	require.ErrorIs(t, tpl.Validate(), channel_domain.ErrPolicyUnknownRole)
+
+	tpl = reviewTemplate()
+	tpl.DefaultPolicy.AllowedAssetTypes = []string{"invoice"}
+	require.ErrorIs(t, tpl.Validate(), channel_domain.ErrTemplateInvalid, "the default policy must allow the template asset type")
+}
+
+func TestChannelTemplate_Instantiate(t *testing.T) {
+	tpl := reviewTemplate()
+
+	_, err := tpl.Instantiate("", "workspace-001", nil, nil)
+	require.ErrorIs(t, err, channel_domain.ErrTemplatePropertyMissing)
+
+	_, err = tpl.Instantiate("", "workspace-001",
+		[]channel_domain.Assignment{{SlotID: "slot-999", OwnerID: "vault-oem"}},
+		[]channel_domain.ChannelProperty{{Key: "program", Value: "EV-2"}},
+	)
+	require.ErrorIs(t, err, channel_domain.ErrSlotNotFound)
+
+	channel, err := tpl.Instantiate("", "workspace-001",
+		[]channel_domain.Assignment{{SlotID: "slot-001", OwnerID: "vault-oem"}},
+		[]channel_domain.ChannelProperty{{Key: "program", Value: "EV-2"}, {Key: channel_domain.TemplateVersionProperty, Value: "42"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "design-review", channel.TemplateID)
+	require.Equal(t, "Design review", channel.Title)
+	require.Len(t, channel.Slots, 2)
+	require.Equal(t, 1, channel_domain.TemplateVersionOf(&channel))
+
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cad-drawing"}, doc.AllowedAssetTypes)
+	require.NoError(t, channel_domain.NewPolicyEvaluator().CanUseAssetType(&channel, "cad-drawing"))
+}
+
+func TestChannelTemplate_MigrateChannel(t *testing.T) {
+	v1 := reviewTemplate()
+	channel, err := v1.Instantiate("", "workspace-001",
+		[]channel_domain.Assignment{{SlotID: "slot-001", OwnerID: "vault-oem"}},
+		[]channel_domain.ChannelProperty{{Key: "program", Value: "EV-2"}},
+	)
+	require.NoError(t, err)
+
+	v2 := reviewTemplate()
+	v2.Version = 2
+	v2.Slots = []channel_domain.Slot{
+		{ID: "slot-001", Name: "Battery Engineering", Role: "lead-engineer", Gated: true, Order: 1},
+		{ID: "slot-002", Name: "Safety Review", Role: "safety-reviewer", Gated: true, Order: 2},
+		{ID: "slot-003", Name: "Homologation", Role: "homologation", Order: 3},
+	}
+	v2.DefaultPolicy.ThreadEvents = map[string][]string{"safety-reviewer": {"review.approved"}}
+	v2.RequiredProperties = []string{"program", "market"}
+	v2.Migrations = []channel_domain.TemplateMigrationStep{
+		{ToVersion: 2, RenameRoles: map[string]string{"reviewer": "safety-reviewer"}},
+	}
+
+	before := channel
+	_, err = v2.MigrateChannel(&channel, nil)
+	require.ErrorIs(t, err, channel_domain.ErrTemplatePropertyMissing)
+	require.Equal(t, before, channel, "a failed migration leaves the channel untouched")
+
+	report, err := v2.MigrateChannel(&channel, []channel_domain.ChannelProperty{{Key: "market", Value: "EU"}})
+	require.NoError(t, err)
+	require.True(t, report.Changed())
+	require.Equal(t, 1, report.FromVersion)
+	require.Equal(t, []string{"slot-003"}, report.AddedSlots)
+	require.Equal(t, []string{"slot-002"}, report.UpdatedSlots)
+	require.True(t, report.PolicyReplaced)
+	require.Equal(t, 2, channel_domain.TemplateVersionOf(&channel))
+
+	slot, ok := channel.GetSlotByID("slot-002")
+	require.True(t, ok)
+	require.Equal(t, "safety-reviewer", slot.Role)
+	require.True(t, slot.Gated)
+	_, assigned := channel.GetAssignmentBySlotID("slot-001")
+	require.True(t, assigned)
+
+	report, err = v2.MigrateChannel(&channel, nil)
+	require.NoError(t, err)
+	require.False(t, report.Changed())
+
+	_, err = v1.MigrateChannel(&channel, nil)
+	require.ErrorIs(t, err, channel_domain.ErrTemplateVersionUnsupported)
+}
+
+func TestChannelTemplate_MigrateKeepsEditedPolicyAndGuardsAssignedSlots(t *testing.T) {
+	v1 := reviewTemplate()
+	channel, err := v1.Instantiate("", "workspace-001",
+		[]channel_domain.Assignment{{SlotID: "slot-002", OwnerID: "vault-regulator"}},
+		[]channel_domain.ChannelProperty{{Key: "program", Value: "EV-2"}},
+	)
+	require.NoError(t, err)
+
+	doc, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+	doc.Revision = 3
+	doc.Invite.Roles = []string{"reviewer"}
+	doc.GatedSlots.ApproverRoles = []string{"reviewer"}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channel.SetPolicy(policy)
+
+	v2 := reviewTemplate()
+	v2.Version = 2
+	v2.Slots = v2.Slots[:1]
+	v2.DefaultPolicy.ThreadEvents = nil
+	v2.Migrations = []channel_domain.TemplateMigrationStep{{ToVersion: 2, RemoveSlots: []string{"slot-002"}}}
+
+	_, err = v2.MigrateChannel(&channel, nil)
+	require.ErrorIs(t, err, channel_domain.ErrTemplateMigrationConflict)
+
+	v2.Slots = append(v2.Slots, channel_domain.Slot{ID: "slot-002", Name: "Safety Review", Role: "assessor", Order: 2})
+	v2.DefaultPolicy.ThreadEvents = map[string][]string{"assessor": {"review.approved"}}
+	v2.Migrations = []channel_domain.TemplateMigrationStep{{ToVersion: 2, RenameRoles: map[string]string{"reviewer": "assessor"}}}
+
+	report, err := v2.MigrateChannel(&channel, nil)
+	require.NoError(t, err)
+	require.False(t, report.PolicyReplaced)
+
+	migrated, err := channel_domain.ParsePolicy(channel.Policy)
+	require.NoError(t, err)
+	require.Equal(t, 4, migrated.Revision)
+	require.Equal(t, []string{"assessor"}, migrated.Invite.Roles)
+	require.Equal(t, []string{"assessor"}, migrated.GatedSlots.ApproverRoles)
+	require.Equal(t, map[string][]string{"assessor": {"review.approved"}}, migrated.ThreadEvents)
+}
@@ -0,0 +1,152 @@
+package channel_domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ==============================================================================
+// Federation trust
+// ==============================================================================
+
+// Reasons recorded when trust changes without an operator-supplied reason.
+const (
+	TrustReasonKeyChanged = "key_changed"
+)
+
+// IdentityDocument is what a vault presents during the federation handshake.
+// It is signed with the vault's Stellar key, which the peer pins on approval.
+type IdentityDocument struct {
+	VaultID         string    `json:"vault_id"`
+	PublicKey       string    `json:"public_key"`
+	Endpoint        string    `json:"endpoint"`
+	ProtocolVersion string    `json:"protocol_version"`
+	IssuedAt        time.Time `json:"issued_at"`
+	// Signature is the base64 signature over SigningBytes.
+	Signature string `json:"signature,omitempty"`
+}
+
+func (d IdentityDocument) Validate() error {
+	if d.VaultID == "" {
+		return fmt.Errorf("%w: vault id is required", ErrIdentityDocumentInvalid)
+	}
+	if d.PublicKey == "" {
+		return fmt.Errorf("%w: public key is required", ErrIdentityDocumentInvalid)
+	}
+	if d.ProtocolVersion == "" {
+		return fmt.Errorf("%w: protocol version is required", ErrIdentityDocumentInvalid)
+	}
+	return nil
+}
+
+// SigningBytes returns the bytes the signature covers: the document with its
+// signature cleared.
+func (d IdentityDocument) SigningBytes() ([]byte, error) {
+	d.Signature = ""
+	return json.Marshal(d)
+}
+
+func (r *RemoteVault) setTrust(state string, reason string, now time.Time) {
+	r.TrustState = TrustState(state)
+	r.TrustReason = reason
+	r.TrustChangedAt = now
+}
+
+// KeyChangePending reports whether a pinned remote presented a different key
+// that still needs approval.
+func (r RemoteVault) KeyChangePending() bool {
+	return r.PublicKey != "" && r.PresentedKey != "" && r.PresentedKey != r.PublicKey
+}
+
+// PresentIdentity records a verified identity document. A remote without a
+// pinned key becomes pending. A document carrying the pinned key refreshes
+// the endpoint and protocol version. A different key suspends the remote
+// until it is approved; the endpoint is left untouched so an unapproved key
+// cannot redirect sync. A document issued no later than the one on record is
+// a replay and is refused. It reports whether the key changed.
+func (r *RemoteVault) PresentIdentity(doc IdentityDocument, now time.Time) (bool, error) {
+	if r.TrustState == TrustState(TrustRevoked) {
+		return false, ErrRemoteVaultRevoked
+	}
+	if !r.IdentityIssuedAt.IsZero() && !doc.IssuedAt.After(r.IdentityIssuedAt) {
+		return false, fmt.Errorf("%w: issued %s, on record %s", ErrIdentityReplayed,
+			doc.IssuedAt.Format(time.RFC3339Nano), r.IdentityIssuedAt.Format(time.RFC3339Nano))
+	}
+	r.LastSeen = now
+	r.IdentityIssuedAt = doc.IssuedAt
+
+	if r.PublicKey == "" {
+		r.PresentedKey = doc.PublicKey
+		r.Endpoint = doc.Endpoint
+		r.ProtocolVersion = doc.ProtocolVersion
+		r.setTrust(TrustPending, "", now)
+		return false, nil
+	}
+
+	if doc.PublicKey == r.PublicKey {
+		r.PresentedKey = ""
+		if doc.Endpoint != "" {
+			r.Endpoint = doc.Endpoint
+		}
+		r.ProtocolVersion = doc.ProtocolVersion
+		return false, nil
+	}
+
+	r.PresentedKey = doc.PublicKey
+	if r.TrustState != TrustState(TrustPending) {
+		r.setTrust(TrustSuspended, TrustReasonKeyChanged, now)
+	}
+	return true, nil
+}
+
+// ApproveTrust pins the presented key, or keeps the pinned one, and trusts
+// the remote. expectedKey must be the key being pinned, so the operator
+// approves exactly the key they compared out of band.
+func (r *RemoteVault) ApproveTrust(expectedKey string, now time.Time) error {
+	if r.TrustState == TrustState(TrustRevoked) {
+		return ErrRemoteVaultRevoked
+	}
+
+	key := r.PresentedKey
+	if key == "" {
+		key = r.PublicKey
+	}
+	if key == "" {
+		return ErrRemoteKeyRequired
+	}
+	if expectedKey != key {
+		return ErrRemoteKeyMismatch
+	}
+
+	r.PublicKey = key
+	r.PresentedKey = ""
+	r.setTrust(Trusted, "", now)
+	return nil
+}
+
+// SuspendTrust stops sync to a trusted remote until it is approved again.
+func (r *RemoteVault) SuspendTrust(reason string, now time.Time) error {
+	switch r.TrustState {
+	case TrustState(TrustSuspended):
+		return nil
+	case TrustState(Trusted):
+		r.setTrust(TrustSuspended, reason, now)
+		return nil
+	}
+	return fmt.Errorf("%w: %s → %s", ErrTrustTransition, r.TrustState, TrustSuspended)
+}
+
+// RevokeTrust permanently distrusts the remote and drops its undelivered
+// sync items. It returns how many items were dropped.
+func (r *RemoteVault) RevokeTrust(reason string, now time.Time) int {
+	if r.TrustState == TrustState(TrustRevoked) {
+		return 0
+	}
+
+	dropped := len(r.Pending)
+	r.Pending = nil
+	r.PresentedKey = ""
+	r.setTrust(TrustRevoked, reason, now)
+	return dropped
+}
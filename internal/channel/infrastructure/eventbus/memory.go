@@ -9,11 +9,16 @@ import (
 )
 
 type MemoryEventBus struct {
-	mu                         sync.RWMutex
-	createdHandlers            []func(ctx context.Context, event channel_domain.ChannelCreated)
-	revokedHandlers            []func(ctx context.Context, event channel_domain.ChannelRevoked)
-	deletedHandlers            []func(ctx context.Context, event channel_domain.ChannelDeleted)
-	archivedHandlers           []func(ctx context.Context, event channel_domain.ChannelArchived)
+	mu                             sync.RWMutex
+	createdHandlers                []func(ctx context.Context, event channel_domain.ChannelCreated)
+	revokedHandlers                []func(ctx context.Context, event channel_domain.ChannelRevoked)
+	deletedHandlers                []func(ctx context.Context, event channel_domain.ChannelDeleted)
+	archivedHandlers               []func(ctx context.Context, event channel_domain.ChannelArchived)
+	permissionGrantedHandlers      []func(ctx context.Context, event channel_domain.ChannelPermissionGranted)
+	permissionRevokedHandlers      []func(ctx context.Context, event channel_domain.ChannelPermissionRevoked)
+	slotOccupancyRequestedHandlers []func(ctx context.Context, event channel_domain.SlotOccupancyRequested)
+	slotOccupancyVotedHandlers     []func(ctx context.Context, event channel_domain.SlotOccupancyVoted)
+	slotFilledHandlers             []func(ctx context.Context, event channel_domain.SlotFilled)
 }
 
 func NewMemoryEventBus() channel_events.ChannelEventBus {
@@ -91,3 +96,93 @@ func (b *MemoryEventBus) SubscribeToChannelArchived(handler func(ctx context.Con
 	b.archivedHandlers = append(b.archivedHandlers, handler)
 	return nil
 }
+
+func (b *MemoryEventBus) PublishChannelPermissionGranted(ctx context.Context, event channel_domain.ChannelPermissionGranted) error {
+	b.mu.RLock()
+	handlers := append([]func(ctx context.Context, event channel_domain.ChannelPermissionGranted){}, b.permissionGrantedHandlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryEventBus) SubscribeToChannelPermissionGranted(handler func(ctx context.Context, event channel_domain.ChannelPermissionGranted)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.permissionGrantedHandlers = append(b.permissionGrantedHandlers, handler)
+	return nil
+}
+
+func (b *MemoryEventBus) PublishChannelPermissionRevoked(ctx context.Context, event channel_domain.ChannelPermissionRevoked) error {
+	b.mu.RLock()
+	handlers := append([]func(ctx context.Context, event channel_domain.ChannelPermissionRevoked){}, b.permissionRevokedHandlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryEventBus) SubscribeToChannelPermissionRevoked(handler func(ctx context.Context, event channel_domain.ChannelPermissionRevoked)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.permissionRevokedHandlers = append(b.permissionRevokedHandlers, handler)
+	return nil
+}
+
+func (b *MemoryEventBus) PublishSlotOccupancyRequested(ctx context.Context, event channel_domain.SlotOccupancyRequested) error {
+	b.mu.RLock()
+	handlers := append([]func(ctx context.Context, event channel_domain.SlotOccupancyRequested){}, b.slotOccupancyRequestedHandlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryEventBus) SubscribeToSlotOccupancyRequested(handler func(ctx context.Context, event channel_domain.SlotOccupancyRequested)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.slotOccupancyRequestedHandlers = append(b.slotOccupancyRequestedHandlers, handler)
+	return nil
+}
+
+func (b *MemoryEventBus) PublishSlotOccupancyVoted(ctx context.Context, event channel_domain.SlotOccupancyVoted) error {
+	b.mu.RLock()
+	handlers := append([]func(ctx context.Context, event channel_domain.SlotOccupancyVoted){}, b.slotOccupancyVotedHandlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryEventBus) SubscribeToSlotOccupancyVoted(handler func(ctx context.Context, event channel_domain.SlotOccupancyVoted)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.slotOccupancyVotedHandlers = append(b.slotOccupancyVotedHandlers, handler)
+	return nil
+}
+
+func (b *MemoryEventBus) PublishSlotFilled(ctx context.Context, event channel_domain.SlotFilled) error {
+	b.mu.RLock()
+	handlers := append([]func(ctx context.Context, event channel_domain.SlotFilled){}, b.slotFilledHandlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryEventBus) SubscribeToSlotFilled(handler func(ctx context.Context, event channel_domain.SlotFilled)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.slotFilledHandlers = append(b.slotFilledHandlers, handler)
+	return nil
+}
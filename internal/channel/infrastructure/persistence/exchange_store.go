@@ -0,0 +1,32 @@
+package channel_persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	channel_domain "vault-app/internal/channel/domain"
+	shared_offline "vault-app/internal/shared/offline"
+)
+
+// ThreadEventExchanges loads federation exchanges from the thread events in
+// the local read model: an exchange id is the id of the thread event it
+// relays, and the payload is the event as cached.
+type ThreadEventExchanges struct {
+	store *shared_offline.Store
+}
+
+func NewThreadEventExchanges(store *shared_offline.Store) *ThreadEventExchanges {
+	return &ThreadEventExchanges{store: store}
+}
+
+func (s *ThreadEventExchanges) LoadExchange(ctx context.Context, exchangeID string) (json.RawMessage, error) {
+	var payload json.RawMessage
+	if err := s.store.Get(ctx, shared_offline.KindThreadEvent, exchangeID, &payload); err != nil {
+		if errors.Is(err, shared_offline.ErrNotCached) {
+			return nil, channel_domain.ErrExchangeNotFound
+		}
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,182 @@
+package channel_persistence
+
+import (
+	"context"
+	"fmt"
+
+	channel_domain "vault-app/internal/channel/domain"
+	shared_offline "vault-app/internal/shared/offline"
+	vault_session "vault-app/internal/vault/application/session"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// FederationScope prefixes the listing scope the remote vaults are stored
+// under; each user has their own.
+const FederationScope = "federation"
+
+// VaultSessions gives access to the open vault of a user. The vault session
+// manager implements it.
+type VaultSessions interface {
+	GetSession(userID string) (*vault_session.Session, error)
+	SetVault(userID string, vault *vaults_domain.VaultPayload) error
+	MarkDirty(userID string)
+}
+
+// FederationStore persists each user's federation snapshot in the
+// FederationSnapshot of their vault DAG, and keeps a copy in the local read
+// model, one record per remote vault, so the sync engine survives restarts.
+// The DAG copy wins while the vault is open.
+type FederationStore struct {
+	store  *shared_offline.Store
+	vaults VaultSessions
+}
+
+func NewFederationStore(store *shared_offline.Store) *FederationStore {
+	return &FederationStore{store: store}
+}
+
+// WithVaultSessions writes the snapshot through to the open vault's DAG.
+func (s *FederationStore) WithVaultSessions(vaults VaultSessions) *FederationStore {
+	s.vaults = vaults
+	return s
+}
+
+func federationScope(userID string) string {
+	return FederationScope + ":" + userID
+}
+
+func (s *FederationStore) LoadFederation(ctx context.Context, userID string) (channel_domain.FederationSnapshot, error) {
+	if userID == "" {
+		return channel_domain.FederationSnapshot{}, channel_domain.ErrUserIDRequired
+	}
+
+	if payload, ok, err := s.openVault(userID); err != nil {
+		return channel_domain.FederationSnapshot{}, err
+	} else if ok && len(payload.Collaborative.Federation.RemoteVaults) > 0 {
+		return fromVaultFederation(payload.Collaborative.Federation), nil
+	}
+
+	recs, err := s.store.List(ctx, shared_offline.KindRemoteVault, federationScope(userID))
+	if err != nil {
+		return channel_domain.FederationSnapshot{}, err
+	}
+
+	remotes, err := shared_offline.Decode[channel_domain.RemoteVault](recs)
+	if err != nil {
+		return channel_domain.FederationSnapshot{}, err
+	}
+
+	return channel_domain.FederationSnapshot{RemoteVaults: remotes}, nil
+}
+
+func (s *FederationStore) SaveFederation(ctx context.Context, userID string, snapshot channel_domain.FederationSnapshot) error {
+	if userID == "" {
+		return channel_domain.ErrUserIDRequired
+	}
+
+	scope := federationScope(userID)
+	items := make([]shared_offline.Item, 0, len(snapshot.RemoteVaults))
+	for _, remote := range snapshot.RemoteVaults {
+		items = append(items, shared_offline.Item{ID: scope + "/" + remote.VaultID, Value: remote})
+	}
+	if err := s.store.Replace(ctx, shared_offline.KindRemoteVault, scope, items); err != nil {
+		return err
+	}
+
+	payload, ok, err := s.openVault(userID)
+	if err != nil || !ok {
+		return err
+	}
+	payload.Collaborative.Federation = toVaultFederation(snapshot)
+	if err := s.vaults.SetVault(userID, payload); err != nil {
+		return err
+	}
+	s.vaults.MarkDirty(userID)
+
+	return nil
+}
+
+// openVault decodes the user's open vault. ok is false when no vault
+// sessions are wired or the user has no open vault.
+func (s *FederationStore) openVault(userID string) (*vaults_domain.VaultPayload, bool, error) {
+	if s.vaults == nil {
+		return nil, false, nil
+	}
+	session, err := s.vaults.GetSession(userID)
+	if err != nil || session == nil || len(session.Vault) == 0 {
+		return nil, false, nil
+	}
+	payload, err := vault_session.DecodeSessionVault(session.Vault)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode vault for federation snapshot: %w", err)
+	}
+	return payload, true, nil
+}
+
+func toVaultFederation(snapshot channel_domain.FederationSnapshot) vaults_domain.FederationSnapshot {
+	remotes := make([]vaults_domain.RemoteVault, 0, len(snapshot.RemoteVaults))
+	for _, remote := range snapshot.RemoteVaults {
+		pending := make([]vaults_domain.PendingSyncItem, 0, len(remote.Pending))
+		for _, item := range remote.Pending {
+			pending = append(pending, vaults_domain.PendingSyncItem{
+				ExchangeID:    item.ExchangeID,
+				Status:        string(item.Status),
+				Cursor:        item.Cursor,
+				RetryCount:    item.RetryCount,
+				UpdatedAt:     item.UpdatedAt,
+				NextAttemptAt: item.NextAttemptAt,
+				LastError:     item.LastError,
+			})
+		}
+		remotes = append(remotes, vaults_domain.RemoteVault{
+			VaultID:          remote.VaultID,
+			LastCursor:       remote.LastCursor,
+			LastSeen:         remote.LastSeen,
+			Endpoint:         remote.Endpoint,
+			TrustState:       vaults_domain.TrustState(remote.TrustState),
+			Pending:          pending,
+			ProtocolVersion:  remote.ProtocolVersion,
+			PublicKey:        remote.PublicKey,
+			PresentedKey:     remote.PresentedKey,
+			TrustChangedAt:   remote.TrustChangedAt,
+			TrustReason:      remote.TrustReason,
+			IdentityIssuedAt: remote.IdentityIssuedAt,
+		})
+	}
+
+	return vaults_domain.FederationSnapshot{RemoteVaults: remotes, IsDirty: true}
+}
+
+func fromVaultFederation(snapshot vaults_domain.FederationSnapshot) channel_domain.FederationSnapshot {
+	remotes := make([]channel_domain.RemoteVault, 0, len(snapshot.RemoteVaults))
+	for _, remote := range snapshot.RemoteVaults {
+		pending := make([]channel_domain.PendingSyncItem, 0, len(remote.Pending))
+		for _, item := range remote.Pending {
+			pending = append(pending, channel_domain.PendingSyncItem{
+				ExchangeID:    item.ExchangeID,
+				Status:        channel_domain.SyncStatus(item.Status),
+				Cursor:        item.Cursor,
+				RetryCount:    item.RetryCount,
+				UpdatedAt:     item.UpdatedAt,
+				NextAttemptAt: item.NextAttemptAt,
+				LastError:     item.LastError,
+			})
+		}
+		remotes = append(remotes, channel_domain.RemoteVault{
+			VaultID:          remote.VaultID,
+			LastCursor:       remote.LastCursor,
+			LastSeen:         remote.LastSeen,
+			Endpoint:         remote.Endpoint,
+			TrustState:       channel_domain.TrustState(remote.TrustState),
+			Pending:          pending,
+			ProtocolVersion:  remote.ProtocolVersion,
+			PublicKey:        remote.PublicKey,
+			PresentedKey:     remote.PresentedKey,
+			TrustChangedAt:   remote.TrustChangedAt,
+			TrustReason:      remote.TrustReason,
+			IdentityIssuedAt: remote.IdentityIssuedAt,
+		})
+	}
+
+	return channel_domain.FederationSnapshot{RemoteVaults: remotes}
+}
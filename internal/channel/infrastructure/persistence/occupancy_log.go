@@ -0,0 +1,228 @@
+package channel_persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// occupancyWithdrawal is the payload of a withdrawn event.
+type occupancyWithdrawal struct {
+	RequestID   string     `json:"request_id"`
+	WithdrawnBy string     `json:"withdrawn_by"`
+	WithdrawnAt *time.Time `json:"withdrawn_at"`
+}
+
+// ChannelReader is the part of the channel repository replay needs to read
+// a channel's current policy.
+type ChannelReader interface {
+	GetChannel(ctx context.Context, req *channel_domain.GetChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error)
+}
+
+// OccupancyEventLog keeps gated slot occupancy requests in the channel's
+// Cloud event log, so the approvers' vaults all see the same requests and
+// votes. A request is a requested event, then one event per signed vote and
+// a withdrawn event if it is withdrawn. The local OccupancyStore caches the
+// replayed requests: listing a channel refreshes it, and lookups by request
+// id are served from it.
+//
+// Anyone in the channel can append to its log, so replay trusts none of it
+// on its face: requests are bounded by the channel's policy, votes are
+// verified as SubmitVote verified them and withdrawals must come from a
+// vault allowed to withdraw.
+type OccupancyEventLog struct {
+	log      channel_domain.ChannelEventLog
+	cache    *OccupancyStore
+	channels ChannelReader
+	verifier channel_domain.OccupancyVoteVerifier
+}
+
+func NewOccupancyEventLog(log channel_domain.ChannelEventLog, cache *OccupancyStore) *OccupancyEventLog {
+	return &OccupancyEventLog{log: log, cache: cache}
+}
+
+// WithVerification sets where replay reads the channel and its policy from
+// and who verifies the logged votes. Without it, no logged vote counts.
+func (s *OccupancyEventLog) WithVerification(channels ChannelReader, verifier channel_domain.OccupancyVoteVerifier) *OccupancyEventLog {
+	s.channels = channels
+	s.verifier = verifier
+	return s
+}
+
+// SaveOccupancyRequest appends what r adds to the logged request: the
+// request itself when it is new, its new votes and its withdrawal. The
+// cache is only updated once the log accepted every event.
+func (s *OccupancyEventLog) SaveOccupancyRequest(ctx context.Context, r channel_domain.OccupancyRequest) error {
+	requests, err := s.replay(ctx, r.ChannelID)
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(requests, func(logged channel_domain.OccupancyRequest) bool { return logged.ID == r.ID })
+
+	var logged channel_domain.OccupancyRequest
+	if idx < 0 {
+		logged = r
+		logged.Status, logged.DecidedAt, logged.Votes = channel_domain.OccupancyPending, nil, nil
+		if err := s.append(ctx, r.ChannelID, channel_domain.ChannelEventOccupancyRequested, r.RequestedBy, r.ID, logged); err != nil {
+			return err
+		}
+	} else {
+		logged = requests[idx]
+	}
+
+	for _, vote := range r.Votes {
+		if slices.ContainsFunc(logged.Votes, func(v channel_domain.OccupancyVote) bool { return v.VaultID == vote.VaultID }) {
+			continue
+		}
+		if err := s.append(ctx, r.ChannelID, channel_domain.ChannelEventOccupancyVoted, vote.VaultID, r.ID+":vote:"+vote.VaultID, vote); err != nil {
+			return err
+		}
+	}
+
+	if r.Status == channel_domain.OccupancyWithdrawn && logged.Status != channel_domain.OccupancyWithdrawn {
+		withdrawal := occupancyWithdrawal{RequestID: r.ID, WithdrawnBy: r.WithdrawnBy, WithdrawnAt: r.DecidedAt}
+		if err := s.append(ctx, r.ChannelID, channel_domain.ChannelEventOccupancyWithdrawn, r.WithdrawnBy, r.ID+":withdrawn", withdrawal); err != nil {
+			return err
+		}
+	}
+
+	return s.cache.SaveOccupancyRequest(ctx, r)
+}
+
+func (s *OccupancyEventLog) GetOccupancyRequest(ctx context.Context, requestID string) (*channel_domain.OccupancyRequest, error) {
+	return s.cache.GetOccupancyRequest(ctx, requestID)
+}
+
+// ListOccupancyRequests replays the channel's log and refreshes the cache.
+func (s *OccupancyEventLog) ListOccupancyRequests(ctx context.Context, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	requests, err := s.replay(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range requests {
+		if err := s.cache.SaveOccupancyRequest(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return requests, nil
+}
+
+func (s *OccupancyEventLog) append(ctx context.Context, channelID string, eventType string, actorVaultID string, key string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = s.log.AppendChannelEvent(ctx, &channel_domain.AppendChannelEventRequest{
+		ChannelID:      channelID,
+		Type:           eventType,
+		ActorVaultID:   actorVaultID,
+		Payload:        raw,
+		IdempotencyKey: "occupancy:" + key,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to log %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// replay rebuilds the channel's occupancy requests, in request order, from
+// its event log. Each request is bounded by the channel's current policy and
+// each vote is verified before OccupancyRequest.Vote applies it, so the
+// decision is the one SubmitVote made. Events that fail these checks are
+// skipped: they were never accepted by a vault following the rules.
+func (s *OccupancyEventLog) replay(ctx context.Context, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	if channelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+	resp, err := s.log.ListChannelEvents(ctx, &channel_domain.ListChannelEventsRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, err
+	}
+
+	requests := []channel_domain.OccupancyRequest{}
+	if resp == nil || len(resp.Data) == 0 {
+		return requests, nil
+	}
+	channel, policy, err := s.loadPolicy(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	find := func(requestID string) *channel_domain.OccupancyRequest {
+		if i := slices.IndexFunc(requests, func(r channel_domain.OccupancyRequest) bool { return r.ID == requestID }); i >= 0 {
+			return &requests[i]
+		}
+		return nil
+	}
+
+	for _, e := range resp.Data {
+		switch e.Type {
+		case channel_domain.ChannelEventOccupancyRequested:
+			var r channel_domain.OccupancyRequest
+			if err := json.Unmarshal(e.Payload, &r); err != nil {
+				return nil, fmt.Errorf("invalid %s event %s: %w", e.Type, e.ID, err)
+			}
+			if find(r.ID) != nil || r.ChannelID != channelID || r.RequestedBy != e.ActorVaultID {
+				continue
+			}
+			r.Status, r.DecidedAt, r.WithdrawnBy, r.Votes = channel_domain.OccupancyPending, nil, "", nil
+			if channel != nil {
+				r.BoundTo(channel, policy)
+			}
+			requests = append(requests, r)
+		case channel_domain.ChannelEventOccupancyVoted:
+			var vote channel_domain.OccupancyVote
+			if err := json.Unmarshal(e.Payload, &vote); err != nil {
+				return nil, fmt.Errorf("invalid %s event %s: %w", e.Type, e.ID, err)
+			}
+			r := find(vote.RequestID)
+			if r == nil || channel == nil || s.verifier == nil || vote.VaultID != e.ActorVaultID {
+				continue
+			}
+			if err := s.verifier.VerifyOccupancyVote(ctx, channel, *r, vote); err != nil {
+				continue
+			}
+			// A vote the request no longer accepts was superseded.
+			_ = r.Vote(vote, vote.VotedAt)
+		case channel_domain.ChannelEventOccupancyWithdrawn:
+			var w occupancyWithdrawal
+			if err := json.Unmarshal(e.Payload, &w); err != nil {
+				return nil, fmt.Errorf("invalid %s event %s: %w", e.Type, e.ID, err)
+			}
+			r := find(w.RequestID)
+			if r == nil || w.WithdrawnBy != e.ActorVaultID || w.WithdrawnAt == nil {
+				continue
+			}
+			// Withdraw only lets the requester or the candidate through.
+			_ = r.Withdraw(e.ActorVaultID, *w.WithdrawnAt)
+		}
+	}
+	return requests, nil
+}
+
+// loadPolicy returns the channel and its policy, or a nil channel when the
+// log has no channel repository to read them from.
+func (s *OccupancyEventLog) loadPolicy(ctx context.Context, channelID string) (*channel_domain.Channel, channel_domain.PolicyDocument, error) {
+	if s.channels == nil {
+		return nil, channel_domain.PolicyDocument{}, nil
+	}
+	resp, err := s.channels.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, channel_domain.PolicyDocument{}, err
+	}
+	if resp == nil || resp.Data.ID == "" {
+		return nil, channel_domain.PolicyDocument{}, channel_domain.ErrChannelNotFound
+	}
+	channel := resp.Data
+	policy, err := channel_domain.ParsePolicy(channel.Policy)
+	if err != nil {
+		return nil, channel_domain.PolicyDocument{}, err
+	}
+	return &channel, policy, nil
+}
+
+var _ channel_domain.OccupancyRequestRepository = (*OccupancyEventLog)(nil)
@@ -0,0 +1,48 @@
+package channel_persistence
+
+import (
+	"context"
+	"errors"
+
+	channel_domain "vault-app/internal/channel/domain"
+	shared_offline "vault-app/internal/shared/offline"
+)
+
+// OccupancyStore keeps gated slot occupancy requests in the local read
+// model, listed per channel in the order they were made.
+type OccupancyStore struct {
+	store *shared_offline.Store
+}
+
+func NewOccupancyStore(store *shared_offline.Store) *OccupancyStore {
+	return &OccupancyStore{store: store}
+}
+
+func (s *OccupancyStore) SaveOccupancyRequest(ctx context.Context, r channel_domain.OccupancyRequest) error {
+	return s.store.Put(ctx, shared_offline.KindOccupancy, r.ChannelID, shared_offline.Item{
+		ID:     r.ID,
+		Cursor: uint64(r.RequestedAt.UnixNano()),
+		Value:  r,
+	})
+}
+
+func (s *OccupancyStore) GetOccupancyRequest(ctx context.Context, requestID string) (*channel_domain.OccupancyRequest, error) {
+	var r channel_domain.OccupancyRequest
+	if err := s.store.Get(ctx, shared_offline.KindOccupancy, requestID, &r); err != nil {
+		if errors.Is(err, shared_offline.ErrNotCached) {
+			return nil, channel_domain.ErrOccupancyRequestNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *OccupancyStore) ListOccupancyRequests(ctx context.Context, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	recs, err := s.store.List(ctx, shared_offline.KindOccupancy, channelID)
+	if err != nil {
+		return nil, err
+	}
+	return shared_offline.Decode[channel_domain.OccupancyRequest](recs)
+}
+
+var _ channel_domain.OccupancyRequestRepository = (*OccupancyStore)(nil)
@@ -0,0 +1,349 @@
+package channel_persistence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	channel_domain "vault-app/internal/channel/domain"
+	shared_offline "vault-app/internal/shared/offline"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+const (
+	OpCreateChannel   = "channel.create"
+	OpUpdateChannel   = "channel.update"
+	OpDeleteChannel   = "channel.delete"
+	OpActivateChannel = "channel.activate"
+	OpRevokeChannel   = "channel.revoke"
+
+	MessageServedFromCache = "served from offline cache"
+	MessageQueuedOffline   = "queued offline"
+)
+
+// OfflineRepository is an offline-first ChannelRepository. Cloud stays
+// authoritative: every call goes to the cloud first and successful responses
+// refresh the local cache. When the cloud is unreachable, reads are served
+// from the cache and channel writes are queued for replay on reconnect.
+//
+// Participant joins and invitations are never queued: Cloud validates them
+// and owns the resulting records, so they fail while offline.
+type OfflineRepository struct {
+	cloud channel_domain.ChannelRepository
+	cache *shared_offline.Cache
+}
+
+func NewOfflineRepository(cloud channel_domain.ChannelRepository, cache *shared_offline.Cache) *OfflineRepository {
+	r := &OfflineRepository{cloud: cloud, cache: cache}
+
+	cache.Queue.Register(OpCreateChannel, func(ctx context.Context, payload json.RawMessage) error {
+		var req channel_domain.CreateChannelRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.CreateChannel(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putChannel(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpUpdateChannel, func(ctx context.Context, payload json.RawMessage) error {
+		var req channel_domain.UpdateChannelRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.UpdateChannel(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putChannel(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpDeleteChannel, func(ctx context.Context, payload json.RawMessage) error {
+		var req channel_domain.DeleteChannelRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		return cloud.DeleteChannel(ctx, &req)
+	})
+	cache.Queue.Register(OpActivateChannel, func(ctx context.Context, payload json.RawMessage) error {
+		var req channel_domain.ActivateChannelRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.ActivateChannel(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putChannel(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpRevokeChannel, func(ctx context.Context, payload json.RawMessage) error {
+		var req channel_domain.RevokeChannelRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		return cloud.RevokeChannel(ctx, &req)
+	})
+
+	return r
+}
+
+func (r *OfflineRepository) putChannel(ctx context.Context, c channel_domain.Channel) error {
+	return r.cache.UserStore().Put(ctx, shared_offline.KindChannel, c.WorkspaceID, shared_offline.Item{ID: c.ID, Value: c})
+}
+
+func (r *OfflineRepository) cachedChannel(ctx context.Context, channelID string) (*channel_domain.Channel, error) {
+	var c channel_domain.Channel
+	if err := r.cache.UserStore().Get(ctx, shared_offline.KindChannel, channelID, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// queueChannelChange enqueues the mutation and applies it to the cached
+// channel so offline reads reflect it.
+func (r *OfflineRepository) queueChannelChange(ctx context.Context, op string, req any, c channel_domain.Channel) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	if _, err := r.cache.Queue.Enqueue(ctx, op, req); err != nil {
+		return nil, err
+	}
+	c.IsDirty = true
+	if err := r.putChannel(ctx, c); err != nil {
+		return nil, err
+	}
+	return &tracecore_types.CloudResponse[channel_domain.Channel]{Status: http.StatusAccepted, Data: c, Message: MessageQueuedOffline}, nil
+}
+
+func (r *OfflineRepository) CreateChannel(ctx context.Context, req *channel_domain.CreateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	resp, err := r.cloud.CreateChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		return r.queueChannelChange(ctx, OpCreateChannel, req, req.Channel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putChannel(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListChannels refreshes incrementally: only channels changed since the
+// listing's last refresh are requested and merged into the cache, and the
+// full cached listing is returned.
+func (r *OfflineRepository) ListChannels(ctx context.Context, req *channel_domain.ListChannelsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Channel], error) {
+	scope := shared_offline.Scope(shared_offline.KindChannel, req.WorkspaceID)
+	store := r.cache.UserStore()
+
+	state, err := store.SyncState(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	since := store.Since(*state)
+
+	resp, err := r.cloud.ListChannels(ctx, &channel_domain.ListChannelsRequest{
+		WorkspaceID:  req.WorkspaceID,
+		UpdatedSince: since,
+	})
+	offline := r.cache.Connectivity.Observe(ctx, err)
+	if offline {
+		_ = store.MarkFailed(ctx, scope, err)
+	} else if err != nil {
+		return nil, err
+	} else {
+		items := make([]shared_offline.Item, 0, len(resp.Data))
+		for _, c := range resp.Data {
+			items = append(items, shared_offline.Item{ID: c.ID, Value: c})
+		}
+		if err := store.MergeSynced(ctx, shared_offline.KindChannel, req.WorkspaceID, scope, since, items); err != nil {
+			return nil, err
+		}
+	}
+
+	recs, err := store.List(ctx, shared_offline.KindChannel, req.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := shared_offline.Decode[channel_domain.Channel](recs)
+	if err != nil {
+		return nil, err
+	}
+	if offline {
+		return &tracecore_types.CloudResponse[[]channel_domain.Channel]{Status: http.StatusOK, Data: channels, Message: MessageServedFromCache}, nil
+	}
+	resp.Data = channels
+	return resp, nil
+}
+
+func (r *OfflineRepository) GetChannel(ctx context.Context, req *channel_domain.GetChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	resp, err := r.cloud.GetChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		c, err := r.cachedChannel(ctx, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[channel_domain.Channel]{Status: http.StatusOK, Data: *c, Message: MessageServedFromCache}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putChannel(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) DeleteChannel(ctx context.Context, req *channel_domain.DeleteChannelRequest) error {
+	err := r.cloud.DeleteChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		if _, err := r.cache.Queue.Enqueue(ctx, OpDeleteChannel, req); err != nil {
+			return err
+		}
+		return r.cache.UserStore().Delete(ctx, shared_offline.KindChannel, req.ChannelID)
+	}
+	if err != nil {
+		return err
+	}
+	return r.cache.UserStore().Delete(ctx, shared_offline.KindChannel, req.ChannelID)
+}
+
+func (r *OfflineRepository) UpdateChannel(ctx context.Context, req *channel_domain.UpdateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	resp, err := r.cloud.UpdateChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		return r.queueChannelChange(ctx, OpUpdateChannel, req, req.Channel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putChannel(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) ActivateChannel(ctx context.Context, req *channel_domain.ActivateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	resp, err := r.cloud.ActivateChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		c, err := r.cachedChannel(ctx, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		c.Status = channel_domain.StatusActive
+		return r.queueChannelChange(ctx, OpActivateChannel, req, *c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putChannel(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) RevokeChannel(ctx context.Context, req *channel_domain.RevokeChannelRequest) error {
+	err := r.cloud.RevokeChannel(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		c, err := r.cachedChannel(ctx, req.ChannelID)
+		if err != nil {
+			return err
+		}
+		c.Status = channel_domain.StatusRevoked
+		_, err = r.queueChannelChange(ctx, OpRevokeChannel, req, *c)
+		return err
+	}
+	return err
+}
+
+func (r *OfflineRepository) AddParticipant(ctx context.Context, req *channel_domain.JoinChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Participant], error) {
+	resp, err := r.cloud.AddParticipant(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
+	p := resp.Data
+	if err := r.cache.UserStore().Put(ctx, shared_offline.KindParticipant, req.ChannelID, shared_offline.Item{ID: participantRecordID(req.ChannelID, p.VaultID), Value: p}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// participantRecordID keys a participant by channel: a vault taking part in
+// several channels has one cached record per channel.
+func participantRecordID(channelID, vaultID string) string {
+	return channelID + "/" + vaultID
+}
+
+func (r *OfflineRepository) ListParticipants(ctx context.Context, req *channel_domain.ListParticipantsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Participant], error) {
+	scope := shared_offline.Scope(shared_offline.KindParticipant, req.ChannelID)
+	store := r.cache.UserStore()
+
+	resp, err := r.cloud.ListParticipants(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		_ = store.MarkFailed(ctx, scope, err)
+		recs, err := store.List(ctx, shared_offline.KindParticipant, req.ChannelID)
+		if err != nil {
+			return nil, err
+		}
+		participants, err := shared_offline.Decode[channel_domain.Participant](recs)
+		if err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[[]channel_domain.Participant]{Status: http.StatusOK, Data: participants, Message: MessageServedFromCache}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]shared_offline.Item, 0, len(resp.Data))
+	for _, p := range resp.Data {
+		items = append(items, shared_offline.Item{ID: participantRecordID(req.ChannelID, p.VaultID), Value: p})
+	}
+	if err := store.ReplaceSynced(ctx, shared_offline.KindParticipant, req.ChannelID, scope, items); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) InviteToChannel(ctx context.Context, req *channel_domain.InviteToChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	resp, err := r.cloud.InviteToChannel(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+func (r *OfflineRepository) AcceptChannelInvitation(ctx context.Context, req *channel_domain.AcceptInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	resp, err := r.cloud.AcceptChannelInvitation(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+func (r *OfflineRepository) RejectChannelInvitation(ctx context.Context, req *channel_domain.RejectInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	resp, err := r.cloud.RejectChannelInvitation(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+func (r *OfflineRepository) RevokeChannelInvitation(ctx context.Context, req *channel_domain.RevokeInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	resp, err := r.cloud.RevokeChannelInvitation(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+func (r *OfflineRepository) ExpireChannelInvitation(ctx context.Context, req *channel_domain.ExpireInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	resp, err := r.cloud.ExpireChannelInvitation(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+// ListChannelInvitations is not cached: an invitation answered while offline
+// would otherwise be offered again.
+func (r *OfflineRepository) ListChannelInvitations(ctx context.Context, req *channel_domain.ListInvitationsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+	resp, err := r.cloud.ListChannelInvitations(ctx, req)
+	r.cache.Connectivity.Observe(ctx, err)
+	return resp, err
+}
+
+// Ensure interface satisfaction at compile-time
+var _ channel_domain.ChannelRepository = (*OfflineRepository)(nil)
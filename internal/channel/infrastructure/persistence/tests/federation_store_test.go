@@ -0,0 +1,118 @@
+package channel_persistence_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	channel_domain "vault-app/internal/channel/domain"
+	channel_persistence "vault-app/internal/channel/infrastructure/persistence"
+	shared_offline "vault-app/internal/shared/offline"
+	vault_session "vault-app/internal/vault/application/session"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// memoryVaultSessions holds the open vault of each user.
+type memoryVaultSessions struct {
+	sessions map[string]*vault_session.Session
+}
+
+func (m *memoryVaultSessions) open(userID string) {
+	m.sessions[userID] = &vault_session.Session{UserID: userID, Vault: vaults_domain.InitEmptyVaultPayload("vault", "1").ToBytes()}
+}
+
+func (m *memoryVaultSessions) GetSession(userID string) (*vault_session.Session, error) {
+	s, ok := m.sessions[userID]
+	if !ok {
+		return nil, errors.New("no active session")
+	}
+	return s, nil
+}
+
+func (m *memoryVaultSessions) SetVault(userID string, vault *vaults_domain.VaultPayload) error {
+	s, err := m.GetSession(userID)
+	if err != nil {
+		return err
+	}
+	s.Vault = vault.ToBytes()
+	return nil
+}
+
+func (m *memoryVaultSessions) MarkDirty(userID string) {
+	if s, ok := m.sessions[userID]; ok {
+		s.Dirty = true
+	}
+}
+
+func newFederationStore(t *testing.T, sessions *memoryVaultSessions) *channel_persistence.FederationStore {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	cache, err := shared_offline.NewCache(db)
+	require.NoError(t, err)
+	return channel_persistence.NewFederationStore(cache.Store).WithVaultSessions(sessions)
+}
+
+func TestFederationStore_KeepsOneSnapshotPerUser(t *testing.T) {
+	ctx := context.Background()
+	store := newFederationStore(t, &memoryVaultSessions{sessions: map[string]*vault_session.Session{}})
+
+	shared := channel_domain.RemoteVault{VaultID: "vault-b", Endpoint: "https://b.example"}
+	require.NoError(t, store.SaveFederation(ctx, "alice", channel_domain.FederationSnapshot{RemoteVaults: []channel_domain.RemoteVault{shared}}))
+	require.NoError(t, store.SaveFederation(ctx, "bob", channel_domain.FederationSnapshot{RemoteVaults: []channel_domain.RemoteVault{
+		{VaultID: "vault-b", Endpoint: "https://b.example", LastCursor: 7},
+		{VaultID: "vault-c", Endpoint: "https://c.example"},
+	}}))
+
+	alice, err := store.LoadFederation(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, alice.RemoteVaults, 1)
+	require.Zero(t, alice.RemoteVaults[0].LastCursor)
+
+	bob, err := store.LoadFederation(ctx, "bob")
+	require.NoError(t, err)
+	require.Len(t, bob.RemoteVaults, 2)
+
+	_, err = store.LoadFederation(ctx, "")
+	require.ErrorIs(t, err, channel_domain.ErrUserIDRequired)
+}
+
+func TestFederationStore_WritesThroughToTheVaultDAG(t *testing.T) {
+	ctx := context.Background()
+	sessions := &memoryVaultSessions{sessions: map[string]*vault_session.Session{}}
+	sessions.open("alice")
+	store := newFederationStore(t, sessions)
+
+	remote := channel_domain.RemoteVault{
+		VaultID:    "vault-b",
+		Endpoint:   "https://b.example",
+		TrustState: channel_domain.TrustState(channel_domain.Trusted),
+		PublicKey:  "GPINNED",
+		Pending: []channel_domain.PendingSyncItem{
+			{ExchangeID: "ex-1", Status: channel_domain.SyncFailed, Cursor: 3, RetryCount: 1, LastError: "timeout"},
+		},
+	}
+	require.NoError(t, store.SaveFederation(ctx, "alice", channel_domain.FederationSnapshot{RemoteVaults: []channel_domain.RemoteVault{remote}}))
+
+	session, err := sessions.GetSession("alice")
+	require.NoError(t, err)
+	require.True(t, session.Dirty)
+	payload, err := vault_session.DecodeSessionVault(session.Vault)
+	require.NoError(t, err)
+	dag := payload.Collaborative.Federation
+	require.True(t, dag.IsDirty)
+	require.Len(t, dag.RemoteVaults, 1)
+	require.Equal(t, "GPINNED", dag.RemoteVaults[0].PublicKey)
+	require.Equal(t, "timeout", dag.RemoteVaults[0].Pending[0].LastError)
+
+	// A vault reconstructed on another device carries the snapshot: it is
+	// loaded from the DAG even when the local read model has nothing.
+	other := newFederationStore(t, sessions)
+	loaded, err := other.LoadFederation(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []channel_domain.RemoteVault{remote}, loaded.RemoteVaults)
+}
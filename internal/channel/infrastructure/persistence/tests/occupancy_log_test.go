@@ -0,0 +1,200 @@
+package channel_persistence_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	channel_domain "vault-app/internal/channel/domain"
+	channel_persistence "vault-app/internal/channel/infrastructure/persistence"
+	shared_offline "vault-app/internal/shared/offline"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// memoryEventLog is an in-memory Cloud channel event log that dedupes on
+// idempotency keys.
+type memoryEventLog struct {
+	events []channel_domain.ChannelEvent
+}
+
+func (l *memoryEventLog) AppendChannelEvent(_ context.Context, req *channel_domain.AppendChannelEventRequest) (*tracecore_types.CloudResponse[channel_domain.ChannelEvent], error) {
+	for _, e := range l.events {
+		if e.ChannelID == req.ChannelID && req.IdempotencyKey != "" && e.IdempotencyKey == req.IdempotencyKey {
+			return &tracecore_types.CloudResponse[channel_domain.ChannelEvent]{Data: e}, nil
+		}
+	}
+	e := channel_domain.ChannelEvent{
+		ID:             req.IdempotencyKey,
+		ChannelID:      req.ChannelID,
+		Type:           req.Type,
+		ActorVaultID:   req.ActorVaultID,
+		Payload:        req.Payload,
+		IdempotencyKey: req.IdempotencyKey,
+		Cursor:         uint64(len(l.events) + 1),
+	}
+	l.events = append(l.events, e)
+	return &tracecore_types.CloudResponse[channel_domain.ChannelEvent]{Data: e}, nil
+}
+
+func (l *memoryEventLog) ListChannelEvents(_ context.Context, req *channel_domain.ListChannelEventsRequest) (*tracecore_types.CloudResponse[[]channel_domain.ChannelEvent], error) {
+	events := []channel_domain.ChannelEvent{}
+	for _, e := range l.events {
+		if e.ChannelID == req.ChannelID {
+			events = append(events, e)
+		}
+	}
+	return &tracecore_types.CloudResponse[[]channel_domain.ChannelEvent]{Data: events}, nil
+}
+
+// channelReader serves one channel.
+type channelReader struct {
+	channel channel_domain.Channel
+}
+
+func (r channelReader) GetChannel(_ context.Context, req *channel_domain.GetChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	if req.ChannelID != r.channel.ID {
+		return nil, channel_domain.ErrChannelNotFound
+	}
+	return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: r.channel}, nil
+}
+
+// signatureVerifier accepts the votes signed "sig:<vault>".
+type signatureVerifier struct{}
+
+func (signatureVerifier) VerifyOccupancyVote(_ context.Context, _ *channel_domain.Channel, _ channel_domain.OccupancyRequest, v channel_domain.OccupancyVote) error {
+	if v.Signature != "sig:"+v.VaultID {
+		return channel_domain.ErrOccupancySignature
+	}
+	return nil
+}
+
+func occupancyChannel(t *testing.T) channel_domain.Channel {
+	channel := channel_domain.Channel{
+		ID: "channel-001",
+		Slots: []channel_domain.Slot{
+			{ID: "buyer", Role: "buyer"},
+			{ID: "finance", Role: "finance"},
+			{ID: "supplier", Role: "supplier", Gated: true},
+		},
+		Assignments: []channel_domain.Assignment{
+			{SlotID: "buyer", OwnerID: "vault_buyer"},
+			{SlotID: "finance", OwnerID: "vault_finance"},
+		},
+	}
+	doc := channel_domain.DefaultPolicy()
+	doc.GatedSlots = channel_domain.SlotApprovalPolicy{Approvals: 2}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channel.SetPolicy(policy)
+	return channel
+}
+
+func newVerifiedLog(t *testing.T, log *memoryEventLog) *channel_persistence.OccupancyEventLog {
+	return channel_persistence.NewOccupancyEventLog(log, newOccupancyCache(t)).
+		WithVerification(channelReader{channel: occupancyChannel(t)}, signatureVerifier{})
+}
+
+func newOccupancyCache(t *testing.T) *channel_persistence.OccupancyStore {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	cache, err := shared_offline.NewCache(db)
+	require.NoError(t, err)
+	return channel_persistence.NewOccupancyStore(cache.Store)
+}
+
+func TestOccupancyEventLog_SharesRequestsAndVotesAcrossVaults(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	log := &memoryEventLog{}
+	requester := newVerifiedLog(t, log)
+	approver := newVerifiedLog(t, log)
+
+	request := channel_domain.OccupancyRequest{
+		ID:               "req-1",
+		ChannelID:        "channel-001",
+		SlotID:           "supplier",
+		CandidateVaultID: "vault_supplier",
+		RequestedBy:      "vault_supplier",
+		RequestedAt:      now,
+		Status:           channel_domain.OccupancyPending,
+		Approvers:        []string{"vault_buyer", "vault_finance"},
+		Required:         2,
+	}
+	require.NoError(t, requester.SaveOccupancyRequest(ctx, request))
+
+	// The approver's vault only learns of the request through the log.
+	_, err := approver.GetOccupancyRequest(ctx, "req-1")
+	require.ErrorIs(t, err, channel_domain.ErrOccupancyRequestNotFound)
+	listed, err := approver.ListOccupancyRequests(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	cached, err := approver.GetOccupancyRequest(ctx, "req-1")
+	require.NoError(t, err)
+	require.NoError(t, cached.Vote(channel_domain.OccupancyVote{RequestID: "req-1", VaultID: "vault_buyer", Decision: channel_domain.OccupancyVoteApprove, Signature: "sig:vault_buyer", VotedAt: now}, now))
+	require.NoError(t, approver.SaveOccupancyRequest(ctx, *cached))
+	require.NoError(t, approver.SaveOccupancyRequest(ctx, *cached), "saving again logs nothing new")
+	require.Len(t, log.events, 2)
+
+	listed, err = requester.ListOccupancyRequests(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Equal(t, 1, listed[0].Approvals())
+	require.Equal(t, []string{"vault_finance"}, listed[0].PendingApprovers())
+
+	require.NoError(t, listed[0].Withdraw("vault_supplier", now.Add(time.Hour)))
+	require.NoError(t, requester.SaveOccupancyRequest(ctx, listed[0]))
+
+	listed, err = approver.ListOccupancyRequests(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Equal(t, channel_domain.OccupancyWithdrawn, listed[0].Status)
+	require.NotNil(t, listed[0].DecidedAt)
+}
+
+func TestOccupancyEventLog_ReplayIgnoresForgedEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	log := &memoryEventLog{}
+	occupancy := newVerifiedLog(t, log)
+
+	// The logged request names vaults the policy does not let approve and a
+	// quorum of one where the policy asks for two.
+	require.NoError(t, occupancy.SaveOccupancyRequest(ctx, channel_domain.OccupancyRequest{
+		ID:               "req-1",
+		ChannelID:        "channel-001",
+		SlotID:           "supplier",
+		CandidateVaultID: "vault_supplier",
+		RequestedBy:      "vault_supplier",
+		RequestedAt:      now,
+		Status:           channel_domain.OccupancyPending,
+		Approvers:        []string{"vault_buyer", "vault_finance", "vault_supplier", "vault_stranger"},
+		Required:         1,
+	}))
+	appendEvent := func(eventType, actor, key string, payload any) {
+		raw, err := json.Marshal(payload)
+		require.NoError(t, err)
+		_, err = log.AppendChannelEvent(ctx, &channel_domain.AppendChannelEventRequest{
+			ChannelID: "channel-001", Type: eventType, ActorVaultID: actor, Payload: raw, IdempotencyKey: key,
+		})
+		require.NoError(t, err)
+	}
+	appendEvent(channel_domain.ChannelEventOccupancyVoted, "vault_buyer", "forged-vote", channel_domain.OccupancyVote{
+		RequestID: "req-1", VaultID: "vault_buyer", Decision: channel_domain.OccupancyVoteApprove, Signature: "forged", VotedAt: now,
+	})
+	appendEvent(channel_domain.ChannelEventOccupancyWithdrawn, "vault_buyer", "foreign-withdrawal", map[string]any{
+		"request_id": "req-1", "withdrawn_by": "vault_buyer", "withdrawn_at": now,
+	})
+
+	listed, err := occupancy.ListOccupancyRequests(ctx, "channel-001")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, []string{"vault_buyer", "vault_finance"}, listed[0].Approvers)
+	require.Equal(t, 2, listed[0].Required)
+	require.Equal(t, 0, listed[0].Approvals(), "an unverified vote does not count")
+	require.Equal(t, channel_domain.OccupancyPending, listed[0].Status, "only the requester or the candidate withdraws")
+}
@@ -0,0 +1,59 @@
+package channel_templates
+
+import (
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// BuiltinTemplates are the blueprints shipped with the desktop. The Cloud
+// registry overrides them with newer versions when it is reachable.
+func BuiltinTemplates() []channel_domain.ChannelTemplate {
+	return []channel_domain.ChannelTemplate{
+		{
+			ID:          "procurement",
+			Version:     1,
+			Title:       "Procurement",
+			Description: "Buyer, supplier, finance and auditor exchange purchase orders and invoices.",
+			Slots: []channel_domain.Slot{
+				{ID: "buyer", Name: "Buyer", Role: "buyer", Gated: true, Order: 1},
+				{ID: "supplier", Name: "Supplier", Role: "supplier", Gated: true, Order: 2},
+				{ID: "finance", Name: "Finance", Role: "finance", Order: 3},
+				{ID: "auditor", Name: "Auditor", Role: "auditor", Order: 4},
+			},
+			DefaultPolicy: channel_domain.PolicyDocument{
+				Invite: channel_domain.InvitePolicy{Roles: []string{"buyer"}, RequireInvitation: true},
+				ThreadEvents: map[string][]string{
+					"buyer":    {channel_domain.AnyEventType},
+					"supplier": {"invoice.submitted", "order.acknowledged", "comment.added"},
+					"finance":  {"payment.approved", "payment.released", "comment.added"},
+					"auditor":  {"comment.added"},
+				},
+				Approvals: []channel_domain.ApprovalRequirement{
+					{EventType: "payment.released", ApprovalEventType: "payment.approved", Count: 1},
+				},
+				Retention: channel_domain.RetentionPolicy{RetainDays: 3650},
+			},
+			RequiredProperties: []string{"purchase_order", "currency"},
+			AssetType:          "invoice",
+		},
+		{
+			ID:          "supplier-onboarding",
+			Version:     1,
+			Title:       "Supplier onboarding",
+			Description: "A requester brings a supplier through compliance review and approval.",
+			Slots: []channel_domain.Slot{
+				{ID: "requester", Name: "Requester", Role: "requester", Gated: true, Order: 1},
+				{ID: "supplier", Name: "Supplier", Role: "supplier", Gated: true, Order: 2},
+				{ID: "compliance", Name: "Compliance", Role: "compliance", Order: 3},
+				{ID: "approver", Name: "Approver", Role: "approver", Order: 4},
+			},
+			DefaultPolicy: channel_domain.PolicyDocument{
+				Invite: channel_domain.InvitePolicy{Roles: []string{"requester", "approver"}, RequireInvitation: true},
+				Approvals: []channel_domain.ApprovalRequirement{
+					{EventType: "supplier.approved", ApprovalEventType: "compliance.cleared", Count: 1},
+				},
+			},
+			RequiredProperties: []string{"supplier_name"},
+			AssetType:          "document",
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package channel_templates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// CloudSource is the Cloud channel template registry. TracecoreClient
+// implements it next to GetTemplate/GetPack.
+type CloudSource interface {
+	GetChannelTemplate(ctx context.Context, templateID string) (*channel_domain.ChannelTemplate, error)
+	ListChannelTemplates(ctx context.Context) ([]channel_domain.ChannelTemplate, error)
+}
+
+// Registry serves channel templates from the Cloud registry, falling back to
+// the built-in catalog when the Cloud is unreachable or does not know a
+// template. The highest version wins, and every template is validated before
+// it is served.
+type Registry struct {
+	cloud   CloudSource
+	builtin map[string]channel_domain.ChannelTemplate
+
+	mu    sync.RWMutex
+	cache map[string]channel_domain.ChannelTemplate
+}
+
+func NewRegistry(cloud CloudSource, builtin ...channel_domain.ChannelTemplate) *Registry {
+	r := &Registry{
+		cloud:   cloud,
+		builtin: map[string]channel_domain.ChannelTemplate{},
+		cache:   map[string]channel_domain.ChannelTemplate{},
+	}
+	for _, t := range builtin {
+		r.builtin[t.ID] = t
+	}
+	return r
+}
+
+func (r *Registry) GetChannelTemplate(ctx context.Context, templateID string) (*channel_domain.ChannelTemplate, error) {
+	if templateID == "" {
+		return nil, channel_domain.ErrTemplateIDRequired
+	}
+
+	var best *channel_domain.ChannelTemplate
+	var cloudErr error
+	if r.cloud != nil {
+		t, err := r.cloud.GetChannelTemplate(ctx, templateID)
+		if err != nil {
+			cloudErr = err
+		} else if t != nil {
+			best = t
+		}
+	}
+	if t, ok := r.builtin[templateID]; ok && (best == nil || t.Version > best.Version) {
+		best = &t
+	}
+	if best == nil {
+		// Serve the last version seen while the Cloud is unreachable.
+		r.mu.RLock()
+		cached, ok := r.cache[templateID]
+		r.mu.RUnlock()
+		if ok {
+			return &cached, nil
+		}
+		if cloudErr != nil {
+			return nil, fmt.Errorf("%w: %s: %v", channel_domain.ErrTemplateNotFound, templateID, cloudErr)
+		}
+		return nil, fmt.Errorf("%w: %s", channel_domain.ErrTemplateNotFound, templateID)
+	}
+
+	if err := best.Validate(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.cache[templateID] = *best
+	r.mu.Unlock()
+
+	return best, nil
+}
+
+// ListChannelTemplates merges the Cloud and built-in catalogs. Invalid Cloud
+// templates are left out rather than failing the listing.
+func (r *Registry) ListChannelTemplates(ctx context.Context) ([]channel_domain.ChannelTemplate, error) {
+	byID := map[string]channel_domain.ChannelTemplate{}
+	for id, t := range r.builtin {
+		byID[id] = t
+	}
+	if r.cloud != nil {
+		if cloud, err := r.cloud.ListChannelTemplates(ctx); err == nil {
+			for _, t := range cloud {
+				if t.Validate() != nil {
+					continue
+				}
+				if existing, ok := byID[t.ID]; !ok || t.Version >= existing.Version {
+					byID[t.ID] = t
+				}
+			}
+		}
+	}
+
+	templates := make([]channel_domain.ChannelTemplate, 0, len(byID))
+	for _, t := range byID {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates, nil
+}
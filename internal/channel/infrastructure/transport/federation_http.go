@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	channel_federation "vault-app/internal/channel/application/federation"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// FederationSyncPath is where a remote vault accepts pushed batches.
+const FederationSyncPath = "/federation/sync"
+
+// FederationHandshakePath is where a remote vault exchanges identity
+// documents.
+const FederationHandshakePath = "/federation/handshake"
+
+// HTTPFederationTransport pushes sync batches to a remote vault endpoint
+// (POST {endpoint}/federation/sync) and decodes its ack.
+type HTTPFederationTransport struct {
+	Client *http.Client
+}
+
+func NewHTTPFederationTransport(client *http.Client) *HTTPFederationTransport {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPFederationTransport{Client: client}
+}
+
+func (t *HTTPFederationTransport) Push(
+	ctx context.Context,
+	remote channel_domain.RemoteVault,
+	batch channel_federation.SyncBatch,
+) (*channel_federation.SyncAck, error) {
+	if remote.Endpoint == "" {
+		return nil, channel_domain.ErrRemoteEndpointRequired
+	}
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(batch); err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(remote.Endpoint, "/") + FederationSyncPath
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("remote vault %s returned status %d: %s", remote.VaultID, resp.StatusCode, string(respBytes))
+	}
+
+	// An accepted push without a body is acked later.
+	if len(bytes.TrimSpace(respBytes)) == 0 {
+		return nil, nil
+	}
+
+	var ack channel_federation.SyncAck
+	if err := json.Unmarshal(respBytes, &ack); err != nil {
+		return nil, fmt.Errorf("remote vault %s returned an invalid ack: %w", remote.VaultID, err)
+	}
+	if ack.VaultID == "" {
+		ack.VaultID = remote.VaultID
+	}
+
+	return &ack, nil
+}
+
+// Handshake posts the local identity document to
+// {endpoint}/federation/handshake and decodes the remote's.
+func (t *HTTPFederationTransport) Handshake(
+	ctx context.Context,
+	endpoint string,
+	local channel_domain.IdentityDocument,
+) (*channel_domain.IdentityDocument, error) {
+	if endpoint == "" {
+		return nil, channel_domain.ErrRemoteEndpointRequired
+	}
+
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(local); err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimRight(endpoint, "/") + FederationHandshakePath
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("remote %s returned status %d: %s", endpoint, resp.StatusCode, string(respBytes))
+	}
+
+	var doc channel_domain.IdentityDocument
+	if err := json.Unmarshal(respBytes, &doc); err != nil {
+		return nil, fmt.Errorf("remote %s returned an invalid identity: %w", endpoint, err)
+	}
+
+	return &doc, nil
+}
+
+var (
+	_ channel_federation.Transport  = (*HTTPFederationTransport)(nil)
+	_ channel_federation.Handshaker = (*HTTPFederationTransport)(nil)
+)
@@ -0,0 +1,219 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	channel_federation "vault-app/internal/channel/application/federation"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+var (
+	// ErrLocalRemoteUnavailable is returned while a LocalRemoteVault is offline.
+	ErrLocalRemoteUnavailable = errors.New("local remote vault is unavailable")
+	// ErrLocalRemoteNoIdentity is returned by a handshake before SetIdentity.
+	ErrLocalRemoteNoIdentity = errors.New("local remote vault has no identity")
+)
+
+// LocalRemoteVault is an in-process stand-in for a remote vault. It applies
+// pushed exchanges in cursor order and acks them, and can be taken offline or
+// told to hold its acks. It answers handshakes with the identity it was given.
+// It serves the federation endpoints over HTTP and can be used directly as a
+// Transport and Handshaker.
+type LocalRemoteVault struct {
+	VaultID string
+
+	mu       sync.Mutex
+	offline  bool
+	holdAcks bool
+	cursor   uint64
+	received []channel_federation.SyncEnvelope
+	seen     map[string]bool
+	identity *channel_domain.IdentityDocument
+	peers    []channel_domain.IdentityDocument
+}
+
+func NewLocalRemoteVault(vaultID string) *LocalRemoteVault {
+	return &LocalRemoteVault{
+		VaultID: vaultID,
+		seen:    make(map[string]bool),
+	}
+}
+
+// SetOffline makes every push fail until it is set back.
+func (v *LocalRemoteVault) SetOffline(offline bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.offline = offline
+}
+
+// SetIdentity sets the document returned to handshakes, e.g. re-signed with
+// a new key to simulate a key rotation.
+func (v *LocalRemoteVault) SetIdentity(doc channel_domain.IdentityDocument) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.identity = &doc
+}
+
+// Peers returns the identity documents received in handshakes.
+func (v *LocalRemoteVault) Peers() []channel_domain.IdentityDocument {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := make([]channel_domain.IdentityDocument, len(v.peers))
+	copy(out, v.peers)
+	return out
+}
+
+// HoldAcks accepts pushes without acking them.
+func (v *LocalRemoteVault) HoldAcks(hold bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.holdAcks = hold
+}
+
+// Received returns the exchanges applied so far, without duplicates.
+func (v *LocalRemoteVault) Received() []channel_federation.SyncEnvelope {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := make([]channel_federation.SyncEnvelope, len(v.received))
+	copy(out, v.received)
+	return out
+}
+
+// Ack returns the ack covering everything applied so far.
+func (v *LocalRemoteVault) Ack() channel_federation.SyncAck {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.ackLocked()
+}
+
+func (v *LocalRemoteVault) ackLocked() channel_federation.SyncAck {
+	acked := make([]string, 0, len(v.received))
+	for _, env := range v.received {
+		acked = append(acked, env.ExchangeID)
+	}
+	return channel_federation.SyncAck{VaultID: v.VaultID, Acked: acked, Cursor: v.cursor}
+}
+
+func (v *LocalRemoteVault) apply(batch channel_federation.SyncBatch) (*channel_federation.SyncAck, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.offline {
+		return nil, ErrLocalRemoteUnavailable
+	}
+
+	for _, env := range batch.Items {
+		if v.seen[env.ExchangeID] {
+			continue
+		}
+		v.seen[env.ExchangeID] = true
+		v.received = append(v.received, env)
+		if env.Cursor > v.cursor {
+			v.cursor = env.Cursor
+		}
+	}
+
+	if v.holdAcks {
+		return nil, nil
+	}
+	ack := v.ackLocked()
+	return &ack, nil
+}
+
+func (v *LocalRemoteVault) Push(
+	_ context.Context,
+	_ channel_domain.RemoteVault,
+	batch channel_federation.SyncBatch,
+) (*channel_federation.SyncAck, error) {
+	return v.apply(batch)
+}
+
+func (v *LocalRemoteVault) handshake(peer channel_domain.IdentityDocument) (*channel_domain.IdentityDocument, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.offline {
+		return nil, ErrLocalRemoteUnavailable
+	}
+	if v.identity == nil {
+		return nil, ErrLocalRemoteNoIdentity
+	}
+
+	v.peers = append(v.peers, peer)
+	doc := *v.identity
+	return &doc, nil
+}
+
+func (v *LocalRemoteVault) Handshake(
+	_ context.Context,
+	_ string,
+	local channel_domain.IdentityDocument,
+) (*channel_domain.IdentityDocument, error) {
+	return v.handshake(local)
+}
+
+// ServeHTTP implements the remote side of HTTPFederationTransport.
+func (v *LocalRemoteVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.URL.Path {
+	case FederationSyncPath:
+		v.serveSync(w, r)
+	case FederationHandshakePath:
+		v.serveHandshake(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (v *LocalRemoteVault) serveHandshake(w http.ResponseWriter, r *http.Request) {
+	var peer channel_domain.IdentityDocument
+	if err := json.NewDecoder(r.Body).Decode(&peer); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	doc, err := v.handshake(peer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+func (v *LocalRemoteVault) serveSync(w http.ResponseWriter, r *http.Request) {
+	var batch channel_federation.SyncBatch
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ack, err := v.apply(batch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if ack == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ack)
+}
+
+var (
+	_ channel_federation.Transport  = (*LocalRemoteVault)(nil)
+	_ channel_federation.Handshaker = (*LocalRemoteVault)(nil)
+)
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	channel_application "vault-app/internal/channel/application"
+	channelconfigusecases "vault-app/internal/channel/application/channel_config-usecases"
 	channel_usecase "vault-app/internal/channel/application/channel_lifecycle_usecases"
+	channel_occupancy_usecases "vault-app/internal/channel/application/channel_occupancy_usecases"
+	channel_template_usecases "vault-app/internal/channel/application/channel_template_usecases"
 	channel_domain "vault-app/internal/channel/domain"
 	tracecore_types "vault-app/internal/tracecore/types"
 )
@@ -22,6 +25,25 @@ type ChannelHandler struct {
 	listParticipantsUseCase *channel_usecase.ListParticipantsUsecase
 	inviteToChannelUseCase  *channel_usecase.InviteToChannelUsecase
 	acceptInvitationUseCase *channel_usecase.AcceptChannelInvitationUsecase
+
+	getPolicyUseCase    *channelconfigusecases.GetChannelPolicyUsecase
+	updatePolicyUseCase *channelconfigusecases.UpdateChannelPolicyUsecase
+
+	rejectInvitationUseCase  *channel_usecase.RejectChannelInvitationUsecase
+	revokeInvitationUseCase  *channel_usecase.RevokeChannelInvitationUsecase
+	listInvitationsUseCase   *channel_usecase.ListChannelInvitationsUsecase
+	expireInvitationsUseCase *channel_usecase.ExpireChannelInvitationsUsecase
+
+	grantPermissionUseCase  *channelconfigusecases.GrantChannelPermissionUsecase
+	revokePermissionUseCase *channelconfigusecases.RevokeChannelPermissionUsecase
+	getPermissionsUseCase   *channelconfigusecases.GetParticipantPermissionsUsecase
+
+	listTemplatesUseCase       *channel_template_usecases.ListChannelTemplatesUsecase
+	getTemplateUseCase         *channel_template_usecases.GetChannelTemplateUsecase
+	instantiateTemplateUseCase *channel_template_usecases.InstantiateChannelTemplateUsecase
+	migrateTemplateUseCase     *channel_template_usecases.MigrateChannelTemplateUsecase
+
+	occupancy *channel_occupancy_usecases.SlotOccupancyService
 }
 
 func NewChannelHandler(
@@ -52,6 +74,228 @@ func NewChannelHandler(
 	}
 }
 
+func (h *ChannelHandler) SetPolicyUseCases(
+	getPolicyUC *channelconfigusecases.GetChannelPolicyUsecase,
+	updatePolicyUC *channelconfigusecases.UpdateChannelPolicyUsecase,
+) {
+	h.getPolicyUseCase = getPolicyUC
+	h.updatePolicyUseCase = updatePolicyUC
+}
+
+// GetChannelPolicy returns the typed channel policy, migrated from the
+// pre-schema map when needed.
+func (h *ChannelHandler) GetChannelPolicy(ctx context.Context, userID string, channelID string) (*channel_domain.PolicyDocument, error) {
+	if h.getPolicyUseCase == nil {
+		return nil, fmt.Errorf("get channel policy use case is not initialized")
+	}
+
+	return h.getPolicyUseCase.Execute(ctx, &channel_application.GetChannelPolicyRequest{
+		ChannelID: channelID,
+	})
+}
+
+// UpdateChannelPolicy validates and stores a policy document. It fails with
+// ErrPolicyRevisionConflict when expectedRevision is stale.
+func (h *ChannelHandler) UpdateChannelPolicy(ctx context.Context, userID string, actorVaultID string, channelID string, policy channel_domain.PolicyDocument, expectedRevision int) (*channel_domain.PolicyDocument, error) {
+	if h.updatePolicyUseCase == nil {
+		return nil, fmt.Errorf("update channel policy use case is not initialized")
+	}
+
+	return h.updatePolicyUseCase.Execute(ctx, &channel_application.UpdateChannelPolicyRequest{
+		ChannelID:        channelID,
+		ActorVaultID:     actorVaultID,
+		Policy:           policy,
+		ExpectedRevision: expectedRevision,
+	})
+}
+
+func (h *ChannelHandler) SetPermissionUseCases(
+	grantUC *channelconfigusecases.GrantChannelPermissionUsecase,
+	revokeUC *channelconfigusecases.RevokeChannelPermissionUsecase,
+	getUC *channelconfigusecases.GetParticipantPermissionsUsecase,
+) {
+	h.grantPermissionUseCase = grantUC
+	h.revokePermissionUseCase = revokeUC
+	h.getPermissionsUseCase = getUC
+}
+
+// GrantChannelPermission gives vaultID a permission on behalf of
+// actorVaultID, which must administer the channel.
+func (h *ChannelHandler) GrantChannelPermission(ctx context.Context, userID string, actorVaultID string, channelID string, vaultID string, permission string, reason string) (*channel_domain.PolicyDocument, error) {
+	if h.grantPermissionUseCase == nil {
+		return nil, fmt.Errorf("grant channel permission use case is not initialized")
+	}
+
+	return h.grantPermissionUseCase.Execute(ctx, &channel_application.GrantChannelPermissionRequest{
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
+		VaultID:      vaultID,
+		Permission:   permission,
+		Reason:       reason,
+	})
+}
+
+// RevokeChannelPermission takes a permission from vaultID, overriding its
+// role templates.
+func (h *ChannelHandler) RevokeChannelPermission(ctx context.Context, userID string, actorVaultID string, channelID string, vaultID string, permission string, reason string) (*channel_domain.PolicyDocument, error) {
+	if h.revokePermissionUseCase == nil {
+		return nil, fmt.Errorf("revoke channel permission use case is not initialized")
+	}
+
+	return h.revokePermissionUseCase.Execute(ctx, &channel_application.RevokeChannelPermissionRequest{
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
+		VaultID:      vaultID,
+		Permission:   permission,
+		Reason:       reason,
+	})
+}
+
+// GetChannelPermissions resolves the roles and effective permissions of
+// vaultID in the channel for actorVaultID, which must take part in it.
+func (h *ChannelHandler) GetChannelPermissions(ctx context.Context, userID string, actorVaultID string, channelID string, vaultID string) (*channel_application.ParticipantPermissions, error) {
+	if h.getPermissionsUseCase == nil {
+		return nil, fmt.Errorf("get channel permissions use case is not initialized")
+	}
+
+	return h.getPermissionsUseCase.Execute(ctx, &channel_application.GetParticipantPermissionsRequest{
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
+		VaultID:      vaultID,
+	})
+}
+
+func (h *ChannelHandler) SetTemplateUseCases(
+	listUC *channel_template_usecases.ListChannelTemplatesUsecase,
+	getUC *channel_template_usecases.GetChannelTemplateUsecase,
+	instantiateUC *channel_template_usecases.InstantiateChannelTemplateUsecase,
+	migrateUC *channel_template_usecases.MigrateChannelTemplateUsecase,
+) {
+	h.listTemplatesUseCase = listUC
+	h.getTemplateUseCase = getUC
+	h.instantiateTemplateUseCase = instantiateUC
+	h.migrateTemplateUseCase = migrateUC
+}
+
+// ListChannelTemplates returns the channel template catalog.
+func (h *ChannelHandler) ListChannelTemplates(ctx context.Context, userID string) ([]channel_domain.ChannelTemplate, error) {
+	if h.listTemplatesUseCase == nil {
+		return nil, fmt.Errorf("list channel templates use case is not initialized")
+	}
+
+	return h.listTemplatesUseCase.Execute(ctx)
+}
+
+// GetChannelTemplate returns the latest version of a channel template.
+func (h *ChannelHandler) GetChannelTemplate(ctx context.Context, userID string, templateID string) (*channel_domain.ChannelTemplate, error) {
+	if h.getTemplateUseCase == nil {
+		return nil, fmt.Errorf("get channel template use case is not initialized")
+	}
+
+	return h.getTemplateUseCase.Execute(ctx, templateID)
+}
+
+// CreateChannelFromTemplate instantiates a template and creates the channel
+// through the authoritative Cloud backend.
+func (h *ChannelHandler) CreateChannelFromTemplate(ctx context.Context, userID string, workspaceID string, templateID string, title string, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, federation string) (*tracecore_types.ChannelDTO, error) {
+	if h.instantiateTemplateUseCase == nil {
+		return nil, fmt.Errorf("instantiate channel template use case is not initialized")
+	}
+
+	ch, err := h.instantiateTemplateUseCase.Execute(ctx, &channel_application.InstantiateChannelTemplateRequest{
+		TemplateID:  templateID,
+		Title:       title,
+		WorkspaceID: workspaceID,
+		Assignments: assignments,
+		Properties:  properties,
+		Federation:  federation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreChannelDTO(ch), nil
+}
+
+// MigrateChannelTemplate moves a channel to the latest version of its
+// template and reports what changed.
+func (h *ChannelHandler) MigrateChannelTemplate(ctx context.Context, userID string, actorVaultID string, channelID string, properties []channel_domain.ChannelProperty) (*channel_domain.TemplateMigration, error) {
+	if h.migrateTemplateUseCase == nil {
+		return nil, fmt.Errorf("migrate channel template use case is not initialized")
+	}
+
+	result, err := h.migrateTemplateUseCase.Execute(ctx, &channel_application.MigrateChannelTemplateRequest{
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
+		Properties:   properties,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Migration, nil
+}
+
+func (h *ChannelHandler) SetOccupancyService(occupancy *channel_occupancy_usecases.SlotOccupancyService) {
+	h.occupancy = occupancy
+}
+
+// RequestSlotOccupancy asks for candidate to fill a gated slot. An empty
+// candidate vault requests the slot for the acting vault.
+func (h *ChannelHandler) RequestSlotOccupancy(ctx context.Context, userID string, actorVaultID string, channelID string, slotID string, candidate channel_domain.Assignment) (*channel_domain.OccupancyRequest, error) {
+	if h.occupancy == nil {
+		return nil, fmt.Errorf("slot occupancy service is not initialized")
+	}
+
+	return h.occupancy.Request(ctx, &channel_application.RequestSlotOccupancyRequest{
+		ChannelID:    channelID,
+		SlotID:       slotID,
+		ActorVaultID: actorVaultID,
+		Candidate:    candidate,
+	})
+}
+
+// VoteSlotOccupancy signs and records the acting vault's approval or
+// rejection of an occupancy request.
+func (h *ChannelHandler) VoteSlotOccupancy(ctx context.Context, userID string, signer channel_occupancy_usecases.VoteSigner, actorVaultID string, requestID string, decision string, reason string) (*channel_domain.OccupancyRequest, error) {
+	if h.occupancy == nil {
+		return nil, fmt.Errorf("slot occupancy service is not initialized")
+	}
+
+	return h.occupancy.Vote(ctx, signer, &channel_application.VoteSlotOccupancyRequest{
+		RequestID:    requestID,
+		ActorVaultID: actorVaultID,
+		Decision:     decision,
+		Reason:       reason,
+	})
+}
+
+func (h *ChannelHandler) WithdrawSlotOccupancy(ctx context.Context, userID string, actorVaultID string, requestID string) (*channel_domain.OccupancyRequest, error) {
+	if h.occupancy == nil {
+		return nil, fmt.Errorf("slot occupancy service is not initialized")
+	}
+
+	return h.occupancy.Withdraw(ctx, &channel_application.WithdrawSlotOccupancyRequest{
+		RequestID:    requestID,
+		ActorVaultID: actorVaultID,
+	})
+}
+
+func (h *ChannelHandler) ListSlotOccupancyRequests(ctx context.Context, userID string, channelID string) ([]channel_domain.OccupancyRequest, error) {
+	if h.occupancy == nil {
+		return nil, fmt.Errorf("slot occupancy service is not initialized")
+	}
+	return h.occupancy.List(ctx, channelID)
+}
+
+// GetChannelBlockers explains what keeps the channel from accepting threads.
+func (h *ChannelHandler) GetChannelBlockers(ctx context.Context, userID string, channelID string) ([]channel_domain.ChannelBlocker, error) {
+	if h.occupancy == nil {
+		return nil, fmt.Errorf("slot occupancy service is not initialized")
+	}
+	return h.occupancy.Blockers(ctx, channelID)
+}
+
 func (h *ChannelHandler) CreateChannel(ctx context.Context, userID string, workspaceID string, title string, templateID string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy channel_domain.Policy, federation string) (*tracecore_types.ChannelDTO, error) {
 	if h.createUseCase == nil {
 		return nil, fmt.Errorf("create channel use case is not initialized")
@@ -79,13 +323,14 @@ func (h *ChannelHandler) CreateChannel(ctx context.Context, userID string, works
 // GetChannel fetches a single Channel from the authoritative Cloud backend
 // (GET /channels/{id}). The returned Channel is the Cloud-persisted aggregate;
 // no local channel is ever fabricated.
-func (h *ChannelHandler) GetChannel(ctx context.Context, userID string, channelID string) (*tracecore_types.ChannelDTO, error) {
+func (h *ChannelHandler) GetChannel(ctx context.Context, userID string, actorVaultID string, channelID string) (*tracecore_types.ChannelDTO, error) {
 	if h.getUseCase == nil {
 		return nil, fmt.Errorf("get channel use case is not initialized")
 	}
 
 	req := &channel_application.GetChannelRequest{
-		ChannelID: channelID,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
 	}
 
 	ch, err := h.getUseCase.Execute(ctx, req)
@@ -99,18 +344,19 @@ func (h *ChannelHandler) GetChannel(ctx context.Context, userID string, channelI
 // UpdateChannel updates an existing Channel through the authoritative Cloud
 // backend (PUT /channels/{id}). The Cloud-persisted aggregate is returned; no
 // local mutation is performed.
-func (h *ChannelHandler) UpdateChannel(ctx context.Context, userID string, channelID string, title string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy channel_domain.Policy) (*tracecore_types.ChannelDTO, error) {
+func (h *ChannelHandler) UpdateChannel(ctx context.Context, userID string, actorVaultID string, channelID string, title string, slots []channel_domain.Slot, assignments []channel_domain.Assignment, properties []channel_domain.ChannelProperty, policy channel_domain.Policy) (*tracecore_types.ChannelDTO, error) {
 	if h.updateUseCase == nil {
 		return nil, fmt.Errorf("update channel use case is not initialized")
 	}
 
 	req := &channel_application.UpdateChannelRequest{
-		ChannelID:   channelID,
-		Title:       title,
-		Slots:       slots,
-		Assignments: assignments,
-		Properties:  properties,
-		Policy:      policy,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
+		Title:        title,
+		Slots:        slots,
+		Assignments:  assignments,
+		Properties:   properties,
+		Policy:       policy,
 	}
 
 	ch, err := h.updateUseCase.Execute(ctx, req)
@@ -124,25 +370,27 @@ func (h *ChannelHandler) UpdateChannel(ctx context.Context, userID string, chann
 // DeleteChannel deletes a Channel through the authoritative Cloud backend
 // (DELETE /channels/{id}). Cloud is the single source of truth for channel
 // existence; a 2xx response is success and HTTP >=400 is surfaced verbatim.
-func (h *ChannelHandler) DeleteChannel(ctx context.Context, userID string, channelID string) error {
+func (h *ChannelHandler) DeleteChannel(ctx context.Context, userID string, actorVaultID string, channelID string) error {
 	if h.deleteUseCase == nil {
 		return fmt.Errorf("delete channel use case is not initialized")
 	}
 
 	req := &channel_application.DeleteChannelRequest{
-		ChannelID: channelID,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
 	}
 
 	return h.deleteUseCase.Execute(ctx, req)
 }
 
-func (h *ChannelHandler) ActivateChannel(ctx context.Context, userID string, channelID string) (*tracecore_types.ChannelDTO, error) {
+func (h *ChannelHandler) ActivateChannel(ctx context.Context, userID string, actorVaultID string, channelID string) (*tracecore_types.ChannelDTO, error) {
 	if h.activateUseCase == nil {
 		return nil, fmt.Errorf("activate channel use case is not initialized")
 	}
 
 	req := &channel_application.ActivateChannelRequest{
-		ChannelID: channelID,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
 	}
 
 	ch, err := h.activateUseCase.Execute(ctx, req)
@@ -153,13 +401,14 @@ func (h *ChannelHandler) ActivateChannel(ctx context.Context, userID string, cha
 	return toTracecoreChannelDTO(ch), nil
 }
 
-func (h *ChannelHandler) RevokeChannel(ctx context.Context, userID string, channelID string) error {
+func (h *ChannelHandler) RevokeChannel(ctx context.Context, userID string, actorVaultID string, channelID string) error {
 	if h.revokeUseCase == nil {
 		return fmt.Errorf("revoke channel use case is not initialized")
 	}
 
 	req := &channel_application.RevokeChannelRequest{
-		ChannelID: channelID,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
 	}
 
 	return h.revokeUseCase.Execute(ctx, req)
@@ -202,13 +451,14 @@ func (h *ChannelHandler) AddParticipant(
 
 // ListParticipants returns the vaults Cloud has persisted as participants for
 // the channel. An empty result is valid.
-func (h *ChannelHandler) ListParticipants(ctx context.Context, userID string, channelID string) ([]tracecore_types.ChannelParticipantDTO, error) {
+func (h *ChannelHandler) ListParticipants(ctx context.Context, userID string, actorVaultID string, channelID string) ([]tracecore_types.ChannelParticipantDTO, error) {
 	if h.listParticipantsUseCase == nil {
 		return nil, fmt.Errorf("list participants use case is not initialized")
 	}
 
 	req := &channel_application.ListParticipantsRequest{
-		ChannelID: channelID,
+		ChannelID:    channelID,
+		ActorVaultID: actorVaultID,
 	}
 
 	participants, err := h.listParticipantsUseCase.Execute(ctx, req)
@@ -305,6 +555,122 @@ func (h *ChannelHandler) AcceptChannelInvitation(
 	return toChannelInvitationDTO(inv), nil
 }
 
+func (h *ChannelHandler) SetInvitationLifecycleUseCases(
+	rejectUC *channel_usecase.RejectChannelInvitationUsecase,
+	revokeUC *channel_usecase.RevokeChannelInvitationUsecase,
+	listUC *channel_usecase.ListChannelInvitationsUsecase,
+	expireUC *channel_usecase.ExpireChannelInvitationsUsecase,
+) {
+	h.rejectInvitationUseCase = rejectUC
+	h.revokeInvitationUseCase = revokeUC
+	h.listInvitationsUseCase = listUC
+	h.expireInvitationsUseCase = expireUC
+}
+
+// RejectChannelInvitation declines a pending invitation on behalf of its
+// invitee (POST /channels/invitations/{id}/reject).
+func (h *ChannelHandler) RejectChannelInvitation(
+	ctx context.Context,
+	userID string,
+	invitationID string,
+	inviteeVaultID string,
+) (*tracecore_types.ChannelInvitationDTO, error) {
+	if h.rejectInvitationUseCase == nil {
+		return nil, fmt.Errorf("reject channel invitation use case is not initialized")
+	}
+
+	inv, err := h.rejectInvitationUseCase.Execute(ctx, &channel_application.RejectChannelInvitationRequest{
+		InvitationID:   invitationID,
+		InviteeVaultID: inviteeVaultID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toChannelInvitationDTO(inv), nil
+}
+
+// RevokeChannelInvitation withdraws a pending invitation on behalf of its
+// inviter (POST /channels/invitations/{id}/revoke).
+func (h *ChannelHandler) RevokeChannelInvitation(
+	ctx context.Context,
+	userID string,
+	invitationID string,
+	inviterVaultID string,
+	reason string,
+) (*tracecore_types.ChannelInvitationDTO, error) {
+	if h.revokeInvitationUseCase == nil {
+		return nil, fmt.Errorf("revoke channel invitation use case is not initialized")
+	}
+
+	inv, err := h.revokeInvitationUseCase.Execute(ctx, &channel_application.RevokeChannelInvitationRequest{
+		InvitationID:   invitationID,
+		InviterVaultID: inviterVaultID,
+		Reason:         reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toChannelInvitationDTO(inv), nil
+}
+
+// ListChannelInvitations lists the invitations a vault received or sent.
+// Pending invitations past their expiry are reported as expired.
+func (h *ChannelHandler) ListChannelInvitations(
+	ctx context.Context,
+	userID string,
+	vaultID string,
+	direction string,
+	channelID string,
+	pendingOnly bool,
+) ([]tracecore_types.ChannelInvitationDTO, error) {
+	if h.listInvitationsUseCase == nil {
+		return nil, fmt.Errorf("list channel invitations use case is not initialized")
+	}
+
+	invitations, err := h.listInvitationsUseCase.Execute(ctx, &channel_application.ListChannelInvitationsRequest{
+		VaultID:     vaultID,
+		Direction:   channel_domain.InvitationDirection(direction),
+		ChannelID:   channelID,
+		PendingOnly: pendingOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toChannelInvitationDTOs(invitations), nil
+}
+
+// ExpireChannelInvitations marks expired the pending invitations the vault
+// sent that ran past their expiry and returns them.
+func (h *ChannelHandler) ExpireChannelInvitations(
+	ctx context.Context,
+	userID string,
+	inviterVaultID string,
+) ([]tracecore_types.ChannelInvitationDTO, error) {
+	if h.expireInvitationsUseCase == nil {
+		return nil, fmt.Errorf("expire channel invitations use case is not initialized")
+	}
+
+	invitations, err := h.expireInvitationsUseCase.Execute(ctx, &channel_application.ExpireChannelInvitationsRequest{
+		InviterVaultID: inviterVaultID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toChannelInvitationDTOs(invitations), nil
+}
+
+func toChannelInvitationDTOs(invitations []channel_domain.Invitation) []tracecore_types.ChannelInvitationDTO {
+	res := make([]tracecore_types.ChannelInvitationDTO, 0, len(invitations))
+	for i := range invitations {
+		res = append(res, *toChannelInvitationDTO(&invitations[i]))
+	}
+	return res
+}
+
 func toChannelInvitationDTO(inv *channel_domain.Invitation) *tracecore_types.ChannelInvitationDTO {
 	if inv == nil {
 		return nil
@@ -318,16 +684,18 @@ func toChannelInvitationDTO(inv *channel_domain.Invitation) *tracecore_types.Cha
 		Status:         string(inv.Status),
 		CreatedAt:      inv.CreatedAt,
 		AcceptedAt:     inv.AcceptedAt,
+		ExpiresAt:      inv.ExpiresAt,
 	}
 }
 
-func (h *ChannelHandler) ListChannels(ctx context.Context, userID string, workspaceID string) ([]tracecore_types.ChannelDTO, error) {
+func (h *ChannelHandler) ListChannels(ctx context.Context, userID string, actorVaultID string, workspaceID string) ([]tracecore_types.ChannelDTO, error) {
 	if h.listUseCase == nil {
 		return nil, fmt.Errorf("list channel use case is not initialized")
 	}
 
 	req := &channel_application.ListChannelsRequest{
-		WorkspaceID: workspaceID,
+		WorkspaceID:  workspaceID,
+		ActorVaultID: actorVaultID,
 	}
 
 	channels, err := h.listUseCase.Execute(ctx, req)
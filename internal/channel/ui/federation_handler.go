@@ -0,0 +1,135 @@
+package channel_ui
+
+import (
+	"context"
+	"fmt"
+
+	channel_federation "vault-app/internal/channel/application/federation"
+	channel_domain "vault-app/internal/channel/domain"
+)
+
+// FederationHandler exposes the federation sync engine to operators: run a
+// pass, inspect dead letters and requeue them, and manage remote vault trust.
+type FederationHandler struct {
+	engine *channel_federation.Engine
+	trust  *channel_federation.TrustService
+}
+
+func NewFederationHandler(engine *channel_federation.Engine) *FederationHandler {
+	return &FederationHandler{engine: engine}
+}
+
+// SetTrustService enables the remote vault trust operations.
+func (h *FederationHandler) SetTrustService(trust *channel_federation.TrustService) {
+	h.trust = trust
+}
+
+// SetLocalVault sets the user whose snapshot the engine drives and the vault
+// it pushes batches from.
+func (h *FederationHandler) SetLocalVault(userID string, vaultID string) {
+	if h.engine != nil {
+		h.engine.SetLocalVault(userID, vaultID)
+	}
+}
+
+// OnVaultLocked stops the engine from driving userID's snapshot.
+func (h *FederationHandler) OnVaultLocked(userID string) {
+	if h.engine != nil {
+		h.engine.ClearLocalVault(userID)
+	}
+}
+
+func (h *FederationHandler) RunSync(ctx context.Context, userID string) (*channel_federation.SyncReport, error) {
+	if h.engine == nil {
+		return nil, fmt.Errorf("federation engine is not initialized")
+	}
+
+	return h.engine.RunOnce(ctx)
+}
+
+// ListDeadLetters lists dead-lettered sync items, for one remote vault or
+// all of them when vaultID is empty.
+func (h *FederationHandler) ListDeadLetters(ctx context.Context, userID string, vaultID string) ([]channel_federation.DeadLetter, error) {
+	if h.engine == nil {
+		return nil, fmt.Errorf("federation engine is not initialized")
+	}
+
+	return h.engine.DeadLetters(ctx, vaultID)
+}
+
+// RequeueDeadLetters requeues one dead-lettered item, or every dead letter
+// of the vault (all vaults when vaultID is empty) when exchangeID is empty.
+// It returns how many items were requeued.
+func (h *FederationHandler) RequeueDeadLetters(ctx context.Context, userID string, vaultID string, exchangeID string) (int, error) {
+	if h.engine == nil {
+		return 0, fmt.Errorf("federation engine is not initialized")
+	}
+
+	if exchangeID == "" {
+		return h.engine.RequeueAll(ctx, vaultID)
+	}
+	if err := h.engine.Requeue(ctx, vaultID, exchangeID); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Handshake exchanges identity documents with the vault at remoteEndpoint.
+// The remote is recorded as pending until its key is approved.
+func (h *FederationHandler) Handshake(
+	ctx context.Context,
+	userID string,
+	signer channel_federation.IdentitySigner,
+	localVaultID string,
+	localEndpoint string,
+	remoteEndpoint string,
+) (*channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.Handshake(ctx, signer, localVaultID, localEndpoint, remoteEndpoint)
+}
+
+// AcceptIdentity records an identity document a remote vault sent us.
+func (h *FederationHandler) AcceptIdentity(ctx context.Context, userID string, doc channel_domain.IdentityDocument) (*channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.AcceptIdentity(ctx, doc)
+}
+
+func (h *FederationHandler) ListRemoteVaults(ctx context.Context, userID string) ([]channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.List(ctx)
+}
+
+// ApproveRemoteVault pins publicKey, which must be the key the remote
+// presented, and trusts the remote.
+func (h *FederationHandler) ApproveRemoteVault(ctx context.Context, userID string, vaultID string, publicKey string) (*channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.Approve(ctx, vaultID, publicKey)
+}
+
+func (h *FederationHandler) SuspendRemoteVault(ctx context.Context, userID string, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.Suspend(ctx, vaultID, reason)
+}
+
+func (h *FederationHandler) RevokeRemoteVault(ctx context.Context, userID string, vaultID string, reason string) (*channel_domain.RemoteVault, error) {
+	if h.trust == nil {
+		return nil, fmt.Errorf("federation trust service is not initialized")
+	}
+
+	return h.trust.Revoke(ctx, vaultID, reason)
+}
@@ -0,0 +1,125 @@
+package cli_usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cli_domain "vault-app/internal/cli/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type CreateEntryRequest struct {
+	Type   string
+	Name   string
+	Folder string
+	// Fields are applied with cli_domain.SetField, in order.
+	Fields [][2]string
+}
+
+type EditEntryRequest struct {
+	Entry  string
+	Name   string
+	Folder string
+	Fields [][2]string
+}
+
+// EntriesUsecase creates, edits and trashes entries through the backend.
+type EntriesUsecase struct {
+	Backend cli_domain.Backend
+	Now     func() time.Time
+}
+
+func NewEntriesUsecase(backend cli_domain.Backend) *EntriesUsecase {
+	return &EntriesUsecase{Backend: backend, Now: time.Now}
+}
+
+func (uc *EntriesUsecase) Create(ctx context.Context, req CreateEntryRequest) (cli_domain.Entry, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: an entry name is required", cli_domain.ErrUsage)
+	}
+	entry, err := cli_domain.NewEntry(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	base := entry.GetBase()
+	base.EntryName = strings.TrimSpace(req.Name)
+	now := uc.Now().Format(time.RFC3339)
+	base.CreatedAt, base.UpdatedAt = now, now
+	if err := uc.apply(ctx, entry, req.Folder, req.Fields); err != nil {
+		return nil, err
+	}
+	if otp, ok := entry.(*vaults_domain.OTPEntry); ok {
+		if err := otp.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.Backend.AddEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (uc *EntriesUsecase) Edit(ctx context.Context, req EditEntryRequest) (cli_domain.Entry, error) {
+	vp, err := uc.Backend.Vault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := cli_domain.FindEntry(vp, req.Entry)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		entry.GetBase().EntryName = name
+	}
+	if err := uc.apply(ctx, entry, req.Folder, req.Fields); err != nil {
+		return nil, err
+	}
+	if otp, ok := entry.(*vaults_domain.OTPEntry); ok {
+		if err := otp.Normalize(); err != nil {
+			return nil, err
+		}
+	}
+	entry.GetBase().UpdatedAt = uc.Now().Format(time.RFC3339)
+	if err := uc.Backend.UpdateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Delete moves an entry to the trash, like the desktop app.
+func (uc *EntriesUsecase) Delete(ctx context.Context, key string) (cli_domain.Entry, error) {
+	vp, err := uc.Backend.Vault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := cli_domain.FindEntry(vp, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.Backend.TrashEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (uc *EntriesUsecase) apply(ctx context.Context, entry cli_domain.Entry, folder string, fields [][2]string) error {
+	if folder != "" {
+		vp, err := uc.Backend.Vault(ctx)
+		if err != nil {
+			return err
+		}
+		f, err := cli_domain.FindFolder(vp, folder)
+		if err != nil {
+			return err
+		}
+		entry.GetBase().FolderID = f.ID
+	}
+	for _, kv := range fields {
+		if err := cli_domain.SetField(entry, kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
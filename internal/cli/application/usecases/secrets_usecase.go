@@ -0,0 +1,131 @@
+package cli_usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cli_domain "vault-app/internal/cli/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// templateRef matches {{ dvault://entry/field }} in inject templates.
+var templateRef = regexp.MustCompile(`\{\{\s*(dvault://[^}]*?)\s*\}\}`)
+
+// SecretResolver reads secret references against the unlocked vault. The
+// vault is loaded once per resolver, so a template or environment sees a
+// consistent snapshot.
+type SecretResolver struct {
+	Backend cli_domain.Backend
+
+	vp *vaults_domain.VaultPayload
+}
+
+func NewSecretResolver(backend cli_domain.Backend) *SecretResolver {
+	return &SecretResolver{Backend: backend}
+}
+
+func (r *SecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	parsed, err := cli_domain.ParseSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+	if r.vp == nil {
+		if r.vp, err = r.Backend.Vault(ctx); err != nil {
+			return "", err
+		}
+	}
+	entry, err := cli_domain.FindEntry(r.vp, parsed.Entry)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+	value, err := cli_domain.FieldValue(entry, parsed.Field)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+	return value, nil
+}
+
+// ResolveEnv builds the environment of a child process. Every variable of
+// env whose value is a secret reference is replaced by the secret, then
+// each NAME=dvault://... mapping is added on top. The resolved secrets are
+// returned as well so callers can mask them.
+func (r *SecretResolver) ResolveEnv(ctx context.Context, env []string, mappings []string) ([]string, []string, error) {
+	vars := map[string]string{}
+	var order []string
+	set := func(kv string) error {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("%w: environment mapping %q must be NAME=VALUE", cli_domain.ErrUsage, kv)
+		}
+		if _, seen := vars[name]; !seen {
+			order = append(order, name)
+		}
+		vars[name] = value
+		return nil
+	}
+	for _, kv := range env {
+		if err := set(kv); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, kv := range mappings {
+		if err := set(kv); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var secrets []string
+	out := make([]string, 0, len(order))
+	for _, name := range order {
+		value := vars[name]
+		if cli_domain.IsSecretRef(value) {
+			secret, err := r.Resolve(ctx, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+			value = secret
+			secrets = append(secrets, secret)
+		}
+		out = append(out, name+"="+value)
+	}
+	return out, secrets, nil
+}
+
+// Render replaces every {{ dvault://entry/field }} of tmpl with its secret.
+// All references are resolved before anything is returned, so a missing
+// secret never yields a half-rendered file.
+func (r *SecretResolver) Render(ctx context.Context, tmpl []byte) ([]byte, error) {
+	var firstErr error
+	out := templateRef.ReplaceAllFunc(tmpl, func(m []byte) []byte {
+		if firstErr != nil {
+			return m
+		}
+		ref := string(templateRef.FindSubmatch(m)[1])
+		value, err := r.Resolve(ctx, ref)
+		if err != nil {
+			firstErr = err
+			return m
+		}
+		return []byte(value)
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// Mask conceals every secret in s, longest first so a
+// secret containing another is fully hidden.
+func Mask(s string, secrets []string) string {
+	sorted := append([]string{}, secrets...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, secret := range sorted {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "<concealed by dvault>")
+		}
+	}
+	return s
+}
@@ -0,0 +1,285 @@
+package cli_domain
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// EntryTypes lists the type names accepted by NewEntry, as used by the
+// entry registry.
+var EntryTypes = []string{"login", "card", "identity", "note", "sshkey", "otp"}
+
+func NewEntry(typeName string) (Entry, error) {
+	switch strings.ToLower(typeName) {
+	case "login":
+		return &vaults_domain.LoginEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntryLogin}}, nil
+	case "card":
+		return &vaults_domain.CardEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntryCard}}, nil
+	case "identity":
+		return &vaults_domain.IdentityEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntryIdentity}}, nil
+	case "note":
+		return &vaults_domain.NoteEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntryNote}}, nil
+	case "sshkey", "ssh_key", "ssh":
+		return &vaults_domain.SSHKeyEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntrySSHKey}}, nil
+	case "otp", "totp", "hotp":
+		return &vaults_domain.OTPEntry{BaseEntry: vaults_domain.BaseEntry{Type: vaults_domain.EntryOTP}}, nil
+	}
+	return nil, fmt.Errorf("%w: %s (expected one of %s)", ErrUnknownEntryType, typeName, strings.Join(EntryTypes, ", "))
+}
+
+// Entries returns the entries of the vault, skipping trashed ones unless asked.
+func Entries(vp *vaults_domain.VaultPayload, trashed bool) []Entry {
+	var out []Entry
+	for _, e := range vp.Entries.All() {
+		entry, ok := e.(Entry)
+		if !ok || entry.GetBase().Trashed != trashed {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// FindEntry resolves an entry by id, then by case-insensitive name among
+// entries that are not trashed.
+func FindEntry(vp *vaults_domain.VaultPayload, key string) (Entry, error) {
+	for _, e := range vp.Entries.All() {
+		if entry, ok := e.(Entry); ok && entry.GetId() == key {
+			return entry, nil
+		}
+	}
+	var found []Entry
+	for _, entry := range Entries(vp, false) {
+		if strings.EqualFold(entry.GetName(), key) {
+			found = append(found, entry)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, key)
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("%w: %q matches %d entries", ErrAmbiguousEntry, key, len(found))
+}
+
+// FindFolder resolves a folder by id or case-insensitive name.
+func FindFolder(vp *vaults_domain.VaultPayload, key string) (*vaults_domain.Folder, error) {
+	for i := range vp.Folders {
+		if vp.Folders[i].ID == key {
+			return &vp.Folders[i], nil
+		}
+	}
+	for i := range vp.Folders {
+		if strings.EqualFold(vp.Folders[i].Name, key) {
+			return &vp.Folders[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFolderNotFound, key)
+}
+
+// FindAttachment resolves an attachment of entry by id or file name.
+func FindAttachment(entry Entry, key string) (*vaults_domain.Attachment, error) {
+	atts := entry.GetBase().Attachments
+	for i := range atts {
+		if atts[i].ID == key || strings.EqualFold(atts[i].Name, key) {
+			return &atts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s on %s", ErrAttachmentNotFound, key, entry.GetName())
+}
+
+// internalFields are bookkeeping fields the CLI neither shows nor edits.
+var internalFields = map[string]bool{
+	"id": true, "cid": true, "type": true, "template_id": true, "folder_id": true,
+	"record_type": true, "schema_version": true, "shema_version": true,
+	"trashed": true, "is_draft": true, "is_dirty": true,
+	"created_at": true, "updated_at": true,
+}
+
+// fieldAliases map friendly names onto the entries' JSON field names.
+var fieldAliases = map[string]string{
+	"name":  "entryname",
+	"title": "entryname",
+	"notes": "additionnalnote",
+	"note":  "additionnalnote",
+	"url":   "website",
+}
+
+func normalizeField(name string) string {
+	n := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+	if alias, ok := fieldAliases[n]; ok {
+		return alias
+	}
+	return n
+}
+
+type structField struct {
+	name  string
+	value reflect.Value
+}
+
+// structFields lists the editable scalar fields of an entry by JSON name,
+// including those of the embedded BaseEntry.
+func structFields(entry Entry) []structField {
+	var out []structField
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(v.Field(i))
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" || name == "-" || internalFields[name] || !f.IsExported() {
+				continue
+			}
+			switch f.Type.Kind() {
+			case reflect.String, reflect.Bool, reflect.Int, reflect.Uint64:
+				out = append(out, structField{name: name, value: v.Field(i)})
+			}
+		}
+	}
+	walk(reflect.ValueOf(entry).Elem())
+	return out
+}
+
+// Fields flattens an entry into name/value strings: its own fields, then
+// custom fields. Empty values are left out.
+func Fields(entry Entry) map[string]string {
+	out := map[string]string{}
+	for _, f := range structFields(entry) {
+		if s := formatValue(f.value); s != "" {
+			out[f.name] = s
+		}
+	}
+	for k, v := range entry.GetBase().CustomFields {
+		if _, taken := out[k]; taken || v == nil {
+			continue
+		}
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// FieldNames returns the names of Fields in a stable order.
+func FieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FieldValue reads one field. OTP entries also expose "code", the current
+// TOTP code; HOTP codes consume a counter and are not read this way.
+func FieldValue(entry Entry, field string) (string, error) {
+	want := normalizeField(field)
+	if otp, ok := entry.(*vaults_domain.OTPEntry); ok && want == "code" {
+		if otp.Kind == vaults_domain.OTPKindHOTP {
+			return "", fmt.Errorf("%w: code of HOTP entry %s", ErrFieldNotFound, entry.GetName())
+		}
+		code, err := otp.Generate(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return code.Code, nil
+	}
+	for _, f := range structFields(entry) {
+		if normalizeField(f.name) == want {
+			return formatValue(f.value), nil
+		}
+	}
+	for k, v := range entry.GetBase().CustomFields {
+		if normalizeField(k) == want && v != nil {
+			return fmt.Sprint(v), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s on %s", ErrFieldNotFound, field, entry.GetName())
+}
+
+// SetField writes one field; names that are not entry fields become custom
+// fields. An empty value clears the field.
+func SetField(entry Entry, field string, value string) error {
+	want := normalizeField(field)
+	for _, f := range structFields(entry) {
+		if normalizeField(f.name) != want {
+			continue
+		}
+		switch f.value.Kind() {
+		case reflect.String:
+			f.value.SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(orDefault(value, "false"))
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			f.value.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.Atoi(orDefault(value, "0"))
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			f.value.SetInt(int64(n))
+		case reflect.Uint64:
+			n, err := strconv.ParseUint(orDefault(value, "0"), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			f.value.SetUint(n)
+		}
+		return nil
+	}
+	base := entry.GetBase()
+	for k := range base.CustomFields {
+		if normalizeField(k) == want {
+			field = k
+			break
+		}
+	}
+	if value == "" {
+		delete(base.CustomFields, field)
+		return nil
+	}
+	if base.CustomFields == nil {
+		base.CustomFields = vaults_domain.JSONMap{}
+	}
+	base.CustomFields[field] = value
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		if v.Bool() {
+			return "true"
+		}
+	case reflect.Int:
+		if v.Int() != 0 {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint64:
+		if v.Uint() != 0 {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	}
+	return ""
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
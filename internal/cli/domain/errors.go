@@ -0,0 +1,17 @@
+package cli_domain
+
+import "errors"
+
+var (
+	ErrCredentialsRequired = errors.New("credentials required: set DVAULT_EMAIL and DVAULT_PASSWORD, or DVAULT_STELLAR_SECRET")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrVaultLocked         = errors.New("vault is locked")
+	ErrEntryNotFound       = errors.New("entry not found")
+	ErrAmbiguousEntry      = errors.New("several entries match; use the entry id")
+	ErrFieldNotFound       = errors.New("field not found")
+	ErrFolderNotFound      = errors.New("folder not found")
+	ErrAttachmentNotFound  = errors.New("attachment not found")
+	ErrUnknownEntryType    = errors.New("unknown entry type")
+	ErrInvalidSecretRef    = errors.New("invalid secret reference")
+	ErrUsage               = errors.New("usage")
+)
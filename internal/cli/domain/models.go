@@ -0,0 +1,105 @@
+package cli_domain
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// Credentials unlock a vault: email and password, or a Stellar secret key
+// alone, the same two ways the desktop app signs in.
+type Credentials struct {
+	Email         string
+	Password      string
+	StellarSecret string
+}
+
+func (c Credentials) Stellar() bool {
+	return c.StellarSecret != ""
+}
+
+func (c Credentials) Validate() error {
+	if c.Stellar() || (c.Email != "" && c.Password != "") {
+		return nil
+	}
+	return ErrCredentialsRequired
+}
+
+// Session describes the vault a Backend unlocked.
+type Session struct {
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	VaultName string `json:"vault_name"`
+}
+
+// Backend is what the CLI drives. Writes change the unlocked session only;
+// Sync is what persists them, as in the desktop app.
+type Backend interface {
+	Unlock(ctx context.Context, creds Credentials) (*Session, error)
+	Lock(ctx context.Context) error
+	Vault(ctx context.Context) (*vaults_domain.VaultPayload, error)
+
+	AddEntry(ctx context.Context, entry Entry) error
+	UpdateEntry(ctx context.Context, entry Entry) error
+	TrashEntry(ctx context.Context, entry Entry) error
+
+	CreateFolder(ctx context.Context, name string) (*vaults_domain.Folder, error)
+	RenameFolder(ctx context.Context, id string, name string) error
+	DeleteFolder(ctx context.Context, id string) error
+
+	GetAttachment(ctx context.Context, att vaults_domain.Attachment) ([]byte, error)
+	PutAttachment(ctx context.Context, entry Entry, name string, data []byte) (*vaults_domain.Attachment, error)
+
+	Sync(ctx context.Context) (string, error)
+}
+
+// FeatureGate is implemented by backends that know the account's
+// subscription. Commands behind a paid feature refuse to run on backends
+// that do not implement it.
+type FeatureGate interface {
+	GitCLIEnabled(ctx context.Context) (bool, error)
+}
+
+// Entry is a vault entry as handled by the CLI.
+type Entry interface {
+	vaults_domain.VaultEntry
+	GetBase() *vaults_domain.BaseEntry
+}
+
+const SecretRefScheme = "dvault://"
+
+// SecretRef points at one field of one entry: dvault://<entry>/<field>.
+// The entry is an id or a name; names containing "/" are allowed since the
+// field is always the last segment. Either part may be percent-encoded.
+type SecretRef struct {
+	Entry string
+	Field string
+}
+
+func ParseSecretRef(s string) (SecretRef, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), SecretRefScheme)
+	if !ok {
+		return SecretRef{}, fmt.Errorf("%w: %q does not start with %s", ErrInvalidSecretRef, s, SecretRefScheme)
+	}
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 || i == len(rest)-1 {
+		return SecretRef{}, fmt.Errorf("%w: %q, expected %s<entry>/<field>", ErrInvalidSecretRef, s, SecretRefScheme)
+	}
+	entry, err1 := url.PathUnescape(rest[:i])
+	field, err2 := url.PathUnescape(rest[i+1:])
+	if err1 != nil || err2 != nil {
+		return SecretRef{}, fmt.Errorf("%w: %q has an invalid escape", ErrInvalidSecretRef, s)
+	}
+	return SecretRef{Entry: entry, Field: field}, nil
+}
+
+func IsSecretRef(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), SecretRefScheme)
+}
+
+func (r SecretRef) String() string {
+	return SecretRefScheme + r.Entry + "/" + r.Field
+}
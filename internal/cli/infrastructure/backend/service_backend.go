@@ -0,0 +1,307 @@
+package cli_backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+
+	"vault-app/internal/blockchain"
+	cli_domain "vault-app/internal/cli/domain"
+	app_config_domain "vault-app/internal/config/domain"
+	app_config_ui "vault-app/internal/config/ui"
+	identity_commands "vault-app/internal/identity/application/commands"
+	identity_ui "vault-app/internal/identity/ui"
+	onboarding_domain "vault-app/internal/onboarding/domain"
+	subscription_domain "vault-app/internal/subscription/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+	vault_commands "vault-app/internal/vault/application/commands"
+	vault_dto "vault-app/internal/vault/application/dto"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_ui "vault-app/internal/vault/ui"
+)
+
+// stellarLoginMessage is the challenge signed for a Stellar secret login.
+const stellarLoginMessage = "dvault-cli-login"
+
+type SubscriptionFinder interface {
+	FindByEmail(ctx context.Context, email string) (*subscription_domain.Subscription, error)
+}
+
+// ServiceBackend drives the application services the desktop app uses:
+// identity login, the vault session, entry handlers and cloud sync. It
+// follows App.SignIn without the realtime and cloud delegation steps, which
+// a one-shot command has no use for.
+type ServiceBackend struct {
+	Vaults         *vault_ui.VaultHandler
+	Identity       *identity_ui.IdentityHandler
+	AppConfig      *app_config_ui.AppConfigHandler
+	OnboardingRepo onboarding_domain.UserRepository
+	Subscriptions  SubscriptionFinder
+
+	userID       string
+	email        string
+	password     string
+	onboardingID string
+	subscription *subscription_domain.Subscription
+}
+
+var (
+	_ cli_domain.Backend     = (*ServiceBackend)(nil)
+	_ cli_domain.FeatureGate = (*ServiceBackend)(nil)
+)
+
+func (b *ServiceBackend) Unlock(ctx context.Context, creds cli_domain.Credentials) (*cli_domain.Session, error) {
+	if err := creds.Validate(); err != nil {
+		return nil, err
+	}
+	cmd := identity_commands.LoginCommand{Email: creds.Email, Password: creds.Password}
+	if creds.Stellar() {
+		kp, err := keypair.ParseFull(creds.StellarSecret)
+		if err != nil {
+			return nil, cli_domain.ErrInvalidCredentials
+		}
+		signature, err := blockchain.SignActorWithStellarPrivateKey(creds.StellarSecret, stellarLoginMessage)
+		if err != nil {
+			return nil, err
+		}
+		cmd = identity_commands.LoginCommand{PublicKey: kp.Address(), SignedMessage: stellarLoginMessage, Signature: signature}
+	}
+
+	result, err := b.Identity.Login(cmd)
+	if err != nil || result == nil || result.User == nil {
+		return nil, cli_domain.ErrInvalidCredentials
+	}
+	userID, email := result.User.ID, result.User.Email
+
+	password := creds.Password
+	if creds.Stellar() {
+		if password, err = b.stellarPassword(userID, creds.StellarSecret); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cloud sync needs a cloud token; without one the vault stays usable locally.
+	if tc := b.Vaults.TracecoreClient; tc != nil {
+		res, err := tc.Login(ctx, tracecore_types.LoginRequest{Email: email, Password: password})
+		if err == nil && res != nil && res.AuthenticationToken != nil && res.AuthenticationToken.Token != "" {
+			tc.SetToken(res.AuthenticationToken.Token)
+		}
+	}
+
+	session, err := b.Vaults.PrepareSession(userID)
+	if err != nil {
+		return nil, err
+	}
+	onboarding, err := b.OnboardingRepo.FindByEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("find onboarding user: %w", err)
+	}
+	subscription, err := b.Subscriptions.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("find subscription: %w", err)
+	}
+	res, err := b.Vaults.Open(ctx, vault_commands.OpenVaultCommand{
+		UserID:           userID,
+		Password:         password,
+		Session:          session,
+		UserOnboardingID: onboarding.ID,
+		Subscription:     *subscription,
+	}, b.AppConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	b.userID, b.email, b.password = userID, email, password
+	b.onboardingID, b.subscription = onboarding.ID, subscription
+
+	name := ""
+	if res.Content != nil {
+		name = res.Content.Name
+	}
+	return &cli_domain.Session{UserID: userID, Email: email, VaultName: name}, nil
+}
+
+// stellarPassword recovers the vault password the desktop app stored
+// encrypted under the user's Stellar key.
+func (b *ServiceBackend) stellarPassword(userID, secret string) (string, error) {
+	cfg, err := b.AppConfig.GetUserConfigByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	acct := cfg.StellarAccount
+	if len(acct.EncPassword) == 0 {
+		return "", errors.New("no vault password is stored for this Stellar account")
+	}
+	var password string
+	if len(acct.EncSalt) > 0 {
+		password, err = blockchain.DecryptPasswordWithStellarSecure(acct.EncSalt, acct.EncNonce, acct.EncPassword, secret)
+	} else {
+		password, err = blockchain.DecryptPasswordWithStellar(acct.EncNonce, acct.EncPassword, secret)
+	}
+	if err != nil {
+		return "", cli_domain.ErrInvalidCredentials
+	}
+	return password, nil
+}
+
+func (b *ServiceBackend) Lock(context.Context) error {
+	if b.userID == "" {
+		return nil
+	}
+	err := b.Vaults.LogoutUser(b.userID)
+	b.userID, b.email, b.password, b.onboardingID, b.subscription = "", "", "", "", nil
+	return err
+}
+
+func (b *ServiceBackend) Vault(context.Context) (*vaults_domain.VaultPayload, error) {
+	if b.userID == "" {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	return b.Vaults.GetVaultSession(b.userID)
+}
+
+func (b *ServiceBackend) AddEntry(_ context.Context, entry cli_domain.Entry) error {
+	if b.userID == "" {
+		return cli_domain.ErrVaultLocked
+	}
+	_, err := b.Vaults.AddEntryFor(b.userID, entry)
+	return err
+}
+
+func (b *ServiceBackend) UpdateEntry(_ context.Context, entry cli_domain.Entry) error {
+	if b.userID == "" {
+		return cli_domain.ErrVaultLocked
+	}
+	_, err := b.Vaults.UpdateEntryFor(b.userID, entry, false)
+	return err
+}
+
+func (b *ServiceBackend) TrashEntry(_ context.Context, entry cli_domain.Entry) error {
+	if b.userID == "" {
+		return cli_domain.ErrVaultLocked
+	}
+	return b.Vaults.TrashEntryFor(b.userID, entry)
+}
+
+func (b *ServiceBackend) CreateFolder(_ context.Context, name string) (*vaults_domain.Folder, error) {
+	if b.userID == "" {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	vp, err := b.Vaults.CreateFolder(b.userID, name)
+	if err != nil {
+		return nil, err
+	}
+	folder := vp.Folders[len(vp.Folders)-1]
+	return &folder, nil
+}
+
+// RenameFolder updates the folder row, then the session copy, which
+// VaultHandler.UpdateFolder leaves alone.
+func (b *ServiceBackend) RenameFolder(_ context.Context, id string, name string) error {
+	if b.userID == "" {
+		return cli_domain.ErrVaultLocked
+	}
+	folder, err := b.Vaults.UpdateFolder(id, name, false)
+	if err != nil {
+		return err
+	}
+	vp, err := b.Vaults.GetVaultSession(b.userID)
+	if err != nil {
+		return err
+	}
+	for i := range vp.Folders {
+		if vp.Folders[i].ID == id {
+			vp.Folders[i].Name = folder.Name
+			vp.Folders[i].UpdatedAt = folder.UpdatedAt
+			vp.Folders[i].IsDirty = true
+		}
+	}
+	if err := b.Vaults.SessionManager.SetVault(b.userID, vp); err != nil {
+		return err
+	}
+	b.Vaults.MarkDirty(b.userID)
+	return nil
+}
+
+func (b *ServiceBackend) DeleteFolder(_ context.Context, id string) error {
+	if b.userID == "" {
+		return cli_domain.ErrVaultLocked
+	}
+	return b.Vaults.DeleteFolder(b.userID, id)
+}
+
+func (b *ServiceBackend) GetAttachment(_ context.Context, att vaults_domain.Attachment) ([]byte, error) {
+	if b.userID == "" {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	return b.Vaults.LoadEntryAttachment(b.userID, att.Hash)
+}
+
+func (b *ServiceBackend) PutAttachment(ctx context.Context, entry cli_domain.Entry, name string, data []byte) (*vaults_domain.Attachment, error) {
+	if b.userID == "" {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	vault, cfg, err := b.configs()
+	if err != nil {
+		return nil, err
+	}
+	return b.Vaults.AddAttachement(ctx, vault_dto.AddAttachementRequest{
+		UserID:           b.userID,
+		Data:             data,
+		Password:         b.password,
+		EntryID:          entry.GetId(),
+		VaultName:        vault.Name,
+		UserOnboardingID: b.onboardingID,
+		Configs:          *cfg,
+		Name:             name,
+		Size:             int64(len(data)),
+		Ext:              strings.TrimPrefix(filepath.Ext(name), "."),
+	})
+}
+
+func (b *ServiceBackend) Sync(ctx context.Context) (string, error) {
+	if b.userID == "" {
+		return "", cli_domain.ErrVaultLocked
+	}
+	vault, cfg, err := b.configs()
+	if err != nil {
+		return "", err
+	}
+	// No frontend listens to the progress events.
+	return b.Vaults.SyncVaultWithProgress(ctx, vault_dto.SynchronizeVaultRequest{
+		UserID:         b.userID,
+		Password:       b.password,
+		Vault:          *vault,
+		UserOnboarding: b.onboardingID,
+		Configs:        *cfg,
+	}, nil)
+}
+
+// GitCLIEnabled reads the Git CLI feature from the unlocked user's config.
+func (b *ServiceBackend) GitCLIEnabled(context.Context) (bool, error) {
+	if b.userID == "" {
+		return false, cli_domain.ErrVaultLocked
+	}
+	_, cfg, err := b.configs()
+	if err != nil {
+		return false, err
+	}
+	return cfg.Subscription != nil && cfg.Subscription.Features.GitCLIEnabled, nil
+}
+
+// configs mirrors App.GetAllConfigs for the unlocked user.
+func (b *ServiceBackend) configs() (*vaults_domain.Vault, *app_config_domain.Config, error) {
+	vault, err := b.Vaults.GetLatestByUserID(b.userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := b.AppConfig.GetConfig(b.userID, *vault, b.subscription)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vault, cfg, nil
+}
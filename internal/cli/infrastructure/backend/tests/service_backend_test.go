@@ -0,0 +1,356 @@
+package cli_backend_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+
+	"vault-app/internal/blockchain"
+	cli_domain "vault-app/internal/cli/domain"
+	cli_backend "vault-app/internal/cli/infrastructure/backend"
+	app_config "vault-app/internal/config"
+	app_config_dto "vault-app/internal/config/application/dto"
+	app_config_domain "vault-app/internal/config/domain"
+	"vault-app/internal/driver"
+	identity_ui "vault-app/internal/identity/ui"
+	"vault-app/internal/logger/logger"
+	onboarding_usecase "vault-app/internal/onboarding/application/usecase"
+	onboarding_infrastructure_eventbus "vault-app/internal/onboarding/infrastructure/eventbus"
+	subscription_domain "vault-app/internal/subscription/domain"
+	subscription_persistence "vault-app/internal/subscription/infrastructure/persistence"
+	"vault-app/internal/tracecore"
+	vault_commands "vault-app/internal/vault/application/commands"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_infrastructure_crypto "vault-app/internal/vault/infrastructure/crypto"
+	vaults_persistence "vault-app/internal/vault/infrastructure/persistence"
+)
+
+const (
+	email    = "alice@example.com"
+	password = "correct horse battery staple"
+)
+
+// fakeIPFS answers the IPFS HTTP API calls the local storage mode makes:
+// version, add, cat, pin and id, keeping the blocks in memory.
+type fakeIPFS struct {
+	mu     sync.Mutex
+	blocks map[string][]byte
+}
+
+func (f *fakeIPFS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v0/add":
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, err := mr.NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		digest, err := multihash.Sum(data, multihash.SHA2_256, -1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c := cid.NewCidV1(cid.Raw, digest).String()
+		f.mu.Lock()
+		f.blocks[c] = data
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]string{"Hash": c})
+	case "/api/v0/cat":
+		f.mu.Lock()
+		data, ok := f.blocks[r.URL.Query().Get("arg")]
+		f.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"Message": "block not found", "Type": "error"})
+			return
+		}
+		_, _ = w.Write(data)
+	case "/api/v0/pin/add":
+		_ = json.NewEncoder(w).Encode(map[string][]string{"Pins": {r.URL.Query().Get("arg")}})
+	case "/api/v0/id":
+		_ = json.NewEncoder(w).Encode(map[string]string{"ID": "fake"})
+	case "/api/v0/version":
+		_ = json.NewEncoder(w).Encode(map[string]string{"Version": "0.29.0"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// fixture is an onboarded account whose vault lives on a fake IPFS node,
+// over an in-memory database shared by every backend it builds.
+type fixture struct {
+	t           *testing.T
+	ctx         context.Context
+	logger      *logger.Logger
+	dsn         string
+	keyringPath string
+	ipfs        *httptest.Server
+	submitted   []string
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	// Attachments are written under the working directory.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	ipfs := httptest.NewServer(&fakeIPFS{blocks: map[string][]byte{}})
+	t.Cleanup(ipfs.Close)
+
+	f := &fixture{
+		t:           t,
+		ctx:         context.Background(),
+		logger:      logger.New(logger.ERROR),
+		dsn:         "file:" + uuid.NewString() + "?mode=memory&cache=shared",
+		keyringPath: t.TempDir(),
+		ipfs:        ipfs,
+	}
+	f.onboard()
+	return f
+}
+
+// backend builds a ServiceBackend as a new dvault process would.
+func (f *fixture) backend() *cli_backend.ServiceBackend {
+	f.t.Helper()
+	db, err := driver.InitDatabase(f.dsn, *f.logger)
+	require.NoError(f.t, err)
+	// Holding a connection keeps the in-memory database alive.
+	sqlDB, err := db.DB.DB()
+	require.NoError(f.t, err)
+	sqlDB.SetMaxIdleConns(1)
+
+	tc := tracecore.NewTracecoreClient(f.ipfs.URL, "", f.ipfs.URL, f.ipfs.URL)
+	b := cli_backend.NewServiceBackend(f.ctx, f.logger, db, blockchain.NewIPFSClient(f.ipfs.URL), tc, f.keyringPath)
+	b.Vaults.SubmitCIDFunc = func(secretKey, cid string) (string, error) {
+		f.submitted = append(f.submitted, cid)
+		return "tx-" + cid, nil
+	}
+	return b
+}
+
+// onboard follows the onboarding use cases: the account and its keyring,
+// the identity user, configs on local IPFS, the subscription and the vault.
+func (f *fixture) onboard() {
+	t := f.t
+	b := f.backend()
+
+	createAccount := onboarding_usecase.NewCreateAccountUseCase(
+		blockchain.NewStellarService(f.logger),
+		b.OnboardingRepo,
+		onboarding_infrastructure_eventbus.NewMemoryBus(),
+		f.logger,
+		b.Vaults.KeyringService,
+		vault_infrastructure_crypto.NewKeyService(),
+	)
+	_, err := createAccount.Execute(onboarding_usecase.AccountCreationRequest{Email: email, Password: password})
+	require.NoError(t, err)
+	onboardUser, err := b.OnboardingRepo.FindByEmail(email)
+	require.NoError(t, err)
+
+	user, err := b.Identity.Registers(identity_ui.OnboardRequest{Email: email, Password: onboardUser.Password})
+	require.NoError(t, err)
+
+	subscription := &subscription_domain.Subscription{ID: uuid.NewString(), Email: email, UserID: uuid.NewString(), Tier: "pro", Active: true}
+	require.NoError(t, subscription_persistence.NewSubscriptionRepository(b.AppConfig.DB, f.logger).Save(f.ctx, subscription))
+
+	configs, err := app_config_domain.InitConfigFromVault(user.ID, "Personal")
+	require.NoError(t, err)
+	configs.App.Storage.Mode = app_config.StorageLocal
+	configs.App.Storage.LocalIPFS.APIEndpoint = f.ipfs.URL
+	configs.User.Email = email
+	configs.Subscription.UserID = subscription.UserID
+	configs.Subscription.BaseVaultConfig.UserID = subscription.UserID
+	configs.Onboarding.UserID = onboardUser.ID
+	saved, err := b.AppConfig.SaveConfigs(&app_config_dto.CreateConfigCommandInput{Configs: *configs})
+	require.NoError(t, err)
+
+	_, err = b.Vaults.CreateVaultCommandHandler.CreateVault(vault_commands.CreateVaultCommand{
+		UserID:             user.ID,
+		VaultName:          "Personal",
+		Password:           password,
+		UserSubscriptionID: subscription.UserID,
+		Configs:            saved.Configs,
+		UserOnboarding:     onboardUser,
+	})
+	require.NoError(t, err)
+}
+
+func (f *fixture) unlock() *cli_backend.ServiceBackend {
+	f.t.Helper()
+	b := f.backend()
+	_, err := b.Unlock(f.ctx, cli_domain.Credentials{Email: email, Password: password})
+	require.NoError(f.t, err)
+	return b
+}
+
+func login(name, username, pw string) *vaults_domain.LoginEntry {
+	e := &vaults_domain.LoginEntry{UserName: username, Password: pw}
+	e.EntryName = name
+	e.Type = "login"
+	return e
+}
+
+func TestServiceBackend_Unlock(t *testing.T) {
+	f := newFixture(t)
+	b := f.backend()
+
+	_, err := b.Vault(f.ctx)
+	require.ErrorIs(t, err, cli_domain.ErrVaultLocked)
+
+	_, err = b.Unlock(f.ctx, cli_domain.Credentials{Email: email, Password: "wrong"})
+	require.ErrorIs(t, err, cli_domain.ErrInvalidCredentials)
+
+	session, err := b.Unlock(f.ctx, cli_domain.Credentials{Email: email, Password: password})
+	require.NoError(t, err)
+	require.Equal(t, email, session.Email)
+	require.Equal(t, "Personal", session.VaultName)
+
+	vault, err := b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Empty(t, vault.Entries)
+
+	require.NoError(t, b.Lock(f.ctx))
+	_, err = b.Vault(f.ctx)
+	require.ErrorIs(t, err, cli_domain.ErrVaultLocked)
+}
+
+func TestServiceBackend_SyncPersistsEntries(t *testing.T) {
+	f := newFixture(t)
+	b := f.unlock()
+
+	entry := login("GitHub", "alice", "s3cret")
+	require.NoError(t, b.AddEntry(f.ctx, entry))
+	vault, err := b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Len(t, vault.Entries.Login, 1)
+
+	// Unsynced changes stay on this device.
+	require.Empty(t, f.onNewDevice().Entries.Login)
+
+	cid, err := b.Sync(f.ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{cid}, f.submitted)
+
+	vault = f.onNewDevice()
+	require.Len(t, vault.Entries.Login, 1)
+	require.Equal(t, "GitHub", vault.Entries.Login[0].EntryName)
+	require.Equal(t, "s3cret", vault.Entries.Login[0].Password)
+
+}
+
+func TestServiceBackend_UpdateAndTrash(t *testing.T) {
+	f := newFixture(t)
+	b := f.unlock()
+
+	entry := login("GitHub", "alice", "s3cret")
+	require.NoError(t, b.AddEntry(f.ctx, entry))
+	vault, err := b.Vault(f.ctx)
+	require.NoError(t, err)
+
+	saved := vault.Entries.Login[0]
+	saved.Password = "n3w"
+	require.NoError(t, b.UpdateEntry(f.ctx, &saved))
+	require.NoError(t, b.TrashEntry(f.ctx, &saved))
+
+	vault, err = b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Len(t, vault.Entries.Login, 1)
+	require.Equal(t, "n3w", vault.Entries.Login[0].Password)
+	require.True(t, vault.Entries.Login[0].Trashed)
+}
+
+func TestServiceBackend_LockKeepsTheSession(t *testing.T) {
+	f := newFixture(t)
+	b := f.unlock()
+	require.NoError(t, b.AddEntry(f.ctx, login("GitHub", "alice", "s3cret")))
+	require.NoError(t, b.Lock(f.ctx))
+
+	b = f.unlock()
+	vault, err := b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Len(t, vault.Entries.Login, 1)
+}
+
+// onNewDevice drops the saved sessions, so that the vault is rebuilt from
+// its last synced CID, and returns its content.
+func (f *fixture) onNewDevice() *vaults_domain.VaultPayload {
+	f.t.Helper()
+	b := f.backend()
+	require.NoError(f.t, b.AppConfig.DB.Where("1 = 1").Delete(&vaults_persistence.SessionMapper{}).Error)
+	b = f.unlock()
+	vault, err := b.Vault(f.ctx)
+	require.NoError(f.t, err)
+	return vault
+}
+
+func TestServiceBackend_Folders(t *testing.T) {
+	f := newFixture(t)
+	b := f.unlock()
+
+	folder, err := b.CreateFolder(f.ctx, "Work")
+	require.NoError(t, err)
+	require.NoError(t, b.RenameFolder(f.ctx, folder.ID, "Office"))
+
+	vault, err := b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Len(t, vault.Folders, 1)
+	require.Equal(t, "Office", vault.Folders[0].Name)
+
+	require.NoError(t, b.DeleteFolder(f.ctx, folder.ID))
+	vault, err = b.Vault(f.ctx)
+	require.NoError(t, err)
+	require.Empty(t, vault.Folders)
+}
+
+func TestServiceBackend_Attachments(t *testing.T) {
+	f := newFixture(t)
+	b := f.unlock()
+
+	entry := login("Certificates", "", "")
+	require.NoError(t, b.AddEntry(f.ctx, entry))
+	content := []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n")
+
+	att, err := b.PutAttachment(f.ctx, entry, "ca.pem", content)
+	require.NoError(t, err)
+	require.Equal(t, "ca.pem", att.Name)
+	require.EqualValues(t, len(content), att.Size)
+
+	got, err := b.GetAttachment(f.ctx, *att)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+}
+
+func TestServiceBackend_GitCLIFollowsTheSubscription(t *testing.T) {
+	f := newFixture(t)
+	b := f.backend()
+
+	_, err := b.GitCLIEnabled(f.ctx)
+	require.ErrorIs(t, err, cli_domain.ErrVaultLocked)
+
+	b = f.unlock()
+	enabled, err := b.GitCLIEnabled(f.ctx)
+	require.NoError(t, err)
+	require.True(t, enabled)
+}
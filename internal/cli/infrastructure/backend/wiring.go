@@ -0,0 +1,151 @@
+package cli_backend
+
+import (
+	"context"
+	"os"
+	"time"
+
+	auth_usecases "vault-app/internal/auth/application/use_cases"
+	auth_domain "vault-app/internal/auth/domain"
+	auth_persistence "vault-app/internal/auth/infrastructure/persistence"
+	"vault-app/internal/blockchain"
+	app_config_ui "vault-app/internal/config/ui"
+	"vault-app/internal/driver"
+	identity_ui "vault-app/internal/identity/ui"
+	"vault-app/internal/logger/logger"
+	"vault-app/internal/models"
+	onboarding_ui_wails "vault-app/internal/onboarding/ui/wails"
+	"vault-app/internal/registry"
+	subscription_persistence "vault-app/internal/subscription/infrastructure/persistence"
+	"vault-app/internal/tracecore"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_ui "vault-app/internal/vault/ui"
+)
+
+// NewServiceBackendFromEnv builds the backend from the same environment and
+// in the same order as NewApp, minus everything only the desktop UI uses.
+func NewServiceBackendFromEnv(ctx context.Context, appLogger *logger.Logger) (*ServiceBackend, error) {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "sqlite3.db"
+	}
+	cloudBackURL := os.Getenv("CLOUD_BACK_URL")
+	if cloudBackURL == "" {
+		cloudBackURL = "http://localhost:4001/api"
+	}
+
+	// -------------------------------------------------------------------------------------------------
+	// Infrastructure
+	// -------------------------------------------------------------------------------------------------
+	db, err := driver.InitDatabase(dsn, *appLogger)
+	if err != nil {
+		return nil, err
+	}
+	ipfs := blockchain.NewIPFSClient(os.Getenv("IPFS_CLIENT"))
+	tracecoreClient := tracecore.NewTracecoreClient(cloudBackURL, os.Getenv("TRACECORE_TOKEN"), os.Getenv("CLOUD_FRONT_URL"), cloudBackURL)
+
+	return NewServiceBackend(ctx, appLogger, db, ipfs, tracecoreClient, os.Getenv("KEYRING_PATH")), nil
+}
+
+// NewServiceBackend wires the application services over the given
+// database, IPFS client, cloud client and keyring directory.
+func NewServiceBackend(
+	ctx context.Context,
+	appLogger *logger.Logger,
+	db *models.DBModel,
+	ipfs blockchain.IPFSClientInterface,
+	tracecoreClient *tracecore.TracecoreClient,
+	keyringPath string,
+) *ServiceBackend {
+	// -------------------------------------------------------------------------------------------------
+	// Registry
+	// -------------------------------------------------------------------------------------------------
+	reg := registry.NewRegistry(appLogger)
+	reg.RegisterDefinitions([]registry.EntryDefinition{
+		{
+			Type:    "login",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.LoginEntry{} },
+			Handler: vault_ui.NewLoginHandler(*db, appLogger),
+		},
+		{
+			Type:    "card",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.CardEntry{} },
+			Handler: vault_ui.NewCardHandler(*db, appLogger),
+		},
+		{
+			Type:    "note",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.NoteEntry{} },
+			Handler: vault_ui.NewNoteHandler(*db, appLogger),
+		},
+		{
+			Type:    "identity",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.IdentityEntry{} },
+			Handler: vault_ui.NewIdentityHandler(*db, appLogger),
+		},
+		{
+			Type:    "sshkey",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.SSHKeyEntry{} },
+			Handler: vault_ui.NewSSHKeyHandler(*db, appLogger),
+		},
+		{
+			Type:    "otp",
+			Factory: func() vaults_domain.VaultEntry { return &vaults_domain.OTPEntry{} },
+			Handler: vault_ui.NewOTPHandler(*db, appLogger),
+		},
+	})
+
+	// -------------------------------------------------------------------------------------------------
+	// Vault & AppConfig
+	// -------------------------------------------------------------------------------------------------
+	stellarService := blockchain.NewStellarService(appLogger)
+	appConfigHandler := app_config_ui.NewAppConfigHandler(db.DB, *appLogger)
+	cryptoService := blockchain.CryptoService{}
+	vaultHandler := vault_ui.NewVaultHandler(
+		reg,
+		*appLogger,
+		ctx,
+		ipfs,
+		&cryptoService,
+		db.DB,
+		tracecoreClient,
+		keyringPath,
+	)
+	// SetVaultHandler keeps a copy; point at the live handler as App.GetAllConfigs does.
+	appConfigHandler.VaultHandler = vaultHandler
+
+	// -------------------------------------------------------------------------------------------------
+	// Subscription & Onboarding
+	// -------------------------------------------------------------------------------------------------
+	userSubscriptionRepo := subscription_persistence.NewUserSubscriptionRepository(db.DB, appLogger)
+	subscriptionSubRepo := subscription_persistence.NewSubscriptionRepository(db.DB, appLogger)
+	onBoardingHandler := onboarding_ui_wails.NewOnBoardingHandler(
+		stellarService,
+		userSubscriptionRepo,
+		subscriptionSubRepo,
+		tracecoreClient,
+		db.DB,
+		appLogger,
+		*vaultHandler.KeyringService,
+	)
+	appConfigHandler.SetOnboardingHandler(*onBoardingHandler)
+
+	// -------------------------------------------------------------------------------------------------
+	// Auth & Identity
+	// -------------------------------------------------------------------------------------------------
+	// Tokens are issued by Login but never used by the CLI; same settings as NewApp.
+	authV2 := auth_domain.Auth{
+		TokenExpiry:   time.Minute * 15,
+		RefreshExpiry: time.Hour * 24,
+	}
+	authRepository := auth_persistence.NewGormAuthRepository(db.DB)
+	authTokenService := auth_usecases.NewTokenService(authV2, authRepository, db.DB)
+	identityHandler := identity_ui.NewIdentityHandler(db.DB, authTokenService, onBoardingHandler.UserRepo)
+
+	return &ServiceBackend{
+		Vaults:         vaultHandler,
+		Identity:       identityHandler,
+		AppConfig:      appConfigHandler,
+		OnboardingRepo: onBoardingHandler.UserRepo,
+		Subscriptions:  subscriptionSubRepo,
+	}
+}
@@ -0,0 +1,426 @@
+package cli_ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	cli_usecases "vault-app/internal/cli/application/usecases"
+	cli_domain "vault-app/internal/cli/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// EntryView is the JSON shape of an entry.
+type EntryView struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	FolderID    string            `json:"folder_id,omitempty"`
+	Folder      string            `json:"folder,omitempty"`
+	Trashed     bool              `json:"trashed,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	Attachments []AttachmentView  `json:"attachments,omitempty"`
+}
+
+type AttachmentView struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Hash string `json:"hash"`
+}
+
+type FolderView struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func entryView(vp *vaults_domain.VaultPayload, e cli_domain.Entry, withFields bool) EntryView {
+	base := e.GetBase()
+	v := EntryView{ID: e.GetId(), Type: e.GetTypeName(), Name: e.GetName(), FolderID: base.FolderID, Trashed: base.Trashed}
+	if f, err := cli_domain.FindFolder(vp, base.FolderID); err == nil && base.FolderID != "" {
+		v.Folder = f.Name
+	}
+	if withFields {
+		v.Fields = cli_domain.Fields(e)
+		for _, a := range base.Attachments {
+			v.Attachments = append(v.Attachments, attachmentView(a))
+		}
+	}
+	return v
+}
+
+func attachmentView(a vaults_domain.Attachment) AttachmentView {
+	return AttachmentView{ID: a.ID, Name: a.Name, Size: a.Size, Hash: a.Hash}
+}
+
+func cmdUnlock(_ context.Context, r *runner, args []string) (int, error) {
+	if err := wantArgs(args, 0, "no arguments"); err != nil {
+		return 0, err
+	}
+	return 0, r.print(r.session, func(w io.Writer) {
+		fmt.Fprintf(w, "unlocked %q for %s\n", r.session.VaultName, r.session.Email)
+	})
+}
+
+func cmdList(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	typeName := fs.String("type", "", "")
+	folder := fs.String("folder", "", "")
+	trashed := fs.Bool("trashed", false, "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 0, "no positional arguments"); err != nil {
+		return 0, err
+	}
+	vp, err := r.backend.Vault(ctx)
+	if err != nil {
+		return 0, err
+	}
+	folderID := ""
+	if *folder != "" {
+		f, err := cli_domain.FindFolder(vp, *folder)
+		if err != nil {
+			return 0, err
+		}
+		folderID = f.ID
+	}
+	wantType := ""
+	if *typeName != "" {
+		probe, err := cli_domain.NewEntry(*typeName)
+		if err != nil {
+			return 0, err
+		}
+		wantType = probe.GetTypeName()
+	}
+
+	views := []EntryView{}
+	for _, e := range cli_domain.Entries(vp, *trashed) {
+		if wantType != "" && e.GetTypeName() != wantType {
+			continue
+		}
+		if folderID != "" && e.GetBase().FolderID != folderID {
+			continue
+		}
+		views = append(views, entryView(vp, e, false))
+	}
+	return 0, r.print(views, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTYPE\tNAME\tFOLDER")
+		for _, v := range views {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", v.ID, v.Type, v.Name, v.Folder)
+		}
+		tw.Flush()
+	})
+}
+
+func cmdGet(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	field := fs.String("field", "", "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 1, "get <entry>"); err != nil {
+		return 0, err
+	}
+	vp, err := r.backend.Vault(ctx)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := cli_domain.FindEntry(vp, rest[0])
+	if err != nil {
+		return 0, err
+	}
+	if *field != "" {
+		value, err := cli_domain.FieldValue(entry, *field)
+		if err != nil {
+			return 0, err
+		}
+		return 0, r.print(map[string]string{"value": value}, func(w io.Writer) {
+			fmt.Fprintln(w, value)
+		})
+	}
+	view := entryView(vp, entry, true)
+	return 0, r.print(view, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "id\t%s\ntype\t%s\n", view.ID, view.Type)
+		if view.Folder != "" {
+			fmt.Fprintf(tw, "folder\t%s\n", view.Folder)
+		}
+		for _, name := range cli_domain.FieldNames(view.Fields) {
+			fmt.Fprintf(tw, "%s\t%s\n", name, view.Fields[name])
+		}
+		for _, a := range view.Attachments {
+			fmt.Fprintf(tw, "attachment\t%s (%d bytes)\n", a.Name, a.Size)
+		}
+		tw.Flush()
+	})
+}
+
+func cmdCreate(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	name := fs.String("name", "", "")
+	folder := fs.String("folder", "", "")
+	var fields multiFlag
+	fs.Var(&fields, "field", "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 1, "create <type> --name NAME"); err != nil {
+		return 0, err
+	}
+	pairs, err := fieldPairs(fields)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := cli_usecases.NewEntriesUsecase(r.backend).Create(ctx, cli_usecases.CreateEntryRequest{
+		Type: rest[0], Name: *name, Folder: *folder, Fields: pairs,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.changed(ctx, entry, "created")
+}
+
+func cmdEdit(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	name := fs.String("name", "", "")
+	folder := fs.String("folder", "", "")
+	var fields multiFlag
+	fs.Var(&fields, "field", "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 1, "edit <entry>"); err != nil {
+		return 0, err
+	}
+	pairs, err := fieldPairs(fields)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := cli_usecases.NewEntriesUsecase(r.backend).Edit(ctx, cli_usecases.EditEntryRequest{
+		Entry: rest[0], Name: *name, Folder: *folder, Fields: pairs,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return r.changed(ctx, entry, "updated")
+}
+
+func cmdDelete(ctx context.Context, r *runner, args []string) (int, error) {
+	if err := wantArgs(args, 1, "delete <entry>"); err != nil {
+		return 0, err
+	}
+	entry, err := cli_usecases.NewEntriesUsecase(r.backend).Delete(ctx, args[0])
+	if err != nil {
+		return 0, err
+	}
+	return r.changed(ctx, entry, "trashed")
+}
+
+// changed syncs after an entry change and reports the entry.
+func (r *runner) changed(ctx context.Context, entry cli_domain.Entry, verb string) (int, error) {
+	if err := r.afterChange(ctx); err != nil {
+		return 0, err
+	}
+	vp, err := r.backend.Vault(ctx)
+	if err != nil {
+		return 0, err
+	}
+	view := entryView(vp, entry, false)
+	return 0, r.print(view, func(w io.Writer) {
+		fmt.Fprintf(w, "%s %s %q (%s)\n", verb, view.Type, view.Name, view.ID)
+	})
+}
+
+func fieldPairs(fields []string) ([][2]string, error) {
+	pairs := make([][2]string, 0, len(fields))
+	for _, kv := range fields {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%w: --field %q must be NAME=VALUE", cli_domain.ErrUsage, kv)
+		}
+		pairs = append(pairs, [2]string{k, v})
+	}
+	return pairs, nil
+}
+
+func cmdFolder(ctx context.Context, r *runner, args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("%w: folder list|create|rename|delete", cli_domain.ErrUsage)
+	}
+	vp, err := r.backend.Vault(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "list":
+		if err := wantArgs(args, 0, "folder list"); err != nil {
+			return 0, err
+		}
+		views := []FolderView{}
+		for _, f := range vp.Folders {
+			views = append(views, FolderView{ID: f.ID, Name: f.Name})
+		}
+		return 0, r.print(views, func(w io.Writer) {
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tNAME")
+			for _, v := range views {
+				fmt.Fprintf(tw, "%s\t%s\n", v.ID, v.Name)
+			}
+			tw.Flush()
+		})
+
+	case "create":
+		if err := wantArgs(args, 1, "folder create <name>"); err != nil {
+			return 0, err
+		}
+		f, err := r.backend.CreateFolder(ctx, args[0])
+		if err != nil {
+			return 0, err
+		}
+		return r.folderChanged(ctx, FolderView{ID: f.ID, Name: f.Name}, "created")
+
+	case "rename":
+		if err := wantArgs(args, 2, "folder rename <folder> <name>"); err != nil {
+			return 0, err
+		}
+		f, err := cli_domain.FindFolder(vp, args[0])
+		if err != nil {
+			return 0, err
+		}
+		if err := r.backend.RenameFolder(ctx, f.ID, args[1]); err != nil {
+			return 0, err
+		}
+		return r.folderChanged(ctx, FolderView{ID: f.ID, Name: args[1]}, "renamed")
+
+	case "delete":
+		if err := wantArgs(args, 1, "folder delete <folder>"); err != nil {
+			return 0, err
+		}
+		f, err := cli_domain.FindFolder(vp, args[0])
+		if err != nil {
+			return 0, err
+		}
+		if err := r.backend.DeleteFolder(ctx, f.ID); err != nil {
+			return 0, err
+		}
+		return r.folderChanged(ctx, FolderView{ID: f.ID, Name: f.Name}, "deleted")
+	}
+	return 0, fmt.Errorf("%w: unknown folder command %q", cli_domain.ErrUsage, sub)
+}
+
+func (r *runner) folderChanged(ctx context.Context, view FolderView, verb string) (int, error) {
+	if err := r.afterChange(ctx); err != nil {
+		return 0, err
+	}
+	return 0, r.print(view, func(w io.Writer) {
+		fmt.Fprintf(w, "%s folder %q (%s)\n", verb, view.Name, view.ID)
+	})
+}
+
+func cmdAttachment(ctx context.Context, r *runner, args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("%w: attachment list|get|put", cli_domain.ErrUsage)
+	}
+	sub := args[0]
+	fs := flag.NewFlagSet("attachment "+sub, flag.ContinueOnError)
+	out := fs.String("o", "", "")
+	name := fs.String("name", "", "")
+	rest, err := parseArgs(fs, args[1:])
+	if err != nil {
+		return 0, err
+	}
+	if len(rest) == 0 {
+		return 0, fmt.Errorf("%w: attachment %s <entry>", cli_domain.ErrUsage, sub)
+	}
+	vp, err := r.backend.Vault(ctx)
+	if err != nil {
+		return 0, err
+	}
+	entry, err := cli_domain.FindEntry(vp, rest[0])
+	if err != nil {
+		return 0, err
+	}
+
+	switch sub {
+	case "list":
+		if err := wantArgs(rest, 1, "attachment list <entry>"); err != nil {
+			return 0, err
+		}
+		views := []AttachmentView{}
+		for _, a := range entry.GetBase().Attachments {
+			views = append(views, attachmentView(a))
+		}
+		return 0, r.print(views, func(w io.Writer) {
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tNAME\tSIZE")
+			for _, v := range views {
+				fmt.Fprintf(tw, "%s\t%s\t%d\n", v.ID, v.Name, v.Size)
+			}
+			tw.Flush()
+		})
+
+	case "get":
+		if err := wantArgs(rest, 2, "attachment get <entry> <attachment>"); err != nil {
+			return 0, err
+		}
+		att, err := cli_domain.FindAttachment(entry, rest[1])
+		if err != nil {
+			return 0, err
+		}
+		data, err := r.backend.GetAttachment(ctx, *att)
+		if err != nil {
+			return 0, err
+		}
+		return 0, writeOutput(*out, data, r.io.Stdout)
+
+	case "put":
+		if err := wantArgs(rest, 2, "attachment put <entry> <file>"); err != nil {
+			return 0, err
+		}
+		data, err := os.ReadFile(rest[1])
+		if err != nil {
+			return 0, err
+		}
+		fileName := *name
+		if fileName == "" {
+			fileName = filepath.Base(rest[1])
+		}
+		att, err := r.backend.PutAttachment(ctx, entry, fileName, data)
+		if err != nil {
+			return 0, err
+		}
+		if err := r.afterChange(ctx); err != nil {
+			return 0, err
+		}
+		view := attachmentView(*att)
+		return 0, r.print(view, func(w io.Writer) {
+			fmt.Fprintf(w, "attached %q to %q (%s)\n", view.Name, entry.GetName(), view.ID)
+		})
+	}
+	return 0, fmt.Errorf("%w: unknown attachment command %q", cli_domain.ErrUsage, sub)
+}
+
+func cmdSync(ctx context.Context, r *runner, args []string) (int, error) {
+	if err := wantArgs(args, 0, "no arguments"); err != nil {
+		return 0, err
+	}
+	res, err := r.backend.Sync(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return 0, r.print(map[string]string{"result": res}, func(w io.Writer) {
+		fmt.Fprintf(w, "synced: %s\n", res)
+	})
+}
@@ -0,0 +1,118 @@
+package cli_ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	cli_domain "vault-app/internal/cli/domain"
+	git_credential_usecases "vault-app/internal/git_credential/application/usecases"
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_socket "vault-app/internal/git_credential/infrastructure/socket"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// cmdGitCredential serves git-credential-dvault from the unlocked session
+// until interrupted, for machines where the desktop app is not running.
+func cmdGitCredential(ctx context.Context, r *runner, args []string) (int, error) {
+	const use = "git-credential serve [--socket PATH]"
+	if len(args) == 0 || args[0] != "serve" {
+		return 0, fmt.Errorf("%w: %s", cli_domain.ErrUsage, use)
+	}
+	fs := flag.NewFlagSet("git-credential serve", flag.ContinueOnError)
+	socketPath := fs.String("socket", "", "")
+	rest, err := parseArgs(fs, args[1:])
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 0, use); err != nil {
+		return 0, err
+	}
+
+	gate, ok := r.backend.(cli_domain.FeatureGate)
+	if !ok {
+		return 0, git_credential_domain.ErrFeatureDisabled
+	}
+	featureGate := git_credential_domain.FeatureGateFunc(func(string) (bool, error) {
+		return gate.GitCLIEnabled(ctx)
+	})
+	enabled, err := featureGate.GitCLIEnabled(r.session.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if !enabled {
+		return 0, git_credential_domain.ErrFeatureDisabled
+	}
+	if *socketPath == "" {
+		if *socketPath, err = git_credential_socket.DefaultSocketPath(); err != nil {
+			return 0, err
+		}
+	}
+
+	vault := &sessionEntries{ctx: ctx, r: r}
+	server := git_credential_socket.NewServer(git_credential_usecases.NewCredentialHelper(r.session.UserID, vault, featureGate))
+	if err := server.Listen(*socketPath); err != nil {
+		return 0, err
+	}
+	defer server.Close()
+	if err := r.print(map[string]string{"socket_path": *socketPath}, func(w io.Writer) {
+		fmt.Fprintf(w, "git credential helper listening on %s\n", *socketPath)
+	}); err != nil {
+		return 0, err
+	}
+	<-ctx.Done()
+	return 0, nil
+}
+
+// sessionEntries adapts the CLI backend to the vault operations the helper
+// uses. The socket server runs one request at a time, so the backend is
+// never used concurrently.
+type sessionEntries struct {
+	ctx context.Context
+	r   *runner
+}
+
+func (s *sessionEntries) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	return s.r.backend.Vault(s.ctx)
+}
+
+func (s *sessionEntries) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	e, ok := entry.(cli_domain.Entry)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", cli_domain.ErrUnknownEntryType, entry)
+	}
+	if err := s.r.backend.AddEntry(s.ctx, e); err != nil {
+		return nil, err
+	}
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (s *sessionEntries) UpdateEntryFor(_ string, entry any, _ bool) (*vaults_domain.VaultEntry, error) {
+	e, ok := entry.(cli_domain.Entry)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", cli_domain.ErrUnknownEntryType, entry)
+	}
+	if err := s.r.backend.UpdateEntry(s.ctx, e); err != nil {
+		return nil, err
+	}
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (s *sessionEntries) TrashEntryFor(_ string, entry any) error {
+	e, ok := entry.(cli_domain.Entry)
+	if !ok {
+		return fmt.Errorf("%w: %T", cli_domain.ErrUnknownEntryType, entry)
+	}
+	return s.r.backend.TrashEntry(s.ctx, e)
+}
+
+// MarkDirty syncs right away unless --no-sync was given; a failed sync is
+// reported but leaves the change in the session for the next one.
+func (s *sessionEntries) MarkDirty(string) {
+	if err := s.r.afterChange(s.ctx); err != nil {
+		fmt.Fprintf(s.r.io.Stderr, "dvault: sync: %v\n", err)
+	}
+}
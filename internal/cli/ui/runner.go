@@ -0,0 +1,237 @@
+package cli_ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cli_domain "vault-app/internal/cli/domain"
+)
+
+const usage = `usage: dvault [global flags] <command> [args]
+
+Unlocks with DVAULT_EMAIL and DVAULT_PASSWORD, or DVAULT_STELLAR_SECRET.
+
+Global flags:
+  --json             print machine-readable JSON
+  --email EMAIL      account email (overrides DVAULT_EMAIL)
+  --password-stdin   read the password from the first line of stdin
+  --no-sync          do not sync after a change
+
+Commands:
+  unlock                                   check the credentials and show the vault
+  list [--type T] [--folder F] [--trashed] list entries
+  get <entry> [--field F]                  show an entry, or one of its fields
+  create <type> --name N [--folder F] [--field K=V]...
+  edit <entry> [--name N] [--folder F] [--field K=V]...
+  delete <entry>                           move an entry to the trash
+  folder list | create <name> | rename <folder> <name> | delete <folder>
+  attachment list <entry> | get <entry> <attachment> [-o FILE] | put <entry> <file> [--name N]
+  sync                                     push local changes
+  run [--env NAME=dvault://entry/field]... [--no-masking] -- <command> [args]
+  inject [-i TEMPLATE] [-o FILE]           replace {{ dvault://entry/field }} references
+  git-credential serve [--socket PATH]     answer git-credential-dvault until interrupted
+
+Entries and folders are named by id or by name.
+`
+
+// IO is the process environment a command runs in.
+type IO struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Env    []string
+}
+
+type runner struct {
+	io      IO
+	stdin   *bufio.Reader
+	backend cli_domain.Backend
+	session *cli_domain.Session
+	json    bool
+	noSync  bool
+}
+
+// Run executes one dvault command and returns the process exit code.
+func Run(ctx context.Context, args []string, streams IO, backend cli_domain.Backend) int {
+	if streams.Stdin == nil {
+		streams.Stdin = strings.NewReader("")
+	}
+	r := &runner{io: streams, stdin: bufio.NewReader(streams.Stdin), backend: backend}
+
+	global := flag.NewFlagSet("dvault", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	global.BoolVar(&r.json, "json", false, "")
+	email := global.String("email", "", "")
+	passwordStdin := global.Bool("password-stdin", false, "")
+	global.BoolVar(&r.noSync, "no-sync", false, "")
+	if err := global.Parse(args); err != nil {
+		return r.fail(fmt.Errorf("%w: %v", cli_domain.ErrUsage, err))
+	}
+	rest := global.Args()
+	if len(rest) == 0 || rest[0] == "help" || rest[0] == "-h" || rest[0] == "--help" {
+		fmt.Fprint(r.io.Stdout, usage)
+		return 0
+	}
+
+	cmd, ok := commands[rest[0]]
+	if !ok {
+		return r.fail(fmt.Errorf("%w: unknown command %q", cli_domain.ErrUsage, rest[0]))
+	}
+
+	creds, err := r.credentials(*email, *passwordStdin)
+	if err != nil {
+		return r.fail(err)
+	}
+	if r.session, err = backend.Unlock(ctx, creds); err != nil {
+		return r.fail(err)
+	}
+	defer backend.Lock(ctx)
+
+	code, err := cmd(ctx, r, rest[1:])
+	if err != nil {
+		return r.fail(err)
+	}
+	return code
+}
+
+type command func(ctx context.Context, r *runner, args []string) (int, error)
+
+var commands = map[string]command{
+	"unlock":         cmdUnlock,
+	"list":           cmdList,
+	"get":            cmdGet,
+	"create":         cmdCreate,
+	"edit":           cmdEdit,
+	"delete":         cmdDelete,
+	"folder":         cmdFolder,
+	"attachment":     cmdAttachment,
+	"sync":           cmdSync,
+	"run":            cmdRun,
+	"inject":         cmdInject,
+	"git-credential": cmdGitCredential,
+}
+
+func (r *runner) credentials(email string, passwordStdin bool) (cli_domain.Credentials, error) {
+	creds := cli_domain.Credentials{
+		Email:         r.getenv("DVAULT_EMAIL"),
+		Password:      r.getenv("DVAULT_PASSWORD"),
+		StellarSecret: r.getenv("DVAULT_STELLAR_SECRET"),
+	}
+	if email != "" {
+		creds.Email = email
+	}
+	if passwordStdin {
+		line, err := r.stdin.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return creds, err
+		}
+		creds.Password = strings.TrimRight(line, "\r\n")
+		creds.StellarSecret = ""
+	}
+	return creds, creds.Validate()
+}
+
+func (r *runner) getenv(name string) string {
+	for i := len(r.io.Env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(r.io.Env[i], "="); ok && k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+// childEnv is the environment handed to child processes, without the
+// variables dvault itself unlocks with.
+func (r *runner) childEnv() []string {
+	out := make([]string, 0, len(r.io.Env))
+	for _, kv := range r.io.Env {
+		switch k, _, _ := strings.Cut(kv, "="); k {
+		case "DVAULT_PASSWORD", "DVAULT_STELLAR_SECRET":
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out
+}
+
+// afterChange syncs unless --no-sync was given.
+func (r *runner) afterChange(ctx context.Context) error {
+	if r.noSync {
+		return nil
+	}
+	_, err := r.backend.Sync(ctx)
+	return err
+}
+
+// print writes v as JSON with --json, otherwise calls text.
+func (r *runner) print(v any, text func(w io.Writer)) error {
+	if r.json {
+		enc := json.NewEncoder(r.io.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	text(r.io.Stdout)
+	return nil
+}
+
+func (r *runner) fail(err error) int {
+	fmt.Fprintf(r.io.Stderr, "dvault: %v\n", err)
+	if errors.Is(err, cli_domain.ErrUsage) {
+		fmt.Fprintln(r.io.Stderr, "run 'dvault help' for usage")
+		return 2
+	}
+	return 1
+}
+
+// multiFlag collects a repeatable flag.
+type multiFlag []string
+
+func (m *multiFlag) String() string     { return strings.Join(*m, ",") }
+func (m *multiFlag) Set(v string) error { *m = append(*m, v); return nil }
+
+// parseArgs parses fs allowing flags after positional arguments, as in
+// "get github --field password". Everything after "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var tail []string
+	for i, a := range args {
+		if a == "--" {
+			args, tail = args[:i], args[i+1:]
+			break
+		}
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", cli_domain.ErrUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return append(positional, tail...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func wantArgs(args []string, n int, what string) error {
+	if len(args) != n {
+		return fmt.Errorf("%w: expected %s", cli_domain.ErrUsage, what)
+	}
+	return nil
+}
+
+func writeOutput(path string, data []byte, stdout io.Writer) error {
+	if path == "" || path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
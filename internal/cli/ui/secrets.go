@@ -0,0 +1,127 @@
+package cli_ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	cli_usecases "vault-app/internal/cli/application/usecases"
+	cli_domain "vault-app/internal/cli/domain"
+)
+
+// cmdRun starts a child process with secrets in its environment: variables
+// already holding dvault:// references, plus --env mappings. Unless
+// --no-masking is given, secrets echoed by the child are concealed.
+func cmdRun(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	var mappings multiFlag
+	fs.Var(&mappings, "env", "")
+	noMasking := fs.Bool("no-masking", false, "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(rest) == 0 {
+		return 0, fmt.Errorf("%w: run [--env NAME=REF]... -- <command> [args]", cli_domain.ErrUsage)
+	}
+
+	env, secrets, err := cli_usecases.NewSecretResolver(r.backend).ResolveEnv(ctx, r.childEnv(), mappings)
+	if err != nil {
+		return 0, err
+	}
+	// Nothing is written from here on; release the vault before the child runs.
+	if err := r.backend.Lock(ctx); err != nil {
+		return 0, err
+	}
+
+	cmd := exec.CommandContext(ctx, rest[0], rest[1:]...)
+	cmd.Env = env
+	cmd.Stdin = r.stdin
+	if *noMasking || len(secrets) == 0 {
+		cmd.Stdout, cmd.Stderr = r.io.Stdout, r.io.Stderr
+	} else {
+		stdout := &maskingWriter{w: r.io.Stdout, secrets: secrets}
+		stderr := &maskingWriter{w: r.io.Stderr, secrets: secrets}
+		defer stdout.Flush()
+		defer stderr.Flush()
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+	}
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code, nil
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// cmdInject renders a template, replacing {{ dvault://entry/field }} with
+// secrets. Rendering is all or nothing: a missing secret writes no output.
+func cmdInject(ctx context.Context, r *runner, args []string) (int, error) {
+	fs := flag.NewFlagSet("inject", flag.ContinueOnError)
+	in := fs.String("i", "-", "")
+	out := fs.String("o", "", "")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if err := wantArgs(rest, 0, "inject [-i TEMPLATE] [-o FILE]"); err != nil {
+		return 0, err
+	}
+	var tmpl []byte
+	if *in == "-" {
+		tmpl, err = io.ReadAll(r.stdin)
+	} else {
+		tmpl, err = os.ReadFile(*in)
+	}
+	if err != nil {
+		return 0, err
+	}
+	rendered, err := cli_usecases.NewSecretResolver(r.backend).Render(ctx, tmpl)
+	if err != nil {
+		return 0, err
+	}
+	return 0, writeOutput(*out, rendered, r.io.Stdout)
+}
+
+// maskingWriter conceals secrets line by line, so a secret is masked as
+// long as the child does not split it across lines.
+type maskingWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	secrets []string
+	buf     []byte
+}
+
+func (m *maskingWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buf = append(m.buf, p...)
+	if i := bytes.LastIndexByte(m.buf, '\n'); i >= 0 {
+		if _, err := io.WriteString(m.w, cli_usecases.Mask(string(m.buf[:i+1]), m.secrets)); err != nil {
+			return 0, err
+		}
+		m.buf = append(m.buf[:0], m.buf[i+1:]...)
+	}
+	return len(p), nil
+}
+
+func (m *maskingWriter) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.buf) > 0 {
+		io.WriteString(m.w, cli_usecases.Mask(string(m.buf), m.secrets))
+		m.buf = nil
+	}
+}
@@ -0,0 +1,359 @@
+package cli_tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cli_domain "vault-app/internal/cli/domain"
+	cli_ui "vault-app/internal/cli/ui"
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_socket "vault-app/internal/git_credential/infrastructure/socket"
+)
+
+const (
+	email    = "alice@example.com"
+	password = "correct horse battery staple"
+	secret   = "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// newVault creates a vault and returns a function running dvault against
+// it, each call with a fresh backend as a new process would.
+func newVault(t *testing.T) (*memoryVault, func(env []string, stdin string, args ...string) result) {
+	t.Helper()
+	vault := newMemoryVault(email, password, secret, "Personal")
+
+	run := func(env []string, stdin string, args ...string) result {
+		var stdout, stderr bytes.Buffer
+		code := cli_ui.Run(context.Background(), args, cli_ui.IO{
+			Stdin:  strings.NewReader(stdin),
+			Stdout: &stdout,
+			Stderr: &stderr,
+			Env:    env,
+		}, vault.backend())
+		return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+	}
+	return vault, run
+}
+
+var passwordEnv = []string{"PATH=" + os.Getenv("PATH"), "DVAULT_EMAIL=" + email, "DVAULT_PASSWORD=" + password}
+
+func decode[T any](t *testing.T, s string) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.Unmarshal([]byte(s), &v), s)
+	return v
+}
+
+func TestUnlock(t *testing.T) {
+	_, dvault := newVault(t)
+
+	res := dvault(passwordEnv, "", "--json", "unlock")
+	require.Equal(t, 0, res.code, res.stderr)
+	session := decode[map[string]string](t, res.stdout)
+	require.Equal(t, email, session["email"])
+	require.Equal(t, "Personal", session["vault_name"])
+
+	res = dvault([]string{"DVAULT_STELLAR_SECRET=" + secret}, "", "unlock")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	res = dvault([]string{"DVAULT_EMAIL=" + email}, password+"\n", "--password-stdin", "unlock")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	res = dvault([]string{"DVAULT_EMAIL=" + email, "DVAULT_PASSWORD=wrong"}, "", "unlock")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "invalid credentials")
+
+	res = dvault([]string{"DVAULT_STELLAR_SECRET=SOTHER"}, "", "unlock")
+	require.Equal(t, 1, res.code)
+
+	res = dvault(nil, "", "unlock")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "credentials required")
+}
+
+func TestEntryLifecycle(t *testing.T) {
+	_, dvault := newVault(t)
+
+	res := dvault(passwordEnv, "", "--json", "create", "login", "--name", "GitHub",
+		"--field", "username=alice", "--field", "password=s3cret", "--field", "url=https://github.com", "--field", "team=core")
+	require.Equal(t, 0, res.code, res.stderr)
+	created := decode[cli_ui.EntryView](t, res.stdout)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, "login", created.Type)
+
+	// Every invocation reopens the vault, so this also checks the auto-sync.
+	res = dvault(passwordEnv, "", "get", "github", "--field", "password")
+	require.Equal(t, 0, res.code, res.stderr)
+	require.Equal(t, "s3cret\n", res.stdout)
+
+	res = dvault(passwordEnv, "", "--json", "get", created.ID)
+	require.Equal(t, 0, res.code, res.stderr)
+	entry := decode[cli_ui.EntryView](t, res.stdout)
+	require.Equal(t, "alice", entry.Fields["user_name"])
+	require.Equal(t, "https://github.com", entry.Fields["web_site"])
+	require.Equal(t, "core", entry.Fields["team"])
+
+	res = dvault(passwordEnv, "", "edit", "GitHub", "--name", "GitHub Work", "--field", "password=n3w", "--field", "team=")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "get", "github work")
+	require.Equal(t, 0, res.code, res.stderr)
+	entry = decode[cli_ui.EntryView](t, res.stdout)
+	require.Equal(t, "n3w", entry.Fields["password"])
+	require.NotContains(t, entry.Fields, "team")
+
+	res = dvault(passwordEnv, "", "create", "note", "--name", "Recovery codes", "--field", "notes=1111 2222")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "list", "--type", "note")
+	require.Equal(t, 0, res.code, res.stderr)
+	notes := decode[[]cli_ui.EntryView](t, res.stdout)
+	require.Len(t, notes, 1)
+	require.Equal(t, "Recovery codes", notes[0].Name)
+
+	res = dvault(passwordEnv, "", "delete", "GitHub Work")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "list")
+	require.Len(t, decode[[]cli_ui.EntryView](t, res.stdout), 1)
+	res = dvault(passwordEnv, "", "--json", "list", "--trashed")
+	trashed := decode[[]cli_ui.EntryView](t, res.stdout)
+	require.Len(t, trashed, 1)
+	require.Equal(t, created.ID, trashed[0].ID)
+
+	res = dvault(passwordEnv, "", "get", "GitHub Work")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "entry not found")
+
+	res = dvault(passwordEnv, "", "create", "spaceship", "--name", "x")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "unknown entry type")
+}
+
+func TestNoSyncKeepsChangesOut(t *testing.T) {
+	_, dvault := newVault(t)
+
+	res := dvault(passwordEnv, "", "--no-sync", "create", "login", "--name", "Draft")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "list")
+	require.Empty(t, decode[[]cli_ui.EntryView](t, res.stdout))
+}
+
+func TestFolders(t *testing.T) {
+	_, dvault := newVault(t)
+
+	res := dvault(passwordEnv, "", "--json", "folder", "create", "Work")
+	require.Equal(t, 0, res.code, res.stderr)
+	folder := decode[cli_ui.FolderView](t, res.stdout)
+
+	res = dvault(passwordEnv, "", "create", "login", "--name", "Jira", "--folder", "work")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "create", "login", "--name", "Bank")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	res = dvault(passwordEnv, "", "folder", "rename", "Work", "Office")
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "folder", "list")
+	folders := decode[[]cli_ui.FolderView](t, res.stdout)
+	require.Equal(t, []cli_ui.FolderView{{ID: folder.ID, Name: "Office"}}, folders)
+
+	res = dvault(passwordEnv, "", "--json", "list", "--folder", "Office")
+	inFolder := decode[[]cli_ui.EntryView](t, res.stdout)
+	require.Len(t, inFolder, 1)
+	require.Equal(t, "Jira", inFolder[0].Name)
+	require.Equal(t, "Office", inFolder[0].Folder)
+
+	// Deleting a folder keeps its entries, unsorted.
+	res = dvault(passwordEnv, "", "folder", "delete", folder.ID)
+	require.Equal(t, 0, res.code, res.stderr)
+	res = dvault(passwordEnv, "", "--json", "get", "Jira")
+	require.Empty(t, decode[cli_ui.EntryView](t, res.stdout).FolderID)
+	res = dvault(passwordEnv, "", "folder", "delete", "Office")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "folder not found")
+}
+
+func TestAttachments(t *testing.T) {
+	_, dvault := newVault(t)
+	res := dvault(passwordEnv, "", "create", "note", "--name", "Certificates")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	content := []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n")
+	src := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(src, content, 0o600))
+
+	res = dvault(passwordEnv, "", "--json", "attachment", "put", "Certificates", src)
+	require.Equal(t, 0, res.code, res.stderr)
+	att := decode[cli_ui.AttachmentView](t, res.stdout)
+	require.Equal(t, "ca.pem", att.Name)
+	require.EqualValues(t, len(content), att.Size)
+
+	res = dvault(passwordEnv, "", "--json", "attachment", "list", "Certificates")
+	require.Len(t, decode[[]cli_ui.AttachmentView](t, res.stdout), 1)
+
+	res = dvault(passwordEnv, "", "attachment", "get", "Certificates", "ca.pem")
+	require.Equal(t, 0, res.code, res.stderr)
+	require.Equal(t, string(content), res.stdout)
+
+	out := filepath.Join(t.TempDir(), "out.pem")
+	res = dvault(passwordEnv, "", "attachment", "get", "Certificates", att.ID, "-o", out)
+	require.Equal(t, 0, res.code, res.stderr)
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+
+	res = dvault(passwordEnv, "", "attachment", "get", "Certificates", "missing.pem")
+	require.Equal(t, 1, res.code)
+}
+
+func TestRunInjectsSecrets(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	_, dvault := newVault(t)
+	res := dvault(passwordEnv, "", "create", "login", "--name", "Database", "--field", "username=app", "--field", "password=pg-pass-123")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	env := append([]string{"DB_USER=dvault://Database/username"}, passwordEnv...)
+	script := `printf '%s:%s:%s\n' "$DB_USER" "$DB_PASS" "${DVAULT_PASSWORD:-unset}"; printf 'err %s\n' "$DB_PASS" >&2; exit 3`
+
+	res = dvault(env, "", "run", "--env", "DB_PASS=dvault://Database/password", "--", "sh", "-c", script)
+	require.Equal(t, 3, res.code, res.stderr)
+	require.Equal(t, "<concealed by dvault>:<concealed by dvault>:unset\n", res.stdout)
+	require.Equal(t, "err <concealed by dvault>\n", res.stderr)
+
+	res = dvault(env, "", "run", "--no-masking", "--env", "DB_PASS=dvault://Database/password", "--", "sh", "-c", script)
+	require.Equal(t, 3, res.code)
+	require.Equal(t, "app:pg-pass-123:unset\n", res.stdout)
+
+	res = dvault(passwordEnv, "", "run", "--env", "X=dvault://Nope/password", "--", "sh", "-c", "echo ran")
+	require.Equal(t, 1, res.code)
+	require.NotContains(t, res.stdout, "ran")
+
+	res = dvault(passwordEnv, "", "run")
+	require.Equal(t, 2, res.code)
+}
+
+func TestInject(t *testing.T) {
+	_, dvault := newVault(t)
+	res := dvault(passwordEnv, "", "create", "login", "--name", "Database", "--field", "username=app", "--field", "password=pg-pass-123")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	tmplDir := t.TempDir()
+	tmpl := filepath.Join(tmplDir, "config.tmpl")
+	require.NoError(t, os.WriteFile(tmpl, []byte("user: {{ dvault://Database/username }}\npass: {{dvault://Database/password}}\n"), 0o600))
+
+	out := filepath.Join(tmplDir, "config.yml")
+	res = dvault(passwordEnv, "", "inject", "-i", tmpl, "-o", out)
+	require.Equal(t, 0, res.code, res.stderr)
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, "user: app\npass: pg-pass-123\n", string(got))
+	fi, err := os.Stat(out)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	res = dvault(passwordEnv, "pin: {{ dvault://Database/pin }}\n", "inject")
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, "field not found")
+	require.Empty(t, res.stdout)
+
+	missing := filepath.Join(tmplDir, "missing.yml")
+	require.NoError(t, os.WriteFile(tmpl, []byte("a: {{ dvault://Ghost/password }}\n"), 0o600))
+	res = dvault(passwordEnv, "", "inject", "-i", tmpl, "-o", missing)
+	require.Equal(t, 1, res.code)
+	require.NoFileExists(t, missing)
+}
+
+func TestUsageErrors(t *testing.T) {
+	_, dvault := newVault(t)
+
+	res := dvault(passwordEnv, "", "help")
+	require.Equal(t, 0, res.code)
+	require.Contains(t, res.stdout, "usage: dvault")
+
+	res = dvault(passwordEnv, "", "teleport")
+	require.Equal(t, 2, res.code)
+	require.Contains(t, res.stderr, `unknown command "teleport"`)
+
+	res = dvault(passwordEnv, "", "get")
+	require.Equal(t, 2, res.code)
+
+	res = dvault(passwordEnv, "", "create", "login", "--field", "nokey")
+	require.Equal(t, 2, res.code)
+}
+
+func TestGitCredentialServe(t *testing.T) {
+	vault, dvault := newVault(t)
+	res := dvault(passwordEnv, "", "create", "login", "--name", "Forge",
+		"--field", "username=alice", "--field", "password=forge-pw", "--field", "url=https://git.example.com")
+	require.Equal(t, 0, res.code, res.stderr)
+
+	sockDir, err := os.MkdirTemp("", "gc")
+	require.NoError(t, err)
+	defer os.RemoveAll(sockDir)
+	sock := filepath.Join(sockDir, "git-credential.sock")
+
+	serve := func(backend cli_domain.Backend) (context.CancelFunc, <-chan result) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan result, 1)
+		go func() {
+			var stdout, stderr bytes.Buffer
+			code := cli_ui.Run(ctx, []string{"git-credential", "serve", "--socket", sock}, cli_ui.IO{
+				Stdout: &stdout, Stderr: &stderr, Env: passwordEnv,
+			}, backend)
+			done <- result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+		}()
+		return cancel, done
+	}
+
+	cancel, done := serve(vault.backend())
+	res = <-done
+	cancel()
+	require.Equal(t, 1, res.code)
+	require.Contains(t, res.stderr, git_credential_domain.ErrFeatureDisabled.Error())
+
+	cancel, done = serve(gatedBackend{memoryBackend: vault.backend()})
+	res = <-done
+	cancel()
+	require.Equal(t, 1, res.code)
+
+	cancel, done = serve(gatedBackend{memoryBackend: vault.backend(), gitCLI: true})
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(git_credential_socket.TokenPath(sock))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ctx := context.Background()
+	got, err := git_credential_socket.Call(ctx, sock, git_credential_domain.OpGet,
+		git_credential_domain.Credential{Protocol: "https", Host: "git.example.com", Path: "acme/api.git"})
+	require.NoError(t, err)
+	require.Equal(t, "alice", got.Username)
+	require.Equal(t, "forge-pw", got.Password)
+
+	_, err = git_credential_socket.Call(ctx, sock, git_credential_domain.OpStore,
+		git_credential_domain.Credential{Protocol: "https", Host: "git.example.com", Path: "acme/api.git", Username: "ci", Password: "token-1"})
+	require.NoError(t, err)
+
+	cancel()
+	res = <-done
+	require.Equal(t, 0, res.code, res.stderr)
+	require.Contains(t, res.stdout, sock)
+
+	// The stored login was synced by the serving session.
+	res = dvault(passwordEnv, "", "get", "git.example.com/acme/api", "--field", "password")
+	require.Equal(t, 0, res.code, res.stderr)
+	require.Equal(t, "token-1\n", res.stdout)
+}
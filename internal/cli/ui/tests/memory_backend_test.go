@@ -0,0 +1,236 @@
+package cli_tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	cli_domain "vault-app/internal/cli/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// memoryVault is the synced state of one account, shared by the backends
+// of successive dvault invocations.
+type memoryVault struct {
+	email         string
+	password      string
+	stellarSecret string
+	name          string
+	payload       []byte
+	attachments   map[string][]byte
+	revision      int
+}
+
+func newMemoryVault(email, password, stellarSecret, name string) *memoryVault {
+	payload, err := json.Marshal(vaults_domain.InitEmptyVaultPayload(name, "1.0.0"))
+	if err != nil {
+		panic(err)
+	}
+	return &memoryVault{
+		email:         email,
+		password:      password,
+		stellarSecret: stellarSecret,
+		name:          name,
+		payload:       payload,
+		attachments:   map[string][]byte{},
+	}
+}
+
+// backend opens a new session on the vault, as a new process would.
+func (m *memoryVault) backend() *memoryBackend {
+	return &memoryBackend{vault: m}
+}
+
+// memoryBackend is a cli_domain.Backend keeping its session in memory;
+// only Sync writes it back to the vault.
+type memoryBackend struct {
+	vault *memoryVault
+	vp    *vaults_domain.VaultPayload
+}
+
+func (b *memoryBackend) Unlock(_ context.Context, creds cli_domain.Credentials) (*cli_domain.Session, error) {
+	if err := creds.Validate(); err != nil {
+		return nil, err
+	}
+	if creds.Stellar() {
+		if creds.StellarSecret != b.vault.stellarSecret {
+			return nil, cli_domain.ErrInvalidCredentials
+		}
+	} else if !strings.EqualFold(creds.Email, b.vault.email) || creds.Password != b.vault.password {
+		return nil, cli_domain.ErrInvalidCredentials
+	}
+	vp := &vaults_domain.VaultPayload{}
+	if err := json.Unmarshal(b.vault.payload, vp); err != nil {
+		return nil, err
+	}
+	vp.Name = b.vault.name
+	b.vp = vp
+	return &cli_domain.Session{UserID: "user-1", Email: b.vault.email, VaultName: b.vault.name}, nil
+}
+
+func (b *memoryBackend) Lock(context.Context) error {
+	b.vp = nil
+	return nil
+}
+
+// Vault returns a copy so that callers go through the backend to change it.
+func (b *memoryBackend) Vault(context.Context) (*vaults_domain.VaultPayload, error) {
+	if b.vp == nil {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	raw, err := json.Marshal(b.vp)
+	if err != nil {
+		return nil, err
+	}
+	vp := &vaults_domain.VaultPayload{}
+	if err := json.Unmarshal(raw, vp); err != nil {
+		return nil, err
+	}
+	vp.Name = b.vp.Name
+	return vp, nil
+}
+
+func (b *memoryBackend) AddEntry(_ context.Context, entry cli_domain.Entry) error {
+	if b.vp == nil {
+		return cli_domain.ErrVaultLocked
+	}
+	base := entry.GetBase()
+	if base.ID == "" {
+		base.ID = uuid.NewString()
+	}
+	if err := b.vp.AddEntry(entry.GetTypeName(), entry); err != nil {
+		return err
+	}
+	if _, err := b.find(base.ID); err != nil {
+		return fmt.Errorf("%w: %s", cli_domain.ErrUnknownEntryType, entry.GetTypeName())
+	}
+	return nil
+}
+
+func (b *memoryBackend) UpdateEntry(_ context.Context, entry cli_domain.Entry) error {
+	target, err := b.find(entry.GetId())
+	if err != nil {
+		return err
+	}
+	dst, src := reflect.ValueOf(target).Elem(), reflect.ValueOf(entry).Elem()
+	if dst.Type() != src.Type() {
+		return fmt.Errorf("entry %s changed type", entry.GetId())
+	}
+	dst.Set(src)
+	return nil
+}
+
+func (b *memoryBackend) TrashEntry(_ context.Context, entry cli_domain.Entry) error {
+	target, err := b.find(entry.GetId())
+	if err != nil {
+		return err
+	}
+	target.GetBase().Trashed = true
+	return nil
+}
+
+func (b *memoryBackend) CreateFolder(_ context.Context, name string) (*vaults_domain.Folder, error) {
+	if b.vp == nil {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	now := time.Now().Format(time.RFC3339)
+	folder := vaults_domain.Folder{ID: uuid.NewString(), Name: name, CreatedAt: now, UpdatedAt: now}
+	b.vp.Folders = append(b.vp.Folders, folder)
+	return &folder, nil
+}
+
+func (b *memoryBackend) RenameFolder(_ context.Context, id string, name string) error {
+	if b.vp == nil {
+		return cli_domain.ErrVaultLocked
+	}
+	for i := range b.vp.Folders {
+		if b.vp.Folders[i].ID == id {
+			b.vp.Folders[i].Name = name
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", cli_domain.ErrFolderNotFound, id)
+}
+
+func (b *memoryBackend) DeleteFolder(_ context.Context, id string) error {
+	if b.vp == nil {
+		return cli_domain.ErrVaultLocked
+	}
+	for i := range b.vp.Folders {
+		if b.vp.Folders[i].ID == id {
+			b.vp.MoveEntriesToUnsorted(id)
+			b.vp.Folders = append(b.vp.Folders[:i], b.vp.Folders[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", cli_domain.ErrFolderNotFound, id)
+}
+
+func (b *memoryBackend) GetAttachment(_ context.Context, att vaults_domain.Attachment) ([]byte, error) {
+	if b.vp == nil {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	data, ok := b.vault.attachments[att.Hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", cli_domain.ErrAttachmentNotFound, att.Name)
+	}
+	return data, nil
+}
+
+func (b *memoryBackend) PutAttachment(_ context.Context, entry cli_domain.Entry, name string, data []byte) (*vaults_domain.Attachment, error) {
+	target, err := b.find(entry.GetId())
+	if err != nil {
+		return nil, err
+	}
+	att := vaults_domain.Attachment{
+		ID:   uuid.NewString(),
+		Hash: uuid.NewString(),
+		Name: name,
+		Size: int64(len(data)),
+		Ext:  strings.TrimPrefix(filepath.Ext(name), "."),
+	}
+	b.vault.attachments[att.Hash] = append([]byte(nil), data...)
+	base := target.GetBase()
+	base.Attachments = append(base.Attachments, att)
+	return &att, nil
+}
+
+func (b *memoryBackend) Sync(context.Context) (string, error) {
+	if b.vp == nil {
+		return "", cli_domain.ErrVaultLocked
+	}
+	payload, err := json.Marshal(b.vp)
+	if err != nil {
+		return "", err
+	}
+	b.vault.payload = payload
+	b.vault.revision++
+	return fmt.Sprintf("memory:%d", b.vault.revision), nil
+}
+
+func (b *memoryBackend) find(id string) (cli_domain.Entry, error) {
+	if b.vp == nil {
+		return nil, cli_domain.ErrVaultLocked
+	}
+	for _, e := range b.vp.Entries.All() {
+		if entry, ok := e.(cli_domain.Entry); ok && entry.GetId() == id {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", cli_domain.ErrEntryNotFound, id)
+}
+
+// gatedBackend is a backend on an account with or without the Git CLI
+// feature; the plain backend knows no subscription.
+type gatedBackend struct {
+	*memoryBackend
+	gitCLI bool
+}
+
+func (b gatedBackend) GitCLIEnabled(context.Context) (bool, error) { return b.gitCLI, nil }
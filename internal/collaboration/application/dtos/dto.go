@@ -3,6 +3,7 @@ package collaboration_dtos
 import (
 	c3_asset_domain "vault-app/internal/c3_asset/domain"
 	trustgroup_dtos "vault-app/internal/trust_group/application/dtos"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
 )
 
 
@@ -44,7 +45,53 @@ type ResolveCollaborativeShareResponse struct {
 	CreatedAt    string            `json:"created_at"`
 	Metadata     map[string]string `json:"metadata"`
 	Plaintext    []byte            `json:"plaintext"`
+	// KEKVersion is the version the DEK is wrapped under after resolution.
+	KEKVersion uint64 `json:"kek_version"`
+	// Rewrapped is set when this read migrated the entry to the current KEK.
+	Rewrapped bool `json:"rewrapped,omitempty"`
 }
 
+type RemoveTrustGroupMemberRequest struct {
+	RequestID    string `json:"request_id,omitempty"` // Idempotency key
+	TrustGroupID string `json:"trust_group_id"`
+	MemberID     string `json:"member_id"`
+	ActorID      string `json:"actor_id"`
+	Reason       string `json:"reason,omitempty"`
+}
 
+type RemoveTrustGroupMemberResponse struct {
+	TrustGroup             trustgroup_domain.TrustGroup `json:"trust_group"`
+	KEKVersion             uint64                       `json:"kek_version"`
+	RevokedDeviceIDs       []string                     `json:"revoked_device_ids"`
+	ReWrappedShareEntryIDs []string                     `json:"rewrapped_share_entry_ids"`
+	PendingShareEntryIDs   []string                     `json:"pending_share_entry_ids"`
+}
 
+type ProvisionDeviceEnvelopesRequest struct {
+	// MemberID is the trust group member (vault) the device belongs to;
+	// ActorID is the user whose local keyring and seed open its envelopes.
+	MemberID         string `json:"member_id"`
+	ActorID          string `json:"actor_id"`
+	ApproverDeviceID string `json:"approver_device_id"`
+	DeviceID         string `json:"device_id"`
+	PublicKey        string `json:"public_key"`
+}
+
+type ProvisionDeviceEnvelopesResponse struct {
+	TrustGroupIDs []string `json:"trust_group_ids"`
+	// SkippedTrustGroupIDs are groups the approving device holds no current
+	// envelope for; another trusted device has to provision those.
+	SkippedTrustGroupIDs []string `json:"skipped_trust_group_ids"`
+}
+
+type RotateForRevokedDeviceRequest struct {
+	RequestID string `json:"request_id,omitempty"`
+	MemberID  string `json:"member_id"`
+	ActorID   string `json:"actor_id"`
+	DeviceID  string `json:"device_id"`
+}
+
+type RotateForRevokedDeviceResponse struct {
+	RotatedTrustGroupIDs []string `json:"rotated_trust_group_ids"`
+	FailedTrustGroupIDs  []string `json:"failed_trust_group_ids,omitempty"`
+}
@@ -0,0 +1,20 @@
+package collaboration_ports
+
+import (
+	"context"
+
+	c3_asset_domain "vault-app/internal/c3_asset/domain"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+// ShareEntryLister enumerates the share entries granted to a trust group so a
+// KEK rotation can find every DEK wrapped under the outgoing version.
+type ShareEntryLister interface {
+	ListShareEntriesByTrustGroup(ctx context.Context, trustGroupID string) ([]c3_asset_domain.ShareEntry, error)
+}
+
+// MemberTrustGroupLister finds every trust group a vault belongs to, which is
+// what a device enrollment or revocation fans out over.
+type MemberTrustGroupLister interface {
+	ListTrustGroupsForMember(ctx context.Context, memberID string) ([]trustgroup_domain.TrustGroup, error)
+}
@@ -0,0 +1,14 @@
+package collaboration_ports
+
+import (
+	"context"
+
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+// TrustGroupRotationPublisher is the slice of the trust group event bus a
+// member removal needs.
+type TrustGroupRotationPublisher interface {
+	PublishMemberRemovedFromTrustGroup(ctx context.Context, event trustgroup_domain.MemberRemovedFromTrustGroup) error
+	PublishTrustGroupKEKRotated(ctx context.Context, event trustgroup_domain.TrustGroupKEKRotated) error
+}
@@ -0,0 +1,131 @@
+package collaboration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
+	collaboration_usecases "vault-app/internal/collaboration/application/usecases"
+	trustgroup_ports "vault-app/internal/trust_group/application/ports"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+func (r *fakeTrustGroupRepo) ListTrustGroupsForMember(ctx context.Context, memberID string) ([]trustgroup_domain.TrustGroup, error) {
+	groups := []trustgroup_domain.TrustGroup{}
+	for _, tg := range r.groups {
+		if tg.IsMember(memberID) {
+			groups = append(groups, *tg)
+		}
+	}
+	return groups, nil
+}
+
+// TestDeviceTrust_EnrollThenRevokeOldDevice follows alice moving to a new
+// laptop: dev-alice approves dev-alice-2, then dev-alice is revoked.
+func TestDeviceTrust_EnrollThenRevokeOldDevice(t *testing.T) {
+	ctx := context.Background()
+	f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+	tgID := f.trustGroup.ID
+
+	kpNew, err := keypair.Random()
+	require.NoError(t, err)
+
+	provisionUC := collaboration_usecases.NewProvisionDeviceEnvelopesUseCase(f.tgRepo, f.tgRepo, f.identityResolver, f.orchestrator)
+	provisioned, err := provisionUC.Execute(ctx, collaboration_dtos.ProvisionDeviceEnvelopesRequest{
+		MemberID:         "alice",
+		ActorID:          "alice",
+		ApproverDeviceID: "dev-alice",
+		DeviceID:         "dev-alice-2",
+		PublicKey:        kpNew.Address(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{tgID}, provisioned.TrustGroupIDs)
+	assert.Empty(t, provisioned.SkippedTrustGroupIDs)
+
+	env, ok := f.tgRepo.groups[tgID].Envelope("alice", "dev-alice-2", 1)
+	require.True(t, ok)
+	assert.Nil(t, env.RevokedAt)
+
+	// Provisioning again is a no-op.
+	again, err := provisionUC.Execute(ctx, collaboration_dtos.ProvisionDeviceEnvelopesRequest{
+		MemberID:         "alice",
+		ActorID:          "alice",
+		ApproverDeviceID: "dev-alice",
+		DeviceID:         "dev-alice-2",
+		PublicKey:        kpNew.Address(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{tgID}, again.TrustGroupIDs)
+	assert.Len(t, f.tgRepo.groups[tgID].ActiveEnvelopes(), 3)
+
+	// dev-alice is still reported active by the resolver; the exclusion
+	// alone must keep it out of the new KEK.
+	f.deviceResolver.devices["dev-alice-2"] = &trustgroup_ports.DeviceSummary{ID: "dev-alice-2", VaultID: "alice", PublicKey: kpNew.Address(), IsActive: true}
+
+	rotateUC := collaboration_usecases.NewRotateForRevokedDeviceUseCase(
+		collaboration_usecases.NewRotateTrustGroupKEKUseCase(f.tgRepo, f.shareRepo),
+		f.tgRepo, f.shareRepo, f.deviceResolver, f.identityResolver, f.orchestrator, f.publisher,
+	)
+	rotated, err := rotateUC.Execute(ctx, collaboration_dtos.RotateForRevokedDeviceRequest{
+		RequestID: "revoke_dev-alice",
+		MemberID:  "alice",
+		ActorID:   "alice",
+		DeviceID:  "dev-alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{tgID}, rotated.RotatedTrustGroupIDs)
+	assert.Empty(t, rotated.FailedTrustGroupIDs)
+
+	stored := f.tgRepo.groups[tgID]
+	assert.Equal(t, uint64(2), stored.KEKVersion)
+	assert.True(t, stored.IsMember("alice"))
+	devices := []string{}
+	for _, env := range stored.ActiveEnvelopes() {
+		devices = append(devices, env.DeviceID)
+	}
+	assert.ElementsMatch(t, []string{"dev-alice-2", "dev-bob"}, devices)
+	assert.Equal(t, uint64(2), f.shareRepo.entries["se_minutes"].KEKVersion)
+
+	require.Len(t, f.publisher.rotated, 1)
+	assert.Equal(t, trustgroup_domain.KEKRotationDeviceRevoked, f.publisher.rotated[0].Reason)
+
+	// The new laptop reads the share on its own envelope.
+	f.identityResolver.seeds["alice"] = kpNew.Seed()
+	res, err := f.resolveUseCase.Execute(ctx, collaboration_dtos.ResolveCollaborativeShareRequest{
+		ShareEntryID: "se_minutes", CallerUserID: "alice", DeviceID: "dev-alice-2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, f.rawContent, res.Plaintext)
+
+	// A device with no live envelope triggers no rotation.
+	none, err := rotateUC.Execute(ctx, collaboration_dtos.RotateForRevokedDeviceRequest{
+		MemberID: "alice",
+		ActorID:  "alice",
+		DeviceID: "dev-alice",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, none.RotatedTrustGroupIDs)
+}
+
+func TestProvisionDeviceEnvelopes_SkipsGroupsApproverCannotOpen(t *testing.T) {
+	f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+
+	kpNew, err := keypair.Random()
+	require.NoError(t, err)
+
+	provisionUC := collaboration_usecases.NewProvisionDeviceEnvelopesUseCase(f.tgRepo, f.tgRepo, f.identityResolver, f.orchestrator)
+	resp, err := provisionUC.Execute(context.Background(), collaboration_dtos.ProvisionDeviceEnvelopesRequest{
+		MemberID:         "alice",
+		ActorID:          "alice",
+		ApproverDeviceID: "dev-unknown",
+		DeviceID:         "dev-alice-2",
+		PublicKey:        kpNew.Address(),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.TrustGroupIDs)
+	assert.Equal(t, []string{f.trustGroup.ID}, resp.SkippedTrustGroupIDs)
+}
@@ -0,0 +1,264 @@
+package collaboration_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	c3_asset_domain "vault-app/internal/c3_asset/domain"
+	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
+	collaboration_usecases "vault-app/internal/collaboration/application/usecases"
+	trustgroup_orchestrator "vault-app/internal/trust_group/application/orchestrator"
+	trustgroup_ports "vault-app/internal/trust_group/application/ports"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_infrastructure_crypto "vault-app/internal/vault/infrastructure/crypto"
+	vault_infrastructure_security "vault-app/internal/vault/infrastructure/security"
+)
+
+func (r *fakeShareEntryRepo) ListShareEntriesByTrustGroup(ctx context.Context, trustGroupID string) ([]c3_asset_domain.ShareEntry, error) {
+	entries := []c3_asset_domain.ShareEntry{}
+	for _, entry := range r.entries {
+		if entry.TrustGroupID == trustGroupID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+type recordingRotationPublisher struct {
+	removed []trustgroup_domain.MemberRemovedFromTrustGroup
+	rotated []trustgroup_domain.TrustGroupKEKRotated
+}
+
+func (p *recordingRotationPublisher) PublishMemberRemovedFromTrustGroup(_ context.Context, event trustgroup_domain.MemberRemovedFromTrustGroup) error {
+	p.removed = append(p.removed, event)
+	return nil
+}
+
+func (p *recordingRotationPublisher) PublishTrustGroupKEKRotated(_ context.Context, event trustgroup_domain.TrustGroupKEKRotated) error {
+	p.rotated = append(p.rotated, event)
+	return nil
+}
+
+type removalFixture struct {
+	tgRepo           *fakeTrustGroupRepo
+	shareRepo        *fakeShareEntryRepo
+	deviceResolver   *fakeDeviceResolver
+	identityResolver *mockIdentityResolver
+	publisher        *recordingRotationPublisher
+	orchestrator     *trustgroup_orchestrator.TrustGroupCryptoOrchestrator
+	useCase          *collaboration_usecases.RemoveTrustGroupMemberUseCase
+	resolveUseCase   *collaboration_usecases.ResolveCollaborativeShareUseCase
+	trustGroup       *trustgroup_domain.TrustGroup
+	rawContent       []byte
+}
+
+// setupRemovalFixture builds a group shared by alice (dev-alice) and bob
+// (dev-bob) with one share entry wrapped under KEK v1.
+func setupRemovalFixture(t *testing.T, policy trustgroup_domain.ShareRewrapPolicy) *removalFixture {
+	ctx := context.Background()
+	keyringSvc := vault_infrastructure_security.NewKeyringService(nil, nil, "/tmp/keyz", nil)
+	orchestrator := trustgroup_orchestrator.NewTrustGroupCryptoOrchestrator(
+		keyringSvc,
+		&vault_infrastructure_crypto.AESService{},
+		&vault_infrastructure_crypto.AsymmetricService{},
+	)
+
+	kpAlice, err := keypair.Random()
+	require.NoError(t, err)
+	kpBob, err := keypair.Random()
+	require.NoError(t, err)
+
+	tg := trustgroup_domain.NewTrustGroup("ch_rotation", "Legal", []string{"alice", "bob"})
+	require.NoError(t, tg.SetShareRewrapPolicy(policy))
+
+	aliceKeyring := &vaults_domain.VaultKeyring{UserID: "alice", VaultID: "alice"}
+	rawContent := []byte("board minutes, do not forward")
+	prepared, err := orchestrator.PrepareCollaborativeAsset(ctx, trustgroup_orchestrator.PrepareCollaborativeAssetPayload{
+		AssetID:      "asset-minutes",
+		TrustGroupID: tg.ID,
+		KEKVersion:   1,
+		RawPayload:   rawContent,
+		Keyring:      aliceKeyring,
+		ActiveDevices: []trustgroup_orchestrator.ActiveDevice{
+			{DeviceID: "dev-alice", MemberID: "alice", PublicKey: kpAlice.Address(), IsActive: true},
+			{DeviceID: "dev-bob", MemberID: "bob", PublicKey: kpBob.Address(), IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+	for _, env := range prepared.Envelopes {
+		require.NoError(t, tg.AddEnvelope(trustgroup_domain.TrustGroupKeyEnvelope{
+			MemberID:   env.MemberID,
+			DeviceID:   env.DeviceID,
+			KEKVersion: env.KEKVersion,
+			WrappedKEK: env.WrappedKEK,
+		}))
+	}
+
+	entry, err := c3_asset_domain.NewShareEntry("cid_minutes", tg.ID, base64.StdEncoding.EncodeToString(prepared.WrappedDEK), 1, "alice", nil)
+	require.NoError(t, err)
+	entry.ID = "se_minutes"
+
+	shareRepo := newFakeShareEntryRepo()
+	shareRepo.entries[entry.ID] = entry
+	tgRepo := newFakeTrustGroupRepo()
+	tgRepo.groups[tg.ID] = tg
+
+	deviceResolver := newFakeDeviceResolver()
+	deviceResolver.devices["dev-alice"] = &trustgroup_ports.DeviceSummary{ID: "dev-alice", VaultID: "alice", PublicKey: kpAlice.Address(), IsActive: true}
+	deviceResolver.devices["dev-bob"] = &trustgroup_ports.DeviceSummary{ID: "dev-bob", VaultID: "bob", PublicKey: kpBob.Address(), IsActive: true}
+
+	identityResolver := &mockIdentityResolver{
+		seeds:    map[string]string{"alice": kpAlice.Seed(), "bob": kpBob.Seed()},
+		keyrings: map[string]*vaults_domain.VaultKeyring{"alice": aliceKeyring},
+	}
+	publisher := &recordingRotationPublisher{}
+
+	rotateUC := collaboration_usecases.NewRotateTrustGroupKEKUseCase(tgRepo, shareRepo)
+	useCase := collaboration_usecases.NewRemoveTrustGroupMemberUseCase(rotateUC, tgRepo, shareRepo, deviceResolver, identityResolver, orchestrator, publisher)
+	resolveUC := collaboration_usecases.NewResolveCollaborativeShareUseCase(shareRepo, tgRepo, &mockAssetResolver{
+		assets: map[string][]byte{"cid_minutes": prepared.EncryptedData},
+	}, identityResolver, orchestrator)
+
+	return &removalFixture{
+		tgRepo:           tgRepo,
+		shareRepo:        shareRepo,
+		deviceResolver:   deviceResolver,
+		identityResolver: identityResolver,
+		publisher:        publisher,
+		orchestrator:     orchestrator,
+		useCase:          useCase,
+		resolveUseCase:   resolveUC,
+		trustGroup:       tg,
+		rawContent:       rawContent,
+	}
+}
+
+func removeBob() collaboration_dtos.RemoveTrustGroupMemberRequest {
+	return collaboration_dtos.RemoveTrustGroupMemberRequest{
+		RequestID: "req-remove-bob",
+		MemberID:  "bob",
+		ActorID:   "alice",
+	}
+}
+
+func TestRemoveTrustGroupMember_EagerRotatesAndRewraps(t *testing.T) {
+	f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+	req := removeBob()
+	req.TrustGroupID = f.trustGroup.ID
+
+	resp, err := f.useCase.Execute(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), resp.KEKVersion)
+	assert.Equal(t, []string{"dev-bob"}, resp.RevokedDeviceIDs)
+	assert.Equal(t, []string{"se_minutes"}, resp.ReWrappedShareEntryIDs)
+	assert.Empty(t, resp.PendingShareEntryIDs)
+
+	stored := f.tgRepo.groups[f.trustGroup.ID]
+	assert.False(t, stored.IsMember("bob"))
+	active := stored.ActiveEnvelopes()
+	require.Len(t, active, 1)
+	assert.Equal(t, "dev-alice", active[0].DeviceID)
+	assert.Equal(t, uint64(2), f.shareRepo.entries["se_minutes"].KEKVersion)
+
+	require.Len(t, f.publisher.removed, 1)
+	assert.Equal(t, "bob", f.publisher.removed[0].MemberID)
+	assert.Equal(t, []string{"dev-bob"}, f.publisher.removed[0].RevokedDeviceIDs)
+	require.Len(t, f.publisher.rotated, 1)
+	assert.Equal(t, uint64(1), f.publisher.rotated[0].OldVersion)
+	assert.Equal(t, uint64(2), f.publisher.rotated[0].NewVersion)
+	assert.Equal(t, trustgroup_domain.KEKRotationMemberRemoved, f.publisher.rotated[0].Reason)
+
+	// Alice reads through her v2 envelope alone (empty keyring).
+	f.identityResolver.keyrings["alice"] = &vaults_domain.VaultKeyring{UserID: "alice", VaultID: "alice"}
+	res, err := f.resolveUseCase.Execute(context.Background(), collaboration_dtos.ResolveCollaborativeShareRequest{
+		ShareEntryID: "se_minutes", CallerUserID: "alice", DeviceID: "dev-alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, f.rawContent, res.Plaintext)
+	assert.False(t, res.Rewrapped)
+
+	_, err = f.resolveUseCase.Execute(context.Background(), collaboration_dtos.ResolveCollaborativeShareRequest{
+		ShareEntryID: "se_minutes", CallerUserID: "bob", DeviceID: "dev-bob",
+	})
+	assert.ErrorIs(t, err, collaboration_usecases.ErrUnauthorizedMember)
+}
+
+func TestRemoveTrustGroupMember_LazyRewrapsOnRead(t *testing.T) {
+	f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapLazy)
+	req := removeBob()
+	req.TrustGroupID = f.trustGroup.ID
+
+	resp, err := f.useCase.Execute(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, resp.ReWrappedShareEntryIDs)
+	assert.Equal(t, []string{"se_minutes"}, resp.PendingShareEntryIDs)
+	assert.Equal(t, uint64(1), f.shareRepo.entries["se_minutes"].KEKVersion)
+
+	// A fresh keyring forces both KEKs to come from alice's envelopes.
+	f.identityResolver.keyrings["alice"] = &vaults_domain.VaultKeyring{UserID: "alice", VaultID: "alice"}
+	res, err := f.resolveUseCase.Execute(context.Background(), collaboration_dtos.ResolveCollaborativeShareRequest{
+		ShareEntryID: "se_minutes", CallerUserID: "alice", DeviceID: "dev-alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, f.rawContent, res.Plaintext)
+	assert.True(t, res.Rewrapped)
+	assert.Equal(t, uint64(2), res.KEKVersion)
+	assert.Equal(t, uint64(2), f.shareRepo.entries["se_minutes"].KEKVersion)
+
+	// The migrated entry now reads without a re-wrap.
+	res, err = f.resolveUseCase.Execute(context.Background(), collaboration_dtos.ResolveCollaborativeShareRequest{
+		ShareEntryID: "se_minutes", CallerUserID: "alice", DeviceID: "dev-alice",
+	})
+	require.NoError(t, err)
+	assert.False(t, res.Rewrapped)
+	assert.Equal(t, f.rawContent, res.Plaintext)
+}
+
+func TestRemoveTrustGroupMember_IsIdempotent(t *testing.T) {
+	f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+	req := removeBob()
+	req.TrustGroupID = f.trustGroup.ID
+
+	first, err := f.useCase.Execute(context.Background(), req)
+	require.NoError(t, err)
+	second, err := f.useCase.Execute(context.Background(), req)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Len(t, f.publisher.rotated, 1)
+	assert.Equal(t, uint64(2), f.tgRepo.groups[f.trustGroup.ID].KEKVersion)
+}
+
+func TestRemoveTrustGroupMember_Rejections(t *testing.T) {
+	t.Run("self removal", func(t *testing.T) {
+		f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+		_, err := f.useCase.Execute(context.Background(), collaboration_dtos.RemoveTrustGroupMemberRequest{
+			TrustGroupID: f.trustGroup.ID, MemberID: "alice", ActorID: "alice",
+		})
+		assert.ErrorIs(t, err, trustgroup_domain.ErrSelfRemovalRotation)
+	})
+
+	t.Run("actor outside the group", func(t *testing.T) {
+		f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+		_, err := f.useCase.Execute(context.Background(), collaboration_dtos.RemoveTrustGroupMemberRequest{
+			TrustGroupID: f.trustGroup.ID, MemberID: "bob", ActorID: "mallory",
+		})
+		assert.ErrorIs(t, err, collaboration_usecases.ErrUnauthorizedMember)
+	})
+
+	t.Run("no remaining active device", func(t *testing.T) {
+		f := setupRemovalFixture(t, trustgroup_domain.ShareRewrapEager)
+		f.deviceResolver.devices["dev-alice"].IsActive = false
+		_, err := f.useCase.Execute(context.Background(), collaboration_dtos.RemoveTrustGroupMemberRequest{
+			TrustGroupID: f.trustGroup.ID, MemberID: "bob", ActorID: "alice",
+		})
+		assert.ErrorIs(t, err, trustgroup_domain.ErrNoActiveDevices)
+		assert.True(t, f.tgRepo.groups[f.trustGroup.ID].IsMember("bob"), "a failed removal leaves the group as it was")
+		assert.Empty(t, f.publisher.removed)
+	})
+}
@@ -7,13 +7,28 @@ import (
 	"strings"
 
 	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
+	thread_domain "vault-app/internal/thread/domain"
 	trustgroup_dtos "vault-app/internal/trust_group/application/dtos"
 	trustgroup_usecases "vault-app/internal/trust_group/application/usecases/envelope"
 )
 
+// ThreadAppendGate authorizes the thread event a share will be recorded as,
+// so a share the channel policy forbids is never created.
+type ThreadAppendGate interface {
+	Authorize(ctx context.Context, actorVaultID string, threadID string, eventType string, payload thread_domain.EventResourceRef) error
+}
+
 type CreateCollaborativeShareUseCase struct {
 	shareAssetUseCase  *ShareAssetWithTrustGroupUsecase
 	addEnvelopeUseCase *trustgroup_usecases.AddTrustGroupKeyEnvelopeUseCase
+	threadGate         ThreadAppendGate
+}
+
+// WithThreadGate makes shares bound to a thread (Metadata["thread_id"])
+// consult the thread's channel policy first.
+func (u *CreateCollaborativeShareUseCase) WithThreadGate(gate ThreadAppendGate) *CreateCollaborativeShareUseCase {
+	u.threadGate = gate
+	return u
 }
 
 func NewCreateCollaborativeShareUseCase(
@@ -63,6 +78,16 @@ func (u *CreateCollaborativeShareUseCase) Execute(
 		return nil, err
 	}
 
+	if threadID := req.Metadata["thread_id"]; u.threadGate != nil && threadID != "" {
+		err := u.threadGate.Authorize(ctx, req.CreatedBy, threadID, string(thread_domain.EventEntryShared), thread_domain.EventResourceRef{
+			RefType:      thread_domain.ResourceShareEntry,
+			TrustGroupID: req.TrustGroupID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// 1. Create and persist ShareEntry via ShareAssetWithTrustGroupUsecase
 	shareEntry, err := u.shareAssetUseCase.Execute(ctx, collaboration_dtos.ShareAssetWithTrustGroupRequest{
 		AssetCID:     req.AssetCID,
@@ -0,0 +1,248 @@
+package collaboration_usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
+	collaboration_ports "vault-app/internal/collaboration/application/ports"
+	trustgroup_orchestrator "vault-app/internal/trust_group/application/orchestrator"
+	trustgroup_ports "vault-app/internal/trust_group/application/ports"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+// ProvisionDeviceEnvelopesUseCase runs on the approving device right after a
+// new device of the same vault is approved: for every trust group the vault
+// belongs to, the current KEK is sealed to the new device's public key.
+type ProvisionDeviceEnvelopesUseCase struct {
+	trustGroupRepo   trustgroup_domain.TrustGroupRepository
+	groupLister      collaboration_ports.MemberTrustGroupLister
+	identityResolver collaboration_ports.SovereignIdentityResolver
+	orchestrator     *trustgroup_orchestrator.TrustGroupCryptoOrchestrator
+}
+
+func NewProvisionDeviceEnvelopesUseCase(
+	trustGroupRepo trustgroup_domain.TrustGroupRepository,
+	groupLister collaboration_ports.MemberTrustGroupLister,
+	identityResolver collaboration_ports.SovereignIdentityResolver,
+	orchestrator *trustgroup_orchestrator.TrustGroupCryptoOrchestrator,
+) *ProvisionDeviceEnvelopesUseCase {
+	return &ProvisionDeviceEnvelopesUseCase{
+		trustGroupRepo:   trustGroupRepo,
+		groupLister:      groupLister,
+		identityResolver: identityResolver,
+		orchestrator:     orchestrator,
+	}
+}
+
+func (u *ProvisionDeviceEnvelopesUseCase) ValidateDependencies() error {
+	if u.trustGroupRepo == nil {
+		return trustgroup_domain.ErrRepositoryNil
+	}
+	if u.groupLister == nil {
+		return errors.New("member trust group lister is required")
+	}
+	if u.identityResolver == nil {
+		return errors.New("sovereign identity resolver is required")
+	}
+	if u.orchestrator == nil {
+		return errors.New("crypto orchestrator is required")
+	}
+	return nil
+}
+
+func (u *ProvisionDeviceEnvelopesUseCase) ValidateRequest(req collaboration_dtos.ProvisionDeviceEnvelopesRequest) error {
+	if strings.TrimSpace(req.MemberID) == "" {
+		return errors.New("member id is required")
+	}
+	if strings.TrimSpace(req.ActorID) == "" {
+		return errors.New("actor id is required")
+	}
+	if strings.TrimSpace(req.ApproverDeviceID) == "" {
+		return errors.New("approver device id is required")
+	}
+	if strings.TrimSpace(req.DeviceID) == "" {
+		return errors.New("device id is required")
+	}
+	if strings.TrimSpace(req.PublicKey) == "" {
+		return errors.New("device public key is required")
+	}
+	return nil
+}
+
+func (u *ProvisionDeviceEnvelopesUseCase) Execute(
+	ctx context.Context,
+	req collaboration_dtos.ProvisionDeviceEnvelopesRequest,
+) (*collaboration_dtos.ProvisionDeviceEnvelopesResponse, error) {
+	if err := u.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if err := u.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	groups, err := u.groupLister.ListTrustGroupsForMember(ctx, req.MemberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trust groups for member: %w", err)
+	}
+	seed, err := u.identityResolver.GetDeviceSeed(ctx, req.ActorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local device seed for user %s: %w", req.ActorID, err)
+	}
+	keyring, err := u.identityResolver.GetVaultKeyring(ctx, req.ActorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local keyring for user %s: %w", req.ActorID, err)
+	}
+
+	resp := &collaboration_dtos.ProvisionDeviceEnvelopesResponse{
+		TrustGroupIDs:        []string{},
+		SkippedTrustGroupIDs: []string{},
+	}
+	for _, tg := range groups {
+		if !tg.IsMember(req.MemberID) {
+			continue
+		}
+		if env, ok := tg.Envelope(req.MemberID, req.DeviceID, tg.KEKVersion); ok && env.RevokedAt == nil {
+			resp.TrustGroupIDs = append(resp.TrustGroupIDs, tg.ID)
+			continue
+		}
+		approverEnv, ok := tg.Envelope(req.MemberID, req.ApproverDeviceID, tg.KEKVersion)
+		if !ok || approverEnv.RevokedAt != nil {
+			resp.SkippedTrustGroupIDs = append(resp.SkippedTrustGroupIDs, tg.ID)
+			continue
+		}
+
+		envReq, err := u.orchestrator.WrapKEKForDevice(ctx, trustgroup_orchestrator.WrapKEKForDevicePayload{
+			TrustGroupID: tg.ID,
+			KEKVersion:   tg.KEKVersion,
+			WrappedKEK:   approverEnv.WrappedKEK,
+			DeviceSeed:   seed,
+			Keyring:      keyring,
+			Device: trustgroup_orchestrator.ActiveDevice{
+				DeviceID:  req.DeviceID,
+				MemberID:  req.MemberID,
+				PublicKey: req.PublicKey,
+				IsActive:  true,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap KEK for trust group %s: %w", tg.ID, err)
+		}
+
+		if err := tg.AddEnvelope(trustgroup_domain.TrustGroupKeyEnvelope{
+			MemberID:   envReq.MemberID,
+			DeviceID:   envReq.DeviceID,
+			KEKVersion: envReq.KEKVersion,
+			WrappedKEK: envReq.WrappedKEK,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := u.trustGroupRepo.UpdateTrustGroup(ctx, &trustgroup_domain.UpdateTrustGroupRequest{
+			TrustGroup: tg,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to persist envelope for trust group %s: %w", tg.ID, err)
+		}
+		resp.TrustGroupIDs = append(resp.TrustGroupIDs, tg.ID)
+	}
+
+	return resp, nil
+}
+
+// RotateForRevokedDeviceUseCase rotates the KEK of every trust group in which
+// a just-revoked device still held a live envelope. Each group rotates in its
+// own transaction; a failure in one does not roll back the others, and the
+// failed groups are reported so the rotation can be retried.
+type RotateForRevokedDeviceUseCase struct {
+	groupLister collaboration_ports.MemberTrustGroupLister
+	rotation    kekRotation
+	publisher   collaboration_ports.TrustGroupRotationPublisher
+}
+
+func NewRotateForRevokedDeviceUseCase(
+	rotateUseCase *RotateTrustGroupKEKUseCase,
+	groupLister collaboration_ports.MemberTrustGroupLister,
+	shareLister collaboration_ports.ShareEntryLister,
+	deviceResolver trustgroup_ports.DeviceResolver,
+	identityResolver collaboration_ports.SovereignIdentityResolver,
+	orchestrator *trustgroup_orchestrator.TrustGroupCryptoOrchestrator,
+	publisher collaboration_ports.TrustGroupRotationPublisher,
+) *RotateForRevokedDeviceUseCase {
+	return &RotateForRevokedDeviceUseCase{
+		groupLister: groupLister,
+		rotation: kekRotation{
+			rotateUseCase:    rotateUseCase,
+			shareLister:      shareLister,
+			deviceResolver:   deviceResolver,
+			identityResolver: identityResolver,
+			orchestrator:     orchestrator,
+		},
+		publisher: publisher,
+	}
+}
+
+func (u *RotateForRevokedDeviceUseCase) ValidateDependencies() error {
+	if u.groupLister == nil {
+		return errors.New("member trust group lister is required")
+	}
+	return u.rotation.validate()
+}
+
+func (u *RotateForRevokedDeviceUseCase) Execute(
+	ctx context.Context,
+	req collaboration_dtos.RotateForRevokedDeviceRequest,
+) (*collaboration_dtos.RotateForRevokedDeviceResponse, error) {
+	if err := u.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.MemberID) == "" {
+		return nil, errors.New("member id is required")
+	}
+	if strings.TrimSpace(req.ActorID) == "" {
+		return nil, errors.New("actor id is required")
+	}
+	if strings.TrimSpace(req.DeviceID) == "" {
+		return nil, errors.New("device id is required")
+	}
+
+	groups, err := u.groupLister.ListTrustGroupsForMember(ctx, req.MemberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trust groups for member: %w", err)
+	}
+
+	resp := &collaboration_dtos.RotateForRevokedDeviceResponse{RotatedTrustGroupIDs: []string{}}
+	var errs []error
+	for _, tg := range groups {
+		if !holdsLiveEnvelope(tg, req.MemberID, req.DeviceID) {
+			continue
+		}
+		requestID := ""
+		if req.RequestID != "" {
+			requestID = req.RequestID + ":" + tg.ID
+		}
+		outcome, err := u.rotation.rotate(ctx, tg, kekRotationRequest{
+			RequestID:        requestID,
+			ActorID:          req.ActorID,
+			ExcludedDeviceID: req.DeviceID,
+		})
+		if err != nil {
+			resp.FailedTrustGroupIDs = append(resp.FailedTrustGroupIDs, tg.ID)
+			errs = append(errs, fmt.Errorf("trust group %s: %w", tg.ID, err))
+			continue
+		}
+		publishKEKRotated(ctx, u.publisher, tg, outcome, trustgroup_domain.KEKRotationDeviceRevoked, req.ActorID)
+		resp.RotatedTrustGroupIDs = append(resp.RotatedTrustGroupIDs, tg.ID)
+	}
+
+	return resp, errors.Join(errs...)
+}
+
+func holdsLiveEnvelope(tg trustgroup_domain.TrustGroup, memberID string, deviceID string) bool {
+	for _, env := range tg.ActiveEnvelopes() {
+		if env.MemberID == memberID && env.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
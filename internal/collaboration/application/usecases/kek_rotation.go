@@ -0,0 +1,237 @@
+package collaboration_usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	c3_asset_domain "vault-app/internal/c3_asset/domain"
+	collaboration_ports "vault-app/internal/collaboration/application/ports"
+	trustgroup_orchestrator "vault-app/internal/trust_group/application/orchestrator"
+	trustgroup_ports "vault-app/internal/trust_group/application/ports"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+// kekRotation is the rotation shared by member removal and device
+// revocation: wrap a fresh KEK for every device that should keep access,
+// re-wrap share DEKs per the group's policy, and persist it all through
+// RotateTrustGroupKEKUseCase.
+type kekRotation struct {
+	rotateUseCase    *RotateTrustGroupKEKUseCase
+	shareLister      collaboration_ports.ShareEntryLister
+	deviceResolver   trustgroup_ports.DeviceResolver
+	identityResolver collaboration_ports.SovereignIdentityResolver
+	orchestrator     *trustgroup_orchestrator.TrustGroupCryptoOrchestrator
+}
+
+type kekRotationOutcome struct {
+	TrustGroup trustgroup_domain.TrustGroup
+	OldVersion uint64
+	NewVersion uint64
+	Policy     trustgroup_domain.ShareRewrapPolicy
+	ReWrapped  []string
+	Pending    []string
+}
+
+func (r *kekRotation) validate() error {
+	if r.rotateUseCase == nil {
+		return errors.New("rotate KEK use case is required")
+	}
+	if r.shareLister == nil {
+		return errors.New("share entry lister is required")
+	}
+	if r.deviceResolver == nil {
+		return errors.New("device resolver is required")
+	}
+	if r.identityResolver == nil {
+		return errors.New("sovereign identity resolver is required")
+	}
+	if r.orchestrator == nil {
+		return errors.New("crypto orchestrator is required")
+	}
+	return nil
+}
+
+type kekRotationRequest struct {
+	RequestID string
+	ActorID   string
+	// RemovedMemberID, when set, is dropped from the group in the same
+	// transaction.
+	RemovedMemberID string
+	// ExcludedDeviceID never receives the new KEK, even if the identity
+	// layer still reports it as active.
+	ExcludedDeviceID string
+}
+
+// rotate moves tg to KEK version N+1. Devices the identity layer no longer
+// reports as active simply receive no new envelope.
+func (r *kekRotation) rotate(
+	ctx context.Context,
+	tg trustgroup_domain.TrustGroup,
+	req kekRotationRequest,
+) (*kekRotationOutcome, error) {
+	actorID := req.ActorID
+
+	// Remaining active devices receive the new KEK
+	devices, err := r.remainingDevices(ctx, &tg, req.RemovedMemberID, req.ExcludedDeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, trustgroup_domain.ErrNoActiveDevices
+	}
+
+	// Split share entries by policy
+	entries, err := r.shareLister.ListShareEntriesByTrustGroup(ctx, tg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share entries: %w", err)
+	}
+	policy := tg.RewrapPolicy()
+	assets := []trustgroup_orchestrator.RotateCollaborativeAssetInput{}
+	pending := []string{}
+	for _, entry := range entries {
+		if entry.TrustGroupID != tg.ID || entry.Status != c3_asset_domain.ShareEntryStatusActive {
+			continue
+		}
+		// Only DEKs under the outgoing KEK can be re-wrapped now; older
+		// ones are left for lazy re-wrap on read.
+		if policy == trustgroup_domain.ShareRewrapLazy || entry.KEKVersion != tg.KEKVersion {
+			pending = append(pending, entry.ID)
+			continue
+		}
+		assets = append(assets, trustgroup_orchestrator.RotateCollaborativeAssetInput{
+			ShareEntryID: entry.ID,
+			WrappedDEK:   decodeWrappedDEK(entry.WrappedDEK),
+		})
+	}
+
+	// Generate and wrap the new KEK locally
+	keyring, err := r.identityResolver.GetVaultKeyring(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local keyring for user %s: %w", actorID, err)
+	}
+	oldVersion := tg.KEKVersion
+	newVersion := oldVersion + 1
+	rotated, err := r.orchestrator.RotateTrustGroupKEK(ctx, trustgroup_orchestrator.RotateTrustGroupKEKPayload{
+		TrustGroupID:  tg.ID,
+		OldVersion:    oldVersion,
+		NewVersion:    newVersion,
+		ActiveDevices: devices,
+		Assets:        assets,
+		Keyring:       keyring,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate trust group KEK: %w", err)
+	}
+
+	rotatedEntries := make([]RotatedShareEntryInput, 0, len(rotated.RotatedAssets))
+	rewrapped := make([]string, 0, len(rotated.RotatedAssets))
+	for _, asset := range rotated.RotatedAssets {
+		rotatedEntries = append(rotatedEntries, RotatedShareEntryInput{
+			ShareEntryID: asset.ShareEntryID,
+			ReWrappedDEK: base64.StdEncoding.EncodeToString(asset.ReWrappedDEK),
+		})
+		rewrapped = append(rewrapped, asset.ShareEntryID)
+	}
+
+	// Persist rotation (and removal) with the re-wrapped entries atomically
+	rotateResp, err := r.rotateUseCase.Execute(ctx, RotateTrustGroupKEKRequest{
+		RequestID:           req.RequestID,
+		TrustGroupID:        tg.ID,
+		OldVersion:          oldVersion,
+		NewVersion:          newVersion,
+		RevokedMemberID:     req.RemovedMemberID,
+		NewEnvelopes:        rotated.NewEnvelopes,
+		RotatedShareEntries: rotatedEntries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &kekRotationOutcome{
+		TrustGroup: rotateResp.TrustGroup,
+		OldVersion: oldVersion,
+		NewVersion: newVersion,
+		Policy:     policy,
+		ReWrapped:  rewrapped,
+		Pending:    pending,
+	}, nil
+}
+
+// remainingDevices resolves the devices of every other member that holds a
+// live envelope for the current KEK. Devices the identity layer reports as
+// inactive, or that no longer belong to the member, are left out.
+func (r *kekRotation) remainingDevices(
+	ctx context.Context,
+	tg *trustgroup_domain.TrustGroup,
+	removedMemberID string,
+	excludedDeviceID string,
+) ([]trustgroup_orchestrator.ActiveDevice, error) {
+	seen := make(map[string]bool)
+	devices := []trustgroup_orchestrator.ActiveDevice{}
+	for _, env := range tg.ActiveEnvelopes() {
+		if env.MemberID == removedMemberID || env.DeviceID == excludedDeviceID || !tg.IsMember(env.MemberID) || seen[env.DeviceID] {
+			continue
+		}
+		seen[env.DeviceID] = true
+
+		device, err := r.deviceResolver.GetDevice(ctx, env.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve device %s: %w", env.DeviceID, err)
+		}
+		if device == nil || !device.IsActive || device.PublicKey == "" {
+			continue
+		}
+		if device.VaultID != "" && device.VaultID != env.MemberID {
+			continue
+		}
+		devices = append(devices, trustgroup_orchestrator.ActiveDevice{
+			DeviceID:  env.DeviceID,
+			MemberID:  env.MemberID,
+			PublicKey: device.PublicKey,
+			IsActive:  true,
+		})
+	}
+	return devices, nil
+}
+
+func publishKEKRotated(
+	ctx context.Context,
+	publisher collaboration_ports.TrustGroupRotationPublisher,
+	tg trustgroup_domain.TrustGroup,
+	outcome *kekRotationOutcome,
+	reason string,
+	actorID string,
+) {
+	if publisher == nil {
+		return
+	}
+	_ = publisher.PublishTrustGroupKEKRotated(ctx, trustgroup_domain.TrustGroupKEKRotated{
+		EventID:                uuid.NewString(),
+		EventTimestamp:         time.Now(),
+		TrustGroupID:           tg.ID,
+		Name:                   tg.Name,
+		ChannelID:              tg.ChannelID,
+		OldVersion:             outcome.OldVersion,
+		NewVersion:             outcome.NewVersion,
+		Reason:                 reason,
+		ActorID:                actorID,
+		RewrapPolicy:           outcome.Policy,
+		ReWrappedShareEntryIDs: outcome.ReWrapped,
+		PendingShareEntryIDs:   outcome.Pending,
+	})
+}
+
+// decodeWrappedDEK accepts the base64 form share entries are stored in and
+// falls back to the raw bytes.
+func decodeWrappedDEK(wrapped string) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil || len(decoded) == 0 {
+		return []byte(wrapped)
+	}
+	return decoded
+}
@@ -0,0 +1,168 @@
+package collaboration_usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
+	collaboration_ports "vault-app/internal/collaboration/application/ports"
+	trustgroup_orchestrator "vault-app/internal/trust_group/application/orchestrator"
+	trustgroup_ports "vault-app/internal/trust_group/application/ports"
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+// RemoveTrustGroupMemberUseCase removes a member and cuts off their future
+// access in one step: their envelopes are revoked, a fresh KEK is wrapped for
+// every remaining active device, and share DEKs follow the group's re-wrap
+// policy. Persistence goes through RotateTrustGroupKEKUseCase, so the group
+// and the eagerly re-wrapped entries commit together or not at all.
+type RemoveTrustGroupMemberUseCase struct {
+	trustGroupRepo trustgroup_domain.TrustGroupRepository
+	rotation       kekRotation
+	publisher      collaboration_ports.TrustGroupRotationPublisher
+
+	mu                sync.Mutex
+	processedRequests map[string]*collaboration_dtos.RemoveTrustGroupMemberResponse
+}
+
+// NewRemoveTrustGroupMemberUseCase wires the removal flow. publisher may be
+// nil, in which case the removal and rotation events are not emitted.
+func NewRemoveTrustGroupMemberUseCase(
+	rotateUseCase *RotateTrustGroupKEKUseCase,
+	trustGroupRepo trustgroup_domain.TrustGroupRepository,
+	shareLister collaboration_ports.ShareEntryLister,
+	deviceResolver trustgroup_ports.DeviceResolver,
+	identityResolver collaboration_ports.SovereignIdentityResolver,
+	orchestrator *trustgroup_orchestrator.TrustGroupCryptoOrchestrator,
+	publisher collaboration_ports.TrustGroupRotationPublisher,
+) *RemoveTrustGroupMemberUseCase {
+	return &RemoveTrustGroupMemberUseCase{
+		trustGroupRepo: trustGroupRepo,
+		rotation: kekRotation{
+			rotateUseCase:    rotateUseCase,
+			shareLister:      shareLister,
+			deviceResolver:   deviceResolver,
+			identityResolver: identityResolver,
+			orchestrator:     orchestrator,
+		},
+		publisher:         publisher,
+		processedRequests: make(map[string]*collaboration_dtos.RemoveTrustGroupMemberResponse),
+	}
+}
+
+func (u *RemoveTrustGroupMemberUseCase) ValidateDependencies() error {
+	if u.trustGroupRepo == nil {
+		return trustgroup_domain.ErrRepositoryNil
+	}
+	return u.rotation.validate()
+}
+
+func (u *RemoveTrustGroupMemberUseCase) ValidateRequest(req collaboration_dtos.RemoveTrustGroupMemberRequest) error {
+	if strings.TrimSpace(req.TrustGroupID) == "" {
+		return errors.New("trust group id is required")
+	}
+	if strings.TrimSpace(req.MemberID) == "" {
+		return errors.New("member id is required")
+	}
+	if strings.TrimSpace(req.ActorID) == "" {
+		return errors.New("actor id is required")
+	}
+	if req.ActorID == req.MemberID {
+		return trustgroup_domain.ErrSelfRemovalRotation
+	}
+	return nil
+}
+
+func (u *RemoveTrustGroupMemberUseCase) Execute(
+	ctx context.Context,
+	req collaboration_dtos.RemoveTrustGroupMemberRequest,
+) (*collaboration_dtos.RemoveTrustGroupMemberResponse, error) {
+	if err := u.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if err := u.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	if req.RequestID != "" {
+		u.mu.Lock()
+		if resp, exists := u.processedRequests[req.RequestID]; exists {
+			u.mu.Unlock()
+			return resp, nil
+		}
+		u.mu.Unlock()
+	}
+
+	// 1. Load the group and authorize the actor
+	tgResp, err := u.trustGroupRepo.GetTrustGroup(ctx, &trustgroup_domain.GetTrustGroupRequest{
+		TrustGroupID: req.TrustGroupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trust group: %w", err)
+	}
+	if tgResp == nil || tgResp.Data.ID == "" {
+		return nil, trustgroup_domain.ErrTrustGroupNotFound
+	}
+	tg := tgResp.Data
+	if !tg.IsMember(req.MemberID) {
+		return nil, trustgroup_domain.ErrTrustGroupMemberNotFound
+	}
+	if !tg.IsMember(req.ActorID) {
+		return nil, ErrUnauthorizedMember
+	}
+
+	// The devices losing access are recorded from a copy; the aggregate
+	// itself is mutated by the rotate use case.
+	preview := tg
+	preview.KeyEnvelopes = append([]trustgroup_domain.TrustGroupKeyEnvelope(nil), tg.KeyEnvelopes...)
+	revokedDevices := preview.RevokeMemberEnvelopes(req.MemberID, time.Now())
+
+	// 2-5. Rotate to a KEK only the remaining devices can open
+	outcome, err := u.rotation.rotate(ctx, tg, kekRotationRequest{
+		RequestID:       req.RequestID,
+		ActorID:         req.ActorID,
+		RemovedMemberID: req.MemberID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &collaboration_dtos.RemoveTrustGroupMemberResponse{
+		TrustGroup:             outcome.TrustGroup,
+		KEKVersion:             outcome.NewVersion,
+		RevokedDeviceIDs:       revokedDevices,
+		ReWrappedShareEntryIDs: outcome.ReWrapped,
+		PendingShareEntryIDs:   outcome.Pending,
+	}
+
+	// 6. Record the removal and the rotation
+	if u.publisher != nil {
+		now := time.Now()
+		_ = u.publisher.PublishMemberRemovedFromTrustGroup(ctx, trustgroup_domain.MemberRemovedFromTrustGroup{
+			EventID:          uuid.NewString(),
+			EventTimestamp:   now,
+			TrustGroupID:     tg.ID,
+			Name:             tg.Name,
+			ChannelID:        tg.ChannelID,
+			MemberID:         req.MemberID,
+			ActorID:          req.ActorID,
+			RevokedDeviceIDs: revokedDevices,
+			KEKVersion:       outcome.NewVersion,
+		})
+		publishKEKRotated(ctx, u.publisher, tg, outcome, trustgroup_domain.KEKRotationMemberRemoved, req.ActorID)
+	}
+
+	if req.RequestID != "" {
+		u.mu.Lock()
+		u.processedRequests[req.RequestID] = resp
+		u.mu.Unlock()
+	}
+
+	return resp, nil
+}
@@ -129,13 +129,21 @@ func (u *ResolveCollaborativeShareUseCase) Execute(
 		return nil, ErrUnauthorizedMember
 	}
 
-	// 4. Authorize & Resolve Active Device Envelope BEFORE resolving assets or key material
+	// 4. Authorize & Resolve Active Device Envelope BEFORE resolving assets or key material.
+	// An entry left behind by a lazy rotation is read through the caller's
+	// envelope for the current KEK; the envelope for the entry's own version
+	// (revoked by the rotation) is only used to unwrap the old DEK.
+	rewrapNeeded := shareEntry.KEKVersion < trustGroup.KEKVersion
+	targetVersion := shareEntry.KEKVersion
+	if rewrapNeeded {
+		targetVersion = trustGroup.KEKVersion
+	}
 	var activeEnvelope *trustgroup_domain.TrustGroupKeyEnvelope
 	for i := range trustGroup.KeyEnvelopes {
 		env := &trustGroup.KeyEnvelopes[i]
 		if env.MemberID == req.CallerUserID &&
 			env.DeviceID == req.DeviceID &&
-			env.KEKVersion == shareEntry.KEKVersion &&
+			env.KEKVersion == targetVersion &&
 			env.RevokedAt == nil {
 			activeEnvelope = env
 			break
@@ -166,9 +174,30 @@ func (u *ResolveCollaborativeShareUseCase) Execute(
 	}
 
 	// 7. Decode WrappedDEK (Base64 string or raw bytes)
-	wrappedDEKBytes, err := base64.StdEncoding.DecodeString(shareEntry.WrappedDEK)
-	if err != nil || len(wrappedDEKBytes) == 0 {
-		wrappedDEKBytes = []byte(shareEntry.WrappedDEK)
+	wrappedDEKBytes := decodeWrappedDEK(shareEntry.WrappedDEK)
+
+	// 7b. Lazy re-wrap: move the DEK to the current KEK before reading
+	if rewrapNeeded {
+		oldWrappedKEK := ""
+		if oldEnv, ok := trustGroup.Envelope(req.CallerUserID, req.DeviceID, shareEntry.KEKVersion); ok {
+			oldWrappedKEK = oldEnv.WrappedKEK
+		}
+		reWrapped, err := u.cryptoOrchestrator.RewrapDEK(ctx, trustgroup_orchestrator.RewrapDEKPayload{
+			TrustGroupID:  shareEntry.TrustGroupID,
+			OldVersion:    shareEntry.KEKVersion,
+			NewVersion:    trustGroup.KEKVersion,
+			WrappedDEK:    wrappedDEKBytes,
+			OldWrappedKEK: oldWrappedKEK,
+			NewWrappedKEK: activeEnvelope.WrappedKEK,
+			DeviceSeed:    deviceSeed,
+			Keyring:       keyring,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-wrap share entry DEK: %w", err)
+		}
+		wrappedDEKBytes = reWrapped
+		shareEntry.WrappedDEK = base64.StdEncoding.EncodeToString(reWrapped)
+		shareEntry.KEKVersion = trustGroup.KEKVersion
 	}
 
 	// 8. Invoke Cryptographic Resolution (Local Sovereign Unwrapping & Decryption)
@@ -186,6 +215,14 @@ func (u *ResolveCollaborativeShareUseCase) Execute(
 		return nil, fmt.Errorf("cryptographic resolution failed: %w", err)
 	}
 
+	// Persist the migrated entry only once it is known to decrypt. A failed
+	// write is not fatal: the next read simply re-wraps again.
+	if rewrapNeeded {
+		_, _ = u.shareEntryRepo.UpdateShareEntry(ctx, &c3_asset_domain.UpdateShareEntryRequest{
+			ShareEntry: shareEntry,
+		})
+	}
+
 	createdAtStr := shareEntry.CreatedAt.Format(time.RFC3339)
 
 	// 9. Return Clean Response DTO (Zero Secret Leakage)
@@ -196,5 +233,7 @@ func (u *ResolveCollaborativeShareUseCase) Execute(
 		CreatedAt:    createdAtStr,
 		Metadata:     shareEntry.Metadata,
 		Plaintext:    cryptoResult.Plaintext,
+		KEKVersion:   shareEntry.KEKVersion,
+		Rewrapped:    rewrapNeeded,
 	}, nil
 }
@@ -128,7 +128,9 @@ func (u *RotateTrustGroupKEKUseCase) Execute(
 	// 3. Prepare Domain Envelopes & Aggregate State Transition
 	// -------------------------------------------------------------------------
 	if req.RevokedMemberID != "" {
-		_ = tg.RemoveMember(req.RevokedMemberID)
+		if err := tg.RemoveMember(req.RevokedMemberID); err != nil {
+			return nil, fmt.Errorf("failed to remove revoked member: %w", err)
+		}
 	}
 
 	domainEnvelopes := make([]trustgroup_domain.TrustGroupKeyEnvelope, 0, len(req.NewEnvelopes))
@@ -0,0 +1,76 @@
+package collaboration_identity
+
+import (
+	"context"
+	"fmt"
+
+	collaboration_ports "vault-app/internal/collaboration/application/ports"
+	app_config_domain "vault-app/internal/config/domain"
+	onboarding_domain "vault-app/internal/onboarding/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type UserConfigSource interface {
+	GetUserConfigByUserID(userID string) (*app_config_domain.UserConfig, error)
+}
+
+type OnboardingUserFinder interface {
+	FindByEmail(email string) (*onboarding_domain.User, error)
+}
+
+type KeyringLoader interface {
+	LoadHybrid(userID string, password, stellar string) (*vaults_domain.VaultKeyring, error)
+}
+
+// SessionIdentityResolver resolves the local member's credentials from the
+// user config: the device seed is the Stellar secret, and the keyring is
+// the one written at onboarding, unwrapped with that same secret.
+type SessionIdentityResolver struct {
+	configs  UserConfigSource
+	users    OnboardingUserFinder
+	keyrings KeyringLoader
+}
+
+func NewSessionIdentityResolver(configs UserConfigSource, users OnboardingUserFinder, keyrings KeyringLoader) *SessionIdentityResolver {
+	return &SessionIdentityResolver{configs: configs, users: users, keyrings: keyrings}
+}
+
+func (r *SessionIdentityResolver) GetDeviceSeed(_ context.Context, userID string) (string, error) {
+	cfg, err := r.userConfig(userID)
+	if err != nil {
+		return "", err
+	}
+	return cfg.StellarAccount.PrivateKey, nil
+}
+
+func (r *SessionIdentityResolver) GetVaultKeyring(_ context.Context, userID string) (*vaults_domain.VaultKeyring, error) {
+	cfg, err := r.userConfig(userID)
+	if err != nil {
+		return nil, err
+	}
+	if r.users == nil || r.keyrings == nil {
+		return nil, fmt.Errorf("keyring source is not initialized")
+	}
+	user, err := r.users.FindByEmail(cfg.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find onboarding user: %w", err)
+	}
+	return r.keyrings.LoadHybrid(user.ID, "", cfg.StellarAccount.PrivateKey)
+}
+
+func (r *SessionIdentityResolver) userConfig(userID string) (*app_config_domain.UserConfig, error) {
+	if r.configs == nil {
+		return nil, fmt.Errorf("user config source is not initialized")
+	}
+	cfg, err := r.configs.GetUserConfigByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user config: %w", err)
+	}
+	if cfg == nil || cfg.StellarAccount.PrivateKey == "" {
+		return nil, fmt.Errorf("stellar signing key not found in user config")
+	}
+	return cfg, nil
+}
+
+// Ensure interface satisfaction at compile-time
+var _ collaboration_ports.SovereignIdentityResolver = (*SessionIdentityResolver)(nil)
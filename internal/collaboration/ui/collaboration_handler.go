@@ -5,6 +5,8 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
+
 	collaboration_dtos "vault-app/internal/collaboration/application/dtos"
 	collaboration_usecases "vault-app/internal/collaboration/application/usecases"
 	thread_domain "vault-app/internal/thread/domain"
@@ -16,6 +18,9 @@ type CollaborationHandler struct {
 	createCollabShareUC  *collaboration_usecases.CreateCollaborativeShareUseCase
 	resolveCollabShareUC *collaboration_usecases.ResolveCollaborativeShareUseCase
 	appendEventUC        *thread_usecase.AppendThreadEventUsecase
+	removeMemberUC       *collaboration_usecases.RemoveTrustGroupMemberUseCase
+	provisionDeviceUC    *collaboration_usecases.ProvisionDeviceEnvelopesUseCase
+	revokedDeviceUC      *collaboration_usecases.RotateForRevokedDeviceUseCase
 }
 
 func NewCollaborationHandler(
@@ -30,6 +35,28 @@ func NewCollaborationHandler(
 	}
 }
 
+// SetRemoveMemberUseCase enables trust group member removal, which rotates
+// the group KEK as part of the same operation.
+func (h *CollaborationHandler) SetRemoveMemberUseCase(uc *collaboration_usecases.RemoveTrustGroupMemberUseCase) {
+	h.removeMemberUC = uc
+}
+
+// SetDeviceTrustUseCases enables the trust group side of device enrollment
+// and revocation.
+func (h *CollaborationHandler) SetDeviceTrustUseCases(
+	provision *collaboration_usecases.ProvisionDeviceEnvelopesUseCase,
+	revoked *collaboration_usecases.RotateForRevokedDeviceUseCase,
+) {
+	h.provisionDeviceUC = provision
+	h.revokedDeviceUC = revoked
+}
+
+// HasDeviceTrust reports whether device changes are propagated to trust
+// group envelopes.
+func (h *CollaborationHandler) HasDeviceTrust() bool {
+	return h.provisionDeviceUC != nil && h.revokedDeviceUC != nil
+}
+
 // CreateCollaborativeShare persists a C3 share entry through the real
 // Cloud persistence path and returns the authoritative ShareEntryRef.
 //
@@ -37,10 +64,12 @@ func NewCollaborationHandler(
 // desktop crypto layer (TrustGroupCryptoOrchestrator.PrepareCollaborativeAsset).
 // They must be supplied by the caller; this handler never invents them.
 // A successful response only ever contains the share_entry_id returned by
-// the Cloud C3 ShareEntry persistence path.
+// the Cloud C3 ShareEntry persistence path. The entry.shared thread event is
+// appended as actorVaultID, the caller's vault.
 func (h *CollaborationHandler) CreateCollaborativeShare(
 	ctx context.Context,
 	userID string,
+	actorVaultID string,
 	threadID string,
 	trustGroupID string,
 	assetCID string,
@@ -102,7 +131,7 @@ func (h *CollaborationHandler) CreateCollaborativeShare(
 			TrustGroupID: createdTrustGroupID,
 		}
 		idempotencyKey := "evt_share_" + createdShareID
-		_, err := h.appendEventUC.Execute(ctx, threadID, "entry.shared", refPayload, idempotencyKey)
+		_, err := h.appendEventUC.ExecuteAs(ctx, actorVaultID, threadID, "entry.shared", refPayload, idempotencyKey)
 		if err != nil {
 			return &shareRef, err
 		}
@@ -129,3 +158,72 @@ func (h *CollaborationHandler) ResolveCollaborativeShare(
 
 	return h.resolveCollabShareUC.Execute(ctx, req)
 }
+
+// RemoveTrustGroupMember removes memberID from the trust group and rotates
+// the KEK so the removed member cannot open anything shared afterwards.
+func (h *CollaborationHandler) RemoveTrustGroupMember(
+	ctx context.Context,
+	userID string,
+	trustGroupID string,
+	memberID string,
+	reason string,
+) (*collaboration_dtos.RemoveTrustGroupMemberResponse, error) {
+	if h.removeMemberUC == nil {
+		return nil, errors.New("remove trust group member use case is not initialized")
+	}
+
+	// Each call is a new removal: a member re-added after an earlier removal
+	// must trigger a fresh rotation, not replay the cached response.
+	req := collaboration_dtos.RemoveTrustGroupMemberRequest{
+		RequestID:    uuid.NewString(),
+		TrustGroupID: trustGroupID,
+		MemberID:     memberID,
+		ActorID:      userID,
+		Reason:       reason,
+	}
+
+	return h.removeMemberUC.Execute(ctx, req)
+}
+
+// ProvisionDeviceEnvelopes seals the current KEK of every trust group the
+// vault belongs to for a newly approved device.
+func (h *CollaborationHandler) ProvisionDeviceEnvelopes(
+	ctx context.Context,
+	userID string,
+	vaultID string,
+	approverDeviceID string,
+	deviceID string,
+	publicKey string,
+) (*collaboration_dtos.ProvisionDeviceEnvelopesResponse, error) {
+	if h.provisionDeviceUC == nil {
+		return nil, errors.New("provision device envelopes use case is not initialized")
+	}
+
+	return h.provisionDeviceUC.Execute(ctx, collaboration_dtos.ProvisionDeviceEnvelopesRequest{
+		MemberID:         vaultID,
+		ActorID:          userID,
+		ApproverDeviceID: approverDeviceID,
+		DeviceID:         deviceID,
+		PublicKey:        publicKey,
+	})
+}
+
+// RotateForRevokedDevice rotates the KEK of every trust group a revoked
+// device could still open.
+func (h *CollaborationHandler) RotateForRevokedDevice(
+	ctx context.Context,
+	userID string,
+	vaultID string,
+	deviceID string,
+) (*collaboration_dtos.RotateForRevokedDeviceResponse, error) {
+	if h.revokedDeviceUC == nil {
+		return nil, errors.New("revoked device rotation use case is not initialized")
+	}
+
+	return h.revokedDeviceUC.Execute(ctx, collaboration_dtos.RotateForRevokedDeviceRequest{
+		RequestID: "revoke_" + deviceID,
+		MemberID:  vaultID,
+		ActorID:   userID,
+		DeviceID:  deviceID,
+	})
+}
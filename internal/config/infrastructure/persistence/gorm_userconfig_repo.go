@@ -57,6 +57,7 @@ func (r *GormUserConfigRepository) DeleteUserConfig(id string) error {
 // -------- Mappers --------
 type UserConfigMapper struct {
 	ID               string               `json:"id" gorm:"primaryKey;autoIncrement:false;size:36;uniqueIndex"`
+	Email            string               `json:"email,omitempty" gorm:"column:email"`
 	Role             string               `json:"role" gorm:"column:role"`
 	Signature        string               `json:"signature" gorm:"column:signature"`
 	ConnectedOrgs    []string             `json:"connected_orgs" gorm:"type:json;serializer:json"`
@@ -68,6 +69,7 @@ type UserConfigMapper struct {
 func (r *GormUserConfigRepository) toUserConfigMapper(userConfig *app_config_domain.UserConfig) *UserConfigMapper {
 	return &UserConfigMapper{
 		ID:             userConfig.ID,
+		Email:          userConfig.Email,
 		Role:           userConfig.Role,
 		Signature:      userConfig.Signature,
 		ConnectedOrgs:  userConfig.ConnectedOrgs,
@@ -81,6 +83,7 @@ func (r *GormUserConfigRepository) toUserConfigMapper(userConfig *app_config_dom
 func (r *GormUserConfigRepository) toUserConfigModel(userConfig *UserConfigMapper) *app_config_domain.UserConfig {
 	return &app_config_domain.UserConfig{
 		ID:             userConfig.ID,
+		Email:          userConfig.Email,
 		Role:           userConfig.Role,
 		Signature:      userConfig.Signature,
 		ConnectedOrgs:  userConfig.ConnectedOrgs,
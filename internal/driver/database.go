@@ -9,6 +9,7 @@ import (
 	share_entry_infrastructure "vault-app/internal/share_entry/infrastructure"
 	// auth_domain "vault-app/internal/auth/domain"
 	// auth_persistence "vault-app/internal/auth/infrastructure/persistence"
+	browser_extension_domain "vault-app/internal/browser_extension/domain"
 	app_config "vault-app/internal/config"
 	app_config_persistence "vault-app/internal/config/infrastructure/persistence"
 	share_domain "vault-app/internal/domain/shared"
@@ -20,6 +21,7 @@ import (
 	subscription_persistence "vault-app/internal/subscription/infrastructure/persistence"
 	vaults_domain "vault-app/internal/vault/domain"
 	vaults_persistence "vault-app/internal/vault/infrastructure/persistence"
+	vault_export_domain "vault-app/internal/vault_export/domain"
 )
 
 func AutoMigrate(db *gorm.DB) error {
@@ -69,11 +71,15 @@ func AutoMigrate(db *gorm.DB) error {
 
 		// Identity
 		&identity_domain.User{},
+		&identity_domain.Device{},
+		&identity_domain.DeviceEnrollment{},
 
 		// vault
 		&vaults_persistence.VaultMapper{},
 		&vaults_persistence.SessionMapper{},
 		&vaults_persistence.KeyringMapper{},
 		&vaults_domain.Folder{}, // delete this later
+		&vault_export_domain.ExportAudit{},
+		&browser_extension_domain.PairedClient{},
 	)
 }
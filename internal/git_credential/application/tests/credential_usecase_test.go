@@ -0,0 +1,230 @@
+package git_credential_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	git_credential_usecases "vault-app/internal/git_credential/application/usecases"
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type vaultMock struct {
+	vp     vaults_domain.VaultPayload
+	locked bool
+	dirty  int
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	return &m.vp, nil
+}
+
+func (m *vaultMock) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	e := entry.(*vaults_domain.LoginEntry)
+	e.ID = "stored-1"
+	m.vp.Entries.Login = append(m.vp.Entries.Login, *e)
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) UpdateEntryFor(_ string, entry any, _ bool) (*vaults_domain.VaultEntry, error) {
+	e := entry.(*vaults_domain.LoginEntry)
+	for i := range m.vp.Entries.Login {
+		if m.vp.Entries.Login[i].ID == e.ID {
+			m.vp.Entries.Login[i] = *e
+		}
+	}
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) TrashEntryFor(_ string, entry any) error {
+	id := entry.(*vaults_domain.LoginEntry).ID
+	for i := range m.vp.Entries.Login {
+		if m.vp.Entries.Login[i].ID == id {
+			m.vp.Entries.Login[i].Trashed = true
+		}
+	}
+	return nil
+}
+
+func (m *vaultMock) MarkDirty(string) { m.dirty++ }
+
+func login(id, website, user, password, updatedAt string) vaults_domain.LoginEntry {
+	e := vaults_domain.LoginEntry{UserName: user, Password: password, Website: website}
+	e.ID, e.EntryName, e.UpdatedAt = id, id, updatedAt
+	return e
+}
+
+var enabled = git_credential_domain.FeatureGateFunc(func(string) (bool, error) { return true, nil })
+
+func https(host, path, user string) git_credential_domain.Credential {
+	return git_credential_domain.Credential{Protocol: "https", Host: host, Path: path, Username: user}
+}
+
+func TestGet_PicksMostSpecificLogin(t *testing.T) {
+	vault := &vaultMock{}
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{
+		login("host-old", "https://git.example.com", "alice", "host-old-pw", "2026-01-01T00:00:00Z"),
+		login("host-new", "git.example.com/", "alice", "host-new-pw", "2026-06-01T00:00:00Z"),
+		login("org", "https://git.example.com/acme", "deploy", "org-pw", "2026-01-01T00:00:00Z"),
+		login("repo", "https://git.example.com/acme/api.git", "ci", "repo-pw", "2026-01-01T00:00:00Z"),
+		login("other-org", "https://git.example.com/acme-labs", "x", "x-pw", "2026-01-01T00:00:00Z"),
+		login("port", "https://git.example.com:8443", "alice", "port-pw", "2026-09-01T00:00:00Z"),
+		login("http", "http://git.example.com", "alice", "http-pw", "2026-09-01T00:00:00Z"),
+	}
+	uc := git_credential_usecases.NewGetCredentialUsecase(vault, enabled)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name     string
+		req      git_credential_domain.Credential
+		password string
+	}{
+		{"host only, most recent wins", https("git.example.com", "", ""), "host-new-pw"},
+		{"repo path", https("GIT.example.com", "acme/api", ""), "repo-pw"},
+		{"org prefix on segment boundary", https("git.example.com", "acme/web.git", ""), "org-pw"},
+		{"no partial segment match", https("git.example.com", "acme-labsx/repo", ""), "host-new-pw"},
+		{"username narrows", https("git.example.com", "acme/api", "deploy"), "org-pw"},
+		{"port must match", https("git.example.com:8443", "", ""), "port-pw"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := uc.Execute(ctx, "user-1", tc.req)
+			require.NoError(t, err)
+			require.NotNil(t, res)
+			require.Equal(t, tc.password, res.Password)
+		})
+	}
+
+	res, err := uc.Execute(ctx, "user-1", https("other.example.com", "", ""))
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	_, err = uc.Execute(ctx, "user-1", git_credential_domain.Credential{Protocol: "http", Host: "git.example.com"})
+	require.ErrorIs(t, err, git_credential_domain.ErrUnsupportedProtocol)
+}
+
+func TestGet_SkipsTrashedAndEmptyPasswords(t *testing.T) {
+	vault := &vaultMock{}
+	trashed := login("trashed", "https://git.example.com", "alice", "pw", "")
+	trashed.Trashed = true
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{
+		trashed,
+		login("empty", "https://git.example.com", "alice", "", ""),
+	}
+	res, err := git_credential_usecases.NewGetCredentialUsecase(vault, enabled).
+		Execute(context.Background(), "user-1", https("git.example.com", "", ""))
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
+func TestStore_CreatesThenUpdatesLogin(t *testing.T) {
+	vault := &vaultMock{}
+	uc := git_credential_usecases.NewStoreCredentialUsecase(vault, enabled)
+	ctx := context.Background()
+
+	c := https("Git.Example.com", "acme/api.git", "ci")
+	c.Password = "first"
+	entry, err := uc.Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+	require.Equal(t, "https://git.example.com/acme/api", entry.Website)
+	require.Equal(t, "git.example.com/acme/api", entry.EntryName)
+	require.Equal(t, vaults_domain.EntryLogin, entry.Type)
+	require.Equal(t, true, entry.CustomFields[git_credential_domain.StoredField])
+	require.Len(t, vault.vp.Entries.Login, 1)
+	require.Equal(t, 1, vault.dirty)
+
+	// Same password again: nothing to write.
+	_, err = uc.Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+	require.Equal(t, 1, vault.dirty)
+
+	c.Password = "rotated"
+	_, err = uc.Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+	require.Len(t, vault.vp.Entries.Login, 1)
+	require.Equal(t, "rotated", vault.vp.Entries.Login[0].Password)
+	require.Equal(t, 2, vault.dirty)
+
+	// A host-wide login does not absorb a repository credential.
+	c.Path, c.Password = "", "host"
+	_, err = uc.Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+	require.Len(t, vault.vp.Entries.Login, 2)
+
+	_, err = uc.Execute(ctx, "user-1", https("git.example.com", "", "ci"))
+	require.ErrorIs(t, err, git_credential_domain.ErrInvalidCredential)
+}
+
+func TestErase_OnlyTrashesHelperStoredLogins(t *testing.T) {
+	vault := &vaultMock{}
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{
+		login("manual", "https://git.example.com", "alice", "pw", ""),
+	}
+	ctx := context.Background()
+	c := https("git.example.com", "acme/api", "ci")
+	c.Password = "pw"
+	_, err := git_credential_usecases.NewStoreCredentialUsecase(vault, enabled).Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+
+	erase := git_credential_usecases.NewEraseCredentialUsecase(vault, enabled)
+
+	manual := https("git.example.com", "", "alice")
+	erased, err := erase.Execute(ctx, "user-1", manual)
+	require.NoError(t, err)
+	require.False(t, erased)
+
+	stale := c
+	stale.Password = "older"
+	erased, err = erase.Execute(ctx, "user-1", stale)
+	require.NoError(t, err)
+	require.False(t, erased)
+
+	erased, err = erase.Execute(ctx, "user-1", c)
+	require.NoError(t, err)
+	require.True(t, erased)
+	require.False(t, vault.vp.Entries.Login[0].Trashed)
+	require.True(t, vault.vp.Entries.Login[1].Trashed)
+}
+
+func TestHelper_RefusesWithoutFeatureOrSession(t *testing.T) {
+	ctx := context.Background()
+	req := https("git.example.com", "", "")
+
+	disabled := git_credential_domain.FeatureGateFunc(func(string) (bool, error) { return false, nil })
+	helper := git_credential_usecases.NewCredentialHelper("user-1", &vaultMock{}, disabled)
+	_, err := helper.Handle(ctx, git_credential_domain.OpGet, req)
+	require.ErrorIs(t, err, git_credential_domain.ErrFeatureDisabled)
+
+	helper = git_credential_usecases.NewCredentialHelper("user-1", &vaultMock{locked: true}, enabled)
+	_, err = helper.Handle(ctx, git_credential_domain.OpGet, req)
+	require.ErrorIs(t, err, git_credential_domain.ErrVaultLocked)
+
+	helper = git_credential_usecases.NewCredentialHelper("", &vaultMock{}, enabled)
+	_, err = helper.Handle(ctx, git_credential_domain.OpGet, req)
+	require.ErrorIs(t, err, git_credential_domain.ErrUserIDRequired)
+
+	helper = git_credential_usecases.NewCredentialHelper("user-1", &vaultMock{}, enabled)
+	_, err = helper.Handle(ctx, "approve", req)
+	require.ErrorIs(t, err, git_credential_domain.ErrUnknownOperation)
+}
+
+func TestReadCredential_ParsesGitInput(t *testing.T) {
+	c, err := git_credential_domain.ReadCredential(strings.NewReader("protocol=https\nhost=git.example.com\nwwwauth[]=Basic\n\nignored=1\n"))
+	require.NoError(t, err)
+	require.Equal(t, https("git.example.com", "", ""), c)
+
+	c, err = git_credential_domain.ReadCredential(strings.NewReader("url=https://ci@git.example.com:8443/acme/api.git\n"))
+	require.NoError(t, err)
+	require.Equal(t, https("git.example.com:8443", "acme/api.git", "ci"), c)
+
+	_, err = git_credential_domain.ReadCredential(strings.NewReader("garbage\n"))
+	require.ErrorIs(t, err, git_credential_domain.ErrInvalidCredential)
+}
@@ -0,0 +1,40 @@
+package git_credential_usecases
+
+import (
+	"context"
+	"fmt"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+)
+
+// CredentialHelper serves the helper operations for one user.
+type CredentialHelper struct {
+	UserID string
+	Get    *GetCredentialUsecase
+	Store  *StoreCredentialUsecase
+	Erase  *EraseCredentialUsecase
+}
+
+func NewCredentialHelper(userID string, vault VaultEntries, gate git_credential_domain.FeatureGate) *CredentialHelper {
+	return &CredentialHelper{
+		UserID: userID,
+		Get:    NewGetCredentialUsecase(vault, gate),
+		Store:  NewStoreCredentialUsecase(vault, gate),
+		Erase:  NewEraseCredentialUsecase(vault, gate),
+	}
+}
+
+// Handle runs op. Only get answers with a credential.
+func (h *CredentialHelper) Handle(ctx context.Context, op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+	switch op {
+	case git_credential_domain.OpGet:
+		return h.Get.Execute(ctx, h.UserID, c)
+	case git_credential_domain.OpStore:
+		_, err := h.Store.Execute(ctx, h.UserID, c)
+		return nil, err
+	case git_credential_domain.OpErase:
+		_, err := h.Erase.Execute(ctx, h.UserID, c)
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %q", git_credential_domain.ErrUnknownOperation, op)
+}
@@ -0,0 +1,43 @@
+package git_credential_usecases
+
+import (
+	"context"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+)
+
+// EraseCredentialUsecase handles a credential git reports as rejected. Only
+// logins the helper stored itself are trashed; logins the user created are
+// never touched from the command line.
+type EraseCredentialUsecase struct {
+	Vault VaultEntries
+	Gate  git_credential_domain.FeatureGate
+}
+
+func NewEraseCredentialUsecase(vault VaultEntries, gate git_credential_domain.FeatureGate) *EraseCredentialUsecase {
+	return &EraseCredentialUsecase{Vault: vault, Gate: gate}
+}
+
+// Execute reports whether a login was trashed. When git passes the
+// rejected password, a login holding a different one is kept.
+func (uc *EraseCredentialUsecase) Execute(ctx context.Context, userID string, c git_credential_domain.Credential) (bool, error) {
+	vp, err := open(uc.Gate, uc.Vault, userID)
+	if err != nil {
+		return false, err
+	}
+	if err := c.Validate(); err != nil {
+		return false, err
+	}
+	entry := exact(vp, c)
+	if entry == nil || entry.CustomFields[git_credential_domain.StoredField] != true {
+		return false, nil
+	}
+	if c.Password != "" && entry.Password != c.Password {
+		return false, nil
+	}
+	if err := uc.Vault.TrashEntryFor(userID, entry); err != nil {
+		return false, err
+	}
+	uc.Vault.MarkDirty(userID)
+	return true, nil
+}
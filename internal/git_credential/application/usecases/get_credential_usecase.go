@@ -0,0 +1,37 @@
+package git_credential_usecases
+
+import (
+	"context"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+)
+
+// GetCredentialUsecase answers "git credential fill" with the best
+// matching login of the unlocked vault.
+type GetCredentialUsecase struct {
+	Vault VaultEntries
+	Gate  git_credential_domain.FeatureGate
+}
+
+func NewGetCredentialUsecase(vault VaultEntries, gate git_credential_domain.FeatureGate) *GetCredentialUsecase {
+	return &GetCredentialUsecase{Vault: vault, Gate: gate}
+}
+
+// Execute returns nil, without error, when no login matches so git moves
+// on to the next helper or prompts.
+func (uc *GetCredentialUsecase) Execute(ctx context.Context, userID string, c git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+	vp, err := open(uc.Gate, uc.Vault, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	found := candidates(vp, c)
+	if len(found) == 0 {
+		return nil, nil
+	}
+	best := found[0].entry
+	c.Username, c.Password = best.UserName, best.Password
+	return &c, nil
+}
@@ -0,0 +1,82 @@
+package git_credential_usecases
+
+import (
+	"fmt"
+	"sort"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultEntries is the part of the vault handler the helper reads logins
+// from and writes them back through, so stored credentials follow the
+// normal commit path.
+type VaultEntries interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+	AddEntryFor(userID string, entry any) (*vaults_domain.VaultEntry, error)
+	UpdateEntryFor(userID string, entry any, isSyncMode bool) (*vaults_domain.VaultEntry, error)
+	TrashEntryFor(userID string, entry any) error
+	MarkDirty(userID string)
+}
+
+// open checks the subscription and returns the unlocked vault of userID.
+func open(gate git_credential_domain.FeatureGate, vault VaultEntries, userID string) (*vaults_domain.VaultPayload, error) {
+	if gate == nil || vault == nil {
+		return nil, fmt.Errorf("git credential use case is not initialized")
+	}
+	if userID == "" {
+		return nil, git_credential_domain.ErrUserIDRequired
+	}
+	enabled, err := gate.GitCLIEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, git_credential_domain.ErrFeatureDisabled
+	}
+	vp, err := vault.GetVaultSession(userID)
+	if err != nil || vp == nil {
+		return nil, fmt.Errorf("%w: %v", git_credential_domain.ErrVaultLocked, err)
+	}
+	return vp, nil
+}
+
+type candidate struct {
+	entry *vaults_domain.LoginEntry
+	score int
+}
+
+// candidates returns the logins matching c, most specific first and, among
+// equally specific ones, most recently updated first.
+func candidates(vp *vaults_domain.VaultPayload, c git_credential_domain.Credential) []candidate {
+	var out []candidate
+	for i := range vp.Entries.Login {
+		e := &vp.Entries.Login[i]
+		if e.Trashed || e.Password == "" {
+			continue
+		}
+		if c.Username != "" && e.UserName != c.Username {
+			continue
+		}
+		if score := git_credential_domain.Match(e.Website, c); score >= 0 {
+			out = append(out, candidate{entry: e, score: score})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		return out[i].entry.UpdatedAt > out[j].entry.UpdatedAt
+	})
+	return out
+}
+
+// exact returns the login saved for exactly c's URL and username.
+func exact(vp *vaults_domain.VaultPayload, c git_credential_domain.Credential) *vaults_domain.LoginEntry {
+	for _, cand := range candidates(vp, c) {
+		if cand.score == c.Depth() && cand.entry.UserName == c.Username {
+			return cand.entry
+		}
+	}
+	return nil
+}
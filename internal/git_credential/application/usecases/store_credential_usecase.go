@@ -0,0 +1,66 @@
+package git_credential_usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// StoreCredentialUsecase saves a credential git reports as working. An
+// existing login for the same URL and username gets the new password;
+// otherwise a login is created. Either way the vault is marked dirty so
+// the change is committed with the next sync.
+type StoreCredentialUsecase struct {
+	Vault VaultEntries
+	Gate  git_credential_domain.FeatureGate
+}
+
+func NewStoreCredentialUsecase(vault VaultEntries, gate git_credential_domain.FeatureGate) *StoreCredentialUsecase {
+	return &StoreCredentialUsecase{Vault: vault, Gate: gate}
+}
+
+func (uc *StoreCredentialUsecase) Execute(ctx context.Context, userID string, c git_credential_domain.Credential) (*vaults_domain.LoginEntry, error) {
+	vp, err := open(uc.Gate, uc.Vault, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if c.Username == "" || c.Password == "" {
+		return nil, fmt.Errorf("%w: username and password are required", git_credential_domain.ErrInvalidCredential)
+	}
+
+	if existing := exact(vp, c); existing != nil {
+		if existing.Password == c.Password {
+			return existing, nil
+		}
+		updated := *existing
+		updated.Password = c.Password
+		updated.UpdatedAt = time.Now().Format(time.RFC3339)
+		updated.PasswordChangedAt = updated.UpdatedAt
+		if _, err := uc.Vault.UpdateEntryFor(userID, &updated, false); err != nil {
+			return nil, err
+		}
+		uc.Vault.MarkDirty(userID)
+		return &updated, nil
+	}
+
+	entry := &vaults_domain.LoginEntry{
+		UserName: c.Username,
+		Password: c.Password,
+		Website:  c.URL(),
+	}
+	entry.EntryName = strings.TrimPrefix(entry.Website, "https://")
+	entry.Type = vaults_domain.EntryLogin
+	entry.CustomFields = vaults_domain.JSONMap{git_credential_domain.StoredField: true}
+	if _, err := uc.Vault.AddEntryFor(userID, entry); err != nil {
+		return nil, err
+	}
+	uc.Vault.MarkDirty(userID)
+	return entry, nil
+}
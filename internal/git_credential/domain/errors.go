@@ -0,0 +1,15 @@
+package git_credential_domain
+
+import "errors"
+
+var (
+	ErrFeatureDisabled     = errors.New("git credential helper is not included in your subscription")
+	ErrVaultLocked         = errors.New("vault is locked")
+	ErrUnauthorized        = errors.New("git credential request is not authorized")
+	ErrUnsupportedProtocol = errors.New("only https credentials are served")
+	ErrInvalidCredential   = errors.New("invalid git credential request")
+	ErrUnknownOperation    = errors.New("unknown git credential operation")
+	ErrHelperRunning       = errors.New("git credential helper is already running")
+	ErrHelperNotRunning    = errors.New("git credential helper is not running")
+	ErrUserIDRequired      = errors.New("user id is required")
+)
@@ -0,0 +1,178 @@
+package git_credential_domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Operation is the action git asks a credential helper for.
+type Operation string
+
+const (
+	OpGet   Operation = "get"
+	OpStore Operation = "store"
+	OpErase Operation = "erase"
+)
+
+func (o Operation) Valid() bool {
+	switch o {
+	case OpGet, OpStore, OpErase:
+		return true
+	}
+	return false
+}
+
+// StoredField is the LoginEntry custom field marking entries the helper
+// created. Erase only ever trashes those.
+const StoredField = "git_credential"
+
+// Credential is the attribute set of the git credential protocol.
+type Credential struct {
+	Protocol string `json:"protocol,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// ReadCredential parses key=value lines up to a blank line or EOF. A url
+// attribute is split into protocol, host, path and username; unknown
+// attributes are ignored as the protocol requires.
+func ReadCredential(r io.Reader) (Credential, error) {
+	var c Credential
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return c, fmt.Errorf("%w: %q is not key=value", ErrInvalidCredential, line)
+		}
+		switch key {
+		case "protocol":
+			c.Protocol = value
+		case "host":
+			c.Host = value
+		case "path":
+			c.Path = value
+		case "username":
+			c.Username = value
+		case "password":
+			c.Password = value
+		case "url":
+			u, err := url.Parse(value)
+			if err != nil {
+				return c, fmt.Errorf("%w: %v", ErrInvalidCredential, err)
+			}
+			c.Protocol, c.Host, c.Path = u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/")
+			if u.User != nil {
+				c.Username = u.User.Username()
+			}
+		}
+	}
+	return c, sc.Err()
+}
+
+// WriteCredential writes c in the git credential format.
+func WriteCredential(w io.Writer, c Credential) error {
+	for _, kv := range [][2]string{
+		{"protocol", c.Protocol}, {"host", c.Host}, {"path", c.Path},
+		{"username", c.Username}, {"password", c.Password},
+	} {
+		if kv[1] == "" {
+			continue
+		}
+		if strings.ContainsAny(kv[1], "\n\x00") {
+			return fmt.Errorf("%w: %s contains a newline", ErrInvalidCredential, kv[0])
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the request names an https host.
+func (c Credential) Validate() error {
+	if c.Host == "" {
+		return fmt.Errorf("%w: host is required", ErrInvalidCredential)
+	}
+	if !strings.EqualFold(c.Protocol, "https") {
+		return fmt.Errorf("%w: %q", ErrUnsupportedProtocol, c.Protocol)
+	}
+	return nil
+}
+
+// URL is the entry URL a stored credential is saved under.
+func (c Credential) URL() string {
+	u := url.URL{Scheme: "https", Host: strings.ToLower(c.Host), Path: "/" + cleanPath(c.Path)}
+	if u.Path == "/" {
+		u.Path = ""
+	}
+	return u.String()
+}
+
+// Depth is the number of path segments in the request.
+func (c Credential) Depth() int { return len(segments(c.Path)) }
+
+// Match scores an entry URL against a request: -1 when it does not apply,
+// otherwise the number of path segments it pins, so the most specific
+// entry wins. Entry URLs without a scheme are taken as https; the host,
+// port included, must be equal. When git sends a path (credential.useHttpPath)
+// the entry path must be a prefix of it on segment boundaries; without
+// one, only host-wide entries match.
+func Match(entryURL string, c Credential) int {
+	entryURL = strings.TrimSpace(entryURL)
+	if entryURL == "" {
+		return -1
+	}
+	if !strings.Contains(entryURL, "://") {
+		entryURL = "https://" + entryURL
+	}
+	u, err := url.Parse(entryURL)
+	if err != nil || !strings.EqualFold(u.Scheme, c.Protocol) || !strings.EqualFold(u.Host, c.Host) {
+		return -1
+	}
+	entry := segments(u.Path)
+	want := segments(c.Path)
+	if len(entry) > len(want) {
+		return -1
+	}
+	for i := range entry {
+		if entry[i] != want[i] {
+			return -1
+		}
+	}
+	return len(entry)
+}
+
+// cleanPath drops surrounding slashes and a ".git" suffix, which git adds
+// or leaves out depending on how the remote was written.
+func cleanPath(p string) string {
+	p = strings.Trim(p, "/")
+	return strings.TrimSuffix(p, ".git")
+}
+
+func segments(p string) []string {
+	p = cleanPath(p)
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// FeatureGate tells whether the user's subscription includes the git
+// credential helper (FeatureFlags.GitCLIEnabled).
+type FeatureGate interface {
+	GitCLIEnabled(userID string) (bool, error)
+}
+
+// FeatureGateFunc adapts a function to FeatureGate.
+type FeatureGateFunc func(userID string) (bool, error)
+
+func (f FeatureGateFunc) GitCLIEnabled(userID string) (bool, error) { return f(userID) }
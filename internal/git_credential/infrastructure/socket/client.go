@@ -0,0 +1,69 @@
+package git_credential_socket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+)
+
+// knownErrors are the failures a client can tell apart across the socket.
+var knownErrors = []error{
+	git_credential_domain.ErrFeatureDisabled,
+	git_credential_domain.ErrVaultLocked,
+	git_credential_domain.ErrUnauthorized,
+	git_credential_domain.ErrUnsupportedProtocol,
+	git_credential_domain.ErrInvalidCredential,
+	git_credential_domain.ErrUnknownOperation,
+}
+
+// Call sends one operation to the helper listening on socketPath,
+// authenticating with the token published next to it.
+func Call(ctx context.Context, socketPath string, op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+	token, err := os.ReadFile(TokenPath(socketPath))
+	if os.IsNotExist(err) {
+		return nil, git_credential_domain.ErrHelperNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", git_credential_domain.ErrHelperNotRunning, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := request{Token: strings.TrimSpace(string(token)), Op: op, Credential: c}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, remoteError(resp.Error)
+	}
+	return resp.Credential, nil
+}
+
+func remoteError(msg string) error {
+	for _, known := range knownErrors {
+		if msg == known.Error() {
+			return known
+		}
+		if strings.HasPrefix(msg, known.Error()+": ") {
+			return fmt.Errorf("%w%s", known, strings.TrimPrefix(msg, known.Error()))
+		}
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,171 @@
+package git_credential_socket
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	shared_localsocket "vault-app/internal/shared/localsocket"
+)
+
+// requestTimeout bounds one helper round trip; git waits on it.
+const requestTimeout = 10 * time.Second
+
+// DefaultSocketPath is $XDG_RUNTIME_DIR/vaultcore/git-credential.sock,
+// falling back to ~/.vaultcore/git-credential.sock.
+func DefaultSocketPath() (string, error) {
+	return shared_localsocket.DefaultPath("git-credential.sock")
+}
+
+// TokenPath is where the server publishes the token clients of socketPath
+// must present.
+func TokenPath(socketPath string) string {
+	return strings.TrimSuffix(socketPath, ".sock") + ".token"
+}
+
+// Handler answers one helper operation.
+type Handler interface {
+	Handle(ctx context.Context, op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error)
+}
+
+type request struct {
+	Token      string                           `json:"token"`
+	Op         git_credential_domain.Operation  `json:"op"`
+	Credential git_credential_domain.Credential `json:"credential"`
+}
+
+type response struct {
+	Credential *git_credential_domain.Credential `json:"credential,omitempty"`
+	Error      string                            `json:"error,omitempty"`
+}
+
+// Server answers helper requests on a Unix socket. Each connection carries
+// one JSON request and its response. Besides the socket being private to
+// the user, every request must carry the token written next to it, which
+// lives only as long as the server.
+type Server struct {
+	Handler Handler
+
+	mu       sync.Mutex
+	serve    sync.Mutex
+	listener net.Listener
+	path     string
+	token    string
+	wg       sync.WaitGroup
+}
+
+func NewServer(handler Handler) *Server {
+	return &Server{Handler: handler}
+}
+
+// Listen creates the socket and token file, readable by the current user
+// only, and starts accepting clients in the background.
+func (s *Server) Listen(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return git_credential_domain.ErrHelperRunning
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+	l, err := shared_localsocket.Listen(path, git_credential_domain.ErrHelperRunning)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(TokenPath(path), []byte(token), 0o600); err != nil {
+		l.Close()
+		return err
+	}
+	s.listener, s.path, s.token = l, path, token
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serveConn(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: git_credential_domain.ErrInvalidCredential.Error()})
+		return
+	}
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+	if token == "" || subtle.ConstantTimeCompare([]byte(req.Token), []byte(token)) != 1 {
+		_ = json.NewEncoder(conn).Encode(response{Error: git_credential_domain.ErrUnauthorized.Error()})
+		return
+	}
+
+	// Requests touch the vault session; run them one at a time.
+	s.serve.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	cred, err := s.Handler.Handle(ctx, req.Op, req.Credential)
+	cancel()
+	s.serve.Unlock()
+
+	resp := response{Credential: cred}
+	if err != nil {
+		resp = response{Error: err.Error()}
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener != nil
+}
+
+func (s *Server) SocketPath() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.path
+}
+
+// Close stops accepting clients and removes the socket and token file.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	l, path := s.listener, s.path
+	s.listener, s.path, s.token = nil, "", ""
+	s.mu.Unlock()
+
+	if l == nil {
+		return git_credential_domain.ErrHelperNotRunning
+	}
+	err := l.Close()
+	s.wg.Wait()
+	for _, p := range []string{path, TokenPath(path)} {
+		if rmErr := os.Remove(p); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package git_credential_tests
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_socket "vault-app/internal/git_credential/infrastructure/socket"
+)
+
+type handlerFunc func(op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error)
+
+func (f handlerFunc) Handle(_ context.Context, op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+	return f(op, c)
+}
+
+func socketPath(t *testing.T) string {
+	t.Helper()
+	// Unix socket paths are short; t.TempDir can exceed the limit.
+	dir, err := os.MkdirTemp("", "gc")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "run", "git-credential.sock")
+}
+
+func TestServer_RoundTrip(t *testing.T) {
+	var seen []git_credential_domain.Operation
+	server := git_credential_socket.NewServer(handlerFunc(func(op git_credential_domain.Operation, c git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+		seen = append(seen, op)
+		switch op {
+		case git_credential_domain.OpGet:
+			c.Username, c.Password = "ci", "s3cret"
+			return &c, nil
+		case git_credential_domain.OpErase:
+			return nil, git_credential_domain.ErrFeatureDisabled
+		}
+		return nil, nil
+	}))
+	path := socketPath(t)
+	require.NoError(t, server.Listen(path))
+	defer server.Close()
+
+	for _, p := range []string{path, git_credential_socket.TokenPath(path)} {
+		fi, err := os.Stat(p)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+
+	ctx := context.Background()
+	req := git_credential_domain.Credential{Protocol: "https", Host: "git.example.com"}
+	res, err := git_credential_socket.Call(ctx, path, git_credential_domain.OpGet, req)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", res.Password)
+
+	res, err = git_credential_socket.Call(ctx, path, git_credential_domain.OpStore, req)
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	_, err = git_credential_socket.Call(ctx, path, git_credential_domain.OpErase, req)
+	require.ErrorIs(t, err, git_credential_domain.ErrFeatureDisabled)
+	require.Equal(t, []git_credential_domain.Operation{"get", "store", "erase"}, seen)
+
+	require.NoError(t, server.Close())
+	_, err = os.Stat(git_credential_socket.TokenPath(path))
+	require.True(t, os.IsNotExist(err))
+	_, err = git_credential_socket.Call(ctx, path, git_credential_domain.OpGet, req)
+	require.ErrorIs(t, err, git_credential_domain.ErrHelperNotRunning)
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	called := false
+	server := git_credential_socket.NewServer(handlerFunc(func(git_credential_domain.Operation, git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+		called = true
+		return nil, nil
+	}))
+	path := socketPath(t)
+	require.NoError(t, server.Listen(path))
+	defer server.Close()
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, json.NewEncoder(conn).Encode(map[string]any{
+		"token": "not-the-token",
+		"op":    "get",
+		"credential": map[string]string{
+			"protocol": "https",
+			"host":     "git.example.com",
+		},
+	}))
+	var resp struct {
+		Error string `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(conn).Decode(&resp))
+	require.Equal(t, git_credential_domain.ErrUnauthorized.Error(), resp.Error)
+	require.False(t, called)
+}
+
+func TestServer_ReplacesStaleSocketButNotLiveOne(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	noop := handlerFunc(func(git_credential_domain.Operation, git_credential_domain.Credential) (*git_credential_domain.Credential, error) {
+		return nil, nil
+	})
+	require.ErrorIs(t, git_credential_socket.NewServer(noop).Listen(path), git_credential_domain.ErrHelperRunning)
+
+	require.NoError(t, l.Close())
+	server := git_credential_socket.NewServer(noop)
+	require.NoError(t, server.Listen(path))
+	require.True(t, server.Running())
+	require.ErrorIs(t, server.Listen(path), git_credential_domain.ErrHelperRunning)
+	require.NoError(t, server.Close())
+	require.ErrorIs(t, server.Close(), git_credential_domain.ErrHelperNotRunning)
+}
@@ -0,0 +1,90 @@
+package git_credential_ui
+
+import (
+	"fmt"
+	"sync"
+
+	git_credential_usecases "vault-app/internal/git_credential/application/usecases"
+	git_credential_domain "vault-app/internal/git_credential/domain"
+	git_credential_socket "vault-app/internal/git_credential/infrastructure/socket"
+)
+
+// HelperStatus is what the settings screen shows about the helper.
+type HelperStatus struct {
+	Running    bool   `json:"running"`
+	SocketPath string `json:"socket_path,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+}
+
+// GitCredentialHandler runs one helper socket at a time, answering with
+// the logins of the user who started it.
+type GitCredentialHandler struct {
+	vault git_credential_usecases.VaultEntries
+
+	mu     sync.Mutex
+	server *git_credential_socket.Server
+	userID string
+}
+
+func NewGitCredentialHandler(vault git_credential_usecases.VaultEntries) *GitCredentialHandler {
+	return &GitCredentialHandler{vault: vault}
+}
+
+// Start serves userID's logins on socketPath, or on the default path when
+// empty, and returns the path the git-credential-dvault helper dials. The
+// gate is checked up front and again on every request, so a downgraded
+// subscription stops the helper from answering.
+func (h *GitCredentialHandler) Start(userID string, gate git_credential_domain.FeatureGate, socketPath string) (string, error) {
+	if h.vault == nil {
+		return "", fmt.Errorf("git credential vault session is not initialized")
+	}
+	if userID == "" {
+		return "", git_credential_domain.ErrUserIDRequired
+	}
+	enabled, err := gate.GitCLIEnabled(userID)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "", git_credential_domain.ErrFeatureDisabled
+	}
+	if socketPath == "" {
+		path, err := git_credential_socket.DefaultSocketPath()
+		if err != nil {
+			return "", err
+		}
+		socketPath = path
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server != nil {
+		return "", git_credential_domain.ErrHelperRunning
+	}
+	server := git_credential_socket.NewServer(git_credential_usecases.NewCredentialHelper(userID, h.vault, gate))
+	if err := server.Listen(socketPath); err != nil {
+		return "", err
+	}
+	h.server, h.userID = server, userID
+	return socketPath, nil
+}
+
+func (h *GitCredentialHandler) Stop() error {
+	h.mu.Lock()
+	server := h.server
+	h.server, h.userID = nil, ""
+	h.mu.Unlock()
+	if server == nil {
+		return git_credential_domain.ErrHelperNotRunning
+	}
+	return server.Close()
+}
+
+func (h *GitCredentialHandler) Status() HelperStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server == nil {
+		return HelperStatus{}
+	}
+	return HelperStatus{Running: true, SocketPath: h.server.SocketPath(), UserID: h.userID}
+}
@@ -20,6 +20,7 @@ import (
 	"vault-app/internal/services"
 	"vault-app/internal/tracecore"
 	tracecore_models "vault-app/internal/tracecore/models"
+	tracecore_rules "vault-app/internal/tracecore/rules"
 	utils "vault-app/internal/utils"
 	vault_session "vault-app/internal/vault/application/session"
 	vaults_domain "vault-app/internal/vault/domain"
@@ -1039,14 +1040,7 @@ func (vh *VaultHandler) PrepareTracecoreEnvelope(userID string, entry vaults_dom
 		return nil, nil // not enabled -> nothing to do
 	}
 
-	// prepare actor signature (from session user stellar private key)
 	userCfg := session.VaultRuntimeContext.CurrentUser
-	msgToSign := fmt.Sprintf("user-%d:%s:%d", userID, entry.GetTypeName(), time.Now().UnixNano())
-
-	actorSig, err := blockchain.SignActorWithStellarPrivateKey(userCfg.StellarAccount.PrivateKey, msgToSign)
-	if err != nil {
-		return nil, fmt.Errorf("❌ failed to sign actor: %w", err)
-	}
 
 	log.Printf("🔍 Options in PrepareTracecoreEnvelope: %+v", options)
 	// Create a factory for creating/updating... a post entry payload
@@ -1082,20 +1076,46 @@ func (vh *VaultHandler) PrepareTracecoreEnvelope(userID string, entry vaults_dom
 		vh.logger.Error("❌ Failed to create commit tracecore payload: %v", err)
 		return nil, fmt.Errorf("❌ failed to create commit tracecore payload - %s: %w", services.CREATE_ENTRY, err)
 	}
+	// the role is the one template rules name (user, signer, reviewer...)
+	actorRole := userCfg.Role
+	if actorRole == "" {
+		actorRole = "end_user"
+	}
 	payloadMetadata.Actor = tracecore_models.Actor{
-		ID:        userCfg.ID,
-		Role:      "end_user",
-		Signature: actorSig,
+		ID:   userCfg.ID,
+		Role: actorRole,
 	}
-	payloadMetadata.Signature = actorSig
-	// ✅ Removed noisy fmt.Printf - use logger instead if needed
 
 	// Build payload
+	rules := tracecore_rules.FromCommitRules(appCfg.CommitRules)
 	tracePayload := tracecore_models.CommitPayload{
 		RepoID:          appCfg.RepoID,
 		Branch:          session.VaultRuntimeContext.AppSettings.Branch,
 		Metadata:        *payloadMetadata,
-		ValidationRules: []string{"REQUIRES_SIGNATURE", "VALID_ACTORS_ONLY"}, // extractRuleNames(appCfg.CommitRules), // adapt to your types
+		ValidationRules: tracecore_rules.ValidationRuleNames(rules),
+	}
+
+	// actor signature over the canonical payload (session user stellar private key)
+	actorSig, err := blockchain.SignCommitAsActor(userCfg.StellarAccount.PrivateKey, tracePayload)
+	if err != nil {
+		return nil, fmt.Errorf("❌ failed to sign actor: %w", err)
+	}
+	tracePayload.Metadata.Actor.Signature = actorSig
+	tracePayload.Metadata.Signature = actorSig
+
+	// evaluate commit rules locally once the actor has signed (signature
+	// rules check it) and before the dvault signature; Tracecore re-checks them
+	engine := tracecore_rules.NewEngine(rules, appCfg.Actors)
+	if vh.TracecoreClient != nil && vh.Ctx != nil {
+		actors, err := vh.TracecoreClient.ListRepoActors(vh.Ctx, appCfg.RepoID)
+		if err != nil {
+			vh.logger.Warn("⚠️ Repo actors unavailable, reviewer roles are left to Tracecore: %v", err)
+		} else {
+			engine.WithRepoActors(actors)
+		}
+	}
+	if _, err := engine.Enforce(tracePayload); err != nil {
+		return nil, fmt.Errorf("❌ commit blocked: %w", err)
 	}
 
 	// sign the payload with dvault private key (server-side signing)
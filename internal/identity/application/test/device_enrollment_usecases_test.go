@@ -0,0 +1,222 @@
+package identity_usecase_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	identity_usecase "vault-app/internal/identity/application/usecase"
+	identity_domain "vault-app/internal/identity/domain"
+	identity_persistence "vault-app/internal/identity/infrastructure/persistence"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type enrollmentFixture struct {
+	repo        *identity_persistence.MemoryDeviceRepository
+	enrollments *identity_persistence.MemoryDeviceEnrollmentRepository
+	enroll      *identity_usecase.EnrollDeviceUseCase
+	approve     *identity_usecase.ApproveDeviceEnrollmentUseCase
+}
+
+func newEnrollmentFixture(policy identity_usecase.StaticDevicePolicy) *enrollmentFixture {
+	repo := identity_persistence.NewMemoryDeviceRepository()
+	enrollments := identity_persistence.NewMemoryDeviceEnrollmentRepository()
+	return &enrollmentFixture{
+		repo:        repo,
+		enrollments: enrollments,
+		enroll:      identity_usecase.NewEnrollDeviceUseCase(repo, enrollments, policy, nil),
+		approve:     identity_usecase.NewApproveDeviceEnrollmentUseCase(repo, enrollments, policy, nil),
+	}
+}
+
+func (f *enrollmentFixture) enrollDevice(t *testing.T, vaultID, pub string) *identity_usecase.EnrollDeviceResponse {
+	t.Helper()
+	resp, err := f.enroll.Execute(context.Background(), identity_usecase.EnrollDeviceRequest{
+		VaultID:   vaultID,
+		PublicKey: pub,
+		KeyType:   identity_domain.DeviceKeyTypeEd25519,
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func newDeviceKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(pub), priv
+}
+
+func signApproval(priv ed25519.PrivateKey, challenge *identity_usecase.EnrollmentChallenge, code, pub string) string {
+	msg := identity_domain.EnrollmentApprovalMessage(challenge.EnrollmentID, code, pub)
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(priv, msg))
+}
+
+func TestEnrollDevice_FirstDeviceIsTrusted(t *testing.T) {
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{MaxDevices: 3, RequireDeviceApproval: true})
+
+	resp := f.enrollDevice(t, "vault-1", "pub-1")
+	assert.Nil(t, resp.Challenge)
+	assert.True(t, resp.Device.IsActive())
+}
+
+func TestEnrollDevice_ApprovalByTrustedDevice(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{MaxDevices: 3, RequireDeviceApproval: true})
+	firstPub, firstKey := newDeviceKey(t)
+	first := f.enrollDevice(t, "vault-1", firstPub).Device
+
+	resp := f.enrollDevice(t, "vault-1", "pub-2")
+	require.NotNil(t, resp.Challenge)
+	assert.True(t, resp.Device.IsPending())
+	assert.Contains(t, resp.Challenge.QRPayload, resp.Challenge.Code)
+	code := resp.Challenge.Code
+
+	// A device from another vault cannot vouch for this one.
+	otherPub, otherKey := newDeviceKey(t)
+	other := f.enrollDevice(t, "vault-2", otherPub).Device
+	_, err := f.approve.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+		VaultID:          "vault-1",
+		EnrollmentID:     resp.Challenge.EnrollmentID,
+		Code:             code,
+		ApproverDeviceID: other.ID,
+		Signature:        signApproval(otherKey, resp.Challenge, code, "pub-2"),
+	})
+	assert.ErrorIs(t, err, identity_domain.ErrApproverNotTrusted)
+
+	// Naming a trusted device is not enough: the approval must carry its
+	// signature.
+	for _, sig := range []string{"", signApproval(otherKey, resp.Challenge, code, "pub-2"), signApproval(firstKey, resp.Challenge, code, "pub-other")} {
+		_, err = f.approve.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+			VaultID:          "vault-1",
+			EnrollmentID:     resp.Challenge.EnrollmentID,
+			Code:             code,
+			ApproverDeviceID: first.ID,
+			Signature:        sig,
+		})
+		assert.ErrorIs(t, err, identity_domain.ErrDeviceSignatureInvalid)
+	}
+	stored, err := f.enrollments.FindByID(ctx, resp.Challenge.EnrollmentID)
+	require.NoError(t, err)
+	assert.Equal(t, identity_domain.EnrollmentStatusPending, stored.Status)
+
+	_, err = f.approve.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+		VaultID:          "vault-1",
+		EnrollmentID:     resp.Challenge.EnrollmentID,
+		Code:             "WRONG",
+		ApproverDeviceID: first.ID,
+		Signature:        signApproval(firstKey, resp.Challenge, "WRONG", "pub-2"),
+	})
+	assert.ErrorIs(t, err, identity_domain.ErrEnrollmentCodeMismatch)
+	stored, err = f.enrollments.FindByID(ctx, resp.Challenge.EnrollmentID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Attempts)
+
+	dev, err := f.approve.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+		VaultID:          "vault-1",
+		EnrollmentID:     resp.Challenge.EnrollmentID,
+		Code:             code,
+		ApproverDeviceID: first.ID,
+		Signature:        signApproval(firstKey, resp.Challenge, code, "pub-2"),
+	})
+	require.NoError(t, err)
+	assert.True(t, dev.IsActive())
+}
+
+func TestEnrollDevice_MaxDevices(t *testing.T) {
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{MaxDevices: 1})
+	f.enrollDevice(t, "vault-1", "pub-1")
+
+	_, err := f.enroll.Execute(context.Background(), identity_usecase.EnrollDeviceRequest{
+		VaultID:   "vault-1",
+		PublicKey: "pub-2",
+		KeyType:   identity_domain.DeviceKeyTypeEd25519,
+	})
+	assert.ErrorIs(t, err, identity_domain.ErrDeviceLimitReached)
+}
+
+func TestEnrollDevice_NoApprovalRequired(t *testing.T) {
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{})
+	f.enrollDevice(t, "vault-1", "pub-1")
+
+	resp := f.enrollDevice(t, "vault-1", "pub-2")
+	assert.Nil(t, resp.Challenge)
+	assert.True(t, resp.Device.IsActive())
+}
+
+func TestListDevices_MostRecentlySeenFirst(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{})
+	a := f.enrollDevice(t, "vault-1", "pub-a").Device
+	b := f.enrollDevice(t, "vault-1", "pub-b").Device
+
+	touch := identity_usecase.NewTouchDeviceUseCase(f.repo)
+	require.NoError(t, touch.Execute(ctx, identity_usecase.TouchDeviceRequest{VaultID: "vault-1", DeviceID: b.ID}))
+	time.Sleep(time.Millisecond)
+	require.NoError(t, touch.Execute(ctx, identity_usecase.TouchDeviceRequest{VaultID: "vault-1", DeviceID: a.ID}))
+
+	devices, err := identity_usecase.NewListDevicesUseCase(f.repo).Execute(ctx, "vault-1")
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, a.ID, devices[0].ID)
+	require.NotNil(t, devices[0].LastSeenAt)
+}
+
+func TestTouchDevice_OtherVault(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{})
+	dev := f.enrollDevice(t, "vault-1", "pub-a").Device
+
+	touch := identity_usecase.NewTouchDeviceUseCase(f.repo)
+	err := touch.Execute(ctx, identity_usecase.TouchDeviceRequest{VaultID: "vault-2", DeviceID: dev.ID})
+	assert.ErrorIs(t, err, identity_domain.ErrDeviceNotFound)
+
+	stored, err := f.repo.FindByID(ctx, dev.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.LastSeenAt)
+}
+
+func TestEnrollDevice_RevokedDeviceStillRequiresApproval(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{MaxDevices: 3, RequireDeviceApproval: true})
+	first := f.enrollDevice(t, "vault-1", "pub-1").Device
+	require.NoError(t, identity_usecase.NewRevokeDeviceUseCase(f.repo, nil).Execute(ctx, first.ID))
+
+	// The vault is known even without an active device, so the next one is
+	// not trusted on its own word.
+	resp := f.enrollDevice(t, "vault-1", "pub-2")
+	require.NotNil(t, resp.Challenge)
+	assert.True(t, resp.Device.IsPending())
+}
+
+func TestApproveDeviceEnrollment_SignApproval(t *testing.T) {
+	ctx := context.Background()
+	f := newEnrollmentFixture(identity_usecase.StaticDevicePolicy{MaxDevices: 3, RequireDeviceApproval: true})
+	firstPub, firstKey := newDeviceKey(t)
+	first := f.enrollDevice(t, "vault-1", firstPub).Device
+	resp := f.enrollDevice(t, "vault-1", "pub-2")
+	require.NotNil(t, resp.Challenge)
+
+	sign := func(msg []byte) ([]byte, error) { return ed25519.Sign(firstKey, msg), nil }
+	_, err := f.approve.SignApproval(ctx, "vault-2", resp.Challenge.EnrollmentID, resp.Challenge.Code, sign)
+	assert.ErrorIs(t, err, identity_domain.ErrEnrollmentNotFound)
+
+	signature, err := f.approve.SignApproval(ctx, "vault-1", resp.Challenge.EnrollmentID, resp.Challenge.Code, sign)
+	require.NoError(t, err)
+	assert.Equal(t, signApproval(firstKey, resp.Challenge, resp.Challenge.Code, "pub-2"), signature)
+
+	dev, err := f.approve.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+		VaultID:          "vault-1",
+		EnrollmentID:     resp.Challenge.EnrollmentID,
+		Code:             resp.Challenge.Code,
+		ApproverDeviceID: first.ID,
+		Signature:        signature,
+	})
+	require.NoError(t, err)
+	assert.True(t, dev.IsActive())
+}
@@ -0,0 +1,352 @@
+package identity_usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"time"
+
+	app_config_domain "vault-app/internal/config/domain"
+	identity_eventbus "vault-app/internal/identity/application"
+	identity_domain "vault-app/internal/identity/domain"
+)
+
+// DevicePolicySource resolves the device policy that applies to a vault.
+type DevicePolicySource interface {
+	DevicePolicy(ctx context.Context, vaultID string) (app_config_domain.DevicePolicy, error)
+}
+
+// StaticDevicePolicy applies the same policy to every vault.
+type StaticDevicePolicy app_config_domain.DevicePolicy
+
+func (p StaticDevicePolicy) DevicePolicy(ctx context.Context, vaultID string) (app_config_domain.DevicePolicy, error) {
+	return app_config_domain.DevicePolicy(p), nil
+}
+
+type EnrollDeviceRequest struct {
+	VaultID   string
+	PublicKey string
+	KeyType   string
+	Name      string
+}
+
+// EnrollmentChallenge is shown on the new device: as a QR code (QRPayload)
+// or as the short Code typed into an already-trusted device.
+type EnrollmentChallenge struct {
+	EnrollmentID string    `json:"enrollment_id"`
+	Code         string    `json:"code"`
+	Fingerprint  string    `json:"fingerprint"`
+	QRPayload    string    `json:"qr_payload"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type EnrollDeviceResponse struct {
+	Device *identity_domain.Device `json:"device"`
+	// Challenge is nil when the device was trusted straight away: the vault's
+	// first device, or a policy that does not require approval.
+	Challenge *EnrollmentChallenge `json:"challenge,omitempty"`
+}
+
+type EnrollDeviceUseCase struct {
+	repo        identity_domain.DeviceRepository
+	enrollments identity_domain.DeviceEnrollmentRepository
+	policy      DevicePolicySource
+	bus         identity_eventbus.EventBus
+	ttl         time.Duration
+}
+
+func NewEnrollDeviceUseCase(
+	repo identity_domain.DeviceRepository,
+	enrollments identity_domain.DeviceEnrollmentRepository,
+	policy DevicePolicySource,
+	bus identity_eventbus.EventBus,
+) *EnrollDeviceUseCase {
+	return &EnrollDeviceUseCase{
+		repo:        repo,
+		enrollments: enrollments,
+		policy:      policy,
+		bus:         bus,
+		ttl:         identity_domain.DefaultEnrollmentTTL,
+	}
+}
+
+func (uc *EnrollDeviceUseCase) ValidateDependencies() error {
+	if uc.repo == nil || uc.enrollments == nil {
+		return errors.New("device repositories are required")
+	}
+	if uc.policy == nil {
+		return errors.New("device policy source is required")
+	}
+	return nil
+}
+
+func (uc *EnrollDeviceUseCase) Execute(ctx context.Context, req EnrollDeviceRequest) (*EnrollDeviceResponse, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+
+	dev, err := identity_domain.NewPendingDevice(req.VaultID, req.PublicKey, req.KeyType, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := uc.policy.DevicePolicy(ctx, req.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := uc.repo.ListByVaultID(ctx, req.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	if policy.MaxDevices > 0 && countActive(devices) >= policy.MaxDevices {
+		return nil, identity_domain.ErrDeviceLimitReached
+	}
+
+	// Nothing can vouch for the vault's first device, so it is trusted as
+	// enrolled. Any other device, even a pending or revoked one, means the
+	// vault already exists somewhere and the new one has to be approved.
+	if len(devices) == 0 || !policy.RequireDeviceApproval {
+		if err := dev.Approve(); err != nil {
+			return nil, err
+		}
+		if err := uc.repo.Save(ctx, dev); err != nil {
+			return nil, err
+		}
+		publishDeviceCreated(ctx, uc.bus, dev)
+		return &EnrollDeviceResponse{Device: dev}, nil
+	}
+
+	enrollment, code, err := identity_domain.NewDeviceEnrollment(dev.VaultID, dev.ID, uc.ttl)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Save(ctx, dev); err != nil {
+		return nil, err
+	}
+	if err := uc.enrollments.Save(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return &EnrollDeviceResponse{
+		Device: dev,
+		Challenge: &EnrollmentChallenge{
+			EnrollmentID: enrollment.ID,
+			Code:         code,
+			Fingerprint:  identity_domain.PublicKeyFingerprint(dev.PublicKey),
+			QRPayload:    identity_domain.EnrollmentQRPayload(enrollment.ID, code, dev.PublicKey),
+			ExpiresAt:    enrollment.ExpiresAt,
+		},
+	}, nil
+}
+
+type ApproveDeviceEnrollmentRequest struct {
+	VaultID          string
+	EnrollmentID     string
+	Code             string
+	ApproverDeviceID string
+	// Signature is the approver device's signature (base64) of
+	// identity_domain.EnrollmentApprovalMessage.
+	Signature string
+}
+
+type ApproveDeviceEnrollmentUseCase struct {
+	repo        identity_domain.DeviceRepository
+	enrollments identity_domain.DeviceEnrollmentRepository
+	policy      DevicePolicySource
+	bus         identity_eventbus.EventBus
+}
+
+func NewApproveDeviceEnrollmentUseCase(
+	repo identity_domain.DeviceRepository,
+	enrollments identity_domain.DeviceEnrollmentRepository,
+	policy DevicePolicySource,
+	bus identity_eventbus.EventBus,
+) *ApproveDeviceEnrollmentUseCase {
+	return &ApproveDeviceEnrollmentUseCase{
+		repo:        repo,
+		enrollments: enrollments,
+		policy:      policy,
+		bus:         bus,
+	}
+}
+
+func (uc *ApproveDeviceEnrollmentUseCase) ValidateDependencies() error {
+	if uc.repo == nil || uc.enrollments == nil {
+		return errors.New("device repositories are required")
+	}
+	if uc.policy == nil {
+		return errors.New("device policy source is required")
+	}
+	return nil
+}
+
+// Execute activates the enrolled device once an active device of the same
+// vault presents the right code, signed with its own key. The returned
+// device is ready to receive trust group envelopes.
+func (uc *ApproveDeviceEnrollmentUseCase) Execute(ctx context.Context, req ApproveDeviceEnrollmentRequest) (*identity_domain.Device, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if req.EnrollmentID == "" {
+		return nil, identity_domain.ErrEnrollmentNotFound
+	}
+
+	approver, err := uc.repo.FindByID(ctx, req.ApproverDeviceID)
+	if err != nil || approver == nil || !approver.IsActive() || approver.VaultID != req.VaultID {
+		return nil, identity_domain.ErrApproverNotTrusted
+	}
+
+	enrollment, err := uc.enrollments.FindByID(ctx, req.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.VaultID != req.VaultID {
+		return nil, identity_domain.ErrEnrollmentNotFound
+	}
+
+	dev, err := uc.repo.FindByID(ctx, enrollment.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !dev.IsPending() {
+		return nil, identity_domain.ErrDeviceNotPending
+	}
+	message := identity_domain.EnrollmentApprovalMessage(enrollment.ID, req.Code, dev.PublicKey)
+	if err := approver.VerifySignature(message, req.Signature); err != nil {
+		return nil, err
+	}
+	policy, err := uc.policy.DevicePolicy(ctx, req.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	active, err := activeDeviceCount(ctx, uc.repo, req.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	if policy.MaxDevices > 0 && active >= policy.MaxDevices {
+		return nil, identity_domain.ErrDeviceLimitReached
+	}
+
+	if err := enrollment.Approve(req.Code, approver.ID, time.Now()); err != nil {
+		// Attempts and expiry are part of the enrollment's state.
+		_ = uc.enrollments.Update(ctx, enrollment)
+		return nil, err
+	}
+	if err := dev.Approve(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, dev); err != nil {
+		return nil, err
+	}
+	if err := uc.enrollments.Update(ctx, enrollment); err != nil {
+		return nil, err
+	}
+	publishDeviceCreated(ctx, uc.bus, dev)
+
+	return dev, nil
+}
+
+// SignApproval builds the approval message of an enrollment of vaultID and
+// signs it with sign, returning the base64 signature Execute expects.
+func (uc *ApproveDeviceEnrollmentUseCase) SignApproval(ctx context.Context, vaultID, enrollmentID, code string, sign func([]byte) ([]byte, error)) (string, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return "", err
+	}
+	if enrollmentID == "" {
+		return "", identity_domain.ErrEnrollmentNotFound
+	}
+	enrollment, err := uc.enrollments.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return "", err
+	}
+	if enrollment.VaultID != vaultID {
+		return "", identity_domain.ErrEnrollmentNotFound
+	}
+	dev, err := uc.repo.FindByID(ctx, enrollment.DeviceID)
+	if err != nil {
+		return "", err
+	}
+	sig, err := sign(identity_domain.EnrollmentApprovalMessage(enrollment.ID, code, dev.PublicKey))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+type TouchDeviceRequest struct {
+	VaultID  string
+	DeviceID string
+}
+
+type TouchDeviceUseCase struct {
+	repo identity_domain.DeviceRepository
+}
+
+func NewTouchDeviceUseCase(repo identity_domain.DeviceRepository) *TouchDeviceUseCase {
+	return &TouchDeviceUseCase{repo: repo}
+}
+
+// Execute records that the device was seen now. Revoked devices and devices
+// of another vault are not updated.
+func (uc *TouchDeviceUseCase) Execute(ctx context.Context, req TouchDeviceRequest) error {
+	if req.DeviceID == "" {
+		return identity_domain.ErrDeviceNotFound
+	}
+	dev, err := uc.repo.FindByID(ctx, req.DeviceID)
+	if err != nil {
+		return err
+	}
+	if dev == nil || dev.VaultID != req.VaultID || dev.Status == identity_domain.DeviceStatusRevoked {
+		return identity_domain.ErrDeviceNotFound
+	}
+	dev.Touch(time.Now())
+	return uc.repo.Update(ctx, dev)
+}
+
+// sortDevicesByLastSeen orders devices most recently seen first; devices
+// never seen fall back to their creation time.
+func sortDevicesByLastSeen(devices []*identity_domain.Device) {
+	seen := func(d *identity_domain.Device) time.Time {
+		if d.LastSeenAt != nil {
+			return *d.LastSeenAt
+		}
+		return d.CreatedAt
+	}
+	sort.SliceStable(devices, func(i, j int) bool {
+		return seen(devices[i]).After(seen(devices[j]))
+	})
+}
+
+func activeDeviceCount(ctx context.Context, repo identity_domain.DeviceRepository, vaultID string) (int, error) {
+	devices, err := repo.ListByVaultID(ctx, vaultID)
+	if err != nil {
+		return 0, err
+	}
+	return countActive(devices), nil
+}
+
+func countActive(devices []*identity_domain.Device) int {
+	count := 0
+	for _, d := range devices {
+		if d.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
+func publishDeviceCreated(ctx context.Context, bus identity_eventbus.EventBus, dev *identity_domain.Device) {
+	if bus == nil {
+		return
+	}
+	domainEvt := identity_domain.NewDeviceCreated(dev)
+	_ = bus.PublishDeviceCreated(ctx, identity_eventbus.DeviceCreated{
+		DeviceID:   domainEvt.DeviceID,
+		VaultID:    domainEvt.VaultID,
+		PublicKey:  domainEvt.PublicKey,
+		KeyType:    domainEvt.KeyType,
+		OccurredAt: domainEvt.OccurredAt,
+	})
+}
@@ -76,7 +76,12 @@ func (uc *ListDevicesUseCase) Execute(ctx context.Context, vaultID string) ([]*i
 	if vaultID == "" {
 		return nil, identity_domain.ErrDeviceVaultIDRequired
 	}
-	return uc.repo.ListByVaultID(ctx, vaultID)
+	devices, err := uc.repo.ListByVaultID(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	sortDevicesByLastSeen(devices)
+	return devices, nil
 }
 
 type RevokeDeviceUseCase struct {
@@ -1,6 +1,9 @@
 package identity_domain
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +12,9 @@ import (
 const (
 	DeviceStatusActive  = "active"
 	DeviceStatusRevoked = "revoked"
+	// DeviceStatusPending marks an enrolled device still waiting for a
+	// trusted device to approve it.
+	DeviceStatusPending = "pending"
 )
 
 const (
@@ -25,6 +31,9 @@ type Device struct {
 	Status    string     `json:"status" gorm:"not null"`
 	CreatedAt time.Time  `json:"created_at" gorm:"not null"`
 	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+
+	Name       string     `json:"name,omitempty"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 }
 
 func (Device) TableName() string {
@@ -57,8 +66,58 @@ func (d *Device) IsActive() bool {
 	return d.Status == DeviceStatusActive && d.RevokedAt == nil
 }
 
+// NewPendingDevice registers a device that cannot receive key material until
+// Approve is called.
+func NewPendingDevice(vaultID, publicKey, keyType, name string) (*Device, error) {
+	d, err := NewDevice(vaultID, publicKey, keyType)
+	if err != nil {
+		return nil, err
+	}
+	d.Status = DeviceStatusPending
+	d.Name = name
+	return d, nil
+}
+
+func (d *Device) IsPending() bool {
+	return d.Status == DeviceStatusPending && d.RevokedAt == nil
+}
+
+// VerifySignature checks a base64 signature of message made with the
+// device's key. Only ed25519 keys (base64 or hex) can sign for now.
+func (d *Device) VerifySignature(message []byte, signature string) error {
+	if d.KeyType != DeviceKeyTypeEd25519 {
+		return ErrDeviceKeyTypeCannotSign
+	}
+	pub, err := base64.StdEncoding.DecodeString(d.PublicKey)
+	if err != nil {
+		pub, err = hex.DecodeString(d.PublicKey)
+	}
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return ErrDeviceSignatureInvalid
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(pub), message, sig) {
+		return ErrDeviceSignatureInvalid
+	}
+	return nil
+}
+
+func (d *Device) Approve() error {
+	if !d.IsPending() {
+		return ErrDeviceNotPending
+	}
+	d.Status = DeviceStatusActive
+	return nil
+}
+
+// Touch records that the device was just used.
+func (d *Device) Touch(at time.Time) {
+	seen := at
+	d.LastSeenAt = &seen
+}
+
 func (d *Device) Revoke() error {
-	if !d.IsActive() || d.Status == DeviceStatusRevoked || d.RevokedAt != nil {
+	if d.Status == DeviceStatusRevoked || d.RevokedAt != nil {
 		return ErrDeviceAlreadyRevoked
 	}
 	now := time.Now()
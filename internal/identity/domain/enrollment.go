@@ -0,0 +1,154 @@
+package identity_domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EnrollmentStatusPending  = "pending"
+	EnrollmentStatusApproved = "approved"
+	EnrollmentStatusFailed   = "failed"
+)
+
+const (
+	DefaultEnrollmentTTL  = 10 * time.Minute
+	MaxEnrollmentAttempts = 5
+
+	enrollmentCodeLength = 8
+	// No 0/O or 1/I/L, so a code read off another screen is typed correctly.
+	enrollmentCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+)
+
+// DeviceEnrollment is the challenge a new device shows (as a QR code or a
+// short code) until an already-trusted device of the same vault confirms it.
+// Only a hash of the code is kept.
+type DeviceEnrollment struct {
+	ID               string     `json:"id" gorm:"primaryKey"`
+	VaultID          string     `json:"vault_id" gorm:"index;not null"`
+	DeviceID         string     `json:"device_id" gorm:"index;not null"`
+	CodeHash         string     `json:"-" gorm:"not null"`
+	Status           string     `json:"status" gorm:"not null"`
+	Attempts         int        `json:"attempts"`
+	ApproverDeviceID string     `json:"approver_device_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"not null"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"not null"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+}
+
+func (DeviceEnrollment) TableName() string {
+	return "identity_device_enrollments"
+}
+
+// NewDeviceEnrollment opens a challenge for deviceID and returns it together
+// with the plaintext code, which is never stored.
+func NewDeviceEnrollment(vaultID, deviceID string, ttl time.Duration) (*DeviceEnrollment, string, error) {
+	if vaultID == "" {
+		return nil, "", ErrDeviceVaultIDRequired
+	}
+	if deviceID == "" {
+		return nil, "", ErrDeviceNotFound
+	}
+	if ttl <= 0 {
+		ttl = DefaultEnrollmentTTL
+	}
+
+	code, err := newEnrollmentCode()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	return &DeviceEnrollment{
+		ID:        uuid.New().String(),
+		VaultID:   vaultID,
+		DeviceID:  deviceID,
+		CodeHash:  hashEnrollmentCode(code),
+		Status:    EnrollmentStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, code, nil
+}
+
+// Approve checks the code presented by the approving device. A wrong code
+// counts as an attempt; after MaxEnrollmentAttempts the enrollment fails and
+// the new device has to start over.
+func (e *DeviceEnrollment) Approve(code string, approverDeviceID string, at time.Time) error {
+	if e.Status != EnrollmentStatusPending {
+		return ErrEnrollmentClosed
+	}
+	if at.After(e.ExpiresAt) {
+		e.close(EnrollmentStatusFailed, at)
+		return ErrEnrollmentExpired
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if subtle.ConstantTimeCompare([]byte(hashEnrollmentCode(code)), []byte(e.CodeHash)) != 1 {
+		e.Attempts++
+		if e.Attempts >= MaxEnrollmentAttempts {
+			e.close(EnrollmentStatusFailed, at)
+		}
+		return ErrEnrollmentCodeMismatch
+	}
+	e.ApproverDeviceID = approverDeviceID
+	e.close(EnrollmentStatusApproved, at)
+	return nil
+}
+
+func (e *DeviceEnrollment) close(status string, at time.Time) {
+	resolved := at
+	e.Status = status
+	e.ResolvedAt = &resolved
+}
+
+// EnrollmentQRPayload is what the new device renders as a QR code: the
+// enrollment, the code and a fingerprint of the public key being enrolled,
+// so the approver can see which key it is about to trust.
+func EnrollmentQRPayload(enrollmentID, code, publicKey string) string {
+	return fmt.Sprintf("dvault-enroll:%s:%s:%s", enrollmentID, code, PublicKeyFingerprint(publicKey))
+}
+
+// EnrollmentApprovalMessage is what the approving device signs with its own
+// key. It binds the approval to the enrollment, its code and the key being
+// enrolled, so knowing a trusted device's ID is not enough to approve.
+func EnrollmentApprovalMessage(enrollmentID, code, publicKey string) []byte {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return []byte(fmt.Sprintf("dvault-enroll-approve:%s:%s:%s", enrollmentID, code, PublicKeyFingerprint(publicKey)))
+}
+
+// PublicKeyFingerprint is a short, human-comparable digest of a device key.
+func PublicKeyFingerprint(publicKey string) string {
+	sum := sha256.Sum256([]byte(publicKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// newEnrollmentCode draws each character uniformly from the alphabet. Bytes
+// at or above the largest multiple of the alphabet size are discarded, since
+// folding them in with a modulo would favour the first characters.
+func newEnrollmentCode() (string, error) {
+	limit := 256 - 256%len(enrollmentCodeAlphabet)
+	code := make([]byte, 0, enrollmentCodeLength)
+	buf := make([]byte, enrollmentCodeLength)
+	for len(code) < enrollmentCodeLength {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate enrollment code: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= limit || len(code) == enrollmentCodeLength {
+				continue
+			}
+			code = append(code, enrollmentCodeAlphabet[int(b)%len(enrollmentCodeAlphabet)])
+		}
+	}
+	return string(code), nil
+}
+
+func hashEnrollmentCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package identity_domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	identity_domain "vault-app/internal/identity/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceEnrollment_ApproveWithCode(t *testing.T) {
+	e, code, err := identity_domain.NewDeviceEnrollment("vault-1", "dev-2", 0)
+	require.NoError(t, err)
+
+	assert.Len(t, code, 8)
+	assert.NotContains(t, e.CodeHash, code)
+	assert.Equal(t, identity_domain.EnrollmentStatusPending, e.Status)
+	assert.WithinDuration(t, e.CreatedAt.Add(identity_domain.DefaultEnrollmentTTL), e.ExpiresAt, time.Second)
+
+	// Codes are typed by hand: case and surrounding spaces do not matter.
+	require.NoError(t, e.Approve(" "+strings.ToLower(code)+" ", "dev-1", time.Now()))
+	assert.Equal(t, identity_domain.EnrollmentStatusApproved, e.Status)
+	assert.Equal(t, "dev-1", e.ApproverDeviceID)
+	require.NotNil(t, e.ResolvedAt)
+
+	assert.ErrorIs(t, e.Approve(code, "dev-1", time.Now()), identity_domain.ErrEnrollmentClosed)
+}
+
+func TestDeviceEnrollment_CodeAlphabet(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		_, code, err := identity_domain.NewDeviceEnrollment("vault-1", "dev-2", 0)
+		require.NoError(t, err)
+		require.Len(t, code, 8)
+		for _, c := range code {
+			assert.Contains(t, "23456789ABCDEFGHJKMNPQRSTUVWXYZ", string(c))
+		}
+	}
+}
+
+func TestDeviceEnrollment_FailsAfterMaxAttempts(t *testing.T) {
+	e, code, err := identity_domain.NewDeviceEnrollment("vault-1", "dev-2", time.Minute)
+	require.NoError(t, err)
+
+	for i := 0; i < identity_domain.MaxEnrollmentAttempts; i++ {
+		assert.ErrorIs(t, e.Approve("WRONG", "dev-1", time.Now()), identity_domain.ErrEnrollmentCodeMismatch)
+	}
+	assert.Equal(t, identity_domain.EnrollmentStatusFailed, e.Status)
+	assert.ErrorIs(t, e.Approve(code, "dev-1", time.Now()), identity_domain.ErrEnrollmentClosed)
+}
+
+func TestDeviceEnrollment_Expired(t *testing.T) {
+	e, code, err := identity_domain.NewDeviceEnrollment("vault-1", "dev-2", time.Minute)
+	require.NoError(t, err)
+
+	err = e.Approve(code, "dev-1", e.ExpiresAt.Add(time.Second))
+	assert.ErrorIs(t, err, identity_domain.ErrEnrollmentExpired)
+	assert.Equal(t, identity_domain.EnrollmentStatusFailed, e.Status)
+}
+
+func TestEnrollmentQRPayload(t *testing.T) {
+	payload := identity_domain.EnrollmentQRPayload("enr-1", "ABCD2345", "pub-key")
+	assert.Equal(t, "dvault-enroll:enr-1:ABCD2345:"+identity_domain.PublicKeyFingerprint("pub-key"), payload)
+	assert.Len(t, identity_domain.PublicKeyFingerprint("pub-key"), 16)
+}
+
+func TestPendingDevice_ApproveAndRevoke(t *testing.T) {
+	dev, err := identity_domain.NewPendingDevice("vault-1", "pub", identity_domain.DeviceKeyTypeEd25519, "laptop")
+	require.NoError(t, err)
+	assert.True(t, dev.IsPending())
+	assert.False(t, dev.IsActive())
+
+	require.NoError(t, dev.Approve())
+	assert.True(t, dev.IsActive())
+	assert.ErrorIs(t, dev.Approve(), identity_domain.ErrDeviceNotPending)
+
+	pending, err := identity_domain.NewPendingDevice("vault-1", "pub-2", identity_domain.DeviceKeyTypeEd25519, "")
+	require.NoError(t, err)
+	require.NoError(t, pending.Revoke())
+	assert.Equal(t, identity_domain.DeviceStatusRevoked, pending.Status)
+}
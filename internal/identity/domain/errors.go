@@ -12,5 +12,15 @@ var (
     ErrDeviceKeyTypeRequired   = errors.New("device key type is required")
     ErrDeviceAlreadyRevoked    = errors.New("device is already revoked")
     ErrDeviceNotFound          = errors.New("device not found")
+    ErrDeviceNotPending        = errors.New("device is not awaiting approval")
+    ErrDeviceLimitReached      = errors.New("maximum number of devices reached")
+    ErrApproverNotTrusted      = errors.New("approving device is not an active device of this vault")
+    ErrDeviceSignatureInvalid  = errors.New("device signature does not verify")
+    ErrDeviceKeyTypeCannotSign = errors.New("device key type cannot sign approvals")
+
+    ErrEnrollmentNotFound      = errors.New("device enrollment not found")
+    ErrEnrollmentClosed        = errors.New("device enrollment is no longer open")
+    ErrEnrollmentExpired       = errors.New("device enrollment challenge has expired")
+    ErrEnrollmentCodeMismatch  = errors.New("device enrollment code does not match")
 )
 
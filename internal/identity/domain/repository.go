@@ -18,3 +18,8 @@ type DeviceRepository interface {
 	Update(ctx context.Context, d *Device) error
 }
 
+type DeviceEnrollmentRepository interface {
+	Save(ctx context.Context, e *DeviceEnrollment) error
+	FindByID(ctx context.Context, id string) (*DeviceEnrollment, error)
+	Update(ctx context.Context, e *DeviceEnrollment) error
+}
@@ -0,0 +1,47 @@
+package identity_persistence
+
+import (
+	"context"
+	"errors"
+
+	identity_domain "vault-app/internal/identity/domain"
+
+	"gorm.io/gorm"
+)
+
+type GormDeviceEnrollmentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDeviceEnrollmentRepository(db *gorm.DB) *GormDeviceEnrollmentRepository {
+	return &GormDeviceEnrollmentRepository{db: db}
+}
+
+func (r *GormDeviceEnrollmentRepository) Save(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r *GormDeviceEnrollmentRepository) FindByID(ctx context.Context, id string) (*identity_domain.DeviceEnrollment, error) {
+	var e identity_domain.DeviceEnrollment
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, identity_domain.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *GormDeviceEnrollmentRepository) Update(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	res := r.db.WithContext(ctx).Save(e)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return identity_domain.ErrEnrollmentNotFound
+	}
+	return nil
+}
+
+// Ensure interface satisfaction at compile-time
+var _ identity_domain.DeviceEnrollmentRepository = (*GormDeviceEnrollmentRepository)(nil)
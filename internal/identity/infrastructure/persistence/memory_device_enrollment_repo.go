@@ -0,0 +1,52 @@
+package identity_persistence
+
+import (
+	"context"
+	"sync"
+
+	identity_domain "vault-app/internal/identity/domain"
+)
+
+type MemoryDeviceEnrollmentRepository struct {
+	mu   sync.RWMutex
+	byID map[string]*identity_domain.DeviceEnrollment
+}
+
+func NewMemoryDeviceEnrollmentRepository() *MemoryDeviceEnrollmentRepository {
+	return &MemoryDeviceEnrollmentRepository{
+		byID: make(map[string]*identity_domain.DeviceEnrollment),
+	}
+}
+
+func (r *MemoryDeviceEnrollmentRepository) Save(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e.ID == "" {
+		return identity_domain.ErrEnrollmentNotFound
+	}
+	r.byID[e.ID] = e
+	return nil
+}
+
+func (r *MemoryDeviceEnrollmentRepository) FindByID(ctx context.Context, id string) (*identity_domain.DeviceEnrollment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byID[id]
+	if !ok {
+		return nil, identity_domain.ErrEnrollmentNotFound
+	}
+	return e, nil
+}
+
+func (r *MemoryDeviceEnrollmentRepository) Update(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[e.ID]; !ok {
+		return identity_domain.ErrEnrollmentNotFound
+	}
+	r.byID[e.ID] = e
+	return nil
+}
+
+// Ensure interface satisfaction at compile-time
+var _ identity_domain.DeviceEnrollmentRepository = (*MemoryDeviceEnrollmentRepository)(nil)
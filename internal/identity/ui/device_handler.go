@@ -0,0 +1,98 @@
+package identity_ui
+
+import (
+	"context"
+
+	identity_eventbus "vault-app/internal/identity/application"
+	identity_usecase "vault-app/internal/identity/application/usecase"
+	identity_domain "vault-app/internal/identity/domain"
+)
+
+// DeviceHandler exposes device enrollment, approval, listing and revocation
+// for the devices of a vault.
+type DeviceHandler struct {
+	getUC     *identity_usecase.GetDeviceUseCase
+	listUC    *identity_usecase.ListDevicesUseCase
+	enrollUC  *identity_usecase.EnrollDeviceUseCase
+	approveUC *identity_usecase.ApproveDeviceEnrollmentUseCase
+	touchUC   *identity_usecase.TouchDeviceUseCase
+	revokeUC  *identity_usecase.RevokeDeviceUseCase
+}
+
+func NewDeviceHandler(
+	repo identity_domain.DeviceRepository,
+	enrollments identity_domain.DeviceEnrollmentRepository,
+	policy identity_usecase.DevicePolicySource,
+	bus identity_eventbus.EventBus,
+) *DeviceHandler {
+	return &DeviceHandler{
+		getUC:     identity_usecase.NewGetDeviceUseCase(repo),
+		listUC:    identity_usecase.NewListDevicesUseCase(repo),
+		enrollUC:  identity_usecase.NewEnrollDeviceUseCase(repo, enrollments, policy, bus),
+		approveUC: identity_usecase.NewApproveDeviceEnrollmentUseCase(repo, enrollments, policy, bus),
+		touchUC:   identity_usecase.NewTouchDeviceUseCase(repo),
+		revokeUC:  identity_usecase.NewRevokeDeviceUseCase(repo, bus),
+	}
+}
+
+func (h *DeviceHandler) EnrollDevice(ctx context.Context, vaultID, publicKey, keyType, name string) (*identity_usecase.EnrollDeviceResponse, error) {
+	return h.enrollUC.Execute(ctx, identity_usecase.EnrollDeviceRequest{
+		VaultID:   vaultID,
+		PublicKey: publicKey,
+		KeyType:   keyType,
+		Name:      name,
+	})
+}
+
+func (h *DeviceHandler) ApproveDeviceEnrollment(ctx context.Context, vaultID, enrollmentID, code, approverDeviceID, signature string) (*identity_domain.Device, error) {
+	return h.approveUC.Execute(ctx, identity_usecase.ApproveDeviceEnrollmentRequest{
+		VaultID:          vaultID,
+		EnrollmentID:     enrollmentID,
+		Code:             code,
+		ApproverDeviceID: approverDeviceID,
+		Signature:        signature,
+	})
+}
+
+// EnrollmentApprovalSigner signs enrollment approvals as one of the vault's
+// devices.
+type EnrollmentApprovalSigner interface {
+	DeviceID() string
+	Sign(message []byte) ([]byte, error)
+}
+
+// ApproveDeviceEnrollmentWithSigner approves an enrollment as the signer's
+// device, signing the approval message on the caller's behalf.
+func (h *DeviceHandler) ApproveDeviceEnrollmentWithSigner(ctx context.Context, vaultID, enrollmentID, code string, signer EnrollmentApprovalSigner) (*identity_domain.Device, error) {
+	signature, err := h.approveUC.SignApproval(ctx, vaultID, enrollmentID, code, signer.Sign)
+	if err != nil {
+		return nil, err
+	}
+	return h.ApproveDeviceEnrollment(ctx, vaultID, enrollmentID, code, signer.DeviceID(), signature)
+}
+
+// ListDevices returns the vault's devices, most recently seen first.
+func (h *DeviceHandler) ListDevices(ctx context.Context, vaultID string) ([]*identity_domain.Device, error) {
+	return h.listUC.Execute(ctx, vaultID)
+}
+
+// TouchDevice marks a device of vaultID as seen now.
+func (h *DeviceHandler) TouchDevice(ctx context.Context, vaultID, deviceID string) error {
+	return h.touchUC.Execute(ctx, identity_usecase.TouchDeviceRequest{VaultID: vaultID, DeviceID: deviceID})
+}
+
+// RevokeDevice revokes a device of vaultID. Devices of other vaults are
+// reported as not found.
+func (h *DeviceHandler) RevokeDevice(ctx context.Context, vaultID, deviceID string) (*identity_domain.Device, error) {
+	dev, err := h.getUC.Execute(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if dev == nil || dev.VaultID != vaultID {
+		return nil, identity_domain.ErrDeviceNotFound
+	}
+	if err := h.revokeUC.Execute(ctx, deviceID); err != nil {
+		return nil, err
+	}
+	return h.getUC.Execute(ctx, deviceID)
+}
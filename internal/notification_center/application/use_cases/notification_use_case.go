@@ -2,12 +2,14 @@ package notification_center_usecases
 
 import (
 	"context"
+	"fmt"
 
 	notification_center_domain "vault-app/internal/notification_center/domain"
 )
 
 type NotificationUseCase struct {
 	client notification_center_domain.NotificationServiceInterface
+	local  notification_center_domain.LocalNotificationStore
 }
 
 func NewNotificationUseCase(
@@ -18,33 +20,100 @@ func NewNotificationUseCase(
 	}
 }
 
+// WithLocalStore adds device-local notifications to the ones the cloud
+// serves.
+func (uc *NotificationUseCase) WithLocalStore(
+	local notification_center_domain.LocalNotificationStore,
+) *NotificationUseCase {
+	uc.local = local
+	return uc
+}
+
+// Publish records a notification raised on this device.
+func (uc *NotificationUseCase) Publish(
+	ctx context.Context,
+	n notification_center_domain.Notification,
+) error {
+	if uc.local == nil {
+		return fmt.Errorf("local notification store is not initialized")
+	}
+	return uc.local.Save(ctx, n)
+}
+
+// Withdraw removes the user's local notification raised under eventID, once
+// what it reported no longer holds.
+func (uc *NotificationUseCase) Withdraw(
+	ctx context.Context,
+	userID string,
+	eventID string,
+) error {
+	if uc.local == nil {
+		return fmt.Errorf("local notification store is not initialized")
+	}
+	return uc.local.Withdraw(ctx, userID, eventID)
+}
+
+// ListByUser puts local notifications ahead of the cloud's first page.
 func (uc *NotificationUseCase) ListByUser(
 	ctx context.Context,
 	userID string,
 	limit int,
 	offset int,
 ) ([]notification_center_domain.Notification, error) {
-	return uc.client.ListByUser(ctx, userID, limit, offset)
+	remote, err := uc.client.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if uc.local == nil || offset > 0 {
+		return remote, nil
+	}
+	local, err := uc.local.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append(local, remote...), nil
 }
 
 func (uc *NotificationUseCase) CountUnread(
 	ctx context.Context,
 	userID string,
 ) (int64, error) {
-	return uc.client.CountUnread(ctx, userID)
+	count, err := uc.client.CountUnread(ctx, userID)
+	if err != nil || uc.local == nil {
+		return count, err
+	}
+	local, err := uc.local.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	return count + local, nil
 }
 
+// MarkRead and Archive try the user's local notifications first; other
+// ids go to the cloud.
 func (uc *NotificationUseCase) MarkRead(
 	ctx context.Context,
+	userID string,
 	id string,
 ) error {
+	if uc.local != nil {
+		if found, err := uc.local.MarkRead(ctx, userID, id); found || err != nil {
+			return err
+		}
+	}
 	return uc.client.MarkRead(ctx, id)
 }
 
 func (uc *NotificationUseCase) Archive(
 	ctx context.Context,
+	userID string,
 	id string,
 ) error {
+	if uc.local != nil {
+		if found, err := uc.local.Archive(ctx, userID, id); found || err != nil {
+			return err
+		}
+	}
 	return uc.client.Archive(ctx, id)
 }
 
@@ -52,5 +121,10 @@ func (uc *NotificationUseCase) MarkAllRead(
 	ctx context.Context,
 	userID string,
 ) error {
+	if uc.local != nil {
+		if err := uc.local.MarkAllRead(ctx, userID); err != nil {
+			return err
+		}
+	}
 	return uc.client.MarkAllRead(ctx, userID)
-}
\ No newline at end of file
+}
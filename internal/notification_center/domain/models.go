@@ -98,4 +98,7 @@ type Notification struct {
 	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
 
 	Sequence int64 `gorm:"column:sequence" json:"sequence"`
-}
\ No newline at end of file
+}
+// CategorySecurity marks notifications raised on this device about the
+// user's own vault. They are kept locally and never sent to the cloud.
+const CategorySecurity Category = "security"
@@ -30,4 +30,23 @@ type NotificationServiceInterface interface {
 		ctx context.Context,
 		userID string,
 	) error
-}
\ No newline at end of file
+}
+// LocalNotificationStore holds notifications raised on this device. Save
+// replaces a notification with the same user and EventID, keeping its read
+// state when the body did not change; Withdraw removes it. The bool results
+// tell whether the id is one of the user's local notifications.
+type LocalNotificationStore interface {
+	Save(ctx context.Context, n Notification) error
+
+	Withdraw(ctx context.Context, userID string, eventID string) error
+
+	ListByUser(ctx context.Context, userID string) ([]Notification, error)
+
+	CountUnread(ctx context.Context, userID string) (int64, error)
+
+	MarkRead(ctx context.Context, userID string, id string) (bool, error)
+
+	Archive(ctx context.Context, userID string, id string) (bool, error)
+
+	MarkAllRead(ctx context.Context, userID string) error
+}
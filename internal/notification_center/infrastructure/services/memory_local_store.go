@@ -0,0 +1,135 @@
+package notification_center_infrastructure_services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	notification_center_domain "vault-app/internal/notification_center/domain"
+)
+
+// MemoryLocalStore keeps device-local notifications for the lifetime of the
+// process. Nothing is written to disk: the vault health report, their only
+// source, runs again after every sign-in.
+type MemoryLocalStore struct {
+	mu    sync.Mutex
+	items map[string]notification_center_domain.Notification
+	now   func() time.Time
+}
+
+var _ notification_center_domain.LocalNotificationStore = (*MemoryLocalStore)(nil)
+
+func NewMemoryLocalStore() *MemoryLocalStore {
+	return &MemoryLocalStore{
+		items: map[string]notification_center_domain.Notification{},
+		now:   time.Now,
+	}
+}
+
+func (s *MemoryLocalStore) Save(_ context.Context, n notification_center_domain.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for id, old := range s.items {
+		if old.UserID != n.UserID || old.EventID == "" || old.EventID != n.EventID {
+			continue
+		}
+		if old.Body == n.Body && old.Title == n.Title {
+			old.Payload, old.UpdatedAt = n.Payload, now
+			s.items[id] = old
+			return nil
+		}
+		delete(s.items, id)
+	}
+	if n.ID == "" {
+		n.ID = uuid.NewString()
+	}
+	if n.Status == "" {
+		n.Status = notification_center_domain.StatusUnread
+	}
+	n.ReadAt = nil
+	n.CreatedAt, n.UpdatedAt = now, now
+	s.items[n.ID] = n
+	return nil
+}
+
+func (s *MemoryLocalStore) Withdraw(_ context.Context, userID string, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, n := range s.items {
+		if n.UserID == userID && eventID != "" && n.EventID == eventID {
+			delete(s.items, id)
+		}
+	}
+	return nil
+}
+
+// ListByUser returns the user's notifications, archived ones excluded,
+// newest first.
+func (s *MemoryLocalStore) ListByUser(_ context.Context, userID string) ([]notification_center_domain.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []notification_center_domain.Notification
+	for _, n := range s.items {
+		if n.UserID == userID && n.Status != notification_center_domain.StatusArchived {
+			out = append(out, n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *MemoryLocalStore) CountUnread(_ context.Context, userID string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var count int64
+	for _, n := range s.items {
+		if n.UserID == userID && n.Status == notification_center_domain.StatusUnread {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *MemoryLocalStore) MarkRead(_ context.Context, userID string, id string) (bool, error) {
+	return s.setStatus(userID, id, notification_center_domain.StatusRead), nil
+}
+
+func (s *MemoryLocalStore) Archive(_ context.Context, userID string, id string) (bool, error) {
+	return s.setStatus(userID, id, notification_center_domain.StatusArchived), nil
+}
+
+func (s *MemoryLocalStore) MarkAllRead(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, n := range s.items {
+		if n.UserID == userID && n.Status == notification_center_domain.StatusUnread {
+			s.items[id] = s.read(n)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryLocalStore) setStatus(userID string, id string, status notification_center_domain.Status) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.items[id]
+	if !ok || n.UserID != userID {
+		return false
+	}
+	if n.ReadAt == nil {
+		n = s.read(n)
+	}
+	n.Status = status
+	s.items[id] = n
+	return true
+}
+
+func (s *MemoryLocalStore) read(n notification_center_domain.Notification) notification_center_domain.Notification {
+	now := s.now()
+	n.Status, n.ReadAt, n.UpdatedAt = notification_center_domain.StatusRead, &now, now
+	return n
+}
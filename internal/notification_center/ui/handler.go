@@ -37,16 +37,18 @@ func (uc *NotificationHandler) CountUnread(
 
 func (uc *NotificationHandler) MarkRead(
 	ctx context.Context,
+	userID string,
 	id string,
 ) error {
-	return uc.notificationUseCase.MarkRead(ctx, id)
+	return uc.notificationUseCase.MarkRead(ctx, userID, id)
 }
 
 func (uc *NotificationHandler) Archive(
 	ctx context.Context,
+	userID string,
 	id string,
 ) error {
-	return uc.notificationUseCase.Archive(ctx, id)
+	return uc.notificationUseCase.Archive(ctx, userID, id)
 }
 
 func (uc *NotificationHandler) MarkAllRead(
@@ -54,4 +56,17 @@ func (uc *NotificationHandler) MarkAllRead(
 	userID string,
 ) error {
 	return uc.notificationUseCase.MarkAllRead(ctx, userID)
-}
\ No newline at end of file
+}
+func (uc *NotificationHandler) Publish(
+	ctx context.Context,
+	n notification_center_domain.Notification,
+) error {
+	return uc.notificationUseCase.Publish(ctx, n)
+}
+func (uc *NotificationHandler) Withdraw(
+	ctx context.Context,
+	userID string,
+	eventID string,
+) error {
+	return uc.notificationUseCase.Withdraw(ctx, userID, eventID)
+}
@@ -0,0 +1,54 @@
+package realtime_client_handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	shared_offline "vault-app/internal/shared/offline"
+	shared_realtime "vault-app/internal/shared/realtime"
+)
+
+// C3CacheInvalidationHandler marks the offline cache scope of a changed C3
+// aggregate stale and tells the UI to refetch it.
+type C3CacheInvalidationHandler struct {
+	appCtx context.Context
+	cache  *shared_offline.Cache
+}
+
+func NewC3CacheInvalidationHandler(
+	appCtx context.Context,
+	cache *shared_offline.Cache,
+) *C3CacheInvalidationHandler {
+	return &C3CacheInvalidationHandler{
+		appCtx: appCtx,
+		cache:  cache,
+	}
+}
+
+func (h *C3CacheInvalidationHandler) Handle(
+	ctx context.Context,
+	msg shared_realtime.Message,
+) error {
+
+	var payload shared_realtime.C3ChangedPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return err
+	}
+
+	if h.cache != nil {
+		scope := shared_offline.Scope(payload.Kind, payload.ParentID)
+		if err := h.cache.UserStore().Invalidate(ctx, scope); err != nil {
+			return err
+		}
+	}
+
+	runtime.EventsEmit(
+		h.appCtx,
+		msg.Type,
+		payload,
+	)
+
+	return nil
+}
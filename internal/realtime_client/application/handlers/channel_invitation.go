@@ -0,0 +1,55 @@
+package realtime_client_handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	shared_offline "vault-app/internal/shared/offline"
+	shared_realtime "vault-app/internal/shared/realtime"
+)
+
+// ChannelInvitationHandler forwards channel invitation changes to the UI so
+// invitees see new invitations without polling. An accepted invitation also
+// changes the channel participants, whose cached listing is marked stale.
+type ChannelInvitationHandler struct {
+	appCtx context.Context
+	cache  *shared_offline.Cache
+}
+
+func NewChannelInvitationHandler(
+	appCtx context.Context,
+	cache *shared_offline.Cache,
+) *ChannelInvitationHandler {
+	return &ChannelInvitationHandler{
+		appCtx: appCtx,
+		cache:  cache,
+	}
+}
+
+func (h *ChannelInvitationHandler) Handle(
+	ctx context.Context,
+	msg shared_realtime.Message,
+) error {
+
+	var payload shared_realtime.ChannelInvitationPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return err
+	}
+
+	if h.cache != nil && msg.Type == shared_realtime.ChannelInvitationAccepted && payload.ChannelID != "" {
+		scope := shared_offline.Scope(shared_offline.KindParticipant, payload.ChannelID)
+		if err := h.cache.UserStore().Invalidate(ctx, scope); err != nil {
+			return err
+		}
+	}
+
+	runtime.EventsEmit(
+		h.appCtx,
+		msg.Type,
+		payload,
+	)
+
+	return nil
+}
@@ -27,12 +27,15 @@ type EntryRegistry struct {
 	handlers map[string]EntryHandler
 	Vault    *vault_session.Session
 	Session  *vault_session.Session
+	schemas  *vaults_domain.RecordSchemaRegistry
 }
 
 type EntryDefinition struct {
 	Type    string
 	Factory func() vaults_domain.VaultEntry
 	Handler EntryHandler
+	// Schemas are the custom record schemas built on this entry type.
+	Schemas []vaults_domain.RecordSchema
 }
 
 func (r *EntryRegistry) RegisterDefinitions(defs []EntryDefinition) {
@@ -43,6 +46,11 @@ func (r *EntryRegistry) RegisterDefinitions(defs []EntryDefinition) {
 		if def.Handler != nil {
 			r.Register(def.Type, def.Handler)
 		}
+		for _, schema := range def.Schemas {
+			if err := r.RegisterRecordSchema(schema); err != nil {
+				r.logger.Error("🔧 Registry - skipping record schema: %v", err)
+			}
+		}
 	}
 }
 func NewRegistry(logger *logger.Logger) *EntryRegistry {
@@ -50,6 +58,7 @@ func NewRegistry(logger *logger.Logger) *EntryRegistry {
 	return &EntryRegistry{
 		logger:   *logger,
 		handlers: make(map[string]EntryHandler),
+		schemas:  vaults_domain.NewRecordSchemaRegistry(),
 	}
 }
 
@@ -80,3 +89,22 @@ func (r *EntryRegistry) Register(entryType string, handler EntryHandler) {
 func (r *EntryRegistry) RegisterEntryType(name string, factory func() vaults_domain.VaultEntry) {
 	entryFactories[name] = factory
 }
+
+// RegisterRecordSchema adds one version of a custom record schema. Entries
+// of that record type are validated against it when the vault is built.
+func (r *EntryRegistry) RegisterRecordSchema(schema vaults_domain.RecordSchema) error {
+	return r.RecordSchemas().Register(schema)
+}
+
+// RegisterRecordMigration registers the upgrade of recordType from
+// fromVersion to fromVersion+1, applied when older entries are loaded.
+func (r *EntryRegistry) RegisterRecordMigration(recordType string, fromVersion int, migrate vaults_domain.RecordMigration) error {
+	return r.RecordSchemas().RegisterMigration(recordType, fromVersion, migrate)
+}
+
+func (r *EntryRegistry) RecordSchemas() *vaults_domain.RecordSchemaRegistry {
+	if r.schemas == nil {
+		r.schemas = vaults_domain.NewRecordSchemaRegistry()
+	}
+	return r.schemas
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package shared_localsocket
+
+import (
+	"net"
+	"os"
+)
+
+// listenPrivate restricts the socket after binding where there is no umask.
+func listenPrivate(path string) (net.Listener, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package shared_localsocket
+
+import (
+	"net"
+	"sync"
+	"syscall"
+)
+
+// umaskMu serializes the umask changes of concurrent listeners; the umask
+// is process wide.
+var umaskMu sync.Mutex
+
+// listenPrivate binds the socket under a umask leaving it 0600, so other
+// users can never reach it, not even between bind and a chmod.
+func listenPrivate(path string) (net.Listener, error) {
+	umaskMu.Lock()
+	defer umaskMu.Unlock()
+	old := syscall.Umask(0o177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,55 @@
+package shared_localsocket
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultPath is $XDG_RUNTIME_DIR/vaultcore/<name>, falling back to
+// ~/.vaultcore/<name>.
+func DefaultPath(name string) (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "vaultcore", name), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".vaultcore", name), nil
+}
+
+// Listen creates a Unix socket at path that only the current user can
+// connect to. A socket left by a previous run is removed; one that still
+// answers fails with inUse.
+func Listen(path string, inUse error) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := removeStale(path, inUse); err != nil {
+		return nil, err
+	}
+	return listenPrivate(path)
+}
+
+// removeStale deletes a socket left by a previous run, refusing to touch a
+// live server or anything that is not a socket.
+func removeStale(path string, inUse error) error {
+	fi, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return errors.New("socket path exists and is not a socket: " + path)
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return inUse
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,67 @@
+package shared_localsocket_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	shared_localsocket "vault-app/internal/shared/localsocket"
+)
+
+var errInUse = errors.New("in use")
+
+// socketPath stays under the Unix socket path limit, which t.TempDir can
+// exceed.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "ls")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "run", "test.sock")
+}
+
+func TestListen_PrivateSocket(t *testing.T) {
+	path := socketPath(t)
+	l, err := shared_localsocket.Listen(path, errInUse)
+	require.NoError(t, err)
+	defer l.Close()
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	dir, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o700), dir.Mode().Perm())
+
+	_, err = shared_localsocket.Listen(path, errInUse)
+	require.ErrorIs(t, err, errInUse)
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	l, err := shared_localsocket.Listen(path, errInUse)
+	require.NoError(t, err)
+	// A crashed server leaves its socket behind.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+	require.FileExists(t, path)
+
+	l, err = shared_localsocket.Listen(path, errInUse)
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+}
+
+func TestListen_RefusesOtherFiles(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("keep"), 0o600))
+
+	_, err := shared_localsocket.Listen(path, errInUse)
+	require.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "keep", string(data))
+}
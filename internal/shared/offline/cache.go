@@ -0,0 +1,87 @@
+package shared_offline
+
+import (
+	"context"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Cache bundles the local read model, the offline mutation queue and the
+// connectivity tracker shared by the offline-first C3 repositories.
+type Cache struct {
+	Store        *Store
+	Queue        *MutationQueue
+	Connectivity *Connectivity
+}
+
+func NewCache(db *gorm.DB) (*Cache, error) {
+	store, err := NewStore(db)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		Store:        store,
+		Queue:        NewMutationQueue(db),
+		Connectivity: NewConnectivity(),
+	}
+	// On reconnect, only the current session's writes can be replayed.
+	c.Connectivity.OnReconnect(func(ctx context.Context) {
+		userID := c.Queue.User()
+		if userID == "" {
+			return
+		}
+		res, err := c.Queue.Replay(ctx, userID)
+		if err != nil {
+			log.Printf("[OFFLINE] replay failed: %v", err)
+			return
+		}
+		log.Printf("[OFFLINE] replayed=%d failed=%d pending=%d", res.Replayed, res.Failed, res.Pending)
+	})
+
+	return c, nil
+}
+
+// UserStore is the read model of the user whose Cloud session is current,
+// the one writes are queued for.
+func (c *Cache) UserStore() *Store {
+	return c.Store.ForUser(c.Queue.User())
+}
+
+// Status reports connectivity, the mutations queued for userID and the
+// staleness of every scope cached for them.
+func (c *Cache) Status(ctx context.Context, userID string) (*Status, error) {
+	online := c.Connectivity.Online()
+
+	pending, err := c.Queue.Pending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	store := c.Store.ForUser(userID)
+	states, err := store.SyncStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Online:           online,
+		PendingMutations: len(pending),
+		Scopes:           make([]Staleness, 0, len(states)),
+	}
+	for _, st := range states {
+		status.Scopes = append(status.Scopes, store.Staleness(st, online))
+	}
+	return status, nil
+}
+
+// ScopeStaleness returns the indicator for a single listing of userID.
+func (c *Cache) ScopeStaleness(ctx context.Context, userID, kind, parentID string) (*Staleness, error) {
+	store := c.Store.ForUser(userID)
+	state, err := store.SyncState(ctx, Scope(kind, parentID))
+	if err != nil {
+		return nil, err
+	}
+	st := store.Staleness(*state, c.Connectivity.Online())
+	return &st, nil
+}
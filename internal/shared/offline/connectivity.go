@@ -0,0 +1,99 @@
+package shared_offline
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// IsOfflineError reports whether err means the cloud could not be reached,
+// as opposed to the cloud answering with an error. Only failures to connect
+// (dial, DNS lookup, unreachable network) and timeouts count; a request that
+// reached the cloud and failed otherwise does not.
+func IsOfflineError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ENETUNREACH),
+		errors.Is(err, syscall.EHOSTUNREACH):
+		return true
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return true
+	case errors.As(err, &dnsErr):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	}
+	return false
+}
+
+// Connectivity tracks whether the cloud is reachable, based on the outcome
+// of the last cloud call. Reconnect handlers run when the state flips back
+// to online.
+type Connectivity struct {
+	mu          sync.RWMutex
+	online      bool
+	onReconnect []func(ctx context.Context)
+	onChange    []func(online bool)
+}
+
+func NewConnectivity() *Connectivity {
+	return &Connectivity{online: true}
+}
+
+func (c *Connectivity) Online() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.online
+}
+
+// Observe records the outcome of a cloud call and returns whether the call
+// failed because the cloud is unreachable.
+func (c *Connectivity) Observe(ctx context.Context, err error) bool {
+	offline := IsOfflineError(err)
+	c.set(ctx, !offline)
+	return offline
+}
+
+func (c *Connectivity) set(ctx context.Context, online bool) {
+	c.mu.Lock()
+	changed := c.online != online
+	c.online = online
+	reconnect := append([]func(ctx context.Context){}, c.onReconnect...)
+	change := append([]func(online bool){}, c.onChange...)
+	c.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, h := range change {
+		h(online)
+	}
+	if online {
+		for _, h := range reconnect {
+			// replay must not block the call that noticed the reconnect
+			go h(context.WithoutCancel(ctx))
+		}
+	}
+}
+
+func (c *Connectivity) OnReconnect(handler func(ctx context.Context)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onReconnect = append(c.onReconnect, handler)
+}
+
+func (c *Connectivity) OnChange(handler func(online bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onChange = append(c.onChange, handler)
+}
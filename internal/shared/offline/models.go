@@ -0,0 +1,89 @@
+package shared_offline
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Record kinds cached locally.
+const (
+	KindWorkspace   = "workspace"
+	KindChannel     = "channel"
+	KindParticipant = "participant"
+	KindThread      = "thread"
+	KindThreadEvent = "thread_event"
+	KindRemoteVault = "remote_vault"
+	KindOccupancy   = "slot_occupancy_request"
+)
+
+// CachedRecord is the local read model row of one C3 aggregate. The
+// aggregate is stored as JSON so the cache does not need a table per type;
+// ParentID is the listing scope (vault for workspaces, workspace for
+// channels, channel for threads, thread for events). Rows belong to the user
+// whose Cloud session fetched them.
+type CachedRecord struct {
+	UserID    string          `json:"user_id" gorm:"primaryKey"`
+	Kind      string          `json:"kind" gorm:"primaryKey"`
+	ID        string          `json:"id" gorm:"primaryKey"`
+	ParentID  string          `json:"parent_id" gorm:"index"`
+	Cursor    uint64          `json:"cursor"`
+	Data      json.RawMessage `json:"data" gorm:"type:blob"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+func (CachedRecord) TableName() string { return "c3_cached_records" }
+
+// SyncState tracks the last successful refresh of a user's listing scope.
+type SyncState struct {
+	UserID       string    `json:"user_id" gorm:"primaryKey"`
+	Scope        string    `json:"scope" gorm:"primaryKey"`
+	Cursor       uint64    `json:"cursor"`
+	LastSyncedAt time.Time `json:"last_synced_at"`
+	LastError    string    `json:"last_error,omitempty"`
+	// UpdatedSince is what the next incremental refresh of the listing asks
+	// the cloud for; FullSyncedAt is when the listing was last fetched whole.
+	UpdatedSince time.Time `json:"updated_since"`
+	FullSyncedAt time.Time `json:"full_synced_at"`
+}
+
+func (SyncState) TableName() string { return "c3_sync_states" }
+
+// PendingMutation is a write made while offline, replayed in order on
+// reconnect.
+type PendingMutation struct {
+	ID  string `json:"id" gorm:"primaryKey"`
+	Seq int64  `json:"seq" gorm:"autoIncrement:false;index"`
+	// UserID is whose Cloud session made the write; only that user's
+	// session replays it.
+	UserID    string          `json:"user_id" gorm:"index"`
+	Operation string          `json:"operation"`
+	Payload   json.RawMessage `json:"payload" gorm:"type:blob"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (PendingMutation) TableName() string { return "c3_pending_mutations" }
+
+// Staleness is what the UI shows next to cached C3 data.
+type Staleness struct {
+	Scope        string        `json:"scope"`
+	Online       bool          `json:"online"`
+	LastSyncedAt time.Time     `json:"last_synced_at"`
+	Age          time.Duration `json:"age"`
+	Stale        bool          `json:"stale"`
+	Cursor       uint64        `json:"cursor"`
+	LastError    string        `json:"last_error,omitempty"`
+}
+
+// Status summarises the offline cache for the UI.
+type Status struct {
+	Online           bool        `json:"online"`
+	PendingMutations int         `json:"pending_mutations"`
+	Scopes           []Staleness `json:"scopes"`
+}
+
+// Scope builds the sync-state key of a listing.
+func Scope(kind, parentID string) string {
+	return kind + ":" + parentID
+}
@@ -0,0 +1,145 @@
+package shared_offline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoReplayer  = errors.New("no replayer registered for operation")
+	ErrNoQueueUser = errors.New("no user session to queue the mutation for")
+)
+
+// Replayer re-sends one queued mutation to the cloud.
+type Replayer func(ctx context.Context, payload json.RawMessage) error
+
+// MutationQueue persists writes made while offline and replays them in the
+// order they were made once the cloud is reachable again. Each write is
+// queued for the user whose Cloud session is current (SetUser) and only
+// replayed with that user's session.
+type MutationQueue struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	replayers map[string]Replayer
+
+	userMu sync.RWMutex
+	userID string
+}
+
+func NewMutationQueue(db *gorm.DB) *MutationQueue {
+	return &MutationQueue{db: db, replayers: make(map[string]Replayer)}
+}
+
+// Register binds an operation name to the function that replays it.
+func (q *MutationQueue) Register(operation string, replayer Replayer) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.replayers[operation] = replayer
+}
+
+// SetUser records whose Cloud session the next writes are made with.
+func (q *MutationQueue) SetUser(userID string) {
+	q.userMu.Lock()
+	defer q.userMu.Unlock()
+	q.userID = userID
+}
+
+// User returns the user writes are currently queued for.
+func (q *MutationQueue) User() string {
+	q.userMu.RLock()
+	defer q.userMu.RUnlock()
+	return q.userID
+}
+
+func (q *MutationQueue) Enqueue(ctx context.Context, operation string, payload any) (*PendingMutation, error) {
+	userID := q.User()
+	if userID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoQueueUser, operation)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s mutation: %w", operation, err)
+	}
+
+	now := time.Now()
+	m := &PendingMutation{
+		ID:        uuid.NewString(),
+		Seq:       now.UnixNano(),
+		UserID:    userID,
+		Operation: operation,
+		Payload:   data,
+		CreatedAt: now,
+	}
+	if err := q.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Pending returns the mutations queued for userID, oldest first.
+func (q *MutationQueue) Pending(ctx context.Context, userID string) ([]PendingMutation, error) {
+	var ms []PendingMutation
+	err := q.db.WithContext(ctx).Where("user_id = ?", userID).Order("seq ASC").Find(&ms).Error
+	return ms, err
+}
+
+// ReplayResult reports one replay pass.
+type ReplayResult struct {
+	Replayed int `json:"replayed"`
+	Failed   int `json:"failed"`
+	Pending  int `json:"pending"`
+}
+
+// Replay sends userID's queued mutations in order, so the caller must hold
+// that user's Cloud session. It stops at the first mutation
+// that fails because the cloud is unreachable, so ordering is preserved;
+// mutations the cloud rejects stay queued with their error for the user to
+// inspect.
+func (q *MutationQueue) Replay(ctx context.Context, userID string) (*ReplayResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending, err := q.Pending(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ReplayResult{}
+	for i, m := range pending {
+		replay, ok := q.replayers[m.Operation]
+		if !ok {
+			err = fmt.Errorf("%w: %s", ErrNoReplayer, m.Operation)
+		} else {
+			err = replay(ctx, m.Payload)
+		}
+
+		if err == nil {
+			if err := q.db.WithContext(ctx).Delete(&PendingMutation{}, "id = ?", m.ID).Error; err != nil {
+				return nil, err
+			}
+			res.Replayed++
+			continue
+		}
+
+		m.Attempts++
+		m.LastError = err.Error()
+		if err := q.db.WithContext(ctx).Save(&m).Error; err != nil {
+			return nil, err
+		}
+		if IsOfflineError(err) {
+			res.Pending = len(pending) - i
+			return res, nil
+		}
+		res.Failed++
+	}
+
+	res.Pending = res.Failed
+	return res, nil
+}
@@ -0,0 +1,335 @@
+package shared_offline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotCached = errors.New("record not cached")
+)
+
+// DefaultStaleAfter is how long a listing is considered fresh after its last
+// successful refresh.
+const DefaultStaleAfter = 5 * time.Minute
+
+// DefaultFullRefreshAfter is how long a listing is refreshed by deltas only.
+// A delta cannot report deletions, so a full refresh drops them.
+const DefaultFullRefreshAfter = time.Hour
+
+// refreshOverlap moves the updated-since marker back, so changes committed
+// while a refresh was in flight, or hidden by clock skew, are fetched again.
+// Merging them twice is harmless.
+const refreshOverlap = time.Minute
+
+// Store is the SQLite-backed local read model of C3 aggregates. Every row
+// and sync state belongs to a user: a Store reads and writes only its own
+// user's (see ForUser).
+type Store struct {
+	db               *gorm.DB
+	StaleAfter       time.Duration
+	FullRefreshAfter time.Duration
+	userID           string
+}
+
+func NewStore(db *gorm.DB) (*Store, error) {
+	// Tables from before rows were keyed by user cannot tell whose they
+	// are; they only hold cloud data, so they are dropped and refetched.
+	for _, model := range []any{&CachedRecord{}, &SyncState{}} {
+		if db.Migrator().HasTable(model) && !db.Migrator().HasColumn(model, "UserID") {
+			if err := db.Migrator().DropTable(model); err != nil {
+				return nil, fmt.Errorf("failed to reset offline cache: %w", err)
+			}
+		}
+	}
+	if err := db.AutoMigrate(&CachedRecord{}, &SyncState{}, &PendingMutation{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate offline cache: %w", err)
+	}
+	return &Store{db: db, StaleAfter: DefaultStaleAfter, FullRefreshAfter: DefaultFullRefreshAfter}, nil
+}
+
+// ForUser returns the store of userID's rows, sharing the same database.
+func (s *Store) ForUser(userID string) *Store {
+	scoped := *s
+	scoped.userID = userID
+	return &scoped
+}
+
+// User returns whose rows the store reads and writes.
+func (s *Store) User() string {
+	return s.userID
+}
+
+// records starts a query on the user's cached records.
+func (s *Store) records(db *gorm.DB) *gorm.DB {
+	return db.Where("user_id = ?", s.userID)
+}
+
+// Item is one aggregate written into a listing scope.
+type Item struct {
+	ID     string
+	Cursor uint64
+	Value  any
+}
+
+func toRecord(userID, kind, parentID string, item Item, now time.Time) (CachedRecord, error) {
+	data, err := json.Marshal(item.Value)
+	if err != nil {
+		return CachedRecord{}, fmt.Errorf("encode cached %s %s: %w", kind, item.ID, err)
+	}
+	return CachedRecord{
+		UserID:    userID,
+		Kind:      kind,
+		ID:        item.ID,
+		ParentID:  parentID,
+		Cursor:    item.Cursor,
+		Data:      data,
+		FetchedAt: now,
+	}, nil
+}
+
+func (s *Store) toRecords(kind, parentID string, items []Item) ([]CachedRecord, error) {
+	now := time.Now()
+	records := make([]CachedRecord, 0, len(items))
+	for _, item := range items {
+		rec, err := toRecord(s.userID, kind, parentID, item, now)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Put upserts items into a listing scope, leaving other rows untouched.
+func (s *Store) Put(ctx context.Context, kind, parentID string, items ...Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	records, err := s.toRecords(kind, parentID, items)
+	if err != nil {
+		return err
+	}
+	return upsertRecords(s.db.WithContext(ctx), records)
+}
+
+func upsertRecords(db *gorm.DB, records []CachedRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&records).Error
+}
+
+// replaceRecords deletes the user's scope rows missing from records, then
+// upserts records. Run it inside a transaction.
+func (s *Store) replaceRecords(tx *gorm.DB, kind, parentID string, records []CachedRecord) error {
+	keep := make([]string, 0, len(records))
+	for _, rec := range records {
+		keep = append(keep, rec.ID)
+	}
+
+	q := s.records(tx).Where("kind = ? AND parent_id = ?", kind, parentID)
+	if len(keep) > 0 {
+		q = q.Where("id NOT IN ?", keep)
+	}
+	if err := q.Delete(&CachedRecord{}).Error; err != nil {
+		return err
+	}
+	return upsertRecords(tx, records)
+}
+
+// Replace makes the listing scope match items exactly: rows the cloud no
+// longer returns are removed. Items are encoded before anything is written,
+// and a failure leaves the scope as it was.
+func (s *Store) Replace(ctx context.Context, kind, parentID string, items []Item) error {
+	records, err := s.toRecords(kind, parentID, items)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.replaceRecords(tx, kind, parentID, records)
+	})
+}
+
+// ReplaceSynced replaces the listing scope and records the refresh of scope
+// in one transaction: the listing and its sync state change together or not
+// at all. The scope cursor is left as it was.
+func (s *Store) ReplaceSynced(ctx context.Context, kind, parentID, scope string, items []Item) error {
+	records, err := s.toRecords(kind, parentID, items)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.replaceRecords(tx, kind, parentID, records); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   syncStateKey,
+			DoUpdates: clause.AssignmentColumns([]string{"last_synced_at", "last_error"}),
+		}).Create(&SyncState{UserID: s.userID, Scope: scope, LastSyncedAt: time.Now()}).Error
+	})
+}
+
+// Since returns the marker to refresh the listing of state with: changes
+// after it are requested from the cloud. It is zero when the listing is due
+// a full refresh.
+func (s *Store) Since(state SyncState) time.Time {
+	fullRefreshAfter := s.FullRefreshAfter
+	if fullRefreshAfter <= 0 {
+		fullRefreshAfter = DefaultFullRefreshAfter
+	}
+	if state.UpdatedSince.IsZero() || time.Since(state.FullSyncedAt) > fullRefreshAfter {
+		return time.Time{}
+	}
+	return state.UpdatedSince
+}
+
+// MergeSynced applies a listing refresh requested with since (see Since) and
+// records it in one transaction. A delta (since set) is merged into the
+// listing; a full refresh (since zero) replaces it. Either way the marker of
+// the next refresh moves to now.
+func (s *Store) MergeSynced(ctx context.Context, kind, parentID, scope string, since time.Time, items []Item) error {
+	records, err := s.toRecords(kind, parentID, items)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	state := &SyncState{UserID: s.userID, Scope: scope, LastSyncedAt: now, UpdatedSince: now.Add(-refreshOverlap)}
+	columns := []string{"last_synced_at", "last_error", "updated_since"}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if since.IsZero() {
+			if err := s.replaceRecords(tx, kind, parentID, records); err != nil {
+				return err
+			}
+			state.FullSyncedAt = now
+			columns = append(columns, "full_synced_at")
+		} else if err := upsertRecords(tx, records); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   syncStateKey,
+			DoUpdates: clause.AssignmentColumns(columns),
+		}).Create(state).Error
+	})
+}
+
+// Get decodes the cached record into out.
+func (s *Store) Get(ctx context.Context, kind, id string, out any) error {
+	var rec CachedRecord
+	err := s.records(s.db.WithContext(ctx)).Where("kind = ? AND id = ?", kind, id).First(&rec).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotCached
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rec.Data, out)
+}
+
+// List returns the cached records of a listing scope ordered by cursor.
+func (s *Store) List(ctx context.Context, kind, parentID string) ([]CachedRecord, error) {
+	var recs []CachedRecord
+	err := s.records(s.db.WithContext(ctx)).
+		Where("kind = ? AND parent_id = ?", kind, parentID).
+		Order("cursor ASC, fetched_at ASC, id ASC").
+		Find(&recs).Error
+	return recs, err
+}
+
+func (s *Store) Delete(ctx context.Context, kind, id string) error {
+	return s.records(s.db.WithContext(ctx)).Where("kind = ? AND id = ?", kind, id).Delete(&CachedRecord{}).Error
+}
+
+// Decode unmarshals cached records into aggregates.
+func Decode[T any](recs []CachedRecord) ([]T, error) {
+	out := make([]T, 0, len(recs))
+	for _, rec := range recs {
+		var v T
+		if err := json.Unmarshal(rec.Data, &v); err != nil {
+			return nil, fmt.Errorf("decode cached %s %s: %w", rec.Kind, rec.ID, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// syncStateKey is the conflict target of sync state upserts.
+var syncStateKey = []clause.Column{{Name: "user_id"}, {Name: "scope"}}
+
+// MarkSynced records a successful refresh of the scope up to cursor.
+func (s *Store) MarkSynced(ctx context.Context, scope string, cursor uint64) error {
+	return s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   syncStateKey,
+			DoUpdates: clause.AssignmentColumns([]string{"cursor", "last_synced_at", "last_error"}),
+		}).
+		Create(&SyncState{UserID: s.userID, Scope: scope, Cursor: cursor, LastSyncedAt: time.Now()}).Error
+}
+
+// MarkFailed keeps the last cursor and sync time and records the error.
+func (s *Store) MarkFailed(ctx context.Context, scope string, cause error) error {
+	state, err := s.SyncState(ctx, scope)
+	if err != nil {
+		return err
+	}
+	state.LastError = cause.Error()
+	return s.db.WithContext(ctx).Save(state).Error
+}
+
+// SyncState returns the sync state of the scope, or a zero state if the
+// scope has never been refreshed.
+func (s *Store) SyncState(ctx context.Context, scope string) (*SyncState, error) {
+	var state SyncState
+	err := s.db.WithContext(ctx).Where("user_id = ? AND scope = ?", s.userID, scope).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &SyncState{UserID: s.userID, Scope: scope}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Invalidate marks the scope stale (a realtime event announced a change)
+// while keeping its cursor, so the next read refreshes incrementally.
+func (s *Store) Invalidate(ctx context.Context, scope string) error {
+	return s.db.WithContext(ctx).
+		Model(&SyncState{}).
+		Where("user_id = ? AND scope = ?", s.userID, scope).
+		Update("last_synced_at", time.Time{}).Error
+}
+
+func (s *Store) SyncStates(ctx context.Context) ([]SyncState, error) {
+	var states []SyncState
+	err := s.db.WithContext(ctx).Where("user_id = ?", s.userID).Order("scope ASC").Find(&states).Error
+	return states, err
+}
+
+// Staleness derives the UI indicator of a sync state.
+func (s *Store) Staleness(state SyncState, online bool) Staleness {
+	staleAfter := s.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	st := Staleness{
+		Scope:        state.Scope,
+		Online:       online,
+		LastSyncedAt: state.LastSyncedAt,
+		Cursor:       state.Cursor,
+		LastError:    state.LastError,
+		Stale:        true,
+	}
+	if !state.LastSyncedAt.IsZero() {
+		st.Age = time.Since(state.LastSyncedAt)
+		st.Stale = !online || st.Age > staleAfter
+	}
+	return st
+}
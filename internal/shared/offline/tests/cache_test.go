@@ -0,0 +1,342 @@
+package shared_offline_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	shared_offline "vault-app/internal/shared/offline"
+)
+
+func newCache(t *testing.T) *shared_offline.Cache {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	cache, err := shared_offline.NewCache(db)
+	require.NoError(t, err)
+	cache.Queue.SetUser("user-1")
+	return cache
+}
+
+var errUnreachable = &url.Error{Op: "Get", URL: "http://cloud", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+
+type item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestStore_ReplacePutList(t *testing.T) {
+	ctx := context.Background()
+	store := newCache(t).Store
+
+	require.NoError(t, store.Replace(ctx, "thing", "p1", []shared_offline.Item{
+		{ID: "a", Cursor: 2, Value: item{ID: "a", Name: "A"}},
+		{ID: "b", Cursor: 1, Value: item{ID: "b", Name: "B"}},
+	}))
+	require.NoError(t, store.Put(ctx, "thing", "p2", shared_offline.Item{ID: "c", Value: item{ID: "c"}}))
+
+	recs, err := store.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	items, err := shared_offline.Decode[item](recs)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "b", items[0].ID, "ordered by cursor")
+
+	// replace drops rows the cloud no longer returns, only within the scope
+	require.NoError(t, store.Replace(ctx, "thing", "p1", []shared_offline.Item{
+		{ID: "a", Value: item{ID: "a", Name: "A2"}},
+	}))
+	recs, err = store.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+
+	var got item
+	require.NoError(t, store.Get(ctx, "thing", "a", &got))
+	assert.Equal(t, "A2", got.Name)
+	require.NoError(t, store.Get(ctx, "thing", "c", &got))
+	assert.ErrorIs(t, store.Get(ctx, "thing", "b", &got), shared_offline.ErrNotCached)
+}
+
+func TestStore_ReplaceSyncedIsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	store := newCache(t).Store
+	scope := shared_offline.Scope("thing", "p1")
+
+	require.NoError(t, store.MarkSynced(ctx, scope, 7))
+	require.NoError(t, store.ReplaceSynced(ctx, "thing", "p1", scope, []shared_offline.Item{
+		{ID: "a", Value: item{ID: "a"}},
+		{ID: "b", Value: item{ID: "b"}},
+	}))
+	state, err := store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), state.Cursor, "replace keeps the cursor")
+	syncedAt := state.LastSyncedAt
+	assert.False(t, syncedAt.IsZero())
+
+	// An item that cannot be encoded fails the refresh before anything is
+	// deleted, and the sync time does not move.
+	err = store.ReplaceSynced(ctx, "thing", "p1", scope, []shared_offline.Item{
+		{ID: "a", Value: item{ID: "a"}},
+		{ID: "x", Value: make(chan int)},
+	})
+	require.Error(t, err)
+	recs, err := store.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	assert.Len(t, recs, 2)
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.Equal(t, syncedAt.UnixNano(), state.LastSyncedAt.UnixNano())
+}
+
+func TestStore_RowsAreScopedByUser(t *testing.T) {
+	ctx := context.Background()
+	cache := newCache(t)
+	alice := cache.Store.ForUser("alice")
+	bob := cache.Store.ForUser("bob")
+	scope := shared_offline.Scope("thing", "p1")
+
+	require.NoError(t, alice.ReplaceSynced(ctx, "thing", "p1", scope, []shared_offline.Item{
+		{ID: "a", Value: item{ID: "a", Name: "Alice's"}},
+	}))
+	require.NoError(t, bob.Put(ctx, "thing", "p1", shared_offline.Item{ID: "a", Value: item{ID: "a", Name: "Bob's"}}))
+
+	var got item
+	require.NoError(t, alice.Get(ctx, "thing", "a", &got))
+	assert.Equal(t, "Alice's", got.Name, "same key, another user's row")
+	assert.ErrorIs(t, cache.Store.ForUser("carol").Get(ctx, "thing", "a", &got), shared_offline.ErrNotCached)
+
+	// Bob's refresh of the listing leaves Alice's rows and sync state alone.
+	require.NoError(t, bob.ReplaceSynced(ctx, "thing", "p1", scope, nil))
+	recs, err := alice.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	assert.Len(t, recs, 1)
+	recs, err = bob.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+
+	require.NoError(t, alice.Delete(ctx, "thing", "a"))
+	require.NoError(t, bob.Put(ctx, "thing", "p1", shared_offline.Item{ID: "a", Value: item{ID: "a"}}))
+	require.NoError(t, bob.Invalidate(ctx, scope))
+	state, err := alice.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.False(t, state.LastSyncedAt.IsZero(), "invalidating Bob's listing does not touch Alice's")
+
+	cache.Queue.SetUser("bob")
+	assert.Equal(t, "bob", cache.UserStore().User())
+	status, err := cache.Status(ctx, "carol")
+	require.NoError(t, err)
+	assert.Empty(t, status.Scopes)
+}
+
+func TestStore_MergeSyncedAppliesDeltasBetweenFullRefreshes(t *testing.T) {
+	ctx := context.Background()
+	store := newCache(t).UserStore()
+	scope := shared_offline.Scope("thing", "p1")
+
+	state, err := store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	since := store.Since(*state)
+	assert.True(t, since.IsZero(), "first refresh is full")
+	require.NoError(t, store.MergeSynced(ctx, "thing", "p1", scope, since, []shared_offline.Item{
+		{ID: "a", Value: item{ID: "a", Name: "A"}},
+		{ID: "b", Value: item{ID: "b", Name: "B"}},
+	}))
+
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	since = store.Since(*state)
+	require.False(t, since.IsZero())
+	assert.True(t, since.Before(state.LastSyncedAt), "the marker overlaps the refresh")
+
+	// A delta only carries what changed; the rest of the listing stays.
+	require.NoError(t, store.MergeSynced(ctx, "thing", "p1", scope, since, []shared_offline.Item{
+		{ID: "b", Value: item{ID: "b", Name: "B2"}},
+		{ID: "c", Value: item{ID: "c", Name: "C"}},
+	}))
+	recs, err := store.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	assert.Len(t, recs, 3)
+	var got item
+	require.NoError(t, store.Get(ctx, "thing", "b", &got))
+	assert.Equal(t, "B2", got.Name)
+
+	// Once due, a full refresh drops what the cloud no longer returns.
+	store.FullRefreshAfter = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	since = store.Since(*state)
+	require.True(t, since.IsZero())
+	require.NoError(t, store.MergeSynced(ctx, "thing", "p1", scope, since, []shared_offline.Item{
+		{ID: "c", Value: item{ID: "c", Name: "C"}},
+	}))
+	recs, err = store.List(ctx, "thing", "p1")
+	require.NoError(t, err)
+	assert.Len(t, recs, 1)
+
+	// Invalidating the listing keeps the marker: the next read is a delta.
+	store.FullRefreshAfter = time.Hour
+	require.NoError(t, store.Invalidate(ctx, scope))
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.False(t, store.Since(*state).IsZero())
+}
+
+func TestStore_Staleness(t *testing.T) {
+	ctx := context.Background()
+	store := newCache(t).Store
+	scope := shared_offline.Scope("thing", "p1")
+
+	state, err := store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.True(t, store.Staleness(*state, true).Stale, "never synced")
+
+	require.NoError(t, store.MarkSynced(ctx, scope, 42))
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), state.Cursor)
+	assert.False(t, store.Staleness(*state, true).Stale)
+	assert.True(t, store.Staleness(*state, false).Stale, "offline data is stale")
+
+	store.StaleAfter = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	assert.True(t, store.Staleness(*state, true).Stale)
+
+	store.StaleAfter = time.Hour
+	require.NoError(t, store.Invalidate(ctx, scope))
+	state, err = store.SyncState(ctx, scope)
+	require.NoError(t, err)
+	assert.True(t, store.Staleness(*state, true).Stale)
+	assert.Equal(t, uint64(42), state.Cursor, "invalidate keeps the cursor")
+}
+
+func TestMutationQueue_ReplayInOrder(t *testing.T) {
+	ctx := context.Background()
+	queue := newCache(t).Queue
+
+	var replayed []string
+	offline := true
+	queue.Register("op", func(ctx context.Context, payload json.RawMessage) error {
+		if offline {
+			return errUnreachable
+		}
+		var v string
+		require.NoError(t, json.Unmarshal(payload, &v))
+		replayed = append(replayed, v)
+		return nil
+	})
+	queue.Register("rejected", func(ctx context.Context, payload json.RawMessage) error {
+		return errors.New("server error 400")
+	})
+
+	for _, v := range []string{"first", "second"} {
+		_, err := queue.Enqueue(ctx, "op", v)
+		require.NoError(t, err)
+	}
+	_, err := queue.Enqueue(ctx, "rejected", "x")
+	require.NoError(t, err)
+
+	res, err := queue.Replay(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Replayed)
+	assert.Equal(t, 3, res.Pending)
+
+	offline = false
+	res, err = queue.Replay(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Replayed)
+	assert.Equal(t, 1, res.Failed)
+	assert.Equal(t, []string{"first", "second"}, replayed)
+
+	pending, err := queue.Pending(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "rejected", pending[0].Operation)
+	assert.Equal(t, 1, pending[0].Attempts, "the offline pass stopped before it")
+}
+
+func TestMutationQueue_ReplaysOnlyTheUsersOwnMutations(t *testing.T) {
+	ctx := context.Background()
+	queue := newCache(t).Queue
+
+	var replayed []string
+	queue.Register("op", func(ctx context.Context, payload json.RawMessage) error {
+		var v string
+		require.NoError(t, json.Unmarshal(payload, &v))
+		replayed = append(replayed, v)
+		return nil
+	})
+
+	_, err := queue.Enqueue(ctx, "op", "mine")
+	require.NoError(t, err)
+	queue.SetUser("user-2")
+	_, err = queue.Enqueue(ctx, "op", "theirs")
+	require.NoError(t, err)
+
+	pending, err := queue.Pending(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "user-1", pending[0].UserID)
+
+	res, err := queue.Replay(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Replayed)
+	assert.Equal(t, []string{"mine"}, replayed)
+
+	pending, err = queue.Pending(ctx, "user-2")
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "another user's session does not replay it")
+
+	queue.SetUser("")
+	_, err = queue.Enqueue(ctx, "op", "nobody")
+	assert.ErrorIs(t, err, shared_offline.ErrNoQueueUser)
+}
+
+func TestIsOfflineError_OnlyConnectFailuresAndTimeouts(t *testing.T) {
+	assert.True(t, shared_offline.IsOfflineError(errUnreachable))
+	assert.True(t, shared_offline.IsOfflineError(&url.Error{Op: "Get", URL: "http://cloud", Err: &net.DNSError{Err: "no such host", Name: "cloud", IsNotFound: true}}))
+	assert.True(t, shared_offline.IsOfflineError(fmt.Errorf("request failed: %w", context.DeadlineExceeded)))
+
+	// The request reached the cloud: not an offline condition.
+	assert.False(t, shared_offline.IsOfflineError(&url.Error{Op: "Get", URL: "http://cloud", Err: errors.New("stopped after 10 redirects")}))
+	assert.False(t, shared_offline.IsOfflineError(&url.Error{Op: "Post", URL: "http://cloud", Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}))
+	assert.False(t, shared_offline.IsOfflineError(errors.New("server error 500")))
+}
+
+func TestConnectivity_ReplaysOnReconnect(t *testing.T) {
+	ctx := context.Background()
+	cache := newCache(t)
+
+	done := make(chan struct{}, 1)
+	cache.Queue.Register("op", func(ctx context.Context, payload json.RawMessage) error {
+		done <- struct{}{}
+		return nil
+	})
+	_, err := cache.Queue.Enqueue(ctx, "op", "v")
+	require.NoError(t, err)
+
+	assert.True(t, cache.Connectivity.Observe(ctx, errUnreachable))
+	assert.False(t, cache.Connectivity.Online())
+	assert.False(t, cache.Connectivity.Observe(ctx, errors.New("server error 500")), "cloud answered")
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued mutation was not replayed on reconnect")
+	}
+
+	status, err := cache.Status(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+}
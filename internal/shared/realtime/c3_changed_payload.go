@@ -0,0 +1,11 @@
+package shared_realtime
+
+// C3ChangedPayload announces that a C3 aggregate changed on the Cloud.
+// ParentID is the listing the aggregate belongs to (vault, workspace,
+// channel or thread).
+type C3ChangedPayload struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id"`
+	Cursor   uint64 `json:"cursor,omitempty"`
+}
@@ -0,0 +1,13 @@
+package shared_realtime
+
+// ChannelInvitationPayload announces a channel invitation lifecycle change.
+// The Cloud routes it to the invitee and the inviter.
+type ChannelInvitationPayload struct {
+	NotificationPayload
+	InvitationID   string `json:"invitation_id"`
+	ChannelID      string `json:"channel_id"`
+	InviterVaultID string `json:"inviter_vault_id"`
+	InviteeVaultID string `json:"invitee_vault_id"`
+	Status         string `json:"status"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+}
@@ -10,4 +10,15 @@ const (
     ShareReadyToAccept = "share.ready_to_accept"
     
     NotificationAck = "notification.ack"
+
+    C3WorkspaceChanged  = "c3.workspace.changed"
+    C3ChannelChanged    = "c3.channel.changed"
+    C3ThreadChanged     = "c3.thread.changed"
+    C3ThreadEventAppended = "c3.thread.event_appended"
+
+    ChannelInvitationReceived = "channel.invitation"
+    ChannelInvitationAccepted = "channel.invitation.accepted"
+    ChannelInvitationRejected = "channel.invitation.rejected"
+    ChannelInvitationRevoked  = "channel.invitation.revoked"
+    ChannelInvitationExpired  = "channel.invitation.expired"
 )
\ No newline at end of file
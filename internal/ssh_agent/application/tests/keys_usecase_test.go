@@ -0,0 +1,140 @@
+package ssh_agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	ssh_agent_usecases "vault-app/internal/ssh_agent/application/usecases"
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type vaultMock struct {
+	vp     vaults_domain.VaultPayload
+	locked bool
+	dirty  int
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	return &m.vp, nil
+}
+
+func (m *vaultMock) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	e := entry.(*vaults_domain.SSHKeyEntry)
+	e.ID = "generated-1"
+	m.vp.Entries.SSHKey = append(m.vp.Entries.SSHKey, *e)
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) UpdateEntryFor(_ string, entry any, _ bool) (*vaults_domain.VaultEntry, error) {
+	e := entry.(*vaults_domain.SSHKeyEntry)
+	for i := range m.vp.Entries.SSHKey {
+		if m.vp.Entries.SSHKey[i].ID == e.ID {
+			m.vp.Entries.SSHKey[i] = *e
+		}
+	}
+	var ve vaults_domain.VaultEntry = e
+	return &ve, nil
+}
+
+func (m *vaultMock) MarkDirty(string) { m.dirty++ }
+
+func TestGenerateKey_StoresUsableKeyInVault(t *testing.T) {
+	for _, tc := range []struct {
+		keyType ssh_agent_domain.KeyType
+		algo    string
+	}{
+		{ssh_agent_domain.KeyTypeEd25519, ssh.KeyAlgoED25519},
+		{ssh_agent_domain.KeyTypeECDSAP256, ssh.KeyAlgoECDSA256},
+		{ssh_agent_domain.KeyTypeECDSAP384, ssh.KeyAlgoECDSA384},
+		{ssh_agent_domain.KeyTypeECDSAP521, ssh.KeyAlgoECDSA521},
+	} {
+		t.Run(string(tc.keyType), func(t *testing.T) {
+			vault := &vaultMock{}
+			uc := ssh_agent_usecases.NewGenerateKeyUsecase(vault)
+
+			entry, err := uc.Execute(context.Background(), ssh_agent_usecases.GenerateKeyRequest{
+				UserID:  "user-1",
+				Type:    tc.keyType,
+				Name:    "Deploy",
+				Comment: "deploy@ci",
+				Policy:  ssh_agent_domain.KeyPolicy{Confirm: true, Hosts: []string{" git.example.com ", ""}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, "generated-1", entry.ID)
+			require.Equal(t, 1, vault.dirty)
+			require.Len(t, vault.vp.Entries.SSHKey, 1)
+
+			signer, err := ssh.ParsePrivateKey([]byte(entry.PrivateKey))
+			require.NoError(t, err)
+			require.Equal(t, tc.algo, signer.PublicKey().Type())
+
+			pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(entry.PublicKey))
+			require.NoError(t, err)
+			require.Equal(t, "deploy@ci", comment)
+			require.Equal(t, signer.PublicKey().Marshal(), pub.Marshal())
+			require.Equal(t, ssh.FingerprintSHA256(pub), entry.EFingerprint)
+
+			policy := ssh_agent_domain.PolicyOf(entry)
+			require.True(t, policy.Confirm)
+			require.Equal(t, []string{"git.example.com"}, policy.Hosts)
+		})
+	}
+}
+
+func TestGenerateKey_Errors(t *testing.T) {
+	vault := &vaultMock{}
+	uc := ssh_agent_usecases.NewGenerateKeyUsecase(vault)
+	ctx := context.Background()
+
+	_, err := uc.Execute(ctx, ssh_agent_usecases.GenerateKeyRequest{UserID: "user-1", Type: "rsa"})
+	require.ErrorIs(t, err, ssh_agent_domain.ErrUnsupportedKeyType)
+
+	_, err = uc.Execute(ctx, ssh_agent_usecases.GenerateKeyRequest{Type: ssh_agent_domain.KeyTypeEd25519})
+	require.ErrorIs(t, err, ssh_agent_domain.ErrUserIDRequired)
+
+	vault.locked = true
+	_, err = uc.Execute(ctx, ssh_agent_usecases.GenerateKeyRequest{UserID: "user-1"})
+	require.ErrorIs(t, err, ssh_agent_domain.ErrVaultLocked)
+	require.Empty(t, vault.vp.Entries.SSHKey)
+}
+
+func TestSetKeyPolicy(t *testing.T) {
+	key := vaults_domain.SSHKeyEntry{PrivateKey: "pem"}
+	key.ID = "key-1"
+	key.CustomFields = vaults_domain.JSONMap{"passphrase": "secret"}
+	vault := &vaultMock{}
+	vault.vp.Entries.SSHKey = []vaults_domain.SSHKeyEntry{key}
+	uc := ssh_agent_usecases.NewSetKeyPolicyUsecase(vault)
+	ctx := context.Background()
+
+	updated, err := uc.Execute(ctx, "user-1", "key-1", ssh_agent_domain.KeyPolicy{LifetimeSeconds: 3600})
+	require.NoError(t, err)
+	require.Equal(t, 3600, ssh_agent_domain.PolicyOf(updated).LifetimeSeconds)
+	stored := &vault.vp.Entries.SSHKey[0]
+	require.Equal(t, 3600, ssh_agent_domain.PolicyOf(stored).LifetimeSeconds)
+	require.Equal(t, "secret", stored.CustomFields["passphrase"])
+	require.NotContains(t, key.CustomFields, ssh_agent_domain.PolicyField, "the original entry map is not mutated")
+
+	// Clearing the policy removes the field.
+	_, err = uc.Execute(ctx, "user-1", "key-1", ssh_agent_domain.KeyPolicy{})
+	require.NoError(t, err)
+	require.NotContains(t, vault.vp.Entries.SSHKey[0].CustomFields, ssh_agent_domain.PolicyField)
+
+	// The JSON string form left by flat exports is still understood.
+	stored.CustomFields[ssh_agent_domain.PolicyField] = `{"confirm":true,"hosts":["a"]}`
+	policy := ssh_agent_domain.PolicyOf(stored)
+	require.True(t, policy.Confirm)
+	require.Equal(t, []string{"a"}, policy.Hosts)
+
+	_, err = uc.Execute(ctx, "user-1", "missing", ssh_agent_domain.KeyPolicy{})
+	require.ErrorIs(t, err, ssh_agent_domain.ErrKeyNotFound)
+}
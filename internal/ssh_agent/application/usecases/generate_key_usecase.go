@@ -0,0 +1,124 @@
+package ssh_agent_usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type GenerateKeyRequest struct {
+	UserID string
+	Type   ssh_agent_domain.KeyType
+	Name   string
+	// Comment ends up in the authorized_keys line; defaults to Name.
+	Comment  string
+	FolderID string
+	Policy   ssh_agent_domain.KeyPolicy
+}
+
+// GenerateKeyUsecase creates a key pair directly inside the vault. The
+// private key never touches the disk.
+type GenerateKeyUsecase struct {
+	Vault VaultEntries
+	Rand  io.Reader
+}
+
+func NewGenerateKeyUsecase(vault VaultEntries) *GenerateKeyUsecase {
+	return &GenerateKeyUsecase{Vault: vault, Rand: rand.Reader}
+}
+
+func (uc *GenerateKeyUsecase) Execute(ctx context.Context, req GenerateKeyRequest) (*vaults_domain.SSHKeyEntry, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" {
+		return nil, ssh_agent_domain.ErrUserIDRequired
+	}
+	if req.Type == "" {
+		req.Type = ssh_agent_domain.KeyTypeEd25519
+	}
+	if !req.Type.Valid() {
+		return nil, fmt.Errorf("%w: %s", ssh_agent_domain.ErrUnsupportedKeyType, req.Type)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "SSH key " + string(req.Type)
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		comment = name
+	}
+	// The vault must be unlocked before a key is generated for it.
+	if _, err := uc.Vault.GetVaultSession(req.UserID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrVaultLocked, err)
+	}
+
+	priv, err := uc.generate(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, fmt.Errorf("marshal private key: %w", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	pub := signer.PublicKey()
+
+	entry := &vaults_domain.SSHKeyEntry{
+		PrivateKey:   string(pem.EncodeToMemory(block)),
+		PublicKey:    string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(pub))) + " " + comment,
+		EFingerprint: ssh.FingerprintSHA256(pub),
+	}
+	entry.EntryName = name
+	entry.FolderID = req.FolderID
+	entry.Type = vaults_domain.EntrySSHKey
+	ssh_agent_domain.SetPolicy(entry, req.Policy)
+
+	if _, err := uc.Vault.AddEntryFor(req.UserID, entry); err != nil {
+		return nil, err
+	}
+	uc.Vault.MarkDirty(req.UserID)
+	return entry, nil
+}
+
+func (uc *GenerateKeyUsecase) generate(t ssh_agent_domain.KeyType) (crypto.PrivateKey, error) {
+	random := uc.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+	switch t {
+	case ssh_agent_domain.KeyTypeEd25519:
+		_, priv, err := ed25519.GenerateKey(random)
+		return priv, err
+	case ssh_agent_domain.KeyTypeECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), random)
+	case ssh_agent_domain.KeyTypeECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), random)
+	case ssh_agent_domain.KeyTypeECDSAP521:
+		return ecdsa.GenerateKey(elliptic.P521(), random)
+	}
+	return nil, fmt.Errorf("%w: %s", ssh_agent_domain.ErrUnsupportedKeyType, t)
+}
+
+func (uc *GenerateKeyUsecase) ValidateDependencies() error {
+	if uc.Vault == nil {
+		return ssh_agent_domain.ErrVaultRequired
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package ssh_agent_usecases
+
+import (
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultEntries is the part of the vault handler that key management writes
+// through, so new and edited keys follow the normal commit path.
+type VaultEntries interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+	AddEntryFor(userID string, entry any) (*vaults_domain.VaultEntry, error)
+	UpdateEntryFor(userID string, entry any, isSyncMode bool) (*vaults_domain.VaultEntry, error)
+	MarkDirty(userID string)
+}
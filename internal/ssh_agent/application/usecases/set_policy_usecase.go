@@ -0,0 +1,56 @@
+package ssh_agent_usecases
+
+import (
+	"context"
+	"fmt"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// SetKeyPolicyUsecase stores the agent policy of an SSH key entry. The
+// running agent reads it from the session on the next request.
+type SetKeyPolicyUsecase struct {
+	Vault VaultEntries
+}
+
+func NewSetKeyPolicyUsecase(vault VaultEntries) *SetKeyPolicyUsecase {
+	return &SetKeyPolicyUsecase{Vault: vault}
+}
+
+func (uc *SetKeyPolicyUsecase) Execute(ctx context.Context, userID string, entryID string, policy ssh_agent_domain.KeyPolicy) (*vaults_domain.SSHKeyEntry, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ssh_agent_domain.ErrUserIDRequired
+	}
+	vp, err := uc.Vault.GetVaultSession(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrVaultLocked, err)
+	}
+	for _, e := range vp.Entries.SSHKey {
+		if e.ID != entryID || e.Trashed {
+			continue
+		}
+		// Work on a copy so a failed update leaves the session untouched.
+		updated := e
+		updated.CustomFields = make(vaults_domain.JSONMap, len(e.CustomFields)+1)
+		for k, v := range e.CustomFields {
+			updated.CustomFields[k] = v
+		}
+		ssh_agent_domain.SetPolicy(&updated, policy)
+		if _, err := uc.Vault.UpdateEntryFor(userID, &updated, false); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ssh_agent_domain.ErrKeyNotFound, entryID)
+}
+
+func (uc *SetKeyPolicyUsecase) ValidateDependencies() error {
+	if uc.Vault == nil {
+		return ssh_agent_domain.ErrVaultRequired
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package ssh_agent_domain
+
+import "errors"
+
+var (
+	ErrVaultLocked          = errors.New("vault is locked")
+	ErrKeyNotFound          = errors.New("ssh key not found in vault")
+	ErrKeyExpired           = errors.New("ssh key lifetime expired")
+	ErrConfirmationDenied   = errors.New("key use was not confirmed")
+	ErrHostNotAllowed       = errors.New("key is not allowed for this host")
+	ErrUnboundSession       = errors.New("key is host-restricted and the client did not bind a session")
+	ErrUnsupportedKeyType   = errors.New("unsupported ssh key type")
+	ErrExternalKeys         = errors.New("keys are managed in the vault; ssh-add cannot add keys")
+	ErrAgentLocked          = errors.New("agent is locked")
+	ErrAgentRunning         = errors.New("ssh agent is already running")
+	ErrAgentNotRunning      = errors.New("ssh agent is not running")
+	ErrAgentNotOwned        = errors.New("ssh agent was started by another user")
+	ErrInvalidSessionBind   = errors.New("invalid session-bind request")
+	ErrConfirmationNotFound = errors.New("confirmation request not found or already answered")
+	ErrUserIDRequired       = errors.New("user id is required")
+	ErrVaultRequired        = errors.New("vault session is required")
+)
@@ -0,0 +1,159 @@
+package ssh_agent_domain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// KeyType names the algorithms GenerateKey can create.
+type KeyType string
+
+const (
+	KeyTypeEd25519   KeyType = "ed25519"
+	KeyTypeECDSAP256 KeyType = "ecdsa-p256"
+	KeyTypeECDSAP384 KeyType = "ecdsa-p384"
+	KeyTypeECDSAP521 KeyType = "ecdsa-p521"
+)
+
+func (t KeyType) Valid() bool {
+	switch t {
+	case KeyTypeEd25519, KeyTypeECDSAP256, KeyTypeECDSAP384, KeyTypeECDSAP521:
+		return true
+	}
+	return false
+}
+
+const (
+	// PolicyField is the SSHKeyEntry custom field holding the KeyPolicy.
+	PolicyField = "ssh_agent"
+	// PassphraseField holds the passphrase of an encrypted private key; it
+	// is the field the importers already fill.
+	PassphraseField = "passphrase"
+)
+
+// KeyPolicy restricts how the agent may use a key.
+type KeyPolicy struct {
+	// Disabled keeps the key out of the agent entirely.
+	Disabled bool `json:"disabled,omitempty"`
+	// Confirm asks the user before every signature.
+	Confirm bool `json:"confirm,omitempty"`
+	// Hosts limits signatures to sessions bound to these destinations:
+	// host names or patterns checked against known_hosts, or SHA256:
+	// host key fingerprints. Empty means any host.
+	Hosts []string `json:"hosts,omitempty"`
+	// LifetimeSeconds drops the key this long after the agent loaded it.
+	// Zero means until the vault is locked.
+	LifetimeSeconds int `json:"lifetime_seconds,omitempty"`
+}
+
+func (p KeyPolicy) Lifetime() time.Duration {
+	return time.Duration(p.LifetimeSeconds) * time.Second
+}
+
+func (p KeyPolicy) HostRestricted() bool {
+	return len(p.Hosts) > 0
+}
+
+// PolicyOf reads the policy of an entry. Both the nested object written by
+// SetPolicy and its JSON string form (as left by a flat export) are read.
+func PolicyOf(e *vaults_domain.SSHKeyEntry) KeyPolicy {
+	var p KeyPolicy
+	raw, ok := e.CustomFields[PolicyField]
+	if !ok || raw == nil {
+		return p
+	}
+	var data []byte
+	if s, isString := raw.(string); isString {
+		data = []byte(s)
+	} else {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return p
+		}
+		data = b
+	}
+	_ = json.Unmarshal(data, &p)
+	return p
+}
+
+// SetPolicy stores p on the entry, removing the field when p is the zero policy.
+func SetPolicy(e *vaults_domain.SSHKeyEntry, p KeyPolicy) {
+	hosts := make([]string, 0, len(p.Hosts))
+	for _, h := range p.Hosts {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	p.Hosts = hosts
+	if p.LifetimeSeconds < 0 {
+		p.LifetimeSeconds = 0
+	}
+	if !p.Disabled && !p.Confirm && len(p.Hosts) == 0 && p.LifetimeSeconds == 0 {
+		delete(e.CustomFields, PolicyField)
+		return
+	}
+	if e.CustomFields == nil {
+		e.CustomFields = vaults_domain.JSONMap{}
+	}
+	m := map[string]any{}
+	if p.Disabled {
+		m["disabled"] = true
+	}
+	if p.Confirm {
+		m["confirm"] = true
+	}
+	if len(p.Hosts) > 0 {
+		list := make([]any, len(p.Hosts))
+		for i, h := range p.Hosts {
+			list[i] = h
+		}
+		m["hosts"] = list
+	}
+	if p.LifetimeSeconds > 0 {
+		m["lifetime_seconds"] = p.LifetimeSeconds
+	}
+	e.CustomFields[PolicyField] = m
+}
+
+// KeyInfo describes a key as currently served by the agent.
+type KeyInfo struct {
+	EntryID     string    `json:"entry_id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Fingerprint string    `json:"fingerprint"`
+	PublicKey   string    `json:"public_key"`
+	Policy      KeyPolicy `json:"policy"`
+	LoadedAt    time.Time `json:"loaded_at"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+}
+
+// ConfirmRequest is shown to the user before a confirm-policy key signs.
+type ConfirmRequest struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	EntryID     string `json:"entry_id"`
+	KeyName     string `json:"key_name"`
+	Fingerprint string `json:"fingerprint"`
+	// Host is the destination the client bound the session to, if any.
+	Host      string `json:"host,omitempty"`
+	Forwarded bool   `json:"forwarded"`
+}
+
+// Confirmer asks the user to approve a signature. A nil error approves it.
+type Confirmer interface {
+	Confirm(req ConfirmRequest) error
+}
+
+// ConfirmerFunc adapts a function to Confirmer.
+type ConfirmerFunc func(req ConfirmRequest) error
+
+func (f ConfirmerFunc) Confirm(req ConfirmRequest) error { return f(req) }
+
+// VaultSession gives the agent a live view of the unlocked vault. An error
+// means the vault is locked and no key may be served.
+type VaultSession interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+}
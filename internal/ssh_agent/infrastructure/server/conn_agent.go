@@ -0,0 +1,273 @@
+package ssh_agent_server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+)
+
+const (
+	sessionBindExtension = "session-bind@openssh.com"
+	// maxSessionBinds mirrors OpenSSH's limit per agent connection.
+	maxSessionBinds = 16
+)
+
+// sessionBind is what an OpenSSH client records on the agent connection
+// after key exchange: the destination host key and the session it signed.
+type sessionBind struct {
+	hostKey    ssh.PublicKey
+	sessionID  []byte
+	forwarding bool
+}
+
+// connAgent is the agent.ExtendedAgent of a single client connection. The
+// session binds it collects are what host-restricted keys are checked
+// against, so they must never outlive the connection.
+type connAgent struct {
+	ring       *Keyring
+	confirmer  ssh_agent_domain.Confirmer
+	knownHosts []string
+	binds      []sessionBind
+}
+
+var _ agent.ExtendedAgent = (*connAgent)(nil)
+
+func (a *connAgent) List() ([]*agent.Key, error) {
+	keys, err := a.ring.active()
+	if errors.Is(err, ssh_agent_domain.ErrVaultLocked) || errors.Is(err, ssh_agent_domain.ErrAgentLocked) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*agent.Key, 0, len(keys))
+	for _, k := range keys {
+		// Host-restricted keys are only offered where they may be used.
+		if k.policy.HostRestricted() {
+			if _, err := a.allowedHost(k.policy); err != nil {
+				continue
+			}
+		}
+		pub := k.signer.PublicKey()
+		out = append(out, &agent.Key{Format: pub.Type(), Blob: pub.Marshal(), Comment: k.name})
+	}
+	return out, nil
+}
+
+func (a *connAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+func (a *connAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	k, err := a.ring.find(key)
+	if err != nil {
+		return nil, err
+	}
+
+	host := ""
+	if k.policy.HostRestricted() {
+		if host, err = a.allowedHost(k.policy); err != nil {
+			return nil, err
+		}
+		// The user-auth request must belong to the bound session, or a
+		// signature for another server could be obtained.
+		if !bytes.Equal(leadingString(data), a.binds[len(a.binds)-1].sessionID) {
+			return nil, ssh_agent_domain.ErrHostNotAllowed
+		}
+	} else if len(a.binds) > 0 {
+		host = ssh.FingerprintSHA256(a.binds[len(a.binds)-1].hostKey)
+	}
+
+	if k.policy.Confirm {
+		if a.confirmer == nil {
+			return nil, ssh_agent_domain.ErrConfirmationDenied
+		}
+		err := a.confirmer.Confirm(ssh_agent_domain.ConfirmRequest{
+			ID:          uuid.NewString(),
+			UserID:      a.ring.UserID,
+			EntryID:     k.entryID,
+			KeyName:     k.name,
+			Fingerprint: ssh.FingerprintSHA256(k.signer.PublicKey()),
+			Host:        host,
+			Forwarded:   a.forwarded(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrConfirmationDenied, err)
+		}
+	}
+
+	if algo := rsaAlgorithm(flags); algo != "" {
+		if as, ok := k.signer.(ssh.AlgorithmSigner); ok && k.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+			return as.SignWithAlgorithm(rand.Reader, data, algo)
+		}
+	}
+	return k.signer.Sign(rand.Reader, data)
+}
+
+func (a *connAgent) Add(agent.AddedKey) error {
+	return ssh_agent_domain.ErrExternalKeys
+}
+
+func (a *connAgent) Remove(key ssh.PublicKey) error {
+	return a.ring.remove(key)
+}
+
+func (a *connAgent) RemoveAll() error {
+	return a.ring.removeAll()
+}
+
+func (a *connAgent) Lock(passphrase []byte) error {
+	return a.ring.lock(passphrase)
+}
+
+func (a *connAgent) Unlock(passphrase []byte) error {
+	return a.ring.unlock(passphrase)
+}
+
+// Signers is not reachable over the wire; it is refused so policies cannot
+// be bypassed from inside the process either.
+func (a *connAgent) Signers() ([]ssh.Signer, error) {
+	return nil, errors.New("signers are not exported by the vault agent")
+}
+
+func (a *connAgent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	if extensionType != sessionBindExtension {
+		return nil, agent.ErrExtensionUnsupported
+	}
+	var msg struct {
+		HostKey    []byte
+		SessionID  []byte
+		Signature  []byte
+		Forwarding bool
+	}
+	if err := ssh.Unmarshal(contents, &msg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrInvalidSessionBind, err)
+	}
+	hostKey, err := ssh.ParsePublicKey(msg.HostKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrInvalidSessionBind, err)
+	}
+	var sig ssh.Signature
+	if err := ssh.Unmarshal(msg.Signature, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ssh_agent_domain.ErrInvalidSessionBind, err)
+	}
+	if err := hostKey.Verify(msg.SessionID, &sig); err != nil {
+		return nil, fmt.Errorf("%w: host key signature: %v", ssh_agent_domain.ErrInvalidSessionBind, err)
+	}
+	for _, b := range a.binds {
+		if bytes.Equal(b.sessionID, msg.SessionID) {
+			if !bytes.Equal(b.hostKey.Marshal(), hostKey.Marshal()) {
+				return nil, fmt.Errorf("%w: session rebound to another host", ssh_agent_domain.ErrInvalidSessionBind)
+			}
+			return nil, nil
+		}
+	}
+	if len(a.binds) >= maxSessionBinds {
+		return nil, fmt.Errorf("%w: too many session binds", ssh_agent_domain.ErrInvalidSessionBind)
+	}
+	a.binds = append(a.binds, sessionBind{hostKey: hostKey, sessionID: msg.SessionID, forwarding: msg.Forwarding})
+	return nil, nil
+}
+
+func (a *connAgent) forwarded() bool {
+	for _, b := range a.binds {
+		if b.forwarding {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedHost returns the policy host that matches the destination the
+// connection is bound to. Host-restricted keys are never used through agent
+// forwarding: past the first hop the final destination cannot be verified.
+func (a *connAgent) allowedHost(policy ssh_agent_domain.KeyPolicy) (string, error) {
+	if len(a.binds) == 0 {
+		return "", ssh_agent_domain.ErrUnboundSession
+	}
+	if a.forwarded() {
+		return "", ssh_agent_domain.ErrHostNotAllowed
+	}
+	hostKey := a.binds[len(a.binds)-1].hostKey
+	fingerprint := ssh.FingerprintSHA256(hostKey)
+
+	var check ssh.HostKeyCallback
+	for _, host := range policy.Hosts {
+		if strings.HasPrefix(host, "SHA256:") {
+			if host == fingerprint {
+				return host, nil
+			}
+			continue
+		}
+		if check == nil {
+			check = a.knownHostsCallback()
+		}
+		if check(hostAddress(host), &net.TCPAddr{IP: net.IPv4zero, Port: 22}, hostKey) == nil {
+			return host, nil
+		}
+	}
+	return "", ssh_agent_domain.ErrHostNotAllowed
+}
+
+// knownHostsCallback reads the known_hosts files on every check so hosts
+// trusted after the agent started are honoured. Missing files are skipped.
+func (a *connAgent) knownHostsCallback() ssh.HostKeyCallback {
+	var files []string
+	for _, f := range a.knownHosts {
+		if _, err := os.Stat(f); err == nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return func(string, net.Addr, ssh.PublicKey) error { return ssh_agent_domain.ErrHostNotAllowed }
+	}
+	cb, err := knownhosts.New(files...)
+	if err != nil {
+		return func(string, net.Addr, ssh.PublicKey) error { return err }
+	}
+	return cb
+}
+
+// hostAddress turns "host" or "[host]:port" into the address form the
+// known_hosts callback expects.
+func hostAddress(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, "22")
+}
+
+// leadingString returns the first SSH string of data, which for a
+// user-auth signature request is the session identifier.
+func leadingString(data []byte) []byte {
+	if len(data) < 4 {
+		return nil
+	}
+	n := binary.BigEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(n) {
+		return nil
+	}
+	return data[4 : 4+n]
+}
+
+func rsaAlgorithm(flags agent.SignatureFlags) string {
+	switch {
+	case flags&agent.SignatureFlagRsaSha512 != 0:
+		return ssh.KeyAlgoRSASHA512
+	case flags&agent.SignatureFlagRsaSha256 != 0:
+		return ssh.KeyAlgoRSASHA256
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+package ssh_agent_server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// loadedKey is an SSHKeyEntry parsed into a signer. Entries that fail to
+// parse are remembered with err set so they are not parsed on every request.
+type loadedKey struct {
+	entryID  string
+	name     string
+	source   string
+	signer   ssh.Signer
+	err      error
+	policy   ssh_agent_domain.KeyPolicy
+	loadedAt time.Time
+}
+
+func (k *loadedKey) expired(now time.Time) bool {
+	lifetime := k.policy.Lifetime()
+	return lifetime > 0 && !now.Before(k.loadedAt.Add(lifetime))
+}
+
+func (k *loadedKey) info() ssh_agent_domain.KeyInfo {
+	pub := k.signer.PublicKey()
+	info := ssh_agent_domain.KeyInfo{
+		EntryID:     k.entryID,
+		Name:        k.name,
+		Type:        pub.Type(),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		PublicKey:   string(bytes.TrimSpace(ssh.MarshalAuthorizedKey(pub))),
+		Policy:      k.policy,
+		LoadedAt:    k.loadedAt,
+	}
+	if lifetime := k.policy.Lifetime(); lifetime > 0 {
+		info.ExpiresAt = k.loadedAt.Add(lifetime)
+	}
+	return info
+}
+
+// Keyring serves the SSH keys of one user's vault. It holds no key of its
+// own: every request re-reads the vault session, so keys disappear as soon
+// as the vault is locked and edits to entries are picked up immediately.
+type Keyring struct {
+	Vault  ssh_agent_domain.VaultSession
+	UserID string
+	Now    func() time.Time
+
+	mu       sync.Mutex
+	loaded   map[string]*loadedKey
+	removed  map[string]bool
+	lockPass []byte
+}
+
+func NewKeyring(vault ssh_agent_domain.VaultSession, userID string) *Keyring {
+	return &Keyring{
+		Vault:   vault,
+		UserID:  userID,
+		Now:     time.Now,
+		loaded:  map[string]*loadedKey{},
+		removed: map[string]bool{},
+	}
+}
+
+// Infos lists the keys the agent currently serves.
+func (r *Keyring) Infos() ([]ssh_agent_domain.KeyInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys, err := r.activeLocked()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ssh_agent_domain.KeyInfo, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k.info())
+	}
+	return out, nil
+}
+
+// Clear forgets every parsed key, lifetime and removal. It is called when
+// the vault locks so the next unlock starts from a clean state.
+func (r *Keyring) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clearLocked()
+}
+
+func (r *Keyring) clearLocked() {
+	r.loaded = map[string]*loadedKey{}
+	r.removed = map[string]bool{}
+}
+
+func (r *Keyring) active() ([]*loadedKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.activeLocked()
+}
+
+func (r *Keyring) find(pub ssh.PublicKey) (*loadedKey, error) {
+	keys, err := r.active()
+	if err != nil {
+		return nil, err
+	}
+	wanted := pub.Marshal()
+	for _, k := range keys {
+		if bytes.Equal(k.signer.PublicKey().Marshal(), wanted) {
+			return k, nil
+		}
+	}
+	// Tell an expired key apart from an unknown one.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.loaded {
+		if k.signer != nil && bytes.Equal(k.signer.PublicKey().Marshal(), wanted) && k.expired(r.Now()) {
+			return nil, ssh_agent_domain.ErrKeyExpired
+		}
+	}
+	return nil, ssh_agent_domain.ErrKeyNotFound
+}
+
+// activeLocked syncs the cache with the vault session and returns the keys
+// that may be used right now.
+func (r *Keyring) activeLocked() ([]*loadedKey, error) {
+	if r.lockPass != nil {
+		return nil, ssh_agent_domain.ErrAgentLocked
+	}
+	vp, err := r.Vault.GetVaultSession(r.UserID)
+	if err != nil || vp == nil {
+		r.clearLocked()
+		return nil, ssh_agent_domain.ErrVaultLocked
+	}
+
+	now := r.Now()
+	seen := make(map[string]bool, len(vp.Entries.SSHKey))
+	var keys []*loadedKey
+	for i := range vp.Entries.SSHKey {
+		entry := &vp.Entries.SSHKey[i]
+		policy := ssh_agent_domain.PolicyOf(entry)
+		if entry.Trashed || policy.Disabled || entry.PrivateKey == "" {
+			continue
+		}
+		seen[entry.ID] = true
+
+		source := entry.PrivateKey + "\x00" + passphrase(entry)
+		k, ok := r.loaded[entry.ID]
+		if !ok || k.source != source {
+			k = parseEntry(entry, source, now)
+			r.loaded[entry.ID] = k
+			delete(r.removed, entry.ID)
+		}
+		k.name, k.policy = entry.EntryName, policy
+		if k.err != nil || r.removed[entry.ID] || k.expired(now) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	for id := range r.loaded {
+		if !seen[id] {
+			delete(r.loaded, id)
+			delete(r.removed, id)
+		}
+	}
+	return keys, nil
+}
+
+// remove hides a key until the vault is locked or the entry changes, the
+// way ssh-add -d behaves for a key loaded from disk.
+func (r *Keyring) remove(pub ssh.PublicKey) error {
+	k, err := r.find(pub)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed[k.entryID] = true
+	return nil
+}
+
+func (r *Keyring) removeAll() error {
+	keys, err := r.active()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range keys {
+		r.removed[k.entryID] = true
+	}
+	return nil
+}
+
+func (r *Keyring) lock(passphrase []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lockPass != nil {
+		return ssh_agent_domain.ErrAgentLocked
+	}
+	r.lockPass = append([]byte{}, passphrase...)
+	return nil
+}
+
+func (r *Keyring) unlock(passphrase []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lockPass == nil {
+		return errors.New("agent is not locked")
+	}
+	if subtle.ConstantTimeCompare(r.lockPass, passphrase) != 1 {
+		return errors.New("incorrect passphrase")
+	}
+	r.lockPass = nil
+	return nil
+}
+
+func parseEntry(entry *vaults_domain.SSHKeyEntry, source string, now time.Time) *loadedKey {
+	k := &loadedKey{entryID: entry.ID, source: source, loadedAt: now}
+	if pass := passphrase(entry); pass != "" {
+		k.signer, k.err = ssh.ParsePrivateKeyWithPassphrase([]byte(entry.PrivateKey), []byte(pass))
+	} else {
+		k.signer, k.err = ssh.ParsePrivateKey([]byte(entry.PrivateKey))
+	}
+	return k
+}
+
+func passphrase(entry *vaults_domain.SSHKeyEntry) string {
+	s, _ := entry.CustomFields[ssh_agent_domain.PassphraseField].(string)
+	return s
+}
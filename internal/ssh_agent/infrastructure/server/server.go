@@ -0,0 +1,151 @@
+package ssh_agent_server
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh/agent"
+
+	shared_localsocket "vault-app/internal/shared/localsocket"
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+)
+
+// DefaultSocketPath is $XDG_RUNTIME_DIR/vaultcore/ssh-agent.sock, falling
+// back to ~/.vaultcore/ssh-agent.sock.
+func DefaultSocketPath() (string, error) {
+	return shared_localsocket.DefaultPath("ssh-agent.sock")
+}
+
+// DefaultKnownHosts returns the user's OpenSSH known_hosts file.
+func DefaultKnownHosts() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(home, ".ssh", "known_hosts")}
+}
+
+// Server speaks the ssh-agent protocol on a Unix socket. Every connection
+// gets its own agent so session binds are never shared between clients.
+type Server struct {
+	Keyring         *Keyring
+	Confirmer       ssh_agent_domain.Confirmer
+	KnownHostsFiles []string
+
+	mu       sync.Mutex
+	listener net.Listener
+	path     string
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+func NewServer(keyring *Keyring, confirmer ssh_agent_domain.Confirmer) *Server {
+	return &Server{
+		Keyring:         keyring,
+		Confirmer:       confirmer,
+		KnownHostsFiles: DefaultKnownHosts(),
+		conns:           map[net.Conn]struct{}{},
+	}
+}
+
+// Listen creates the socket, readable by the current user only, and starts
+// accepting clients in the background.
+func (s *Server) Listen(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return ssh_agent_domain.ErrAgentRunning
+	}
+	l, err := shared_localsocket.Listen(path, ssh_agent_domain.ErrAgentRunning)
+	if err != nil {
+		return err
+	}
+	s.listener, s.path = l, path
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				_ = s.ServeConn(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// ServeConn serves one client until it disconnects or the server closes.
+// A connection arriving while the server is not listening, such as one
+// accepted just before Close, is closed unserved.
+func (s *Server) ServeConn(conn net.Conn) error {
+	s.mu.Lock()
+	if s.listener == nil {
+		s.mu.Unlock()
+		conn.Close()
+		return ssh_agent_domain.ErrAgentNotRunning
+	}
+	if s.conns == nil {
+		s.conns = map[net.Conn]struct{}{}
+	}
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	err := agent.ServeAgent(&connAgent{
+		ring:       s.Keyring,
+		confirmer:  s.Confirmer,
+		knownHosts: s.KnownHostsFiles,
+	}, conn)
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener != nil
+}
+
+func (s *Server) SocketPath() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.path
+}
+
+// Close stops accepting clients, drops open connections and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	l, path := s.listener, s.path
+	s.listener, s.path = nil, ""
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	if l == nil {
+		return ssh_agent_domain.ErrAgentNotRunning
+	}
+	err := l.Close()
+	s.wg.Wait()
+	if rmErr := os.Remove(path); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,341 @@
+package ssh_agent_tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	ssh_agent_server "vault-app/internal/ssh_agent/infrastructure/server"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type vaultMock struct {
+	vp     vaults_domain.VaultPayload
+	locked bool
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	return &m.vp, nil
+}
+
+func newKeyEntry(t *testing.T, id string, policy ssh_agent_domain.KeyPolicy) (vaults_domain.SSHKeyEntry, ssh.PublicKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, id)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	e := vaults_domain.SSHKeyEntry{PrivateKey: string(pem.EncodeToMemory(block))}
+	e.ID, e.EntryName = id, id
+	ssh_agent_domain.SetPolicy(&e, policy)
+	return e, signer.PublicKey()
+}
+
+type fixture struct {
+	vault  *vaultMock
+	ring   *ssh_agent_server.Keyring
+	server *ssh_agent_server.Server
+	now    time.Time
+}
+
+func newFixture(keys ...vaults_domain.SSHKeyEntry) *fixture {
+	f := &fixture{vault: &vaultMock{}, now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	f.vault.vp.Entries.SSHKey = keys
+	f.ring = ssh_agent_server.NewKeyring(f.vault, "user-1")
+	f.ring.Now = func() time.Time { return f.now }
+	f.server = ssh_agent_server.NewServer(f.ring, nil)
+	f.server.KnownHostsFiles = nil
+	return f
+}
+
+// client connects an agent client to a fresh server connection, starting
+// the server on a temporary socket first.
+func (f *fixture) client(t *testing.T) agent.ExtendedAgent {
+	t.Helper()
+	if !f.server.Running() {
+		dir, err := os.MkdirTemp("", "agent")
+		require.NoError(t, err)
+		require.NoError(t, f.server.Listen(filepath.Join(dir, "agent.sock")))
+		t.Cleanup(func() {
+			f.server.Close()
+			os.RemoveAll(dir)
+		})
+	}
+	c, err := net.Dial("unix", f.server.SocketPath())
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return agent.NewClient(c)
+}
+
+func userAuthData(sessionID []byte) []byte {
+	return append(ssh.Marshal(struct{ S []byte }{sessionID}), "userauth-request"...)
+}
+
+// bind performs the session-bind@openssh.com handshake an OpenSSH client
+// sends after key exchange.
+func bind(t *testing.T, client agent.ExtendedAgent, hostKey ssh.Signer, sessionID []byte, forwarding bool) error {
+	t.Helper()
+	sig, err := hostKey.Sign(rand.Reader, sessionID)
+	require.NoError(t, err)
+	_, err = client.Extension("session-bind@openssh.com", ssh.Marshal(struct {
+		HostKey    []byte
+		SessionID  []byte
+		Signature  []byte
+		Forwarding bool
+	}{hostKey.PublicKey().Marshal(), sessionID, ssh.Marshal(sig), forwarding}))
+	return err
+}
+
+func hostSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	s, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAgent_ListsAndSignsWhileUnlocked(t *testing.T) {
+	deploy, deployPub := newKeyEntry(t, "deploy", ssh_agent_domain.KeyPolicy{})
+	disabled, _ := newKeyEntry(t, "disabled", ssh_agent_domain.KeyPolicy{Disabled: true})
+	trashed, _ := newKeyEntry(t, "trashed", ssh_agent_domain.KeyPolicy{})
+	trashed.Trashed = true
+	f := newFixture(deploy, disabled, trashed)
+	client := f.client(t)
+
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "deploy", keys[0].Comment)
+	require.Equal(t, deployPub.Marshal(), keys[0].Blob)
+
+	data := []byte("challenge")
+	sig, err := client.Sign(deployPub, data)
+	require.NoError(t, err)
+	require.NoError(t, deployPub.Verify(data, sig))
+
+	// Keys cannot be pushed in with ssh-add.
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	require.Error(t, client.Add(agent.AddedKey{PrivateKey: priv}))
+
+	// Locking the vault removes every key.
+	f.vault.locked = true
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	_, err = client.Sign(deployPub, data)
+	require.Error(t, err)
+
+	f.vault.locked = false
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+}
+
+func TestAgent_RemoveAndAgentLock(t *testing.T) {
+	a, aPub := newKeyEntry(t, "a", ssh_agent_domain.KeyPolicy{})
+	b, _ := newKeyEntry(t, "b", ssh_agent_domain.KeyPolicy{})
+	f := newFixture(a, b)
+	client := f.client(t)
+
+	require.NoError(t, client.Remove(aPub))
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "b", keys[0].Comment)
+
+	require.NoError(t, client.Lock([]byte("pass")))
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	require.Error(t, client.Unlock([]byte("wrong")))
+	require.NoError(t, client.Unlock([]byte("pass")))
+
+	require.NoError(t, client.RemoveAll())
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	// A lock/unlock cycle of the vault restores removed keys.
+	f.vault.locked = true
+	_, _ = client.List()
+	f.vault.locked = false
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+}
+
+func TestAgent_LifetimeLimit(t *testing.T) {
+	key, pub := newKeyEntry(t, "short", ssh_agent_domain.KeyPolicy{LifetimeSeconds: 60})
+	f := newFixture(key)
+	client := f.client(t)
+
+	infos, err := f.ring.Infos()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, f.now.Add(time.Minute), infos[0].ExpiresAt)
+
+	f.now = f.now.Add(59 * time.Second)
+	_, err = client.Sign(pub, []byte("x"))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(time.Second)
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	_, err = client.Sign(pub, []byte("x"))
+	require.Error(t, err)
+
+	// The lifetime restarts with the next unlock.
+	f.ring.Clear()
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+}
+
+func TestAgent_Confirmation(t *testing.T) {
+	key, pub := newKeyEntry(t, "prod", ssh_agent_domain.KeyPolicy{Confirm: true})
+	f := newFixture(key)
+	var asked []ssh_agent_domain.ConfirmRequest
+	approve := true
+	f.server.Confirmer = ssh_agent_domain.ConfirmerFunc(func(req ssh_agent_domain.ConfirmRequest) error {
+		asked = append(asked, req)
+		if !approve {
+			return errors.New("no")
+		}
+		return nil
+	})
+	client := f.client(t)
+
+	_, err := client.Sign(pub, []byte("x"))
+	require.NoError(t, err)
+	require.Len(t, asked, 1)
+	require.Equal(t, "prod", asked[0].KeyName)
+	require.Equal(t, "user-1", asked[0].UserID)
+	require.Equal(t, ssh.FingerprintSHA256(pub), asked[0].Fingerprint)
+
+	approve = false
+	_, err = client.Sign(pub, []byte("x"))
+	require.Error(t, err)
+	require.Len(t, asked, 2)
+}
+
+func TestAgent_HostConstraints(t *testing.T) {
+	github := hostSigner(t)
+	other := hostSigner(t)
+
+	dir := t.TempDir()
+	knownHosts := filepath.Join(dir, "known_hosts")
+	line := "git.example.com " + string(ssh.MarshalAuthorizedKey(github.PublicKey()))
+	require.NoError(t, os.WriteFile(knownHosts, []byte(line), 0o600))
+
+	byName, byNamePub := newKeyEntry(t, "by-name", ssh_agent_domain.KeyPolicy{Hosts: []string{"git.example.com"}})
+	byFP, byFPPub := newKeyEntry(t, "by-fingerprint", ssh_agent_domain.KeyPolicy{Hosts: []string{ssh.FingerprintSHA256(other.PublicKey())}})
+	f := newFixture(byName, byFP)
+	f.server.KnownHostsFiles = []string{knownHosts}
+
+	// Without a session bind restricted keys are neither listed nor usable.
+	client := f.client(t)
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	_, err = client.Sign(byNamePub, userAuthData([]byte("s1")))
+	require.Error(t, err)
+
+	// Bound to git.example.com (known_hosts) only by-name is offered.
+	require.NoError(t, bind(t, client, github, []byte("s1"), false))
+	keys, err = client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, "by-name", keys[0].Comment)
+	_, err = client.Sign(byNamePub, userAuthData([]byte("s1")))
+	require.NoError(t, err)
+	_, err = client.Sign(byNamePub, userAuthData([]byte("other-session")))
+	require.Error(t, err)
+	_, err = client.Sign(byFPPub, userAuthData([]byte("s1")))
+	require.Error(t, err)
+
+	// A host key fingerprint matches too.
+	client = f.client(t)
+	require.NoError(t, bind(t, client, other, []byte("s2"), false))
+	_, err = client.Sign(byFPPub, userAuthData([]byte("s2")))
+	require.NoError(t, err)
+
+	// Forwarded connections never get host-restricted keys.
+	client = f.client(t)
+	require.NoError(t, bind(t, client, github, []byte("s3"), true))
+	_, err = client.Sign(byNamePub, userAuthData([]byte("s3")))
+	require.Error(t, err)
+
+	// A bind whose signature does not verify is rejected.
+	client = f.client(t)
+	sig, err := other.Sign(rand.Reader, []byte("s4"))
+	require.NoError(t, err)
+	_, err = client.Extension("session-bind@openssh.com", ssh.Marshal(struct {
+		HostKey    []byte
+		SessionID  []byte
+		Signature  []byte
+		Forwarding bool
+	}{github.PublicKey().Marshal(), []byte("s4"), ssh.Marshal(sig), false}))
+	require.Error(t, err)
+
+	_, err = client.Extension("unknown@example.com", nil)
+	require.ErrorIs(t, err, agent.ErrExtensionUnsupported)
+}
+
+func TestServer_UnixSocket(t *testing.T) {
+	key, pub := newKeyEntry(t, "deploy", ssh_agent_domain.KeyPolicy{})
+	f := newFixture(key)
+
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "run", "agent.sock")
+
+	require.NoError(t, f.server.Listen(path))
+	require.ErrorIs(t, f.server.Listen(path), ssh_agent_domain.ErrAgentRunning)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	client := agent.NewClient(conn)
+	keys, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.True(t, bytes.Equal(pub.Marshal(), keys[0].Blob))
+
+	require.NoError(t, f.server.Close())
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	_, err = client.List()
+	require.Error(t, err)
+}
+
+func TestServer_ServeConnRefusesWhenNotListening(t *testing.T) {
+	f := newFixture()
+
+	c, s := net.Pipe()
+	defer c.Close()
+	require.ErrorIs(t, f.server.ServeConn(s), ssh_agent_domain.ErrAgentNotRunning)
+	_, err := agent.NewClient(c).List()
+	require.Error(t, err, "the connection is closed unserved")
+}
@@ -0,0 +1,103 @@
+package ssh_agent_ui
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+)
+
+const (
+	// ConfirmEvent is emitted to the UI with a ConfirmRequest payload.
+	ConfirmEvent = "ssh-agent:confirm"
+	// ConfirmResolvedEvent tells the UI a prompt was answered or timed out.
+	ConfirmResolvedEvent = "ssh-agent:confirm-resolved"
+
+	DefaultConfirmTimeout = 60 * time.Second
+)
+
+type pendingConfirmation struct {
+	userID string
+	answer chan bool
+}
+
+// UIConfirmer turns signature confirmations into UI prompts and blocks the
+// agent until the user answers through Respond or the prompt times out.
+type UIConfirmer struct {
+	Emit    func(event string, payload any)
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingConfirmation
+}
+
+func NewUIConfirmer(emit func(event string, payload any)) *UIConfirmer {
+	return &UIConfirmer{
+		Emit:    emit,
+		Timeout: DefaultConfirmTimeout,
+		pending: map[string]*pendingConfirmation{},
+	}
+}
+
+var _ ssh_agent_domain.Confirmer = (*UIConfirmer)(nil)
+
+func (c *UIConfirmer) Confirm(req ssh_agent_domain.ConfirmRequest) error {
+	if c.Emit == nil {
+		return errors.New("no UI to confirm with")
+	}
+	p := &pendingConfirmation{userID: req.UserID, answer: make(chan bool, 1)}
+	c.mu.Lock()
+	c.pending[req.ID] = p
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, req.ID)
+		c.mu.Unlock()
+		c.Emit(ConfirmResolvedEvent, map[string]any{"id": req.ID})
+	}()
+
+	c.Emit(ConfirmEvent, req)
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+	select {
+	case approved := <-p.answer:
+		if !approved {
+			return errors.New("denied by user")
+		}
+		return nil
+	case <-time.After(timeout):
+		return errors.New("confirmation timed out")
+	}
+}
+
+// Respond answers a pending prompt on behalf of userID. Only the user the
+// prompt was raised for can answer it; any other user gets
+// ErrConfirmationNotFound and the prompt stays open.
+func (c *UIConfirmer) Respond(userID string, id string, approved bool) error {
+	c.mu.Lock()
+	p, ok := c.pending[id]
+	if ok && p.userID == userID {
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+	if !ok || p.userID != userID {
+		return ssh_agent_domain.ErrConfirmationNotFound
+	}
+	p.answer <- approved
+	return nil
+}
+
+// DenyAll refuses every prompt still open for userID, e.g. when the vault locks.
+func (c *UIConfirmer) DenyAll(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, p := range c.pending {
+		if p.userID == userID {
+			p.answer <- false
+			delete(c.pending, id)
+		}
+	}
+}
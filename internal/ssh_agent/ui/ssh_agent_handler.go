@@ -0,0 +1,175 @@
+package ssh_agent_ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ssh_agent_usecases "vault-app/internal/ssh_agent/application/usecases"
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	ssh_agent_server "vault-app/internal/ssh_agent/infrastructure/server"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// AgentStatus is what the settings screen shows about the agent.
+type AgentStatus struct {
+	Running    bool   `json:"running"`
+	SocketPath string `json:"socket_path,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+}
+
+// SSHAgentHandler runs one agent at a time, serving the keys of the user
+// who started it.
+type SSHAgentHandler struct {
+	vault              ssh_agent_domain.VaultSession
+	generateKeyUseCase *ssh_agent_usecases.GenerateKeyUsecase
+	setPolicyUseCase   *ssh_agent_usecases.SetKeyPolicyUsecase
+	Confirmer          *UIConfirmer
+
+	mu      sync.Mutex
+	server  *ssh_agent_server.Server
+	keyring *ssh_agent_server.Keyring
+}
+
+func NewSSHAgentHandler(
+	vault ssh_agent_domain.VaultSession,
+	generateUC *ssh_agent_usecases.GenerateKeyUsecase,
+	setPolicyUC *ssh_agent_usecases.SetKeyPolicyUsecase,
+	confirmer *UIConfirmer,
+) *SSHAgentHandler {
+	return &SSHAgentHandler{
+		vault:              vault,
+		generateKeyUseCase: generateUC,
+		setPolicyUseCase:   setPolicyUC,
+		Confirmer:          confirmer,
+	}
+}
+
+// Start serves userID's keys on socketPath, or on the default path when
+// empty, and returns the path to export as SSH_AUTH_SOCK.
+func (h *SSHAgentHandler) Start(userID string, socketPath string) (string, error) {
+	if h.vault == nil {
+		return "", fmt.Errorf("ssh agent vault session is not initialized")
+	}
+	if userID == "" {
+		return "", ssh_agent_domain.ErrUserIDRequired
+	}
+	if socketPath == "" {
+		path, err := ssh_agent_server.DefaultSocketPath()
+		if err != nil {
+			return "", err
+		}
+		socketPath = path
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server != nil {
+		return "", ssh_agent_domain.ErrAgentRunning
+	}
+	keyring := ssh_agent_server.NewKeyring(h.vault, userID)
+	var confirmer ssh_agent_domain.Confirmer
+	if h.Confirmer != nil {
+		confirmer = h.Confirmer
+	}
+	server := ssh_agent_server.NewServer(keyring, confirmer)
+	if err := server.Listen(socketPath); err != nil {
+		return "", err
+	}
+	h.server, h.keyring = server, keyring
+	return socketPath, nil
+}
+
+// Stop shuts the agent down. Only the user who started it can stop it.
+func (h *SSHAgentHandler) Stop(userID string) error {
+	h.mu.Lock()
+	server, keyring := h.server, h.keyring
+	if server != nil && keyring.UserID != userID {
+		h.mu.Unlock()
+		return ssh_agent_domain.ErrAgentNotOwned
+	}
+	h.server, h.keyring = nil, nil
+	h.mu.Unlock()
+	return h.close(server, keyring)
+}
+
+// Shutdown stops the agent whoever started it, when the app exits.
+func (h *SSHAgentHandler) Shutdown() error {
+	h.mu.Lock()
+	server, keyring := h.server, h.keyring
+	h.server, h.keyring = nil, nil
+	h.mu.Unlock()
+	return h.close(server, keyring)
+}
+
+func (h *SSHAgentHandler) close(server *ssh_agent_server.Server, keyring *ssh_agent_server.Keyring) error {
+	if server == nil {
+		return ssh_agent_domain.ErrAgentNotRunning
+	}
+	keyring.Clear()
+	if h.Confirmer != nil {
+		h.Confirmer.DenyAll(keyring.UserID)
+	}
+	return server.Close()
+}
+
+func (h *SSHAgentHandler) Status() AgentStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.server == nil {
+		return AgentStatus{}
+	}
+	return AgentStatus{Running: true, SocketPath: h.server.SocketPath(), UserID: h.keyring.UserID}
+}
+
+// OnVaultLocked drops every key loaded for userID and refuses pending
+// prompts. The agent keeps listening and serves the keys again once the
+// vault is unlocked.
+func (h *SSHAgentHandler) OnVaultLocked(userID string) {
+	h.mu.Lock()
+	keyring := h.keyring
+	h.mu.Unlock()
+	if keyring != nil && keyring.UserID == userID {
+		keyring.Clear()
+	}
+	if h.Confirmer != nil {
+		h.Confirmer.DenyAll(userID)
+	}
+}
+
+// ListKeys returns the keys the running agent serves for userID.
+func (h *SSHAgentHandler) ListKeys(userID string) ([]ssh_agent_domain.KeyInfo, error) {
+	h.mu.Lock()
+	keyring := h.keyring
+	h.mu.Unlock()
+	if keyring == nil {
+		return nil, ssh_agent_domain.ErrAgentNotRunning
+	}
+	if keyring.UserID != userID {
+		return []ssh_agent_domain.KeyInfo{}, nil
+	}
+	return keyring.Infos()
+}
+
+func (h *SSHAgentHandler) GenerateKey(ctx context.Context, req ssh_agent_usecases.GenerateKeyRequest) (*vaults_domain.SSHKeyEntry, error) {
+	if h.generateKeyUseCase == nil {
+		return nil, fmt.Errorf("generate ssh key use case is not initialized")
+	}
+	return h.generateKeyUseCase.Execute(ctx, req)
+}
+
+func (h *SSHAgentHandler) SetKeyPolicy(ctx context.Context, userID string, entryID string, policy ssh_agent_domain.KeyPolicy) (*vaults_domain.SSHKeyEntry, error) {
+	if h.setPolicyUseCase == nil {
+		return nil, fmt.Errorf("set ssh key policy use case is not initialized")
+	}
+	return h.setPolicyUseCase.Execute(ctx, userID, entryID, policy)
+}
+
+// RespondConfirmation answers, on behalf of userID, a prompt emitted as
+// ConfirmEvent.
+func (h *SSHAgentHandler) RespondConfirmation(userID string, id string, approved bool) error {
+	if h.Confirmer == nil {
+		return fmt.Errorf("ssh agent confirmer is not initialized")
+	}
+	return h.Confirmer.Respond(userID, id, approved)
+}
@@ -0,0 +1,63 @@
+package ssh_agent_ui_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ssh_agent_domain "vault-app/internal/ssh_agent/domain"
+	ssh_agent_ui "vault-app/internal/ssh_agent/ui"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type vaultMock struct{}
+
+func (vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	return &vaults_domain.VaultPayload{}, nil
+}
+
+func TestUIConfirmer_OnlyThePromptedUserCanAnswer(t *testing.T) {
+	prompts := make(chan ssh_agent_domain.ConfirmRequest, 1)
+	confirmer := ssh_agent_ui.NewUIConfirmer(func(event string, payload any) {
+		if req, ok := payload.(ssh_agent_domain.ConfirmRequest); ok && event == ssh_agent_ui.ConfirmEvent {
+			prompts <- req
+		}
+	})
+
+	result := make(chan error, 1)
+	go func() {
+		result <- confirmer.Confirm(ssh_agent_domain.ConfirmRequest{ID: "req-1", UserID: "alice"})
+	}()
+	req := <-prompts
+
+	require.ErrorIs(t, confirmer.Respond("bob", req.ID, true), ssh_agent_domain.ErrConfirmationNotFound)
+	select {
+	case err := <-result:
+		t.Fatalf("prompt answered by another user: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, confirmer.Respond("alice", req.ID, true))
+	require.NoError(t, <-result)
+	require.ErrorIs(t, confirmer.Respond("alice", req.ID, true), ssh_agent_domain.ErrConfirmationNotFound)
+}
+
+func TestSSHAgentHandler_OnlyTheOwnerCanStop(t *testing.T) {
+	dir, err := os.MkdirTemp("", "agent")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	handler := ssh_agent_ui.NewSSHAgentHandler(vaultMock{}, nil, nil, ssh_agent_ui.NewUIConfirmer(func(string, any) {}))
+	_, err = handler.Start("alice", filepath.Join(dir, "agent.sock"))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, handler.Stop("bob"), ssh_agent_domain.ErrAgentNotOwned)
+	require.True(t, handler.Status().Running)
+
+	require.NoError(t, handler.Stop("alice"))
+	require.False(t, handler.Status().Running)
+	require.ErrorIs(t, handler.Stop("alice"), ssh_agent_domain.ErrAgentNotRunning)
+}
@@ -8,3 +8,26 @@ type CreateThreadRequest struct {
 	Title       string `json:"title"`
 	Subtitle    string `json:"subtitle"`
 }
+
+type CloseThreadRequest struct {
+	ThreadID string `json:"thread_id"`
+	ActorID  string `json:"actor_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ReopenThreadRequest struct {
+	ThreadID string `json:"thread_id"`
+	ActorID  string `json:"actor_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type InitiateThreadTransferRequest struct {
+	ThreadID  string `json:"thread_id"`
+	ActorID   string `json:"actor_id"`
+	ToVaultID string `json:"to_vault_id"`
+}
+
+type CompleteThreadTransferRequest struct {
+	ThreadID string `json:"thread_id"`
+	ActorID  string `json:"actor_id"`
+}
@@ -15,4 +15,16 @@ type ThreadEventBus interface {
 
 	PublishThreadUpdated(ctx context.Context, event thread_domain.ThreadUpdated) error
 	SubscribeToThreadUpdated(handler func(ctx context.Context, event thread_domain.ThreadUpdated)) error
+
+	PublishThreadClosed(ctx context.Context, event thread_domain.ThreadClosedEvent) error
+	SubscribeToThreadClosed(handler func(ctx context.Context, event thread_domain.ThreadClosedEvent)) error
+
+	PublishThreadReopened(ctx context.Context, event thread_domain.ThreadReopened) error
+	SubscribeToThreadReopened(handler func(ctx context.Context, event thread_domain.ThreadReopened)) error
+
+	PublishThreadTransferInitiated(ctx context.Context, event thread_domain.ThreadTransferInitiated) error
+	SubscribeToThreadTransferInitiated(handler func(ctx context.Context, event thread_domain.ThreadTransferInitiated)) error
+
+	PublishThreadTransferCompleted(ctx context.Context, event thread_domain.ThreadTransferCompleted) error
+	SubscribeToThreadTransferCompleted(handler func(ctx context.Context, event thread_domain.ThreadTransferCompleted)) error
 }
@@ -53,6 +53,7 @@ func (s *stubThreadRepo) AppendThreadEvent(_ context.Context, _ *thread_domain.A
 // ---------------------------------------------------------------------------
 type stubThreadEventBus struct {
 	lastCreatedEvent *thread_domain.ThreadCreated
+	lifecycleEvents  []any
 }
 
 func (s *stubThreadEventBus) PublishThreadCreated(_ context.Context, event thread_domain.ThreadCreated) error {
@@ -68,6 +69,34 @@ func (s *stubThreadEventBus) PublishThreadUpdated(_ context.Context, _ thread_do
 func (s *stubThreadEventBus) SubscribeToThreadUpdated(_ func(ctx context.Context, event thread_domain.ThreadUpdated)) error {
 	return nil
 }
+func (s *stubThreadEventBus) PublishThreadClosed(_ context.Context, event thread_domain.ThreadClosedEvent) error {
+	s.lifecycleEvents = append(s.lifecycleEvents, event)
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadClosed(_ func(ctx context.Context, event thread_domain.ThreadClosedEvent)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadReopened(_ context.Context, event thread_domain.ThreadReopened) error {
+	s.lifecycleEvents = append(s.lifecycleEvents, event)
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadReopened(_ func(ctx context.Context, event thread_domain.ThreadReopened)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadTransferInitiated(_ context.Context, event thread_domain.ThreadTransferInitiated) error {
+	s.lifecycleEvents = append(s.lifecycleEvents, event)
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadTransferInitiated(_ func(ctx context.Context, event thread_domain.ThreadTransferInitiated)) error {
+	return nil
+}
+func (s *stubThreadEventBus) PublishThreadTransferCompleted(_ context.Context, event thread_domain.ThreadTransferCompleted) error {
+	s.lifecycleEvents = append(s.lifecycleEvents, event)
+	return nil
+}
+func (s *stubThreadEventBus) SubscribeToThreadTransferCompleted(_ func(ctx context.Context, event thread_domain.ThreadTransferCompleted)) error {
+	return nil
+}
 
 var _ thread_events.ThreadEventBus = (*stubThreadEventBus)(nil)
 
@@ -0,0 +1,253 @@
+package thread_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_dtos "vault-app/internal/thread/application/dtos"
+	thread_usecase "vault-app/internal/thread/application/usecases"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// ---------------------------------------------------------------------------
+// Stateful repository: one thread and its appended events
+// ---------------------------------------------------------------------------
+type lifecycleThreadRepo struct {
+	stubThreadRepo
+	thread   thread_domain.Thread
+	appended []thread_domain.AppendThreadEventRequest
+}
+
+func (s *lifecycleThreadRepo) GetThread(_ context.Context, req *thread_domain.GetThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	if req.ThreadID != s.thread.ID {
+		return nil, thread_domain.ErrThreadNotFound
+	}
+	return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: 200, Data: s.thread}, nil
+}
+func (s *lifecycleThreadRepo) UpdateThread(_ context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	s.thread = req.Thread
+	return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: 200, Data: req.Thread}, nil
+}
+func (s *lifecycleThreadRepo) AppendThreadEvent(_ context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	s.appended = append(s.appended, *req)
+	return &tracecore_types.CloudResponse[thread_domain.ThreadEvent]{
+		Status: 200,
+		Data: thread_domain.ThreadEvent{
+			ID:       req.IdempotencyKey,
+			ThreadID: req.ThreadID,
+			Type:     thread_domain.ThreadEventType(req.EventType),
+			Headers:  req.Headers,
+		},
+	}, nil
+}
+
+func newLifecycleRepo() *lifecycleThreadRepo {
+	th := thread_domain.NewThread("ch-1", "invoice", "Invoice #42", "")
+	th.WorkspaceID = "ws-1"
+	return &lifecycleThreadRepo{thread: th}
+}
+
+func reopenableChannel(allowed bool) *stubChannelReader {
+	ch := channel_domain.Channel{ID: "ch-1", Status: channel_domain.StatusActive, WorkspaceID: "ws-1"}
+	ch.SetPolicy(channel_domain.Policy{channel_domain.LegacyPolicyAllowThreadReopen: allowed})
+	return &stubChannelReader{channels: map[string]*channel_domain.Channel{"ch-1": &ch}}
+}
+
+// ---------------------------------------------------------------------------
+// Aggregate transitions
+// ---------------------------------------------------------------------------
+
+func TestThreadLifecycle_Transitions(t *testing.T) {
+	th := thread_domain.NewThread("ch-1", "invoice", "Invoice", "")
+	require.NoError(t, th.CanAppend())
+
+	// open -> closed
+	require.NoError(t, th.Close())
+	assert.Equal(t, thread_domain.ThreadClosed, th.Status)
+	require.NotNil(t, th.ClosedAt)
+	assert.ErrorIs(t, th.CanAppend(), thread_domain.ErrThreadClosed)
+	assert.ErrorIs(t, th.Close(), thread_domain.ErrThreadClosed)
+	assert.ErrorIs(t, th.InitiateTransfer("vault-b"), thread_domain.ErrThreadClosed)
+
+	// closed -> open
+	require.NoError(t, th.Reopen())
+	assert.Equal(t, thread_domain.ThreadOpen, th.Status)
+	assert.Nil(t, th.ClosedAt)
+	assert.ErrorIs(t, th.Reopen(), thread_domain.ErrThreadNotClosed)
+
+	// open -> transferring -> open
+	assert.ErrorIs(t, th.InitiateTransfer(""), thread_domain.ErrThreadTransferTargetRequired)
+	require.NoError(t, th.InitiateTransfer("vault-b"))
+	assert.Equal(t, thread_domain.ThreadTransferring, th.Status)
+	assert.ErrorIs(t, th.CanAppend(), thread_domain.ErrThreadTransferInProgress)
+	assert.ErrorIs(t, th.Close(), thread_domain.ErrThreadTransferInProgress)
+
+	to, err := th.CompleteTransfer()
+	require.NoError(t, err)
+	assert.Equal(t, "vault-b", to)
+	assert.Equal(t, thread_domain.ThreadOpen, th.Status)
+	assert.Empty(t, th.TransferTo)
+
+	_, err = th.CompleteTransfer()
+	assert.ErrorIs(t, err, thread_domain.ErrThreadNotTransferring)
+}
+
+// ---------------------------------------------------------------------------
+// Use cases
+// ---------------------------------------------------------------------------
+
+func TestCloseThread_RecordsEventAndPublishes(t *testing.T) {
+	ctx := context.Background()
+	repo := newLifecycleRepo()
+	bus := &stubThreadEventBus{}
+
+	th, err := thread_usecase.NewCloseThreadUsecase(repo, bus).Execute(ctx, thread_dtos.CloseThreadRequest{
+		ThreadID: repo.thread.ID,
+		ActorID:  "vault-a",
+		Reason:   "paid",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, thread_domain.ThreadClosed, th.Status)
+	assert.Equal(t, thread_domain.ThreadClosed, repo.thread.Status)
+
+	require.Len(t, repo.appended, 1)
+	assert.Equal(t, string(thread_domain.EventThreadClosed), repo.appended[0].EventType)
+	assert.Equal(t, "vault-a", repo.appended[0].Headers[thread_domain.HeaderActorID])
+	assert.Equal(t, "paid", repo.appended[0].Headers[thread_domain.HeaderReason])
+
+	require.Len(t, bus.lifecycleEvents, 1)
+	closed, ok := bus.lifecycleEvents[0].(thread_domain.ThreadClosedEvent)
+	require.True(t, ok)
+	assert.Equal(t, repo.thread.ID, closed.ThreadID)
+	assert.Equal(t, "ws-1", closed.WorkspaceID)
+}
+
+func TestAppendThreadEvent_RejectedInInvalidStates(t *testing.T) {
+	ctx := context.Background()
+	repo := newLifecycleRepo()
+	appendUC := thread_usecase.NewAppendThreadEventUsecase(repo)
+	ref := thread_domain.EventResourceRef{RefType: thread_domain.ResourceStorageAsset, CID: "cid-1"}
+
+	_, err := appendUC.Execute(ctx, repo.thread.ID, string(thread_domain.EventInvoiceCreated), ref)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.thread.InitiateTransfer("vault-b"))
+	_, err = appendUC.Execute(ctx, repo.thread.ID, string(thread_domain.EventFinanceApproved), ref)
+	assert.ErrorIs(t, err, thread_domain.ErrThreadTransferInProgress)
+
+	repo.thread.Status = thread_domain.ThreadClosed
+	_, err = appendUC.Execute(ctx, repo.thread.ID, string(thread_domain.EventFinanceApproved), ref)
+	assert.ErrorIs(t, err, thread_domain.ErrThreadClosed)
+
+	assert.Len(t, repo.appended, 1)
+}
+
+func TestReopenThread_PolicyGated(t *testing.T) {
+	ctx := context.Background()
+	req := func(repo *lifecycleThreadRepo) thread_dtos.ReopenThreadRequest {
+		return thread_dtos.ReopenThreadRequest{ThreadID: repo.thread.ID, ActorID: "vault-a"}
+	}
+
+	t.Run("denied without policy", func(t *testing.T) {
+		repo := newLifecycleRepo()
+		require.NoError(t, repo.thread.Close())
+
+		_, err := thread_usecase.NewReopenThreadUsecase(repo, &stubThreadEventBus{}, reopenableChannel(false)).Execute(ctx, req(repo))
+		assert.ErrorIs(t, err, thread_domain.ErrThreadReopenNotAllowed)
+
+		_, err = thread_usecase.NewReopenThreadUsecase(repo, &stubThreadEventBus{}, nil).Execute(ctx, req(repo))
+		assert.ErrorIs(t, err, thread_domain.ErrThreadReopenNotAllowed)
+
+		assert.Equal(t, thread_domain.ThreadClosed, repo.thread.Status)
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("allowed by policy", func(t *testing.T) {
+		repo := newLifecycleRepo()
+		require.NoError(t, repo.thread.Close())
+		bus := &stubThreadEventBus{}
+
+		th, err := thread_usecase.NewReopenThreadUsecase(repo, bus, reopenableChannel(true)).Execute(ctx, req(repo))
+		require.NoError(t, err)
+		assert.Equal(t, thread_domain.ThreadOpen, th.Status)
+		require.Len(t, repo.appended, 1)
+		assert.Equal(t, string(thread_domain.EventThreadReopened), repo.appended[0].EventType)
+		require.Len(t, bus.lifecycleEvents, 1)
+		assert.IsType(t, thread_domain.ThreadReopened{}, bus.lifecycleEvents[0])
+	})
+
+	t.Run("open thread cannot be reopened", func(t *testing.T) {
+		repo := newLifecycleRepo()
+		_, err := thread_usecase.NewReopenThreadUsecase(repo, &stubThreadEventBus{}, reopenableChannel(true)).Execute(ctx, req(repo))
+		assert.ErrorIs(t, err, thread_domain.ErrThreadNotClosed)
+	})
+}
+
+func TestThreadTransfer_InitiateAndComplete(t *testing.T) {
+	ctx := context.Background()
+	repo := newLifecycleRepo()
+	bus := &stubThreadEventBus{}
+
+	th, err := thread_usecase.NewInitiateThreadTransferUsecase(repo, bus).Execute(ctx, thread_dtos.InitiateThreadTransferRequest{
+		ThreadID:  repo.thread.ID,
+		ActorID:   "vault-a",
+		ToVaultID: "vault-b",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, thread_domain.ThreadTransferring, th.Status)
+	assert.Equal(t, "vault-b", th.TransferTo)
+
+	_, err = thread_usecase.NewCloseThreadUsecase(repo, bus).Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: repo.thread.ID})
+	assert.ErrorIs(t, err, thread_domain.ErrThreadTransferInProgress)
+
+	th, err = thread_usecase.NewCompleteThreadTransferUsecase(repo, bus).Execute(ctx, thread_dtos.CompleteThreadTransferRequest{
+		ThreadID: repo.thread.ID,
+		ActorID:  "vault-b",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, thread_domain.ThreadOpen, th.Status)
+
+	require.Len(t, repo.appended, 2)
+	assert.Equal(t, string(thread_domain.EventThreadTransferInitiated), repo.appended[0].EventType)
+	assert.Equal(t, string(thread_domain.EventThreadTransferCompleted), repo.appended[1].EventType)
+	assert.Equal(t, "vault-b", repo.appended[1].Headers[thread_domain.HeaderTransferTo])
+
+	require.Len(t, bus.lifecycleEvents, 2)
+	completed, ok := bus.lifecycleEvents[1].(thread_domain.ThreadTransferCompleted)
+	require.True(t, ok)
+	assert.Equal(t, "vault-b", completed.ToVaultID)
+}
+
+// failingAppendRepo stores thread updates but refuses every event append.
+type failingAppendRepo struct {
+	lifecycleThreadRepo
+	updates int
+}
+
+func (s *failingAppendRepo) UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	s.updates++
+	return s.lifecycleThreadRepo.UpdateThread(ctx, req)
+}
+
+func (s *failingAppendRepo) AppendThreadEvent(_ context.Context, _ *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	return nil, errors.New("append refused")
+}
+
+func TestCloseThread_RestoresStatusWhenAppendFails(t *testing.T) {
+	repo := &failingAppendRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	bus := &stubThreadEventBus{}
+
+	_, err := thread_usecase.NewCloseThreadUsecase(repo, bus).Execute(context.Background(), thread_dtos.CloseThreadRequest{ThreadID: repo.thread.ID})
+
+	require.EqualError(t, err, "append refused")
+	assert.Equal(t, 2, repo.updates)
+	assert.Equal(t, thread_domain.ThreadOpen, repo.thread.Status)
+	assert.Nil(t, repo.thread.ClosedAt)
+	assert.Empty(t, bus.lifecycleEvents)
+}
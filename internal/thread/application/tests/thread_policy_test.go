@@ -0,0 +1,269 @@
+package thread_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_dtos "vault-app/internal/thread/application/dtos"
+	thread_usecase "vault-app/internal/thread/application/usecases"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// governedThreadRepo replays appended events as the thread history so
+// approval requirements can be satisfied.
+type governedThreadRepo struct {
+	lifecycleThreadRepo
+}
+
+func (s *governedThreadRepo) ListThreadEvents(_ context.Context, _ *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
+	events := make([]thread_domain.ThreadEvent, 0, len(s.appended))
+	for i, req := range s.appended {
+		events = append(events, thread_domain.ThreadEvent{
+			ThreadID: req.ThreadID,
+			Type:     thread_domain.ThreadEventType(req.EventType),
+			Cursor:   uint64(i + 1),
+			Headers:  req.Headers,
+		})
+	}
+	return &tracecore_types.CloudResponse[[]thread_domain.ThreadEvent]{Status: 200, Data: events}, nil
+}
+
+func governedChannel(t *testing.T) *stubChannelReader {
+	doc := channel_domain.DefaultPolicy()
+	doc.ThreadEvents = map[string][]string{
+		"buyer":   {"payment.released"},
+		"finance": {"finance.approved"},
+	}
+	doc.Approvals = []channel_domain.ApprovalRequirement{{EventType: "payment.released", ApprovalEventType: "finance.approved", Count: 1}}
+	doc.AllowedAssetTypes = []string{"invoice"}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+
+	ch := channel_domain.Channel{
+		ID:          "ch-1",
+		Status:      channel_domain.StatusActive,
+		WorkspaceID: "ws-1",
+		Slots: []channel_domain.Slot{
+			{ID: "slot-1", Role: "buyer", VaultID: "vault-buyer"},
+			{ID: "slot-2", Role: "finance", VaultID: "vault-finance"},
+		},
+	}
+	ch.SetPolicy(policy)
+	return &stubChannelReader{channels: map[string]*channel_domain.Channel{"ch-1": &ch}}
+}
+
+func TestAppendThreadEvent_EnforcesChannelPolicy(t *testing.T) {
+	ctx := context.Background()
+	repo := &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	uc := thread_usecase.NewAppendThreadEventUsecase(repo).WithPolicy(governedChannel(t), channel_domain.NewPolicyEvaluator())
+	threadID := repo.thread.ID
+
+	// The buyer cannot approve, and the finance vault cannot release.
+	_, err := uc.ExecuteAs(ctx, "vault-buyer", threadID, "finance.approved", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyDenied)
+	_, err = uc.ExecuteAs(ctx, "vault-finance", threadID, "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyDenied)
+
+	// Asset types outside the allow-list are refused.
+	_, err = uc.ExecuteAs(ctx, "vault-finance", threadID, "finance.approved", thread_domain.EventResourceRef{AssetType: "cad-drawing"})
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyAssetTypeNotAllowed)
+
+	// Release is blocked until finance has approved.
+	_, err = uc.ExecuteAs(ctx, "vault-buyer", threadID, "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyApprovalsMissing)
+
+	_, err = uc.ExecuteAs(ctx, "vault-finance", threadID, "finance.approved", thread_domain.EventResourceRef{AssetType: "invoice"})
+	require.NoError(t, err)
+	_, err = uc.ExecuteAs(ctx, "vault-buyer", threadID, "payment.released", thread_domain.EventResourceRef{})
+	require.NoError(t, err)
+	assert.Equal(t, "vault-finance", repo.appended[0].Headers[thread_domain.HeaderActorID])
+
+	// The approval was spent on that release.
+	_, err = uc.ExecuteAs(ctx, "vault-buyer", threadID, "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyApprovalsMissing)
+
+	require.Len(t, repo.appended, 2)
+}
+
+func TestThreadUsecases_EnforceChannelPermissions(t *testing.T) {
+	ctx := context.Background()
+	channels := governedChannel(t)
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions.Roles = map[string][]string{
+		"buyer":   {channel_domain.PermChannelAdmin},
+		"finance": {channel_domain.ThreadAppendPermission("finance.approved")},
+	}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channels.channels["ch-1"].SetPolicy(policy)
+
+	repo := &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	threadID := repo.thread.ID
+	evaluator := channel_domain.NewPolicyEvaluator()
+	appendUC := thread_usecase.NewAppendThreadEventUsecase(repo).WithPolicy(channels, evaluator)
+
+	_, err = appendUC.ExecuteAs(ctx, "vault-finance", threadID, "finance.approved", thread_domain.EventResourceRef{})
+	require.NoError(t, err)
+	_, err = appendUC.ExecuteAs(ctx, "vault-finance", threadID, "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+
+	// Referencing a share needs asset.share on top of the append permission.
+	_, err = appendUC.ExecuteAs(ctx, "vault-finance", threadID, "finance.approved", thread_domain.EventResourceRef{RefType: thread_domain.ResourceShareEntry})
+	assert.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+
+	closeUC := thread_usecase.NewCloseThreadUsecase(repo, &stubThreadEventBus{}).WithPolicy(channels, evaluator)
+	_, err = closeUC.Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: threadID, ActorID: "vault-finance"})
+	assert.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+	_, err = closeUC.Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: threadID, ActorID: "vault-buyer"})
+	require.NoError(t, err)
+}
+
+func TestCompleteThreadTransfer_RequiresRecipientOrManager(t *testing.T) {
+	ctx := context.Background()
+	channels := governedChannel(t)
+	doc := channel_domain.DefaultPolicy()
+	doc.Permissions.Roles = map[string][]string{
+		"buyer":   {channel_domain.PermChannelAdmin},
+		"finance": {channel_domain.ThreadAppendPermission("finance.approved")},
+	}
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channels.channels["ch-1"].SetPolicy(policy)
+
+	evaluator := channel_domain.NewPolicyEvaluator()
+	bus := &stubThreadEventBus{}
+	completeUC := thread_usecase.NewCompleteThreadTransferUsecase
+	initiate := func(repo *governedThreadRepo) {
+		_, err := thread_usecase.NewInitiateThreadTransferUsecase(repo, bus).WithPolicy(channels, evaluator).
+			Execute(ctx, thread_dtos.InitiateThreadTransferRequest{ThreadID: repo.thread.ID, ActorID: "vault-buyer", ToVaultID: "vault-recipient"})
+		require.NoError(t, err)
+	}
+
+	repo := &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	initiate(repo)
+	_, err = completeUC(repo, bus).WithPolicy(channels, evaluator).
+		Execute(ctx, thread_dtos.CompleteThreadTransferRequest{ThreadID: repo.thread.ID, ActorID: "vault-finance"})
+	assert.ErrorIs(t, err, channel_domain.ErrPermissionDenied)
+	_, err = completeUC(repo, bus).WithPolicy(channels, evaluator).
+		Execute(ctx, thread_dtos.CompleteThreadTransferRequest{ThreadID: repo.thread.ID, ActorID: "vault-recipient"})
+	require.NoError(t, err)
+
+	// A thread.manage holder may complete on the recipient's behalf.
+	repo = &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	initiate(repo)
+	_, err = completeUC(repo, bus).WithPolicy(channels, evaluator).
+		Execute(ctx, thread_dtos.CompleteThreadTransferRequest{ThreadID: repo.thread.ID, ActorID: "vault-buyer"})
+	require.NoError(t, err)
+}
+
+func TestThreadUsecases_EnforceRetention(t *testing.T) {
+	ctx := context.Background()
+	doc := channel_domain.DefaultPolicy()
+	doc.AllowThreadReopen = true
+	doc.Retention.RetainDays = 30
+	policy, err := doc.ToPolicy()
+	require.NoError(t, err)
+	channels := governedChannel(t)
+	channels.channels["ch-1"].SetPolicy(policy)
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	closedFor := func(days int) *governedThreadRepo {
+		repo := &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+		require.NoError(t, repo.thread.Close())
+		closedAt := time.Now().AddDate(0, 0, -days)
+		repo.thread.ClosedAt = &closedAt
+		return repo
+	}
+
+	recent := closedFor(1)
+	_, err = thread_usecase.NewListThreadEventsUsecase(recent).WithPolicy(channels, evaluator).Execute(ctx, recent.thread.ID)
+	require.NoError(t, err)
+
+	expired := closedFor(60)
+	_, err = thread_usecase.NewListThreadEventsUsecase(expired).WithPolicy(channels, evaluator).Execute(ctx, expired.thread.ID)
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyRetentionElapsed)
+
+	_, err = thread_usecase.NewReopenThreadUsecase(expired, &stubThreadEventBus{}, channels).
+		Execute(ctx, thread_dtos.ReopenThreadRequest{ThreadID: expired.thread.ID, ActorID: "vault-buyer"})
+	assert.ErrorIs(t, err, thread_domain.ErrThreadReopenNotAllowed)
+	assert.ErrorIs(t, err, channel_domain.ErrPolicyRetentionElapsed)
+	assert.Empty(t, expired.appended)
+}
+
+func TestAppendThreadEvent_UnresolvedThreadFailsClosed(t *testing.T) {
+	ctx := context.Background()
+	uc := thread_usecase.NewAppendThreadEventUsecase(newLifecycleRepo()).WithPolicy(governedChannel(t), channel_domain.NewPolicyEvaluator())
+
+	err := uc.Authorize(ctx, "vault-buyer", "missing-thread", "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, thread_domain.ErrThreadNotFound)
+
+	// A repository that answers without data is not treated as a pass either.
+	err = thread_usecase.NewAppendThreadEventUsecase(&stubThreadRepo{}).Authorize(ctx, "vault-buyer", "thread-1", "payment.released", thread_domain.EventResourceRef{})
+	assert.ErrorIs(t, err, thread_domain.ErrRepositoryResponse)
+}
+
+func TestThreadUsecases_ArchivedChannelIsReadOnly(t *testing.T) {
+	ctx := context.Background()
+	channels := governedChannel(t)
+	require.NoError(t, channels.channels["ch-1"].Archive())
+
+	repo := &governedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	threadID := repo.thread.ID
+	evaluator := channel_domain.NewPolicyEvaluator()
+
+	_, err := thread_usecase.NewAppendThreadEventUsecase(repo).WithPolicy(channels, evaluator).
+		ExecuteAs(ctx, "vault-finance", threadID, "finance.approved", thread_domain.EventResourceRef{AssetType: "invoice"})
+	assert.ErrorIs(t, err, thread_domain.ErrChannelArchived)
+
+	_, err = thread_usecase.NewCloseThreadUsecase(repo, &stubThreadEventBus{}).WithPolicy(channels, evaluator).
+		Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: threadID, ActorID: "vault-buyer"})
+	assert.ErrorIs(t, err, thread_domain.ErrChannelArchived)
+	assert.Empty(t, repo.appended)
+}
+
+// federatedThreadRepo numbers appended events the way the Cloud does.
+type federatedThreadRepo struct {
+	lifecycleThreadRepo
+}
+
+func (s *federatedThreadRepo) AppendThreadEvent(ctx context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	resp, err := s.lifecycleThreadRepo.AppendThreadEvent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Data.Cursor = uint64(len(s.appended))
+	return resp, nil
+}
+
+type recordingExchangeFeed struct {
+	fed []string
+}
+
+func (f *recordingExchangeFeed) FeedExchange(_ context.Context, channelID string, exchangeID string, cursor uint64) error {
+	f.fed = append(f.fed, fmt.Sprintf("%s/%s@%d", channelID, exchangeID, cursor))
+	return nil
+}
+
+func TestAppendThreadEvent_FeedsFederationExchanges(t *testing.T) {
+	ctx := context.Background()
+	repo := &federatedThreadRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	feed := &recordingExchangeFeed{}
+	uc := thread_usecase.NewAppendThreadEventUsecase(repo).WithExchanges(feed)
+
+	_, err := uc.Execute(ctx, repo.thread.ID, "invoice.sent", thread_domain.EventResourceRef{}, "evt-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ch-1/evt-1@1"}, feed.fed)
+
+	// Without a Cloud cursor (queued offline) nothing is relayed yet.
+	offline := newLifecycleRepo()
+	_, err = thread_usecase.NewAppendThreadEventUsecase(offline).WithExchanges(feed).Execute(ctx, offline.thread.ID, "invoice.sent", thread_domain.EventResourceRef{}, "evt-2")
+	require.NoError(t, err)
+	assert.Len(t, feed.fed, 1)
+}
@@ -0,0 +1,322 @@
+package thread_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	thread_dtos "vault-app/internal/thread/application/dtos"
+	thread_usecase "vault-app/internal/thread/application/usecases"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// stubEventLogRepo serves a fixed thread log.
+type stubEventLogRepo struct {
+	stubThreadRepo
+	events []thread_domain.ThreadEvent
+}
+
+func (s *stubEventLogRepo) ListThreadEvents(_ context.Context, _ *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
+	return &tracecore_types.CloudResponse[[]thread_domain.ThreadEvent]{Status: 200, Data: s.events}, nil
+}
+
+type stubDeviceKeys map[string]*thread_domain.DeviceKey
+
+func (s stubDeviceKeys) DeviceKey(_ context.Context, deviceID string) (*thread_domain.DeviceKey, error) {
+	k, ok := s[deviceID]
+	if !ok {
+		return nil, errors.New("device not found")
+	}
+	return k, nil
+}
+
+type author struct {
+	deviceID string
+	priv     ed25519.PrivateKey
+}
+
+func newAuthor(t *testing.T, deviceID string, keys stubDeviceKeys) author {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys[deviceID] = &thread_domain.DeviceKey{
+		DeviceID:  deviceID,
+		PublicKey: base64.StdEncoding.EncodeToString(pub),
+		KeyType:   thread_domain.DeviceKeyTypeEd25519,
+	}
+	return author{deviceID: deviceID, priv: priv}
+}
+
+func (a author) DeviceID() string { return a.deviceID }
+
+func (a author) Sign(digest []byte) ([]byte, error) { return ed25519.Sign(a.priv, digest), nil }
+
+func (a author) sign(t *testing.T, e thread_domain.ThreadEvent) thread_domain.ThreadEvent {
+	return a.signAt(t, e, time.Now())
+}
+
+func (a author) signAt(t *testing.T, e thread_domain.ThreadEvent, signedAt time.Time) thread_domain.ThreadEvent {
+	require.NoError(t, thread_domain.SignEvent(&e, a, signedAt))
+	return e
+}
+
+// regulatedLog builds invoice -> approval -> payment, each linked to its
+// predecessor and signed by its author.
+func regulatedLog(t *testing.T, a author) []thread_domain.ThreadEvent {
+	types := []thread_domain.ThreadEventType{
+		thread_domain.EventInvoiceCreated,
+		thread_domain.EventFinanceApproved,
+		thread_domain.EventPaymentReleased,
+	}
+
+	events := make([]thread_domain.ThreadEvent, 0, len(types))
+	var prev *string
+	for i, typ := range types {
+		id := []string{"evt-invoice", "evt-approval", "evt-payment"}[i]
+		e := a.sign(t, thread_domain.ThreadEvent{
+			ID:              id,
+			ThreadID:        "th-1",
+			PreviousEventID: prev,
+			Type:            typ,
+			Payload:         thread_domain.EventResourceRef{RefType: thread_domain.ResourceStorageAsset, CID: "cid-" + id},
+			Cursor:          uint64(i + 1),
+			CreatedAt:       time.Now(),
+		})
+		events = append(events, e)
+		prev = &id
+	}
+	return events
+}
+
+func issueKinds(r thread_domain.ChainReport) []thread_domain.ChainIssueKind {
+	kinds := make([]thread_domain.ChainIssueKind, 0, len(r.Issues))
+	for _, i := range r.Issues {
+		kinds = append(kinds, i.Kind)
+	}
+	return kinds
+}
+
+func TestVerifyEventChain_ValidLog(t *testing.T) {
+	keys := stubDeviceKeys{}
+	events := regulatedLog(t, newAuthor(t, "dev-1", keys))
+
+	report := thread_domain.VerifyEventChain(context.Background(), "th-1", events, keys)
+
+	assert.True(t, report.Valid, report.Issues)
+	assert.Equal(t, "evt-payment", report.HeadEventID)
+	assert.Equal(t, uint64(3), report.HeadCursor)
+}
+
+func TestVerifyEventChain_DetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	keys := stubDeviceKeys{}
+	a := newAuthor(t, "dev-1", keys)
+
+	t.Run("payload changed by server", func(t *testing.T) {
+		events := regulatedLog(t, a)
+		events[1].Payload.CID = "cid-forged"
+		report := thread_domain.VerifyEventChain(ctx, "th-1", events, keys)
+		assert.Contains(t, issueKinds(report), thread_domain.ChainIssueHashMismatch)
+	})
+
+	t.Run("signature replaced", func(t *testing.T) {
+		events := regulatedLog(t, a)
+		other := newAuthor(t, "dev-2", stubDeviceKeys{})
+		events[2].Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(other.priv, []byte("x")))
+		report := thread_domain.VerifyEventChain(ctx, "th-1", events, keys)
+		assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueBadSignature}, issueKinds(report))
+	})
+
+	t.Run("event dropped", func(t *testing.T) {
+		events := regulatedLog(t, a)
+		events = []thread_domain.ThreadEvent{events[0], events[2]}
+		report := thread_domain.VerifyEventChain(ctx, "th-1", events, keys)
+		assert.Contains(t, issueKinds(report), thread_domain.ChainIssueGap)
+		assert.Contains(t, issueKinds(report), thread_domain.ChainIssueBrokenLink)
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		events := regulatedLog(t, a)
+		events[0], events[1] = events[1], events[0]
+		report := thread_domain.VerifyEventChain(ctx, "th-1", events, keys)
+		assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueReordered}, issueKinds(report))
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		events := regulatedLog(t, a)
+		fork := a.sign(t, thread_domain.ThreadEvent{
+			ID:              "evt-payment-2",
+			ThreadID:        "th-1",
+			PreviousEventID: events[1].PreviousEventID,
+			Type:            thread_domain.EventPaymentReleased,
+			Cursor:          4,
+		})
+		report := thread_domain.VerifyEventChain(ctx, "th-1", append(events, fork), keys)
+		assert.Contains(t, issueKinds(report), thread_domain.ChainIssueFork)
+	})
+
+	t.Run("unknown and revoked devices", func(t *testing.T) {
+		events := regulatedLog(t, newAuthor(t, "dev-unregistered", stubDeviceKeys{}))
+		report := thread_domain.VerifyEventChain(ctx, "th-1", events[:1], keys)
+		assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueUnknownDevice}, issueKinds(report))
+
+		revokedKeys := stubDeviceKeys{}
+		r := newAuthor(t, "dev-r", revokedKeys)
+		revokedAt := time.Now().Add(-time.Hour)
+		revokedKeys["dev-r"].RevokedAt = &revokedAt
+		report = thread_domain.VerifyEventChain(ctx, "th-1", regulatedLog(t, r)[:1], revokedKeys)
+		assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueRevokedDevice}, issueKinds(report))
+	})
+}
+
+func TestVerifyEventChain_RevocationUsesSignedTime(t *testing.T) {
+	ctx := context.Background()
+	keys := stubDeviceKeys{}
+	a := newAuthor(t, "dev-1", keys)
+	revokedAt := time.Now().Add(-time.Hour)
+	keys["dev-1"].RevokedAt = &revokedAt
+
+	event := func(signedAt, createdAt time.Time) thread_domain.ThreadEvent {
+		return a.signAt(t, thread_domain.ThreadEvent{
+			ID:        "evt-1",
+			ThreadID:  "th-1",
+			Type:      thread_domain.EventInvoiceCreated,
+			Cursor:    1,
+			CreatedAt: createdAt,
+		}, signedAt)
+	}
+	before := revokedAt.Add(-time.Hour)
+
+	report := thread_domain.VerifyEventChain(ctx, "th-1", []thread_domain.ThreadEvent{event(before, before)}, keys)
+	assert.True(t, report.Valid, report.Issues)
+
+	// A revoked key backdating its signature is caught by the server time.
+	report = thread_domain.VerifyEventChain(ctx, "th-1", []thread_domain.ThreadEvent{event(before, time.Now())}, keys)
+	assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueRevokedDevice}, issueKinds(report))
+
+	// A server backdating CreatedAt cannot hide a signature made after.
+	report = thread_domain.VerifyEventChain(ctx, "th-1", []thread_domain.ThreadEvent{event(time.Now(), before)}, keys)
+	assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueRevokedDevice}, issueKinds(report))
+
+	// Tampering with the signed time breaks the content hash.
+	forged := event(time.Now(), before)
+	forged.Headers[thread_domain.HeaderSignedAt] = before.UTC().Format(time.RFC3339Nano)
+	report = thread_domain.VerifyEventChain(ctx, "th-1", []thread_domain.ThreadEvent{forged}, keys)
+	assert.Equal(t, []thread_domain.ChainIssueKind{thread_domain.ChainIssueHashMismatch}, issueKinds(report))
+}
+
+// signedLogRepo records appends as the server would: with a cursor and the
+// link the author signed.
+type signedLogRepo struct {
+	lifecycleThreadRepo
+	log []thread_domain.ThreadEvent
+}
+
+func (s *signedLogRepo) ListThreadEvents(_ context.Context, _ *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
+	return &tracecore_types.CloudResponse[[]thread_domain.ThreadEvent]{Status: 200, Data: s.log}, nil
+}
+
+func (s *signedLogRepo) AppendThreadEvent(_ context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	e := thread_domain.ThreadEvent{
+		ID:              fmt.Sprintf("evt-%d", len(s.log)+1),
+		ThreadID:        req.ThreadID,
+		PreviousEventID: req.PreviousEventID,
+		Type:            thread_domain.ThreadEventType(req.EventType),
+		Payload:         req.Payload,
+		IdempotencyKey:  req.IdempotencyKey,
+		Cursor:          uint64(len(s.log) + 1),
+		Headers:         req.Headers,
+		Signature:       req.Signature,
+		CreatedAt:       time.Now(),
+	}
+	s.log = append(s.log, e)
+	return &tracecore_types.CloudResponse[thread_domain.ThreadEvent]{Status: 201, Data: e}, nil
+}
+
+func TestAppendThreadEvent_SignsWithSessionDevice(t *testing.T) {
+	ctx := context.Background()
+	keys := stubDeviceKeys{}
+	repo := &signedLogRepo{lifecycleThreadRepo: *newLifecycleRepo()}
+	uc := thread_usecase.NewAppendThreadEventUsecase(repo)
+
+	uc.SetSigner(newAuthor(t, "dev-1", keys))
+	_, err := uc.Execute(ctx, repo.thread.ID, "invoice.created", thread_domain.EventResourceRef{CID: "cid-1"})
+	require.NoError(t, err)
+	_, err = uc.Execute(ctx, repo.thread.ID, "finance.approved", thread_domain.EventResourceRef{}, "key-2")
+	require.NoError(t, err)
+
+	require.Len(t, repo.log, 2)
+	require.NotNil(t, repo.log[1].PreviousEventID)
+	assert.Equal(t, "evt-1", *repo.log[1].PreviousEventID)
+	report := thread_domain.VerifyEventChain(ctx, repo.thread.ID, repo.log, keys)
+	assert.True(t, report.Valid, report.Issues)
+
+	uc.SetSigner(nil)
+	_, err = uc.Execute(ctx, repo.thread.ID, "payment.released", thread_domain.EventResourceRef{})
+	require.NoError(t, err)
+	assert.Empty(t, repo.log[2].Signature)
+}
+
+// flakyLogRepo fails the first append after recording its request.
+type flakyLogRepo struct {
+	signedLogRepo
+	attempts []thread_domain.AppendThreadEventRequest
+}
+
+func (s *flakyLogRepo) AppendThreadEvent(ctx context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	s.attempts = append(s.attempts, *req)
+	if len(s.attempts) == 1 {
+		return nil, errors.New("cloud unavailable")
+	}
+	return s.signedLogRepo.AppendThreadEvent(ctx, req)
+}
+
+func TestCloseThread_SignsTheLifecycleEventAndReportsItsID(t *testing.T) {
+	ctx := context.Background()
+	keys := stubDeviceKeys{}
+	repo := &flakyLogRepo{signedLogRepo: signedLogRepo{lifecycleThreadRepo: *newLifecycleRepo()}}
+	repo.log = []thread_domain.ThreadEvent{{ID: "evt-0", ThreadID: repo.thread.ID, Cursor: 1}}
+	events := thread_usecase.NewAppendThreadEventUsecase(repo)
+	events.SetSigner(newAuthor(t, "dev-1", keys))
+	bus := &stubThreadEventBus{}
+	closeUC := thread_usecase.NewCloseThreadUsecase(repo, bus).WithEvents(events)
+
+	_, err := closeUC.Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: repo.thread.ID, ActorID: "vault-a"})
+	require.Error(t, err)
+	_, err = closeUC.Execute(ctx, thread_dtos.CloseThreadRequest{ThreadID: repo.thread.ID, ActorID: "vault-a"})
+	require.NoError(t, err)
+
+	require.Len(t, repo.attempts, 2)
+	assert.Equal(t, repo.attempts[0].IdempotencyKey, repo.attempts[1].IdempotencyKey, "a retried transition reuses its key")
+
+	closed := repo.log[1]
+	assert.Equal(t, thread_domain.EventThreadClosed, closed.Type)
+	assert.NotEmpty(t, closed.Signature)
+	require.NotNil(t, closed.PreviousEventID)
+	assert.Equal(t, "evt-0", *closed.PreviousEventID)
+	assert.Equal(t, "vault-a", closed.Headers[thread_domain.HeaderActorID])
+
+	require.Len(t, bus.lifecycleEvents, 1)
+	assert.Equal(t, closed.ID, bus.lifecycleEvents[0].(thread_domain.ThreadClosedEvent).EventID)
+}
+
+func TestVerifyThreadEventsUsecase(t *testing.T) {
+	keys := stubDeviceKeys{}
+	repo := &stubEventLogRepo{events: regulatedLog(t, newAuthor(t, "dev-1", keys))}
+
+	report, err := thread_usecase.NewVerifyThreadEventsUsecase(repo, keys).Execute(context.Background(), "th-1")
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 3, report.Events)
+
+	_, err = thread_usecase.NewVerifyThreadEventsUsecase(repo, nil).Execute(context.Background(), "th-1")
+	assert.Error(t, err)
+}
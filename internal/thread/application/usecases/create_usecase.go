@@ -27,6 +27,15 @@ type CreateThreadUsecase struct {
 	Repo        thread_domain.ThreadRepository
 	DomainBus   thread_events.ThreadEventBus
 	ChannelReader ChannelGovernanceReader
+	// Policy, when set with a ChannelReader, enforces the channel's allowed
+	// asset types, expiry and the creator's thread.create permission.
+	Policy *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the channel policy check.
+func (uc *CreateThreadUsecase) WithPolicy(policy *channel_domain.PolicyEvaluator) *CreateThreadUsecase {
+	uc.Policy = policy
+	return uc
 }
 
 func NewCreateThreadUsecase(
@@ -73,6 +82,15 @@ func (uc *CreateThreadUsecase) Execute(ctx context.Context, req thread_dtos.Crea
 			}
 		}
 
+		if uc.Policy != nil {
+			if err := uc.Policy.CanUseAssetType(channel, req.AssetType); err != nil {
+				return nil, err
+			}
+			if err := uc.Policy.RequirePermission(channel, req.IdentityID, channel_domain.PermThreadCreate); err != nil {
+				return nil, err
+			}
+		}
+
 		// Channel is authoritative for WorkspaceID
 		if req.WorkspaceID != "" && req.WorkspaceID != channel.WorkspaceID {
 			return nil, thread_domain.ErrWorkspaceMismatch
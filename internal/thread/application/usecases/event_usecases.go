@@ -1,14 +1,33 @@
 package thread_usecase
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+
+	channel_domain "vault-app/internal/channel/domain"
 	thread_domain "vault-app/internal/thread/domain"
 )
 
 type ListThreadEventsUsecase struct {
 	Repo thread_domain.ThreadRepository
+	// ChannelReader and Policy, when both set, withhold the history of closed
+	// threads past the channel's retention window.
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the retention check.
+func (uc *ListThreadEventsUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *ListThreadEventsUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
 }
 
 func NewListThreadEventsUsecase(repo thread_domain.ThreadRepository) *ListThreadEventsUsecase {
@@ -24,6 +43,9 @@ func (uc *ListThreadEventsUsecase) Execute(ctx context.Context, threadID string)
 	if threadID == "" {
 		return nil, errors.New("thread id is required")
 	}
+	if err := uc.checkRetention(ctx, threadID); err != nil {
+		return nil, err
+	}
 
 	resp, err := uc.Repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{
 		ThreadID: threadID,
@@ -38,8 +60,119 @@ func (uc *ListThreadEventsUsecase) Execute(ctx context.Context, threadID string)
 	return resp.Data, nil
 }
 
+func (uc *ListThreadEventsUsecase) checkRetention(ctx context.Context, threadID string) error {
+	if uc.ChannelReader == nil || uc.Policy == nil {
+		return nil
+	}
+
+	getResp, err := uc.Repo.GetThread(ctx, &thread_domain.GetThreadRequest{ThreadID: threadID})
+	if err != nil {
+		return err
+	}
+	if getResp == nil {
+		return thread_domain.ErrRepositoryResponse
+	}
+	thread := getResp.Data
+	if thread.Status != thread_domain.ThreadClosed {
+		return nil
+	}
+
+	resp, err := uc.ChannelReader.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: thread.ChannelID})
+	if err != nil || resp == nil {
+		return thread_domain.ErrChannelNotFound
+	}
+	return uc.Policy.CheckRetention(&resp.Data, thread.ClosedAt)
+}
+
+// VerifyThreadEventsUsecase produces the tamper-evidence report of a thread
+// log without trusting the server.
+type VerifyThreadEventsUsecase struct {
+	Repo thread_domain.ThreadRepository
+	Keys thread_domain.DeviceKeyResolver
+}
+
+func NewVerifyThreadEventsUsecase(repo thread_domain.ThreadRepository, keys thread_domain.DeviceKeyResolver) *VerifyThreadEventsUsecase {
+	return &VerifyThreadEventsUsecase{
+		Repo: repo,
+		Keys: keys,
+	}
+}
+
+func (uc *VerifyThreadEventsUsecase) Execute(ctx context.Context, threadID string) (*thread_domain.ChainReport, error) {
+	if uc.Repo == nil {
+		return nil, errors.New("repository is required")
+	}
+	if uc.Keys == nil {
+		return nil, errors.New("device key resolver is required")
+	}
+	if threadID == "" {
+		return nil, errors.New("thread id is required")
+	}
+
+	resp, err := uc.Repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{
+		ThreadID: threadID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := []thread_domain.ThreadEvent{}
+	if resp != nil {
+		events = resp.Data
+	}
+
+	report := thread_domain.VerifyEventChain(ctx, threadID, events, uc.Keys)
+	return &report, nil
+}
+
 type AppendThreadEventUsecase struct {
 	Repo thread_domain.ThreadRepository
+	// ChannelReader and Policy, when both set, make every append consult the
+	// parent channel policy (roles, permissions, approvals, asset types,
+	// expiry).
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+	// Exchanges, when set, is handed every event the Cloud accepted so the
+	// channel's remote vaults receive it through federation.
+	Exchanges ExchangeFeed
+
+	mu sync.RWMutex
+	// signer, once a vault session is open, signs every append with the
+	// session's device key.
+	signer thread_domain.EventSigner
+}
+
+// ExchangeFeed queues an appended event as a federation exchange of its
+// channel.
+type ExchangeFeed interface {
+	FeedExchange(ctx context.Context, channelID string, exchangeID string, cursor uint64) error
+}
+
+// WithExchanges relays appended events to the channel's remote vaults.
+func (uc *AppendThreadEventUsecase) WithExchanges(exchanges ExchangeFeed) *AppendThreadEventUsecase {
+	uc.Exchanges = exchanges
+	return uc
+}
+
+// WithPolicy enables the channel policy check on appends.
+func (uc *AppendThreadEventUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *AppendThreadEventUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
+}
+
+// SetSigner sets the device signer of the open vault session; nil stops
+// signing.
+func (uc *AppendThreadEventUsecase) SetSigner(signer thread_domain.EventSigner) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.signer = signer
+}
+
+func (uc *AppendThreadEventUsecase) currentSigner() thread_domain.EventSigner {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.signer
 }
 
 func NewAppendThreadEventUsecase(repo thread_domain.ThreadRepository) *AppendThreadEventUsecase {
@@ -55,14 +188,22 @@ func (uc *AppendThreadEventUsecase) Execute(
 	payload thread_domain.EventResourceRef,
 	idempotencyKey ...string,
 ) (*thread_domain.ThreadEvent, error) {
-	if uc.Repo == nil {
-		return nil, errors.New("repository is required")
-	}
-	if threadID == "" {
-		return nil, errors.New("thread id is required")
-	}
-	if eventType == "" {
-		return nil, errors.New("event type is required")
+	return uc.ExecuteAs(ctx, "", threadID, eventType, payload, idempotencyKey...)
+}
+
+// ExecuteAs appends on behalf of actorVaultID, whose channel roles decide
+// which event types the policy lets through.
+func (uc *AppendThreadEventUsecase) ExecuteAs(
+	ctx context.Context,
+	actorVaultID string,
+	threadID string,
+	eventType string,
+	payload thread_domain.EventResourceRef,
+	idempotencyKey ...string,
+) (*thread_domain.ThreadEvent, error) {
+	thread, err := uc.authorize(ctx, actorVaultID, threadID, eventType, payload)
+	if err != nil {
+		return nil, err
 	}
 
 	key := ""
@@ -72,12 +213,71 @@ func (uc *AppendThreadEventUsecase) Execute(
 		key = "evt_share_" + payload.ShareEntryID
 	}
 
-	resp, err := uc.Repo.AppendThreadEvent(ctx, &thread_domain.AppendThreadEventRequest{
+	req := &thread_domain.AppendThreadEventRequest{
 		ThreadID:       threadID,
 		EventType:      eventType,
 		Payload:        payload,
 		IdempotencyKey: key,
-	})
+	}
+	// The actor is covered by the signature and counted by approval checks.
+	if actorVaultID != "" {
+		req.Headers = map[string]string{thread_domain.HeaderActorID: actorVaultID}
+	}
+	if signer := uc.currentSigner(); signer != nil {
+		head, err := uc.head(ctx, threadID)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.sign(signer, head, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.append(ctx, thread.ChannelID, req)
+}
+
+// AppendLifecycleEvent records a lifecycle transition of thread, signed like
+// any other append. Lifecycle events are the only events a thread accepts
+// outside the open state, so the append checks are skipped; the lifecycle use
+// cases run their own. The event is keyed by its type and the thread head it
+// follows, so retrying the same transition appends it once.
+func (uc *AppendThreadEventUsecase) AppendLifecycleEvent(
+	ctx context.Context,
+	thread *thread_domain.Thread,
+	eventType thread_domain.ThreadEventType,
+	headers map[string]string,
+) (*thread_domain.ThreadEvent, error) {
+	if uc.Repo == nil {
+		return nil, errors.New("repository is required")
+	}
+
+	head, err := uc.head(ctx, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+	after := "start"
+	if head != nil {
+		after = head.ID
+	}
+	req := &thread_domain.AppendThreadEventRequest{
+		ThreadID:       thread.ID,
+		EventType:      string(eventType),
+		IdempotencyKey: fmt.Sprintf("evt_lifecycle_%s_%s_after_%s", thread.ID, eventType, after),
+		Headers:        headers,
+	}
+	if signer := uc.currentSigner(); signer != nil {
+		if err := uc.sign(signer, head, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.append(ctx, thread.ChannelID, req)
+}
+
+// append stores req and relays the accepted event to the channel's remote
+// vaults.
+func (uc *AppendThreadEventUsecase) append(ctx context.Context, channelID string, req *thread_domain.AppendThreadEventRequest) (*thread_domain.ThreadEvent, error) {
+	resp, err := uc.Repo.AppendThreadEvent(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -85,5 +285,163 @@ func (uc *AppendThreadEventUsecase) Execute(
 		return nil, errors.New("empty repository response")
 	}
 
+	// Events queued offline have no Cloud cursor yet; they are relayed
+	// once appended again on reconnect.
+	if uc.Exchanges != nil && channelID != "" && resp.Data.Cursor > 0 {
+		if err := uc.Exchanges.FeedExchange(ctx, channelID, resp.Data.ID, resp.Data.Cursor); err != nil {
+			return nil, fmt.Errorf("event %s appended but not queued for federation: %w", resp.Data.ID, err)
+		}
+	}
+
 	return &resp.Data, nil
 }
+
+// head returns the latest event of a thread, or nil for an empty thread.
+func (uc *AppendThreadEventUsecase) head(ctx context.Context, threadID string) (*thread_domain.ThreadEvent, error) {
+	events, err := uc.Repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{ThreadID: threadID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve thread head: %w", err)
+	}
+	var head *thread_domain.ThreadEvent
+	if events != nil {
+		for i := range events.Data {
+			if head == nil || events.Data[i].Cursor > head.Cursor {
+				head = &events.Data[i]
+			}
+		}
+	}
+	return head, nil
+}
+
+// sign links req to head and signs it. The idempotency key is covered by
+// the signature, so it is fixed here rather than left to the repository.
+func (uc *AppendThreadEventUsecase) sign(signer thread_domain.EventSigner, head *thread_domain.ThreadEvent, req *thread_domain.AppendThreadEventRequest) error {
+	if head != nil {
+		prev := head.ID
+		req.PreviousEventID = &prev
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = uuid.NewString()
+	}
+
+	evt := thread_domain.ThreadEvent{
+		ThreadID:        req.ThreadID,
+		PreviousEventID: req.PreviousEventID,
+		Type:            thread_domain.ThreadEventType(req.EventType),
+		Payload:         req.Payload,
+		IdempotencyKey:  req.IdempotencyKey,
+		Headers:         req.Headers,
+	}
+	if err := thread_domain.SignEvent(&evt, signer, time.Now()); err != nil {
+		return err
+	}
+	req.Headers = evt.Headers
+	req.Signature = evt.Signature
+	return nil
+}
+
+// Authorize runs the checks an append must pass without appending: the
+// thread lifecycle state and, when configured, the channel policy. A thread
+// that cannot be resolved fails the check.
+func (uc *AppendThreadEventUsecase) Authorize(
+	ctx context.Context,
+	actorVaultID string,
+	threadID string,
+	eventType string,
+	payload thread_domain.EventResourceRef,
+) error {
+	_, err := uc.authorize(ctx, actorVaultID, threadID, eventType, payload)
+	return err
+}
+
+// authorize runs the Authorize checks and returns the resolved thread.
+func (uc *AppendThreadEventUsecase) authorize(
+	ctx context.Context,
+	actorVaultID string,
+	threadID string,
+	eventType string,
+	payload thread_domain.EventResourceRef,
+) (*thread_domain.Thread, error) {
+	if uc.Repo == nil {
+		return nil, errors.New("repository is required")
+	}
+	if threadID == "" {
+		return nil, errors.New("thread id is required")
+	}
+	if eventType == "" {
+		return nil, errors.New("event type is required")
+	}
+
+	resp, err := uc.Repo.GetThread(ctx, &thread_domain.GetThreadRequest{ThreadID: threadID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, thread_domain.ErrRepositoryResponse
+	}
+	thread := resp.Data
+	if thread.Status != "" {
+		if err := thread.CanAppend(); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.Policy == nil || uc.ChannelReader == nil || thread.ChannelID == "" {
+		return &thread, nil
+	}
+	if err := uc.checkPolicy(ctx, actorVaultID, thread, eventType, payload); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (uc *AppendThreadEventUsecase) checkPolicy(
+	ctx context.Context,
+	actorVaultID string,
+	thread thread_domain.Thread,
+	eventType string,
+	payload thread_domain.EventResourceRef,
+) error {
+	chResp, err := uc.ChannelReader.GetChannel(ctx, &channel_domain.GetChannelRequest{
+		ChannelID: thread.ChannelID,
+	})
+	if err != nil || chResp == nil {
+		return thread_domain.ErrChannelNotFound
+	}
+	channel := &chResp.Data
+	if channel.Status == channel_domain.StatusArchived {
+		return thread_domain.ErrChannelArchived
+	}
+
+	if err := uc.Policy.CanAppendEvent(channel, actorVaultID, eventType); err != nil {
+		return err
+	}
+	if err := uc.Policy.CanUseAssetType(channel, payload.AssetType); err != nil {
+		return err
+	}
+	if err := uc.Policy.RequirePermission(channel, actorVaultID, channel_domain.ThreadAppendPermission(eventType)); err != nil {
+		return err
+	}
+	if payload.RefType == thread_domain.ResourceShareEntry {
+		if err := uc.Policy.RequirePermission(channel, actorVaultID, channel_domain.PermAssetShare); err != nil {
+			return err
+		}
+	}
+
+	if !uc.Policy.RequiresApprovals(channel, eventType) {
+		return nil
+	}
+	events, err := uc.Repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{ThreadID: thread.ID})
+	if err != nil {
+		return err
+	}
+	history := []channel_domain.ThreadHistoryEntry{}
+	if events != nil {
+		log := slices.Clone(events.Data)
+		slices.SortStableFunc(log, func(a, b thread_domain.ThreadEvent) int { return cmp.Compare(a.Cursor, b.Cursor) })
+		for _, e := range log {
+			history = append(history, channel_domain.ThreadHistoryEntry{EventType: string(e.Type), ActorVaultID: e.Headers[thread_domain.HeaderActorID]})
+		}
+	}
+	return uc.Policy.CheckApprovals(channel, eventType, history)
+}
@@ -0,0 +1,431 @@
+package thread_usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_dtos "vault-app/internal/thread/application/dtos"
+	thread_events "vault-app/internal/thread/application/events"
+	thread_domain "vault-app/internal/thread/domain"
+)
+
+// transition loads a thread, applies a lifecycle transition on the aggregate,
+// persists it and records the matching thread event through events, which
+// signs it with the session device. Without events the event is appended
+// unsigned. When the event cannot be appended, the stored thread is put back
+// as it was loaded. It returns the appended event's ID.
+func transition(
+	ctx context.Context,
+	repo thread_domain.ThreadRepository,
+	events *AppendThreadEventUsecase,
+	threadID string,
+	eventType thread_domain.ThreadEventType,
+	headers map[string]string,
+	apply func(thread *thread_domain.Thread) error,
+) (*thread_domain.Thread, string, error) {
+	getResp, err := repo.GetThread(ctx, &thread_domain.GetThreadRequest{ThreadID: threadID})
+	if err != nil {
+		return nil, "", err
+	}
+	if getResp == nil {
+		return nil, "", thread_domain.ErrRepositoryResponse
+	}
+
+	thread := getResp.Data
+	if err := apply(&thread); err != nil {
+		return nil, "", err
+	}
+
+	updated, err := repo.UpdateThread(ctx, &thread_domain.UpdateThreadRequest{Thread: thread})
+	if err != nil {
+		return nil, "", err
+	}
+	if updated == nil {
+		return nil, "", thread_domain.ErrRepositoryResponse
+	}
+
+	if events == nil {
+		events = NewAppendThreadEventUsecase(repo)
+	}
+	evt, err := events.AppendLifecycleEvent(ctx, &thread, eventType, headers)
+	if err != nil {
+		if _, rbErr := repo.UpdateThread(ctx, &thread_domain.UpdateThreadRequest{Thread: getResp.Data}); rbErr != nil {
+			return nil, "", fmt.Errorf("%w (restoring thread status failed: %w)", err, rbErr)
+		}
+		return nil, "", err
+	}
+
+	return &updated.Data, evt.ID, nil
+}
+
+// requireThreadManage checks the actor holds thread.manage in the thread's
+// channel, which must not be archived. It passes when the use case was built
+// without a policy.
+func requireThreadManage(
+	ctx context.Context,
+	channelReader ChannelGovernanceReader,
+	policy *channel_domain.PolicyEvaluator,
+	channelID string,
+	actorID string,
+) error {
+	channel, err := loadWritableChannel(ctx, channelReader, policy, channelID)
+	if err != nil || channel == nil {
+		return err
+	}
+	return policy.RequirePermission(channel, actorID, channel_domain.PermThreadManage)
+}
+
+// loadWritableChannel loads the thread's channel and fails when it is
+// archived. It returns nil, nil when the use case was built without a policy.
+func loadWritableChannel(
+	ctx context.Context,
+	channelReader ChannelGovernanceReader,
+	policy *channel_domain.PolicyEvaluator,
+	channelID string,
+) (*channel_domain.Channel, error) {
+	if channelReader == nil || policy == nil || channelID == "" {
+		return nil, nil
+	}
+
+	resp, err := channelReader.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil || resp == nil {
+		return nil, thread_domain.ErrChannelNotFound
+	}
+	if resp.Data.Status == channel_domain.StatusArchived {
+		return nil, thread_domain.ErrChannelArchived
+	}
+	return &resp.Data, nil
+}
+
+// -------- CLOSE --------
+
+type CloseThreadUsecase struct {
+	Repo      thread_domain.ThreadRepository
+	DomainBus thread_events.ThreadEventBus
+	// ChannelReader and Policy, when both set, require thread.manage.
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+	// Events, when set, signs the lifecycle event like any other append.
+	Events *AppendThreadEventUsecase
+}
+
+// WithEvents appends the lifecycle event through the append use case.
+func (uc *CloseThreadUsecase) WithEvents(events *AppendThreadEventUsecase) *CloseThreadUsecase {
+	uc.Events = events
+	return uc
+}
+
+// WithPolicy enables the thread.manage permission check.
+func (uc *CloseThreadUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *CloseThreadUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
+}
+
+func NewCloseThreadUsecase(repo thread_domain.ThreadRepository, threadBus thread_events.ThreadEventBus) *CloseThreadUsecase {
+	return &CloseThreadUsecase{
+		Repo:      repo,
+		DomainBus: threadBus,
+	}
+}
+
+func (uc *CloseThreadUsecase) Execute(ctx context.Context, req thread_dtos.CloseThreadRequest) (*thread_domain.Thread, error) {
+	if err := validateLifecycleDependencies(uc.Repo, uc.DomainBus); err != nil {
+		return nil, err
+	}
+	if req.ThreadID == "" {
+		return nil, thread_domain.ErrThreadIDRequired
+	}
+
+	thread, eventID, err := transition(ctx, uc.Repo, uc.Events, req.ThreadID, thread_domain.EventThreadClosed,
+		map[string]string{thread_domain.HeaderActorID: req.ActorID, thread_domain.HeaderReason: req.Reason},
+		func(th *thread_domain.Thread) error {
+			if err := requireThreadManage(ctx, uc.ChannelReader, uc.Policy, th.ChannelID, req.ActorID); err != nil {
+				return err
+			}
+			return th.Close()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.DomainBus.PublishThreadClosed(ctx, thread_domain.ThreadClosedEvent{
+		EventID:     eventID,
+		ThreadID:    thread.ID,
+		ChannelID:   thread.ChannelID,
+		WorkspaceID: thread.WorkspaceID,
+		ActorID:     req.ActorID,
+		Reason:      req.Reason,
+		Timestamp:   time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+// -------- REOPEN --------
+
+// ReopenThreadUsecase is gated by the parent channel: the channel must be
+// active and its policy must allow reopening threads.
+type ReopenThreadUsecase struct {
+	Repo          thread_domain.ThreadRepository
+	DomainBus     thread_events.ThreadEventBus
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+	// Events, when set, signs the lifecycle event like any other append.
+	Events *AppendThreadEventUsecase
+}
+
+// WithEvents appends the lifecycle event through the append use case.
+func (uc *ReopenThreadUsecase) WithEvents(events *AppendThreadEventUsecase) *ReopenThreadUsecase {
+	uc.Events = events
+	return uc
+}
+
+func NewReopenThreadUsecase(
+	repo thread_domain.ThreadRepository,
+	threadBus thread_events.ThreadEventBus,
+	channelReader ChannelGovernanceReader,
+) *ReopenThreadUsecase {
+	return &ReopenThreadUsecase{
+		Repo:          repo,
+		DomainBus:     threadBus,
+		ChannelReader: channelReader,
+		Policy:        channel_domain.NewPolicyEvaluator(),
+	}
+}
+
+// WithPolicy replaces the channel reader and evaluator the reopen check uses.
+func (uc *ReopenThreadUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *ReopenThreadUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
+}
+
+func (uc *ReopenThreadUsecase) Execute(ctx context.Context, req thread_dtos.ReopenThreadRequest) (*thread_domain.Thread, error) {
+	if err := validateLifecycleDependencies(uc.Repo, uc.DomainBus); err != nil {
+		return nil, err
+	}
+	if req.ThreadID == "" {
+		return nil, thread_domain.ErrThreadIDRequired
+	}
+
+	thread, eventID, err := transition(ctx, uc.Repo, uc.Events, req.ThreadID, thread_domain.EventThreadReopened,
+		map[string]string{thread_domain.HeaderActorID: req.ActorID, thread_domain.HeaderReason: req.Reason},
+		func(th *thread_domain.Thread) error {
+			if err := uc.checkReopenPolicy(ctx, th, req.ActorID); err != nil {
+				return err
+			}
+			return th.Reopen()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.DomainBus.PublishThreadReopened(ctx, thread_domain.ThreadReopened{
+		EventID:     eventID,
+		ThreadID:    thread.ID,
+		ChannelID:   thread.ChannelID,
+		WorkspaceID: thread.WorkspaceID,
+		ActorID:     req.ActorID,
+		Reason:      req.Reason,
+		Timestamp:   time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+// checkReopenPolicy denies by default: without a channel reader, or unless
+// the policy sets AllowThreadReopen, closed threads stay closed. A thread past
+// the retention window stays closed too. The actor also needs thread.manage.
+func (uc *ReopenThreadUsecase) checkReopenPolicy(ctx context.Context, thread *thread_domain.Thread, actorID string) error {
+	if uc.ChannelReader == nil {
+		return thread_domain.ErrThreadReopenNotAllowed
+	}
+
+	resp, err := uc.ChannelReader.GetChannel(ctx, &channel_domain.GetChannelRequest{
+		ChannelID: thread.ChannelID,
+	})
+	if err != nil || resp == nil {
+		return thread_domain.ErrChannelNotFound
+	}
+
+	channel := &resp.Data
+	if channel.Status != channel_domain.StatusActive {
+		return thread_domain.ErrChannelNotActive
+	}
+
+	policy := uc.Policy
+	if policy == nil {
+		policy = channel_domain.NewPolicyEvaluator()
+	}
+	if err := policy.CanReopenThread(channel); err != nil {
+		return fmt.Errorf("%w: %w", thread_domain.ErrThreadReopenNotAllowed, err)
+	}
+	if err := policy.CheckRetention(channel, thread.ClosedAt); err != nil {
+		return fmt.Errorf("%w: %w", thread_domain.ErrThreadReopenNotAllowed, err)
+	}
+
+	return policy.RequirePermission(channel, actorID, channel_domain.PermThreadManage)
+}
+
+// -------- TRANSFER --------
+
+type InitiateThreadTransferUsecase struct {
+	Repo      thread_domain.ThreadRepository
+	DomainBus thread_events.ThreadEventBus
+	// ChannelReader and Policy, when both set, require thread.manage.
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+	// Events, when set, signs the lifecycle event like any other append.
+	Events *AppendThreadEventUsecase
+}
+
+// WithEvents appends the lifecycle event through the append use case.
+func (uc *InitiateThreadTransferUsecase) WithEvents(events *AppendThreadEventUsecase) *InitiateThreadTransferUsecase {
+	uc.Events = events
+	return uc
+}
+
+// WithPolicy enables the thread.manage permission check.
+func (uc *InitiateThreadTransferUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *InitiateThreadTransferUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
+}
+
+func NewInitiateThreadTransferUsecase(repo thread_domain.ThreadRepository, threadBus thread_events.ThreadEventBus) *InitiateThreadTransferUsecase {
+	return &InitiateThreadTransferUsecase{
+		Repo:      repo,
+		DomainBus: threadBus,
+	}
+}
+
+func (uc *InitiateThreadTransferUsecase) Execute(ctx context.Context, req thread_dtos.InitiateThreadTransferRequest) (*thread_domain.Thread, error) {
+	if err := validateLifecycleDependencies(uc.Repo, uc.DomainBus); err != nil {
+		return nil, err
+	}
+	if req.ThreadID == "" {
+		return nil, thread_domain.ErrThreadIDRequired
+	}
+	if req.ToVaultID == "" {
+		return nil, thread_domain.ErrThreadTransferTargetRequired
+	}
+
+	thread, eventID, err := transition(ctx, uc.Repo, uc.Events, req.ThreadID, thread_domain.EventThreadTransferInitiated,
+		map[string]string{thread_domain.HeaderActorID: req.ActorID, thread_domain.HeaderTransferTo: req.ToVaultID},
+		func(th *thread_domain.Thread) error {
+			if err := requireThreadManage(ctx, uc.ChannelReader, uc.Policy, th.ChannelID, req.ActorID); err != nil {
+				return err
+			}
+			return th.InitiateTransfer(req.ToVaultID)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.DomainBus.PublishThreadTransferInitiated(ctx, thread_domain.ThreadTransferInitiated{
+		EventID:     eventID,
+		ThreadID:    thread.ID,
+		ChannelID:   thread.ChannelID,
+		WorkspaceID: thread.WorkspaceID,
+		ActorID:     req.ActorID,
+		ToVaultID:   req.ToVaultID,
+		Timestamp:   time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+type CompleteThreadTransferUsecase struct {
+	Repo      thread_domain.ThreadRepository
+	DomainBus thread_events.ThreadEventBus
+	// ChannelReader and Policy, when both set, require the actor to be the
+	// transfer recipient or to hold thread.manage.
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+	// Events, when set, signs the lifecycle event like any other append.
+	Events *AppendThreadEventUsecase
+}
+
+// WithEvents appends the lifecycle event through the append use case.
+func (uc *CompleteThreadTransferUsecase) WithEvents(events *AppendThreadEventUsecase) *CompleteThreadTransferUsecase {
+	uc.Events = events
+	return uc
+}
+
+// WithPolicy enables the recipient check.
+func (uc *CompleteThreadTransferUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *CompleteThreadTransferUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
+}
+
+func NewCompleteThreadTransferUsecase(repo thread_domain.ThreadRepository, threadBus thread_events.ThreadEventBus) *CompleteThreadTransferUsecase {
+	return &CompleteThreadTransferUsecase{
+		Repo:      repo,
+		DomainBus: threadBus,
+	}
+}
+
+func (uc *CompleteThreadTransferUsecase) Execute(ctx context.Context, req thread_dtos.CompleteThreadTransferRequest) (*thread_domain.Thread, error) {
+	if err := validateLifecycleDependencies(uc.Repo, uc.DomainBus); err != nil {
+		return nil, err
+	}
+	if req.ThreadID == "" {
+		return nil, thread_domain.ErrThreadIDRequired
+	}
+
+	headers := map[string]string{thread_domain.HeaderActorID: req.ActorID}
+	thread, eventID, err := transition(ctx, uc.Repo, uc.Events, req.ThreadID, thread_domain.EventThreadTransferCompleted, headers,
+		func(th *thread_domain.Thread) error {
+			if th.Status == thread_domain.ThreadTransferring && th.TransferTo != req.ActorID {
+				if err := requireThreadManage(ctx, uc.ChannelReader, uc.Policy, th.ChannelID, req.ActorID); err != nil {
+					return err
+				}
+			} else if _, err := loadWritableChannel(ctx, uc.ChannelReader, uc.Policy, th.ChannelID); err != nil {
+				return err
+			}
+			to, err := th.CompleteTransfer()
+			headers[thread_domain.HeaderTransferTo] = to
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.DomainBus.PublishThreadTransferCompleted(ctx, thread_domain.ThreadTransferCompleted{
+		EventID:     eventID,
+		ThreadID:    thread.ID,
+		ChannelID:   thread.ChannelID,
+		WorkspaceID: thread.WorkspaceID,
+		ActorID:     req.ActorID,
+		ToVaultID:   headers[thread_domain.HeaderTransferTo],
+		Timestamp:   time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+func validateLifecycleDependencies(repo thread_domain.ThreadRepository, bus thread_events.ThreadEventBus) error {
+	if repo == nil {
+		return thread_domain.ErrRepositoryNil
+	}
+	if bus == nil {
+		return thread_domain.ErrThreadBusRequired
+	}
+	return nil
+}
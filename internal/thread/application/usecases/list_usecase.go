@@ -5,11 +5,24 @@ import (
 	"errors"
 	"log"
 
+	channel_domain "vault-app/internal/channel/domain"
 	thread_domain "vault-app/internal/thread/domain"
 )
 
 type ListThreadsUsecase struct {
 	Repo thread_domain.ThreadRepository
+	// ChannelReader and Policy, when both set, show the threads of a channel
+	// that enforces permissions to its participants only.
+	ChannelReader ChannelGovernanceReader
+	Policy        *channel_domain.PolicyEvaluator
+}
+
+// WithPolicy enables the participant check. Participant records count when
+// channelReader can list them.
+func (uc *ListThreadsUsecase) WithPolicy(channelReader ChannelGovernanceReader, policy *channel_domain.PolicyEvaluator) *ListThreadsUsecase {
+	uc.ChannelReader = channelReader
+	uc.Policy = policy
+	return uc
 }
 
 func NewListThreadsUsecase(repo thread_domain.ThreadRepository) *ListThreadsUsecase {
@@ -19,6 +32,11 @@ func NewListThreadsUsecase(repo thread_domain.ThreadRepository) *ListThreadsUsec
 }
 
 func (uc *ListThreadsUsecase) Execute(ctx context.Context, channelID string) ([]thread_domain.Thread, error) {
+	return uc.ExecuteAs(ctx, "", channelID)
+}
+
+// ExecuteAs lists the threads of channelID on behalf of actorVaultID.
+func (uc *ListThreadsUsecase) ExecuteAs(ctx context.Context, actorVaultID string, channelID string) ([]thread_domain.Thread, error) {
 	log.Printf("[THREAD LIST USECASE] channelID=%s", channelID)
 	if uc.Repo == nil {
 		return nil, errors.New("repository is required")
@@ -26,6 +44,9 @@ func (uc *ListThreadsUsecase) Execute(ctx context.Context, channelID string) ([]
 	if channelID == "" {
 		return nil, errors.New("channel id is required")
 	}
+	if err := uc.requireParticipant(ctx, actorVaultID, channelID); err != nil {
+		return nil, err
+	}
 
 	resp, err := uc.Repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{
 		ChannelID: channelID,
@@ -40,3 +61,17 @@ func (uc *ListThreadsUsecase) Execute(ctx context.Context, channelID string) ([]
 	log.Printf("[THREAD LIST USECASE RETURN] count=%d", len(resp.Data))
 	return resp.Data, nil
 }
+
+func (uc *ListThreadsUsecase) requireParticipant(ctx context.Context, actorVaultID string, channelID string) error {
+	if uc.ChannelReader == nil || uc.Policy == nil {
+		return nil
+	}
+
+	resp, err := uc.ChannelReader.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: channelID})
+	if err != nil || resp == nil {
+		return thread_domain.ErrChannelNotFound
+	}
+	channel := &resp.Data
+	lister, _ := uc.ChannelReader.(channel_domain.ParticipantLister)
+	return uc.Policy.RequireParticipant(channel, actorVaultID, uc.Policy.GoverningParticipants(ctx, lister, channel)...)
+}
@@ -24,6 +24,7 @@ type Thread struct {
 	Status      ThreadStatus `json:"status"`
 	CreatedAt   time.Time    `json:"created_at"`
 	ClosedAt    *time.Time   `json:"closed_at,omitempty"`
+	TransferTo  string       `json:"transfer_to,omitempty"`
 	IsDraft     bool         `json:"is_draft"`
 	IsDirty     bool         `json:"is_dirty" gorm:"boolean"`
 }
@@ -40,7 +41,93 @@ func NewThread(channelID string, assetType string, title string, subtitle string
 		IsDraft:   true,
 		IsDirty:   false,
 	}
-}	
+}
+
+// ==============================================================================
+// Lifecycle
+// ==============================================================================
+//
+//	open ──Close──▶ closed ──Reopen──▶ open
+//	open ──InitiateTransfer──▶ transferring ──CompleteTransfer──▶ open
+//
+// Events can only be appended while the thread is open.
+
+// CanAppend reports whether new events may be appended in the current state.
+func (t *Thread) CanAppend() error {
+	switch t.Status {
+	case ThreadOpen:
+		return nil
+	case ThreadClosed:
+		return ErrThreadClosed
+	case ThreadTransferring:
+		return ErrThreadTransferInProgress
+	default:
+		return ErrThreadStatusInvalid
+	}
+}
+
+// Close transitions an open thread to closed. A thread in transfer must
+// complete the transfer first.
+func (t *Thread) Close() error {
+	switch t.Status {
+	case ThreadOpen:
+	case ThreadClosed:
+		return ErrThreadClosed
+	case ThreadTransferring:
+		return ErrThreadTransferInProgress
+	default:
+		return ErrThreadStatusInvalid
+	}
+
+	now := time.Now().UTC()
+	t.Status = ThreadClosed
+	t.ClosedAt = &now
+
+	return nil
+}
+
+// Reopen transitions a closed thread back to open. Whether reopening is
+// allowed at all is a channel policy decision taken by the caller.
+func (t *Thread) Reopen() error {
+	if t.Status != ThreadClosed {
+		return ErrThreadNotClosed
+	}
+
+	t.Status = ThreadOpen
+	t.ClosedAt = nil
+
+	return nil
+}
+
+// InitiateTransfer hands the thread over to the given vault. Appends are
+// blocked until the transfer completes.
+func (t *Thread) InitiateTransfer(toVaultID string) error {
+	if toVaultID == "" {
+		return ErrThreadTransferTargetRequired
+	}
+	if err := t.CanAppend(); err != nil {
+		return err
+	}
+
+	t.Status = ThreadTransferring
+	t.TransferTo = toVaultID
+
+	return nil
+}
+
+// CompleteTransfer reopens the thread for the recipient and returns the vault
+// it was transferred to.
+func (t *Thread) CompleteTransfer() (string, error) {
+	if t.Status != ThreadTransferring {
+		return "", ErrThreadNotTransferring
+	}
+
+	to := t.TransferTo
+	t.Status = ThreadOpen
+	t.TransferTo = ""
+
+	return to, nil
+}
 
 type InsertStatus int
 
@@ -59,6 +146,18 @@ const (
 	EventReceiptIssued         ThreadEventType = "receipt.issued"
 	EventFederationEntryShared ThreadEventType = "federation.shared.created"
 	EventThreadEventAppended   ThreadEventType = "thread.event.appended"
+
+	EventThreadClosed            ThreadEventType = "thread.closed"
+	EventThreadReopened          ThreadEventType = "thread.reopened"
+	EventThreadTransferInitiated ThreadEventType = "thread.transfer.initiated"
+	EventThreadTransferCompleted ThreadEventType = "thread.transfer.completed"
+)
+
+// Headers recorded on lifecycle thread events.
+const (
+	HeaderActorID    = "actor_id"
+	HeaderReason     = "reason"
+	HeaderTransferTo = "transfer_to"
 )
 
 type ResourceType string
@@ -0,0 +1,307 @@
+package thread_domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Headers carried by signed thread events.
+const (
+	HeaderAuthorDeviceID = "author_device_id"
+	HeaderContentHash    = "content_hash"
+	// HeaderSignedAt is the author's signing time (RFC 3339). Unlike the
+	// server-assigned CreatedAt it is covered by the signature.
+	HeaderSignedAt = "signed_at"
+)
+
+const DeviceKeyTypeEd25519 = "ed25519"
+
+// ChainIssueKind classifies a problem found while verifying a thread log.
+type ChainIssueKind string
+
+const (
+	ChainIssueReordered        ChainIssueKind = "reordered"
+	ChainIssueGap              ChainIssueKind = "gap"
+	ChainIssueFork             ChainIssueKind = "fork"
+	ChainIssueBrokenLink       ChainIssueKind = "broken_link"
+	ChainIssueWrongThread      ChainIssueKind = "wrong_thread"
+	ChainIssueHashMismatch     ChainIssueKind = "hash_mismatch"
+	ChainIssueMissingSignature ChainIssueKind = "missing_signature"
+	ChainIssueUnknownDevice    ChainIssueKind = "unknown_device"
+	ChainIssueRevokedDevice    ChainIssueKind = "revoked_device"
+	ChainIssueBadSignature     ChainIssueKind = "bad_signature"
+)
+
+type ChainIssue struct {
+	EventID string         `json:"event_id"`
+	Cursor  uint64         `json:"cursor"`
+	Kind    ChainIssueKind `json:"kind"`
+	Detail  string         `json:"detail"`
+}
+
+// ChainReport is the tamper-evidence verdict over a thread log. Valid is
+// true only when no issue was found.
+type ChainReport struct {
+	ThreadID    string       `json:"thread_id"`
+	Events      int          `json:"events"`
+	HeadEventID string       `json:"head_event_id,omitempty"`
+	HeadCursor  uint64       `json:"head_cursor"`
+	Valid       bool         `json:"valid"`
+	Issues      []ChainIssue `json:"issues"`
+}
+
+// DeviceKey is the registered public key of an author device.
+type DeviceKey struct {
+	DeviceID  string
+	PublicKey string // base64 or hex encoded raw key
+	KeyType   string
+	RevokedAt *time.Time
+}
+
+// DeviceKeyResolver looks up the key of the device that authored an event.
+type DeviceKeyResolver interface {
+	DeviceKey(ctx context.Context, deviceID string) (*DeviceKey, error)
+}
+
+// EventSigner signs thread events with the key of a registered author
+// device.
+type EventSigner interface {
+	DeviceID() string
+	Sign(digest []byte) ([]byte, error)
+}
+
+// SignEvent stamps e with the author device and signing time, both covered
+// by the content hash, then records that hash and the author's signature.
+func SignEvent(e *ThreadEvent, signer EventSigner, signedAt time.Time) error {
+	if signer == nil || signer.DeviceID() == "" {
+		return ErrEventSignerRequired
+	}
+	headers := make(map[string]string, len(e.Headers)+3)
+	for k, v := range e.Headers {
+		headers[k] = v
+	}
+	delete(headers, HeaderContentHash)
+	headers[HeaderAuthorDeviceID] = signer.DeviceID()
+	headers[HeaderSignedAt] = signedAt.UTC().Format(time.RFC3339Nano)
+	e.Headers = headers
+
+	digest, err := EventContentHash(*e)
+	if err != nil {
+		return err
+	}
+	sig, err := signer.Sign(digest)
+	if err != nil {
+		return fmt.Errorf("sign thread event: %w", err)
+	}
+	e.Headers[HeaderContentHash] = hex.EncodeToString(digest)
+	e.Signature = base64.StdEncoding.EncodeToString(sig)
+	return nil
+}
+
+// canonicalEvent is the author-controlled content of an event. Cursor,
+// CreatedAt and ID are assigned by the server and are not covered; the link
+// to the predecessor is.
+type canonicalEvent struct {
+	ThreadID        string            `json:"thread_id"`
+	PreviousEventID string            `json:"previous_event_id"`
+	Type            ThreadEventType   `json:"type"`
+	Payload         EventResourceRef  `json:"payload"`
+	IdempotencyKey  string            `json:"idempotency_key"`
+	Headers         map[string]string `json:"headers"`
+}
+
+// CanonicalEventContent returns the bytes an event's content hash covers:
+// compact JSON with sorted header keys, excluding the content_hash header
+// itself.
+func CanonicalEventContent(e ThreadEvent) ([]byte, error) {
+	headers := make(map[string]string, len(e.Headers))
+	for k, v := range e.Headers {
+		if k == HeaderContentHash {
+			continue
+		}
+		headers[k] = v
+	}
+
+	prev := ""
+	if e.PreviousEventID != nil {
+		prev = *e.PreviousEventID
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(canonicalEvent{
+		ThreadID:        e.ThreadID,
+		PreviousEventID: prev,
+		Type:            e.Type,
+		Payload:         e.Payload,
+		IdempotencyKey:  e.IdempotencyKey,
+		Headers:         headers,
+	}); err != nil {
+		return nil, fmt.Errorf("encode canonical event: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// EventContentHash returns the SHA-256 digest of the canonical event. The
+// author signs this digest.
+func EventContentHash(e ThreadEvent) ([]byte, error) {
+	content, err := CanonicalEventContent(e)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	return sum[:], nil
+}
+
+func decodeKey(s string) ([]byte, error) {
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return hex.DecodeString(s)
+}
+
+// VerifyEventChain checks a thread log as returned by the server without
+// trusting it: events are ordered by cursor, each must link to its
+// predecessor, cursors must be contiguous, and every event's content hash
+// and author signature must verify.
+func VerifyEventChain(ctx context.Context, threadID string, events []ThreadEvent, keys DeviceKeyResolver) ChainReport {
+	report := ChainReport{ThreadID: threadID, Events: len(events), Issues: []ChainIssue{}}
+	flag := func(e ThreadEvent, kind ChainIssueKind, format string, args ...any) {
+		report.Issues = append(report.Issues, ChainIssue{
+			EventID: e.ID,
+			Cursor:  e.Cursor,
+			Kind:    kind,
+			Detail:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	ordered := append([]ThreadEvent(nil), events...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Cursor < ordered[j].Cursor })
+
+	for i := range events {
+		if events[i].ID != ordered[i].ID {
+			flag(events[i], ChainIssueReordered, "server returned event at position %d out of cursor order", i)
+			break
+		}
+	}
+
+	seen := make(map[string]int, len(ordered))
+	children := make(map[string]string, len(ordered))
+
+	for i, e := range ordered {
+		if threadID != "" && e.ThreadID != threadID {
+			flag(e, ChainIssueWrongThread, "event belongs to thread %q", e.ThreadID)
+		}
+
+		prev := ""
+		if e.PreviousEventID != nil {
+			prev = *e.PreviousEventID
+		}
+
+		if i > 0 {
+			before := ordered[i-1]
+			switch {
+			case e.Cursor == before.Cursor:
+				flag(e, ChainIssueFork, "cursor %d shared with event %q", e.Cursor, before.ID)
+			case e.Cursor != before.Cursor+1:
+				flag(e, ChainIssueGap, "cursor jumps from %d to %d", before.Cursor, e.Cursor)
+			}
+		}
+
+		if other, ok := children[prev]; ok {
+			flag(e, ChainIssueFork, "event %q already extends %q", other, prev)
+		} else {
+			children[prev] = e.ID
+		}
+
+		switch {
+		case i == 0 && prev != "":
+			if _, known := seen[prev]; !known {
+				flag(e, ChainIssueBrokenLink, "first event links to %q which is not in the log", prev)
+			}
+		case i > 0 && prev != ordered[i-1].ID:
+			if _, known := seen[prev]; known {
+				flag(e, ChainIssueFork, "links to %q instead of its predecessor %q", prev, ordered[i-1].ID)
+			} else {
+				flag(e, ChainIssueBrokenLink, "links to %q instead of its predecessor %q", prev, ordered[i-1].ID)
+			}
+		}
+		seen[e.ID] = i
+
+		verifyEventSignature(ctx, e, keys, flag)
+	}
+
+	if n := len(ordered); n > 0 {
+		report.HeadEventID = ordered[n-1].ID
+		report.HeadCursor = ordered[n-1].Cursor
+	}
+	report.Valid = len(report.Issues) == 0
+	return report
+}
+
+func verifyEventSignature(ctx context.Context, e ThreadEvent, keys DeviceKeyResolver, flag func(ThreadEvent, ChainIssueKind, string, ...any)) {
+	digest, err := EventContentHash(e)
+	if err != nil {
+		flag(e, ChainIssueHashMismatch, "cannot hash event: %v", err)
+		return
+	}
+	if claimed := e.Headers[HeaderContentHash]; claimed != "" && claimed != hex.EncodeToString(digest) {
+		flag(e, ChainIssueHashMismatch, "content hash %s does not match recomputed %x", claimed, digest)
+		return
+	}
+
+	if e.Signature == "" {
+		flag(e, ChainIssueMissingSignature, "event is not signed")
+		return
+	}
+
+	deviceID := e.Headers[HeaderAuthorDeviceID]
+	if deviceID == "" || keys == nil {
+		flag(e, ChainIssueUnknownDevice, "author device %q cannot be resolved", deviceID)
+		return
+	}
+	key, err := keys.DeviceKey(ctx, deviceID)
+	if err != nil || key == nil {
+		flag(e, ChainIssueUnknownDevice, "author device %q: %v", deviceID, err)
+		return
+	}
+	if key.KeyType != "" && key.KeyType != DeviceKeyTypeEd25519 {
+		flag(e, ChainIssueBadSignature, "unsupported device key type %q", key.KeyType)
+		return
+	}
+	if key.RevokedAt != nil && signedAfterRevocation(e, *key.RevokedAt) {
+		flag(e, ChainIssueRevokedDevice, "device %q was revoked at %s", deviceID, key.RevokedAt.Format(time.RFC3339))
+		return
+	}
+
+	pub, err := decodeKey(key.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		flag(e, ChainIssueBadSignature, "device %q has a malformed public key", deviceID)
+		return
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.Signature)
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(pub), digest, sig) {
+		flag(e, ChainIssueBadSignature, "signature does not verify against device %q", deviceID)
+	}
+}
+
+// signedAfterRevocation reports whether e may postdate revokedAt. The signed
+// timestamp is authoritative; an event without one cannot prove it predates
+// the revocation. The server's CreatedAt is checked too, so a revoked key
+// cannot backdate its own events.
+func signedAfterRevocation(e ThreadEvent, revokedAt time.Time) bool {
+	signedAt, err := time.Parse(time.RFC3339Nano, e.Headers[HeaderSignedAt])
+	if err != nil || signedAt.After(revokedAt) {
+		return true
+	}
+	return !e.CreatedAt.IsZero() && e.CreatedAt.After(revokedAt)
+}
@@ -16,7 +16,16 @@ var (
 	ErrThreadSubtitleRequired    = errors.New("thread subtitle is required")
 	ErrChannelNotFound           = errors.New("channel not found")
 	ErrChannelNotActive          = errors.New("channel is not active")
+	ErrChannelArchived           = errors.New("channel is archived; its threads are read-only")
 	ErrChannelGatedSlotsIncomplete = errors.New("channel gated slots are incomplete")
 	ErrWorkspaceMismatch         = errors.New("workspace ID does not match channel workspace")
 	ErrThreadClosed              = errors.New("thread is closed")
+	ErrEventSignerRequired       = errors.New("thread event signer is required")
+	ErrAuthorDeviceNotRegistered = errors.New("no active device is registered for the signing key")
+	ErrThreadNotClosed           = errors.New("thread is not closed")
+	ErrThreadNotTransferring     = errors.New("thread is not being transferred")
+	ErrThreadTransferInProgress  = errors.New("thread transfer is in progress")
+	ErrThreadTransferTargetRequired = errors.New("thread transfer target is required")
+	ErrThreadReopenNotAllowed    = errors.New("channel policy does not allow reopening threads")
+	ErrThreadStatusInvalid       = errors.New("thread status is invalid")
 )
\ No newline at end of file
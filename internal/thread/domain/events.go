@@ -19,3 +19,45 @@ type ThreadUpdated struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+
+// ThreadClosedEvent is published when a thread is closed. (ThreadClosed is the
+// status.)
+type ThreadClosedEvent struct {
+	EventID     string    `json:"event_id"`
+	ThreadID    string    `json:"thread_id"`
+	ChannelID   string    `json:"channel_id"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	ActorID     string    `json:"actor_id"`
+	Reason      string    `json:"reason,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type ThreadReopened struct {
+	EventID     string    `json:"event_id"`
+	ThreadID    string    `json:"thread_id"`
+	ChannelID   string    `json:"channel_id"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	ActorID     string    `json:"actor_id"`
+	Reason      string    `json:"reason,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type ThreadTransferInitiated struct {
+	EventID     string    `json:"event_id"`
+	ThreadID    string    `json:"thread_id"`
+	ChannelID   string    `json:"channel_id"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	ActorID     string    `json:"actor_id"`
+	ToVaultID   string    `json:"to_vault_id"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+type ThreadTransferCompleted struct {
+	EventID     string    `json:"event_id"`
+	ThreadID    string    `json:"thread_id"`
+	ChannelID   string    `json:"channel_id"`
+	WorkspaceID string    `json:"workspace_id,omitempty"`
+	ActorID     string    `json:"actor_id"`
+	ToVaultID   string    `json:"to_vault_id"`
+	Timestamp   time.Time `json:"timestamp"`
+}
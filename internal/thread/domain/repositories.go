@@ -2,6 +2,7 @@ package thread_domain
 
 import (
 	"context"
+	"time"
 
 	tracecore_types "vault-app/internal/tracecore/types"
 )
@@ -14,6 +15,9 @@ type CreateThreadRequest struct {
 
 type ListThreadsRequest struct {
 	ChannelID string
+	// UpdatedSince, when set, restricts the listing to threads changed after
+	// it.
+	UpdatedSince time.Time
 }
 type GetThreadRequest struct {
 	ThreadID string
@@ -24,6 +28,9 @@ type UpdateThreadRequest struct {
 
 type ListThreadEventsRequest struct {
 	ThreadID string
+	// AfterCursor, when set, restricts the listing to events with a greater
+	// cursor.
+	AfterCursor uint64
 }
 
 type AppendThreadEventRequest struct {
@@ -31,6 +38,12 @@ type AppendThreadEventRequest struct {
 	EventType      string
 	Payload        EventResourceRef
 	IdempotencyKey string
+	// Headers are recorded with the event (e.g. lifecycle actor and reason).
+	Headers map[string]string
+	// PreviousEventID and Signature are set on signed appends: the author
+	// signs the link to the head it appends after.
+	PreviousEventID *string
+	Signature       string
 }
 
 type ThreadRepository interface {
@@ -0,0 +1,35 @@
+package thread_devicekeys
+
+import (
+	"context"
+
+	identity_domain "vault-app/internal/identity/domain"
+	thread_domain "vault-app/internal/thread/domain"
+)
+
+// IdentityResolver resolves thread event author keys from the identity
+// device registry.
+type IdentityResolver struct {
+	devices identity_domain.DeviceRepository
+}
+
+func NewIdentityResolver(devices identity_domain.DeviceRepository) *IdentityResolver {
+	return &IdentityResolver{devices: devices}
+}
+
+func (r *IdentityResolver) DeviceKey(ctx context.Context, deviceID string) (*thread_domain.DeviceKey, error) {
+	d, err := r.devices.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &thread_domain.DeviceKey{
+		DeviceID:  d.ID,
+		PublicKey: d.PublicKey,
+		KeyType:   d.KeyType,
+		RevokedAt: d.RevokedAt,
+	}, nil
+}
+
+// Ensure interface satisfaction at compile-time
+var _ thread_domain.DeviceKeyResolver = (*IdentityResolver)(nil)
@@ -0,0 +1,56 @@
+package thread_devicekeys
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+
+	identity_domain "vault-app/internal/identity/domain"
+	thread_domain "vault-app/internal/thread/domain"
+)
+
+// DeviceSigner signs thread events with a local ed25519 device key on behalf
+// of the registered device holding that key.
+type DeviceSigner struct {
+	deviceID string
+	key      ed25519.PrivateKey
+}
+
+// NewDeviceSigner picks, among a vault's devices, the active ed25519 device
+// registered with key's public half.
+func NewDeviceSigner(devices []*identity_domain.Device, key ed25519.PrivateKey) (*DeviceSigner, error) {
+	pub, ok := key.Public().(ed25519.PublicKey)
+	if !ok || len(key) != ed25519.PrivateKeySize {
+		return nil, thread_domain.ErrAuthorDeviceNotRegistered
+	}
+	for _, d := range devices {
+		if d == nil || !d.IsActive() || d.KeyType != identity_domain.DeviceKeyTypeEd25519 {
+			continue
+		}
+		if registered, err := decodePublicKey(d.PublicKey); err == nil && bytes.Equal(registered, pub) {
+			return &DeviceSigner{deviceID: d.ID, key: key}, nil
+		}
+	}
+	return nil, thread_domain.ErrAuthorDeviceNotRegistered
+}
+
+func (s *DeviceSigner) DeviceID() string {
+	return s.deviceID
+}
+
+func (s *DeviceSigner) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, digest), nil
+}
+
+// decodePublicKey accepts the base64 or hex encodings device keys are
+// registered with.
+func decodePublicKey(s string) ([]byte, error) {
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return hex.DecodeString(s)
+}
+
+// Ensure interface satisfaction at compile-time
+var _ thread_domain.EventSigner = (*DeviceSigner)(nil)
@@ -8,15 +8,23 @@ import (
 )
 
 type MemoryBus struct {
-	threadCreatedSubscribers []func(ctx context.Context, event thread_domain.ThreadCreated)
-	threadUpdatedSubscribers []func(ctx context.Context, event thread_domain.ThreadUpdated)
-	lock        sync.RWMutex
+	threadCreatedSubscribers           []func(ctx context.Context, event thread_domain.ThreadCreated)
+	threadUpdatedSubscribers           []func(ctx context.Context, event thread_domain.ThreadUpdated)
+	threadClosedSubscribers            []func(ctx context.Context, event thread_domain.ThreadClosedEvent)
+	threadReopenedSubscribers          []func(ctx context.Context, event thread_domain.ThreadReopened)
+	threadTransferInitiatedSubscribers []func(ctx context.Context, event thread_domain.ThreadTransferInitiated)
+	threadTransferCompletedSubscribers []func(ctx context.Context, event thread_domain.ThreadTransferCompleted)
+	lock                               sync.RWMutex
 }
 
 func NewMemoryBus() *MemoryBus {
 	return &MemoryBus{
-		threadCreatedSubscribers: make([]func(ctx context.Context, event thread_domain.ThreadCreated), 0),
-		threadUpdatedSubscribers: make([]func(ctx context.Context, event thread_domain.ThreadUpdated), 0),
+		threadCreatedSubscribers:           make([]func(ctx context.Context, event thread_domain.ThreadCreated), 0),
+		threadUpdatedSubscribers:           make([]func(ctx context.Context, event thread_domain.ThreadUpdated), 0),
+		threadClosedSubscribers:            make([]func(ctx context.Context, event thread_domain.ThreadClosedEvent), 0),
+		threadReopenedSubscribers:          make([]func(ctx context.Context, event thread_domain.ThreadReopened), 0),
+		threadTransferInitiatedSubscribers: make([]func(ctx context.Context, event thread_domain.ThreadTransferInitiated), 0),
+		threadTransferCompletedSubscribers: make([]func(ctx context.Context, event thread_domain.ThreadTransferCompleted), 0),
 	}
 }
 
@@ -50,3 +58,63 @@ func (mb *MemoryBus) SubscribeToThreadUpdated(handler func(ctx context.Context,
 	mb.threadUpdatedSubscribers = append(mb.threadUpdatedSubscribers, handler)
 	return nil
 }
+
+func (mb *MemoryBus) PublishThreadClosed(ctx context.Context, event thread_domain.ThreadClosedEvent) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.threadClosedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToThreadClosed(handler func(ctx context.Context, event thread_domain.ThreadClosedEvent)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.threadClosedSubscribers = append(mb.threadClosedSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishThreadReopened(ctx context.Context, event thread_domain.ThreadReopened) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.threadReopenedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToThreadReopened(handler func(ctx context.Context, event thread_domain.ThreadReopened)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.threadReopenedSubscribers = append(mb.threadReopenedSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishThreadTransferInitiated(ctx context.Context, event thread_domain.ThreadTransferInitiated) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.threadTransferInitiatedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToThreadTransferInitiated(handler func(ctx context.Context, event thread_domain.ThreadTransferInitiated)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.threadTransferInitiatedSubscribers = append(mb.threadTransferInitiatedSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishThreadTransferCompleted(ctx context.Context, event thread_domain.ThreadTransferCompleted) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.threadTransferCompletedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToThreadTransferCompleted(handler func(ctx context.Context, event thread_domain.ThreadTransferCompleted)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.threadTransferCompletedSubscribers = append(mb.threadTransferCompletedSubscribers, handler)
+	return nil
+}
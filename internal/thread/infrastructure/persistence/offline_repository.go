@@ -0,0 +1,294 @@
+package thread_persistence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	shared_offline "vault-app/internal/shared/offline"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OpCreateThread      = "thread.create"
+	OpUpdateThread      = "thread.update"
+	OpAppendThreadEvent = "thread.append_event"
+
+	// MessageServedFromCache marks responses built from the local cache.
+	MessageServedFromCache = "served from offline cache"
+	// MessageQueuedOffline marks writes accepted locally and queued for
+	// replay.
+	MessageQueuedOffline = "queued offline"
+)
+
+// OfflineRepository is an offline-first ThreadRepository. Cloud stays
+// authoritative: every call goes to the cloud first and successful responses
+// refresh the local cache. When the cloud is unreachable, reads are served
+// from the cache and writes are queued for replay on reconnect.
+type OfflineRepository struct {
+	cloud thread_domain.ThreadRepository
+	cache *shared_offline.Cache
+}
+
+func NewOfflineRepository(cloud thread_domain.ThreadRepository, cache *shared_offline.Cache) *OfflineRepository {
+	r := &OfflineRepository{cloud: cloud, cache: cache}
+
+	cache.Queue.Register(OpCreateThread, func(ctx context.Context, payload json.RawMessage) error {
+		var req thread_domain.CreateThreadRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.CreateThread(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putThread(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpUpdateThread, func(ctx context.Context, payload json.RawMessage) error {
+		var req thread_domain.UpdateThreadRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.UpdateThread(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putThread(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpAppendThreadEvent, func(ctx context.Context, payload json.RawMessage) error {
+		var req thread_domain.AppendThreadEventRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.AppendThreadEvent(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return r.putEvent(ctx, resp.Data)
+	})
+
+	return r
+}
+
+func (r *OfflineRepository) putThread(ctx context.Context, t thread_domain.Thread) error {
+	return r.cache.UserStore().Put(ctx, shared_offline.KindThread, t.ChannelID, shared_offline.Item{ID: t.ID, Value: t})
+}
+
+func (r *OfflineRepository) putEvent(ctx context.Context, e thread_domain.ThreadEvent) error {
+	return r.cache.UserStore().Put(ctx, shared_offline.KindThreadEvent, e.ThreadID, shared_offline.Item{ID: e.ID, Cursor: e.Cursor, Value: e})
+}
+
+func (r *OfflineRepository) CreateThread(ctx context.Context, req *thread_domain.CreateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	resp, err := r.cloud.CreateThread(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		if _, err := r.cache.Queue.Enqueue(ctx, OpCreateThread, req); err != nil {
+			return nil, err
+		}
+		thread := req.Thread
+		thread.IsDirty = true
+		if err := r.putThread(ctx, thread); err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusAccepted, Data: thread, Message: MessageQueuedOffline}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putThread(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListThreads refreshes incrementally: only threads changed since the
+// listing's last refresh are requested and merged into the cache, and the
+// full cached listing is returned.
+func (r *OfflineRepository) ListThreads(ctx context.Context, req *thread_domain.ListThreadsRequest) (*tracecore_types.CloudResponse[[]thread_domain.Thread], error) {
+	scope := shared_offline.Scope(shared_offline.KindThread, req.ChannelID)
+	store := r.cache.UserStore()
+
+	state, err := store.SyncState(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	since := store.Since(*state)
+
+	resp, err := r.cloud.ListThreads(ctx, &thread_domain.ListThreadsRequest{
+		ChannelID:    req.ChannelID,
+		UpdatedSince: since,
+	})
+	offline := r.cache.Connectivity.Observe(ctx, err)
+	if offline {
+		_ = store.MarkFailed(ctx, scope, err)
+	} else if err != nil {
+		return nil, err
+	} else {
+		items := make([]shared_offline.Item, 0, len(resp.Data))
+		for _, t := range resp.Data {
+			items = append(items, shared_offline.Item{ID: t.ID, Value: t})
+		}
+		if err := store.MergeSynced(ctx, shared_offline.KindThread, req.ChannelID, scope, since, items); err != nil {
+			return nil, err
+		}
+	}
+
+	recs, err := store.List(ctx, shared_offline.KindThread, req.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := shared_offline.Decode[thread_domain.Thread](recs)
+	if err != nil {
+		return nil, err
+	}
+	if offline {
+		return &tracecore_types.CloudResponse[[]thread_domain.Thread]{Status: http.StatusOK, Data: threads, Message: MessageServedFromCache}, nil
+	}
+	resp.Data = threads
+	return resp, nil
+}
+
+func (r *OfflineRepository) GetThread(ctx context.Context, req *thread_domain.GetThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	resp, err := r.cloud.GetThread(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		var thread thread_domain.Thread
+		if err := r.cache.UserStore().Get(ctx, shared_offline.KindThread, req.ThreadID, &thread); err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusOK, Data: thread, Message: MessageServedFromCache}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putThread(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	resp, err := r.cloud.UpdateThread(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		if _, err := r.cache.Queue.Enqueue(ctx, OpUpdateThread, req); err != nil {
+			return nil, err
+		}
+		thread := req.Thread
+		thread.IsDirty = true
+		if err := r.putThread(ctx, thread); err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusAccepted, Data: thread, Message: MessageQueuedOffline}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putThread(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListThreadEvents refreshes incrementally: only events after the last
+// synced cursor are requested, merged into the cache, and the full cached
+// log is returned in cursor order.
+func (r *OfflineRepository) ListThreadEvents(ctx context.Context, req *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
+	scope := shared_offline.Scope(shared_offline.KindThreadEvent, req.ThreadID)
+	store := r.cache.UserStore()
+
+	state, err := store.SyncState(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.cloud.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{
+		ThreadID:    req.ThreadID,
+		AfterCursor: state.Cursor,
+	})
+	offline := r.cache.Connectivity.Observe(ctx, err)
+	if offline {
+		_ = store.MarkFailed(ctx, scope, err)
+	} else if err != nil {
+		return nil, err
+	} else {
+		cursor := state.Cursor
+		items := make([]shared_offline.Item, 0, len(resp.Data))
+		for _, e := range resp.Data {
+			items = append(items, shared_offline.Item{ID: e.ID, Cursor: e.Cursor, Value: e})
+			if e.Cursor > cursor {
+				cursor = e.Cursor
+			}
+		}
+		if err := store.Put(ctx, shared_offline.KindThreadEvent, req.ThreadID, items...); err != nil {
+			return nil, err
+		}
+		if err := store.MarkSynced(ctx, scope, cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	recs, err := store.List(ctx, shared_offline.KindThreadEvent, req.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := shared_offline.Decode[thread_domain.ThreadEvent](recs)
+	if err != nil {
+		return nil, err
+	}
+	filtered := events[:0]
+	for _, e := range events {
+		if e.Cursor > req.AfterCursor {
+			filtered = append(filtered, e)
+		}
+	}
+
+	message := "ok"
+	if offline {
+		message = MessageServedFromCache
+	}
+	return &tracecore_types.CloudResponse[[]thread_domain.ThreadEvent]{Status: http.StatusOK, Data: filtered, Message: message}, nil
+}
+
+// AppendThreadEvent queues the append when offline. The idempotency key
+// makes the replay safe if the original request reached the cloud.
+func (r *OfflineRepository) AppendThreadEvent(ctx context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = uuid.NewString()
+	}
+
+	resp, err := r.cloud.AppendThreadEvent(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		if _, err := r.cache.Queue.Enqueue(ctx, OpAppendThreadEvent, req); err != nil {
+			return nil, err
+		}
+		return &tracecore_types.CloudResponse[thread_domain.ThreadEvent]{
+			Status: http.StatusAccepted,
+			Data: thread_domain.ThreadEvent{
+				ID:              req.IdempotencyKey,
+				ThreadID:        req.ThreadID,
+				PreviousEventID: req.PreviousEventID,
+				Type:            thread_domain.ThreadEventType(req.EventType),
+				Payload:         req.Payload,
+				IdempotencyKey:  req.IdempotencyKey,
+				Headers:         req.Headers,
+				Signature:       req.Signature,
+			},
+			Message: MessageQueuedOffline,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.putEvent(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Ensure interface satisfaction at compile-time
+var _ thread_domain.ThreadRepository = (*OfflineRepository)(nil)
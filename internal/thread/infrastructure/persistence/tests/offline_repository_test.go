@@ -0,0 +1,246 @@
+package thread_persistence_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	shared_offline "vault-app/internal/shared/offline"
+	thread_domain "vault-app/internal/thread/domain"
+	thread_persistence "vault-app/internal/thread/infrastructure/persistence"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+var errUnreachable = &url.Error{Op: "Get", URL: "http://cloud", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+
+// stubCloud is an in-memory cloud that can be switched offline.
+type stubCloud struct {
+	offline     bool
+	threads     map[string]thread_domain.Thread
+	events      []thread_domain.ThreadEvent
+	afterCursor []uint64
+	since       []time.Time
+	appended    []thread_domain.AppendThreadEventRequest
+}
+
+var _ thread_domain.ThreadRepository = (*stubCloud)(nil)
+
+func newStubCloud() *stubCloud {
+	return &stubCloud{threads: map[string]thread_domain.Thread{}}
+}
+
+func (s *stubCloud) CreateThread(ctx context.Context, req *thread_domain.CreateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	t := req.Thread
+	t.IsDraft = false
+	s.threads[t.ID] = t
+	return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusCreated, Data: t}, nil
+}
+
+func (s *stubCloud) ListThreads(ctx context.Context, req *thread_domain.ListThreadsRequest) (*tracecore_types.CloudResponse[[]thread_domain.Thread], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	s.since = append(s.since, req.UpdatedSince)
+	out := []thread_domain.Thread{}
+	for _, t := range s.threads {
+		if t.ChannelID == req.ChannelID {
+			out = append(out, t)
+		}
+	}
+	return &tracecore_types.CloudResponse[[]thread_domain.Thread]{Status: http.StatusOK, Data: out}, nil
+}
+
+func (s *stubCloud) GetThread(ctx context.Context, req *thread_domain.GetThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusOK, Data: s.threads[req.ThreadID]}, nil
+}
+
+func (s *stubCloud) UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	s.threads[req.Thread.ID] = req.Thread
+	return &tracecore_types.CloudResponse[thread_domain.Thread]{Status: http.StatusOK, Data: req.Thread}, nil
+}
+
+func (s *stubCloud) ListThreadEvents(ctx context.Context, req *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	s.afterCursor = append(s.afterCursor, req.AfterCursor)
+	out := []thread_domain.ThreadEvent{}
+	for _, e := range s.events {
+		if e.ThreadID == req.ThreadID && e.Cursor > req.AfterCursor {
+			out = append(out, e)
+		}
+	}
+	return &tracecore_types.CloudResponse[[]thread_domain.ThreadEvent]{Status: http.StatusOK, Data: out}, nil
+}
+
+func (s *stubCloud) AppendThreadEvent(ctx context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
+	if s.offline {
+		return nil, errUnreachable
+	}
+	s.appended = append(s.appended, *req)
+	e := thread_domain.ThreadEvent{
+		ID:             req.IdempotencyKey,
+		ThreadID:       req.ThreadID,
+		Type:           thread_domain.ThreadEventType(req.EventType),
+		Cursor:         uint64(len(s.events) + 1),
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	s.events = append(s.events, e)
+	return &tracecore_types.CloudResponse[thread_domain.ThreadEvent]{Status: http.StatusCreated, Data: e}, nil
+}
+
+func newRepo(t *testing.T) (*thread_persistence.OfflineRepository, *stubCloud, *shared_offline.Cache) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	require.NoError(t, err)
+	cache, err := shared_offline.NewCache(db)
+	require.NoError(t, err)
+	cache.Queue.SetUser("user-1")
+	cloud := newStubCloud()
+	return thread_persistence.NewOfflineRepository(cloud, cache), cloud, cache
+}
+
+func TestOfflineRepository_ListThreads_FallsBackToCache(t *testing.T) {
+	ctx := context.Background()
+	repo, cloud, cache := newRepo(t)
+
+	thread := thread_domain.NewThread("ch-1", "invoice", "Invoice", "")
+	_, err := repo.CreateThread(ctx, &thread_domain.CreateThreadRequest{Thread: thread})
+	require.NoError(t, err)
+
+	online, err := repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+	require.Len(t, online.Data, 1)
+
+	cloud.offline = true
+	offline, err := repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+	assert.Equal(t, thread_persistence.MessageServedFromCache, offline.Message)
+	require.Len(t, offline.Data, 1)
+	assert.Equal(t, thread.ID, offline.Data[0].ID)
+
+	st, err := cache.ScopeStaleness(ctx, "user-1", shared_offline.KindThread, "ch-1")
+	require.NoError(t, err)
+	assert.False(t, st.Online)
+	assert.True(t, st.Stale)
+	assert.NotEmpty(t, st.LastError)
+}
+
+func TestOfflineRepository_ServesOnlyTheSessionUsersCache(t *testing.T) {
+	ctx := context.Background()
+	repo, cloud, cache := newRepo(t)
+
+	thread := thread_domain.NewThread("ch-1", "invoice", "Invoice", "")
+	_, err := repo.CreateThread(ctx, &thread_domain.CreateThreadRequest{Thread: thread})
+	require.NoError(t, err)
+	_, err = repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+
+	cache.Queue.SetUser("user-2")
+	cloud.offline = true
+	listed, err := repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+	assert.Empty(t, listed.Data, "the previous user's threads are not served")
+	_, err = repo.GetThread(ctx, &thread_domain.GetThreadRequest{ThreadID: thread.ID})
+	assert.ErrorIs(t, err, shared_offline.ErrNotCached)
+
+	cache.Queue.SetUser("user-1")
+	listed, err = repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+	assert.Len(t, listed.Data, 1)
+}
+
+func TestOfflineRepository_ListThreads_MergesDeltas(t *testing.T) {
+	ctx := context.Background()
+	repo, cloud, _ := newRepo(t)
+
+	first := thread_domain.NewThread("ch-1", "invoice", "Invoice", "")
+	cloud.threads[first.ID] = first
+	listed, err := repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+	require.Len(t, listed.Data, 1)
+
+	// The cloud answers the delta with the changed thread only.
+	second := thread_domain.NewThread("ch-1", "invoice", "Receipt", "")
+	cloud.threads = map[string]thread_domain.Thread{second.ID: second}
+	listed, err = repo.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: "ch-1"})
+	require.NoError(t, err)
+
+	require.Len(t, cloud.since, 2)
+	assert.True(t, cloud.since[0].IsZero(), "first refresh is full")
+	assert.False(t, cloud.since[1].IsZero(), "second refresh asks for changes only")
+	assert.Len(t, listed.Data, 2, "the delta is merged into the cached listing")
+}
+
+func TestOfflineRepository_ListThreadEvents_IncrementalByCursor(t *testing.T) {
+	ctx := context.Background()
+	repo, cloud, _ := newRepo(t)
+
+	cloud.events = []thread_domain.ThreadEvent{
+		{ID: "e1", ThreadID: "th-1", Cursor: 1},
+		{ID: "e2", ThreadID: "th-1", Cursor: 2},
+	}
+	first, err := repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{ThreadID: "th-1"})
+	require.NoError(t, err)
+	require.Len(t, first.Data, 2)
+
+	cloud.events = append(cloud.events, thread_domain.ThreadEvent{ID: "e3", ThreadID: "th-1", Cursor: 3})
+	second, err := repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{ThreadID: "th-1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint64{0, 2}, cloud.afterCursor, "second refresh only asks for new events")
+	require.Len(t, second.Data, 3)
+	assert.Equal(t, "e3", second.Data[2].ID)
+
+	cloud.offline = true
+	cached, err := repo.ListThreadEvents(ctx, &thread_domain.ListThreadEventsRequest{ThreadID: "th-1", AfterCursor: 1})
+	require.NoError(t, err)
+	assert.Len(t, cached.Data, 2)
+}
+
+func TestOfflineRepository_QueuesWritesAndReplaysOnReconnect(t *testing.T) {
+	ctx := context.Background()
+	repo, cloud, cache := newRepo(t)
+	cloud.offline = true
+
+	resp, err := repo.AppendThreadEvent(ctx, &thread_domain.AppendThreadEventRequest{
+		ThreadID:  "th-1",
+		EventType: string(thread_domain.EventEntryShared),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.Status)
+	assert.NotEmpty(t, resp.Data.IdempotencyKey)
+
+	pending, err := cache.Queue.Pending(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	cloud.offline = false
+	res, err := cache.Queue.Replay(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Replayed)
+	require.Len(t, cloud.appended, 1)
+	assert.Equal(t, resp.Data.IdempotencyKey, cloud.appended[0].IdempotencyKey)
+
+	pending, err = cache.Queue.Pending(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
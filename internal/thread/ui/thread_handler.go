@@ -12,10 +12,16 @@ import (
 )
 
 type ThreadHandler struct {
-	createUseCase      *thread_usecase.CreateThreadUsecase
-	listUseCase        *thread_usecase.ListThreadsUsecase
-	listEventsUseCase  *thread_usecase.ListThreadEventsUsecase
-	appendEventUseCase *thread_usecase.AppendThreadEventUsecase
+	createUseCase       *thread_usecase.CreateThreadUsecase
+	listUseCase         *thread_usecase.ListThreadsUsecase
+	listEventsUseCase   *thread_usecase.ListThreadEventsUsecase
+	appendEventUseCase  *thread_usecase.AppendThreadEventUsecase
+	verifyEventsUseCase *thread_usecase.VerifyThreadEventsUsecase
+
+	closeUseCase            *thread_usecase.CloseThreadUsecase
+	reopenUseCase           *thread_usecase.ReopenThreadUsecase
+	initiateTransferUseCase *thread_usecase.InitiateThreadTransferUsecase
+	completeTransferUseCase *thread_usecase.CompleteThreadTransferUsecase
 }
 
 func NewThreadHandler(
@@ -60,9 +66,12 @@ func (h *ThreadHandler) CreateThread(
 	return toTracecoreThreadDTO(th), nil
 }
 
+// ListThreads lists the threads of a channel as actorVaultID, the vault of
+// the signed-in user.
 func (h *ThreadHandler) ListThreads(
 	ctx context.Context,
 	userID string,
+	actorVaultID string,
 	channelID string,
 ) ([]tracecore_types.ThreadDTO, error) {
 	log.Printf("[THREAD LIST HANDLER] userID=%s channelID=%s", userID, channelID)
@@ -70,7 +79,7 @@ func (h *ThreadHandler) ListThreads(
 		return nil, fmt.Errorf("list thread use case is not initialized")
 	}
 
-	threads, err := h.listUseCase.Execute(ctx, channelID)
+	threads, err := h.listUseCase.ExecuteAs(ctx, actorVaultID, channelID)
 	if err != nil {
 		return nil, err
 	}
@@ -106,10 +115,136 @@ func (h *ThreadHandler) ListThreadEvents(
 	return res, nil
 }
 
-func (h *ThreadHandler) AppendThreadEvent(
+func (h *ThreadHandler) SetLifecycleUseCases(
+	closeUC *thread_usecase.CloseThreadUsecase,
+	reopenUC *thread_usecase.ReopenThreadUsecase,
+	initiateTransferUC *thread_usecase.InitiateThreadTransferUsecase,
+	completeTransferUC *thread_usecase.CompleteThreadTransferUsecase,
+) {
+	h.closeUseCase = closeUC
+	h.reopenUseCase = reopenUC
+	h.initiateTransferUseCase = initiateTransferUC
+	h.completeTransferUseCase = completeTransferUC
+}
+
+func (h *ThreadHandler) CloseThread(
+	ctx context.Context,
+	userID string,
+	threadID string,
+	reason string,
+) (*tracecore_types.ThreadDTO, error) {
+	if h.closeUseCase == nil {
+		return nil, fmt.Errorf("close thread use case is not initialized")
+	}
+
+	th, err := h.closeUseCase.Execute(ctx, thread_dtos.CloseThreadRequest{
+		ThreadID: threadID,
+		ActorID:  userID,
+		Reason:   reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreThreadDTO(th), nil
+}
+
+func (h *ThreadHandler) ReopenThread(
+	ctx context.Context,
+	userID string,
+	threadID string,
+	reason string,
+) (*tracecore_types.ThreadDTO, error) {
+	if h.reopenUseCase == nil {
+		return nil, fmt.Errorf("reopen thread use case is not initialized")
+	}
+
+	th, err := h.reopenUseCase.Execute(ctx, thread_dtos.ReopenThreadRequest{
+		ThreadID: threadID,
+		ActorID:  userID,
+		Reason:   reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreThreadDTO(th), nil
+}
+
+func (h *ThreadHandler) InitiateThreadTransfer(
+	ctx context.Context,
+	userID string,
+	threadID string,
+	toVaultID string,
+) (*tracecore_types.ThreadDTO, error) {
+	if h.initiateTransferUseCase == nil {
+		return nil, fmt.Errorf("initiate thread transfer use case is not initialized")
+	}
+
+	th, err := h.initiateTransferUseCase.Execute(ctx, thread_dtos.InitiateThreadTransferRequest{
+		ThreadID:  threadID,
+		ActorID:   userID,
+		ToVaultID: toVaultID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreThreadDTO(th), nil
+}
+
+func (h *ThreadHandler) CompleteThreadTransfer(
+	ctx context.Context,
+	userID string,
+	threadID string,
+) (*tracecore_types.ThreadDTO, error) {
+	if h.completeTransferUseCase == nil {
+		return nil, fmt.Errorf("complete thread transfer use case is not initialized")
+	}
+
+	th, err := h.completeTransferUseCase.Execute(ctx, thread_dtos.CompleteThreadTransferRequest{
+		ThreadID: threadID,
+		ActorID:  userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreThreadDTO(th), nil
+}
+
+// SetEventSigner sets the device that signs appended events for the open
+// vault session; nil stops signing.
+func (h *ThreadHandler) SetEventSigner(signer thread_domain.EventSigner) {
+	if h.appendEventUseCase != nil {
+		h.appendEventUseCase.SetSigner(signer)
+	}
+}
+
+func (h *ThreadHandler) SetVerifyEventsUseCase(uc *thread_usecase.VerifyThreadEventsUsecase) {
+	h.verifyEventsUseCase = uc
+}
+
+// VerifyThreadEvents reports gaps, forks, reordering and bad signatures in a
+// thread log.
+func (h *ThreadHandler) VerifyThreadEvents(
 	ctx context.Context,
 	userID string,
 	threadID string,
+) (*thread_domain.ChainReport, error) {
+	if h.verifyEventsUseCase == nil {
+		return nil, fmt.Errorf("verify thread events use case is not initialized")
+	}
+
+	return h.verifyEventsUseCase.Execute(ctx, threadID)
+}
+
+// AppendThreadEvent appends as actorVaultID, the vault of the signed-in
+// session; channel policy is evaluated against that vault.
+func (h *ThreadHandler) AppendThreadEvent(
+	ctx context.Context,
+	actorVaultID string,
+	threadID string,
 	eventType string,
 	payload thread_domain.EventResourceRef,
 ) (*tracecore_types.ThreadEventDTO, error) {
@@ -117,7 +252,7 @@ func (h *ThreadHandler) AppendThreadEvent(
 		return nil, fmt.Errorf("append thread event use case is not initialized")
 	}
 
-	evt, err := h.appendEventUseCase.Execute(ctx, threadID, eventType, payload)
+	evt, err := h.appendEventUseCase.ExecuteAs(ctx, actorVaultID, threadID, eventType, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +273,8 @@ func toTracecoreThreadDTO(th *thread_domain.Thread) *tracecore_types.ThreadDTO {
 		Subtitle:    th.Subtitle,
 		Status:      string(th.Status),
 		CreatedAt:   th.CreatedAt,
+		ClosedAt:    th.ClosedAt,
+		TransferTo:  th.TransferTo,
 	}
 }
 
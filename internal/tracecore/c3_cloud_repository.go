@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 
 	c3_asset_domain "vault-app/internal/c3_asset/domain"
 	tracecore_types "vault-app/internal/tracecore/types"
@@ -80,9 +81,36 @@ func (c *TracecoreClient) ListTrustGroups(ctx context.Context, req *trustgroup_d
 	return nil, fmt.Errorf("ListTrustGroups is not supported by Cloud yet")
 }
 
-// UpdateTrustGroup has no Cloud endpoint yet.
+// ListTrustGroupsForMember returns every trust group the vault belongs to
+// (GET /api/trustgroups?member_id={id}).
+func (c *TracecoreClient) ListTrustGroupsForMember(ctx context.Context, memberID string) ([]trustgroup_domain.TrustGroup, error) {
+	if memberID == "" {
+		return nil, fmt.Errorf("member id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[[]trustgroup_domain.TrustGroup]
+	if err := c.c3Request(ctx, http.MethodGet, "/trustgroups?member_id="+url.QueryEscape(memberID), nil, &cloudResp); err != nil {
+		return nil, fmt.Errorf("failed to list trust groups: %w", err)
+	}
+	if cloudResp.Data == nil {
+		return []trustgroup_domain.TrustGroup{}, nil
+	}
+	return cloudResp.Data, nil
+}
+
+// UpdateTrustGroup persists the whole trust group aggregate, envelopes and
+// KEK version included (PUT /api/trustgroups/{id}).
 func (c *TracecoreClient) UpdateTrustGroup(ctx context.Context, req *trustgroup_domain.UpdateTrustGroupRequest) (*tracecore_types.CloudResponse[trustgroup_domain.TrustGroup], error) {
-	return nil, fmt.Errorf("UpdateTrustGroup is not supported by Cloud yet")
+	if req == nil || req.TrustGroup.ID == "" {
+		return nil, fmt.Errorf("trust group id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[trustgroup_domain.TrustGroup]
+	if err := c.c3Request(ctx, http.MethodPut, "/trustgroups/"+url.PathEscape(req.TrustGroup.ID), req.TrustGroup, &cloudResp); err != nil {
+		return nil, fmt.Errorf("failed to update trust group: %w", err)
+	}
+	if cloudResp.Data.ID == "" {
+		return nil, fmt.Errorf("Cloud returned a trust group without an ID")
+	}
+	return &cloudResp, nil
 }
 
 // DeleteTrustGroup has no Cloud endpoint yet.
@@ -226,12 +254,82 @@ func (r *CloudShareEntryRepository) GetShareEntry(ctx context.Context, req *c3_a
 	return r.client.GetShareEntryDirect(ctx, req.ShareEntryID)
 }
 
-// UpdateShareEntry has no Cloud endpoint yet.
+// UpdateShareEntry persists a re-wrapped DEK and its KEK version
+// (PUT /api/c3/share-entries/{id}).
 func (r *CloudShareEntryRepository) UpdateShareEntry(ctx context.Context, req *c3_asset_domain.UpdateShareEntryRequest) (*tracecore_types.CloudResponse[c3_asset_domain.ShareEntry], error) {
-	return nil, fmt.Errorf("UpdateShareEntry is not supported by Cloud yet")
+	if req == nil || req.ShareEntry.ID == "" {
+		return nil, fmt.Errorf("share entry id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[c3_asset_domain.ShareEntry]
+	if err := r.client.c3Request(ctx, http.MethodPut, "/c3/share-entries/"+url.PathEscape(req.ShareEntry.ID), req.ShareEntry, &cloudResp); err != nil {
+		return nil, fmt.Errorf("failed to update share entry: %w", err)
+	}
+	if cloudResp.Data.ID == "" {
+		return nil, fmt.Errorf("Cloud returned a share entry without an ID")
+	}
+	return &cloudResp, nil
+}
+
+// ListShareEntriesByTrustGroup returns every share entry granted to a trust
+// group (GET /api/c3/share-entries?trust_group_id={id}).
+func (r *CloudShareEntryRepository) ListShareEntriesByTrustGroup(ctx context.Context, trustGroupID string) ([]c3_asset_domain.ShareEntry, error) {
+	if trustGroupID == "" {
+		return nil, fmt.Errorf("trust group id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[[]c3_asset_domain.ShareEntry]
+	if err := r.client.c3Request(ctx, http.MethodGet, "/c3/share-entries?trust_group_id="+url.QueryEscape(trustGroupID), nil, &cloudResp); err != nil {
+		return nil, fmt.Errorf("failed to list share entries: %w", err)
+	}
+	if cloudResp.Data == nil {
+		return []c3_asset_domain.ShareEntry{}, nil
+	}
+	return cloudResp.Data, nil
 }
 
 // DeleteShareEntry has no Cloud endpoint yet.
 func (r *CloudShareEntryRepository) DeleteShareEntry(ctx context.Context, req *c3_asset_domain.DeleteShareEntryRequest) (*tracecore_types.CloudResponse[c3_asset_domain.ShareEntry], error) {
 	return nil, fmt.Errorf("DeleteShareEntry is not supported by Cloud yet")
 }
+
+// c3Request sends body as JSON to Ankhora Cloud and decodes the response
+// envelope into out.
+func (c *TracecoreClient) c3Request(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.AnkhoraCloudUrl+path, reader)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return &CloudStatusError{StatusCode: resp.StatusCode, Body: string(respBytes)}
+	}
+	if out == nil || len(respBytes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBytes, out); err != nil {
+		return fmt.Errorf("failed to decode Cloud response: %w", err)
+	}
+	return nil
+}
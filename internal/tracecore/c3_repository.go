@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"time"
 
 
@@ -16,32 +18,49 @@ import (
 // WorkspaceRepository Implementation on TracecoreClient
 
 func (c *TracecoreClient) UpdateWorkspace(ctx context.Context, req workspace_domain.UpdateRequest) (*tracecore_types.CloudResponse[workspace_domain.Workspace], error) {
-	ws := req.Workspace
-	ws.UpdatedAt = time.Now()
+	if req.Workspace.ID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.CloudWorkspaceDTO]
+	path := "/workspaces/" + url.PathEscape(req.Workspace.ID)
+	if err := c.c3Request(ctx, http.MethodPut, path, toCloudWorkspaceDTO(req.Workspace), &cloudResp); err != nil {
+		utils.LogPretty("🚫 [Workspace] Repository.UpdateWorkspace error", err)
+		return nil, err
+	}
 	return &tracecore_types.CloudResponse[workspace_domain.Workspace]{
-		Status:  200,
-		Data:    ws,
-		Message: "success",
-		Success: true,
+		Status:  cloudResp.Status,
+		Data:    toDomainWorkspace(*tracecore_types.MapCloudWorkspaceToTypes(&cloudResp.Data)),
+		Message: cloudResp.Message,
+		Success: cloudResp.Success,
 	}, nil
 }
 
 func (c *TracecoreClient) DeleteWorkspace(ctx context.Context, req workspace_domain.DeleteRequest) error {
-	return nil
+	if req.WorkspaceID == "" {
+		return fmt.Errorf("workspace id is required")
+	}
+	return c.c3Request(ctx, http.MethodDelete, "/workspaces/"+url.PathEscape(req.WorkspaceID), nil, nil)
 }
 
 func (c *TracecoreClient) GetWorkspace(ctx context.Context, req workspace_domain.GetRequest) (*workspace_domain.Workspace, error) {
-	now := time.Now()
-	ws := workspace_domain.NewWorkspace("v1", "Default Workspace", "Default workspace", "u1")
-	ws.ID = req.WorkspaceID
-	ws.CreatedAt = now
-	ws.UpdatedAt = now
+	if req.WorkspaceID == "" {
+		return nil, fmt.Errorf("workspace id is required")
+	}
+	var cloudResp tracecore_types.CloudResponse[*tracecore_types.CloudWorkspaceDTO]
+	if err := c.c3Request(ctx, http.MethodGet, "/workspaces/"+url.PathEscape(req.WorkspaceID), nil, &cloudResp); err != nil {
+		utils.LogPretty("🚫 [Workspace] Repository.GetWorkspace error", err)
+		return nil, err
+	}
+	if cloudResp.Data == nil {
+		return nil, fmt.Errorf("workspace %s not found", req.WorkspaceID)
+	}
+	ws := toDomainWorkspace(*tracecore_types.MapCloudWorkspaceToTypes(cloudResp.Data))
 	return &ws, nil
 }
 
 func (c *TracecoreClient) ListWorkspace(ctx context.Context, req workspace_domain.ListRequest) ([]workspace_domain.Workspace, error) {
 	utils.LogPretty("[Workspace] Repository.ListWorkspace vault_id", req.VaultID)
-	cloudWorkspaces, err := c.ListWorkspaces(ctx, req.VaultID)
+	cloudWorkspaces, err := c.ListWorkspaces(ctx, req.VaultID, req.UpdatedSince)
 	if err != nil {
 		utils.LogPretty("🚫 [Workspace] Repository.ListWorkspace error", err)
 		return nil, err
@@ -49,31 +68,71 @@ func (c *TracecoreClient) ListWorkspace(ctx context.Context, req workspace_domai
 
 	result := make([]workspace_domain.Workspace, 0, len(cloudWorkspaces))
 	for _, ws := range cloudWorkspaces {
-		createdAt := ws.CreatedAt
-		updatedAt := ws.UpdatedAt
-		if createdAt.IsZero() {
-			createdAt = time.Now()
+		if ws.CreatedAt.IsZero() {
+			ws.CreatedAt = time.Now()
 		}
-		if updatedAt.IsZero() {
-			updatedAt = time.Now()
+		if ws.UpdatedAt.IsZero() {
+			ws.UpdatedAt = time.Now()
 		}
-		result = append(result, workspace_domain.Workspace{
-			ID:          ws.ID,
-			VaultID:     ws.VaultID,
-			Name:        ws.Name,
-			Description: ws.Description,
-			Status:      workspace_domain.WorkspaceStatus(ws.Status),
-			OwnerID:     ws.OwnerID,
-			CreatedAt:   createdAt,
-			UpdatedAt:   updatedAt,
-			IsDraft:     ws.IsDraft,
-			IsDirty:     ws.IsDirty,
-		})
+		result = append(result, toDomainWorkspace(ws))
 	}
 	utils.LogPretty("[Workspace] Repository.ListWorkspace result", result)
 	return result, nil
 }
 
+func toDomainWorkspace(ws tracecore_types.Workspace) workspace_domain.Workspace {
+	var members []workspace_domain.WorkspaceMember
+	for _, m := range ws.Members {
+		members = append(members, workspace_domain.WorkspaceMember{
+			VaultID: m.VaultID,
+			Role:    workspace_domain.WorkspaceRole(m.Role),
+			AddedBy: m.AddedBy,
+			AddedAt: m.AddedAt,
+		})
+	}
+	return workspace_domain.Workspace{
+		ID:          ws.ID,
+		VaultID:     ws.VaultID,
+		Name:        ws.Name,
+		Description: ws.Description,
+		Status:      workspace_domain.WorkspaceStatus(ws.Status),
+		OwnerID:     ws.OwnerID,
+		Members:     members,
+		CreatedAt:   ws.CreatedAt,
+		UpdatedAt:   ws.UpdatedAt,
+		ArchivedAt:  ws.ArchivedAt,
+		IsDraft:     ws.IsDraft,
+		IsDirty:     ws.IsDirty,
+	}
+}
+
+// toCloudWorkspaceDTO encodes ws in the Cloud's PascalCase layout.
+func toCloudWorkspaceDTO(ws workspace_domain.Workspace) tracecore_types.CloudWorkspaceDTO {
+	members := make([]tracecore_types.CloudWorkspaceMemberDTO, 0, len(ws.Members))
+	for _, m := range ws.Members {
+		members = append(members, tracecore_types.CloudWorkspaceMemberDTO{
+			VaultID: m.VaultID,
+			Role:    string(m.Role),
+			AddedBy: m.AddedBy,
+			AddedAt: m.AddedAt,
+		})
+	}
+	return tracecore_types.CloudWorkspaceDTO{
+		ID:          ws.ID,
+		VaultID:     ws.VaultID,
+		Name:        ws.Name,
+		Description: ws.Description,
+		Status:      string(ws.Status),
+		OwnerID:     ws.OwnerID,
+		CreatedAt:   ws.CreatedAt,
+		UpdatedAt:   ws.UpdatedAt,
+		IsDraft:     ws.IsDraft,
+		IsDirty:     ws.IsDirty,
+		ArchivedAt:  ws.ArchivedAt,
+		Members:     members,
+	}
+}
+
 // ThreadRepository Implementation on TracecoreClient
 
 func (c *TracecoreClient) CreateThread(ctx context.Context, req *thread_domain.CreateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
@@ -109,7 +168,7 @@ func (c *TracecoreClient) CreateThread(ctx context.Context, req *thread_domain.C
 
 func (c *TracecoreClient) ListThreads(ctx context.Context, req *thread_domain.ListThreadsRequest) (*tracecore_types.CloudResponse[[]thread_domain.Thread], error) {
 	log.Printf("[THREAD LIST REPO/TRACECORE] channelID=%s", req.ChannelID)
-	dtos, err := c.ListThreadsDirect(ctx, "me", req.ChannelID)
+	dtos, err := c.ListThreadsDirect(ctx, "me", req.ChannelID, req.UpdatedSince)
 	if err != nil {
 		return nil, fmt.Errorf("cloud list threads failed: %w", err)
 	}
@@ -121,13 +180,15 @@ func (c *TracecoreClient) ListThreads(ctx context.Context, req *thread_domain.Li
 			createdAt = time.Now()
 		}
 		result = append(result, thread_domain.Thread{
-			ID:        dto.ID,
-			ChannelID: dto.ChannelID,
-			AssetType: dto.AssetType,
-			Title:     dto.Title,
-			Subtitle:  dto.Subtitle,
-			Status:    thread_domain.ThreadStatus(dto.Status),
-			CreatedAt: createdAt,
+			ID:         dto.ID,
+			ChannelID:  dto.ChannelID,
+			AssetType:  dto.AssetType,
+			Title:      dto.Title,
+			Subtitle:   dto.Subtitle,
+			Status:     thread_domain.ThreadStatus(dto.Status),
+			CreatedAt:  createdAt,
+			ClosedAt:   dto.ClosedAt,
+			TransferTo: dto.TransferTo,
 		})
 	}
 
@@ -160,16 +221,35 @@ func (c *TracecoreClient) GetThread(ctx context.Context, req *thread_domain.GetT
 }
 
 func (c *TracecoreClient) UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	th := req.Thread
+	dto, err := c.UpdateThreadDirect(ctx, tracecore_types.ThreadDTO{
+		ID:         th.ID,
+		Title:      th.Title,
+		Subtitle:   th.Subtitle,
+		Status:     string(th.Status),
+		ClosedAt:   th.ClosedAt,
+		TransferTo: th.TransferTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloud update thread failed: %w", err)
+	}
+
+	// The Cloud echoes the lifecycle fields; identity fields are kept from
+	// the request when omitted.
+	th.Status = thread_domain.ThreadStatus(dto.Status)
+	th.ClosedAt = dto.ClosedAt
+	th.TransferTo = dto.TransferTo
+
 	return &tracecore_types.CloudResponse[thread_domain.Thread]{
 		Status:  200,
-		Data:    req.Thread,
+		Data:    th,
 		Message: "success",
 		Success: true,
 	}, nil
 }
 
 func (c *TracecoreClient) ListThreadEvents(ctx context.Context, req *thread_domain.ListThreadEventsRequest) (*tracecore_types.CloudResponse[[]thread_domain.ThreadEvent], error) {
-	dtos, err := c.ListThreadEventsDirect(ctx, "me", req.ThreadID)
+	dtos, err := c.ListThreadEventsDirect(ctx, "me", req.ThreadID, req.AfterCursor)
 	if err != nil {
 		return nil, fmt.Errorf("cloud list thread events failed: %w", err)
 	}
@@ -188,9 +268,26 @@ func (c *TracecoreClient) ListThreadEvents(ctx context.Context, req *thread_doma
 }
 
 func (c *TracecoreClient) AppendThreadEvent(ctx context.Context, req *thread_domain.AppendThreadEventRequest) (*tracecore_types.CloudResponse[thread_domain.ThreadEvent], error) {
-	payload := eventResourceRefToPayload(req.Payload)
+	reqPayload := map[string]interface{}{
+		"type":      req.EventType,
+		"thread_id": req.ThreadID,
+		"payload":   eventResourceRefToPayload(req.Payload),
+	}
+	if req.IdempotencyKey != "" {
+		reqPayload["idempotency_key"] = req.IdempotencyKey
+	}
+	if len(req.Headers) > 0 {
+		reqPayload["headers"] = req.Headers
+	}
+	// Signed appends carry the head the author linked to and signed.
+	if req.PreviousEventID != nil {
+		reqPayload["previous_event_id"] = *req.PreviousEventID
+	}
+	if req.Signature != "" {
+		reqPayload["signature"] = req.Signature
+	}
 
-	dto, err := c.AppendThreadEventDirect(ctx, "me", req.ThreadID, req.EventType, payload, req.IdempotencyKey)
+	dto, err := c.postThreadEvent(ctx, req.ThreadID, reqPayload)
 	if err != nil {
 		return nil, fmt.Errorf("cloud append thread event failed: %w", err)
 	}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
 
 	channel_domain "vault-app/internal/channel/domain"
 	tracecore_types "vault-app/internal/tracecore/types"
@@ -99,12 +101,21 @@ func (c *TracecoreClient) CreateChannel(ctx context.Context, req *channel_domain
 	}, nil
 }
 
+// updatedSinceParam encodes the updated_since query parameter of the
+// incremental listings.
+func updatedSinceParam(t time.Time) string {
+	return url.QueryEscape(t.UTC().Format(time.RFC3339Nano))
+}
+
 func (c *TracecoreClient) ListChannels(ctx context.Context, req *channel_domain.ListChannelsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Channel], error) {
 	baseUrl := c.AnkhoraCloudUrl
 	if baseUrl == "" {
 		baseUrl = c.BaseURL
 	}
 	url := baseUrl + "/channels/workspace/" + req.WorkspaceID
+	if !req.UpdatedSince.IsZero() {
+		url += "?updated_since=" + updatedSinceParam(req.UpdatedSince)
+	}
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -813,6 +824,9 @@ func (c *TracecoreClient) InviteToChannel(ctx context.Context, req *channel_doma
 		"inviter_vault_id": req.InviterVaultID,
 		"invitee_vault_id": req.InviteeVaultID,
 	}
+	if req.ExpiresAt != nil {
+		payload["expires_at"] = req.ExpiresAt.UTC()
+	}
 
 	body := &bytes.Buffer{}
 	if err := json.NewEncoder(body).Encode(payload); err != nil {
@@ -965,6 +979,215 @@ func (c *TracecoreClient) AcceptChannelInvitation(ctx context.Context, req *chan
 	}, nil
 }
 
+// RejectChannelInvitation declines a pending invitation through the
+// authoritative Cloud backend (POST /channels/invitations/{id}/reject). Cloud
+// checks that the rejecting vault is the invitee.
+func (c *TracecoreClient) RejectChannelInvitation(ctx context.Context, req *channel_domain.RejectInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InvitationID == "" {
+		return nil, channel_domain.ErrInvitationIDRequired
+	}
+	if req.InviteeVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	payload := map[string]interface{}{
+		"invitation_id":    req.InvitationID,
+		"invitee_vault_id": req.InviteeVaultID,
+	}
+
+	return c.postChannelInvitationAction(ctx, "RejectChannelInvitation", req.InvitationID, "reject", payload)
+}
+
+// RevokeChannelInvitation withdraws a pending invitation through the
+// authoritative Cloud backend (POST /channels/invitations/{id}/revoke). Cloud
+// checks that the revoking vault is the inviter.
+func (c *TracecoreClient) RevokeChannelInvitation(ctx context.Context, req *channel_domain.RevokeInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InvitationID == "" {
+		return nil, channel_domain.ErrInvitationIDRequired
+	}
+	if req.InviterVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	payload := map[string]interface{}{
+		"invitation_id":    req.InvitationID,
+		"inviter_vault_id": req.InviterVaultID,
+	}
+	if req.Reason != "" {
+		payload["reason"] = req.Reason
+	}
+
+	return c.postChannelInvitationAction(ctx, "RevokeChannelInvitation", req.InvitationID, "revoke", payload)
+}
+
+// ExpireChannelInvitation records the expiry of a pending invitation through
+// the authoritative Cloud backend (POST /channels/invitations/{id}/expire).
+// Cloud checks that the vault is the inviter and that the invitation is past
+// its expiry.
+func (c *TracecoreClient) ExpireChannelInvitation(ctx context.Context, req *channel_domain.ExpireInvitationRequest) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.InvitationID == "" {
+		return nil, channel_domain.ErrInvitationIDRequired
+	}
+	if req.InviterVaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	payload := map[string]interface{}{
+		"invitation_id":    req.InvitationID,
+		"inviter_vault_id": req.InviterVaultID,
+	}
+
+	return c.postChannelInvitationAction(ctx, "ExpireChannelInvitation", req.InvitationID, "expire", payload)
+}
+
+// postChannelInvitationAction posts a lifecycle action on one invitation and
+// decodes the resulting Invitation.
+func (c *TracecoreClient) postChannelInvitationAction(ctx context.Context, op string, invitationID string, action string, payload map[string]interface{}) (*tracecore_types.CloudResponse[channel_domain.Invitation], error) {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(payload); err != nil {
+		return nil, err
+	}
+
+	baseUrl := c.AnkhoraCloudUrl
+	if baseUrl == "" {
+		baseUrl = c.BaseURL
+	}
+	url := baseUrl + "/channels/invitations/" + invitationID + "/" + action
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body failed: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Cloud backend returned status %d: %s", resp.StatusCode, string(respBytes))
+	}
+
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.CloudChannelInvitation]
+	if err := json.Unmarshal(respBytes, &cloudResp); err == nil && cloudResp.Data.ID != "" {
+		return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+			Status:  200,
+			Data:    mapCloudChannelInvitation(cloudResp.Data),
+			Message: "success",
+			Success: true,
+		}, nil
+	}
+
+	// Tolerate a bare invitation object as a fallback shape.
+	var dto tracecore_types.CloudChannelInvitation
+	if errDTO := json.Unmarshal(respBytes, &dto); errDTO != nil || dto.ID == "" {
+		return nil, fmt.Errorf("TracecoreClient - %s - unexpected Cloud response shape (missing invitation data): %s", op, string(respBytes))
+	}
+
+	return &tracecore_types.CloudResponse[channel_domain.Invitation]{
+		Status:  200,
+		Data:    mapCloudChannelInvitation(dto),
+		Message: "success",
+		Success: true,
+	}, nil
+}
+
+// ListChannelInvitations lists the invitations a vault received or sent
+// through the authoritative Cloud backend (GET /channels/invitations). With no
+// invitations the Cloud marshals a nil slice as null, which is a valid empty
+// list.
+func (c *TracecoreClient) ListChannelInvitations(ctx context.Context, req *channel_domain.ListInvitationsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Invitation], error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.VaultID == "" {
+		return nil, channel_domain.ErrVaultIDRequired
+	}
+
+	query := url.Values{}
+	query.Set("vault_id", req.VaultID)
+	query.Set("direction", string(req.Direction))
+	if req.ChannelID != "" {
+		query.Set("channel_id", req.ChannelID)
+	}
+	if req.Status != "" {
+		query.Set("status", string(req.Status))
+	}
+
+	baseUrl := c.AnkhoraCloudUrl
+	if baseUrl == "" {
+		baseUrl = c.BaseURL
+	}
+	endpoint := baseUrl + "/channels/invitations?" + query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body failed: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Cloud backend returned status %d: %s", resp.StatusCode, string(respBytes))
+	}
+
+	var dtos []tracecore_types.CloudChannelInvitation
+	var cloudResp tracecore_types.CloudResponse[[]tracecore_types.CloudChannelInvitation]
+	if err := json.Unmarshal(respBytes, &cloudResp); err == nil {
+		dtos = cloudResp.Data
+	} else if errArr := json.Unmarshal(respBytes, &dtos); errArr != nil {
+		// Tolerate a bare array of invitations as a fallback shape.
+		return nil, fmt.Errorf("TracecoreClient - ListChannelInvitations - unexpected Cloud response shape: %s", string(respBytes))
+	}
+
+	invitations := make([]channel_domain.Invitation, 0, len(dtos))
+	for i, dto := range dtos {
+		if dto.ID == "" || dto.ChannelID == "" {
+			return nil, fmt.Errorf("TracecoreClient - ListChannelInvitations - Cloud returned a malformed invitation at index %d: missing invitation/channel identity", i)
+		}
+		invitations = append(invitations, mapCloudChannelInvitation(dto))
+	}
+
+	return &tracecore_types.CloudResponse[[]channel_domain.Invitation]{
+		Status:  200,
+		Data:    invitations,
+		Message: "success",
+		Success: true,
+	}, nil
+}
+
 // mapCloudChannelInvitation is the canonical Cloud -> Desktop Invitation
 // mapping. Every field exposed by the Cloud Invitation aggregate is preserved.
 func mapCloudChannelInvitation(dto tracecore_types.CloudChannelInvitation) channel_domain.Invitation {
@@ -976,5 +1199,75 @@ func mapCloudChannelInvitation(dto tracecore_types.CloudChannelInvitation) chann
 		Status:         channel_domain.InvitationStatus(dto.Status),
 		CreatedAt:      dto.CreatedAt,
 		AcceptedAt:     dto.AcceptedAt,
+		ExpiresAt:      dto.ExpiresAt,
+	}
+}
+
+// GetChannelTemplate loads the latest version of a channel template from the
+// Cloud registry (GET /channel-templates/{id}), the channel counterpart of
+// GetTemplate.
+func (c *TracecoreClient) GetChannelTemplate(ctx context.Context, templateID string) (*channel_domain.ChannelTemplate, error) {
+	if templateID == "" {
+		return nil, channel_domain.ErrTemplateIDRequired
+	}
+
+	var template channel_domain.ChannelTemplate
+	if err := c.getChannelTemplates(ctx, "/channel-templates/"+url.PathEscape(templateID), &template); err != nil {
+		return nil, err
 	}
+	return &template, nil
+}
+
+// ListChannelTemplates lists the Cloud channel template catalog
+// (GET /channel-templates).
+func (c *TracecoreClient) ListChannelTemplates(ctx context.Context) ([]channel_domain.ChannelTemplate, error) {
+	templates := []channel_domain.ChannelTemplate{}
+	if err := c.getChannelTemplates(ctx, "/channel-templates", &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (c *TracecoreClient) getChannelTemplates(ctx context.Context, path string, out any) error {
+	baseUrl := c.AnkhoraCloudUrl
+	if baseUrl == "" {
+		baseUrl = c.BaseURL
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read body failed: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return channel_domain.ErrTemplateNotFound
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Cloud backend returned status %d: %s", resp.StatusCode, string(respBytes))
+	}
+
+	cloudResp := tracecore_types.CloudResponse[json.RawMessage]{}
+	if err := json.Unmarshal(respBytes, &cloudResp); err != nil {
+		return fmt.Errorf("invalid cloud response: %w", err)
+	}
+	if len(cloudResp.Data) == 0 || string(cloudResp.Data) == "null" {
+		return channel_domain.ErrTemplateNotFound
+	}
+	if err := json.Unmarshal(cloudResp.Data, out); err != nil {
+		return fmt.Errorf("invalid channel template: %w", err)
+	}
+	return nil
 }
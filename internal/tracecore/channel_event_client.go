@@ -0,0 +1,79 @@
+package tracecore
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// AppendChannelEvent appends to the channel's event log
+// (POST /channels/{id}/events). The Cloud assigns the id and cursor.
+func (c *TracecoreClient) AppendChannelEvent(ctx context.Context, req *channel_domain.AppendChannelEventRequest) (*tracecore_types.CloudResponse[channel_domain.ChannelEvent], error) {
+	if req == nil {
+		return nil, channel_domain.ErrRequestRequired
+	}
+	if req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+
+	payload := tracecore_types.CloudChannelEvent{
+		ChannelID:      req.ChannelID,
+		Type:           req.Type,
+		ActorVaultID:   req.ActorVaultID,
+		Payload:        req.Payload,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.CloudChannelEvent]
+	if err := c.c3Request(ctx, http.MethodPost, "/channels/"+url.PathEscape(req.ChannelID)+"/events", payload, &cloudResp); err != nil {
+		return nil, err
+	}
+
+	return &tracecore_types.CloudResponse[channel_domain.ChannelEvent]{
+		Status:  cloudResp.Status,
+		Data:    mapCloudChannelEvent(cloudResp.Data),
+		Message: cloudResp.Message,
+		Success: cloudResp.Success,
+	}, nil
+}
+
+// ListChannelEvents lists the channel's event log in cursor order
+// (GET /channels/{id}/events). An empty log is valid.
+func (c *TracecoreClient) ListChannelEvents(ctx context.Context, req *channel_domain.ListChannelEventsRequest) (*tracecore_types.CloudResponse[[]channel_domain.ChannelEvent], error) {
+	if req == nil || req.ChannelID == "" {
+		return nil, channel_domain.ErrChannelIDRequired
+	}
+
+	var cloudResp tracecore_types.CloudResponse[[]tracecore_types.CloudChannelEvent]
+	if err := c.c3Request(ctx, http.MethodGet, "/channels/"+url.PathEscape(req.ChannelID)+"/events", nil, &cloudResp); err != nil {
+		return nil, err
+	}
+
+	events := make([]channel_domain.ChannelEvent, 0, len(cloudResp.Data))
+	for _, dto := range cloudResp.Data {
+		events = append(events, mapCloudChannelEvent(dto))
+	}
+	return &tracecore_types.CloudResponse[[]channel_domain.ChannelEvent]{
+		Status:  cloudResp.Status,
+		Data:    events,
+		Message: cloudResp.Message,
+		Success: cloudResp.Success,
+	}, nil
+}
+
+func mapCloudChannelEvent(dto tracecore_types.CloudChannelEvent) channel_domain.ChannelEvent {
+	return channel_domain.ChannelEvent{
+		ID:             dto.ID,
+		ChannelID:      dto.ChannelID,
+		Type:           dto.Type,
+		ActorVaultID:   dto.ActorVaultID,
+		Payload:        dto.Payload,
+		IdempotencyKey: dto.IdempotencyKey,
+		Cursor:         dto.Cursor,
+		CreatedAt:      dto.CreatedAt,
+	}
+}
+
+var _ channel_domain.ChannelEventLog = (*TracecoreClient)(nil)
@@ -10,6 +10,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
 	app_config "vault-app/internal/config"
 	app_config_domain "vault-app/internal/config/domain"
@@ -155,6 +156,55 @@ func (c *TracecoreClient) doRequest(ctx context.Context, method, path string, bo
 	return nil
 }
 
+// ListCommits returns the commit envelopes Tracecore recorded for a repo
+// branch, oldest first.
+// GET /d-vault/vaults/{repoID}/commits?branch={branch}
+func (tc *TracecoreClient) ListCommits(ctx context.Context, repoID, branch string) ([]tracecore_models.CommitEnvelope, error) {
+	if tc == nil || tc.HTTPClient == nil {
+		return nil, fmt.Errorf("TracecoreClient is not initialized")
+	}
+
+	path := "/d-vault/vaults/" + url.PathEscape(repoID) + "/commits"
+	if branch != "" {
+		path += "?branch=" + url.QueryEscape(branch)
+	}
+
+	var out []tracecore_models.CommitEnvelope
+	if err := tc.doRequest(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, fmt.Errorf("list commits failed: %w", err)
+	}
+	return out, nil
+}
+
+// ListRepoActors returns the actors registered on a repo with their keys.
+// GET /d-vault/vaults/{repoID}/actors
+func (tc *TracecoreClient) ListRepoActors(ctx context.Context, repoID string) ([]tracecore_models.RepoActor, error) {
+	if tc == nil || tc.HTTPClient == nil {
+		return nil, fmt.Errorf("TracecoreClient is not initialized")
+	}
+
+	var out []tracecore_models.RepoActor
+	if err := tc.doRequest(ctx, http.MethodGet, "/d-vault/vaults/"+url.PathEscape(repoID)+"/actors", nil, &out); err != nil {
+		return nil, fmt.Errorf("list repo actors failed: %w", err)
+	}
+	return out, nil
+}
+
+// ListDelegationGrants returns the delegation grants recorded on a repo,
+// revoked ones included.
+// GET /d-vault/vaults/{repoID}/delegations
+func (tc *TracecoreClient) ListDelegationGrants(ctx context.Context, repoID string) ([]tracecore_models.DelegationGrant, error) {
+	if tc == nil || tc.HTTPClient == nil {
+		return nil, fmt.Errorf("TracecoreClient is not initialized")
+	}
+
+	var out []tracecore_models.DelegationGrant
+	if err := tc.doRequest(ctx, http.MethodGet, "/d-vault/vaults/"+url.PathEscape(repoID)+"/delegations", nil, &out); err != nil {
+		return nil, fmt.Errorf("list delegation grants failed: %w", err)
+	}
+	return out, nil
+}
+
 func (tc *TracecoreClient) Commit(payload tracecore_models.CommitEnvelope) (*tracecore_models.CommitResponse, error) {
 	if tc == nil {
 		return nil, fmt.Errorf("TracecoreClient is nil")
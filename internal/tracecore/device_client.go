@@ -0,0 +1,168 @@
+package tracecore
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	identity_domain "vault-app/internal/identity/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// CloudDeviceRepository keeps a vault's devices in the Cloud identity store
+// (/identity/devices), so every installation of the vault sees the same
+// devices: the first-device check and approvals from another machine rely
+// on it.
+type CloudDeviceRepository struct {
+	client *TracecoreClient
+}
+
+func NewCloudDeviceRepository(client *TracecoreClient) *CloudDeviceRepository {
+	return &CloudDeviceRepository{client: client}
+}
+
+var _ identity_domain.DeviceRepository = (*CloudDeviceRepository)(nil)
+
+func (r *CloudDeviceRepository) Save(ctx context.Context, d *identity_domain.Device) error {
+	return r.client.c3Request(ctx, http.MethodPost, "/identity/devices", toCloudDevice(d), nil)
+}
+
+func (r *CloudDeviceRepository) FindByID(ctx context.Context, id string) (*identity_domain.Device, error) {
+	if id == "" {
+		return nil, identity_domain.ErrDeviceNotFound
+	}
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.CloudDevice]
+	if err := r.client.c3Request(ctx, http.MethodGet, "/identity/devices/"+url.PathEscape(id), nil, &cloudResp); err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			return nil, identity_domain.ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	if cloudResp.Data.ID == "" {
+		return nil, identity_domain.ErrDeviceNotFound
+	}
+	return fromCloudDevice(cloudResp.Data), nil
+}
+
+func (r *CloudDeviceRepository) ListByVaultID(ctx context.Context, vaultID string) ([]*identity_domain.Device, error) {
+	if vaultID == "" {
+		return nil, identity_domain.ErrDeviceVaultIDRequired
+	}
+	var cloudResp tracecore_types.CloudResponse[[]tracecore_types.CloudDevice]
+	if err := r.client.c3Request(ctx, http.MethodGet, "/identity/devices?vault_id="+url.QueryEscape(vaultID), nil, &cloudResp); err != nil {
+		return nil, err
+	}
+	devices := make([]*identity_domain.Device, 0, len(cloudResp.Data))
+	for _, dto := range cloudResp.Data {
+		devices = append(devices, fromCloudDevice(dto))
+	}
+	return devices, nil
+}
+
+func (r *CloudDeviceRepository) Update(ctx context.Context, d *identity_domain.Device) error {
+	err := r.client.c3Request(ctx, http.MethodPut, "/identity/devices/"+url.PathEscape(d.ID), toCloudDevice(d), nil)
+	if errors.Is(err, ErrResourceNotFound) {
+		return identity_domain.ErrDeviceNotFound
+	}
+	return err
+}
+
+// CloudDeviceEnrollmentRepository keeps pending device approvals in the
+// Cloud identity store (/identity/device-enrollments).
+type CloudDeviceEnrollmentRepository struct {
+	client *TracecoreClient
+}
+
+func NewCloudDeviceEnrollmentRepository(client *TracecoreClient) *CloudDeviceEnrollmentRepository {
+	return &CloudDeviceEnrollmentRepository{client: client}
+}
+
+var _ identity_domain.DeviceEnrollmentRepository = (*CloudDeviceEnrollmentRepository)(nil)
+
+func (r *CloudDeviceEnrollmentRepository) Save(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	return r.client.c3Request(ctx, http.MethodPost, "/identity/device-enrollments", toCloudDeviceEnrollment(e), nil)
+}
+
+func (r *CloudDeviceEnrollmentRepository) FindByID(ctx context.Context, id string) (*identity_domain.DeviceEnrollment, error) {
+	if id == "" {
+		return nil, identity_domain.ErrEnrollmentNotFound
+	}
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.CloudDeviceEnrollment]
+	if err := r.client.c3Request(ctx, http.MethodGet, "/identity/device-enrollments/"+url.PathEscape(id), nil, &cloudResp); err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			return nil, identity_domain.ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	if cloudResp.Data.ID == "" {
+		return nil, identity_domain.ErrEnrollmentNotFound
+	}
+	return fromCloudDeviceEnrollment(cloudResp.Data), nil
+}
+
+func (r *CloudDeviceEnrollmentRepository) Update(ctx context.Context, e *identity_domain.DeviceEnrollment) error {
+	err := r.client.c3Request(ctx, http.MethodPut, "/identity/device-enrollments/"+url.PathEscape(e.ID), toCloudDeviceEnrollment(e), nil)
+	if errors.Is(err, ErrResourceNotFound) {
+		return identity_domain.ErrEnrollmentNotFound
+	}
+	return err
+}
+
+func toCloudDevice(d *identity_domain.Device) tracecore_types.CloudDevice {
+	return tracecore_types.CloudDevice{
+		ID:         d.ID,
+		VaultID:    d.VaultID,
+		PublicKey:  d.PublicKey,
+		KeyType:    d.KeyType,
+		Status:     d.Status,
+		Name:       d.Name,
+		CreatedAt:  d.CreatedAt,
+		RevokedAt:  d.RevokedAt,
+		LastSeenAt: d.LastSeenAt,
+	}
+}
+
+func fromCloudDevice(dto tracecore_types.CloudDevice) *identity_domain.Device {
+	return &identity_domain.Device{
+		ID:         dto.ID,
+		VaultID:    dto.VaultID,
+		PublicKey:  dto.PublicKey,
+		KeyType:    dto.KeyType,
+		Status:     dto.Status,
+		Name:       dto.Name,
+		CreatedAt:  dto.CreatedAt,
+		RevokedAt:  dto.RevokedAt,
+		LastSeenAt: dto.LastSeenAt,
+	}
+}
+
+func toCloudDeviceEnrollment(e *identity_domain.DeviceEnrollment) tracecore_types.CloudDeviceEnrollment {
+	return tracecore_types.CloudDeviceEnrollment{
+		ID:               e.ID,
+		VaultID:          e.VaultID,
+		DeviceID:         e.DeviceID,
+		CodeHash:         e.CodeHash,
+		Status:           e.Status,
+		Attempts:         e.Attempts,
+		ApproverDeviceID: e.ApproverDeviceID,
+		CreatedAt:        e.CreatedAt,
+		ExpiresAt:        e.ExpiresAt,
+		ResolvedAt:       e.ResolvedAt,
+	}
+}
+
+func fromCloudDeviceEnrollment(dto tracecore_types.CloudDeviceEnrollment) *identity_domain.DeviceEnrollment {
+	return &identity_domain.DeviceEnrollment{
+		ID:               dto.ID,
+		VaultID:          dto.VaultID,
+		DeviceID:         dto.DeviceID,
+		CodeHash:         dto.CodeHash,
+		Status:           dto.Status,
+		Attempts:         dto.Attempts,
+		ApproverDeviceID: dto.ApproverDeviceID,
+		CreatedAt:        dto.CreatedAt,
+		ExpiresAt:        dto.ExpiresAt,
+		ResolvedAt:       dto.ResolvedAt,
+	}
+}
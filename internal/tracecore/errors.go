@@ -19,6 +19,21 @@ var (
 	ErrDelegationAlreadyExists  = errors.New("active delegation already exists")
 )
 
+// CloudStatusError is an error status returned by Ankhora Cloud. A 404
+// matches ErrResourceNotFound so callers can tell a missing record apart.
+type CloudStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *CloudStatusError) Error() string {
+	return fmt.Sprintf("Cloud backend returned status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *CloudStatusError) Is(target error) bool {
+	return target == ErrResourceNotFound && e.StatusCode == http.StatusNotFound
+}
+
 // MapHTTPStatusToError converts an HTTP status code and response body into a typed error
 func MapHTTPStatusToError(statusCode int, body string) error {
 	switch statusCode {
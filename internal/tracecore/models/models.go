@@ -1,5 +1,6 @@
 package tracecore_models
 
+import "time"
 
 
 type CommitPayload struct {
@@ -31,19 +32,43 @@ type Actor struct {
 	DelegatedFrom string `json:"delegated_from"` // Optional org/unit
 	Signature     string `json:"signature"`
 }
+
+// RepoActor is an actor registered on a Tracecore repo, with the Stellar
+// key its commit signatures are checked against.
+type RepoActor struct {
+	ID        string `json:"id"`
+	Role      string `json:"role"`
+	PublicKey string `json:"public_key"`
+}
+
+// DelegationGrant records that Delegator let Delegate commit on its behalf.
+type DelegationGrant struct {
+	Delegate  string     `json:"delegate"`
+	Delegator string     `json:"delegator"`
+	GrantedAt time.Time  `json:"granted_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
 type CommitResponse struct {
 	CommitID    string `json:"commit_id"`
 	Status      int `json:"status"`
 	Anchored    bool   `json:"anchored"`
 	CID         string `json:"cid"`
 	TxID        string `json:"tx_id"`
-	RuleResults map[string]struct {
-		Passed bool `json:"passed"`
-		Steps  []struct {
-			Name   string `json:"name"`
-			Passed bool   `json:"passed"`
-		} `json:"steps"`
-	} `json:"rule_results"`
+	RuleResults map[string]RuleResult `json:"rule_results"`
+}
+
+// RuleResult is the per-rule outcome reported by Tracecore and reproduced by
+// the client-side rule engine before submission.
+type RuleResult struct {
+	Passed bool       `json:"passed"`
+	Steps  []RuleStep `json:"steps"`
+}
+
+type RuleStep struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
 }
 
 type CommitEnvelope struct {
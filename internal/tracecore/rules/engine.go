@@ -0,0 +1,208 @@
+package tracecore_rules
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+)
+
+var (
+	ErrCommitRejected = errors.New("commit rejected by commit rules")
+	ErrRuleNameEmpty  = errors.New("rule name is required")
+	ErrEvaluatorNil   = errors.New("rule evaluator is nil")
+)
+
+// Engine evaluates AppConfig.CommitRules locally, before a commit envelope is
+// signed and sent to Tracecore. It reproduces the step-by-step structure of
+// CommitResponse.RuleResults so the UI can render local and remote results
+// the same way.
+//
+// Rules the engine does not know, or cannot check without data only
+// Tracecore has, are reported as skipped rather than failed: Tracecore
+// remains authoritative for them.
+type Engine struct {
+	Rules         []Rule
+	AllowedActors []string
+	// ActorRoles maps the repo's registered actors to their role, for rules
+	// that name roles of actors other than the author.
+	ActorRoles map[string]string
+	evaluators map[string]Evaluator
+}
+
+func NewEngine(rules []Rule, allowedActors []string) *Engine {
+	return &Engine{
+		Rules:         rules,
+		AllowedActors: allowedActors,
+		evaluators:    defaultEvaluators(),
+	}
+}
+
+// WithRepoActors resolves other actors' roles from the repo's registered
+// actors.
+func (e *Engine) WithRepoActors(actors []tracecore_models.RepoActor) *Engine {
+	e.ActorRoles = make(map[string]string, len(actors))
+	for _, a := range actors {
+		if a.ID != "" && a.Role != "" {
+			e.ActorRoles[a.ID] = a.Role
+		}
+	}
+	return e
+}
+
+// Register adds or replaces the evaluator for a rule name.
+func (e *Engine) Register(name string, evaluator Evaluator) error {
+	name = Rule{Rule: name}.Name()
+	if name == "" {
+		return ErrRuleNameEmpty
+	}
+	if evaluator == nil {
+		return ErrEvaluatorNil
+	}
+
+	e.evaluators[name] = evaluator
+	return nil
+}
+
+// Report is the outcome of evaluating a rule set against one commit.
+type Report struct {
+	Passed  bool                                   `json:"passed"`
+	Results map[string]tracecore_models.RuleResult `json:"rule_results"`
+	Skipped []string                               `json:"skipped,omitempty"`
+}
+
+// Violations lists every failed step as "rule/step: reason", sorted by rule.
+func (r Report) Violations() []string {
+	names := make([]string, 0, len(r.Results))
+	for name := range r.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	violations := make([]string, 0)
+	for _, name := range names {
+		for _, step := range r.Results[name].Steps {
+			if step.Passed {
+				continue
+			}
+			violations = append(violations, fmt.Sprintf("%s/%s: %s", name, step.Name, step.Reason))
+		}
+	}
+
+	return violations
+}
+
+// Evaluate runs every rule against the commit. Results are keyed by the rule
+// expression as configured, matching the keys Tracecore returns.
+func (e *Engine) Evaluate(commit tracecore_models.CommitPayload) Report {
+	report := Report{
+		Passed:  true,
+		Results: make(map[string]tracecore_models.RuleResult, len(e.Rules)),
+		Skipped: []string{},
+	}
+
+	for _, rule := range e.Rules {
+		evaluator, ok := e.evaluators[rule.Name()]
+		if !ok {
+			report.Skipped = append(report.Skipped, rule.Rule)
+			continue
+		}
+
+		steps := evaluator(Evaluation{
+			Commit:        commit,
+			Rule:          rule,
+			AllowedActors: e.AllowedActors,
+			ActorRoles:    e.ActorRoles,
+		})
+		if len(steps) == 0 {
+			report.Skipped = append(report.Skipped, rule.Rule)
+			continue
+		}
+
+		result := tracecore_models.RuleResult{Passed: true, Steps: steps}
+		for _, step := range steps {
+			if !step.Passed {
+				result.Passed = false
+				break
+			}
+		}
+
+		if !result.Passed {
+			report.Passed = false
+		}
+		report.Results[rule.Rule] = result
+	}
+
+	return report
+}
+
+// Enforce evaluates the commit and returns a *ViolationError when any rule
+// fails. The report is returned in both cases.
+func (e *Engine) Enforce(commit tracecore_models.CommitPayload) (*Report, error) {
+	report := e.Evaluate(commit)
+	if !report.Passed {
+		return &report, &ViolationError{Report: report}
+	}
+
+	return &report, nil
+}
+
+// ViolationError explains why a commit was blocked. It unwraps to
+// ErrCommitRejected.
+type ViolationError struct {
+	Report Report
+}
+
+func (v *ViolationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrCommitRejected, strings.Join(v.Report.Violations(), "; "))
+}
+
+func (v *ViolationError) Unwrap() error {
+	return ErrCommitRejected
+}
+
+// DryRunResult is the evaluation of one historical commit.
+type DryRunResult struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+	ActorID string `json:"actor_id"`
+	Report  Report `json:"report"`
+}
+
+// DryRunSummary aggregates a dry run so an admin can see the impact of a
+// rule set before enabling it.
+type DryRunSummary struct {
+	Total   int            `json:"total"`
+	Passed  int            `json:"passed"`
+	Failed  int            `json:"failed"`
+	Results []DryRunResult `json:"results"`
+}
+
+// DryRun evaluates the rule set against previously submitted commits without
+// blocking anything.
+func (e *Engine) DryRun(commits []tracecore_models.CommitEnvelope) DryRunSummary {
+	summary := DryRunSummary{
+		Total:   len(commits),
+		Results: make([]DryRunResult, 0, len(commits)),
+	}
+
+	for i, env := range commits {
+		report := e.Evaluate(env.Commit)
+		if report.Passed {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+
+		summary.Results = append(summary.Results, DryRunResult{
+			Index:   i,
+			Message: env.Commit.Metadata.Message,
+			ActorID: env.Commit.Metadata.Actor.ID,
+			Report:  report,
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,238 @@
+package tracecore_rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+)
+
+// Evaluation is the input handed to an Evaluator: the commit under test, the
+// rule being evaluated, the actors allowed globally by AppConfig.Actors and
+// the roles of the repo's registered actors, keyed by actor id.
+type Evaluation struct {
+	Commit        tracecore_models.CommitPayload
+	Rule          Rule
+	AllowedActors []string
+	ActorRoles    map[string]string
+}
+
+// Evaluator produces the ordered steps of one rule. A rule passes when every
+// step passes. An evaluator returns no steps when the rule depends on
+// something only Tracecore sees, such as another actor's signature or a
+// review recorded after submission; the rule is then reported as skipped.
+type Evaluator func(e Evaluation) []tracecore_models.RuleStep
+
+func defaultEvaluators() map[string]Evaluator {
+	return map[string]Evaluator{
+		RuleRequiresSignature:  evaluateRequiresSignature,
+		RuleValidActorsOnly:    evaluateValidActorsOnly,
+		RuleAllowedRoles:       evaluateAllowedRoles,
+		RuleRequiresReview:     evaluateRequiresReview,
+		RuleRequiredFields:     evaluateRequiredFields,
+		RuleStatusTransition:   evaluateStatusTransition,
+		RuleRequiresAttachment: evaluateRequiresAttachments,
+	}
+}
+
+func pass(name string) tracecore_models.RuleStep {
+	return tracecore_models.RuleStep{Name: name, Passed: true}
+}
+
+func fail(name string, format string, args ...any) tracecore_models.RuleStep {
+	return tracecore_models.RuleStep{Name: name, Passed: false, Reason: fmt.Sprintf(format, args...)}
+}
+
+func check(name string, ok bool, format string, args ...any) tracecore_models.RuleStep {
+	if ok {
+		return pass(name)
+	}
+	return fail(name, format, args...)
+}
+
+// actorMatches reports whether the actor id or role is listed. An empty list
+// places no restriction.
+func actorMatches(actor tracecore_models.Actor, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == actor.ID || (actor.Role != "" && a == actor.Role) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateRequiresSignature checks the author's signature. When the rule
+// names other signers, their signature is collected by Tracecore and the
+// rule cannot be checked locally.
+func evaluateRequiresSignature(e Evaluation) []tracecore_models.RuleStep {
+	actor := e.Commit.Metadata.Actor
+	if !actorMatches(actor, e.Rule.Actors) {
+		return nil
+	}
+
+	return []tracecore_models.RuleStep{
+		check("actor_signature_present", actor.Signature != "",
+			"actor %q did not sign the commit", actor.ID),
+		check("metadata_signature_present", e.Commit.Metadata.Signature != "",
+			"commit metadata carries no signature"),
+	}
+}
+
+func evaluateValidActorsOnly(e Evaluation) []tracecore_models.RuleStep {
+	actor := e.Commit.Metadata.Actor
+	allowed := append(append([]string{}, e.Rule.Actors...), e.AllowedActors...)
+
+	return []tracecore_models.RuleStep{
+		check("actor_identified", actor.ID != "", "commit has no actor id"),
+		check("actor_allowed", actorMatches(actor, allowed),
+			"actor %q (role %q) is not one of %v", actor.ID, actor.Role, allowed),
+	}
+}
+
+func evaluateAllowedRoles(e Evaluation) []tracecore_models.RuleStep {
+	actor := e.Commit.Metadata.Actor
+	roles := append(append([]string{}, e.Rule.Args()...), e.Rule.Actors...)
+
+	allowed := len(roles) == 0
+	for _, role := range roles {
+		if role == actor.Role {
+			allowed = true
+			break
+		}
+	}
+
+	return []tracecore_models.RuleStep{
+		check("actor_role_present", actor.Role != "", "actor %q has no role", actor.ID),
+		check("actor_role_allowed", allowed, "role %q is not one of %v", actor.Role, roles),
+	}
+}
+
+// evaluateRequiresReview checks the reviewer recorded in the commit context
+// under "reviewed_by". Without one, the review is still to come and only
+// Tracecore can enforce it. Rule.Actors name roles, so the reviewer's role is
+// resolved from the repo actors first; when it cannot be, only Tracecore can
+// tell whether the reviewer was allowed.
+func evaluateRequiresReview(e Evaluation) []tracecore_models.RuleStep {
+	reviewerID := e.Commit.Metadata.Context["reviewed_by"]
+	if reviewerID == "" {
+		return nil
+	}
+
+	reviewer := tracecore_models.Actor{ID: reviewerID, Role: e.ActorRoles[reviewerID]}
+	notAuthor := check("reviewer_is_not_author", reviewerID != e.Commit.Metadata.Actor.ID,
+		"actor %q cannot review their own commit", reviewerID)
+
+	allowed := actorMatches(reviewer, e.Rule.Actors)
+	if !allowed && reviewer.Role == "" {
+		return []tracecore_models.RuleStep{notAuthor}
+	}
+
+	return []tracecore_models.RuleStep{
+		check("reviewer_allowed", allowed,
+			"reviewer %q (role %q) is not one of %v", reviewer.ID, reviewer.Role, e.Rule.Actors),
+		notAuthor,
+	}
+}
+
+// evaluateRequiredFields checks every argument as a field name. Top-level
+// fields (message, repo_id, branch) are read from the payload, anything else
+// from the metadata content or context maps. Like the other content rules,
+// it only applies to the actors the rule names.
+func evaluateRequiredFields(e Evaluation) []tracecore_models.RuleStep {
+	if !actorMatches(e.Commit.Metadata.Actor, e.Rule.Actors) {
+		return nil
+	}
+	fields := e.Rule.Args()
+	if len(fields) == 0 {
+		return []tracecore_models.RuleStep{fail("fields_declared", "rule declares no fields")}
+	}
+
+	steps := make([]tracecore_models.RuleStep, 0, len(fields))
+	for _, field := range fields {
+		steps = append(steps, check("field:"+field, hasField(e.Commit, field),
+			"required field %q is missing or empty", field))
+	}
+
+	return steps
+}
+
+func hasField(commit tracecore_models.CommitPayload, field string) bool {
+	switch field {
+	case "message":
+		return commit.Metadata.Message != ""
+	case "repo_id":
+		return commit.RepoID != ""
+	case "branch":
+		return commit.Branch != ""
+	}
+
+	if v, ok := commit.Metadata.Content[field]; ok && v != nil {
+		if s, isString := v.(string); !isString || s != "" {
+			return true
+		}
+	}
+	if v, ok := commit.Metadata.Context[field]; ok && v != "" {
+		return true
+	}
+
+	return false
+}
+
+// evaluateStatusTransition accepts arguments of the form "old>new". A "*"
+// on either side matches any status. Commits that change no status are not
+// concerned.
+func evaluateStatusTransition(e Evaluation) []tracecore_models.RuleStep {
+	change := e.Commit.Metadata.StatusChange
+	if change.New == "" || !actorMatches(e.Commit.Metadata.Actor, e.Rule.Actors) {
+		return nil
+	}
+	transitions := e.Rule.Args()
+
+	allowed := false
+	for _, t := range transitions {
+		from, to, ok := strings.Cut(t, ">")
+		if !ok {
+			continue
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if (from == "*" || from == change.Old) && (to == "*" || to == change.New) {
+			allowed = true
+			break
+		}
+	}
+
+	return []tracecore_models.RuleStep{
+		check("transition_allowed", allowed,
+			"transition %q -> %q is not one of %v", change.Old, change.New, transitions),
+	}
+}
+
+// evaluateRequiresAttachments optionally takes the minimum attachment count
+// as its single argument.
+func evaluateRequiresAttachments(e Evaluation) []tracecore_models.RuleStep {
+	if !actorMatches(e.Commit.Metadata.Actor, e.Rule.Actors) {
+		return nil
+	}
+	min := 1
+	if args := e.Rule.Args(); len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil && n > 0 {
+			min = n
+		}
+	}
+
+	count := 0
+	for _, a := range e.Commit.Metadata.Attachments {
+		if strings.TrimSpace(a) != "" {
+			count++
+		}
+	}
+
+	return []tracecore_models.RuleStep{
+		check("attachments_present", count >= min,
+			"commit has %d attachment(s), at least %d required", count, min),
+	}
+}
@@ -0,0 +1,115 @@
+package tracecore_rules
+
+import (
+	"strings"
+
+	app_config "vault-app/internal/config"
+)
+
+// Rule is the client-side view of a commit rule. It mirrors
+// AppConfig.CommitRules: a rule expression plus the actors (ids or roles)
+// the rule applies to.
+//
+// A rule expression is either a bare name ("requires_signature") or a name
+// followed by a comma separated argument list ("required_fields:entry_id,entry_type").
+// Names are case-insensitive so "REQUIRES_SIGNATURE" (the form sent in
+// CommitPayload.ValidationRules) and "requires_signature" are the same rule.
+type Rule struct {
+	Rule   string   `json:"rule"`
+	Actors []string `json:"actors"`
+}
+
+const (
+	RuleRequiresSignature  = "requires_signature"
+	RuleValidActorsOnly    = "valid_actors_only"
+	RuleAllowedRoles       = "allowed_roles"
+	RuleRequiresReview     = "requires_review"
+	RuleRequiredFields     = "required_fields"
+	RuleStatusTransition   = "status_transition"
+	RuleRequiresAttachment = "requires_attachments"
+)
+
+// aliases maps historical rule spellings found in vault templates to their
+// canonical name.
+var aliases = map[string]string{
+	"signature_required":   RuleRequiresSignature,
+	"attachment_required":  RuleRequiresAttachment,
+	"attachments_required": RuleRequiresAttachment,
+	"requires_attachment":  RuleRequiresAttachment,
+	"valid_actors":         RuleValidActorsOnly,
+}
+
+// Name returns the canonical rule name without arguments.
+func (r Rule) Name() string {
+	name, _ := r.parse()
+	return name
+}
+
+// Args returns the rule arguments, if any.
+func (r Rule) Args() []string {
+	_, args := r.parse()
+	return args
+}
+
+func (r Rule) parse() (string, []string) {
+	expr := strings.TrimSpace(r.Rule)
+	name, rawArgs, _ := strings.Cut(expr, ":")
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+
+	args := make([]string, 0)
+	for _, arg := range strings.Split(rawArgs, ",") {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			args = append(args, arg)
+		}
+	}
+
+	return name, args
+}
+
+// FromCommitRules converts persisted AppConfig commit rules into engine rules.
+func FromCommitRules(rules []app_config.CommitRule) []Rule {
+	res := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		res = append(res, Rule{Rule: r.Rule, Actors: r.Actors})
+	}
+
+	return res
+}
+
+// BaselineValidationRules are sent with every commit, whatever the vault
+// template configures.
+var BaselineValidationRules = []string{"REQUIRES_SIGNATURE", "VALID_ACTORS_ONLY"}
+
+// ValidationRuleNames returns the baseline rules followed by the configured
+// ones, in the form Tracecore expects in CommitPayload.ValidationRules: the
+// upper-case canonical name, then the arguments as configured
+// ("REQUIRED_FIELDS:entry_id,entry_type").
+func ValidationRuleNames(rules []Rule) []string {
+	names := append(make([]string, 0, len(BaselineValidationRules)+len(rules)), BaselineValidationRules...)
+	seen := make(map[string]bool, cap(names))
+	for _, name := range names {
+		seen[name] = true
+	}
+
+	for _, r := range rules {
+		name, args := r.parse()
+		if name == "" {
+			continue
+		}
+		name = strings.ToUpper(name)
+		if len(args) > 0 {
+			name += ":" + strings.Join(args, ",")
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,255 @@
+package tracecore_rules_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+	tracecore_rules "vault-app/internal/tracecore/rules"
+)
+
+func validCommit() tracecore_models.CommitPayload {
+	return tracecore_models.CommitPayload{
+		RepoID: "repo-1",
+		Branch: "main",
+		Metadata: tracecore_models.CommitMetadata{
+			Message: "update entry",
+			Content: map[string]any{"entry_id": "e-1"},
+			Context: map[string]string{"reviewed_by": "bob"},
+			StatusChange: tracecore_models.StatusChange{
+				Old: "draft",
+				New: "published",
+			},
+			Actor: tracecore_models.Actor{
+				ID:        "alice",
+				Role:      "editor",
+				Signature: "sig",
+			},
+			Attachments: []string{"cid-1"},
+			Signature:   "sig",
+		},
+	}
+}
+
+func allRules() []tracecore_rules.Rule {
+	return []tracecore_rules.Rule{
+		{Rule: "REQUIRES_SIGNATURE"},
+		{Rule: "valid_actors_only", Actors: []string{"alice"}},
+		{Rule: "allowed_roles:editor,admin"},
+		{Rule: "requires_review", Actors: []string{"bob"}},
+		{Rule: "required_fields:message,entry_id"},
+		{Rule: "status_transition:draft>published,published>archived"},
+		{Rule: "attachment_required"},
+	}
+}
+
+func TestEngine_Evaluate_AllRulesPass(t *testing.T) {
+	engine := tracecore_rules.NewEngine(allRules(), nil)
+
+	report := engine.Evaluate(validCommit())
+
+	assert.True(t, report.Passed)
+	assert.Len(t, report.Results, 7)
+	assert.Empty(t, report.Skipped)
+	for name, result := range report.Results {
+		assert.True(t, result.Passed, name)
+		assert.NotEmpty(t, result.Steps, name)
+	}
+}
+
+func TestEngine_Evaluate_ReportsFailingSteps(t *testing.T) {
+	engine := tracecore_rules.NewEngine(allRules(), nil)
+
+	commit := validCommit()
+	commit.Metadata.Actor.Role = "viewer"
+	commit.Metadata.StatusChange = tracecore_models.StatusChange{Old: "archived", New: "draft"}
+	commit.Metadata.Attachments = nil
+	delete(commit.Metadata.Content, "entry_id")
+
+	report := engine.Evaluate(commit)
+
+	assert.False(t, report.Passed)
+	assert.False(t, report.Results["allowed_roles:editor,admin"].Passed)
+	assert.False(t, report.Results["status_transition:draft>published,published>archived"].Passed)
+	assert.False(t, report.Results["attachment_required"].Passed)
+	assert.True(t, report.Results["REQUIRES_SIGNATURE"].Passed)
+
+	fields := report.Results["required_fields:message,entry_id"]
+	require.Len(t, fields.Steps, 2)
+	assert.True(t, fields.Steps[0].Passed)
+	assert.False(t, fields.Steps[1].Passed)
+	assert.Contains(t, fields.Steps[1].Reason, "entry_id")
+}
+
+func TestEngine_Evaluate_StatusTransitionOnlyChecksStatusChanges(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{{Rule: "status_transition:draft>published"}}, nil)
+
+	commit := validCommit()
+	commit.Metadata.StatusChange = tracecore_models.StatusChange{}
+	report, err := engine.Enforce(commit)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status_transition:draft>published"}, report.Skipped)
+
+	commit.Metadata.StatusChange = tracecore_models.StatusChange{Old: "published", New: "draft"}
+	_, err = engine.Enforce(commit)
+	require.ErrorIs(t, err, tracecore_rules.ErrCommitRejected)
+}
+
+func TestEngine_Evaluate_ContentRulesApplyToTheirActors(t *testing.T) {
+	rules := []tracecore_rules.Rule{
+		{Rule: "required_fields:ticket", Actors: []string{"contractor"}},
+		{Rule: "status_transition:draft>review", Actors: []string{"contractor"}},
+		{Rule: "requires_attachments:2", Actors: []string{"contractor"}},
+	}
+	engine := tracecore_rules.NewEngine(rules, nil)
+
+	// alice is an editor: none of the rules concern her.
+	report, err := engine.Enforce(validCommit())
+	require.NoError(t, err)
+	assert.Empty(t, report.Results)
+	assert.Len(t, report.Skipped, 3)
+
+	commit := validCommit()
+	commit.Metadata.Actor.Role = "contractor"
+	report, err = engine.Enforce(commit)
+	require.ErrorIs(t, err, tracecore_rules.ErrCommitRejected)
+	assert.False(t, report.Results["required_fields:ticket"].Passed)
+	assert.False(t, report.Results["status_transition:draft>review"].Passed)
+	assert.False(t, report.Results["requires_attachments:2"].Passed)
+}
+
+func TestEngine_Evaluate_UsesGlobalActors(t *testing.T) {
+	rules := []tracecore_rules.Rule{{Rule: "valid_actors_only", Actors: []string{"bob"}}}
+
+	assert.False(t, tracecore_rules.NewEngine(rules, nil).Evaluate(validCommit()).Passed)
+	assert.True(t, tracecore_rules.NewEngine(rules, []string{"editor"}).Evaluate(validCommit()).Passed)
+}
+
+func TestEngine_Evaluate_SkipsUnknownRules(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{{Rule: "server_only_rule"}}, nil)
+
+	report := engine.Evaluate(validCommit())
+
+	assert.True(t, report.Passed)
+	assert.Equal(t, []string{"server_only_rule"}, report.Skipped)
+}
+
+func TestEngine_Register_CustomEvaluator(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{{Rule: "no_friday"}}, nil)
+
+	require.NoError(t, engine.Register("NO_FRIDAY", func(e tracecore_rules.Evaluation) []tracecore_models.RuleStep {
+		return []tracecore_models.RuleStep{{Name: "not_friday", Passed: false, Reason: "it is friday"}}
+	}))
+	assert.ErrorIs(t, engine.Register("", nil), tracecore_rules.ErrRuleNameEmpty)
+	assert.ErrorIs(t, engine.Register("x", nil), tracecore_rules.ErrEvaluatorNil)
+
+	report := engine.Evaluate(validCommit())
+	assert.False(t, report.Passed)
+	assert.Equal(t, []string{"no_friday/not_friday: it is friday"}, report.Violations())
+}
+
+func TestEngine_Enforce_BlocksWithExplanation(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{{Rule: "requires_signature"}}, nil)
+
+	commit := validCommit()
+	commit.Metadata.Actor.Signature = ""
+
+	report, err := engine.Enforce(commit)
+
+	require.Error(t, err)
+	require.NotNil(t, report)
+	assert.True(t, errors.Is(err, tracecore_rules.ErrCommitRejected))
+
+	var violation *tracecore_rules.ViolationError
+	require.True(t, errors.As(err, &violation))
+	assert.Contains(t, err.Error(), "requires_signature/actor_signature_present")
+
+	_, err = engine.Enforce(validCommit())
+	assert.NoError(t, err)
+}
+
+func TestEngine_DryRun(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{{Rule: "requires_attachments:1"}}, nil)
+
+	failing := validCommit()
+	failing.Metadata.Attachments = []string{""}
+
+	summary := engine.DryRun([]tracecore_models.CommitEnvelope{
+		{Commit: validCommit()},
+		{Commit: failing},
+	})
+
+	assert.Equal(t, 2, summary.Total)
+	assert.Equal(t, 1, summary.Passed)
+	assert.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Results, 2)
+	assert.Equal(t, 1, summary.Results[1].Index)
+	assert.Equal(t, "alice", summary.Results[1].ActorID)
+	assert.False(t, summary.Results[1].Report.Passed)
+}
+
+func TestEngine_Evaluate_SkipsRulesOnlyTracecoreCanCheck(t *testing.T) {
+	// The team template: a reviewer and a signer other than the author.
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{
+		{Rule: "requires_review", Actors: []string{"reviewer"}},
+		{Rule: "requires_signature", Actors: []string{"signer"}},
+	}, nil)
+
+	commit := validCommit()
+	commit.Metadata.Actor.Role = "user"
+	delete(commit.Metadata.Context, "reviewed_by")
+
+	report, err := engine.Enforce(commit)
+
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.Empty(t, report.Results)
+	assert.Equal(t, []string{"requires_review", "requires_signature"}, report.Skipped)
+
+	// A recorded review is checked locally.
+	commit.Metadata.Context["reviewed_by"] = "alice"
+	report, err = engine.Enforce(commit)
+	require.Error(t, err)
+	assert.False(t, report.Results["requires_review"].Passed)
+}
+
+func TestEngine_Evaluate_ResolvesTheReviewerRole(t *testing.T) {
+	engine := tracecore_rules.NewEngine([]tracecore_rules.Rule{
+		{Rule: "requires_review", Actors: []string{"reviewer"}},
+	}, nil).WithRepoActors([]tracecore_models.RepoActor{
+		{ID: "bob", Role: "reviewer"},
+		{ID: "carol", Role: "viewer"},
+	})
+
+	commit := validCommit()
+	report, err := engine.Enforce(commit)
+	require.NoError(t, err)
+	assert.True(t, report.Results["requires_review"].Passed)
+
+	commit.Metadata.Context["reviewed_by"] = "carol"
+	report, err = engine.Enforce(commit)
+	require.ErrorIs(t, err, tracecore_rules.ErrCommitRejected)
+	assert.False(t, report.Results["requires_review"].Passed)
+
+	// An actor the repo does not know only gets the self-review check.
+	commit.Metadata.Context["reviewed_by"] = "dave"
+	report, err = engine.Enforce(commit)
+	require.NoError(t, err)
+	require.Len(t, report.Results["requires_review"].Steps, 1)
+	assert.Equal(t, "reviewer_is_not_author", report.Results["requires_review"].Steps[0].Name)
+}
+
+func TestValidationRuleNames(t *testing.T) {
+	names := tracecore_rules.ValidationRuleNames([]tracecore_rules.Rule{
+		{Rule: "requires_signature"},
+		{Rule: "signature_required"},
+		{Rule: "required_fields: message, entry_id"},
+		{Rule: "requires_review"},
+	})
+
+	assert.Equal(t, []string{"REQUIRES_SIGNATURE", "VALID_ACTORS_ONLY", "REQUIRED_FIELDS:message,entry_id", "REQUIRES_REVIEW"}, names)
+	assert.Equal(t, tracecore_rules.BaselineValidationRules, tracecore_rules.ValidationRuleNames(nil))
+}
@@ -0,0 +1,61 @@
+package tracecore_signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+)
+
+// CanonicalPayload returns the byte form of a commit payload that the app
+// (envelope) signature covers: compact JSON, object keys sorted, HTML
+// characters left unescaped and numbers kept as written. The same payload
+// always produces the same bytes regardless of map iteration order.
+func CanonicalPayload(cp tracecore_models.CommitPayload) ([]byte, error) {
+	return canonicalJSON(cp)
+}
+
+// ActorSigningBytes returns the bytes the actor signs: the canonical payload
+// with both actor-level signature fields cleared, since a signature cannot
+// cover itself.
+func ActorSigningBytes(cp tracecore_models.CommitPayload) ([]byte, error) {
+	cp.Metadata.Actor.Signature = ""
+	cp.Metadata.Signature = ""
+	return canonicalJSON(cp)
+}
+
+// LegacyPayload returns the bytes app signatures covered before commits were
+// canonicalised: the plain json.Marshal form of the payload.
+func LegacyPayload(cp tracecore_models.CommitPayload) ([]byte, error) {
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("marshal commit payload failed: %w", err)
+	}
+	return raw, nil
+}
+
+func canonicalJSON(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal commit payload failed: %w", err)
+	}
+
+	// round-trip through a generic value so every object, including the
+	// free-form content map, is re-encoded with sorted keys
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, fmt.Errorf("decode commit payload failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(generic); err != nil {
+		return nil, fmt.Errorf("encode canonical payload failed: %w", err)
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
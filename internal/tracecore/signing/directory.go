@@ -0,0 +1,80 @@
+package tracecore_signing
+
+import (
+	"sync"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+)
+
+// ActorDirectory resolves the keys and delegation grants needed to verify
+// commits.
+type ActorDirectory interface {
+	// PublicKey returns the Stellar public key registered for the actor.
+	PublicKey(actorID string) (string, error)
+	// Delegator returns the actor that granted actorID its authority, or ""
+	// when actorID acts in its own right.
+	Delegator(actorID string) (string, error)
+}
+
+// StaticDirectory is an in-memory ActorDirectory, populated from the local
+// app and user configuration.
+type StaticDirectory struct {
+	mu         sync.RWMutex
+	keys       map[string]string
+	delegators map[string]string
+}
+
+func NewStaticDirectory() *StaticDirectory {
+	return &StaticDirectory{
+		keys:       make(map[string]string),
+		delegators: make(map[string]string),
+	}
+}
+
+// NewRepoDirectory builds a directory from a repo's registered actors and
+// delegation grants. Revoked grants are left out.
+func NewRepoDirectory(actors []tracecore_models.RepoActor, grants []tracecore_models.DelegationGrant) *StaticDirectory {
+	d := NewStaticDirectory()
+	for _, a := range actors {
+		if a.ID != "" && a.PublicKey != "" {
+			d.AddActor(a.ID, a.PublicKey)
+		}
+	}
+	for _, g := range grants {
+		if g.Delegate != "" && g.Delegator != "" && g.RevokedAt == nil {
+			d.AddDelegation(g.Delegate, g.Delegator)
+		}
+	}
+	return d
+}
+
+func (d *StaticDirectory) AddActor(actorID, publicKey string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.keys[actorID] = publicKey
+}
+
+// AddDelegation records that delegator granted delegate authority to act on
+// its behalf.
+func (d *StaticDirectory) AddDelegation(delegate, delegator string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.delegators[delegate] = delegator
+}
+
+func (d *StaticDirectory) PublicKey(actorID string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	key, ok := d.keys[actorID]
+	if !ok || key == "" {
+		return "", ErrUnknownActor
+	}
+	return key, nil
+}
+
+func (d *StaticDirectory) Delegator(actorID string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.delegators[actorID], nil
+}
@@ -0,0 +1,264 @@
+package tracecore_signing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+	tracecore_signing "vault-app/internal/tracecore/signing"
+)
+
+// hexKeyVerifier stands in for the Stellar verifier: public keys are hex
+// encoded raw ed25519 keys.
+type hexKeyVerifier struct{}
+
+var _ tracecore_signing.KeyVerifier = hexKeyVerifier{}
+
+func (hexKeyVerifier) Verify(publicKey string, message, signature []byte) error {
+	pub, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), message, signature) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+type signer struct {
+	pub  string
+	priv ed25519.PrivateKey
+}
+
+func newSigner(t *testing.T) signer {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return signer{pub: hex.EncodeToString(pub), priv: priv}
+}
+
+func (s signer) sign(msg []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.priv, msg))
+}
+
+func signedEnvelope(t *testing.T, actor, app signer, a tracecore_models.Actor) tracecore_models.CommitEnvelope {
+	cp := tracecore_models.CommitPayload{
+		RepoID: "repo-1",
+		Branch: "main",
+		Metadata: tracecore_models.CommitMetadata{
+			Message: "create entry",
+			Content: map[string]any{"b": 2, "a": "<x>"},
+			Context: map[string]string{"z": "1", "y": "2"},
+			Actor:   a,
+		},
+		ValidationRules: []string{"REQUIRES_SIGNATURE"},
+	}
+
+	actorBytes, err := tracecore_signing.ActorSigningBytes(cp)
+	require.NoError(t, err)
+	sig := actor.sign(actorBytes)
+	cp.Metadata.Actor.Signature = sig
+	cp.Metadata.Signature = sig
+
+	appBytes, err := tracecore_signing.CanonicalPayload(cp)
+	require.NoError(t, err)
+
+	return tracecore_models.CommitEnvelope{Commit: cp, Signature: app.sign(appBytes)}
+}
+
+func TestCanonicalPayload_IsDeterministic(t *testing.T) {
+	cp := tracecore_models.CommitPayload{
+		Metadata: tracecore_models.CommitMetadata{
+			Content: map[string]any{"b": 1, "a": map[string]any{"d": 1, "c": "<&>"}},
+		},
+	}
+
+	first, err := tracecore_signing.CanonicalPayload(cp)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		again, err := tracecore_signing.CanonicalPayload(cp)
+		require.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+
+	assert.Contains(t, string(first), `"content":{"a":{"c":"<&>","d":1},"b":1}`)
+}
+
+func TestActorSigningBytes_ExcludesSignatures(t *testing.T) {
+	cp := tracecore_models.CommitPayload{RepoID: "r"}
+	unsigned, err := tracecore_signing.ActorSigningBytes(cp)
+	require.NoError(t, err)
+
+	cp.Metadata.Actor.Signature = "sig"
+	cp.Metadata.Signature = "sig"
+	signed, err := tracecore_signing.ActorSigningBytes(cp)
+	require.NoError(t, err)
+
+	assert.Equal(t, unsigned, signed)
+}
+
+func TestVerifier_VerifyEnvelope(t *testing.T) {
+	alice, app := newSigner(t), newSigner(t)
+	dir := tracecore_signing.NewStaticDirectory()
+	dir.AddActor("alice", alice.pub)
+	v := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub)
+
+	env := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", Role: "editor"})
+	require.NoError(t, v.VerifyEnvelope(env))
+
+	t.Run("tampered payload fails both layers", func(t *testing.T) {
+		tampered := env
+		tampered.Commit.Metadata.Message = "something else"
+
+		err := v.VerifyEnvelope(tampered)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, tracecore_signing.ErrCommitNotVerifiable)
+		assert.ErrorIs(t, err, tracecore_signing.ErrInvalidSignature)
+
+		var verr *tracecore_signing.VerificationError
+		require.True(t, errors.As(err, &verr))
+		assert.Len(t, verr.Failures, 2)
+	})
+
+	t.Run("wrong app key", func(t *testing.T) {
+		other := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, newSigner(t).pub)
+		assert.ErrorIs(t, other.VerifyEnvelope(env), tracecore_signing.ErrInvalidSignature)
+	})
+
+	t.Run("unknown actor", func(t *testing.T) {
+		bob := newSigner(t)
+		env := signedEnvelope(t, bob, app, tracecore_models.Actor{ID: "bob"})
+		assert.ErrorIs(t, v.VerifyEnvelope(env), tracecore_signing.ErrUnknownActor)
+	})
+
+	t.Run("missing actor signature", func(t *testing.T) {
+		unsigned := env
+		unsigned.Commit.Metadata.Actor.Signature = ""
+		assert.ErrorIs(t, v.VerifyEnvelope(unsigned), tracecore_signing.ErrMissingSignature)
+	})
+}
+
+func TestVerifier_DelegationChain(t *testing.T) {
+	alice, app := newSigner(t), newSigner(t)
+	dir := tracecore_signing.NewStaticDirectory()
+	dir.AddActor("alice", alice.pub)
+	dir.AddDelegation("alice", "team-lead")
+	dir.AddDelegation("team-lead", "org-acme")
+	v := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub)
+
+	ok := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", DelegatedFrom: "org-acme"})
+	assert.NoError(t, v.VerifyEnvelope(ok))
+
+	broken := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", DelegatedFrom: "org-other"})
+	assert.ErrorIs(t, v.VerifyEnvelope(broken), tracecore_signing.ErrDelegationBroken)
+
+	dir.AddDelegation("org-acme", "alice")
+	v.MaxDelegationDepth = 4
+	cyclic := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", DelegatedFrom: "org-other"})
+	assert.ErrorIs(t, v.VerifyEnvelope(cyclic), tracecore_signing.ErrDelegationTooDeep)
+}
+
+func TestVerifier_ReconstructHistory_RefusesUnverifiable(t *testing.T) {
+	alice, app := newSigner(t), newSigner(t)
+	dir := tracecore_signing.NewStaticDirectory()
+	dir.AddActor("alice", alice.pub)
+	v := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub)
+
+	good := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice"})
+	forged := signedEnvelope(t, alice, newSigner(t), tracecore_models.Actor{ID: "alice"})
+
+	history, err := v.ReconstructHistory([]tracecore_models.CommitEnvelope{good, forged, good})
+	require.NoError(t, err)
+
+	assert.Len(t, history.Commits, 2)
+	require.Len(t, history.Rejected, 1)
+	assert.Equal(t, 1, history.Rejected[0].Index)
+	assert.Contains(t, history.Rejected[0].Reason, "envelope")
+
+	_, err = tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, "").ReconstructHistory(nil)
+	assert.ErrorIs(t, err, tracecore_signing.ErrAppKeyNotConfigured)
+}
+
+// legacyEnvelope signs the way commits were signed before canonicalisation:
+// the actor over an unrecorded message, the app over json.Marshal(payload).
+func legacyEnvelope(t *testing.T, actor, app signer, a tracecore_models.Actor) tracecore_models.CommitEnvelope {
+	cp := tracecore_models.CommitPayload{
+		RepoID:   "repo-1",
+		Branch:   "main",
+		Metadata: tracecore_models.CommitMetadata{Message: "create entry", Actor: a},
+	}
+	sig := actor.sign([]byte("user-1:login:1700000000"))
+	cp.Metadata.Actor.Signature = sig
+	cp.Metadata.Signature = sig
+
+	appBytes, err := tracecore_signing.LegacyPayload(cp)
+	require.NoError(t, err)
+	return tracecore_models.CommitEnvelope{Commit: cp, Signature: app.sign(appBytes)}
+}
+
+func TestVerifier_LegacySignatures(t *testing.T) {
+	alice, app := newSigner(t), newSigner(t)
+	dir := tracecore_signing.NewStaticDirectory()
+	dir.AddActor("alice", alice.pub)
+
+	legacy := legacyEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice"})
+	current := signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice"})
+
+	// refused unless legacy acceptance is turned on
+	strict := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub)
+	assert.ErrorIs(t, strict.VerifyEnvelope(legacy), tracecore_signing.ErrInvalidSignature)
+
+	v := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub).WithLegacySignatures()
+	// a lone envelope cannot be placed before the cutoff
+	assert.ErrorIs(t, v.VerifyEnvelope(legacy), tracecore_signing.ErrInvalidSignature)
+
+	// a current-format commit with a forged actor signature does not pass as legacy
+	forged := current
+	forged.Commit.Metadata.Actor.Signature = newSigner(t).sign([]byte("x"))
+	forged.Commit.Metadata.Signature = forged.Commit.Metadata.Actor.Signature
+	assert.ErrorIs(t, v.VerifyEnvelope(forged), tracecore_signing.ErrInvalidSignature)
+
+	unknown := legacyEnvelope(t, alice, app, tracecore_models.Actor{ID: "mallory"})
+
+	history, err := v.ReconstructHistory([]tracecore_models.CommitEnvelope{legacy, unknown, current})
+	require.NoError(t, err)
+	assert.Len(t, history.Commits, 2)
+	assert.Equal(t, []int{0}, history.Legacy)
+	require.Len(t, history.Rejected, 1)
+	assert.Equal(t, 1, history.Rejected[0].Index)
+
+	// once the branch has a canonical commit, legacy ones are refused
+	history, err = v.ReconstructHistory([]tracecore_models.CommitEnvelope{legacy, current, legacy})
+	require.NoError(t, err)
+	assert.Len(t, history.Commits, 2)
+	assert.Equal(t, []int{0}, history.Legacy)
+	require.Len(t, history.Rejected, 1)
+	assert.Equal(t, 2, history.Rejected[0].Index)
+	assert.Contains(t, history.Rejected[0].Reason, tracecore_signing.ErrLegacyAfterCutoff.Error())
+}
+
+func TestNewRepoDirectory_LoadsActorsAndLiveGrants(t *testing.T) {
+	alice, app := newSigner(t), newSigner(t)
+	revokedAt := time.Now()
+	dir := tracecore_signing.NewRepoDirectory(
+		[]tracecore_models.RepoActor{{ID: "alice", PublicKey: alice.pub}, {ID: "no-key"}},
+		[]tracecore_models.DelegationGrant{
+			{Delegate: "alice", Delegator: "org-acme"},
+			{Delegate: "org-acme", Delegator: "org-parent", RevokedAt: &revokedAt},
+		},
+	)
+	v := tracecore_signing.NewVerifier(hexKeyVerifier{}, dir, app.pub)
+
+	assert.NoError(t, v.VerifyEnvelope(signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", DelegatedFrom: "org-acme"})))
+	assert.ErrorIs(t, v.VerifyEnvelope(signedEnvelope(t, alice, app, tracecore_models.Actor{ID: "alice", DelegatedFrom: "org-parent"})), tracecore_signing.ErrDelegationBroken)
+
+	_, err := dir.PublicKey("no-key")
+	assert.ErrorIs(t, err, tracecore_signing.ErrUnknownActor)
+}
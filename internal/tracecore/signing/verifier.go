@@ -0,0 +1,295 @@
+package tracecore_signing
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	tracecore_models "vault-app/internal/tracecore/models"
+)
+
+var (
+	ErrUnknownActor          = errors.New("unknown actor")
+	ErrMissingSignature      = errors.New("missing signature")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrSignatureMismatch     = errors.New("actor and metadata signatures differ")
+	ErrDelegationBroken      = errors.New("delegation chain does not reach delegated_from")
+	ErrDelegationTooDeep     = errors.New("delegation chain too deep")
+	ErrAppKeyNotConfigured   = errors.New("app public key not configured")
+	ErrLegacyAfterCutoff     = errors.New("legacy signature after the branch switched to canonical signatures")
+	ErrCommitNotVerifiable   = errors.New("commit signature verification failed")
+	ErrVerifierMisconfigured = errors.New("verifier is missing dependencies")
+)
+
+// DefaultMaxDelegationDepth bounds delegation chains, which also guards
+// against cycles in the directory.
+const DefaultMaxDelegationDepth = 8
+
+// KeyVerifier checks a raw signature against a public key. The Stellar
+// implementation lives in the blockchain package.
+type KeyVerifier interface {
+	Verify(publicKey string, message, signature []byte) error
+}
+
+// Verifier checks both signature layers of a CommitEnvelope: the actor's
+// signature over ActorSigningBytes, and the app signature over
+// CanonicalPayload.
+type Verifier struct {
+	Keys               KeyVerifier
+	Directory          ActorDirectory
+	AppPublicKey       string
+	MaxDelegationDepth int
+	// AcceptLegacy admits commits signed before payloads were canonicalised,
+	// up to the first canonical commit of a history (see
+	// WithLegacySignatures).
+	AcceptLegacy bool
+}
+
+func NewVerifier(keys KeyVerifier, directory ActorDirectory, appPublicKey string) *Verifier {
+	return &Verifier{
+		Keys:               keys,
+		Directory:          directory,
+		AppPublicKey:       appPublicKey,
+		MaxDelegationDepth: DefaultMaxDelegationDepth,
+	}
+}
+
+// WithLegacySignatures admits commits in the legacy format: the app
+// signature covers the json.Marshal form of the payload, and the actor
+// signature a message that was never recorded with the commit. Such commits
+// rest on the app signature and a known actor, so they are only accepted in
+// ReconstructHistory and only before the branch's first canonical commit:
+// nothing has been signed the legacy way since. They are listed in
+// History.Legacy.
+func (v *Verifier) WithLegacySignatures() *Verifier {
+	v.AcceptLegacy = true
+	return v
+}
+
+func (v *Verifier) ValidateDependencies() error {
+	if v.Keys == nil || v.Directory == nil {
+		return ErrVerifierMisconfigured
+	}
+	if v.AppPublicKey == "" {
+		return ErrAppKeyNotConfigured
+	}
+	return nil
+}
+
+// VerificationError collects every failed check of one envelope. It unwraps
+// to ErrCommitNotVerifiable and to each underlying cause.
+type VerificationError struct {
+	Failures []error
+}
+
+func (e *VerificationError) Error() string {
+	msgs := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrCommitNotVerifiable, strings.Join(msgs, "; "))
+}
+
+func (e *VerificationError) Unwrap() []error {
+	return append([]error{ErrCommitNotVerifiable}, e.Failures...)
+}
+
+// VerifyEnvelope returns nil when the actor signature, the app signature and
+// the delegation chain all check out. A lone envelope cannot be placed
+// before the legacy cutoff, so only the current format is accepted.
+func (v *Verifier) VerifyEnvelope(env tracecore_models.CommitEnvelope) error {
+	if err := v.ValidateDependencies(); err != nil {
+		return err
+	}
+	_, err := v.verify(env, false)
+	return err
+}
+
+// verify checks env in the current format and, when legacy is true, falls
+// back to the legacy one. It reports whether env was accepted as legacy.
+func (v *Verifier) verify(env tracecore_models.CommitEnvelope, legacy bool) (bool, error) {
+	err := v.verifyCurrent(env)
+	if err == nil || !legacy {
+		return false, err
+	}
+	if v.verifyLegacy(env) != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (v *Verifier) verifyCurrent(env tracecore_models.CommitEnvelope) error {
+	var failures []error
+	if err := v.verifyActor(env.Commit); err != nil {
+		failures = append(failures, err)
+	}
+	if err := v.verifyApp(env); err != nil {
+		failures = append(failures, err)
+	}
+	if err := v.verifyDelegation(env.Commit.Metadata.Actor); err != nil {
+		failures = append(failures, err)
+	}
+
+	if len(failures) > 0 {
+		return &VerificationError{Failures: failures}
+	}
+	return nil
+}
+
+func (v *Verifier) verifyActor(cp tracecore_models.CommitPayload) error {
+	actor := cp.Metadata.Actor
+	if actor.Signature == "" {
+		return fmt.Errorf("actor %q: %w", actor.ID, ErrMissingSignature)
+	}
+	if cp.Metadata.Signature != "" && cp.Metadata.Signature != actor.Signature {
+		return fmt.Errorf("actor %q: %w", actor.ID, ErrSignatureMismatch)
+	}
+
+	pub, err := v.Directory.PublicKey(actor.ID)
+	if err != nil {
+		return fmt.Errorf("actor %q: %w", actor.ID, err)
+	}
+
+	msg, err := ActorSigningBytes(cp)
+	if err != nil {
+		return err
+	}
+
+	if err := v.check(pub, msg, actor.Signature); err != nil {
+		return fmt.Errorf("actor %q: %w", actor.ID, err)
+	}
+	return nil
+}
+
+// verifyLegacy accepts a legacy commit whose app signature covers the
+// json.Marshal payload. The canonical form never matches those bytes, so a
+// current-format commit cannot pass here.
+func (v *Verifier) verifyLegacy(env tracecore_models.CommitEnvelope) error {
+	actor := env.Commit.Metadata.Actor
+	if actor.Signature == "" || env.Signature == "" {
+		return ErrMissingSignature
+	}
+	if env.Commit.Metadata.Signature != "" && env.Commit.Metadata.Signature != actor.Signature {
+		return ErrSignatureMismatch
+	}
+	if _, err := v.Directory.PublicKey(actor.ID); err != nil {
+		return err
+	}
+
+	msg, err := LegacyPayload(env.Commit)
+	if err != nil {
+		return err
+	}
+	if err := v.check(v.AppPublicKey, msg, env.Signature); err != nil {
+		return err
+	}
+	return v.verifyDelegation(actor)
+}
+
+func (v *Verifier) verifyApp(env tracecore_models.CommitEnvelope) error {
+	if env.Signature == "" {
+		return fmt.Errorf("envelope: %w", ErrMissingSignature)
+	}
+
+	msg, err := CanonicalPayload(env.Commit)
+	if err != nil {
+		return err
+	}
+
+	if err := v.check(v.AppPublicKey, msg, env.Signature); err != nil {
+		return fmt.Errorf("envelope: %w", err)
+	}
+	return nil
+}
+
+// verifyDelegation walks the directory's delegator links from the actor
+// until it reaches Actor.DelegatedFrom.
+func (v *Verifier) verifyDelegation(actor tracecore_models.Actor) error {
+	if actor.DelegatedFrom == "" || actor.DelegatedFrom == actor.ID {
+		return nil
+	}
+
+	maxDepth := v.MaxDelegationDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDelegationDepth
+	}
+
+	current := actor.ID
+	for depth := 0; depth < maxDepth; depth++ {
+		next, err := v.Directory.Delegator(current)
+		if err != nil {
+			return fmt.Errorf("delegation of %q: %w", current, err)
+		}
+		if next == "" {
+			return fmt.Errorf("actor %q for %q: %w", actor.ID, actor.DelegatedFrom, ErrDelegationBroken)
+		}
+		if next == actor.DelegatedFrom {
+			return nil
+		}
+		current = next
+	}
+
+	return fmt.Errorf("actor %q for %q: %w", actor.ID, actor.DelegatedFrom, ErrDelegationTooDeep)
+}
+
+func (v *Verifier) check(publicKey string, msg []byte, signatureB64 string) error {
+	sig, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := v.Keys.Verify(publicKey, msg, sig); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// RejectedCommit is a commit refused during history reconstruction.
+type RejectedCommit struct {
+	Index    int                             `json:"index"`
+	Envelope tracecore_models.CommitEnvelope `json:"envelope"`
+	Reason   string                          `json:"reason"`
+}
+
+// History is a commit history split into verified and refused commits.
+// Legacy holds the input positions of commits accepted in the legacy format.
+type History struct {
+	Commits  []tracecore_models.CommitEnvelope `json:"commits"`
+	Rejected []RejectedCommit                  `json:"rejected"`
+	Legacy   []int                             `json:"legacy"`
+}
+
+// ReconstructHistory keeps only the commits whose signatures verify. envs
+// are oldest first; once one of them verifies in the current format, later
+// legacy commits are refused. Refused commits are reported with their
+// position and reason and never make it into the reconstructed history.
+func (v *Verifier) ReconstructHistory(envs []tracecore_models.CommitEnvelope) (*History, error) {
+	if err := v.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+
+	history := &History{
+		Commits:  make([]tracecore_models.CommitEnvelope, 0, len(envs)),
+		Rejected: []RejectedCommit{},
+		Legacy:   []int{},
+	}
+	legacyOpen := v.AcceptLegacy
+	for i, env := range envs {
+		legacy, err := v.verify(env, legacyOpen)
+		if err != nil && v.AcceptLegacy && !legacyOpen && v.verifyLegacy(env) == nil {
+			err = fmt.Errorf("%w: %v", ErrLegacyAfterCutoff, err)
+		}
+		if err != nil {
+			history.Rejected = append(history.Rejected, RejectedCommit{Index: i, Envelope: env, Reason: err.Error()})
+			continue
+		}
+		if legacy {
+			history.Legacy = append(history.Legacy, i)
+		} else {
+			legacyOpen = false
+		}
+		history.Commits = append(history.Commits, env)
+	}
+
+	return history, nil
+}
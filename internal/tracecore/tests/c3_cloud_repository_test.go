@@ -0,0 +1,91 @@
+package tracecore_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	c3_asset_domain "vault-app/internal/c3_asset/domain"
+	tracecore "vault-app/internal/tracecore"
+)
+
+func TestListShareEntriesByTrustGroup_QueriesByGroup(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/c3/share-entries" || r.URL.Query().Get("trust_group_id") != "tg 1" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":200,"data":[{"id":"se_1","trust_group_id":"tg 1","kek_version":2,"status":"active"}]}`)
+	})
+
+	entries, err := tracecore.NewCloudShareEntryRepository(client).ListShareEntriesByTrustGroup(context.Background(), "tg 1")
+	if err != nil {
+		t.Fatalf("ListShareEntriesByTrustGroup returned error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "se_1" || entries[0].KEKVersion != 2 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestListShareEntriesByTrustGroup_SurfacesCloudErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	if _, err := tracecore.NewCloudShareEntryRepository(client).ListShareEntriesByTrustGroup(context.Background(), "tg_1"); err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+}
+
+func TestUpdateShareEntry_PutsEntry(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/c3/share-entries/se_1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body c3_asset_domain.ShareEntry
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if body.WrappedDEK != "rewrapped" || body.KEKVersion != 3 {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"status": 200, "data": body})
+	})
+
+	resp, err := tracecore.NewCloudShareEntryRepository(client).UpdateShareEntry(context.Background(), &c3_asset_domain.UpdateShareEntryRequest{
+		ShareEntry: c3_asset_domain.ShareEntry{ID: "se_1", WrappedDEK: "rewrapped", KEKVersion: 3},
+	})
+	if err != nil {
+		t.Fatalf("UpdateShareEntry returned error: %v", err)
+	}
+	if resp.Data.KEKVersion != 3 {
+		t.Errorf("KEKVersion = %d, want 3", resp.Data.KEKVersion)
+	}
+}
+
+func TestListTrustGroupsForMember_QueriesByMember(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/trustgroups" || r.URL.Query().Get("member_id") != "vault_001" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":200,"data":[{"id":"tg_1","kek_version":4,"member_cids":["vault_001","vault_002"]}]}`)
+	})
+
+	groups, err := client.ListTrustGroupsForMember(context.Background(), "vault_001")
+	if err != nil {
+		t.Fatalf("ListTrustGroupsForMember returned error: %v", err)
+	}
+	if len(groups) != 1 || groups[0].ID != "tg_1" || groups[0].KEKVersion != 4 {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if !groups[0].IsMember("vault_001") {
+		t.Error("member list was lost during decoding")
+	}
+}
@@ -0,0 +1,61 @@
+package tracecore_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	channel_domain "vault-app/internal/channel/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+func TestAppendChannelEvent_PostsToChannelLog(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/channels/ch_1/events" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body tracecore_types.CloudChannelEvent
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if body.Type != channel_domain.ChannelEventPermissionGranted || body.IdempotencyKey != "key-1" || string(body.Payload) != `{"vault_id":"v2"}` {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		body.ID, body.Cursor = "evt_1", 4
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"status": 201, "data": body})
+	})
+
+	resp, err := client.AppendChannelEvent(context.Background(), &channel_domain.AppendChannelEventRequest{
+		ChannelID:      "ch_1",
+		Type:           channel_domain.ChannelEventPermissionGranted,
+		Payload:        json.RawMessage(`{"vault_id":"v2"}`),
+		IdempotencyKey: "key-1",
+	})
+	if err != nil {
+		t.Fatalf("AppendChannelEvent returned error: %v", err)
+	}
+	if resp.Data.ID != "evt_1" || resp.Data.Cursor != 4 || resp.Data.ChannelID != "ch_1" {
+		t.Errorf("unexpected event: %+v", resp.Data)
+	}
+}
+
+func TestListChannelEvents_MapsLog(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/channels/ch_1/events" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":200,"data":[{"ID":"evt_1","ChannelID":"ch_1","Type":"slot.occupancy_requested","Payload":{"id":"req-1"},"Cursor":1}]}`)
+	})
+
+	resp, err := client.ListChannelEvents(context.Background(), &channel_domain.ListChannelEventsRequest{ChannelID: "ch_1"})
+	if err != nil {
+		t.Fatalf("ListChannelEvents returned error: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Type != channel_domain.ChannelEventOccupancyRequested || string(resp.Data[0].Payload) != `{"id":"req-1"}` {
+		t.Fatalf("unexpected events: %+v", resp.Data)
+	}
+}
@@ -0,0 +1,64 @@
+package tracecore_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	identity_domain "vault-app/internal/identity/domain"
+	tracecore "vault-app/internal/tracecore"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+func TestCloudDeviceRepository_ListAndNotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/identity/devices" && r.URL.Query().Get("vault_id") == "vault-1":
+			_ = json.NewEncoder(w).Encode(tracecore_types.CloudResponse[[]tracecore_types.CloudDevice]{
+				Status: http.StatusOK,
+				Data:   []tracecore_types.CloudDevice{{ID: "dev-1", VaultID: "vault-1", Status: identity_domain.DeviceStatusActive}},
+			})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+	repo := tracecore.NewCloudDeviceRepository(client)
+
+	devices, err := repo.ListByVaultID(context.Background(), "vault-1")
+	if err != nil {
+		t.Fatalf("ListByVaultID: %v", err)
+	}
+	if len(devices) != 1 || devices[0].ID != "dev-1" || !devices[0].IsActive() {
+		t.Fatalf("unexpected devices: %+v", devices)
+	}
+
+	if _, err := repo.FindByID(context.Background(), "missing"); !errors.Is(err, identity_domain.ErrDeviceNotFound) {
+		t.Fatalf("expected ErrDeviceNotFound, got %v", err)
+	}
+}
+
+func TestCloudDeviceEnrollmentRepository_KeepsCodeHash(t *testing.T) {
+	var saved tracecore_types.CloudDeviceEnrollment
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/identity/device-enrollments" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	enrollment, _, err := identity_domain.NewDeviceEnrollment("vault-1", "dev-2", 0)
+	if err != nil {
+		t.Fatalf("NewDeviceEnrollment: %v", err)
+	}
+
+	if err := tracecore.NewCloudDeviceEnrollmentRepository(client).Save(context.Background(), enrollment); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if saved.CodeHash == "" || saved.CodeHash != enrollment.CodeHash {
+		t.Fatalf("code hash not sent: %+v", saved)
+	}
+}
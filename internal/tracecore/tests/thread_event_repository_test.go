@@ -152,3 +152,33 @@ func TestThreadEvents_Repository_VerticalSlice(t *testing.T) {
 		t.Errorf("listed event Payload mismatch: %+v", listedEvt.Payload)
 	}
 }
+
+func TestAppendThreadEvent_SendsSignedLink(t *testing.T) {
+	var receivedBody map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&receivedBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":201,"data":{"id":"evt-2","thread_id":"th-1","type":"invoice.sent","previous_event_id":"evt-1","cursor":2,"signature":"c2ln"}}`)
+	})
+
+	prev := "evt-1"
+	resp, err := client.AppendThreadEvent(context.Background(), &thread_domain.AppendThreadEventRequest{
+		ThreadID:        "th-1",
+		EventType:       "invoice.sent",
+		IdempotencyKey:  "key-2",
+		Headers:         map[string]string{thread_domain.HeaderAuthorDeviceID: "dev-1"},
+		PreviousEventID: &prev,
+		Signature:       "c2ln",
+	})
+	if err != nil {
+		t.Fatalf("AppendThreadEvent failed: %v", err)
+	}
+	if receivedBody["previous_event_id"] != "evt-1" || receivedBody["signature"] != "c2ln" {
+		t.Errorf("signed link not sent: %v", receivedBody)
+	}
+	if resp.Data.Signature != "c2ln" || resp.Data.PreviousEventID == nil || *resp.Data.PreviousEventID != "evt-1" {
+		t.Errorf("unexpected event: %+v", resp.Data)
+	}
+}
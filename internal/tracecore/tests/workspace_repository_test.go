@@ -0,0 +1,82 @@
+package tracecore_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	tracecore_types "vault-app/internal/tracecore/types"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+func TestGetWorkspace_MapsArchiveAndMembers(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/workspaces/ws_1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":200,"data":{"ID":"ws_1","VaultID":"vault_001","Name":"Ops","Status":"archived",
+			"ArchivedAt":"2026-08-18T14:00:00Z","Members":[{"VaultID":"vault_002","Role":"viewer","AddedBy":"vault_001"}]}}`)
+	})
+
+	ws, err := client.GetWorkspace(context.Background(), workspace_domain.GetRequest{WorkspaceID: "ws_1"})
+	if err != nil {
+		t.Fatalf("GetWorkspace returned error: %v", err)
+	}
+	if ws.ID != "ws_1" || ws.VaultID != "vault_001" || ws.Name != "Ops" {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	if ws.ArchivedAt == nil {
+		t.Error("ArchivedAt was lost during mapping")
+	}
+	if role, ok := ws.RoleOf("vault_002"); !ok || role != workspace_domain.WorkspaceRoleViewer {
+		t.Errorf("member role = %q, %v; want viewer", role, ok)
+	}
+}
+
+func TestGetWorkspace_SurfacesCloudErrors(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	if _, err := client.GetWorkspace(context.Background(), workspace_domain.GetRequest{WorkspaceID: "ws_missing"}); err == nil {
+		t.Fatal("expected an error for a 404 response")
+	}
+}
+
+func TestUpdateWorkspace_PutsWorkspace(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/workspaces/ws_1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body tracecore_types.CloudWorkspaceDTO
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		if body.Name != "Renamed" || len(body.Members) != 1 || body.Members[0].Role != "admin" {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		body.Description = "stored"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"status": 200, "data": body})
+	})
+
+	resp, err := client.UpdateWorkspace(context.Background(), workspace_domain.UpdateRequest{
+		Workspace: workspace_domain.Workspace{
+			ID:      "ws_1",
+			Name:    "Renamed",
+			Members: []workspace_domain.WorkspaceMember{{VaultID: "vault_002", Role: workspace_domain.WorkspaceRoleAdmin}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkspace returned error: %v", err)
+	}
+	if resp.Data.Description != "stored" {
+		t.Errorf("UpdateWorkspace returned its input instead of the Cloud copy: %+v", resp.Data)
+	}
+	if len(resp.Data.Members) != 1 {
+		t.Errorf("members = %+v, want one", resp.Data.Members)
+	}
+}
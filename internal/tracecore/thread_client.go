@@ -8,6 +8,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	tracecore_types "vault-app/internal/tracecore/types"
 )
@@ -78,12 +80,16 @@ func (c *TracecoreClient) ListThreadsDirect(
 	ctx context.Context,
 	userID string,
 	channelID string,
+	updatedSince ...time.Time,
 ) ([]tracecore_types.ThreadDTO, error) {
 	baseURL := c.AnkhoraCloudUrl
 	if baseURL == "" {
 		baseURL = c.BaseURL
 	}
 	url := baseURL + "/threads/by-channel/" + channelID
+	if len(updatedSince) > 0 && !updatedSince[0].IsZero() {
+		url += "?updated_since=" + updatedSinceParam(updatedSince[0])
+	}
 
 	log.Printf("[THREAD LIST] AnkhoraCloudUrl = %s", c.AnkhoraCloudUrl)
 	log.Printf("[THREAD LIST] BaseURL = %s", c.BaseURL)
@@ -145,12 +151,18 @@ func (c *TracecoreClient) ListThreadsDirect(
 	return []tracecore_types.ThreadDTO{}, nil
 }
 
-func (c *TracecoreClient) ListThreadEventsDirect(ctx context.Context, userID string, threadID string) ([]tracecore_types.ThreadEventDTO, error) {
+// ListThreadEventsDirect lists thread events (GET /threads/{id}/events). An
+// optional afterCursor asks Cloud only for events with a greater cursor, for
+// incremental refresh of the local cache.
+func (c *TracecoreClient) ListThreadEventsDirect(ctx context.Context, userID string, threadID string, afterCursor ...uint64) ([]tracecore_types.ThreadEventDTO, error) {
 	baseURL := c.AnkhoraCloudUrl
 	if baseURL == "" {
 		baseURL = c.BaseURL
 	}
 	url := baseURL + "/threads/" + threadID + "/events"
+	if len(afterCursor) > 0 && afterCursor[0] > 0 {
+		url += "?after_cursor=" + strconv.FormatUint(afterCursor[0], 10)
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -187,6 +199,12 @@ func (c *TracecoreClient) ListThreadEventsDirect(ctx context.Context, userID str
 }
 
 func (c *TracecoreClient) AppendThreadEventDirect(ctx context.Context, userID string, threadID string, eventType string, payload map[string]interface{}, idempotencyKey string) (*tracecore_types.ThreadEventDTO, error) {
+	return c.AppendThreadEventWithHeadersDirect(ctx, userID, threadID, eventType, payload, idempotencyKey, nil)
+}
+
+// AppendThreadEventWithHeadersDirect is AppendThreadEventDirect with event
+// headers recorded alongside the payload (e.g. lifecycle actor and reason).
+func (c *TracecoreClient) AppendThreadEventWithHeadersDirect(ctx context.Context, userID string, threadID string, eventType string, payload map[string]interface{}, idempotencyKey string, headers map[string]string) (*tracecore_types.ThreadEventDTO, error) {
 	reqPayload := map[string]interface{}{
 		"type":      eventType,
 		"thread_id": threadID,
@@ -195,6 +213,14 @@ func (c *TracecoreClient) AppendThreadEventDirect(ctx context.Context, userID st
 	if idempotencyKey != "" {
 		reqPayload["idempotency_key"] = idempotencyKey
 	}
+	if len(headers) > 0 {
+		reqPayload["headers"] = headers
+	}
+	return c.postThreadEvent(ctx, threadID, reqPayload)
+}
+
+// postThreadEvent sends an append body to POST /threads/{id}/events.
+func (c *TracecoreClient) postThreadEvent(ctx context.Context, threadID string, reqPayload map[string]interface{}) (*tracecore_types.ThreadEventDTO, error) {
 	body, err := json.Marshal(reqPayload)
 	if err != nil {
 		return nil, err
@@ -239,3 +265,58 @@ func (c *TracecoreClient) AppendThreadEventDirect(ctx context.Context, userID st
 
 	return nil, fmt.Errorf("unexpected response shape from Cloud POST /api/threads/%s/events: %s", threadID, string(respBytes))
 }
+
+// UpdateThreadDirect persists the mutable thread fields (title, subtitle and
+// lifecycle state) via PATCH /threads/{id}.
+func (c *TracecoreClient) UpdateThreadDirect(ctx context.Context, thread tracecore_types.ThreadDTO) (*tracecore_types.ThreadDTO, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"title":       thread.Title,
+		"subtitle":    thread.Subtitle,
+		"status":      thread.Status,
+		"closed_at":   thread.ClosedAt,
+		"transfer_to": thread.TransferTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := c.AnkhoraCloudUrl
+	if baseURL == "" {
+		baseURL = c.BaseURL
+	}
+	url := baseURL + "/threads/" + thread.ID
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Cloud backend returned status %d: %s", resp.StatusCode, string(respBytes))
+	}
+
+	var cloudResp tracecore_types.CloudResponse[tracecore_types.ThreadDTO]
+	if err := json.Unmarshal(respBytes, &cloudResp); err == nil && cloudResp.Data.ID != "" {
+		return &cloudResp.Data, nil
+	}
+
+	var updated tracecore_types.ThreadDTO
+	if err := json.Unmarshal(respBytes, &updated); err == nil && updated.ID != "" {
+		return &updated, nil
+	}
+
+	return nil, fmt.Errorf("unexpected response shape from Cloud PATCH /api/threads/%s: %s", thread.ID, string(respBytes))
+}
@@ -1,6 +1,9 @@
 package tracecore_types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // The Cloud backend marshals its Channel aggregates with default Go JSON
 // encoding (capitalized field names). These DTOs mirror that wire format so
@@ -80,4 +83,18 @@ type CloudChannelInvitation struct {
 	Status         string     `json:"Status"`
 	CreatedAt      time.Time  `json:"CreatedAt"`
 	AcceptedAt     *time.Time `json:"AcceptedAt"`
+	ExpiresAt      *time.Time `json:"ExpiresAt"`
+}
+
+// CloudChannelEvent mirrors an entry of the Cloud channel event log,
+// marshalled with default Go JSON encoding (capitalized field names).
+type CloudChannelEvent struct {
+	ID             string          `json:"ID"`
+	ChannelID      string          `json:"ChannelID"`
+	Type           string          `json:"Type"`
+	ActorVaultID   string          `json:"ActorVaultID"`
+	Payload        json.RawMessage `json:"Payload"`
+	IdempotencyKey string          `json:"IdempotencyKey"`
+	Cursor         uint64          `json:"Cursor"`
+	CreatedAt      time.Time       `json:"CreatedAt"`
 }
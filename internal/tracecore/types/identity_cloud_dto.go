@@ -0,0 +1,33 @@
+package tracecore_types
+
+import "time"
+
+// CloudDevice mirrors a device record of the Cloud identity store, shared
+// by every installation of a vault.
+type CloudDevice struct {
+	ID         string     `json:"ID"`
+	VaultID    string     `json:"VaultID"`
+	PublicKey  string     `json:"PublicKey"`
+	KeyType    string     `json:"KeyType"`
+	Status     string     `json:"Status"`
+	Name       string     `json:"Name"`
+	CreatedAt  time.Time  `json:"CreatedAt"`
+	RevokedAt  *time.Time `json:"RevokedAt,omitempty"`
+	LastSeenAt *time.Time `json:"LastSeenAt,omitempty"`
+}
+
+// CloudDeviceEnrollment mirrors a pending device approval. CodeHash travels
+// to the Cloud so an approver on another installation can check the code;
+// the plaintext code never leaves the new device.
+type CloudDeviceEnrollment struct {
+	ID               string     `json:"ID"`
+	VaultID          string     `json:"VaultID"`
+	DeviceID         string     `json:"DeviceID"`
+	CodeHash         string     `json:"CodeHash"`
+	Status           string     `json:"Status"`
+	Attempts         int        `json:"Attempts"`
+	ApproverDeviceID string     `json:"ApproverDeviceID"`
+	CreatedAt        time.Time  `json:"CreatedAt"`
+	ExpiresAt        time.Time  `json:"ExpiresAt"`
+	ResolvedAt       *time.Time `json:"ResolvedAt,omitempty"`
+}
@@ -317,12 +317,21 @@ type Workspace struct {
 	Description string `json:"description"`
 	Status      string `json:"status"`
 
-	OwnerID string `json:"owner_id"`
+	OwnerID string            `json:"owner_id"`
+	Members []WorkspaceMember `json:"members,omitempty" gorm:"-"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	IsDraft   bool      `json:"is_draft"`
-	IsDirty   bool      `json:"is_dirty" gorm:"boolean"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	IsDraft    bool       `json:"is_draft"`
+	IsDirty    bool       `json:"is_dirty" gorm:"boolean"`
+}
+
+type WorkspaceMember struct {
+	VaultID string    `json:"vault_id"`
+	Role    string    `json:"role"`
+	AddedBy string    `json:"added_by"`
+	AddedAt time.Time `json:"added_at"`
 }
 
 type ChannelDTO struct {
@@ -383,17 +392,20 @@ type ChannelInvitationDTO struct {
 	Status         string     `json:"status"`
 	CreatedAt      time.Time  `json:"created_at"`
 	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
 }
 
 type ThreadDTO struct {
-	ID          string    `json:"id"`
-	ChannelID   string    `json:"channel_id"`
-	WorkspaceID string    `json:"workspace_id,omitempty"`
-	AssetType   string    `json:"asset_type"`
-	Title       string    `json:"title"`
-	Subtitle    string    `json:"subtitle"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string     `json:"id"`
+	ChannelID   string     `json:"channel_id"`
+	WorkspaceID string     `json:"workspace_id,omitempty"`
+	AssetType   string     `json:"asset_type"`
+	Title       string     `json:"title"`
+	Subtitle    string     `json:"subtitle"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	TransferTo  string     `json:"transfer_to,omitempty"`
 }
 
 type ThreadEventDTO struct {
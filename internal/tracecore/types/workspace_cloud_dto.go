@@ -17,6 +17,17 @@ type CloudWorkspaceDTO struct {
 	UpdatedAt   time.Time `json:"UpdatedAt"`
 	IsDraft     bool      `json:"IsDraft"`
 	IsDirty     bool      `json:"IsDirty"`
+
+	ArchivedAt *time.Time                `json:"ArchivedAt,omitempty"`
+	Members    []CloudWorkspaceMemberDTO `json:"Members,omitempty"`
+}
+
+// CloudWorkspaceMemberDTO mirrors a Cloud workspace member entry.
+type CloudWorkspaceMemberDTO struct {
+	VaultID string    `json:"VaultID"`
+	Role    string    `json:"Role"`
+	AddedBy string    `json:"AddedBy"`
+	AddedAt time.Time `json:"AddedAt"`
 }
 
 // MapCloudWorkspaceToTypes converts a CloudWorkspaceDTO into a tracecore_types.Workspace.
@@ -24,6 +35,10 @@ func MapCloudWorkspaceToTypes(dto *CloudWorkspaceDTO) *Workspace {
 	if dto == nil {
 		return nil
 	}
+	var members []WorkspaceMember
+	for _, m := range dto.Members {
+		members = append(members, WorkspaceMember{VaultID: m.VaultID, Role: m.Role, AddedBy: m.AddedBy, AddedAt: m.AddedAt})
+	}
 	return &Workspace{
 		ID:          dto.ID,
 		VaultID:     dto.VaultID,
@@ -33,6 +48,8 @@ func MapCloudWorkspaceToTypes(dto *CloudWorkspaceDTO) *Workspace {
 		OwnerID:     dto.OwnerID,
 		CreatedAt:   dto.CreatedAt,
 		UpdatedAt:   dto.UpdatedAt,
+		ArchivedAt:  dto.ArchivedAt,
+		Members:     members,
 		IsDraft:     dto.IsDraft,
 		IsDirty:     dto.IsDirty,
 	}
@@ -182,8 +182,11 @@ func (c *TracecoreClient) CreateWorkspaceDirect(ctx context.Context, vaultID str
 	}, nil
 }
 
-func (c *TracecoreClient) ListWorkspaces(ctx context.Context, vaultID string) ([]tracecore_types.Workspace, error) {
+func (c *TracecoreClient) ListWorkspaces(ctx context.Context, vaultID string, updatedSince ...time.Time) ([]tracecore_types.Workspace, error) {
 	url := c.AnkhoraCloudUrl + "/workspaces?vault_id=" + vaultID
+	if len(updatedSince) > 0 && !updatedSince[0].IsZero() {
+		url += "&updated_since=" + updatedSinceParam(updatedSince[0])
+	}
 	utils.LogPretty("[Workspace] Cloud GET URL", url)
 	log.Printf("[CLOUD-TRACE] LEDGER REQUEST: client_pointer=%p token_length=%d token_fingerprint=%s authorization_header=%v vault_id=%s",
 		c, len(c.Token), traceTokenFingerprint(c.Token), c.Token != "", vaultID)
@@ -175,37 +175,10 @@ func (o *TrustGroupCryptoOrchestrator) ResolveCollaborativeAsset(
 		return nil, errors.New("wrapped DEK cannot be empty")
 	}
 
-	// 1. Resolve KEK for TrustGroup + KEKVersion (Fast path: Keyring)
-	var kek []byte
-	if req.Keyring != nil && o.keyringService != nil {
-		k, err := o.keyringService.GetTrustGroupKEK(req.Keyring, req.TrustGroupID, req.KEKVersion)
-		if err == nil && len(k) == 32 {
-			kek = k
-		}
-	}
-
-	// 2. Slow path: Unwrap KEK using member device private seed
-	if len(kek) == 0 {
-		if req.WrappedKEK == "" {
-			return nil, errors.New("wrapped KEK envelope is required when KEK is not cached in keyring")
-		}
-		if req.DeviceSeed == "" {
-			return nil, errors.New("device private seed is required to unwrap KEK envelope")
-		}
-
-		unwrappedKEK, err := o.aesService.AsymetricDecrypt(req.DeviceSeed, req.WrappedKEK)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unwrap KEK envelope for device: %w", err)
-		}
-		if len(unwrappedKEK) != 32 {
-			return nil, errors.New("unwrapped KEK must be exactly 32 bytes")
-		}
-		kek = unwrappedKEK
-
-		// Cache recovered KEK in local VaultKeyring
-		if req.Keyring != nil && o.keyringService != nil {
-			_, _ = o.keyringService.StoreTrustGroupKEK(req.Keyring, req.TrustGroupID, req.KEKVersion, kek)
-		}
+	// 1-2. Resolve KEK: keyring fast path, device envelope slow path
+	kek, err := o.resolveKEK(req.Keyring, req.TrustGroupID, req.KEKVersion, req.WrappedKEK, req.DeviceSeed)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. Unwrap DEK using KEK (AES-256-GCM)
@@ -231,6 +204,136 @@ func (o *TrustGroupCryptoOrchestrator) ResolveCollaborativeAsset(
 	}, nil
 }
 
+// resolveKEK returns the KEK for a trust group version, from the keyring when
+// cached, otherwise by unwrapping the device envelope (and caching it).
+func (o *TrustGroupCryptoOrchestrator) resolveKEK(
+	keyring *vaults_domain.VaultKeyring,
+	trustGroupID string,
+	version uint64,
+	wrappedKEK string,
+	deviceSeed string,
+) ([]byte, error) {
+	if keyring != nil && o.keyringService != nil {
+		k, err := o.keyringService.GetTrustGroupKEK(keyring, trustGroupID, version)
+		if err == nil && len(k) == 32 {
+			return k, nil
+		}
+	}
+
+	if wrappedKEK == "" {
+		return nil, errors.New("wrapped KEK envelope is required when KEK is not cached in keyring")
+	}
+	if deviceSeed == "" {
+		return nil, errors.New("device private seed is required to unwrap KEK envelope")
+	}
+
+	kek, err := o.aesService.AsymetricDecrypt(deviceSeed, wrappedKEK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap KEK envelope for device: %w", err)
+	}
+	if len(kek) != 32 {
+		return nil, errors.New("unwrapped KEK must be exactly 32 bytes")
+	}
+
+	// Cache recovered KEK in local VaultKeyring
+	if keyring != nil && o.keyringService != nil {
+		_, _ = o.keyringService.StoreTrustGroupKEK(keyring, trustGroupID, version, kek)
+	}
+	return kek, nil
+}
+
+type RewrapDEKPayload struct {
+	TrustGroupID  string
+	OldVersion    uint64
+	NewVersion    uint64
+	WrappedDEK    []byte // AES-256-GCM(DEK, KEK vOld)
+	OldWrappedKEK string // Device envelope for vOld; optional when cached
+	NewWrappedKEK string // Device envelope for vNew; optional when cached
+	DeviceSeed    string
+	Keyring       *vaults_domain.VaultKeyring
+}
+
+// RewrapDEK moves a single DEK from an older KEK version to a newer one. It
+// backs the lazy share re-wrap policy, where entries are migrated one by one
+// as remaining members read them after a rotation.
+func (o *TrustGroupCryptoOrchestrator) RewrapDEK(
+	ctx context.Context,
+	req RewrapDEKPayload,
+) ([]byte, error) {
+	if req.TrustGroupID == "" {
+		return nil, errors.New("trust group ID is required")
+	}
+	if req.OldVersion == 0 || req.NewVersion <= req.OldVersion {
+		return nil, errors.New("new version must be greater than old version")
+	}
+	if len(req.WrappedDEK) == 0 {
+		return nil, errors.New("wrapped DEK cannot be empty")
+	}
+
+	oldKEK, err := o.resolveKEK(req.Keyring, req.TrustGroupID, req.OldVersion, req.OldWrappedKEK, req.DeviceSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve KEK v%d: %w", req.OldVersion, err)
+	}
+	newKEK, err := o.resolveKEK(req.Keyring, req.TrustGroupID, req.NewVersion, req.NewWrappedKEK, req.DeviceSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve KEK v%d: %w", req.NewVersion, err)
+	}
+
+	dek, err := o.aesService.Decrypt(req.WrappedDEK, oldKEK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap DEK with KEK v%d: %w", req.OldVersion, err)
+	}
+	reWrapped, err := o.aesService.Encrypt(dek, newKEK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-wrap DEK with KEK v%d: %w", req.NewVersion, err)
+	}
+	return reWrapped, nil
+}
+
+type WrapKEKForDevicePayload struct {
+	TrustGroupID string
+	KEKVersion   uint64
+	WrappedKEK   string // Approving device's envelope; optional when cached
+	DeviceSeed   string // Approving device's seed
+	Keyring      *vaults_domain.VaultKeyring
+	Device       ActiveDevice // Device being granted access
+}
+
+// WrapKEKForDevice hands an existing KEK to a newly trusted device: the
+// approving device recovers the KEK and seals it to the new device's key.
+func (o *TrustGroupCryptoOrchestrator) WrapKEKForDevice(
+	ctx context.Context,
+	req WrapKEKForDevicePayload,
+) (*trustgroup_dtos.AddTrustGroupKeyEnvelopeRequest, error) {
+	if req.TrustGroupID == "" {
+		return nil, errors.New("trust group ID is required")
+	}
+	if req.KEKVersion == 0 {
+		return nil, errors.New("KEK version is required")
+	}
+	if !req.Device.IsActive || req.Device.PublicKey == "" || req.Device.DeviceID == "" || req.Device.MemberID == "" {
+		return nil, errors.New("an active device with a public key is required")
+	}
+
+	kek, err := o.resolveKEK(req.Keyring, req.TrustGroupID, req.KEKVersion, req.WrappedKEK, req.DeviceSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKEKPayload, err := o.aesService.EncryptPayload(req.Device.PublicKey, kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap KEK for device %s: %w", req.Device.DeviceID, err)
+	}
+
+	return &trustgroup_dtos.AddTrustGroupKeyEnvelopeRequest{
+		TrustGroupID: req.TrustGroupID,
+		MemberID:     req.Device.MemberID,
+		DeviceID:     req.Device.DeviceID,
+		KEKVersion:   req.KEKVersion,
+		WrappedKEK:   wrappedKEKPayload.ToString(),
+	}, nil
+}
+
 type RotateCollaborativeAssetInput struct {
 	ShareEntryID string
 	WrappedDEK   []byte // WrappedDEK under KEK vN
@@ -13,6 +13,8 @@ type  TrustGroup struct {
 	KEKVersion  uint64 `json:"kek_version"`
 	MemberCIDs  []string `json:"member_cids"`
 	KeyEnvelopes []TrustGroupKeyEnvelope   `json:"key_envelopes"`
+	// ShareRewrapPolicy says when share-entry DEKs move to a rotated KEK.
+	ShareRewrapPolicy ShareRewrapPolicy `json:"share_rewrap_policy,omitempty"`
 	CreatedAt   string `json:"created_at"`
 	IsDraft     bool   `json:"is_draft"`
 	IsDirty     bool   `json:"is_dirty" gorm:"boolean"`
@@ -93,6 +95,8 @@ func (g *TrustGroup) RemoveMember(vaultID string) error {
 		return ErrTrustGroupMemberNotFound
 	}
 	g.MemberCIDs = updatedCIDs
+	// A removed member keeps no live envelope, whatever happens next.
+	g.RevokeMemberEnvelopes(vaultID, time.Now())
 	g.IsDirty = true
 	return nil
 }
@@ -101,6 +105,16 @@ func (g *TrustGroup) RotateKEK(newVersion uint64, newEnvelopes []TrustGroupKeyEn
 	if newVersion != g.KEKVersion+1 {
 		return ErrInvalidKEKVersionIncrement
 	}
+	// Check every envelope before touching state: the new KEK only ever
+	// goes to current members.
+	for _, env := range newEnvelopes {
+		if env.KEKVersion != newVersion {
+			return ErrStaleKEKVersion
+		}
+		if !g.IsMember(env.MemberID) {
+			return ErrMemberNotInTrustGroup
+		}
+	}
 	now := time.Now()
 	// Mark all existing active envelopes as revoked
 	for i := range g.KeyEnvelopes {
@@ -110,9 +124,6 @@ func (g *TrustGroup) RotateKEK(newVersion uint64, newEnvelopes []TrustGroupKeyEn
 	}
 	g.KEKVersion = newVersion
 	for _, env := range newEnvelopes {
-		if env.KEKVersion != g.KEKVersion {
-			return ErrStaleKEKVersion
-		}
 		if env.ID == "" {
 			env.ID = uuid.NewString()
 		}
@@ -27,6 +27,9 @@ var (
 	ErrDeviceMemberMismatch = errors.New("device does not belong to expected member or vault")
 	ErrMemberNotInTrustGroup = errors.New("member does not belong to trust group")
 	ErrInvalidKEKVersionIncrement = errors.New("invalid KEK version increment: must be N+1")
+	ErrShareRewrapPolicyInvalid = errors.New("share rewrap policy must be eager or lazy")
+	ErrNoActiveDevices = errors.New("no active device of a remaining member can receive the rotated KEK")
+	ErrSelfRemovalRotation = errors.New("a member cannot rotate the group KEK while removing itself")
 )
 
 
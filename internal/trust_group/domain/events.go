@@ -11,6 +11,9 @@ const (
 	EventMemberAddedToTrustGroup = "trustgroup.member.added"
 	EventMemberRemovedFromTrustGroup = "trustgroup.member.removed"
 	EventTrustGroupKEKRotated = "trustgroup.kek.rotated"
+
+	KEKRotationMemberRemoved = "member_removed"
+	KEKRotationDeviceRevoked = "device_revoked"
 )
 
 type TrustGroupCreated struct {
@@ -75,6 +78,14 @@ type MemberRemovedFromTrustGroup struct {
 	WorkspaceID     string
 	OwnerID     string
 	Name string
+
+	ChannelID string
+	MemberID  string
+	ActorID   string
+	// RevokedDeviceIDs are the devices whose envelopes the removal revoked.
+	RevokedDeviceIDs []string
+	// KEKVersion is the version the group rotated to on removal.
+	KEKVersion uint64
 }
 
 func (MemberRemovedFromTrustGroup) EventType() string {
@@ -89,6 +100,17 @@ type TrustGroupKEKRotated struct {
 	WorkspaceID     string
 	OwnerID     string
 	Name string
+
+	ChannelID  string
+	OldVersion uint64
+	NewVersion uint64
+	Reason     string
+	ActorID    string
+	RewrapPolicy ShareRewrapPolicy
+	// ReWrappedShareEntryIDs moved to the new KEK during the rotation;
+	// PendingShareEntryIDs are left for lazy re-wrap.
+	ReWrappedShareEntryIDs []string
+	PendingShareEntryIDs   []string
 }
 
 func (TrustGroupKEKRotated) EventType() string {
@@ -0,0 +1,81 @@
+package trustgroup_domain
+
+import "time"
+
+// ShareRewrapPolicy decides what happens to share entries when the KEK
+// rotates. Eager re-wraps every DEK as part of the rotation; lazy leaves
+// entries on the old version and re-wraps each one the next time a
+// remaining member resolves it.
+type ShareRewrapPolicy string
+
+const (
+	ShareRewrapEager ShareRewrapPolicy = "eager"
+	ShareRewrapLazy  ShareRewrapPolicy = "lazy"
+)
+
+// RewrapPolicy returns the group's policy, eager when unset.
+func (g *TrustGroup) RewrapPolicy() ShareRewrapPolicy {
+	if g.ShareRewrapPolicy == "" {
+		return ShareRewrapEager
+	}
+	return g.ShareRewrapPolicy
+}
+
+func (g *TrustGroup) SetShareRewrapPolicy(policy ShareRewrapPolicy) error {
+	if policy != ShareRewrapEager && policy != ShareRewrapLazy {
+		return ErrShareRewrapPolicyInvalid
+	}
+	g.ShareRewrapPolicy = policy
+	g.IsDirty = true
+	return nil
+}
+
+func (g *TrustGroup) IsMember(vaultID string) bool {
+	for _, cid := range g.MemberCIDs {
+		if cid == vaultID {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveEnvelopes returns the unrevoked envelopes of the current KEK
+// version.
+func (g *TrustGroup) ActiveEnvelopes() []TrustGroupKeyEnvelope {
+	active := []TrustGroupKeyEnvelope{}
+	for _, env := range g.KeyEnvelopes {
+		if env.KEKVersion == g.KEKVersion && env.RevokedAt == nil {
+			active = append(active, env)
+		}
+	}
+	return active
+}
+
+// Envelope returns the envelope of deviceID for memberID at version, revoked
+// or not.
+func (g *TrustGroup) Envelope(memberID string, deviceID string, version uint64) (*TrustGroupKeyEnvelope, bool) {
+	for i := range g.KeyEnvelopes {
+		env := &g.KeyEnvelopes[i]
+		if env.MemberID == memberID && env.DeviceID == deviceID && env.KEKVersion == version {
+			return env, true
+		}
+	}
+	return nil, false
+}
+
+// RevokeMemberEnvelopes revokes every live envelope held by memberID and
+// returns the affected device IDs.
+func (g *TrustGroup) RevokeMemberEnvelopes(memberID string, at time.Time) []string {
+	devices := []string{}
+	for i := range g.KeyEnvelopes {
+		env := &g.KeyEnvelopes[i]
+		if env.MemberID != memberID || env.RevokedAt != nil {
+			continue
+		}
+		revokedAt := at
+		env.RevokedAt = &revokedAt
+		devices = append(devices, env.DeviceID)
+		g.IsDirty = true
+	}
+	return devices
+}
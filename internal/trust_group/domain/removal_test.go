@@ -0,0 +1,68 @@
+package trustgroup_domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	trustgroup_domain "vault-app/internal/trust_group/domain"
+)
+
+func groupWithDevices(t *testing.T) *trustgroup_domain.TrustGroup {
+	tg := trustgroup_domain.NewTrustGroup("channel-001", "OEM Group", []string{"member-1", "member-2"})
+	for _, env := range []trustgroup_domain.TrustGroupKeyEnvelope{
+		{MemberID: "member-1", DeviceID: "device-1a", KEKVersion: 1, WrappedKEK: "w1a"},
+		{MemberID: "member-2", DeviceID: "device-2a", KEKVersion: 1, WrappedKEK: "w2a"},
+		{MemberID: "member-2", DeviceID: "device-2b", KEKVersion: 1, WrappedKEK: "w2b"},
+	} {
+		require.NoError(t, tg.AddEnvelope(env))
+	}
+	return tg
+}
+
+func TestTrustGroup_RemoveMember_RevokesEnvelopes(t *testing.T) {
+	tg := groupWithDevices(t)
+
+	require.NoError(t, tg.RemoveMember("member-2"))
+	require.False(t, tg.IsMember("member-2"))
+
+	active := tg.ActiveEnvelopes()
+	require.Len(t, active, 1)
+	require.Equal(t, "device-1a", active[0].DeviceID)
+	for _, env := range tg.KeyEnvelopes {
+		if env.MemberID == "member-2" {
+			require.NotNil(t, env.RevokedAt)
+		}
+	}
+}
+
+func TestTrustGroup_RevokeMemberEnvelopes_ReturnsDevices(t *testing.T) {
+	tg := groupWithDevices(t)
+
+	devices := tg.RevokeMemberEnvelopes("member-2", time.Now())
+	require.ElementsMatch(t, []string{"device-2a", "device-2b"}, devices)
+	require.Empty(t, tg.RevokeMemberEnvelopes("member-2", time.Now()), "already revoked envelopes are not reported twice")
+}
+
+func TestTrustGroup_RotateKEK_RejectsEnvelopeForNonMember(t *testing.T) {
+	tg := groupWithDevices(t)
+	require.NoError(t, tg.RemoveMember("member-2"))
+
+	err := tg.RotateKEK(2, []trustgroup_domain.TrustGroupKeyEnvelope{
+		{MemberID: "member-1", DeviceID: "device-1a", KEKVersion: 2, WrappedKEK: "w1a-v2"},
+		{MemberID: "member-2", DeviceID: "device-2a", KEKVersion: 2, WrappedKEK: "w2a-v2"},
+	})
+	require.ErrorIs(t, err, trustgroup_domain.ErrMemberNotInTrustGroup)
+	require.Equal(t, uint64(1), tg.KEKVersion, "a rejected rotation leaves the group untouched")
+	require.Len(t, tg.ActiveEnvelopes(), 1)
+}
+
+func TestTrustGroup_RewrapPolicy_DefaultsToEager(t *testing.T) {
+	tg := groupWithDevices(t)
+	require.Equal(t, trustgroup_domain.ShareRewrapEager, tg.RewrapPolicy())
+
+	require.NoError(t, tg.SetShareRewrapPolicy(trustgroup_domain.ShareRewrapLazy))
+	require.Equal(t, trustgroup_domain.ShareRewrapLazy, tg.RewrapPolicy())
+	require.ErrorIs(t, tg.SetShareRewrapPolicy("sometimes"), trustgroup_domain.ErrShareRewrapPolicyInvalid)
+}
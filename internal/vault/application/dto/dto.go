@@ -36,6 +36,8 @@ type SynchronizeVaultRequest struct {
 	UserOnboarding string               `json:"user_onboarding"`
 	Configs        app_config_domain.Config
 	PrivateKey     string
+	// RotateSearchIndexKey seals the search index under a new index key.
+	RotateSearchIndexKey bool
 }
 type SynchronizeAttachmentRequest struct {
 	UserID         string               `json:"user_id"`
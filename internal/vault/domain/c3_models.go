@@ -0,0 +1,324 @@
+package vaults_domain
+
+import "time"
+
+// ==============================================================================
+// C3VaultContent => CollaborativeRoot
+// ==============================================================================
+type C3VaultContent struct {
+	Workspaces        []Workspace
+	Channels          []Channel
+	Threads           []Thread
+	ShareEntries      []ShareEntry
+	TrustGroups       []TrustGroup
+	TrustGroupMembers []TrustGroupMember
+	Federation        FederationSnapshot
+	Participants      []Participant
+	Assets            []Asset
+	Index             IndexC3
+	CreatedAt         string `json:"created_at" gorm:"-"`
+	UpdatedAt         string `json:"updated_at" gorm:"-"`
+}
+
+func (v *VaultPayload) InitVaultCollaborative() {
+	v.Collaborative = C3VaultContent{
+		Workspaces:        []Workspace{},
+		Channels:          []Channel{},
+		Threads:           []Thread{},
+		ShareEntries:      []ShareEntry{},
+		TrustGroups:       []TrustGroup{},
+		TrustGroupMembers: []TrustGroupMember{},
+		Federation:        FederationSnapshot{RemoteVaults: []RemoteVault{}},
+		Participants:      []Participant{},
+		Assets:            []Asset{},
+		Index: IndexC3{
+			Threads: ThreadsIndex{
+				ByChannel: make(map[string][]Link),
+				ByStatus:  make(map[string][]Link),
+			},
+			Assets: AssetsIndex{
+				ByHash: make(map[string][]Link),
+				ByType: make(map[string][]Link),
+			},
+			Federations: FederationsIndex{
+				ByVault:      make(map[string][]Link),
+				ByTrustState: make(map[string][]Link),
+			},
+			TrustGroups: TrustGroupsIndex{
+				ByWorkspace: make(map[string][]Link),
+				ByMember:    make(map[string][]Link),
+			},
+		},
+	}
+}
+
+// ==============================================================================
+// Workspace
+// ==============================================================================
+type WorkspaceStatus string
+
+type Workspace struct {
+	ID          string          `json:"id"`
+	VaultID     string          `json:"vault_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Status      WorkspaceStatus `json:"status"`
+	OwnerID     string          `json:"owner_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	IsDraft     bool            `json:"is_draft"`
+	IsDirty     bool            `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// Channel
+// ==============================================================================
+type ChannelStatus string
+
+type Slot struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	VaultID string `json:"vault_id"`
+	Gated   bool   `json:"gated"`
+	Order   int    `json:"order"`
+}
+
+type Assignment struct {
+	SlotID       string `json:"slot_id"`
+	OwnerID      string `json:"owner_id"`
+	PublicKey    string `json:"public_key"`
+	VaultAddress string `json:"vault_address"`
+}
+
+type ChannelProperty struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Policy map[string]any
+
+type Channel struct {
+	ID          string            `json:"id"`
+	TemplateID  string            `json:"template_id"`
+	Title       string            `json:"title"`
+	Status      ChannelStatus     `json:"status"`
+	Slots       []Slot            `json:"slots"`
+	Assignments []Assignment      `json:"assignments"`
+	Properties  []ChannelProperty `json:"properties"`
+	Policy      Policy            `json:"policy"`
+	Federation  string            `json:"federation"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	RevokedAt   *time.Time        `json:"revoked_at,omitempty"`
+	WorkspaceID string            `json:"workspace_id"`
+	IsDraft     bool              `json:"is_draft"`
+	IsDirty     bool              `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// Thread
+// ==============================================================================
+type ThreadStatus string
+
+type Thread struct {
+	ID        string       `json:"id"`
+	ChannelID string       `json:"channel_id"`
+	AssetType string       `json:"asset_type"`
+	Title     string       `json:"title"`
+	Subtitle  string       `json:"subtitle"`
+	Status    ThreadStatus `json:"status"`
+	CreatedAt time.Time
+	ClosedAt  *time.Time
+	IsDraft   bool `json:"is_draft"`
+	IsDirty   bool `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// ShareEntry
+// ==============================================================================
+type ShareEntry struct {
+	ID           string            `json:"id"`
+	AssetCID     string            `json:"asset_cid"`
+	TrustGroupID string            `json:"trust_group_id"`
+	WrappedDEK   string            `json:"wrapped_dek"`
+	CreatedBy    string            `json:"created_by"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Metadata     map[string]string `json:"metadata"`
+	IsDraft      bool              `json:"is_draft"`
+	IsDirty      bool              `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// TrustGroup
+// ==============================================================================
+type TrustGroup struct {
+	ID          string `json:"id"`
+	WorkspaceID string `json:"workspace_id"`
+	Name        string `json:"name"`
+	KEKVersion  int64  `json:"kek_version"`
+	MemberCIDs  string `json:"member_cids"`
+	CreatedAt   string `json:"created_at"`
+	IsDraft     bool   `json:"is_draft"`
+	IsDirty     bool   `json:"is_dirty" gorm:"boolean"`
+}
+
+type WrappedGroupKey struct {
+	Version uint64 `json:"version"`
+	Value   string `json:"value"`
+}
+
+type TrustGroupMember struct {
+	ID         string           `json:"id"`
+	VaultID    string           `json:"vault_id"`
+	Role       string           `json:"role"`
+	WrappedKEK *WrappedGroupKey `json:"wrapped_kek,omitempty"`
+	JoinedAt   time.Time        `json:"joined_at"`
+	IsDraft    bool             `json:"is_draft"`
+	IsDirty    bool             `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// Federation
+// ==============================================================================
+type TrustState string
+
+type PendingSyncItem struct {
+	ExchangeID    string    `json:"exchange_id"`
+	Status        string    `json:"status"`
+	Cursor        uint64    `json:"cursor"`
+	RetryCount    int       `json:"retry_count"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	NextAttemptAt time.Time `json:"next_attempt_at,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+type RemoteVault struct {
+	VaultID          string            `json:"vault_id"`
+	LastCursor       uint64            `json:"last_cursor"`
+	LastSeen         time.Time         `json:"last_seen"`
+	Endpoint         string            `json:"endpoint"`
+	TrustState       TrustState        `json:"trust_state"`
+	Pending          []PendingSyncItem `json:"pending"`
+	ProtocolVersion  string            `json:"protocol_version"`
+	PublicKey        string            `json:"public_key,omitempty"`
+	PresentedKey     string            `json:"presented_key,omitempty"`
+	TrustChangedAt   time.Time         `json:"trust_changed_at,omitempty"`
+	TrustReason      string            `json:"trust_reason,omitempty"`
+	IdentityIssuedAt time.Time         `json:"identity_issued_at,omitempty"`
+}
+
+type FederationSnapshot struct {
+	RemoteVaults []RemoteVault `json:"remote_vaults"`
+	IsDraft      bool          `json:"is_draft"`
+	IsDirty      bool          `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// Participant
+// ==============================================================================
+type Participant struct {
+	ChannelID   string   `json:"channel_id"`
+	VaultID     string   `json:"vault_id"`
+	PublicKey   string   `json:"public_key"`
+	Direction   string   `json:"direction"` // inbound | outbound | bidirectional
+	JoinedAt    int64    `json:"joined_at"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	IsDraft     bool     `json:"is_draft"`
+	IsDirty     bool     `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// Asset
+// ==============================================================================
+type Asset struct {
+	CID         string `json:"cid"`
+	ContentHash string `json:"content_hash"`
+	Size        int64  `json:"size"`
+	Type        string `json:"type"`
+	IsDirty     bool   `json:"is_dirty" gorm:"boolean"`
+}
+
+// ==============================================================================
+// IndexC3
+// ==============================================================================
+type ThreadsIndex struct {
+	ByChannel map[string][]Link `json:"by_channel"`
+	ByStatus  map[string][]Link `json:"by_status"`
+}
+type AssetsIndex struct {
+	ByHash map[string][]Link `json:"by_hash"`
+	ByType map[string][]Link `json:"by_type"`
+}
+type FederationsIndex struct {
+	ByVault      map[string][]Link `json:"by_vault"`
+	ByTrustState map[string][]Link `json:"by_trust_state"`
+}
+type TrustGroupsIndex struct {
+	ByWorkspace map[string][]Link `json:"by_workspace"`
+	ByMember    map[string][]Link `json:"by_member"`
+}
+type IndexC3 struct {
+	Threads     ThreadsIndex     `json:"threads_index"`
+	Assets      AssetsIndex      `json:"assets_index"`
+	Federations FederationsIndex `json:"federations_index"`
+	TrustGroups TrustGroupsIndex `json:"trust_groups_index"`
+}
+
+// ==============================================================================
+// CollaborativeNode
+// ==============================================================================
+type CollaborativeNode struct {
+	Type         string `json:"Type"`
+	Version      string `json:"Version"`
+	Workspaces   Link   `json:"workspaces"`
+	Participants Link   `json:"participants"`
+	Channels     Link   `json:"channels"`
+	Threads      Link   `json:"threads"`
+	ShareEntries Link   `json:"share_entries"`
+	Assets       Link   `json:"assets"`
+	TrustGroups  Link   `json:"trust_groups"`
+	TrustMembers Link   `json:"trust_members"`
+	Federation   Link   `json:"federation"`
+	Index        Link   `json:"index"`
+}
+type CollaborativeIndexRoot struct {
+	ThreadsIndex     Link `json:"threads_index"`
+	AssetsIndex      Link `json:"assets_index"`
+	FederationsIndex Link `json:"federations_index"`
+	TrustGroupsIndex Link `json:"trust_groups_index"`
+}
+type WorkspacesRoot struct {
+	Items []Link `json:"items"`
+}
+type ChannelsRoot struct {
+	Items []Link `json:"items"`
+}
+type SlotsRoot struct {
+	Items []Link `json:"items"`
+}
+type AssignmentsRoot struct {
+	Items []Link `json:"items"`
+}
+type ThreadsRoot struct {
+	Items []Link `json:"items"`
+}
+type AssetsRoot struct {
+	Items []Link `json:"items"`
+}
+type TrustGroupsRoot struct {
+	Items []Link `json:"items"`
+}
+type TrustGroupMembersRoot struct {
+	Items []Link `json:"items"`
+}
+type ShareEntriesRoot struct {
+	Items []Link `json:"items"`
+}
+type ParticipantsRoot struct {
+	Items []Link `json:"items"`
+}
+type RemoteVaultsRoot struct {
+	Items []Link `json:"items"`
+}
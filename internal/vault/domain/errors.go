@@ -5,5 +5,18 @@ import "errors"
 var (
 	ErrVaultNotFound = errors.New("vault not found")
 	ErrInvalidKey = errors.New("invalid key")
+
+	ErrRecordSchemaInvalid    = errors.New("invalid record schema")
+	ErrRecordValidation       = errors.New("record does not match its schema")
+	ErrUnknownSchemaVersion   = errors.New("unknown record schema version")
+	ErrRecordMigrationMissing = errors.New("no migration registered for record schema version")
+
+	ErrOTPInvalidURI           = errors.New("invalid otpauth URI")
+	ErrOTPInvalidSecret        = errors.New("invalid OTP secret")
+	ErrOTPUnsupportedAlgorithm = errors.New("unsupported OTP algorithm")
+	ErrOTPInvalidParameters    = errors.New("invalid OTP parameters")
+	ErrOTPLinkedLoginNotFound  = errors.New("linked login entry not found")
+
+	ErrInvalidSearchQuery = errors.New("invalid search query")
 )
 	
\ No newline at end of file
@@ -167,6 +167,7 @@ func (v *VaultPayload) InitEntries() {
 		Identity: []IdentityEntry{},
 		Note:     []NoteEntry{},
 		SSHKey:   []SSHKeyEntry{},
+		OTP:      []OTPEntry{},
 	}
 }
 func (v *VaultPayload) InitAttachments() {
@@ -217,6 +218,11 @@ func (v *VaultPayload) GetEntriesByFolder(folderID string) Entries {
 			filtered.SSHKey = append(filtered.SSHKey, e)
 		}
 	}
+	for _, e := range v.Entries.OTP {
+		if e.FolderID == folderID {
+			filtered.OTP = append(filtered.OTP, e)
+		}
+	}
 
 	return filtered
 }
@@ -243,6 +249,10 @@ func (v *VaultPayload) GetEntriesByType(entryType string) []VaultEntry {
 		for _, e := range v.Entries.SSHKey {
 			results = append(results, e)
 		}
+	case "otp":
+		for _, e := range v.Entries.OTP {
+			results = append(results, e)
+		}
 	}
 	return results
 }
@@ -308,6 +318,16 @@ func (v *VaultPayload) AddEntry(entryType string, anEntry any) error {
 		v.Entries.SSHKey = append(v.Entries.SSHKey, e)
 		return nil
 
+	case *OTPEntry:
+		if e == nil {
+			return fmt.Errorf("nil OTPEntry")
+		}
+		v.Entries.OTP = append(v.Entries.OTP, *e)
+		return nil
+	case OTPEntry:
+		v.Entries.OTP = append(v.Entries.OTP, e)
+		return nil
+
 	default:
 		return fmt.Errorf("unsupported entry type %T", anEntry)
 	}
@@ -369,6 +389,17 @@ func (v *VaultPayload) SetEntriesByType(entryType string, entries []VaultEntry)
 		}
 		v.Entries.SSHKey = out
 
+	case string(EntryOTP):
+		out := make([]OTPEntry, 0, len(entries))
+		for _, e := range entries {
+			oe, ok := e.(*OTPEntry)
+			if !ok {
+				return fmt.Errorf("expected *OTPEntry, got %T", e)
+			}
+			out = append(out, *oe)
+		}
+		v.Entries.OTP = out
+
 	default:
 		return fmt.Errorf("unsupported entry type %q", entryType)
 	}
@@ -413,6 +444,13 @@ func (v *VaultPayload) MoveEntriesToUnsorted(folderID string) Entries {
 			moved.SSHKey = append(moved.SSHKey, v.Entries.SSHKey[i])
 		}
 	}
+	// OTP
+	for i, e := range v.Entries.OTP {
+		if e.FolderID == folderID {
+			v.Entries.OTP[i].FolderID = ""
+			moved.OTP = append(moved.OTP, v.Entries.OTP[i])
+		}
+	}
 
 	return moved
 }
@@ -440,6 +478,9 @@ func (v *VaultPayload) Normalize() {
 	if v.Entries.SSHKey == nil {
 		v.Entries.SSHKey = []SSHKeyEntry{}
 	}
+	if v.Entries.OTP == nil {
+		v.Entries.OTP = []OTPEntry{}
+	}
 }
 
 func (s *VaultPayload) ToBytes() []byte {
@@ -503,6 +544,13 @@ func (v *VaultPayload) AddEntryAttachment(
 					return true
 				}
 			}
+		case []OTPEntry:
+			for i := range xs {
+				if xs[i].BaseEntry.ID == entryID {
+					xs[i].BaseEntry.AttachmentCIDs = append(xs[i].BaseEntry.AttachmentCIDs, att.NodeCID)
+					return true
+				}
+			}
 		}
 		return false
 	}
@@ -521,6 +569,9 @@ func (v *VaultPayload) AddEntryAttachment(
 	if !found {
 		found = find(v.Entries.SSHKey)
 	}
+	if !found {
+		found = find(v.Entries.OTP)
+	}
 
 	if !found {
 		return errors.New("entry not found")
@@ -561,6 +612,12 @@ func (v *VaultPayload) GetEntryAttachments(entryID string) []Attachment {
 					return e.Attachments
 				}
 			}
+		case []OTPEntry:
+			for _, e := range xs {
+				if e.ID == entryID {
+					return e.Attachments
+				}
+			}
 		}
 		return nil
 	}
@@ -580,6 +637,9 @@ func (v *VaultPayload) GetEntryAttachments(entryID string) []Attachment {
 	if a := find(v.Entries.SSHKey); a != nil {
 		return a
 	}
+	if a := find(v.Entries.OTP); a != nil {
+		return a
+	}
 
 	return nil
 }
@@ -644,6 +704,16 @@ func (v *VaultPayload) UpdateEntryAttachment(
 					}
 				}
 			}
+		case []OTPEntry:
+			for i := range xs {
+				if xs[i].ID == entryID {
+					for j := range xs[i].Attachments {
+						if xs[i].Attachments[j].ID == attachmentID {
+							return updateFn(&xs[i].Attachments[j])
+						}
+					}
+				}
+			}
 		}
 		return errors.New("entry not found")
 	}
@@ -668,6 +738,10 @@ func (v *VaultPayload) UpdateEntryAttachment(
 	if err == nil {
 		return nil
 	}
+	err = findAndUpdate(v.Entries.OTP)
+	if err == nil {
+		return nil
+	}
 
 	// reuse a sentinel error
 	return errors.New("entry or attachment not found")
@@ -760,6 +834,20 @@ func (v *VaultPayload) DeleteEntryAttachment(
 					}
 				}
 			}
+		case []OTPEntry:
+			for i := range xs {
+				if xs[i].BaseEntry.ID == entryID {
+					for j, att := range xs[i].BaseEntry.Attachments {
+						if att.ID == attachmentID {
+							xs[i].BaseEntry.Attachments = append(
+								xs[i].BaseEntry.Attachments[:j],
+								xs[i].BaseEntry.Attachments[j+1:]...,
+							)
+							return true
+						}
+					}
+				}
+			}
 		}
 		return false
 	}
@@ -778,6 +866,9 @@ func (v *VaultPayload) DeleteEntryAttachment(
 	if !found {
 		found = findAndDelete(v.Entries.SSHKey)
 	}
+	if !found {
+		found = findAndDelete(v.Entries.OTP)
+	}
 
 	if !found {
 		return errors.New("entry or attachment not found")
@@ -807,6 +898,7 @@ const (
 	EntryIdentity EntryType = "identity"
 	EntryNote     EntryType = "note"
 	EntrySSHKey   EntryType = "ssh_key"
+	EntryOTP      EntryType = "otp"
 )
 
 // ==============================================================================
@@ -856,6 +948,19 @@ type LoginEntry struct {
 	UserName string `json:"user_name"`
 	Password string `json:"password"`
 	Website  string `json:"web_site,omitempty"`
+	// PasswordChangedAt is when the password itself last changed; UpdatedAt
+	// moves with any edit.
+	PasswordChangedAt string `json:"password_changed_at,omitempty"`
+}
+
+// TrackPasswordChange sets PasswordChangedAt on an edit of previous: now
+// when the password differs, the previous value otherwise.
+func (e *LoginEntry) TrackPasswordChange(previous LoginEntry, now string) {
+	if e.Password != previous.Password {
+		e.PasswordChangedAt = now
+		return
+	}
+	e.PasswordChangedAt = previous.PasswordChangedAt
 }
 
 func (e *LoginEntry) AddAttachments(attachments []Attachment) *LoginEntry {
@@ -951,6 +1056,31 @@ func (e *SSHKeyEntry) OnShareCreated(cids []string, atts []string) bool {
 	return ParseAndUpdateCIDs(e.BaseEntry.Attachments, cids, atts)
 }
 
+// OTPEntry holds a 2FA seed. Secret is base32 (RFC 4648, no padding); codes
+// are computed on demand and never stored.
+type OTPEntry struct {
+	BaseEntry
+	Kind        OTPKind `json:"otp_type"`
+	Issuer      string  `json:"issuer,omitempty"`
+	AccountName string  `json:"account_name,omitempty"`
+	Secret      string  `json:"secret"`
+	Algorithm   string  `json:"algorithm"`
+	Digits      int     `json:"digits"`
+	Period      int     `json:"period,omitempty"`
+	Counter     uint64  `json:"counter,omitempty"`
+	// LinkedLoginID optionally ties the seed to the LoginEntry it protects.
+	LinkedLoginID string `json:"linked_login_id,omitempty"`
+}
+
+func (e *OTPEntry) AddAttachments(attachments []Attachment) *OTPEntry {
+	e.Attachments = append(e.Attachments, attachments...)
+	e.UpdatedAt = time.Now().Format(time.RFC3339)
+	return e
+}
+func (e *OTPEntry) OnShareCreated(cids []string, atts []string) bool {
+	return ParseAndUpdateCIDs(e.BaseEntry.Attachments, cids, atts)
+}
+
 func (e LoginEntry) GetId() string          { return e.ID }
 func (e LoginEntry) GetTypeName() string    { return "login" }
 func (e LoginEntry) GetName() string        { return e.EntryName }
@@ -966,6 +1096,9 @@ func (e NoteEntry) GetName() string         { return e.EntryName }
 func (e SSHKeyEntry) GetId() string         { return e.ID }
 func (e SSHKeyEntry) GetTypeName() string   { return "sshkey" }
 func (e SSHKeyEntry) GetName() string       { return e.EntryName }
+func (e OTPEntry) GetId() string            { return e.ID }
+func (e OTPEntry) GetTypeName() string      { return "otp" }
+func (e OTPEntry) GetName() string          { return e.EntryName }
 
 type Entries struct {
 	Login    []LoginEntry    `json:"login"`
@@ -973,6 +1106,7 @@ type Entries struct {
 	Identity []IdentityEntry `json:"identity"`
 	Note     []NoteEntry     `json:"note"`
 	SSHKey   []SSHKeyEntry   `json:"sshkey"`
+	OTP      []OTPEntry      `json:"otp"`
 }
 
 type EntryInterface interface {
@@ -984,6 +1118,31 @@ func (e *CardEntry) GetBase() *BaseEntry     { return &e.BaseEntry }
 func (e *IdentityEntry) GetBase() *BaseEntry { return &e.BaseEntry }
 func (e *NoteEntry) GetBase() *BaseEntry     { return &e.BaseEntry }
 func (e *SSHKeyEntry) GetBase() *BaseEntry   { return &e.BaseEntry }
+func (e *OTPEntry) GetBase() *BaseEntry      { return &e.BaseEntry }
+
+// All returns every entry as an EntryInterface pointing into the slices.
+func (e *Entries) All() []EntryInterface {
+	all := make([]EntryInterface, 0, len(e.Login)+len(e.Card)+len(e.Identity)+len(e.Note)+len(e.SSHKey)+len(e.OTP))
+	for i := range e.Login {
+		all = append(all, &e.Login[i])
+	}
+	for i := range e.Card {
+		all = append(all, &e.Card[i])
+	}
+	for i := range e.Identity {
+		all = append(all, &e.Identity[i])
+	}
+	for i := range e.Note {
+		all = append(all, &e.Note[i])
+	}
+	for i := range e.SSHKey {
+		all = append(all, &e.SSHKey[i])
+	}
+	for i := range e.OTP {
+		all = append(all, &e.OTP[i])
+	}
+	return all
+}
 
 // ==============================================================================
 // Utilities
@@ -1146,6 +1305,9 @@ type AttachementsRoot struct {
 type Index struct {
 	ByType   map[string][]Link `json:"byType"`
 	ByFolder map[string][]Link `json:"byFolder"`
+	// Search points to the encrypted SearchIndexNode; empty when the vault
+	// has no index key yet.
+	Search Link `json:"search"`
 }
 type WrappedKey struct {
 	ID        string // uuid
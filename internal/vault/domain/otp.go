@@ -0,0 +1,494 @@
+package vaults_domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OTPKind string
+
+const (
+	OTPKindTOTP OTPKind = "totp"
+	OTPKindHOTP OTPKind = "hotp"
+)
+
+const (
+	OTPAlgorithmSHA1   = "SHA1"
+	OTPAlgorithmSHA256 = "SHA256"
+	OTPAlgorithmSHA512 = "SHA512"
+
+	DefaultOTPDigits = 6
+	DefaultOTPPeriod = 30
+)
+
+var otpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// OTPCode is a generated one-time password. For TOTP, RemainingSeconds and
+// ExpiresAt give the rest of the current time step; for HOTP, Counter is the
+// counter value the code was computed from.
+type OTPCode struct {
+	Code             string    `json:"code"`
+	Kind             OTPKind   `json:"otp_type"`
+	Period           int       `json:"period,omitempty"`
+	RemainingSeconds int       `json:"remaining_seconds,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at,omitempty"`
+	Counter          uint64    `json:"counter,omitempty"`
+}
+
+// NewOTPEntry builds an entry from a raw seed, applying the usual
+// authenticator defaults (SHA1, 6 digits, 30 s).
+func NewOTPEntry(kind OTPKind, issuer, accountName string, secret []byte) (*OTPEntry, error) {
+	e := &OTPEntry{
+		BaseEntry: BaseEntry{
+			ID:        uuid.New().String(),
+			Type:      EntryOTP,
+			CreatedAt: time.Now().Format(time.RFC3339),
+			UpdatedAt: time.Now().Format(time.RFC3339),
+			IsDirty:   true,
+		},
+		Kind:        kind,
+		Issuer:      issuer,
+		AccountName: accountName,
+		Secret:      otpSecretEncoding.EncodeToString(secret),
+	}
+	e.applyDefaults()
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *OTPEntry) applyDefaults() {
+	if e.Kind == "" {
+		e.Kind = OTPKindTOTP
+	}
+	if e.Algorithm == "" {
+		e.Algorithm = OTPAlgorithmSHA1
+	}
+	e.Algorithm = strings.ToUpper(e.Algorithm)
+	if e.Digits == 0 {
+		e.Digits = DefaultOTPDigits
+	}
+	if e.Kind == OTPKindTOTP && e.Period == 0 {
+		e.Period = DefaultOTPPeriod
+	}
+	if e.EntryName == "" {
+		switch {
+		case e.Issuer != "" && e.AccountName != "":
+			e.EntryName = e.Issuer + " (" + e.AccountName + ")"
+		case e.Issuer != "":
+			e.EntryName = e.Issuer
+		default:
+			e.EntryName = e.AccountName
+		}
+	}
+}
+
+// Normalize fills defaults on an entry received from the UI and checks it.
+func (e *OTPEntry) Normalize() error {
+	e.Type = EntryOTP
+	e.Secret = strings.ToUpper(strings.ReplaceAll(strings.TrimRight(e.Secret, "="), " ", ""))
+	e.applyDefaults()
+	return e.Validate()
+}
+
+func (e *OTPEntry) Validate() error {
+	if e.Kind != OTPKindTOTP && e.Kind != OTPKindHOTP {
+		return fmt.Errorf("%w: unknown type %q", ErrOTPInvalidParameters, e.Kind)
+	}
+	if _, err := otpHash(e.Algorithm); err != nil {
+		return err
+	}
+	if e.Digits < 6 || e.Digits > 8 {
+		return fmt.Errorf("%w: digits must be between 6 and 8", ErrOTPInvalidParameters)
+	}
+	if e.Kind == OTPKindTOTP && e.Period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrOTPInvalidParameters)
+	}
+	key, err := e.Key()
+	if err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return ErrOTPInvalidSecret
+	}
+	return nil
+}
+
+// Key decodes the stored base32 secret.
+func (e *OTPEntry) Key() ([]byte, error) {
+	key, err := otpSecretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(e.Secret, "=")))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOTPInvalidSecret, err)
+	}
+	return key, nil
+}
+
+// Generate computes the code valid at at. HOTP entries consume their
+// counter: the counter is advanced and the entry marked dirty, so callers
+// must persist the entry afterwards.
+func (e *OTPEntry) Generate(at time.Time) (*OTPCode, error) {
+	key, err := e.Key()
+	if err != nil {
+		return nil, err
+	}
+	switch e.Kind {
+	case OTPKindHOTP:
+		code, err := HOTP(key, e.Counter, e.Digits, e.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		res := &OTPCode{Code: code, Kind: OTPKindHOTP, Counter: e.Counter}
+		e.Counter++
+		e.IsDirty = true
+		return res, nil
+	case OTPKindTOTP:
+		code, err := TOTP(key, at, e.Period, e.Digits, e.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		period := int64(e.Period)
+		expires := time.Unix((at.Unix()/period+1)*period, 0)
+		return &OTPCode{
+			Code:             code,
+			Kind:             OTPKindTOTP,
+			Period:           e.Period,
+			RemainingSeconds: int(expires.Unix() - at.Unix()),
+			ExpiresAt:        expires,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrOTPInvalidParameters, e.Kind)
+	}
+}
+
+// HOTP computes an RFC 4226 one-time password.
+func HOTP(key []byte, counter uint64, digits int, algorithm string) (string, error) {
+	newHash, err := otpHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	if digits < 1 || digits > 9 {
+		return "", fmt.Errorf("%w: digits must be between 1 and 9", ErrOTPInvalidParameters)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 §5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+// TOTP computes an RFC 6238 one-time password with T0 = 0.
+func TOTP(key []byte, at time.Time, period int, digits int, algorithm string) (string, error) {
+	if period <= 0 {
+		return "", fmt.Errorf("%w: period must be positive", ErrOTPInvalidParameters)
+	}
+	return HOTP(key, uint64(at.Unix()/int64(period)), digits, algorithm)
+}
+
+func otpHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", OTPAlgorithmSHA1:
+		return sha1.New, nil
+	case OTPAlgorithmSHA256:
+		return sha256.New, nil
+	case OTPAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrOTPUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// -----------------------------
+//
+//	Import
+//
+// -----------------------------
+
+// ParseOTPImport accepts either an otpauth:// URI or a Google Authenticator
+// export (otpauth-migration://offline?data=...) and returns the entries it
+// describes.
+func ParseOTPImport(raw string) ([]*OTPEntry, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(strings.ToLower(raw), "otpauth-migration://") {
+		return ParseOTPMigrationURI(raw)
+	}
+	e, err := ParseOTPAuthURI(raw)
+	if err != nil {
+		return nil, err
+	}
+	return []*OTPEntry{e}, nil
+}
+
+// ParseOTPAuthURI parses otpauth://TYPE/LABEL?secret=...&issuer=...
+// (Key Uri Format).
+func ParseOTPAuthURI(raw string) (*OTPEntry, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, ErrOTPInvalidURI
+	}
+	kind := OTPKind(strings.ToLower(u.Host))
+	if kind != OTPKindTOTP && kind != OTPKindHOTP {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrOTPInvalidURI, u.Host)
+	}
+
+	q := u.Query()
+	issuer, account := splitOTPLabel(strings.TrimPrefix(u.Path, "/"))
+	if qi := q.Get("issuer"); qi != "" {
+		issuer = qi
+	}
+
+	e := &OTPEntry{
+		BaseEntry: BaseEntry{
+			ID:        uuid.New().String(),
+			Type:      EntryOTP,
+			CreatedAt: time.Now().Format(time.RFC3339),
+			UpdatedAt: time.Now().Format(time.RFC3339),
+			IsDirty:   true,
+		},
+		Kind:        kind,
+		Issuer:      issuer,
+		AccountName: account,
+		Secret:      q.Get("secret"),
+		Algorithm:   q.Get("algorithm"),
+	}
+	if e.Secret == "" {
+		return nil, fmt.Errorf("%w: secret is required", ErrOTPInvalidURI)
+	}
+	if d := q.Get("digits"); d != "" {
+		if e.Digits, err = strconv.Atoi(d); err != nil {
+			return nil, fmt.Errorf("%w: digits", ErrOTPInvalidURI)
+		}
+	}
+	if p := q.Get("period"); p != "" {
+		if e.Period, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("%w: period", ErrOTPInvalidURI)
+		}
+	}
+	if kind == OTPKindHOTP {
+		c := q.Get("counter")
+		if c == "" {
+			return nil, fmt.Errorf("%w: counter is required for hotp", ErrOTPInvalidURI)
+		}
+		if e.Counter, err = strconv.ParseUint(c, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: counter", ErrOTPInvalidURI)
+		}
+	}
+
+	if err := e.Normalize(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// URI renders the entry as an otpauth:// URI, the inverse of
+// ParseOTPAuthURI.
+func (e *OTPEntry) URI() string {
+	label := e.AccountName
+	if e.Issuer != "" {
+		label = e.Issuer + ":" + e.AccountName
+	}
+	q := url.Values{}
+	q.Set("secret", e.Secret)
+	if e.Issuer != "" {
+		q.Set("issuer", e.Issuer)
+	}
+	if e.Algorithm != "" {
+		q.Set("algorithm", e.Algorithm)
+	}
+	if e.Digits != 0 {
+		q.Set("digits", strconv.Itoa(e.Digits))
+	}
+	kind := e.Kind
+	if kind == "" {
+		kind = OTPKindTOTP
+	}
+	if kind == OTPKindHOTP {
+		q.Set("counter", strconv.FormatUint(e.Counter, 10))
+	} else if e.Period != 0 {
+		q.Set("period", strconv.Itoa(e.Period))
+	}
+	u := url.URL{Scheme: "otpauth", Host: string(kind), Path: "/" + label, RawQuery: q.Encode()}
+	return u.String()
+}
+
+func splitOTPLabel(label string) (issuer string, account string) {
+	if i := strings.Index(label, ":"); i >= 0 {
+		return strings.TrimSpace(label[:i]), strings.TrimSpace(label[i+1:])
+	}
+	return "", strings.TrimSpace(label)
+}
+
+// ParseOTPMigrationURI decodes a Google Authenticator export. The payload is
+// a base64 protobuf MigrationPayload; only the fields needed to rebuild the
+// seeds are read.
+func ParseOTPMigrationURI(raw string) ([]*OTPEntry, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || !strings.EqualFold(u.Scheme, "otpauth-migration") {
+		return nil, ErrOTPInvalidURI
+	}
+	data := u.Query().Get("data")
+	if data == "" {
+		return nil, fmt.Errorf("%w: data is required", ErrOTPInvalidURI)
+	}
+	// A literal '+' that was not percent-encoded arrives as a space.
+	data = strings.ReplaceAll(data, " ", "+")
+	payload, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		if payload, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+			return nil, fmt.Errorf("%w: data is not base64", ErrOTPInvalidURI)
+		}
+	}
+
+	entries := []*OTPEntry{}
+	err = walkProtoFields(payload, func(field int, value []byte, _ uint64) error {
+		if field != 1 { // repeated OtpParameters otp_parameters = 1
+			return nil
+		}
+		e, err := parseMigrationParameters(value)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no accounts in export", ErrOTPInvalidURI)
+	}
+	return entries, nil
+}
+
+func parseMigrationParameters(msg []byte) (*OTPEntry, error) {
+	var (
+		secret       []byte
+		name, issuer string
+		algorithm    = OTPAlgorithmSHA1
+		digits       = DefaultOTPDigits
+		kind         = OTPKindTOTP
+		counter      uint64
+	)
+	err := walkProtoFields(msg, func(field int, value []byte, varint uint64) error {
+		switch field {
+		case 1:
+			secret = append([]byte(nil), value...)
+		case 2:
+			name = string(value)
+		case 3:
+			issuer = string(value)
+		case 4:
+			switch varint {
+			case 2:
+				algorithm = OTPAlgorithmSHA256
+			case 3:
+				algorithm = OTPAlgorithmSHA512
+			case 4:
+				return fmt.Errorf("%w: MD5", ErrOTPUnsupportedAlgorithm)
+			}
+		case 5:
+			if varint == 2 {
+				digits = 8
+			}
+		case 6:
+			if varint == 1 {
+				kind = OTPKindHOTP
+			}
+		case 7:
+			counter = varint
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	labelIssuer, account := splitOTPLabel(name)
+	if issuer == "" {
+		issuer = labelIssuer
+	}
+	e, err := NewOTPEntry(kind, issuer, account, secret)
+	if err != nil {
+		return nil, err
+	}
+	e.Algorithm = algorithm
+	e.Digits = digits
+	e.Counter = counter
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// walkProtoFields iterates over the top-level fields of a protobuf message.
+// Length-delimited fields are passed as value, varints as varint; fixed-size
+// fields are skipped.
+func walkProtoFields(msg []byte, fn func(field int, value []byte, varint uint64) error) error {
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+		}
+		msg = msg[n:]
+		field, wire := int(tag>>3), tag&0x7
+
+		switch wire {
+		case 0:
+			v, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+			}
+			msg = msg[n:]
+			if err := fn(field, nil, v); err != nil {
+				return err
+			}
+		case 1:
+			if len(msg) < 8 {
+				return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+			}
+			msg = msg[8:]
+		case 2:
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+			}
+			value := msg[n : n+int(l)]
+			msg = msg[n+int(l):]
+			if err := fn(field, value, 0); err != nil {
+				return err
+			}
+		case 5:
+			if len(msg) < 4 {
+				return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+			}
+			msg = msg[4:]
+		default:
+			return fmt.Errorf("%w: malformed payload", ErrOTPInvalidURI)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package vaults_domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------
+//
+//	RecordSchema
+//
+// -----------------------------
+type RecordFieldType string
+
+const (
+	RecordFieldText    RecordFieldType = "text"
+	RecordFieldSecret  RecordFieldType = "secret"
+	RecordFieldNumber  RecordFieldType = "number"
+	RecordFieldBoolean RecordFieldType = "boolean"
+	RecordFieldDate    RecordFieldType = "date"
+	RecordFieldEmail   RecordFieldType = "email"
+	RecordFieldURL     RecordFieldType = "url"
+)
+
+// RecordFieldSchema describes one typed field of a custom record. Format is
+// an optional regular expression the (string) value must match in full.
+// Sensitive fields hold secrets and must not be shown or indexed in clear.
+type RecordFieldSchema struct {
+	Name      string          `json:"name"`
+	Label     string          `json:"label,omitempty"`
+	Type      RecordFieldType `json:"type"`
+	Required  bool            `json:"required,omitempty"`
+	Format    string          `json:"format,omitempty"`
+	Sensitive bool            `json:"sensitive,omitempty"`
+
+	format *regexp.Regexp
+}
+
+// RecordSchema is one version of a custom record type. Entries carry the
+// record type and schema version they were written with; fields are kept in
+// the entry's free-form field map.
+type RecordSchema struct {
+	RecordType string              `json:"record_type"`
+	Version    int                 `json:"version"`
+	Fields     []RecordFieldSchema `json:"fields"`
+	// AllowAdditional keeps fields the schema does not declare instead of
+	// rejecting them.
+	AllowAdditional bool `json:"allow_additional,omitempty"`
+}
+
+// RecordMigration upgrades the fields of a record from one schema version to
+// the next. It receives a copy and may modify and return it.
+type RecordMigration func(fields JSONMap) (JSONMap, error)
+
+type RecordFieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// RecordValidationError lists every field that failed validation.
+type RecordValidationError struct {
+	RecordType string             `json:"record_type"`
+	Version    int                `json:"version"`
+	Fields     []RecordFieldError `json:"fields"`
+}
+
+func (e *RecordValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Reason)
+	}
+	return fmt.Sprintf("%s: %s v%d: %s", ErrRecordValidation, e.RecordType, e.Version, strings.Join(parts, "; "))
+}
+
+func (e *RecordValidationError) Unwrap() error { return ErrRecordValidation }
+
+func (s *RecordSchema) compile() error {
+	if strings.TrimSpace(s.RecordType) == "" {
+		return fmt.Errorf("%w: record type is required", ErrRecordSchemaInvalid)
+	}
+	if s.Version < 1 {
+		return fmt.Errorf("%w: %s: version must be >= 1", ErrRecordSchemaInvalid, s.RecordType)
+	}
+	seen := map[string]bool{}
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		if f.Name == "" || seen[f.Name] {
+			return fmt.Errorf("%w: %s v%d: empty or duplicate field name %q", ErrRecordSchemaInvalid, s.RecordType, s.Version, f.Name)
+		}
+		seen[f.Name] = true
+		switch f.Type {
+		case RecordFieldText, RecordFieldSecret, RecordFieldNumber, RecordFieldBoolean,
+			RecordFieldDate, RecordFieldEmail, RecordFieldURL:
+		default:
+			return fmt.Errorf("%w: %s v%d: field %q has unknown type %q", ErrRecordSchemaInvalid, s.RecordType, s.Version, f.Name, f.Type)
+		}
+		if f.Format != "" {
+			re, err := regexp.Compile("^(?:" + f.Format + ")$")
+			if err != nil {
+				return fmt.Errorf("%w: %s v%d: field %q: %v", ErrRecordSchemaInvalid, s.RecordType, s.Version, f.Name, err)
+			}
+			f.format = re
+		}
+	}
+	return nil
+}
+
+// Validate checks fields against the schema and reports every violation at
+// once.
+func (s RecordSchema) Validate(fields JSONMap) error {
+	var errs []RecordFieldError
+	declared := make(map[string]bool, len(s.Fields))
+
+	for _, f := range s.Fields {
+		declared[f.Name] = true
+		value, present := fields[f.Name]
+		if !present || value == nil || value == "" {
+			if f.Required {
+				errs = append(errs, RecordFieldError{Field: f.Name, Reason: "is required"})
+			}
+			continue
+		}
+		if reason := f.check(value); reason != "" {
+			errs = append(errs, RecordFieldError{Field: f.Name, Reason: reason})
+		}
+	}
+	if !s.AllowAdditional {
+		extra := []string{}
+		for name := range fields {
+			if !declared[name] {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			errs = append(errs, RecordFieldError{Field: name, Reason: "is not declared by the schema"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &RecordValidationError{RecordType: s.RecordType, Version: s.Version, Fields: errs}
+	}
+	return nil
+}
+
+// SensitiveFields returns the names of the fields flagged as sensitive.
+func (s RecordSchema) SensitiveFields() []string {
+	names := []string{}
+	for _, f := range s.Fields {
+		if f.Sensitive || f.Type == RecordFieldSecret {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func (f RecordFieldSchema) check(value any) string {
+	switch f.Type {
+	case RecordFieldNumber:
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64, uint, uint32, uint64:
+		case json.Number:
+			if _, err := v.Float64(); err != nil {
+				return "must be a number"
+			}
+		default:
+			return "must be a number"
+		}
+		return ""
+	case RecordFieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+		return ""
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "must be a string"
+	}
+	switch f.Type {
+	case RecordFieldDate:
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return "must be a date (YYYY-MM-DD or RFC 3339)"
+			}
+		}
+	case RecordFieldEmail:
+		addr, err := mail.ParseAddress(str)
+		if err != nil || addr.Address != str {
+			return "must be an email address"
+		}
+	case RecordFieldURL:
+		u, err := url.Parse(str)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URL"
+		}
+	}
+	re := f.format
+	if re == nil && f.Format != "" {
+		// Schemas validated without being registered are compiled here.
+		var err error
+		if re, err = regexp.Compile("^(?:" + f.Format + ")$"); err != nil {
+			return "has an invalid format constraint"
+		}
+	}
+	if re != nil && !re.MatchString(str) {
+		return "does not match the expected format"
+	}
+	return ""
+}
+
+// -----------------------------
+//
+//	RecordSchemaRegistry
+//
+// -----------------------------
+
+// RecordSchemaRegistry holds every registered version of each custom record
+// type together with the migrations between consecutive versions.
+type RecordSchemaRegistry struct {
+	mu         sync.RWMutex
+	schemas    map[string]map[int]RecordSchema
+	migrations map[string]map[int]RecordMigration
+}
+
+func NewRecordSchemaRegistry() *RecordSchemaRegistry {
+	return &RecordSchemaRegistry{
+		schemas:    make(map[string]map[int]RecordSchema),
+		migrations: make(map[string]map[int]RecordMigration),
+	}
+}
+
+// Register adds (or replaces) one version of a record schema.
+func (r *RecordSchemaRegistry) Register(schema RecordSchema) error {
+	schema.Fields = append([]RecordFieldSchema(nil), schema.Fields...)
+	if err := schema.compile(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas[schema.RecordType] == nil {
+		r.schemas[schema.RecordType] = make(map[int]RecordSchema)
+	}
+	r.schemas[schema.RecordType][schema.Version] = schema
+	return nil
+}
+
+// RegisterMigration registers the upgrade from fromVersion to fromVersion+1.
+func (r *RecordSchemaRegistry) RegisterMigration(recordType string, fromVersion int, migrate RecordMigration) error {
+	if recordType == "" || fromVersion < 1 || migrate == nil {
+		return fmt.Errorf("%w: migration needs a record type, a version >= 1 and a function", ErrRecordSchemaInvalid)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.migrations[recordType] == nil {
+		r.migrations[recordType] = make(map[int]RecordMigration)
+	}
+	r.migrations[recordType][fromVersion] = migrate
+	return nil
+}
+
+func (r *RecordSchemaRegistry) Schema(recordType string, version int) (RecordSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.schemas[recordType][version]
+	return schema, ok
+}
+
+// Latest returns the highest registered version of recordType.
+func (r *RecordSchemaRegistry) Latest(recordType string) (RecordSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var latest RecordSchema
+	found := false
+	for version, schema := range r.schemas[recordType] {
+		if !found || version > latest.Version {
+			latest = schema
+			found = true
+		}
+	}
+	return latest, found
+}
+
+// StampSchemaVersion sets the latest schema version on a typed entry that
+// has none yet. It reports whether the entry changed.
+func (r *RecordSchemaRegistry) StampSchemaVersion(entry EntryInterface) bool {
+	rec := recordOf(entry)
+	if rec.recordType == "" || rec.version != 0 {
+		return false
+	}
+	latest, ok := r.Latest(rec.recordType)
+	if !ok {
+		return false
+	}
+	rec.set(latest.Version, rec.fields)
+	return true
+}
+
+// ValidateEntry validates the record fields of entry without modifying it.
+// Entries without a record type, or whose type has no registered schema, are
+// free-form and pass; an entry without a version is checked against the
+// latest one.
+func (r *RecordSchemaRegistry) ValidateEntry(entry EntryInterface) error {
+	rec := recordOf(entry)
+	if rec.recordType == "" {
+		return nil
+	}
+	latest, ok := r.Latest(rec.recordType)
+	if !ok {
+		return nil
+	}
+	if rec.version == 0 {
+		return latest.Validate(rec.fields)
+	}
+	schema, ok := r.Schema(rec.recordType, rec.version)
+	if !ok {
+		return fmt.Errorf("%w: %s v%d", ErrUnknownSchemaVersion, rec.recordType, rec.version)
+	}
+	return schema.Validate(rec.fields)
+}
+
+// MigrateEntry upgrades entry one version at a time up to the latest schema
+// of its record type and validates the result. It reports whether the entry
+// changed; on error the entry is left untouched.
+func (r *RecordSchemaRegistry) MigrateEntry(entry EntryInterface) (bool, error) {
+	rec := recordOf(entry)
+	if rec.recordType == "" || rec.version == 0 {
+		return false, nil
+	}
+	latest, ok := r.Latest(rec.recordType)
+	if !ok || rec.version >= latest.Version {
+		return false, nil
+	}
+
+	fields := copyJSONMap(rec.fields)
+	for version := rec.version; version < latest.Version; version++ {
+		r.mu.RLock()
+		migrate, ok := r.migrations[rec.recordType][version]
+		r.mu.RUnlock()
+		if !ok {
+			return false, fmt.Errorf("%w: %s v%d -> v%d", ErrRecordMigrationMissing, rec.recordType, version, version+1)
+		}
+		next, err := migrate(copyJSONMap(fields))
+		if err != nil {
+			return false, fmt.Errorf("migrating %s v%d -> v%d: %w", rec.recordType, version, version+1, err)
+		}
+		fields = next
+	}
+	if err := latest.Validate(fields); err != nil {
+		return false, err
+	}
+	rec.set(latest.Version, fields)
+	return true, nil
+}
+
+// entryRecord is the record view of an entry. Card entries keep their own
+// record type, version and fields alongside the legacy card columns; every
+// other entry uses the ones on BaseEntry.
+type entryRecord struct {
+	recordType string
+	version    int
+	fields     JSONMap
+	set        func(version int, fields JSONMap)
+}
+
+func recordOf(entry EntryInterface) entryRecord {
+	if card, ok := entry.(*CardEntry); ok && card.RecordType != "" {
+		version, _ := strconv.Atoi(card.SchemaVersion)
+		return entryRecord{
+			recordType: card.RecordType,
+			version:    version,
+			fields:     card.Fields,
+			set: func(v int, fields JSONMap) {
+				card.SchemaVersion = strconv.Itoa(v)
+				card.Fields = fields
+			},
+		}
+	}
+	base := entry.GetBase()
+	return entryRecord{
+		recordType: base.RecordType,
+		version:    base.SchemaVersion,
+		fields:     base.CustomFields,
+		set: func(v int, fields JSONMap) {
+			base.SchemaVersion = v
+			base.CustomFields = fields
+		},
+	}
+}
+
+func copyJSONMap(m JSONMap) JSONMap {
+	out := make(JSONMap, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
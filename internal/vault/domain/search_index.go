@@ -0,0 +1,568 @@
+package vaults_domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Searchable fields. Custom record fields are indexed as "custom.<name>".
+const (
+	SearchFieldName     = "name"
+	SearchFieldUsername = "username"
+	SearchFieldURL      = "url"
+	SearchFieldTags     = "tags"
+	SearchFieldIssuer   = "issuer"
+	SearchFieldCustom   = "custom"
+
+	searchIndexVersion = 1
+)
+
+// Free-form custom fields have no schema to tell whether they are secret,
+// so a field is left out of the index when its name contains one of
+// secretFieldHints or has a word in secretFieldWords.
+var (
+	secretFieldHints = []string{"password", "passwd", "passphrase", "secret", "cardnumber"}
+	secretFieldWords = map[string]bool{
+		"pin": true, "cvc": true, "cvv": true, "key": true, "token": true,
+		"seed": true, "otp": true, "ssn": true, "iban": true, "number": true,
+	}
+)
+
+// Tokens with no search value, mostly URL noise.
+var searchStopTokens = map[string]bool{"http": true, "https": true, "www": true}
+
+// SearchIndex is a tokenized inverted index over entry metadata. It never
+// holds secret values (passwords, card numbers, keys, OTP seeds or sensitive
+// custom fields) and is stored encrypted under the KeyTypeIndex key.
+type SearchIndex struct {
+	Version int                        `json:"version"`
+	Docs    map[string]SearchDocument  `json:"docs"`
+	Terms   map[string][]SearchPosting `json:"terms"`
+}
+
+// SearchDocument is the indexed view of a single entry. Stamp changes
+// whenever the entry does, so Sync only re-tokenizes modified entries.
+type SearchDocument struct {
+	EntryID  string              `json:"entry_id"`
+	Type     EntryType           `json:"type"`
+	Name     string              `json:"name"`
+	FolderID string              `json:"folder_id,omitempty"`
+	Trashed  bool                `json:"trashed,omitempty"`
+	Stamp    string              `json:"stamp"`
+	Fields   map[string][]string `json:"fields"`
+}
+
+type SearchPosting struct {
+	EntryID string `json:"entry_id"`
+	Field   string `json:"field"`
+}
+
+// SearchIndexNode is the DAG node holding an encrypted SearchIndex.
+// KeyVersion names the KeyTypeIndex key it was sealed with.
+type SearchIndexNode struct {
+	Type       string  `json:"type"`
+	KeyType    KeyType `json:"key_type"`
+	KeyVersion int     `json:"key_version"`
+	Ciphertext []byte  `json:"ciphertext"`
+}
+
+type SearchOptions struct {
+	Type           EntryType `json:"type,omitempty"`
+	FolderID       string    `json:"folder_id,omitempty"`
+	IncludeTrashed bool      `json:"include_trashed,omitempty"`
+	Limit          int       `json:"limit,omitempty"`
+}
+
+type SearchHit struct {
+	EntryID  string    `json:"entry_id"`
+	Type     EntryType `json:"type"`
+	Name     string    `json:"name"`
+	FolderID string    `json:"folder_id,omitempty"`
+	Score    int       `json:"score"`
+	Fields   []string  `json:"fields"`
+}
+
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		Version: searchIndexVersion,
+		Docs:    map[string]SearchDocument{},
+		Terms:   map[string][]SearchPosting{},
+	}
+}
+
+// Clone returns a copy of idx that can be synced without touching idx.
+// Documents are shared: Upsert replaces them rather than editing them.
+func (idx *SearchIndex) Clone() *SearchIndex {
+	c := &SearchIndex{
+		Version: idx.Version,
+		Docs:    make(map[string]SearchDocument, len(idx.Docs)),
+		Terms:   make(map[string][]SearchPosting, len(idx.Terms)),
+	}
+	for id, doc := range idx.Docs {
+		c.Docs[id] = doc
+	}
+	for token, postings := range idx.Terms {
+		c.Terms[token] = append([]SearchPosting(nil), postings...)
+	}
+	return c
+}
+
+func (idx *SearchIndex) ensure() {
+	if idx.Docs == nil {
+		idx.Docs = map[string]SearchDocument{}
+	}
+	if idx.Terms == nil {
+		idx.Terms = map[string][]SearchPosting{}
+	}
+	if idx.Version == 0 {
+		idx.Version = searchIndexVersion
+	}
+}
+
+// Sync brings the index in line with entries: new or modified entries are
+// (re)indexed and entries that disappeared are dropped. It reports whether
+// anything changed.
+func (idx *SearchIndex) Sync(entries []EntryInterface, schemas *RecordSchemaRegistry) bool {
+	idx.ensure()
+	changed := false
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		base := entry.GetBase()
+		if base.IsDraft || base.ID == "" {
+			continue
+		}
+		seen[base.ID] = true
+		if doc, ok := idx.Docs[base.ID]; ok && doc.Stamp == searchStamp(base) {
+			continue
+		}
+		idx.Upsert(SearchDocumentFor(entry, schemas))
+		changed = true
+	}
+	for id := range idx.Docs {
+		if !seen[id] {
+			idx.Remove(id)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (idx *SearchIndex) Upsert(doc SearchDocument) {
+	idx.ensure()
+	idx.Remove(doc.EntryID)
+	for field, tokens := range doc.Fields {
+		for _, token := range tokens {
+			idx.Terms[token] = append(idx.Terms[token], SearchPosting{EntryID: doc.EntryID, Field: field})
+		}
+	}
+	idx.Docs[doc.EntryID] = doc
+}
+
+func (idx *SearchIndex) Remove(entryID string) {
+	doc, ok := idx.Docs[entryID]
+	if !ok {
+		return
+	}
+	for _, tokens := range doc.Fields {
+		for _, token := range tokens {
+			postings := idx.Terms[token][:0]
+			for _, p := range idx.Terms[token] {
+				if p.EntryID != entryID {
+					postings = append(postings, p)
+				}
+			}
+			if len(postings) == 0 {
+				delete(idx.Terms, token)
+			} else {
+				idx.Terms[token] = postings
+			}
+		}
+	}
+	delete(idx.Docs, entryID)
+}
+
+// Search evaluates a query against the index. A query is a list of
+// whitespace-separated clauses that must all match:
+//
+//	github          exact token
+//	git*            prefix
+//	gihtub~         fuzzy (edit distance 1 for short tokens, 2 otherwise)
+//	gihtub~1        fuzzy with an explicit distance (max 2)
+//	url:github      any of the above scoped to one field
+//	custom:acme     scoped to any custom field
+func (idx *SearchIndex) Search(query string, opts SearchOptions) ([]SearchHit, error) {
+	clauses, err := ParseSearchQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(clauses) == 0 {
+		return []SearchHit{}, nil
+	}
+	idx.ensure()
+
+	var scores map[string]int
+	matched := map[string]map[string]bool{}
+	for _, clause := range clauses {
+		clauseScores := idx.matchClause(clause, matched)
+		if scores == nil {
+			scores = clauseScores
+			continue
+		}
+		for id := range scores {
+			if s, ok := clauseScores[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := []SearchHit{}
+	for id, score := range scores {
+		doc := idx.Docs[id]
+		if doc.Trashed && !opts.IncludeTrashed {
+			continue
+		}
+		if opts.Type != "" && doc.Type != opts.Type {
+			continue
+		}
+		if opts.FolderID != "" && doc.FolderID != opts.FolderID {
+			continue
+		}
+		fields := make([]string, 0, len(matched[id]))
+		for f := range matched[id] {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		hits = append(hits, SearchHit{
+			EntryID:  id,
+			Type:     doc.Type,
+			Name:     doc.Name,
+			FolderID: doc.FolderID,
+			Score:    score,
+			Fields:   fields,
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return strings.ToLower(hits[i].Name) < strings.ToLower(hits[j].Name)
+	})
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits, nil
+}
+
+// matchClause returns the best score of each entry matching every token of
+// the clause. Exact matches outrank prefix matches, which outrank fuzzy ones.
+func (idx *SearchIndex) matchClause(clause SearchClause, matched map[string]map[string]bool) map[string]int {
+	var result map[string]int
+	for i, token := range clause.Tokens {
+		mode := SearchExact
+		if i == len(clause.Tokens)-1 {
+			mode = clause.Mode
+		}
+		tokenScores := map[string]int{}
+		collect := func(term string, score int) {
+			for _, p := range idx.Terms[term] {
+				if !clause.inScope(p.Field) {
+					continue
+				}
+				if tokenScores[p.EntryID] < score {
+					tokenScores[p.EntryID] = score
+				}
+				if matched[p.EntryID] == nil {
+					matched[p.EntryID] = map[string]bool{}
+				}
+				matched[p.EntryID][p.Field] = true
+			}
+		}
+
+		collect(token, 3)
+		switch mode {
+		case SearchPrefix:
+			for term := range idx.Terms {
+				if term != token && strings.HasPrefix(term, token) {
+					collect(term, 2)
+				}
+			}
+		case SearchFuzzy:
+			for term := range idx.Terms {
+				if term != token && withinEditDistance(term, token, clause.Distance) {
+					collect(term, 1)
+				}
+			}
+		}
+
+		if result == nil {
+			result = tokenScores
+			continue
+		}
+		for id := range result {
+			if s, ok := tokenScores[id]; ok {
+				result[id] += s
+			} else {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// -----------------------------
+//
+//	Query
+//
+// -----------------------------
+type SearchMode int
+
+const (
+	SearchExact SearchMode = iota
+	SearchPrefix
+	SearchFuzzy
+)
+
+type SearchClause struct {
+	Field    string
+	Tokens   []string
+	Mode     SearchMode
+	Distance int
+}
+
+func (c SearchClause) inScope(field string) bool {
+	switch c.Field {
+	case "":
+		return true
+	case SearchFieldCustom:
+		return strings.HasPrefix(field, SearchFieldCustom+".")
+	default:
+		return field == c.Field
+	}
+}
+
+func ParseSearchQuery(query string) ([]SearchClause, error) {
+	clauses := []SearchClause{}
+	for _, raw := range strings.Fields(query) {
+		clause := SearchClause{}
+		if i := strings.Index(raw, ":"); i > 0 {
+			clause.Field = strings.ToLower(raw[:i])
+			raw = raw[i+1:]
+		}
+		switch {
+		case strings.HasSuffix(raw, "*"):
+			clause.Mode = SearchPrefix
+			raw = strings.TrimSuffix(raw, "*")
+		case strings.Contains(raw, "~"):
+			i := strings.LastIndex(raw, "~")
+			clause.Mode = SearchFuzzy
+			if d := raw[i+1:]; d != "" {
+				n, err := strconv.Atoi(d)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("%w: bad fuzzy distance in %q", ErrInvalidSearchQuery, raw)
+				}
+				clause.Distance = n
+			}
+			raw = raw[:i]
+		}
+		clause.Tokens = tokenize(raw)
+		if len(clause.Tokens) == 0 {
+			if clause.Field != "" {
+				return nil, fmt.Errorf("%w: empty term for field %q", ErrInvalidSearchQuery, clause.Field)
+			}
+			continue
+		}
+		if clause.Mode == SearchFuzzy {
+			last := clause.Tokens[len(clause.Tokens)-1]
+			if clause.Distance == 0 {
+				clause.Distance = 1
+				if len([]rune(last)) > 4 {
+					clause.Distance = 2
+				}
+			}
+			if clause.Distance > 2 {
+				clause.Distance = 2
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses, nil
+}
+
+// -----------------------------
+//
+//	Documents
+//
+// -----------------------------
+
+// SearchDocumentFor extracts the searchable, non-secret fields of an entry.
+// Secret columns (passwords, card numbers, private keys, OTP seeds, identity
+// numbers) are never read. Custom record fields are included unless their
+// schema marks them sensitive or, without a schema, their name looks secret.
+func SearchDocumentFor(entry EntryInterface, schemas *RecordSchemaRegistry) SearchDocument {
+	base := entry.GetBase()
+	doc := SearchDocument{
+		EntryID:  base.ID,
+		Type:     base.Type,
+		Name:     base.EntryName,
+		FolderID: base.FolderID,
+		Trashed:  base.Trashed,
+		Stamp:    searchStamp(base),
+		Fields:   map[string][]string{},
+	}
+	add := func(field string, values ...string) {
+		for _, v := range values {
+			doc.Fields[field] = appendUnique(doc.Fields[field], tokenize(v)...)
+		}
+	}
+
+	add(SearchFieldName, base.EntryName)
+	switch e := entry.(type) {
+	case *LoginEntry:
+		add(SearchFieldUsername, e.UserName)
+		add(SearchFieldURL, e.Website)
+	case *CardEntry:
+		add(SearchFieldTags, e.Tags...)
+	case *IdentityEntry:
+		add(SearchFieldUsername, e.Username)
+	case *OTPEntry:
+		add(SearchFieldUsername, e.AccountName)
+		add(SearchFieldIssuer, e.Issuer)
+	}
+
+	rec := recordOf(entry)
+	sensitive := map[string]bool{}
+	known := map[string]bool{}
+	hasSchema := false
+	if schemas != nil && rec.recordType != "" {
+		schema, ok := schemas.Schema(rec.recordType, rec.version)
+		if !ok {
+			schema, ok = schemas.Latest(rec.recordType)
+		}
+		if ok {
+			hasSchema = true
+			for _, f := range schema.Fields {
+				known[f.Name] = true
+			}
+			for _, name := range schema.SensitiveFields() {
+				sensitive[name] = true
+			}
+		}
+	}
+	for name, value := range rec.fields {
+		if sensitive[name] {
+			continue
+		}
+		if (!hasSchema || !known[name]) && looksSecret(name) {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			add(SearchFieldCustom+"."+strings.ToLower(name), v)
+		case float64, int, int64:
+			add(SearchFieldCustom+"."+strings.ToLower(name), fmt.Sprint(v))
+		}
+	}
+
+	for field, tokens := range doc.Fields {
+		if len(tokens) == 0 {
+			delete(doc.Fields, field)
+		}
+	}
+	return doc
+}
+
+func searchStamp(base *BaseEntry) string {
+	return base.CID + "|" + base.UpdatedAt + "|" + strconv.FormatBool(base.Trashed) + "|" + base.FolderID + "|" + base.EntryName
+}
+
+func looksSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, hint := range secretFieldHints {
+		if strings.Contains(name, hint) {
+			return true
+		}
+	}
+	for _, word := range tokenize(name) {
+		if secretFieldWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenize(s string) []string {
+	parts := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := parts[:0]
+	for _, p := range parts {
+		if !searchStopTokens[p] {
+			tokens = append(tokens, p)
+		}
+	}
+	return tokens
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// withinEditDistance reports whether the Levenshtein distance between a and
+// b is at most max.
+func withinEditDistance(a, b string, max int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return false
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > max {
+			return false
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)] <= max
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
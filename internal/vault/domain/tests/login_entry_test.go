@@ -0,0 +1,26 @@
+package vaults_domain_tests
+
+import (
+	"testing"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+func TestLoginEntry_TrackPasswordChange(t *testing.T) {
+	previous := vaults_domain.LoginEntry{Password: "old", PasswordChangedAt: "2024-01-01T00:00:00Z"}
+
+	renamed := previous
+	renamed.EntryName = "renamed"
+	renamed.PasswordChangedAt = ""
+	renamed.TrackPasswordChange(previous, "2026-10-18T12:00:00Z")
+	if renamed.PasswordChangedAt != "2024-01-01T00:00:00Z" {
+		t.Errorf("rename: expected the previous change time, got %q", renamed.PasswordChangedAt)
+	}
+
+	changed := previous
+	changed.Password = "new"
+	changed.TrackPasswordChange(previous, "2026-10-18T12:00:00Z")
+	if changed.PasswordChangedAt != "2026-10-18T12:00:00Z" {
+		t.Errorf("password change: expected now, got %q", changed.PasswordChangedAt)
+	}
+}
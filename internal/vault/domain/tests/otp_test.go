@@ -0,0 +1,192 @@
+package vaults_domain_tests
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// RFC 4226 Appendix D.
+func TestHOTP_RFC4226Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, expected := range want {
+		got, err := vaults_domain.HOTP(key, uint64(counter), 6, vaults_domain.OTPAlgorithmSHA1)
+		if err != nil {
+			t.Fatalf("counter %d: %v", counter, err)
+		}
+		if got != expected {
+			t.Errorf("counter %d: expected %s, got %s", counter, expected, got)
+		}
+	}
+}
+
+// RFC 6238 Appendix B.
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	keys := map[string][]byte{
+		vaults_domain.OTPAlgorithmSHA1:   []byte("12345678901234567890"),
+		vaults_domain.OTPAlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		vaults_domain.OTPAlgorithmSHA512: []byte(strings.Repeat("1234567890", 6) + "1234"),
+	}
+	vectors := []struct {
+		at   int64
+		want map[string]string
+	}{
+		{59, map[string]string{"SHA1": "94287082", "SHA256": "46119246", "SHA512": "90693936"}},
+		{1111111109, map[string]string{"SHA1": "07081804", "SHA256": "68084774", "SHA512": "25091201"}},
+		{1111111111, map[string]string{"SHA1": "14050471", "SHA256": "67062674", "SHA512": "99943326"}},
+		{1234567890, map[string]string{"SHA1": "89005924", "SHA256": "91819424", "SHA512": "93441116"}},
+		{2000000000, map[string]string{"SHA1": "69279037", "SHA256": "90698825", "SHA512": "38618901"}},
+		{20000000000, map[string]string{"SHA1": "65353130", "SHA256": "77737706", "SHA512": "47863826"}},
+	}
+	for _, v := range vectors {
+		for algorithm, expected := range v.want {
+			got, err := vaults_domain.TOTP(keys[algorithm], time.Unix(v.at, 0), 30, 8, algorithm)
+			if err != nil {
+				t.Fatalf("%s at %d: %v", algorithm, v.at, err)
+			}
+			if got != expected {
+				t.Errorf("%s at %d: expected %s, got %s", algorithm, v.at, expected, got)
+			}
+		}
+	}
+}
+
+func TestOTPEntry_GenerateTOTPWindow(t *testing.T) {
+	entry, err := vaults_domain.ParseOTPAuthURI("otpauth://totp/ACME%20Co:alice@acme.test?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=ACME%20Co&digits=8")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	code, err := entry.Generate(time.Unix(1111111111, 0))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if code.Code != "14050471" {
+		t.Fatalf("expected 14050471, got %s", code.Code)
+	}
+	// 1111111111 is 1 s into a 30 s step.
+	if code.RemainingSeconds != 29 || code.ExpiresAt.Unix() != 1111111140 {
+		t.Fatalf("unexpected window: %d s, expires %d", code.RemainingSeconds, code.ExpiresAt.Unix())
+	}
+}
+
+func TestOTPEntry_GenerateHOTPAdvancesCounter(t *testing.T) {
+	entry, err := vaults_domain.ParseOTPAuthURI("otpauth://hotp/alice?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=3")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	code, err := entry.Generate(time.Now())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if code.Code != "969429" || code.Counter != 3 || entry.Counter != 4 {
+		t.Fatalf("unexpected hotp result: code=%s counter=%d next=%d", code.Code, code.Counter, entry.Counter)
+	}
+}
+
+func TestParseOTPAuthURI(t *testing.T) {
+	entry, err := vaults_domain.ParseOTPAuthURI("otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP&algorithm=sha256&period=60")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if entry.Type != vaults_domain.EntryOTP || entry.Issuer != "Example" || entry.AccountName != "alice@google.com" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if entry.Algorithm != "SHA256" || entry.Digits != 6 || entry.Period != 60 {
+		t.Fatalf("unexpected parameters: %s/%d/%d", entry.Algorithm, entry.Digits, entry.Period)
+	}
+	if entry.EntryName != "Example (alice@google.com)" {
+		t.Fatalf("unexpected name %q", entry.EntryName)
+	}
+
+	bad := map[string]error{
+		"https://example.com":                                vaults_domain.ErrOTPInvalidURI,
+		"otpauth://totp/alice":                               vaults_domain.ErrOTPInvalidURI,
+		"otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP":       vaults_domain.ErrOTPInvalidURI,
+		"otpauth://totp/alice?secret=not-base32!":            vaults_domain.ErrOTPInvalidSecret,
+		"otpauth://totp/alice?secret=JBSWY3DP&algorithm=md5": vaults_domain.ErrOTPUnsupportedAlgorithm,
+		"otpauth://totp/alice?secret=JBSWY3DP&digits=12":     vaults_domain.ErrOTPInvalidParameters,
+	}
+	for uri, want := range bad {
+		if _, err := vaults_domain.ParseOTPAuthURI(uri); !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", uri, want, err)
+		}
+	}
+}
+
+// protoField encodes a length-delimited (bytes) or varint protobuf field.
+func protoField(field int, value any) []byte {
+	switch v := value.(type) {
+	case []byte:
+		out := appendVarint(nil, uint64(field<<3|2))
+		out = appendVarint(out, uint64(len(v)))
+		return append(out, v...)
+	case string:
+		return protoField(field, []byte(v))
+	case int:
+		out := appendVarint(nil, uint64(field<<3))
+		return appendVarint(out, uint64(v))
+	}
+	panic("unsupported proto value")
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func TestParseOTPMigrationURI(t *testing.T) {
+	var totp, hotp []byte
+	totp = append(totp, protoField(1, []byte("12345678901234567890"))...)
+	totp = append(totp, protoField(2, "ACME:alice@acme.test")...)
+	totp = append(totp, protoField(3, "ACME")...)
+	totp = append(totp, protoField(4, 1)...) // SHA1
+	totp = append(totp, protoField(5, 2)...) // 8 digits
+	totp = append(totp, protoField(6, 2)...) // TOTP
+
+	hotp = append(hotp, protoField(1, []byte("12345678901234567890"))...)
+	hotp = append(hotp, protoField(2, "bob")...)
+	hotp = append(hotp, protoField(6, 1)...) // HOTP
+	hotp = append(hotp, protoField(7, 5)...)
+
+	var payload []byte
+	payload = append(payload, protoField(1, totp)...)
+	payload = append(payload, protoField(1, hotp)...)
+	payload = append(payload, protoField(2, 1)...) // version
+	payload = append(payload, protoField(3, 1)...) // batch_size
+
+	uri := "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+	entries, err := vaults_domain.ParseOTPImport(uri)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Kind != vaults_domain.OTPKindTOTP || first.Issuer != "ACME" || first.AccountName != "alice@acme.test" || first.Digits != 8 {
+		t.Fatalf("unexpected totp entry: %+v", first)
+	}
+	code, err := first.Generate(time.Unix(59, 0))
+	if err != nil || code.Code != "94287082" {
+		t.Fatalf("expected 94287082, got %v (%v)", code, err)
+	}
+
+	second := entries[1]
+	if second.Kind != vaults_domain.OTPKindHOTP || second.Counter != 5 || second.AccountName != "bob" {
+		t.Fatalf("unexpected hotp entry: %+v", second)
+	}
+	code, err = second.Generate(time.Now())
+	if err != nil || code.Code != "254676" {
+		t.Fatalf("expected 254676, got %v (%v)", code, err)
+	}
+}
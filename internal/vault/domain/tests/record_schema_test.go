@@ -0,0 +1,174 @@
+package vaults_domain_tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+func contractSchemaV1() vaults_domain.RecordSchema {
+	return vaults_domain.RecordSchema{
+		RecordType: "contract",
+		Version:    1,
+		Fields: []vaults_domain.RecordFieldSchema{
+			{Name: "counterparty", Type: vaults_domain.RecordFieldText, Required: true},
+			{Name: "reference", Type: vaults_domain.RecordFieldText, Format: `[A-Z]{3}-\d{4}`},
+			{Name: "signed_on", Type: vaults_domain.RecordFieldDate},
+			{Name: "portal_password", Type: vaults_domain.RecordFieldSecret},
+		},
+	}
+}
+
+func contractSchemaV2() vaults_domain.RecordSchema {
+	return vaults_domain.RecordSchema{
+		RecordType: "contract",
+		Version:    2,
+		Fields: []vaults_domain.RecordFieldSchema{
+			{Name: "counterparty", Type: vaults_domain.RecordFieldText, Required: true},
+			{Name: "reference", Type: vaults_domain.RecordFieldText, Format: `[A-Z]{3}-\d{4}`},
+			{Name: "signed_on", Type: vaults_domain.RecordFieldDate},
+			{Name: "portal_password", Type: vaults_domain.RecordFieldSecret},
+			{Name: "contact_email", Type: vaults_domain.RecordFieldEmail, Required: true},
+			{Name: "auto_renew", Type: vaults_domain.RecordFieldBoolean},
+		},
+	}
+}
+
+func newContractRegistry(t *testing.T) *vaults_domain.RecordSchemaRegistry {
+	t.Helper()
+	reg := vaults_domain.NewRecordSchemaRegistry()
+	if err := reg.Register(contractSchemaV1()); err != nil {
+		t.Fatalf("register v1: %v", err)
+	}
+	if err := reg.Register(contractSchemaV2()); err != nil {
+		t.Fatalf("register v2: %v", err)
+	}
+	return reg
+}
+
+func TestRecordSchema_ValidateReportsEveryField(t *testing.T) {
+	reg := newContractRegistry(t)
+	schema, ok := reg.Schema("contract", 1)
+	if !ok {
+		t.Fatal("expected contract v1")
+	}
+
+	err := schema.Validate(vaults_domain.JSONMap{
+		"reference": "abc-12",
+		"signed_on": "yesterday",
+		"colour":    "blue",
+	})
+	var verr *vaults_domain.RecordValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, vaults_domain.ErrRecordValidation) {
+		t.Fatalf("expected a record validation error, got %v", err)
+	}
+	got := map[string]bool{}
+	for _, f := range verr.Fields {
+		got[f.Field] = true
+	}
+	for _, field := range []string{"counterparty", "reference", "signed_on", "colour"} {
+		if !got[field] {
+			t.Errorf("expected %s to be reported, got %v", field, verr.Fields)
+		}
+	}
+
+	if err := schema.Validate(vaults_domain.JSONMap{
+		"counterparty": "ACME",
+		"reference":    "ACM-2024",
+		"signed_on":    "2024-05-01",
+	}); err != nil {
+		t.Fatalf("expected valid record, got %v", err)
+	}
+
+	if sensitive := schema.SensitiveFields(); len(sensitive) != 1 || sensitive[0] != "portal_password" {
+		t.Fatalf("unexpected sensitive fields: %v", sensitive)
+	}
+}
+
+func TestRecordSchemaRegistry_RejectsInvalidSchemas(t *testing.T) {
+	reg := vaults_domain.NewRecordSchemaRegistry()
+	cases := []vaults_domain.RecordSchema{
+		{Version: 1},
+		{RecordType: "x", Version: 0},
+		{RecordType: "x", Version: 1, Fields: []vaults_domain.RecordFieldSchema{{Name: "a", Type: "colour"}}},
+		{RecordType: "x", Version: 1, Fields: []vaults_domain.RecordFieldSchema{{Name: "a", Type: "text"}, {Name: "a", Type: "text"}}},
+		{RecordType: "x", Version: 1, Fields: []vaults_domain.RecordFieldSchema{{Name: "a", Type: "text", Format: "("}}},
+	}
+	for i, schema := range cases {
+		if err := reg.Register(schema); !errors.Is(err, vaults_domain.ErrRecordSchemaInvalid) {
+			t.Errorf("case %d: expected ErrRecordSchemaInvalid, got %v", i, err)
+		}
+	}
+}
+
+func TestRecordSchemaRegistry_ValidateEntry(t *testing.T) {
+	reg := newContractRegistry(t)
+
+	// Free-form entries are not validated.
+	note := &vaults_domain.NoteEntry{}
+	if err := reg.ValidateEntry(note); err != nil {
+		t.Fatalf("expected free-form entry to pass, got %v", err)
+	}
+
+	entry := &vaults_domain.NoteEntry{}
+	entry.RecordType = "contract"
+	entry.CustomFields = vaults_domain.JSONMap{"counterparty": "ACME", "contact_email": "legal@acme.test"}
+	if err := reg.ValidateEntry(entry); err != nil {
+		t.Fatalf("expected valid entry, got %v", err)
+	}
+	if entry.SchemaVersion != 0 {
+		t.Fatalf("expected validation to leave the entry untouched, got v%d", entry.SchemaVersion)
+	}
+	if !reg.StampSchemaVersion(entry) || entry.SchemaVersion != 2 {
+		t.Fatalf("expected entry to be stamped with v2, got %d", entry.SchemaVersion)
+	}
+	if reg.StampSchemaVersion(entry) {
+		t.Fatal("expected an already versioned entry to be left alone")
+	}
+
+	entry.SchemaVersion = 7
+	if err := reg.ValidateEntry(entry); !errors.Is(err, vaults_domain.ErrUnknownSchemaVersion) {
+		t.Fatalf("expected ErrUnknownSchemaVersion, got %v", err)
+	}
+}
+
+func TestRecordSchemaRegistry_MigrateEntry(t *testing.T) {
+	reg := newContractRegistry(t)
+
+	card := &vaults_domain.CardEntry{
+		RecordType:    "contract",
+		SchemaVersion: "1",
+		Fields:        vaults_domain.JSONMap{"counterparty": "ACME", "reference": "ACM-2024"},
+	}
+
+	if _, err := reg.MigrateEntry(card); !errors.Is(err, vaults_domain.ErrRecordMigrationMissing) {
+		t.Fatalf("expected ErrRecordMigrationMissing, got %v", err)
+	}
+	if card.SchemaVersion != "1" {
+		t.Fatalf("failed migration must leave the entry untouched")
+	}
+
+	err := reg.RegisterMigration("contract", 1, func(fields vaults_domain.JSONMap) (vaults_domain.JSONMap, error) {
+		fields["contact_email"] = "legal@" + strings.ToLower(fields["counterparty"].(string)) + ".test"
+		fields["auto_renew"] = false
+		return fields, nil
+	})
+	if err != nil {
+		t.Fatalf("register migration: %v", err)
+	}
+
+	migrated, err := reg.MigrateEntry(card)
+	if err != nil || !migrated {
+		t.Fatalf("expected migration, got migrated=%v err=%v", migrated, err)
+	}
+	if card.SchemaVersion != "2" || card.Fields["contact_email"] != "legal@acme.test" {
+		t.Fatalf("unexpected migrated card: %s %v", card.SchemaVersion, card.Fields)
+	}
+
+	migrated, err = reg.MigrateEntry(card)
+	if err != nil || migrated {
+		t.Fatalf("expected up-to-date entry to be left alone, got migrated=%v err=%v", migrated, err)
+	}
+}
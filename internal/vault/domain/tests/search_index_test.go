@@ -0,0 +1,201 @@
+package vaults_domain_tests
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+func searchFixture() *vaults_domain.Entries {
+	github := vaults_domain.LoginEntry{UserName: "alicedev", Password: "hunter2-correct-horse", Website: "https://github.com/login"}
+	github.ID, github.Type, github.EntryName = "login-1", vaults_domain.EntryLogin, "GitHub"
+
+	gitlab := vaults_domain.LoginEntry{UserName: "alice", Password: "gitlab-pass", Website: "https://gitlab.example.org"}
+	gitlab.ID, gitlab.Type, gitlab.EntryName = "login-2", vaults_domain.EntryLogin, "GitLab work"
+
+	card := vaults_domain.CardEntry{Owner: "Alice", Number: "4111111111111111", CVC: "737", Tags: []string{"travel", "personal"}}
+	card.ID, card.Type, card.EntryName = "card-1", vaults_domain.EntryCard, "Visa"
+
+	note := vaults_domain.NoteEntry{}
+	note.ID, note.Type, note.EntryName = "note-1", vaults_domain.EntryNote, "Contract ACME"
+	note.RecordType = "contract"
+	note.CustomFields = vaults_domain.JSONMap{
+		"counterparty":    "Acme Corporation",
+		"portal_password": "s3cr3t-portal",
+		"api_key":         "sk_live_abcdef",
+	}
+
+	return &vaults_domain.Entries{
+		Login: []vaults_domain.LoginEntry{github, gitlab},
+		Card:  []vaults_domain.CardEntry{card},
+		Note:  []vaults_domain.NoteEntry{note},
+	}
+}
+
+func searchIDs(t *testing.T, idx *vaults_domain.SearchIndex, query string) []string {
+	t.Helper()
+	hits, err := idx.Search(query, vaults_domain.SearchOptions{})
+	if err != nil {
+		t.Fatalf("search %q: %v", query, err)
+	}
+	ids := []string{}
+	for _, h := range hits {
+		ids = append(ids, h.EntryID)
+	}
+	return ids
+}
+
+func TestSearchIndex_NeverIndexesSecrets(t *testing.T) {
+	entries := searchFixture()
+	idx := vaults_domain.NewSearchIndex()
+	idx.Sync(entries.All(), nil)
+
+	raw, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "horse", "4111111111111111", "737", "s3cr3t", "sk_live", "abcdef"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("secret %q leaked into the index", secret)
+		}
+	}
+	if ids := searchIDs(t, idx, "acme"); len(ids) != 1 || ids[0] != "note-1" {
+		t.Fatalf("expected the contract note, got %v", ids)
+	}
+}
+
+func TestSearchIndex_SchemaSensitiveFieldsAreSkipped(t *testing.T) {
+	reg := vaults_domain.NewRecordSchemaRegistry()
+	err := reg.Register(vaults_domain.RecordSchema{
+		RecordType:      "contract",
+		Version:         1,
+		AllowAdditional: true,
+		Fields: []vaults_domain.RecordFieldSchema{
+			{Name: "counterparty", Type: vaults_domain.RecordFieldText, Sensitive: true},
+			{Name: "portal_password", Type: vaults_domain.RecordFieldText},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := searchFixture()
+	entries.Note[0].SchemaVersion = 1
+	idx := vaults_domain.NewSearchIndex()
+	idx.Sync(entries.All(), reg)
+
+	if ids := searchIDs(t, idx, "custom:acme"); len(ids) != 0 {
+		t.Fatalf("sensitive schema field was indexed: %v", ids)
+	}
+	// Declared non-sensitive by the schema, so indexed despite its name.
+	if ids := searchIDs(t, idx, "custom.portal_password:s3cr3t"); len(ids) != 1 {
+		t.Fatalf("expected schema-declared field to be indexed, got %v", ids)
+	}
+	// Additional, undeclared fields still go through the name check.
+	if ids := searchIDs(t, idx, "sk_live*"); len(ids) != 0 {
+		t.Fatalf("secret-looking additional field was indexed: %v", ids)
+	}
+}
+
+func TestSearchIndex_PrefixFuzzyAndFieldScopedQueries(t *testing.T) {
+	entries := searchFixture()
+	idx := vaults_domain.NewSearchIndex()
+	idx.Sync(entries.All(), nil)
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"github", []string{"login-1"}},
+		{"git*", []string{"login-1", "login-2"}},
+		{"gihtub~", []string{"login-1"}},
+		{"url:gitlab", []string{"login-2"}},
+		{"username:alice", []string{"login-2"}},
+		{"username:alice*", []string{"login-1", "login-2"}},
+		{"name:gitlab work", []string{"login-2"}},
+		{"tags:travel", []string{"card-1"}},
+		{"url:travel", []string{}},
+		{"git* url:github.com", []string{"login-1"}},
+	}
+	for _, tc := range cases {
+		got := searchIDs(t, idx, tc.query)
+		if strings.Join(sorted(got), ",") != strings.Join(sorted(tc.want), ",") {
+			t.Errorf("%q: expected %v, got %v", tc.query, tc.want, got)
+		}
+	}
+
+	// Exact hits rank ahead of prefix hits.
+	if ids := searchIDs(t, idx, "username:alice*"); ids[0] != "login-2" {
+		t.Errorf("expected exact username match first, got %v", ids)
+	}
+
+	if _, err := idx.Search("name:git~x", vaults_domain.SearchOptions{}); !errors.Is(err, vaults_domain.ErrInvalidSearchQuery) {
+		t.Errorf("expected ErrInvalidSearchQuery, got %v", err)
+	}
+}
+
+func TestSearchIndex_SyncIsIncremental(t *testing.T) {
+	entries := searchFixture()
+	idx := vaults_domain.NewSearchIndex()
+	if !idx.Sync(entries.All(), nil) {
+		t.Fatal("expected first sync to change the index")
+	}
+	if idx.Sync(entries.All(), nil) {
+		t.Fatal("expected unchanged entries to be left alone")
+	}
+
+	entries.Login[0].EntryName = "GitHub Enterprise"
+	entries.Login[0].UpdatedAt = "2026-01-01T00:00:00Z"
+	entries.Card = nil
+	if !idx.Sync(entries.All(), nil) {
+		t.Fatal("expected modified entries to be re-indexed")
+	}
+	if ids := searchIDs(t, idx, "enterprise"); len(ids) != 1 || ids[0] != "login-1" {
+		t.Fatalf("expected renamed login, got %v", ids)
+	}
+	if ids := searchIDs(t, idx, "travel"); len(ids) != 0 {
+		t.Fatalf("expected removed card to be dropped, got %v", ids)
+	}
+
+	entries.Login[1].Trashed = true
+	idx.Sync(entries.All(), nil)
+	if ids := searchIDs(t, idx, "gitlab"); len(ids) != 0 {
+		t.Fatalf("expected trashed entry to be hidden, got %v", ids)
+	}
+	hits, err := idx.Search("gitlab", vaults_domain.SearchOptions{IncludeTrashed: true})
+	if err != nil || len(hits) != 1 {
+		t.Fatalf("expected trashed entry with IncludeTrashed, got %v (%v)", hits, err)
+	}
+}
+
+func TestSearchIndex_CloneIsIndependent(t *testing.T) {
+	entries := searchFixture()
+	idx := vaults_domain.NewSearchIndex()
+	idx.Sync(entries.All(), nil)
+	before := sorted(searchIDs(t, idx, "alice"))
+
+	clone := idx.Clone()
+	entries.Login = entries.Login[1:]
+	if !clone.Sync(entries.All(), nil) {
+		t.Fatal("expected clone to drop the removed login")
+	}
+	if ids := searchIDs(t, clone, "github"); len(ids) != 0 {
+		t.Fatalf("expected removed login to be gone from the clone, got %v", ids)
+	}
+	if ids := searchIDs(t, idx, "github"); len(ids) != 1 || ids[0] != "login-1" {
+		t.Fatalf("expected original index to be untouched, got %v", ids)
+	}
+	if ids := sorted(searchIDs(t, idx, "alice")); strings.Join(ids, ",") != strings.Join(before, ",") {
+		t.Fatalf("expected original postings %v to be untouched, got %v", before, ids)
+	}
+}
+
+func sorted(ids []string) []string {
+	out := append([]string(nil), ids...)
+	sort.Strings(out)
+	return out
+}
@@ -72,12 +72,20 @@ func (s *VaultService) BuildEntriesBranch(session vault_session.Session, vp vaul
 	// =========================
 	// 1. BUILD ENTRIES
 	// =========================
-	entries := vp.Personal.Entries
+	// The entry handlers edit the working copy, not Personal.
+	entries := vp.Entries
 	entryLinks, indexByType, indexByFolder, entryUpdates, err := s.BuildEntries(entries, mode)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
 
+	// =========================
+	// 2. SEARCH INDEX
+	// =========================
+	if _, err := s.BuildSearchIndexBranch(entries); err != nil {
+		return "", nil, nil, nil, err
+	}
+
 	
 	// =========================
 	// 4. ENTRIES ROOT
@@ -99,11 +107,19 @@ func (s *VaultService) BuildEntries(entries vaults_domain.Entries, mode SyncMode
 	var entriesUpdate []EntryUpdate
 	policy := resolvePolicy(mode)
 
-	s.processEntryList(loginToInterfaces(entries.Login), &links, byType, byFolder, &entriesUpdate, policy)
-	s.processEntryList(cardToInterfaces(entries.Card), &links, byType, byFolder, &entriesUpdate, policy)
-	s.processEntryList(identityToInterfaces(entries.Identity), &links, byType, byFolder, &entriesUpdate, policy)
-	s.processEntryList(noteToInterfaces(entries.Note), &links, byType, byFolder, &entriesUpdate, policy)
-	s.processEntryList(sshKeyToInterfaces(entries.SSHKey), &links, byType, byFolder, &entriesUpdate, policy)
+	lists := [][]vaults_domain.EntryInterface{
+		loginToInterfaces(entries.Login),
+		cardToInterfaces(entries.Card),
+		identityToInterfaces(entries.Identity),
+		noteToInterfaces(entries.Note),
+		sshKeyToInterfaces(entries.SSHKey),
+		otpToInterfaces(entries.OTP),
+	}
+	for _, list := range lists {
+		if err := s.processEntryList(list, &links, byType, byFolder, &entriesUpdate, policy); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
 
 	return links, byType, byFolder, entriesUpdate, nil
 }
@@ -163,6 +179,13 @@ func (s *VaultService) processEntryList(
 		// =========================
 		// 🔥 REBUILD PATH
 		// =========================
+		if s.Schemas != nil {
+			if err := s.Schemas.ValidateEntry(entry); err != nil {
+				return fmt.Errorf("entry %s: %w", base.ID, err)
+			}
+			s.Schemas.StampSchemaVersion(entry)
+		}
+
 		node := EntryNode{
 			ID:             base.ID,
 			Type:           base.Type,
@@ -231,6 +254,13 @@ func sshKeyToInterfaces(list []vaults_domain.SSHKeyEntry) []vaults_domain.EntryI
 	}
 	return result
 }
+func otpToInterfaces(list []vaults_domain.OTPEntry) []vaults_domain.EntryInterface {
+	result := make([]vaults_domain.EntryInterface, len(list))
+	for i := range list {
+		result[i] = &list[i]
+	}
+	return result
+}
 
 func (s *VaultService) RotateEntryGraph(session vault_session.Session, vp vaults_domain.VaultPayload, mode SyncMode) (string, map[string][]vaults_domain.Link, map[string][]vaults_domain.Link, []EntryUpdate, error) {
 	// mark all entries by type dirty
@@ -249,6 +279,9 @@ func (s *VaultService) RotateEntryGraph(session vault_session.Session, vp vaults
 	for i := range vp.Entries.SSHKey {
 		vp.Entries.SSHKey[i].BaseEntry.IsDirty = true
 	}
+	for i := range vp.Entries.OTP {
+		vp.Entries.OTP[i].BaseEntry.IsDirty = true
+	}
 	// 	↓
 	return s.BuildEntriesBranch(session, vp, mode)
 }
@@ -270,13 +303,17 @@ func (r *VaultReconstructor) resolveEntries(
 		if err != nil {
 			return result, err
 		}
+		raw, err := entryData(res.Raw)
+		if err != nil {
+			return result, err
+		}
 
 		// 1. Detect type first (light struct)
 		var meta struct {
 			Type string `json:"type"`
 		}
 
-		if err := json.Unmarshal(res.Raw, &meta); err != nil {
+		if err := json.Unmarshal(raw, &meta); err != nil {
 			return result, err
 		}
 
@@ -285,39 +322,64 @@ func (r *VaultReconstructor) resolveEntries(
 
 		case "login":
 			var e vaults_domain.LoginEntry
-			if err := json.Unmarshal(res.Raw, &e); err != nil {
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
 				return result, err
 			}
 			result.Login = append(result.Login, e)
 
 		case "card":
 			var e vaults_domain.CardEntry
-			if err := json.Unmarshal(res.Raw, &e); err != nil {
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
 				return result, err
 			}
 			result.Card = append(result.Card, e)
 
 		case "identity":
 			var e vaults_domain.IdentityEntry
-			if err := json.Unmarshal(res.Raw, &e); err != nil {
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
 				return result, err
 			}
 			result.Identity = append(result.Identity, e)
 
 		case "note":
 			var e vaults_domain.NoteEntry
-			if err := json.Unmarshal(res.Raw, &e); err != nil {
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
 				return result, err
 			}
 			result.Note = append(result.Note, e)
 
 		case "sshkey":
 			var e vaults_domain.SSHKeyEntry
-			if err := json.Unmarshal(res.Raw, &e); err != nil {
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
 				return result, err
 			}
 			result.SSHKey = append(result.SSHKey, e)
 
+		case "otp":
+			var e vaults_domain.OTPEntry
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return result, err
+			}
+			if err := r.upgradeRecord(&e); err != nil {
+				return result, err
+			}
+			result.OTP = append(result.OTP, e)
+
 		default:
 			return result, fmt.Errorf("unknown entry type: %s", meta.Type)
 		}
@@ -325,3 +387,35 @@ func (r *VaultReconstructor) resolveEntries(
 
 	return result, nil
 }
+
+// entryData returns the entry an EntryNode carries in Data. Nodes written
+// before entries were wrapped are the entry itself.
+func entryData(raw []byte) ([]byte, error) {
+	var node struct {
+		Data json.RawMessage
+	}
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Data) == 0 || string(node.Data) == "null" {
+		return raw, nil
+	}
+	return node.Data, nil
+}
+
+// upgradeRecord migrates a custom record written under an older schema
+// version. Upgraded entries are marked dirty so the next commit persists
+// them under the current version.
+func (r *VaultReconstructor) upgradeRecord(entry vaults_domain.EntryInterface) error {
+	if r.Schemas == nil {
+		return nil
+	}
+	migrated, err := r.Schemas.MigrateEntry(entry)
+	if err != nil {
+		return fmt.Errorf("entry %s: %w", entry.GetBase().ID, err)
+	}
+	if migrated {
+		entry.GetBase().IsDirty = true
+	}
+	return nil
+}
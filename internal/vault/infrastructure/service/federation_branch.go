@@ -24,10 +24,17 @@ type FederationNode struct {
 type RemoteVaultNode struct {
 	Version string `json:"version"`
 
-	VaultID    string                   `json:"vault_id"`
-	LastCursor uint64                   `json:"last_cursor"`
-	LastSeen   time.Time                `json:"last_seen"`
-	TrustState vaults_domain.TrustState `json:"trust_state"`
+	VaultID          string                   `json:"vault_id"`
+	LastCursor       uint64                   `json:"last_cursor"`
+	LastSeen         time.Time                `json:"last_seen"`
+	Endpoint         string                   `json:"endpoint,omitempty"`
+	TrustState       vaults_domain.TrustState `json:"trust_state"`
+	ProtocolVersion  string                   `json:"protocol_version,omitempty"`
+	PublicKey        string                   `json:"public_key,omitempty"`
+	PresentedKey     string                   `json:"presented_key,omitempty"`
+	TrustChangedAt   time.Time                `json:"trust_changed_at,omitempty"`
+	TrustReason      string                   `json:"trust_reason,omitempty"`
+	IdentityIssuedAt time.Time                `json:"identity_issued_at,omitempty"`
 
 	PendingSync PendingSyncNode `json:"pending"`
 }
@@ -126,12 +133,19 @@ func (s *VaultService) BuildRemoteVaults(vaults []vaults_domain.RemoteVault) ([]
 	for _, remote := range vaults {
 
 		node := RemoteVaultNode{
-			Version:     "1.0",
-			VaultID:     remote.VaultID,
-			LastCursor:  remote.LastCursor,
-			LastSeen:    remote.LastSeen,
-			TrustState:  remote.TrustState,
-			PendingSync: PendingSyncNode{Items: remote.Pending},
+			Version:          "1.0",
+			VaultID:          remote.VaultID,
+			LastCursor:       remote.LastCursor,
+			LastSeen:         remote.LastSeen,
+			Endpoint:         remote.Endpoint,
+			TrustState:       remote.TrustState,
+			ProtocolVersion:  remote.ProtocolVersion,
+			PublicKey:        remote.PublicKey,
+			PresentedKey:     remote.PresentedKey,
+			TrustChangedAt:   remote.TrustChangedAt,
+			TrustReason:      remote.TrustReason,
+			IdentityIssuedAt: remote.IdentityIssuedAt,
+			PendingSync:      PendingSyncNode{Items: remote.Pending},
 		}
 
 		cid, _, err := s.putNode(node)
@@ -188,7 +202,6 @@ func (r *VaultReconstructor) resolveFederation(
 ) (vaults_domain.FederationSnapshot, error) {
 	var snapshot vaults_domain.FederationSnapshot
 
-
 	fmt.Printf("FederationNode type: %+v\n", node)
 	utils.LogPretty(
 		"FEDERATION NODE",
@@ -270,13 +283,23 @@ func (r *VaultReconstructor) resolveRemoteVaults(
 			len(remoteRoot.Items),
 		)
 
+		protocolVersion := node.ProtocolVersion
+		if protocolVersion == "" {
+			protocolVersion = node.Version
+		}
 		result = append(result, vaults_domain.RemoteVault{
-			VaultID:         node.VaultID,
-			LastCursor:      node.LastCursor,
-			LastSeen:        node.LastSeen,
-			TrustState:      node.TrustState,
-			Pending:         node.PendingSync.Items,
-			ProtocolVersion: node.Version,
+			VaultID:          node.VaultID,
+			LastCursor:       node.LastCursor,
+			LastSeen:         node.LastSeen,
+			Endpoint:         node.Endpoint,
+			TrustState:       node.TrustState,
+			Pending:          node.PendingSync.Items,
+			ProtocolVersion:  protocolVersion,
+			PublicKey:        node.PublicKey,
+			PresentedKey:     node.PresentedKey,
+			TrustChangedAt:   node.TrustChangedAt,
+			TrustReason:      node.TrustReason,
+			IdentityIssuedAt: node.IdentityIssuedAt,
 		})
 	}
 
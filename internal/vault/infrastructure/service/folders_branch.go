@@ -21,7 +21,7 @@ func (s *VaultService) BuildFoldersBranch(session vault_session.Session, vp vaul
 	// =========================
 	// 1. BUILD ENTRIES
 	// =========================
-	folders := vp.Personal.Folders
+	folders := vp.Folders
 	folderLinks, err := s.BuildFolders(folders)
 	if err != nil {
 		return "", err
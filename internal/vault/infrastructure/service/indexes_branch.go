@@ -16,6 +16,7 @@ func (s *VaultService) buildIndex(byType, byFolder map[string][]vaults_domain.Li
 	index := vaults_domain.Index{
 		ByType:   byType,
 		ByFolder: byFolder,
+		Search:   vaults_domain.Link{CID: s.searchIndexCID},
 	}
 	return s.putNode(index)
 }
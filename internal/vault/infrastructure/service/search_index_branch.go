@@ -0,0 +1,122 @@
+package vaults_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	vault_queries "vault-app/internal/vault/application/queries"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_infrastructure_crypto "vault-app/internal/vault/infrastructure/crypto"
+)
+
+// =======================================================================================
+// WRITE
+// =======================================================================================
+
+// BuildSearchIndexBranch syncs s.SearchIndex with entries and stores it as
+// an encrypted SearchIndexNode. Only entries whose stamp changed since the
+// last build are re-tokenized; when nothing changed the previous node is
+// reused. Without an index key the search index is skipped.
+func (s *VaultService) BuildSearchIndexBranch(entries vaults_domain.Entries) (string, error) {
+	if s.IndexKey == nil {
+		return "", nil
+	}
+	if s.SearchIndex == nil {
+		s.SearchIndex = vaults_domain.NewSearchIndex()
+	}
+
+	changed := s.SearchIndex.Sync(entries.All(), s.Schemas)
+	if !changed && s.searchIndexCID != "" && s.searchIndexKeyVersion == s.IndexKey.Version {
+		return s.searchIndexCID, nil
+	}
+
+	plain, err := json.Marshal(s.SearchIndex)
+	if err != nil {
+		return "", err
+	}
+	if s.Encryptor == nil {
+		return "", errors.New("VaultService - BuildSearchIndexBranch - encryptor is nil")
+	}
+	sealed, err := s.Encryptor.Encrypt(plain, s.IndexKey.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("VaultService - BuildSearchIndexBranch - failed to encrypt search index: %w", err)
+	}
+
+	cid, _, err := s.putNode(vaults_domain.SearchIndexNode{
+		Type:       "search_index",
+		KeyType:    vaults_domain.KeyTypeIndex,
+		KeyVersion: s.IndexKey.Version,
+		Ciphertext: sealed,
+	})
+	if err != nil {
+		return "", err
+	}
+	s.searchIndexCID = cid
+	s.searchIndexKeyVersion = s.IndexKey.Version
+	return cid, nil
+}
+
+// RotateSearchIndexKey re-seals the search index under a new index key.
+func (s *VaultService) RotateSearchIndexKey(entries vaults_domain.Entries, key *vaults_domain.EncryptedKey) (string, error) {
+	s.IndexKey = key
+	s.searchIndexCID = ""
+	return s.BuildSearchIndexBranch(entries)
+}
+
+// =======================================================================================
+// READ
+// =======================================================================================
+
+// ResolveSearchIndex fetches and decrypts the search index linked from
+// index. The keyring must hold the KeyTypeIndex key version the node was
+// sealed with.
+func (r *VaultReconstructor) ResolveSearchIndex(
+	ctx context.Context,
+	cmd vault_queries.GetIPFSDataQuerry,
+	index vaults_domain.Index,
+	keyring *vaults_domain.VaultKeyring,
+) (*vaults_domain.SearchIndex, error) {
+	if index.Search.CID == "" {
+		return vaults_domain.NewSearchIndex(), nil
+	}
+
+	res, err := r.Query.Execute(ctx, cmd.WithCID(index.Search.CID))
+	if err != nil {
+		return nil, err
+	}
+	var node vaults_domain.SearchIndexNode
+	if err := json.Unmarshal(res.Raw, &node); err != nil {
+		return nil, err
+	}
+
+	key := indexKey(keyring, node.KeyVersion)
+	if key == nil {
+		return nil, fmt.Errorf("no %s key version %d in keyring", vaults_domain.KeyTypeIndex, node.KeyVersion)
+	}
+	crypto := &vault_infrastructure_crypto.AESService{}
+	plain, err := crypto.Decrypt(node.Ciphertext, key.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt search index: %w", err)
+	}
+
+	var idx vaults_domain.SearchIndex
+	if err := json.Unmarshal(plain, &idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
+
+func indexKey(keyring *vaults_domain.VaultKeyring, version int) *vaults_domain.EncryptedKey {
+	if keyring == nil {
+		return nil
+	}
+	for i := range keyring.Keys {
+		k := &keyring.Keys[i]
+		if k.Type == vaults_domain.KeyTypeIndex && k.Version == version {
+			return k
+		}
+	}
+	return nil
+}
@@ -17,6 +17,9 @@ type QueryExecutor interface {
 
 type VaultReconstructor struct {
 	Query QueryExecutor
+	// Schemas upgrades custom record entries to their latest schema version
+	// as they are loaded; nil loads entries as stored.
+	Schemas *vaults_domain.RecordSchemaRegistry
 }
 
 func NewVaultReconstructor(q *vault_queries.GetIPFSDataQuerryHandler) *VaultReconstructor {
@@ -49,6 +52,37 @@ func (r *VaultReconstructor) BuildFromRoot(
 	log.Println("RAW ROOT LENGTH", len(rootRes.Raw))
 	log.Println("RAW ROOT FIRST BYTES", rootRes.Raw[:20])
 
+	// Init Guard - TODO: change the condition
+	// A vault that was never synced has no sub-nodes to fetch.
+	if root.Version == "" {
+		// FIRST SYNC CASE
+		emptyVp := emptyVaultPayload(cmd.VaultName, "1.0.0")
+		return vaults_domain.VaultPayload{
+			Version: rootRes.Data.Version,
+			Name:    rootRes.Data.Name,
+			// BaseVaultContent: vaults_domain.BaseVaultContent{
+			// 	Entries: emptyVp.Entries,
+			// 	Folders: emptyVp.Folders, // TODO: risky...
+			// },
+			Personal: vaults_domain.BaseVaultContent{
+				Entries: emptyVp.Entries,
+				Folders: emptyVp.Folders, // TODO: risky...
+			},
+			Collaborative: vaults_domain.C3VaultContent{
+				Workspaces:        emptyVp.Collaborative.Workspaces,
+				Channels:          emptyVp.Collaborative.Channels,
+				Threads:           emptyVp.Collaborative.Threads,
+				ShareEntries:      emptyVp.Collaborative.ShareEntries,
+				TrustGroups:       emptyVp.Collaborative.TrustGroups,
+				TrustGroupMembers: emptyVp.Collaborative.TrustGroupMembers,
+				Federation:        emptyVp.Collaborative.Federation,
+				Participants:      emptyVp.Collaborative.Participants,
+				Assets:            emptyVp.Collaborative.Assets,
+				Index:             emptyVp.Collaborative.Index,
+			},
+		}, nil
+	}
+
 	// -----------------------------
 	// 2. PERSONAL
 	// -----------------------------
@@ -80,36 +114,6 @@ func (r *VaultReconstructor) BuildFromRoot(
 	}
 	// utils.LogPretty("collaborativeRoot after unmarshal", collaborativeRoot)
 
-	// Init Guard - TODO: change the condition
-	if root.Version == "" {
-		// FIRST SYNC CASE
-		emptyVp := emptyVaultPayload(cmd.VaultName, "1.0.0")
-		return vaults_domain.VaultPayload{
-			Version: rootRes.Data.Version,
-			Name:    rootRes.Data.Name,
-			// BaseVaultContent: vaults_domain.BaseVaultContent{
-			// 	Entries: emptyVp.Entries,
-			// 	Folders: emptyVp.Folders, // TODO: risky...
-			// },
-			Personal: vaults_domain.BaseVaultContent{
-				Entries: emptyVp.Entries,
-				Folders: emptyVp.Folders, // TODO: risky...
-			},
-			Collaborative: vaults_domain.C3VaultContent{
-				Workspaces:        emptyVp.Collaborative.Workspaces,
-				Channels:          emptyVp.Collaborative.Channels,
-				Threads:           emptyVp.Collaborative.Threads,
-				ShareEntries:      emptyVp.Collaborative.ShareEntries,
-				TrustGroups:       emptyVp.Collaborative.TrustGroups,
-				TrustGroupMembers: emptyVp.Collaborative.TrustGroupMembers,
-				Federation:        emptyVp.Collaborative.Federation,
-				Participants:      emptyVp.Collaborative.Participants,
-				Assets:            emptyVp.Collaborative.Assets,
-				Index:             emptyVp.Collaborative.Index,
-			},
-		}, nil
-	}
-
 	personalVault, err := r.resolvePersonalPart(ctx, cmd, personalRoot)
 	if err != nil {
 		utils.LogPretty("VaultReconstructor - personalVault - failed to reconstruct personalVault %v", err)
@@ -121,15 +125,18 @@ func (r *VaultReconstructor) BuildFromRoot(
 		return vaults_domain.VaultPayload{}, err
 	}
 
+	personal := vaults_domain.BaseVaultContent{
+		Entries:     personalVault.entries,
+		Folders:     personalVault.folders,
+		Attachments: personalVault.attachments,
+		Index:       personalVault.index,
+	}
 	return vaults_domain.VaultPayload{
 		Version: root.Version,
 		Name:    "reconstructed",
-		Personal: vaults_domain.BaseVaultContent{
-			Entries:     personalVault.entries,
-			Folders:     personalVault.folders,
-			Attachments: personalVault.attachments,
-			Index:       personalVault.index,
-		},
+		// The session edits the embedded working copy; start it from the DAG.
+		BaseVaultContent: personal,
+		Personal:         personal,
 		Collaborative: vaults_domain.C3VaultContent{
 			Workspaces:        collaborativeVault.Workspaces,
 			Channels:          collaborativeVault.Channels,
@@ -90,6 +90,17 @@ type VaultService struct {
 	DraftStorage DraftStorage
 	Personal     string
 	C3           string
+	// Schemas validates custom record entries before they are written;
+	// nil skips validation.
+	Schemas *vaults_domain.RecordSchemaRegistry
+	// SearchIndex is kept across commits so only modified entries are
+	// re-indexed; IndexKey is the KeyTypeIndex key sealing it. A nil
+	// IndexKey leaves the search index out of the DAG.
+	SearchIndex *vaults_domain.SearchIndex
+	IndexKey    *vaults_domain.EncryptedKey
+
+	searchIndexCID        string
+	searchIndexKeyVersion int
 }
 
 func NewVaultServiceDryRun(
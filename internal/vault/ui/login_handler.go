@@ -53,6 +53,12 @@ func (h *LoginHandler) Add(userID string, anEntry any) (*vaults_domain.VaultPayl
 		return nil, fmt.Errorf("vault not initialized for user %s", userID)
 	}
 	entry.ID = uuid.New().String() // Ensure entry has a UUID
+	if entry.PasswordChangedAt == "" {
+		entry.PasswordChangedAt = entry.CreatedAt
+	}
+	if entry.PasswordChangedAt == "" {
+		entry.PasswordChangedAt = h.NowUTC()
+	}
 	// 2. ---------- Add entry to vault ----------
 	h.Vault.Entries.Login = append(h.Vault.Entries.Login, *entry)
 	h.logger.Info("✅ Added login entry for user %s: %s\n", userID, entry.EntryName)
@@ -76,6 +82,10 @@ func (h *LoginHandler) Edit(userID string, entry any) (*vaults_domain.VaultPaylo
 
 	for i, entry := range entries {
 		if entry.ID == updatedEntry.ID {
+			// A synced entry carries its own password change time.
+			if !h.SyncMode || updatedEntry.PasswordChangedAt == "" {
+				updatedEntry.TrackPasswordChange(entry, h.NowUTC())
+			}
 			// Update the fields (you could also do a full replace)
 			entries[i] = *updatedEntry
 			updatedEntry.IsDraft = true
@@ -168,6 +178,7 @@ func (h *LoginHandler) EditWithAttachments(userID string, entry any, attachments
 	// 4. ---------- Update entry ----------
 	for i, entry := range entries {
 		if entry.ID == updatedEntry.ID {
+			updatedEntry.TrackPasswordChange(entry, h.NowUTC())
 			// Update the fields (you could also do a full replace)
 			updatedEntry = updatedEntry.AddAttachments(entryAttachments)
 			entries[i] = *updatedEntry
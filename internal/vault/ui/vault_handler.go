@@ -75,6 +75,17 @@ type VaultHandler struct {
 	Vault                vaults_domain.VaultPayload
 	GetVaultSessionFunc  func(userID string) (*vaults_domain.VaultPayload, error)
 	UpdateEntryForFunc   func(userID string, entry any, isSyncMode bool) (*vaults_domain.VaultEntry, error)
+	// SubmitCIDFunc anchors a synced CID; nil submits it to Stellar.
+	SubmitCIDFunc func(secretKey string, cid string) (string, error)
+
+	// searchIndexes holds the search index of each unlocked vault, loaded
+	// from its DAG node at unlock and dropped on lock.
+	searchMu      sync.Mutex
+	searchIndexes map[string]*vaults_domain.SearchIndex
+
+	// otpLocks serializes code generation per user and entry, so an HOTP
+	// counter is read and advanced by one call at a time.
+	otpLocks sync.Map
 }
 
 func NewVaultHandler(
@@ -117,6 +128,9 @@ func NewVaultHandler(
 	sf := blockchain_ipfs.DefaultStorageFactory{}
 	ipfsDataQueryHandler := vault_queries.NewGetIPFSDataQuerryHandler(crypto, vc, &sf, &unlockVaultHandler)
 	reconstructor := vaults_service.NewVaultReconstructor(ipfsDataQueryHandler)
+	if entriesRegistry != nil {
+		reconstructor.Schemas = entriesRegistry.RecordSchemas()
+	}
 
 	return &VaultHandler{
 		DB:                              db,
@@ -227,6 +241,9 @@ func (vh *VaultHandler) SessionAttachRuntime(ctx context.Context, req vault_comm
 	return nil
 }
 func (vh *VaultHandler) LogoutUser(userID string) error {
+	// The index is dropped once the session is gone, so that a commit in
+	// flight cannot put it back.
+	defer vh.setSearchIndex(userID, nil)
 	return vh.SessionManager.LogoutUser(userID)
 }
 func (vh *VaultHandler) GetVaultSession(userID string) (*vaults_domain.VaultPayload, error) {
@@ -291,6 +308,7 @@ func (vh *VaultHandler) Open(
 		vh.logger.Error("❌ OpenVault - opening vault for user %s: %v", req.UserID, err)
 		return nil, err
 	}
+	vh.loadSearchIndex(ctx, req, res, appConfigHandler)
 
 	return res, nil
 }
@@ -323,6 +341,9 @@ func (vh *VaultHandler) AddEntryFor(userID string, entry any) (*vaults_domain.Va
 	// 2.3 ---------- Add entry to vault ----------
 	handler.SetSession(session)
 	sessionWithNewEntry, err := handler.Add(userID, entry) // (vault, new_entry)
+	if err != nil {
+		return nil, err
+	}
 	vh.logger.Info("✅ Created %s entry for user %s", entryType, userID)
 	// 4. ---------- Update session ----------
 	vh.SessionManager.SetVault(userID, sessionWithNewEntry)
@@ -450,6 +471,215 @@ func (vh *VaultHandler) UpdateEntry(userID string, entryType string, raw json.Ra
 	return vh.UpdateEntryFor(userID, parsed, isSyncMode)
 }
 
+// -----------------------------
+// Vault - OTP
+// -----------------------------
+// ImportOTPAuthURI adds the seeds described by an otpauth:// URI or a Google
+// Authenticator export. linkedLoginID, when set, must name an existing login.
+// Every seed is validated before the session is touched, so a bad seed
+// leaves the vault unchanged.
+func (vh *VaultHandler) ImportOTPAuthURI(userID string, uri string, folderID string, linkedLoginID string) ([]*vaults_domain.OTPEntry, error) {
+	entries, err := vaults_domain.ParseOTPImport(uri)
+	if err != nil {
+		vh.logger.Error("❌ VaultHandler - ImportOTPAuthURI - failed to parse uri for user %s: %v", userID, err)
+		return nil, err
+	}
+	vp, err := vh.GetVaultSession(userID)
+	if err != nil {
+		return nil, err
+	}
+	if linkedLoginID != "" && !hasLogin(vp, linkedLoginID) {
+		return nil, fmt.Errorf("%w: %s", vaults_domain.ErrOTPLinkedLoginNotFound, linkedLoginID)
+	}
+	for _, entry := range entries {
+		entry.ID = uuid.New().String()
+		entry.FolderID = folderID
+		entry.LinkedLoginID = linkedLoginID
+		if err := entry.Normalize(); err != nil {
+			vh.logger.Error("❌ VaultHandler - ImportOTPAuthURI - invalid seed %q for user %s: %v", entry.EntryName, userID, err)
+			return nil, err
+		}
+	}
+	for _, entry := range entries {
+		if err := vp.AddEntry(string(vaults_domain.EntryOTP), entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := vh.SessionManager.SetVault(userID, vp); err != nil {
+		return nil, err
+	}
+	vh.SessionManager.MarkDirty(userID)
+	return entries, nil
+}
+
+// GenerateOTPCode computes the current code for an OTP entry. HOTP entries
+// advance their counter, which is written back to the session.
+func (vh *VaultHandler) GenerateOTPCode(userID string, entryID string) (*vaults_domain.OTPCode, error) {
+	lock, _ := vh.otpLocks.LoadOrStore(userID+"/"+entryID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	vp, err := vh.GetVaultSession(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vp.Entries.OTP {
+		entry := vp.Entries.OTP[i]
+		if entry.ID != entryID {
+			continue
+		}
+		if entry.Trashed {
+			return nil, fmt.Errorf("entry with ID %s is trashed", entryID)
+		}
+		code, err := entry.Generate(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if entry.Kind == vaults_domain.OTPKindHOTP {
+			if _, err := vh.UpdateEntryFor(userID, &entry, false); err != nil {
+				return nil, err
+			}
+		}
+		return code, nil
+	}
+	return nil, fmt.Errorf("otp entry with ID %s not found", entryID)
+}
+
+func hasLogin(vp *vaults_domain.VaultPayload, entryID string) bool {
+	for _, login := range vp.Entries.Login {
+		if login.ID == entryID && !login.Trashed {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------
+// Vault - Search
+// -----------------------------
+// SearchEntries runs query against the search index loaded at unlock. Only
+// the entries changed in the session since the index was built are
+// re-tokenized.
+func (vh *VaultHandler) SearchEntries(userID string, query string, opts vaults_domain.SearchOptions) ([]vaults_domain.SearchHit, error) {
+	vp, err := vh.GetVaultSession(userID)
+	if err != nil {
+		return nil, err
+	}
+	vh.searchMu.Lock()
+	defer vh.searchMu.Unlock()
+	idx := vh.searchIndexes[userID]
+	if idx == nil {
+		// Vault opened without a readable index node: build it once.
+		idx = vaults_domain.NewSearchIndex()
+		vh.storeSearchIndex(userID, idx)
+	}
+	idx.Sync(vp.Entries.All(), vh.recordSchemas())
+	return idx.Search(query, opts)
+}
+
+// loadSearchIndex resolves the search index node of the vault just opened.
+// Without a node, or when it cannot be read, the index is rebuilt from the
+// session on the first search.
+func (vh *VaultHandler) loadSearchIndex(
+	ctx context.Context,
+	req vault_commands.OpenVaultCommand,
+	res *vault_commands.OpenVaultResult,
+	configFacade vault_commands.AppConfigFacade,
+) {
+	vh.setSearchIndex(req.UserID, nil)
+	if res == nil || res.Content == nil || res.Content.Personal.Index.Search.CID == "" || vh.KeyringService == nil {
+		return
+	}
+	vault := res.Vault
+	if vault == nil {
+		v, err := vh.VaultRepository.GetLatestByUserID(req.UserID)
+		if err != nil {
+			vh.logger.Error("❌ VaultHandler - loadSearchIndex - failed to get vault: %v", err)
+			return
+		}
+		vault = v
+	}
+	cfgs, err := configFacade.GetConfig(req.UserID, *vault, &req.Subscription)
+	if err != nil || cfgs == nil {
+		vh.logger.Error("❌ VaultHandler - loadSearchIndex - failed to get config: %v", err)
+		return
+	}
+	kr, err := vh.KeyringService.LoadHybrid(req.UserOnboardingID, req.Password, "")
+	if err != nil {
+		vh.logger.Error("❌ VaultHandler - loadSearchIndex - failed to load keyring: %v", err)
+		return
+	}
+	idx, err := vh.Reconstructor.ResolveSearchIndex(ctx, vault_queries.GetIPFSDataQuerry{
+		Password:         req.Password,
+		Configs:          *cfgs,
+		UserID:           req.UserID,
+		VaultName:        vault.Name,
+		UserOnboardingID: req.UserOnboardingID,
+	}, res.Content.Personal.Index, kr)
+	if err != nil {
+		vh.logger.Error("❌ VaultHandler - loadSearchIndex - failed to resolve search index: %v", err)
+		return
+	}
+	vh.setSearchIndex(req.UserID, idx)
+}
+
+// setSearchIndex replaces the index of userID; nil drops it.
+func (vh *VaultHandler) setSearchIndex(userID string, idx *vaults_domain.SearchIndex) {
+	vh.searchMu.Lock()
+	defer vh.searchMu.Unlock()
+	vh.storeSearchIndex(userID, idx)
+}
+
+// storeSearchIndex is setSearchIndex for callers holding searchMu.
+func (vh *VaultHandler) storeSearchIndex(userID string, idx *vaults_domain.SearchIndex) {
+	if idx == nil {
+		delete(vh.searchIndexes, userID)
+		return
+	}
+	if vh.searchIndexes == nil {
+		vh.searchIndexes = map[string]*vaults_domain.SearchIndex{}
+	}
+	vh.searchIndexes[userID] = idx
+}
+
+// searchIndexSnapshot returns a copy of the index of userID for a commit to
+// sync, so searches are not blocked while it uploads.
+func (vh *VaultHandler) searchIndexSnapshot(userID string) *vaults_domain.SearchIndex {
+	vh.searchMu.Lock()
+	defer vh.searchMu.Unlock()
+	if idx := vh.searchIndexes[userID]; idx != nil {
+		return idx.Clone()
+	}
+	return vaults_domain.NewSearchIndex()
+}
+
+// searchIndexKey returns the latest KeyTypeIndex key of the keyring,
+// generating one on first use or when rotate is set. On failure the commit
+// goes ahead without a search index node.
+func (vh *VaultHandler) searchIndexKey(userOnboardingID string, password string, rotate bool) *vaults_domain.EncryptedKey {
+	if vh.KeyringService == nil || password == "" {
+		return nil
+	}
+	kr, err := vh.KeyringService.LoadHybrid(userOnboardingID, password, "")
+	if err != nil {
+		vh.logger.Error("❌ VaultHandler - searchIndexKey - failed to load keyring: %v", err)
+		return nil
+	}
+	if key := kr.GetLatestKey(vaults_domain.KeyTypeIndex); key != nil && !rotate {
+		return key
+	}
+	key, err := vh.KeyringService.AddKey(kr, vaults_domain.KeyTypeIndex)
+	if err != nil {
+		vh.logger.Error("❌ VaultHandler - searchIndexKey - failed to create index key: %v", err)
+		return nil
+	}
+	if err := vh.KeyringService.SaveHybrid(kr, userOnboardingID, password, ""); err != nil {
+		vh.logger.Error("❌ VaultHandler - searchIndexKey - failed to save keyring: %v", err)
+		return nil
+	}
+	return key
+}
+
 // TODO: replaced by AddAttachments()
 func (vh *VaultHandler) UpdateEntryWithAttachments(userID string, entryType string, raw json.RawMessage, vaultName string, attachments []vault_dto.SelectedAttachment) (*vaults_domain.VaultEntry, error) {
 	parsed, err := vh.EntryRegistry.UnmarshalEntry(strings.ToLower(entryType), raw)
@@ -754,6 +984,18 @@ func (vh *VaultHandler) UpdateWorkspaceSession(userID string, vp vaults_domain.V
 
 
 func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.SynchronizeVaultRequest, tc *tracecore.TracecoreClient) (string, error) {
+	return vh.SyncVaultWithProgress(ctx, input, func(event string, data map[string]interface{}) {
+		runtime.EventsEmit(ctx, event, data)
+	})
+}
+
+// SyncVaultWithProgress is SyncVault reporting its progress to progress
+// instead of the Wails frontend, which headless callers do not have. A nil
+// progress drops it.
+func (vh *VaultHandler) SyncVaultWithProgress(ctx context.Context, input vault_dto.SynchronizeVaultRequest, progress func(event string, data map[string]interface{})) (string, error) {
+	if progress == nil {
+		progress = func(string, map[string]interface{}) {}
+	}
 	// 0. Initialisation - Guard
 	// ========================================================================================================
 	vh.logger.Info("🔄 Starting vault sync for UserID: %s", input.UserID)
@@ -766,7 +1008,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 1. Get session
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 10, "stage": "retrieving session"})
+	progress("progress-update", map[string]interface{}{"percent": 10, "stage": "retrieving session"})
 
 	vh.logger.Info("🔄 SyncVault - Retrieving session for UserID: %s", userID)
 	session, err := vh.GetSession(userID)
@@ -777,7 +1019,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 2. Marshal vault
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 20, "stage": "marshalling vault"})
+	progress("progress-update", map[string]interface{}{"percent": 20, "stage": "marshalling vault"})
 
 	vaultBytes, err := json.Marshal(session.Vault)
 	if err != nil {
@@ -786,7 +1028,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 3. Encrypt vault
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 40, "stage": "encrypting vault"})
+	progress("progress-update", map[string]interface{}{"percent": 40, "stage": "encrypting vault"})
 
 	encrypted, err := blockchain.Encrypt(vaultBytes, password)
 	if err != nil {
@@ -796,7 +1038,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 4. Upload to IPFS
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 70, "stage": "uploading to IPFS"})
+	progress("progress-update", map[string]interface{}{"percent": 70, "stage": "uploading to IPFS"})
 
 	newCID, entryUpdates, _, _, err := vh.CommitVault(input, *session)
 	if err != nil {
@@ -807,10 +1049,14 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 5. Submit to Stellar
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 90, "stage": "submitting to Stellar"})
+	progress("progress-update", map[string]interface{}{"percent": 90, "stage": "submitting to Stellar"})
 
 	userCfg := session.Runtime.UserConfig
-	txHash, err := blockchain.SubmitCID(userCfg.StellarAccount.PrivateKey, newCID)
+	submitCID := vh.SubmitCIDFunc
+	if submitCID == nil {
+		submitCID = blockchain.SubmitCID
+	}
+	txHash, err := submitCID(userCfg.StellarAccount.PrivateKey, newCID)
 	if err != nil {
 		return "", fmt.Errorf("SyncVault - stellar submission failed: %w", err)
 	}
@@ -818,7 +1064,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 6. Create new vault
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 95, "stage": "saving metadata"})
+	progress("progress-update", map[string]interface{}{"percent": 95, "stage": "saving metadata"})
 
 	currentMeta, err := vh.VaultRepository.GetLatestByUserID(userID)
 	if err != nil {
@@ -843,7 +1089,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 7. Update session
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "progress-update", map[string]interface{}{"percent": 100, "stage": "complete"})
+	progress("progress-update", map[string]interface{}{"percent": 100, "stage": "complete"})
 
 	vh.SessionManager.Sync(userID, newCID)
 	vaultPayload, err := vh.GetVaultPayload(session)
@@ -855,7 +1101,7 @@ func (vh *VaultHandler) SyncVault(ctx context.Context, input vault_dto.Synchroni
 
 	// 8. Emit event
 	// ========================================================================================================
-	runtime.EventsEmit(ctx, "vault-synced", map[string]interface{}{"userID": userID, "newCID": newCID})
+	progress("vault-synced", map[string]interface{}{"userID": userID, "newCID": newCID})
 
 	return newCID, nil
 }
@@ -900,9 +1146,35 @@ func (vh *VaultHandler) CommitVault(
 
 	mode := vaults_service.IncrementalSync
 
+	// The commit syncs a copy of the search index, kept once it succeeds
+	// unless the vault was locked in the meantime.
+	service.SearchIndex = vh.searchIndexSnapshot(input.UserID)
+	service.IndexKey = vh.searchIndexKey(input.UserOnboarding, input.Password, input.RotateSearchIndexKey)
+	if input.RotateSearchIndexKey {
+		if service.IndexKey == nil {
+			return "", nil, 0, 0, errors.New("failed to create a new search index key")
+		}
+		vp, err := vault_session.DecodeSessionVault(session.Vault)
+		if err != nil {
+			return "", nil, 0, 0, err
+		}
+		if _, err := service.RotateSearchIndexKey(vp.Entries, service.IndexKey); err != nil {
+			return "", nil, 0, 0, err
+		}
+	}
+
 	// 1. Commit
 	// ========================================================================================================
-	return service.CommitVault(session, mode)
+	cid, updates, totalBytes, newBytes, err := service.CommitVault(session, mode)
+	if err != nil {
+		return cid, updates, totalBytes, newBytes, err
+	}
+	vh.searchMu.Lock()
+	if _, err := vh.SessionManager.GetSession(input.UserID); err == nil {
+		vh.storeSearchIndex(input.UserID, service.SearchIndex)
+	}
+	vh.searchMu.Unlock()
+	return cid, updates, totalBytes, newBytes, nil
 }
 func (vh *VaultHandler) CommitAttachments(
 	input vault_dto.SynchronizeAttachmentRequest,
@@ -1005,6 +1277,7 @@ func (vh *VaultHandler) PrepareCommit(
 		vaultCtx,
 	)
 	service.Password = input.Password
+	service.Schemas = vh.recordSchemas()
 
 	return service, nil
 }
@@ -1218,6 +1491,7 @@ func (vh *VaultHandler) AddAttachement(ctx context.Context, req vault_dto.AddAtt
 		vh.CreateIPFSPayloadCommandHandler,
 	)
 	service.Password = req.Password
+	service.Schemas = vh.recordSchemas()
 
 	attachmentNodeLink, err := service.GetAttachmentNodeLink(*att)
 	att.NodeCID = attachmentNodeLink.CID
@@ -1259,6 +1533,20 @@ func (h *VaultHandler) SaveAttachment(userID string, vaultID string, data []byte
 	return hash, nil
 
 }
+// LoadEntryAttachment reads an attachment from the local store of the
+// user's open vault, by the hash SaveAttachment returned.
+func (vh *VaultHandler) LoadEntryAttachment(userID string, hash string) ([]byte, error) {
+	session, err := vh.GetSession(userID)
+	if err != nil {
+		return nil, fmt.Errorf("❌ VaultHandler - LoadEntryAttachment: failed to get session: %w", err)
+	}
+	vault, err := vh.VaultRepository.GetVault(session.Runtime.VaultID)
+	if err != nil {
+		return nil, fmt.Errorf("❌ VaultHandler - LoadEntryAttachment: failed to get vault for user %s: %w", userID, err)
+	}
+	attachmentStore := vaults_storage.NewAttachmentStore(vault.GetVaultAttachmentPath())
+	return vh.LoadAttachmentToBytes(userID, vault.Name, hash, *attachmentStore)
+}
 func (vh *VaultHandler) UploadAttachementToIPFS(userID string, ur UploadAttachRequest) (string, error) {
 
 	// ------------------------------------------------------------
@@ -1713,3 +2001,10 @@ func (vh *VaultHandler) HandleShareCreated(
 
 	return nil
 }
+
+func (vh *VaultHandler) recordSchemas() *vaults_domain.RecordSchemaRegistry {
+	if vh.EntryRegistry == nil {
+		return nil
+	}
+	return vh.EntryRegistry.RecordSchemas()
+}
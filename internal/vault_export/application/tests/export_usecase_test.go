@@ -0,0 +1,171 @@
+package vault_export_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_export_usecases "vault-app/internal/vault_export/application/usecases"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+	vault_export_persistence "vault-app/internal/vault_export/infrastructure/persistence"
+)
+
+type vaultSessionMock struct {
+	vp vaults_domain.VaultPayload
+}
+
+func (m *vaultSessionMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	return &m.vp, nil
+}
+
+type attachmentsMock map[string][]byte
+
+func (m attachmentsMock) LoadEntryAttachment(_ string, hash string) ([]byte, error) {
+	data, ok := m[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+// writerSpy records the snapshot it was given.
+type writerSpy struct {
+	got *vault_export_domain.Snapshot
+}
+
+func (w *writerSpy) Write(s *vault_export_domain.Snapshot, _ string) ([]byte, string, error) {
+	w.got = s
+	return []byte("export"), "out", nil
+}
+
+type fixture struct {
+	vault  *vaultSessionMock
+	writer *writerSpy
+	audit  *vault_export_persistence.MemoryAuditRepository
+	reauth []string
+	uc     *vault_export_usecases.ExportVaultUsecase
+}
+
+func newFixture() *fixture {
+	login := vaults_domain.LoginEntry{UserName: "alice", Password: "s3cret"}
+	login.ID, login.EntryName, login.FolderID = "login-1", "GitHub", "folder-1"
+	login.Attachments = []vaults_domain.Attachment{{Name: "codes", Ext: "txt", Hash: "hash-1"}}
+	trashed := vaults_domain.NoteEntry{}
+	trashed.ID, trashed.EntryName, trashed.Trashed = "note-1", "Old", true
+
+	f := &fixture{
+		vault: &vaultSessionMock{vp: vaults_domain.VaultPayload{
+			Name: "Personal",
+			BaseVaultContent: vaults_domain.BaseVaultContent{
+				Folders: []vaults_domain.Folder{{ID: "folder-1", Name: "Work"}},
+				Entries: vaults_domain.Entries{
+					Login: []vaults_domain.LoginEntry{login},
+					Note:  []vaults_domain.NoteEntry{trashed},
+				},
+			},
+		}},
+		writer: &writerSpy{},
+		audit:  vault_export_persistence.NewMemoryAuditRepository(),
+	}
+	reauth := vault_export_usecases.ReauthenticatorFunc(func(_ context.Context, email, password string) error {
+		f.reauth = append(f.reauth, email)
+		if password != "account-password" {
+			return errors.New("bad password")
+		}
+		return nil
+	})
+	f.uc = vault_export_usecases.NewExportVaultUsecase(
+		f.vault,
+		attachmentsMock{"hash-1": []byte("1111-2222")},
+		map[vault_export_domain.Format]vault_export_domain.Writer{
+			vault_export_domain.FormatArchive:       f.writer,
+			vault_export_domain.FormatBitwardenJSON: f.writer,
+		},
+		reauth,
+		f.audit,
+	)
+	f.uc.Now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	return f
+}
+
+func TestExportVault_EncryptedArchive(t *testing.T) {
+	f := newFixture()
+
+	res, err := f.uc.Execute(context.Background(), vault_export_usecases.ExportVaultRequest{
+		UserID:   "user-1",
+		Format:   vault_export_domain.FormatArchive,
+		Password: "export-password",
+	})
+	require.NoError(t, err)
+	require.Empty(t, f.reauth, "encrypted exports do not need the account password")
+	require.Equal(t, "Personal-export-20261018-120000.out", res.FileName)
+	require.Equal(t, 1, res.Entries)
+	require.Equal(t, 1, res.Attachments)
+
+	// Trashed entries are skipped, folders and attachments resolved.
+	require.Len(t, f.writer.got.Items, 1)
+	item := f.writer.got.Items[0]
+	require.Equal(t, "Work", item.Folder)
+	require.Equal(t, []vault_export_domain.Attachment{{Name: "codes.txt", Data: []byte("1111-2222")}}, item.Attachments)
+
+	audits, err := f.audit.ListByUser(context.Background(), "user-1")
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	require.Equal(t, vault_export_domain.FormatArchive, audits[0].Format)
+	require.False(t, audits[0].Plaintext)
+	require.Len(t, audits[0].SHA256, 64)
+}
+
+func TestExportVault_PlaintextRequiresReauthentication(t *testing.T) {
+	f := newFixture()
+	req := vault_export_usecases.ExportVaultRequest{UserID: "user-1", Email: "alice@example.com", Format: vault_export_domain.FormatBitwardenJSON}
+
+	_, err := f.uc.Execute(context.Background(), req)
+	require.ErrorIs(t, err, vault_export_domain.ErrReauthenticationNeeded)
+
+	req.AccountPassword = "wrong"
+	_, err = f.uc.Execute(context.Background(), req)
+	require.ErrorIs(t, err, vault_export_domain.ErrReauthenticationFailed)
+
+	audits, _ := f.audit.ListByUser(context.Background(), "user-1")
+	require.Empty(t, audits)
+
+	req.AccountPassword = "account-password"
+	res, err := f.uc.Execute(context.Background(), req)
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Warnings)
+	require.Equal(t, []string{"alice@example.com", "alice@example.com"}, f.reauth)
+
+	audits, _ = f.audit.ListByUser(context.Background(), "user-1")
+	require.Len(t, audits, 1)
+	require.True(t, audits[0].Plaintext)
+}
+
+func TestExportVault_Errors(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	_, err := f.uc.Execute(ctx, vault_export_usecases.ExportVaultRequest{UserID: "user-1", Format: "csv", Password: "export-password"})
+	require.ErrorIs(t, err, vault_export_domain.ErrUnsupportedFormat)
+
+	_, err = f.uc.Execute(ctx, vault_export_usecases.ExportVaultRequest{UserID: "user-1", Format: vault_export_domain.FormatArchive})
+	require.ErrorIs(t, err, vault_export_domain.ErrExportPasswordRequired)
+
+	_, err = f.uc.Execute(ctx, vault_export_usecases.ExportVaultRequest{UserID: "user-1", Format: vault_export_domain.FormatArchive, Password: "short"})
+	require.ErrorIs(t, err, vault_export_domain.ErrWeakExportPassword)
+
+	f.uc.Attachments = attachmentsMock{}
+	_, err = f.uc.Execute(ctx, vault_export_usecases.ExportVaultRequest{UserID: "user-1", Format: vault_export_domain.FormatArchive, Password: "export-password"})
+	require.ErrorIs(t, err, vault_export_domain.ErrAttachmentUnavailable)
+
+	f.vault.vp.Entries = vaults_domain.Entries{}
+	_, err = f.uc.Execute(ctx, vault_export_usecases.ExportVaultRequest{UserID: "user-1", Format: vault_export_domain.FormatArchive, Password: "export-password"})
+	require.ErrorIs(t, err, vault_export_domain.ErrNothingToExport)
+
+	audits, _ := f.audit.ListByUser(ctx, "user-1")
+	require.Empty(t, audits)
+}
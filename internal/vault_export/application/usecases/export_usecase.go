@@ -0,0 +1,197 @@
+package vault_export_usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+type ExportVaultRequest struct {
+	UserID string
+	// Email identifies the account for re-authentication.
+	Email  string
+	Format vault_export_domain.Format
+	// Password protects encrypted formats; it is unrelated to the account
+	// password and the Stellar key.
+	Password string
+	// AccountPassword is required for plaintext formats.
+	AccountPassword string
+}
+
+// ExportVaultUsecase writes the current vault session to a portable file.
+// Plaintext formats require re-authentication, and every successful export
+// is audited before the file is returned.
+type ExportVaultUsecase struct {
+	Vault       VaultSession
+	Attachments AttachmentLoader
+	Writers     map[vault_export_domain.Format]vault_export_domain.Writer
+	Reauth      Reauthenticator
+	Audit       vault_export_domain.AuditRepository
+	Now         func() time.Time
+}
+
+func NewExportVaultUsecase(
+	vault VaultSession,
+	attachments AttachmentLoader,
+	writers map[vault_export_domain.Format]vault_export_domain.Writer,
+	reauth Reauthenticator,
+	audit vault_export_domain.AuditRepository,
+) *ExportVaultUsecase {
+	return &ExportVaultUsecase{
+		Vault:       vault,
+		Attachments: attachments,
+		Writers:     writers,
+		Reauth:      reauth,
+		Audit:       audit,
+		Now:         time.Now,
+	}
+}
+
+func (uc *ExportVaultUsecase) Execute(ctx context.Context, req ExportVaultRequest) (*vault_export_domain.Result, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" {
+		return nil, vault_export_domain.ErrUserIDRequired
+	}
+	writer, ok := uc.Writers[req.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", vault_export_domain.ErrUnsupportedFormat, req.Format)
+	}
+
+	// 1. ---------- Authorise ----------
+	if req.Format.Plaintext() {
+		if req.AccountPassword == "" || uc.Reauth == nil {
+			return nil, vault_export_domain.ErrReauthenticationNeeded
+		}
+		if err := uc.Reauth.Reauthenticate(ctx, req.Email, req.AccountPassword); err != nil {
+			return nil, fmt.Errorf("%w: %v", vault_export_domain.ErrReauthenticationFailed, err)
+		}
+	} else {
+		if req.Password == "" {
+			return nil, vault_export_domain.ErrExportPasswordRequired
+		}
+		if len(req.Password) < vault_export_domain.MinExportPassword {
+			return nil, vault_export_domain.ErrWeakExportPassword
+		}
+	}
+
+	// 2. ---------- Snapshot ----------
+	vp, err := uc.Vault.GetVaultSession(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := vault_export_domain.NewSnapshot(vp, uc.Now())
+	if len(snapshot.Items) == 0 {
+		return nil, vault_export_domain.ErrNothingToExport
+	}
+	for i := range snapshot.Items {
+		item := &snapshot.Items[i]
+		for _, att := range item.Entry.GetBase().Attachments {
+			if uc.Attachments == nil || att.Hash == "" {
+				return nil, fmt.Errorf("%w: %s on %s", vault_export_domain.ErrAttachmentUnavailable, att.Name, item.Entry.GetName())
+			}
+			data, err := uc.Attachments.LoadEntryAttachment(req.UserID, att.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s on %s: %v", vault_export_domain.ErrAttachmentUnavailable, att.Name, item.Entry.GetName(), err)
+			}
+			item.Attachments = append(item.Attachments, vault_export_domain.Attachment{Name: attachmentName(att.Name, att.Ext), Data: data})
+		}
+	}
+
+	// 3. ---------- Write ----------
+	data, ext, err := writer.Write(snapshot, req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("write %s export: %w", req.Format, err)
+	}
+	result := &vault_export_domain.Result{
+		Format:      req.Format,
+		FileName:    exportFileName(snapshot, ext),
+		Data:        data,
+		Entries:     len(snapshot.Items),
+		Attachments: snapshot.AttachmentCount(),
+	}
+	if req.Format.Plaintext() {
+		result.Warnf("this file contains unencrypted secrets; delete it once imported")
+	}
+
+	// 4. ---------- Audit ----------
+	// The file is only handed out once the audit record is stored.
+	digest := sha256.Sum256(data)
+	if err := uc.Audit.Save(ctx, &vault_export_domain.ExportAudit{
+		ID:          uuid.NewString(),
+		UserID:      req.UserID,
+		VaultName:   snapshot.VaultName,
+		Format:      req.Format,
+		Plaintext:   req.Format.Plaintext(),
+		Entries:     result.Entries,
+		Attachments: result.Attachments,
+		SHA256:      hex.EncodeToString(digest[:]),
+		CreatedAt:   snapshot.ExportedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("record export audit: %w", err)
+	}
+	return result, nil
+}
+
+func (uc *ExportVaultUsecase) ValidateDependencies() error {
+	if uc.Vault == nil {
+		return vault_export_domain.ErrVaultRequired
+	}
+	if len(uc.Writers) == 0 {
+		return vault_export_domain.ErrWritersRequired
+	}
+	if uc.Audit == nil {
+		return vault_export_domain.ErrAuditRepositoryRequired
+	}
+	return nil
+}
+
+// ListExportsUsecase returns the export audit trail of a user.
+type ListExportsUsecase struct {
+	Audit vault_export_domain.AuditRepository
+}
+
+func NewListExportsUsecase(audit vault_export_domain.AuditRepository) *ListExportsUsecase {
+	return &ListExportsUsecase{Audit: audit}
+}
+
+func (uc *ListExportsUsecase) Execute(ctx context.Context, userID string) ([]vault_export_domain.ExportAudit, error) {
+	if uc.Audit == nil {
+		return nil, vault_export_domain.ErrAuditRepositoryRequired
+	}
+	if userID == "" {
+		return nil, vault_export_domain.ErrUserIDRequired
+	}
+	return uc.Audit.ListByUser(ctx, userID)
+}
+
+// attachmentName makes sure the exported file name carries Attachment.Ext.
+func attachmentName(name string, ext string) string {
+	ext = strings.TrimPrefix(ext, ".")
+	if ext == "" || strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(ext)) {
+		return name
+	}
+	return name + "." + ext
+}
+
+func exportFileName(s *vault_export_domain.Snapshot, ext string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, s.VaultName)
+	if strings.Trim(name, "-") == "" {
+		name = "vault"
+	}
+	return fmt.Sprintf("%s-export-%s.%s", name, s.ExportedAt.UTC().Format("20060102-150405"), ext)
+}
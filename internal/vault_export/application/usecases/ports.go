@@ -0,0 +1,29 @@
+package vault_export_usecases
+
+import (
+	"context"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultSession is the part of the vault handler exports read from.
+type VaultSession interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+}
+
+// AttachmentLoader returns the decrypted content of a stored attachment.
+type AttachmentLoader interface {
+	LoadEntryAttachment(userID string, hash string) ([]byte, error)
+}
+
+// Reauthenticator checks the account password again before secrets leave
+// the app in plaintext.
+type Reauthenticator interface {
+	Reauthenticate(ctx context.Context, email string, password string) error
+}
+
+type ReauthenticatorFunc func(ctx context.Context, email string, password string) error
+
+func (f ReauthenticatorFunc) Reauthenticate(ctx context.Context, email string, password string) error {
+	return f(ctx, email, password)
+}
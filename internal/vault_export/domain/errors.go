@@ -0,0 +1,19 @@
+package vault_export_domain
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat       = errors.New("unsupported export format")
+	ErrExportPasswordRequired  = errors.New("export password is required")
+	ErrWeakExportPassword      = errors.New("export password is too short")
+	ErrReauthenticationNeeded  = errors.New("plaintext exports require re-entering the account password")
+	ErrReauthenticationFailed  = errors.New("re-authentication failed")
+	ErrAttachmentUnavailable   = errors.New("attachment content is not available locally")
+	ErrNothingToExport         = errors.New("vault has no entries to export")
+	ErrMalformedArchive        = errors.New("malformed export archive")
+	ErrInvalidArchivePassword  = errors.New("invalid archive password or corrupted archive")
+	ErrUserIDRequired          = errors.New("user id is required")
+	ErrVaultRequired           = errors.New("vault session is required")
+	ErrWritersRequired         = errors.New("no export writers configured")
+	ErrAuditRepositoryRequired = errors.New("export audit repository is required")
+)
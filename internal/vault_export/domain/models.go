@@ -0,0 +1,128 @@
+package vault_export_domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type Format string
+
+const (
+	// FormatArchive is the native single-file archive, sealed with a key
+	// derived from the export password alone.
+	FormatArchive       Format = "dvault_archive"
+	FormatBitwardenJSON Format = "bitwarden_json"
+	FormatKeePass       Format = "keepass_kdbx"
+)
+
+// MinExportPassword is the shortest password accepted for encrypted exports.
+const MinExportPassword = 8
+
+// Plaintext reports whether the format writes secrets unencrypted, in which
+// case the user has to re-authenticate before exporting.
+func (f Format) Plaintext() bool {
+	return f == FormatBitwardenJSON
+}
+
+// Entry is one of the vault entry types (*LoginEntry, *CardEntry, ...).
+type Entry interface {
+	vaults_domain.VaultEntry
+	vaults_domain.EntryInterface
+}
+
+// Attachment is the decrypted content of an entry attachment.
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// Item is one exported entry with its folder name and attachment content.
+type Item struct {
+	Entry       Entry
+	Folder      string
+	Attachments []Attachment
+}
+
+// Snapshot is the vault content handed to a Writer.
+type Snapshot struct {
+	VaultName  string
+	ExportedAt time.Time
+	Folders    []string
+	Items      []Item
+}
+
+func (s *Snapshot) AttachmentCount() int {
+	n := 0
+	for _, it := range s.Items {
+		n += len(it.Attachments)
+	}
+	return n
+}
+
+// NewSnapshot walks a reconstructed payload. Trashed entries are left out
+// and folder IDs are resolved to names; entries pointing at a missing folder
+// are exported at the root.
+func NewSnapshot(vp *vaults_domain.VaultPayload, now time.Time) *Snapshot {
+	s := &Snapshot{VaultName: vp.Name, ExportedAt: now}
+	folders := map[string]string{}
+	for _, f := range vp.Folders {
+		folders[f.ID] = f.Name
+		s.Folders = append(s.Folders, f.Name)
+	}
+	for _, e := range vp.Entries.All() {
+		if e.GetBase().Trashed {
+			continue
+		}
+		entry, ok := e.(Entry)
+		if !ok {
+			continue
+		}
+		s.Items = append(s.Items, Item{Entry: entry, Folder: folders[e.GetBase().FolderID]})
+	}
+	return s
+}
+
+// Writer encodes a snapshot into one export format. Extension is the file
+// extension the output should be saved with, which can depend on content
+// (Bitwarden exports with attachments are zip files).
+type Writer interface {
+	Write(s *Snapshot, password string) (data []byte, extension string, err error)
+}
+
+// Result is the export file returned to the caller.
+type Result struct {
+	Format      Format   `json:"format"`
+	FileName    string   `json:"file_name"`
+	Data        []byte   `json:"data"`
+	Entries     int      `json:"entries"`
+	Attachments int      `json:"attachments"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+func (r *Result) Warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// ExportAudit records that an export file left the app. The file itself is
+// never stored, only its digest.
+type ExportAudit struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:64"`
+	UserID      string    `json:"user_id" gorm:"index;size:64"`
+	VaultName   string    `json:"vault_name"`
+	Format      Format    `json:"format" gorm:"size:32"`
+	Plaintext   bool      `json:"plaintext"`
+	Entries     int       `json:"entries"`
+	Attachments int       `json:"attachments"`
+	SHA256      string    `json:"sha256" gorm:"size:64"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (ExportAudit) TableName() string { return "vault_export_audits" }
+
+type AuditRepository interface {
+	Save(ctx context.Context, a *ExportAudit) error
+	ListByUser(ctx context.Context, userID string) ([]ExportAudit, error)
+}
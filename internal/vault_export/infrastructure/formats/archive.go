@@ -0,0 +1,240 @@
+package vault_export_formats
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+// Archive writes the native export: a JSON envelope around the vault content
+// sealed with XChaCha20-Poly1305 under an Argon2id key derived from the
+// export password. It does not depend on the Stellar key or the vault
+// keyring, so it can be opened on a fresh install.
+type Archive struct {
+	// KDF parameters; zero values fall back to the defaults below.
+	Time      uint32
+	MemoryKiB uint32
+	Threads   uint8
+}
+
+const (
+	archiveMagic   = "dvault-archive"
+	archiveVersion = 1
+
+	archiveDefaultTime      = 3
+	archiveDefaultMemoryKiB = 64 * 1024
+	archiveDefaultThreads   = 4
+	// The maxima bound the memory and time a crafted archive can make us
+	// spend deriving its key.
+	archiveMaxTime      = 16
+	archiveMaxMemoryKiB = 1024 * 1024
+	archiveMaxThreads   = 64
+)
+
+type archiveKDF struct {
+	Name      string `json:"name"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	MemoryKiB uint32 `json:"memory_kib"`
+	Threads   uint8  `json:"threads"`
+}
+
+func (k archiveKDF) supported() bool {
+	return k.Name == "argon2id" &&
+		k.Time > 0 && k.Time <= archiveMaxTime &&
+		k.MemoryKiB <= archiveMaxMemoryKiB &&
+		k.Threads > 0 && k.Threads <= archiveMaxThreads
+}
+
+// archiveHeader is authenticated as associated data, so tampering with the
+// KDF parameters fails decryption instead of silently weakening the key.
+type archiveHeader struct {
+	Format  string     `json:"format"`
+	Version int        `json:"version"`
+	KDF     archiveKDF `json:"kdf"`
+	Cipher  string     `json:"cipher"`
+}
+
+type archiveEnvelope struct {
+	archiveHeader
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type archivePayload struct {
+	VaultName  string         `json:"vault_name"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Folders    []string       `json:"folders"`
+	Entries    []archiveEntry `json:"entries"`
+}
+
+type archiveEntry struct {
+	Type        string              `json:"type"`
+	Folder      string              `json:"folder,omitempty"`
+	Entry       json.RawMessage     `json:"entry"`
+	Attachments []archiveAttachment `json:"attachments,omitempty"`
+}
+
+type archiveAttachment struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+func (a Archive) Write(s *vault_export_domain.Snapshot, password string) ([]byte, string, error) {
+	payload := archivePayload{
+		VaultName:  s.VaultName,
+		ExportedAt: s.ExportedAt.UTC(),
+		Folders:    s.Folders,
+		Entries:    make([]archiveEntry, 0, len(s.Items)),
+	}
+	for _, it := range s.Items {
+		raw, err := json.Marshal(it.Entry)
+		if err != nil {
+			return nil, "", fmt.Errorf("encode entry %s: %w", it.Entry.GetId(), err)
+		}
+		entry := archiveEntry{Type: it.Entry.GetTypeName(), Folder: it.Folder, Entry: raw}
+		for _, att := range it.Attachments {
+			entry.Attachments = append(entry.Attachments, archiveAttachment{Name: att.Name, Data: att.Data})
+		}
+		payload.Entries = append(payload.Entries, entry)
+	}
+	plain, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	header := archiveHeader{
+		Format:  archiveMagic,
+		Version: archiveVersion,
+		KDF: archiveKDF{
+			Name:      "argon2id",
+			Salt:      make([]byte, 16),
+			Time:      orDefault(a.Time, archiveDefaultTime),
+			MemoryKiB: orDefault(a.MemoryKiB, archiveDefaultMemoryKiB),
+			Threads:   uint8(orDefault(uint32(a.Threads), archiveDefaultThreads)),
+		},
+		Cipher: "xchacha20-poly1305",
+	}
+	if !header.KDF.supported() {
+		return nil, "", fmt.Errorf("archive kdf parameters exceed time %d, memory %d KiB or threads %d",
+			archiveMaxTime, archiveMaxMemoryKiB, archiveMaxThreads)
+	}
+	if _, err := rand.Read(header.KDF.Salt); err != nil {
+		return nil, "", err
+	}
+	aead, err := chacha20poly1305.NewX(archiveKey(password, header.KDF))
+	if err != nil {
+		return nil, "", err
+	}
+	ad, err := json.Marshal(header)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+
+	out, err := json.MarshalIndent(archiveEnvelope{
+		archiveHeader: header,
+		Nonce:         nonce,
+		Ciphertext:    aead.Seal(nil, nonce, plain, ad),
+	}, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+	return out, "dvault", nil
+}
+
+// OpenArchive decrypts an archive written by Archive.Write.
+func OpenArchive(data []byte, password string) (*vault_export_domain.Snapshot, error) {
+	var env archiveEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_export_domain.ErrMalformedArchive, err)
+	}
+	if env.Format != archiveMagic || env.Version != archiveVersion {
+		return nil, fmt.Errorf("%w: not a version %d archive", vault_export_domain.ErrMalformedArchive, archiveVersion)
+	}
+	if !env.KDF.supported() || env.Cipher != "xchacha20-poly1305" {
+		return nil, fmt.Errorf("%w: unsupported kdf or cipher", vault_export_domain.ErrMalformedArchive)
+	}
+	aead, err := chacha20poly1305.NewX(archiveKey(password, env.KDF))
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce", vault_export_domain.ErrMalformedArchive)
+	}
+	ad, err := json.Marshal(env.archiveHeader)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, env.Nonce, env.Ciphertext, ad)
+	if err != nil {
+		return nil, vault_export_domain.ErrInvalidArchivePassword
+	}
+
+	var payload archivePayload
+	if err := json.Unmarshal(plain, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_export_domain.ErrMalformedArchive, err)
+	}
+	s := &vault_export_domain.Snapshot{
+		VaultName:  payload.VaultName,
+		ExportedAt: payload.ExportedAt,
+		Folders:    payload.Folders,
+	}
+	for _, e := range payload.Entries {
+		entry, err := decodeArchiveEntry(e.Type, e.Entry)
+		if err != nil {
+			return nil, err
+		}
+		item := vault_export_domain.Item{Entry: entry, Folder: e.Folder}
+		for _, att := range e.Attachments {
+			item.Attachments = append(item.Attachments, vault_export_domain.Attachment{Name: att.Name, Data: att.Data})
+		}
+		s.Items = append(s.Items, item)
+	}
+	return s, nil
+}
+
+func decodeArchiveEntry(kind string, raw json.RawMessage) (vault_export_domain.Entry, error) {
+	var entry vault_export_domain.Entry
+	switch kind {
+	case "login":
+		entry = &vaults_domain.LoginEntry{}
+	case "card":
+		entry = &vaults_domain.CardEntry{}
+	case "identity":
+		entry = &vaults_domain.IdentityEntry{}
+	case "note":
+		entry = &vaults_domain.NoteEntry{}
+	case "sshkey":
+		entry = &vaults_domain.SSHKeyEntry{}
+	case "otp":
+		entry = &vaults_domain.OTPEntry{}
+	default:
+		return nil, fmt.Errorf("%w: unknown entry type %q", vault_export_domain.ErrMalformedArchive, kind)
+	}
+	if err := json.Unmarshal(raw, entry); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_export_domain.ErrMalformedArchive, err)
+	}
+	return entry, nil
+}
+
+func archiveKey(password string, kdf archiveKDF) []byte {
+	return argon2.IDKey([]byte(password), kdf.Salt, kdf.Time, kdf.MemoryKiB, kdf.Threads, chacha20poly1305.KeySize)
+}
+
+func orDefault(v, def uint32) uint32 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
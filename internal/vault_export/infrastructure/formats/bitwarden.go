@@ -0,0 +1,248 @@
+package vault_export_formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+// BitwardenJSON writes the unencrypted Bitwarden export, readable by
+// Bitwarden and most other managers. The JSON format has no room for files,
+// so when entries carry attachments the output is the Bitwarden zip layout:
+// data.json plus attachments/<item id>/<file name>.
+type BitwardenJSON struct{}
+
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenURI struct {
+	Match *int   `json:"match"`
+	URI   string `json:"uri"`
+}
+
+type bitwardenLogin struct {
+	Username string         `json:"username"`
+	Password string         `json:"password"`
+	TOTP     string         `json:"totp,omitempty"`
+	URIs     []bitwardenURI `json:"uris"`
+}
+
+type bitwardenCard struct {
+	CardholderName string `json:"cardholderName"`
+	Brand          string `json:"brand,omitempty"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"expMonth"`
+	ExpYear        string `json:"expYear"`
+	Code           string `json:"code"`
+}
+
+type bitwardenIdentity struct {
+	Title          string `json:"title"`
+	FirstName      string `json:"firstName"`
+	MiddleName     string `json:"middleName"`
+	LastName       string `json:"lastName"`
+	Address1       string `json:"address1"`
+	Address2       string `json:"address2"`
+	Address3       string `json:"address3"`
+	City           string `json:"city"`
+	State          string `json:"state"`
+	PostalCode     string `json:"postalCode"`
+	Country        string `json:"country"`
+	Company        string `json:"company"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	SSN            string `json:"ssn"`
+	Username       string `json:"username"`
+	PassportNumber string `json:"passportNumber"`
+	LicenseNumber  string `json:"licenseNumber"`
+}
+
+type bitwardenSSHKey struct {
+	PrivateKey     string `json:"privateKey"`
+	PublicKey      string `json:"publicKey"`
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+type bitwardenSecureNote struct {
+	Type int `json:"type"`
+}
+
+type bitwardenItem struct {
+	ID         string               `json:"id"`
+	FolderID   *string              `json:"folderId"`
+	Type       int                  `json:"type"`
+	Name       string               `json:"name"`
+	Notes      string               `json:"notes,omitempty"`
+	Favorite   bool                 `json:"favorite"`
+	Fields     []bitwardenField     `json:"fields,omitempty"`
+	Login      *bitwardenLogin      `json:"login,omitempty"`
+	Card       *bitwardenCard       `json:"card,omitempty"`
+	Identity   *bitwardenIdentity   `json:"identity,omitempty"`
+	SSHKey     *bitwardenSSHKey     `json:"sshKey,omitempty"`
+	SecureNote *bitwardenSecureNote `json:"secureNote,omitempty"`
+}
+
+const (
+	bitwardenLoginType    = 1
+	bitwardenNoteType     = 2
+	bitwardenCardType     = 3
+	bitwardenIdentityType = 4
+	bitwardenSSHKeyType   = 5
+)
+
+func (BitwardenJSON) Write(s *vault_export_domain.Snapshot, _ string) ([]byte, string, error) {
+	export := bitwardenExport{Folders: []bitwardenFolder{}, Items: []bitwardenItem{}}
+	folderIDs := map[string]string{}
+	for _, name := range s.Folders {
+		if _, ok := folderIDs[name]; ok {
+			continue
+		}
+		id := newUUID()
+		folderIDs[name] = id
+		export.Folders = append(export.Folders, bitwardenFolder{ID: id, Name: name})
+	}
+
+	for _, it := range s.Items {
+		item := bitwardenItemOf(it.Entry)
+		if id, ok := folderIDs[it.Folder]; ok && it.Folder != "" {
+			item.FolderID = &id
+		}
+		export.Items = append(export.Items, item)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+	if s.AttachmentCount() == 0 {
+		return data, "json", nil
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("data.json")
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, "", err
+	}
+	for i, it := range s.Items {
+		for _, att := range it.Attachments {
+			w, err := zw.Create(path.Join("attachments", export.Items[i].ID, path.Base("/"+att.Name)))
+			if err != nil {
+				return nil, "", err
+			}
+			if _, err := w.Write(att.Data); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "zip", nil
+}
+
+func bitwardenItemOf(entry vault_export_domain.Entry) bitwardenItem {
+	base := entry.GetBase()
+	item := bitwardenItem{
+		ID:       entry.GetId(),
+		Name:     entry.GetName(),
+		Notes:    base.AdditionnalNote,
+		Favorite: base.IsFavorite,
+	}
+	if item.ID == "" {
+		item.ID = newUUID()
+	}
+
+	switch e := entry.(type) {
+	case *vaults_domain.LoginEntry:
+		item.Type = bitwardenLoginType
+		item.Login = &bitwardenLogin{Username: e.UserName, Password: e.Password, URIs: []bitwardenURI{}}
+		if e.Website != "" {
+			item.Login.URIs = append(item.Login.URIs, bitwardenURI{URI: e.Website})
+		}
+	case *vaults_domain.CardEntry:
+		month, year := splitExpiration(e.Expiration)
+		item.Type = bitwardenCardType
+		item.Card = &bitwardenCard{
+			CardholderName: e.Owner,
+			Number:         e.Number,
+			ExpMonth:       month,
+			ExpYear:        year,
+			Code:           e.CVC,
+		}
+	case *vaults_domain.IdentityEntry:
+		item.Type = bitwardenIdentityType
+		item.Identity = &bitwardenIdentity{
+			Title:          e.Genre,
+			FirstName:      e.FirstName,
+			MiddleName:     e.SecondFirstName,
+			LastName:       e.LastName,
+			Address1:       e.AddressOne,
+			Address2:       e.AddressTwo,
+			Address3:       e.AddressThree,
+			City:           e.City,
+			State:          e.State,
+			PostalCode:     e.PostalCode,
+			Country:        e.Country,
+			Company:        e.Company,
+			Email:          e.Mail,
+			Phone:          e.Telephone,
+			SSN:            e.SocialSecurityNumber,
+			Username:       e.Username,
+			PassportNumber: e.IDNumber,
+			LicenseNumber:  e.DriverLicense,
+		}
+	case *vaults_domain.SSHKeyEntry:
+		item.Type = bitwardenSSHKeyType
+		item.SSHKey = &bitwardenSSHKey{PrivateKey: e.PrivateKey, PublicKey: e.PublicKey, KeyFingerprint: e.EFingerprint}
+	case *vaults_domain.OTPEntry:
+		// Bitwarden keeps seeds on logins; a bare login with only a TOTP is
+		// how it represents a standalone authenticator entry.
+		item.Type = bitwardenLoginType
+		item.Login = &bitwardenLogin{Username: e.AccountName, TOTP: e.URI(), URIs: []bitwardenURI{}}
+	default:
+		item.Type = bitwardenNoteType
+		item.SecureNote = &bitwardenSecureNote{}
+	}
+
+	for _, f := range customFields(entry) {
+		item.Fields = append(item.Fields, bitwardenField{Name: f.name, Value: f.value})
+	}
+	return item
+}
+
+// splitExpiration turns "MM/YY" into Bitwarden's month and four-digit year.
+func splitExpiration(exp string) (string, string) {
+	month, year, ok := strings.Cut(strings.TrimSpace(exp), "/")
+	if !ok {
+		return "", ""
+	}
+	month = strings.TrimLeft(strings.TrimSpace(month), "0")
+	year = strings.TrimSpace(year)
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	return month, year
+}
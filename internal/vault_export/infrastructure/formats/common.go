@@ -0,0 +1,58 @@
+package vault_export_formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+// Writers returns every supported format keyed by its identifier.
+func Writers() map[vault_export_domain.Format]vault_export_domain.Writer {
+	return map[vault_export_domain.Format]vault_export_domain.Writer{
+		vault_export_domain.FormatArchive:       Archive{},
+		vault_export_domain.FormatBitwardenJSON: BitwardenJSON{},
+		vault_export_domain.FormatKeePass:       KeePass{},
+	}
+}
+
+type field struct {
+	name  string
+	value string
+}
+
+// customFields flattens BaseEntry.CustomFields into name/value strings in
+// name order, so output is stable. Non-string values are JSON-encoded.
+func customFields(entry vault_export_domain.Entry) []field {
+	m := entry.GetBase().CustomFields
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]field, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := m[name].(type) {
+		case string:
+			value = v
+		case nil:
+		default:
+			if b, err := json.Marshal(v); err == nil {
+				value = string(b)
+			} else {
+				value = fmt.Sprint(v)
+			}
+		}
+		out = append(out, field{name: name, value: value})
+	}
+	return out
+}
+
+func newUUID() string {
+	return uuid.NewString()
+}
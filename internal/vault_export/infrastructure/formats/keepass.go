@@ -0,0 +1,390 @@
+package vault_export_formats
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+// KeePass writes a KDBX 4 database (AES-256, Argon2id, ChaCha20 inner
+// stream) that opens in KeePass 2.35+, KeePassXC and compatible apps.
+// Folders become groups below a root group named after the vault, "/" in a
+// folder name nests groups, and attachments are stored as entry binaries.
+// SSH keys are written KeeAgent-style: the private key as an "id_key"
+// binary next to "id_key.pub".
+type KeePass struct {
+	// Argon2 parameters; zero values fall back to the defaults below.
+	Iterations uint64
+	MemoryKiB  uint64
+	Lanes      uint32
+}
+
+// https://keepass.info/help/kb/kdbx_4.html
+const (
+	kdbxSignature1 = 0x9AA2D903
+	kdbxSignature2 = 0xB54BFB67
+	kdbxVersion    = 0x00040000
+
+	kdbxEndOfHeader      = 0
+	kdbxCipherID         = 2
+	kdbxCompressionFlags = 3
+	kdbxMasterSeed       = 4
+	kdbxEncryptionIV     = 7
+	kdbxKdfParameters    = 11
+
+	kdbxInnerEnd       = 0
+	kdbxInnerStreamID  = 1
+	kdbxInnerStreamKey = 2
+	kdbxInnerBinary    = 3
+	kdbxStreamChaCha20 = 3
+
+	kdbxHeaderBlockIndex = ^uint64(0)
+	kdbxBlockSize        = 1 << 20
+
+	kdbxDefaultIterations = 10
+	kdbxDefaultMemoryKiB  = 64 * 1024
+	kdbxDefaultLanes      = 2
+)
+
+var (
+	kdbxCipherAES256 = uuid.MustParse("31c1f2e6-bf71-4350-be58-05216afc5aff")
+	kdbxKdfArgon2id  = uuid.MustParse("9e298b19-56db-4773-b23d-fc3ec6f0a1e6")
+)
+
+type kdbxFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    kdbxMeta `xml:"Meta"`
+	Root    struct {
+		Group *kdbxGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type kdbxMeta struct {
+	Generator         string `xml:"Generator"`
+	DatabaseName      string `xml:"DatabaseName"`
+	RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+}
+
+// kdbxGroup lists entries before sub-groups; protectValues walks them in the
+// same order so the inner stream lines up with the marshalled document.
+type kdbxGroup struct {
+	UUID    string       `xml:"UUID"`
+	Name    string       `xml:"Name"`
+	Entries []*kdbxEntry `xml:"Entry"`
+	Groups  []*kdbxGroup `xml:"Group"`
+}
+
+type kdbxEntry struct {
+	UUID     string       `xml:"UUID"`
+	Tags     string       `xml:"Tags,omitempty"`
+	Strings  []kdbxString `xml:"String"`
+	Binaries []kdbxBinary `xml:"Binary"`
+}
+
+type kdbxString struct {
+	Key   string    `xml:"Key"`
+	Value kdbxValue `xml:"Value"`
+}
+
+type kdbxValue struct {
+	Protected string `xml:"Protected,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+type kdbxBinary struct {
+	Key   string `xml:"Key"`
+	Value struct {
+		Ref string `xml:"Ref,attr"`
+	} `xml:"Value"`
+}
+
+func (k KeePass) Write(s *vault_export_domain.Snapshot, password string) ([]byte, string, error) {
+	root := &kdbxGroup{UUID: kdbxUUID(""), Name: s.VaultName}
+	if root.Name == "" {
+		root.Name = "Vault"
+	}
+	groups := map[string]*kdbxGroup{"": root}
+	var group func(path string) *kdbxGroup
+	group = func(path string) *kdbxGroup {
+		if g, ok := groups[path]; ok {
+			return g
+		}
+		parent, name := "", path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parent, name = path[:i], path[i+1:]
+		}
+		g := &kdbxGroup{UUID: kdbxUUID(""), Name: name}
+		p := group(parent)
+		p.Groups = append(p.Groups, g)
+		groups[path] = g
+		return g
+	}
+	for _, f := range s.Folders {
+		group(strings.Trim(f, "/"))
+	}
+
+	var binaries [][]byte
+	for _, it := range s.Items {
+		entry, files := keePassEntryOf(it)
+		for _, f := range files {
+			b := kdbxBinary{Key: f.Name}
+			b.Value.Ref = strconv.Itoa(len(binaries))
+			binaries = append(binaries, f.Data)
+			entry.Binaries = append(entry.Binaries, b)
+		}
+		g := group(strings.Trim(it.Folder, "/"))
+		g.Entries = append(g.Entries, entry)
+	}
+
+	// ---------- Inner stream and document ----------
+	streamKey := make([]byte, 64)
+	if _, err := rand.Read(streamKey); err != nil {
+		return nil, "", err
+	}
+	h := sha512.Sum512(streamKey)
+	stream, err := chacha20.NewUnauthenticatedCipher(h[:32], h[32:44])
+	if err != nil {
+		return nil, "", err
+	}
+	protectValues(root, stream)
+
+	doc := kdbxFile{Meta: kdbxMeta{Generator: "dVault", DatabaseName: root.Name, RecycleBinEnabled: "False"}}
+	doc.Root.Group = root
+	body, err := xml.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var inner bytes.Buffer
+	writeField(&inner, kdbxInnerStreamID, u32(kdbxStreamChaCha20))
+	writeField(&inner, kdbxInnerStreamKey, streamKey)
+	for _, b := range binaries {
+		writeField(&inner, kdbxInnerBinary, append([]byte{0}, b...))
+	}
+	writeField(&inner, kdbxInnerEnd, nil)
+	inner.WriteString(xml.Header)
+	inner.Write(body)
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(inner.Bytes()); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	// ---------- Keys ----------
+	seed, iv, salt := make([]byte, 32), make([]byte, aes.BlockSize), make([]byte, 32)
+	for _, b := range [][]byte{seed, iv, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+	}
+	iterations, memoryKiB, lanes := k.Iterations, k.MemoryKiB, k.Lanes
+	if iterations == 0 {
+		iterations = kdbxDefaultIterations
+	}
+	if memoryKiB == 0 {
+		memoryKiB = kdbxDefaultMemoryKiB
+	}
+	if lanes == 0 {
+		lanes = kdbxDefaultLanes
+	}
+	pw := sha256.Sum256([]byte(password))
+	composite := sha256.Sum256(pw[:])
+	transformed := argon2.IDKey(composite[:], salt, uint32(iterations), uint32(memoryKiB), uint8(lanes), 32)
+	masterKey := sha256.Sum256(append(append([]byte{}, seed...), transformed...))
+	hmacBase := sha512.Sum512(append(append(append([]byte{}, seed...), transformed...), 0x01))
+
+	// ---------- Outer header ----------
+	var kdf bytes.Buffer
+	kdf.Write([]byte{0x00, 0x01})
+	writeVariant(&kdf, 0x42, "$UUID", kdbxKdfArgon2id[:])
+	writeVariant(&kdf, 0x42, "S", salt)
+	writeVariant(&kdf, 0x04, "P", u32(lanes))
+	writeVariant(&kdf, 0x05, "M", u64(memoryKiB*1024))
+	writeVariant(&kdf, 0x05, "I", u64(iterations))
+	writeVariant(&kdf, 0x04, "V", u32(0x13))
+	kdf.WriteByte(0)
+
+	var out bytes.Buffer
+	out.Write(u32(kdbxSignature1))
+	out.Write(u32(kdbxSignature2))
+	out.Write(u32(kdbxVersion))
+	writeField(&out, kdbxCipherID, kdbxCipherAES256[:])
+	writeField(&out, kdbxCompressionFlags, u32(1))
+	writeField(&out, kdbxMasterSeed, seed)
+	writeField(&out, kdbxEncryptionIV, iv)
+	writeField(&out, kdbxKdfParameters, kdf.Bytes())
+	writeField(&out, kdbxEndOfHeader, []byte("\r\n\r\n"))
+	headerHash := sha256.Sum256(out.Bytes())
+	headerMAC := kdbxBlockMAC(hmacBase[:], kdbxHeaderBlockIndex, out.Bytes())
+	out.Write(headerHash[:])
+	out.Write(headerMAC)
+
+	// ---------- Encrypt into HMAC blocks ----------
+	block, err := aes.NewCipher(masterKey[:])
+	if err != nil {
+		return nil, "", err
+	}
+	plain := compressed.Bytes()
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	plain = append(plain, bytes.Repeat([]byte{byte(pad)}, pad)...)
+	payload := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(payload, plain)
+
+	for index := uint64(0); ; index++ {
+		n := len(payload)
+		if n > kdbxBlockSize {
+			n = kdbxBlockSize
+		}
+		chunk := payload[:n]
+		payload = payload[n:]
+		var prefix [12]byte
+		binary.LittleEndian.PutUint64(prefix[:8], index)
+		binary.LittleEndian.PutUint32(prefix[8:], uint32(n))
+		out.Write(kdbxBlockMAC(hmacBase[:], index, append(prefix[:], chunk...)))
+		out.Write(prefix[8:])
+		out.Write(chunk)
+		if n == 0 {
+			break
+		}
+	}
+	return out.Bytes(), "kdbx", nil
+}
+
+func keePassEntryOf(it vault_export_domain.Item) (*kdbxEntry, []vault_export_domain.Attachment) {
+	entry := &kdbxEntry{UUID: kdbxUUID(it.Entry.GetId())}
+	base := it.Entry.GetBase()
+	add := func(key, value string, protected bool) {
+		if value == "" && key != "Title" {
+			return
+		}
+		v := kdbxValue{Text: value}
+		if protected {
+			v.Protected = "True"
+		}
+		entry.Strings = append(entry.Strings, kdbxString{Key: key, Value: v})
+	}
+	add("Title", it.Entry.GetName(), false)
+	files := append([]vault_export_domain.Attachment{}, it.Attachments...)
+
+	switch e := it.Entry.(type) {
+	case *vaults_domain.LoginEntry:
+		add("UserName", e.UserName, false)
+		add("Password", e.Password, true)
+		add("URL", e.Website, false)
+	case *vaults_domain.CardEntry:
+		add("Card Holder", e.Owner, false)
+		add("Card Number", e.Number, true)
+		add("Expiration", e.Expiration, false)
+		add("CVC", e.CVC, true)
+	case *vaults_domain.IdentityEntry:
+		for _, f := range []field{
+			{"Title Prefix", e.Genre}, {"First Name", e.FirstName}, {"Middle Name", e.SecondFirstName},
+			{"Last Name", e.LastName}, {"Username", e.Username}, {"Company", e.Company},
+			{"Social Security Number", e.SocialSecurityNumber}, {"ID Number", e.IDNumber},
+			{"Driver License", e.DriverLicense}, {"Email", e.Mail}, {"Phone", e.Telephone},
+			{"Address 1", e.AddressOne}, {"Address 2", e.AddressTwo}, {"Address 3", e.AddressThree},
+			{"City", e.City}, {"State", e.State}, {"Postal Code", e.PostalCode}, {"Country", e.Country},
+		} {
+			add(f.name, f.value, false)
+		}
+	case *vaults_domain.SSHKeyEntry:
+		add("Fingerprint", e.EFingerprint, false)
+		if e.PrivateKey != "" {
+			files = append(files, vault_export_domain.Attachment{Name: "id_key", Data: []byte(e.PrivateKey)})
+		}
+		if e.PublicKey != "" {
+			files = append(files, vault_export_domain.Attachment{Name: "id_key.pub", Data: []byte(e.PublicKey)})
+		}
+	case *vaults_domain.OTPEntry:
+		// KeePassXC reads seeds from the "otp" attribute.
+		add("UserName", e.AccountName, false)
+		add("otp", e.URI(), true)
+	}
+	add("Notes", base.AdditionnalNote, false)
+	for _, f := range customFields(it.Entry) {
+		add(f.name, f.value, false)
+	}
+	if base.IsFavorite {
+		entry.Tags = "favorite"
+	}
+	return entry, files
+}
+
+// protectValues replaces Protected="True" values with their base64
+// ciphertext, in document order.
+func protectValues(g *kdbxGroup, stream cipher.Stream) {
+	for _, e := range g.Entries {
+		for i := range e.Strings {
+			v := &e.Strings[i].Value
+			if v.Protected == "" {
+				continue
+			}
+			sealed := make([]byte, len(v.Text))
+			stream.XORKeyStream(sealed, []byte(v.Text))
+			v.Text = base64.StdEncoding.EncodeToString(sealed)
+		}
+	}
+	for _, child := range g.Groups {
+		protectValues(child, stream)
+	}
+}
+
+func kdbxBlockMAC(base []byte, index uint64, data []byte) []byte {
+	key := sha512.Sum512(append(u64(index), base...))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// kdbxUUID encodes id as a KeePass UUID, generating one when id is not a
+// UUID so every entry and group stays unique.
+func kdbxUUID(id string) string {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		u = uuid.New()
+	}
+	return base64.StdEncoding.EncodeToString(u[:])
+}
+
+func writeField(buf *bytes.Buffer, id byte, value []byte) {
+	buf.WriteByte(id)
+	buf.Write(u32(uint32(len(value))))
+	buf.Write(value)
+}
+
+func writeVariant(buf *bytes.Buffer, kind byte, key string, value []byte) {
+	buf.WriteByte(kind)
+	buf.Write(u32(uint32(len(key))))
+	buf.WriteString(key)
+	buf.Write(u32(uint32(len(value))))
+	buf.Write(value)
+}
+
+func u32(v uint32) []byte {
+	return binary.LittleEndian.AppendUint32(nil, v)
+}
+
+func u64(v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(nil, v)
+}
@@ -0,0 +1,31 @@
+package vault_export_persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+type GormAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditRepository(db *gorm.DB) *GormAuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+func (r *GormAuditRepository) Save(ctx context.Context, a *vault_export_domain.ExportAudit) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r *GormAuditRepository) ListByUser(ctx context.Context, userID string) ([]vault_export_domain.ExportAudit, error) {
+	var out []vault_export_domain.ExportAudit
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var _ vault_export_domain.AuditRepository = (*GormAuditRepository)(nil)
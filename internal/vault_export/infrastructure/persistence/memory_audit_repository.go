@@ -0,0 +1,40 @@
+package vault_export_persistence
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+type MemoryAuditRepository struct {
+	mu      sync.Mutex
+	records []vault_export_domain.ExportAudit
+}
+
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+func (r *MemoryAuditRepository) Save(_ context.Context, a *vault_export_domain.ExportAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, *a)
+	return nil
+}
+
+func (r *MemoryAuditRepository) ListByUser(_ context.Context, userID string) ([]vault_export_domain.ExportAudit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []vault_export_domain.ExportAudit{}
+	for _, a := range r.records {
+		if a.UserID == userID {
+			out = append(out, a)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+var _ vault_export_domain.AuditRepository = (*MemoryAuditRepository)(nil)
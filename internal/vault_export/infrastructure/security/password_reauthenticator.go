@@ -0,0 +1,36 @@
+package vault_export_security
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+
+	onboarding_domain "vault-app/internal/onboarding/domain"
+)
+
+var errInvalidAccountPassword = errors.New("invalid account password")
+
+// PasswordReauthenticator checks the account password against the bcrypt
+// hash stored at onboarding, the same check email sign-in performs.
+type PasswordReauthenticator struct {
+	users onboarding_domain.UserRepository
+}
+
+func NewPasswordReauthenticator(users onboarding_domain.UserRepository) *PasswordReauthenticator {
+	return &PasswordReauthenticator{users: users}
+}
+
+func (r *PasswordReauthenticator) Reauthenticate(_ context.Context, email string, password string) error {
+	if email == "" || password == "" {
+		return errInvalidAccountPassword
+	}
+	user, err := r.users.FindByEmail(email)
+	if err != nil || user == nil {
+		return errInvalidAccountPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errInvalidAccountPassword
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package vault_export_ui
+
+import (
+	"context"
+	"fmt"
+
+	vault_export_usecases "vault-app/internal/vault_export/application/usecases"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+)
+
+type ExportHandler struct {
+	exportUseCase *vault_export_usecases.ExportVaultUsecase
+	listUseCase   *vault_export_usecases.ListExportsUsecase
+}
+
+func NewExportHandler(
+	exportUC *vault_export_usecases.ExportVaultUsecase,
+	listUC *vault_export_usecases.ListExportsUsecase,
+) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUC,
+		listUseCase:   listUC,
+	}
+}
+
+func (h *ExportHandler) ExportVault(ctx context.Context, req vault_export_usecases.ExportVaultRequest) (*vault_export_domain.Result, error) {
+	if h.exportUseCase == nil {
+		return nil, fmt.Errorf("export vault use case is not initialized")
+	}
+	return h.exportUseCase.Execute(ctx, req)
+}
+
+// ListExports returns the export audit trail, newest first.
+func (h *ExportHandler) ListExports(ctx context.Context, userID string) ([]vault_export_domain.ExportAudit, error) {
+	if h.listUseCase == nil {
+		return nil, fmt.Errorf("list exports use case is not initialized")
+	}
+	return h.listUseCase.Execute(ctx, userID)
+}
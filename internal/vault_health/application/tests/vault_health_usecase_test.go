@@ -0,0 +1,307 @@
+package vault_health_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	notification_center_usecases "vault-app/internal/notification_center/application/use_cases"
+	notification_center_domain "vault-app/internal/notification_center/domain"
+	notification_center_services "vault-app/internal/notification_center/infrastructure/services"
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_health_usecases "vault-app/internal/vault_health/application/usecases"
+	vault_health_domain "vault-app/internal/vault_health/domain"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+const strongPassword = "vT9#qLm2!xWz7&Rb4pKe"
+
+type vaultMock struct {
+	vp     vaults_domain.VaultPayload
+	locked bool
+}
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	if m.locked {
+		return nil, errors.New("no session")
+	}
+	return &m.vp, nil
+}
+
+type breaches map[string]bool
+
+func (b breaches) Contains(password string) bool { return b[password] }
+
+func login(id, website, password string) vaults_domain.LoginEntry {
+	e := vaults_domain.LoginEntry{UserName: "alice", Password: password, Website: website}
+	e.ID, e.EntryName = id, id
+	e.PasswordChangedAt = now.AddDate(0, -1, 0).Format(time.RFC3339)
+	return e
+}
+
+func sshKey(t *testing.T, id string, pub ssh.PublicKey) vaults_domain.SSHKeyEntry {
+	t.Helper()
+	e := vaults_domain.SSHKeyEntry{PublicKey: string(ssh.MarshalAuthorizedKey(pub))}
+	e.ID, e.EntryName = id, id
+	return e
+}
+
+func kinds(findings []vault_health_domain.Finding, entryID string) []vault_health_domain.RiskKind {
+	var out []vault_health_domain.RiskKind
+	for _, f := range findings {
+		if f.EntryID == entryID {
+			out = append(out, f.Kind)
+		}
+	}
+	return out
+}
+
+func TestEstimateStrength(t *testing.T) {
+	for _, tc := range []struct {
+		password string
+		max, min int
+		warning  string
+	}{
+		{"password", 0, 0, vault_health_domain.WarnCommon},
+		{"P@ssw0rd", 0, 0, vault_health_domain.WarnCommon},
+		{"Summer2024!", 1, 0, vault_health_domain.WarnDictionary},
+		{"aaaaaaaaaaaa", 1, 0, vault_health_domain.WarnRepeat},
+		{"abcdefgh12345678", 1, 0, vault_health_domain.WarnSequence},
+		{"qwertyuiop", 1, 0, vault_health_domain.WarnKeyboard},
+		{"x7#Kp", 1, 0, vault_health_domain.WarnShort},
+		{"correct horse battery staple", 4, 3, ""},
+		{strongPassword, 4, 4, ""},
+	} {
+		s := vault_health_domain.EstimateStrength(tc.password)
+		require.LessOrEqual(t, s.Score, tc.max, tc.password)
+		require.GreaterOrEqual(t, s.Score, tc.min, tc.password)
+		if tc.warning != "" {
+			require.Contains(t, s.Warnings, tc.warning, tc.password)
+		}
+	}
+}
+
+func TestCardExpiry(t *testing.T) {
+	for exp, want := range map[string]string{
+		"01/27":   "2027-02-01",
+		"12/2026": "2027-01-01",
+		"2026-09": "2026-10-01",
+		" 3-28 ":  "2028-04-01",
+	} {
+		end, ok := vault_health_domain.CardExpiry(exp)
+		require.True(t, ok, exp)
+		require.Equal(t, want, end.Format("2006-01-02"), exp)
+	}
+	for _, exp := range []string{"", "13/27", "0127", "01/2"} {
+		_, ok := vault_health_domain.CardExpiry(exp)
+		require.False(t, ok, exp)
+	}
+}
+
+func TestAnalyze_ReportsEachRisk(t *testing.T) {
+	var vp vaults_domain.VaultPayload
+	e := &vp.Entries
+
+	healthy := login("healthy", "https://bank.example", strongPassword)
+	breached := login("breached", "bank2.example", "Tr0ub4dor&3-horse-stapler")
+	weak := login("weak", "https://shop.example", "Summer2024!")
+	reusedA := login("reused-a", "https://a.example", "K9!vq2#Lm7@pXz4R")
+	reusedB := login("reused-b", "https://b.example", "K9!vq2#Lm7@pXz4R")
+	stale := login("stale", "https://old.example", "Zx8$wQ4!nB6#tY2@")
+	stale.PasswordChangedAt = now.AddDate(-2, 0, 0).Format(time.RFC3339)
+	// Renaming it yesterday did not change the password.
+	stale.UpdatedAt = now.AddDate(0, 0, -1).Format(time.RFC3339)
+	// Untracked entries go by their creation, whatever their last edit.
+	legacy := login("legacy", "https://legacy.example", "Mn3@vB8!cX5#zL1q")
+	legacy.PasswordChangedAt = ""
+	legacy.CreatedAt = now.AddDate(-2, 0, 0).Format(time.RFC3339)
+	legacy.UpdatedAt = now.AddDate(0, 0, -1).Format(time.RFC3339)
+	insecure := login("insecure", "http://forum.example/login", "Hj5^rT8*kP3!mW7q")
+	router := login("router", "http://192.168.1.1", "Lr6&eD2!sF9#gH4j")
+	github := login("github", "https://github.com/login", "Gq7!Np3$Xs8^Vb2m")
+	gitlab := login("gitlab", "gitlab.com", "Wc4#Jz9!Ht6&Qd1y")
+	trashed := login("trashed", "http://x.example", "123")
+	trashed.Trashed = true
+	e.Login = []vaults_domain.LoginEntry{healthy, breached, weak, reusedA, reusedB, stale, legacy, insecure, router, github, gitlab, trashed}
+
+	// github is covered by a linked seed, gitlab only by its issuer.
+	githubOTP := vaults_domain.OTPEntry{LinkedLoginID: "github"}
+	gitlabOTP := vaults_domain.OTPEntry{Issuer: "GitLab"}
+	e.OTP = []vaults_domain.OTPEntry{githubOTP}
+
+	expired := vaults_domain.CardEntry{Expiration: "09/26"}
+	expired.ID, expired.EntryName = "expired-card", "Old Visa"
+	valid := vaults_domain.CardEntry{Fields: vaults_domain.JSONMap{"expiration": "10/26"}}
+	valid.ID, valid.EntryName = "valid-card", "Visa"
+	e.Card = []vaults_domain.CardEntry{expired, valid}
+
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edKey, err := ssh.NewPublicKey(edPub)
+	require.NoError(t, err)
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	rsaKey, err := ssh.NewPublicKey(&rsaPriv.PublicKey)
+	require.NoError(t, err)
+	_, caPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ca, err := ssh.NewSignerFromKey(caPriv)
+	require.NoError(t, err)
+	cert := &ssh.Certificate{Key: edKey, CertType: ssh.UserCert, ValidBefore: uint64(now.AddDate(0, 0, -1).Unix())}
+	require.NoError(t, cert.SignCert(rand.Reader, ca))
+	// A key stored without its public half is read from the private key.
+	block, err := ssh.MarshalPrivateKey(rsaPriv, "")
+	require.NoError(t, err)
+	privateOnly := vaults_domain.SSHKeyEntry{PrivateKey: string(pem.EncodeToMemory(block))}
+	privateOnly.ID, privateOnly.EntryName = "private-only", "private-only"
+	e.SSHKey = []vaults_domain.SSHKeyEntry{sshKey(t, "ed25519", edKey), sshKey(t, "rsa-1024", rsaKey), sshKey(t, "cert", cert), privateOnly}
+
+	analyzer := vault_health_domain.Analyzer{
+		Options:  vault_health_domain.DefaultOptions(),
+		Breaches: breaches{"Tr0ub4dor&3-horse-stapler": true},
+	}
+	report := analyzer.Analyze("user-1", &vp, now)
+	f := report.Findings
+
+	require.True(t, report.BreachCheck)
+	require.Equal(t, 17, report.EntriesScanned)
+	require.Empty(t, kinds(f, "healthy"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskBreachedPassword}, kinds(f, "breached"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskWeakPassword}, kinds(f, "weak"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskReusedPassword}, kinds(f, "reused-a"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskStalePassword}, kinds(f, "stale"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskStalePassword}, kinds(f, "legacy"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskInsecureURL}, kinds(f, "insecure"))
+	require.Empty(t, kinds(f, "router"))
+	require.Empty(t, kinds(f, "github"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskMissingTOTP}, kinds(f, "gitlab"))
+	require.Empty(t, kinds(f, "trashed"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskExpiredCard}, kinds(f, "expired-card"))
+	require.Empty(t, kinds(f, "valid-card"))
+	require.Empty(t, kinds(f, "ed25519"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskWeakSSHKey}, kinds(f, "rsa-1024"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskExpiredSSHKey}, kinds(f, "cert"))
+	require.Equal(t, []vault_health_domain.RiskKind{vault_health_domain.RiskWeakSSHKey}, kinds(f, "private-only"))
+
+	// Most urgent first; details never carry the password.
+	require.Equal(t, vault_health_domain.SeverityCritical, f[0].Severity)
+	for _, finding := range f {
+		require.NotContains(t, finding.Detail, "Summer2024")
+		if finding.EntryID == "reused-a" {
+			require.Equal(t, []string{"reused-b"}, finding.Related)
+		}
+	}
+	require.Equal(t, "1 breached password, 2 reused passwords, 1 weak password, 1 expired SSH key, "+
+		"2 weak SSH keys, 1 login without two-factor codes, 1 http-only website, 1 expired card, 2 old passwords", report.Summary())
+	// 17 entries, 10 with a finding above low severity.
+	require.Equal(t, 41, report.Score)
+
+	// The issuer of a seed covers the login too.
+	e.OTP = append(e.OTP, gitlabOTP)
+	require.Empty(t, kinds(analyzer.Analyze("user-1", &vp, now).Findings, "gitlab"))
+
+	// Without a corpus the breach check is skipped.
+	report = vault_health_domain.Analyzer{Options: vault_health_domain.DefaultOptions()}.Analyze("user-1", &vp, now)
+	require.False(t, report.BreachCheck)
+	require.Empty(t, kinds(report.Findings, "breached"))
+}
+
+type cloudMock struct{ marked []string }
+
+func (c *cloudMock) ListByUser(context.Context, string, int, int) ([]notification_center_domain.Notification, error) {
+	return []notification_center_domain.Notification{{ID: "cloud-1", Status: notification_center_domain.StatusUnread}}, nil
+}
+func (c *cloudMock) CountUnread(context.Context, string) (int64, error) { return 1, nil }
+func (c *cloudMock) MarkRead(_ context.Context, id string) error {
+	c.marked = append(c.marked, id)
+	return nil
+}
+func (c *cloudMock) Archive(context.Context, string) error     { return nil }
+func (c *cloudMock) MarkAllRead(context.Context, string) error { return nil }
+
+func TestHealthReport_FeedsNotificationCenter(t *testing.T) {
+	vault := &vaultMock{}
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{login("weak", "https://shop.example", "password")}
+	cloud := &cloudMock{}
+	notifications := notification_center_usecases.NewNotificationUseCase(cloud).
+		WithLocalStore(notification_center_services.NewMemoryLocalStore())
+	enabled := true
+	gate := vault_health_domain.FeatureGateFunc(func(string) (bool, error) { return enabled, nil })
+
+	uc := vault_health_usecases.NewHealthReportUsecase(vault, gate, notifications)
+	uc.Now = func() time.Time { return now }
+	ctx := context.Background()
+
+	report, err := uc.Execute(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, report.Findings, 1)
+
+	list, err := notifications.ListByUser(ctx, "user-1", 20, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	local := list[0]
+	require.Equal(t, vault_health_usecases.NotificationType, local.Type)
+	require.Equal(t, notification_center_domain.CategorySecurity, local.Category)
+	require.Equal(t, "1 weak password", local.Body)
+	require.NotContains(t, local.Body, "shop.example")
+	count, err := notifications.CountUnread(ctx, "user-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	// Another user cannot touch it.
+	require.NoError(t, notifications.MarkRead(ctx, "user-2", local.ID))
+	require.Equal(t, []string{local.ID}, cloud.marked)
+	count, _ = notifications.CountUnread(ctx, "user-1")
+	require.EqualValues(t, 2, count)
+	cloud.marked = nil
+
+	// Read state survives an identical re-run; a change raises it again.
+	require.NoError(t, notifications.MarkRead(ctx, "user-1", local.ID))
+	require.Empty(t, cloud.marked)
+	_, err = uc.Execute(ctx, "user-1")
+	require.NoError(t, err)
+	count, _ = notifications.CountUnread(ctx, "user-1")
+	require.EqualValues(t, 1, count)
+
+	vault.vp.Entries.Login = append(vault.vp.Entries.Login, login("weak-2", "https://shop2.example", "qwerty"))
+	_, err = uc.Execute(ctx, "user-1")
+	require.NoError(t, err)
+	list, _ = notifications.ListByUser(ctx, "user-1", 20, 0)
+	require.Len(t, list, 2)
+	require.Equal(t, "2 weak passwords", list[0].Body)
+	require.Equal(t, notification_center_domain.StatusUnread, list[0].Status)
+
+	// Cloud ids still go to the cloud; later pages carry no local items.
+	require.NoError(t, notifications.MarkRead(ctx, "user-1", "cloud-1"))
+	require.Equal(t, []string{"cloud-1"}, cloud.marked)
+	list, _ = notifications.ListByUser(ctx, "user-1", 20, 20)
+	require.Len(t, list, 1)
+
+	// A clean report withdraws the notification.
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{login("healthy", "https://bank.example", strongPassword)}
+	report, err = uc.Execute(ctx, "user-1")
+	require.NoError(t, err)
+	require.Empty(t, report.Findings)
+	list, _ = notifications.ListByUser(ctx, "user-1", 20, 0)
+	require.Len(t, list, 1)
+	require.Equal(t, "cloud-1", list[0].ID)
+
+	enabled = false
+	_, err = uc.Execute(ctx, "user-1")
+	require.ErrorIs(t, err, vault_health_domain.ErrFeatureDisabled)
+	enabled = true
+	vault.locked = true
+	_, err = uc.Execute(ctx, "user-1")
+	require.ErrorIs(t, err, vault_health_domain.ErrVaultLocked)
+	_, err = uc.Execute(ctx, "")
+	require.ErrorIs(t, err, vault_health_domain.ErrUserIDRequired)
+}
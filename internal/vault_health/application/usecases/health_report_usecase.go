@@ -0,0 +1,100 @@
+package vault_health_usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	notification_center_domain "vault-app/internal/notification_center/domain"
+	vault_health_domain "vault-app/internal/vault_health/domain"
+)
+
+// NotificationType identifies health report notifications.
+const NotificationType = "vault.health_report"
+
+// HealthReportUsecase analyses the unlocked vault and raises the summary
+// in the notification center.
+type HealthReportUsecase struct {
+	Vault    VaultSessions
+	Gate     vault_health_domain.FeatureGate
+	Notifier Notifier
+	// Breaches is optional; the report says whether it was checked.
+	Breaches vault_health_domain.BreachCorpus
+	Options  vault_health_domain.Options
+	Now      func() time.Time
+}
+
+func NewHealthReportUsecase(vault VaultSessions, gate vault_health_domain.FeatureGate, notifier Notifier) *HealthReportUsecase {
+	return &HealthReportUsecase{
+		Vault:    vault,
+		Gate:     gate,
+		Notifier: notifier,
+		Options:  vault_health_domain.DefaultOptions(),
+		Now:      time.Now,
+	}
+}
+
+// Execute returns the report. When a notifier is set, the summary is
+// published, or withdrawn once the report is clean; a notifier failure is
+// returned alongside the report.
+func (uc *HealthReportUsecase) Execute(ctx context.Context, userID string) (*vault_health_domain.Report, error) {
+	if uc.Vault == nil {
+		return nil, fmt.Errorf("vault health vault session is not initialized")
+	}
+	if uc.Gate == nil {
+		return nil, fmt.Errorf("vault health feature gate is not initialized")
+	}
+	if userID == "" {
+		return nil, vault_health_domain.ErrUserIDRequired
+	}
+	enabled, err := uc.Gate.ThreatDetectionEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, vault_health_domain.ErrFeatureDisabled
+	}
+	vp, err := uc.Vault.GetVaultSession(userID)
+	if err != nil || vp == nil {
+		return nil, fmt.Errorf("%w: %v", vault_health_domain.ErrVaultLocked, err)
+	}
+
+	analyzer := vault_health_domain.Analyzer{Options: uc.Options, Breaches: uc.Breaches}
+	report := analyzer.Analyze(userID, vp, uc.Now())
+	if uc.Notifier == nil {
+		return report, nil
+	}
+	if len(report.Findings) == 0 {
+		if err := uc.Notifier.Withdraw(ctx, userID, eventID(userID)); err != nil {
+			return report, fmt.Errorf("withdraw vault health notification: %w", err)
+		}
+		return report, nil
+	}
+	if err := uc.Notifier.Publish(ctx, notification(report)); err != nil {
+		return report, fmt.Errorf("publish vault health notification: %w", err)
+	}
+	return report, nil
+}
+
+// notification carries counts only: entry names and details stay in the
+// report.
+func notification(r *vault_health_domain.Report) notification_center_domain.Notification {
+	counts := map[string]int{}
+	for kind, n := range r.Counts {
+		counts[string(kind)] = n
+	}
+	return notification_center_domain.Notification{
+		UserID:   r.UserID,
+		Type:     NotificationType,
+		Category: notification_center_domain.CategorySecurity,
+		Title:    fmt.Sprintf("Vault health: %d issue(s) found", len(r.Findings)),
+		Body:     r.Summary(),
+		Payload:  map[string]any{"score": r.Score, "counts": counts},
+		EventID:  eventID(r.UserID),
+	}
+}
+
+// eventID keys the user's single health report notification.
+func eventID(userID string) string {
+	return NotificationType + ":" + userID
+}
@@ -0,0 +1,20 @@
+package vault_health_usecases
+
+import (
+	"context"
+
+	notification_center_domain "vault-app/internal/notification_center/domain"
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultSessions reads the decrypted vault of an unlocked user.
+type VaultSessions interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+}
+
+// Notifier raises a device-local notification in the notification center,
+// and withdraws it.
+type Notifier interface {
+	Publish(ctx context.Context, n notification_center_domain.Notification) error
+	Withdraw(ctx context.Context, userID string, eventID string) error
+}
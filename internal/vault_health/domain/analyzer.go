@@ -0,0 +1,174 @@
+package vault_health_domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// Analyzer runs every health check over a decrypted vault. Trashed entries
+// are ignored.
+type Analyzer struct {
+	Options Options
+	// Breaches is optional; without it the breach check is skipped.
+	Breaches BreachCorpus
+}
+
+func (a Analyzer) Analyze(userID string, vp *vaults_domain.VaultPayload, now time.Time) *Report {
+	r := &Report{UserID: userID, GeneratedAt: now, Counts: map[RiskKind]int{}, BreachCheck: a.Breaches != nil}
+	if vp == nil {
+		r.Score = 100
+		return r
+	}
+	entries := &vp.Entries
+	flagged := map[string]bool{}
+	add := func(f Finding) {
+		r.Findings = append(r.Findings, f)
+		r.Counts[f.Kind]++
+		if f.Severity.rank() >= SeverityMedium.rank() {
+			flagged[f.EntryType+"/"+f.EntryID] = true
+		}
+	}
+
+	var logins []*vaults_domain.LoginEntry
+	for i := range entries.Login {
+		if !entries.Login[i].Trashed {
+			logins = append(logins, &entries.Login[i])
+		}
+	}
+	var otps []*vaults_domain.OTPEntry
+	for i := range entries.OTP {
+		if !entries.OTP[i].Trashed {
+			otps = append(otps, &entries.OTP[i])
+		}
+	}
+
+	byPassword := map[string][]*vaults_domain.LoginEntry{}
+	for _, e := range logins {
+		r.EntriesScanned++
+		for _, f := range a.loginRisks(e, otps, now) {
+			add(f)
+		}
+		if e.Password != "" {
+			byPassword[e.Password] = append(byPassword[e.Password], e)
+		}
+	}
+	for _, group := range byPassword {
+		if len(group) < 2 {
+			continue
+		}
+		for _, e := range group {
+			f := finding(e, RiskReusedPassword, SeverityHigh, fmt.Sprintf("same password as %d other login(s)", len(group)-1))
+			for _, other := range group {
+				if other != e {
+					f.Related = append(f.Related, other.ID)
+				}
+			}
+			add(f)
+		}
+	}
+
+	for i := range entries.Card {
+		e := &entries.Card[i]
+		if e.Trashed {
+			continue
+		}
+		r.EntriesScanned++
+		if end, ok := CardExpiry(cardExpiration(e)); ok && !now.Before(end) {
+			add(finding(e, RiskExpiredCard, SeverityMedium, "expired at the end of "+end.AddDate(0, 0, -1).Format("01/2006")))
+		}
+	}
+	for i := range entries.SSHKey {
+		e := &entries.SSHKey[i]
+		if e.Trashed {
+			continue
+		}
+		r.EntriesScanned++
+		for _, f := range sshKeyRisks(e, now, a.Options.MinRSABits) {
+			add(f)
+		}
+	}
+
+	sortFindings(r.Findings)
+	r.Score = 100
+	if r.EntriesScanned > 0 {
+		r.Score = 100 * (r.EntriesScanned - len(flagged)) / r.EntriesScanned
+	}
+	return r
+}
+
+func (a Analyzer) loginRisks(e *vaults_domain.LoginEntry, otps []*vaults_domain.OTPEntry, now time.Time) []Finding {
+	var findings []Finding
+	if e.Password != "" {
+		if a.Breaches != nil && a.Breaches.Contains(e.Password) {
+			findings = append(findings, finding(e, RiskBreachedPassword, SeverityCritical, "password appears in a known data breach"))
+		}
+		if s := EstimateStrength(e.Password); s.Score < a.Options.MinPasswordScore {
+			severity := SeverityMedium
+			if s.Score <= 1 {
+				severity = SeverityHigh
+			}
+			detail := fmt.Sprintf("strength %d/4", s.Score)
+			if len(s.Warnings) > 0 {
+				detail += ": " + strings.Join(s.Warnings, ", ")
+			}
+			findings = append(findings, finding(e, RiskWeakPassword, severity, detail))
+		}
+		if changed, ok := passwordChanged(e); ok && a.Options.StaleAfter > 0 && now.Sub(changed) > a.Options.StaleAfter {
+			days := int(now.Sub(changed).Hours() / 24)
+			findings = append(findings, finding(e, RiskStalePassword, SeverityLow, fmt.Sprintf("not changed for %d days", days)))
+		}
+	}
+
+	scheme, host, ok := websiteHost(e.Website)
+	if !ok {
+		return findings
+	}
+	if scheme == "http" && !isLocalHost(host) {
+		findings = append(findings, finding(e, RiskInsecureURL, SeverityMedium, "website uses http://"+host))
+	}
+	if domain, ok := a.Options.TwoFactorSites.Lookup(host); ok {
+		covered := false
+		for _, otp := range otps {
+			if otpCovers(otp, e, domain) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			findings = append(findings, finding(e, RiskMissingTOTP, SeverityMedium, domain+" supports authenticator codes"))
+		}
+	}
+	return findings
+}
+
+type namedEntry interface {
+	vaults_domain.EntryInterface
+	GetTypeName() string
+}
+
+func finding(e namedEntry, kind RiskKind, severity Severity, detail string) Finding {
+	base := e.GetBase()
+	return Finding{
+		Kind:      kind,
+		Severity:  severity,
+		EntryID:   base.ID,
+		EntryName: base.EntryName,
+		EntryType: e.GetTypeName(),
+		Detail:    detail,
+	}
+}
+
+// passwordChanged reads when the login's password last changed. Entries
+// saved before that was tracked fall back to their creation time, and to
+// their update time only when that is missing too.
+func passwordChanged(e *vaults_domain.LoginEntry) (time.Time, bool) {
+	for _, v := range []string{e.PasswordChangedAt, e.CreatedAt, e.UpdatedAt} {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
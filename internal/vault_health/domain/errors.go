@@ -0,0 +1,10 @@
+package vault_health_domain
+
+import "errors"
+
+var (
+	ErrFeatureDisabled = errors.New("threat detection is not included in your subscription")
+	ErrVaultLocked     = errors.New("vault is locked")
+	ErrUserIDRequired  = errors.New("user id is required")
+	ErrInvalidCorpus   = errors.New("invalid breach corpus")
+)
@@ -0,0 +1,112 @@
+package vault_health_domain
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// CardExpiry parses a card expiration ("MM/YY", "MM/YYYY", "MM-YY" or
+// "YYYY-MM") and returns the first instant the card is no longer valid.
+func CardExpiry(exp string) (time.Time, bool) {
+	exp = strings.TrimSpace(exp)
+	sep := strings.IndexAny(exp, "/-")
+	if sep < 0 {
+		return time.Time{}, false
+	}
+	a, b := strings.TrimSpace(exp[:sep]), strings.TrimSpace(exp[sep+1:])
+	if len(a) == 4 {
+		a, b = b, a
+	}
+	month, err := strconv.Atoi(a)
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, false
+	}
+	year, err := strconv.Atoi(b)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch len(b) {
+	case 2:
+		year += 2000
+	case 4:
+	default:
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC), true
+}
+
+// cardExpiration reads the legacy field, then the typed record fields.
+func cardExpiration(e *vaults_domain.CardEntry) string {
+	if e.Expiration != "" {
+		return e.Expiration
+	}
+	for _, key := range []string{"expiration", "expiry", "exp"} {
+		if v, ok := e.Fields[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// sshKeyRisks reports an expired certificate and a weak algorithm or key
+// size. Keys whose public half cannot be read are skipped.
+func sshKeyRisks(e *vaults_domain.SSHKeyEntry, now time.Time, minRSABits int) []Finding {
+	pub := sshPublicKey(e)
+	if pub == nil {
+		return nil
+	}
+	base := Finding{EntryID: e.ID, EntryName: e.EntryName, EntryType: e.GetTypeName()}
+	var findings []Finding
+	if cert, ok := pub.(*ssh.Certificate); ok {
+		if cert.ValidBefore != ssh.CertTimeInfinity && now.Unix() >= int64(cert.ValidBefore) {
+			f := base
+			f.Kind, f.Severity = RiskExpiredSSHKey, SeverityHigh
+			f.Detail = "certificate expired on " + time.Unix(int64(cert.ValidBefore), 0).UTC().Format("2006-01-02")
+			findings = append(findings, f)
+		}
+		pub = cert.Key
+	}
+	if detail := weakSSHKey(pub, minRSABits); detail != "" {
+		f := base
+		f.Kind, f.Severity, f.Detail = RiskWeakSSHKey, SeverityHigh, detail
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func sshPublicKey(e *vaults_domain.SSHKeyEntry) ssh.PublicKey {
+	if e.PublicKey != "" {
+		if pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(e.PublicKey)); err == nil {
+			return pub
+		}
+	}
+	if e.PrivateKey != "" {
+		if signer, err := ssh.ParsePrivateKey([]byte(e.PrivateKey)); err == nil {
+			return signer.PublicKey()
+		}
+	}
+	return nil
+}
+
+func weakSSHKey(pub ssh.PublicKey, minRSABits int) string {
+	switch pub.Type() {
+	case ssh.KeyAlgoDSA:
+		return "DSA keys are deprecated and refused by OpenSSH"
+	case ssh.KeyAlgoRSA:
+		cpk, ok := pub.(ssh.CryptoPublicKey)
+		if !ok {
+			return ""
+		}
+		if key, ok := cpk.CryptoPublicKey().(*rsa.PublicKey); ok && key.N.BitLen() < minRSABits {
+			return fmt.Sprintf("RSA key of %d bits, below %d", key.N.BitLen(), minRSABits)
+		}
+	}
+	return ""
+}
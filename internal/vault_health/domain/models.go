@@ -0,0 +1,162 @@
+package vault_health_domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RiskKind names one check of the health report.
+type RiskKind string
+
+const (
+	RiskBreachedPassword RiskKind = "breached_password"
+	RiskWeakPassword     RiskKind = "weak_password"
+	RiskReusedPassword   RiskKind = "reused_password"
+	RiskStalePassword    RiskKind = "stale_password"
+	RiskMissingTOTP      RiskKind = "missing_totp"
+	RiskInsecureURL      RiskKind = "insecure_url"
+	RiskExpiredCard      RiskKind = "expired_card"
+	RiskExpiredSSHKey    RiskKind = "expired_ssh_key"
+	RiskWeakSSHKey       RiskKind = "weak_ssh_key"
+)
+
+// riskOrder is the order kinds are summarised in, most urgent first.
+var riskOrder = []RiskKind{
+	RiskBreachedPassword, RiskReusedPassword, RiskWeakPassword, RiskExpiredSSHKey,
+	RiskWeakSSHKey, RiskMissingTOTP, RiskInsecureURL, RiskExpiredCard, RiskStalePassword,
+}
+
+var riskLabels = map[RiskKind][2]string{
+	RiskBreachedPassword: {"breached password", "breached passwords"},
+	RiskWeakPassword:     {"weak password", "weak passwords"},
+	RiskReusedPassword:   {"reused password", "reused passwords"},
+	RiskStalePassword:    {"old password", "old passwords"},
+	RiskMissingTOTP:      {"login without two-factor codes", "logins without two-factor codes"},
+	RiskInsecureURL:      {"http-only website", "http-only websites"},
+	RiskExpiredCard:      {"expired card", "expired cards"},
+	RiskExpiredSSHKey:    {"expired SSH key", "expired SSH keys"},
+	RiskWeakSSHKey:       {"weak SSH key", "weak SSH keys"},
+}
+
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 3
+	case SeverityHigh:
+		return 2
+	case SeverityMedium:
+		return 1
+	}
+	return 0
+}
+
+// Finding is one risk on one entry. It never carries secret material.
+type Finding struct {
+	Kind      RiskKind `json:"kind"`
+	Severity  Severity `json:"severity"`
+	EntryID   string   `json:"entry_id"`
+	EntryName string   `json:"entry_name"`
+	EntryType string   `json:"entry_type"`
+	Detail    string   `json:"detail"`
+	// Related lists the other entries sharing a reused password.
+	Related []string `json:"related,omitempty"`
+}
+
+// Report is the result of one analysis of a vault.
+type Report struct {
+	UserID         string    `json:"user_id"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	EntriesScanned int       `json:"entries_scanned"`
+	// Score is the share of scanned entries, 0 to 100, without a finding
+	// of medium severity or above.
+	Score       int              `json:"score"`
+	Counts      map[RiskKind]int `json:"counts"`
+	Findings    []Finding        `json:"findings"`
+	BreachCheck bool             `json:"breach_check"`
+}
+
+// Summary lists the number of findings per kind, most urgent first, e.g.
+// "2 breached passwords, 1 weak password".
+func (r *Report) Summary() string {
+	var parts []string
+	for _, kind := range riskOrder {
+		n := r.Counts[kind]
+		if n == 0 {
+			continue
+		}
+		label := riskLabels[kind][1]
+		if n == 1 {
+			label = riskLabels[kind][0]
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, label))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortFindings orders by severity, then kind, then entry name.
+func sortFindings(findings []Finding) {
+	order := map[RiskKind]int{}
+	for i, kind := range riskOrder {
+		order[kind] = i
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity.rank() != b.Severity.rank() {
+			return a.Severity.rank() > b.Severity.rank()
+		}
+		if a.Kind != b.Kind {
+			return order[a.Kind] < order[b.Kind]
+		}
+		return strings.ToLower(a.EntryName) < strings.ToLower(b.EntryName)
+	})
+}
+
+// Options tunes the analysis.
+type Options struct {
+	// StaleAfter is the age past which a login password is reported. The
+	// entry's last update stands in for the password change date.
+	StaleAfter time.Duration
+	// MinPasswordScore is the lowest acceptable Strength score (0-4).
+	MinPasswordScore int
+	// MinRSABits is the smallest acceptable RSA SSH key.
+	MinRSABits int
+	// TwoFactorSites lists the sites offering TOTP.
+	TwoFactorSites TwoFactorDirectory
+}
+
+func DefaultOptions() Options {
+	return Options{
+		StaleAfter:       365 * 24 * time.Hour,
+		MinPasswordScore: 3,
+		MinRSABits:       2048,
+		TwoFactorSites:   DefaultTwoFactorSites,
+	}
+}
+
+// BreachCorpus answers whether a password appears in known breaches. A
+// probabilistic corpus may report false positives, never false negatives.
+type BreachCorpus interface {
+	Contains(password string) bool
+}
+
+// FeatureGate tells whether the user's subscription includes threat
+// detection (FeatureFlags.ThreatDetectionEnabled).
+type FeatureGate interface {
+	ThreatDetectionEnabled(userID string) (bool, error)
+}
+
+// FeatureGateFunc adapts a function to FeatureGate.
+type FeatureGateFunc func(userID string) (bool, error)
+
+func (f FeatureGateFunc) ThreatDetectionEnabled(userID string) (bool, error) { return f(userID) }
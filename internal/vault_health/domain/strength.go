@@ -0,0 +1,206 @@
+package vault_health_domain
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength estimates how hard a password is to guess.
+type Strength struct {
+	// Score goes from 0 (guessed instantly) to 4 (out of reach).
+	Score int `json:"score"`
+	// Entropy is the estimated number of bits, after pattern penalties.
+	Entropy float64 `json:"entropy"`
+	// Warnings name the patterns that lowered the estimate.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+const (
+	WarnCommon     = "common password"
+	WarnDictionary = "common word with predictable additions"
+	WarnRepeat     = "repeated characters"
+	WarnSequence   = "character sequence"
+	WarnKeyboard   = "keyboard pattern"
+	WarnShort      = "shorter than 8 characters"
+)
+
+// scoreBits are the entropy thresholds for scores 1 to 4.
+var scoreBits = [4]float64{28, 36, 60, 80}
+
+// keyboardRows are scanned for runs of adjacent keys.
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm", "azertyuiop", "qwertzuiop"}
+
+// commonPasswords holds the most frequent passwords and base words of
+// public breach lists, lowercased and de-leeted.
+var commonPasswords = map[string]bool{}
+
+func init() {
+	for _, p := range strings.Fields(`
+		password letmein welcome admin administrator login master
+		qwerty azerty iloveyou monkey dragon football baseball soccer
+		sunshine princess shadow superman batman whatever freedom trustno
+		starwars pokemon computer internet secret hello charlie michael
+		jordan jennifer hunter ranger buster thomas tigger robert daniel
+		summer winter spring autumn flower cheese cookie chocolate banana
+		orange purple access changeme default guest root toor test user
+		mustang harley maggie ginger pepper killer matrix ninja samsung
+		google apple linkedin facebook twitter microsoft yankees liverpool
+		arsenal chelsea america canada london paris africa love lovely family
+		friends forever
+	`) {
+		commonPasswords[p] = true
+	}
+}
+
+var leet = strings.NewReplacer("4", "a", "@", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t", "+", "t")
+
+// EstimateStrength scores a password from its character pool and length,
+// discounting repeats, sequences, keyboard runs and common words.
+func EstimateStrength(password string) Strength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Strength{Warnings: []string{WarnShort}}
+	}
+	bitsPerChar := math.Log2(float64(poolSize(runes)))
+
+	if bits, warn, ok := dictionaryBits(password); ok {
+		return score(bits, len(runes), []string{warn})
+	}
+
+	var warnings []string
+	effective := float64(len(runes))
+	lower := []rune(strings.ToLower(password))
+	for i := 0; i < len(lower); {
+		n, warn := patternRun(lower[i:])
+		if n >= 3 {
+			// A run costs about as much as its first character plus a choice
+			// of length and direction.
+			effective -= float64(n) - 1.5
+			warnings = appendOnce(warnings, warn)
+			i += n
+			continue
+		}
+		i++
+	}
+	return score(effective*bitsPerChar, len(runes), warnings)
+}
+
+func score(bits float64, length int, warnings []string) Strength {
+	s := Strength{Entropy: math.Round(bits*10) / 10, Warnings: warnings}
+	for _, threshold := range scoreBits {
+		if bits >= threshold {
+			s.Score++
+		}
+	}
+	if length < 8 {
+		s.Warnings = appendOnce(s.Warnings, WarnShort)
+		s.Score = min(s.Score, 1)
+	}
+	return s
+}
+
+func poolSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	for _, c := range []struct {
+		set  bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if c.set {
+			pool += c.size
+		}
+	}
+	return pool
+}
+
+// dictionaryBits recognises a common password, alone or with up to six
+// leading or trailing digits and symbols ("Summer2024!").
+func dictionaryBits(password string) (float64, string, bool) {
+	lower := strings.ToLower(password)
+	if commonPasswords[leet.Replace(lower)] {
+		return 0, WarnCommon, true
+	}
+	core := strings.TrimFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	added := len([]rune(lower)) - len([]rune(core))
+	if core == "" || added > 6 || !commonPasswords[leet.Replace(core)] {
+		return 0, "", false
+	}
+	// About 10 bits for the word and its capitalisation, plus the
+	// additions, which are mostly years and "!".
+	return 10 + float64(added)*3.3, WarnDictionary, true
+}
+
+// patternRun returns the length of the repeat, sequence or keyboard run
+// starting at s.
+func patternRun(s []rune) (int, string) {
+	if len(s) < 3 {
+		return 0, ""
+	}
+	repeat := 1
+	for repeat < len(s) && s[repeat] == s[0] {
+		repeat++
+	}
+	if repeat >= 3 {
+		return repeat, WarnRepeat
+	}
+	step := s[1] - s[0]
+	if step == 1 || step == -1 {
+		n := 2
+		for n < len(s) && s[n]-s[n-1] == step {
+			n++
+		}
+		if n >= 3 {
+			return n, WarnSequence
+		}
+	}
+	best := 0
+	for _, row := range keyboardRows {
+		for _, r := range []string{row, reverse(row)} {
+			start := strings.IndexRune(r, s[0])
+			if start < 0 {
+				continue
+			}
+			n := 0
+			for n < len(s) && start+n < len(r) && rune(r[start+n]) == s[n] {
+				n++
+			}
+			best = max(best, n)
+		}
+	}
+	if best >= 4 {
+		return best, WarnKeyboard
+	}
+	return 0, ""
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+func appendOnce(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
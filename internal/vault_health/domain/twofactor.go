@@ -0,0 +1,90 @@
+package vault_health_domain
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// TwoFactorDirectory is the set of domains known to offer TOTP codes.
+// Subdomains are covered by their parent.
+type TwoFactorDirectory map[string]bool
+
+// DefaultTwoFactorSites lists widely used services with TOTP support.
+var DefaultTwoFactorSites = NewTwoFactorDirectory(
+	"google.com", "microsoft.com", "live.com", "apple.com", "amazon.com", "aws.amazon.com",
+	"github.com", "gitlab.com", "bitbucket.org", "atlassian.com", "npmjs.com", "pypi.org",
+	"docker.com", "cloudflare.com", "digitalocean.com", "heroku.com", "vercel.com",
+	"netlify.com", "sentry.io", "hetzner.com", "ovh.com", "namecheap.com", "godaddy.com",
+	"facebook.com", "instagram.com", "twitter.com", "x.com", "linkedin.com", "reddit.com",
+	"discord.com", "slack.com", "zoom.us", "dropbox.com", "notion.so", "figma.com",
+	"proton.me", "protonmail.com", "fastmail.com", "yahoo.com", "paypal.com", "stripe.com",
+	"shopify.com", "salesforce.com", "okta.com", "coinbase.com", "kraken.com", "binance.com",
+	"twitch.tv", "wordpress.com", "mailchimp.com",
+)
+
+func NewTwoFactorDirectory(domains ...string) TwoFactorDirectory {
+	d := TwoFactorDirectory{}
+	for _, domain := range domains {
+		d[strings.ToLower(domain)] = true
+	}
+	return d
+}
+
+// Lookup returns the listed domain covering host.
+func (d TwoFactorDirectory) Lookup(host string) (string, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for host != "" {
+		if d[host] {
+			return host, true
+		}
+		_, parent, ok := strings.Cut(host, ".")
+		if !ok {
+			break
+		}
+		host = parent
+	}
+	return "", false
+}
+
+// websiteHost extracts the host of a login's website; entries often omit
+// the scheme.
+func websiteHost(website string) (scheme, host string, ok bool) {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return "", "", false
+	}
+	if !strings.Contains(website, "://") {
+		website = "https://" + website
+	}
+	u, err := url.Parse(website)
+	if err != nil || u.Hostname() == "" {
+		return "", "", false
+	}
+	return strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), true
+}
+
+// isLocalHost tells hosts for which plain http is expected.
+func isLocalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
+
+// otpCovers tells whether an OTP entry protects the login: it is linked to
+// it, or its issuer names the site ("GitHub" for github.com).
+func otpCovers(otp *vaults_domain.OTPEntry, login *vaults_domain.LoginEntry, domain string) bool {
+	if otp.LinkedLoginID != "" {
+		return otp.LinkedLoginID == login.ID
+	}
+	issuer := strings.ToLower(strings.ReplaceAll(otp.Issuer, " ", ""))
+	if issuer == "" {
+		return false
+	}
+	label, _, _ := strings.Cut(domain, ".")
+	return issuer == domain || issuer == label || strings.HasSuffix(issuer, "."+domain)
+}
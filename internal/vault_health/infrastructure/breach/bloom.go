@@ -0,0 +1,174 @@
+package vault_health_breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vault_health_domain "vault-app/internal/vault_health/domain"
+)
+
+// magic starts a corpus file. It is followed by the number of hash
+// functions (uint32), the number of bits (uint64), both big-endian, and
+// the bit array.
+const magic = "DVBLOOM1"
+
+const (
+	maxHashes = 64
+	// maxBits bounds what a corpus file may ask us to allocate (2 GiB).
+	maxBits = 1 << 34
+)
+
+// DefaultCorpusPath is where the app looks for a corpus when none is given.
+func DefaultCorpusPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".vaultcore", "breach-corpus.bloom"), nil
+}
+
+// BloomFilter is an offline breach corpus. Keys are SHA-1 digests of the
+// passwords, the format of the Have I Been Pwned hash lists, so a corpus
+// can be built from those without handling plaintext.
+type BloomFilter struct {
+	k    uint32
+	m    uint64
+	bits []byte
+}
+
+var _ vault_health_domain.BreachCorpus = (*BloomFilter)(nil)
+
+// NewBloomFilter sizes a filter for n passwords at the given false
+// positive rate.
+func NewBloomFilter(n uint64, falsePositiveRate float64) (*BloomFilter, error) {
+	if n == 0 || falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("%w: need a positive size and a rate between 0 and 1", vault_health_domain.ErrInvalidCorpus)
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 7) &^ 7
+	if m > maxBits {
+		return nil, fmt.Errorf("%w: %d bits is too large", vault_health_domain.ErrInvalidCorpus, m)
+	}
+	k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	k = min(max(k, 1), maxHashes)
+	return &BloomFilter{k: k, m: m, bits: make([]byte, m/8)}, nil
+}
+
+// Add records a plaintext password.
+func (f *BloomFilter) Add(password string) {
+	sum := sha1.Sum([]byte(password))
+	f.addDigest(sum[:])
+}
+
+// AddSHA1 records a password given as a hex SHA-1 digest.
+func (f *BloomFilter) AddSHA1(hexDigest string) error {
+	digest, err := hex.DecodeString(strings.TrimSpace(hexDigest))
+	if err != nil || len(digest) != sha1.Size {
+		return fmt.Errorf("%w: %q is not a SHA-1 digest", vault_health_domain.ErrInvalidCorpus, hexDigest)
+	}
+	f.addDigest(digest)
+	return nil
+}
+
+// AddHashList reads "HASH" or "HASH:COUNT" lines and returns how many were
+// added.
+func (f *BloomFilter) AddHashList(r io.Reader) (int, error) {
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		digest, _, _ := strings.Cut(line, ":")
+		if err := f.AddSHA1(digest); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, sc.Err()
+}
+
+func (f *BloomFilter) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	h1, h2 := split(sum[:])
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BloomFilter) addDigest(digest []byte) {
+	h1, h2 := split(digest)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// split derives the two hashes of double hashing from a digest; the second
+// is odd so it never degenerates to a single bit.
+func split(digest []byte) (uint64, uint64) {
+	return binary.BigEndian.Uint64(digest[0:8]), binary.BigEndian.Uint64(digest[8:16]) | 1
+}
+
+// SizeBytes is the size of the bit array.
+func (f *BloomFilter) SizeBytes() int64 { return int64(len(f.bits)) }
+
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, len(magic)+12)
+	copy(header, magic)
+	binary.BigEndian.PutUint32(header[len(magic):], f.k)
+	binary.BigEndian.PutUint64(header[len(magic)+4:], f.m)
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(f.bits)
+	return int64(n + m), err
+}
+
+// ReadBloomFilter reads a corpus written by WriteTo.
+func ReadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	header := make([]byte, len(magic)+12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_health_domain.ErrInvalidCorpus, err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: not a breach corpus file", vault_health_domain.ErrInvalidCorpus)
+	}
+	k := binary.BigEndian.Uint32(header[len(magic):])
+	m := binary.BigEndian.Uint64(header[len(magic)+4:])
+	if k == 0 || k > maxHashes || m == 0 || m%8 != 0 || m > maxBits {
+		return nil, fmt.Errorf("%w: bad parameters k=%d m=%d", vault_health_domain.ErrInvalidCorpus, k, m)
+	}
+	// The array grows with the data actually read, so a forged header
+	// cannot make us allocate what the file does not hold.
+	var bits bytes.Buffer
+	if _, err := io.CopyN(&bits, r, int64(m/8)); err != nil {
+		return nil, fmt.Errorf("%w: truncated bit array", vault_health_domain.ErrInvalidCorpus)
+	}
+	return &BloomFilter{k: k, m: m, bits: bits.Bytes()}, nil
+}
+
+// LoadBloomFilter reads a corpus file.
+func LoadBloomFilter(path string) (*BloomFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadBloomFilter(bufio.NewReaderSize(file, 1<<20))
+}
@@ -0,0 +1,125 @@
+package vault_health_tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_health_domain "vault-app/internal/vault_health/domain"
+	vault_health_breach "vault-app/internal/vault_health/infrastructure/breach"
+	vault_health_ui "vault-app/internal/vault_health/ui"
+)
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestBloomFilter_RoundTripAndFalsePositiveRate(t *testing.T) {
+	f, err := vault_health_breach.NewBloomFilter(10_000, 0.001)
+	require.NoError(t, err)
+
+	// Hash lists come in the Have I Been Pwned "HASH:COUNT" format.
+	var list strings.Builder
+	for i := 0; i < 5_000; i++ {
+		fmt.Fprintf(&list, "%s:%d\n", sha1Hex(fmt.Sprintf("breached-%d", i)), i+1)
+	}
+	n, err := f.AddHashList(strings.NewReader(list.String()))
+	require.NoError(t, err)
+	require.Equal(t, 5_000, n)
+	f.Add("hunter2")
+
+	var buf bytes.Buffer
+	_, err = f.WriteTo(&buf)
+	require.NoError(t, err)
+	loaded, err := vault_health_breach.ReadBloomFilter(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	for i := 0; i < 5_000; i++ {
+		require.True(t, loaded.Contains(fmt.Sprintf("breached-%d", i)))
+	}
+	require.True(t, loaded.Contains("hunter2"))
+	falsePositives := 0
+	for i := 0; i < 10_000; i++ {
+		if loaded.Contains(fmt.Sprintf("clean-%d", i)) {
+			falsePositives++
+		}
+	}
+	require.Less(t, falsePositives, 50)
+
+	_, err = f.AddHashList(strings.NewReader("not-a-hash\n"))
+	require.ErrorIs(t, err, vault_health_domain.ErrInvalidCorpus)
+}
+
+func TestBloomFilter_RejectsBadFiles(t *testing.T) {
+	f, err := vault_health_breach.NewBloomFilter(100, 0.01)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = f.WriteTo(&buf)
+	require.NoError(t, err)
+	raw := buf.Bytes()
+
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("NOTBLOOM"), raw[8:]...),
+		"truncated": raw[:len(raw)-1],
+		// A header asking for 2 GiB backed by a few bytes must not allocate it.
+		"forged": append(append([]byte{}, raw[:12]...), 0, 0, 0, 4, 0, 0, 0, 0, 1, 2, 3),
+	} {
+		_, err := vault_health_breach.ReadBloomFilter(bytes.NewReader(data))
+		require.ErrorIs(t, err, vault_health_domain.ErrInvalidCorpus, name)
+	}
+	_, err = vault_health_breach.NewBloomFilter(0, 0.01)
+	require.ErrorIs(t, err, vault_health_domain.ErrInvalidCorpus)
+}
+
+type vaultMock struct{ vp vaults_domain.VaultPayload }
+
+func (m *vaultMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) { return &m.vp, nil }
+
+func TestHandler_LoadsCorpusFromFile(t *testing.T) {
+	f, err := vault_health_breach.NewBloomFilter(100, 0.001)
+	require.NoError(t, err)
+	require.NoError(t, f.AddSHA1(sha1Hex("Xk8!mQ2#vL9$pR4&")))
+	path := filepath.Join(t.TempDir(), "breach-corpus.bloom")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = f.WriteTo(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	vault := &vaultMock{}
+	e := vaults_domain.LoginEntry{Password: "Xk8!mQ2#vL9$pR4&"}
+	e.ID = "leaked"
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{e}
+	h := vault_health_ui.NewVaultHealthHandler(vault, nil)
+	gate := vault_health_domain.FeatureGateFunc(func(string) (bool, error) { return true, nil })
+
+	report, err := h.Report(context.Background(), "user-1", gate)
+	require.NoError(t, err)
+	require.False(t, report.BreachCheck)
+	require.Empty(t, report.Findings)
+
+	_, err = h.LoadBreachCorpus(filepath.Join(t.TempDir(), "missing.bloom"))
+	require.Error(t, err)
+	require.False(t, h.CorpusStatus().Loaded)
+
+	status, err := h.LoadBreachCorpus(path)
+	require.NoError(t, err)
+	require.True(t, status.Loaded)
+	require.Equal(t, path, status.Path)
+	report, err = h.Report(context.Background(), "user-1", gate)
+	require.NoError(t, err)
+	require.True(t, report.BreachCheck)
+	require.Len(t, report.Findings, 1)
+	require.Equal(t, vault_health_domain.RiskBreachedPassword, report.Findings[0].Kind)
+}
@@ -0,0 +1,73 @@
+package vault_health_ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	vault_health_usecases "vault-app/internal/vault_health/application/usecases"
+	vault_health_domain "vault-app/internal/vault_health/domain"
+	vault_health_breach "vault-app/internal/vault_health/infrastructure/breach"
+)
+
+// CorpusStatus describes the loaded breach corpus.
+type CorpusStatus struct {
+	Loaded    bool   `json:"loaded"`
+	Path      string `json:"path,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+}
+
+// VaultHealthHandler builds health reports for unlocked vaults and holds
+// the offline breach corpus they are checked against.
+type VaultHealthHandler struct {
+	vault    vault_health_usecases.VaultSessions
+	notifier vault_health_usecases.Notifier
+
+	mu     sync.Mutex
+	corpus *vault_health_breach.BloomFilter
+	path   string
+}
+
+func NewVaultHealthHandler(vault vault_health_usecases.VaultSessions, notifier vault_health_usecases.Notifier) *VaultHealthHandler {
+	return &VaultHealthHandler{vault: vault, notifier: notifier}
+}
+
+// Report analyses userID's vault and publishes the summary.
+func (h *VaultHealthHandler) Report(ctx context.Context, userID string, gate vault_health_domain.FeatureGate) (*vault_health_domain.Report, error) {
+	uc := vault_health_usecases.NewHealthReportUsecase(h.vault, gate, h.notifier)
+	h.mu.Lock()
+	if h.corpus != nil {
+		uc.Breaches = h.corpus
+	}
+	h.mu.Unlock()
+	return uc.Execute(ctx, userID)
+}
+
+// LoadBreachCorpus replaces the corpus with the file at path, the default
+// location when empty. The previous corpus stays on failure.
+func (h *VaultHealthHandler) LoadBreachCorpus(path string) (CorpusStatus, error) {
+	if path == "" {
+		p, err := vault_health_breach.DefaultCorpusPath()
+		if err != nil {
+			return CorpusStatus{}, err
+		}
+		path = p
+	}
+	corpus, err := vault_health_breach.LoadBloomFilter(path)
+	if err != nil {
+		return CorpusStatus{}, fmt.Errorf("load breach corpus: %w", err)
+	}
+	h.mu.Lock()
+	h.corpus, h.path = corpus, path
+	h.mu.Unlock()
+	return h.CorpusStatus(), nil
+}
+
+func (h *VaultHealthHandler) CorpusStatus() CorpusStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.corpus == nil {
+		return CorpusStatus{}
+	}
+	return CorpusStatus{Loaded: true, Path: h.path, SizeBytes: h.corpus.SizeBytes()}
+}
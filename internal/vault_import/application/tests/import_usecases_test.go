@@ -0,0 +1,244 @@
+package vault_import_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_usecases "vault-app/internal/vault_import/application/usecases"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+	vault_import_persistence "vault-app/internal/vault_import/infrastructure/persistence"
+)
+
+// vaultSessionMock stands in for the vault handler: entries are appended to
+// an in-memory payload the way the entry handlers do.
+type vaultSessionMock struct {
+	vp      vaults_domain.VaultPayload
+	nextID  int
+	dirty   bool
+	addErrs map[string]error
+}
+
+func (m *vaultSessionMock) GetVaultSession(string) (*vaults_domain.VaultPayload, error) {
+	return &m.vp, nil
+}
+
+func (m *vaultSessionMock) CreateFolder(_ string, name string) (*vaults_domain.VaultPayload, error) {
+	m.nextID++
+	m.vp.Folders = append(m.vp.Folders, vaults_domain.Folder{ID: fmt.Sprintf("folder-%d", m.nextID), Name: name})
+	return &m.vp, nil
+}
+
+func (m *vaultSessionMock) AddEntryFor(_ string, entry any) (*vaults_domain.VaultEntry, error) {
+	ve := entry.(vaults_domain.VaultEntry)
+	if err := m.addErrs[ve.GetName()]; err != nil {
+		return nil, err
+	}
+	m.nextID++
+	id := fmt.Sprintf("entry-%d", m.nextID)
+	switch e := entry.(type) {
+	case *vaults_domain.LoginEntry:
+		e.ID = id
+		m.vp.Entries.Login = append(m.vp.Entries.Login, *e)
+	case *vaults_domain.NoteEntry:
+		e.ID = id
+		m.vp.Entries.Note = append(m.vp.Entries.Note, *e)
+	default:
+		return nil, fmt.Errorf("unexpected entry %T", entry)
+	}
+	return &ve, nil
+}
+
+func (m *vaultSessionMock) MarkDirty(string) { m.dirty = true }
+
+// parserStub returns a fresh batch on every call.
+type parserStub func() *vault_import_domain.Batch
+
+func (p parserStub) Parse([]byte, string) (*vault_import_domain.Batch, error) { return p(), nil }
+
+func login(name, user, pass, site string) *vaults_domain.LoginEntry {
+	e := &vaults_domain.LoginEntry{UserName: user, Password: pass, Website: site}
+	e.EntryName, e.Type = name, vaults_domain.EntryLogin
+	return e
+}
+
+func sampleBatch() *vault_import_domain.Batch {
+	note := &vaults_domain.NoteEntry{}
+	note.EntryName, note.Type = "Runbook", vaults_domain.EntryNote
+	return &vault_import_domain.Batch{
+		Format: vault_import_domain.FormatBitwardenJSON,
+		Records: []vault_import_domain.Record{
+			{SourceID: "a", Folder: "Personal", Entry: login("GitHub", "alice", "pw1", "https://github.com/login")},
+			{SourceID: "b", Folder: "Work/Infra", Entry: login("Grafana", "ops", "pw2", "https://grafana.example.com")},
+			{SourceID: "c", Folder: "Work/Infra", Entry: login("Grafana again", "ops", "pw2", "grafana.example.com")},
+			{SourceID: "d", Entry: login("GitLab", "alice", "new-pw", "https://gitlab.com"),
+				Warnings: []string{"parser warning"}},
+			{SourceID: "e", Folder: "Work/Infra", Entry: note,
+				Attachments: []vault_import_domain.Attachment{{Name: "runbook.md", Data: []byte("# Runbook")}}},
+		},
+	}
+}
+
+func newImportFixture() (*vaultSessionMock, *vault_import_usecases.PreviewImportUsecase, *vault_import_usecases.CommitImportUsecase) {
+	existing := login("GitHub", "Alice", "pw1", "https://www.github.com")
+	existing.ID = "existing-github"
+	gitlab := login("GitLab", "alice", "old-pw", "https://gitlab.com")
+	gitlab.ID = "existing-gitlab"
+
+	vault := &vaultSessionMock{}
+	vault.vp.Folders = []vaults_domain.Folder{{ID: "folder-personal", Name: "personal"}}
+	vault.vp.Entries.Login = []vaults_domain.LoginEntry{*existing, *gitlab}
+
+	store := vault_import_persistence.NewMemoryPreviewStore()
+	parsers := map[vault_import_domain.Format]vault_import_domain.Parser{
+		vault_import_domain.FormatBitwardenJSON: parserStub(sampleBatch),
+	}
+	return vault,
+		vault_import_usecases.NewPreviewImportUsecase(parsers, vault, store),
+		vault_import_usecases.NewCommitImportUsecase(vault, store)
+}
+
+func TestPreviewImport_IsADryRun(t *testing.T) {
+	ctx := context.Background()
+	vault, preview, _ := newImportFixture()
+
+	res, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{
+		UserID: "user-1",
+		Format: vault_import_domain.FormatBitwardenJSON,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, res.Records, 5)
+	require.Equal(t, 2, res.Duplicates)
+	require.Equal(t, "existing-github", res.Records[0].DuplicateOf, "host and username match case-insensitively")
+	require.Equal(t, "b", res.Records[2].DuplicateOf, "repeat within the export")
+	require.Equal(t, "existing-gitlab", res.Records[3].ConflictsWith)
+	require.Empty(t, res.Records[3].DuplicateOf)
+	require.Equal(t, []string{"parser warning"}, res.Records[3].Warnings)
+	require.Equal(t, []string{"runbook.md"}, res.Records[4].Attachments)
+	require.Equal(t, []string{"Personal", "Work/Infra"}, res.Folders)
+	require.Equal(t, []string{"Work/Infra"}, res.NewFolders)
+
+	require.Len(t, vault.vp.Entries.Login, 2, "preview must not write entries")
+	require.Len(t, vault.vp.Folders, 1, "preview must not create folders")
+	require.False(t, vault.dirty)
+}
+
+func TestPreviewImport_Errors(t *testing.T) {
+	ctx := context.Background()
+	_, preview, _ := newImportFixture()
+
+	_, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: "csv"})
+	require.True(t, errors.Is(err, vault_import_domain.ErrUnsupportedFormat))
+
+	_, err = preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{Format: vault_import_domain.FormatBitwardenJSON})
+	require.True(t, errors.Is(err, vault_import_domain.ErrUserIDRequired))
+
+	preview.Parsers[vault_import_domain.FormatLastPass] = parserStub(func() *vault_import_domain.Batch {
+		return &vault_import_domain.Batch{Format: vault_import_domain.FormatLastPass}
+	})
+	_, err = preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: vault_import_domain.FormatLastPass})
+	require.True(t, errors.Is(err, vault_import_domain.ErrEmptyImport))
+}
+
+func TestCommitImport_SkipsDuplicatesAndCreatesFolders(t *testing.T) {
+	ctx := context.Background()
+	vault, preview, commit := newImportFixture()
+
+	res, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: vault_import_domain.FormatBitwardenJSON})
+	require.NoError(t, err)
+
+	out, err := commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID, SkipDuplicates: true})
+	require.NoError(t, err)
+
+	require.Len(t, out.Imported, 3)
+	require.Len(t, out.Skipped, 2)
+	require.Equal(t, []string{"Work/Infra"}, out.FoldersCreated, "existing folders are matched case-insensitively")
+	require.True(t, vault.dirty)
+
+	byName := map[string]vaults_domain.LoginEntry{}
+	for _, l := range vault.vp.Entries.Login {
+		byName[l.EntryName] = l
+	}
+	require.Len(t, vault.vp.Entries.Login, 4)
+	require.Empty(t, byName["GitLab"].FolderID)
+	require.Equal(t, vault.vp.Folders[1].ID, byName["Grafana"].FolderID)
+	require.Equal(t, vault.vp.Folders[1].ID, vault.vp.Entries.Note[0].FolderID)
+
+	runbook := out.Imported[2]
+	require.Equal(t, vault.vp.Entries.Note[0].ID, runbook.EntryID)
+	require.Len(t, runbook.Attachments, 1, "attachments are handed back for upload")
+
+	require.NoError(t, commit.RecordAttachment(ctx, "user-1", res.ID, runbook.SourceID))
+	require.NoError(t, commit.Finish(ctx, "user-1", res.ID))
+	_, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.True(t, errors.Is(err, vault_import_domain.ErrPreviewNotFound), "a preview is committed once")
+}
+
+func TestCommitImport_ResumesAfterAFailure(t *testing.T) {
+	ctx := context.Background()
+	vault, preview, commit := newImportFixture()
+
+	res, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: vault_import_domain.FormatBitwardenJSON})
+	require.NoError(t, err)
+
+	vault.addErrs = map[string]error{"GitLab": errors.New("disk full")}
+	_, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.Error(t, err)
+	require.Len(t, vault.vp.Entries.Login, 5, "records before the failure are written")
+	require.True(t, vault.dirty)
+
+	vault.addErrs = nil
+	out, err := commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.NoError(t, err)
+	require.Len(t, out.Imported, 5)
+	require.Len(t, vault.vp.Entries.Login, 6, "committed records are not imported twice")
+	require.Len(t, vault.vp.Entries.Note, 1)
+
+	runbook := out.Imported[4]
+	require.Len(t, runbook.Attachments, 1)
+	require.NoError(t, commit.RecordAttachment(ctx, "user-1", res.ID, runbook.SourceID))
+
+	out, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.NoError(t, err, "the preview is kept until the caller finishes it")
+	require.Empty(t, out.Imported[4].Attachments, "uploaded attachments are not handed back again")
+	require.Len(t, vault.vp.Entries.Login, 6)
+
+	_, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.True(t, errors.Is(err, vault_import_domain.ErrPreviewNotFound), "nothing outstanding drops the preview")
+}
+
+func TestCommitImport_KeepsDuplicatesWhenAsked(t *testing.T) {
+	ctx := context.Background()
+	vault, preview, commit := newImportFixture()
+
+	res, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: vault_import_domain.FormatBitwardenJSON})
+	require.NoError(t, err)
+
+	out, err := commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.NoError(t, err)
+	require.Len(t, out.Imported, 5)
+	require.Empty(t, out.Skipped)
+	require.Len(t, vault.vp.Entries.Login, 6)
+}
+
+func TestCommitImport_PreviewIsScopedToUserAndExpires(t *testing.T) {
+	ctx := context.Background()
+	_, preview, commit := newImportFixture()
+
+	res, err := preview.Execute(ctx, vault_import_usecases.PreviewImportRequest{UserID: "user-1", Format: vault_import_domain.FormatBitwardenJSON})
+	require.NoError(t, err)
+
+	_, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-2", PreviewID: res.ID})
+	require.True(t, errors.Is(err, vault_import_domain.ErrPreviewNotFound))
+
+	store := commit.Store.(*vault_import_persistence.MemoryPreviewStore)
+	store.Now = func() time.Time { return time.Now().Add(vault_import_usecases.DefaultPreviewTTL + time.Minute) }
+	_, err = commit.Execute(ctx, vault_import_usecases.CommitImportRequest{UserID: "user-1", PreviewID: res.ID})
+	require.True(t, errors.Is(err, vault_import_domain.ErrPreviewNotFound))
+}
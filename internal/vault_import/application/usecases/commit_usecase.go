@@ -0,0 +1,177 @@
+package vault_import_usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+type CommitImportRequest struct {
+	UserID         string
+	PreviewID      string
+	SkipDuplicates bool
+}
+
+type ImportedEntry struct {
+	SourceID string `json:"source_id"`
+	EntryID  string `json:"entry_id"`
+	Type     string `json:"type"`
+	// Attachments still have to be uploaded against EntryID; the vault
+	// attachment flow needs the vault password, which imports never see.
+	Attachments []vault_import_domain.Attachment `json:"-"`
+}
+
+type SkippedRecord struct {
+	SourceID    string `json:"source_id"`
+	DuplicateOf string `json:"duplicate_of"`
+}
+
+type CommitImportResult struct {
+	Imported       []ImportedEntry `json:"imported"`
+	Skipped        []SkippedRecord `json:"skipped"`
+	FoldersCreated []string        `json:"folders_created"`
+}
+
+// CommitImportUsecase writes a previewed batch into the vault session:
+// missing folders are created, then each record is added through the entry
+// handlers. Vault folders are flat, so nested source paths keep their
+// "Parent/Child" form as the folder name.
+//
+// Progress is saved on the preview after every record, and the preview is
+// only dropped once nothing is left to do: when records still carry
+// attachments, the caller uploads them, reports each one through
+// RecordAttachment and then calls Finish.
+type CommitImportUsecase struct {
+	Vault VaultSession
+	Store vault_import_domain.PreviewStore
+}
+
+func NewCommitImportUsecase(vault VaultSession, store vault_import_domain.PreviewStore) *CommitImportUsecase {
+	return &CommitImportUsecase{Vault: vault, Store: store}
+}
+
+func (uc *CommitImportUsecase) Execute(ctx context.Context, req CommitImportRequest) (*CommitImportResult, error) {
+	if uc.Vault == nil {
+		return nil, vault_import_domain.ErrVaultRequired
+	}
+	if uc.Store == nil {
+		return nil, vault_import_domain.ErrPreviewStoreRequired
+	}
+	if req.UserID == "" {
+		return nil, vault_import_domain.ErrUserIDRequired
+	}
+
+	pending, err := uc.Store.GetPending(ctx, req.UserID, req.PreviewID)
+	if err != nil {
+		return nil, err
+	}
+	vp, err := uc.Vault.GetVaultSession(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// The vault may have changed since the preview.
+	records := pending.Batch.Records
+	vault_import_domain.MarkDuplicates(records, vp.Entries.All())
+
+	result := &CommitImportResult{Imported: []ImportedEntry{}, Skipped: []SkippedRecord{}, FoldersCreated: []string{}}
+
+	folders := map[string]string{}
+	for _, f := range vp.Folders {
+		folders[strings.ToLower(f.Name)] = f.ID
+	}
+	outstanding := false
+	for i := range records {
+		r := &records[i]
+		if entryID, ok := pending.Committed[r.SourceID]; ok {
+			// Written by an earlier attempt; only its attachments may remain.
+			imported := ImportedEntry{SourceID: r.SourceID, EntryID: entryID, Type: r.Entry.GetTypeName()}
+			if done := pending.Uploaded[r.SourceID]; done < len(r.Attachments) {
+				imported.Attachments = r.Attachments[done:]
+				outstanding = true
+			}
+			result.Imported = append(result.Imported, imported)
+			continue
+		}
+		if req.SkipDuplicates && r.DuplicateOf != "" {
+			result.Skipped = append(result.Skipped, SkippedRecord{SourceID: r.SourceID, DuplicateOf: r.DuplicateOf})
+			continue
+		}
+
+		folderID := ""
+		if r.Folder != "" {
+			key := strings.ToLower(r.Folder)
+			if _, ok := folders[key]; !ok {
+				updated, err := uc.Vault.CreateFolder(req.UserID, r.Folder)
+				if err != nil {
+					return result, fmt.Errorf("create folder %q: %w", r.Folder, err)
+				}
+				folders[key] = folderIDByName(updated.Folders, r.Folder)
+				result.FoldersCreated = append(result.FoldersCreated, r.Folder)
+			}
+			folderID = folders[key]
+		}
+
+		r.Entry.GetBase().FolderID = folderID
+		if _, err := uc.Vault.AddEntryFor(req.UserID, r.Entry); err != nil {
+			return result, fmt.Errorf("import record %s: %w", r.SourceID, err)
+		}
+		uc.Vault.MarkDirty(req.UserID)
+		pending.MarkCommitted(r.SourceID, r.Entry.GetId())
+		if err := uc.Store.SavePending(ctx, pending); err != nil {
+			return result, fmt.Errorf("record import progress: %w", err)
+		}
+		result.Imported = append(result.Imported, ImportedEntry{
+			SourceID:    r.SourceID,
+			EntryID:     r.Entry.GetId(),
+			Type:        r.Entry.GetTypeName(),
+			Attachments: r.Attachments,
+		})
+		outstanding = outstanding || len(r.Attachments) > 0
+	}
+
+	if outstanding {
+		return result, nil
+	}
+	if err := uc.Store.DeletePending(ctx, req.UserID, req.PreviewID); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// RecordAttachment notes that the next attachment of sourceID was stored, so
+// a retried commit does not upload it again.
+func (uc *CommitImportUsecase) RecordAttachment(ctx context.Context, userID, previewID, sourceID string) error {
+	if uc.Store == nil {
+		return vault_import_domain.ErrPreviewStoreRequired
+	}
+	pending, err := uc.Store.GetPending(ctx, userID, previewID)
+	if err != nil {
+		return err
+	}
+	if _, ok := pending.Committed[sourceID]; !ok {
+		return fmt.Errorf("record %s has not been imported", sourceID)
+	}
+	pending.MarkAttachmentUploaded(sourceID)
+	return uc.Store.SavePending(ctx, pending)
+}
+
+// Finish drops the preview once every record and attachment is stored.
+func (uc *CommitImportUsecase) Finish(ctx context.Context, userID, previewID string) error {
+	if uc.Store == nil {
+		return vault_import_domain.ErrPreviewStoreRequired
+	}
+	return uc.Store.DeletePending(ctx, userID, previewID)
+}
+
+// folderIDByName returns the most recently appended folder called name.
+func folderIDByName(folders []vaults_domain.Folder, name string) string {
+	for i := len(folders) - 1; i >= 0; i-- {
+		if folders[i].Name == name {
+			return folders[i].ID
+		}
+	}
+	return ""
+}
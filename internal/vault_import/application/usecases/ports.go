@@ -0,0 +1,15 @@
+package vault_import_usecases
+
+import (
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// VaultSession is the part of the vault handler imports read from and write
+// to. Entries go through the regular entry handlers so they are committed by
+// the next SyncVault like any entry created in the UI.
+type VaultSession interface {
+	GetVaultSession(userID string) (*vaults_domain.VaultPayload, error)
+	CreateFolder(userID string, name string) (*vaults_domain.VaultPayload, error)
+	AddEntryFor(userID string, entry any) (*vaults_domain.VaultEntry, error)
+	MarkDirty(userID string)
+}
@@ -0,0 +1,97 @@
+package vault_import_usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+const DefaultPreviewTTL = 30 * time.Minute
+
+type PreviewImportRequest struct {
+	UserID   string
+	Format   vault_import_domain.Format
+	Data     []byte
+	Password string
+}
+
+// PreviewImportUsecase parses an export and reports what committing it would
+// do, without touching the vault. The parsed batch is kept for CommitImport.
+type PreviewImportUsecase struct {
+	Parsers map[vault_import_domain.Format]vault_import_domain.Parser
+	Vault   VaultSession
+	Store   vault_import_domain.PreviewStore
+	TTL     time.Duration
+	Now     func() time.Time
+}
+
+func NewPreviewImportUsecase(
+	parsers map[vault_import_domain.Format]vault_import_domain.Parser,
+	vault VaultSession,
+	store vault_import_domain.PreviewStore,
+) *PreviewImportUsecase {
+	return &PreviewImportUsecase{
+		Parsers: parsers,
+		Vault:   vault,
+		Store:   store,
+		TTL:     DefaultPreviewTTL,
+		Now:     time.Now,
+	}
+}
+
+func (uc *PreviewImportUsecase) Execute(ctx context.Context, req PreviewImportRequest) (*vault_import_domain.Preview, error) {
+	if err := uc.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if req.UserID == "" {
+		return nil, vault_import_domain.ErrUserIDRequired
+	}
+	parser, ok := uc.Parsers[req.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", vault_import_domain.ErrUnsupportedFormat, req.Format)
+	}
+
+	batch, err := parser.Parse(req.Data, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch.Records) == 0 {
+		return nil, vault_import_domain.ErrEmptyImport
+	}
+
+	vp, err := uc.Vault.GetVaultSession(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	vault_import_domain.MarkDuplicates(batch.Records, vp.Entries.All())
+
+	now := uc.Now()
+	pending := &vault_import_domain.PendingImport{
+		ID:        uuid.NewString(),
+		UserID:    req.UserID,
+		Batch:     batch,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.TTL),
+	}
+	if err := uc.Store.SavePending(ctx, pending); err != nil {
+		return nil, err
+	}
+	return vault_import_domain.NewPreview(pending, vp.Folders), nil
+}
+
+func (uc *PreviewImportUsecase) ValidateDependencies() error {
+	if len(uc.Parsers) == 0 {
+		return vault_import_domain.ErrParsersRequired
+	}
+	if uc.Vault == nil {
+		return vault_import_domain.ErrVaultRequired
+	}
+	if uc.Store == nil {
+		return vault_import_domain.ErrPreviewStoreRequired
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package vault_import_domain
+
+import (
+	"net/url"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+// fingerprint splits an entry into what identifies it (the account, card or
+// key) and the secret it holds. Records matching on both are duplicates;
+// matching on identity alone is a conflict worth a warning.
+type fingerprint struct {
+	identity string
+	secret   string
+}
+
+func fingerprintOf(entry vaults_domain.VaultEntry) (fingerprint, bool) {
+	norm := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+	switch e := entry.(type) {
+	case *vaults_domain.LoginEntry:
+		id := norm(e.UserName) + "@" + hostOf(e.Website)
+		if e.UserName == "" && hostOf(e.Website) == "" {
+			id = norm(e.EntryName)
+		}
+		return fingerprint{"login|" + id, e.Password}, true
+	case *vaults_domain.CardEntry:
+		number := digitsOnly(e.Number)
+		if number == "" {
+			return fingerprint{"card|" + norm(e.EntryName), e.CVC}, true
+		}
+		return fingerprint{"card|" + number, e.Expiration + "|" + e.CVC}, true
+	case *vaults_domain.IdentityEntry:
+		return fingerprint{"identity|" + norm(e.FirstName) + "|" + norm(e.LastName) + "|" + norm(e.Mail), norm(e.EntryName)}, true
+	case *vaults_domain.NoteEntry:
+		return fingerprint{"note|" + norm(e.EntryName), strings.TrimSpace(e.AdditionnalNote)}, true
+	case *vaults_domain.SSHKeyEntry:
+		key := strings.TrimSpace(e.PublicKey)
+		if key == "" {
+			key = strings.TrimSpace(e.PrivateKey)
+		}
+		return fingerprint{"sshkey|" + key, strings.TrimSpace(e.PrivateKey)}, true
+	case *vaults_domain.OTPEntry:
+		return fingerprint{"otp|" + norm(e.Issuer) + "|" + norm(e.AccountName), strings.ToUpper(e.Secret)}, true
+	}
+	return fingerprint{}, false
+}
+
+// MarkDuplicates flags records already present in the vault, or repeated
+// earlier in the same export, by setting DuplicateOf, and records that share
+// an account with a vault entry but carry another secret by setting
+// ConflictsWith. Trashed vault entries are ignored so a deleted login can be
+// brought back by re-importing. It is safe to call again after the vault
+// changed.
+func MarkDuplicates(records []Record, existing []vaults_domain.EntryInterface) {
+	type known struct {
+		id     string
+		secret string
+	}
+	vault := map[string]known{}
+	for _, e := range existing {
+		if e.GetBase().Trashed {
+			continue
+		}
+		ve, ok := e.(vaults_domain.VaultEntry)
+		if !ok {
+			continue
+		}
+		if fp, ok := fingerprintOf(ve); ok {
+			vault[fp.identity] = known{id: ve.GetId(), secret: fp.secret}
+		}
+	}
+
+	seen := map[fingerprint]string{}
+	for i := range records {
+		r := &records[i]
+		r.DuplicateOf, r.ConflictsWith = "", ""
+		fp, ok := fingerprintOf(r.Entry)
+		if !ok {
+			continue
+		}
+		if k, ok := vault[fp.identity]; ok {
+			if k.secret == fp.secret {
+				r.DuplicateOf = k.id
+				continue
+			}
+			r.ConflictsWith = k.id
+		}
+		if first, ok := seen[fp]; ok {
+			r.DuplicateOf = first
+			continue
+		}
+		seen[fp] = r.SourceID
+	}
+}
+
+func hostOf(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return strings.ToLower(raw)
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
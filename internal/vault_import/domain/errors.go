@@ -0,0 +1,17 @@
+package vault_import_domain
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat     = errors.New("unsupported import format")
+	ErrMalformedExport       = errors.New("malformed export file")
+	ErrUnsupportedEncryption = errors.New("export uses an unsupported encryption scheme")
+	ErrPasswordRequired      = errors.New("export is encrypted and requires a password")
+	ErrInvalidCredentials    = errors.New("invalid export password or corrupted file")
+	ErrEmptyImport           = errors.New("export contains no importable records")
+	ErrPreviewNotFound       = errors.New("import preview not found or expired")
+	ErrUserIDRequired        = errors.New("user id is required")
+	ErrParsersRequired       = errors.New("no import parsers configured")
+	ErrVaultRequired         = errors.New("vault session is required")
+	ErrPreviewStoreRequired  = errors.New("preview store is required")
+)
@@ -0,0 +1,197 @@
+package vault_import_domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+)
+
+type Format string
+
+const (
+	FormatBitwardenJSON Format = "bitwarden_json"
+	FormatBitwardenCSV  Format = "bitwarden_csv"
+	FormatOnePassword   Format = "1password_1pux"
+	FormatKeePass       Format = "keepass_kdbx"
+	FormatLastPass      Format = "lastpass_csv"
+	// FormatArchive is the native archive written by the vault export.
+	FormatArchive Format = "dvault_archive"
+)
+
+// Entry is what importers produce: a pointer to one of the vault entry
+// types (*LoginEntry, *CardEntry, ...), ready for VaultHandler.AddEntryFor.
+type Entry interface {
+	vaults_domain.VaultEntry
+	vaults_domain.EntryInterface
+}
+
+// Attachment is a file carried by a source record. It is uploaded through
+// the regular attachment flow once the entry it belongs to exists.
+type Attachment struct {
+	Name string `json:"name"`
+	Data []byte `json:"-"`
+}
+
+func (a Attachment) Ext() string {
+	if i := strings.LastIndex(a.Name, "."); i > 0 {
+		return a.Name[i+1:]
+	}
+	return ""
+}
+
+// Record is one source item mapped onto a vault entry.
+type Record struct {
+	SourceID string `json:"source_id"`
+	// Folder is the source folder path, "/"-separated for nested groups.
+	Folder      string       `json:"folder,omitempty"`
+	Entry       Entry        `json:"-"`
+	Attachments []Attachment `json:"-"`
+	Warnings    []string     `json:"warnings,omitempty"`
+	// DuplicateOf is the ID of the vault entry this record duplicates, or
+	// the SourceID of an earlier record in the same export.
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	// ConflictsWith is the ID of a vault entry for the same account holding
+	// a different secret; the record is still imported as a new entry.
+	ConflictsWith string `json:"conflicts_with,omitempty"`
+}
+
+func (r *Record) Warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Batch is the parsed content of one export file.
+type Batch struct {
+	Format   Format
+	Records  []Record
+	Warnings []string
+}
+
+func (b *Batch) Warnf(format string, args ...any) {
+	b.Warnings = append(b.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Folders returns the distinct folder paths referenced by records, in
+// first-seen order.
+func (b *Batch) Folders() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, r := range b.Records {
+		if r.Folder == "" || seen[r.Folder] {
+			continue
+		}
+		seen[r.Folder] = true
+		out = append(out, r.Folder)
+	}
+	return out
+}
+
+// Parser reads one export format.
+type Parser interface {
+	Parse(data []byte, password string) (*Batch, error)
+}
+
+// PendingImport is a parsed batch waiting for the user to confirm it.
+type PendingImport struct {
+	ID        string
+	UserID    string
+	Batch     *Batch
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// Committed maps each record already written to the vault to its entry
+	// ID, and Uploaded counts the attachments stored per record, so a commit
+	// that fails half way resumes instead of importing twice.
+	Committed map[string]string
+	Uploaded  map[string]int
+}
+
+// MarkCommitted records that the record sourceID was written as entryID.
+func (p *PendingImport) MarkCommitted(sourceID, entryID string) {
+	if p.Committed == nil {
+		p.Committed = map[string]string{}
+	}
+	p.Committed[sourceID] = entryID
+}
+
+// MarkAttachmentUploaded records one more stored attachment of sourceID;
+// attachments are uploaded in record order.
+func (p *PendingImport) MarkAttachmentUploaded(sourceID string) {
+	if p.Uploaded == nil {
+		p.Uploaded = map[string]int{}
+	}
+	p.Uploaded[sourceID]++
+}
+
+// Preview is the dry-run report shown before anything is written.
+type Preview struct {
+	ID         string          `json:"id"`
+	Format     Format          `json:"format"`
+	Records    []RecordSummary `json:"records"`
+	Folders    []string        `json:"folders"`
+	NewFolders []string        `json:"new_folders"`
+	Warnings   []string        `json:"warnings,omitempty"`
+	Duplicates int             `json:"duplicates"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+}
+
+type RecordSummary struct {
+	SourceID      string   `json:"source_id"`
+	Type          string   `json:"type"`
+	Name          string   `json:"name"`
+	Folder        string   `json:"folder,omitempty"`
+	Attachments   []string `json:"attachments,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+	DuplicateOf   string   `json:"duplicate_of,omitempty"`
+	ConflictsWith string   `json:"conflicts_with,omitempty"`
+}
+
+func NewPreview(p *PendingImport, existing []vaults_domain.Folder) *Preview {
+	known := map[string]bool{}
+	for _, f := range existing {
+		known[strings.ToLower(f.Name)] = true
+	}
+
+	preview := &Preview{
+		ID:         p.ID,
+		Format:     p.Batch.Format,
+		Records:    make([]RecordSummary, 0, len(p.Batch.Records)),
+		Folders:    p.Batch.Folders(),
+		NewFolders: []string{},
+		Warnings:   p.Batch.Warnings,
+		ExpiresAt:  p.ExpiresAt,
+	}
+	for _, f := range preview.Folders {
+		if !known[strings.ToLower(f)] {
+			preview.NewFolders = append(preview.NewFolders, f)
+		}
+	}
+	for _, r := range p.Batch.Records {
+		summary := RecordSummary{
+			SourceID:      r.SourceID,
+			Type:          r.Entry.GetTypeName(),
+			Name:          r.Entry.GetName(),
+			Folder:        r.Folder,
+			Warnings:      r.Warnings,
+			DuplicateOf:   r.DuplicateOf,
+			ConflictsWith: r.ConflictsWith,
+		}
+		for _, a := range r.Attachments {
+			summary.Attachments = append(summary.Attachments, a.Name)
+		}
+		if r.DuplicateOf != "" {
+			preview.Duplicates++
+		}
+		preview.Records = append(preview.Records, summary)
+	}
+	return preview
+}
+
+// PreviewStore holds pending imports between preview and commit. Batches
+// carry plaintext secrets, so implementations must not persist them.
+type PreviewStore interface {
+	SavePending(ctx context.Context, p *PendingImport) error
+	GetPending(ctx context.Context, userID string, id string) (*PendingImport, error)
+	DeletePending(ctx context.Context, userID string, id string) error
+}
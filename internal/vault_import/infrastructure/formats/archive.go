@@ -0,0 +1,49 @@
+package vault_import_formats
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_export_domain "vault-app/internal/vault_export/domain"
+	vault_export_formats "vault-app/internal/vault_export/infrastructure/formats"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// Archive reads the native archive written by the vault export. Entries keep
+// their content and timestamps; what tied them to the source vault (CIDs,
+// attachment references) is dropped, and attachments are uploaded again.
+type Archive struct{}
+
+func (Archive) Parse(data []byte, password string) (*vault_import_domain.Batch, error) {
+	if password == "" {
+		return nil, vault_import_domain.ErrPasswordRequired
+	}
+	s, err := vault_export_formats.OpenArchive(data, password)
+	switch {
+	case errors.Is(err, vault_export_domain.ErrInvalidArchivePassword):
+		return nil, vault_import_domain.ErrInvalidCredentials
+	case errors.Is(err, vault_export_domain.ErrMalformedArchive):
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	case err != nil:
+		return nil, err
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatArchive}
+	for i, it := range s.Items {
+		base := it.Entry.GetBase()
+		rec := vault_import_domain.Record{SourceID: base.ID, Folder: it.Folder, Entry: it.Entry}
+		if rec.SourceID == "" {
+			rec.SourceID = "item-" + strconv.Itoa(i)
+		}
+		base.Type = vaults_domain.EntryType(it.Entry.GetTypeName())
+		base.CID, base.AttachmentCIDs, base.Attachments = "", nil, nil
+		base.IsDraft, base.IsDirty = false, true
+		for _, att := range it.Attachments {
+			rec.Attachments = append(rec.Attachments, vault_import_domain.Attachment{Name: att.Name, Data: att.Data})
+		}
+		batch.Records = append(batch.Records, rec)
+	}
+	return batch, nil
+}
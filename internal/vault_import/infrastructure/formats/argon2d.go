@@ -0,0 +1,196 @@
+package vault_import_formats
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// argon2dKey derives a key with Argon2d (RFC 9106), the default KeePass KDF.
+// golang.org/x/crypto/argon2 only exposes the i and id variants, so this is
+// a compact version of its implementation restricted to data-dependent
+// addressing. version is 0x10 or 0x13 as stored in the KDF parameters.
+func argon2dKey(password, salt, secret, data []byte, time, memory, threads, version uint32, keyLen uint32) []byte {
+	if time < 1 {
+		time = 1
+	}
+	if threads < 1 {
+		threads = 1
+	}
+
+	var h0 [blake2b.Size + 8]byte
+	b2, _ := blake2b.New512(nil)
+	for _, v := range []uint32{threads, keyLen, memory, time, version, 0} {
+		_ = binary.Write(b2, binary.LittleEndian, v)
+	}
+	for _, in := range [][]byte{password, salt, secret, data} {
+		_ = binary.Write(b2, binary.LittleEndian, uint32(len(in)))
+		b2.Write(in)
+	}
+	b2.Sum(h0[:0])
+
+	memory = memory / (argonSyncPoints * threads) * (argonSyncPoints * threads)
+	if memory < 2*argonSyncPoints*threads {
+		memory = 2 * argonSyncPoints * threads
+	}
+	lanes := memory / threads
+	segments := lanes / argonSyncPoints
+
+	B := make([]argonBlock, memory)
+	var buf [1024]byte
+	for lane := uint32(0); lane < threads; lane++ {
+		binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], i)
+			blake2bLong(buf[:], h0[:])
+			for j := range B[lane*lanes+i] {
+				B[lane*lanes+i][j] = binary.LittleEndian.Uint64(buf[j*8:])
+			}
+		}
+	}
+
+	segment := func(n, slice, lane uint32, wg *sync.WaitGroup) {
+		defer wg.Done()
+		index := uint32(0)
+		if n == 0 && slice == 0 {
+			index = 2
+		}
+		offset := lane*lanes + slice*segments + index
+		for ; index < segments; index, offset = index+1, offset+1 {
+			prev := offset - 1
+			if index == 0 && slice == 0 {
+				prev += lanes
+			}
+			ref := argonIndexAlpha(B[prev][0], lanes, segments, threads, n, slice, lane, index)
+			argonCompress(&B[offset], &B[prev], &B[ref], version == 0x13 && n > 0)
+		}
+	}
+	for n := uint32(0); n < time; n++ {
+		for slice := uint32(0); slice < argonSyncPoints; slice++ {
+			var wg sync.WaitGroup
+			for lane := uint32(0); lane < threads; lane++ {
+				wg.Add(1)
+				go segment(n, slice, lane, &wg)
+			}
+			wg.Wait()
+		}
+	}
+
+	final := B[memory-1]
+	for lane := uint32(0); lane < threads-1; lane++ {
+		for i, v := range B[lane*lanes+lanes-1] {
+			final[i] ^= v
+		}
+	}
+	for i, v := range final {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	key := make([]byte, keyLen)
+	blake2bLong(key, buf[:])
+	return key
+}
+
+const argonSyncPoints = 4
+
+type argonBlock [128]uint64
+
+func argonIndexAlpha(rand uint64, lanes, segments, threads, n, slice, lane, index uint32) uint32 {
+	refLane := uint32(rand>>32) % threads
+	if n == 0 && slice == 0 {
+		refLane = lane
+	}
+	m, s := 3*segments, ((slice+1)%argonSyncPoints)*segments
+	if lane == refLane {
+		m += index
+	}
+	if n == 0 {
+		m, s = slice*segments, 0
+		if slice == 0 || lane == refLane {
+			m += index
+		}
+	}
+	if index == 0 || lane == refLane {
+		m--
+	}
+	p := rand & 0xFFFFFFFF
+	p = (p * p) >> 32
+	p = (p * uint64(m)) >> 32
+	return refLane*lanes + uint32((uint64(s)+uint64(m)-(p+1))%uint64(lanes))
+}
+
+// argonCompress computes G(x, y) into out, XORing with its previous
+// content for passes after the first in version 0x13.
+func argonCompress(out, x, y *argonBlock, xor bool) {
+	var r, t argonBlock
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+	t = r
+	for i := 0; i < 128; i += 16 {
+		argonRound(&t, [16]int{i, i + 1, i + 2, i + 3, i + 4, i + 5, i + 6, i + 7, i + 8, i + 9, i + 10, i + 11, i + 12, i + 13, i + 14, i + 15})
+	}
+	for i := 0; i < 16; i += 2 {
+		argonRound(&t, [16]int{i, i + 1, 16 + i, 17 + i, 32 + i, 33 + i, 48 + i, 49 + i, 64 + i, 65 + i, 80 + i, 81 + i, 96 + i, 97 + i, 112 + i, 113 + i})
+	}
+	for i := range t {
+		if xor {
+			out[i] ^= r[i] ^ t[i]
+		} else {
+			out[i] = r[i] ^ t[i]
+		}
+	}
+}
+
+// argonRound is the BlaMka permutation P over 16 words of b.
+func argonRound(b *argonBlock, w [16]int) {
+	g := func(a, bb, c, d int) {
+		mul := func(x, y uint64) uint64 { return 2 * uint64(uint32(x)) * uint64(uint32(y)) }
+		b[w[a]] += b[w[bb]] + mul(b[w[a]], b[w[bb]])
+		b[w[d]] = bits.RotateLeft64(b[w[d]]^b[w[a]], -32)
+		b[w[c]] += b[w[d]] + mul(b[w[c]], b[w[d]])
+		b[w[bb]] = bits.RotateLeft64(b[w[bb]]^b[w[c]], -24)
+		b[w[a]] += b[w[bb]] + mul(b[w[a]], b[w[bb]])
+		b[w[d]] = bits.RotateLeft64(b[w[d]]^b[w[a]], -16)
+		b[w[c]] += b[w[d]] + mul(b[w[c]], b[w[d]])
+		b[w[bb]] = bits.RotateLeft64(b[w[bb]]^b[w[c]], -63)
+	}
+	g(0, 4, 8, 12)
+	g(1, 5, 9, 13)
+	g(2, 6, 10, 14)
+	g(3, 7, 11, 15)
+	g(0, 5, 10, 15)
+	g(1, 6, 11, 12)
+	g(2, 7, 8, 13)
+	g(3, 4, 9, 14)
+}
+
+// blake2bLong is the variable-length hash H' from RFC 9106 section 3.3.
+func blake2bLong(out []byte, in []byte) {
+	var prefix [4]byte
+	binary.LittleEndian.PutUint32(prefix[:], uint32(len(out)))
+	if len(out) <= blake2b.Size {
+		h, _ := blake2b.New(len(out), nil)
+		h.Write(prefix[:])
+		h.Write(in)
+		h.Sum(out[:0])
+		return
+	}
+
+	h, _ := blake2b.New512(nil)
+	h.Write(prefix[:])
+	h.Write(in)
+	v := h.Sum(nil)
+	copy(out, v[:32])
+	out = out[32:]
+	for len(out) > blake2b.Size {
+		v2 := blake2b.Sum512(v)
+		v = v2[:]
+		copy(out, v[:32])
+		out = out[32:]
+	}
+	h, _ = blake2b.New(len(out), nil)
+	h.Write(v)
+	h.Sum(out[:0])
+}
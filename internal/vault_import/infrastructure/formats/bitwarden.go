@@ -0,0 +1,305 @@
+package vault_import_formats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// BitwardenJSON reads the unencrypted JSON export ("File > Export vault >
+// .json"). Password-protected exports are rejected; the user can re-export
+// without a file password.
+type BitwardenJSON struct{}
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	ID       string `json:"id"`
+	FolderID string `json:"folderId"`
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Favorite bool   `json:"favorite"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+		Type  int    `json:"type"`
+	} `json:"fields"`
+	Login *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+		Fido2Credentials []json.RawMessage `json:"fido2Credentials"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Brand          string `json:"brand"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity *struct {
+		Title          string `json:"title"`
+		FirstName      string `json:"firstName"`
+		MiddleName     string `json:"middleName"`
+		LastName       string `json:"lastName"`
+		Address1       string `json:"address1"`
+		Address2       string `json:"address2"`
+		Address3       string `json:"address3"`
+		City           string `json:"city"`
+		State          string `json:"state"`
+		PostalCode     string `json:"postalCode"`
+		Country        string `json:"country"`
+		Company        string `json:"company"`
+		Email          string `json:"email"`
+		Phone          string `json:"phone"`
+		SSN            string `json:"ssn"`
+		Username       string `json:"username"`
+		PassportNumber string `json:"passportNumber"`
+		LicenseNumber  string `json:"licenseNumber"`
+	} `json:"identity"`
+	SSHKey *struct {
+		PrivateKey     string `json:"privateKey"`
+		PublicKey      string `json:"publicKey"`
+		KeyFingerprint string `json:"keyFingerprint"`
+	} `json:"sshKey"`
+}
+
+const (
+	bitwardenLogin    = 1
+	bitwardenNote     = 2
+	bitwardenCard     = 3
+	bitwardenIdentity = 4
+	bitwardenSSHKey   = 5
+
+	bitwardenFieldLinked = 3
+)
+
+func (BitwardenJSON) Parse(data []byte, _ string) (*vault_import_domain.Batch, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("%w: password-protected Bitwarden exports are not supported", vault_import_domain.ErrUnsupportedEncryption)
+	}
+
+	folders := map[string]string{}
+	for _, f := range export.Folders {
+		folders[f.ID] = f.Name
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatBitwardenJSON}
+	for _, item := range export.Items {
+		rec, ok := bitwardenRecord(item)
+		if !ok {
+			batch.Warnf("item %s (%s) has unknown type %d and was skipped", item.ID, item.Name, item.Type)
+			continue
+		}
+		rec.Folder = folders[item.FolderID]
+		batch.Records = append(batch.Records, rec)
+	}
+	return batch, nil
+}
+
+func bitwardenRecord(item bitwardenItem) (vault_import_domain.Record, bool) {
+	rec := vault_import_domain.Record{SourceID: item.ID}
+
+	switch {
+	case item.Type == bitwardenLogin && item.Login != nil:
+		login := &vaults_domain.LoginEntry{UserName: item.Login.Username, Password: item.Login.Password}
+		rec.Entry = login
+		for i, u := range item.Login.URIs {
+			if i == 0 {
+				login.Website = u.URI
+				continue
+			}
+			setCustom(login, "uri", u.URI)
+		}
+		if item.Login.TOTP != "" {
+			setCustom(login, "totp", item.Login.TOTP)
+		}
+		if n := len(item.Login.Fido2Credentials); n > 0 {
+			rec.Warnf("%d passkey(s) cannot be exported by Bitwarden and were not imported", n)
+		}
+	case item.Type == bitwardenCard && item.Card != nil:
+		c := item.Card
+		card := &vaults_domain.CardEntry{
+			Owner:      c.CardholderName,
+			Number:     c.Number,
+			Expiration: cardExpiration(c.ExpMonth, c.ExpYear),
+			CVC:        c.Code,
+		}
+		if c.Brand != "" {
+			setCustom(card, "brand", c.Brand)
+		}
+		rec.Entry = card
+	case item.Type == bitwardenIdentity && item.Identity != nil:
+		id := item.Identity
+		identity := &vaults_domain.IdentityEntry{
+			Genre:                id.Title,
+			FirstName:            id.FirstName,
+			SecondFirstName:      id.MiddleName,
+			LastName:             id.LastName,
+			Username:             id.Username,
+			Company:              id.Company,
+			SocialSecurityNumber: id.SSN,
+			IDNumber:             id.PassportNumber,
+			DriverLicense:        id.LicenseNumber,
+			Mail:                 id.Email,
+			Telephone:            id.Phone,
+			AddressOne:           id.Address1,
+			AddressTwo:           id.Address2,
+			AddressThree:         id.Address3,
+			City:                 id.City,
+			State:                id.State,
+			PostalCode:           id.PostalCode,
+			Country:              id.Country,
+		}
+		rec.Entry = identity
+	case item.Type == bitwardenSSHKey && item.SSHKey != nil:
+		rec.Entry = &vaults_domain.SSHKeyEntry{
+			PrivateKey:   item.SSHKey.PrivateKey,
+			PublicKey:    item.SSHKey.PublicKey,
+			EFingerprint: item.SSHKey.KeyFingerprint,
+		}
+	case item.Type == bitwardenNote:
+		rec.Entry = &vaults_domain.NoteEntry{}
+	default:
+		return rec, false
+	}
+
+	for _, f := range item.Fields {
+		if f.Type == bitwardenFieldLinked {
+			rec.Warnf("linked field %q has no equivalent and was skipped", f.Name)
+			continue
+		}
+		setCustom(rec.Entry, f.Name, f.Value)
+	}
+	stamp(rec.Entry, item.Name, item.Notes, item.Favorite)
+	return rec, true
+}
+
+// BitwardenCSV reads the CSV export, which only carries logins and notes.
+type BitwardenCSV struct{}
+
+func (BitwardenCSV) Parse(data []byte, _ string) (*vault_import_domain.Batch, error) {
+	rows, err := readCSV(data, "name", "type")
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatBitwardenCSV}
+	for i, row := range rows {
+		rec := vault_import_domain.Record{SourceID: fmt.Sprintf("row-%d", i+2), Folder: row["folder"]}
+		switch row["type"] {
+		case "login":
+			login := &vaults_domain.LoginEntry{
+				UserName: row["login_username"],
+				Password: row["login_password"],
+			}
+			uris := strings.Split(row["login_uri"], ",")
+			login.Website = strings.TrimSpace(uris[0])
+			for _, u := range uris[1:] {
+				setCustom(login, "uri", strings.TrimSpace(u))
+			}
+			if row["login_totp"] != "" {
+				setCustom(login, "totp", row["login_totp"])
+			}
+			rec.Entry = login
+		case "note":
+			rec.Entry = &vaults_domain.NoteEntry{}
+		default:
+			batch.Warnf("row %d has unsupported type %q and was skipped", i+2, row["type"])
+			continue
+		}
+
+		// Custom fields are serialised as "name: value" lines.
+		for _, line := range strings.Split(row["fields"], "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(line, ": ")
+			if !ok {
+				rec.Warnf("could not read custom field %q", line)
+				continue
+			}
+			setCustom(rec.Entry, name, value)
+		}
+		stamp(rec.Entry, row["name"], row["notes"], row["favorite"] == "1")
+		batch.Records = append(batch.Records, rec)
+	}
+	return batch, nil
+}
+
+// readCSV returns the rows keyed by header name, checking that the required
+// columns are present.
+func readCSV(data []byte, required ...string) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	for _, col := range required {
+		found := false
+		for _, h := range header {
+			found = found || h == col
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: missing column %q", vault_import_domain.ErrMalformedExport, col)
+		}
+	}
+
+	var rows []map[string]string
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+		}
+		row := map[string]string{}
+		for i, h := range header {
+			if i < len(fields) {
+				row[h] = fields[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func cardExpiration(month, year string) string {
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
@@ -0,0 +1,76 @@
+package vault_import_formats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// Parsers returns every supported format keyed by its identifier.
+func Parsers() map[vault_import_domain.Format]vault_import_domain.Parser {
+	return map[vault_import_domain.Format]vault_import_domain.Parser{
+		vault_import_domain.FormatBitwardenJSON: BitwardenJSON{},
+		vault_import_domain.FormatBitwardenCSV:  BitwardenCSV{},
+		vault_import_domain.FormatOnePassword:   OnePassword{},
+		vault_import_domain.FormatKeePass:       KeePass{},
+		vault_import_domain.FormatLastPass:      LastPass{},
+		vault_import_domain.FormatArchive:       Archive{},
+	}
+}
+
+// stamp fills the BaseEntry fields every imported entry shares. Type is
+// taken from GetTypeName so entries round-trip through the entries branch.
+func stamp(entry vault_import_domain.Entry, name string, notes string, favorite bool) vault_import_domain.Entry {
+	now := time.Now().Format(time.RFC3339)
+	base := entry.GetBase()
+	base.Type = vaults_domain.EntryType(entry.GetTypeName())
+	base.EntryName = strings.TrimSpace(name)
+	base.AdditionnalNote = notes
+	base.IsFavorite = favorite
+	base.IsDirty = true
+	base.CreatedAt = now
+	base.UpdatedAt = now
+	if base.EntryName == "" {
+		base.EntryName = "Untitled " + entry.GetTypeName()
+	}
+	return entry
+}
+
+// setCustom stores a source field that has no dedicated slot on the entry.
+// Repeated names get a numeric suffix rather than overwriting each other.
+func setCustom(entry vault_import_domain.Entry, name string, value any) {
+	if name == "" {
+		name = "field"
+	}
+	base := entry.GetBase()
+	if base.CustomFields == nil {
+		base.CustomFields = vaults_domain.JSONMap{}
+	}
+	key := name
+	for i := 2; ; i++ {
+		if _, taken := base.CustomFields[key]; !taken {
+			break
+		}
+		key = fmt.Sprintf("%s_%d", name, i)
+	}
+	base.CustomFields[key] = value
+}
+
+// looksLikePrivateKey reports whether s is a PEM or OpenSSH private key.
+func looksLikePrivateKey(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "-----BEGIN") && strings.Contains(s, "PRIVATE KEY-----")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
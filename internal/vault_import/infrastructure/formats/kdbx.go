@@ -0,0 +1,405 @@
+package vault_import_formats
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/salsa20/salsa"
+
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// KDBX 4 container, as written by KeePass 2.35+ and KeePassXC 2.3+.
+// https://keepass.info/help/kb/kdbx_4.html
+
+const (
+	kdbxSignature1 = 0x9AA2D903
+	kdbxSignature2 = 0xB54BFB67
+)
+
+// Outer header field ids.
+const (
+	kdbxEndOfHeader      = 0
+	kdbxCipherID         = 2
+	kdbxCompressionFlags = 3
+	kdbxMasterSeed       = 4
+	kdbxEncryptionIV     = 7
+	kdbxKdfParameters    = 11
+)
+
+// Inner header field ids.
+const (
+	kdbxInnerEnd          = 0
+	kdbxInnerStreamID     = 1
+	kdbxInnerStreamKey    = 2
+	kdbxInnerBinary       = 3
+	kdbxStreamSalsa20     = 2
+	kdbxStreamChaCha20    = 3
+	kdbxCompressionGzip   = 1
+	kdbxHeaderBlockIndex  = ^uint64(0)
+	kdbxVariantDictionary = 0x0100
+)
+
+var (
+	kdbxCipherAES256   = mustHex("31c1f2e6bf714350be5805216afc5aff")
+	kdbxCipherChaCha20 = mustHex("d6038a2b8b6f4cb5a524339a31dbb59a")
+	kdbxKdfAES         = mustHex("c9d9f39a628a4460bf740d08c18a4fea")
+	kdbxKdfArgon2d     = mustHex("ef636ddf8c29444b91f7a9a403e30a0c")
+	kdbxKdfArgon2id    = mustHex("9e298b1956db4773b23dfc3ec6f0a1e6")
+	kdbxSalsa20Nonce   = []byte{0xE8, 0x30, 0x09, 0x4B, 0x97, 0x20, 0x5D, 0x2A}
+)
+
+// kdbxDatabase is a decrypted KDBX 4 file: the XML document, the binaries
+// pool from the inner header and the stream used to reveal protected values.
+type kdbxDatabase struct {
+	XML      []byte
+	Binaries [][]byte
+	stream   cipher.Stream
+}
+
+// Unprotect XORs a protected value with the next bytes of the inner stream.
+// Values must be revealed in document order.
+func (db *kdbxDatabase) Unprotect(sealed []byte) []byte {
+	out := make([]byte, len(sealed))
+	db.stream.XORKeyStream(out, sealed)
+	return out
+}
+
+func openKDBX(data []byte, password string) (*kdbxDatabase, error) {
+	malformed := func(what string) error {
+		return fmt.Errorf("%w: %s", vault_import_domain.ErrMalformedExport, what)
+	}
+	if len(data) < 12 ||
+		binary.LittleEndian.Uint32(data[0:4]) != kdbxSignature1 ||
+		binary.LittleEndian.Uint32(data[4:8]) != kdbxSignature2 {
+		return nil, malformed("not a KeePass database")
+	}
+	if major := binary.LittleEndian.Uint16(data[10:12]); major != 4 {
+		return nil, fmt.Errorf("%w: KDBX version %d, only KDBX 4 is supported", vault_import_domain.ErrUnsupportedEncryption, major)
+	}
+
+	// ---------- Outer header ----------
+	fields := map[byte][]byte{}
+	pos := 12
+	for {
+		if pos+5 > len(data) {
+			return nil, malformed("truncated header")
+		}
+		id := data[pos]
+		size := int(binary.LittleEndian.Uint32(data[pos+1 : pos+5]))
+		pos += 5
+		if size < 0 || pos+size > len(data) {
+			return nil, malformed("truncated header field")
+		}
+		fields[id] = data[pos : pos+size]
+		pos += size
+		if id == kdbxEndOfHeader {
+			break
+		}
+	}
+	header := data[:pos]
+	if pos+64 > len(data) {
+		return nil, malformed("missing header hashes")
+	}
+	headerHash, headerMAC := data[pos:pos+32], data[pos+32:pos+64]
+	pos += 64
+	if sum := sha256.Sum256(header); !hmac.Equal(sum[:], headerHash) {
+		return nil, malformed("header checksum mismatch")
+	}
+
+	seed := fields[kdbxMasterSeed]
+	if len(seed) != 32 {
+		return nil, malformed("invalid master seed")
+	}
+	kdf, err := readVariantDictionary(fields[kdbxKdfParameters])
+	if err != nil {
+		return nil, err
+	}
+
+	// ---------- Keys ----------
+	if password == "" {
+		return nil, vault_import_domain.ErrPasswordRequired
+	}
+	pw := sha256.Sum256([]byte(password))
+	composite := sha256.Sum256(pw[:])
+	transformed, err := kdbxTransformKey(composite[:], kdf)
+	if err != nil {
+		return nil, err
+	}
+	masterKey := sha256.Sum256(append(append([]byte{}, seed...), transformed...))
+	hmacBase := sha512.Sum512(append(append(append([]byte{}, seed...), transformed...), 0x01))
+
+	if !hmac.Equal(kdbxBlockMAC(hmacBase[:], kdbxHeaderBlockIndex, header), headerMAC) {
+		return nil, vault_import_domain.ErrInvalidCredentials
+	}
+
+	// ---------- HMAC block stream ----------
+	var payload []byte
+	for index := uint64(0); ; index++ {
+		if pos+36 > len(data) {
+			return nil, malformed("truncated block")
+		}
+		mac := data[pos : pos+32]
+		size := int(binary.LittleEndian.Uint32(data[pos+32 : pos+36]))
+		if size < 0 || pos+36+size > len(data) {
+			return nil, malformed("truncated block")
+		}
+		block := data[pos+36 : pos+36+size]
+		var prefix [12]byte
+		binary.LittleEndian.PutUint64(prefix[:8], index)
+		copy(prefix[8:], data[pos+32:pos+36])
+		if !hmac.Equal(kdbxBlockMAC(hmacBase[:], index, append(prefix[:], block...)), mac) {
+			return nil, malformed(fmt.Sprintf("block %d failed authentication", index))
+		}
+		pos += 36 + size
+		if size == 0 {
+			break
+		}
+		payload = append(payload, block...)
+	}
+
+	// ---------- Decrypt and decompress ----------
+	plain, err := kdbxDecrypt(fields[kdbxCipherID], masterKey[:], fields[kdbxEncryptionIV], payload)
+	if err != nil {
+		return nil, err
+	}
+	if flags := fields[kdbxCompressionFlags]; len(flags) == 4 && binary.LittleEndian.Uint32(flags) == kdbxCompressionGzip {
+		zr, err := gzip.NewReader(bytes.NewReader(plain))
+		if err != nil {
+			return nil, malformed("invalid gzip payload")
+		}
+		if plain, err = io.ReadAll(zr); err != nil {
+			return nil, malformed("invalid gzip payload")
+		}
+	}
+
+	// ---------- Inner header ----------
+	db := &kdbxDatabase{}
+	var streamID uint32
+	var streamKey []byte
+	pos = 0
+	for {
+		if pos+5 > len(plain) {
+			return nil, malformed("truncated inner header")
+		}
+		id := plain[pos]
+		size := int(binary.LittleEndian.Uint32(plain[pos+1 : pos+5]))
+		pos += 5
+		if size < 0 || pos+size > len(plain) {
+			return nil, malformed("truncated inner header field")
+		}
+		value := plain[pos : pos+size]
+		pos += size
+		switch id {
+		case kdbxInnerStreamID:
+			if len(value) == 4 {
+				streamID = binary.LittleEndian.Uint32(value)
+			}
+		case kdbxInnerStreamKey:
+			streamKey = value
+		case kdbxInnerBinary:
+			if len(value) > 0 {
+				// The first byte holds flags (protected in memory); skip it.
+				db.Binaries = append(db.Binaries, value[1:])
+			}
+		}
+		if id == kdbxInnerEnd {
+			break
+		}
+	}
+	db.XML = plain[pos:]
+
+	switch streamID {
+	case kdbxStreamChaCha20:
+		h := sha512.Sum512(streamKey)
+		db.stream, err = chacha20.NewUnauthenticatedCipher(h[:32], h[32:44])
+		if err != nil {
+			return nil, err
+		}
+	case kdbxStreamSalsa20:
+		db.stream = &salsa20Stream{key: sha256.Sum256(streamKey)}
+	default:
+		return nil, fmt.Errorf("%w: inner stream %d", vault_import_domain.ErrUnsupportedEncryption, streamID)
+	}
+	return db, nil
+}
+
+func kdbxBlockMAC(base []byte, index uint64, data []byte) []byte {
+	var idx [8]byte
+	binary.LittleEndian.PutUint64(idx[:], index)
+	key := sha512.Sum512(append(idx[:], base...))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Upper bounds on the key derivation parameters read from a database
+// header. They are far above the KeePass defaults and keep a crafted file
+// from making the import derive for hours or allocate unbounded memory
+// before the password is even checked.
+const (
+	kdbxMaxAESRounds    = 100_000_000
+	kdbxMaxArgon2Memory = 1 << 30 // bytes
+	kdbxMaxArgon2Passes = 100
+	kdbxMaxArgon2Lanes  = 64
+)
+
+func kdbxTransformKey(composite []byte, kdf map[string][]byte) ([]byte, error) {
+	u32 := func(k string) uint32 {
+		if v := kdf[k]; len(v) >= 4 {
+			return binary.LittleEndian.Uint32(v)
+		}
+		return 0
+	}
+	u64 := func(k string) uint64 {
+		if v := kdf[k]; len(v) >= 8 {
+			return binary.LittleEndian.Uint64(v)
+		}
+		return uint64(u32(k))
+	}
+
+	switch uuid := kdf["$UUID"]; {
+	case bytes.Equal(uuid, kdbxKdfAES):
+		block, err := aes.NewCipher(kdf["S"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid AES-KDF seed", vault_import_domain.ErrMalformedExport)
+		}
+		rounds := u64("R")
+		if rounds > kdbxMaxAESRounds {
+			return nil, fmt.Errorf("%w: AES-KDF with %d rounds", vault_import_domain.ErrUnsupportedEncryption, rounds)
+		}
+		key := append([]byte{}, composite...)
+		for r := rounds; r > 0; r-- {
+			block.Encrypt(key[0:16], key[0:16])
+			block.Encrypt(key[16:32], key[16:32])
+		}
+		sum := sha256.Sum256(key)
+		return sum[:], nil
+	case bytes.Equal(uuid, kdbxKdfArgon2d), bytes.Equal(uuid, kdbxKdfArgon2id):
+		memory, passes, lanes, version := u64("M"), u64("I"), u32("P"), u32("V")
+		if lanes == 0 || passes == 0 {
+			return nil, fmt.Errorf("%w: invalid Argon2 parameters", vault_import_domain.ErrMalformedExport)
+		}
+		if memory > kdbxMaxArgon2Memory || passes > kdbxMaxArgon2Passes || lanes > kdbxMaxArgon2Lanes {
+			return nil, fmt.Errorf("%w: Argon2 with %d bytes, %d passes and %d lanes", vault_import_domain.ErrUnsupportedEncryption, memory, passes, lanes)
+		}
+		memoryKiB, iterations := uint32(memory/1024), uint32(passes)
+		if bytes.Equal(uuid, kdbxKdfArgon2id) {
+			if len(kdf["K"]) > 0 || len(kdf["A"]) > 0 || version != 0x13 {
+				return nil, fmt.Errorf("%w: Argon2id with secret or version %#x", vault_import_domain.ErrUnsupportedEncryption, version)
+			}
+			return argon2.IDKey(composite, kdf["S"], iterations, memoryKiB, uint8(lanes), 32), nil
+		}
+		return argon2dKey(composite, kdf["S"], kdf["K"], kdf["A"], iterations, memoryKiB, lanes, version, 32), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown key derivation function", vault_import_domain.ErrUnsupportedEncryption)
+	}
+}
+
+func kdbxDecrypt(cipherID, key, iv, payload []byte) ([]byte, error) {
+	switch {
+	case bytes.Equal(cipherID, kdbxCipherAES256):
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(iv) != aes.BlockSize || len(payload) == 0 || len(payload)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("%w: invalid AES payload", vault_import_domain.ErrMalformedExport)
+		}
+		out := make([]byte, len(payload))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, payload)
+		pad := int(out[len(out)-1])
+		if pad == 0 || pad > aes.BlockSize || pad > len(out) {
+			return nil, vault_import_domain.ErrInvalidCredentials
+		}
+		return out[:len(out)-pad], nil
+	case bytes.Equal(cipherID, kdbxCipherChaCha20):
+		c, err := chacha20.NewUnauthenticatedCipher(key, iv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid ChaCha20 nonce", vault_import_domain.ErrMalformedExport)
+		}
+		out := make([]byte, len(payload))
+		c.XORKeyStream(out, payload)
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%w: cipher %x (only AES-256 and ChaCha20 are supported)", vault_import_domain.ErrUnsupportedEncryption, cipherID)
+	}
+}
+
+// readVariantDictionary decodes the KDF parameters. Values are returned raw;
+// integers are little-endian.
+func readVariantDictionary(b []byte) (map[string][]byte, error) {
+	malformed := fmt.Errorf("%w: invalid KDF parameters", vault_import_domain.ErrMalformedExport)
+	if len(b) < 2 || binary.LittleEndian.Uint16(b)&0xFF00 != kdbxVariantDictionary {
+		return nil, malformed
+	}
+	out := map[string][]byte{}
+	pos := 2
+	for pos < len(b) {
+		kind := b[pos]
+		pos++
+		if kind == 0 {
+			return out, nil
+		}
+		if pos+4 > len(b) {
+			return nil, malformed
+		}
+		klen := int(binary.LittleEndian.Uint32(b[pos:]))
+		pos += 4
+		if klen < 0 || pos+klen+4 > len(b) {
+			return nil, malformed
+		}
+		key := string(b[pos : pos+klen])
+		pos += klen
+		vlen := int(binary.LittleEndian.Uint32(b[pos:]))
+		pos += 4
+		if vlen < 0 || pos+vlen > len(b) {
+			return nil, malformed
+		}
+		out[key] = b[pos : pos+vlen]
+		pos += vlen
+	}
+	return nil, malformed
+}
+
+// salsa20Stream adapts x/crypto/salsa20 to cipher.Stream for the legacy
+// inner stream, keeping the keystream position across calls.
+type salsa20Stream struct {
+	key     [32]byte
+	counter uint64
+	buf     []byte
+}
+
+func (s *salsa20Stream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if len(s.buf) == 0 {
+			var nonce [16]byte
+			copy(nonce[:8], kdbxSalsa20Nonce)
+			binary.LittleEndian.PutUint64(nonce[8:], s.counter)
+			s.buf = make([]byte, 64)
+			salsa.XORKeyStream(s.buf, s.buf, &nonce, &s.key)
+			s.counter++
+		}
+		dst[i] = src[i] ^ s.buf[0]
+		s.buf = s.buf[1:]
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,234 @@
+package vault_import_formats
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// KeePass reads a password-protected KDBX 4 database. Groups below the root
+// become "/"-separated folder paths, entry binaries become attachments, and
+// entries in the recycle bin are skipped. Key files are not supported.
+type KeePass struct{}
+
+func (KeePass) Parse(data []byte, password string) (*vault_import_domain.Batch, error) {
+	db, err := openKDBX(data, password)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseKeePassXML(db)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatKeePass}
+	recycleBin := root.find("Meta", "RecycleBinUUID").Text
+	top := root.find("Root", "Group")
+	if top == nil {
+		return nil, fmt.Errorf("%w: database has no root group", vault_import_domain.ErrMalformedExport)
+	}
+
+	var walk func(group *xmlNode, path string)
+	walk = func(group *xmlNode, path string) {
+		for _, child := range group.Children {
+			switch child.Name {
+			case "Entry":
+				batch.Records = append(batch.Records, keePassRecord(child, path, db.Binaries))
+			case "Group":
+				if recycleBin != "" && child.find("UUID").Text == recycleBin {
+					if n := child.count("Entry"); n > 0 {
+						batch.Warnf("%d entries in the recycle bin were skipped", n)
+					}
+					continue
+				}
+				name := child.find("Name").Text
+				if path != "" {
+					name = path + "/" + name
+				}
+				walk(child, name)
+			}
+		}
+	}
+	walk(top, "")
+	return batch, nil
+}
+
+func keePassRecord(e *xmlNode, folder string, binaries [][]byte) vault_import_domain.Record {
+	rec := vault_import_domain.Record{SourceID: e.find("UUID").Text, Folder: folder}
+
+	strs := map[string]string{}
+	for _, s := range e.all("String") {
+		strs[s.find("Key").Text] = s.find("Value").Text
+	}
+	take := func(key string) string {
+		v := strs[key]
+		delete(strs, key)
+		return v
+	}
+	title, notes := take("Title"), take("Notes")
+	username, pass, url := take("UserName"), take("Password"), take("URL")
+
+	var privateKey, publicKey string
+	var attachments []vault_import_domain.Attachment
+	for _, b := range e.all("Binary") {
+		name := b.find("Key").Text
+		ref, err := strconv.Atoi(b.find("Value").Attr("Ref"))
+		if err != nil || ref < 0 || ref >= len(binaries) {
+			rec.Warnf("attachment %q references a missing binary", name)
+			continue
+		}
+		content := binaries[ref]
+		switch {
+		case looksLikePrivateKey(string(content)):
+			privateKey = string(content)
+		case strings.HasSuffix(name, ".pub") && publicKey == "":
+			publicKey = strings.TrimSpace(string(content))
+		default:
+			attachments = append(attachments, vault_import_domain.Attachment{Name: name, Data: content})
+		}
+	}
+
+	switch {
+	case privateKey != "" && username == "" && url == "":
+		// KeeAgent-style entry holding a key file.
+		rec.Entry = &vaults_domain.SSHKeyEntry{PrivateKey: privateKey, PublicKey: publicKey}
+		if pass != "" {
+			setCustom(rec.Entry, "passphrase", pass)
+		}
+	case username != "" || pass != "" || url != "":
+		rec.Entry = &vaults_domain.LoginEntry{UserName: username, Password: pass, Website: url}
+		if privateKey != "" {
+			attachments = append(attachments, vault_import_domain.Attachment{Name: "private_key", Data: []byte(privateKey)})
+		}
+	default:
+		rec.Entry = &vaults_domain.NoteEntry{}
+	}
+	rec.Attachments = attachments
+
+	for _, key := range sortedKeys(strs) {
+		if strs[key] != "" {
+			setCustom(rec.Entry, key, strs[key])
+		}
+	}
+	if tags := e.find("Tags").Text; tags != "" {
+		setCustom(rec.Entry, "tags", tags)
+	}
+	if n := e.find("History").count("Entry"); n > 0 {
+		rec.Warnf("%d history versions were not imported", n)
+	}
+	stamp(rec.Entry, title, notes, false)
+	return rec
+}
+
+// xmlNode is a minimal DOM; the KeePass document is small enough to hold in
+// memory and a tree makes revealing protected values in order simple.
+type xmlNode struct {
+	Name     string
+	Attrs    []xml.Attr
+	Text     string
+	Children []*xmlNode
+}
+
+func (n *xmlNode) Attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// find follows a path of direct children, returning an empty node when any
+// step is missing so lookups can be chained.
+func (n *xmlNode) find(path ...string) *xmlNode {
+	cur := n
+	for _, name := range path {
+		var next *xmlNode
+		if cur != nil {
+			for _, c := range cur.Children {
+				if c.Name == name {
+					next = c
+					break
+				}
+			}
+		}
+		cur = next
+	}
+	if cur == nil {
+		return &xmlNode{}
+	}
+	return cur
+}
+
+func (n *xmlNode) all(name string) []*xmlNode {
+	var out []*xmlNode
+	for _, c := range n.Children {
+		if c.Name == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// count returns the number of name descendants, recursing into groups.
+func (n *xmlNode) count(name string) int {
+	total := 0
+	for _, c := range n.Children {
+		if c.Name == name {
+			total++
+		}
+		if c.Name == "Group" {
+			total += c.count(name)
+		}
+	}
+	return total
+}
+
+// parseKeePassXML builds the document tree, revealing Protected="True"
+// values as they are closed. Protected values are leaves, so closing order
+// is document order, which is the order the inner stream expects.
+func parseKeePassXML(db *kdbxDatabase) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(db.XML))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+		}
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name.Local, Attrs: t.Attr}
+			top.Children = append(top.Children, node)
+			stack = append(stack, node)
+		case xml.CharData:
+			top.Text += string(t)
+		case xml.EndElement:
+			if strings.EqualFold(top.Attr("Protected"), "True") {
+				sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(top.Text))
+				if err != nil {
+					return nil, fmt.Errorf("%w: invalid protected value", vault_import_domain.ErrMalformedExport)
+				}
+				top.Text = string(db.Unprotect(sealed))
+			} else if len(top.Children) > 0 {
+				top.Text = ""
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return root.find("KeePassFile"), nil
+}
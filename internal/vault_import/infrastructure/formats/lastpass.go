@@ -0,0 +1,160 @@
+package vault_import_formats
+
+import (
+	"fmt"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// LastPass reads the CSV export. Secure notes (url "http://sn") carry their
+// structured fields in "extra" as "Key:Value" lines under a NoteType header.
+type LastPass struct{}
+
+const lastPassNoteURL = "http://sn"
+
+func (LastPass) Parse(data []byte, _ string) (*vault_import_domain.Batch, error) {
+	rows, err := readCSV(data, "url", "username", "password", "extra", "name", "grouping")
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatLastPass}
+	for i, row := range rows {
+		rec := vault_import_domain.Record{
+			SourceID: fmt.Sprintf("row-%d", i+2),
+			Folder:   strings.ReplaceAll(strings.Trim(row["grouping"], `\`), `\`, "/"),
+		}
+		notes := row["extra"]
+
+		if row["url"] != lastPassNoteURL {
+			login := &vaults_domain.LoginEntry{
+				UserName: row["username"],
+				Password: row["password"],
+				Website:  row["url"],
+			}
+			if row["totp"] != "" {
+				setCustom(login, "totp", row["totp"])
+			}
+			rec.Entry = login
+		} else {
+			noteType, fields, rest := parseLastPassNote(notes)
+			rec.Entry, notes = lastPassNoteEntry(&rec, noteType, fields, rest)
+		}
+
+		stamp(rec.Entry, row["name"], notes, row["fav"] == "1")
+		batch.Records = append(batch.Records, rec)
+	}
+	return batch, nil
+}
+
+// parseLastPassNote splits a structured note into its type, its fields and
+// the free-form "Notes" tail. Plain notes return an empty type.
+func parseLastPassNote(extra string) (string, map[string]string, string) {
+	if !strings.HasPrefix(extra, "NoteType:") {
+		return "", nil, extra
+	}
+	fields := map[string]string{}
+	lines := strings.Split(extra, "\n")
+	noteType := strings.TrimPrefix(lines[0], "NoteType:")
+	for i := 1; i < len(lines); i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok {
+			continue
+		}
+		if key == "Notes" {
+			// Notes is always last and may span several lines.
+			return noteType, fields, strings.Join(append([]string{value}, lines[i+1:]...), "\n")
+		}
+		if key == "Private Key" || key == "Public Key" {
+			// Keys are multi-line too; gather lines until the next field.
+			j := i + 1
+			for j < len(lines) && !isLastPassFieldLine(lines[j]) {
+				j++
+			}
+			value = strings.Join(append([]string{value}, lines[i+1:j]...), "\n")
+			i = j - 1
+		}
+		fields[key] = value
+	}
+	return noteType, fields, ""
+}
+
+var lastPassFieldNames = map[string]bool{
+	"Bit Strength": true, "Format": true, "Passphrase": true, "Private Key": true,
+	"Public Key": true, "Hostname": true, "Date": true, "Notes": true,
+}
+
+func isLastPassFieldLine(line string) bool {
+	key, _, ok := strings.Cut(line, ":")
+	return ok && lastPassFieldNames[key]
+}
+
+func lastPassNoteEntry(rec *vault_import_domain.Record, noteType string, fields map[string]string, notes string) (vault_import_domain.Entry, string) {
+	take := func(key string) string {
+		v := fields[key]
+		delete(fields, key)
+		return v
+	}
+
+	var entry vault_import_domain.Entry
+	switch noteType {
+	case "":
+		return &vaults_domain.NoteEntry{}, notes
+	case "Credit Card":
+		entry = &vaults_domain.CardEntry{
+			Owner:      take("Name on Card"),
+			Number:     take("Number"),
+			CVC:        take("Security Code"),
+			Expiration: lastPassExpiration(take("Expiration Date")),
+		}
+	case "Address":
+		entry = &vaults_domain.IdentityEntry{
+			Genre:           take("Title"),
+			FirstName:       take("First Name"),
+			SecondFirstName: take("Middle Name"),
+			LastName:        take("Last Name"),
+			Username:        take("Username"),
+			Company:         take("Company"),
+			AddressOne:      take("Address 1"),
+			AddressTwo:      take("Address 2"),
+			AddressThree:    take("Address 3"),
+			City:            take("City / Town"),
+			State:           take("State"),
+			PostalCode:      take("Zip / Postal Code"),
+			Country:         take("Country"),
+			Mail:            take("Email Address"),
+			Telephone:       take("Phone"),
+		}
+	case "SSH Key":
+		entry = &vaults_domain.SSHKeyEntry{
+			PrivateKey: take("Private Key"),
+			PublicKey:  take("Public Key"),
+		}
+	default:
+		entry = &vaults_domain.NoteEntry{}
+		rec.Warnf("LastPass note type %q was imported as a secure note", noteType)
+	}
+	for _, key := range sortedKeys(fields) {
+		if fields[key] != "" {
+			setCustom(entry, key, fields[key])
+		}
+	}
+	return entry, notes
+}
+
+// lastPassExpiration turns "January,2027" into "01/27".
+func lastPassExpiration(v string) string {
+	month, year, ok := strings.Cut(v, ",")
+	if !ok {
+		return v
+	}
+	months := []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	for i, m := range months {
+		if m == month {
+			return cardExpiration(fmt.Sprint(i+1), year)
+		}
+	}
+	return v
+}
@@ -0,0 +1,301 @@
+package vault_import_formats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// OnePassword reads a 1PUX archive: a zip holding export.data (JSON) and the
+// item files under files/<documentId>__<fileName>. 1Password has no folders,
+// so each 1Password vault becomes a folder.
+type OnePassword struct{}
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Vaults []struct {
+			Attrs struct {
+				UUID string `json:"uuid"`
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	UUID         string `json:"uuid"`
+	FavIndex     int    `json:"favIndex"`
+	State        string `json:"state"`
+	CategoryUUID string `json:"categoryUuid"`
+	Details      struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain string `json:"notesPlain"`
+		Password   string `json:"password"`
+		Sections   []struct {
+			Title  string `json:"title"`
+			Fields []struct {
+				Title string                     `json:"title"`
+				ID    string                     `json:"id"`
+				Value map[string]json.RawMessage `json:"value"`
+			} `json:"fields"`
+		} `json:"sections"`
+		DocumentAttributes *onePasswordFile `json:"documentAttributes"`
+	} `json:"details"`
+	Overview struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+		Tags []string `json:"tags"`
+	} `json:"overview"`
+}
+
+type onePasswordFile struct {
+	FileName   string `json:"fileName"`
+	DocumentID string `json:"documentId"`
+}
+
+const (
+	onePasswordLogin    = "001"
+	onePasswordCard     = "002"
+	onePasswordNote     = "003"
+	onePasswordIdentity = "004"
+	onePasswordPassword = "005"
+	onePasswordDocument = "006"
+	onePasswordSSHKey   = "114"
+)
+
+func (OnePassword) Parse(data []byte, _ string) (*vault_import_domain.Batch, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	raw, err := readZipFile(files["export.data"])
+	if err != nil {
+		return nil, err
+	}
+	var export onePasswordExport
+	if err := json.Unmarshal(raw, &export); err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	}
+
+	batch := &vault_import_domain.Batch{Format: vault_import_domain.FormatOnePassword}
+	for _, account := range export.Accounts {
+		for _, vault := range account.Vaults {
+			for _, item := range vault.Items {
+				rec := onePasswordRecord(item)
+				rec.Folder = vault.Attrs.Name
+				for _, ref := range onePasswordFiles(item) {
+					content, err := readZipFile(files["files/"+ref.DocumentID+"__"+ref.FileName])
+					if err != nil {
+						rec.Warnf("attachment %q is missing from the archive", ref.FileName)
+						continue
+					}
+					rec.Attachments = append(rec.Attachments, vault_import_domain.Attachment{Name: ref.FileName, Data: content})
+				}
+				batch.Records = append(batch.Records, rec)
+			}
+		}
+	}
+	return batch, nil
+}
+
+func onePasswordRecord(item onePasswordItem) vault_import_domain.Record {
+	rec := vault_import_domain.Record{SourceID: item.UUID}
+	d := item.Details
+
+	// Section fields, keyed by their 1Password id; consumed as they are
+	// mapped and the rest kept as custom fields.
+	fields := map[string]string{}
+	titles := map[string]string{}
+	var sshKey *struct {
+		PrivateKey string `json:"privateKey"`
+		Metadata   struct {
+			PublicKey   string `json:"publicKey"`
+			Fingerprint string `json:"fingerprint"`
+		} `json:"metadata"`
+	}
+	for _, s := range d.Sections {
+		for _, f := range s.Fields {
+			if raw, ok := f.Value["sshKey"]; ok {
+				_ = json.Unmarshal(raw, &sshKey)
+				continue
+			}
+			if _, ok := f.Value["file"]; ok {
+				continue
+			}
+			value, ok := onePasswordValue(f.Value)
+			if !ok || value == "" {
+				continue
+			}
+			id := f.ID
+			if id == "" || fields[id] != "" {
+				id = f.Title
+			}
+			fields[id] = value
+			titles[id] = f.Title
+		}
+	}
+	take := func(ids ...string) string {
+		for _, id := range ids {
+			if v, ok := fields[id]; ok {
+				delete(fields, id)
+				return v
+			}
+		}
+		return ""
+	}
+
+	switch item.CategoryUUID {
+	case onePasswordLogin, onePasswordPassword:
+		login := &vaults_domain.LoginEntry{Website: item.Overview.URL, Password: d.Password}
+		for _, lf := range d.LoginFields {
+			switch lf.Designation {
+			case "username":
+				login.UserName = lf.Value
+			case "password":
+				login.Password = lf.Value
+			}
+		}
+		for _, u := range item.Overview.URLs {
+			if u.URL != login.Website {
+				setCustom(login, "uri", u.URL)
+			}
+		}
+		rec.Entry = login
+	case onePasswordCard:
+		rec.Entry = &vaults_domain.CardEntry{
+			Owner:      take("cardholder"),
+			Number:     take("ccnum"),
+			CVC:        take("cvv"),
+			Expiration: onePasswordExpiry(take("expiry")),
+			Tags:       item.Overview.Tags,
+		}
+	case onePasswordIdentity:
+		identity := &vaults_domain.IdentityEntry{
+			FirstName:       take("firstname"),
+			SecondFirstName: take("initial"),
+			LastName:        take("lastname"),
+			Company:         take("company"),
+			Mail:            take("email"),
+			Telephone:       take("defphone", "cellphone", "homephone", "busphone"),
+			Username:        take("username"),
+		}
+		identity.AddressOne = take("address")
+		rec.Entry = identity
+	case onePasswordSSHKey:
+		entry := &vaults_domain.SSHKeyEntry{}
+		if sshKey != nil {
+			entry.PrivateKey = sshKey.PrivateKey
+			entry.PublicKey = sshKey.Metadata.PublicKey
+			entry.EFingerprint = sshKey.Metadata.Fingerprint
+		} else {
+			rec.Warnf("SSH key item has no key material")
+		}
+		rec.Entry = entry
+	case onePasswordNote, onePasswordDocument:
+		rec.Entry = &vaults_domain.NoteEntry{}
+	default:
+		rec.Entry = &vaults_domain.NoteEntry{}
+		rec.Warnf("1Password category %s was imported as a secure note", item.CategoryUUID)
+	}
+
+	for _, id := range sortedKeys(fields) {
+		setCustom(rec.Entry, titles[id], fields[id])
+	}
+	stamp(rec.Entry, item.Overview.Title, d.NotesPlain, item.FavIndex > 0)
+	if item.State == "archived" {
+		rec.Entry.GetBase().Trashed = true
+		rec.Warnf("archived in 1Password; imported into the trash")
+	}
+	return rec
+}
+
+// onePasswordValue flattens a typed field value ({"string": ...},
+// {"concealed": ...}, {"email": {...}}, {"address": {...}}, ...) to text.
+func onePasswordValue(v map[string]json.RawMessage) (string, bool) {
+	for kind, raw := range v {
+		switch kind {
+		case "email":
+			var email struct {
+				Address string `json:"email_address"`
+			}
+			if json.Unmarshal(raw, &email) == nil {
+				return email.Address, true
+			}
+		case "address":
+			var a map[string]string
+			if json.Unmarshal(raw, &a) == nil {
+				parts := []string{}
+				for _, k := range []string{"street", "city", "state", "zip", "country"} {
+					if a[k] != "" {
+						parts = append(parts, a[k])
+					}
+				}
+				return strings.Join(parts, ", "), true
+			}
+		default:
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				return s, true
+			}
+			var n json.Number
+			if json.Unmarshal(raw, &n) == nil {
+				return n.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// onePasswordExpiry turns a monthYear value (202712) into "12/27".
+func onePasswordExpiry(v string) string {
+	if len(v) != 6 {
+		return v
+	}
+	return cardExpiration(v[4:], v[:4])
+}
+
+func onePasswordFiles(item onePasswordItem) []onePasswordFile {
+	var out []onePasswordFile
+	if item.Details.DocumentAttributes != nil {
+		out = append(out, *item.Details.DocumentAttributes)
+	}
+	for _, s := range item.Details.Sections {
+		for _, f := range s.Fields {
+			var ref onePasswordFile
+			if raw, ok := f.Value["file"]; ok && json.Unmarshal(raw, &ref) == nil {
+				out = append(out, ref)
+			}
+		}
+	}
+	return out
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f == nil {
+		return nil, fmt.Errorf("%w: missing archive member", vault_import_domain.ErrMalformedExport)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vault_import_domain.ErrMalformedExport, err)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,63 @@
+package vault_import_persistence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+// MemoryPreviewStore keeps pending imports in process memory only; expired
+// previews are dropped on access.
+type MemoryPreviewStore struct {
+	mu      sync.Mutex
+	pending map[string]*vault_import_domain.PendingImport
+	Now     func() time.Time
+}
+
+func NewMemoryPreviewStore() *MemoryPreviewStore {
+	return &MemoryPreviewStore{
+		pending: map[string]*vault_import_domain.PendingImport{},
+		Now:     time.Now,
+	}
+}
+
+func (s *MemoryPreviewStore) SavePending(_ context.Context, p *vault_import_domain.PendingImport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpired()
+	s.pending[p.ID] = p
+	return nil
+}
+
+func (s *MemoryPreviewStore) GetPending(_ context.Context, userID string, id string) (*vault_import_domain.PendingImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpired()
+	p, ok := s.pending[id]
+	if !ok || p.UserID != userID {
+		return nil, vault_import_domain.ErrPreviewNotFound
+	}
+	return p, nil
+}
+
+func (s *MemoryPreviewStore) DeletePending(_ context.Context, userID string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.pending[id]; ok && p.UserID == userID {
+		delete(s.pending, id)
+	}
+	return nil
+}
+
+func (s *MemoryPreviewStore) evictExpired() {
+	now := s.Now()
+	for id, p := range s.pending {
+		if !p.ExpiresAt.IsZero() && now.After(p.ExpiresAt) {
+			delete(s.pending, id)
+		}
+	}
+}
+
+var _ vault_import_domain.PreviewStore = (*MemoryPreviewStore)(nil)
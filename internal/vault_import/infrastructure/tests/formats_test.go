@@ -0,0 +1,260 @@
+package vault_import_tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	vaults_domain "vault-app/internal/vault/domain"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+	vault_import_formats "vault-app/internal/vault_import/infrastructure/formats"
+)
+
+const fixturePassword = "fixture-password"
+
+func parseFixture(t *testing.T, format vault_import_domain.Format, name string, password string) *vault_import_domain.Batch {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	parser, ok := vault_import_formats.Parsers()[format]
+	require.True(t, ok, "no parser for %s", format)
+	batch, err := parser.Parse(data, password)
+	require.NoError(t, err)
+	require.Equal(t, format, batch.Format)
+	return batch
+}
+
+func recordNamed(t *testing.T, batch *vault_import_domain.Batch, name string) vault_import_domain.Record {
+	t.Helper()
+	for _, r := range batch.Records {
+		if r.Entry.GetName() == name {
+			return r
+		}
+	}
+	t.Fatalf("no record named %q", name)
+	return vault_import_domain.Record{}
+}
+
+func TestBitwardenJSON_MapsEveryItemType(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatBitwardenJSON, "bitwarden.json", "")
+
+	require.Len(t, batch.Records, 5)
+	require.Len(t, batch.Warnings, 1, "unknown item type should be reported")
+	require.Equal(t, []string{"Personal", "Work/Infra"}, batch.Folders())
+
+	github := recordNamed(t, batch, "GitHub")
+	login, ok := github.Entry.(*vaults_domain.LoginEntry)
+	require.True(t, ok)
+	require.Equal(t, "alice", login.UserName)
+	require.Equal(t, "correct horse battery staple", login.Password)
+	require.Equal(t, "https://github.com/login", login.Website)
+	require.Equal(t, "personal account", login.AdditionnalNote)
+	require.True(t, login.IsFavorite)
+	require.Equal(t, vaults_domain.EntryLogin, login.Type)
+	require.Equal(t, "https://gist.github.com", login.CustomFields["uri"])
+	require.Equal(t, "1111-2222 3333-4444", login.CustomFields["Recovery codes"])
+	require.Contains(t, login.CustomFields, "totp")
+	require.Len(t, github.Warnings, 1, "linked field should be reported")
+
+	card := recordNamed(t, batch, "Visa").Entry.(*vaults_domain.CardEntry)
+	require.Equal(t, "Alice Martin", card.Owner)
+	require.Equal(t, "07/29", card.Expiration)
+	require.Equal(t, "737", card.CVC)
+
+	identity := recordNamed(t, batch, "Alice").Entry.(*vaults_domain.IdentityEntry)
+	require.Equal(t, "Martin", identity.LastName)
+	require.Equal(t, "X1234567", identity.IDNumber)
+	require.Equal(t, "Paris", identity.City)
+
+	key := recordNamed(t, batch, "Deploy key")
+	require.Equal(t, "Work/Infra", key.Folder)
+	ssh := key.Entry.(*vaults_domain.SSHKeyEntry)
+	require.Equal(t, "SHA256:deployfingerprint", ssh.EFingerprint)
+	require.Equal(t, "sshkey", string(ssh.Type))
+
+	note := recordNamed(t, batch, "Wi-Fi").Entry.(*vaults_domain.NoteEntry)
+	require.Equal(t, "SSID home, password on the fridge", note.AdditionnalNote)
+}
+
+func TestBitwardenJSON_RejectsEncryptedExport(t *testing.T) {
+	_, err := vault_import_formats.BitwardenJSON{}.Parse([]byte(`{"encrypted":true,"passwordProtected":true}`), "")
+	require.True(t, errors.Is(err, vault_import_domain.ErrUnsupportedEncryption), "got %v", err)
+}
+
+func TestBitwardenCSV(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatBitwardenCSV, "bitwarden.csv", "")
+
+	require.Len(t, batch.Records, 3)
+	login := recordNamed(t, batch, "GitHub").Entry.(*vaults_domain.LoginEntry)
+	require.Equal(t, "https://github.com/login", login.Website)
+	require.Equal(t, "https://gist.github.com", login.CustomFields["uri"])
+	require.Equal(t, "1111-2222 3333-4444", login.CustomFields["Recovery codes"])
+	require.True(t, login.IsFavorite)
+
+	grafana := recordNamed(t, batch, "Grafana")
+	require.Equal(t, "Work/Infra", grafana.Folder)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", grafana.Entry.GetBase().CustomFields["totp"])
+
+	_, ok := recordNamed(t, batch, "Wi-Fi").Entry.(*vaults_domain.NoteEntry)
+	require.True(t, ok)
+}
+
+func TestLastPassCSV_StructuredNotes(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatLastPass, "lastpass.csv", "")
+
+	require.Len(t, batch.Records, 6)
+	require.Equal(t, []string{"Personal", "Work/Infra"}, batch.Folders())
+
+	card := recordNamed(t, batch, "Visa")
+	cardEntry := card.Entry.(*vaults_domain.CardEntry)
+	require.Equal(t, "4111111111111111", cardEntry.Number)
+	require.Equal(t, "07/29", cardEntry.Expiration)
+	require.Equal(t, "card for travel", cardEntry.AdditionnalNote)
+
+	ssh := recordNamed(t, batch, "Deploy key").Entry.(*vaults_domain.SSHKeyEntry)
+	require.Contains(t, ssh.PrivateKey, "BEGIN OPENSSH PRIVATE KEY")
+	require.Contains(t, ssh.PrivateKey, "END OPENSSH PRIVATE KEY")
+	require.Equal(t, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeploy", ssh.PublicKey)
+	require.Equal(t, "deploy.example.com", ssh.CustomFields["Hostname"])
+
+	passport := recordNamed(t, batch, "Passport")
+	require.IsType(t, &vaults_domain.NoteEntry{}, passport.Entry)
+	require.Len(t, passport.Warnings, 1, "unmapped note type should be reported")
+	require.Equal(t, "X1234567", passport.Entry.GetBase().CustomFields["Number"])
+
+	wifi := recordNamed(t, batch, "Wi-Fi").Entry.(*vaults_domain.NoteEntry)
+	require.Equal(t, "SSID home", wifi.AdditionnalNote)
+}
+
+func TestOnePassword1PUX(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatOnePassword, "onepassword.1pux", "")
+
+	require.Len(t, batch.Records, 7)
+	require.Equal(t, []string{"Personal", "Infra"}, batch.Folders())
+
+	github := recordNamed(t, batch, "GitHub").Entry.(*vaults_domain.LoginEntry)
+	require.Equal(t, "alice", github.UserName)
+	require.Equal(t, "correct horse battery staple", github.Password)
+	require.Equal(t, "1111-2222 3333-4444", github.CustomFields["Recovery codes"])
+	require.True(t, github.IsFavorite)
+
+	card := recordNamed(t, batch, "Visa").Entry.(*vaults_domain.CardEntry)
+	require.Equal(t, "4111111111111111", card.Number)
+	require.Equal(t, "07/29", card.Expiration)
+	require.Equal(t, []string{"travel"}, card.Tags)
+
+	identity := recordNamed(t, batch, "Alice").Entry.(*vaults_domain.IdentityEntry)
+	require.Equal(t, "alice@example.org", identity.Mail)
+	require.Equal(t, "+33 1 23 45 67 89", identity.Telephone)
+	require.Contains(t, identity.AddressOne, "Paris")
+
+	doc := recordNamed(t, batch, "Passport scan")
+	require.Len(t, doc.Attachments, 1)
+	require.Equal(t, "passport.pdf", doc.Attachments[0].Name)
+	require.Equal(t, []byte("%PDF-1.4 fake"), doc.Attachments[0].Data)
+
+	key := recordNamed(t, batch, "Deploy key")
+	require.Equal(t, "Infra", key.Folder)
+	require.Equal(t, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeploy", key.Entry.(*vaults_domain.SSHKeyEntry).PublicKey)
+	require.Len(t, key.Attachments, 1)
+
+	archived := recordNamed(t, batch, "Old router")
+	require.True(t, archived.Entry.GetBase().Trashed)
+	require.Equal(t, "hunter2", archived.Entry.(*vaults_domain.LoginEntry).Password)
+
+	wifi := recordNamed(t, batch, "Wi-Fi")
+	require.Len(t, wifi.Warnings, 1, "unmapped category should be reported")
+	require.Equal(t, "wifi-pass", wifi.Entry.GetBase().CustomFields["wireless network password"])
+}
+
+func TestKeePassKDBX4_AESKDF(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatKeePass, "keepass.kdbx", fixturePassword)
+
+	require.Len(t, batch.Records, 3, "recycle bin and history entries are not imported")
+	require.Len(t, batch.Warnings, 1)
+	require.Equal(t, []string{"Work", "Work/Servers"}, batch.Folders())
+
+	github := recordNamed(t, batch, "GitHub")
+	login := github.Entry.(*vaults_domain.LoginEntry)
+	require.Equal(t, "correct horse battery staple", login.Password)
+	require.Equal(t, "1111-2222 3333-4444", login.CustomFields["Recovery codes"])
+	require.Equal(t, "dev;code", login.CustomFields["tags"])
+	require.Equal(t, "", github.Folder)
+	require.Len(t, github.Warnings, 1, "history should be reported")
+
+	onboarding := recordNamed(t, batch, "Onboarding")
+	require.IsType(t, &vaults_domain.NoteEntry{}, onboarding.Entry)
+	require.Len(t, onboarding.Attachments, 1)
+	require.Equal(t, "checklist.txt", onboarding.Attachments[0].Name)
+	require.Equal(t, "txt", onboarding.Attachments[0].Ext())
+
+	key := recordNamed(t, batch, "Deploy key")
+	require.Equal(t, "Work/Servers", key.Folder)
+	ssh := key.Entry.(*vaults_domain.SSHKeyEntry)
+	require.Contains(t, ssh.PrivateKey, "BEGIN OPENSSH PRIVATE KEY")
+	require.Equal(t, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIDeploy deploy@ci", ssh.PublicKey)
+	require.Equal(t, "key passphrase", ssh.CustomFields["passphrase"])
+	require.Empty(t, key.Attachments)
+}
+
+func TestKeePassKDBX4_Argon2dChaCha20(t *testing.T) {
+	batch := parseFixture(t, vault_import_domain.FormatKeePass, "keepass_argon2d.kdbx", fixturePassword)
+
+	require.Len(t, batch.Records, 1)
+	login := batch.Records[0].Entry.(*vaults_domain.LoginEntry)
+	require.Equal(t, "Bank", login.EntryName)
+	require.Equal(t, "s3cure-bank", login.Password)
+}
+
+// withKDFParam rewrites a uint64 KDF parameter of a KDBX 4 fixture and
+// fixes up the header checksum, which needs no key.
+func withKDFParam(t *testing.T, fixture string, name byte, value uint64) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	require.NoError(t, err)
+
+	entry := []byte{0x05, 1, 0, 0, 0, name, 8, 0, 0, 0}
+	at := bytes.Index(data, entry)
+	require.GreaterOrEqual(t, at, 0, "fixture has no %c parameter", name)
+	binary.LittleEndian.PutUint64(data[at+len(entry):], value)
+
+	end := bytes.Index(data, []byte{0x00, 4, 0, 0, 0, '\r', '\n', '\r', '\n'}) + 9
+	sum := sha256.Sum256(data[:end])
+	copy(data[end:], sum[:])
+	return data
+}
+
+func TestKeePassKDBX4_RefusesExcessiveKDFParameters(t *testing.T) {
+	for _, tc := range []struct {
+		fixture string
+		name    byte
+		value   uint64
+	}{
+		{"keepass.kdbx", 'R', 1 << 40},
+		{"keepass_argon2d.kdbx", 'M', 64 << 30},
+		{"keepass_argon2d.kdbx", 'I', 1 << 20},
+	} {
+		_, err := vault_import_formats.KeePass{}.Parse(withKDFParam(t, tc.fixture, tc.name, tc.value), fixturePassword)
+		require.ErrorIs(t, err, vault_import_domain.ErrUnsupportedEncryption, "%s %c", tc.fixture, tc.name)
+	}
+}
+
+func TestKeePassKDBX4_Credentials(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "keepass.kdbx"))
+	require.NoError(t, err)
+
+	_, err = vault_import_formats.KeePass{}.Parse(data, "wrong")
+	require.True(t, errors.Is(err, vault_import_domain.ErrInvalidCredentials), "got %v", err)
+
+	_, err = vault_import_formats.KeePass{}.Parse(data, "")
+	require.True(t, errors.Is(err, vault_import_domain.ErrPasswordRequired), "got %v", err)
+
+	_, err = vault_import_formats.KeePass{}.Parse([]byte("not a database"), fixturePassword)
+	require.True(t, errors.Is(err, vault_import_domain.ErrMalformedExport), "got %v", err)
+}
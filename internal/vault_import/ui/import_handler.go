@@ -0,0 +1,66 @@
+package vault_import_ui
+
+import (
+	"context"
+	"fmt"
+
+	vault_import_usecases "vault-app/internal/vault_import/application/usecases"
+	vault_import_domain "vault-app/internal/vault_import/domain"
+)
+
+type ImportHandler struct {
+	previewUseCase *vault_import_usecases.PreviewImportUsecase
+	commitUseCase  *vault_import_usecases.CommitImportUsecase
+}
+
+func NewImportHandler(
+	previewUC *vault_import_usecases.PreviewImportUsecase,
+	commitUC *vault_import_usecases.CommitImportUsecase,
+) *ImportHandler {
+	return &ImportHandler{
+		previewUseCase: previewUC,
+		commitUseCase:  commitUC,
+	}
+}
+
+// PreviewImport is the dry run: it parses the export and reports records,
+// duplicates, folders to create and warnings without changing the vault.
+func (h *ImportHandler) PreviewImport(ctx context.Context, userID string, format string, data []byte, password string) (*vault_import_domain.Preview, error) {
+	if h.previewUseCase == nil {
+		return nil, fmt.Errorf("preview import use case is not initialized")
+	}
+	return h.previewUseCase.Execute(ctx, vault_import_usecases.PreviewImportRequest{
+		UserID:   userID,
+		Format:   vault_import_domain.Format(format),
+		Data:     data,
+		Password: password,
+	})
+}
+
+func (h *ImportHandler) CommitImport(ctx context.Context, userID string, previewID string, skipDuplicates bool) (*vault_import_usecases.CommitImportResult, error) {
+	if h.commitUseCase == nil {
+		return nil, fmt.Errorf("commit import use case is not initialized")
+	}
+	return h.commitUseCase.Execute(ctx, vault_import_usecases.CommitImportRequest{
+		UserID:         userID,
+		PreviewID:      previewID,
+		SkipDuplicates: skipDuplicates,
+	})
+}
+
+// RecordImportedAttachment marks one attachment of an imported record as
+// stored.
+func (h *ImportHandler) RecordImportedAttachment(ctx context.Context, userID string, previewID string, sourceID string) error {
+	if h.commitUseCase == nil {
+		return fmt.Errorf("commit import use case is not initialized")
+	}
+	return h.commitUseCase.RecordAttachment(ctx, userID, previewID, sourceID)
+}
+
+// FinishImport drops a preview whose records and attachments are all stored.
+func (h *ImportHandler) FinishImport(ctx context.Context, userID string, previewID string) error {
+	if h.commitUseCase == nil {
+		return fmt.Errorf("commit import use case is not initialized")
+	}
+	return h.commitUseCase.Finish(ctx, userID, previewID)
+}
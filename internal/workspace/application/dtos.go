@@ -31,3 +31,57 @@ type ListWorkspacesRequest struct {
 	VaultID string
 }
 
+
+// ArchiveWorkspaceRequest archives or restores a workspace on behalf of
+// ActorVaultID, which must be its owner or an admin.
+type ArchiveWorkspaceRequest struct {
+	WorkspaceID  string
+	ActorVaultID string
+	Signature    string
+}
+
+type WorkspaceMemberRequest struct {
+	WorkspaceID  string
+	ActorVaultID string
+	VaultID      string
+	// Role is ignored when removing a member.
+	Role      workspace_domain.WorkspaceRole
+	Signature string
+}
+
+type MoveChannelRequest struct {
+	ChannelID       string
+	FromWorkspaceID string
+	ToWorkspaceID   string
+	ActorVaultID    string
+}
+
+// SearchWorkspacesRequest searches the channels and threads of every
+// workspace of VaultID. Each whitespace-separated term of Query must match.
+type SearchWorkspacesRequest struct {
+	VaultID         string
+	Query           string
+	IncludeArchived bool
+	// Limit caps the hits; zero means DefaultSearchLimit.
+	Limit int
+}
+
+const (
+	SearchHitChannel = "channel"
+	SearchHitThread  = "thread"
+
+	DefaultSearchLimit = 50
+)
+
+type SearchHit struct {
+	Kind          string `json:"kind"`
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceName string `json:"workspace_name"`
+	ChannelID     string `json:"channel_id"`
+	ChannelTitle  string `json:"channel_title"`
+	ThreadID      string `json:"thread_id,omitempty"`
+	Title         string `json:"title"`
+	// MatchedFields names the fields the terms were found in.
+	MatchedFields []string `json:"matched_fields"`
+	Archived      bool     `json:"archived"`
+}
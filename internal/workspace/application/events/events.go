@@ -20,4 +20,16 @@ type WorkspaceEventBus interface {
 
 	PublishWorkspaceDeleted(ctx context.Context, event workspace_domain.WorkspaceDeleted) error
 	SubscribeToWorkspaceDeleted(handler func(ctx context.Context, event workspace_domain.WorkspaceDeleted)) error
+
+	PublishWorkspaceArchived(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent) error
+	SubscribeToWorkspaceArchived(handler func(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent)) error
+
+	PublishWorkspaceRestored(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent) error
+	SubscribeToWorkspaceRestored(handler func(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent)) error
+
+	PublishWorkspaceMembershipChanged(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged) error
+	SubscribeToWorkspaceMembershipChanged(handler func(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged)) error
+
+	PublishWorkspaceChannelMoved(ctx context.Context, event workspace_domain.WorkspaceChannelMoved) error
+	SubscribeToWorkspaceChannelMoved(handler func(ctx context.Context, event workspace_domain.WorkspaceChannelMoved)) error
 }
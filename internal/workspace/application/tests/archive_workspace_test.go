@@ -0,0 +1,230 @@
+package workspace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_usecase "vault-app/internal/workspace/application/usecases"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+// c3StoreStub keeps channels and threads in memory and satisfies both
+// workspace_usecase.ChannelStore and workspace_usecase.ThreadStore.
+type c3StoreStub struct {
+	channels []channel_domain.Channel
+	threads  []thread_domain.Thread
+
+	updatedChannels []string
+	updatedThreads  []string
+}
+
+func (s *c3StoreStub) ListChannels(ctx context.Context, req *channel_domain.ListChannelsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Channel], error) {
+	channels := []channel_domain.Channel{}
+	for _, c := range s.channels {
+		if c.WorkspaceID == req.WorkspaceID {
+			channels = append(channels, c)
+		}
+	}
+	return &tracecore_types.CloudResponse[[]channel_domain.Channel]{Data: channels}, nil
+}
+
+func (s *c3StoreStub) GetChannel(ctx context.Context, req *channel_domain.GetChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	for _, c := range s.channels {
+		if c.ID == req.ChannelID {
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: c}, nil
+		}
+	}
+	return nil, channel_domain.ErrChannelNotFound
+}
+
+func (s *c3StoreStub) UpdateChannel(ctx context.Context, req *channel_domain.UpdateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error) {
+	for i := range s.channels {
+		if s.channels[i].ID == req.Channel.ID {
+			s.channels[i] = req.Channel
+			s.updatedChannels = append(s.updatedChannels, req.Channel.ID)
+			return &tracecore_types.CloudResponse[channel_domain.Channel]{Data: req.Channel}, nil
+		}
+	}
+	return nil, channel_domain.ErrChannelNotFound
+}
+
+func (s *c3StoreStub) ListThreads(ctx context.Context, req *thread_domain.ListThreadsRequest) (*tracecore_types.CloudResponse[[]thread_domain.Thread], error) {
+	threads := []thread_domain.Thread{}
+	for _, t := range s.threads {
+		if t.ChannelID == req.ChannelID {
+			threads = append(threads, t)
+		}
+	}
+	return &tracecore_types.CloudResponse[[]thread_domain.Thread]{Data: threads}, nil
+}
+
+func (s *c3StoreStub) UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error) {
+	for i := range s.threads {
+		if s.threads[i].ID == req.Thread.ID {
+			s.threads[i] = req.Thread
+			s.updatedThreads = append(s.updatedThreads, req.Thread.ID)
+			return &tracecore_types.CloudResponse[thread_domain.Thread]{Data: req.Thread}, nil
+		}
+	}
+	return nil, errors.New("thread not found")
+}
+
+func (s *c3StoreStub) channel(id string) channel_domain.Channel {
+	for _, c := range s.channels {
+		if c.ID == id {
+			return c
+		}
+	}
+	return channel_domain.Channel{}
+}
+
+// workspaceRepoWith serves the given workspaces and keeps updates.
+func workspaceRepoWith(workspaces ...*workspace_domain.Workspace) *workspaceRepositoryMock {
+	byID := map[string]*workspace_domain.Workspace{}
+	for _, ws := range workspaces {
+		byID[ws.ID] = ws
+	}
+	return &workspaceRepositoryMock{
+		getFn: func(ctx context.Context, req workspace_domain.GetRequest) (*workspace_domain.Workspace, error) {
+			ws, ok := byID[req.WorkspaceID]
+			if !ok {
+				return nil, errors.New("workspace not found")
+			}
+			copied := *ws
+			return &copied, nil
+		},
+		updateFn: func(ctx context.Context, req workspace_domain.UpdateRequest) (*tracecore_types.CloudResponse[workspace_domain.Workspace], error) {
+			updated := req.Workspace
+			byID[updated.ID] = &updated
+			return &tracecore_types.CloudResponse[workspace_domain.Workspace]{Data: updated}, nil
+		},
+		listFn: func(ctx context.Context, req workspace_domain.ListRequest) ([]workspace_domain.Workspace, error) {
+			res := []workspace_domain.Workspace{}
+			for _, ws := range workspaces {
+				if current := byID[ws.ID]; current.VaultID == req.VaultID {
+					res = append(res, *current)
+				}
+			}
+			return res, nil
+		},
+	}
+}
+
+func storedWorkspace(id string, name string) *workspace_domain.Workspace {
+	ws := NewWorkspace("vault-123", name, "", "user-123")
+	ws.ID = id
+	return &ws
+}
+
+func workspaceChannel(id string, workspaceID string, title string, status channel_domain.ChannelStatus) channel_domain.Channel {
+	channel := channel_domain.NewChannel("tpl", title, workspaceID)
+	channel.ID = id
+	channel.Status = status
+	return channel
+}
+
+func archiveRequest(workspaceID string, actor string) *workspace_application.ArchiveWorkspaceRequest {
+	return &workspace_application.ArchiveWorkspaceRequest{
+		WorkspaceID:  workspaceID,
+		ActorVaultID: actor,
+		Signature:    "sig",
+	}
+}
+
+func TestArchiveWorkspaceUsecase_CascadesToActiveChannels(t *testing.T) {
+	ctx := context.Background()
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"))
+	store := &c3StoreStub{channels: []channel_domain.Channel{
+		workspaceChannel("ch-active", "ws-1", "Shipments", channel_domain.StatusActive),
+		workspaceChannel("ch-archived", "ws-1", "Old shipments", channel_domain.StatusArchived),
+		workspaceChannel("ch-other", "ws-2", "Elsewhere", channel_domain.StatusActive),
+	}}
+	bus := &workspaceEventBusMock{}
+
+	archive := workspace_usecase.NewArchiveWorkspaceUsecase(repo, bus, store)
+	ws, err := archive.Execute(ctx, archiveRequest("ws-1", "vault-123"))
+	require.NoError(t, err)
+	require.Equal(t, workspace_domain.WorkspaceArchived, ws.Status)
+	require.NotNil(t, ws.ArchivedAt)
+
+	require.Equal(t, []string{"ch-active"}, store.updatedChannels)
+	require.Equal(t, channel_domain.StatusArchived, store.channel("ch-active").Status)
+	require.Equal(t, channel_domain.StatusActive, store.channel("ch-other").Status)
+	require.Len(t, bus.publishedArchivedEvents, 1)
+	require.Equal(t, []string{"ch-active"}, bus.publishedArchivedEvents[0].ChannelIDs)
+
+	_, err = archive.Execute(ctx, archiveRequest("ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceArchived)
+
+	require.EqualError(t, ws.Rename("renamed"), workspace_domain.ErrWorkspaceArchived)
+}
+
+func TestRestoreWorkspaceUsecase_RevivesOnlyCascadedChannels(t *testing.T) {
+	ctx := context.Background()
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"))
+	store := &c3StoreStub{channels: []channel_domain.Channel{
+		workspaceChannel("ch-active", "ws-1", "Shipments", channel_domain.StatusActive),
+		workspaceChannel("ch-archived", "ws-1", "Old shipments", channel_domain.StatusArchived),
+	}}
+	bus := &workspaceEventBusMock{}
+
+	restore := workspace_usecase.NewRestoreWorkspaceUsecase(repo, bus, store)
+	_, err := restore.Execute(ctx, archiveRequest("ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceNotArchived)
+
+	_, err = workspace_usecase.NewArchiveWorkspaceUsecase(repo, bus, store).Execute(ctx, archiveRequest("ws-1", "vault-123"))
+	require.NoError(t, err)
+
+	ws, err := restore.Execute(ctx, archiveRequest("ws-1", "vault-123"))
+	require.NoError(t, err)
+	require.Equal(t, workspace_domain.WorkspaceActive, ws.Status)
+	require.Nil(t, ws.ArchivedAt)
+
+	revived := store.channel("ch-active")
+	require.Equal(t, channel_domain.StatusActive, revived.Status)
+	_, marked := revived.Property(channel_domain.WorkspaceArchivedProperty)
+	require.False(t, marked)
+	require.Equal(t, channel_domain.StatusArchived, store.channel("ch-archived").Status)
+	require.Len(t, bus.publishedRestoredEvents, 1)
+	require.Equal(t, []string{"ch-active"}, bus.publishedRestoredEvents[0].ChannelIDs)
+}
+
+func TestArchiveWorkspaceUsecase_RequiresManager(t *testing.T) {
+	ws := storedWorkspace("ws-1", "Logistics")
+	require.NoError(t, ws.AddMember("vault-member", workspace_domain.WorkspaceRoleMember, "vault-123"))
+	require.NoError(t, ws.AddMember("vault-admin", workspace_domain.WorkspaceRoleAdmin, "vault-123"))
+	repo := workspaceRepoWith(ws)
+	store := &c3StoreStub{}
+	bus := &workspaceEventBusMock{}
+	archive := workspace_usecase.NewArchiveWorkspaceUsecase(repo, bus, store)
+
+	_, err := archive.Execute(context.Background(), archiveRequest("ws-1", "vault-member"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspacePermissionDenied)
+	_, err = archive.Execute(context.Background(), archiveRequest("ws-1", "vault-stranger"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspacePermissionDenied)
+	require.Empty(t, bus.publishedArchivedEvents)
+
+	_, err = archive.Execute(context.Background(), archiveRequest("ws-1", "vault-admin"))
+	require.NoError(t, err)
+}
+
+func TestArchiveWorkspaceUsecase_ValidateDependencies(t *testing.T) {
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"))
+	bus := &workspaceEventBusMock{}
+
+	_, err := workspace_usecase.NewArchiveWorkspaceUsecase(nil, bus, &c3StoreStub{}).Execute(context.Background(), archiveRequest("ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrRepositoryNil)
+
+	_, err = workspace_usecase.NewArchiveWorkspaceUsecase(repo, nil, &c3StoreStub{}).Execute(context.Background(), archiveRequest("ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceBusRequired)
+
+	_, err = workspace_usecase.NewArchiveWorkspaceUsecase(repo, bus, nil).Execute(context.Background(), archiveRequest("ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrChannelStoreRequired)
+}
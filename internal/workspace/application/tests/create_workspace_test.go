@@ -124,6 +124,11 @@ type workspaceEventBusMock struct {
 
 	publishedCreatedEvents []workspace_domain.WorkspaceCreated
 	publishedDeletedEvents []workspace_domain.WorkspaceDeleted
+
+	publishedArchivedEvents          []workspace_domain.WorkspaceArchivedEvent
+	publishedRestoredEvents          []workspace_domain.WorkspaceRestoredEvent
+	publishedMembershipChangedEvents []workspace_domain.WorkspaceMembershipChanged
+	publishedChannelMovedEvents      []workspace_domain.WorkspaceChannelMoved
 }
 
 func (m *workspaceEventBusMock) PublishWorkspaceCreated(ctx context.Context, event workspace_domain.WorkspaceCreated) error {
@@ -177,6 +182,38 @@ func (m *workspaceEventBusMock) SubscribeToWorkspaceDeleted(handler func(ctx con
 	return nil
 }
 
+func (m *workspaceEventBusMock) PublishWorkspaceArchived(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent) error {
+	m.publishedArchivedEvents = append(m.publishedArchivedEvents, event)
+	return nil
+}
+func (m *workspaceEventBusMock) SubscribeToWorkspaceArchived(handler func(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent)) error {
+	return nil
+}
+
+func (m *workspaceEventBusMock) PublishWorkspaceRestored(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent) error {
+	m.publishedRestoredEvents = append(m.publishedRestoredEvents, event)
+	return nil
+}
+func (m *workspaceEventBusMock) SubscribeToWorkspaceRestored(handler func(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent)) error {
+	return nil
+}
+
+func (m *workspaceEventBusMock) PublishWorkspaceMembershipChanged(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged) error {
+	m.publishedMembershipChangedEvents = append(m.publishedMembershipChangedEvents, event)
+	return nil
+}
+func (m *workspaceEventBusMock) SubscribeToWorkspaceMembershipChanged(handler func(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged)) error {
+	return nil
+}
+
+func (m *workspaceEventBusMock) PublishWorkspaceChannelMoved(ctx context.Context, event workspace_domain.WorkspaceChannelMoved) error {
+	m.publishedChannelMovedEvents = append(m.publishedChannelMovedEvents, event)
+	return nil
+}
+func (m *workspaceEventBusMock) SubscribeToWorkspaceChannelMoved(handler func(ctx context.Context, event workspace_domain.WorkspaceChannelMoved)) error {
+	return nil
+}
+
 func validCreateWorkspaceRequest() *workspace_application.CreateWorkspaceRequest {
 	return &workspace_application.CreateWorkspaceRequest{
 		VaultID:     "vault-001",
@@ -0,0 +1,82 @@
+package workspace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_domain "vault-app/internal/thread/domain"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_usecase "vault-app/internal/workspace/application/usecases"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+func moveRequest(from string, to string, actor string) *workspace_application.MoveChannelRequest {
+	return &workspace_application.MoveChannelRequest{
+		ChannelID:       "ch-1",
+		FromWorkspaceID: from,
+		ToWorkspaceID:   to,
+		ActorVaultID:    actor,
+	}
+}
+
+func moveFixture() (*workspaceRepositoryMock, *c3StoreStub, *workspace_domain.Workspace) {
+	target := storedWorkspace("ws-2", "Finance")
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"), target)
+
+	withWorkspace := thread_domain.NewThread("ch-1", "invoice", "Invoice 42", "")
+	withWorkspace.WorkspaceID = "ws-1"
+	legacy := thread_domain.NewThread("ch-1", "invoice", "Invoice 41", "")
+	store := &c3StoreStub{
+		channels: []channel_domain.Channel{workspaceChannel("ch-1", "ws-1", "Invoices", channel_domain.StatusActive)},
+		threads:  []thread_domain.Thread{withWorkspace, legacy},
+	}
+	return repo, store, target
+}
+
+func TestMoveChannelUsecase_MovesChannelAndThreads(t *testing.T) {
+	repo, store, _ := moveFixture()
+	bus := &workspaceEventBusMock{}
+
+	channel, err := workspace_usecase.NewMoveChannelUsecase(repo, bus, store, store).Execute(context.Background(), moveRequest("ws-1", "ws-2", "vault-123"))
+	require.NoError(t, err)
+	require.Equal(t, "ws-2", channel.WorkspaceID)
+	require.Equal(t, "ws-2", store.channel("ch-1").WorkspaceID)
+
+	require.Len(t, store.updatedThreads, 1, "threads without a workspace follow their channel implicitly")
+	require.Equal(t, "ws-2", store.threads[0].WorkspaceID)
+
+	require.Len(t, bus.publishedChannelMovedEvents, 1)
+	moved := bus.publishedChannelMovedEvents[0]
+	require.Equal(t, "ws-1", moved.FromWorkspaceID)
+	require.Equal(t, "ws-2", moved.ToWorkspaceID)
+	require.Equal(t, []string{store.threads[0].ID}, moved.ThreadIDs)
+}
+
+func TestMoveChannelUsecase_Rejections(t *testing.T) {
+	ctx := context.Background()
+	bus := &workspaceEventBusMock{}
+
+	repo, store, _ := moveFixture()
+	_, err := workspace_usecase.NewMoveChannelUsecase(repo, bus, store, store).Execute(ctx, moveRequest("ws-2", "ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrChannelNotInWorkspace)
+
+	_, err = workspace_usecase.NewMoveChannelUsecase(repo, bus, store, store).Execute(ctx, moveRequest("ws-1", "ws-1", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrChannelNotInWorkspace)
+
+	_, err = workspace_usecase.NewMoveChannelUsecase(repo, bus, store, store).Execute(ctx, moveRequest("ws-1", "ws-2", "vault-stranger"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspacePermissionDenied)
+
+	repo, store, target := moveFixture()
+	require.NoError(t, target.Archive())
+	_, err = workspace_usecase.NewMoveChannelUsecase(repo, bus, store, store).Execute(ctx, moveRequest("ws-1", "ws-2", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceArchived)
+
+	_, err = workspace_usecase.NewMoveChannelUsecase(repo, bus, store, nil).Execute(ctx, moveRequest("ws-1", "ws-2", "vault-123"))
+	require.EqualError(t, err, workspace_domain.ErrThreadStoreRequired)
+
+	require.Empty(t, bus.publishedChannelMovedEvents)
+	require.Empty(t, store.updatedChannels)
+}
@@ -0,0 +1,80 @@
+package workspace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_domain "vault-app/internal/thread/domain"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_usecase "vault-app/internal/workspace/application/usecases"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+func searchFixture(t *testing.T) *workspace_usecase.SearchWorkspacesUsecase {
+	archivedWs := storedWorkspace("ws-3", "Legacy")
+	require.NoError(t, archivedWs.Archive())
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"), storedWorkspace("ws-2", "Finance"), archivedWs)
+
+	shipments := workspaceChannel("ch-ship", "ws-1", "Shipments", channel_domain.StatusActive)
+	shipments.Properties = []channel_domain.ChannelProperty{{Key: "carrier", Value: "Maersk Line"}}
+	invoices := workspaceChannel("ch-inv", "ws-2", "Supplier invoices", channel_domain.StatusActive)
+	legacy := workspaceChannel("ch-old", "ws-3", "Old shipments", channel_domain.StatusArchived)
+
+	store := &c3StoreStub{
+		channels: []channel_domain.Channel{shipments, invoices, legacy},
+		threads: []thread_domain.Thread{
+			thread_domain.NewThread("ch-inv", "invoice", "Invoice 42", "Maersk freight"),
+			thread_domain.NewThread("ch-ship", "container", "MSKU 1234", ""),
+		},
+	}
+	return workspace_usecase.NewSearchWorkspacesUsecase(repo, store, store)
+}
+
+func TestSearchWorkspacesUsecase_MatchesAcrossWorkspaces(t *testing.T) {
+	search := searchFixture(t)
+
+	hits, err := search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "MAERSK"})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+
+	require.Equal(t, workspace_application.SearchHitChannel, hits[0].Kind)
+	require.Equal(t, "Logistics", hits[0].WorkspaceName)
+	require.Equal(t, []string{"property.carrier"}, hits[0].MatchedFields)
+
+	require.Equal(t, workspace_application.SearchHitThread, hits[1].Kind)
+	require.Equal(t, "ws-2", hits[1].WorkspaceID)
+	require.Equal(t, "Supplier invoices", hits[1].ChannelTitle)
+	require.Equal(t, []string{"subtitle"}, hits[1].MatchedFields)
+
+	hits, err = search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "invoice freight"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1, "every term must match")
+	require.Equal(t, "Invoice 42", hits[0].Title)
+}
+
+func TestSearchWorkspacesUsecase_RanksTitlesAndSkipsArchived(t *testing.T) {
+	search := searchFixture(t)
+
+	hits, err := search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "shipments"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.False(t, hits[0].Archived)
+
+	hits, err = search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "shipments", IncludeArchived: true})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	require.True(t, hits[1].Archived)
+
+	hits, err = search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "s", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	for _, hit := range hits {
+		require.Contains(t, hit.MatchedFields, "title")
+	}
+
+	_, err = search.Execute(context.Background(), &workspace_application.SearchWorkspacesRequest{VaultID: "vault-123", Query: "  "})
+	require.EqualError(t, err, workspace_domain.ErrSearchQueryRequired)
+}
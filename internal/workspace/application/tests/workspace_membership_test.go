@@ -0,0 +1,86 @@
+package workspace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_usecase "vault-app/internal/workspace/application/usecases"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+func memberRequest(actor string, vaultID string, role workspace_domain.WorkspaceRole) *workspace_application.WorkspaceMemberRequest {
+	return &workspace_application.WorkspaceMemberRequest{
+		WorkspaceID:  "ws-1",
+		ActorVaultID: actor,
+		VaultID:      vaultID,
+		Role:         role,
+		Signature:    "sig",
+	}
+}
+
+func TestWorkspaceMembershipUsecase_AddChangeRemove(t *testing.T) {
+	ctx := context.Background()
+	repo := workspaceRepoWith(storedWorkspace("ws-1", "Logistics"))
+	bus := &workspaceEventBusMock{}
+	uc := workspace_usecase.NewWorkspaceMembershipUsecase(repo, bus)
+
+	ws, err := uc.AddMember(ctx, memberRequest("vault-123", "vault-a", workspace_domain.WorkspaceRoleAdmin))
+	require.NoError(t, err)
+	require.Len(t, ws.Members, 1)
+	require.Equal(t, "vault-123", ws.Members[0].AddedBy)
+
+	_, err = uc.AddMember(ctx, memberRequest("vault-123", "vault-a", workspace_domain.WorkspaceRoleMember))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceMemberExists)
+
+	// Admins manage membership too.
+	_, err = uc.AddMember(ctx, memberRequest("vault-a", "vault-b", workspace_domain.WorkspaceRoleViewer))
+	require.NoError(t, err)
+
+	ws, err = uc.SetMemberRole(ctx, memberRequest("vault-a", "vault-b", workspace_domain.WorkspaceRoleMember))
+	require.NoError(t, err)
+	role, ok := ws.RoleOf("vault-b")
+	require.True(t, ok)
+	require.Equal(t, workspace_domain.WorkspaceRoleMember, role)
+
+	ws, err = uc.RemoveMember(ctx, memberRequest("vault-123", "vault-b", ""))
+	require.NoError(t, err)
+	require.False(t, ws.CanView("vault-b"))
+
+	require.Len(t, bus.publishedMembershipChangedEvents, 4)
+	changed := bus.publishedMembershipChangedEvents[2]
+	require.Equal(t, workspace_domain.MembershipRoleChanged, changed.Change)
+	require.Equal(t, workspace_domain.WorkspaceRoleViewer, changed.PreviousRole)
+	require.Equal(t, workspace_domain.WorkspaceRoleMember, changed.Role)
+	removed := bus.publishedMembershipChangedEvents[3]
+	require.Equal(t, workspace_domain.MembershipRemoved, removed.Change)
+	require.Equal(t, workspace_domain.WorkspaceRoleMember, removed.PreviousRole)
+}
+
+func TestWorkspaceMembershipUsecase_Rejections(t *testing.T) {
+	ctx := context.Background()
+	ws := storedWorkspace("ws-1", "Logistics")
+	require.NoError(t, ws.AddMember("vault-viewer", workspace_domain.WorkspaceRoleViewer, "vault-123"))
+	bus := &workspaceEventBusMock{}
+	uc := workspace_usecase.NewWorkspaceMembershipUsecase(workspaceRepoWith(ws), bus)
+
+	_, err := uc.AddMember(ctx, memberRequest("vault-viewer", "vault-a", workspace_domain.WorkspaceRoleMember))
+	require.EqualError(t, err, workspace_domain.ErrWorkspacePermissionDenied)
+
+	_, err = uc.AddMember(ctx, memberRequest("vault-123", "vault-a", workspace_domain.WorkspaceRoleOwner))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceRoleInvalid)
+
+	_, err = uc.SetMemberRole(ctx, memberRequest("vault-123", "vault-123", workspace_domain.WorkspaceRoleViewer))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceOwnerImmutable)
+
+	_, err = uc.RemoveMember(ctx, memberRequest("vault-123", "vault-ghost", ""))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceMemberNotFound)
+	require.Empty(t, bus.publishedMembershipChangedEvents)
+
+	require.NoError(t, ws.Archive())
+	uc = workspace_usecase.NewWorkspaceMembershipUsecase(workspaceRepoWith(ws), bus)
+	_, err = uc.AddMember(ctx, memberRequest("vault-123", "vault-a", workspace_domain.WorkspaceRoleMember))
+	require.EqualError(t, err, workspace_domain.ErrWorkspaceArchived)
+}
@@ -0,0 +1,197 @@
+package workspace_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	channel_domain "vault-app/internal/channel/domain"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_events "vault-app/internal/workspace/application/events"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+// loadManagedWorkspace fetches the workspace and checks the actor may manage
+// it.
+func loadManagedWorkspace(ctx context.Context, repo workspace_domain.Repository, workspaceID string, actorVaultID string) (*workspace_domain.Workspace, error) {
+	if workspaceID == "" {
+		return nil, errors.New(workspace_domain.ErrWorkspaceIDRequired)
+	}
+	ws, err := repo.GetWorkspace(ctx, workspace_domain.GetRequest{WorkspaceID: workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	if ws == nil {
+		return nil, errors.New(workspace_domain.ErrRepositoryResponse)
+	}
+	if !ws.CanManage(actorVaultID) {
+		return nil, errors.New(workspace_domain.ErrWorkspacePermissionDenied)
+	}
+	return ws, nil
+}
+
+// -------- ARCHIVE --------
+
+// ArchiveWorkspaceUsecase retires a workspace. Its active channels are
+// archived first, which makes their threads read-only; channels already
+// archived or revoked keep their status. The workspace is written last so a
+// failed cascade can simply be retried.
+type ArchiveWorkspaceUsecase struct {
+	Repo      workspace_domain.Repository
+	DomainBus workspace_events.WorkspaceEventBus
+	Channels  ChannelStore
+}
+
+func NewArchiveWorkspaceUsecase(repo workspace_domain.Repository, workspaceBus workspace_events.WorkspaceEventBus, channels ChannelStore) *ArchiveWorkspaceUsecase {
+	return &ArchiveWorkspaceUsecase{
+		Repo:      repo,
+		DomainBus: workspaceBus,
+		Channels:  channels,
+	}
+}
+
+func (c *ArchiveWorkspaceUsecase) Execute(ctx context.Context, req *workspace_application.ArchiveWorkspaceRequest) (*workspace_domain.Workspace, error) {
+	if err := validateCascadeDependencies(c.Repo, c.DomainBus, c.Channels); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, errors.New(workspace_domain.ErrRequestRequired)
+	}
+
+	ws, err := loadManagedWorkspace(ctx, c.Repo, req.WorkspaceID, req.ActorVaultID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ws.Archive(); err != nil {
+		return nil, err
+	}
+
+	channels, err := listWorkspaceChannels(ctx, c.Channels, ws.ID)
+	if err != nil {
+		return nil, err
+	}
+	archived := []string{}
+	for _, channel := range channels {
+		if !channel.ArchiveWithWorkspace() {
+			continue
+		}
+		if _, err := c.Channels.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel}); err != nil {
+			return nil, fmt.Errorf("failed to archive channel %s: %w", channel.ID, err)
+		}
+		archived = append(archived, channel.ID)
+	}
+
+	updated, err := c.Repo.UpdateWorkspace(ctx, workspace_domain.UpdateRequest{
+		UserID:    ws.OwnerID,
+		VaultID:   ws.VaultID,
+		Workspace: *ws,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errEvent := c.DomainBus.PublishWorkspaceArchived(ctx, workspace_domain.WorkspaceArchivedEvent{
+		EventID:        uuid.NewString(),
+		EventTimestamp: time.Now(),
+		WorkspaceID:    ws.ID,
+		VaultID:        ws.VaultID,
+		ActorVaultID:   req.ActorVaultID,
+		ChannelIDs:     archived,
+	})
+	if errEvent != nil {
+		return nil, errEvent
+	}
+
+	return &updated.Data, nil
+}
+
+// -------- RESTORE --------
+
+// RestoreWorkspaceUsecase reactivates an archived workspace and the channels
+// its archive archived. Channels archived on their own stay archived.
+type RestoreWorkspaceUsecase struct {
+	Repo      workspace_domain.Repository
+	DomainBus workspace_events.WorkspaceEventBus
+	Channels  ChannelStore
+}
+
+func NewRestoreWorkspaceUsecase(repo workspace_domain.Repository, workspaceBus workspace_events.WorkspaceEventBus, channels ChannelStore) *RestoreWorkspaceUsecase {
+	return &RestoreWorkspaceUsecase{
+		Repo:      repo,
+		DomainBus: workspaceBus,
+		Channels:  channels,
+	}
+}
+
+func (c *RestoreWorkspaceUsecase) Execute(ctx context.Context, req *workspace_application.ArchiveWorkspaceRequest) (*workspace_domain.Workspace, error) {
+	if err := validateCascadeDependencies(c.Repo, c.DomainBus, c.Channels); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, errors.New(workspace_domain.ErrRequestRequired)
+	}
+
+	ws, err := loadManagedWorkspace(ctx, c.Repo, req.WorkspaceID, req.ActorVaultID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ws.Restore(); err != nil {
+		return nil, err
+	}
+
+	channels, err := listWorkspaceChannels(ctx, c.Channels, ws.ID)
+	if err != nil {
+		return nil, err
+	}
+	restored := []string{}
+	for _, channel := range channels {
+		if !channel.RestoreWithWorkspace() {
+			continue
+		}
+		if _, err := c.Channels.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel}); err != nil {
+			return nil, fmt.Errorf("failed to restore channel %s: %w", channel.ID, err)
+		}
+		restored = append(restored, channel.ID)
+	}
+
+	updated, err := c.Repo.UpdateWorkspace(ctx, workspace_domain.UpdateRequest{
+		UserID:    ws.OwnerID,
+		VaultID:   ws.VaultID,
+		Workspace: *ws,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errEvent := c.DomainBus.PublishWorkspaceRestored(ctx, workspace_domain.WorkspaceRestoredEvent{
+		EventID:        uuid.NewString(),
+		EventTimestamp: time.Now(),
+		WorkspaceID:    ws.ID,
+		VaultID:        ws.VaultID,
+		ActorVaultID:   req.ActorVaultID,
+		ChannelIDs:     restored,
+	})
+	if errEvent != nil {
+		return nil, errEvent
+	}
+
+	return &updated.Data, nil
+}
+
+func validateCascadeDependencies(repo workspace_domain.Repository, bus workspace_events.WorkspaceEventBus, channels ChannelStore) error {
+	if repo == nil {
+		return errors.New(workspace_domain.ErrRepositoryNil)
+	}
+	if bus == nil {
+		return errors.New(workspace_domain.ErrWorkspaceBusRequired)
+	}
+	if channels == nil {
+		return errors.New(workspace_domain.ErrChannelStoreRequired)
+	}
+	return nil
+}
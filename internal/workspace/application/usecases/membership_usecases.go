@@ -0,0 +1,102 @@
+package workspace_usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_events "vault-app/internal/workspace/application/events"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+// WorkspaceMembershipUsecase adds, re-roles and removes workspace members.
+// Only the owner and admins may change membership, and every change is
+// published for audit.
+type WorkspaceMembershipUsecase struct {
+	Repo      workspace_domain.Repository
+	DomainBus workspace_events.WorkspaceEventBus
+}
+
+func NewWorkspaceMembershipUsecase(repo workspace_domain.Repository, workspaceBus workspace_events.WorkspaceEventBus) *WorkspaceMembershipUsecase {
+	return &WorkspaceMembershipUsecase{
+		Repo:      repo,
+		DomainBus: workspaceBus,
+	}
+}
+
+func (c *WorkspaceMembershipUsecase) AddMember(ctx context.Context, req *workspace_application.WorkspaceMemberRequest) (*workspace_domain.Workspace, error) {
+	return c.change(ctx, req, workspace_domain.MembershipAdded, func(ws *workspace_domain.Workspace) (workspace_domain.WorkspaceRole, error) {
+		return "", ws.AddMember(req.VaultID, req.Role, req.ActorVaultID)
+	})
+}
+
+func (c *WorkspaceMembershipUsecase) SetMemberRole(ctx context.Context, req *workspace_application.WorkspaceMemberRequest) (*workspace_domain.Workspace, error) {
+	return c.change(ctx, req, workspace_domain.MembershipRoleChanged, func(ws *workspace_domain.Workspace) (workspace_domain.WorkspaceRole, error) {
+		return ws.SetMemberRole(req.VaultID, req.Role)
+	})
+}
+
+func (c *WorkspaceMembershipUsecase) RemoveMember(ctx context.Context, req *workspace_application.WorkspaceMemberRequest) (*workspace_domain.Workspace, error) {
+	return c.change(ctx, req, workspace_domain.MembershipRemoved, func(ws *workspace_domain.Workspace) (workspace_domain.WorkspaceRole, error) {
+		return ws.RemoveMember(req.VaultID)
+	})
+}
+
+func (c *WorkspaceMembershipUsecase) change(
+	ctx context.Context,
+	req *workspace_application.WorkspaceMemberRequest,
+	change string,
+	apply func(ws *workspace_domain.Workspace) (workspace_domain.WorkspaceRole, error),
+) (*workspace_domain.Workspace, error) {
+	if c.Repo == nil {
+		return nil, errors.New(workspace_domain.ErrRepositoryNil)
+	}
+	if c.DomainBus == nil {
+		return nil, errors.New(workspace_domain.ErrWorkspaceBusRequired)
+	}
+	if req == nil {
+		return nil, errors.New(workspace_domain.ErrRequestRequired)
+	}
+
+	ws, err := loadManagedWorkspace(ctx, c.Repo, req.WorkspaceID, req.ActorVaultID)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := apply(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := c.Repo.UpdateWorkspace(ctx, workspace_domain.UpdateRequest{
+		UserID:    ws.OwnerID,
+		VaultID:   ws.VaultID,
+		Workspace: *ws,
+		Signature: req.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if change == workspace_domain.MembershipRemoved {
+		role = ""
+	}
+	errEvent := c.DomainBus.PublishWorkspaceMembershipChanged(ctx, workspace_domain.WorkspaceMembershipChanged{
+		EventID:        uuid.NewString(),
+		EventTimestamp: time.Now(),
+		WorkspaceID:    ws.ID,
+		ActorVaultID:   req.ActorVaultID,
+		VaultID:        req.VaultID,
+		Change:         change,
+		Role:           role,
+		PreviousRole:   previous,
+	})
+	if errEvent != nil {
+		return nil, errEvent
+	}
+
+	return &updated.Data, nil
+}
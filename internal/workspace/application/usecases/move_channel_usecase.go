@@ -0,0 +1,133 @@
+package workspace_usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_domain "vault-app/internal/thread/domain"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_events "vault-app/internal/workspace/application/events"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+// MoveChannelUsecase moves a channel, and the threads recorded under it, to
+// another workspace of the same vault. The actor must manage both
+// workspaces and neither may be archived.
+type MoveChannelUsecase struct {
+	Repo      workspace_domain.Repository
+	DomainBus workspace_events.WorkspaceEventBus
+	Channels  ChannelStore
+	Threads   ThreadStore
+}
+
+func NewMoveChannelUsecase(repo workspace_domain.Repository, workspaceBus workspace_events.WorkspaceEventBus, channels ChannelStore, threads ThreadStore) *MoveChannelUsecase {
+	return &MoveChannelUsecase{
+		Repo:      repo,
+		DomainBus: workspaceBus,
+		Channels:  channels,
+		Threads:   threads,
+	}
+}
+
+func (c *MoveChannelUsecase) Execute(ctx context.Context, req *workspace_application.MoveChannelRequest) (*channel_domain.Channel, error) {
+	if err := validateCascadeDependencies(c.Repo, c.DomainBus, c.Channels); err != nil {
+		return nil, err
+	}
+	if c.Threads == nil {
+		return nil, errors.New(workspace_domain.ErrThreadStoreRequired)
+	}
+	if err := c.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	from, err := loadManagedWorkspace(ctx, c.Repo, req.FromWorkspaceID, req.ActorVaultID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := loadManagedWorkspace(ctx, c.Repo, req.ToWorkspaceID, req.ActorVaultID)
+	if err != nil {
+		return nil, err
+	}
+	if from.IsArchived() || to.IsArchived() {
+		return nil, errors.New(workspace_domain.ErrWorkspaceArchived)
+	}
+	if from.VaultID != to.VaultID {
+		return nil, fmt.Errorf("%s: workspaces belong to different vaults", workspace_domain.ErrWorkspacePermissionDenied)
+	}
+
+	resp, err := c.Channels.GetChannel(ctx, &channel_domain.GetChannelRequest{ChannelID: req.ChannelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, errors.New(workspace_domain.ErrRepositoryResponse)
+	}
+	channel := resp.Data
+	if channel.WorkspaceID != from.ID {
+		return nil, errors.New(workspace_domain.ErrChannelNotInWorkspace)
+	}
+	if err := channel.MoveToWorkspace(to.ID); err != nil {
+		return nil, err
+	}
+
+	moved, err := c.Channels.UpdateChannel(ctx, &channel_domain.UpdateChannelRequest{Channel: channel})
+	if err != nil {
+		return nil, err
+	}
+	if moved != nil && moved.Data.ID != "" {
+		channel = moved.Data
+	}
+
+	// Threads carry their workspace for listing; re-home the ones that do.
+	threads, err := listChannelThreads(ctx, c.Threads, channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	threadIDs := []string{}
+	for _, thread := range threads {
+		if thread.WorkspaceID == "" || thread.WorkspaceID == to.ID {
+			continue
+		}
+		thread.WorkspaceID = to.ID
+		if _, err := c.Threads.UpdateThread(ctx, &thread_domain.UpdateThreadRequest{Thread: thread}); err != nil {
+			return nil, fmt.Errorf("failed to move thread %s: %w", thread.ID, err)
+		}
+		threadIDs = append(threadIDs, thread.ID)
+	}
+
+	errEvent := c.DomainBus.PublishWorkspaceChannelMoved(ctx, workspace_domain.WorkspaceChannelMoved{
+		EventID:         uuid.NewString(),
+		EventTimestamp:  time.Now(),
+		ChannelID:       channel.ID,
+		FromWorkspaceID: from.ID,
+		ToWorkspaceID:   to.ID,
+		ActorVaultID:    req.ActorVaultID,
+		ThreadIDs:       threadIDs,
+	})
+	if errEvent != nil {
+		return nil, errEvent
+	}
+
+	return &channel, nil
+}
+
+func (c *MoveChannelUsecase) ValidateRequest(req *workspace_application.MoveChannelRequest) error {
+	if req == nil {
+		return errors.New(workspace_domain.ErrRequestRequired)
+	}
+	if req.ChannelID == "" {
+		return errors.New(workspace_domain.ErrChannelIDRequired)
+	}
+	if req.FromWorkspaceID == "" || req.ToWorkspaceID == "" {
+		return errors.New(workspace_domain.ErrWorkspaceIDRequired)
+	}
+	if req.FromWorkspaceID == req.ToWorkspaceID {
+		return errors.New(workspace_domain.ErrChannelNotInWorkspace)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package workspace_usecase
+
+import (
+	"context"
+
+	channel_domain "vault-app/internal/channel/domain"
+	thread_domain "vault-app/internal/thread/domain"
+	tracecore_types "vault-app/internal/tracecore/types"
+)
+
+// ChannelStore is the part of the channel repository the workspace use cases
+// cascade to.
+type ChannelStore interface {
+	ListChannels(ctx context.Context, req *channel_domain.ListChannelsRequest) (*tracecore_types.CloudResponse[[]channel_domain.Channel], error)
+	GetChannel(ctx context.Context, req *channel_domain.GetChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error)
+	UpdateChannel(ctx context.Context, req *channel_domain.UpdateChannelRequest) (*tracecore_types.CloudResponse[channel_domain.Channel], error)
+}
+
+// ThreadStore is the part of the thread repository the workspace use cases
+// read and re-home.
+type ThreadStore interface {
+	ListThreads(ctx context.Context, req *thread_domain.ListThreadsRequest) (*tracecore_types.CloudResponse[[]thread_domain.Thread], error)
+	UpdateThread(ctx context.Context, req *thread_domain.UpdateThreadRequest) (*tracecore_types.CloudResponse[thread_domain.Thread], error)
+}
+
+func listWorkspaceChannels(ctx context.Context, channels ChannelStore, workspaceID string) ([]channel_domain.Channel, error) {
+	resp, err := channels.ListChannels(ctx, &channel_domain.ListChannelsRequest{WorkspaceID: workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.Data, nil
+}
+
+func listChannelThreads(ctx context.Context, threads ThreadStore, channelID string) ([]thread_domain.Thread, error) {
+	resp, err := threads.ListThreads(ctx, &thread_domain.ListThreadsRequest{ChannelID: channelID})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,189 @@
+package workspace_usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	channel_domain "vault-app/internal/channel/domain"
+	workspace_application "vault-app/internal/workspace/application"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+// SearchWorkspacesUsecase finds channels and threads by title, channel
+// properties and thread subtitle/asset type across every workspace of a
+// vault. Matching is case-insensitive and every query term must match.
+type SearchWorkspacesUsecase struct {
+	Repo     workspace_domain.Repository
+	Channels ChannelStore
+	Threads  ThreadStore
+}
+
+func NewSearchWorkspacesUsecase(repo workspace_domain.Repository, channels ChannelStore, threads ThreadStore) *SearchWorkspacesUsecase {
+	return &SearchWorkspacesUsecase{
+		Repo:     repo,
+		Channels: channels,
+		Threads:  threads,
+	}
+}
+
+func (c *SearchWorkspacesUsecase) Execute(ctx context.Context, req *workspace_application.SearchWorkspacesRequest) ([]workspace_application.SearchHit, error) {
+	if err := c.ValidateDependencies(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, errors.New(workspace_domain.ErrRequestRequired)
+	}
+	if req.VaultID == "" {
+		return nil, errors.New(workspace_domain.ErrVaultIDRequired)
+	}
+	terms := strings.Fields(strings.ToLower(req.Query))
+	if len(terms) == 0 {
+		return nil, errors.New(workspace_domain.ErrSearchQueryRequired)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = workspace_application.DefaultSearchLimit
+	}
+
+	workspaces, err := c.Repo.ListWorkspace(ctx, workspace_domain.ListRequest{VaultID: req.VaultID})
+	if err != nil {
+		return nil, err
+	}
+
+	type ranked struct {
+		hit     workspace_application.SearchHit
+		inTitle bool
+	}
+	results := []ranked{}
+
+	for _, ws := range workspaces {
+		if ws.IsArchived() && !req.IncludeArchived {
+			continue
+		}
+		channels, err := listWorkspaceChannels(ctx, c.Channels, ws.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, channel := range channels {
+			archived := ws.IsArchived() || channel.Status == channel_domain.StatusArchived
+			if archived && !req.IncludeArchived {
+				continue
+			}
+
+			fields := map[string]string{"title": channel.Title}
+			for _, p := range channel.Properties {
+				if p.Key == channel_domain.WorkspaceArchivedProperty {
+					continue
+				}
+				fields["property."+p.Key] = p.Value
+			}
+			if matched, ok := matchTerms(terms, fields); ok {
+				results = append(results, ranked{
+					hit: workspace_application.SearchHit{
+						Kind:          workspace_application.SearchHitChannel,
+						WorkspaceID:   ws.ID,
+						WorkspaceName: ws.Name,
+						ChannelID:     channel.ID,
+						ChannelTitle:  channel.Title,
+						Title:         channel.Title,
+						MatchedFields: matched,
+						Archived:      archived,
+					},
+					inTitle: containsString(matched, "title"),
+				})
+			}
+
+			threads, err := listChannelThreads(ctx, c.Threads, channel.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, thread := range threads {
+				matched, ok := matchTerms(terms, map[string]string{
+					"title":      thread.Title,
+					"subtitle":   thread.Subtitle,
+					"asset_type": thread.AssetType,
+				})
+				if !ok {
+					continue
+				}
+				results = append(results, ranked{
+					hit: workspace_application.SearchHit{
+						Kind:          workspace_application.SearchHitThread,
+						WorkspaceID:   ws.ID,
+						WorkspaceName: ws.Name,
+						ChannelID:     channel.ID,
+						ChannelTitle:  channel.Title,
+						ThreadID:      thread.ID,
+						Title:         thread.Title,
+						MatchedFields: matched,
+						Archived:      archived,
+					},
+					inTitle: containsString(matched, "title"),
+				})
+			}
+		}
+	}
+
+	// Title matches first; otherwise keep workspace/channel order.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].inTitle && !results[j].inTitle
+	})
+
+	hits := []workspace_application.SearchHit{}
+	for _, r := range results {
+		if len(hits) == limit {
+			break
+		}
+		hits = append(hits, r.hit)
+	}
+	return hits, nil
+}
+
+func (c *SearchWorkspacesUsecase) ValidateDependencies() error {
+	if c.Repo == nil {
+		return errors.New(workspace_domain.ErrRepositoryNil)
+	}
+	if c.Channels == nil {
+		return errors.New(workspace_domain.ErrChannelStoreRequired)
+	}
+	if c.Threads == nil {
+		return errors.New(workspace_domain.ErrThreadStoreRequired)
+	}
+	return nil
+}
+
+// matchTerms reports whether every term occurs in at least one field and
+// returns the fields that matched, sorted by name.
+func matchTerms(terms []string, fields map[string]string) ([]string, bool) {
+	matched := map[string]bool{}
+	for _, term := range terms {
+		found := false
+		for name, value := range fields {
+			if strings.Contains(strings.ToLower(value), term) {
+				matched[name] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	names := make([]string, 0, len(matched))
+	for name := range matched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -23,9 +23,12 @@ type Workspace struct {
 	Status      WorkspaceStatus `json:"status"`
 
 	OwnerID string `json:"owner_id"`
+	// Members are the vaults granted a role besides the owning vault.
+	Members []WorkspaceMember `json:"members,omitempty"`
 
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 	IsDraft   bool      `json:"is_draft"`
 	IsDirty   bool      `json:"is_dirty" gorm:"boolean"`
 }
@@ -35,6 +38,9 @@ func (w *Workspace) Rename(name string) error {
 	if name == "" {
 		return errors.New(ErrInvalidName)
 	}
+	if w.IsArchived() {
+		return errors.New(ErrWorkspaceArchived)
+	}
 
 	w.Name = name
 
@@ -43,6 +49,39 @@ func (w *Workspace) Rename(name string) error {
 	return nil
 }
 
+// IsArchived reports whether the workspace is read-only. Its channels and
+// their threads are archived with it.
+func (w *Workspace) IsArchived() bool {
+	return w.Status == WorkspaceArchived
+}
+
+// Archive retires an active workspace without deleting its history.
+func (w *Workspace) Archive() error {
+	if w.IsArchived() {
+		return errors.New(ErrWorkspaceArchived)
+	}
+
+	now := time.Now().UTC()
+	w.Status = WorkspaceArchived
+	w.ArchivedAt = &now
+	w.UpdatedAt = now
+
+	return nil
+}
+
+// Restore makes an archived workspace active again.
+func (w *Workspace) Restore() error {
+	if !w.IsArchived() {
+		return errors.New(ErrWorkspaceNotArchived)
+	}
+
+	w.Status = WorkspaceActive
+	w.ArchivedAt = nil
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
 func NewWorkspace(vaultID string, name string, description string, ownerID string) Workspace {
 	now := time.Now()
 	return Workspace{
@@ -11,4 +11,16 @@ var (
 	ErrWorkspaceBusRequired = "Event bus is nil"
 	ErrRequestRequired = "Request is nil"
 	ErrRepositoryResponse = "workspace repository returned nil response"
+	ErrWorkspaceArchived = "workspace is archived"
+	ErrWorkspaceNotArchived = "workspace is not archived"
+	ErrWorkspaceRoleInvalid = "workspace role is invalid"
+	ErrWorkspaceMemberExists = "vault is already a workspace member"
+	ErrWorkspaceMemberNotFound = "vault is not a workspace member"
+	ErrWorkspaceOwnerImmutable = "workspace owner cannot be changed or removed"
+	ErrWorkspacePermissionDenied = "vault may not manage the workspace"
+	ErrChannelIDRequired = "channel id is required"
+	ErrChannelNotInWorkspace = "channel does not belong to the workspace"
+	ErrSearchQueryRequired = "search query is required"
+	ErrChannelStoreRequired = "channel store is required"
+	ErrThreadStoreRequired = "thread store is required"
 )
@@ -8,6 +8,18 @@ const (
 	EventWorkspaceCreated = "workspace.created"
 	EventWorkspaceRenamed = "workspace.renamed"
 	EventWorkspaceDeleted = "workspace.deleted"
+
+	EventWorkspaceArchived          = "workspace.archived"
+	EventWorkspaceRestored          = "workspace.restored"
+	EventWorkspaceMembershipChanged = "workspace.membership_changed"
+	EventWorkspaceChannelMoved      = "workspace.channel_moved"
+)
+
+// Membership changes recorded by WorkspaceMembershipChanged.
+const (
+	MembershipAdded       = "added"
+	MembershipRoleChanged = "role_changed"
+	MembershipRemoved     = "removed"
 )
 
 type WorkspaceCreated struct {
@@ -49,4 +61,67 @@ type WorkspaceDeleted struct {
 
 func (WorkspaceDeleted) EventType() string {
 	return EventWorkspaceDeleted
-}
\ No newline at end of file
+}
+
+// WorkspaceArchivedEvent lists the channels the archive made read-only. The
+// Event suffix keeps it apart from the WorkspaceArchived status.
+type WorkspaceArchivedEvent struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	WorkspaceID  string
+	VaultID      string
+	ActorVaultID string
+	ChannelIDs   []string
+}
+
+func (WorkspaceArchivedEvent) EventType() string {
+	return EventWorkspaceArchived
+}
+
+// WorkspaceRestoredEvent lists the channels the restore reactivated.
+type WorkspaceRestoredEvent struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	WorkspaceID  string
+	VaultID      string
+	ActorVaultID string
+	ChannelIDs   []string
+}
+
+func (WorkspaceRestoredEvent) EventType() string {
+	return EventWorkspaceRestored
+}
+
+type WorkspaceMembershipChanged struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	WorkspaceID  string
+	ActorVaultID string
+	VaultID      string
+	Change       string
+	Role         WorkspaceRole
+	PreviousRole WorkspaceRole
+}
+
+func (WorkspaceMembershipChanged) EventType() string {
+	return EventWorkspaceMembershipChanged
+}
+
+type WorkspaceChannelMoved struct {
+	EventID        string
+	EventTimestamp time.Time
+
+	ChannelID       string
+	FromWorkspaceID string
+	ToWorkspaceID   string
+	ActorVaultID    string
+	// ThreadIDs are the channel's threads re-homed with it.
+	ThreadIDs []string
+}
+
+func (WorkspaceChannelMoved) EventType() string {
+	return EventWorkspaceChannelMoved
+}
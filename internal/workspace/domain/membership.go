@@ -0,0 +1,129 @@
+package workspace_domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// WorkspaceRole is what a vault may do in a workspace. The owning vault is
+// always owner; other vaults are members with a recorded role.
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleMember WorkspaceRole = "member"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+type WorkspaceMember struct {
+	VaultID string        `json:"vault_id"`
+	Role    WorkspaceRole `json:"role"`
+	AddedBy string        `json:"added_by"`
+	AddedAt time.Time     `json:"added_at"`
+}
+
+// ValidMemberRole reports whether role can be given to a member. Ownership
+// is not transferable through membership.
+func ValidMemberRole(role WorkspaceRole) bool {
+	return role == WorkspaceRoleAdmin || role == WorkspaceRoleMember || role == WorkspaceRoleViewer
+}
+
+func (w *Workspace) isOwner(vaultID string) bool {
+	return vaultID != "" && (vaultID == w.VaultID || vaultID == w.OwnerID)
+}
+
+func (w *Workspace) memberIndex(vaultID string) int {
+	return slices.IndexFunc(w.Members, func(m WorkspaceMember) bool { return m.VaultID == vaultID })
+}
+
+// RoleOf returns the role vaultID holds in the workspace.
+func (w *Workspace) RoleOf(vaultID string) (WorkspaceRole, bool) {
+	if w.isOwner(vaultID) {
+		return WorkspaceRoleOwner, true
+	}
+	if i := w.memberIndex(vaultID); i >= 0 {
+		return w.Members[i].Role, true
+	}
+	return "", false
+}
+
+// CanManage reports whether vaultID may archive, restore, move channels and
+// change membership.
+func (w *Workspace) CanManage(vaultID string) bool {
+	role, ok := w.RoleOf(vaultID)
+	return ok && (role == WorkspaceRoleOwner || role == WorkspaceRoleAdmin)
+}
+
+// CanView reports whether vaultID may read the workspace.
+func (w *Workspace) CanView(vaultID string) bool {
+	_, ok := w.RoleOf(vaultID)
+	return ok
+}
+
+// AddMember grants vaultID a role. Archived workspaces cannot change.
+func (w *Workspace) AddMember(vaultID string, role WorkspaceRole, addedBy string) error {
+	if err := w.checkMemberChange(vaultID, role); err != nil {
+		return err
+	}
+	if w.memberIndex(vaultID) >= 0 {
+		return errors.New(ErrWorkspaceMemberExists)
+	}
+
+	now := time.Now().UTC()
+	w.Members = append(w.Members, WorkspaceMember{VaultID: vaultID, Role: role, AddedBy: addedBy, AddedAt: now})
+	w.UpdatedAt = now
+	return nil
+}
+
+// SetMemberRole changes a member's role and returns the previous one.
+func (w *Workspace) SetMemberRole(vaultID string, role WorkspaceRole) (WorkspaceRole, error) {
+	if err := w.checkMemberChange(vaultID, role); err != nil {
+		return "", err
+	}
+	i := w.memberIndex(vaultID)
+	if i < 0 {
+		return "", errors.New(ErrWorkspaceMemberNotFound)
+	}
+
+	previous := w.Members[i].Role
+	w.Members[i].Role = role
+	w.UpdatedAt = time.Now().UTC()
+	return previous, nil
+}
+
+// RemoveMember revokes a member's access and returns the role it held.
+func (w *Workspace) RemoveMember(vaultID string) (WorkspaceRole, error) {
+	if w.IsArchived() {
+		return "", errors.New(ErrWorkspaceArchived)
+	}
+	if w.isOwner(vaultID) {
+		return "", errors.New(ErrWorkspaceOwnerImmutable)
+	}
+	i := w.memberIndex(vaultID)
+	if i < 0 {
+		return "", errors.New(ErrWorkspaceMemberNotFound)
+	}
+
+	previous := w.Members[i].Role
+	w.Members = slices.Delete(w.Members, i, i+1)
+	w.UpdatedAt = time.Now().UTC()
+	return previous, nil
+}
+
+func (w *Workspace) checkMemberChange(vaultID string, role WorkspaceRole) error {
+	if w.IsArchived() {
+		return errors.New(ErrWorkspaceArchived)
+	}
+	if vaultID == "" {
+		return errors.New(ErrVaultIDRequired)
+	}
+	if w.isOwner(vaultID) {
+		return errors.New(ErrWorkspaceOwnerImmutable)
+	}
+	if !ValidMemberRole(role) {
+		return errors.New(ErrWorkspaceRoleInvalid)
+	}
+	return nil
+}
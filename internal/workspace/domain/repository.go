@@ -2,6 +2,7 @@ package workspace_domain
 
 import (
 	"context"
+	"time"
 
 	tracecore_types "vault-app/internal/tracecore/types"
 )
@@ -27,6 +28,9 @@ type GetRequest struct {
 }
 type ListRequest struct {
 	VaultID string
+	// UpdatedSince, when set, restricts the listing to workspaces changed
+	// after it.
+	UpdatedSince time.Time
 }
 type Repository interface {
 	CreateWorkspace(ctx context.Context, req CreateRequest) (*tracecore_types.CloudResponse[Workspace], error)
@@ -11,6 +11,10 @@ type MemoryBus struct {
 	workspaceCreatedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceCreated)
 	workspaceRenamedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceRenamed)
 	workspaceDeletedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceDeleted)
+	workspaceArchivedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent)
+	workspaceRestoredSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent)
+	workspaceMembershipChangedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged)
+	workspaceChannelMovedSubscribers []func(ctx context.Context, event workspace_domain.WorkspaceChannelMoved)
 	lock        sync.RWMutex
 }
 
@@ -67,3 +71,63 @@ func (mb *MemoryBus) SubscribeToWorkspaceDeleted(handler func(ctx context.Contex
 	mb.workspaceDeletedSubscribers = append(mb.workspaceDeletedSubscribers, handler)
 	return nil
 }
+
+func (mb *MemoryBus) PublishWorkspaceArchived(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.workspaceArchivedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToWorkspaceArchived(handler func(ctx context.Context, event workspace_domain.WorkspaceArchivedEvent)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.workspaceArchivedSubscribers = append(mb.workspaceArchivedSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishWorkspaceRestored(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.workspaceRestoredSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToWorkspaceRestored(handler func(ctx context.Context, event workspace_domain.WorkspaceRestoredEvent)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.workspaceRestoredSubscribers = append(mb.workspaceRestoredSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishWorkspaceMembershipChanged(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.workspaceMembershipChangedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToWorkspaceMembershipChanged(handler func(ctx context.Context, event workspace_domain.WorkspaceMembershipChanged)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.workspaceMembershipChangedSubscribers = append(mb.workspaceMembershipChangedSubscribers, handler)
+	return nil
+}
+
+func (mb *MemoryBus) PublishWorkspaceChannelMoved(ctx context.Context, event workspace_domain.WorkspaceChannelMoved) error {
+	mb.lock.RLock()
+	defer mb.lock.RUnlock()
+	for _, h := range mb.workspaceChannelMovedSubscribers {
+		go h(ctx, event)
+	}
+	return nil
+}
+func (mb *MemoryBus) SubscribeToWorkspaceChannelMoved(handler func(ctx context.Context, event workspace_domain.WorkspaceChannelMoved)) error {
+	mb.lock.Lock()
+	defer mb.lock.Unlock()
+	mb.workspaceChannelMovedSubscribers = append(mb.workspaceChannelMovedSubscribers, handler)
+	return nil
+}
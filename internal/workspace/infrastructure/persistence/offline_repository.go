@@ -0,0 +1,180 @@
+package workspace_persistence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	shared_offline "vault-app/internal/shared/offline"
+	tracecore_types "vault-app/internal/tracecore/types"
+	workspace_domain "vault-app/internal/workspace/domain"
+)
+
+const (
+	OpCreateWorkspace = "workspace.create"
+	OpUpdateWorkspace = "workspace.update"
+	OpDeleteWorkspace = "workspace.delete"
+
+	MessageQueuedOffline = "queued offline"
+)
+
+// OfflineRepository is an offline-first workspace Repository. Cloud stays
+// authoritative: every call goes to the cloud first and successful responses
+// refresh the local cache. When the cloud is unreachable, reads are served
+// from the cache and writes are queued for replay on reconnect.
+type OfflineRepository struct {
+	cloud workspace_domain.Repository
+	cache *shared_offline.Cache
+}
+
+func NewOfflineRepository(cloud workspace_domain.Repository, cache *shared_offline.Cache) *OfflineRepository {
+	r := &OfflineRepository{cloud: cloud, cache: cache}
+
+	cache.Queue.Register(OpCreateWorkspace, func(ctx context.Context, payload json.RawMessage) error {
+		var req workspace_domain.CreateRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.CreateWorkspace(ctx, req)
+		if err != nil {
+			return err
+		}
+		return r.put(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpUpdateWorkspace, func(ctx context.Context, payload json.RawMessage) error {
+		var req workspace_domain.UpdateRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		resp, err := cloud.UpdateWorkspace(ctx, req)
+		if err != nil {
+			return err
+		}
+		return r.put(ctx, resp.Data)
+	})
+	cache.Queue.Register(OpDeleteWorkspace, func(ctx context.Context, payload json.RawMessage) error {
+		var req workspace_domain.DeleteRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+		return cloud.DeleteWorkspace(ctx, req)
+	})
+
+	return r
+}
+
+func (r *OfflineRepository) put(ctx context.Context, ws workspace_domain.Workspace) error {
+	return r.cache.UserStore().Put(ctx, shared_offline.KindWorkspace, ws.VaultID, shared_offline.Item{ID: ws.ID, Value: ws})
+}
+
+func (r *OfflineRepository) queue(ctx context.Context, op string, req any, ws workspace_domain.Workspace) (*tracecore_types.CloudResponse[workspace_domain.Workspace], error) {
+	if _, err := r.cache.Queue.Enqueue(ctx, op, req); err != nil {
+		return nil, err
+	}
+	ws.IsDirty = true
+	if err := r.put(ctx, ws); err != nil {
+		return nil, err
+	}
+	return &tracecore_types.CloudResponse[workspace_domain.Workspace]{Status: http.StatusAccepted, Data: ws, Message: MessageQueuedOffline}, nil
+}
+
+func (r *OfflineRepository) CreateWorkspace(ctx context.Context, req workspace_domain.CreateRequest) (*tracecore_types.CloudResponse[workspace_domain.Workspace], error) {
+	resp, err := r.cloud.CreateWorkspace(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		return r.queue(ctx, OpCreateWorkspace, req, req.Workspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.put(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) UpdateWorkspace(ctx context.Context, req workspace_domain.UpdateRequest) (*tracecore_types.CloudResponse[workspace_domain.Workspace], error) {
+	resp, err := r.cloud.UpdateWorkspace(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		return r.queue(ctx, OpUpdateWorkspace, req, req.Workspace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.put(ctx, resp.Data); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *OfflineRepository) DeleteWorkspace(ctx context.Context, req workspace_domain.DeleteRequest) error {
+	err := r.cloud.DeleteWorkspace(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		if _, err := r.cache.Queue.Enqueue(ctx, OpDeleteWorkspace, req); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return r.cache.UserStore().Delete(ctx, shared_offline.KindWorkspace, req.WorkspaceID)
+}
+
+func (r *OfflineRepository) GetWorkspace(ctx context.Context, req workspace_domain.GetRequest) (*workspace_domain.Workspace, error) {
+	ws, err := r.cloud.GetWorkspace(ctx, req)
+	if r.cache.Connectivity.Observe(ctx, err) {
+		var cached workspace_domain.Workspace
+		if err := r.cache.UserStore().Get(ctx, shared_offline.KindWorkspace, req.WorkspaceID, &cached); err != nil {
+			return nil, err
+		}
+		return &cached, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ws != nil {
+		if err := r.put(ctx, *ws); err != nil {
+			return nil, err
+		}
+	}
+	return ws, nil
+}
+
+// ListWorkspace refreshes incrementally: only workspaces changed since the
+// listing's last refresh are requested and merged into the cache, and the
+// full cached listing is returned.
+func (r *OfflineRepository) ListWorkspace(ctx context.Context, req workspace_domain.ListRequest) ([]workspace_domain.Workspace, error) {
+	scope := shared_offline.Scope(shared_offline.KindWorkspace, req.VaultID)
+	store := r.cache.UserStore()
+
+	state, err := store.SyncState(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	since := store.Since(*state)
+
+	list, err := r.cloud.ListWorkspace(ctx, workspace_domain.ListRequest{VaultID: req.VaultID, UpdatedSince: since})
+	if r.cache.Connectivity.Observe(ctx, err) {
+		_ = store.MarkFailed(ctx, scope, err)
+	} else if err != nil {
+		return nil, err
+	} else {
+		items := make([]shared_offline.Item, 0, len(list))
+		for _, ws := range list {
+			items = append(items, shared_offline.Item{ID: ws.ID, Value: ws})
+		}
+		if err := store.MergeSynced(ctx, shared_offline.KindWorkspace, req.VaultID, scope, since, items); err != nil {
+			return nil, err
+		}
+	}
+
+	recs, err := store.List(ctx, shared_offline.KindWorkspace, req.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	return shared_offline.Decode[workspace_domain.Workspace](recs)
+}
+
+// Ensure interface satisfaction at compile-time
+var _ workspace_domain.Repository = (*OfflineRepository)(nil)
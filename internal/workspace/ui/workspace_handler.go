@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	channel_domain "vault-app/internal/channel/domain"
 	tracecore_types "vault-app/internal/tracecore/types"
 	"vault-app/internal/utils"
 	workspace_application "vault-app/internal/workspace/application"
@@ -14,6 +15,12 @@ import (
 type WorkspaceHandler struct {
 	createUseCase *workspace_usecase.CreateWorkspaceUsecase
 	listUseCase   *workspace_usecase.ListWorkspaceUsecase
+
+	archiveUseCase    *workspace_usecase.ArchiveWorkspaceUsecase
+	restoreUseCase    *workspace_usecase.RestoreWorkspaceUsecase
+	moveUseCase       *workspace_usecase.MoveChannelUsecase
+	membershipUseCase *workspace_usecase.WorkspaceMembershipUsecase
+	searchUseCase     *workspace_usecase.SearchWorkspacesUsecase
 }
 
 func NewWorkspaceHandler(
@@ -70,6 +77,132 @@ func (h *WorkspaceHandler) ListWorkspaces(ctx context.Context,vaultID string) ([
 	return res, nil
 }
 
+// SetLifecycleUseCases wires archive, restore and channel moves, which
+// cascade into the channel and thread contexts.
+func (h *WorkspaceHandler) SetLifecycleUseCases(
+	archiveUC *workspace_usecase.ArchiveWorkspaceUsecase,
+	restoreUC *workspace_usecase.RestoreWorkspaceUsecase,
+	moveUC *workspace_usecase.MoveChannelUsecase,
+) {
+	h.archiveUseCase = archiveUC
+	h.restoreUseCase = restoreUC
+	h.moveUseCase = moveUC
+}
+
+func (h *WorkspaceHandler) SetMembershipUseCase(membershipUC *workspace_usecase.WorkspaceMembershipUsecase) {
+	h.membershipUseCase = membershipUC
+}
+
+func (h *WorkspaceHandler) SetSearchUseCase(searchUC *workspace_usecase.SearchWorkspacesUsecase) {
+	h.searchUseCase = searchUC
+}
+
+func (h *WorkspaceHandler) ArchiveWorkspace(ctx context.Context, actorVaultID string, workspaceID string) (*tracecore_types.Workspace, error) {
+	if h.archiveUseCase == nil {
+		return nil, fmt.Errorf("archive workspace use case is not initialized")
+	}
+
+	ws, err := h.archiveUseCase.Execute(ctx, &workspace_application.ArchiveWorkspaceRequest{
+		WorkspaceID:  workspaceID,
+		ActorVaultID: actorVaultID,
+		Signature:    "desktop_authenticated",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreWorkspace(ws), nil
+}
+
+func (h *WorkspaceHandler) RestoreWorkspace(ctx context.Context, actorVaultID string, workspaceID string) (*tracecore_types.Workspace, error) {
+	if h.restoreUseCase == nil {
+		return nil, fmt.Errorf("restore workspace use case is not initialized")
+	}
+
+	ws, err := h.restoreUseCase.Execute(ctx, &workspace_application.ArchiveWorkspaceRequest{
+		WorkspaceID:  workspaceID,
+		ActorVaultID: actorVaultID,
+		Signature:    "desktop_authenticated",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toTracecoreWorkspace(ws), nil
+}
+
+func (h *WorkspaceHandler) MoveChannel(ctx context.Context, actorVaultID string, channelID string, fromWorkspaceID string, toWorkspaceID string) (*channel_domain.Channel, error) {
+	if h.moveUseCase == nil {
+		return nil, fmt.Errorf("move channel use case is not initialized")
+	}
+
+	return h.moveUseCase.Execute(ctx, &workspace_application.MoveChannelRequest{
+		ChannelID:       channelID,
+		FromWorkspaceID: fromWorkspaceID,
+		ToWorkspaceID:   toWorkspaceID,
+		ActorVaultID:    actorVaultID,
+	})
+}
+
+func (h *WorkspaceHandler) AddWorkspaceMember(ctx context.Context, actorVaultID string, workspaceID string, vaultID string, role string) ([]workspace_domain.WorkspaceMember, error) {
+	if h.membershipUseCase == nil {
+		return nil, fmt.Errorf("workspace membership use case is not initialized")
+	}
+
+	ws, err := h.membershipUseCase.AddMember(ctx, memberRequest(actorVaultID, workspaceID, vaultID, role))
+	if err != nil {
+		return nil, err
+	}
+	return ws.Members, nil
+}
+
+func (h *WorkspaceHandler) UpdateWorkspaceMemberRole(ctx context.Context, actorVaultID string, workspaceID string, vaultID string, role string) ([]workspace_domain.WorkspaceMember, error) {
+	if h.membershipUseCase == nil {
+		return nil, fmt.Errorf("workspace membership use case is not initialized")
+	}
+
+	ws, err := h.membershipUseCase.SetMemberRole(ctx, memberRequest(actorVaultID, workspaceID, vaultID, role))
+	if err != nil {
+		return nil, err
+	}
+	return ws.Members, nil
+}
+
+func (h *WorkspaceHandler) RemoveWorkspaceMember(ctx context.Context, actorVaultID string, workspaceID string, vaultID string) ([]workspace_domain.WorkspaceMember, error) {
+	if h.membershipUseCase == nil {
+		return nil, fmt.Errorf("workspace membership use case is not initialized")
+	}
+
+	ws, err := h.membershipUseCase.RemoveMember(ctx, memberRequest(actorVaultID, workspaceID, vaultID, ""))
+	if err != nil {
+		return nil, err
+	}
+	return ws.Members, nil
+}
+
+func (h *WorkspaceHandler) SearchWorkspaces(ctx context.Context, vaultID string, query string, includeArchived bool, limit int) ([]workspace_application.SearchHit, error) {
+	if h.searchUseCase == nil {
+		return nil, fmt.Errorf("search workspaces use case is not initialized")
+	}
+
+	return h.searchUseCase.Execute(ctx, &workspace_application.SearchWorkspacesRequest{
+		VaultID:         vaultID,
+		Query:           query,
+		IncludeArchived: includeArchived,
+		Limit:           limit,
+	})
+}
+
+func memberRequest(actorVaultID string, workspaceID string, vaultID string, role string) *workspace_application.WorkspaceMemberRequest {
+	return &workspace_application.WorkspaceMemberRequest{
+		WorkspaceID:  workspaceID,
+		ActorVaultID: actorVaultID,
+		VaultID:      vaultID,
+		Role:         workspace_domain.WorkspaceRole(role),
+		Signature:    "desktop_authenticated",
+	}
+}
+
 func toTracecoreWorkspace(ws *workspace_domain.Workspace) *tracecore_types.Workspace {
 	if ws == nil {
 		return nil
@@ -83,6 +216,7 @@ func toTracecoreWorkspace(ws *workspace_domain.Workspace) *tracecore_types.Works
 		OwnerID:     ws.OwnerID,
 		CreatedAt:   ws.CreatedAt,
 		UpdatedAt:   ws.UpdatedAt,
+		ArchivedAt:  ws.ArchivedAt,
 		IsDraft:     ws.IsDraft,
 		IsDirty:     ws.IsDirty,
 	}
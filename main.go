@@ -56,6 +56,9 @@ func main() {
 		OnShutdown: func(ctx context.Context) {
 			app.Logger.Info("🛑 App shutting down, flushing sessions...")
 			app.FlushAllSessions()
+			_ = app.SSHAgentHandler.Shutdown()
+			_ = app.GitCredentialHandler.Stop()
+			_ = app.BrowserExtensionHandler.Stop()
 
 			if app.cancel != nil {
 				app.cancel()